@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+type ExampleRunner struct {
+	// provider is the provider whose examples are printed. Value assigned via
+	// --provider flag.
+	provider string
+}
+
+// PrintExample prints the source manifests of the examples registered by the
+// provider for its features, followed by the Gateway API resources they are
+// converted to.
+func (er *ExampleRunner) PrintExample(cmd *cobra.Command, _ []string) error {
+	examples := i2gw.GetProviderExamples(i2gw.ProviderName(er.provider))
+	if len(examples) == 0 {
+		return fmt.Errorf("no examples are registered for provider %q, providers with examples are %v", er.provider, providersWithExamples())
+	}
+	manifest := exampleManifest(examples)
+
+	file, err := os.CreateTemp("", "ingress2gateway-example-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create the example manifest: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err = io.WriteString(file, manifest); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write the example manifest: %w", err)
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("failed to write the example manifest: %w", err)
+	}
+
+	result, err := i2gw.ToGatewayAPIResources(cmd.Context(), i2gw.ConversionOptions{
+		InputFile: file.Name(),
+		Providers: []string{er.provider},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to convert the examples of provider %q: %w", er.provider, err)
+	}
+
+	fmt.Printf("# Source resources of the %s provider\n", er.provider)
+	fmt.Print(manifest)
+	fmt.Println("---")
+	fmt.Println("# Gateway API resources they are converted to")
+	pr := &PrintRunner{resourcePrinter: &printers.YAMLPrinter{}}
+	pr.outputResult(os.Stdout, result.GatewayResources)
+	return nil
+}
+
+// exampleManifest concatenates the manifests of the examples, each preceded
+// by a comment describing its feature.
+func exampleManifest(examples []i2gw.ProviderExample) string {
+	documents := make([]string, 0, len(examples))
+	for _, example := range examples {
+		documents = append(documents, fmt.Sprintf("# %s: %s\n%s", example.Feature, example.Description, example.Manifest))
+	}
+	return strings.Join(documents, "---\n")
+}
+
+func providersWithExamples() []string {
+	var providers []string
+	for _, provider := range i2gw.GetSupportedProviders() {
+		if len(i2gw.GetProviderExamples(i2gw.ProviderName(provider))) > 0 {
+			providers = append(providers, provider)
+		}
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+func newExampleCommand() *cobra.Command {
+	er := &ExampleRunner{}
+
+	// exampleCmd represents the example command. It prints representative
+	// source manifests of the features of a provider and their conversion.
+	var cmd = &cobra.Command{
+		Use:   "example",
+		Short: "Prints representative source manifests of the features of a provider, followed by the Gateway API resources they are converted to.",
+		RunE:  er.PrintExample,
+	}
+
+	cmd.Flags().StringVar(&er.provider, "provider", "",
+		fmt.Sprintf("The provider whose examples are printed, supported values are %v.", providersWithExamples()))
+
+	_ = cmd.MarkFlagRequired("provider")
+	return cmd
+}
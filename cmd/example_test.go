@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+)
+
+// Test_providerExamples converts the examples of every provider, which must
+// be converted without errors to Gateway API resources.
+func Test_providerExamples(t *testing.T) {
+	providers := providersWithExamples()
+	if len(providers) == 0 {
+		t.Fatal("Expected providers to register examples")
+	}
+	for _, provider := range providers {
+		t.Run(provider, func(t *testing.T) {
+			inputFile := filepath.Join(t.TempDir(), "example.yaml")
+			if err := os.WriteFile(inputFile, []byte(exampleManifest(i2gw.GetProviderExamples(i2gw.ProviderName(provider)))), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			result, err := i2gw.ToGatewayAPIResources(context.Background(), i2gw.ConversionOptions{
+				InputFile: inputFile,
+				Providers: []string{provider},
+			})
+			if err != nil {
+				t.Fatalf("Failed to convert the examples: %v", err)
+			}
+			var httpRoutes int
+			for _, resources := range result.GatewayResources {
+				httpRoutes += len(resources.HTTPRoutes)
+			}
+			if httpRoutes == 0 {
+				t.Errorf("Expected the examples to be converted to HTTPRoutes")
+			}
+		})
+	}
+}
@@ -17,31 +17,49 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/snapshot"
 	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/client-go/tools/clientcmd"
-
-	// Call init function for the providers
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+
+	// Call init function for the emitters and the providers
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/envoygateway"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/istio"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/kgateway"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/nginxgatewayfabric"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/traefik"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/ako"
 	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/apisix"
 	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/cilium"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/f5"
 	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/gce"
 	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/ingressnginx"
 	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/istio"
 	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/kong"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/netscaler"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/nginx"
 	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/openapi3"
-
-	// Call init for notifications
-	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/openshift"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/skipper"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/traefik"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/voyager"
 )
 
 type PrintRunner struct {
@@ -52,6 +70,10 @@ type PrintRunner struct {
 	// The path to the input yaml config file. Value assigned via --input-file flag
 	inputFile string
 
+	// The path to the snapshot archive written by the snapshot command. Value
+	// assigned via --input-snapshot flag.
+	inputSnapshot string
+
 	// The namespace used to query Gateway API objects. Value assigned via
 	// --namespace/-n flag.
 	// On absence, the current user active namespace is used.
@@ -70,8 +92,124 @@ type PrintRunner struct {
 	// providers indicates which providers are used to execute convert action.
 	providers []string
 
+	// providerPriority lists the providers taking precedence when several
+	// providers claim the same Ingress. Value assigned via --provider-priority flag.
+	providerPriority []string
+
+	// hostConflictPriority lists the providers taking precedence when the routes
+	// of several providers serve the same hostname. Value assigned via
+	// --host-conflict-priority flag.
+	hostConflictPriority []string
+
 	// Provider specific flags --<provider>-<flag>.
 	providerSpecificFlags map[string]*string
+
+	// redact indicates whether sensitive values should be masked in the
+	// notifications and the printed resources. Value assigned via --redact flag.
+	redact bool
+
+	// mesh indicates whether routes for east-west traffic should be attached to
+	// Services for GAMMA mesh implementations. Value assigned via --mesh flag.
+	mesh bool
+
+	// clusterDomain is the domain of the cluster-local Service hostnames.
+	// Value assigned via --cluster-domain flag.
+	clusterDomain string
+
+	// profile is the name of the conversion profile. Value assigned via
+	// --profile flag.
+	profile string
+
+	// gatewayStrategy is the strategy used to generate Gateways. Value assigned
+	// via --gateway-strategy flag.
+	gatewayStrategy string
+
+	// listenerStrategy is the strategy used to assign the hostnames of the
+	// Gateways to their listeners. Value assigned via --listener-strategy flag.
+	listenerStrategy string
+
+	// enabledFeatures and disabledFeatures select the features of the
+	// providers to convert. Values assigned via --enable-features and
+	// --disable-features flags.
+	enabledFeatures  []string
+	disabledFeatures []string
+
+	// certManagerCertificates indicates whether cert-manager Certificates
+	// should be generated for the Gateways annotated with a cert-manager
+	// issuer. Value assigned via --cert-manager-certificates flag.
+	certManagerCertificates bool
+
+	// hostnamePolicy is the policy applied to the invalid hostnames of the
+	// sources. Value assigned via --hostname-policy flag.
+	hostnamePolicy string
+
+	// annotateSources indicates whether the source resources should be printed
+	// annotated with the summary of their conversion. Value assigned via
+	// --annotate-sources flag.
+	annotateSources bool
+
+	// backendTLSWellKnownCACertificates are the well-known CA certificates
+	// validating the generated BackendTLSPolicies without CA certificates.
+	// Value assigned via --backend-tls-well-known-ca-certificates flag.
+	backendTLSWellKnownCACertificates string
+
+	// emitter is the name of the emitter extending the generated resources
+	// with implementation-specific resources. Value assigned via --emitter flag.
+	emitter string
+
+	// noRouteMerge indicates whether each source resource should yield its own
+	// routes, even when their hosts overlap. Value assigned via
+	// --no-route-merge flag.
+	noRouteMerge bool
+
+	// centralGatewayNamespace is the namespace of the Gateways shared by the
+	// routes of all the namespaces. Value assigned via
+	// --central-gateway-namespace flag.
+	centralGatewayNamespace string
+
+	// gatewayClassMapping maps IngressClasses to the GatewayClasses serving
+	// them. Value assigned via --gateway-class-mapping flag.
+	gatewayClassMapping map[string]string
+
+	// overrideFile is the path of the file of the overrides forcing fields of
+	// the routes generated from given source resources. Value assigned via
+	// --override flag.
+	overrideFile string
+
+	// serviceMappingFile is the path of the file mapping the Services
+	// referenced by the backends to the Services they are renamed or moved
+	// to. Value assigned via --service-mapping flag.
+	serviceMappingFile string
+
+	// lintFor is the implementation whose known incompatibilities the
+	// generated HTTPRoutes are checked against. Value assigned via --lint-for
+	// flag.
+	lintFor string
+
+	// signOutput is the directory the printed manifests bundle is written to,
+	// along with its checksums, for the applied resources to be verified
+	// against it. Value assigned via --sign-output flag.
+	signOutput string
+
+	// signOutputProvenance indicates whether a provenance predicate of the
+	// manifests bundle, to be signed with cosign, should be written to the
+	// signOutput directory too. Value assigned via --sign-output-provenance
+	// flag.
+	signOutputProvenance bool
+
+	// gatewayAPIVersion is the Gateway API version the generated resources
+	// conform to. Value assigned via --gateway-api-version flag.
+	gatewayAPIVersion string
+
+	// compatStripUnknown indicates whether the fields unknown to the
+	// CustomResourceDefinitions of the cluster should be removed from the
+	// printed resources. Value assigned via --compat-strip-unknown flag.
+	compatStripUnknown bool
+
+	// watch indicates whether the command should keep running, printing the
+	// resources affected by the changes of the Ingresses of the cluster as
+	// they happen. Value assigned via --watch flag.
+	watch bool
 }
 
 // PrintGatewayAPIObjects performs necessary steps to digest and print
@@ -79,6 +217,28 @@ type PrintRunner struct {
 // construct ingresses and provider-specific resources, convert them, then print
 // the Gateway API objects out.
 func (pr *PrintRunner) PrintGatewayAPIObjects(cmd *cobra.Command, _ []string) error {
+	startedOn := time.Now()
+
+	if pr.inputSnapshot != "" {
+		manifest, metadata, cleanup, err := snapshot.ExtractManifest(pr.inputSnapshot)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		for _, provider := range pr.providers {
+			if !slices.Contains(metadata.Providers, provider) {
+				fmt.Fprintf(os.Stderr, "# Warning: the snapshot was not taken for the %s provider, some of its resources may be missing\n", provider)
+			}
+		}
+		pr.inputFile = manifest
+		// Unless requested otherwise, convert the resources of the namespace
+		// the snapshot was taken in.
+		if pr.namespace == "" && !pr.allNamespaces {
+			pr.namespace = metadata.Namespace
+			pr.allNamespaces = metadata.Namespace == ""
+		}
+	}
+
 	err := pr.initializeResourcePrinter()
 	if err != nil {
 		return fmt.Errorf("failed to initialize resrouce printer: %w", err)
@@ -88,30 +248,73 @@ func (pr *PrintRunner) PrintGatewayAPIObjects(cmd *cobra.Command, _ []string) er
 		return fmt.Errorf("failed to initialize namespace filter: %w", err)
 	}
 
-	gatewayResources, notificationTablesMap, err := i2gw.ToGatewayAPIResources(cmd.Context(), pr.namespaceFilter, pr.inputFile, pr.providers, pr.getProviderSpecificFlags())
+	opts := i2gw.ConversionOptions{
+		KubeContext:           kubeContext,
+		CertificateAuthority:  certificateAuthority,
+		Impersonate:           impersonate,
+		ImpersonateGroups:     impersonateGroups,
+		Namespace:             pr.namespaceFilter,
+		InputFile:             pr.inputFile,
+		Providers:             pr.providers,
+		ProviderPriority:      pr.providerPriority,
+		HostConflictPriority:  pr.hostConflictPriority,
+		ProviderSpecificFlags: pr.getProviderSpecificFlags(),
+		Mesh:                  pr.mesh,
+		ClusterDomain:         pr.clusterDomain,
+		Profile:               i2gw.ProfileName(pr.profile),
+		GatewayStrategy:       i2gw.GatewayStrategy(pr.gatewayStrategy),
+		ListenerStrategy:      i2gw.ListenerStrategy(pr.listenerStrategy),
+		HostnamePolicy:        i2gw.HostnamePolicy(pr.hostnamePolicy),
+		AnnotateSources:       pr.annotateSources,
+		Redact:                pr.redact,
+
+		BackendTLSWellKnownCACertificates: gatewayv1alpha3.WellKnownCACertificatesType(pr.backendTLSWellKnownCACertificates),
+		CertManagerCertificates:           pr.certManagerCertificates,
+		EnabledFeatures:                   pr.enabledFeatures,
+		DisabledFeatures:                  pr.disabledFeatures,
+		Emitter:                           i2gw.EmitterName(pr.emitter),
+		NoRouteMerge:                      pr.noRouteMerge,
+		CentralGatewayNamespace:           pr.centralGatewayNamespace,
+		GatewayClassMapping:               pr.gatewayClassMapping,
+		OverrideFile:                      pr.overrideFile,
+		ServiceMappingFile:                pr.serviceMappingFile,
+		LintFor:                           i2gw.LintTarget(pr.lintFor),
+		CompatStripUnknown:                pr.compatStripUnknown,
+		GatewayAPIVersion:                 pr.gatewayAPIVersion,
+	}
+	result, err := i2gw.ToGatewayAPIResources(cmd.Context(), opts)
 	if err != nil {
 		return err
 	}
 
-	for _, table := range notificationTablesMap {
+	for _, table := range result.NotificationTables {
 		fmt.Println(table)
 	}
+	gatewayResources := result.GatewayResources
 
-	pr.outputResult(gatewayResources)
+	if pr.signOutput == "" {
+		pr.outputResult(os.Stdout, gatewayResources)
+		if pr.watch {
+			return pr.watchGatewayAPIObjects(cmd.Context(), opts, gatewayResources)
+		}
+		return nil
+	}
 
-	return nil
+	var bundle bytes.Buffer
+	pr.outputResult(io.MultiWriter(os.Stdout, &bundle), gatewayResources)
+	return pr.writeSignedOutput(bundle.Bytes(), changedFlags(cmd), startedOn)
 }
 
-func (pr *PrintRunner) outputResult(gatewayResources []i2gw.GatewayResources) {
+func (pr *PrintRunner) outputResult(w io.Writer, gatewayResources []i2gw.GatewayResources) {
 	resourceCount := 0
 
 	for _, r := range gatewayResources {
 		resourceCount += len(r.GatewayClasses)
 		for _, gatewayClass := range r.GatewayClasses {
 			gatewayClass := gatewayClass
-			err := pr.resourcePrinter.PrintObj(&gatewayClass, os.Stdout)
+			err := pr.resourcePrinter.PrintObj(&gatewayClass, w)
 			if err != nil {
-				fmt.Printf("# Error printing %s GatewayClass: %v\n", gatewayClass.Name, err)
+				fmt.Fprintf(w, "# Error printing %s GatewayClass: %v\n", gatewayClass.Name, err)
 			}
 		}
 	}
@@ -124,24 +327,40 @@ func (pr *PrintRunner) outputResult(gatewayResources []i2gw.GatewayResources) {
 				gateway.Annotations = make(map[string]string)
 			}
 			gateway.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.CurrentVersion)
-			err := pr.resourcePrinter.PrintObj(&gateway, os.Stdout)
+			err := pr.resourcePrinter.PrintObj(&gateway, w)
 			if err != nil {
-				fmt.Printf("# Error printing %s Gateway: %v\n", gateway.Name, err)
+				fmt.Fprintf(w, "# Error printing %s Gateway: %v\n", gateway.Name, err)
 			}
 		}
 	}
 
 	for _, r := range gatewayResources {
 		resourceCount += len(r.HTTPRoutes)
-		for _, httpRoute := range r.HTTPRoutes {
+		for key, httpRoute := range r.HTTPRoutes {
 			httpRoute := httpRoute
 			if httpRoute.Annotations == nil {
 				httpRoute.Annotations = make(map[string]string)
 			}
 			httpRoute.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.CurrentVersion)
-			err := pr.resourcePrinter.PrintObj(&httpRoute, os.Stdout)
+			err := pr.resourcePrinter.PrintObj(i2gw.HTTPRouteObject(httpRoute, r.MirrorFractions[key]), w)
+			if err != nil {
+				fmt.Fprintf(w, "# Error printing %s HTTPRoute: %v\n", httpRoute.Name, err)
+			}
+			pr.printUnsupportedFeatures(w, httpRoute, r.UnsupportedFeatures)
+		}
+	}
+
+	for _, r := range gatewayResources {
+		resourceCount += len(r.GRPCRoutes)
+		for _, grpcRoute := range r.GRPCRoutes {
+			grpcRoute := grpcRoute
+			if grpcRoute.Annotations == nil {
+				grpcRoute.Annotations = make(map[string]string)
+			}
+			grpcRoute.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.CurrentVersion)
+			err := pr.resourcePrinter.PrintObj(&grpcRoute, w)
 			if err != nil {
-				fmt.Printf("# Error printing %s HTTPRoute: %v\n", httpRoute.Name, err)
+				fmt.Fprintf(w, "# Error printing %s GRPCRoute: %v\n", grpcRoute.Name, err)
 			}
 		}
 	}
@@ -154,9 +373,9 @@ func (pr *PrintRunner) outputResult(gatewayResources []i2gw.GatewayResources) {
 				tlsRoute.Annotations = make(map[string]string)
 			}
 			tlsRoute.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.CurrentVersion)
-			err := pr.resourcePrinter.PrintObj(&tlsRoute, os.Stdout)
+			err := pr.resourcePrinter.PrintObj(&tlsRoute, w)
 			if err != nil {
-				fmt.Printf("# Error printing %s TLSRoute: %v\n", tlsRoute.Name, err)
+				fmt.Fprintf(w, "# Error printing %s TLSRoute: %v\n", tlsRoute.Name, err)
 			}
 		}
 	}
@@ -169,9 +388,9 @@ func (pr *PrintRunner) outputResult(gatewayResources []i2gw.GatewayResources) {
 				tcpRoute.Annotations = make(map[string]string)
 			}
 			tcpRoute.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.CurrentVersion)
-			err := pr.resourcePrinter.PrintObj(&tcpRoute, os.Stdout)
+			err := pr.resourcePrinter.PrintObj(&tcpRoute, w)
 			if err != nil {
-				fmt.Printf("# Error printing %s TCPRoute: %v\n", tcpRoute.Name, err)
+				fmt.Fprintf(w, "# Error printing %s TCPRoute: %v\n", tcpRoute.Name, err)
 			}
 		}
 	}
@@ -184,9 +403,9 @@ func (pr *PrintRunner) outputResult(gatewayResources []i2gw.GatewayResources) {
 				udpRoute.Annotations = make(map[string]string)
 			}
 			udpRoute.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.CurrentVersion)
-			err := pr.resourcePrinter.PrintObj(&udpRoute, os.Stdout)
+			err := pr.resourcePrinter.PrintObj(&udpRoute, w)
 			if err != nil {
-				fmt.Printf("# Error printing %s UDPRoute: %v\n", udpRoute.Name, err)
+				fmt.Fprintf(w, "# Error printing %s UDPRoute: %v\n", udpRoute.Name, err)
 			}
 		}
 	}
@@ -199,9 +418,48 @@ func (pr *PrintRunner) outputResult(gatewayResources []i2gw.GatewayResources) {
 				referenceGrant.Annotations = make(map[string]string)
 			}
 			referenceGrant.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.CurrentVersion)
-			err := pr.resourcePrinter.PrintObj(&referenceGrant, os.Stdout)
+			err := pr.resourcePrinter.PrintObj(&referenceGrant, w)
+			if err != nil {
+				fmt.Fprintf(w, "# Error printing %s ReferenceGrant: %v\n", referenceGrant.Name, err)
+			}
+		}
+	}
+
+	for _, r := range gatewayResources {
+		resourceCount += len(r.BackendTLSPolicies)
+		for _, backendTLSPolicy := range r.BackendTLSPolicies {
+			backendTLSPolicy := backendTLSPolicy
+			if backendTLSPolicy.Annotations == nil {
+				backendTLSPolicy.Annotations = make(map[string]string)
+			}
+			backendTLSPolicy.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.CurrentVersion)
+			err := pr.resourcePrinter.PrintObj(&backendTLSPolicy, w)
+			if err != nil {
+				fmt.Fprintf(w, "# Error printing %s BackendTLSPolicy: %v\n", backendTLSPolicy.Name, err)
+			}
+		}
+	}
+
+	for _, r := range gatewayResources {
+		resourceCount += len(r.BackendLBPolicies)
+		for _, backendLBPolicy := range r.BackendLBPolicies {
+			backendLBPolicy := backendLBPolicy
+			if backendLBPolicy.Annotations == nil {
+				backendLBPolicy.Annotations = make(map[string]string)
+			}
+			backendLBPolicy.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.CurrentVersion)
+			err := pr.resourcePrinter.PrintObj(&backendLBPolicy, w)
+			if err != nil {
+				fmt.Fprintf(w, "# Error printing %s BackendLBPolicy: %v\n", backendLBPolicy.Name, err)
+			}
+		}
+	}
+
+	for _, r := range gatewayResources {
+		for _, source := range r.AnnotatedSources {
+			err := pr.resourcePrinter.PrintObj(source, w)
 			if err != nil {
-				fmt.Printf("# Error printing %s ReferenceGrant: %v\n", referenceGrant.Name, err)
+				fmt.Fprintf(w, "# Error printing %s source: %v\n", source.GetName(), err)
 			}
 		}
 	}
@@ -210,9 +468,9 @@ func (pr *PrintRunner) outputResult(gatewayResources []i2gw.GatewayResources) {
 		resourceCount += len(r.GatewayExtensions)
 		for _, gatewayExtension := range r.GatewayExtensions {
 			gatewayExtension := gatewayExtension
-			fmt.Println("---")
-			if err := PrintUnstructuredAsYaml(&gatewayExtension); err != nil {
-				fmt.Printf("# Error printing %s gatewayExtension: %v\n", gatewayExtension.GetName(), err)
+			fmt.Fprintln(w, "---")
+			if err := PrintUnstructuredAsYaml(w, &gatewayExtension); err != nil {
+				fmt.Fprintf(w, "# Error printing %s gatewayExtension: %v\n", gatewayExtension.GetName(), err)
 			}
 		}
 	}
@@ -222,8 +480,27 @@ func (pr *PrintRunner) outputResult(gatewayResources []i2gw.GatewayResources) {
 		if pr.namespaceFilter != "" {
 			msg = fmt.Sprintf("%s in %s namespace", msg, pr.namespaceFilter)
 		}
-		fmt.Println(msg)
+		fmt.Fprintln(w, msg)
+	}
+}
+
+// printUnsupportedFeatures prints the unsupported features of the HTTPRoute as
+// a commented-out stub following it. Comments can't be represented in JSON, so
+// the stubs are only printed in YAML.
+func (pr *PrintRunner) printUnsupportedFeatures(w io.Writer, httpRoute gatewayv1.HTTPRoute, unsupportedFeatures map[types.NamespacedName][]intermediate.UnsupportedFeature) {
+	if pr.outputFormat == "json" {
+		return
 	}
+	features := unsupportedFeatures[types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}]
+	if len(features) == 0 {
+		return
+	}
+	stub, err := i2gw.UnsupportedFeatureStub(types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}, features)
+	if err != nil {
+		fmt.Fprintf(w, "# Error printing %s unsupported features: %v\n", httpRoute.Name, err)
+		return
+	}
+	fmt.Fprint(w, stub)
 }
 
 // initializeResourcePrinter assign a specific type of printers.ResourcePrinter
@@ -288,6 +565,12 @@ func newPrintCommand() *cobra.Command {
 			if openAPIExist && len(pr.providers) != 1 {
 				return fmt.Errorf("openapi3 must be the only provider when specified")
 			}
+			if pr.signOutputProvenance && pr.signOutput == "" {
+				return fmt.Errorf("--sign-output-provenance requires --sign-output")
+			}
+			if pr.watch && (pr.inputFile != "" || pr.inputSnapshot != "") {
+				return fmt.Errorf("--watch requires reading the resources from the cluster")
+			}
 			return nil
 		},
 	}
@@ -298,6 +581,10 @@ func newPrintCommand() *cobra.Command {
 	cmd.Flags().StringVar(&pr.inputFile, "input-file", "",
 		`Path to the manifest file. When set, the tool will read ingresses from the file instead of reading from the cluster. Supported files are yaml and json.`)
 
+	cmd.Flags().StringVar(&pr.inputSnapshot, "input-snapshot", "",
+		`Path to a snapshot archive written by the snapshot command. When set, the tool will read the resources from the
+snapshot instead of reading from the cluster.`)
+
 	cmd.Flags().StringVarP(&pr.namespace, "namespace", "n", "",
 		`If present, the namespace scope for this CLI request.`)
 
@@ -308,6 +595,106 @@ if specified with --namespace.`)
 	cmd.Flags().StringSliceVar(&pr.providers, "providers", []string{},
 		fmt.Sprintf("If present, the tool will try to convert only resources related to the specified providers, supported values are %v.", i2gw.GetSupportedProviders()))
 
+	cmd.Flags().StringSliceVar(&pr.providerPriority, "provider-priority", []string{},
+		`Comma-separated list of providers taking precedence, in order, when several providers claim the same Ingress,
+e.g. an Ingress without class carrying annotations of several providers. Other providers are ranked alphabetically.`)
+
+	cmd.Flags().StringSliceVar(&pr.hostConflictPriority, "host-conflict-priority", []string{},
+		`Comma-separated list of providers taking precedence, in order, when the routes of several providers serve the
+same hostname, e.g. an Istio VirtualService and an Ingress. The hostname is removed from the routes of the other
+providers. If not specified, the conflicts are only reported.`)
+
+	cmd.Flags().BoolVar(&pr.redact, "redact", false,
+		`If present, sensitive values such as Secret names, credentials embedded in URLs and basic-auth data are masked
+in the notifications and the printed resources.`)
+
+	cmd.Flags().BoolVar(&pr.mesh, "mesh", false,
+		`If present, routes for east-west traffic are attached to Services instead of Gateways, as expected by GAMMA
+mesh implementations. Supported by the istio and ingress-nginx providers.`)
+
+	cmd.Flags().StringVar(&pr.clusterDomain, "cluster-domain", "cluster.local",
+		`The domain of the cluster-local Service hostnames, <service>.<namespace>.svc.<cluster-domain>, which are
+converted to references to the Services.`)
+
+	cmd.Flags().StringVar(&pr.profile, "profile", string(i2gw.BalancedProfile),
+		fmt.Sprintf(`The conversion profile, trading fidelity for safety. One of: (%s).`, strings.Join(i2gw.GetSupportedProfiles(), ", ")))
+
+	cmd.Flags().StringVar(&pr.gatewayStrategy, "gateway-strategy", string(i2gw.MergedGatewayStrategy),
+		fmt.Sprintf(`The strategy used to generate Gateways: merged Gateways shared by the source resources, or one Gateway
+per source resource for a 1:1 mapping. One of: (%s).`, strings.Join(i2gw.GetSupportedGatewayStrategies(), ", ")))
+
+	cmd.Flags().StringVar(&pr.listenerStrategy, "listener-strategy", string(i2gw.PerHostListenerStrategy),
+		fmt.Sprintf(`The strategy used to assign hostnames to listeners: a listener per hostname, a listener per TLS
+certificate, or a single listener per port. One of: (%s).`, strings.Join(i2gw.GetSupportedListenerStrategies(), ", ")))
+
+	cmd.Flags().StringSliceVar(&pr.enabledFeatures, "enable-features", []string{},
+		`If present, the features of the providers to convert, as <feature> for the feature of all the providers, or
+<provider>/<feature>, e.g. ingress-nginx/canary. The providers it names features of don't convert their other features.`)
+
+	cmd.Flags().StringSliceVar(&pr.disabledFeatures, "disable-features", []string{},
+		`The features of the providers not to convert, as <feature> or <provider>/<feature>, e.g. canary,kong/plugins,
+taking precedence over --enable-features. The annotations of the disabled features are ignored.`)
+
+	cmd.Flags().BoolVar(&pr.certManagerCertificates, "cert-manager-certificates", false,
+		`If present, cert-manager Certificates are generated for the TLS listeners of the Gateways annotated with
+a cert-manager issuer, and the cert-manager annotations of the Gateways are removed.`)
+
+	cmd.Flags().StringVar(&pr.hostnamePolicy, "hostname-policy", string(i2gw.SkipHostnamePolicy),
+		fmt.Sprintf(`The policy applied to the hostnames Gateway API rejects, e.g. with underscores or too long: fail the
+conversion, sanitize them, or drop them with warnings. One of: (%s).`, strings.Join(i2gw.GetSupportedHostnamePolicies(), ", ")))
+
+	cmd.Flags().BoolVar(&pr.annotateSources, "annotate-sources", false,
+		fmt.Sprintf(`If present, the source resources are printed along with the generated resources, annotated with the
+status of their conversion and the generated resources under the %s annotation.`, i2gw.SourceAnnotationKey))
+
+	cmd.Flags().StringVar(&pr.backendTLSWellKnownCACertificates, "backend-tls-well-known-ca-certificates", "",
+		fmt.Sprintf(`If set, the BackendTLSPolicies generated for backends the source resources indicate TLS to, but without
+referencing CA certificates, are validated with these well-known CA certificates. Otherwise, such BackendTLSPolicies
+are not generated. One of: (%s).`, strings.Join(i2gw.GetSupportedWellKnownCACertificates(), ", ")))
+
+	cmd.Flags().StringVar(&pr.emitter, "emitter", "",
+		fmt.Sprintf(`If set, the generated resources are extended with the implementation-specific resources of this Gateway API
+implementation, for the features exceeding the Gateway API core. Otherwise, only Gateway API resources are
+generated. One of: (%s).`, strings.Join(i2gw.GetSupportedEmitters(), ", ")))
+
+	cmd.Flags().BoolVar(&pr.noRouteMerge, "no-route-merge", false,
+		`If present, each source resource yields its own routes, even when its hosts overlap with other source resources,
+preserving the ownership boundaries and RBAC of the sources. Overrides the route merging of the profile.`)
+
+	cmd.Flags().StringVar(&pr.centralGatewayNamespace, "central-gateway-namespace", "",
+		`If set, the Gateways are generated in this namespace, shared by the routes of all the namespaces, as owned by a
+platform team, instead of in the namespace of each source resource. The listeners allow the routes of the namespaces
+of the sources, and ReferenceGrants are generated for their certificates. Can't be combined with the per-source
+gateway strategy.`)
+
+	cmd.Flags().StringToStringVar(&pr.gatewayClassMapping, "gateway-class-mapping", nil,
+		`Comma-separated list of <ingress-class>=<gateway-class> pairs declaring the GatewayClass serving each IngressClass,
+e.g. nginx=envoy,internal-nginx=private. The Gateways are sharded by GatewayClass: the Gateways of a namespace mapped
+to the same GatewayClass are merged into a Gateway named after it. Unmapped IngressClasses keep their class.`)
+
+	cmd.Flags().StringVar(&pr.overrideFile, "override", "",
+		`Path to a YAML file of overrides forcing fields of the HTTPRoutes generated from given source resources, e.g. the
+path type, the backend port or the parent listener, for the cases the conversion gets wrong.`)
+
+	cmd.Flags().StringVar(&pr.serviceMappingFile, "service-mapping", "",
+		`Path to a file mapping the Services referenced by the generated backends, mirrors included, to the Services they
+are renamed or moved to, one <namespace>/<name>=<namespace>/<name> pair per line. ReferenceGrants are generated
+for the Services moved to another namespace than the one of their routes.`)
+
+	cmd.Flags().StringVar(&pr.lintFor, "lint-for", "",
+		fmt.Sprintf(`If set, the generated HTTPRoutes are checked against the known incompatibilities of this Gateway API
+implementation, e.g. RegularExpression path matches it doesn't support, with a warning for each. Supported values
+are %v.`, i2gw.GetSupportedLintTargets()))
+
+	cmd.Flags().StringVar(&pr.gatewayAPIVersion, "gateway-api-version", i2gw.DefaultGatewayAPIVersion,
+		`The Gateway API version the generated resources conform to, as v<major>.<minor>. Later versions than the default
+enable their fields, e.g. v1.3 the percent and fraction of the requests RequestMirror filters mirror.`)
+
+	cmd.Flags().BoolVar(&pr.compatStripUnknown, "compat-strip-unknown", false,
+		`If set, the fields of the generated resources unknown to the CustomResourceDefinitions installed in the cluster
+of the current kubeconfig context, e.g. fields of Gateway API versions newer than the installed one, are removed
+before printing, with a warning for each resource.`)
+
 	pr.providerSpecificFlags = make(map[string]*string)
 	for provider, flags := range i2gw.GetProviderSpecificFlagDefinitions() {
 		for _, flag := range flags {
@@ -318,14 +705,34 @@ if specified with --namespace.`)
 
 	_ = cmd.MarkFlagRequired("providers")
 	cmd.MarkFlagsMutuallyExclusive("namespace", "all-namespaces")
+	cmd.MarkFlagsMutuallyExclusive("input-file", "input-snapshot")
+	cmd.Flags().StringVar(&pr.signOutput, "sign-output", "",
+		`If set, the printed manifests bundle is written to this directory along with a SHA256SUMS file of its checksum,
+for the resources applied after review to be verified against it.`)
+
+	cmd.Flags().BoolVar(&pr.signOutputProvenance, "sign-output-provenance", false,
+		`If set, a SLSA provenance predicate of the manifests bundle is written to the --sign-output directory too, to be
+signed with cosign attest-blob.`)
+
+	cmd.Flags().BoolVar(&pr.watch, "watch", false,
+		`If set, keep running after printing the resources, and print the resources added, changed or deleted by the
+changes of the Ingresses of the cluster as they happen.`)
+
+	// Redacted sources can't be applied back without breaking them.
+	cmd.MarkFlagsMutuallyExclusive("redact", "annotate-sources")
+	// The printed bundle is final, and the annotated sources aren't generated
+	// resources whose changes can be printed.
+	cmd.MarkFlagsMutuallyExclusive("watch", "sign-output")
+	cmd.MarkFlagsMutuallyExclusive("watch", "annotate-sources")
 	return cmd
 }
 
-// getNamespaceInCurrentContext returns the namespace in the current active context of the user.
+// getNamespaceInCurrentContext returns the namespace in the current active context of the user,
+// or in the context set via --context flag.
 func getNamespaceInCurrentContext() (string, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: kubeContext})
 	currentNamespace, _, err := kubeConfig.Namespace()
 
 	return currentNamespace, err
@@ -349,7 +756,7 @@ func (pr *PrintRunner) getProviderSpecificFlags() map[string]map[string]string {
 	return providerSpecificFlags
 }
 
-func PrintUnstructuredAsYaml(obj *unstructured.Unstructured) error {
+func PrintUnstructuredAsYaml(w io.Writer, obj *unstructured.Unstructured) error {
 	// Create a YAML serializer
 	serializer := json.NewSerializerWithOptions(json.DefaultMetaFactory, nil, nil,
 		json.SerializerOptions{
@@ -359,7 +766,7 @@ func PrintUnstructuredAsYaml(obj *unstructured.Unstructured) error {
 		})
 
 	// Encode the unstructured object to YAML
-	err := serializer.Encode(obj, os.Stdout)
+	err := serializer.Encode(obj, w)
 	if err != nil {
 		return err
 	}
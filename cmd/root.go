@@ -18,12 +18,40 @@ package cmd
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
-// kubeconfig indicates kubeconfig file location.
-var kubeconfig string
+// kubectlPluginPrefix is the prefix kubectl expects from plugin binaries, see
+// https://kubernetes.io/docs/tasks/extend-kubectl/kubectl-plugins/.
+const kubectlPluginPrefix = "kubectl-"
+
+var (
+	// kubeconfig indicates kubeconfig file location.
+	kubeconfig string
+
+	// kubeContext indicates the name of the kubeconfig context to use.
+	kubeContext string
+
+	// certificateAuthority is the path of the CA bundle the certificate of the
+	// API server is verified with.
+	certificateAuthority string
+
+	// impersonate is the user the cluster is read as.
+	impersonate string
+
+	// impersonateGroups are the groups the cluster is read as.
+	impersonateGroups []string
+
+	// showVersion indicates whether the version information is printed.
+	showVersion bool
+
+	// versionOutputFormat is the format the version information is printed
+	// in. Value assigned via --output/-o flag of the root command.
+	versionOutputFormat string
+)
 
 func newRootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
@@ -32,13 +60,46 @@ func newRootCmd() *cobra.Command {
 		PersistentPreRun: func(_ *cobra.Command, _ []string) {
 			getKubeconfig()
 		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !showVersion {
+				return cmd.Help()
+			}
+			return printVersion(cmd.OutOrStdout(), newVersionInfo(), versionOutputFormat)
+		},
+	}
+
+	// When invoked as a kubectl plugin, e.g. "kubectl ingress2gateway print",
+	// usage and help messages should reflect the kubectl invocation.
+	if isKubectlPlugin(os.Args[0]) {
+		rootCmd.Annotations = map[string]string{
+			cobra.CommandDisplayNameAnnotation: "kubectl ingress2gateway",
+		}
 	}
 
 	rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "",
 		`The kubeconfig file to use when talking to the cluster. If the flag is not set, a set of standard locations can be searched for an existing kubeconfig file.`)
+	rootCmd.PersistentFlags().StringVar(&kubeContext, "context", "",
+		`The name of the kubeconfig context to use. If the flag is not set, the current context is used.`)
+	rootCmd.PersistentFlags().StringVar(&certificateAuthority, "certificate-authority", "",
+		`Path to a cert file for the certificate authority of the API server. If the flag is not set, the one of the kubeconfig is used.`)
+	rootCmd.PersistentFlags().StringVar(&impersonate, "as", "",
+		`Username to impersonate when reading the cluster.`)
+	rootCmd.PersistentFlags().StringSliceVar(&impersonateGroups, "as-group", []string{},
+		`Group to impersonate when reading the cluster, this flag can be repeated to specify multiple groups. Requires --as.`)
+	rootCmd.Flags().BoolVar(&showVersion, "version", false,
+		`If present, print the version, the Gateway API version compiled against, and the supported providers and emitters with their support level.`)
+	rootCmd.Flags().StringVarP(&versionOutputFormat, "output", "o", "text",
+		`Output format of --version. One of: (text, json).`)
 	return rootCmd
 }
 
+// isKubectlPlugin returns true if the binary was invoked through its kubectl
+// plugin name, e.g. "kubectl-ingress2gateway".
+func isKubectlPlugin(binaryPath string) bool {
+	binaryName := strings.TrimSuffix(filepath.Base(binaryPath), ".exe")
+	return strings.HasPrefix(binaryName, kubectlPluginPrefix)
+}
+
 func getKubeconfig() {
 	if kubeconfig != "" {
 		os.Setenv("KUBECONFIG", kubeconfig)
@@ -48,6 +109,8 @@ func getKubeconfig() {
 func Execute() {
 	rootCmd := newRootCmd()
 	rootCmd.AddCommand(newPrintCommand())
+	rootCmd.AddCommand(newSnapshotCommand())
+	rootCmd.AddCommand(newExampleCommand())
 	err := rootCmd.Execute()
 	if err != nil {
 		os.Exit(1)
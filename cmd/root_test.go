@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import "testing"
+
+func Test_isKubectlPlugin(t *testing.T) {
+	testCases := []struct {
+		name       string
+		binaryPath string
+		expected   bool
+	}{
+		{
+			name:       "standalone binary",
+			binaryPath: "/usr/local/bin/ingress2gateway",
+			expected:   false,
+		},
+		{
+			name:       "kubectl plugin",
+			binaryPath: "/home/user/.krew/bin/kubectl-ingress2gateway",
+			expected:   true,
+		},
+		{
+			name:       "kubectl plugin on windows",
+			binaryPath: "kubectl-ingress2gateway.exe",
+			expected:   true,
+		},
+		{
+			name:       "relative path",
+			binaryPath: "./kubectl-ingress2gateway",
+			expected:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isKubectlPlugin(tc.binaryPath); got != tc.expected {
+				t.Errorf("isKubectlPlugin(%q) = %v, expected %v", tc.binaryPath, got, tc.expected)
+			}
+		})
+	}
+}
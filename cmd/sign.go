@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// checksumsFileName is the name of the checksums file, in the format read
+	// by sha256sum --check.
+	checksumsFileName = "SHA256SUMS"
+
+	// provenanceFileName is the name of the SLSA provenance predicate of the
+	// manifests bundle.
+	provenanceFileName = "provenance.json"
+
+	provenanceBuildType = "https://github.com/kubernetes-sigs/ingress2gateway/print@v1"
+	provenanceBuilderID = "https://github.com/kubernetes-sigs/ingress2gateway"
+)
+
+// provenancePredicate is a SLSA v1 provenance predicate. It is the predicate
+// of the in-toto attestation of the manifests bundle, which
+// `cosign attest-blob --type slsaprovenance1` wraps in a statement and signs.
+type provenancePredicate struct {
+	BuildDefinition buildDefinition `json:"buildDefinition"`
+	RunDetails      runDetails      `json:"runDetails"`
+}
+
+type buildDefinition struct {
+	BuildType          string            `json:"buildType"`
+	ExternalParameters map[string]string `json:"externalParameters"`
+	InternalParameters map[string]string `json:"internalParameters"`
+}
+
+type runDetails struct {
+	Builder  builder     `json:"builder"`
+	Metadata runMetadata `json:"metadata"`
+}
+
+type builder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version"`
+}
+
+type runMetadata struct {
+	StartedOn  time.Time `json:"startedOn"`
+	FinishedOn time.Time `json:"finishedOn"`
+}
+
+// signedOutputFile is a file written to the --sign-output directory.
+type signedOutputFile struct {
+	name    string
+	content []byte
+}
+
+// writeSignedOutput writes the printed manifests bundle to the --sign-output
+// directory, along with its checksums file and, if requested, its provenance
+// predicate.
+func (pr *PrintRunner) writeSignedOutput(bundle []byte, flags map[string]string, startedOn time.Time) error {
+	if err := os.MkdirAll(pr.signOutput, 0o755); err != nil {
+		return fmt.Errorf("failed to create the %s directory: %w", pr.signOutput, err)
+	}
+
+	bundleFileName := "manifests.yaml"
+	if pr.outputFormat == "json" {
+		bundleFileName = "manifests.json"
+	}
+	digest := sha256.Sum256(bundle)
+	checksums := fmt.Sprintf("%s  %s\n", hex.EncodeToString(digest[:]), bundleFileName)
+
+	files := []signedOutputFile{
+		{name: bundleFileName, content: bundle},
+		{name: checksumsFileName, content: []byte(checksums)},
+	}
+	if pr.signOutputProvenance {
+		provenance, err := json.MarshalIndent(newProvenancePredicate(flags, startedOn, time.Now()), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the provenance predicate: %w", err)
+		}
+		files = append(files, signedOutputFile{name: provenanceFileName, content: append(provenance, '\n')})
+	}
+
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(pr.signOutput, file.name), file.content, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "# Manifests bundle and checksums written to %s\n", pr.signOutput)
+	if pr.signOutputProvenance {
+		fmt.Fprintf(os.Stderr, "# Sign the provenance with: cosign attest-blob --type slsaprovenance1 --predicate %s %s\n",
+			filepath.Join(pr.signOutput, provenanceFileName), filepath.Join(pr.signOutput, bundleFileName))
+	}
+	return nil
+}
+
+// newProvenancePredicate returns the provenance predicate of a manifests
+// bundle generated with the given flags.
+func newProvenancePredicate(flags map[string]string, startedOn, finishedOn time.Time) provenancePredicate {
+	info := newVersionInfo()
+	version := map[string]string{
+		"ingress2gateway": info.Version,
+		"gatewayAPI":      info.GatewayAPIVersion,
+		"ir":              info.IRVersion,
+	}
+	if info.GitCommit != "" {
+		version["gitCommit"] = info.GitCommit
+	}
+	return provenancePredicate{
+		BuildDefinition: buildDefinition{
+			BuildType:          provenanceBuildType,
+			ExternalParameters: flags,
+			InternalParameters: map[string]string{"platform": info.Platform, "goVersion": info.GoVersion},
+		},
+		RunDetails: runDetails{
+			Builder: builder{
+				ID:      provenanceBuilderID,
+				Version: version,
+			},
+			Metadata: runMetadata{
+				StartedOn:  startedOn.UTC(),
+				FinishedOn: finishedOn.UTC(),
+			},
+		},
+	}
+}
+
+// changedFlags returns the flags set on the command line, by name.
+func changedFlags(cmd *cobra.Command) map[string]string {
+	flags := map[string]string{}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		flags[flag.Name] = flag.Value.String()
+	})
+	return flags
+}
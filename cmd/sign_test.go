@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_writeSignedOutput(t *testing.T) {
+	bundle := []byte("apiVersion: gateway.networking.k8s.io/v1\nkind: HTTPRoute\n")
+	digest := sha256.Sum256(bundle)
+	flags := map[string]string{"providers": "[ingress-nginx]", "input-file": "ingress.yaml"}
+
+	testCases := []struct {
+		name               string
+		outputFormat       string
+		provenance         bool
+		expectedBundleFile string
+	}{
+		{
+			name:               "yaml bundle without provenance",
+			outputFormat:       "yaml",
+			expectedBundleFile: "manifests.yaml",
+		},
+		{
+			name:               "json bundle with provenance",
+			outputFormat:       "json",
+			provenance:         true,
+			expectedBundleFile: "manifests.json",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "out")
+			pr := PrintRunner{outputFormat: tc.outputFormat, signOutput: dir, signOutputProvenance: tc.provenance}
+			if err := pr.writeSignedOutput(bundle, flags, time.Now()); err != nil {
+				t.Fatalf("writeSignedOutput() returned an error: %v", err)
+			}
+
+			written, err := os.ReadFile(filepath.Join(dir, tc.expectedBundleFile))
+			if err != nil {
+				t.Fatalf("failed to read the bundle: %v", err)
+			}
+			if string(written) != string(bundle) {
+				t.Errorf("bundle = %q, expected %q", written, bundle)
+			}
+
+			checksums, err := os.ReadFile(filepath.Join(dir, checksumsFileName))
+			if err != nil {
+				t.Fatalf("failed to read the checksums: %v", err)
+			}
+			expectedChecksums := hex.EncodeToString(digest[:]) + "  " + tc.expectedBundleFile + "\n"
+			if string(checksums) != expectedChecksums {
+				t.Errorf("checksums = %q, expected %q", checksums, expectedChecksums)
+			}
+
+			provenance, err := os.ReadFile(filepath.Join(dir, provenanceFileName))
+			if !tc.provenance {
+				if !os.IsNotExist(err) {
+					t.Errorf("expected no provenance, got error %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to read the provenance: %v", err)
+			}
+			var predicate provenancePredicate
+			if err := json.Unmarshal(provenance, &predicate); err != nil {
+				t.Fatalf("failed to unmarshal the provenance: %v", err)
+			}
+			if predicate.BuildDefinition.BuildType != provenanceBuildType {
+				t.Errorf("buildType = %q, expected %q", predicate.BuildDefinition.BuildType, provenanceBuildType)
+			}
+			if predicate.BuildDefinition.ExternalParameters["input-file"] != "ingress.yaml" {
+				t.Errorf("externalParameters = %v, expected the flags %v", predicate.BuildDefinition.ExternalParameters, flags)
+			}
+			if predicate.RunDetails.Builder.ID != provenanceBuilderID {
+				t.Errorf("builder.id = %q, expected %q", predicate.RunDetails.Builder.ID, provenanceBuilderID)
+			}
+		})
+	}
+}
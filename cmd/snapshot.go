@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/snapshot"
+	"github.com/spf13/cobra"
+)
+
+type SnapshotRunner struct {
+	// outputFile is the path of the written snapshot archive. Value assigned
+	// via --output/-o flag.
+	outputFile string
+
+	// The namespace the resources are read from. Value assigned via
+	// --namespace/-n flag.
+	// On absence, the current user active namespace is used.
+	namespace string
+
+	// allNamespaces indicates whether all namespaces should be used. Value assigned via
+	// --all-namespaces/-A flag.
+	allNamespaces bool
+
+	// providers indicates which providers the resources are read for.
+	providers []string
+}
+
+// WriteSnapshot reads the resources the providers read from the cluster, and
+// writes them to the snapshot archive.
+func (sr *SnapshotRunner) WriteSnapshot(cmd *cobra.Command, _ []string) error {
+	namespace := sr.namespace
+	if sr.allNamespaces {
+		namespace = ""
+	} else if namespace == "" {
+		ns, err := getNamespaceInCurrentContext()
+		if err != nil {
+			return err
+		}
+		namespace = ns
+	}
+
+	resources, err := i2gw.ReadSourceResources(cmd.Context(), i2gw.ConversionOptions{
+		KubeContext:          kubeContext,
+		CertificateAuthority: certificateAuthority,
+		Impersonate:          impersonate,
+		ImpersonateGroups:    impersonateGroups,
+		Namespace:            namespace,
+		Providers:            sr.providers,
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(sr.outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create the snapshot: %w", err)
+	}
+	defer file.Close()
+	if err = snapshot.Write(file, snapshot.Snapshot{
+		Metadata:  snapshot.Metadata{Providers: sr.providers, Namespace: namespace},
+		Resources: resources,
+	}); err != nil {
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("failed to write the snapshot: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d resources to %s\n", len(resources), sr.outputFile)
+	return nil
+}
+
+func newSnapshotCommand() *cobra.Command {
+	sr := &SnapshotRunner{}
+
+	// snapshotCmd represents the snapshot command. It exports the source
+	// resources of the providers for offline conversion.
+	var cmd = &cobra.Command{
+		Use:   "snapshot",
+		Short: "Exports the source resources the providers read from the cluster to an archive, for offline conversion with print --input-snapshot.",
+		RunE:  sr.WriteSnapshot,
+	}
+
+	cmd.Flags().StringVarP(&sr.outputFile, "output", "o", "snapshot.tar.gz",
+		`Path of the written snapshot archive.`)
+
+	cmd.Flags().StringVarP(&sr.namespace, "namespace", "n", "",
+		`If present, the namespace scope for this CLI request.`)
+
+	cmd.Flags().BoolVarP(&sr.allNamespaces, "all-namespaces", "A", false,
+		`If present, export the resources across all namespaces. Namespace in current context is ignored even
+if specified with --namespace.`)
+
+	cmd.Flags().StringSliceVar(&sr.providers, "providers", []string{},
+		fmt.Sprintf("The providers whose source resources are exported, supported values are %v.", i2gw.GetSupportedProviders()))
+
+	_ = cmd.MarkFlagRequired("providers")
+	cmd.MarkFlagsMutuallyExclusive("namespace", "all-namespaces")
+	return cmd
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+)
+
+// gatewayAPIModule is the module of the Gateway API types the binary is
+// compiled against.
+const gatewayAPIModule = "sigs.k8s.io/gateway-api"
+
+// versionInfo is the build information of the binary, and the providers and
+// emitters it supports, for tooling to check compatibility before invoking
+// conversions.
+type versionInfo struct {
+	Version           string       `json:"version"`
+	GitCommit         string       `json:"gitCommit,omitempty"`
+	GoVersion         string       `json:"goVersion"`
+	Platform          string       `json:"platform"`
+	GatewayAPIVersion string       `json:"gatewayAPIVersion"`
+	IRVersion         string       `json:"irVersion"`
+	Providers         []capability `json:"providers"`
+	Emitters          []capability `json:"emitters"`
+}
+
+// capability is a provider or an emitter, with its support level.
+type capability struct {
+	Name         string            `json:"name"`
+	SupportLevel i2gw.SupportLevel `json:"supportLevel"`
+}
+
+func newVersionInfo() versionInfo {
+	info := versionInfo{
+		Version:           i2gw.CurrentVersion,
+		GoVersion:         runtime.Version(),
+		Platform:          fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+		GatewayAPIVersion: "unknown",
+		IRVersion:         intermediate.IRVersion,
+		Providers:         []capability{},
+		Emitters:          []capability{},
+	}
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range buildInfo.Deps {
+			if dep.Path == gatewayAPIModule {
+				info.GatewayAPIVersion = dep.Version
+			}
+		}
+		for _, setting := range buildInfo.Settings {
+			if setting.Key == "vcs.revision" {
+				info.GitCommit = setting.Value
+			}
+		}
+	}
+
+	providers := i2gw.GetSupportedProviders()
+	slices.Sort(providers)
+	for _, name := range providers {
+		info.Providers = append(info.Providers, capability{Name: name, SupportLevel: i2gw.GetProviderSupportLevel(i2gw.ProviderName(name))})
+	}
+	for _, name := range i2gw.GetSupportedEmitters() {
+		info.Emitters = append(info.Emitters, capability{Name: name, SupportLevel: i2gw.GetEmitterSupportLevel(i2gw.EmitterName(name))})
+	}
+	return info
+}
+
+// printVersion prints the version information in the given output format,
+// text by default, or json.
+func printVersion(w io.Writer, info versionInfo, outputFormat string) error {
+	switch outputFormat {
+	case "", "text":
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(info)
+	default:
+		return fmt.Errorf("%s is not a supported version output format, supported values are [text json]", outputFormat)
+	}
+
+	fmt.Fprintf(w, "ingress2gateway %s\n", info.Version)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if info.GitCommit != "" {
+		fmt.Fprintf(tw, "Git commit:\t%s\n", info.GitCommit)
+	}
+	fmt.Fprintf(tw, "Go version:\t%s\n", info.GoVersion)
+	fmt.Fprintf(tw, "Platform:\t%s\n", info.Platform)
+	fmt.Fprintf(tw, "Gateway API version:\t%s\n", info.GatewayAPIVersion)
+	fmt.Fprintf(tw, "IR version:\t%s\n", info.IRVersion)
+	fmt.Fprintln(tw, "\nProviders:")
+	for _, provider := range info.Providers {
+		fmt.Fprintf(tw, "  %s\t%s\n", provider.Name, provider.SupportLevel)
+	}
+	fmt.Fprintln(tw, "\nEmitters:")
+	for _, emitter := range info.Emitters {
+		fmt.Fprintf(tw, "  %s\t%s\n", emitter.Name, emitter.SupportLevel)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+)
+
+func Test_printVersion(t *testing.T) {
+	info := versionInfo{
+		Version:           "0.3.0",
+		GoVersion:         "go1.22.0",
+		Platform:          "linux/amd64",
+		GatewayAPIVersion: "v1.1.0",
+		IRVersion:         "v1alpha1",
+		Providers:         []capability{{Name: "ingress-nginx", SupportLevel: i2gw.StableSupportLevel}},
+		Emitters:          []capability{{Name: "istio", SupportLevel: i2gw.ExperimentalSupportLevel}},
+	}
+
+	var text bytes.Buffer
+	if err := printVersion(&text, info, "text"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{"ingress2gateway 0.3.0\n", "Gateway API version:  v1.1.0\n", "  ingress-nginx  stable\n", "  istio  experimental\n"} {
+		if !strings.Contains(text.String(), expected) {
+			t.Errorf("expected the text output to contain %q, got:\n%s", expected, text.String())
+		}
+	}
+
+	var output bytes.Buffer
+	if err := printVersion(&output, info, "json"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded versionInfo
+	if err := json.Unmarshal(output.Bytes(), &decoded); err != nil {
+		t.Fatalf("failed to decode the json output: %v", err)
+	}
+	if decoded.GatewayAPIVersion != info.GatewayAPIVersion || len(decoded.Providers) != 1 || decoded.Providers[0] != info.Providers[0] {
+		t.Errorf("expected %+v, got %+v", info, decoded)
+	}
+
+	if err := printVersion(&output, info, "yaml"); err == nil {
+		t.Errorf("expected an error for the yaml output format")
+	}
+}
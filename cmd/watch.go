@@ -0,0 +1,181 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// watchDebounce is the period the changes of the Ingresses are gathered
+// during before converting them again, as an apply often changes several.
+const watchDebounce = time.Second
+
+// resourceID identifies a printed resource across conversions.
+type resourceID struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func (id resourceID) String() string {
+	return fmt.Sprintf("%s %s/%s", id.kind, id.namespace, id.name)
+}
+
+// watchGatewayAPIObjects converts the resources again whenever the Ingresses
+// of the cluster change, until the context is done. It prints the resources
+// added or changed since the previous conversion, and lists the deleted ones
+// along with the notifications of the changed ones on stderr, for stdout to
+// remain a stream of resources.
+func (pr *PrintRunner) watchGatewayAPIObjects(ctx context.Context, opts i2gw.ConversionOptions, gatewayResources []i2gw.GatewayResources) error {
+	printed := pr.printedResources(gatewayResources)
+	fmt.Fprintln(os.Stderr, "# Watching the Ingresses of the cluster for changes")
+	return i2gw.WatchIngresses(ctx, opts, watchDebounce, func() {
+		// The notifications of each conversion are reported on their own.
+		notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+		result, err := i2gw.ToGatewayAPIResources(ctx, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "# Failed to convert the changed resources: %v\n", err)
+			return
+		}
+		current := pr.printedResources(result.GatewayResources)
+		pr.printChanges(os.Stdout, os.Stderr, printed, current, resourceNotifications(result))
+		printed = current
+	})
+}
+
+// printChanges prints the resources of current which are not printed the same
+// in previous to w, and the deleted resources and the notifications of the
+// printed ones to notes.
+func (pr *PrintRunner) printChanges(w, notes io.Writer, previous, current map[resourceID]string, notificationsByID map[resourceID][]notifications.Notification) {
+	var changed, deleted []resourceID
+	for id, document := range current {
+		if previous[id] != document {
+			changed = append(changed, id)
+		}
+	}
+	for id := range previous {
+		if _, ok := current[id]; !ok {
+			deleted = append(deleted, id)
+		}
+	}
+	sortResourceIDs(changed)
+	sortResourceIDs(deleted)
+
+	fmt.Fprintf(notes, "# Ingresses changed: %d resources added or changed, %d deleted\n", len(changed), len(deleted))
+	for _, id := range deleted {
+		fmt.Fprintf(notes, "# Deleted %s\n", id)
+	}
+	for _, id := range changed {
+		for _, n := range notificationsByID[id] {
+			fmt.Fprintf(notes, "# %s %s: %s\n", n.Type, id, n.Message)
+		}
+		if pr.outputFormat != "json" {
+			fmt.Fprintln(w, "---")
+		}
+		fmt.Fprint(w, current[id])
+	}
+}
+
+// printedResources returns the output of each resource, printed on its own
+// as by outputResult.
+func (pr *PrintRunner) printedResources(gatewayResources []i2gw.GatewayResources) map[resourceID]string {
+	printed := map[resourceID]string{}
+	for _, r := range gatewayResources {
+		split := func(id resourceID, set func(*i2gw.GatewayResources)) {
+			single := i2gw.GatewayResources{UnsupportedFeatures: r.UnsupportedFeatures, MirrorFractions: r.MirrorFractions}
+			set(&single)
+			// Each resource is printed by its own printer, without the
+			// separator of the following documents. The output format was
+			// validated by the first print.
+			printer := *pr
+			_ = printer.initializeResourcePrinter()
+			var output bytes.Buffer
+			printer.outputResult(&output, []i2gw.GatewayResources{single})
+			printed[id] = output.String()
+		}
+		splitResources(split, "GatewayClass", r.GatewayClasses, func(s *i2gw.GatewayResources, m map[types.NamespacedName]gatewayv1.GatewayClass) {
+			s.GatewayClasses = m
+		})
+		splitResources(split, "Gateway", r.Gateways, func(s *i2gw.GatewayResources, m map[types.NamespacedName]gatewayv1.Gateway) { s.Gateways = m })
+		splitResources(split, "HTTPRoute", r.HTTPRoutes, func(s *i2gw.GatewayResources, m map[types.NamespacedName]gatewayv1.HTTPRoute) { s.HTTPRoutes = m })
+		splitResources(split, "GRPCRoute", r.GRPCRoutes, func(s *i2gw.GatewayResources, m map[types.NamespacedName]gatewayv1.GRPCRoute) { s.GRPCRoutes = m })
+		splitResources(split, "TLSRoute", r.TLSRoutes, func(s *i2gw.GatewayResources, m map[types.NamespacedName]gatewayv1alpha2.TLSRoute) { s.TLSRoutes = m })
+		splitResources(split, "TCPRoute", r.TCPRoutes, func(s *i2gw.GatewayResources, m map[types.NamespacedName]gatewayv1alpha2.TCPRoute) { s.TCPRoutes = m })
+		splitResources(split, "UDPRoute", r.UDPRoutes, func(s *i2gw.GatewayResources, m map[types.NamespacedName]gatewayv1alpha2.UDPRoute) { s.UDPRoutes = m })
+		splitResources(split, "ReferenceGrant", r.ReferenceGrants, func(s *i2gw.GatewayResources, m map[types.NamespacedName]gatewayv1beta1.ReferenceGrant) {
+			s.ReferenceGrants = m
+		})
+		splitResources(split, "BackendTLSPolicy", r.BackendTLSPolicies, func(s *i2gw.GatewayResources, m map[types.NamespacedName]gatewayv1alpha3.BackendTLSPolicy) {
+			s.BackendTLSPolicies = m
+		})
+		splitResources(split, "BackendLBPolicy", r.BackendLBPolicies, func(s *i2gw.GatewayResources, m map[types.NamespacedName]gatewayv1alpha2.BackendLBPolicy) {
+			s.BackendLBPolicies = m
+		})
+		for _, extension := range r.GatewayExtensions {
+			extension := extension
+			split(resourceID{kind: extension.GetKind(), namespace: extension.GetNamespace(), name: extension.GetName()}, func(s *i2gw.GatewayResources) {
+				s.GatewayExtensions = []unstructured.Unstructured{extension}
+			})
+		}
+	}
+	return printed
+}
+
+// splitResources splits the resources of the kind, calling split for each
+// of them with the function setting it alone in a GatewayResources.
+func splitResources[T any](split func(resourceID, func(*i2gw.GatewayResources)), kind string, resources map[types.NamespacedName]T, set func(*i2gw.GatewayResources, map[types.NamespacedName]T)) {
+	for key, resource := range resources {
+		key, resource := key, resource
+		split(resourceID{kind: kind, namespace: key.Namespace, name: key.Name}, func(s *i2gw.GatewayResources) {
+			set(s, map[types.NamespacedName]T{key: resource})
+		})
+	}
+}
+
+// resourceNotifications returns the notifications of the resources of the
+// result, by resourceID.
+func resourceNotifications(result i2gw.ConversionResult) map[resourceID][]notifications.Notification {
+	notificationsByID := map[resourceID][]notifications.Notification{}
+	for gvk, resources := range result.Resources {
+		for _, resource := range resources {
+			id := resourceID{kind: gvk.Kind, namespace: resource.Object.GetNamespace(), name: resource.Object.GetName()}
+			notificationsByID[id] = append(notificationsByID[id], resource.Notifications...)
+		}
+	}
+	return notificationsByID
+}
+
+func sortResourceIDs(ids []resourceID) {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_printChanges(t *testing.T) {
+	httpRoute := func(name string, hostname gatewayv1.Hostname) gatewayv1.HTTPRoute {
+		return gatewayv1.HTTPRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: gatewayv1.GroupVersion.String(), Kind: "HTTPRoute"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       gatewayv1.HTTPRouteSpec{Hostnames: []gatewayv1.Hostname{hostname}},
+		}
+	}
+	gateway := gatewayv1.Gateway{
+		TypeMeta:   metav1.TypeMeta{APIVersion: gatewayv1.GroupVersion.String(), Kind: "Gateway"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+	}
+	previous := []i2gw.GatewayResources{{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{{Namespace: "default", Name: "nginx"}: gateway},
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+			{Namespace: "default", Name: "a"}: httpRoute("a", "a.example.com"),
+			{Namespace: "default", Name: "b"}: httpRoute("b", "b.example.com"),
+		},
+	}}
+	current := []i2gw.GatewayResources{{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{{Namespace: "default", Name: "nginx"}: gateway},
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+			{Namespace: "default", Name: "a"}: httpRoute("a", "a.example.org"),
+			{Namespace: "default", Name: "c"}: httpRoute("c", "c.example.com"),
+		},
+	}}
+	notificationsByID := map[resourceID][]notifications.Notification{
+		{kind: "HTTPRoute", namespace: "default", name: "a"}:   {notifications.NewNotification(notifications.WarningNotification, "unsupported annotation")},
+		{kind: "Gateway", namespace: "default", name: "nginx"}: {notifications.NewNotification(notifications.InfoNotification, "unchanged")},
+	}
+
+	pr := &PrintRunner{outputFormat: "yaml"}
+	var output, notes bytes.Buffer
+	pr.printChanges(&output, &notes, pr.printedResources(previous), pr.printedResources(current), notificationsByID)
+
+	expectedNotes := `# Ingresses changed: 2 resources added or changed, 1 deleted
+# Deleted HTTPRoute default/b
+# WARNING HTTPRoute default/a: unsupported annotation
+`
+	if diff := cmp.Diff(expectedNotes, notes.String()); diff != "" {
+		t.Errorf("Unexpected notes (-want +got): %s", diff)
+	}
+
+	documents := strings.Split(output.String(), "---\n")
+	if len(documents) != 3 || documents[0] != "" {
+		t.Fatalf("Expected 2 printed resources, got:\n%s", output.String())
+	}
+	for i, expected := range []string{"name: a\n", "name: c\n"} {
+		if !strings.Contains(documents[i+1], expected) || !strings.Contains(documents[i+1], "kind: HTTPRoute") {
+			t.Errorf("Expected resource %d to be HTTPRoute with %q, got:\n%s", i, expected, documents[i+1])
+		}
+	}
+}
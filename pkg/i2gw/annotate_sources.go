@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// SourceAnnotationKey is the annotation set on the source resources when
+// ConversionOptions.AnnotateSources is enabled. Its value is a JSON encoded
+// ConversionSummary.
+const SourceAnnotationKey = "ingress2gateway.sigs.k8s.io/conversion-summary"
+
+// ConversionStatus describes the outcome of the conversion of a source resource.
+type ConversionStatus string
+
+const (
+	// ConvertedStatus indicates the source resource was converted without
+	// warnings.
+	ConvertedStatus ConversionStatus = "Converted"
+	// ConvertedWithWarningsStatus indicates warnings were reported while
+	// converting the source resource, which should be reviewed.
+	ConvertedWithWarningsStatus ConversionStatus = "ConvertedWithWarnings"
+)
+
+// ConversionSummary is the content of the SourceAnnotationKey annotation.
+type ConversionSummary struct {
+	Status    ConversionStatus `json:"status"`
+	Generator string           `json:"generator"`
+	// Resources lists the generated resources as <Kind>/<namespace>/<name>.
+	Resources []string `json:"resources"`
+}
+
+// annotateSources returns a copy of the sources of the IR HTTPRoutes, annotated
+// with the summary of their conversion. The status of each source is computed
+// from the notifications reported so far by the provider and the common
+// conversion logic.
+func annotateSources(providerName ProviderName, ir intermediate.IR) ([]client.Object, error) {
+	var sources []client.Object
+	resourcesBySource := map[client.Object][]string{}
+	for key, httpRouteContext := range ir.HTTPRoutes {
+		generated := []string{fmt.Sprintf("HTTPRoute/%s", key)}
+		for _, gatewayKey := range gatewayParentRefKeys(key, httpRouteContext.Spec.ParentRefs) {
+			generated = append(generated, fmt.Sprintf("Gateway/%s", gatewayKey))
+		}
+		for _, source := range httpRouteContext.Sources {
+			if _, ok := resourcesBySource[source]; !ok {
+				sources = append(sources, source)
+			}
+			resourcesBySource[source] = append(resourcesBySource[source], generated...)
+		}
+	}
+
+	notificationsByProvider := notifications.NotificationAggr.Notifications
+	reported := append(slices.Clone(notificationsByProvider[string(providerName)]), notificationsByProvider["common"]...)
+
+	annotatedSources := make([]client.Object, 0, len(sources))
+	for _, source := range sources {
+		resources := resourcesBySource[source]
+		slices.Sort(resources)
+		summary := ConversionSummary{
+			Status:    ConvertedStatus,
+			Generator: fmt.Sprintf("ingress2gateway-%s", CurrentVersion),
+			Resources: slices.Compact(resources),
+		}
+		if hasWarnings(reported, source) {
+			summary.Status = ConvertedWithWarningsStatus
+		}
+		value, err := json.Marshal(summary)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the conversion summary of %s: %w", client.ObjectKeyFromObject(source), err)
+		}
+
+		annotated := source.DeepCopyObject().(client.Object)
+		if annotated.GetObjectKind().GroupVersionKind().Empty() {
+			gvk, err := apiutil.GVKForObject(annotated, clientgoscheme.Scheme)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the kind of %s: %w", client.ObjectKeyFromObject(source), err)
+			}
+			annotated.GetObjectKind().SetGroupVersionKind(gvk)
+		}
+		annotations := annotated.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[SourceAnnotationKey] = string(value)
+		annotated.SetAnnotations(annotations)
+		annotatedSources = append(annotatedSources, annotated)
+	}
+
+	slices.SortFunc(annotatedSources, func(a, b client.Object) int {
+		return strings.Compare(client.ObjectKeyFromObject(a).String(), client.ObjectKeyFromObject(b).String())
+	})
+	return annotatedSources, nil
+}
+
+// hasWarnings returns true if any of the notifications is a warning about the
+// given source.
+func hasWarnings(reported []notifications.Notification, source client.Object) bool {
+	for _, n := range reported {
+		if n.Type != notifications.WarningNotification {
+			continue
+		}
+		for _, callingObject := range n.CallingObjects {
+			if reflect.TypeOf(callingObject) == reflect.TypeOf(source) &&
+				client.ObjectKeyFromObject(callingObject) == client.ObjectKeyFromObject(source) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_annotateSources(t *testing.T) {
+	foo := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}}
+	bar := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar", Annotations: map[string]string{"team": "bar"}}}
+
+	notifications.NotificationAggr = notifications.NotificationAggregator{Notifications: map[string][]notifications.Notification{}}
+	notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification, "warning", bar), "test")
+
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			{Namespace: "default", Name: "foo-example-com"}: {
+				HTTPRoute: gatewayv1.HTTPRoute{
+					Spec: gatewayv1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayv1.CommonRouteSpec{
+							ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}},
+						},
+					},
+				},
+				Sources: []client.Object{foo, bar},
+			},
+			{Namespace: "default", Name: "bar-example-net"}: {
+				Sources: []client.Object{bar},
+			},
+		},
+	}
+
+	annotatedSources, err := annotateSources("test", ir)
+	require.NoError(t, err)
+	require.Len(t, annotatedSources, 2)
+
+	wantSummaries := []ConversionSummary{
+		{
+			Status:    ConvertedWithWarningsStatus,
+			Generator: "ingress2gateway-" + CurrentVersion,
+			Resources: []string{"Gateway/default/nginx", "HTTPRoute/default/bar-example-net", "HTTPRoute/default/foo-example-com"},
+		},
+		{
+			Status:    ConvertedStatus,
+			Generator: "ingress2gateway-" + CurrentVersion,
+			Resources: []string{"Gateway/default/nginx", "HTTPRoute/default/foo-example-com"},
+		},
+	}
+	for i, source := range annotatedSources {
+		require.Equal(t, "Ingress", source.GetObjectKind().GroupVersionKind().Kind)
+		var summary ConversionSummary
+		require.NoError(t, json.Unmarshal([]byte(source.GetAnnotations()[SourceAnnotationKey]), &summary))
+		require.Equal(t, wantSummaries[i], summary)
+	}
+	require.Equal(t, "bar", annotatedSources[0].GetAnnotations()["team"])
+
+	// The sources read by the provider must not be modified.
+	require.NotContains(t, bar.Annotations, SourceAnnotationKey)
+	require.Nil(t, foo.Annotations)
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"slices"
+
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+)
+
+// GetSupportedWellKnownCACertificates returns the well-known CA certificates
+// the generated BackendTLSPolicies may be validated with.
+func GetSupportedWellKnownCACertificates() []string {
+	return []string{string(gatewayv1alpha3.WellKnownCACertificatesSystem)}
+}
+
+// validateWellKnownCACertificates returns an error if the given well-known CA
+// certificates are not supported. An empty value disables their use.
+func validateWellKnownCACertificates(wellKnownCACertificates gatewayv1alpha3.WellKnownCACertificatesType) error {
+	if wellKnownCACertificates != "" && !slices.Contains(GetSupportedWellKnownCACertificates(), string(wellKnownCACertificates)) {
+		return fmt.Errorf("%s are not supported well-known CA certificates, supported values are %v", wellKnownCACertificates, GetSupportedWellKnownCACertificates())
+	}
+	return nil
+}
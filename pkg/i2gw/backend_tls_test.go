@@ -0,0 +1,30 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+)
+
+func Test_validateWellKnownCACertificates(t *testing.T) {
+	require.NoError(t, validateWellKnownCACertificates(""))
+	require.NoError(t, validateWellKnownCACertificates(gatewayv1alpha3.WellKnownCACertificatesSystem))
+	require.Error(t, validateWellKnownCACertificates("Mozilla"))
+}
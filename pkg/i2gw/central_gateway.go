@@ -0,0 +1,225 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// namespaceNameLabel is the label set by Kubernetes on each Namespace to its
+// name, used to select the namespaces of the routes allowed on a listener.
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// validateCentralGatewayNamespace returns an error if the given namespace of
+// the central Gateways is not a valid namespace name, or can't be combined
+// with the given Gateway strategy. An empty namespace means the Gateways stay
+// in the namespaces of their sources.
+func validateCentralGatewayNamespace(namespace string, strategy GatewayStrategy) error {
+	if namespace == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("%s is not a valid central gateway namespace: %s", namespace, strings.Join(errs, ", "))
+	}
+	if strategy == PerSourceGatewayStrategy {
+		return fmt.Errorf("a central gateway namespace can't be combined with the %s gateway strategy", PerSourceGatewayStrategy)
+	}
+	return nil
+}
+
+// centralizeGateways moves the Gateways of the IR to the given namespace,
+// merging the Gateways of the same name, e.g. of the same IngressClass, from
+// all the namespaces into a single Gateway shared by their routes, as owned by
+// a platform team. The listeners only allow the routes of the namespaces the
+// Gateways were generated in, and reference their certificates across
+// namespaces, allowed by generated ReferenceGrants.
+func centralizeGateways(providerName ProviderName, ir *intermediate.IR, namespace string) {
+	if len(ir.Gateways) == 0 {
+		return
+	}
+
+	centralGateways := map[types.NamespacedName]intermediate.GatewayContext{}
+	routeNamespaces := map[types.NamespacedName]sets.Set[string]{}
+	secretNamespaces := sets.New[string]()
+	for _, key := range sortedNamespacedNames(ir.Gateways) {
+		gatewayContext := ir.Gateways[key]
+		centralKey := types.NamespacedName{Namespace: namespace, Name: key.Name}
+		centralGateway, ok := centralGateways[centralKey]
+		if !ok {
+			centralGateway = intermediate.GatewayContext{
+				Gateway:            *gatewayContext.Gateway.DeepCopy(),
+				ProviderSpecificIR: gatewayContext.ProviderSpecificIR,
+			}
+			centralGateway.Namespace = namespace
+			centralGateway.Spec.Listeners = nil
+			routeNamespaces[centralKey] = sets.New[string]()
+		}
+		routeNamespaces[centralKey].Insert(key.Namespace)
+
+		for _, listener := range gatewayContext.Spec.Listeners {
+			listener := *listener.DeepCopy()
+			if listener.TLS != nil {
+				for i, ref := range listener.TLS.CertificateRefs {
+					refNamespace := key.Namespace
+					if ref.Namespace != nil {
+						refNamespace = string(*ref.Namespace)
+					}
+					if refNamespace != namespace {
+						listener.TLS.CertificateRefs[i].Namespace = ptr.To(gatewayv1.Namespace(refNamespace))
+						secretNamespaces.Insert(refNamespace)
+					}
+				}
+			}
+			centralGateway.Spec.Listeners = mergeCentralListener(centralGateway.Spec.Listeners, listener, key.Namespace)
+		}
+		centralGateways[centralKey] = centralGateway
+	}
+
+	for centralKey, namespaces := range routeNamespaces {
+		if namespaces.Equal(sets.New(namespace)) {
+			continue
+		}
+		centralGateway := centralGateways[centralKey]
+		for i := range centralGateway.Spec.Listeners {
+			allowedRoutes := centralGateway.Spec.Listeners[i].AllowedRoutes
+			if allowedRoutes == nil {
+				allowedRoutes = &gatewayv1.AllowedRoutes{}
+			}
+			allowedRoutes.Namespaces = &gatewayv1.RouteNamespaces{
+				From: ptr.To(gatewayv1.NamespacesFromSelector),
+				Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      namespaceNameLabel,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   sets.List(namespaces),
+				}}},
+			}
+			centralGateway.Spec.Listeners[i].AllowedRoutes = allowedRoutes
+		}
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.InfoNotification,
+			fmt.Sprintf("Gateway %s is shared by the routes of namespaces %v", centralKey, sets.List(namespaces)),
+			&centralGateway.Gateway), string(providerName))
+	}
+
+	for key, httpRouteContext := range ir.HTTPRoutes {
+		centralizeParentRefs(key.Namespace, httpRouteContext.Spec.ParentRefs, ir.Gateways, namespace)
+	}
+	for key, route := range ir.GRPCRoutes {
+		centralizeParentRefs(key.Namespace, route.Spec.ParentRefs, ir.Gateways, namespace)
+	}
+	for key, route := range ir.TLSRoutes {
+		centralizeParentRefs(key.Namespace, route.Spec.ParentRefs, ir.Gateways, namespace)
+	}
+	for key, route := range ir.TCPRoutes {
+		centralizeParentRefs(key.Namespace, route.Spec.ParentRefs, ir.Gateways, namespace)
+	}
+	for key, route := range ir.UDPRoutes {
+		centralizeParentRefs(key.Namespace, route.Spec.ParentRefs, ir.Gateways, namespace)
+	}
+	ir.Gateways = centralGateways
+
+	for _, secretNamespace := range sets.List(secretNamespaces) {
+		addSecretReferenceGrant(ir, namespace, secretNamespace)
+	}
+}
+
+// mergeCentralListener adds the listener, of a Gateway of the given namespace,
+// to the listeners of a central Gateway. Listeners of the same name, port,
+// protocol and hostname are merged, while listeners only sharing the name are
+// suffixed with their namespace.
+func mergeCentralListener(listeners []gatewayv1.Listener, listener gatewayv1.Listener, namespace string) []gatewayv1.Listener {
+	i := slices.IndexFunc(listeners, func(l gatewayv1.Listener) bool { return l.Name == listener.Name })
+	if i < 0 {
+		return append(listeners, listener)
+	}
+	existing := &listeners[i]
+	if existing.Port != listener.Port || existing.Protocol != listener.Protocol || !ptrEqual(existing.Hostname, listener.Hostname) {
+		listener.Name = gatewayv1.SectionName(fmt.Sprintf("%s-%s", listener.Name, namespace))
+		return append(listeners, listener)
+	}
+	if listener.TLS != nil {
+		if existing.TLS == nil {
+			existing.TLS = listener.TLS
+		} else {
+			for _, ref := range listener.TLS.CertificateRefs {
+				if !slices.Contains(existing.TLS.CertificateRefs, ref) {
+					existing.TLS.CertificateRefs = append(existing.TLS.CertificateRefs, ref)
+				}
+			}
+		}
+	}
+	return listeners
+}
+
+// centralizeParentRefs points the parentRefs of a route of the given namespace
+// to the central Gateways replacing the Gateways they referenced.
+func centralizeParentRefs(routeNamespace string, parentRefs []gatewayv1.ParentReference, gateways map[types.NamespacedName]intermediate.GatewayContext, namespace string) {
+	for i, parentRef := range parentRefs {
+		if (parentRef.Group != nil && *parentRef.Group != gatewayv1.GroupName) || (parentRef.Kind != nil && *parentRef.Kind != "Gateway") {
+			continue
+		}
+		gatewayKey := types.NamespacedName{Namespace: routeNamespace, Name: string(parentRef.Name)}
+		if parentRef.Namespace != nil {
+			gatewayKey.Namespace = string(*parentRef.Namespace)
+		}
+		if _, ok := gateways[gatewayKey]; !ok {
+			continue
+		}
+		parentRefs[i].Namespace = nil
+		if routeNamespace != namespace {
+			parentRefs[i].Namespace = ptr.To(gatewayv1.Namespace(namespace))
+		}
+	}
+}
+
+// addSecretReferenceGrant adds to the IR the ReferenceGrant allowing the
+// Gateways of the given namespace to reference the Secrets of another one.
+func addSecretReferenceGrant(ir *intermediate.IR, gatewayNamespace, secretNamespace string) {
+	if ir.ReferenceGrants == nil {
+		ir.ReferenceGrants = map[types.NamespacedName]gatewayv1beta1.ReferenceGrant{}
+	}
+	key := types.NamespacedName{Namespace: secretNamespace, Name: fmt.Sprintf("generated-reference-grant-from-%s-to-%s", gatewayNamespace, secretNamespace)}
+	referenceGrant, ok := ir.ReferenceGrants[key]
+	if !ok {
+		referenceGrant = gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		}
+		referenceGrant.SetGroupVersionKind(gatewayv1beta1.SchemeGroupVersion.WithKind("ReferenceGrant"))
+	}
+	from := gatewayv1beta1.ReferenceGrantFrom{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: gatewayv1.Namespace(gatewayNamespace)}
+	if !slices.Contains(referenceGrant.Spec.From, from) {
+		referenceGrant.Spec.From = append(referenceGrant.Spec.From, from)
+	}
+	to := gatewayv1beta1.ReferenceGrantTo{Group: "", Kind: "Secret"}
+	if !slices.ContainsFunc(referenceGrant.Spec.To, func(t gatewayv1beta1.ReferenceGrantTo) bool {
+		return t.Group == to.Group && t.Kind == to.Kind && t.Name == nil
+	}) {
+		referenceGrant.Spec.To = append(referenceGrant.Spec.To, to)
+	}
+	ir.ReferenceGrants[key] = referenceGrant
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_validateCentralGatewayNamespace(t *testing.T) {
+	require.NoError(t, validateCentralGatewayNamespace("", PerSourceGatewayStrategy))
+	require.NoError(t, validateCentralGatewayNamespace("gateway-system", MergedGatewayStrategy))
+	require.Error(t, validateCentralGatewayNamespace("Gateway_System", MergedGatewayStrategy))
+	require.Error(t, validateCentralGatewayNamespace("gateway-system", PerSourceGatewayStrategy))
+}
+
+func Test_centralizeGateways(t *testing.T) {
+	gateway := func(namespace string, listeners ...gatewayv1.Listener) intermediate.GatewayContext {
+		return intermediate.GatewayContext{Gateway: gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "nginx"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "nginx", Listeners: listeners},
+		}}
+	}
+	httpRoute := func(namespace string) intermediate.HTTPRouteContext {
+		return intermediate.HTTPRouteContext{HTTPRoute: gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "route"},
+			Spec: gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}},
+			}},
+		}}
+	}
+	https := func(certificateName string) gatewayv1.Listener {
+		return gatewayv1.Listener{
+			Name:     "example-com-https",
+			Hostname: ptr.To[gatewayv1.Hostname]("example.com"),
+			Port:     443,
+			Protocol: gatewayv1.HTTPSProtocolType,
+			TLS: &gatewayv1.GatewayTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{
+				{Name: gatewayv1.ObjectName(certificateName)},
+			}},
+		}
+	}
+
+	t.Run("Gateways of several namespaces", func(t *testing.T) {
+		ir := intermediate.IR{
+			Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+				{Namespace: "a", Name: "nginx"}: gateway("a", https("a-cert")),
+				{Namespace: "b", Name: "nginx"}: gateway("b", https("b-cert"), gatewayv1.Listener{Name: "example-com-https", Port: 8443, Protocol: gatewayv1.HTTPSProtocolType}),
+			},
+			HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+				{Namespace: "a", Name: "route"}:              httpRoute("a"),
+				{Namespace: "gateway-system", Name: "route"}: httpRoute("gateway-system"),
+			},
+		}
+		centralizeGateways("test", &ir, "gateway-system")
+
+		require.Len(t, ir.Gateways, 1)
+		listeners := ir.Gateways[types.NamespacedName{Namespace: "gateway-system", Name: "nginx"}].Spec.Listeners
+		require.Len(t, listeners, 2)
+		require.Equal(t, []gatewayv1.SecretObjectReference{
+			{Name: "a-cert", Namespace: ptr.To[gatewayv1.Namespace]("a")},
+			{Name: "b-cert", Namespace: ptr.To[gatewayv1.Namespace]("b")},
+		}, listeners[0].TLS.CertificateRefs)
+		require.Equal(t, gatewayv1.SectionName("example-com-https-b"), listeners[1].Name)
+		require.Equal(t, []string{"a", "b"}, listeners[0].AllowedRoutes.Namespaces.Selector.MatchExpressions[0].Values)
+
+		require.Equal(t, ptr.To[gatewayv1.Namespace]("gateway-system"), ir.HTTPRoutes[types.NamespacedName{Namespace: "a", Name: "route"}].Spec.ParentRefs[0].Namespace)
+		// The route of another Gateway is left untouched.
+		require.Nil(t, ir.HTTPRoutes[types.NamespacedName{Namespace: "gateway-system", Name: "route"}].Spec.ParentRefs[0].Namespace)
+
+		require.Len(t, ir.ReferenceGrants, 2)
+		require.Contains(t, ir.ReferenceGrants, types.NamespacedName{Namespace: "a", Name: "generated-reference-grant-from-gateway-system-to-a"})
+		require.Contains(t, ir.ReferenceGrants, types.NamespacedName{Namespace: "b", Name: "generated-reference-grant-from-gateway-system-to-b"})
+	})
+
+	t.Run("Gateway already in the central namespace", func(t *testing.T) {
+		ir := intermediate.IR{
+			Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+				{Namespace: "gateway-system", Name: "nginx"}: gateway("gateway-system", https("cert")),
+			},
+			HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+				{Namespace: "gateway-system", Name: "route"}: httpRoute("gateway-system"),
+			},
+		}
+		centralizeGateways("test", &ir, "gateway-system")
+
+		listeners := ir.Gateways[types.NamespacedName{Namespace: "gateway-system", Name: "nginx"}].Spec.Listeners
+		require.Equal(t, []gatewayv1.Listener{https("cert")}, listeners)
+		require.Nil(t, ir.HTTPRoutes[types.NamespacedName{Namespace: "gateway-system", Name: "route"}].Spec.ParentRefs[0].Namespace)
+		require.Empty(t, ir.ReferenceGrants)
+	})
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// CertManagerCertificateGVK is the kind of the cert-manager Certificates
+// generated for the listeners of the Gateways annotated with a cert-manager
+// issuer.
+var CertManagerCertificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+const (
+	certManagerAnnotationPrefix        = "cert-manager.io/"
+	certManagerIssuerAnnotation        = certManagerAnnotationPrefix + "issuer"
+	certManagerClusterIssuerAnnotation = certManagerAnnotationPrefix + "cluster-issuer"
+	certManagerIssuerKindAnnotation    = certManagerAnnotationPrefix + "issuer-kind"
+	certManagerIssuerGroupAnnotation   = certManagerAnnotationPrefix + "issuer-group"
+)
+
+// certManagerCertificateFields maps the cert-manager annotations configuring
+// the issued certificates to the fields of the Certificate spec.
+var certManagerCertificateFields = map[string][]string{
+	certManagerAnnotationPrefix + "common-name":                 {"commonName"},
+	certManagerAnnotationPrefix + "duration":                    {"duration"},
+	certManagerAnnotationPrefix + "renew-before":                {"renewBefore"},
+	certManagerAnnotationPrefix + "private-key-algorithm":       {"privateKey", "algorithm"},
+	certManagerAnnotationPrefix + "private-key-encoding":        {"privateKey", "encoding"},
+	certManagerAnnotationPrefix + "private-key-rotation-policy": {"privateKey", "rotationPolicy"},
+}
+
+// generateCertManagerCertificates generates a cert-manager Certificate per
+// Secret referenced by the TLS listeners of the Gateways annotated with a
+// cert-manager issuer, issuing the certificate of the hostnames of the
+// listeners into the Secret, as cert-manager does for annotated Gateways. The
+// cert-manager annotations of the Gateways are removed, so that cert-manager
+// doesn't also manage the Secrets. Listeners without hostname, and Secrets of
+// other namespaces, are skipped with a warning.
+func generateCertManagerCertificates(providerName ProviderName, gatewayResources *GatewayResources) {
+	notify := func(messageType notifications.MessageType, message string, objects ...client.Object) {
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(messageType, message, objects...), string(providerName))
+	}
+
+	certificates := map[types.NamespacedName]*unstructured.Unstructured{}
+	var certificateKeys []types.NamespacedName
+	for _, key := range sortedNamespacedNames(gatewayResources.Gateways) {
+		gateway := gatewayResources.Gateways[key]
+		issuerRef := certManagerIssuerRef(gateway.Annotations)
+		if issuerRef == nil {
+			continue
+		}
+
+		for _, listener := range gateway.Spec.Listeners {
+			if listener.TLS == nil || ptr.Deref(listener.TLS.Mode, gatewayv1.TLSModeTerminate) != gatewayv1.TLSModeTerminate {
+				continue
+			}
+			for _, ref := range listener.TLS.CertificateRefs {
+				if ptr.Deref(ref.Group, "") != "" || ptr.Deref(ref.Kind, "Secret") != "Secret" {
+					continue
+				}
+				if listener.Hostname == nil {
+					notify(notifications.WarningNotification, fmt.Sprintf("listener %s of Gateway %s has no hostname, no Certificate was generated for Secret %s/%s", listener.Name, key, key.Namespace, ref.Name), &gateway)
+					continue
+				}
+				if namespace := ptr.Deref(ref.Namespace, gatewayv1.Namespace(key.Namespace)); string(namespace) != key.Namespace {
+					notify(notifications.WarningNotification, fmt.Sprintf("listener %s of Gateway %s references Secret %s/%s of another namespace, no Certificate was generated for it", listener.Name, key, namespace, ref.Name), &gateway)
+					continue
+				}
+
+				certificateKey := types.NamespacedName{Namespace: key.Namespace, Name: string(ref.Name)}
+				certificate, ok := certificates[certificateKey]
+				if !ok {
+					certificate = newCertManagerCertificate(certificateKey, issuerRef, gateway.Annotations)
+					certificates[certificateKey] = certificate
+					certificateKeys = append(certificateKeys, certificateKey)
+				} else if existing, _, _ := unstructured.NestedStringMap(certificate.Object, "spec", "issuerRef"); !maps.Equal(existing, issuerRef) {
+					notify(notifications.WarningNotification, fmt.Sprintf("Secret %s is issued by different issuers for the Gateways referencing it, Certificate %s keeps issuer %s", certificateKey, certificateKey, existing["name"]), &gateway)
+				}
+				dnsNames, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+				if !slices.Contains(dnsNames, string(*listener.Hostname)) {
+					dnsNames = append(dnsNames, string(*listener.Hostname))
+					slices.Sort(dnsNames)
+					_ = unstructured.SetNestedStringSlice(certificate.Object, dnsNames, "spec", "dnsNames")
+				}
+			}
+		}
+
+		for annotation := range gateway.Annotations {
+			if strings.HasPrefix(annotation, certManagerAnnotationPrefix) {
+				delete(gateway.Annotations, annotation)
+			}
+		}
+		gatewayResources.Gateways[key] = gateway
+		notify(notifications.InfoNotification, fmt.Sprintf("generated the cert-manager Certificates of the listeners of Gateway %s, its cert-manager annotations were removed", key), &gateway)
+	}
+
+	for _, key := range certificateKeys {
+		gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, *certificates[key])
+	}
+}
+
+// certManagerIssuerRef returns the issuerRef of the Certificates of the
+// cert-manager annotations, or nil if they don't reference an issuer.
+func certManagerIssuerRef(annotations map[string]string) map[string]string {
+	issuerRef := map[string]string{}
+	switch {
+	case annotations[certManagerClusterIssuerAnnotation] != "":
+		issuerRef["name"] = annotations[certManagerClusterIssuerAnnotation]
+		issuerRef["kind"] = "ClusterIssuer"
+	case annotations[certManagerIssuerAnnotation] != "":
+		issuerRef["name"] = annotations[certManagerIssuerAnnotation]
+		issuerRef["kind"] = "Issuer"
+	default:
+		return nil
+	}
+	if kind := annotations[certManagerIssuerKindAnnotation]; kind != "" {
+		issuerRef["kind"] = kind
+	}
+	if group := annotations[certManagerIssuerGroupAnnotation]; group != "" {
+		issuerRef["group"] = group
+	}
+	return issuerRef
+}
+
+// newCertManagerCertificate returns the Certificate of the given Secret,
+// configured with the cert-manager annotations.
+func newCertManagerCertificate(key types.NamespacedName, issuerRef map[string]string, annotations map[string]string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"secretName": key.Name,
+		"issuerRef":  toInterfaceMap(issuerRef),
+	}
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	for annotation, fields := range certManagerCertificateFields {
+		if value := annotations[annotation]; value != "" {
+			_ = unstructured.SetNestedField(certificate.Object, value, append([]string{"spec"}, fields...)...)
+		}
+	}
+	certificate.SetGroupVersionKind(CertManagerCertificateGVK)
+	certificate.SetNamespace(key.Namespace)
+	certificate.SetName(key.Name)
+	return certificate
+}
+
+func toInterfaceMap(m map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_generateCertManagerCertificates(t *testing.T) {
+	gatewayResources := GatewayResources{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+			{Namespace: "default", Name: "nginx"}: {
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "nginx",
+					Annotations: map[string]string{
+						"cert-manager.io/cluster-issuer":          "letsencrypt",
+						"cert-manager.io/duration":                "2160h",
+						"external-dns.alpha.kubernetes.io/target": "lb.example.com",
+					},
+				},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners: []gatewayv1.Listener{
+						httpListener("foo-example-com-http", "foo.example.com"),
+						httpsListener("foo-example-com-https", "foo.example.com", "example-com"),
+						httpsListener("bar-example-com-https", "bar.example.com", "example-com"),
+						httpsListener("baz-example-org-https", "baz.example.org", "example-org"),
+						{
+							Name:     "https",
+							Port:     443,
+							Protocol: gatewayv1.HTTPSProtocolType,
+							TLS:      &gatewayv1.GatewayTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "default-cert"}}},
+						},
+					},
+				},
+			},
+			{Namespace: "default", Name: "internal"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "internal"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "internal",
+					Listeners:        []gatewayv1.Listener{httpsListener("qux-example-net-https", "qux.example.net", "example-net")},
+				},
+			},
+		},
+	}
+
+	generateCertManagerCertificates("test", &gatewayResources)
+
+	certificate := func(name string, dnsNames ...interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "cert-manager.io/v1",
+			"kind":       "Certificate",
+			"metadata":   map[string]interface{}{"namespace": "default", "name": name},
+			"spec": map[string]interface{}{
+				"secretName": name,
+				"issuerRef":  map[string]interface{}{"name": "letsencrypt", "kind": "ClusterIssuer"},
+				"dnsNames":   dnsNames,
+				"duration":   "2160h",
+			},
+		}}
+	}
+	require.Equal(t, []unstructured.Unstructured{
+		certificate("example-com", "bar.example.com", "foo.example.com"),
+		certificate("example-org", "baz.example.org"),
+	}, gatewayResources.GatewayExtensions)
+	require.Equal(t, map[string]string{"external-dns.alpha.kubernetes.io/target": "lb.example.com"},
+		gatewayResources.Gateways[types.NamespacedName{Namespace: "default", Name: "nginx"}].Annotations)
+}
+
+func Test_certManagerIssuerRef(t *testing.T) {
+	require.Nil(t, certManagerIssuerRef(map[string]string{"cert-manager.io/duration": "2160h"}))
+	require.Equal(t, map[string]string{"name": "ca", "kind": "Issuer"}, certManagerIssuerRef(map[string]string{"cert-manager.io/issuer": "ca"}))
+	require.Equal(t, map[string]string{"name": "ca", "kind": "AWSPCAClusterIssuer", "group": "awspca.cert-manager.io"}, certManagerIssuerRef(map[string]string{
+		"cert-manager.io/issuer":       "ca",
+		"cert-manager.io/issuer-kind":  "AWSPCAClusterIssuer",
+		"cert-manager.io/issuer-group": "awspca.cert-manager.io",
+	}))
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+)
+
+// IngressClaimRule describes the Ingresses a provider claims.
+type IngressClaimRule struct {
+	// IngressClasses are the classes of the Ingresses claimed by the provider.
+	// The empty class claims the Ingresses without class, with the lowest
+	// strength, for providers implementing the default class.
+	IngressClasses []string
+
+	// AnnotationPrefixes are the prefixes of the annotations specific to the
+	// provider. Ingresses without class carrying such annotations are claimed
+	// by the provider.
+	AnnotationPrefixes []string
+}
+
+// claimStrength ranks the claims of the providers on an Ingress.
+type claimStrength int
+
+const (
+	noClaim claimStrength = iota
+	// defaultClassClaim is the claim on an Ingress without class by a provider
+	// implementing the default class.
+	defaultClassClaim
+	// annotationsClaim is the claim on an Ingress without class carrying
+	// annotations specific to the provider.
+	annotationsClaim
+	// ingressClassClaim is the claim on an Ingress of a class of the provider.
+	ingressClassClaim
+	// annotatedIngressClassClaim is the claim on an Ingress of a class of the
+	// provider carrying annotations specific to the provider, taking
+	// precedence over the other providers sharing the class.
+	annotatedIngressClassClaim
+)
+
+var ingressClaimRules = struct {
+	rules map[ProviderName]IngressClaimRule
+	mu    sync.RWMutex
+}{rules: map[ProviderName]IngressClaimRule{}}
+
+// RegisterIngressClaimRule registers the rule describing the Ingresses the
+// provider claims. It is thread-safe.
+func RegisterIngressClaimRule(provider ProviderName, rule IngressClaimRule) {
+	ingressClaimRules.mu.Lock()
+	defer ingressClaimRules.mu.Unlock()
+	ingressClaimRules.rules[provider] = rule
+}
+
+func getIngressClaimRule(provider ProviderName) (IngressClaimRule, bool) {
+	ingressClaimRules.mu.RLock()
+	defer ingressClaimRules.mu.RUnlock()
+	rule, ok := ingressClaimRules.rules[provider]
+	return rule, ok
+}
+
+// IngressClaimer ensures each Ingress is converted exactly once, by the most
+// appropriate of the enabled providers: an Ingress is claimed by the provider
+// of its class, else by the providers whose annotations it carries, else by
+// the providers implementing the default class. Among the providers sharing
+// the class of the Ingress, the ones whose annotations it carries take
+// precedence. Equal claims are resolved by the priority of the providers.
+type IngressClaimer struct {
+	// providers are the enabled providers, sorted by decreasing priority.
+	providers []ProviderName
+}
+
+// NewIngressClaimer returns an IngressClaimer for the given enabled providers.
+// The providers listed in priority take precedence, in the given order, over
+// the other ones, which are ranked alphabetically.
+func NewIngressClaimer(providers []string, priority []string) (*IngressClaimer, error) {
+	claimer := &IngressClaimer{}
+	for _, name := range priority {
+		if !slices.Contains(providers, name) {
+			return nil, fmt.Errorf("provider %s of the provider priority is not enabled, enabled providers are %v", name, providers)
+		}
+		if !slices.Contains(claimer.providers, ProviderName(name)) {
+			claimer.providers = append(claimer.providers, ProviderName(name))
+		}
+	}
+	others := slices.Clone(providers)
+	slices.Sort(others)
+	for _, name := range others {
+		if !slices.Contains(claimer.providers, ProviderName(name)) {
+			claimer.providers = append(claimer.providers, ProviderName(name))
+		}
+	}
+	return claimer, nil
+}
+
+// Claim returns the Ingress to convert and true if the given provider is the
+// one converting the Ingress. When an Ingress without class is claimed through
+// the annotations of the provider, a copy of the Ingress assigned the first
+// class of the provider is returned so the generated resources are named
+// consistently. The given Ingress is never modified.
+//
+// A nil IngressClaimer only considers the claim of the given provider.
+func (c *IngressClaimer) Claim(provider ProviderName, ingress *networkingv1.Ingress) (*networkingv1.Ingress, bool) {
+	strength := providerClaim(provider, ingress)
+	if strength == noClaim {
+		return nil, false
+	}
+
+	if c != nil {
+		for _, other := range c.providers {
+			if other == provider {
+				continue
+			}
+			otherStrength := providerClaim(other, ingress)
+			if otherStrength > strength || (otherStrength == strength && slices.Index(c.providers, other) < slices.Index(c.providers, provider)) {
+				notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.InfoNotification,
+					fmt.Sprintf("ingress %s/%s is also claimed by the %s provider, which takes precedence", ingress.Namespace, ingress.Name, other), ingress), string(provider))
+				return nil, false
+			}
+		}
+	}
+
+	if strength == annotationsClaim {
+		rule, _ := getIngressClaimRule(provider)
+		if len(rule.IngressClasses) > 0 && rule.IngressClasses[0] != "" {
+			claimed := ingress.DeepCopy()
+			if claimed.Annotations == nil {
+				claimed.Annotations = map[string]string{}
+			}
+			claimed.Annotations[networkingv1beta1.AnnotationIngressClass] = rule.IngressClasses[0]
+			return claimed, true
+		}
+	}
+	return ingress, true
+}
+
+// ClaimFunc returns a function claiming Ingresses for the given provider,
+// suitable for the Ingress readers.
+func (c *IngressClaimer) ClaimFunc(provider ProviderName) func(*networkingv1.Ingress) (*networkingv1.Ingress, bool) {
+	return func(ingress *networkingv1.Ingress) (*networkingv1.Ingress, bool) {
+		return c.Claim(provider, ingress)
+	}
+}
+
+// providerClaim returns the strength of the claim of the provider on the
+// Ingress.
+func providerClaim(provider ProviderName, ingress *networkingv1.Ingress) claimStrength {
+	rule, ok := getIngressClaimRule(provider)
+	if !ok {
+		return noClaim
+	}
+
+	class := ingressClass(ingress)
+	if class != "" {
+		switch {
+		case !slices.Contains(rule.IngressClasses, class):
+			return noClaim
+		case hasAnnotationPrefix(ingress, rule.AnnotationPrefixes):
+			return annotatedIngressClassClaim
+		default:
+			return ingressClassClaim
+		}
+	}
+	if hasAnnotationPrefix(ingress, rule.AnnotationPrefixes) {
+		return annotationsClaim
+	}
+	if slices.Contains(rule.IngressClasses, "") {
+		return defaultClassClaim
+	}
+	return noClaim
+}
+
+// hasAnnotationPrefix returns whether the Ingress has an annotation with one
+// of the given prefixes.
+func hasAnnotationPrefix(ingress *networkingv1.Ingress, prefixes []string) bool {
+	for annotation := range ingress.Annotations {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(annotation, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ingressClass returns the class of the Ingress, read from the
+// ingressClassName field or the legacy kubernetes.io/ingress.class annotation.
+func ingressClass(ingress *networkingv1.Ingress) string {
+	if ingress.Spec.IngressClassName != nil && *ingress.Spec.IngressClassName != "" {
+		return *ingress.Spec.IngressClassName
+	}
+	return ingress.Annotations[networkingv1beta1.AnnotationIngressClass]
+}
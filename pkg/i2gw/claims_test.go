@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func Test_IngressClaimer(t *testing.T) {
+	RegisterIngressClaimRule("test-nginx", IngressClaimRule{IngressClasses: []string{"nginx"}, AnnotationPrefixes: []string{"nginx.ingress.kubernetes.io/"}})
+	RegisterIngressClaimRule("test-kong", IngressClaimRule{IngressClasses: []string{"kong"}, AnnotationPrefixes: []string{"konghq.com/"}})
+	RegisterIngressClaimRule("test-gce", IngressClaimRule{IngressClasses: []string{"gce", ""}})
+	RegisterIngressClaimRule("test-nginx-inc", IngressClaimRule{IngressClasses: []string{"nginx"}, AnnotationPrefixes: []string{"nginx.org/"}})
+
+	providers := []string{"test-nginx", "test-kong", "test-gce", "test-nginx-inc"}
+
+	testCases := []struct {
+		name         string
+		priority     []string
+		ingress      networkingv1.Ingress
+		wantClaimant ProviderName
+		wantClass    string
+	}{
+		{
+			name: "class takes precedence over annotations",
+			ingress: networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"konghq.com/strip-path": "true"}},
+				Spec:       networkingv1.IngressSpec{IngressClassName: ptr.To("nginx")},
+			},
+			wantClaimant: "test-nginx",
+		},
+		{
+			name: "annotations take precedence over the default class",
+			ingress: networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"konghq.com/strip-path": "true"}},
+			},
+			wantClaimant: "test-kong",
+			wantClass:    "kong",
+		},
+		{
+			name: "shared class is resolved alphabetically",
+			ingress: networkingv1.Ingress{
+				Spec: networkingv1.IngressSpec{IngressClassName: ptr.To("nginx")},
+			},
+			wantClaimant: "test-nginx",
+		},
+		{
+			name: "annotations take precedence among the providers of a shared class",
+			ingress: networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"nginx.org/mergeable-ingress-type": "master"}},
+				Spec:       networkingv1.IngressSpec{IngressClassName: ptr.To("nginx")},
+			},
+			wantClaimant: "test-nginx-inc",
+		},
+		{
+			name:         "default class",
+			ingress:      networkingv1.Ingress{},
+			wantClaimant: "test-gce",
+		},
+		{
+			name: "equal claims are resolved alphabetically",
+			ingress: networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					"konghq.com/strip-path":                      "true",
+					"nginx.ingress.kubernetes.io/rewrite-target": "/",
+				}},
+			},
+			wantClaimant: "test-kong",
+			wantClass:    "kong",
+		},
+		{
+			name:     "equal claims are resolved by priority",
+			priority: []string{"test-nginx"},
+			ingress: networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					"konghq.com/strip-path":                      "true",
+					"nginx.ingress.kubernetes.io/rewrite-target": "/",
+				}},
+			},
+			wantClaimant: "test-nginx",
+			wantClass:    "nginx",
+		},
+		{
+			name:    "unknown class is not claimed",
+			ingress: networkingv1.Ingress{Spec: networkingv1.IngressSpec{IngressClassName: ptr.To("traefik")}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			claimer, err := NewIngressClaimer(providers, tc.priority)
+			require.NoError(t, err)
+
+			var claimants []ProviderName
+			for _, provider := range providers {
+				ingress := tc.ingress.DeepCopy()
+				if claimed, ok := claimer.Claim(ProviderName(provider), ingress); ok {
+					claimants = append(claimants, ProviderName(provider))
+					require.Equal(t, tc.wantClass, claimed.Annotations[networkingv1beta1.AnnotationIngressClass])
+				}
+				require.Equal(t, tc.ingress, *ingress, "the claimed Ingress was modified")
+			}
+
+			if tc.wantClaimant == "" {
+				require.Empty(t, claimants)
+				return
+			}
+			require.Equal(t, []ProviderName{tc.wantClaimant}, claimants)
+		})
+	}
+}
+
+func Test_NewIngressClaimer(t *testing.T) {
+	claimer, err := NewIngressClaimer([]string{"b", "c", "a"}, []string{"c"})
+	require.NoError(t, err)
+	require.Equal(t, []ProviderName{"c", "a", "b"}, claimer.providers)
+
+	_, err = NewIngressClaimer([]string{"a"}, []string{"b"})
+	require.Error(t, err)
+}
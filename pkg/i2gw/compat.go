@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// crdSchemas are the OpenAPI schemas of the served versions of the
+// CustomResourceDefinitions of a cluster, by GroupVersionKind.
+type crdSchemas map[schema.GroupVersionKind]*apiextensionsv1.JSONSchemaProps
+
+// readCRDSchemas reads the schemas of the CustomResourceDefinitions installed
+// in the cluster of the kubeconfig context of the options.
+func readCRDSchemas(ctx context.Context, opts ConversionOptions) (crdSchemas, error) {
+	conf, err := clusterConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	scheme := runtime.NewScheme()
+	if err = apiextensionsv1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	cl, err := client.New(conf, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	var crds apiextensionsv1.CustomResourceDefinitionList
+	if err = cl.List(ctx, &crds); err != nil {
+		return nil, fmt.Errorf("failed to read the CustomResourceDefinitions of the cluster: %w", err)
+	}
+	return schemasOfCRDs(crds.Items), nil
+}
+
+// schemasOfCRDs returns the schemas of the served versions of the given
+// CustomResourceDefinitions.
+func schemasOfCRDs(crds []apiextensionsv1.CustomResourceDefinition) crdSchemas {
+	schemas := crdSchemas{}
+	for _, crd := range crds {
+		for _, version := range crd.Spec.Versions {
+			if !version.Served || version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+				continue
+			}
+			gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}
+			schemas[gvk] = version.Schema.OpenAPIV3Schema
+		}
+	}
+	return schemas
+}
+
+// stripUnknownFields removes the fields of the generated resources which the
+// schemas of the CustomResourceDefinitions of the cluster don't define, as the
+// API server would prune them, or reject the resources with server-side apply,
+// when the cluster lags behind the Gateway API version of the conversion. The
+// resources whose CustomResourceDefinition isn't installed are left unchanged.
+func stripUnknownFields(providerName ProviderName, schemas crdSchemas, gatewayResources *GatewayResources) {
+	s := &fieldStripper{providerName: providerName, schemas: schemas, missing: map[schema.GroupVersionKind]bool{}}
+
+	stripTypedObjects(s, gatewayResources.GatewayClasses, gatewayv1.SchemeGroupVersion.WithKind("GatewayClass"))
+	stripTypedObjects(s, gatewayResources.Gateways, gatewayv1.SchemeGroupVersion.WithKind("Gateway"))
+	stripTypedObjects(s, gatewayResources.HTTPRoutes, gatewayv1.SchemeGroupVersion.WithKind("HTTPRoute"))
+	stripTypedObjects(s, gatewayResources.GRPCRoutes, gatewayv1.SchemeGroupVersion.WithKind("GRPCRoute"))
+	stripTypedObjects(s, gatewayResources.TLSRoutes, gatewayv1alpha2.SchemeGroupVersion.WithKind("TLSRoute"))
+	stripTypedObjects(s, gatewayResources.TCPRoutes, gatewayv1alpha2.SchemeGroupVersion.WithKind("TCPRoute"))
+	stripTypedObjects(s, gatewayResources.UDPRoutes, gatewayv1alpha2.SchemeGroupVersion.WithKind("UDPRoute"))
+	stripTypedObjects(s, gatewayResources.ReferenceGrants, gatewayv1beta1.SchemeGroupVersion.WithKind("ReferenceGrant"))
+	stripTypedObjects(s, gatewayResources.BackendTLSPolicies, gatewayv1alpha3.SchemeGroupVersion.WithKind("BackendTLSPolicy"))
+	stripTypedObjects(s, gatewayResources.BackendLBPolicies, gatewayv1alpha2.SchemeGroupVersion.WithKind("BackendLBPolicy"))
+	for i := range gatewayResources.GatewayExtensions {
+		s.strip(gatewayResources.GatewayExtensions[i].Object, gatewayResources.GatewayExtensions[i].GroupVersionKind())
+	}
+}
+
+// fieldStripper strips the unknown fields of the resources of a provider.
+type fieldStripper struct {
+	providerName ProviderName
+	schemas      crdSchemas
+	// missing stores the GroupVersionKinds without CustomResourceDefinition
+	// already reported.
+	missing map[schema.GroupVersionKind]bool
+}
+
+// stripTypedObjects strips the unknown fields of typed resources, of the
+// given default GroupVersionKind unless their type meta is set.
+func stripTypedObjects[T any](s *fieldStripper, objects map[types.NamespacedName]T, defaultGVK schema.GroupVersionKind) {
+	for key, object := range objects {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&object)
+		if err != nil {
+			continue
+		}
+		gvk := (&unstructured.Unstructured{Object: content}).GroupVersionKind()
+		if gvk.Empty() {
+			gvk = defaultGVK
+		}
+		if !s.strip(content, gvk) {
+			continue
+		}
+		var stripped T
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(content, &stripped); err != nil {
+			continue
+		}
+		objects[key] = stripped
+	}
+}
+
+// strip removes the unknown fields of the resource content, and returns
+// whether any was removed.
+func (s *fieldStripper) strip(content map[string]interface{}, gvk schema.GroupVersionKind) bool {
+	objectSchema, ok := s.schemas[gvk]
+	if !ok {
+		if !s.missing[gvk] && isCustomResourceGroup(gvk.Group) {
+			s.missing[gvk] = true
+			notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification,
+				fmt.Sprintf("the cluster serves no CustomResourceDefinition of %s, its resources were not stripped of unknown fields", gvk)), string(s.providerName))
+		}
+		return false
+	}
+
+	var removed []string
+	for _, name := range sortedKeys(content) {
+		switch name {
+		case "apiVersion", "kind", "metadata":
+			continue
+		}
+		if property, ok := objectSchema.Properties[name]; ok {
+			removed = append(removed, pruneUnknownFields(content[name], &property, name)...)
+			continue
+		}
+		delete(content, name)
+		removed = append(removed, name)
+	}
+	if len(removed) == 0 {
+		return false
+	}
+
+	object := unstructured.Unstructured{Object: content}
+	notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification,
+		fmt.Sprintf("removed the fields %s of %s %s/%s, unknown to the CustomResourceDefinition of the cluster", strings.Join(removed, ", "), gvk.Kind, object.GetNamespace(), object.GetName()), &object), string(s.providerName))
+	return true
+}
+
+// pruneUnknownFields removes the fields of the value its schema doesn't
+// define, as the API server prunes the fields of custom resources, and returns
+// their paths.
+func pruneUnknownFields(value interface{}, fieldSchema *apiextensionsv1.JSONSchemaProps, path string) []string {
+	if fieldSchema == nil || (fieldSchema.XPreserveUnknownFields != nil && *fieldSchema.XPreserveUnknownFields) {
+		return nil
+	}
+
+	var removed []string
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if fieldSchema.Type != "object" {
+			return nil
+		}
+		for _, name := range sortedKeys(value) {
+			fieldPath := path + "." + name
+			if property, ok := fieldSchema.Properties[name]; ok {
+				removed = append(removed, pruneUnknownFields(value[name], &property, fieldPath)...)
+				continue
+			}
+			if additionalProperties := fieldSchema.AdditionalProperties; additionalProperties != nil {
+				if additionalProperties.Schema != nil {
+					removed = append(removed, pruneUnknownFields(value[name], additionalProperties.Schema, fieldPath)...)
+					continue
+				}
+				if additionalProperties.Allows {
+					continue
+				}
+			}
+			delete(value, name)
+			removed = append(removed, fieldPath)
+		}
+	case []interface{}:
+		if fieldSchema.Items == nil || fieldSchema.Items.Schema == nil {
+			return nil
+		}
+		for i, item := range value {
+			removed = append(removed, pruneUnknownFields(item, fieldSchema.Items.Schema, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return removed
+}
+
+// isCustomResourceGroup returns whether the resources of the API group are
+// served through CustomResourceDefinitions, i.e. whether the group is neither
+// built into Kubernetes nor the core group, except for the Gateway API group.
+func isCustomResourceGroup(group string) bool {
+	if group == gatewayv1.GroupName {
+		return true
+	}
+	return strings.Contains(group, ".") && !strings.HasSuffix(group, ".k8s.io")
+}
+
+// sortedKeys returns the sorted keys of the map.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_schemasOfCRDs(t *testing.T) {
+	servedSchema := &apiextensionsv1.JSONSchemaProps{Type: "object"}
+	crds := []apiextensionsv1.CustomResourceDefinition{{
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: gatewayv1.GroupName,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "HTTPRoute"},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: servedSchema}},
+				{Name: "v1alpha2", Served: false, Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{}}},
+			},
+		},
+	}}
+
+	require.Equal(t, crdSchemas{
+		{Group: gatewayv1.GroupName, Version: "v1", Kind: "HTTPRoute"}: servedSchema,
+	}, schemasOfCRDs(crds))
+}
+
+func Test_stripUnknownFields(t *testing.T) {
+	object := func(properties map[string]apiextensionsv1.JSONSchemaProps) apiextensionsv1.JSONSchemaProps {
+		return apiextensionsv1.JSONSchemaProps{Type: "object", Properties: properties}
+	}
+	array := func(items apiextensionsv1.JSONSchemaProps) apiextensionsv1.JSONSchemaProps {
+		return apiextensionsv1.JSONSchemaProps{Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &items}}
+	}
+	str := apiextensionsv1.JSONSchemaProps{Type: "string"}
+	integer := apiextensionsv1.JSONSchemaProps{Type: "integer"}
+
+	// The schema of an HTTPRoute CRD predating rule timeouts.
+	httpRouteSchema := object(map[string]apiextensionsv1.JSONSchemaProps{
+		"apiVersion": str,
+		"kind":       str,
+		"metadata":   {Type: "object"},
+		"spec": object(map[string]apiextensionsv1.JSONSchemaProps{
+			"parentRefs": array(object(map[string]apiextensionsv1.JSONSchemaProps{"name": str})),
+			"rules": array(object(map[string]apiextensionsv1.JSONSchemaProps{
+				"backendRefs": array(object(map[string]apiextensionsv1.JSONSchemaProps{"name": str, "port": integer})),
+			})),
+		}),
+	})
+	schemas := crdSchemas{
+		gatewayv1.SchemeGroupVersion.WithKind("HTTPRoute"): &httpRouteSchema,
+		{Group: "example.com", Version: "v1", Kind: "Policy"}: {
+			Type: "object",
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"spec": {Type: "object", XPreserveUnknownFields: ptr.To(true)},
+			},
+		},
+	}
+
+	routeKey := types.NamespacedName{Namespace: "default", Name: "app"}
+	backendRef := gatewayv1.HTTPBackendRef{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+		Name: "app",
+		Port: ptr.To[gatewayv1.PortNumber](80),
+	}}}
+	gatewayResources := GatewayResources{
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+			routeKey: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gateway"}}},
+					Hostnames:       []gatewayv1.Hostname{"example.com"},
+					Rules: []gatewayv1.HTTPRouteRule{{
+						BackendRefs: []gatewayv1.HTTPBackendRef{backendRef},
+						Timeouts:    &gatewayv1.HTTPRouteTimeouts{Request: ptr.To[gatewayv1.Duration]("10s")},
+					}},
+				},
+			},
+		},
+		GatewayExtensions: []unstructured.Unstructured{
+			{Object: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Policy",
+				"metadata":   map[string]interface{}{"name": "policy", "namespace": "default"},
+				"spec":       map[string]interface{}{"anything": "kept"},
+				"unknown":    true,
+			}},
+			{Object: map[string]interface{}{
+				"apiVersion": "example.org/v1",
+				"kind":       "NotInstalled",
+				"metadata":   map[string]interface{}{"name": "other", "namespace": "default"},
+				"spec":       map[string]interface{}{"anything": "kept"},
+			}},
+		},
+	}
+
+	stripUnknownFields("test", schemas, &gatewayResources)
+
+	require.Equal(t, gatewayv1.HTTPRouteSpec{
+		CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gateway"}}},
+		Rules:           []gatewayv1.HTTPRouteRule{{BackendRefs: []gatewayv1.HTTPBackendRef{backendRef}}},
+	}, gatewayResources.HTTPRoutes[routeKey].Spec)
+	require.Equal(t, "app", gatewayResources.HTTPRoutes[routeKey].Name)
+	require.Equal(t, map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Policy",
+		"metadata":   map[string]interface{}{"name": "policy", "namespace": "default"},
+		"spec":       map[string]interface{}{"anything": "kept"},
+	}, gatewayResources.GatewayExtensions[0].Object)
+	require.Equal(t, map[string]interface{}{"anything": "kept"}, gatewayResources.GatewayExtensions[1].Object["spec"])
+}
+
+func Test_isCustomResourceGroup(t *testing.T) {
+	for group, expected := range map[string]bool{
+		gatewayv1.GroupName:        true,
+		"networking.istio.io":      true,
+		"":                         false,
+		"apps":                     false,
+		"networking.k8s.io":        false,
+		"gateway.networking.x-k8s": true,
+	} {
+		require.Equal(t, expected, isCustomResourceGroup(group), group)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// EmitterConstructorByName is a map of EmitterConstructor functions by an
+// emitter name. Different Emitter implementations should add their construction
+// func at startup.
+var EmitterConstructorByName = map[EmitterName]EmitterConstructor{}
+
+// EmitterName is a string alias that stores the concrete Emitter name.
+type EmitterName string
+
+// EmitterConstructor is a construction function that constructs concrete
+// implementations of the Emitter interface.
+type EmitterConstructor func() Emitter
+
+// Emitter extends the Gateway API resources generated by the providers with
+// the implementation-specific resources of a Gateway API implementation, for
+// the features of the IR exceeding the Gateway API core.
+type Emitter interface {
+	// Emit adds the implementation-specific resources, and the references to
+	// them, to the Gateway API resources generated from the IR.
+	Emit(intermediate.IR, *GatewayResources) field.ErrorList
+}
+
+// IRVersionedEmitter is implemented by the emitters built against a given
+// version of the IR, e.g. external emitters, for them not to be run against an
+// IR of an incompatible schema.
+type IRVersionedEmitter interface {
+	Emitter
+	// IRVersion returns the intermediate.IRVersion the emitter was built
+	// against.
+	IRVersion() string
+}
+
+// GetSupportedEmitters returns the sorted names of all the supported emitters.
+func GetSupportedEmitters() []string {
+	supportedEmitters := make([]string, 0, len(EmitterConstructorByName))
+	for name := range EmitterConstructorByName {
+		supportedEmitters = append(supportedEmitters, string(name))
+	}
+	slices.Sort(supportedEmitters)
+	return supportedEmitters
+}
+
+// constructEmitter returns the Emitter of the given name, or nil if the name
+// is empty, meaning that only Gateway API resources are generated.
+func constructEmitter(name EmitterName) (Emitter, error) {
+	if name == "" {
+		return nil, nil
+	}
+	newEmitterFunc, ok := EmitterConstructorByName[name]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a supported emitter, supported values are %v", name, GetSupportedEmitters())
+	}
+	emitter := newEmitterFunc()
+	if versioned, ok := emitter.(IRVersionedEmitter); ok && versioned.IRVersion() != intermediate.IRVersion {
+		return nil, fmt.Errorf("emitter %s was built against IR version %s, the IR version is %s", name, versioned.IRVersion(), intermediate.IRVersion)
+	}
+	return emitter, nil
+}
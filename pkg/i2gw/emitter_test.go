@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+type fakeEmitter struct{}
+
+func (fakeEmitter) Emit(intermediate.IR, *GatewayResources) field.ErrorList { return nil }
+
+type fakeVersionedEmitter struct {
+	fakeEmitter
+	irVersion string
+}
+
+func (e fakeVersionedEmitter) IRVersion() string { return e.irVersion }
+
+func Test_constructEmitter(t *testing.T) {
+	EmitterConstructorByName["fake"] = func() Emitter { return fakeEmitter{} }
+	defer delete(EmitterConstructorByName, "fake")
+
+	emitter, err := constructEmitter("")
+	require.NoError(t, err)
+	require.Nil(t, emitter)
+
+	emitter, err = constructEmitter("fake")
+	require.NoError(t, err)
+	require.Equal(t, fakeEmitter{}, emitter)
+
+	_, err = constructEmitter("unknown")
+	require.EqualError(t, err, "unknown is not a supported emitter, supported values are [fake]")
+}
+
+func Test_constructEmitter_irVersion(t *testing.T) {
+	EmitterConstructorByName["current"] = func() Emitter { return fakeVersionedEmitter{irVersion: intermediate.IRVersion} }
+	EmitterConstructorByName["stale"] = func() Emitter { return fakeVersionedEmitter{irVersion: "v0"} }
+	defer delete(EmitterConstructorByName, "current")
+	defer delete(EmitterConstructorByName, "stale")
+
+	_, err := constructEmitter("current")
+	require.NoError(t, err)
+
+	_, err = constructEmitter("stale")
+	require.EqualError(t, err, "emitter stale was built against IR version v0, the IR version is "+intermediate.IRVersion)
+}
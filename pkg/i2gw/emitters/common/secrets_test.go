@@ -0,0 +1,29 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBasicAuthSecretFormat(t *testing.T) {
+	require.Equal(t, "from its \"auth\" key", BasicAuthSecretFormat("auth-file"))
+	require.Equal(t, "from its \"auth\" key", BasicAuthSecretFormat(""))
+	require.Equal(t, "from one key per user", BasicAuthSecretFormat("auth-map"))
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// rateLimitUnits are the units of the requests limits of the Envoy Gateway
+// rate limits, by the period they limit the requests in.
+var rateLimitUnits = map[time.Duration]string{
+	time.Second:    "Second",
+	time.Minute:    "Minute",
+	time.Hour:      "Hour",
+	24 * time.Hour: "Day",
+}
+
+// ingressNginxBackendTrafficSpec returns the fields of the BackendTrafficPolicy
+// spec of the rate limit, upstream connection and session affinity of the
+// given policy of an Ingress.
+func ingressNginxBackendTrafficSpec(source string, policy intermediate.IngressNginxPolicy) map[string]interface{} {
+	spec := map[string]interface{}{}
+	if rateLimit := policy.RateLimit; rateLimit != nil {
+		if config := localRateLimit(source, int64(rateLimit.Requests), rateLimit.Period); config != nil {
+			spec["rateLimit"] = config
+			notify(notifications.WarningNotification, fmt.Sprintf("Envoy Gateway limits the rate of all the requests of %s per proxy, without burst, while ingress-nginx limits them per client address", source))
+		}
+	}
+	if upstreamConnection := policy.UpstreamConnection; upstreamConnection != nil {
+		upstreamConnectionSpec(source, *upstreamConnection, spec)
+	}
+	if affinity := policy.Affinity; affinity != nil {
+		if loadBalancer := affinityLoadBalancer(source, *affinity); loadBalancer != nil {
+			spec["loadBalancer"] = loadBalancer
+		}
+	}
+	return spec
+}
+
+// skipperBackendTrafficSpec returns the fields of the BackendTrafficPolicy
+// spec of the rate limits of the given policy of an Ingress.
+func skipperBackendTrafficSpec(source string, policy intermediate.SkipperPolicy) map[string]interface{} {
+	spec := map[string]interface{}{}
+	switch {
+	case policy.RateLimit != nil:
+		if config := localRateLimit(source, int64(policy.RateLimit.Requests), policy.RateLimit.Period); config != nil {
+			spec["rateLimit"] = config
+		}
+		if policy.ClientRateLimit != nil {
+			notify(notifications.WarningNotification, fmt.Sprintf("the client rate limit of %s was not converted, only its rate limit of all the clients was", source))
+		}
+	case policy.ClientRateLimit != nil:
+		if config := localRateLimit(source, int64(policy.ClientRateLimit.Requests), policy.ClientRateLimit.Period); config != nil {
+			spec["rateLimit"] = config
+			notify(notifications.WarningNotification, fmt.Sprintf("Envoy Gateway limits the rate of all the requests of %s per proxy, while Skipper limits them per client", source))
+		}
+	}
+	return spec
+}
+
+// kongBackendTrafficSpec returns the fields of the BackendTrafficPolicy spec
+// of the rate limit of the given Kong plugin.
+func kongBackendTrafficSpec(source string, policy intermediate.KongPolicy) map[string]interface{} {
+	spec := map[string]interface{}{}
+	if rateLimit := policy.RateLimit; rateLimit != nil {
+		if config := localRateLimit(source, rateLimit.Requests, rateLimit.Period); config != nil {
+			spec["rateLimit"] = config
+			notify(notifications.WarningNotification, fmt.Sprintf("Envoy Gateway limits the rate of all the requests of %s per proxy, while Kong limits them per %s", source, rateLimit.LimitBy))
+		}
+	}
+	return spec
+}
+
+// localRateLimit returns the local rate limit of the given number of requests
+// per period, or nil if Envoy Gateway can't limit the requests in the period.
+func localRateLimit(source string, requests int64, period time.Duration) map[string]interface{} {
+	unit, ok := rateLimitUnits[period]
+	if !ok {
+		notify(notifications.WarningNotification, fmt.Sprintf("the rate limit of %s, %d requests per %v, was not converted: Envoy Gateway only limits the requests per second, minute, hour or day", source, requests, period))
+		return nil
+	}
+	return map[string]interface{}{
+		"type": "Local",
+		"local": map[string]interface{}{
+			"rules": []interface{}{
+				map[string]interface{}{
+					"limit": map[string]interface{}{"requests": requests, "unit": unit},
+				},
+			},
+		},
+	}
+}
+
+// upstreamConnectionSpec sets the timeout and circuitBreaker fields of the
+// BackendTrafficPolicy spec of the given upstream connection policy.
+func upstreamConnectionSpec(source string, upstreamConnection intermediate.UpstreamConnectionConfig, spec map[string]interface{}) {
+	switch upstreamConnection.HTTPVersion {
+	case intermediate.HTTPVersion10:
+		notify(notifications.WarningNotification, fmt.Sprintf("Envoy Gateway proxies the requests of %s to its backends with HTTP/1.1 instead of HTTP/1.0", source))
+	case intermediate.HTTPVersion2:
+		notify(notifications.WarningNotification, fmt.Sprintf("Envoy Gateway proxies the requests to the backends of %s with HTTP/2 only if the appProtocol of their Service ports is kubernetes.io/h2c or grpc", source))
+	}
+
+	httpTimeout := map[string]interface{}{}
+	for _, timeout := range []struct {
+		name     string
+		duration *time.Duration
+	}{
+		{"connectionIdleTimeout", upstreamConnection.KeepaliveTimeout},
+		{"maxConnectionDuration", upstreamConnection.KeepaliveTime},
+	} {
+		if timeout.duration == nil {
+			continue
+		}
+		duration, err := common.ToGatewayDuration(*timeout.duration)
+		if err != nil {
+			notify(notifications.WarningNotification, fmt.Sprintf("the %s of %s was not converted: %v", timeout.name, source, err))
+			continue
+		}
+		httpTimeout[timeout.name] = string(duration)
+	}
+	if len(httpTimeout) > 0 {
+		spec["timeout"] = map[string]interface{}{"http": httpTimeout}
+	}
+
+	circuitBreaker := map[string]interface{}{}
+	if upstreamConnection.KeepaliveRequests != nil {
+		circuitBreaker["maxRequestsPerConnection"] = int64(*upstreamConnection.KeepaliveRequests)
+	}
+	if keepaliveConnections := upstreamConnection.KeepaliveConnections; keepaliveConnections != nil {
+		if *keepaliveConnections == 0 {
+			// Keepalive connections are disabled, each connection only
+			// carries a single request.
+			circuitBreaker["maxRequestsPerConnection"] = int64(1)
+		} else {
+			notify(notifications.WarningNotification, fmt.Sprintf("Envoy Gateway doesn't limit the number of idle connections to the backends of %s, the keepalive connections limit was ignored", source))
+		}
+	}
+	if len(circuitBreaker) > 0 {
+		spec["circuitBreaker"] = circuitBreaker
+	}
+}
+
+// affinityLoadBalancer returns the consistent hash load balancer pinning the
+// clients as the given session affinity, or nil if it can't be converted.
+func affinityLoadBalancer(source string, affinity intermediate.AffinityConfig) map[string]interface{} {
+	consistentHash := map[string]interface{}{}
+	switch {
+	case affinity.Cookie != nil:
+		cookie := map[string]interface{}{"name": affinity.Cookie.Name}
+		// Envoy only generates the cookie when its ttl is set, a ttl of 0
+		// generating a session cookie.
+		ttl := time.Duration(0)
+		if affinity.Cookie.MaxAge != nil {
+			ttl = *affinity.Cookie.MaxAge
+		} else if affinity.Cookie.Expires != nil {
+			ttl = *affinity.Cookie.Expires
+		}
+		duration, err := common.ToGatewayDuration(ttl)
+		if err != nil {
+			notify(notifications.WarningNotification, fmt.Sprintf("the session affinity of %s was not converted: %v", source, err))
+			return nil
+		}
+		cookie["ttl"] = string(duration)
+		attributes := map[string]interface{}{}
+		if affinity.Cookie.Path != "" {
+			attributes["Path"] = affinity.Cookie.Path
+		}
+		if affinity.Cookie.Domain != "" {
+			attributes["Domain"] = affinity.Cookie.Domain
+		}
+		if affinity.Cookie.SameSite != "" {
+			attributes["SameSite"] = affinity.Cookie.SameSite
+		}
+		if len(attributes) > 0 {
+			cookie["attributes"] = attributes
+		}
+		consistentHash["type"] = "Cookie"
+		consistentHash["cookie"] = cookie
+	case affinity.HashBy == "$binary_remote_addr" || affinity.HashBy == "$remote_addr":
+		consistentHash["type"] = "SourceIP"
+	case strings.HasPrefix(affinity.HashBy, "$http_"):
+		consistentHash["type"] = "Header"
+		consistentHash["header"] = map[string]interface{}{
+			"name": strings.ReplaceAll(strings.TrimPrefix(affinity.HashBy, "$http_"), "_", "-"),
+		}
+	default:
+		notify(notifications.WarningNotification, fmt.Sprintf("the session affinity of %s, hashing %q, was not converted: Envoy Gateway only hashes the client address, a header or a cookie", source, affinity.HashBy))
+		return nil
+	}
+	return map[string]interface{}{"type": "ConsistentHash", "consistentHash": consistentHash}
+}
+
+// directResponseGroup is a direct response of an HTTPRoute and the indices of
+// the rules responding with it.
+type directResponseGroup struct {
+	response    intermediate.DirectResponse
+	ruleIndices []int
+}
+
+// groupDirectResponses groups the rules of the given direct responses by
+// response, in the order of the rules.
+func groupDirectResponses(directResponses map[int]intermediate.DirectResponse) []directResponseGroup {
+	ruleIndices := make([]int, 0, len(directResponses))
+	for i := range directResponses {
+		ruleIndices = append(ruleIndices, i)
+	}
+	slices.Sort(ruleIndices)
+
+	var groups []directResponseGroup
+	for _, i := range ruleIndices {
+		n := slices.IndexFunc(groups, func(group directResponseGroup) bool { return group.response == directResponses[i] })
+		if n < 0 {
+			groups = append(groups, directResponseGroup{response: directResponses[i]})
+			n = len(groups) - 1
+		}
+		groups[n].ruleIndices = append(groups[n].ruleIndices, i)
+	}
+	return groups
+}
+
+// directResponses merges the direct response of the HTTPRoute into its
+// BackendTrafficPolicy. The responseOverride of the policy applies to all the
+// rules of the HTTPRoute, overriding the 500 responses of their backends too:
+// the direct response is only converted when all the rules respond with it,
+// and is reported as unsupported otherwise. The rules are told apart by
+// their index in the IR, which must still be the one of the generated
+// HTTPRoute.
+func directResponses(httpRouteContext intermediate.HTTPRouteContext, httpRoute *gatewayv1.HTTPRoute, backendTraffic *routePolicy) {
+	groups := groupDirectResponses(httpRouteContext.DirectResponses)
+	if len(groups) == 0 {
+		return
+	}
+	routeKey := types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}
+	if len(httpRoute.Spec.Rules) != len(httpRouteContext.Spec.Rules) {
+		notify(notifications.WarningNotification, fmt.Sprintf("the direct responses of HTTPRoute %s were not converted, as the number of its rules changed during the conversion: its rules are answered with a 500 status code", routeKey), httpRoute)
+		return
+	}
+	for _, group := range groups {
+		source := fmt.Sprintf("the %d direct response of HTTPRoute %s", group.response.StatusCode, routeKey)
+		if !coversAllRules(group.ruleIndices, len(httpRoute.Spec.Rules)) {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s is not supported by the Envoy Gateway emitter, as its response override would apply to the other rules of the HTTPRoute too: its rules %v are answered with a 500 status code", source, group.ruleIndices), httpRoute)
+			continue
+		}
+		backendTraffic.merge(source, group.ruleIndices, directResponseSpec(group.response))
+	}
+}
+
+// directResponseSpec returns the responseOverride field of the
+// BackendTrafficPolicy spec of the given direct response. The rules of the
+// direct responses have no backendRefs, and Envoy Gateway answers them with a
+// 500 status code, which is overridden by the direct response.
+func directResponseSpec(response intermediate.DirectResponse) map[string]interface{} {
+	override := map[string]interface{}{"statusCode": int64(response.StatusCode)}
+	if response.ContentType != "" {
+		override["contentType"] = response.ContentType
+	}
+	if response.Body != "" {
+		override["body"] = map[string]interface{}{"type": "Inline", "inline": response.Body}
+	}
+	return map[string]interface{}{
+		"responseOverride": []interface{}{
+			map[string]interface{}{
+				"match": map[string]interface{}{
+					"statusCodes": []interface{}{
+						map[string]interface{}{"type": "Value", "value": int64(500)},
+					},
+				},
+				"response": override,
+			},
+		},
+	}
+}
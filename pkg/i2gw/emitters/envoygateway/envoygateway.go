@@ -0,0 +1,238 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The Name of the emitter.
+const Name = "envoy-gateway"
+
+const envoyGatewayGroup = "gateway.envoyproxy.io"
+
+var (
+	// BackendTrafficPolicyGVK is the GroupVersionKind of the Envoy Gateway
+	// BackendTrafficPolicies.
+	BackendTrafficPolicyGVK = schema.GroupVersionKind{Group: envoyGatewayGroup, Version: "v1alpha1", Kind: "BackendTrafficPolicy"}
+	// SecurityPolicyGVK is the GroupVersionKind of the Envoy Gateway
+	// SecurityPolicies.
+	SecurityPolicyGVK = schema.GroupVersionKind{Group: envoyGatewayGroup, Version: "v1alpha1", Kind: "SecurityPolicy"}
+	// ClientTrafficPolicyGVK is the GroupVersionKind of the Envoy Gateway
+	// ClientTrafficPolicies.
+	ClientTrafficPolicyGVK = schema.GroupVersionKind{Group: envoyGatewayGroup, Version: "v1alpha1", Kind: "ClientTrafficPolicy"}
+	// EnvoyProxyGVK is the GroupVersionKind of the Envoy Gateway EnvoyProxies.
+	EnvoyProxyGVK = schema.GroupVersionKind{Group: envoyGatewayGroup, Version: "v1alpha1", Kind: "EnvoyProxy"}
+	// BackendGVK is the GroupVersionKind of the Envoy Gateway Backends.
+	BackendGVK = schema.GroupVersionKind{Group: envoyGatewayGroup, Version: "v1alpha1", Kind: "Backend"}
+)
+
+func init() {
+	i2gw.EmitterConstructorByName[Name] = NewEmitter
+}
+
+// Emitter implements the i2gw.Emitter interface for Envoy Gateway, generating
+// BackendTrafficPolicies and SecurityPolicies targeting the HTTPRoutes, and
+// ClientTrafficPolicies and EnvoyProxies for the Gateways, for the policies of
+// the IR exceeding the Gateway API core.
+type Emitter struct{}
+
+// NewEmitter constructs and returns the Envoy Gateway implementation of
+// i2gw.Emitter.
+func NewEmitter() i2gw.Emitter {
+	return &Emitter{}
+}
+
+// Emit generates the Envoy Gateway policies of the ingress-nginx, Skipper and
+// Kong policies, and of the direct responses, of the HTTPRoutes. Envoy Gateway attaches a single
+// BackendTrafficPolicy and SecurityPolicy to an HTTPRoute: the policies of
+// the Ingresses, or Kong plugins, of an HTTPRoute are merged into them, the
+// first one, in the order of the Ingresses, configuring each feature being
+// kept.
+func (e *Emitter) Emit(ir intermediate.IR, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	gateways := newGatewayPolicies()
+	backends := map[types.NamespacedName]bool{}
+	for _, routeKey := range sortedKeys(ir.HTTPRoutes) {
+		httpRoute, ok := gatewayResources.HTTPRoutes[routeKey]
+		if !ok {
+			continue
+		}
+		providerIR := ir.HTTPRoutes[routeKey].ProviderSpecificIR
+		backendTraffic := newRoutePolicy(BackendTrafficPolicyGVK, routeKey.Name+"-backend-traffic", &httpRoute)
+		security := newRoutePolicy(SecurityPolicyGVK, routeKey.Name+"-security", &httpRoute)
+
+		if routeIR := providerIR.IngressNginx; routeIR != nil {
+			ingressNames := make([]string, 0, len(routeIR.Policies))
+			for name := range routeIR.Policies {
+				ingressNames = append(ingressNames, name)
+			}
+			slices.Sort(ingressNames)
+
+			for _, ingressName := range ingressNames {
+				policy := routeIR.Policies[ingressName]
+				source := fmt.Sprintf("ingress %s/%s", routeKey.Namespace, ingressName)
+				if policy.CustomHTTPErrors != nil {
+					notify(notifications.WarningNotification, fmt.Sprintf("the custom error responses %v of %s are not converted by the Envoy Gateway emitter, the error responses of the backends are returned as is", policy.CustomHTTPErrors.Codes, source), &httpRoute)
+				}
+				backendTraffic.merge(source, policy.RuleIndices, ingressNginxBackendTrafficSpec(source, policy))
+				spec, backend := ingressNginxSecuritySpec(routeKey.Namespace, ingressName, policy)
+				security.merge(source, policy.RuleIndices, spec)
+				if backend != nil {
+					key := types.NamespacedName{Namespace: backend.GetNamespace(), Name: backend.GetName()}
+					if !backends[key] {
+						backends[key] = true
+						gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, *backend)
+					}
+				}
+				if policy.Buffering != nil {
+					gateways.addBufferLimit(source, policy.Buffering, &httpRoute)
+				}
+				if policy.MultipleAuth() && policy.AuthSatisfy != intermediate.AuthSatisfyAny {
+					gateways.addBasicAuthFirst(&httpRoute)
+				}
+			}
+		}
+
+		if routeIR := providerIR.Skipper; routeIR != nil {
+			ingressNames := make([]string, 0, len(routeIR.Policies))
+			for name := range routeIR.Policies {
+				ingressNames = append(ingressNames, name)
+			}
+			slices.Sort(ingressNames)
+
+			for _, ingressName := range ingressNames {
+				policy := routeIR.Policies[ingressName]
+				source := fmt.Sprintf("ingress %s/%s", routeKey.Namespace, ingressName)
+				backendTraffic.merge(source, policy.RuleIndices, skipperBackendTrafficSpec(source, policy))
+			}
+		}
+
+		if routeIR := providerIR.Kong; routeIR != nil {
+			for _, policy := range routeIR.Policies {
+				source := fmt.Sprintf("Kong plugin %s", policy.Plugin)
+				backendTraffic.merge(source, policy.RuleIndices, kongBackendTrafficSpec(source, policy))
+				security.merge(source, policy.RuleIndices, kongSecuritySpec(source, policy))
+				removePluginFilter(&httpRoute, policy.RuleIndices, policy.Plugin)
+			}
+		}
+
+		directResponses(ir.HTTPRoutes[routeKey], &httpRoute, backendTraffic)
+
+		for _, policy := range []*routePolicy{backendTraffic, security} {
+			if len(policy.spec) == 0 {
+				continue
+			}
+			gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, policy.toUnstructured())
+			notify(notifications.InfoNotification, fmt.Sprintf("generated %s %s/%s targeting HTTPRoute %s", policy.gvk.Kind, routeKey.Namespace, policy.name, routeKey), &httpRoute)
+		}
+		gatewayResources.HTTPRoutes[routeKey] = httpRoute
+	}
+
+	gateways.emit(gatewayResources)
+	return nil
+}
+
+// routePolicy is an Envoy Gateway policy targeting an HTTPRoute, merging the
+// policies of the sources of its rules.
+type routePolicy struct {
+	gvk       schema.GroupVersionKind
+	name      string
+	httpRoute *gatewayv1.HTTPRoute
+	spec      map[string]interface{}
+}
+
+func newRoutePolicy(gvk schema.GroupVersionKind, name string, httpRoute *gatewayv1.HTTPRoute) *routePolicy {
+	return &routePolicy{gvk: gvk, name: name, httpRoute: httpRoute, spec: map[string]interface{}{}}
+}
+
+// merge adds the fields of the spec of the policy of a source, applying to the
+// HTTPRoute rules of the given indices, to the spec of the policy. The fields
+// already set by a previous source are kept.
+func (p *routePolicy) merge(source string, ruleIndices []int, spec map[string]interface{}) {
+	if len(spec) == 0 {
+		return
+	}
+	fields := make([]string, 0, len(spec))
+	for name := range spec {
+		fields = append(fields, name)
+	}
+	slices.Sort(fields)
+
+	for _, name := range fields {
+		if existing, ok := p.spec[name]; ok {
+			if !equality.Semantic.DeepEqual(existing, spec[name]) {
+				notify(notifications.WarningNotification, fmt.Sprintf("%s %s already configures %s for the rules of another source, the %s of %s was ignored", p.gvk.Kind, p.name, name, name, source), p.httpRoute)
+			}
+			continue
+		}
+		p.spec[name] = spec[name]
+	}
+	if !coversAllRules(ruleIndices, len(p.httpRoute.Spec.Rules)) {
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %s applies the %s of %s to all the rules of HTTPRoute %s/%s, including the rules of other sources", p.gvk.Kind, p.name, strings.Join(fields, ", "), source, p.httpRoute.Namespace, p.httpRoute.Name), p.httpRoute)
+	}
+}
+
+func (p *routePolicy) toUnstructured() unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"targetRefs": []interface{}{
+			map[string]interface{}{"group": gatewayv1.GroupName, "kind": "HTTPRoute", "name": p.httpRoute.Name},
+		},
+	}
+	for name, value := range p.spec {
+		spec[name] = value
+	}
+	return newResource(p.gvk, types.NamespacedName{Namespace: p.httpRoute.Namespace, Name: p.name}, spec)
+}
+
+// coversAllRules returns whether the given rule indices include all the rules
+// of an HTTPRoute with the given number of rules.
+func coversAllRules(ruleIndices []int, rules int) bool {
+	for i := 0; i < rules; i++ {
+		if !slices.Contains(ruleIndices, i) {
+			return false
+		}
+	}
+	return true
+}
+
+func newResource(gvk schema.GroupVersionKind, key types.NamespacedName, spec map[string]interface{}) unstructured.Unstructured {
+	resource := unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	resource.SetGroupVersionKind(gvk)
+	resource.SetNamespace(key.Namespace)
+	resource.SetName(key.Name)
+	return resource
+}
+
+func toInterfaces(values []string) []interface{} {
+	var result []interface{}
+	for _, value := range values {
+		result = append(result, strings.TrimSpace(value))
+	}
+	return result
+}
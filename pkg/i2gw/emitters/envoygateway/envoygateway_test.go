@@ -0,0 +1,335 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func envoyGatewayResource(kind, name string, spec map[string]interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.envoyproxy.io/v1alpha1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"namespace": "default", "name": name},
+		"spec":       spec,
+	}}
+}
+
+func routeTargetRefs(name string) []interface{} {
+	return []interface{}{map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "HTTPRoute", "name": name}}
+}
+
+func Test_Emit(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "app-example-com"}
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}}},
+			Rules:           []gatewayv1.HTTPRouteRule{{}, {}},
+		},
+	}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			routeKey: {
+				HTTPRoute: httpRoute,
+				ProviderSpecificIR: intermediate.ProviderSpecificHTTPRouteIR{
+					IngressNginx: &intermediate.IngressNginxHTTPRouteIR{
+						Policies: map[string]intermediate.IngressNginxPolicy{
+							"api": {
+								RuleIndices: []int{0},
+								RateLimit:   &intermediate.RateLimitConfig{Requests: 10, Period: time.Second, Burst: 50},
+								BasicAuth:   &intermediate.BasicAuthConfig{SecretName: "basic-auth", SecretType: "auth-file"},
+								ExternalAuth: &intermediate.ExternalAuthConfig{
+									URL:             "http://auth.auth.svc.cluster.local:8080/verify",
+									ResponseHeaders: []string{"X-User"},
+								},
+								SourceRange: &intermediate.SourceRangeConfig{Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.0.0.1/32"}},
+								Buffering:   &intermediate.BufferingConfig{MaxRequestBodyBytes: ptr.To[int64](8 << 20)},
+								UpstreamConnection: &intermediate.UpstreamConnectionConfig{
+									KeepaliveConnections: ptr.To[int32](0),
+									KeepaliveTimeout:     ptr.To(90 * time.Second),
+								},
+							},
+							"web": {
+								RuleIndices: []int{1},
+								// The rate limit of the api Ingress is kept.
+								RateLimit: &intermediate.RateLimitConfig{Requests: 100, Period: time.Minute},
+								Affinity:  &intermediate.AffinityConfig{HashBy: "$http_x_user"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	gatewayResources := i2gw.GatewayResources{
+		Gateways:   map[types.NamespacedName]gatewayv1.Gateway{gatewayKey: {ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"}}},
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{routeKey: httpRoute},
+	}
+
+	if errs := NewEmitter().Emit(ir, &gatewayResources); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	expected := []unstructured.Unstructured{
+		envoyGatewayResource("BackendTrafficPolicy", "app-example-com-backend-traffic", map[string]interface{}{
+			"targetRefs": routeTargetRefs("app-example-com"),
+			"rateLimit": map[string]interface{}{
+				"type": "Local",
+				"local": map[string]interface{}{
+					"rules": []interface{}{
+						map[string]interface{}{"limit": map[string]interface{}{"requests": int64(10), "unit": "Second"}},
+					},
+				},
+			},
+			"timeout":        map[string]interface{}{"http": map[string]interface{}{"connectionIdleTimeout": "1m30s"}},
+			"circuitBreaker": map[string]interface{}{"maxRequestsPerConnection": int64(1)},
+			"loadBalancer": map[string]interface{}{
+				"type": "ConsistentHash",
+				"consistentHash": map[string]interface{}{
+					"type":   "Header",
+					"header": map[string]interface{}{"name": "x-user"},
+				},
+			},
+		}),
+		envoyGatewayResource("SecurityPolicy", "app-example-com-security", map[string]interface{}{
+			"targetRefs": routeTargetRefs("app-example-com"),
+			"basicAuth":  map[string]interface{}{"users": map[string]interface{}{"name": "basic-auth"}},
+			"extAuth": map[string]interface{}{
+				"http": map[string]interface{}{
+					"backendRefs": []interface{}{
+						map[string]interface{}{"name": "auth", "namespace": "auth", "port": int64(8080)},
+					},
+					"path":             "/verify",
+					"headersToBackend": []interface{}{"X-User"},
+				},
+			},
+			"authorization": map[string]interface{}{
+				"defaultAction": "Deny",
+				"rules": []interface{}{
+					map[string]interface{}{"action": "Deny", "principal": map[string]interface{}{"clientCIDRs": []interface{}{"10.0.0.1/32"}}},
+					map[string]interface{}{"action": "Allow", "principal": map[string]interface{}{"clientCIDRs": []interface{}{"10.0.0.0/8"}}},
+				},
+			},
+		}),
+		envoyGatewayResource("ClientTrafficPolicy", "nginx-client-traffic", map[string]interface{}{
+			"targetRefs": []interface{}{map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "nginx"}},
+			"connection": map[string]interface{}{"bufferLimit": "8Mi"},
+		}),
+		envoyGatewayResource("EnvoyProxy", "nginx-proxy", map[string]interface{}{
+			"filterOrder": []interface{}{
+				map[string]interface{}{"name": "envoy.filters.http.basic_auth", "before": "envoy.filters.http.ext_authz"},
+			},
+		}),
+	}
+	if diff := cmp.Diff(expected, gatewayResources.GatewayExtensions); diff != "" {
+		t.Errorf("Unexpected Envoy Gateway resources (-want +got): %s", diff)
+	}
+
+	expectedParametersRef := &gatewayv1.LocalParametersReference{Group: "gateway.envoyproxy.io", Kind: "EnvoyProxy", Name: "nginx-proxy"}
+	if diff := cmp.Diff(expectedParametersRef, gatewayResources.Gateways[gatewayKey].Spec.Infrastructure.ParametersRef); diff != "" {
+		t.Errorf("Unexpected Gateway parametersRef (-want +got): %s", diff)
+	}
+}
+
+func Test_Emit_kong(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "api"}
+	pluginFilter := gatewayv1.HTTPRouteFilter{
+		Type:         gatewayv1.HTTPRouteFilterExtensionRef,
+		ExtensionRef: &gatewayv1.LocalObjectReference{Group: "configuration.konghq.com", Kind: "KongPlugin", Name: "cors"},
+	}
+	otherFilter := gatewayv1.HTTPRouteFilter{
+		Type:         gatewayv1.HTTPRouteFilterExtensionRef,
+		ExtensionRef: &gatewayv1.LocalObjectReference{Group: "configuration.konghq.com", Kind: "KongPlugin", Name: "other"},
+	}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Rules: []gatewayv1.HTTPRouteRule{{Filters: []gatewayv1.HTTPRouteFilter{pluginFilter, otherFilter}}},
+		},
+	}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			routeKey: {
+				HTTPRoute: httpRoute,
+				ProviderSpecificIR: intermediate.ProviderSpecificHTTPRouteIR{
+					Kong: &intermediate.KongHTTPRouteIR{
+						Policies: []intermediate.KongPolicy{{
+							Plugin:      "cors",
+							RuleIndices: []int{0},
+							CORS: &intermediate.KongCORSConfig{
+								Origins:     []string{"https://example.com"},
+								Methods:     []string{"GET", "POST"},
+								Credentials: true,
+								MaxAge:      ptr.To(time.Hour),
+							},
+						}},
+					},
+				},
+			},
+		},
+	}
+	gatewayResources := i2gw.GatewayResources{
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{routeKey: httpRoute},
+	}
+
+	if errs := NewEmitter().Emit(ir, &gatewayResources); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	expected := []unstructured.Unstructured{
+		envoyGatewayResource("SecurityPolicy", "api-security", map[string]interface{}{
+			"targetRefs": routeTargetRefs("api"),
+			"cors": map[string]interface{}{
+				"allowOrigins":     []interface{}{"https://example.com"},
+				"allowMethods":     []interface{}{"GET", "POST"},
+				"allowCredentials": true,
+				"maxAge":           "1h",
+			},
+		}),
+	}
+	if diff := cmp.Diff(expected, gatewayResources.GatewayExtensions); diff != "" {
+		t.Errorf("Unexpected Envoy Gateway resources (-want +got): %s", diff)
+	}
+	if diff := cmp.Diff([]gatewayv1.HTTPRouteFilter{otherFilter}, gatewayResources.HTTPRoutes[routeKey].Spec.Rules[0].Filters); diff != "" {
+		t.Errorf("Unexpected HTTPRoute filters (-want +got): %s", diff)
+	}
+}
+
+func Test_externalAuthSpec(t *testing.T) {
+	testCases := []struct {
+		name            string
+		url             string
+		expectedExtAuth map[string]interface{}
+		expectedBackend *unstructured.Unstructured
+	}{
+		{
+			name: "Service of the namespace",
+			url:  "http://auth/",
+			expectedExtAuth: map[string]interface{}{"http": map[string]interface{}{
+				"backendRefs": []interface{}{map[string]interface{}{"name": "auth", "port": int64(80)}},
+			}},
+		},
+		{
+			name: "external host",
+			url:  "https://auth.example.com/oauth2/auth",
+			expectedExtAuth: map[string]interface{}{"http": map[string]interface{}{
+				"backendRefs": []interface{}{map[string]interface{}{
+					"group": "gateway.envoyproxy.io",
+					"kind":  "Backend",
+					"name":  "app-external-auth",
+					"port":  int64(443),
+				}},
+				"path": "/oauth2/auth",
+			}},
+			expectedBackend: ptr.To(envoyGatewayResource("Backend", "app-external-auth", map[string]interface{}{
+				"endpoints": []interface{}{
+					map[string]interface{}{"fqdn": map[string]interface{}{"hostname": "auth.example.com", "port": int64(443)}},
+				},
+			})),
+		},
+		{
+			name: "invalid URL",
+			url:  "auth.example.com",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extAuth, backend := externalAuthSpec("default", "app", intermediate.ExternalAuthConfig{URL: tc.url})
+			if diff := cmp.Diff(tc.expectedExtAuth, extAuth); diff != "" {
+				t.Errorf("Unexpected extAuth (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedBackend, backend); diff != "" {
+				t.Errorf("Unexpected Backend (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_Emit_directResponses(t *testing.T) {
+	maintenanceKey := types.NamespacedName{Namespace: "default", Name: "maintenance"}
+	mixedKey := types.NamespacedName{Namespace: "default", Name: "mixed"}
+	maintenance := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: maintenanceKey.Namespace, Name: maintenanceKey.Name},
+		Spec:       gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{}, {}}},
+	}
+	mixed := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: mixedKey.Namespace, Name: mixedKey.Name},
+		Spec:       gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{}, {}, {}}},
+	}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			maintenanceKey: {
+				HTTPRoute: maintenance,
+				DirectResponses: map[int]intermediate.DirectResponse{
+					0: {StatusCode: 503, Body: "Down for maintenance", ContentType: "text/html"},
+					1: {StatusCode: 503, Body: "Down for maintenance", ContentType: "text/html"},
+				},
+			},
+			// The response overrides of Envoy Gateway apply to the whole
+			// HTTPRoute, so that neither the 503 nor the 403 responses, which
+			// leave the last rule proxied, are converted.
+			mixedKey: {
+				HTTPRoute: mixed,
+				DirectResponses: map[int]intermediate.DirectResponse{
+					0: {StatusCode: 503},
+					1: {StatusCode: 403},
+				},
+			},
+		},
+	}
+	gatewayResources := i2gw.GatewayResources{
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{maintenanceKey: maintenance, mixedKey: mixed},
+	}
+
+	if errs := NewEmitter().Emit(ir, &gatewayResources); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	expected := []unstructured.Unstructured{
+		envoyGatewayResource("BackendTrafficPolicy", "maintenance-backend-traffic", map[string]interface{}{
+			"targetRefs": routeTargetRefs("maintenance"),
+			"responseOverride": []interface{}{
+				map[string]interface{}{
+					"match": map[string]interface{}{
+						"statusCodes": []interface{}{map[string]interface{}{"type": "Value", "value": int64(500)}},
+					},
+					"response": map[string]interface{}{
+						"statusCode":  int64(503),
+						"contentType": "text/html",
+						"body":        map[string]interface{}{"type": "Inline", "inline": "Down for maintenance"},
+					},
+				},
+			},
+		}),
+	}
+	if diff := cmp.Diff(expected, gatewayResources.GatewayExtensions); diff != "" {
+		t.Errorf("Unexpected Envoy Gateway resources (-want +got): %s", diff)
+	}
+}
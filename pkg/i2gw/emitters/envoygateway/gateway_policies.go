@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// gatewayPolicies collects the configuration of the ClientTrafficPolicies and
+// EnvoyProxies of the Gateways, set by the policies of their HTTPRoutes.
+type gatewayPolicies struct {
+	// bufferLimits are the connection buffer limits of the Gateways, with the
+	// source they were set by.
+	bufferLimits map[types.NamespacedName]bufferLimit
+	// basicAuthFirst are the Gateways whose proxies must check the basic
+	// authentication before calling the external authentication services.
+	basicAuthFirst map[types.NamespacedName]bool
+}
+
+type bufferLimit struct {
+	bytes  int64
+	source string
+}
+
+func newGatewayPolicies() *gatewayPolicies {
+	return &gatewayPolicies{
+		bufferLimits:   map[types.NamespacedName]bufferLimit{},
+		basicAuthFirst: map[types.NamespacedName]bool{},
+	}
+}
+
+// addBufferLimit limits the buffers of the connections of the Gateways of the
+// HTTPRoute to the maximum size of the request bodies of the given buffering
+// of a source. A Gateway only gets the limit of the first source.
+func (g *gatewayPolicies) addBufferLimit(source string, buffering *intermediate.BufferingConfig, httpRoute *gatewayv1.HTTPRoute) {
+	if buffering.MemRequestBodyBytes != nil {
+		notify(notifications.WarningNotification, fmt.Sprintf("Envoy Gateway buffers the request bodies in memory, the memory buffer size of %s was ignored", source), httpRoute)
+	}
+	// A maximum size of 0 disables the limit.
+	if buffering.MaxRequestBodyBytes == nil || *buffering.MaxRequestBodyBytes == 0 {
+		return
+	}
+	for _, gatewayKey := range routeGateways(httpRoute) {
+		if existing, ok := g.bufferLimits[gatewayKey]; ok {
+			if existing.bytes != *buffering.MaxRequestBodyBytes {
+				notify(notifications.WarningNotification, fmt.Sprintf("the connection buffer limit of Gateway %s was set by %s, the maximum request body size of %s was ignored", gatewayKey, existing.source, source), httpRoute)
+			}
+			continue
+		}
+		g.bufferLimits[gatewayKey] = bufferLimit{bytes: *buffering.MaxRequestBodyBytes, source: source}
+		notify(notifications.WarningNotification, fmt.Sprintf("Envoy Gateway limits the buffers of the connections of all the listeners of Gateway %s to the maximum request body size of %s, rather than rejecting the larger request bodies", gatewayKey, source), httpRoute)
+	}
+}
+
+// addBasicAuthFirst orders the basic authentication before the external
+// authentication in the proxies of the Gateways of the HTTPRoute, as
+// ingress-nginx checks it first.
+func (g *gatewayPolicies) addBasicAuthFirst(httpRoute *gatewayv1.HTTPRoute) {
+	for _, gatewayKey := range routeGateways(httpRoute) {
+		g.basicAuthFirst[gatewayKey] = true
+	}
+}
+
+// emit generates the ClientTrafficPolicies and EnvoyProxies of the Gateways,
+// referencing the EnvoyProxies from the infrastructure of the Gateways.
+func (g *gatewayPolicies) emit(gatewayResources *i2gw.GatewayResources) {
+	for _, gatewayKey := range sortedKeys(g.bufferLimits) {
+		key := types.NamespacedName{Namespace: gatewayKey.Namespace, Name: gatewayKey.Name + "-client-traffic"}
+		gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, newResource(ClientTrafficPolicyGVK, key, map[string]interface{}{
+			"targetRefs": []interface{}{
+				map[string]interface{}{"group": gatewayv1.GroupName, "kind": "Gateway", "name": gatewayKey.Name},
+			},
+			"connection": map[string]interface{}{
+				"bufferLimit": resource.NewQuantity(g.bufferLimits[gatewayKey].bytes, resource.BinarySI).String(),
+			},
+		}))
+	}
+
+	for _, gatewayKey := range sortedKeys(g.basicAuthFirst) {
+		gateway, ok := gatewayResources.Gateways[gatewayKey]
+		if !ok {
+			continue
+		}
+		key := types.NamespacedName{Namespace: gatewayKey.Namespace, Name: gatewayKey.Name + "-proxy"}
+		parametersRef := &gatewayv1.LocalParametersReference{
+			Group: gatewayv1.Group(EnvoyProxyGVK.Group),
+			Kind:  gatewayv1.Kind(EnvoyProxyGVK.Kind),
+			Name:  key.Name,
+		}
+		if gateway.Spec.Infrastructure == nil {
+			gateway.Spec.Infrastructure = &gatewayv1.GatewayInfrastructure{}
+		}
+		if existing := gateway.Spec.Infrastructure.ParametersRef; existing != nil && *existing != *parametersRef {
+			notify(notifications.WarningNotification, fmt.Sprintf("Gateway %s already references parameters %s %s, its proxies call the external authentication services before checking the basic authentication", gatewayKey, existing.Kind, existing.Name), &gateway)
+			continue
+		}
+		gateway.Spec.Infrastructure.ParametersRef = parametersRef
+		gatewayResources.Gateways[gatewayKey] = gateway
+		gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, newResource(EnvoyProxyGVK, key, map[string]interface{}{
+			"filterOrder": []interface{}{
+				map[string]interface{}{"name": "envoy.filters.http.basic_auth", "before": "envoy.filters.http.ext_authz"},
+			},
+		}))
+		notify(notifications.InfoNotification, fmt.Sprintf("generated EnvoyProxy %s checking the basic authentication before the external authentication, as ingress-nginx, and referenced it from Gateway %s", key, gatewayKey), &gateway)
+	}
+}
+
+// routeGateways returns the Gateways referenced by the parentRefs of the
+// HTTPRoute.
+func routeGateways(httpRoute *gatewayv1.HTTPRoute) []types.NamespacedName {
+	var gateways []types.NamespacedName
+	for _, parentRef := range httpRoute.Spec.ParentRefs {
+		if (parentRef.Group != nil && *parentRef.Group != gatewayv1.GroupName) || (parentRef.Kind != nil && *parentRef.Kind != "Gateway") {
+			continue
+		}
+		gateway := types.NamespacedName{Namespace: httpRoute.Namespace, Name: string(parentRef.Name)}
+		if parentRef.Namespace != nil {
+			gateway.Namespace = string(*parentRef.Namespace)
+		}
+		if !slices.Contains(gateways, gateway) {
+			gateways = append(gateways, gateway)
+		}
+	}
+	return gateways
+}
+
+func sortedKeys[V any](m map[types.NamespacedName]V) []types.NamespacedName {
+	keys := make([]types.NamespacedName, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return keys
+}
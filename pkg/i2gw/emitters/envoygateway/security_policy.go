@@ -0,0 +1,225 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	emittercommon "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/common"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	kongResourcesGroup = "configuration.konghq.com"
+	kongPluginKind     = "KongPlugin"
+)
+
+// ingressNginxSecuritySpec returns the fields of the SecurityPolicy spec of
+// the authentications and client address restrictions of the given policy of
+// an Ingress, and the Backend of its external authentication service, if it
+// is outside of the cluster.
+func ingressNginxSecuritySpec(namespace, ingressName string, policy intermediate.IngressNginxPolicy) (map[string]interface{}, *unstructured.Unstructured) {
+	source := fmt.Sprintf("ingress %s/%s", namespace, ingressName)
+	spec := map[string]interface{}{}
+	if basicAuth := policy.BasicAuth; basicAuth != nil {
+		spec["basicAuth"] = map[string]interface{}{
+			"users": map[string]interface{}{"name": basicAuth.SecretName},
+		}
+		notify(notifications.WarningNotification, fmt.Sprintf("Envoy Gateway reads the htpasswd credentials of Secret %s/%s from its \".htpasswd\" key, while ingress-nginx reads them %s: convert the Secret before migrating", namespace, basicAuth.SecretName, emittercommon.BasicAuthSecretFormat(basicAuth.SecretType)))
+	}
+
+	var backend *unstructured.Unstructured
+	if externalAuth := policy.ExternalAuth; externalAuth != nil {
+		var extAuth map[string]interface{}
+		extAuth, backend = externalAuthSpec(namespace, ingressName, *externalAuth)
+		if extAuth != nil {
+			spec["extAuth"] = extAuth
+		}
+		if externalAuth.Method != "" {
+			notify(notifications.WarningNotification, fmt.Sprintf("Envoy Gateway calls the external authentication service of %s with the method of the requests instead of %s", source, externalAuth.Method))
+		}
+		if externalAuth.SigninURL != "" {
+			notify(notifications.WarningNotification, fmt.Sprintf("Envoy Gateway doesn't redirect the unauthenticated clients of %s to the sign-in URL, the external authentication service must redirect them itself", source))
+		}
+		if policy.MultipleAuth() && policy.AuthSatisfy == intermediate.AuthSatisfyAny {
+			notify(notifications.WarningNotification, fmt.Sprintf("Envoy Gateway can't accept the requests of %s satisfying any of its basic and external authentications, SecurityPolicies require both", source))
+		}
+	}
+
+	if sourceRange := policy.SourceRange; sourceRange != nil {
+		spec["authorization"] = sourceRangeAuthorization(*sourceRange)
+	}
+	return spec, backend
+}
+
+// externalAuthSpec returns the extAuth field of the SecurityPolicy spec of the
+// given external authentication of an Ingress. The backend of the external
+// service is the Service of its URL when the URL is a cluster domain name,
+// and otherwise the returned Backend.
+func externalAuthSpec(namespace, ingressName string, externalAuth intermediate.ExternalAuthConfig) (map[string]interface{}, *unstructured.Unstructured) {
+	source := fmt.Sprintf("ingress %s/%s", namespace, ingressName)
+	authURL, err := url.Parse(externalAuth.URL)
+	if err != nil || (authURL.Scheme != "http" && authURL.Scheme != "https") || authURL.Hostname() == "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("the external authentication of %s was not converted: %q isn't an HTTP URL", source, externalAuth.URL))
+		return nil, nil
+	}
+	if authURL.Scheme == "https" {
+		notify(notifications.WarningNotification, fmt.Sprintf("Envoy Gateway calls the external authentication service of %s with TLS only if a BackendTLSPolicy targets its backend", source))
+	}
+	port := int64(80)
+	if authURL.Scheme == "https" {
+		port = 443
+	}
+	if authURL.Port() != "" {
+		if port, err = strconv.ParseInt(authURL.Port(), 10, 32); err != nil {
+			notify(notifications.WarningNotification, fmt.Sprintf("the external authentication of %s was not converted: invalid port in %q", source, externalAuth.URL))
+			return nil, nil
+		}
+	}
+
+	backendRef := map[string]interface{}{"port": port}
+	var backend *unstructured.Unstructured
+	if service, ok := clusterService(authURL.Hostname(), namespace); ok {
+		backendRef["name"] = service.Name
+		if service.Namespace != namespace {
+			backendRef["namespace"] = service.Namespace
+		}
+	} else {
+		key := types.NamespacedName{Namespace: namespace, Name: ingressName + "-external-auth"}
+		resource := newResource(BackendGVK, key, map[string]interface{}{
+			"endpoints": []interface{}{
+				map[string]interface{}{
+					"fqdn": map[string]interface{}{"hostname": authURL.Hostname(), "port": port},
+				},
+			},
+		})
+		backend = &resource
+		backendRef["group"] = BackendGVK.Group
+		backendRef["kind"] = BackendGVK.Kind
+		backendRef["name"] = key.Name
+		notify(notifications.InfoNotification, fmt.Sprintf("generated Backend %s for the external authentication service of %s, the Backend API must be enabled in Envoy Gateway", key, source))
+	}
+
+	httpService := map[string]interface{}{"backendRefs": []interface{}{backendRef}}
+	if path := authURL.Path; path != "" && path != "/" {
+		httpService["path"] = path
+	}
+	if len(externalAuth.ResponseHeaders) > 0 {
+		httpService["headersToBackend"] = toInterfaces(externalAuth.ResponseHeaders)
+	}
+	return map[string]interface{}{"http": httpService}, backend
+}
+
+// clusterService returns the Service of the given host when it is a cluster
+// domain name, <service>, <service>.<namespace>.svc or
+// <service>.<namespace>.svc.cluster.local.
+func clusterService(host, namespace string) (types.NamespacedName, bool) {
+	labels := strings.Split(strings.TrimSuffix(host, ".cluster.local"), ".")
+	switch {
+	case len(labels) == 1:
+		return types.NamespacedName{Namespace: namespace, Name: labels[0]}, true
+	case len(labels) == 3 && labels[2] == "svc":
+		return types.NamespacedName{Namespace: labels[1], Name: labels[0]}, true
+	}
+	return types.NamespacedName{}, false
+}
+
+// sourceRangeAuthorization returns the authorization field of the
+// SecurityPolicy spec of the given client address restrictions. The denied
+// addresses are matched first, as ingress-nginx rejects them even when they
+// are in an allowed range.
+func sourceRangeAuthorization(sourceRange intermediate.SourceRangeConfig) map[string]interface{} {
+	defaultAction := "Allow"
+	var rules []interface{}
+	if len(sourceRange.Deny) > 0 {
+		rules = append(rules, map[string]interface{}{
+			"action":    "Deny",
+			"principal": map[string]interface{}{"clientCIDRs": toInterfaces(sourceRange.Deny)},
+		})
+	}
+	if len(sourceRange.Allow) > 0 {
+		defaultAction = "Deny"
+		rules = append(rules, map[string]interface{}{
+			"action":    "Allow",
+			"principal": map[string]interface{}{"clientCIDRs": toInterfaces(sourceRange.Allow)},
+		})
+	}
+	authorization := map[string]interface{}{"defaultAction": defaultAction}
+	if len(rules) > 0 {
+		authorization["rules"] = rules
+	}
+	return authorization
+}
+
+// kongSecuritySpec returns the fields of the SecurityPolicy spec of the CORS
+// configuration of the given Kong plugin.
+func kongSecuritySpec(source string, policy intermediate.KongPolicy) map[string]interface{} {
+	spec := map[string]interface{}{}
+	if cors := policy.CORS; cors != nil {
+		config := map[string]interface{}{}
+		if len(cors.Origins) > 0 {
+			config["allowOrigins"] = toInterfaces(cors.Origins)
+		}
+		if len(cors.Methods) > 0 {
+			config["allowMethods"] = toInterfaces(cors.Methods)
+		}
+		if len(cors.Headers) > 0 {
+			config["allowHeaders"] = toInterfaces(cors.Headers)
+		}
+		if len(cors.ExposedHeaders) > 0 {
+			config["exposeHeaders"] = toInterfaces(cors.ExposedHeaders)
+		}
+		if cors.Credentials {
+			config["allowCredentials"] = true
+		}
+		if cors.MaxAge != nil {
+			maxAge, err := common.ToGatewayDuration(*cors.MaxAge)
+			if err != nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("the CORS max age of %s was not converted: %v", source, err))
+			} else {
+				config["maxAge"] = string(maxAge)
+			}
+		}
+		spec["cors"] = config
+	}
+	return spec
+}
+
+// removePluginFilter removes the ExtensionRef filters referencing the Kong
+// plugin from the HTTPRoute rules of the given indices, the policies of the
+// plugin being attached to the HTTPRoute.
+func removePluginFilter(httpRoute *gatewayv1.HTTPRoute, ruleIndices []int, plugin string) {
+	for _, i := range ruleIndices {
+		if i >= len(httpRoute.Spec.Rules) {
+			continue
+		}
+		rule := &httpRoute.Spec.Rules[i]
+		rule.Filters = slices.DeleteFunc(rule.Filters, func(filter gatewayv1.HTTPRouteFilter) bool {
+			return filter.ExtensionRef != nil && filter.ExtensionRef.Group == kongResourcesGroup &&
+				filter.ExtensionRef.Kind == kongPluginKind && string(filter.ExtensionRef.Name) == plugin
+		})
+	}
+}
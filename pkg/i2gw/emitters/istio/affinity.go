@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// DestinationRuleGVK is the GroupVersionKind of the Istio DestinationRules.
+var DestinationRuleGVK = schema.GroupVersionKind{
+	Group:   "networking.istio.io",
+	Version: "v1",
+	Kind:    "DestinationRule",
+}
+
+// affinityDestinationRules generates a DestinationRule load balancing each
+// Service the affinity policies of the ingress-nginx HTTPRoutes apply to by
+// consistent hashing. A Service only gets one DestinationRule: when the
+// policies of several Ingresses apply to it, the first one, in the order of
+// the HTTPRoutes and Ingresses, is kept.
+func affinityDestinationRules(routeKeys []types.NamespacedName, ir intermediate.IR, httpRoutes map[types.NamespacedName]gatewayv1.HTTPRoute, emit func(unstructured.Unstructured)) {
+	consistentHashes := map[types.NamespacedName]map[string]interface{}{}
+	for _, routeKey := range routeKeys {
+		routeIR := ir.HTTPRoutes[routeKey].ProviderSpecificIR.IngressNginx
+		httpRoute, ok := httpRoutes[routeKey]
+		if routeIR == nil || !ok {
+			continue
+		}
+
+		ingressNames := make([]string, 0, len(routeIR.Policies))
+		for name := range routeIR.Policies {
+			ingressNames = append(ingressNames, name)
+		}
+		slices.Sort(ingressNames)
+
+		for _, ingressName := range ingressNames {
+			policy := routeIR.Policies[ingressName]
+			if policy.Affinity == nil {
+				continue
+			}
+			consistentHash, ok := affinityConsistentHash(routeKey.Namespace, ingressName, *policy.Affinity, &httpRoute)
+			if !ok {
+				continue
+			}
+			for _, serviceKey := range ruleServices(httpRoute, policy.RuleIndices) {
+				if existing, ok := consistentHashes[serviceKey]; ok {
+					if !equality.Semantic.DeepEqual(existing, consistentHash) {
+						notify(notifications.WarningNotification, fmt.Sprintf("Service %s is a backend of Ingresses pinning their clients differently, only the affinity of the first one was kept, ingress %s/%s was ignored", serviceKey, routeKey.Namespace, ingressName), &httpRoute)
+					}
+					continue
+				}
+				consistentHashes[serviceKey] = consistentHash
+				destinationRule := newObject(DestinationRuleGVK, serviceKey.Namespace, serviceKey.Name+"-affinity", map[string]interface{}{
+					"host": fmt.Sprintf("%s.%s.svc.cluster.local", serviceKey.Name, serviceKey.Namespace),
+					"trafficPolicy": map[string]interface{}{
+						"loadBalancer": map[string]interface{}{"consistentHash": consistentHash},
+					},
+				})
+				emit(destinationRule)
+				notify(notifications.InfoNotification, fmt.Sprintf("generated DestinationRule %s/%s for the affinity of ingress %s/%s", destinationRule.GetNamespace(), destinationRule.GetName(), routeKey.Namespace, ingressName), &httpRoute)
+			}
+		}
+	}
+}
+
+// affinityConsistentHash returns the consistentHash load balancer settings of
+// a DestinationRule equivalent to the affinity policy of an Ingress. It
+// returns false if the expression hashed by nginx has no Istio equivalent.
+func affinityConsistentHash(namespace, ingressName string, affinity intermediate.AffinityConfig, httpRoute *gatewayv1.HTTPRoute) (map[string]interface{}, bool) {
+	if cookie := affinity.Cookie; cookie != nil {
+		// Istio only generates the cookie when it has a TTL, 0s generating a
+		// session cookie.
+		ttl := time.Duration(0)
+		switch {
+		case cookie.MaxAge != nil:
+			ttl = *cookie.MaxAge
+		case cookie.Expires != nil:
+			ttl = *cookie.Expires
+		}
+		httpCookie := map[string]interface{}{
+			"name": cookie.Name,
+			"ttl":  fmt.Sprintf("%ds", int64(ttl.Seconds())),
+		}
+		if cookie.Path != "" {
+			httpCookie["path"] = cookie.Path
+		}
+
+		var ignored []string
+		if cookie.Domain != "" {
+			ignored = append(ignored, "domain")
+		}
+		if cookie.SameSite != "" {
+			ignored = append(ignored, "SameSite")
+		}
+		if cookie.Secure {
+			ignored = append(ignored, "Secure")
+		}
+		if len(ignored) > 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("the %s attributes of the affinity cookie of ingress %s/%s are not set by Istio", strings.Join(ignored, ", "), namespace, ingressName), httpRoute)
+		}
+		if cookie.Mode == intermediate.AffinityModePersistent {
+			notify(notifications.WarningNotification, fmt.Sprintf("the sessions of ingress %s/%s are pinned by consistent hashing, some of them move to other endpoints when the backends scale", namespace, ingressName), httpRoute)
+		}
+		return map[string]interface{}{"httpCookie": httpCookie}, true
+	}
+
+	hashBy := affinity.HashBy
+	switch {
+	case hashBy == "$remote_addr" || hashBy == "$binary_remote_addr":
+		return map[string]interface{}{"useSourceIp": true}, true
+	case strings.HasPrefix(hashBy, "$http_") && isNginxVariableName(hashBy):
+		return map[string]interface{}{"httpHeaderName": strings.ReplaceAll(strings.TrimPrefix(hashBy, "$http_"), "_", "-")}, true
+	case strings.HasPrefix(hashBy, "$arg_") && isNginxVariableName(hashBy):
+		return map[string]interface{}{"httpQueryParameterName": strings.TrimPrefix(hashBy, "$arg_")}, true
+	case strings.HasPrefix(hashBy, "$cookie_") && isNginxVariableName(hashBy):
+		return map[string]interface{}{"httpCookie": map[string]interface{}{"name": strings.TrimPrefix(hashBy, "$cookie_")}}, true
+	}
+	notify(notifications.WarningNotification, fmt.Sprintf("Istio can't hash the requests of ingress %s/%s by %q, their affinity was not converted", namespace, ingressName, hashBy), httpRoute)
+	return nil, false
+}
+
+// isNginxVariableName returns whether the expression is a single nginx
+// variable.
+func isNginxVariableName(expression string) bool {
+	return strings.Count(expression, "$") == 1 && !strings.ContainsAny(expression, " {}")
+}
+
+// ruleServices returns the Services referenced by the backendRefs of the
+// HTTPRoute rules of the given indices.
+func ruleServices(httpRoute gatewayv1.HTTPRoute, ruleIndices []int) []types.NamespacedName {
+	var services []types.NamespacedName
+	for _, i := range ruleIndices {
+		if i >= len(httpRoute.Spec.Rules) {
+			continue
+		}
+		for _, backendRef := range httpRoute.Spec.Rules[i].BackendRefs {
+			if (backendRef.Group != nil && *backendRef.Group != "") || (backendRef.Kind != nil && *backendRef.Kind != "Service") {
+				continue
+			}
+			service := types.NamespacedName{Namespace: httpRoute.Namespace, Name: string(backendRef.Name)}
+			if backendRef.Namespace != nil {
+				service.Namespace = string(*backendRef.Namespace)
+			}
+			if !slices.Contains(services, service) {
+				services = append(services, service)
+			}
+		}
+	}
+	return services
+}
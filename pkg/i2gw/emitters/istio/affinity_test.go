@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_affinityDestinationRules(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "app-example-com"}
+	backendRule := func(service gatewayv1.ObjectName) gatewayv1.HTTPRouteRule {
+		return gatewayv1.HTTPRouteRule{BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: service, Port: ptr.To[gatewayv1.PortNumber](80)}}}}}
+	}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Rules: []gatewayv1.HTTPRouteRule{backendRule("app"), backendRule("api"), backendRule("app")},
+		},
+	}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			routeKey: {
+				HTTPRoute: httpRoute,
+				ProviderSpecificIR: intermediate.ProviderSpecificHTTPRouteIR{
+					IngressNginx: &intermediate.IngressNginxHTTPRouteIR{
+						Policies: map[string]intermediate.IngressNginxPolicy{
+							"app": {
+								RuleIndices: []int{0},
+								Affinity: &intermediate.AffinityConfig{Cookie: &intermediate.AffinityCookieConfig{
+									Name:   "route",
+									Path:   "/",
+									MaxAge: ptr.To(48 * time.Hour),
+									Mode:   intermediate.AffinityModeBalanced,
+								}},
+							},
+							"api": {
+								RuleIndices: []int{1},
+								Affinity:    &intermediate.AffinityConfig{HashBy: "$http_x_user_id"},
+							},
+							// The Service already gets the affinity of the app Ingress.
+							"other": {
+								RuleIndices: []int{2},
+								Affinity:    &intermediate.AffinityConfig{HashBy: "$binary_remote_addr"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var got []unstructured.Unstructured
+	affinityDestinationRules([]types.NamespacedName{routeKey}, ir, map[types.NamespacedName]gatewayv1.HTTPRoute{routeKey: httpRoute}, func(obj unstructured.Unstructured) {
+		got = append(got, obj)
+	})
+
+	destinationRule := func(service string, consistentHash map[string]interface{}) unstructured.Unstructured {
+		return newObject(DestinationRuleGVK, "default", service+"-affinity", map[string]interface{}{
+			"host": service + ".default.svc.cluster.local",
+			"trafficPolicy": map[string]interface{}{
+				"loadBalancer": map[string]interface{}{"consistentHash": consistentHash},
+			},
+		})
+	}
+	expected := []unstructured.Unstructured{
+		destinationRule("api", map[string]interface{}{"httpHeaderName": "x-user-id"}),
+		destinationRule("app", map[string]interface{}{"httpCookie": map[string]interface{}{"name": "route", "path": "/", "ttl": "172800s"}}),
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Unexpected DestinationRules (-want +got): %s", diff)
+	}
+}
+
+func Test_affinityConsistentHash(t *testing.T) {
+	testCases := []struct {
+		name     string
+		affinity intermediate.AffinityConfig
+		expected map[string]interface{}
+	}{
+		{
+			name:     "session cookie",
+			affinity: intermediate.AffinityConfig{Cookie: &intermediate.AffinityCookieConfig{Name: "INGRESSCOOKIE", Mode: intermediate.AffinityModePersistent}},
+			expected: map[string]interface{}{"httpCookie": map[string]interface{}{"name": "INGRESSCOOKIE", "ttl": "0s"}},
+		},
+		{
+			name:     "client address",
+			affinity: intermediate.AffinityConfig{HashBy: "$binary_remote_addr"},
+			expected: map[string]interface{}{"useSourceIp": true},
+		},
+		{
+			name:     "query parameter",
+			affinity: intermediate.AffinityConfig{HashBy: "$arg_session"},
+			expected: map[string]interface{}{"httpQueryParameterName": "session"},
+		},
+		{
+			name:     "existing cookie",
+			affinity: intermediate.AffinityConfig{HashBy: "$cookie_sid"},
+			expected: map[string]interface{}{"httpCookie": map[string]interface{}{"name": "sid"}},
+		},
+		{
+			name:     "request URI",
+			affinity: intermediate.AffinityConfig{HashBy: "$request_uri"},
+		},
+		{
+			name:     "combined variables",
+			affinity: intermediate.AffinityConfig{HashBy: "$http_x_tenant$arg_user"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			consistentHash, ok := affinityConsistentHash("default", "app", tc.affinity, &gatewayv1.HTTPRoute{})
+			if ok != (tc.expected != nil) {
+				t.Fatalf("Expected converted to be %v, got %v", tc.expected != nil, ok)
+			}
+			if diff := cmp.Diff(tc.expected, consistentHash); diff != "" {
+				t.Errorf("Unexpected consistentHash (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	faultFilter  = "envoy.filters.http.fault"
+	faultTypeURL = "type.googleapis.com/envoy.extensions.filters.http.fault.v3.HTTPFault"
+)
+
+// grpcStatusCodes are the codes of the gRPC statuses by name.
+var grpcStatusCodes = map[string]int64{
+	"OK":                  0,
+	"CANCELLED":           1,
+	"UNKNOWN":             2,
+	"INVALID_ARGUMENT":    3,
+	"DEADLINE_EXCEEDED":   4,
+	"NOT_FOUND":           5,
+	"ALREADY_EXISTS":      6,
+	"PERMISSION_DENIED":   7,
+	"RESOURCE_EXHAUSTED":  8,
+	"FAILED_PRECONDITION": 9,
+	"ABORTED":             10,
+	"OUT_OF_RANGE":        11,
+	"UNIMPLEMENTED":       12,
+	"INTERNAL":            13,
+	"UNAVAILABLE":         14,
+	"DATA_LOSS":           15,
+	"UNAUTHENTICATED":     16,
+}
+
+// emitFaultInjections generates the EnvoyFilters configuring the fault
+// injection of the istio HTTPRoutes on the virtual hosts of their hostnames.
+// The fault filter is part of the filter chain of the Istio gateways, so it
+// only needs to be configured.
+func emitFaultInjections(routeKeys []types.NamespacedName, ir intermediate.IR, gatewayResources *i2gw.GatewayResources, emit func(unstructured.Unstructured)) {
+	for _, routeKey := range routeKeys {
+		routeIR := ir.HTTPRoutes[routeKey].ProviderSpecificIR.Istio
+		httpRoute, ok := gatewayResources.HTTPRoutes[routeKey]
+		if routeIR == nil || routeIR.FaultInjection == nil || !ok {
+			continue
+		}
+		config, ok := faultConfig(routeKey, *routeIR.FaultInjection)
+		if !ok {
+			continue
+		}
+		for _, gatewayKey := range parentGateways(httpRoute) {
+			envoyFilter := faultEnvoyFilter(routeKey, gatewayKey, config, virtualHostNames(httpRoute, gatewayResources.Gateways[gatewayKey]))
+			emit(envoyFilter)
+			notify(notifications.WarningNotification, fmt.Sprintf("generated EnvoyFilter %s/%s for the fault injection of HTTPRoute %s, it applies to all the paths of its hostnames", envoyFilter.GetNamespace(), envoyFilter.GetName(), routeKey), &httpRoute)
+		}
+	}
+}
+
+// faultConfig returns the configuration of the Envoy fault filter of the fault
+// injection, and whether any of its faults could be converted.
+func faultConfig(routeKey types.NamespacedName, faultInjection intermediate.FaultInjectionConfig) (map[string]interface{}, bool) {
+	config := map[string]interface{}{}
+	if delay := faultInjection.Delay; delay != nil {
+		config["delay"] = map[string]interface{}{
+			"fixed_delay": delay.FixedDelay.String(),
+			"percentage":  fractionalPercent(delay.Percentage),
+		}
+	}
+	if abort := faultInjection.Abort; abort != nil {
+		status := map[string]interface{}{"percentage": fractionalPercent(abort.Percentage)}
+		switch code, ok := grpcStatusCodes[abort.GRPCStatus]; {
+		case abort.HTTPStatus != 0:
+			status["http_status"] = int64(abort.HTTPStatus)
+			config["abort"] = status
+		case ok:
+			status["grpc_status"] = code
+			config["abort"] = status
+		default:
+			notify(notifications.WarningNotification, fmt.Sprintf("unknown gRPC status %q, the abort fault of HTTPRoute %s was not converted", abort.GRPCStatus, routeKey))
+		}
+	}
+	return config, len(config) > 0
+}
+
+// fractionalPercent returns the Envoy fractional percent of a percentage, in
+// millionths to keep its decimals.
+func fractionalPercent(percentage float64) map[string]interface{} {
+	return map[string]interface{}{
+		"numerator":   int64(math.Round(percentage * 10000)),
+		"denominator": "MILLION",
+	}
+}
+
+// faultEnvoyFilter returns the EnvoyFilter configuring the fault filter on the
+// given virtual hosts.
+func faultEnvoyFilter(routeKey, gatewayKey types.NamespacedName, config map[string]interface{}, virtualHosts []string) unstructured.Unstructured {
+	var configPatches []interface{}
+	for _, virtualHost := range virtualHosts {
+		configPatches = append(configPatches, map[string]interface{}{
+			"applyTo": "VIRTUAL_HOST",
+			"match": map[string]interface{}{
+				"context":            "GATEWAY",
+				"routeConfiguration": map[string]interface{}{"vhost": map[string]interface{}{"name": virtualHost}},
+			},
+			"patch": map[string]interface{}{
+				"operation": "MERGE",
+				"value": map[string]interface{}{
+					"typed_per_filter_config": map[string]interface{}{
+						faultFilter: map[string]interface{}{
+							"@type":    typedStructTypeURL,
+							"type_url": faultTypeURL,
+							"value":    config,
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return newObject(EnvoyFilterGVK, routeKey.Namespace, fmt.Sprintf("%s-%s-fault", routeKey.Name, gatewayKey.Name), map[string]interface{}{
+		"targetRefs":    gatewayTargetRefs(gatewayKey),
+		"configPatches": configPatches,
+	})
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_Emit_faultInjection(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "reviews-idx-0"}
+	gatewayKey := types.NamespacedName{Namespace: "istio-system", Name: "ingress"}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{
+				{Name: "ingress", Namespace: (*gatewayv1.Namespace)(&gatewayKey.Namespace)},
+			}},
+			Hostnames: []gatewayv1.Hostname{"reviews.example.com"},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		faultInjection intermediate.FaultInjectionConfig
+		expectedConfig map[string]interface{}
+	}{
+		{
+			name: "delay and HTTP abort",
+			faultInjection: intermediate.FaultInjectionConfig{
+				Delay: &intermediate.FaultDelay{FixedDelay: 5 * time.Second, Percentage: 10},
+				Abort: &intermediate.FaultAbort{HTTPStatus: 503, Percentage: 0.5},
+			},
+			expectedConfig: map[string]interface{}{
+				"delay": map[string]interface{}{
+					"fixed_delay": "5s",
+					"percentage":  map[string]interface{}{"numerator": int64(100000), "denominator": "MILLION"},
+				},
+				"abort": map[string]interface{}{
+					"http_status": int64(503),
+					"percentage":  map[string]interface{}{"numerator": int64(5000), "denominator": "MILLION"},
+				},
+			},
+		},
+		{
+			name: "gRPC abort",
+			faultInjection: intermediate.FaultInjectionConfig{
+				Abort: &intermediate.FaultAbort{GRPCStatus: "UNAVAILABLE", Percentage: 100},
+			},
+			expectedConfig: map[string]interface{}{
+				"abort": map[string]interface{}{
+					"grpc_status": int64(14),
+					"percentage":  map[string]interface{}{"numerator": int64(1000000), "denominator": "MILLION"},
+				},
+			},
+		},
+		{
+			name: "unknown gRPC status",
+			faultInjection: intermediate.FaultInjectionConfig{
+				Abort: &intermediate.FaultAbort{GRPCStatus: "TEAPOT", Percentage: 100},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			faultInjection := tc.faultInjection
+			ir := intermediate.IR{
+				HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+					routeKey: {
+						HTTPRoute: httpRoute,
+						ProviderSpecificIR: intermediate.ProviderSpecificHTTPRouteIR{
+							Istio: &intermediate.IstioHTTPRouteIR{FaultInjection: &faultInjection},
+						},
+					},
+				},
+			}
+			gatewayResources := i2gw.GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					gatewayKey: {
+						ObjectMeta: metav1.ObjectMeta{Namespace: gatewayKey.Namespace, Name: gatewayKey.Name},
+						Spec: gatewayv1.GatewaySpec{
+							Listeners: []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+						},
+					},
+				},
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{routeKey: httpRoute},
+			}
+
+			if errs := NewEmitter().Emit(ir, &gatewayResources); len(errs) > 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+
+			if tc.expectedConfig == nil {
+				if len(gatewayResources.GatewayExtensions) != 0 {
+					t.Fatalf("Expected no resources, got %v", gatewayResources.GatewayExtensions)
+				}
+				return
+			}
+			if len(gatewayResources.GatewayExtensions) != 1 {
+				t.Fatalf("Expected 1 resource, got %d", len(gatewayResources.GatewayExtensions))
+			}
+			envoyFilter := gatewayResources.GatewayExtensions[0]
+			if envoyFilter.GetNamespace() != "default" || envoyFilter.GetName() != "reviews-idx-0-ingress-fault" {
+				t.Errorf("Unexpected EnvoyFilter %s/%s", envoyFilter.GetNamespace(), envoyFilter.GetName())
+			}
+
+			expectedSpec := map[string]interface{}{
+				"targetRefs": []interface{}{map[string]interface{}{"group": gatewayv1.GroupName, "kind": "Gateway", "name": "ingress"}},
+				"configPatches": []interface{}{map[string]interface{}{
+					"applyTo": "VIRTUAL_HOST",
+					"match": map[string]interface{}{
+						"context":            "GATEWAY",
+						"routeConfiguration": map[string]interface{}{"vhost": map[string]interface{}{"name": "reviews.example.com:80"}},
+					},
+					"patch": map[string]interface{}{
+						"operation": "MERGE",
+						"value": map[string]interface{}{
+							"typed_per_filter_config": map[string]interface{}{
+								"envoy.filters.http.fault": map[string]interface{}{
+									"@type":    "type.googleapis.com/udpa.type.v1.TypedStruct",
+									"type_url": "type.googleapis.com/envoy.extensions.filters.http.fault.v3.HTTPFault",
+									"value":    tc.expectedConfig,
+								},
+							},
+						},
+					},
+				}},
+			}
+			if diff := cmp.Diff(expectedSpec, envoyFilter.Object["spec"]); diff != "" {
+				t.Errorf("Unexpected EnvoyFilter spec (-want +got): %s", diff)
+			}
+		})
+	}
+}
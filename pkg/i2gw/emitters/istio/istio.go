@@ -0,0 +1,494 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The Name of the emitter.
+const Name = "istio"
+
+var (
+	// EnvoyFilterGVK is the GroupVersionKind of the Istio EnvoyFilters.
+	EnvoyFilterGVK = schema.GroupVersionKind{
+		Group:   "networking.istio.io",
+		Version: "v1alpha3",
+		Kind:    "EnvoyFilter",
+	}
+
+	// AuthorizationPolicyGVK is the GroupVersionKind of the Istio
+	// AuthorizationPolicies.
+	AuthorizationPolicyGVK = schema.GroupVersionKind{
+		Group:   "security.istio.io",
+		Version: "v1",
+		Kind:    "AuthorizationPolicy",
+	}
+)
+
+const (
+	localRateLimitFilter   = "envoy.filters.http.local_ratelimit"
+	localRateLimitTypeURL  = "type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit"
+	localRateLimitStatName = "http_local_rate_limiter"
+	typedStructTypeURL     = "type.googleapis.com/udpa.type.v1.TypedStruct"
+)
+
+func init() {
+	i2gw.EmitterConstructorByName[Name] = NewEmitter
+}
+
+// Emitter implements the i2gw.Emitter interface for Istio, generating the
+// EnvoyFilters and AuthorizationPolicies of the policies of the IR exceeding
+// the Gateway API core, attached to the Gateways of the generated HTTPRoutes,
+// and the DestinationRules of their backends.
+//
+// The generated resources rely on the targetRefs field of EnvoyFilters and
+// AuthorizationPolicies, supported since Istio 1.22.
+type Emitter struct{}
+
+// NewEmitter constructs and returns the Istio implementation of i2gw.Emitter.
+func NewEmitter() i2gw.Emitter {
+	return &Emitter{}
+}
+
+// Emit generates the Istio resources of the ingress-nginx policies of the
+// HTTPRoutes:
+//   - rate limits become local rate limits of the virtual hosts of the
+//     HTTPRoute hostnames, configured with EnvoyFilters.
+//   - basic authentication, which Istio doesn't support, becomes an
+//     AuthorizationPolicy denying the requests, so that the protected paths
+//     aren't exposed until another authentication mechanism is configured.
+//   - external authentication becomes a CUSTOM AuthorizationPolicy delegating
+//     the decision to an extension provider of the mesh config. When the
+//     requests may satisfy any of the basic and external authentications, only
+//     the external authentication is enforced.
+//   - affinity becomes a DestinationRule of each backend Service, load
+//     balancing it by consistent hashing of the cookie, header, query
+//     parameter or client address the clients are pinned by.
+//
+// It also generates the EnvoyFilters of the fault injection of the istio
+// HTTPRoutes.
+func (e *Emitter) Emit(ir intermediate.IR, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	routeKeys := make([]types.NamespacedName, 0, len(ir.HTTPRoutes))
+	for key := range ir.HTTPRoutes {
+		routeKeys = append(routeKeys, key)
+	}
+	slices.SortFunc(routeKeys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	emitted := map[types.NamespacedName]bool{}
+	emit := func(obj unstructured.Unstructured) {
+		key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		if emitted[key] {
+			return
+		}
+		emitted[key] = true
+		gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, obj)
+	}
+
+	for _, routeKey := range routeKeys {
+		routeIR := ir.HTTPRoutes[routeKey].ProviderSpecificIR.IngressNginx
+		httpRoute, ok := gatewayResources.HTTPRoutes[routeKey]
+		if routeIR == nil || !ok {
+			continue
+		}
+		gatewayKeys := parentGateways(httpRoute)
+
+		ingressNames := make([]string, 0, len(routeIR.Policies))
+		for name := range routeIR.Policies {
+			ingressNames = append(ingressNames, name)
+		}
+		slices.Sort(ingressNames)
+
+		for _, ingressName := range ingressNames {
+			policy := routeIR.Policies[ingressName]
+			if len(policy.RuleIndices) == 0 {
+				continue
+			}
+
+			if policy.RateLimit != nil {
+				for _, gatewayKey := range gatewayKeys {
+					emit(localRateLimitFilterEnvoyFilter(gatewayKey))
+					envoyFilter := rateLimitEnvoyFilter(routeKey, gatewayKey, ingressName, *policy.RateLimit, virtualHostNames(httpRoute, gatewayResources.Gateways[gatewayKey]))
+					emit(envoyFilter)
+					notify(notifications.InfoNotification, fmt.Sprintf("generated EnvoyFilter %s/%s for the rate limit of ingress %s/%s", envoyFilter.GetNamespace(), envoyFilter.GetName(), routeKey.Namespace, ingressName), &httpRoute)
+				}
+				if len(policy.RuleIndices) < len(httpRoute.Spec.Rules) {
+					notify(notifications.WarningNotification, fmt.Sprintf("the rate limit of ingress %s/%s applies to all the paths of the hostnames of HTTPRoute %s", routeKey.Namespace, ingressName, routeKey), &httpRoute)
+				}
+			}
+
+			basicAuth := policy.BasicAuth
+			if policy.MultipleAuth() && policy.AuthSatisfy == intermediate.AuthSatisfyAny {
+				basicAuth = nil
+				notify(notifications.WarningNotification, fmt.Sprintf("Istio doesn't support basic authentication: the requests of ingress %s/%s satisfying any of its basic and external authentications are only authenticated with the external authentication", routeKey.Namespace, ingressName), &httpRoute)
+			}
+
+			if policy.ExternalAuth != nil {
+				provider := externalAuthProviderName(routeKey.Namespace, ingressName)
+				for _, gatewayKey := range gatewayKeys {
+					authorizationPolicy, approximated := customAuthorizationPolicy(routeKey, gatewayKey, ingressName, provider, httpRoute, policy.RuleIndices)
+					emit(authorizationPolicy)
+					notify(notifications.WarningNotification, fmt.Sprintf("generated AuthorizationPolicy %s/%s delegating the authentication of the requests to the paths of ingress %s/%s to the %q extension provider, which must be configured in the mesh config as an envoyExtAuthzHttp provider calling %s%s", authorizationPolicy.GetNamespace(), authorizationPolicy.GetName(), routeKey.Namespace, ingressName, provider, policy.ExternalAuth.URL, externalAuthHeadersDescription(*policy.ExternalAuth)), &httpRoute)
+					if approximated {
+						notify(notifications.WarningNotification, fmt.Sprintf("the external authentication of ingress %s/%s applies to all the paths of the hostnames of HTTPRoute %s, as some of its paths are regular expressions", routeKey.Namespace, ingressName, routeKey), &httpRoute)
+					}
+				}
+				if policy.ExternalAuth.SigninURL != "" {
+					notify(notifications.WarningNotification, fmt.Sprintf("Istio doesn't redirect the unauthenticated clients of ingress %s/%s to the sign-in URL, the external authentication service must redirect them itself", routeKey.Namespace, ingressName), &httpRoute)
+				}
+			}
+
+			if basicAuth != nil {
+				for _, gatewayKey := range gatewayKeys {
+					authorizationPolicy, approximated := denyAuthorizationPolicy(routeKey, gatewayKey, ingressName, httpRoute, policy.RuleIndices)
+					emit(authorizationPolicy)
+					notify(notifications.WarningNotification, fmt.Sprintf("Istio doesn't support basic authentication: generated AuthorizationPolicy %s/%s denying the requests to the paths of ingress %s/%s until another authentication mechanism, e.g. RequestAuthentication, is configured", authorizationPolicy.GetNamespace(), authorizationPolicy.GetName(), routeKey.Namespace, ingressName), &httpRoute)
+					if approximated {
+						notify(notifications.WarningNotification, fmt.Sprintf("the AuthorizationPolicy of ingress %s/%s denies the requests to all the paths of the hostnames of HTTPRoute %s, as some of its paths are regular expressions", routeKey.Namespace, ingressName, routeKey), &httpRoute)
+					}
+				}
+			}
+
+			if policy.SourceRange != nil {
+				for _, gatewayKey := range gatewayKeys {
+					authorizationPolicy, approximated := sourceRangeAuthorizationPolicy(routeKey, gatewayKey, ingressName, *policy.SourceRange, httpRoute, policy.RuleIndices)
+					emit(authorizationPolicy)
+					notify(notifications.InfoNotification, fmt.Sprintf("generated AuthorizationPolicy %s/%s restricting the client addresses of the requests to the paths of ingress %s/%s, read from X-Forwarded-For according to the numTrustedProxies of the Gateway", authorizationPolicy.GetNamespace(), authorizationPolicy.GetName(), routeKey.Namespace, ingressName), &httpRoute)
+					if approximated {
+						notify(notifications.WarningNotification, fmt.Sprintf("the client addresses of ingress %s/%s are restricted for all the paths of the hostnames of HTTPRoute %s, as some of its paths are regular expressions", routeKey.Namespace, ingressName, routeKey), &httpRoute)
+					}
+				}
+			}
+
+			if policy.Buffering != nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("the request body sizes of ingress %s/%s have no Istio equivalent and were not converted", routeKey.Namespace, ingressName), &httpRoute)
+			}
+			if policy.CustomHTTPErrors != nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("the custom error responses %v of ingress %s/%s are not converted by the Istio emitter, the error responses of the backends are returned as is", policy.CustomHTTPErrors.Codes, routeKey.Namespace, ingressName), &httpRoute)
+			}
+		}
+	}
+	affinityDestinationRules(routeKeys, ir, gatewayResources.HTTPRoutes, emit)
+	emitFaultInjections(routeKeys, ir, gatewayResources, emit)
+	return nil
+}
+
+// parentGateways returns the keys of the Gateways the HTTPRoute is attached to.
+func parentGateways(httpRoute gatewayv1.HTTPRoute) []types.NamespacedName {
+	var keys []types.NamespacedName
+	for _, parentRef := range httpRoute.Spec.ParentRefs {
+		if parentRef.Kind != nil && *parentRef.Kind != "Gateway" {
+			continue
+		}
+		key := types.NamespacedName{Namespace: httpRoute.Namespace, Name: string(parentRef.Name)}
+		if parentRef.Namespace != nil {
+			key.Namespace = string(*parentRef.Namespace)
+		}
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// virtualHostNames returns the names of the Envoy virtual hosts Istio generates
+// for the hostnames of the HTTPRoute on the listeners of the Gateway, in the
+// <hostname>:<port> format.
+func virtualHostNames(httpRoute gatewayv1.HTTPRoute, gateway gatewayv1.Gateway) []string {
+	hostnames := []string{"*"}
+	if len(httpRoute.Spec.Hostnames) > 0 {
+		hostnames = nil
+		for _, hostname := range httpRoute.Spec.Hostnames {
+			hostnames = append(hostnames, string(hostname))
+		}
+	}
+
+	var ports []gatewayv1.PortNumber
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.Protocol != gatewayv1.HTTPProtocolType && listener.Protocol != gatewayv1.HTTPSProtocolType {
+			continue
+		}
+		if !slices.Contains(ports, listener.Port) {
+			ports = append(ports, listener.Port)
+		}
+	}
+	if len(ports) == 0 {
+		ports = []gatewayv1.PortNumber{80}
+	}
+	slices.Sort(ports)
+
+	var names []string
+	for _, hostname := range hostnames {
+		for _, port := range ports {
+			names = append(names, fmt.Sprintf("%s:%d", hostname, port))
+		}
+	}
+	return names
+}
+
+func gatewayTargetRefs(gatewayKey types.NamespacedName) []interface{} {
+	return []interface{}{map[string]interface{}{
+		"group": gatewayv1.GroupName,
+		"kind":  "Gateway",
+		"name":  gatewayKey.Name,
+	}}
+}
+
+func newObject(gvk schema.GroupVersionKind, namespace, name string, spec map[string]interface{}) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+// localRateLimitFilterEnvoyFilter returns the EnvoyFilter inserting the local
+// rate limit filter in the filter chain of the Gateway. The filter only
+// enforces the token buckets configured on the virtual hosts.
+func localRateLimitFilterEnvoyFilter(gatewayKey types.NamespacedName) unstructured.Unstructured {
+	return newObject(EnvoyFilterGVK, gatewayKey.Namespace, gatewayKey.Name+"-local-ratelimit", map[string]interface{}{
+		"targetRefs": gatewayTargetRefs(gatewayKey),
+		"configPatches": []interface{}{map[string]interface{}{
+			"applyTo": "HTTP_FILTER",
+			"match": map[string]interface{}{
+				"context": "GATEWAY",
+				"listener": map[string]interface{}{
+					"filterChain": map[string]interface{}{
+						"filter": map[string]interface{}{
+							"name":      "envoy.filters.network.http_connection_manager",
+							"subFilter": map[string]interface{}{"name": "envoy.filters.http.router"},
+						},
+					},
+				},
+			},
+			"patch": map[string]interface{}{
+				"operation": "INSERT_BEFORE",
+				"value": map[string]interface{}{
+					"name": localRateLimitFilter,
+					"typed_config": map[string]interface{}{
+						"@type":    typedStructTypeURL,
+						"type_url": localRateLimitTypeURL,
+						"value":    map[string]interface{}{"stat_prefix": localRateLimitStatName},
+					},
+				},
+			},
+		}},
+	})
+}
+
+// rateLimitEnvoyFilter returns the EnvoyFilter configuring the token bucket of
+// the rate limit on the given virtual hosts.
+func rateLimitEnvoyFilter(routeKey, gatewayKey types.NamespacedName, ingressName string, rateLimit intermediate.RateLimitConfig, virtualHosts []string) unstructured.Unstructured {
+	var configPatches []interface{}
+	for _, virtualHost := range virtualHosts {
+		configPatches = append(configPatches, map[string]interface{}{
+			"applyTo": "VIRTUAL_HOST",
+			"match": map[string]interface{}{
+				"context":            "GATEWAY",
+				"routeConfiguration": map[string]interface{}{"vhost": map[string]interface{}{"name": virtualHost}},
+			},
+			"patch": map[string]interface{}{
+				"operation": "MERGE",
+				"value": map[string]interface{}{
+					"typed_per_filter_config": map[string]interface{}{
+						localRateLimitFilter: map[string]interface{}{
+							"@type":    typedStructTypeURL,
+							"type_url": localRateLimitTypeURL,
+							"value": map[string]interface{}{
+								"stat_prefix": localRateLimitStatName,
+								"token_bucket": map[string]interface{}{
+									"max_tokens":      int64(rateLimit.Burst),
+									"tokens_per_fill": int64(rateLimit.Requests),
+									"fill_interval":   rateLimit.Period.String(),
+								},
+								"filter_enabled":  fullyEnabled("local_rate_limit_enabled"),
+								"filter_enforced": fullyEnabled("local_rate_limit_enforced"),
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return newObject(EnvoyFilterGVK, routeKey.Namespace, fmt.Sprintf("%s-%s-rate-limit", ingressName, gatewayKey.Name), map[string]interface{}{
+		"targetRefs":    gatewayTargetRefs(gatewayKey),
+		"configPatches": configPatches,
+	})
+}
+
+// fullyEnabled returns a runtime fractional percent of 100%.
+func fullyEnabled(runtimeKey string) map[string]interface{} {
+	return map[string]interface{}{
+		"runtime_key":   runtimeKey,
+		"default_value": map[string]interface{}{"numerator": int64(100), "denominator": "HUNDRED"},
+	}
+}
+
+// denyAuthorizationPolicy returns the AuthorizationPolicy denying the requests
+// to the paths of the given HTTPRoute rules, and whether it denies the requests
+// to all the paths only because some paths can't be expressed as
+// AuthorizationPolicy paths.
+func denyAuthorizationPolicy(routeKey, gatewayKey types.NamespacedName, ingressName string, httpRoute gatewayv1.HTTPRoute, ruleIndices []int) (unstructured.Unstructured, bool) {
+	operation, approximated := authorizationOperation(httpRoute, ruleIndices)
+	return newObject(AuthorizationPolicyGVK, routeKey.Namespace, fmt.Sprintf("%s-%s-basic-auth", ingressName, gatewayKey.Name), map[string]interface{}{
+		"targetRefs": gatewayTargetRefs(gatewayKey),
+		"action":     "DENY",
+		"rules": []interface{}{map[string]interface{}{
+			"to": []interface{}{map[string]interface{}{"operation": operation}},
+		}},
+	}), approximated
+}
+
+// sourceRangeAuthorizationPolicy returns the AuthorizationPolicy denying the
+// requests to the paths of the given HTTPRoute rules from the client addresses
+// out of the allowed ranges or in the denied ranges, and whether it applies to
+// all the paths only because some paths can't be expressed as
+// AuthorizationPolicy paths.
+func sourceRangeAuthorizationPolicy(routeKey, gatewayKey types.NamespacedName, ingressName string, sourceRange intermediate.SourceRangeConfig, httpRoute gatewayv1.HTTPRoute, ruleIndices []int) (unstructured.Unstructured, bool) {
+	operation, approximated := authorizationOperation(httpRoute, ruleIndices)
+	var rules []interface{}
+	for _, source := range []struct {
+		field string
+		cidrs []string
+	}{
+		{field: "notRemoteIpBlocks", cidrs: sourceRange.Allow},
+		{field: "remoteIpBlocks", cidrs: sourceRange.Deny},
+	} {
+		if len(source.cidrs) == 0 {
+			continue
+		}
+		rules = append(rules, map[string]interface{}{
+			"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{source.field: toInterfaces(source.cidrs)}}},
+			"to":   []interface{}{map[string]interface{}{"operation": operation}},
+		})
+	}
+	return newObject(AuthorizationPolicyGVK, routeKey.Namespace, fmt.Sprintf("%s-%s-source-range", ingressName, gatewayKey.Name), map[string]interface{}{
+		"targetRefs": gatewayTargetRefs(gatewayKey),
+		"action":     "DENY",
+		"rules":      rules,
+	}), approximated
+}
+
+// customAuthorizationPolicy returns the AuthorizationPolicy delegating the
+// authorization of the requests to the paths of the given HTTPRoute rules to
+// the extension provider, and whether it applies to all the paths only because
+// some paths can't be expressed as AuthorizationPolicy paths.
+func customAuthorizationPolicy(routeKey, gatewayKey types.NamespacedName, ingressName, provider string, httpRoute gatewayv1.HTTPRoute, ruleIndices []int) (unstructured.Unstructured, bool) {
+	operation, approximated := authorizationOperation(httpRoute, ruleIndices)
+	return newObject(AuthorizationPolicyGVK, routeKey.Namespace, fmt.Sprintf("%s-%s-external-auth", ingressName, gatewayKey.Name), map[string]interface{}{
+		"targetRefs": gatewayTargetRefs(gatewayKey),
+		"action":     "CUSTOM",
+		"provider":   map[string]interface{}{"name": provider},
+		"rules": []interface{}{map[string]interface{}{
+			"to": []interface{}{map[string]interface{}{"operation": operation}},
+		}},
+	}), approximated
+}
+
+// externalAuthProviderName returns the name of the mesh config extension
+// provider expected for the external authentication of the Ingress.
+func externalAuthProviderName(namespace, ingressName string) string {
+	return fmt.Sprintf("%s-%s-ext-authz", namespace, ingressName)
+}
+
+func externalAuthHeadersDescription(externalAuth intermediate.ExternalAuthConfig) string {
+	if len(externalAuth.ResponseHeaders) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", with headersToUpstreamOnAllow %v", externalAuth.ResponseHeaders)
+}
+
+// authorizationOperation returns the AuthorizationPolicy operation matching the
+// hostnames of the HTTPRoute and the paths of the given rules, and whether it
+// matches all the paths only because some paths can't be expressed as
+// AuthorizationPolicy paths.
+func authorizationOperation(httpRoute gatewayv1.HTTPRoute, ruleIndices []int) (map[string]interface{}, bool) {
+	operation := map[string]interface{}{}
+	var hosts []interface{}
+	for _, hostname := range httpRoute.Spec.Hostnames {
+		hosts = append(hosts, string(hostname), string(hostname)+":*")
+	}
+	if len(hosts) > 0 {
+		operation["hosts"] = hosts
+	}
+
+	paths, approximated := ruleAuthorizationPaths(httpRoute, ruleIndices)
+	if len(paths) > 0 {
+		operation["paths"] = paths
+	}
+	return operation, approximated
+}
+
+// ruleAuthorizationPaths returns the AuthorizationPolicy paths matching the
+// path matches of the given HTTPRoute rules, or no paths if they match all the
+// paths. It also returns whether all the paths are matched only because some of
+// the path matches can't be expressed as AuthorizationPolicy paths.
+func ruleAuthorizationPaths(httpRoute gatewayv1.HTTPRoute, ruleIndices []int) ([]interface{}, bool) {
+	var paths []interface{}
+	addPath := func(path string) {
+		if !slices.Contains(paths, interface{}(path)) {
+			paths = append(paths, path)
+		}
+	}
+	for _, i := range ruleIndices {
+		if i >= len(httpRoute.Spec.Rules) {
+			continue
+		}
+		for _, match := range httpRoute.Spec.Rules[i].Matches {
+			if match.Path == nil || match.Path.Value == nil {
+				return nil, false
+			}
+			value := *match.Path.Value
+			switch {
+			case match.Path.Type == nil || *match.Path.Type == gatewayv1.PathMatchPathPrefix:
+				if value == "/" {
+					return nil, false
+				}
+				prefix := strings.TrimSuffix(value, "/")
+				addPath(prefix)
+				addPath(prefix + "/*")
+			case *match.Path.Type == gatewayv1.PathMatchExact:
+				addPath(value)
+			default:
+				return nil, true
+			}
+		}
+	}
+	return paths, false
+}
+
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_Emit(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "app-example-com"}
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+	pathMatch := func(pathType gatewayv1.PathMatchType, value string) []gatewayv1.HTTPRouteMatch {
+		return []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(pathType), Value: ptr.To(value)}}}
+	}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}}},
+			Hostnames:       []gatewayv1.Hostname{"example.com"},
+			Rules: []gatewayv1.HTTPRouteRule{
+				{Matches: pathMatch(gatewayv1.PathMatchPathPrefix, "/")},
+				{Matches: pathMatch(gatewayv1.PathMatchPathPrefix, "/admin/")},
+				{Matches: pathMatch(gatewayv1.PathMatchExact, "/login")},
+			},
+		},
+	}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			routeKey: {
+				HTTPRoute: httpRoute,
+				ProviderSpecificIR: intermediate.ProviderSpecificHTTPRouteIR{
+					IngressNginx: &intermediate.IngressNginxHTTPRouteIR{
+						Policies: map[string]intermediate.IngressNginxPolicy{
+							"admin": {
+								RuleIndices: []int{1, 2},
+								RateLimit:   &intermediate.RateLimitConfig{Requests: 10, Period: time.Second, Burst: 50},
+								BasicAuth:   &intermediate.BasicAuthConfig{SecretName: "basic-auth", SecretType: "auth-file"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	gatewayResources := i2gw.GatewayResources{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+			gatewayKey: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: gatewayKey.Namespace, Name: gatewayKey.Name},
+				Spec: gatewayv1.GatewaySpec{
+					Listeners: []gatewayv1.Listener{
+						{Name: "example-com-http", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+						{Name: "example-com-https", Port: 443, Protocol: gatewayv1.HTTPSProtocolType},
+					},
+				},
+			},
+		},
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{routeKey: httpRoute},
+	}
+
+	if errs := NewEmitter().Emit(ir, &gatewayResources); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	var names []string
+	for _, obj := range gatewayResources.GatewayExtensions {
+		names = append(names, obj.GetKind()+"/"+obj.GetName())
+	}
+	expectedNames := []string{"EnvoyFilter/nginx-local-ratelimit", "EnvoyFilter/admin-nginx-rate-limit", "AuthorizationPolicy/admin-nginx-basic-auth"}
+	if diff := cmp.Diff(expectedNames, names); diff != "" {
+		t.Fatalf("Unexpected resources (-want +got): %s", diff)
+	}
+
+	var virtualHosts []string
+	patches, _, _ := unstructured.NestedSlice(gatewayResources.GatewayExtensions[1].Object, "spec", "configPatches")
+	for _, patch := range patches {
+		name, _, _ := unstructured.NestedString(patch.(map[string]interface{}), "match", "routeConfiguration", "vhost", "name")
+		virtualHosts = append(virtualHosts, name)
+	}
+	if diff := cmp.Diff([]string{"example.com:80", "example.com:443"}, virtualHosts); diff != "" {
+		t.Errorf("Unexpected virtual hosts (-want +got): %s", diff)
+	}
+
+	expectedRules := []interface{}{map[string]interface{}{
+		"to": []interface{}{map[string]interface{}{
+			"operation": map[string]interface{}{
+				"hosts": []interface{}{"example.com", "example.com:*"},
+				"paths": []interface{}{"/admin", "/admin/*", "/login"},
+			},
+		}},
+	}}
+	rules, _, _ := unstructured.NestedSlice(gatewayResources.GatewayExtensions[2].Object, "spec", "rules")
+	if diff := cmp.Diff(expectedRules, rules); diff != "" {
+		t.Errorf("Unexpected AuthorizationPolicy rules (-want +got): %s", diff)
+	}
+}
+
+func Test_Emit_externalAuth(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "app-example-com"}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}}},
+			Hostnames:       []gatewayv1.Hostname{"example.com"},
+			Rules: []gatewayv1.HTTPRouteRule{
+				{Matches: []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/")}}}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		satisfy       intermediate.AuthSatisfy
+		expectedNames []string
+	}{
+		{
+			name:          "all authentications",
+			satisfy:       intermediate.AuthSatisfyAll,
+			expectedNames: []string{"AuthorizationPolicy/app-nginx-external-auth", "AuthorizationPolicy/app-nginx-basic-auth"},
+		},
+		{
+			name:          "any authentication",
+			satisfy:       intermediate.AuthSatisfyAny,
+			expectedNames: []string{"AuthorizationPolicy/app-nginx-external-auth"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir := intermediate.IR{
+				HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+					routeKey: {
+						HTTPRoute: httpRoute,
+						ProviderSpecificIR: intermediate.ProviderSpecificHTTPRouteIR{
+							IngressNginx: &intermediate.IngressNginxHTTPRouteIR{
+								Policies: map[string]intermediate.IngressNginxPolicy{
+									"app": {
+										RuleIndices:  []int{0},
+										BasicAuth:    &intermediate.BasicAuthConfig{SecretName: "basic-auth", SecretType: "auth-file"},
+										ExternalAuth: &intermediate.ExternalAuthConfig{URL: "http://auth.default.svc/verify"},
+										AuthSatisfy:  tc.satisfy,
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			gatewayResources := i2gw.GatewayResources{
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{routeKey: httpRoute},
+			}
+
+			if errs := NewEmitter().Emit(ir, &gatewayResources); len(errs) > 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+
+			var names []string
+			for _, obj := range gatewayResources.GatewayExtensions {
+				names = append(names, obj.GetKind()+"/"+obj.GetName())
+			}
+			if diff := cmp.Diff(tc.expectedNames, names); diff != "" {
+				t.Fatalf("Unexpected resources (-want +got): %s", diff)
+			}
+
+			expectedSpec := map[string]interface{}{
+				"targetRefs": []interface{}{map[string]interface{}{"group": gatewayv1.GroupName, "kind": "Gateway", "name": "nginx"}},
+				"action":     "CUSTOM",
+				"provider":   map[string]interface{}{"name": "default-app-ext-authz"},
+				"rules": []interface{}{map[string]interface{}{
+					"to": []interface{}{map[string]interface{}{
+						"operation": map[string]interface{}{"hosts": []interface{}{"example.com", "example.com:*"}},
+					}},
+				}},
+			}
+			if diff := cmp.Diff(expectedSpec, gatewayResources.GatewayExtensions[0].Object["spec"]); diff != "" {
+				t.Errorf("Unexpected AuthorizationPolicy spec (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_sourceRangeAuthorizationPolicy(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "app-example-com"}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"example.com"},
+			Rules: []gatewayv1.HTTPRouteRule{
+				{Matches: []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/admin")}}}},
+			},
+		},
+	}
+	sourceRange := intermediate.SourceRangeConfig{Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.0.0.1/32"}}
+
+	authorizationPolicy, approximated := sourceRangeAuthorizationPolicy(routeKey, types.NamespacedName{Namespace: "default", Name: "nginx"}, "app", sourceRange, httpRoute, []int{0})
+	if approximated {
+		t.Errorf("Expected the paths not to be approximated")
+	}
+	if name := authorizationPolicy.GetName(); name != "app-nginx-source-range" {
+		t.Errorf("Unexpected AuthorizationPolicy name %s", name)
+	}
+
+	operation := map[string]interface{}{
+		"hosts": []interface{}{"example.com", "example.com:*"},
+		"paths": []interface{}{"/admin", "/admin/*"},
+	}
+	expectedSpec := map[string]interface{}{
+		"targetRefs": []interface{}{map[string]interface{}{"group": gatewayv1.GroupName, "kind": "Gateway", "name": "nginx"}},
+		"action":     "DENY",
+		"rules": []interface{}{
+			map[string]interface{}{
+				"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{"notRemoteIpBlocks": []interface{}{"10.0.0.0/8"}}}},
+				"to":   []interface{}{map[string]interface{}{"operation": operation}},
+			},
+			map[string]interface{}{
+				"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{"remoteIpBlocks": []interface{}{"10.0.0.1/32"}}}},
+				"to":   []interface{}{map[string]interface{}{"operation": operation}},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedSpec, authorizationPolicy.Object["spec"]); diff != "" {
+		t.Errorf("Unexpected AuthorizationPolicy spec (-want +got): %s", diff)
+	}
+}
+
+func Test_ruleAuthorizationPaths(t *testing.T) {
+	httpRoute := gatewayv1.HTTPRoute{
+		Spec: gatewayv1.HTTPRouteSpec{
+			Rules: []gatewayv1.HTTPRouteRule{
+				{Matches: []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/")}}}},
+				{Matches: []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchRegularExpression), Value: ptr.To("/[a-z]+")}}}},
+			},
+		},
+	}
+
+	paths, approximated := ruleAuthorizationPaths(httpRoute, []int{0})
+	if len(paths) != 0 || approximated {
+		t.Errorf("Expected all the paths to be matched, got %v and %v", paths, approximated)
+	}
+	paths, approximated = ruleAuthorizationPaths(httpRoute, []int{1})
+	if len(paths) != 0 || !approximated {
+		t.Errorf("Expected all the paths to be matched as an approximation, got %v and %v", paths, approximated)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kgateway
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+var DirectResponseGVK = schema.GroupVersionKind{
+	Group:   "gateway.kgateway.dev",
+	Version: "v1alpha1",
+	Kind:    "DirectResponse",
+}
+
+// emitDirectResponses generates a DirectResponse per distinct direct response
+// of the rules of the HTTPRoutes, named <httproute>-direct-response-<n>, and
+// references them from the rules with ExtensionRef filters.
+func emitDirectResponses(routeKeys []types.NamespacedName, ir intermediate.IR, gatewayResources *i2gw.GatewayResources) {
+	for _, routeKey := range routeKeys {
+		directResponses := ir.HTTPRoutes[routeKey].DirectResponses
+		httpRoute, ok := gatewayResources.HTTPRoutes[routeKey]
+		if len(directResponses) == 0 || !ok {
+			continue
+		}
+
+		ruleIndices := make([]int, 0, len(directResponses))
+		for i := range directResponses {
+			ruleIndices = append(ruleIndices, i)
+		}
+		slices.Sort(ruleIndices)
+
+		var generated []intermediate.DirectResponse
+		for _, i := range ruleIndices {
+			if i >= len(httpRoute.Spec.Rules) {
+				notify(notifications.WarningNotification, fmt.Sprintf("the %d direct response of rule %d of HTTPRoute %s was not converted, as the HTTPRoute has %d rules", directResponses[i].StatusCode, i, routeKey, len(httpRoute.Spec.Rules)), &httpRoute)
+				continue
+			}
+			response := directResponses[i]
+			n := slices.Index(generated, response)
+			isNew := n < 0
+			if isNew {
+				generated = append(generated, response)
+				n = len(generated) - 1
+			}
+			name := fmt.Sprintf("%s-direct-response-%d", routeKey.Name, n+1)
+			if isNew {
+				gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, newDirectResponse(types.NamespacedName{Namespace: routeKey.Namespace, Name: name}, response))
+				notify(notifications.InfoNotification, fmt.Sprintf("generated DirectResponse %s/%s for the %d direct response of HTTPRoute %s", routeKey.Namespace, name, response.StatusCode, routeKey), &httpRoute)
+			}
+			httpRoute.Spec.Rules[i].Filters = append(httpRoute.Spec.Rules[i].Filters, gatewayv1.HTTPRouteFilter{
+				Type: gatewayv1.HTTPRouteFilterExtensionRef,
+				ExtensionRef: &gatewayv1.LocalObjectReference{
+					Group: gatewayv1.Group(DirectResponseGVK.Group),
+					Kind:  gatewayv1.Kind(DirectResponseGVK.Kind),
+					Name:  gatewayv1.ObjectName(name),
+				},
+			})
+		}
+		gatewayResources.HTTPRoutes[routeKey] = httpRoute
+	}
+}
+
+func newDirectResponse(key types.NamespacedName, response intermediate.DirectResponse) unstructured.Unstructured {
+	spec := map[string]interface{}{"status": int64(response.StatusCode)}
+	if response.Body != "" {
+		spec["body"] = response.Body
+	}
+	directResponse := unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	directResponse.SetGroupVersionKind(DirectResponseGVK)
+	directResponse.SetNamespace(key.Namespace)
+	directResponse.SetName(key.Name)
+	return directResponse
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kgateway
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// emitFaultInjectionTrafficPolicies generates a TrafficPolicy targeting each
+// istio HTTPRoute injecting faults.
+func emitFaultInjectionTrafficPolicies(routeKeys []types.NamespacedName, ir intermediate.IR, gatewayResources *i2gw.GatewayResources) {
+	for _, routeKey := range routeKeys {
+		routeIR := ir.HTTPRoutes[routeKey].ProviderSpecificIR.Istio
+		httpRoute, ok := gatewayResources.HTTPRoutes[routeKey]
+		if routeIR == nil || routeIR.FaultInjection == nil || !ok {
+			continue
+		}
+		faults := faultsSpec(routeKey, *routeIR.FaultInjection)
+		if len(faults) == 0 {
+			continue
+		}
+		key := types.NamespacedName{Namespace: routeKey.Namespace, Name: routeKey.Name + "-fault"}
+		policy := newTrafficPolicy(key, map[string]interface{}{
+			"targetRefs": []interface{}{
+				map[string]interface{}{"group": gatewayv1.GroupName, "kind": "HTTPRoute", "name": routeKey.Name},
+			},
+			"faults": faults,
+		})
+		gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, policy)
+		notify(notifications.InfoNotification, fmt.Sprintf("generated TrafficPolicy %s for the fault injection of HTTPRoute %s", key, routeKey), &httpRoute)
+	}
+}
+
+// faultsSpec returns the faults of the TrafficPolicy of the fault injection.
+func faultsSpec(routeKey types.NamespacedName, faultInjection intermediate.FaultInjectionConfig) map[string]interface{} {
+	faults := map[string]interface{}{}
+	if delay := faultInjection.Delay; delay != nil {
+		faults["delay"] = map[string]interface{}{
+			"fixedDelay": delay.FixedDelay.String(),
+			"percentage": delay.Percentage,
+		}
+	}
+	if abort := faultInjection.Abort; abort != nil {
+		if abort.HTTPStatus != 0 {
+			faults["abort"] = map[string]interface{}{
+				"httpStatus": int64(abort.HTTPStatus),
+				"percentage": abort.Percentage,
+			}
+		} else {
+			notify(notifications.WarningNotification, fmt.Sprintf("kgateway only aborts requests with HTTP statuses, the abort fault of HTTPRoute %s with gRPC status %s was not converted", routeKey, abort.GRPCStatus))
+		}
+	}
+	return faults
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kgateway
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The Name of the emitter.
+const Name = "kgateway"
+
+// BackendConfigPolicyGVK is the GroupVersionKind of the kgateway
+// BackendConfigPolicies.
+var BackendConfigPolicyGVK = schema.GroupVersionKind{
+	Group:   "gateway.kgateway.dev",
+	Version: "v1alpha1",
+	Kind:    "BackendConfigPolicy",
+}
+
+func init() {
+	i2gw.EmitterConstructorByName[Name] = NewEmitter
+}
+
+// Emitter implements the i2gw.Emitter interface for kgateway, generating
+// BackendConfigPolicies for the connections to the backends configured by the
+// policies of the IR.
+type Emitter struct{}
+
+// NewEmitter constructs and returns the kgateway implementation of i2gw.Emitter.
+func NewEmitter() i2gw.Emitter {
+	return &Emitter{}
+}
+
+// Emit generates a BackendConfigPolicy for each Service the upstream
+// connection policies of the ingress-nginx HTTPRoutes apply to. A Service only
+// gets one BackendConfigPolicy: when the policies of several Ingresses apply to
+// it, the first one, in the order of the HTTPRoutes and Ingresses, is kept.
+// It also generates the TrafficPolicies of the Kong plugins and of the fault
+// injection of the istio HTTPRoutes, and the DirectResponses of the rules.
+func (e *Emitter) Emit(ir intermediate.IR, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	routeKeys := make([]types.NamespacedName, 0, len(ir.HTTPRoutes))
+	for key := range ir.HTTPRoutes {
+		routeKeys = append(routeKeys, key)
+	}
+	slices.SortFunc(routeKeys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	policies := map[types.NamespacedName]unstructured.Unstructured{}
+	var policyKeys []types.NamespacedName
+	for _, routeKey := range routeKeys {
+		routeIR := ir.HTTPRoutes[routeKey].ProviderSpecificIR.IngressNginx
+		httpRoute, ok := gatewayResources.HTTPRoutes[routeKey]
+		if routeIR == nil || !ok {
+			continue
+		}
+
+		ingressNames := make([]string, 0, len(routeIR.Policies))
+		for name := range routeIR.Policies {
+			ingressNames = append(ingressNames, name)
+		}
+		slices.Sort(ingressNames)
+
+		for _, ingressName := range ingressNames {
+			policy := routeIR.Policies[ingressName]
+			if policy.SourceRange != nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("the client address restrictions of ingress %s/%s are not converted by the kgateway emitter, the requests of all the clients are allowed", routeKey.Namespace, ingressName), &httpRoute)
+			}
+			if policy.Affinity != nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("the session affinity of ingress %s/%s is not converted by the kgateway emitter, the requests are load balanced across the endpoints", routeKey.Namespace, ingressName), &httpRoute)
+			}
+			if policy.CustomHTTPErrors != nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("the custom error responses %v of ingress %s/%s are not converted by the kgateway emitter, the error responses of the backends are returned as is", policy.CustomHTTPErrors.Codes, routeKey.Namespace, ingressName), &httpRoute)
+			}
+			if policy.UpstreamConnection == nil {
+				continue
+			}
+			spec := backendConfigPolicySpec(routeKey.Namespace, ingressName, *policy.UpstreamConnection)
+			for _, serviceKey := range ruleServices(httpRoute, policy.RuleIndices) {
+				key := types.NamespacedName{Namespace: serviceKey.Namespace, Name: serviceKey.Name + "-backend-config"}
+				if existing, ok := policies[key]; ok {
+					if existingSpec, _, _ := unstructured.NestedMap(existing.Object, "spec"); !equality.Semantic.DeepEqual(withoutTargetRefs(existingSpec), spec) {
+						notify(notifications.WarningNotification, fmt.Sprintf("Service %s is a backend of Ingresses configuring different connections, only the configuration of BackendConfigPolicy %s was kept, ingress %s/%s was ignored", serviceKey, key, routeKey.Namespace, ingressName), &httpRoute)
+					}
+					continue
+				}
+				policies[key] = newBackendConfigPolicy(key, serviceKey.Name, spec)
+				policyKeys = append(policyKeys, key)
+				notify(notifications.InfoNotification, fmt.Sprintf("generated BackendConfigPolicy %s for the upstream connections of ingress %s/%s", key, routeKey.Namespace, ingressName), &httpRoute)
+			}
+		}
+	}
+
+	for _, key := range policyKeys {
+		gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, policies[key])
+	}
+	emitKongTrafficPolicies(routeKeys, ir, gatewayResources)
+	emitFaultInjectionTrafficPolicies(routeKeys, ir, gatewayResources)
+	emitDirectResponses(routeKeys, ir, gatewayResources)
+	return nil
+}
+
+// backendConfigPolicySpec returns the spec of the BackendConfigPolicies of
+// the given upstream connection policy of an Ingress, without targetRefs.
+func backendConfigPolicySpec(namespace, ingressName string, upstreamConnection intermediate.UpstreamConnectionConfig) map[string]interface{} {
+	spec := map[string]interface{}{}
+	switch upstreamConnection.HTTPVersion {
+	case intermediate.HTTPVersion10:
+		notify(notifications.WarningNotification, fmt.Sprintf("kgateway proxies the requests of ingress %s/%s to its backends with HTTP/1.1 instead of HTTP/1.0", namespace, ingressName))
+		spec["http1ProtocolOptions"] = map[string]interface{}{}
+	case intermediate.HTTPVersion11:
+		spec["http1ProtocolOptions"] = map[string]interface{}{}
+	case intermediate.HTTPVersion2:
+		spec["http2ProtocolOptions"] = map[string]interface{}{}
+	}
+
+	protocolOptions := map[string]interface{}{}
+	if upstreamConnection.KeepaliveRequests != nil {
+		protocolOptions["maxRequestsPerConnection"] = int64(*upstreamConnection.KeepaliveRequests)
+	}
+	if upstreamConnection.KeepaliveTimeout != nil {
+		if idleTimeout, err := common.ToGatewayDuration(*upstreamConnection.KeepaliveTimeout); err != nil {
+			notify(notifications.WarningNotification, fmt.Sprintf("the keepalive timeout of ingress %s/%s was not converted: %v", namespace, ingressName, err))
+		} else {
+			protocolOptions["idleTimeout"] = string(idleTimeout)
+		}
+	}
+	if keepaliveConnections := upstreamConnection.KeepaliveConnections; keepaliveConnections != nil {
+		if *keepaliveConnections == 0 {
+			// Keepalive connections are disabled, each connection only
+			// carries a single request.
+			protocolOptions["maxRequestsPerConnection"] = int64(1)
+		} else {
+			notify(notifications.WarningNotification, fmt.Sprintf("kgateway doesn't limit the number of idle connections to the backends of ingress %s/%s, the keepalive connections limit was ignored", namespace, ingressName))
+		}
+	}
+	if upstreamConnection.KeepaliveTime != nil {
+		notify(notifications.WarningNotification, fmt.Sprintf("kgateway doesn't limit the lifetime of the connections to the backends of ingress %s/%s, the keepalive time was ignored", namespace, ingressName))
+	}
+	if len(protocolOptions) > 0 {
+		spec["commonHttpProtocolOptions"] = protocolOptions
+	}
+	return spec
+}
+
+// ruleServices returns the Services referenced by the backendRefs of the
+// HTTPRoute rules of the given indices.
+func ruleServices(httpRoute gatewayv1.HTTPRoute, ruleIndices []int) []types.NamespacedName {
+	var services []types.NamespacedName
+	for _, i := range ruleIndices {
+		if i >= len(httpRoute.Spec.Rules) {
+			continue
+		}
+		for _, backendRef := range httpRoute.Spec.Rules[i].BackendRefs {
+			if (backendRef.Group != nil && *backendRef.Group != "") || (backendRef.Kind != nil && *backendRef.Kind != "Service") {
+				continue
+			}
+			service := types.NamespacedName{Namespace: httpRoute.Namespace, Name: string(backendRef.Name)}
+			if backendRef.Namespace != nil {
+				service.Namespace = string(*backendRef.Namespace)
+			}
+			if !slices.Contains(services, service) {
+				services = append(services, service)
+			}
+		}
+	}
+	return services
+}
+
+func newBackendConfigPolicy(key types.NamespacedName, serviceName string, spec map[string]interface{}) unstructured.Unstructured {
+	spec = runtime.DeepCopyJSON(withoutTargetRefs(spec))
+	spec["targetRefs"] = []interface{}{
+		map[string]interface{}{"group": "", "kind": "Service", "name": serviceName},
+	}
+	policy := unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	policy.SetGroupVersionKind(BackendConfigPolicyGVK)
+	policy.SetNamespace(key.Namespace)
+	policy.SetName(key.Name)
+	return policy
+}
+
+// withoutTargetRefs returns a copy of the spec of a BackendConfigPolicy
+// without its targetRefs.
+func withoutTargetRefs(spec map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(spec))
+	for key, value := range spec {
+		if key != "targetRefs" {
+			copied[key] = value
+		}
+	}
+	return copied
+}
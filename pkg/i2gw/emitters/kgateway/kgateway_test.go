@@ -0,0 +1,350 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kgateway
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_Emit(t *testing.T) {
+	backendRef := func(name string) gatewayv1.HTTPBackendRef {
+		return gatewayv1.HTTPBackendRef{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+			Name: gatewayv1.ObjectName(name),
+			Port: ptr.To[gatewayv1.PortNumber](80),
+		}}}
+	}
+	routeKey := types.NamespacedName{Namespace: "default", Name: "app-example-com"}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Rules: []gatewayv1.HTTPRouteRule{
+				{BackendRefs: []gatewayv1.HTTPBackendRef{backendRef("web")}},
+				{BackendRefs: []gatewayv1.HTTPBackendRef{backendRef("api"), backendRef("web")}},
+				{BackendRefs: []gatewayv1.HTTPBackendRef{backendRef("grpc")}},
+			},
+		},
+	}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			routeKey: {
+				HTTPRoute: httpRoute,
+				ProviderSpecificIR: intermediate.ProviderSpecificHTTPRouteIR{
+					IngressNginx: &intermediate.IngressNginxHTTPRouteIR{
+						Policies: map[string]intermediate.IngressNginxPolicy{
+							"api": {
+								RuleIndices: []int{1},
+								UpstreamConnection: &intermediate.UpstreamConnectionConfig{
+									HTTPVersion:       intermediate.HTTPVersion11,
+									KeepaliveRequests: ptr.To[int32](100),
+									KeepaliveTimeout:  ptr.To(time.Minute),
+								},
+							},
+							"grpc": {
+								RuleIndices:        []int{2},
+								UpstreamConnection: &intermediate.UpstreamConnectionConfig{HTTPVersion: intermediate.HTTPVersion2},
+							},
+							"web": {
+								RuleIndices:        []int{0},
+								UpstreamConnection: &intermediate.UpstreamConnectionConfig{KeepaliveConnections: ptr.To[int32](0)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	gatewayResources := i2gw.GatewayResources{
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{routeKey: httpRoute},
+	}
+
+	if errs := NewEmitter().Emit(ir, &gatewayResources); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	policy := func(name, service string, spec map[string]interface{}) unstructured.Unstructured {
+		spec = runtime.DeepCopyJSON(spec)
+		spec["targetRefs"] = []interface{}{map[string]interface{}{"group": "", "kind": "Service", "name": service}}
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "gateway.kgateway.dev/v1alpha1",
+			"kind":       "BackendConfigPolicy",
+			"metadata":   map[string]interface{}{"namespace": "default", "name": name},
+			"spec":       spec,
+		}}
+	}
+	httpOptions := map[string]interface{}{
+		"http1ProtocolOptions": map[string]interface{}{},
+		"commonHttpProtocolOptions": map[string]interface{}{
+			"maxRequestsPerConnection": int64(100),
+			"idleTimeout":              "1m",
+		},
+	}
+	expected := []unstructured.Unstructured{
+		policy("api-backend-config", "api", httpOptions),
+		// The web Service was first configured by the policy of the api
+		// Ingress.
+		policy("web-backend-config", "web", httpOptions),
+		policy("grpc-backend-config", "grpc", map[string]interface{}{
+			"http2ProtocolOptions": map[string]interface{}{},
+		}),
+	}
+	if diff := cmp.Diff(expected, gatewayResources.GatewayExtensions); diff != "" {
+		t.Errorf("Unexpected BackendConfigPolicies (-want +got): %s", diff)
+	}
+}
+
+func Test_Emit_kongTrafficPolicies(t *testing.T) {
+	pluginRef := func(group, kind, name string) gatewayv1.HTTPRouteFilter {
+		return gatewayv1.HTTPRouteFilter{
+			Type: gatewayv1.HTTPRouteFilterExtensionRef,
+			ExtensionRef: &gatewayv1.LocalObjectReference{
+				Group: gatewayv1.Group(group),
+				Kind:  gatewayv1.Kind(kind),
+				Name:  gatewayv1.ObjectName(name),
+			},
+		}
+	}
+	routeKey := types.NamespacedName{Namespace: "default", Name: "api-example-com"}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Rules: []gatewayv1.HTTPRouteRule{
+				{Filters: []gatewayv1.HTTPRouteFilter{pluginRef("configuration.konghq.com", "KongPlugin", "rate-limit"), pluginRef("configuration.konghq.com", "KongPlugin", "cors")}},
+				{Filters: []gatewayv1.HTTPRouteFilter{pluginRef("configuration.konghq.com", "KongPlugin", "cors")}},
+			},
+		},
+	}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			routeKey: {
+				HTTPRoute: httpRoute,
+				ProviderSpecificIR: intermediate.ProviderSpecificHTTPRouteIR{
+					Kong: &intermediate.KongHTTPRouteIR{Policies: []intermediate.KongPolicy{
+						{
+							Plugin:      "rate-limit",
+							RuleIndices: []int{0},
+							RateLimit:   &intermediate.KongRateLimitConfig{Requests: 100, Period: time.Minute, LimitBy: "consumer"},
+						},
+						{
+							Plugin:        "cors",
+							ClusterScoped: true,
+							RuleIndices:   []int{0, 1},
+							CORS:          &intermediate.KongCORSConfig{Origins: []string{"https://example.com"}, MaxAge: ptr.To(time.Hour)},
+						},
+					}},
+				},
+			},
+		},
+	}
+	gatewayResources := i2gw.GatewayResources{
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{routeKey: httpRoute},
+	}
+
+	if errs := NewEmitter().Emit(ir, &gatewayResources); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	policy := func(name string, spec map[string]interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "gateway.kgateway.dev/v1alpha1",
+			"kind":       "TrafficPolicy",
+			"metadata":   map[string]interface{}{"namespace": "default", "name": name},
+			"spec":       spec,
+		}}
+	}
+	expected := []unstructured.Unstructured{
+		policy("rate-limit", map[string]interface{}{
+			"rateLimit": map[string]interface{}{"local": map[string]interface{}{"tokenBucket": map[string]interface{}{
+				"maxTokens":     int64(100),
+				"tokensPerFill": int64(100),
+				"fillInterval":  "1m0s",
+			}}},
+		}),
+		policy("cors", map[string]interface{}{
+			"cors": map[string]interface{}{
+				"allowOrigins": []interface{}{"https://example.com"},
+				"maxAge":       int64(3600),
+			},
+		}),
+	}
+	if diff := cmp.Diff(expected, gatewayResources.GatewayExtensions); diff != "" {
+		t.Errorf("Unexpected TrafficPolicies (-want +got): %s", diff)
+	}
+	expectedRules := []gatewayv1.HTTPRouteRule{
+		{Filters: []gatewayv1.HTTPRouteFilter{pluginRef("gateway.kgateway.dev", "TrafficPolicy", "rate-limit"), pluginRef("gateway.kgateway.dev", "TrafficPolicy", "cors")}},
+		{Filters: []gatewayv1.HTTPRouteFilter{pluginRef("gateway.kgateway.dev", "TrafficPolicy", "cors")}},
+	}
+	if diff := cmp.Diff(expectedRules, gatewayResources.HTTPRoutes[routeKey].Spec.Rules); diff != "" {
+		t.Errorf("Unexpected HTTPRoute rules (-want +got): %s", diff)
+	}
+}
+
+func Test_Emit_faultInjectionTrafficPolicies(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "reviews-idx-0"}
+	grpcRouteKey := types.NamespacedName{Namespace: "default", Name: "ratings-idx-0"}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			routeKey: {
+				ProviderSpecificIR: intermediate.ProviderSpecificHTTPRouteIR{
+					Istio: &intermediate.IstioHTTPRouteIR{FaultInjection: &intermediate.FaultInjectionConfig{
+						Delay: &intermediate.FaultDelay{FixedDelay: 5 * time.Second, Percentage: 10},
+						Abort: &intermediate.FaultAbort{HTTPStatus: 503, Percentage: 0.5},
+					}},
+				},
+			},
+			grpcRouteKey: {
+				ProviderSpecificIR: intermediate.ProviderSpecificHTTPRouteIR{
+					Istio: &intermediate.IstioHTTPRouteIR{FaultInjection: &intermediate.FaultInjectionConfig{
+						Abort: &intermediate.FaultAbort{GRPCStatus: "UNAVAILABLE", Percentage: 100},
+					}},
+				},
+			},
+		},
+	}
+	gatewayResources := i2gw.GatewayResources{
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+			routeKey:     {ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name}},
+			grpcRouteKey: {ObjectMeta: metav1.ObjectMeta{Namespace: grpcRouteKey.Namespace, Name: grpcRouteKey.Name}},
+		},
+	}
+
+	if errs := NewEmitter().Emit(ir, &gatewayResources); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	expected := []unstructured.Unstructured{{Object: map[string]interface{}{
+		"apiVersion": "gateway.kgateway.dev/v1alpha1",
+		"kind":       "TrafficPolicy",
+		"metadata":   map[string]interface{}{"namespace": "default", "name": "reviews-idx-0-fault"},
+		"spec": map[string]interface{}{
+			"targetRefs": []interface{}{
+				map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "HTTPRoute", "name": "reviews-idx-0"},
+			},
+			"faults": map[string]interface{}{
+				"delay": map[string]interface{}{"fixedDelay": "5s", "percentage": float64(10)},
+				"abort": map[string]interface{}{"httpStatus": int64(503), "percentage": 0.5},
+			},
+		},
+	}}}
+	if diff := cmp.Diff(expected, gatewayResources.GatewayExtensions); diff != "" {
+		t.Errorf("Unexpected TrafficPolicies (-want +got): %s", diff)
+	}
+}
+
+func Test_Emit_directResponses(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "maintenance"}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec:       gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{}, {}, {}}},
+	}
+	maintenance := intermediate.DirectResponse{StatusCode: 503, Body: "Down for maintenance", ContentType: "text/html"}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			routeKey: {
+				HTTPRoute:       httpRoute,
+				DirectResponses: map[int]intermediate.DirectResponse{0: maintenance, 1: {StatusCode: 403}, 2: maintenance},
+			},
+		},
+	}
+	gatewayResources := i2gw.GatewayResources{
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{routeKey: httpRoute},
+	}
+
+	if errs := NewEmitter().Emit(ir, &gatewayResources); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	directResponse := func(name string, spec map[string]interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "gateway.kgateway.dev/v1alpha1",
+			"kind":       "DirectResponse",
+			"metadata":   map[string]interface{}{"namespace": "default", "name": name},
+			"spec":       spec,
+		}}
+	}
+	expected := []unstructured.Unstructured{
+		directResponse("maintenance-direct-response-1", map[string]interface{}{"status": int64(503), "body": "Down for maintenance"}),
+		directResponse("maintenance-direct-response-2", map[string]interface{}{"status": int64(403)}),
+	}
+	if diff := cmp.Diff(expected, gatewayResources.GatewayExtensions); diff != "" {
+		t.Errorf("Unexpected DirectResponses (-want +got): %s", diff)
+	}
+
+	filter := func(name string) []gatewayv1.HTTPRouteFilter {
+		return []gatewayv1.HTTPRouteFilter{{
+			Type:         gatewayv1.HTTPRouteFilterExtensionRef,
+			ExtensionRef: &gatewayv1.LocalObjectReference{Group: "gateway.kgateway.dev", Kind: "DirectResponse", Name: gatewayv1.ObjectName(name)},
+		}}
+	}
+	for i, expectedFilters := range [][]gatewayv1.HTTPRouteFilter{
+		filter("maintenance-direct-response-1"),
+		filter("maintenance-direct-response-2"),
+		filter("maintenance-direct-response-1"),
+	} {
+		if diff := cmp.Diff(expectedFilters, gatewayResources.HTTPRoutes[routeKey].Spec.Rules[i].Filters); diff != "" {
+			t.Errorf("Unexpected filters of rule %d (-want +got): %s", i, diff)
+		}
+	}
+}
+
+func Test_Emit_directResponsesOutOfRules(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	t.Cleanup(func() {
+		notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	})
+
+	routeKey := types.NamespacedName{Namespace: "default", Name: "maintenance"}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec:       gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{}}},
+	}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			routeKey: {
+				HTTPRoute:       httpRoute,
+				DirectResponses: map[int]intermediate.DirectResponse{1: {StatusCode: 403}},
+			},
+		},
+	}
+	gatewayResources := i2gw.GatewayResources{
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{routeKey: httpRoute},
+	}
+
+	if errs := NewEmitter().Emit(ir, &gatewayResources); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	if len(gatewayResources.GatewayExtensions) > 0 {
+		t.Errorf("Expected no DirectResponses, got %v", gatewayResources.GatewayExtensions)
+	}
+	got := notifications.NotificationAggr.Notifications[Name]
+	if len(got) != 1 || got[0].Type != notifications.WarningNotification || !strings.Contains(got[0].Message, "the 403 direct response of rule 1 of HTTPRoute default/maintenance was not converted") {
+		t.Errorf("Expected a warning about the direct response of the missing rule, got %v", got)
+	}
+}
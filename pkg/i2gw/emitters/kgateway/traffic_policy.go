@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kgateway
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+var TrafficPolicyGVK = schema.GroupVersionKind{
+	Group:   "gateway.kgateway.dev",
+	Version: "v1alpha1",
+	Kind:    "TrafficPolicy",
+}
+
+const (
+	kongResourcesGroup = "configuration.konghq.com"
+	kongPluginKind     = "KongPlugin"
+)
+
+// emitKongTrafficPolicies generates a TrafficPolicy per Kong rate-limiting and
+// cors plugin of the HTTPRoutes, and replaces the ExtensionRef filters
+// referencing the plugins by ExtensionRef filters referencing the
+// TrafficPolicies.
+func emitKongTrafficPolicies(routeKeys []types.NamespacedName, ir intermediate.IR, gatewayResources *i2gw.GatewayResources) {
+	generated := map[types.NamespacedName]bool{}
+	for _, routeKey := range routeKeys {
+		routeIR := ir.HTTPRoutes[routeKey].ProviderSpecificIR.Kong
+		httpRoute, ok := gatewayResources.HTTPRoutes[routeKey]
+		if routeIR == nil || !ok {
+			continue
+		}
+		for _, policy := range routeIR.Policies {
+			key := types.NamespacedName{Namespace: routeKey.Namespace, Name: policy.Plugin}
+			if !generated[key] {
+				generated[key] = true
+				gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, newTrafficPolicy(key, trafficPolicySpec(key, policy)))
+				notify(notifications.InfoNotification, fmt.Sprintf("generated TrafficPolicy %s for Kong plugin %s", key, policy.Plugin), &httpRoute)
+			}
+			replacePluginFilter(&httpRoute, policy.RuleIndices, policy.Plugin)
+		}
+		gatewayResources.HTTPRoutes[routeKey] = httpRoute
+	}
+}
+
+func trafficPolicySpec(key types.NamespacedName, policy intermediate.KongPolicy) map[string]interface{} {
+	spec := map[string]interface{}{}
+	if rateLimit := policy.RateLimit; rateLimit != nil {
+		spec["rateLimit"] = map[string]interface{}{
+			"local": map[string]interface{}{
+				"tokenBucket": map[string]interface{}{
+					"maxTokens":     rateLimit.Requests,
+					"tokensPerFill": rateLimit.Requests,
+					"fillInterval":  rateLimit.Period.String(),
+				},
+			},
+		}
+		notify(notifications.WarningNotification, fmt.Sprintf("TrafficPolicy %s limits the rate of all the requests of each proxy, while Kong limits them per %s", key, rateLimit.LimitBy))
+	}
+	if cors := policy.CORS; cors != nil {
+		config := map[string]interface{}{}
+		if len(cors.Origins) > 0 {
+			config["allowOrigins"] = toInterfaces(cors.Origins)
+		}
+		if len(cors.Methods) > 0 {
+			config["allowMethods"] = toInterfaces(cors.Methods)
+		}
+		if len(cors.Headers) > 0 {
+			config["allowHeaders"] = toInterfaces(cors.Headers)
+		}
+		if len(cors.ExposedHeaders) > 0 {
+			config["exposeHeaders"] = toInterfaces(cors.ExposedHeaders)
+		}
+		if cors.Credentials {
+			config["allowCredentials"] = true
+		}
+		if cors.MaxAge != nil {
+			config["maxAge"] = int64(cors.MaxAge.Seconds())
+		}
+		spec["cors"] = config
+	}
+	return spec
+}
+
+func newTrafficPolicy(key types.NamespacedName, spec map[string]interface{}) unstructured.Unstructured {
+	policy := unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	policy.SetGroupVersionKind(TrafficPolicyGVK)
+	policy.SetNamespace(key.Namespace)
+	policy.SetName(key.Name)
+	return policy
+}
+
+// replacePluginFilter replaces the ExtensionRef filters referencing the Kong
+// plugin in the rules of the given indices by ExtensionRef filters referencing
+// the TrafficPolicy of the same name.
+func replacePluginFilter(httpRoute *gatewayv1.HTTPRoute, ruleIndices []int, plugin string) {
+	for _, i := range ruleIndices {
+		if i >= len(httpRoute.Spec.Rules) {
+			continue
+		}
+		rule := &httpRoute.Spec.Rules[i]
+		for j, filter := range rule.Filters {
+			if filter.ExtensionRef == nil || filter.ExtensionRef.Group != kongResourcesGroup ||
+				filter.ExtensionRef.Kind != kongPluginKind || string(filter.ExtensionRef.Name) != plugin {
+				continue
+			}
+			rule.Filters[j].ExtensionRef = &gatewayv1.LocalObjectReference{
+				Group: gatewayv1.Group(TrafficPolicyGVK.Group),
+				Kind:  gatewayv1.Kind(TrafficPolicyGVK.Kind),
+				Name:  gatewayv1.ObjectName(plugin),
+			}
+		}
+		rule.Filters = slices.CompactFunc(rule.Filters, func(a, b gatewayv1.HTTPRouteFilter) bool {
+			return a.ExtensionRef != nil && b.ExtensionRef != nil && *a.ExtensionRef == *b.ExtensionRef
+		})
+	}
+}
+
+func toInterfaces(values []string) []interface{} {
+	var result []interface{}
+	for _, value := range values {
+		result = append(result, strings.TrimSpace(value))
+	}
+	return result
+}
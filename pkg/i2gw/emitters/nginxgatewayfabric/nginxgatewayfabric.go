@@ -0,0 +1,296 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nginxgatewayfabric
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The Name of the emitter.
+const Name = "nginx-gateway-fabric"
+
+// ClientSettingsPolicyGVK is the GroupVersionKind of the NGINX Gateway Fabric
+// ClientSettingsPolicies.
+var ClientSettingsPolicyGVK = schema.GroupVersionKind{
+	Group:   "gateway.nginx.org",
+	Version: "v1alpha1",
+	Kind:    "ClientSettingsPolicy",
+}
+
+// UpstreamSettingsPolicyGVK is the GroupVersionKind of the NGINX Gateway
+// Fabric UpstreamSettingsPolicies.
+var UpstreamSettingsPolicyGVK = schema.GroupVersionKind{
+	Group:   "gateway.nginx.org",
+	Version: "v1alpha1",
+	Kind:    "UpstreamSettingsPolicy",
+}
+
+func init() {
+	i2gw.EmitterConstructorByName[Name] = NewEmitter
+}
+
+// Emitter implements the i2gw.Emitter interface for NGINX Gateway Fabric,
+// generating ClientSettingsPolicies for the request bodies and
+// UpstreamSettingsPolicies for the connections to the backends configured by
+// the policies of the IR.
+type Emitter struct{}
+
+// NewEmitter constructs and returns the NGINX Gateway Fabric implementation of
+// i2gw.Emitter.
+func NewEmitter() i2gw.Emitter {
+	return &Emitter{}
+}
+
+// Emit generates a ClientSettingsPolicy for each ingress-nginx HTTPRoute
+// limiting the size of the request bodies, and an UpstreamSettingsPolicy for
+// each Service the upstream connection policies of the ingress-nginx
+// HTTPRoutes apply to. An HTTPRoute or a Service only gets one policy: when
+// the policies of several Ingresses apply to it, the first one, in the order
+// of the HTTPRoutes and Ingresses, is kept.
+func (e *Emitter) Emit(ir intermediate.IR, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	routeKeys := make([]types.NamespacedName, 0, len(ir.HTTPRoutes))
+	for key := range ir.HTTPRoutes {
+		routeKeys = append(routeKeys, key)
+	}
+	slices.SortFunc(routeKeys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	var clientSettingsPolicies []unstructured.Unstructured
+	upstreamSettingsPolicies := map[types.NamespacedName]unstructured.Unstructured{}
+	var upstreamSettingsPolicyKeys []types.NamespacedName
+	for _, routeKey := range routeKeys {
+		routeIR := ir.HTTPRoutes[routeKey].ProviderSpecificIR.IngressNginx
+		httpRoute, ok := gatewayResources.HTTPRoutes[routeKey]
+		if routeIR == nil || !ok {
+			continue
+		}
+
+		ingressNames := make([]string, 0, len(routeIR.Policies))
+		for name := range routeIR.Policies {
+			ingressNames = append(ingressNames, name)
+		}
+		slices.Sort(ingressNames)
+
+		var bodyMaxSize, bodyMaxSizeSource string
+		for _, ingressName := range ingressNames {
+			policy := routeIR.Policies[ingressName]
+			source := fmt.Sprintf("ingress %s/%s", routeKey.Namespace, ingressName)
+			if policy.SourceRange != nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("the client address restrictions of %s are not converted by the nginx-gateway-fabric emitter, the requests of all the clients are allowed", source), &httpRoute)
+			}
+			if policy.Affinity != nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("the session affinity of %s is not converted by the nginx-gateway-fabric emitter, the requests are load balanced across the endpoints", source), &httpRoute)
+			}
+			if policy.CustomHTTPErrors != nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("the custom error responses %v of %s are not converted by the nginx-gateway-fabric emitter, the error responses of the backends are returned as is", policy.CustomHTTPErrors.Codes, source), &httpRoute)
+			}
+
+			if policy.Buffering != nil {
+				if policy.Buffering.MemRequestBodyBytes != nil {
+					notify(notifications.WarningNotification, fmt.Sprintf("NGINX Gateway Fabric doesn't configure the buffers of the request bodies, the memory buffer size of %s was ignored", source), &httpRoute)
+				}
+				if maxBytes := policy.Buffering.MaxRequestBodyBytes; maxBytes != nil {
+					maxSize, ok := nginxSize(*maxBytes)
+					switch {
+					case !ok:
+						notify(notifications.WarningNotification, fmt.Sprintf("the maximum request body size %d of %s can't be expressed as an NGINX Gateway Fabric size, it was ignored", *maxBytes, source), &httpRoute)
+					case bodyMaxSizeSource == "":
+						bodyMaxSize, bodyMaxSizeSource = maxSize, source
+						if len(policy.RuleIndices) < len(httpRoute.Spec.Rules) {
+							notify(notifications.WarningNotification, fmt.Sprintf("the maximum request body size of %s applies to all the rules of HTTPRoute %s", source, routeKey), &httpRoute)
+						}
+					case maxSize != bodyMaxSize:
+						notify(notifications.WarningNotification, fmt.Sprintf("the maximum request body size of HTTPRoute %s was set by %s, the maximum request body size of %s was ignored", routeKey, bodyMaxSizeSource, source), &httpRoute)
+					}
+				}
+			}
+
+			if policy.UpstreamConnection == nil {
+				continue
+			}
+			spec := upstreamSettingsPolicySpec(source, *policy.UpstreamConnection)
+			if len(spec) == 0 {
+				continue
+			}
+			for _, serviceKey := range ruleServices(httpRoute, policy.RuleIndices) {
+				key := types.NamespacedName{Namespace: serviceKey.Namespace, Name: serviceKey.Name + "-upstream-settings"}
+				if existing, ok := upstreamSettingsPolicies[key]; ok {
+					if existingKeepAlive, _, _ := unstructured.NestedMap(existing.Object, "spec", "keepAlive"); !equality.Semantic.DeepEqual(existingKeepAlive, spec["keepAlive"]) {
+						notify(notifications.WarningNotification, fmt.Sprintf("Service %s is a backend of Ingresses configuring different connections, only the configuration of UpstreamSettingsPolicy %s was kept, %s was ignored", serviceKey, key, source), &httpRoute)
+					}
+					continue
+				}
+				upstreamSettingsPolicies[key] = newUpstreamSettingsPolicy(key, serviceKey.Name, spec)
+				upstreamSettingsPolicyKeys = append(upstreamSettingsPolicyKeys, key)
+				notify(notifications.InfoNotification, fmt.Sprintf("generated UpstreamSettingsPolicy %s for the upstream connections of %s", key, source), &httpRoute)
+			}
+		}
+
+		if bodyMaxSizeSource != "" {
+			clientSettingsPolicies = append(clientSettingsPolicies, newClientSettingsPolicy(routeKey, bodyMaxSize))
+			notify(notifications.InfoNotification, fmt.Sprintf("generated ClientSettingsPolicy %s-client-settings for the maximum request body size of %s", routeKey, bodyMaxSizeSource), &httpRoute)
+		}
+	}
+
+	gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, clientSettingsPolicies...)
+	for _, key := range upstreamSettingsPolicyKeys {
+		gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, upstreamSettingsPolicies[key])
+	}
+	return nil
+}
+
+// upstreamSettingsPolicySpec returns the spec of the UpstreamSettingsPolicies
+// of the given upstream connection policy of a source, without targetRefs.
+func upstreamSettingsPolicySpec(source string, upstreamConnection intermediate.UpstreamConnectionConfig) map[string]interface{} {
+	switch upstreamConnection.HTTPVersion {
+	case intermediate.HTTPVersion10:
+		notify(notifications.WarningNotification, fmt.Sprintf("NGINX Gateway Fabric proxies the requests of %s to its backends with HTTP/1.1 instead of HTTP/1.0", source))
+	case intermediate.HTTPVersion2:
+		notify(notifications.WarningNotification, fmt.Sprintf("NGINX Gateway Fabric proxies the requests of HTTPRoutes to the backends of %s with HTTP/1.1, only GRPCRoutes are proxied with HTTP/2", source))
+	}
+
+	keepAlive := map[string]interface{}{}
+	if upstreamConnection.KeepaliveConnections != nil {
+		// 0 disables the keepalive connections, as with ingress-nginx.
+		keepAlive["connections"] = int64(*upstreamConnection.KeepaliveConnections)
+	}
+	if upstreamConnection.KeepaliveRequests != nil {
+		keepAlive["requests"] = int64(*upstreamConnection.KeepaliveRequests)
+	}
+	for _, setting := range []struct {
+		field, name string
+		duration    *time.Duration
+	}{
+		{"time", "keepalive time", upstreamConnection.KeepaliveTime},
+		{"timeout", "keepalive timeout", upstreamConnection.KeepaliveTimeout},
+	} {
+		if setting.duration == nil {
+			continue
+		}
+		duration, ok := nginxDuration(*setting.duration)
+		if !ok {
+			notify(notifications.WarningNotification, fmt.Sprintf("the %s %s of %s can't be expressed as an NGINX Gateway Fabric duration, it was ignored", setting.name, setting.duration, source))
+			continue
+		}
+		keepAlive[setting.field] = duration
+	}
+
+	if len(keepAlive) == 0 {
+		return nil
+	}
+	return map[string]interface{}{"keepAlive": keepAlive}
+}
+
+// nginxSize returns the NGINX Gateway Fabric size of the given number of
+// bytes, a number of at most 4 digits with an optional k, m or g unit. It
+// returns false when the number of bytes can't be expressed exactly.
+func nginxSize(bytes int64) (string, bool) {
+	if bytes == 0 {
+		return "0", true
+	}
+	for _, unit := range []struct {
+		suffix string
+		bytes  int64
+	}{{"g", 1 << 30}, {"m", 1 << 20}, {"k", 1 << 10}, {"", 1}} {
+		if bytes%unit.bytes == 0 && bytes/unit.bytes <= 9999 {
+			return fmt.Sprintf("%d%s", bytes/unit.bytes, unit.suffix), true
+		}
+	}
+	return "", false
+}
+
+// nginxDuration returns the NGINX Gateway Fabric duration of the given
+// duration, a number of at most 4 digits with a h, m, s or ms unit. It
+// returns false when the duration can't be expressed exactly.
+func nginxDuration(duration time.Duration) (string, bool) {
+	for _, unit := range []struct {
+		suffix   string
+		duration time.Duration
+	}{{"h", time.Hour}, {"m", time.Minute}, {"s", time.Second}, {"ms", time.Millisecond}} {
+		if duration%unit.duration == 0 && duration/unit.duration <= 9999 {
+			return fmt.Sprintf("%d%s", duration/unit.duration, unit.suffix), true
+		}
+	}
+	return "", false
+}
+
+// ruleServices returns the Services referenced by the backendRefs of the
+// HTTPRoute rules of the given indices.
+func ruleServices(httpRoute gatewayv1.HTTPRoute, ruleIndices []int) []types.NamespacedName {
+	var services []types.NamespacedName
+	for _, i := range ruleIndices {
+		if i >= len(httpRoute.Spec.Rules) {
+			continue
+		}
+		for _, backendRef := range httpRoute.Spec.Rules[i].BackendRefs {
+			if (backendRef.Group != nil && *backendRef.Group != "") || (backendRef.Kind != nil && *backendRef.Kind != "Service") {
+				continue
+			}
+			service := types.NamespacedName{Namespace: httpRoute.Namespace, Name: string(backendRef.Name)}
+			if backendRef.Namespace != nil {
+				service.Namespace = string(*backendRef.Namespace)
+			}
+			if !slices.Contains(services, service) {
+				services = append(services, service)
+			}
+		}
+	}
+	return services
+}
+
+func newClientSettingsPolicy(routeKey types.NamespacedName, bodyMaxSize string) unstructured.Unstructured {
+	policy := unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{
+		"targetRef": map[string]interface{}{
+			"group": gatewayv1.GroupName,
+			"kind":  "HTTPRoute",
+			"name":  routeKey.Name,
+		},
+		"body": map[string]interface{}{"maxSize": bodyMaxSize},
+	}}}
+	policy.SetGroupVersionKind(ClientSettingsPolicyGVK)
+	policy.SetNamespace(routeKey.Namespace)
+	policy.SetName(routeKey.Name + "-client-settings")
+	return policy
+}
+
+func newUpstreamSettingsPolicy(key types.NamespacedName, serviceName string, spec map[string]interface{}) unstructured.Unstructured {
+	policy := unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{
+		"targetRefs": []interface{}{
+			map[string]interface{}{"group": "", "kind": "Service", "name": serviceName},
+		},
+		"keepAlive": runtime.DeepCopyJSONValue(spec["keepAlive"]),
+	}}}
+	policy.SetGroupVersionKind(UpstreamSettingsPolicyGVK)
+	policy.SetNamespace(key.Namespace)
+	policy.SetName(key.Name)
+	return policy
+}
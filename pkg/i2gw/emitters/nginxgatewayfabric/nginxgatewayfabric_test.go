@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nginxgatewayfabric
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_Emit(t *testing.T) {
+	backendRef := func(name string) gatewayv1.HTTPBackendRef {
+		return gatewayv1.HTTPBackendRef{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+			Name: gatewayv1.ObjectName(name),
+			Port: ptr.To[gatewayv1.PortNumber](80),
+		}}}
+	}
+	routeKey := types.NamespacedName{Namespace: "default", Name: "app-example-com"}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Rules: []gatewayv1.HTTPRouteRule{
+				{BackendRefs: []gatewayv1.HTTPBackendRef{backendRef("web")}},
+				{BackendRefs: []gatewayv1.HTTPBackendRef{backendRef("api"), backendRef("web")}},
+			},
+		},
+	}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			routeKey: {
+				HTTPRoute: httpRoute,
+				ProviderSpecificIR: intermediate.ProviderSpecificHTTPRouteIR{
+					IngressNginx: &intermediate.IngressNginxHTTPRouteIR{
+						Policies: map[string]intermediate.IngressNginxPolicy{
+							"api": {
+								RuleIndices: []int{1},
+								Buffering:   &intermediate.BufferingConfig{MaxRequestBodyBytes: ptr.To[int64](8 << 20)},
+								UpstreamConnection: &intermediate.UpstreamConnectionConfig{
+									HTTPVersion:       intermediate.HTTPVersion11,
+									KeepaliveRequests: ptr.To[int32](100),
+									KeepaliveTimeout:  ptr.To(time.Minute),
+									KeepaliveTime:     ptr.To(90 * time.Minute),
+								},
+							},
+							"web": {
+								RuleIndices:        []int{0},
+								Buffering:          &intermediate.BufferingConfig{MaxRequestBodyBytes: ptr.To[int64](1 << 20)},
+								UpstreamConnection: &intermediate.UpstreamConnectionConfig{KeepaliveConnections: ptr.To[int32](0)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	gatewayResources := i2gw.GatewayResources{
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{routeKey: httpRoute},
+	}
+
+	if errs := NewEmitter().Emit(ir, &gatewayResources); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	upstreamSettingsPolicy := func(name, service string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "gateway.nginx.org/v1alpha1",
+			"kind":       "UpstreamSettingsPolicy",
+			"metadata":   map[string]interface{}{"namespace": "default", "name": name},
+			"spec": map[string]interface{}{
+				"targetRefs": []interface{}{map[string]interface{}{"group": "", "kind": "Service", "name": service}},
+				"keepAlive": map[string]interface{}{
+					"requests": int64(100),
+					"time":     "90m",
+					"timeout":  "1m",
+				},
+			},
+		}}
+	}
+	expected := []unstructured.Unstructured{
+		// The maximum request body size of the web Ingress was ignored, the
+		// api Ingress set it first.
+		{Object: map[string]interface{}{
+			"apiVersion": "gateway.nginx.org/v1alpha1",
+			"kind":       "ClientSettingsPolicy",
+			"metadata":   map[string]interface{}{"namespace": "default", "name": "app-example-com-client-settings"},
+			"spec": map[string]interface{}{
+				"targetRef": map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "HTTPRoute", "name": "app-example-com"},
+				"body":      map[string]interface{}{"maxSize": "8m"},
+			},
+		}},
+		upstreamSettingsPolicy("api-upstream-settings", "api"),
+		// The web Service was first configured by the policy of the api
+		// Ingress.
+		upstreamSettingsPolicy("web-upstream-settings", "web"),
+	}
+	if diff := cmp.Diff(expected, gatewayResources.GatewayExtensions); diff != "" {
+		t.Errorf("Unexpected policies (-want +got): %s", diff)
+	}
+}
+
+func Test_nginxSize(t *testing.T) {
+	testCases := []struct {
+		bytes    int64
+		expected string
+		ok       bool
+	}{
+		{bytes: 0, expected: "0", ok: true},
+		{bytes: 512, expected: "512", ok: true},
+		{bytes: 100 << 10, expected: "100k", ok: true},
+		{bytes: 8 << 20, expected: "8m", ok: true},
+		{bytes: 2 << 30, expected: "2g", ok: true},
+		{bytes: 10000 << 20, expected: "", ok: false},
+		{bytes: 10001, expected: "", ok: false},
+	}
+	for _, tc := range testCases {
+		size, ok := nginxSize(tc.bytes)
+		if ok != tc.ok || size != tc.expected {
+			t.Errorf("nginxSize(%d) = %q, %t, want %q, %t", tc.bytes, size, ok, tc.expected, tc.ok)
+		}
+	}
+}
+
+func Test_nginxDuration(t *testing.T) {
+	testCases := []struct {
+		duration time.Duration
+		expected string
+		ok       bool
+	}{
+		{duration: 2 * time.Hour, expected: "2h", ok: true},
+		{duration: 90 * time.Minute, expected: "90m", ok: true},
+		{duration: 75 * time.Second, expected: "75s", ok: true},
+		{duration: 1500 * time.Millisecond, expected: "1500ms", ok: true},
+		{duration: time.Microsecond, expected: "", ok: false},
+	}
+	for _, tc := range testCases {
+		duration, ok := nginxDuration(tc.duration)
+		if ok != tc.ok || duration != tc.expected {
+			t.Errorf("nginxDuration(%s) = %q, %t, want %q, %t", tc.duration, duration, ok, tc.expected, tc.ok)
+		}
+	}
+}
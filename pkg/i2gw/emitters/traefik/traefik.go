@@ -0,0 +1,219 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traefik
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/common"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The Name of the emitter.
+const Name = "traefik"
+
+// MiddlewareGVK is the GroupVersionKind of the Traefik Middlewares.
+var MiddlewareGVK = schema.GroupVersionKind{
+	Group:   "traefik.io",
+	Version: "v1alpha1",
+	Kind:    "Middleware",
+}
+
+func init() {
+	i2gw.EmitterConstructorByName[Name] = NewEmitter
+}
+
+// Emitter implements the i2gw.Emitter interface for Traefik v3, generating
+// Traefik Middlewares for the policies of the IR exceeding the Gateway API
+// core, and referencing them from the HTTPRoute rules with ExtensionRef filters.
+type Emitter struct{}
+
+// NewEmitter constructs and returns the Traefik implementation of i2gw.Emitter.
+func NewEmitter() i2gw.Emitter {
+	return &Emitter{}
+}
+
+// Emit generates the Traefik Middlewares of the ingress-nginx policies of the
+// HTTPRoutes, and adds the ExtensionRef filters referencing them to the rules
+// the policies apply to.
+func (e *Emitter) Emit(ir intermediate.IR, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	routeKeys := make([]types.NamespacedName, 0, len(ir.HTTPRoutes))
+	for key := range ir.HTTPRoutes {
+		routeKeys = append(routeKeys, key)
+	}
+	slices.SortFunc(routeKeys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	middlewares := map[types.NamespacedName]bool{}
+	for _, routeKey := range routeKeys {
+		routeIR := ir.HTTPRoutes[routeKey].ProviderSpecificIR.IngressNginx
+		httpRoute, ok := gatewayResources.HTTPRoutes[routeKey]
+		if routeIR == nil || !ok {
+			continue
+		}
+
+		ingressNames := make([]string, 0, len(routeIR.Policies))
+		for name := range routeIR.Policies {
+			ingressNames = append(ingressNames, name)
+		}
+		slices.Sort(ingressNames)
+
+		for _, ingressName := range ingressNames {
+			policy := routeIR.Policies[ingressName]
+			if policy.SourceRange != nil && len(policy.SourceRange.Deny) > 0 {
+				notify(notifications.WarningNotification, fmt.Sprintf("Traefik can't deny client address ranges, the denied ranges %v of ingress %s/%s were not converted and their clients are allowed", policy.SourceRange.Deny, routeKey.Namespace, ingressName), &httpRoute)
+			}
+			if policy.Affinity != nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("the session affinity of ingress %s/%s is not converted by the Traefik emitter, configure the sticky sessions of the Traefik Services of its backends", routeKey.Namespace, ingressName), &httpRoute)
+			}
+			if policy.CustomHTTPErrors != nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("the custom error responses %v of ingress %s/%s are not converted by the Traefik emitter, configure an errors Middleware serving them", policy.CustomHTTPErrors.Codes, routeKey.Namespace, ingressName), &httpRoute)
+			}
+			for _, middleware := range policyMiddlewares(routeKey.Namespace, ingressName, policy) {
+				key := types.NamespacedName{Namespace: middleware.GetNamespace(), Name: middleware.GetName()}
+				if !middlewares[key] {
+					middlewares[key] = true
+					gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, middleware)
+					if _, ok, _ := unstructured.NestedMap(middleware.Object, "spec", "basicAuth"); ok {
+						notify(notifications.WarningNotification, fmt.Sprintf("Traefik reads the htpasswd credentials of Secret %s/%s from its \"users\" key, while ingress-nginx reads them %s: convert the Secret before migrating", routeKey.Namespace, policy.BasicAuth.SecretName, common.BasicAuthSecretFormat(policy.BasicAuth.SecretType)))
+					}
+					if _, ok, _ := unstructured.NestedMap(middleware.Object, "spec", "forwardAuth"); ok {
+						notifyExternalAuth(routeKey.Namespace, ingressName, policy)
+					}
+				}
+				addMiddlewareFilter(&httpRoute, policy.RuleIndices, key.Name)
+				notify(notifications.InfoNotification, fmt.Sprintf("generated Middleware %s for the policy of ingress %s/%s and referenced it from HTTPRoute %s", key, routeKey.Namespace, ingressName, routeKey), &httpRoute)
+			}
+		}
+		gatewayResources.HTTPRoutes[routeKey] = httpRoute
+	}
+	return nil
+}
+
+// policyMiddlewares returns the Middlewares implementing the given policy of an
+// Ingress.
+func policyMiddlewares(namespace, ingressName string, policy intermediate.IngressNginxPolicy) []unstructured.Unstructured {
+	var middlewares []unstructured.Unstructured
+	if policy.SourceRange != nil && len(policy.SourceRange.Allow) > 0 {
+		var sourceRange []interface{}
+		for _, cidr := range policy.SourceRange.Allow {
+			sourceRange = append(sourceRange, cidr)
+		}
+		middlewares = append(middlewares, newMiddleware(namespace, ingressName+"-source-range", "ipAllowList", map[string]interface{}{
+			"sourceRange": sourceRange,
+		}))
+	}
+	if policy.RateLimit != nil {
+		middlewares = append(middlewares, newMiddleware(namespace, ingressName+"-rate-limit", "rateLimit", map[string]interface{}{
+			"average": int64(policy.RateLimit.Requests),
+			"period":  policy.RateLimit.Period.String(),
+			"burst":   int64(policy.RateLimit.Burst),
+		}))
+	}
+	if policy.BasicAuth != nil {
+		basicAuth := map[string]interface{}{
+			"secret": policy.BasicAuth.SecretName,
+		}
+		if policy.BasicAuth.Realm != "" {
+			basicAuth["realm"] = policy.BasicAuth.Realm
+		}
+		middlewares = append(middlewares, newMiddleware(namespace, ingressName+"-basic-auth", "basicAuth", basicAuth))
+	}
+	if policy.ExternalAuth != nil {
+		forwardAuth := map[string]interface{}{
+			"address": policy.ExternalAuth.URL,
+		}
+		if len(policy.ExternalAuth.ResponseHeaders) > 0 {
+			var headers []interface{}
+			for _, header := range policy.ExternalAuth.ResponseHeaders {
+				headers = append(headers, header)
+			}
+			forwardAuth["authResponseHeaders"] = headers
+		}
+		middlewares = append(middlewares, newMiddleware(namespace, ingressName+"-external-auth", "forwardAuth", forwardAuth))
+	}
+	if policy.Buffering != nil {
+		buffering := map[string]interface{}{}
+		if policy.Buffering.MaxRequestBodyBytes != nil {
+			buffering["maxRequestBodyBytes"] = *policy.Buffering.MaxRequestBodyBytes
+		}
+		if policy.Buffering.MemRequestBodyBytes != nil {
+			buffering["memRequestBodyBytes"] = *policy.Buffering.MemRequestBodyBytes
+		}
+		middlewares = append(middlewares, newMiddleware(namespace, ingressName+"-buffering", "buffering", buffering))
+	}
+	return middlewares
+}
+
+// notifyExternalAuth warns about the parts of the external authentication of
+// the given policy the forwardAuth Middleware doesn't convert.
+func notifyExternalAuth(namespace, ingressName string, policy intermediate.IngressNginxPolicy) {
+	externalAuth := policy.ExternalAuth
+	if externalAuth.Method != "" && externalAuth.Method != "GET" {
+		notify(notifications.WarningNotification, fmt.Sprintf("Traefik calls the external authentication service of ingress %s/%s with GET requests instead of %s requests", namespace, ingressName, externalAuth.Method))
+	}
+	if externalAuth.SigninURL != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("Traefik doesn't redirect the unauthenticated clients of ingress %s/%s to the sign-in URL, the external authentication service must redirect them itself", namespace, ingressName))
+	}
+	if policy.MultipleAuth() && policy.AuthSatisfy == intermediate.AuthSatisfyAny {
+		notify(notifications.WarningNotification, fmt.Sprintf("Traefik can't accept the requests of ingress %s/%s satisfying any of its basic and external authentications: the chained Middlewares require both, basic authentication first", namespace, ingressName))
+	}
+}
+
+func newMiddleware(namespace, name, middlewareType string, config map[string]interface{}) unstructured.Unstructured {
+	middleware := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{middlewareType: config},
+	}}
+	middleware.SetGroupVersionKind(MiddlewareGVK)
+	middleware.SetNamespace(namespace)
+	middleware.SetName(name)
+	return middleware
+}
+
+// addMiddlewareFilter adds an ExtensionRef filter referencing the Middleware to
+// the HTTPRoute rules of the given indices.
+func addMiddlewareFilter(httpRoute *gatewayv1.HTTPRoute, ruleIndices []int, middlewareName string) {
+	filter := gatewayv1.HTTPRouteFilter{
+		Type: gatewayv1.HTTPRouteFilterExtensionRef,
+		ExtensionRef: &gatewayv1.LocalObjectReference{
+			Group: gatewayv1.Group(MiddlewareGVK.Group),
+			Kind:  gatewayv1.Kind(MiddlewareGVK.Kind),
+			Name:  gatewayv1.ObjectName(middlewareName),
+		},
+	}
+	for _, i := range ruleIndices {
+		if i >= len(httpRoute.Spec.Rules) {
+			continue
+		}
+		rule := &httpRoute.Spec.Rules[i]
+		if !slices.ContainsFunc(rule.Filters, func(f gatewayv1.HTTPRouteFilter) bool {
+			return f.ExtensionRef != nil && *f.ExtensionRef == *filter.ExtensionRef
+		}) {
+			rule.Filters = append(rule.Filters, filter)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traefik
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_Emit(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "app-example-com"}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Rules: []gatewayv1.HTTPRouteRule{{}, {}},
+		},
+	}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			routeKey: {
+				HTTPRoute: httpRoute,
+				ProviderSpecificIR: intermediate.ProviderSpecificHTTPRouteIR{
+					IngressNginx: &intermediate.IngressNginxHTTPRouteIR{
+						Policies: map[string]intermediate.IngressNginxPolicy{
+							"app": {
+								RuleIndices: []int{1},
+								RateLimit:   &intermediate.RateLimitConfig{Requests: 10, Period: time.Second, Burst: 50},
+								BasicAuth:   &intermediate.BasicAuthConfig{SecretName: "basic-auth", SecretType: "auth-file", Realm: "Restricted"},
+								ExternalAuth: &intermediate.ExternalAuthConfig{
+									URL:             "http://auth.default.svc/verify",
+									ResponseHeaders: []string{"X-User"},
+								},
+								Buffering:   &intermediate.BufferingConfig{MaxRequestBodyBytes: ptr.To[int64](1024)},
+								AuthSatisfy: intermediate.AuthSatisfyAll,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	gatewayResources := i2gw.GatewayResources{
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{routeKey: httpRoute},
+	}
+
+	if errs := NewEmitter().Emit(ir, &gatewayResources); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	middleware := func(name string, spec map[string]interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "traefik.io/v1alpha1",
+			"kind":       "Middleware",
+			"metadata":   map[string]interface{}{"namespace": "default", "name": name},
+			"spec":       spec,
+		}}
+	}
+	expectedMiddlewares := []unstructured.Unstructured{
+		middleware("app-rate-limit", map[string]interface{}{"rateLimit": map[string]interface{}{"average": int64(10), "period": "1s", "burst": int64(50)}}),
+		middleware("app-basic-auth", map[string]interface{}{"basicAuth": map[string]interface{}{"secret": "basic-auth", "realm": "Restricted"}}),
+		middleware("app-external-auth", map[string]interface{}{"forwardAuth": map[string]interface{}{"address": "http://auth.default.svc/verify", "authResponseHeaders": []interface{}{"X-User"}}}),
+		middleware("app-buffering", map[string]interface{}{"buffering": map[string]interface{}{"maxRequestBodyBytes": int64(1024)}}),
+	}
+	if diff := cmp.Diff(expectedMiddlewares, gatewayResources.GatewayExtensions); diff != "" {
+		t.Errorf("Unexpected Middlewares (-want +got): %s", diff)
+	}
+
+	filter := func(name string) gatewayv1.HTTPRouteFilter {
+		return gatewayv1.HTTPRouteFilter{
+			Type:         gatewayv1.HTTPRouteFilterExtensionRef,
+			ExtensionRef: &gatewayv1.LocalObjectReference{Group: "traefik.io", Kind: "Middleware", Name: gatewayv1.ObjectName(name)},
+		}
+	}
+	expectedRules := []gatewayv1.HTTPRouteRule{
+		{},
+		{Filters: []gatewayv1.HTTPRouteFilter{filter("app-rate-limit"), filter("app-basic-auth"), filter("app-external-auth"), filter("app-buffering")}},
+	}
+	if diff := cmp.Diff(expectedRules, gatewayResources.HTTPRoutes[routeKey].Spec.Rules); diff != "" {
+		t.Errorf("Unexpected rules (-want +got): %s", diff)
+	}
+}
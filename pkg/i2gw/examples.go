@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import "sync"
+
+// ProviderExample is a representative manifest of the source resources of a
+// feature of a provider, converted by the example command.
+type ProviderExample struct {
+	// Feature is the name of the feature the example demonstrates, e.g. the
+	// name of its FeatureParser.
+	Feature string
+
+	// Description explains what the example demonstrates.
+	Description string
+
+	// Manifest is the YAML manifest of the source resources of the example.
+	Manifest string
+}
+
+var providerExamples = struct {
+	examples map[ProviderName][]ProviderExample
+	mu       sync.RWMutex
+}{examples: map[ProviderName][]ProviderExample{}}
+
+// RegisterProviderExample registers an example of a feature of the provider.
+// The examples are converted in the order they were registered in. It is
+// thread-safe.
+func RegisterProviderExample(provider ProviderName, example ProviderExample) {
+	providerExamples.mu.Lock()
+	defer providerExamples.mu.Unlock()
+	providerExamples.examples[provider] = append(providerExamples.examples[provider], example)
+}
+
+// GetProviderExamples returns the examples registered by the provider.
+func GetProviderExamples(provider ProviderName) []ProviderExample {
+	providerExamples.mu.RLock()
+	defer providerExamples.mu.RUnlock()
+	return append([]ProviderExample(nil), providerExamples.examples[provider]...)
+}
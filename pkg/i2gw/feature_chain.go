@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// NamedFeatureParser is a FeatureParser of a FeatureChain, along with the
+// names of the FeatureParsers it must run after.
+type NamedFeatureParser struct {
+	// Name identifies the FeatureParser in the chain.
+	Name string
+
+	// Parse is the FeatureParser.
+	Parse FeatureParser
+
+	// After lists the names of the FeatureParsers which must run before this
+	// one, typically because they mutate the same HTTPRoute rules.
+	After []string
+}
+
+// FeatureParserMiddleware wraps the FeatureParser of the given name, e.g. to
+// observe or instrument its invocation.
+type FeatureParserMiddleware func(name string, next FeatureParser) FeatureParser
+
+// FeatureChain runs FeatureParsers in an order satisfying their declared
+// dependencies. FeatureParsers without dependencies between them run in the
+// order they were declared in.
+type FeatureChain struct {
+	parsers     []NamedFeatureParser
+	middlewares []FeatureParserMiddleware
+}
+
+// NewFeatureChain returns a FeatureChain of the given FeatureParsers.
+func NewFeatureChain(parsers ...NamedFeatureParser) *FeatureChain {
+	return &FeatureChain{parsers: parsers}
+}
+
+// Use adds middlewares wrapping every FeatureParser of the chain. The first
+// middleware is the outermost one.
+func (c *FeatureChain) Use(middlewares ...FeatureParserMiddleware) *FeatureChain {
+	c.middlewares = append(c.middlewares, middlewares...)
+	return c
+}
+
+// Order returns the FeatureParsers in the order they run, or an error if their
+// dependencies are duplicated, unknown or cyclic.
+func (c *FeatureChain) Order() ([]NamedFeatureParser, error) {
+	byName := make(map[string]NamedFeatureParser, len(c.parsers))
+	for _, parser := range c.parsers {
+		if _, ok := byName[parser.Name]; ok {
+			return nil, fmt.Errorf("feature parser %q is declared more than once", parser.Name)
+		}
+		byName[parser.Name] = parser
+	}
+	for _, parser := range c.parsers {
+		for _, dependency := range parser.After {
+			if _, ok := byName[dependency]; !ok {
+				return nil, fmt.Errorf("feature parser %q runs after unknown feature parser %q", parser.Name, dependency)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(c.parsers))
+	ordered := make([]NamedFeatureParser, 0, len(c.parsers))
+	var visit func(parser NamedFeatureParser, path []string) error
+	visit = func(parser NamedFeatureParser, path []string) error {
+		switch state[parser.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("feature parsers have cyclic dependencies: %s", strings.Join(append(path, parser.Name), " -> "))
+		}
+		state[parser.Name] = visiting
+		for _, dependency := range parser.After {
+			if err := visit(byName[dependency], append(path, parser.Name)); err != nil {
+				return err
+			}
+		}
+		state[parser.Name] = visited
+		ordered = append(ordered, parser)
+		return nil
+	}
+	for _, parser := range c.parsers {
+		if err := visit(parser, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// Run applies the FeatureParsers of the chain to the IR, in order, and returns
+// the errors of all of them.
+func (c *FeatureChain) Run(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	parsers, err := c.Order()
+	if err != nil {
+		return field.ErrorList{field.InternalError(nil, err)}
+	}
+
+	var errs field.ErrorList
+	for _, parser := range parsers {
+		parse := parser.Parse
+		for i := len(c.middlewares) - 1; i >= 0; i-- {
+			parse = c.middlewares[i](parser.Name, parse)
+		}
+		errs = append(errs, parse(ingresses, ir)...)
+	}
+	return errs
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func Test_FeatureChain_Order(t *testing.T) {
+	noop := func([]networkingv1.Ingress, *intermediate.IR) field.ErrorList { return nil }
+
+	testCases := []struct {
+		name          string
+		parsers       []NamedFeatureParser
+		expectedOrder []string
+		expectedError string
+	}{
+		{
+			name: "declaration order without dependencies",
+			parsers: []NamedFeatureParser{
+				{Name: "a", Parse: noop},
+				{Name: "b", Parse: noop},
+				{Name: "c", Parse: noop},
+			},
+			expectedOrder: []string{"a", "b", "c"},
+		},
+		{
+			name: "dependencies run first",
+			parsers: []NamedFeatureParser{
+				{Name: "a", Parse: noop, After: []string{"c"}},
+				{Name: "b", Parse: noop},
+				{Name: "c", Parse: noop, After: []string{"b"}},
+			},
+			expectedOrder: []string{"b", "c", "a"},
+		},
+		{
+			name: "duplicated feature parser",
+			parsers: []NamedFeatureParser{
+				{Name: "a", Parse: noop},
+				{Name: "a", Parse: noop},
+			},
+			expectedError: `feature parser "a" is declared more than once`,
+		},
+		{
+			name: "unknown dependency",
+			parsers: []NamedFeatureParser{
+				{Name: "a", Parse: noop, After: []string{"b"}},
+			},
+			expectedError: `feature parser "a" runs after unknown feature parser "b"`,
+		},
+		{
+			name: "cyclic dependencies",
+			parsers: []NamedFeatureParser{
+				{Name: "a", Parse: noop, After: []string{"b"}},
+				{Name: "b", Parse: noop, After: []string{"a"}},
+			},
+			expectedError: "feature parsers have cyclic dependencies: a -> b -> a",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsers, err := NewFeatureChain(tc.parsers...).Order()
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			var order []string
+			for _, parser := range parsers {
+				order = append(order, parser.Name)
+			}
+			require.Equal(t, tc.expectedOrder, order)
+		})
+	}
+}
+
+func Test_FeatureChain_Run(t *testing.T) {
+	var calls []string
+	parser := func(name string, errs ...*field.Error) FeatureParser {
+		return func([]networkingv1.Ingress, *intermediate.IR) field.ErrorList {
+			calls = append(calls, name)
+			return errs
+		}
+	}
+	middleware := func(prefix string) FeatureParserMiddleware {
+		return func(name string, next FeatureParser) FeatureParser {
+			return func(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+				calls = append(calls, prefix+name)
+				return next(ingresses, ir)
+			}
+		}
+	}
+
+	chain := NewFeatureChain(
+		NamedFeatureParser{Name: "a", Parse: parser("a", field.Invalid(field.NewPath("a"), "", "invalid")), After: []string{"b"}},
+		NamedFeatureParser{Name: "b", Parse: parser("b")},
+	).Use(middleware("outer:"), middleware("inner:"))
+
+	errs := chain.Run(nil, &intermediate.IR{})
+	require.Len(t, errs, 1)
+	require.Equal(t, []string{"outer:b", "inner:b", "b", "outer:a", "inner:a", "a"}, calls)
+
+	errs = NewFeatureChain(NamedFeatureParser{Name: "a", Parse: parser("a"), After: []string{"a"}}).Run(nil, &intermediate.IR{})
+	require.Len(t, errs, 1)
+	require.Equal(t, field.ErrorTypeInternal, errs[0].Type)
+}
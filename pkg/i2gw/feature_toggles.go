@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// FeatureToggles selects the FeatureParsers of the providers run by a
+// conversion. Features are named `<feature>` for the features of that name of
+// all the providers, or `<provider>/<feature>` for the feature of a provider.
+// A nil FeatureToggles runs all the features.
+type FeatureToggles struct {
+	// enabled lists the features to run. The providers it names features of,
+	// all of them for unqualified features, only run these.
+	enabled []string
+	// disabled lists the features not to run, taking precedence over enabled.
+	disabled []string
+
+	mu sync.Mutex
+	// features are the qualified names of the features the providers run the
+	// FeatureChains of have.
+	features map[string]bool
+}
+
+// newFeatureToggles returns the FeatureToggles of the given features, or an
+// error if they name unknown providers.
+func newFeatureToggles(enabled, disabled []string) (*FeatureToggles, error) {
+	for _, feature := range slices.Concat(enabled, disabled) {
+		provider, name, qualified := strings.Cut(feature, "/")
+		if !qualified {
+			name = provider
+		}
+		if name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("%q is not a valid feature, features are named <feature> or <provider>/<feature>", feature)
+		}
+		if _, ok := ProviderConstructorByName[ProviderName(provider)]; qualified && !ok {
+			return nil, fmt.Errorf("feature %q references %s, which is not a supported provider", feature, provider)
+		}
+	}
+	return &FeatureToggles{enabled: enabled, disabled: disabled, features: map[string]bool{}}, nil
+}
+
+// Middleware returns the FeatureParserMiddleware of the FeatureChain of the
+// provider, replacing the FeatureParsers of the features it doesn't run with
+// no-ops.
+func (t *FeatureToggles) Middleware(providerName ProviderName) FeatureParserMiddleware {
+	return func(name string, next FeatureParser) FeatureParser {
+		if t == nil {
+			return next
+		}
+		t.mu.Lock()
+		t.features[string(providerName)+"/"+name] = true
+		t.mu.Unlock()
+		if t.runs(providerName, name) {
+			return next
+		}
+		return func([]networkingv1.Ingress, *intermediate.IR) field.ErrorList {
+			notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.InfoNotification,
+				fmt.Sprintf("the %s feature of the %s provider is disabled, the annotations it converts were ignored", name, providerName)), string(providerName))
+			return nil
+		}
+	}
+}
+
+// runs returns whether the feature of the provider runs.
+func (t *FeatureToggles) runs(providerName ProviderName, name string) bool {
+	matches := func(feature string) bool {
+		return feature == name || feature == string(providerName)+"/"+name
+	}
+	if slices.ContainsFunc(t.disabled, matches) {
+		return false
+	}
+	restricted := slices.ContainsFunc(t.enabled, func(feature string) bool {
+		provider, _, qualified := strings.Cut(feature, "/")
+		return !qualified || provider == string(providerName)
+	})
+	return !restricted || slices.ContainsFunc(t.enabled, matches)
+}
+
+// unknownFeatures returns an error per feature matching none of the features
+// of the FeatureChains run by the providers.
+func (t *FeatureToggles) unknownFeatures() field.ErrorList {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	features := make([]string, 0, len(t.features))
+	for feature := range t.features {
+		features = append(features, feature)
+	}
+	slices.Sort(features)
+	known := func(feature string) bool {
+		return slices.ContainsFunc(features, func(qualified string) bool {
+			_, name, _ := strings.Cut(qualified, "/")
+			return feature == qualified || feature == name
+		})
+	}
+
+	var errs field.ErrorList
+	for i, feature := range t.enabled {
+		if !known(feature) {
+			errs = append(errs, field.NotSupported(field.NewPath("enable-features").Index(i), feature, features))
+		}
+	}
+	for i, feature := range t.disabled {
+		if !known(feature) {
+			errs = append(errs, field.NotSupported(field.NewPath("disable-features").Index(i), feature, features))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func Test_newFeatureToggles(t *testing.T) {
+	ProviderConstructorByName["test-provider"] = nil
+	defer delete(ProviderConstructorByName, "test-provider")
+
+	_, err := newFeatureToggles([]string{"canary", "test-provider/cors"}, nil)
+	require.NoError(t, err)
+	_, err = newFeatureToggles(nil, []string{"unknown-provider/cors"})
+	require.Error(t, err)
+	_, err = newFeatureToggles(nil, []string{"test-provider/"})
+	require.Error(t, err)
+}
+
+func Test_FeatureToggles_runs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		enabled  []string
+		disabled []string
+		expected map[string]bool
+	}{
+		{
+			name:     "all features run by default",
+			expected: map[string]bool{"canary": true, "cors": true},
+		},
+		{
+			name:     "disabled feature",
+			disabled: []string{"cors"},
+			expected: map[string]bool{"canary": true, "cors": false},
+		},
+		{
+			name:     "disabled feature of another provider",
+			disabled: []string{"other/cors"},
+			expected: map[string]bool{"canary": true, "cors": true},
+		},
+		{
+			name:     "enabled features only",
+			enabled:  []string{"test/canary"},
+			expected: map[string]bool{"canary": true, "cors": false},
+		},
+		{
+			name:     "enabled features of another provider",
+			enabled:  []string{"other/canary"},
+			expected: map[string]bool{"canary": true, "cors": true},
+		},
+		{
+			name:     "disabled over enabled",
+			enabled:  []string{"canary", "cors"},
+			disabled: []string{"test/cors"},
+			expected: map[string]bool{"canary": true, "cors": false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			toggles := &FeatureToggles{enabled: tc.enabled, disabled: tc.disabled, features: map[string]bool{}}
+			for feature, expected := range tc.expected {
+				require.Equal(t, expected, toggles.runs("test", feature), feature)
+			}
+		})
+	}
+}
+
+func Test_FeatureToggles_Middleware(t *testing.T) {
+	var run []string
+	parser := func(name string) NamedFeatureParser {
+		return NamedFeatureParser{Name: name, Parse: func([]networkingv1.Ingress, *intermediate.IR) field.ErrorList {
+			run = append(run, name)
+			return nil
+		}}
+	}
+
+	toggles := &FeatureToggles{disabled: []string{"cors", "rewrite"}, features: map[string]bool{}}
+	errs := NewFeatureChain(parser("canary"), parser("cors")).Use(toggles.Middleware("test")).Run(nil, &intermediate.IR{})
+	require.Empty(t, errs)
+	require.Equal(t, []string{"canary"}, run)
+
+	errs = toggles.unknownFeatures()
+	require.Len(t, errs, 1)
+	require.Equal(t, "disable-features[1]", errs[0].Field)
+
+	run = nil
+	var nilToggles *FeatureToggles
+	require.Empty(t, NewFeatureChain(parser("canary"), parser("cors")).Use(nilToggles.Middleware("test")).Run(nil, &intermediate.IR{}))
+	require.Equal(t, []string{"canary", "cors"}, run)
+}
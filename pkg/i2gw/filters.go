@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// consolidateFilters makes the filters of the rules, and of their backend
+// references, of the generated HTTPRoutes and GRPCRoutes valid: merging
+// routes, or applying several annotations of a provider, can accumulate
+// filters of the types which can only be specified once per rule.
+//   - The duplicated filters are removed.
+//   - The header modifier filters of the same type are merged into one,
+//     applying them in order, unless a header is added after being set or
+//     added already, which a single filter can't express.
+//   - Only the first RequestRedirect or URLRewrite filter is kept, as the
+//     other ones either conflict with it or are redundant.
+//
+// Splitting the rule instead wouldn't preserve the conflicting filters, the
+// requests only matching the first of rules with the same matches, so they
+// are dropped with a warning.
+func consolidateFilters(providerName ProviderName, gatewayResources *GatewayResources) {
+	for _, key := range sortedNamespacedNames(gatewayResources.HTTPRoutes) {
+		route := gatewayResources.HTTPRoutes[key]
+		var messages []string
+		for i := range route.Spec.Rules {
+			rule := &route.Spec.Rules[i]
+			var dropped []string
+			rule.Filters, dropped = consolidateHTTPRouteFilters(rule.Filters)
+			for _, reason := range dropped {
+				messages = append(messages, fmt.Sprintf("HTTPRoute %s: a filter of rule %d was dropped, %s", key, i, reason))
+			}
+			for j := range rule.BackendRefs {
+				rule.BackendRefs[j].Filters, dropped = consolidateHTTPRouteFilters(rule.BackendRefs[j].Filters)
+				for _, reason := range dropped {
+					messages = append(messages, fmt.Sprintf("HTTPRoute %s: a filter of backendRef %d of rule %d was dropped, %s", key, j, i, reason))
+				}
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = route
+		for _, message := range messages {
+			notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification, message, &route), string(providerName))
+		}
+	}
+
+	for _, key := range sortedNamespacedNames(gatewayResources.GRPCRoutes) {
+		route := gatewayResources.GRPCRoutes[key]
+		var messages []string
+		for i := range route.Spec.Rules {
+			rule := &route.Spec.Rules[i]
+			var dropped []string
+			rule.Filters, dropped = consolidateGRPCRouteFilters(rule.Filters)
+			for _, reason := range dropped {
+				messages = append(messages, fmt.Sprintf("GRPCRoute %s: a filter of rule %d was dropped, %s", key, i, reason))
+			}
+		}
+		gatewayResources.GRPCRoutes[key] = route
+		for _, message := range messages {
+			notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification, message, &route), string(providerName))
+		}
+	}
+}
+
+// consolidateHTTPRouteFilters returns the consolidated filters, and why the
+// dropped ones were dropped.
+func consolidateHTTPRouteFilters(filters []gatewayv1.HTTPRouteFilter) ([]gatewayv1.HTTPRouteFilter, []string) {
+	var (
+		consolidated []gatewayv1.HTTPRouteFilter
+		dropped      []string
+	)
+	for _, filter := range filters {
+		if slices.ContainsFunc(consolidated, func(existing gatewayv1.HTTPRouteFilter) bool {
+			return apiequality.Semantic.DeepEqual(existing, filter)
+		}) {
+			continue
+		}
+
+		switch filter.Type {
+		case gatewayv1.HTTPRouteFilterRequestHeaderModifier, gatewayv1.HTTPRouteFilterResponseHeaderModifier:
+			i := slices.IndexFunc(consolidated, func(existing gatewayv1.HTTPRouteFilter) bool { return existing.Type == filter.Type })
+			if i < 0 {
+				consolidated = append(consolidated, filter)
+				continue
+			}
+			existing, next := httpHeaderFilter(&consolidated[i]), httpHeaderFilter(&filter)
+			if existing == nil || next == nil {
+				consolidated = append(consolidated, filter)
+				continue
+			}
+			merged, conflict := mergeHeaderFilters(*existing, *next)
+			if conflict != "" {
+				dropped = append(dropped, fmt.Sprintf("the %s filter conflicts with a previous one: %s", filter.Type, conflict))
+				continue
+			}
+			*existing = merged
+		case gatewayv1.HTTPRouteFilterRequestRedirect, gatewayv1.HTTPRouteFilterURLRewrite:
+			i := slices.IndexFunc(consolidated, func(existing gatewayv1.HTTPRouteFilter) bool {
+				return existing.Type == gatewayv1.HTTPRouteFilterRequestRedirect || existing.Type == gatewayv1.HTTPRouteFilterURLRewrite
+			})
+			if i >= 0 {
+				dropped = append(dropped, fmt.Sprintf("the %s filter conflicts with the previous %s filter", filter.Type, consolidated[i].Type))
+				continue
+			}
+			consolidated = append(consolidated, filter)
+		default:
+			consolidated = append(consolidated, filter)
+		}
+	}
+	return consolidated, dropped
+}
+
+// consolidateGRPCRouteFilters returns the consolidated filters, and why the
+// dropped ones were dropped.
+func consolidateGRPCRouteFilters(filters []gatewayv1.GRPCRouteFilter) ([]gatewayv1.GRPCRouteFilter, []string) {
+	var (
+		consolidated []gatewayv1.GRPCRouteFilter
+		dropped      []string
+	)
+	for _, filter := range filters {
+		if slices.ContainsFunc(consolidated, func(existing gatewayv1.GRPCRouteFilter) bool {
+			return apiequality.Semantic.DeepEqual(existing, filter)
+		}) {
+			continue
+		}
+
+		i := slices.IndexFunc(consolidated, func(existing gatewayv1.GRPCRouteFilter) bool { return existing.Type == filter.Type })
+		if i < 0 {
+			consolidated = append(consolidated, filter)
+			continue
+		}
+		var existing, next *gatewayv1.HTTPHeaderFilter
+		switch filter.Type {
+		case gatewayv1.GRPCRouteFilterRequestHeaderModifier:
+			existing, next = consolidated[i].RequestHeaderModifier, filter.RequestHeaderModifier
+		case gatewayv1.GRPCRouteFilterResponseHeaderModifier:
+			existing, next = consolidated[i].ResponseHeaderModifier, filter.ResponseHeaderModifier
+		}
+		if existing == nil || next == nil {
+			consolidated = append(consolidated, filter)
+			continue
+		}
+		merged, conflict := mergeHeaderFilters(*existing, *next)
+		if conflict != "" {
+			dropped = append(dropped, fmt.Sprintf("the %s filter conflicts with a previous one: %s", filter.Type, conflict))
+			continue
+		}
+		*existing = merged
+	}
+	return consolidated, dropped
+}
+
+func httpHeaderFilter(filter *gatewayv1.HTTPRouteFilter) *gatewayv1.HTTPHeaderFilter {
+	if filter.Type == gatewayv1.HTTPRouteFilterRequestHeaderModifier {
+		return filter.RequestHeaderModifier
+	}
+	return filter.ResponseHeaderModifier
+}
+
+// mergeHeaderFilters returns the header filter equivalent to applying the
+// given ones in order, or why there is none. The header names are case
+// insensitive.
+func mergeHeaderFilters(first, second gatewayv1.HTTPHeaderFilter) (gatewayv1.HTTPHeaderFilter, string) {
+	merged := *first.DeepCopy()
+	for _, header := range second.Set {
+		merged.Add = withoutHeader(merged.Add, header.Name)
+		merged.Remove = withoutName(merged.Remove, string(header.Name))
+		merged.Set = append(withoutHeader(merged.Set, header.Name), header)
+	}
+	for _, header := range second.Add {
+		switch {
+		case hasHeader(merged.Set, header.Name) || hasHeader(merged.Add, header.Name):
+			return first, fmt.Sprintf("header %s is added after being set or added", header.Name)
+		case slices.ContainsFunc(merged.Remove, func(name string) bool { return strings.EqualFold(name, string(header.Name)) }):
+			// Adding a removed header sets it.
+			merged.Remove = withoutName(merged.Remove, string(header.Name))
+			merged.Set = append(merged.Set, header)
+		default:
+			merged.Add = append(merged.Add, header)
+		}
+	}
+	for _, name := range second.Remove {
+		merged.Set = withoutHeader(merged.Set, gatewayv1.HTTPHeaderName(name))
+		merged.Add = withoutHeader(merged.Add, gatewayv1.HTTPHeaderName(name))
+		merged.Remove = append(withoutName(merged.Remove, name), name)
+	}
+	return merged, ""
+}
+
+func hasHeader(headers []gatewayv1.HTTPHeader, name gatewayv1.HTTPHeaderName) bool {
+	return slices.ContainsFunc(headers, func(header gatewayv1.HTTPHeader) bool {
+		return strings.EqualFold(string(header.Name), string(name))
+	})
+}
+
+func withoutHeader(headers []gatewayv1.HTTPHeader, name gatewayv1.HTTPHeaderName) []gatewayv1.HTTPHeader {
+	return slices.DeleteFunc(headers, func(header gatewayv1.HTTPHeader) bool {
+		return strings.EqualFold(string(header.Name), string(name))
+	})
+}
+
+func withoutName(names []string, name string) []string {
+	return slices.DeleteFunc(names, func(existing string) bool {
+		return strings.EqualFold(existing, name)
+	})
+}
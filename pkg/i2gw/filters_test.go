@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_consolidateHTTPRouteFilters(t *testing.T) {
+	requestHeaders := func(filter gatewayv1.HTTPHeaderFilter) gatewayv1.HTTPRouteFilter {
+		return gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier, RequestHeaderModifier: &filter}
+	}
+	redirect := gatewayv1.HTTPRouteFilter{
+		Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+		RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{Scheme: ptr.To("https"), StatusCode: ptr.To(301)},
+	}
+	rewrite := gatewayv1.HTTPRouteFilter{
+		Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+		URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Hostname: ptr.To[gatewayv1.PreciseHostname]("internal.example.com")},
+	}
+	mirror := gatewayv1.HTTPRouteFilter{
+		Type:          gatewayv1.HTTPRouteFilterRequestMirror,
+		RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: gatewayv1.BackendObjectReference{Name: "mirror"}},
+	}
+
+	testCases := []struct {
+		name            string
+		filters         []gatewayv1.HTTPRouteFilter
+		expectedFilters []gatewayv1.HTTPRouteFilter
+		expectedDropped int
+	}{
+		{
+			name:            "duplicated filters",
+			filters:         []gatewayv1.HTTPRouteFilter{redirect, mirror, redirect, mirror},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{redirect, mirror},
+		},
+		{
+			name: "header modifiers merged in order",
+			filters: []gatewayv1.HTTPRouteFilter{
+				requestHeaders(gatewayv1.HTTPHeaderFilter{
+					Set:    []gatewayv1.HTTPHeader{{Name: "X-Env", Value: "dev"}},
+					Add:    []gatewayv1.HTTPHeader{{Name: "X-Trace", Value: "1"}},
+					Remove: []string{"X-Debug"},
+				}),
+				requestHeaders(gatewayv1.HTTPHeaderFilter{
+					Set:    []gatewayv1.HTTPHeader{{Name: "x-env", Value: "prod"}},
+					Add:    []gatewayv1.HTTPHeader{{Name: "X-Debug", Value: "off"}},
+					Remove: []string{"X-Trace"},
+				}),
+			},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{
+				requestHeaders(gatewayv1.HTTPHeaderFilter{
+					Set:    []gatewayv1.HTTPHeader{{Name: "x-env", Value: "prod"}, {Name: "X-Debug", Value: "off"}},
+					Add:    []gatewayv1.HTTPHeader{},
+					Remove: []string{"X-Trace"},
+				}),
+			},
+		},
+		{
+			name: "header added twice",
+			filters: []gatewayv1.HTTPRouteFilter{
+				requestHeaders(gatewayv1.HTTPHeaderFilter{Add: []gatewayv1.HTTPHeader{{Name: "X-Trace", Value: "1"}}}),
+				requestHeaders(gatewayv1.HTTPHeaderFilter{Add: []gatewayv1.HTTPHeader{{Name: "X-Trace", Value: "2"}}}),
+			},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{
+				requestHeaders(gatewayv1.HTTPHeaderFilter{Add: []gatewayv1.HTTPHeader{{Name: "X-Trace", Value: "1"}}}),
+			},
+			expectedDropped: 1,
+		},
+		{
+			name:            "redirect and rewrite",
+			filters:         []gatewayv1.HTTPRouteFilter{redirect, rewrite},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{redirect},
+			expectedDropped: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filters, dropped := consolidateHTTPRouteFilters(tc.filters)
+			require.Equal(t, tc.expectedFilters, filters)
+			require.Len(t, dropped, tc.expectedDropped)
+		})
+	}
+}
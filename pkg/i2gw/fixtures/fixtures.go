@@ -0,0 +1,353 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fixtures runs declarative conversion test cases, written as YAML
+// fixtures holding the input resources, the expected output resources and the
+// expected notifications, so that conversion cases can be contributed without
+// writing Go tests.
+package fixtures
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+// Fixture is a declarative conversion test case.
+type Fixture struct {
+	// Description describes the conversion case, e.g. the issue it reproduces.
+	Description string `json:"description,omitempty"`
+
+	// Providers are the providers converting the input. Defaults to the
+	// provider of the directory the fixture is discovered in.
+	Providers []string `json:"providers,omitempty"`
+
+	// Options are the conversion options.
+	Options Options `json:"options,omitempty"`
+
+	// Input are the source resources.
+	Input []map[string]interface{} `json:"input"`
+
+	// Output are the expected resources, which must all be generated, and be
+	// the only generated resources. Their status is ignored.
+	Output []map[string]interface{} `json:"output"`
+
+	// Notifications are notifications expected among the generated ones.
+	Notifications []Notification `json:"notifications,omitempty"`
+}
+
+// Options are the conversion options of a Fixture, mirroring the flags of the
+// print command.
+type Options struct {
+	Profile                           string                       `json:"profile,omitempty"`
+	GatewayStrategy                   string                       `json:"gatewayStrategy,omitempty"`
+	ListenerStrategy                  string                       `json:"listenerStrategy,omitempty"`
+	HostnamePolicy                    string                       `json:"hostnamePolicy,omitempty"`
+	CertManagerCertificates           bool                         `json:"certManagerCertificates,omitempty"`
+	EnabledFeatures                   []string                     `json:"enabledFeatures,omitempty"`
+	DisabledFeatures                  []string                     `json:"disabledFeatures,omitempty"`
+	Emitter                           string                       `json:"emitter,omitempty"`
+	NoRouteMerge                      bool                         `json:"noRouteMerge,omitempty"`
+	Mesh                              bool                         `json:"mesh,omitempty"`
+	ClusterDomain                     string                       `json:"clusterDomain,omitempty"`
+	CentralGatewayNamespace           string                       `json:"centralGatewayNamespace,omitempty"`
+	BackendTLSWellKnownCACertificates string                       `json:"backendTLSWellKnownCACertificates,omitempty"`
+	GatewayClassMapping               map[string]string            `json:"gatewayClassMapping,omitempty"`
+	GatewayAPIVersion                 string                       `json:"gatewayAPIVersion,omitempty"`
+	Redact                            bool                         `json:"redact,omitempty"`
+	ProviderSpecificFlags             map[string]map[string]string `json:"providerSpecificFlags,omitempty"`
+}
+
+// Notification is an expected notification.
+type Notification struct {
+	// Type is the type of the notification, i.e. INFO, WARNING or ERROR.
+	Type notifications.MessageType `json:"type"`
+	// Message is a substring of the message of the notification.
+	Message string `json:"message"`
+}
+
+// Result is the outcome of the conversion of a Fixture.
+type Result struct {
+	// Output are the generated resources, normalized like the Output of the
+	// Fixture.
+	Output []map[string]interface{}
+	// Notifications are the generated notifications.
+	Notifications []notifications.Notification
+}
+
+// Discover returns the paths of the fixtures of the providers, i.e. the YAML
+// files of the fixtures directory of each provider under the given providers
+// directory.
+func Discover(providersDir string) ([]string, error) {
+	var paths []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(providersDir, "*", "fixtures", pattern))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	slices.Sort(paths)
+	return paths, nil
+}
+
+// Load reads the fixture at the given path. Unless set, its providers default
+// to the provider of the directory the fixture is in.
+func Load(path string) (Fixture, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Fixture{}, fmt.Errorf("failed to read fixture %s: %w", path, err)
+	}
+	var fixture Fixture
+	if err = yaml.UnmarshalStrict(content, &fixture); err != nil {
+		return Fixture{}, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	if len(fixture.Providers) == 0 {
+		fixture.Providers = []string{providerOfPath(path)}
+	}
+	for i := range fixture.Output {
+		normalize(fixture.Output[i])
+	}
+	return fixture, nil
+}
+
+// providerOfPath returns the name of the provider of the fixture at the given
+// path, <providers>/<provider>/fixtures/<fixture>.yaml. The provider
+// directories are named after the providers, without dashes.
+func providerOfPath(path string) string {
+	dir := filepath.Base(filepath.Dir(filepath.Dir(path)))
+	for _, provider := range i2gw.GetSupportedProviders() {
+		if strings.ReplaceAll(provider, "-", "") == dir {
+			return provider
+		}
+	}
+	return dir
+}
+
+// Run converts the input of the fixture, and returns the generated resources
+// and notifications.
+func Run(ctx context.Context, fixture Fixture) (Result, error) {
+	inputFile, err := os.CreateTemp("", "ingress2gateway-fixture-*.yaml")
+	if err != nil {
+		return Result{}, err
+	}
+	defer os.Remove(inputFile.Name())
+	for _, resource := range fixture.Input {
+		out, err := yaml.Marshal(resource)
+		if err != nil {
+			inputFile.Close()
+			return Result{}, fmt.Errorf("failed to marshal input: %w", err)
+		}
+		if _, err = fmt.Fprintf(inputFile, "---\n%s", out); err != nil {
+			inputFile.Close()
+			return Result{}, err
+		}
+	}
+	if err = inputFile.Close(); err != nil {
+		return Result{}, err
+	}
+
+	// The notifications are collected as dispatched to the sinks, i.e.
+	// redacted when the fixture is.
+	var (
+		result Result
+		mutex  sync.Mutex
+	)
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	unsubscribe := notifications.NotificationAggr.Subscribe(func(_ string, notification notifications.Notification) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		result.Notifications = append(result.Notifications, notification)
+	})
+	defer unsubscribe()
+	conversionResult, err := i2gw.ToGatewayAPIResources(ctx, i2gw.ConversionOptions{
+		InputFile:                         inputFile.Name(),
+		Providers:                         fixture.Providers,
+		ProviderSpecificFlags:             fixture.Options.ProviderSpecificFlags,
+		Mesh:                              fixture.Options.Mesh,
+		ClusterDomain:                     fixture.Options.ClusterDomain,
+		Profile:                           i2gw.ProfileName(fixture.Options.Profile),
+		GatewayStrategy:                   i2gw.GatewayStrategy(fixture.Options.GatewayStrategy),
+		ListenerStrategy:                  i2gw.ListenerStrategy(fixture.Options.ListenerStrategy),
+		HostnamePolicy:                    i2gw.HostnamePolicy(fixture.Options.HostnamePolicy),
+		CertManagerCertificates:           fixture.Options.CertManagerCertificates,
+		EnabledFeatures:                   fixture.Options.EnabledFeatures,
+		DisabledFeatures:                  fixture.Options.DisabledFeatures,
+		Emitter:                           i2gw.EmitterName(fixture.Options.Emitter),
+		NoRouteMerge:                      fixture.Options.NoRouteMerge,
+		CentralGatewayNamespace:           fixture.Options.CentralGatewayNamespace,
+		GatewayClassMapping:               fixture.Options.GatewayClassMapping,
+		BackendTLSWellKnownCACertificates: gatewayv1alpha3.WellKnownCACertificatesType(fixture.Options.BackendTLSWellKnownCACertificates),
+		GatewayAPIVersion:                 fixture.Options.GatewayAPIVersion,
+		Redact:                            fixture.Options.Redact,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	for _, resources := range conversionResult.GatewayResources {
+		output, err := toOutput(resources)
+		if err != nil {
+			return Result{}, err
+		}
+		result.Output = append(result.Output, output...)
+	}
+	sortOutput(result.Output)
+	return result, nil
+}
+
+// MissingNotifications returns the expected notifications of the fixture
+// which are not among the notifications of the result.
+func (f Fixture) MissingNotifications(result Result) []Notification {
+	var missing []Notification
+	for _, expected := range f.Notifications {
+		if !slices.ContainsFunc(result.Notifications, func(n notifications.Notification) bool {
+			return n.Type == expected.Type && strings.Contains(n.Message, expected.Message)
+		}) {
+			missing = append(missing, expected)
+		}
+	}
+	return missing
+}
+
+// ExpectedOutput returns the expected output of the fixture, sorted like the
+// Output of a Result.
+func (f Fixture) ExpectedOutput() []map[string]interface{} {
+	output := slices.Clone(f.Output)
+	sortOutput(output)
+	return output
+}
+
+// MarshalOutput marshals the output as YAML, as expected in a fixture, e.g. to
+// write the output of a new fixture.
+func MarshalOutput(output []map[string]interface{}) (string, error) {
+	out, err := yaml.Marshal(map[string]interface{}{"output": output})
+	return string(out), err
+}
+
+// toOutput returns the resources as normalized unstructured objects.
+func toOutput(resources i2gw.GatewayResources) ([]map[string]interface{}, error) {
+	var output []map[string]interface{}
+	add := func(gvk schema.GroupVersionKind, obj interface{}) error {
+		// Marshal the objects as printed, for the fields and numbers to have
+		// the same types as the ones of the parsed fixtures.
+		out, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		var normalized map[string]interface{}
+		if err = json.Unmarshal(out, &normalized); err != nil {
+			return err
+		}
+		normalized["apiVersion"], normalized["kind"] = gvk.GroupVersion().String(), gvk.Kind
+		normalize(normalized)
+		output = append(output, normalized)
+		return nil
+	}
+
+	var errs []error
+	for _, obj := range sortedValues(resources.GatewayClasses) {
+		errs = append(errs, add(gatewayv1.SchemeGroupVersion.WithKind("GatewayClass"), &obj))
+	}
+	for _, obj := range sortedValues(resources.Gateways) {
+		errs = append(errs, add(gatewayv1.SchemeGroupVersion.WithKind("Gateway"), &obj))
+	}
+	for _, obj := range sortedValues(resources.HTTPRoutes) {
+		errs = append(errs, add(gatewayv1.SchemeGroupVersion.WithKind("HTTPRoute"), i2gw.HTTPRouteObject(obj, resources.MirrorFractions[types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}])))
+	}
+	for _, obj := range sortedValues(resources.GRPCRoutes) {
+		errs = append(errs, add(gatewayv1.SchemeGroupVersion.WithKind("GRPCRoute"), &obj))
+	}
+	for _, obj := range sortedValues(resources.TLSRoutes) {
+		errs = append(errs, add(gatewayv1alpha2.SchemeGroupVersion.WithKind("TLSRoute"), &obj))
+	}
+	for _, obj := range sortedValues(resources.TCPRoutes) {
+		errs = append(errs, add(gatewayv1alpha2.SchemeGroupVersion.WithKind("TCPRoute"), &obj))
+	}
+	for _, obj := range sortedValues(resources.UDPRoutes) {
+		errs = append(errs, add(gatewayv1alpha2.SchemeGroupVersion.WithKind("UDPRoute"), &obj))
+	}
+	for _, obj := range sortedValues(resources.ReferenceGrants) {
+		errs = append(errs, add(gatewayv1beta1.SchemeGroupVersion.WithKind("ReferenceGrant"), &obj))
+	}
+	for _, obj := range sortedValues(resources.BackendTLSPolicies) {
+		errs = append(errs, add(gatewayv1alpha3.SchemeGroupVersion.WithKind("BackendTLSPolicy"), &obj))
+	}
+	for _, obj := range sortedValues(resources.BackendLBPolicies) {
+		errs = append(errs, add(gatewayv1alpha2.SchemeGroupVersion.WithKind("BackendLBPolicy"), &obj))
+	}
+	for _, obj := range resources.GatewayExtensions {
+		errs = append(errs, add(obj.GroupVersionKind(), &obj))
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert the output: %w", err)
+		}
+	}
+	return output, nil
+}
+
+func sortedValues[T any](m map[types.NamespacedName]T) []T {
+	keys := make([]types.NamespacedName, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	values := make([]T, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, m[key])
+	}
+	return values
+}
+
+// normalize removes the fields of the object which are not compared: its
+// status, and its creation timestamp when unset.
+func normalize(obj map[string]interface{}) {
+	delete(obj, "status")
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		if metadata["creationTimestamp"] == nil {
+			delete(metadata, "creationTimestamp")
+		}
+	}
+}
+
+// sortOutput sorts the objects by API version, kind, namespace and name.
+func sortOutput(output []map[string]interface{}) {
+	key := func(obj map[string]interface{}) string {
+		metadata, _ := obj["metadata"].(map[string]interface{})
+		return fmt.Sprintf("%v/%v/%v/%v", obj["apiVersion"], obj["kind"], metadata["namespace"], metadata["name"])
+	}
+	slices.SortStableFunc(output, func(a, b map[string]interface{}) int {
+		return strings.Compare(key(a), key(b))
+	})
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fixtures_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/fixtures"
+
+	// Call init function for the emitters and providers
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/envoygateway"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/istio"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/kgateway"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/nginxgatewayfabric"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/traefik"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/ako"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/apisix"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/cilium"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/f5"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/gce"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/ingressnginx"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/istio"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/kong"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/netscaler"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/nginx"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/openapi3"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/openshift"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/skipper"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/traefik"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/voyager"
+)
+
+// Test_Fixtures runs the fixtures of all the providers.
+func Test_Fixtures(t *testing.T) {
+	paths, err := fixtures.Discover(filepath.Join("..", "providers"))
+	if err != nil {
+		t.Fatalf("Failed to discover fixtures: %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("No fixtures discovered")
+	}
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.ToSlash(strings.TrimPrefix(path, filepath.Join("..", "providers")+string(filepath.Separator))), filepath.Ext(path))
+		t.Run(name, func(t *testing.T) {
+			fixture, err := fixtures.Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := fixtures.Run(context.Background(), fixture)
+			if err != nil {
+				t.Fatalf("Failed to convert the input: %v", err)
+			}
+
+			if diff := cmp.Diff(fixture.ExpectedOutput(), result.Output); diff != "" {
+				actual, _ := fixtures.MarshalOutput(result.Output)
+				t.Errorf("Unexpected output (-want +got): %s\nGenerated output:\n%s", diff, actual)
+			}
+			for _, missing := range fixture.MissingNotifications(result) {
+				t.Errorf("Expected %s notification containing %q, got: %v", missing.Type, missing.Message, result.Notifications)
+			}
+		})
+	}
+}
+
+func Test_Load(t *testing.T) {
+	fixture, err := fixtures.Load(filepath.Join("..", "providers", "ingressnginx", "fixtures", "canary.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"ingress-nginx"}, fixture.Providers); diff != "" {
+		t.Errorf("Unexpected default providers (-want +got): %s", diff)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// DefaultGatewayAPIVersion is the Gateway API version the generated resources
+// conform to unless another one is set, the version ingress2gateway is built
+// against.
+const DefaultGatewayAPIVersion = "v1.1"
+
+// gatewayAPIVersionRegexp is the pattern of the Gateway API versions, e.g.
+// v1.3 or v1.3.0.
+var gatewayAPIVersionRegexp = regexp.MustCompile(`^v?(\d+)\.(\d+)(\.\d+)?$`)
+
+// gatewayAPIVersion is a Gateway API release, by major and minor version.
+type gatewayAPIVersion struct {
+	major, minor int
+}
+
+var (
+	defaultGatewayAPIVersion = gatewayAPIVersion{major: 1, minor: 1}
+	// mirrorFractionGatewayAPIVersion is the version adding the percent and
+	// fraction fields of the RequestMirror filter.
+	mirrorFractionGatewayAPIVersion = gatewayAPIVersion{major: 1, minor: 3}
+)
+
+// parseGatewayAPIVersion returns the Gateway API version of the given name,
+// or an error if it isn't a version ingress2gateway can generate resources
+// for. An empty name means the DefaultGatewayAPIVersion.
+func parseGatewayAPIVersion(name string) (gatewayAPIVersion, error) {
+	if name == "" {
+		return defaultGatewayAPIVersion, nil
+	}
+	match := gatewayAPIVersionRegexp.FindStringSubmatch(name)
+	if match == nil {
+		return gatewayAPIVersion{}, fmt.Errorf("%s is not a valid Gateway API version, expected v<major>.<minor>, e.g. %s", name, DefaultGatewayAPIVersion)
+	}
+	major, majorErr := strconv.Atoi(match[1])
+	minor, minorErr := strconv.Atoi(match[2])
+	version := gatewayAPIVersion{major: major, minor: minor}
+	if majorErr != nil || minorErr != nil || major != 1 || !version.atLeast(defaultGatewayAPIVersion) {
+		return gatewayAPIVersion{}, fmt.Errorf("unsupported Gateway API version %s, the generated resources require %s or a later v1 version", name, DefaultGatewayAPIVersion)
+	}
+	return version, nil
+}
+
+// atLeast returns whether the version is the given one or a later one.
+func (v gatewayAPIVersion) atLeast(other gatewayAPIVersion) bool {
+	return v.major > other.major || v.major == other.major && v.minor >= other.minor
+}
+
+// String returns the version as v<major>.<minor>.
+func (v gatewayAPIVersion) String() string {
+	return fmt.Sprintf("v%d.%d", v.major, v.minor)
+}
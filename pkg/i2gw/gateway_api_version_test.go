@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseGatewayAPIVersion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		version         string
+		expectedVersion gatewayAPIVersion
+		expectedError   string
+	}{
+		{name: "default", expectedVersion: gatewayAPIVersion{major: 1, minor: 1}},
+		{name: "minor version", version: "v1.3", expectedVersion: gatewayAPIVersion{major: 1, minor: 3}},
+		{name: "patch version without prefix", version: "1.3.0", expectedVersion: gatewayAPIVersion{major: 1, minor: 3}},
+		{name: "invalid version", version: "latest", expectedError: "not a valid Gateway API version"},
+		{name: "version older than the default", version: "v1.0", expectedError: "unsupported Gateway API version v1.0"},
+		{name: "other major version", version: "v2.0", expectedError: "unsupported Gateway API version v2.0"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			version, err := parseGatewayAPIVersion(tc.version)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedVersion, version)
+		})
+	}
+}
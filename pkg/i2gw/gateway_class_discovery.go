@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// managedGatewayClassAlternatives lists, for the GatewayClasses of the managed
+// cloud implementations, the classes provisioning the closest load balancers,
+// closest first, e.g. the regional classes of the global GKE classes.
+var managedGatewayClassAlternatives = map[string][]string{
+	// GKE
+	"gke-l7-global-external-managed":      {"gke-l7-gxlb", "gke-l7-regional-external-managed"},
+	"gke-l7-gxlb":                         {"gke-l7-global-external-managed", "gke-l7-regional-external-managed"},
+	"gke-l7-regional-external-managed":    {"gke-l7-global-external-managed", "gke-l7-gxlb"},
+	"gke-l7-rilb":                         {"gke-l7-cross-regional-internal-managed-mc", "gke-l7-rilb-mc"},
+	"gke-l7-global-external-managed-mc":   {"gke-l7-gxlb-mc", "gke-l7-regional-external-managed-mc"},
+	"gke-l7-gxlb-mc":                      {"gke-l7-global-external-managed-mc", "gke-l7-regional-external-managed-mc"},
+	"gke-l7-regional-external-managed-mc": {"gke-l7-global-external-managed-mc", "gke-l7-gxlb-mc"},
+	"gke-l7-rilb-mc":                      {"gke-l7-cross-regional-internal-managed-mc", "gke-l7-rilb"},
+	// AKS: Application Gateway for Containers, and the Istio-based application
+	// routing add-on.
+	"azure-alb-external": {"approuting-istio"},
+	"approuting-istio":   {"azure-alb-external"},
+	// EKS: VPC Lattice, and the Gateway API support of the AWS Load Balancer
+	// Controller.
+	"amazon-vpc-lattice": {"aws-alb"},
+	"aws-alb":            {"amazon-vpc-lattice"},
+}
+
+// readGatewayClasses reads the GatewayClasses of the cluster of the kubeconfig
+// context of the options, by name. It returns no GatewayClasses if the
+// Gateway API CRDs are not installed, and nil if they can't be listed, in
+// which case the GatewayClasses of the Gateways are not checked.
+func readGatewayClasses(ctx context.Context, opts ConversionOptions) (map[string]gatewayv1.GatewayClass, error) {
+	conf, err := clusterConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	scheme := runtime.NewScheme()
+	if err = gatewayv1.Install(scheme); err != nil {
+		return nil, err
+	}
+	cl, err := client.New(conf, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	var gatewayClassList gatewayv1.GatewayClassList
+	err = cl.List(ctx, &gatewayClassList)
+	switch {
+	case meta.IsNoMatchError(err) || apierrors.IsNotFound(err):
+		return map[string]gatewayv1.GatewayClass{}, nil
+	case apierrors.IsForbidden(err):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read the GatewayClasses of the cluster: %w", err)
+	}
+	gatewayClasses := make(map[string]gatewayv1.GatewayClass, len(gatewayClassList.Items))
+	for _, gatewayClass := range gatewayClassList.Items {
+		gatewayClasses[gatewayClass.Name] = gatewayClass
+	}
+	return gatewayClasses, nil
+}
+
+// checkGatewayClasses warns about the Gateways whose GatewayClass is neither
+// generated nor an Accepted GatewayClass of the cluster, suggesting the
+// closest Accepted GatewayClass.
+func checkGatewayClasses(providerName ProviderName, gatewayResources *GatewayResources, gatewayClasses map[string]gatewayv1.GatewayClass) {
+	var accepted []string
+	for name, gatewayClass := range gatewayClasses {
+		if meta.IsStatusConditionTrue(gatewayClass.Status.Conditions, string(gatewayv1.GatewayClassConditionStatusAccepted)) {
+			accepted = append(accepted, name)
+		}
+	}
+	slices.Sort(accepted)
+	generated := map[string]bool{}
+	for _, gatewayClass := range gatewayResources.GatewayClasses {
+		generated[gatewayClass.Name] = true
+	}
+
+	for _, key := range sortedNamespacedNames(gatewayResources.Gateways) {
+		gateway := gatewayResources.Gateways[key]
+		className := string(gateway.Spec.GatewayClassName)
+		if generated[className] || slices.Contains(accepted, className) {
+			continue
+		}
+
+		message := fmt.Sprintf("GatewayClass %s of Gateway %s doesn't exist in the cluster", className, key)
+		if _, ok := gatewayClasses[className]; ok {
+			message = fmt.Sprintf("GatewayClass %s of Gateway %s is not Accepted by its controller", className, key)
+		}
+		if suggestion := closestGatewayClass(className, accepted); suggestion != "" {
+			message += fmt.Sprintf(", the closest Accepted GatewayClass is %s, see --gateway-class-mapping", suggestion)
+		} else if len(accepted) == 0 {
+			message += ", the cluster has no Accepted GatewayClass"
+		}
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification, message, &gateway), string(providerName))
+	}
+}
+
+// closestGatewayClass returns the GatewayClass among the given ones closest to
+// the given one: its first managed alternative available, or else the one
+// with the smallest edit distance, provided it shares a prefix with it, e.g.
+// the same cloud.
+func closestGatewayClass(className string, gatewayClasses []string) string {
+	for _, alternative := range managedGatewayClassAlternatives[className] {
+		if slices.Contains(gatewayClasses, alternative) {
+			return alternative
+		}
+	}
+
+	prefix, _, _ := strings.Cut(className, "-")
+	closest, closestDistance := "", -1
+	for _, gatewayClass := range gatewayClasses {
+		if !strings.HasPrefix(gatewayClass, prefix) {
+			continue
+		}
+		if distance := editDistance(className, gatewayClass); closestDistance < 0 || distance < closestDistance {
+			closest, closestDistance = gatewayClass, distance
+		}
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance of the given strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_closestGatewayClass(t *testing.T) {
+	available := []string{"gke-l7-gxlb", "gke-l7-regional-external-managed", "gke-l7-rilb", "istio"}
+	require.Equal(t, "gke-l7-gxlb", closestGatewayClass("gke-l7-global-external-managed", available))
+	require.Equal(t, "gke-l7-regional-external-managed", closestGatewayClass("gke-l7-regional-external", available))
+	require.Equal(t, "", closestGatewayClass("nginx", available))
+	require.Equal(t, "", closestGatewayClass("gke-l7-gxlb", nil))
+}
+
+func Test_editDistance(t *testing.T) {
+	require.Equal(t, 0, editDistance("istio", "istio"))
+	require.Equal(t, 3, editDistance("kitten", "sitting"))
+	require.Equal(t, 4, editDistance("", "rilb"))
+}
+
+func Test_checkGatewayClasses(t *testing.T) {
+	gatewayClass := func(name string, accepted bool) gatewayv1.GatewayClass {
+		status := metav1.ConditionFalse
+		if accepted {
+			status = metav1.ConditionTrue
+		}
+		return gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: gatewayv1.GatewayClassStatus{Conditions: []metav1.Condition{
+				{Type: string(gatewayv1.GatewayClassConditionStatusAccepted), Status: status},
+			}},
+		}
+	}
+	gateway := func(name string, className gatewayv1.ObjectName) gatewayv1.Gateway {
+		return gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: className},
+		}
+	}
+
+	gatewayResources := &GatewayResources{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+			{Namespace: "default", Name: "gce"}:          gateway("gce", "gke-l7-global-external-managed"),
+			{Namespace: "default", Name: "gce-internal"}: gateway("gce-internal", "gke-l7-rilb"),
+			{Namespace: "default", Name: "pending"}:      gateway("pending", "gke-l7-regional-external-managed"),
+			{Namespace: "default", Name: "generated"}:    gateway("generated", "generated"),
+		},
+		GatewayClasses: map[types.NamespacedName]gatewayv1.GatewayClass{
+			{Name: "generated"}: gatewayClass("generated", false),
+		},
+	}
+	gatewayClasses := map[string]gatewayv1.GatewayClass{
+		"gke-l7-gxlb":                      gatewayClass("gke-l7-gxlb", true),
+		"gke-l7-rilb":                      gatewayClass("gke-l7-rilb", true),
+		"gke-l7-regional-external-managed": gatewayClass("gke-l7-regional-external-managed", false),
+	}
+
+	notifications.NotificationAggr = notifications.NotificationAggregator{Notifications: map[string][]notifications.Notification{}}
+	checkGatewayClasses("test", gatewayResources, gatewayClasses)
+
+	var messages []string
+	for _, notification := range notifications.NotificationAggr.Notifications["test"] {
+		messages = append(messages, notification.Message)
+	}
+	require.Equal(t, []string{
+		"GatewayClass gke-l7-global-external-managed of Gateway default/gce doesn't exist in the cluster, the closest Accepted GatewayClass is gke-l7-gxlb, see --gateway-class-mapping",
+		"GatewayClass gke-l7-regional-external-managed of Gateway default/pending is not Accepted by its controller, the closest Accepted GatewayClass is gke-l7-gxlb, see --gateway-class-mapping",
+	}, messages)
+}
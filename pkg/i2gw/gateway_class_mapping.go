@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// validateGatewayClassMapping returns an error if a class of the given
+// IngressClass to GatewayClass mapping is not a valid resource name.
+func validateGatewayClassMapping(mapping map[string]string) error {
+	for ingressClass, gatewayClass := range mapping {
+		for _, name := range []string{ingressClass, gatewayClass} {
+			if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+				return fmt.Errorf("%s=%s is not a valid gateway class mapping: %s", ingressClass, gatewayClass, strings.Join(errs, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// mapGatewayClasses assigns the Gateways generated for the IngressClasses of
+// the mapping, i.e. of their GatewayClassName, the GatewayClass the mapping
+// declares to serve them. The Gateways are sharded by GatewayClass: the ones
+// of a namespace mapped to the same GatewayClass are merged into a single
+// Gateway named after it, and the parentRefs of the routes follow. Listeners
+// only sharing the name of a listener of another IngressClass are suffixed
+// with the name of their original Gateway.
+func mapGatewayClasses(providerName ProviderName, ir *intermediate.IR, mapping map[string]string) {
+	if len(mapping) == 0 {
+		return
+	}
+
+	mappedGateways := map[types.NamespacedName]intermediate.GatewayContext{}
+	mappedKeys := map[types.NamespacedName]types.NamespacedName{}
+	renamedListeners := map[types.NamespacedName]map[gatewayv1.SectionName]gatewayv1.SectionName{}
+	for _, key := range sortedNamespacedNames(ir.Gateways) {
+		gatewayContext := ir.Gateways[key]
+		ingressClass := gatewayContext.Spec.GatewayClassName
+		gatewayClass, mapped := mapping[string(ingressClass)]
+		mappedKey := key
+		if mapped {
+			mappedKey = types.NamespacedName{Namespace: key.Namespace, Name: gatewayClass}
+			mappedKeys[key] = mappedKey
+		}
+
+		mappedGateway, ok := mappedGateways[mappedKey]
+		if !ok {
+			mappedGateway = intermediate.GatewayContext{
+				Gateway:            *gatewayContext.Gateway.DeepCopy(),
+				ProviderSpecificIR: gatewayContext.ProviderSpecificIR,
+			}
+			if mapped {
+				mappedGateway.Name = gatewayClass
+				mappedGateway.Spec.GatewayClassName = gatewayv1.ObjectName(gatewayClass)
+				notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.InfoNotification,
+					fmt.Sprintf("Gateway %s of IngressClass %s is served by GatewayClass %s as Gateway %s", key, ingressClass, gatewayClass, mappedKey),
+					&mappedGateway.Gateway), string(providerName))
+			}
+			mappedGateways[mappedKey] = mappedGateway
+			continue
+		}
+
+		for _, listener := range gatewayContext.Spec.Listeners {
+			merged := mergeCentralListener(mappedGateway.Spec.Listeners, *listener.DeepCopy(), key.Name)
+			if len(merged) > len(mappedGateway.Spec.Listeners) && merged[len(merged)-1].Name != listener.Name {
+				if renamedListeners[key] == nil {
+					renamedListeners[key] = map[gatewayv1.SectionName]gatewayv1.SectionName{}
+				}
+				renamedListeners[key][listener.Name] = merged[len(merged)-1].Name
+				mappedKeys[key] = mappedKey
+			}
+			mappedGateway.Spec.Listeners = merged
+		}
+		mappedGateways[mappedKey] = mappedGateway
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.InfoNotification,
+			fmt.Sprintf("Gateway %s of IngressClass %s is merged into Gateway %s of GatewayClass %s", key, ingressClass, mappedKey, mappedGateway.Spec.GatewayClassName),
+			&mappedGateway.Gateway), string(providerName))
+	}
+
+	remap := func(routeKey types.NamespacedName, parentRefs []gatewayv1.ParentReference) {
+		for i, parentRef := range parentRefs {
+			gatewayKeys := gatewayParentRefKeys(routeKey, []gatewayv1.ParentReference{parentRef})
+			if len(gatewayKeys) == 0 {
+				continue
+			}
+			mappedKey, ok := mappedKeys[gatewayKeys[0]]
+			if !ok {
+				continue
+			}
+			parentRefs[i].Name = gatewayv1.ObjectName(mappedKey.Name)
+			if parentRef.SectionName != nil {
+				if renamed, ok := renamedListeners[gatewayKeys[0]][*parentRef.SectionName]; ok {
+					parentRefs[i].SectionName = &renamed
+				}
+			}
+		}
+	}
+	for key, httpRouteContext := range ir.HTTPRoutes {
+		remap(key, httpRouteContext.Spec.ParentRefs)
+	}
+	for key, route := range ir.GRPCRoutes {
+		remap(key, route.Spec.ParentRefs)
+	}
+	for key, route := range ir.TLSRoutes {
+		remap(key, route.Spec.ParentRefs)
+	}
+	for key, route := range ir.TCPRoutes {
+		remap(key, route.Spec.ParentRefs)
+	}
+	for key, route := range ir.UDPRoutes {
+		remap(key, route.Spec.ParentRefs)
+	}
+	ir.Gateways = mappedGateways
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_mapGatewayClasses(t *testing.T) {
+	gateway := func(name string, listeners ...gatewayv1.Listener) intermediate.GatewayContext {
+		return intermediate.GatewayContext{Gateway: gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: gatewayv1.ObjectName(name), Listeners: listeners},
+		}}
+	}
+	httpRoute := func(gateway string, sectionName gatewayv1.SectionName) intermediate.HTTPRouteContext {
+		return intermediate.HTTPRouteContext{HTTPRoute: gatewayv1.HTTPRoute{Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gateway), SectionName: ptr.To(sectionName)}}},
+		}}}
+	}
+	shopListener := gatewayv1.Listener{Name: "shop-example-com-http", Hostname: ptr.To[gatewayv1.Hostname]("shop.example.com"), Port: 80, Protocol: gatewayv1.HTTPProtocolType}
+	internalListener := gatewayv1.Listener{Name: "http", Port: 8080, Protocol: gatewayv1.HTTPProtocolType}
+	otherListener := gatewayv1.Listener{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}
+
+	ir := intermediate.IR{
+		Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+			{Namespace: "default", Name: "nginx"}:          gateway("nginx", shopListener, otherListener),
+			{Namespace: "default", Name: "internal-nginx"}: gateway("internal-nginx", internalListener),
+			{Namespace: "default", Name: "traefik"}:        gateway("traefik", otherListener),
+		},
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			{Namespace: "default", Name: "shop"}:     httpRoute("nginx", "shop-example-com-http"),
+			{Namespace: "default", Name: "internal"}: httpRoute("internal-nginx", "http"),
+			{Namespace: "default", Name: "catchall"}: httpRoute("nginx", "http"),
+			{Namespace: "default", Name: "other"}:    httpRoute("traefik", "http"),
+		},
+	}
+
+	mapGatewayClasses("ingress-nginx", &ir, map[string]string{"nginx": "envoy", "internal-nginx": "envoy"})
+
+	require.Len(t, ir.Gateways, 2)
+	envoy := ir.Gateways[types.NamespacedName{Namespace: "default", Name: "envoy"}]
+	require.Equal(t, gatewayv1.ObjectName("envoy"), envoy.Spec.GatewayClassName)
+	var listeners []gatewayv1.SectionName
+	for _, listener := range envoy.Spec.Listeners {
+		listeners = append(listeners, listener.Name)
+	}
+	require.Equal(t, []gatewayv1.SectionName{"http", "shop-example-com-http", "http-nginx"}, listeners)
+	require.Equal(t, gatewayv1.ObjectName("traefik"), ir.Gateways[types.NamespacedName{Namespace: "default", Name: "traefik"}].Spec.GatewayClassName)
+
+	require.Equal(t, []gatewayv1.ParentReference{{Name: "envoy", SectionName: ptr.To[gatewayv1.SectionName]("shop-example-com-http")}}, ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "shop"}].Spec.ParentRefs)
+	require.Equal(t, []gatewayv1.ParentReference{{Name: "envoy", SectionName: ptr.To[gatewayv1.SectionName]("http")}}, ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "internal"}].Spec.ParentRefs)
+	require.Equal(t, []gatewayv1.ParentReference{{Name: "envoy", SectionName: ptr.To[gatewayv1.SectionName]("http-nginx")}}, ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "catchall"}].Spec.ParentRefs)
+	require.Equal(t, []gatewayv1.ParentReference{{Name: "traefik", SectionName: ptr.To[gatewayv1.SectionName]("http")}}, ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "other"}].Spec.ParentRefs)
+}
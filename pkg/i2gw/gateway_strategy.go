@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// GatewayStrategy is a string alias that stores the name of the strategy used
+// to generate Gateways.
+type GatewayStrategy string
+
+const (
+	// MergedGatewayStrategy merges the listeners of all the source resources
+	// sharing a Gateway, e.g. the Ingresses of the same class and namespace.
+	MergedGatewayStrategy GatewayStrategy = "merged"
+	// PerSourceGatewayStrategy generates a Gateway for each source resource,
+	// providing a strict 1:1 mapping for auditing.
+	PerSourceGatewayStrategy GatewayStrategy = "per-source"
+)
+
+// GetSupportedGatewayStrategies returns the names of all the supported Gateway
+// strategies.
+func GetSupportedGatewayStrategies() []string {
+	return []string{string(MergedGatewayStrategy), string(PerSourceGatewayStrategy)}
+}
+
+// validateGatewayStrategy returns an error if the given strategy is not
+// supported. An empty strategy means the MergedGatewayStrategy.
+func validateGatewayStrategy(strategy GatewayStrategy) error {
+	if strategy != "" && !slices.Contains(GetSupportedGatewayStrategies(), string(strategy)) {
+		return fmt.Errorf("%s is not a supported gateway strategy, supported values are %v", strategy, GetSupportedGatewayStrategies())
+	}
+	return nil
+}
+
+// splitGatewaysBySource splits the Gateways of the IR so that each source
+// resource of the HTTPRoutes gets its own Gateway, named after the original
+// Gateway and the source. Each Gateway only keeps the listeners matching the
+// hostnames of the routes of its source. The original Gateways are removed
+// once no route is attached to them anymore. HTTPRoutes without sources are
+// left untouched.
+func splitGatewaysBySource(providerName ProviderName, ir *intermediate.IR) {
+	attachedRoutes := map[types.NamespacedName]int{}
+	for key, httpRouteContext := range ir.HTTPRoutes {
+		for _, gatewayKey := range gatewayParentRefKeys(key, httpRouteContext.Spec.ParentRefs) {
+			attachedRoutes[gatewayKey]++
+		}
+	}
+
+	routeKeys := make([]types.NamespacedName, 0, len(ir.HTTPRoutes))
+	for key := range ir.HTTPRoutes {
+		routeKeys = append(routeKeys, key)
+	}
+	slices.SortFunc(routeKeys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	splitGateways := map[types.NamespacedName]intermediate.GatewayContext{}
+	for _, key := range routeKeys {
+		httpRouteContext := ir.HTTPRoutes[key]
+		if len(httpRouteContext.Sources) == 0 {
+			continue
+		}
+		source := httpRouteContext.Sources[0]
+		if len(httpRouteContext.Sources) > 1 {
+			notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification,
+				fmt.Sprintf("HTTPRoute %s merges the rules of several sources, it is attached to the Gateway generated for %s/%s", key, source.GetNamespace(), source.GetName()),
+				&httpRouteContext.HTTPRoute), string(providerName))
+		}
+
+		for i, parentRef := range httpRouteContext.Spec.ParentRefs {
+			gatewayKeys := gatewayParentRefKeys(key, []gatewayv1.ParentReference{parentRef})
+			if len(gatewayKeys) == 0 {
+				continue
+			}
+			gatewayKey := gatewayKeys[0]
+			gatewayContext, ok := ir.Gateways[gatewayKey]
+			if !ok {
+				continue
+			}
+
+			splitKey := types.NamespacedName{Namespace: gatewayKey.Namespace, Name: fmt.Sprintf("%s-%s", gatewayKey.Name, source.GetName())}
+			splitGateway, ok := splitGateways[splitKey]
+			if !ok {
+				splitGateway = intermediate.GatewayContext{
+					Gateway:            *gatewayContext.Gateway.DeepCopy(),
+					ProviderSpecificIR: gatewayContext.ProviderSpecificIR,
+				}
+				splitGateway.Name = splitKey.Name
+				splitGateway.Spec.Listeners = nil
+			}
+			for _, listener := range listenersForHostnames(gatewayContext.Spec.Listeners, httpRouteContext.Spec.Hostnames) {
+				if !slices.ContainsFunc(splitGateway.Spec.Listeners, func(l gatewayv1.Listener) bool { return l.Name == listener.Name }) {
+					splitGateway.Spec.Listeners = append(splitGateway.Spec.Listeners, listener)
+				}
+			}
+			splitGateways[splitKey] = splitGateway
+
+			httpRouteContext.Spec.ParentRefs[i].Name = gatewayv1.ObjectName(splitKey.Name)
+			attachedRoutes[gatewayKey]--
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+
+	for gatewayKey, count := range attachedRoutes {
+		if count == 0 {
+			delete(ir.Gateways, gatewayKey)
+		}
+	}
+	for key, gatewayContext := range splitGateways {
+		ir.Gateways[key] = gatewayContext
+	}
+}
+
+// gatewayParentRefKeys returns the keys of the Gateways referenced by the
+// given parentRefs of the route with the given key.
+func gatewayParentRefKeys(routeKey types.NamespacedName, parentRefs []gatewayv1.ParentReference) []types.NamespacedName {
+	var keys []types.NamespacedName
+	for _, parentRef := range parentRefs {
+		if parentRef.Kind != nil && *parentRef.Kind != "Gateway" {
+			continue
+		}
+		key := types.NamespacedName{Namespace: routeKey.Namespace, Name: string(parentRef.Name)}
+		if parentRef.Namespace != nil {
+			key.Namespace = string(*parentRef.Namespace)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// listenersForHostnames returns the listeners matching the given route
+// hostnames. All the listeners are returned if none matches, e.g. for routes
+// of default backends.
+func listenersForHostnames(listeners []gatewayv1.Listener, hostnames []gatewayv1.Hostname) []gatewayv1.Listener {
+	var matching []gatewayv1.Listener
+	for _, listener := range listeners {
+		if listener.Hostname == nil {
+			if len(hostnames) == 0 {
+				matching = append(matching, listener)
+			}
+			continue
+		}
+		if slices.Contains(hostnames, *listener.Hostname) {
+			matching = append(matching, listener)
+		}
+	}
+	if len(matching) == 0 {
+		return listeners
+	}
+	return matching
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_validateGatewayStrategy(t *testing.T) {
+	require.NoError(t, validateGatewayStrategy(""))
+	require.NoError(t, validateGatewayStrategy(MergedGatewayStrategy))
+	require.NoError(t, validateGatewayStrategy(PerSourceGatewayStrategy))
+	require.Error(t, validateGatewayStrategy("per-host"))
+}
+
+func Test_splitGatewaysBySource(t *testing.T) {
+	foo := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}}
+	bar := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar"}}
+
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+	fooRouteKey := types.NamespacedName{Namespace: "default", Name: "foo-foo-example-com"}
+	barRouteKey := types.NamespacedName{Namespace: "default", Name: "bar-bar-example-com"}
+	otherRouteKey := types.NamespacedName{Namespace: "default", Name: "other"}
+
+	httpRoute := func(key types.NamespacedName, hostname gatewayv1.Hostname, sources ...client.Object) intermediate.HTTPRouteContext {
+		return intermediate.HTTPRouteContext{
+			HTTPRoute: gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{
+						ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}},
+					},
+					Hostnames: []gatewayv1.Hostname{hostname},
+				},
+			},
+			Sources: sources,
+		}
+	}
+
+	ir := intermediate.IR{
+		Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+			gatewayKey: {
+				Gateway: gatewayv1.Gateway{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+					Spec: gatewayv1.GatewaySpec{
+						GatewayClassName: "nginx",
+						Listeners: []gatewayv1.Listener{
+							{Name: "foo-example-com-http", Hostname: ptr.To[gatewayv1.Hostname]("foo.example.com")},
+							{Name: "foo-example-com-https", Hostname: ptr.To[gatewayv1.Hostname]("foo.example.com")},
+							{Name: "bar-example-com-http", Hostname: ptr.To[gatewayv1.Hostname]("bar.example.com")},
+						},
+					},
+				},
+			},
+		},
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			fooRouteKey: httpRoute(fooRouteKey, "foo.example.com", foo),
+			barRouteKey: httpRoute(barRouteKey, "bar.example.com", bar),
+		},
+	}
+
+	splitGatewaysBySource("test", &ir)
+
+	require.NotContains(t, ir.Gateways, gatewayKey)
+	require.Len(t, ir.Gateways, 2)
+
+	fooGateway := ir.Gateways[types.NamespacedName{Namespace: "default", Name: "nginx-foo"}]
+	require.Equal(t, gatewayv1.ObjectName("nginx"), fooGateway.Spec.GatewayClassName)
+	require.Equal(t, []gatewayv1.SectionName{"foo-example-com-http", "foo-example-com-https"}, listenerNames(fooGateway.Spec.Listeners))
+	require.Equal(t, gatewayv1.ObjectName("nginx-foo"), ir.HTTPRoutes[fooRouteKey].Spec.ParentRefs[0].Name)
+
+	barGateway := ir.Gateways[types.NamespacedName{Namespace: "default", Name: "nginx-bar"}]
+	require.Equal(t, []gatewayv1.SectionName{"bar-example-com-http"}, listenerNames(barGateway.Spec.Listeners))
+	require.Equal(t, gatewayv1.ObjectName("nginx-bar"), ir.HTTPRoutes[barRouteKey].Spec.ParentRefs[0].Name)
+
+	t.Run("gateway still referenced by routes without sources is kept", func(t *testing.T) {
+		ir := intermediate.IR{
+			Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+				gatewayKey: {Gateway: gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"}}},
+			},
+			HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+				fooRouteKey:   httpRoute(fooRouteKey, "foo.example.com", foo),
+				otherRouteKey: httpRoute(otherRouteKey, "other.example.com"),
+			},
+		}
+
+		splitGatewaysBySource("test", &ir)
+
+		require.Contains(t, ir.Gateways, gatewayKey)
+		require.Contains(t, ir.Gateways, types.NamespacedName{Namespace: "default", Name: "nginx-foo"})
+		require.Equal(t, gatewayv1.ObjectName("nginx"), ir.HTTPRoutes[otherRouteKey].Spec.ParentRefs[0].Name)
+	})
+}
+
+func listenerNames(listeners []gatewayv1.Listener) []gatewayv1.SectionName {
+	var names []gatewayv1.SectionName
+	for _, listener := range listeners {
+		names = append(names, listener.Name)
+	}
+	return names
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// hostClaim is a route generated by a provider for a hostname, with the
+// source resources it was generated from.
+type hostClaim struct {
+	routeKind string
+	route     types.NamespacedName
+	sources   []client.Object
+}
+
+// String describes the sources of the claim, or the route itself when the
+// provider doesn't track them.
+func (c hostClaim) String() string {
+	if len(c.sources) == 0 {
+		return fmt.Sprintf("%s %s", c.routeKind, c.route)
+	}
+	descriptions := make([]string, 0, len(c.sources))
+	for _, source := range c.sources {
+		kind := source.GetObjectKind().GroupVersionKind().Kind
+		if kind == "" {
+			kind = reflect.TypeOf(source).Elem().Name()
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%s %s", kind, client.ObjectKeyFromObject(source)))
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// hostClaims are the routes generated by a provider, by the hostnames they
+// serve.
+type hostClaims map[gatewayv1.Hostname][]hostClaim
+
+// validateHostConflictPriority returns an error if a provider of the host
+// conflict priority is not enabled.
+func validateHostConflictPriority(providers []string, priority []string) error {
+	for _, name := range priority {
+		if !slices.Contains(providers, name) {
+			return fmt.Errorf("provider %s of the host conflict priority is not enabled, enabled providers are %v", name, providers)
+		}
+	}
+	return nil
+}
+
+// routeHostClaims returns the hostnames of the HTTPRoutes, GRPCRoutes and
+// TLSRoutes generated by a provider. Routes without hostnames don't claim any.
+func routeHostClaims(ir intermediate.IR, gatewayResources *GatewayResources) hostClaims {
+	claims := hostClaims{}
+	add := func(routeKind string, key types.NamespacedName, hostnames []gatewayv1.Hostname, sources []client.Object) {
+		hostnames = slices.Clone(hostnames)
+		slices.Sort(hostnames)
+		for _, hostname := range slices.Compact(hostnames) {
+			claims[hostname] = append(claims[hostname], hostClaim{routeKind: routeKind, route: key, sources: sources})
+		}
+	}
+	for _, key := range sortedNamespacedNames(gatewayResources.HTTPRoutes) {
+		add("HTTPRoute", key, gatewayResources.HTTPRoutes[key].Spec.Hostnames, ir.HTTPRoutes[key].Sources)
+	}
+	for _, key := range sortedNamespacedNames(gatewayResources.GRPCRoutes) {
+		add("GRPCRoute", key, gatewayResources.GRPCRoutes[key].Spec.Hostnames, nil)
+	}
+	for _, key := range sortedNamespacedNames(gatewayResources.TLSRoutes) {
+		add("TLSRoute", key, gatewayResources.TLSRoutes[key].Spec.Hostnames, nil)
+	}
+	return claims
+}
+
+// resolveHostConflicts reports the hostnames routed by several providers,
+// with the sources of their routes in each of them. When one of these
+// providers is listed in the given priority, the first listed one owns the
+// hostname: it is removed from the routes of the other providers, and the
+// routes left without hostnames are removed. Otherwise, the conflict is only
+// reported, as the implementations would merge or reject the routes.
+func resolveHostConflicts(claimsByProvider map[ProviderName]hostClaims, gatewayResourcesByProvider map[ProviderName]*GatewayResources, priority []string) {
+	providersByHostname := map[gatewayv1.Hostname][]ProviderName{}
+	for name, claims := range claimsByProvider {
+		for hostname := range claims {
+			providersByHostname[hostname] = append(providersByHostname[hostname], name)
+		}
+	}
+	hostnames := make([]gatewayv1.Hostname, 0, len(providersByHostname))
+	for hostname, providers := range providersByHostname {
+		if len(providers) > 1 {
+			hostnames = append(hostnames, hostname)
+		}
+	}
+	sort.Slice(hostnames, func(i, j int) bool { return hostnames[i] < hostnames[j] })
+
+	for _, hostname := range hostnames {
+		providers := providersByHostname[hostname]
+		slices.Sort(providers)
+
+		var owner ProviderName
+		for _, name := range priority {
+			if slices.Contains(providers, ProviderName(name)) {
+				owner = ProviderName(name)
+				break
+			}
+		}
+
+		var (
+			claimants []string
+			sources   []client.Object
+		)
+		for _, name := range providers {
+			var descriptions []string
+			for _, claim := range claimsByProvider[name][hostname] {
+				descriptions = append(descriptions, claim.String())
+				sources = append(sources, claim.sources...)
+			}
+			claimants = append(claimants, fmt.Sprintf("the %s provider (%s)", name, strings.Join(descriptions, ", ")))
+		}
+		message := fmt.Sprintf("hostname %s is claimed by %s", hostname, strings.Join(claimants, " and "))
+		if owner == "" {
+			message += ", use --host-conflict-priority to decide which provider generates its routes"
+		} else {
+			message += fmt.Sprintf(", the routes of the %s provider take precedence", owner)
+		}
+
+		for _, name := range providers {
+			notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification, message, sources...), string(name))
+			if owner == "" || name == owner {
+				continue
+			}
+			for _, claim := range claimsByProvider[name][hostname] {
+				removeRouteHostname(gatewayResourcesByProvider[name], claim, hostname)
+			}
+		}
+	}
+}
+
+// removeRouteHostname removes the hostname from the route of the claim, and
+// the route when it has no hostname left, as it would then match all of them.
+func removeRouteHostname(gatewayResources *GatewayResources, claim hostClaim, hostname gatewayv1.Hostname) {
+	without := func(hostnames []gatewayv1.Hostname) []gatewayv1.Hostname {
+		return slices.DeleteFunc(slices.Clone(hostnames), func(h gatewayv1.Hostname) bool { return h == hostname })
+	}
+	switch claim.routeKind {
+	case "HTTPRoute":
+		route := gatewayResources.HTTPRoutes[claim.route]
+		if route.Spec.Hostnames = without(route.Spec.Hostnames); len(route.Spec.Hostnames) == 0 {
+			delete(gatewayResources.HTTPRoutes, claim.route)
+			return
+		}
+		gatewayResources.HTTPRoutes[claim.route] = route
+	case "GRPCRoute":
+		route := gatewayResources.GRPCRoutes[claim.route]
+		if route.Spec.Hostnames = without(route.Spec.Hostnames); len(route.Spec.Hostnames) == 0 {
+			delete(gatewayResources.GRPCRoutes, claim.route)
+			return
+		}
+		gatewayResources.GRPCRoutes[claim.route] = route
+	case "TLSRoute":
+		route := gatewayResources.TLSRoutes[claim.route]
+		if route.Spec.Hostnames = without(route.Spec.Hostnames); len(route.Spec.Hostnames) == 0 {
+			delete(gatewayResources.TLSRoutes, claim.route)
+			return
+		}
+		gatewayResources.TLSRoutes[claim.route] = route
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_resolveHostConflicts(t *testing.T) {
+	httpRoute := func(hostnames ...gatewayv1.Hostname) gatewayv1.HTTPRoute {
+		return gatewayv1.HTTPRoute{Spec: gatewayv1.HTTPRouteSpec{Hostnames: hostnames}}
+	}
+	ingress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "shop"}}
+	ingressRouteKey := types.NamespacedName{Namespace: "default", Name: "shop-example-com"}
+	istioRouteKey := types.NamespacedName{Namespace: "default", Name: "shop"}
+
+	testCases := []struct {
+		name                 string
+		priority             []string
+		expectedNginxRoutes  map[types.NamespacedName]gatewayv1.HTTPRoute
+		expectedIstioRoutes  map[types.NamespacedName]gatewayv1.HTTPRoute
+		expectedNotification string
+	}{
+		{
+			name:                 "conflict reported only",
+			expectedNginxRoutes:  map[types.NamespacedName]gatewayv1.HTTPRoute{ingressRouteKey: httpRoute("shop.example.com")},
+			expectedIstioRoutes:  map[types.NamespacedName]gatewayv1.HTTPRoute{istioRouteKey: httpRoute("api.example.com", "shop.example.com")},
+			expectedNotification: "hostname shop.example.com is claimed by the ingress-nginx provider (Ingress default/shop) and the istio provider (HTTPRoute default/shop), use --host-conflict-priority",
+		},
+		{
+			name:                 "ingress-nginx takes precedence",
+			priority:             []string{"ingress-nginx"},
+			expectedNginxRoutes:  map[types.NamespacedName]gatewayv1.HTTPRoute{ingressRouteKey: httpRoute("shop.example.com")},
+			expectedIstioRoutes:  map[types.NamespacedName]gatewayv1.HTTPRoute{istioRouteKey: httpRoute("api.example.com")},
+			expectedNotification: "the routes of the ingress-nginx provider take precedence",
+		},
+		{
+			name:                 "istio takes precedence",
+			priority:             []string{"istio", "ingress-nginx"},
+			expectedNginxRoutes:  map[types.NamespacedName]gatewayv1.HTTPRoute{},
+			expectedIstioRoutes:  map[types.NamespacedName]gatewayv1.HTTPRoute{istioRouteKey: httpRoute("api.example.com", "shop.example.com")},
+			expectedNotification: "the routes of the istio provider take precedence",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			nginxIR := intermediate.IR{HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+				ingressRouteKey: {HTTPRoute: httpRoute("shop.example.com"), Sources: []client.Object{ingress}},
+			}}
+			nginxResources := &GatewayResources{HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{ingressRouteKey: httpRoute("shop.example.com")}}
+			istioResources := &GatewayResources{HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{istioRouteKey: httpRoute("api.example.com", "shop.example.com")}}
+			notifications.NotificationAggr = notifications.NotificationAggregator{Notifications: map[string][]notifications.Notification{}}
+
+			resolveHostConflicts(map[ProviderName]hostClaims{
+				"ingress-nginx": routeHostClaims(nginxIR, nginxResources),
+				"istio":         routeHostClaims(intermediate.IR{}, istioResources),
+			}, map[ProviderName]*GatewayResources{
+				"ingress-nginx": nginxResources,
+				"istio":         istioResources,
+			}, tc.priority)
+
+			require.Equal(t, tc.expectedNginxRoutes, nginxResources.HTTPRoutes)
+			require.Equal(t, tc.expectedIstioRoutes, istioResources.HTTPRoutes)
+			for _, provider := range []string{"ingress-nginx", "istio"} {
+				require.Len(t, notifications.NotificationAggr.Notifications[provider], 1)
+				require.Contains(t, notifications.NotificationAggr.Notifications[provider][0].Message, tc.expectedNotification)
+			}
+		})
+	}
+}
+
+func Test_validateHostConflictPriority(t *testing.T) {
+	require.NoError(t, validateHostConflictPriority([]string{"ingress-nginx", "istio"}, []string{"istio"}))
+	require.Error(t, validateHostConflictPriority([]string{"ingress-nginx"}, []string{"istio"}))
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// HostnamePolicy is a string alias that stores the name of the policy applied
+// to the hostnames of the sources Gateway API rejects.
+type HostnamePolicy string
+
+const (
+	// StrictHostnamePolicy fails the conversion of the invalid hostnames.
+	StrictHostnamePolicy HostnamePolicy = "strict"
+	// SanitizeHostnamePolicy lowercases the invalid hostnames, replaces their
+	// invalid characters with dashes and truncates their labels to 63
+	// characters. The hostnames which are still invalid are dropped.
+	SanitizeHostnamePolicy HostnamePolicy = "sanitize"
+	// SkipHostnamePolicy drops the invalid hostnames. It is the default
+	// policy.
+	SkipHostnamePolicy HostnamePolicy = "skip"
+)
+
+// GetSupportedHostnamePolicies returns the names of all the supported
+// hostname policies.
+func GetSupportedHostnamePolicies() []string {
+	return []string{string(StrictHostnamePolicy), string(SanitizeHostnamePolicy), string(SkipHostnamePolicy)}
+}
+
+// validateHostnamePolicy returns an error if the given policy is not
+// supported. An empty policy means the SkipHostnamePolicy.
+func validateHostnamePolicy(policy HostnamePolicy) error {
+	if policy != "" && !slices.Contains(GetSupportedHostnamePolicies(), string(policy)) {
+		return fmt.Errorf("%s is not a supported hostname policy, supported values are %v", policy, GetSupportedHostnamePolicies())
+	}
+	return nil
+}
+
+var (
+	// gatewayHostnameRegexp is the pattern of the Hostname of Gateway API.
+	gatewayHostnameRegexp = regexp.MustCompile(`^(\*\.)?[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	// invalidHostnameCharacters are the characters sanitized hostnames
+	// replace with dashes.
+	invalidHostnameCharacters = regexp.MustCompile(`[^a-z0-9-]`)
+)
+
+// invalidHostnameReason returns why Gateway API rejects the hostname, or an
+// empty string if it is valid.
+func invalidHostnameReason(hostname string) string {
+	switch {
+	case net.ParseIP(hostname) != nil:
+		return "IP addresses are not allowed"
+	case len(hostname) > 253:
+		return "must be no more than 253 characters"
+	case !gatewayHostnameRegexp.MatchString(hostname):
+		return "must be a lowercase RFC 1123 hostname, optionally prefixed with a *. wildcard label"
+	}
+	for _, label := range strings.Split(strings.TrimPrefix(hostname, "*."), ".") {
+		if len(label) > 63 {
+			return fmt.Sprintf("label %s must be no more than 63 characters", label)
+		}
+	}
+	return ""
+}
+
+// sanitizeHostname lowercases the hostname, replaces its invalid characters
+// with dashes, truncates its labels to 63 characters, and removes their
+// leading and trailing dashes and the empty labels.
+func sanitizeHostname(hostname string) string {
+	wildcard := strings.HasPrefix(hostname, "*.")
+	var labels []string
+	for _, label := range strings.Split(strings.ToLower(strings.TrimPrefix(hostname, "*.")), ".") {
+		label = invalidHostnameCharacters.ReplaceAllString(label, "-")
+		if len(label) > 63 {
+			label = label[:63]
+		}
+		if label = strings.Trim(label, "-"); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	if len(labels) == 0 {
+		return ""
+	}
+	if wildcard {
+		labels = append([]string{"*"}, labels...)
+	}
+	return strings.Join(labels, ".")
+}
+
+// applyHostnamePolicy applies the policy to the invalid hostnames of the
+// listeners of the Gateways and of the routes of the IR. The strict policy
+// returns an error per invalid hostname, leaving the IR unchanged. The
+// sanitize and skip policies notify the sanitized and dropped hostnames: the
+// listeners whose hostname is dropped, and the routes whose hostnames are all
+// dropped, are removed so that they don't start matching all the hostnames.
+func applyHostnamePolicy(providerName ProviderName, ir *intermediate.IR, policy HostnamePolicy) field.ErrorList {
+	var errs field.ErrorList
+	notify := func(messageType notifications.MessageType, message string, objects ...client.Object) {
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(messageType, message, objects...), string(providerName))
+	}
+
+	// convert returns the hostname generated for the given one, and false if
+	// it is dropped.
+	convert := func(hostname gatewayv1.Hostname, fieldPath *field.Path, objects ...client.Object) (gatewayv1.Hostname, bool) {
+		reason := invalidHostnameReason(string(hostname))
+		if reason == "" {
+			return hostname, true
+		}
+		switch policy {
+		case SanitizeHostnamePolicy:
+			sanitized := sanitizeHostname(string(hostname))
+			if invalidHostnameReason(sanitized) == "" {
+				notify(notifications.InfoNotification, fmt.Sprintf("hostname %s of %s was sanitized to %s: %s", hostname, fieldPath, sanitized, reason), objects...)
+				return gatewayv1.Hostname(sanitized), true
+			}
+			notify(notifications.WarningNotification, fmt.Sprintf("hostname %s of %s can't be sanitized and was dropped: %s", hostname, fieldPath, reason), objects...)
+			return "", false
+		case StrictHostnamePolicy:
+			errs = append(errs, field.Invalid(fieldPath, hostname, reason+", use --hostname-policy to sanitize or skip the invalid hostnames"))
+			return hostname, true
+		default:
+			notify(notifications.WarningNotification, fmt.Sprintf("hostname %s of %s was dropped: %s", hostname, fieldPath, reason), objects...)
+			return "", false
+		}
+	}
+
+	// convertRouteHostnames returns the hostnames of a route generated for the
+	// given ones, and false if they are all dropped.
+	convertRouteHostnames := func(kind string, key types.NamespacedName, hostnames []gatewayv1.Hostname, objects ...client.Object) ([]gatewayv1.Hostname, bool) {
+		if len(hostnames) == 0 {
+			return hostnames, true
+		}
+		fieldPath := field.NewPath(kind).Key(key.String()).Child("spec", "hostnames")
+		var converted []gatewayv1.Hostname
+		for i, hostname := range hostnames {
+			if hostname, ok := convert(hostname, fieldPath.Index(i), objects...); ok && !slices.Contains(converted, hostname) {
+				converted = append(converted, hostname)
+			}
+		}
+		if len(converted) == 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s %s was removed, as none of its hostnames is valid", kind, key), objects...)
+			return nil, false
+		}
+		return converted, true
+	}
+
+	for _, key := range sortedNamespacedNames(ir.Gateways) {
+		gatewayContext := ir.Gateways[key]
+		var listeners []gatewayv1.Listener
+		for i, listener := range gatewayContext.Spec.Listeners {
+			if listener.Hostname != nil {
+				fieldPath := field.NewPath("Gateway").Key(key.String()).Child("spec", "listeners").Index(i).Child("hostname")
+				hostname, ok := convert(*listener.Hostname, fieldPath, &gatewayContext.Gateway)
+				if !ok {
+					notify(notifications.WarningNotification, fmt.Sprintf("listener %s of Gateway %s was removed, as its hostname is not valid", listener.Name, key), &gatewayContext.Gateway)
+					continue
+				}
+				listener.Hostname = &hostname
+			}
+			listeners = append(listeners, listener)
+		}
+		gatewayContext.Spec.Listeners = listeners
+		ir.Gateways[key] = gatewayContext
+	}
+	for _, key := range sortedNamespacedNames(ir.HTTPRoutes) {
+		httpRouteContext := ir.HTTPRoutes[key]
+		hostnames, ok := convertRouteHostnames("HTTPRoute", key, httpRouteContext.Spec.Hostnames, httpRouteContext.Sources...)
+		if !ok {
+			delete(ir.HTTPRoutes, key)
+			continue
+		}
+		httpRouteContext.Spec.Hostnames = hostnames
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+	for _, key := range sortedNamespacedNames(ir.GRPCRoutes) {
+		route := ir.GRPCRoutes[key]
+		hostnames, ok := convertRouteHostnames("GRPCRoute", key, route.Spec.Hostnames, &route)
+		if !ok {
+			delete(ir.GRPCRoutes, key)
+			continue
+		}
+		route.Spec.Hostnames = hostnames
+		ir.GRPCRoutes[key] = route
+	}
+	for _, key := range sortedNamespacedNames(ir.TLSRoutes) {
+		route := ir.TLSRoutes[key]
+		hostnames, ok := convertRouteHostnames("TLSRoute", key, route.Spec.Hostnames, &route)
+		if !ok {
+			delete(ir.TLSRoutes, key)
+			continue
+		}
+		route.Spec.Hostnames = hostnames
+		ir.TLSRoutes[key] = route
+	}
+	return errs
+}
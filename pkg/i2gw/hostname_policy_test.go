@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_validateHostnamePolicy(t *testing.T) {
+	require.NoError(t, validateHostnamePolicy(""))
+	require.NoError(t, validateHostnamePolicy(StrictHostnamePolicy))
+	require.NoError(t, validateHostnamePolicy(SanitizeHostnamePolicy))
+	require.NoError(t, validateHostnamePolicy(SkipHostnamePolicy))
+	require.Error(t, validateHostnamePolicy("drop"))
+}
+
+func Test_invalidHostnameReason(t *testing.T) {
+	require.Empty(t, invalidHostnameReason("foo.example.com"))
+	require.Empty(t, invalidHostnameReason("*.example.com"))
+	require.NotEmpty(t, invalidHostnameReason("foo_bar.example.com"))
+	require.NotEmpty(t, invalidHostnameReason("Foo.example.com"))
+	require.NotEmpty(t, invalidHostnameReason("192.0.2.1"))
+	require.NotEmpty(t, invalidHostnameReason(strings.Repeat("a", 64)+".example.com"))
+	require.NotEmpty(t, invalidHostnameReason(strings.Repeat("a.", 127)+"com"))
+}
+
+func Test_sanitizeHostname(t *testing.T) {
+	require.Equal(t, "foo-bar.example.com", sanitizeHostname("Foo_Bar.example.com"))
+	require.Equal(t, "*.foo.example.com", sanitizeHostname("*.-foo_.example.com"))
+	require.Equal(t, strings.Repeat("a", 63)+".example.com", sanitizeHostname(strings.Repeat("a", 70)+".example.com"))
+	require.Equal(t, "", sanitizeHostname("_"))
+}
+
+func hostnamePolicyIR() intermediate.IR {
+	return intermediate.IR{
+		Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+			{Namespace: "default", Name: "nginx"}: {
+				Gateway: gatewayv1.Gateway{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+					Spec: gatewayv1.GatewaySpec{
+						GatewayClassName: "nginx",
+						Listeners: []gatewayv1.Listener{
+							httpListener("foo-example-com-http", "foo.example.com"),
+							httpListener("foo-bar-example-com-http", "foo_bar.example.com"),
+						},
+					},
+				},
+			},
+		},
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			{Namespace: "default", Name: "foo"}: {
+				HTTPRoute: gatewayv1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+					Spec:       gatewayv1.HTTPRouteSpec{Hostnames: []gatewayv1.Hostname{"foo.example.com", "foo_bar.example.com"}},
+				},
+			},
+			{Namespace: "default", Name: "foo-bar"}: {
+				HTTPRoute: gatewayv1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo-bar"},
+					Spec:       gatewayv1.HTTPRouteSpec{Hostnames: []gatewayv1.Hostname{"foo_bar.example.com"}},
+				},
+			},
+		},
+	}
+}
+
+func Test_applyHostnamePolicy(t *testing.T) {
+	t.Run("strict", func(t *testing.T) {
+		ir := hostnamePolicyIR()
+		errs := applyHostnamePolicy("test", &ir, StrictHostnamePolicy)
+		require.Len(t, errs, 3)
+		require.Equal(t, hostnamePolicyIR(), ir)
+	})
+
+	t.Run("sanitize", func(t *testing.T) {
+		ir := hostnamePolicyIR()
+		require.Empty(t, applyHostnamePolicy("test", &ir, SanitizeHostnamePolicy))
+		require.Equal(t, []gatewayv1.Listener{
+			httpListener("foo-example-com-http", "foo.example.com"),
+			httpListener("foo-bar-example-com-http", "foo-bar.example.com"),
+		}, ir.Gateways[types.NamespacedName{Namespace: "default", Name: "nginx"}].Spec.Listeners)
+		require.Equal(t, []gatewayv1.Hostname{"foo.example.com", "foo-bar.example.com"}, ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo"}].Spec.Hostnames)
+		require.Equal(t, []gatewayv1.Hostname{"foo-bar.example.com"}, ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-bar"}].Spec.Hostnames)
+	})
+
+	for _, policy := range []HostnamePolicy{SkipHostnamePolicy, ""} {
+		t.Run(fmt.Sprintf("skip %q", policy), func(t *testing.T) {
+			ir := hostnamePolicyIR()
+			require.Empty(t, applyHostnamePolicy("test", &ir, policy))
+			require.Equal(t, []gatewayv1.Listener{
+				httpListener("foo-example-com-http", "foo.example.com"),
+			}, ir.Gateways[types.NamespacedName{Namespace: "default", Name: "nginx"}].Spec.Listeners)
+			require.Equal(t, []gatewayv1.Hostname{"foo.example.com"}, ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo"}].Spec.Hostnames)
+			require.NotContains(t, ir.HTTPRoutes, types.NamespacedName{Namespace: "default", Name: "foo-bar"})
+		})
+	}
+}
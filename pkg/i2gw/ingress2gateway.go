@@ -24,66 +24,389 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 )
 
 const GeneratorAnnotationKey = "gateway.networking.k8s.io/generator"
 
 var CurrentVersion = "0.3.0"
 
-func ToGatewayAPIResources(ctx context.Context, namespace string, inputFile string, providers []string, providerSpecificFlags map[string]map[string]string) ([]GatewayResources, map[string]string, error) {
+// ConversionOptions contains the options of a single conversion run.
+type ConversionOptions struct {
+	// KubeContext is the name of the kubeconfig context used to read resources
+	// from the cluster. An empty value means the current context.
+	KubeContext string
+
+	// CertificateAuthority, when set, is the path of the CA bundle the
+	// certificate of the API server is verified with, instead of the one of
+	// the kubeconfig.
+	CertificateAuthority string
+
+	// Impersonate, when set, is the user the cluster is read as.
+	Impersonate string
+
+	// ImpersonateGroups are the groups the cluster is read as. They require
+	// Impersonate.
+	ImpersonateGroups []string
+
+	// Namespace is the namespace resources are read from. An empty value
+	// means all namespaces.
+	Namespace string
+
+	// InputFile is the path of the manifest file resources are read from. When
+	// empty, resources are read from the cluster.
+	InputFile string
+
+	// Providers is the list of providers used for the conversion.
+	Providers []string
+
+	// ProviderSpecificFlags holds the provider-specific flags by provider name.
+	ProviderSpecificFlags map[string]map[string]string
+
+	// Mesh indicates whether routes should be generated for GAMMA mesh
+	// implementations, i.e. attached to Services instead of Gateways.
+	Mesh bool
+
+	// ClusterDomain is the domain of the cluster-local Service hostnames,
+	// "<service>.<namespace>.svc.<ClusterDomain>". An empty value means
+	// cluster.local.
+	ClusterDomain string
+
+	// Profile is the name of the conversion Profile. An empty value means the
+	// BalancedProfile.
+	Profile ProfileName
+
+	// ProviderPriority lists the providers taking precedence, in order, when
+	// several providers claim the same Ingress.
+	ProviderPriority []string
+
+	// HostConflictPriority lists the providers taking precedence, in order,
+	// when the routes of several providers serve the same hostname. An empty
+	// value means the conflicts are only reported.
+	HostConflictPriority []string
+
+	// GatewayStrategy is the strategy used to generate Gateways. An empty
+	// value means the MergedGatewayStrategy.
+	GatewayStrategy GatewayStrategy
+
+	// ListenerStrategy is the strategy used to assign the hostnames of the
+	// Gateways to their listeners. An empty value means the
+	// PerHostListenerStrategy.
+	ListenerStrategy ListenerStrategy
+
+	// AnnotateSources indicates whether the source resources should be
+	// returned annotated with the summary of their conversion, so they can be
+	// applied back for discoverability. It is mutually exclusive with Redact.
+	AnnotateSources bool
+
+	// EnabledFeatures, when set, lists the features of the providers to
+	// convert, as `<feature>` or `<provider>/<feature>`: the providers it
+	// names features of don't convert their other features.
+	EnabledFeatures []string
+
+	// DisabledFeatures lists the features of the providers not to convert,
+	// as `<feature>` or `<provider>/<feature>`, taking precedence over
+	// EnabledFeatures.
+	DisabledFeatures []string
+
+	// CertManagerCertificates indicates whether cert-manager Certificates
+	// should be generated for the TLS listeners of the Gateways annotated with
+	// a cert-manager issuer, instead of relying on the Gateway API support of
+	// cert-manager.
+	CertManagerCertificates bool
+
+	// HostnamePolicy is the policy applied to the hostnames of the sources
+	// Gateway API rejects. An empty value means the SkipHostnamePolicy.
+	HostnamePolicy HostnamePolicy
+
+	// BackendTLSWellKnownCACertificates, when set, is used to validate the
+	// generated BackendTLSPolicies of the backends the sources indicate TLS
+	// to, but which CA certificates are not referenced.
+	BackendTLSWellKnownCACertificates gatewayv1alpha3.WellKnownCACertificatesType
+
+	// Emitter is the name of the Emitter extending the generated resources
+	// with implementation-specific resources. An empty value means only
+	// Gateway API resources are generated.
+	Emitter EmitterName
+
+	// Redact indicates whether the names of the Secrets and the credentials
+	// should be masked in the generated resources and in the notifications,
+	// including the ones dispatched to the sinks during the conversion.
+	Redact bool
+
+	// NoRouteMerge indicates whether each source resource should yield its own
+	// routes, even when their hosts overlap. It overrides the RouteMerging of
+	// the Profile.
+	NoRouteMerge bool
+
+	// CentralGatewayNamespace, when set, is the namespace of the Gateways
+	// shared by the routes of all the namespaces, replacing the Gateways
+	// generated in the namespaces of the source resources.
+	CentralGatewayNamespace string
+
+	// GatewayClassMapping maps IngressClasses to the GatewayClasses serving
+	// them, sharding the generated Gateways by GatewayClass. The Gateways of
+	// the IngressClasses it doesn't map keep their class.
+	GatewayClassMapping map[string]string
+
+	// OverrideFile, when set, is the path of the Overrides forcing fields of
+	// the HTTPRoutes generated from given source resources.
+	OverrideFile string
+
+	// ServiceMappingFile, when set, is the path of the file mapping the
+	// Services referenced by the generated backends to the Services they are
+	// renamed or moved to.
+	ServiceMappingFile string
+
+	// LintFor, when set, is the Gateway API implementation whose known
+	// incompatibilities the generated HTTPRoutes are checked against.
+	LintFor LintTarget
+
+	// CompatStripUnknown indicates whether the fields of the generated
+	// resources unknown to the CustomResourceDefinitions installed in the
+	// cluster should be removed, for clusters lagging behind the Gateway API
+	// version of the conversion.
+	CompatStripUnknown bool
+
+	// GatewayAPIVersion is the Gateway API version the generated resources
+	// conform to, enabling the fields of later versions than the one
+	// ingress2gateway is built against. An empty value means the
+	// DefaultGatewayAPIVersion.
+	GatewayAPIVersion string
+}
+
+// ToGatewayAPIResources reads the resources of the configured providers, from
+// the input file or from the cluster, and converts them to Gateway API resources.
+// The notification tables of the result are set even if the conversion fails.
+func ToGatewayAPIResources(ctx context.Context, opts ConversionOptions) (ConversionResult, error) {
 	var clusterClient client.Client
 
-	if inputFile == "" {
-		conf, err := config.GetConfig()
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get client config: %w", err)
-		}
+	// Redacted sources can't be applied back without breaking them.
+	if opts.Redact && opts.AnnotateSources {
+		return ConversionResult{}, fmt.Errorf("redacting the resources and annotating the sources are mutually exclusive")
+	}
+
+	if opts.Redact && !notifications.NotificationAggr.Redact {
+		notifications.NotificationAggr.Redact = true
+		defer func() { notifications.NotificationAggr.Redact = false }()
+	}
 
-		cl, err := client.New(conf, client.Options{})
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create client: %w", err)
+	profile, err := GetProfile(opts.Profile)
+	if err != nil {
+		return ConversionResult{}, err
+	}
+	if opts.NoRouteMerge {
+		profile.RouteMerging = false
+	}
+	if err = validateGatewayStrategy(opts.GatewayStrategy); err != nil {
+		return ConversionResult{}, err
+	}
+	if err = validateListenerStrategy(opts.ListenerStrategy); err != nil {
+		return ConversionResult{}, err
+	}
+	if err = validateHostnamePolicy(opts.HostnamePolicy); err != nil {
+		return ConversionResult{}, err
+	}
+	if err = validateCentralGatewayNamespace(opts.CentralGatewayNamespace, opts.GatewayStrategy); err != nil {
+		return ConversionResult{}, err
+	}
+	if err = validateWellKnownCACertificates(opts.BackendTLSWellKnownCACertificates); err != nil {
+		return ConversionResult{}, err
+	}
+	if err = validateGatewayClassMapping(opts.GatewayClassMapping); err != nil {
+		return ConversionResult{}, err
+	}
+	if err = validateLintTarget(opts.LintFor); err != nil {
+		return ConversionResult{}, err
+	}
+	gatewayAPIVersion, err := parseGatewayAPIVersion(opts.GatewayAPIVersion)
+	if err != nil {
+		return ConversionResult{}, err
+	}
+	overrides, err := readOverrides(opts.OverrideFile)
+	if err != nil {
+		return ConversionResult{}, err
+	}
+	serviceMapping, err := readServiceMapping(opts.ServiceMappingFile)
+	if err != nil {
+		return ConversionResult{}, err
+	}
+	ingressClaimer, err := NewIngressClaimer(opts.Providers, opts.ProviderPriority)
+	if err != nil {
+		return ConversionResult{}, err
+	}
+	if err = validateHostConflictPriority(opts.Providers, opts.HostConflictPriority); err != nil {
+		return ConversionResult{}, err
+	}
+	featureToggles, err := newFeatureToggles(opts.EnabledFeatures, opts.DisabledFeatures)
+	if err != nil {
+		return ConversionResult{}, err
+	}
+	emitter, err := constructEmitter(opts.Emitter)
+	if err != nil {
+		return ConversionResult{}, err
+	}
+
+	if opts.InputFile == "" {
+		if clusterClient, err = newClusterClient(opts); err != nil {
+			return ConversionResult{}, err
+		}
+	}
+	var gatewayClasses map[string]gatewayv1.GatewayClass
+	if clusterClient != nil {
+		if gatewayClasses, err = readGatewayClasses(ctx, opts); err != nil {
+			return ConversionResult{}, err
+		}
+	}
+	var schemas crdSchemas
+	if opts.CompatStripUnknown {
+		if schemas, err = readCRDSchemas(ctx, opts); err != nil {
+			return ConversionResult{}, err
 		}
-		clusterClient = client.NewNamespacedClient(cl, namespace)
 	}
 
 	providerByName, err := constructProviders(&ProviderConf{
 		Client:                clusterClient,
-		Namespace:             namespace,
-		ProviderSpecificFlags: providerSpecificFlags,
-	}, providers)
+		Namespace:             opts.Namespace,
+		ProviderSpecificFlags: opts.ProviderSpecificFlags,
+		Mesh:                  opts.Mesh,
+		ClusterDomain:         opts.ClusterDomain,
+		Profile:               profile,
+		IngressClaimer:        ingressClaimer,
+
+		BackendTLSWellKnownCACertificates: opts.BackendTLSWellKnownCACertificates,
+		FeatureToggles:                    featureToggles,
+	}, opts.Providers)
 	if err != nil {
-		return nil, nil, err
+		return ConversionResult{}, err
 	}
 
-	if inputFile != "" {
-		if err = readProviderResourcesFromFile(ctx, providerByName, inputFile); err != nil {
-			return nil, nil, err
+	if opts.InputFile != "" {
+		if err = readProviderResourcesFromFile(ctx, providerByName, opts.InputFile); err != nil {
+			return ConversionResult{}, err
 		}
 	} else {
 		if err = readProviderResourcesFromCluster(ctx, providerByName); err != nil {
-			return nil, nil, err
+			return ConversionResult{}, err
 		}
 	}
 
 	var (
-		gatewayResources []GatewayResources
-		errs             field.ErrorList
+		gatewayResourcesByProvider = map[ProviderName]*GatewayResources{}
+		hostClaimsByProvider       = map[ProviderName]hostClaims{}
+		errs                       field.ErrorList
 	)
-	for _, provider := range providerByName {
+	for name, provider := range providerByName {
 		ir, conversionErrs := provider.ToIR()
 		errs = append(errs, conversionErrs...)
+		errs = append(errs, applyHostnamePolicy(name, &ir, opts.HostnamePolicy)...)
+		mapGatewayClasses(name, &ir, opts.GatewayClassMapping)
+		if opts.GatewayStrategy == PerSourceGatewayStrategy {
+			splitGatewaysBySource(name, &ir)
+		}
+		if opts.CentralGatewayNamespace != "" {
+			centralizeGateways(name, &ir, opts.CentralGatewayNamespace)
+		}
+		groupListeners(name, &ir, opts.ListenerStrategy)
+		applyOverrides(name, overrides, &ir)
+		applyServiceMapping(name, serviceMapping, &ir)
 		providerGatewayResources, conversionErrs := provider.ToGatewayResources(ir)
 		errs = append(errs, conversionErrs...)
-		gatewayResources = append(gatewayResources, providerGatewayResources)
+		// The emitters map the IR rule indices to the consolidated rules.
+		consolidateFilters(name, &providerGatewayResources)
+		if emitter != nil {
+			errs = append(errs, emitter.Emit(ir, &providerGatewayResources)...)
+		}
+		orderRulesByPriority(name, ir, &providerGatewayResources)
+		if opts.CertManagerCertificates {
+			generateCertManagerCertificates(name, &providerGatewayResources)
+		}
+		if !profile.ExperimentalFeatures {
+			removeExperimentalResources(name, &providerGatewayResources)
+		}
+		addMirrorFractions(name, ir, &providerGatewayResources, gatewayAPIVersion)
+		validateListeners(name, &providerGatewayResources)
+		lintHTTPRoutes(name, &providerGatewayResources, opts.LintFor)
+		if gatewayClasses != nil {
+			checkGatewayClasses(name, &providerGatewayResources, gatewayClasses)
+		}
+		checkRouteTuples(name, ir, &providerGatewayResources, serviceMapping)
+		providerGatewayResources.UnsupportedFeatures = unsupportedFeaturesByRoute(ir)
+		if opts.AnnotateSources {
+			providerGatewayResources.AnnotatedSources, err = annotateSources(name, ir)
+			if err != nil {
+				return ConversionResult{}, err
+			}
+		}
+		hostClaimsByProvider[name] = routeHostClaims(ir, &providerGatewayResources)
+		gatewayResourcesByProvider[name] = &providerGatewayResources
+	}
+	errs = append(errs, featureToggles.unknownFeatures()...)
+	resolveHostConflicts(hostClaimsByProvider, gatewayResourcesByProvider, opts.HostConflictPriority)
+	for _, name := range sortedProviderNames(gatewayResourcesByProvider) {
+		errs = append(errs, enforceListCaps(name, gatewayResourcesByProvider[name])...)
+	}
+	if opts.CompatStripUnknown {
+		for name, providerGatewayResources := range gatewayResourcesByProvider {
+			stripUnknownFields(name, schemas, providerGatewayResources)
+		}
+	}
+	if opts.Redact {
+		for _, providerGatewayResources := range gatewayResourcesByProvider {
+			RedactGatewayResources([]GatewayResources{*providerGatewayResources})
+		}
 	}
+
 	notificationTablesMap := notifications.NotificationAggr.CreateNotificationTables()
 	if len(errs) > 0 {
-		return nil, notificationTablesMap, aggregatedErrs(errs)
+		return ConversionResult{NotificationTables: notificationTablesMap}, aggregatedErrs(errs)
 	}
 
-	return gatewayResources, notificationTablesMap, nil
+	return newConversionResult(gatewayResourcesByProvider, notificationTablesMap), nil
+}
+
+// newClusterClient returns a client of the cluster of the kubeconfig context
+// of the options, reading the resources of their namespace, or of all the
+// namespaces if empty.
+func newClusterClient(opts ConversionOptions) (client.Client, error) {
+	conf, err := clusterConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cl, err := client.New(conf, client.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	return client.NewNamespacedClient(cl, opts.Namespace), nil
+}
+
+// clusterConfig returns the client config of the kubeconfig context of the
+// options, with their CA bundle and impersonation. As with kubectl, the API
+// server is reached through the proxy of the kubeconfig, or else of the
+// HTTPS_PROXY environment variable, unless excluded by NO_PROXY.
+func clusterConfig(opts ConversionOptions) (*rest.Config, error) {
+	if len(opts.ImpersonateGroups) > 0 && opts.Impersonate == "" {
+		return nil, fmt.Errorf("impersonating groups %v requires impersonating a user", opts.ImpersonateGroups)
+	}
+
+	conf, err := config.GetConfigWithContext(opts.KubeContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client config: %w", err)
+	}
+	if opts.CertificateAuthority != "" {
+		conf.TLSClientConfig.CAFile = opts.CertificateAuthority
+		conf.TLSClientConfig.CAData = nil
+		conf.TLSClientConfig.Insecure = false
+	}
+	if opts.Impersonate != "" {
+		conf.Impersonate = rest.ImpersonationConfig{UserName: opts.Impersonate, Groups: opts.ImpersonateGroups}
+	}
+	return conf, nil
 }
 
 func readProviderResourcesFromFile(ctx context.Context, providerByName map[ProviderName]Provider, inputFile string) error {
@@ -17,7 +17,11 @@ limitations under the License.
 package i2gw
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -74,6 +78,14 @@ func Test_constructProviders(t *testing.T) {
 	}
 }
 
+func Test_ToGatewayAPIResources_redactAnnotateSources(t *testing.T) {
+	// The annotated sources aren't redacted, they would leak the credentials.
+	_, err := ToGatewayAPIResources(context.Background(), ConversionOptions{Redact: true, AnnotateSources: true})
+	if err == nil {
+		t.Errorf("Expected redacting the resources and annotating the sources to be rejected")
+	}
+}
+
 func Test_GetSupportedProviders(t *testing.T) {
 	supportProviders := []string{"ingress-nginx"}
 	for _, provider := range supportProviders {
@@ -93,3 +105,47 @@ func Test_GetSupportedProviders(t *testing.T) {
 		}
 	})
 }
+
+func Test_clusterConfig(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+    insecure-skip-tls-verify: true
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+users:
+- name: test
+  user:
+    token: test
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", kubeconfig)
+
+	conf, err := clusterConfig(ConversionOptions{
+		CertificateAuthority: "ca.crt",
+		Impersonate:          "jane",
+		ImpersonateGroups:    []string{"developers"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if conf.TLSClientConfig.CAFile != "ca.crt" || conf.TLSClientConfig.Insecure {
+		t.Errorf("Expected the certificate authority to be verified with ca.crt, got %+v", conf.TLSClientConfig)
+	}
+	if conf.Impersonate.UserName != "jane" || !slices.Equal(conf.Impersonate.Groups, []string{"developers"}) {
+		t.Errorf("Expected to impersonate jane of group developers, got %+v", conf.Impersonate)
+	}
+
+	if _, err = clusterConfig(ConversionOptions{ImpersonateGroups: []string{"developers"}}); err == nil {
+		t.Errorf("Expected an error impersonating groups without user")
+	}
+}
@@ -18,8 +18,10 @@ package intermediate
 
 import (
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
@@ -33,11 +35,15 @@ type IR struct {
 	Services   map[types.NamespacedName]ProviderSpecificServiceIR
 
 	GatewayClasses map[types.NamespacedName]gatewayv1.GatewayClass
+	GRPCRoutes     map[types.NamespacedName]gatewayv1.GRPCRoute
 	TLSRoutes      map[types.NamespacedName]gatewayv1alpha2.TLSRoute
 	TCPRoutes      map[types.NamespacedName]gatewayv1alpha2.TCPRoute
 	UDPRoutes      map[types.NamespacedName]gatewayv1alpha2.UDPRoute
 
 	ReferenceGrants map[types.NamespacedName]gatewayv1beta1.ReferenceGrant
+
+	BackendTLSPolicies map[types.NamespacedName]gatewayv1alpha3.BackendTLSPolicy
+	BackendLBPolicies  map[types.NamespacedName]gatewayv1alpha2.BackendLBPolicy
 }
 
 // GatewayContext contains the Gateway-API Gateway object and GatewayIR, which
@@ -51,13 +57,20 @@ type GatewayContext struct {
 }
 
 type ProviderSpecificGatewayIR struct {
+	Ako          *AkoGatewayIR
 	Apisix       *ApisixGatewayIR
 	Cilium       *CiliumGatewayIR
+	F5           *F5GatewayIR
 	Gce          *GceGatewayIR
 	IngressNginx *IngressNginxGatewayIR
 	Istio        *IstioGatewayIR
 	Kong         *KongGatewayIR
+	Netscaler    *NetscalerGatewayIR
 	Openapi3     *Openapi3GatewayIR
+	Openshift    *OpenshiftGatewayIR
+	Skipper      *SkipperGatewayIR
+	Traefik      *TraefikGatewayIR
+	Voyager      *VoyagerGatewayIR
 }
 
 // HTTPRouteContext contains the Gateway-API HTTPRoute object and HTTPRouteIR,
@@ -68,26 +81,100 @@ type ProviderSpecificGatewayIR struct {
 type HTTPRouteContext struct {
 	gatewayv1.HTTPRoute
 	ProviderSpecificIR ProviderSpecificHTTPRouteIR
+
+	// Sources contains the resources the HTTPRoute was generated from.
+	Sources []client.Object
+
+	// UnsupportedFeatures contains the features of the sources without
+	// Gateway API equivalent, to be ported manually.
+	UnsupportedFeatures []UnsupportedFeature
+
+	// RulePriorities contains the priority of the rules, by index, for the
+	// providers evaluating the routes of a host in a configured order. The
+	// rules of the generated HTTPRoute are ordered by descending priority
+	// once emitted, the rules without priority having 0. Gateway API only
+	// honours that order among the rules of equal match precedence.
+	RulePriorities map[int]int32
+
+	// DirectResponses contains the fixed responses of the rules, by index,
+	// for the rules the proxy responds to without forwarding the requests.
+	// Gateway API core lacking direct responses, these rules have no
+	// backendRefs, so that they are answered with a 500 status code unless
+	// the emitter generates their direct responses. The indices are those of
+	// the rules of the HTTPRoute, which the emitters resolve before the
+	// rules are ordered or split to fit the list caps of Gateway API.
+	DirectResponses map[int]DirectResponse
+
+	// MirrorFractions contains the fractions of the requests the
+	// RequestMirror filters of the rules mirror, by index of the rule and of
+	// the filter. The filters without fraction mirror all the requests.
+	MirrorFractions map[int]map[int]MirrorFraction
+}
+
+// DirectResponse is a fixed response returned by the proxy.
+type DirectResponse struct {
+	// StatusCode is the status code of the response.
+	StatusCode int32
+	// Body is the body of the response, if any.
+	Body string
+	// ContentType is the media type of the body, the default of the proxy if
+	// empty.
+	ContentType string
+}
+
+// MirrorFraction is the fraction, Numerator/Denominator, of the requests a
+// RequestMirror filter mirrors.
+type MirrorFraction struct {
+	Numerator   int32
+	Denominator int32
+}
+
+// UnsupportedFeature is a feature of a source resource without Gateway API
+// equivalent. Its raw configuration is kept so that it can be ported manually
+// with full context.
+type UnsupportedFeature struct {
+	// SourceKind is the kind of the resource the feature is configured on.
+	SourceKind string
+	// Source is the resource the feature is configured on.
+	Source types.NamespacedName
+	// Name identifies the feature in the source, e.g. an annotation key.
+	Name string
+	// RawConfig is the configuration of the feature in the source.
+	RawConfig string
 }
 
 type ProviderSpecificHTTPRouteIR struct {
+	Ako          *AkoHTTPRouteIR
 	Apisix       *ApisixHTTPRouteIR
 	Cilium       *CiliumHTTPRouteIR
+	F5           *F5HTTPRouteIR
 	Gce          *GceHTTPRouteIR
 	IngressNginx *IngressNginxHTTPRouteIR
 	Istio        *IstioHTTPRouteIR
 	Kong         *KongHTTPRouteIR
+	Netscaler    *NetscalerHTTPRouteIR
 	Openapi3     *Openapi3HTTPRouteIR
+	Openshift    *OpenshiftHTTPRouteIR
+	Skipper      *SkipperHTTPRouteIR
+	Traefik      *TraefikHTTPRouteIR
+	Voyager      *VoyagerHTTPRouteIR
 }
 
 // ServiceIR contains a dedicated field for each provider to specify their
 // extension features on Service.
 type ProviderSpecificServiceIR struct {
+	Ako          *AkoServiceIR
 	Apisix       *ApisixServiceIR
 	Cilium       *CiliumServiceIR
+	F5           *F5ServiceIR
 	Gce          *GceServiceIR
 	IngressNginx *IngressNginxServiceIR
 	Istio        *IstioServiceIR
 	Kong         *KongServiceIR
+	Netscaler    *NetscalerServiceIR
 	Openapi3     *Openapi3ServiceIR
+	Openshift    *OpenshiftServiceIR
+	Skipper      *SkipperServiceIR
+	Traefik      *TraefikServiceIR
+	Voyager      *VoyagerServiceIR
 }
@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package intermediate
+
+type AkoGatewayIR struct{}
+type AkoHTTPRouteIR struct {
+	// LoadBalancerPolicies are the load balancer policies of the paths of
+	// the HTTPRoute, set by AKO HTTPRules.
+	LoadBalancerPolicies []AkoLoadBalancerPolicy
+}
+type AkoServiceIR struct{}
+
+// AkoLoadBalancerPolicy is how the Avi load balancer distributes the requests
+// of HTTPRoute rules among the endpoints of their backends.
+type AkoLoadBalancerPolicy struct {
+	// RuleIndices are the indices of the HTTPRoute rules the policy applies
+	// to.
+	RuleIndices []int
+	// Algorithm is the load balancing algorithm, e.g.
+	// LB_ALGORITHM_LEAST_CONNECTIONS.
+	Algorithm string
+	// Hash is the hash of the LB_ALGORITHM_CONSISTENT_HASH algorithm, e.g.
+	// LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP_ADDRESS.
+	Hash string
+	// HostHeader is the header hashed by the
+	// LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER hash.
+	HostHeader string
+}
@@ -16,6 +16,53 @@ limitations under the License.
 
 package intermediate
 
-type ApisixGatewayIR struct{}
-type ApisixHTTPRouteIR struct{}
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type ApisixGatewayIR struct {
+	// GlobalPlugins are the plugins of the ApisixGlobalRules, which APISIX
+	// runs for all the routes.
+	GlobalPlugins []ApisixPlugin
+}
+
+type ApisixHTTPRouteIR struct {
+	// Plugins are the plugins of the ApisixPluginConfigs referenced by the
+	// sources of the HTTPRoute, which APISIX runs for its requests.
+	Plugins []ApisixPlugin
+}
+
 type ApisixServiceIR struct{}
+
+// ApisixPlugin is an enabled APISIX plugin, with its configuration.
+type ApisixPlugin struct {
+	// Name is the name of the plugin, e.g. proxy-rewrite.
+	Name string
+	// Config is the JSON configuration of the plugin.
+	Config string
+	// Source is the ApisixPluginConfig or ApisixGlobalRule the plugin is
+	// configured in.
+	Source types.NamespacedName
+}
+
+func mergeApisixGatewayIR(current, existing *ApisixGatewayIR) *ApisixGatewayIR {
+	// If either ApisixGatewayIR is nil, return the other one as the merged
+	// result.
+	if current == nil {
+		return existing
+	}
+	if existing == nil {
+		return current
+	}
+
+	// The global rules apply to all the Gateways, keep their plugins once.
+	mergedGatewayIR := ApisixGatewayIR{GlobalPlugins: current.GlobalPlugins}
+	for _, plugin := range existing.GlobalPlugins {
+		if !slices.Contains(mergedGatewayIR.GlobalPlugins, plugin) {
+			mergedGatewayIR.GlobalPlugins = append(mergedGatewayIR.GlobalPlugins, plugin)
+		}
+	}
+	return &mergedGatewayIR
+}
@@ -18,7 +18,10 @@ package intermediate
 
 type GceGatewayIR struct {
 	EnableHTTPSRedirect bool
-	SslPolicy           *SslPolicyConfig
+	// HTTPSRedirectStatusCode is the status code of the HTTPS redirect, 301
+	// unless the FrontendConfig sets another responseCodeName.
+	HTTPSRedirectStatusCode int
+	SslPolicy               *SslPolicyConfig
 }
 type SslPolicyConfig struct {
 	Name string
@@ -28,6 +31,12 @@ type GceServiceIR struct {
 	SessionAffinity *SessionAffinityConfig
 	SecurityPolicy  *SecurityPolicyConfig
 	HealthCheck     *HealthCheckConfig
+
+	TimeoutSec         *int64
+	ConnectionDraining *ConnectionDrainingConfig
+	Logging            *LoggingConfig
+	IAP                *IAPConfig
+	CDN                *CDNConfig
 }
 type SessionAffinityConfig struct {
 	AffinityType string
@@ -36,6 +45,27 @@ type SessionAffinityConfig struct {
 type SecurityPolicyConfig struct {
 	Name string
 }
+type ConnectionDrainingConfig struct {
+	DrainingTimeoutSec int64
+}
+type LoggingConfig struct {
+	Enable bool
+	// SampleRate is the proportion of requests to log, in the range [0, 1].
+	SampleRate *float64
+}
+type IAPConfig struct {
+	Enabled bool
+	// ClientID is the OAuth client ID, set when the BackendConfig references
+	// it directly rather than through the Secret.
+	ClientID string
+	// SecretName is the name of the Secret holding the OAuth client
+	// credentials, in the client_id and client_secret keys.
+	SecretName string
+}
+type CDNConfig struct {
+	Enabled   bool
+	CacheMode *string
+}
 type HealthCheckConfig struct {
 	CheckIntervalSec   *int64
 	TimeoutSec         *int64
@@ -58,5 +88,13 @@ func mergeGceGatewayIR(current, existing *GceGatewayIR) *GceGatewayIR {
 	// If both GceGatewayIRs are not nil, merge their fields.
 	var mergedGatewayIR GceGatewayIR
 	mergedGatewayIR.EnableHTTPSRedirect = current.EnableHTTPSRedirect || existing.EnableHTTPSRedirect
+	mergedGatewayIR.HTTPSRedirectStatusCode = current.HTTPSRedirectStatusCode
+	if mergedGatewayIR.HTTPSRedirectStatusCode == 0 {
+		mergedGatewayIR.HTTPSRedirectStatusCode = existing.HTTPSRedirectStatusCode
+	}
+	mergedGatewayIR.SslPolicy = current.SslPolicy
+	if mergedGatewayIR.SslPolicy == nil {
+		mergedGatewayIR.SslPolicy = existing.SslPolicy
+	}
 	return &mergedGatewayIR
 }
@@ -16,6 +16,234 @@ limitations under the License.
 
 package intermediate
 
+import (
+	"time"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
 type IngressNginxGatewayIR struct{}
-type IngressNginxHTTPRouteIR struct{}
-type IngressNginxServiceIR struct{}
+type IngressNginxHTTPRouteIR struct {
+	// Policies holds the ingress-nginx policies by the name of the source Ingress.
+	Policies map[string]IngressNginxPolicy
+}
+type IngressNginxServiceIR struct {
+	// ExternalName, when set, is the external host the Service resolves to,
+	// for the Services generated as the backends of targets outside of the
+	// cluster, e.g. mirror targets.
+	ExternalName *ExternalNameService
+}
+
+// ExternalNameService is a Service of type ExternalName generated for a
+// backend outside of the cluster.
+type ExternalNameService struct {
+	// Host is the DNS name the Service resolves to.
+	Host string
+	// Port is the port of the Service.
+	Port int32
+}
+
+// IngressNginxPolicy holds the configuration of a single Ingress, set with
+// ingress-nginx annotations, that has no Gateway API core equivalent.
+type IngressNginxPolicy struct {
+	// RuleIndices are the indices of the HTTPRoute rules generated from the
+	// paths of the Ingress.
+	RuleIndices []int
+
+	ProxyRedirect *ProxyRedirectConfig
+	RateLimit     *RateLimitConfig
+	BasicAuth     *BasicAuthConfig
+	ExternalAuth  *ExternalAuthConfig
+	Buffering     *BufferingConfig
+	SourceRange   *SourceRangeConfig
+
+	UpstreamConnection *UpstreamConnectionConfig
+	Affinity           *AffinityConfig
+	CustomHTTPErrors   *CustomHTTPErrorsConfig
+
+	// AuthSatisfy is how BasicAuth and ExternalAuth combine when the requests
+	// are authenticated with both. The BasicAuth is checked first, as it
+	// doesn't involve a call to an external service.
+	AuthSatisfy AuthSatisfy
+}
+
+// AuthSatisfy is how the authentications of a policy combine.
+type AuthSatisfy string
+
+const (
+	// AuthSatisfyAll requires the requests to pass all the authentications.
+	AuthSatisfyAll AuthSatisfy = "all"
+	// AuthSatisfyAny requires the requests to pass any of the
+	// authentications.
+	AuthSatisfyAny AuthSatisfy = "any"
+)
+
+// MultipleAuth returns whether the requests are authenticated with both
+// BasicAuth and ExternalAuth, so that AuthSatisfy applies.
+func (p IngressNginxPolicy) MultipleAuth() bool {
+	return p.BasicAuth != nil && p.ExternalAuth != nil
+}
+
+// ProxyRedirectConfig configures the rewriting of the Location and Refresh
+// response headers sent by the backends.
+type ProxyRedirectConfig struct {
+	// From is the text to be replaced, or "default" to replace the backend
+	// address with the request host.
+	From string
+	// To is the replacement text.
+	To string
+}
+
+// RateLimitConfig limits the rate of the requests of a client.
+type RateLimitConfig struct {
+	// Requests is the number of requests allowed per Period.
+	Requests int32
+	// Period is the period of time Requests are allowed in.
+	Period time.Duration
+	// Burst is the number of requests allowed in excess of the rate.
+	Burst int32
+}
+
+// BasicAuthConfig authenticates the requests with the HTTP Basic
+// authentication scheme.
+type BasicAuthConfig struct {
+	// SecretName is the name of the Secret holding the credentials, in the
+	// namespace of the source.
+	SecretName string
+	// SecretType is the format of the credentials in the Secret, either
+	// "auth-file", for an htpasswd file under the "auth" key, or "auth-map",
+	// for one key per user.
+	SecretType string
+	// Realm is the authentication realm.
+	Realm string
+}
+
+// ExternalAuthConfig authenticates the requests with an external service,
+// called for each request. The requests are allowed if the service responds
+// with a 2xx status code.
+type ExternalAuthConfig struct {
+	// URL is the URL of the external service.
+	URL string
+	// Method is the HTTP method of the calls to the external service, the
+	// method of the request if empty.
+	Method string
+	// SigninURL is the URL the clients are redirected to when the external
+	// service responds with a 401 status code.
+	SigninURL string
+	// ResponseHeaders are the headers of the external service responses
+	// copied to the requests sent to the backends.
+	ResponseHeaders []string
+}
+
+// SourceRangeConfig restricts the client addresses allowed to send requests.
+// Denied addresses are rejected even when they are in an allowed range.
+type SourceRangeConfig struct {
+	// Allow are the CIDRs of the only client addresses allowed, all of them if
+	// empty.
+	Allow []string
+	// Deny are the CIDRs of the client addresses denied.
+	Deny []string
+}
+
+// BufferingConfig configures the buffering of the request bodies.
+type BufferingConfig struct {
+	// MaxRequestBodyBytes is the maximum size of the request bodies, with 0
+	// meaning unlimited.
+	MaxRequestBodyBytes *int64
+	// MemRequestBodyBytes is the size of the request bodies buffered in
+	// memory before being buffered to disk.
+	MemRequestBodyBytes *int64
+}
+
+// UpstreamConnectionConfig configures the connections to the backends.
+type UpstreamConnectionConfig struct {
+	// HTTPVersion is the HTTP version of the requests sent to the backends,
+	// empty for the default HTTP/1.1.
+	HTTPVersion HTTPVersion
+	// KeepaliveConnections is the maximum number of idle keepalive
+	// connections to the backends cached by each worker.
+	KeepaliveConnections *int32
+	// KeepaliveRequests is the maximum number of requests sent through a
+	// keepalive connection.
+	KeepaliveRequests *int32
+	// KeepaliveTime is the maximum lifetime of a keepalive connection.
+	KeepaliveTime *time.Duration
+	// KeepaliveTimeout is the time an idle keepalive connection stays open.
+	KeepaliveTimeout *time.Duration
+}
+
+// HTTPVersion is an HTTP version of the requests sent to the backends.
+type HTTPVersion string
+
+const (
+	HTTPVersion10 HTTPVersion = "1.0"
+	HTTPVersion11 HTTPVersion = "1.1"
+	// HTTPVersion2 is the HTTP version of the gRPC backends.
+	HTTPVersion2 HTTPVersion = "2"
+)
+
+// CustomHTTPErrorsConfig replaces the responses of the backends with the
+// given status codes by the response of an error backend, which receives the
+// original status code in the X-Code header and the original URI in the
+// X-Original-URI header.
+type CustomHTTPErrorsConfig struct {
+	// Codes are the status codes of the replaced responses.
+	Codes []int32
+	// Backend is the backend serving the error responses, the default
+	// backend of the Ingress or of the controller, or nil if the default
+	// backend of the controller is unknown. Its namespace is only set if it
+	// isn't the namespace of the Ingress.
+	Backend *gatewayv1.BackendObjectReference
+}
+
+// AffinityConfig pins the requests of a client to the same endpoint of the
+// backends, either with a cookie or by consistent hashing.
+type AffinityConfig struct {
+	// Cookie, when set, pins the clients with a cookie.
+	Cookie *AffinityCookieConfig
+	// HashBy, when set and Cookie isn't, is the nginx expression the
+	// endpoints are chosen by consistent hashing of, e.g. $binary_remote_addr
+	// or $http_x_user.
+	HashBy string
+}
+
+// AffinityMode is how the sessions pinned by cookie are assigned to the
+// endpoints when the backends scale.
+type AffinityMode string
+
+const (
+	// AffinityModeBalanced rebalances some of the sessions to the new
+	// endpoints.
+	AffinityModeBalanced AffinityMode = "balanced"
+	// AffinityModePersistent keeps the sessions on their endpoint.
+	AffinityModePersistent AffinityMode = "persistent"
+)
+
+// AffinityCookieConfig configures the cookie pinning the clients to an
+// endpoint.
+type AffinityCookieConfig struct {
+	// Name is the name of the cookie.
+	Name string
+	// Path is the path of the cookie, the path of the Ingress if empty.
+	Path string
+	// Domain is the domain of the cookie, the host of the request if empty.
+	Domain string
+	// Expires and MaxAge, when set, are the lifetime of the cookie, which
+	// otherwise lasts for the browser session.
+	Expires *time.Duration
+	MaxAge  *time.Duration
+	// SameSite is the SameSite attribute of the cookie: None, Lax, Strict or
+	// empty.
+	SameSite string
+	// ConditionalSameSiteNone omits SameSite=None for the clients known not
+	// to support it.
+	ConditionalSameSiteNone bool
+	// Secure sets the Secure attribute of the cookie.
+	Secure bool
+	// ChangeOnFailure pins the client to another endpoint when its endpoint
+	// fails, instead of retrying it on the next request.
+	ChangeOnFailure bool
+	// Mode is how the sessions are assigned to the endpoints when the
+	// backends scale.
+	Mode AffinityMode
+}
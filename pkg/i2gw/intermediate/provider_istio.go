@@ -16,6 +16,41 @@ limitations under the License.
 
 package intermediate
 
+import "time"
+
 type IstioGatewayIR struct{}
-type IstioHTTPRouteIR struct{}
+type IstioHTTPRouteIR struct {
+	// FaultInjection is the fault injected in the requests of all the rules
+	// of the HTTPRoute.
+	FaultInjection *FaultInjectionConfig
+}
 type IstioServiceIR struct{}
+
+// FaultInjectionConfig injects faults in a percentage of the requests, to
+// test the resiliency of the clients.
+type FaultInjectionConfig struct {
+	Delay *FaultDelay
+	Abort *FaultAbort
+}
+
+// FaultDelay delays the requests before forwarding them to the backends.
+type FaultDelay struct {
+	// FixedDelay is the time the requests are delayed for.
+	FixedDelay time.Duration
+	// Percentage is the percentage of the requests delayed, between 0 and
+	// 100.
+	Percentage float64
+}
+
+// FaultAbort responds to the requests with an error instead of forwarding
+// them to the backends.
+type FaultAbort struct {
+	// HTTPStatus is the HTTP status code of the responses, if set.
+	HTTPStatus int32
+	// GRPCStatus is the name of the gRPC status of the responses, e.g.
+	// UNAVAILABLE, if set.
+	GRPCStatus string
+	// Percentage is the percentage of the requests aborted, between 0 and
+	// 100.
+	Percentage float64
+}
@@ -16,6 +16,51 @@ limitations under the License.
 
 package intermediate
 
+import "time"
+
 type KongGatewayIR struct{}
-type KongHTTPRouteIR struct{}
+type KongHTTPRouteIR struct {
+	// Policies holds the plugins of the HTTPRoute without Gateway API core
+	// equivalent, in the order they are referenced.
+	Policies []KongPolicy
+}
 type KongServiceIR struct{}
+
+// KongPolicy holds the configuration of a KongPlugin, or KongClusterPlugin,
+// referenced by HTTPRoute rules, which has no Gateway API core equivalent.
+type KongPolicy struct {
+	// Plugin is the name of the KongPlugin, in the namespace of the HTTPRoute,
+	// or of the KongClusterPlugin when ClusterScoped is true.
+	Plugin        string
+	ClusterScoped bool
+	// RuleIndices are the indices of the HTTPRoute rules referencing the
+	// plugin.
+	RuleIndices []int
+
+	RateLimit *KongRateLimitConfig
+	CORS      *KongCORSConfig
+}
+
+// KongRateLimitConfig limits the rate of the requests, as the rate-limiting
+// plugin.
+type KongRateLimitConfig struct {
+	// Requests is the number of requests allowed per Period.
+	Requests int64
+	// Period is the period of time Requests are allowed in.
+	Period time.Duration
+	// LimitBy is the entity the requests are counted by, e.g. "consumer" or
+	// "ip".
+	LimitBy string
+}
+
+// KongCORSConfig configures the Cross-Origin Resource Sharing, as the cors
+// plugin.
+type KongCORSConfig struct {
+	Origins        []string
+	Methods        []string
+	Headers        []string
+	ExposedHeaders []string
+	Credentials    bool
+	// MaxAge is how long the results of the preflight requests can be cached.
+	MaxAge *time.Duration
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package intermediate
+
+type SkipperGatewayIR struct{}
+type SkipperHTTPRouteIR struct {
+	// Policies holds the Skipper policies by the name of the source Ingress.
+	Policies map[string]SkipperPolicy
+}
+type SkipperServiceIR struct{}
+
+// SkipperPolicy holds the configuration of a single Ingress, set with Skipper
+// filters, that has no Gateway API core equivalent.
+type SkipperPolicy struct {
+	// RuleIndices are the indices of the HTTPRoute rules generated from the
+	// paths of the Ingress.
+	RuleIndices []int
+
+	// RateLimit limits the rate of the requests of all the clients, per
+	// proxy instance.
+	RateLimit *RateLimitConfig
+	// ClientRateLimit limits the rate of the requests of each client.
+	ClientRateLimit *RateLimitConfig
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package intermediate
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// IRVersion is the version of the schema of the serialized IR, recorded under
+// the irVersion field of the serialized IRs. It must be bumped whenever a
+// change of the IR structs changes the serialized IR, with the migration from
+// the previous version registered in migrations, so that the IRs serialized by
+// previous releases, and the external emitters consuming them, keep working.
+const IRVersion = "v1alpha1"
+
+// migration migrates a serialized IR from a version of the schema to the next
+// one.
+type migration struct {
+	// to is the version the IR is migrated to.
+	to string
+	// migrate migrates the decoded JSON of the IR in place, but for its
+	// irVersion field.
+	migrate func(ir map[string]interface{}) error
+}
+
+// migrations holds the migration of the serialized IR from each previous
+// version of the schema, by version.
+var migrations = map[string]migration{}
+
+// serializedIR is the serialized form of the IR. The maps keyed by
+// NamespacedName, which JSON can't encode, are serialized as lists of entries.
+type serializedIR struct {
+	IRVersion string `json:"irVersion"`
+
+	Gateways   []entry[serializedGatewayContext]   `json:"gateways,omitempty"`
+	HTTPRoutes []entry[serializedHTTPRouteContext] `json:"httpRoutes,omitempty"`
+	Services   []entry[ProviderSpecificServiceIR]  `json:"services,omitempty"`
+
+	GatewayClasses []entry[gatewayv1.GatewayClass]   `json:"gatewayClasses,omitempty"`
+	GRPCRoutes     []entry[gatewayv1.GRPCRoute]      `json:"grpcRoutes,omitempty"`
+	TLSRoutes      []entry[gatewayv1alpha2.TLSRoute] `json:"tlsRoutes,omitempty"`
+	TCPRoutes      []entry[gatewayv1alpha2.TCPRoute] `json:"tcpRoutes,omitempty"`
+	UDPRoutes      []entry[gatewayv1alpha2.UDPRoute] `json:"udpRoutes,omitempty"`
+
+	ReferenceGrants []entry[gatewayv1beta1.ReferenceGrant] `json:"referenceGrants,omitempty"`
+
+	BackendTLSPolicies []entry[gatewayv1alpha3.BackendTLSPolicy] `json:"backendTLSPolicies,omitempty"`
+	BackendLBPolicies  []entry[gatewayv1alpha2.BackendLBPolicy]  `json:"backendLBPolicies,omitempty"`
+}
+
+// entry is an entry of a map of the IR keyed by NamespacedName.
+type entry[V any] struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Value     V      `json:"value"`
+}
+
+type serializedGatewayContext struct {
+	Gateway            gatewayv1.Gateway         `json:"gateway"`
+	ProviderSpecificIR ProviderSpecificGatewayIR `json:"providerSpecificIR"`
+}
+
+type serializedHTTPRouteContext struct {
+	HTTPRoute          gatewayv1.HTTPRoute         `json:"httpRoute"`
+	ProviderSpecificIR ProviderSpecificHTTPRouteIR `json:"providerSpecificIR"`
+	// Sources are only serialized as references, deserialized as
+	// unstructured objects holding their kind, namespace and name.
+	Sources             []sourceReference              `json:"sources,omitempty"`
+	UnsupportedFeatures []UnsupportedFeature           `json:"unsupportedFeatures,omitempty"`
+	RulePriorities      map[int]int32                  `json:"rulePriorities,omitempty"`
+	DirectResponses     map[int]DirectResponse         `json:"directResponses,omitempty"`
+	MirrorFractions     map[int]map[int]MirrorFraction `json:"mirrorFractions,omitempty"`
+}
+
+type sourceReference struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// Marshal serializes the IR as JSON, with its IRVersion, e.g. for it to be
+// consumed by external emitters.
+func Marshal(ir IR) ([]byte, error) {
+	serialized := serializedIR{
+		IRVersion:          IRVersion,
+		Services:           toEntries(ir.Services, identity[ProviderSpecificServiceIR]),
+		GatewayClasses:     toEntries(ir.GatewayClasses, identity[gatewayv1.GatewayClass]),
+		GRPCRoutes:         toEntries(ir.GRPCRoutes, identity[gatewayv1.GRPCRoute]),
+		TLSRoutes:          toEntries(ir.TLSRoutes, identity[gatewayv1alpha2.TLSRoute]),
+		TCPRoutes:          toEntries(ir.TCPRoutes, identity[gatewayv1alpha2.TCPRoute]),
+		UDPRoutes:          toEntries(ir.UDPRoutes, identity[gatewayv1alpha2.UDPRoute]),
+		ReferenceGrants:    toEntries(ir.ReferenceGrants, identity[gatewayv1beta1.ReferenceGrant]),
+		BackendTLSPolicies: toEntries(ir.BackendTLSPolicies, identity[gatewayv1alpha3.BackendTLSPolicy]),
+		BackendLBPolicies:  toEntries(ir.BackendLBPolicies, identity[gatewayv1alpha2.BackendLBPolicy]),
+	}
+	serialized.Gateways = toEntries(ir.Gateways, func(gatewayContext GatewayContext) serializedGatewayContext {
+		return serializedGatewayContext{Gateway: gatewayContext.Gateway, ProviderSpecificIR: gatewayContext.ProviderSpecificIR}
+	})
+	serialized.HTTPRoutes = toEntries(ir.HTTPRoutes, func(httpRouteContext HTTPRouteContext) serializedHTTPRouteContext {
+		var sources []sourceReference
+		for _, source := range httpRouteContext.Sources {
+			apiVersion, kind := source.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+			sources = append(sources, sourceReference{APIVersion: apiVersion, Kind: kind, Namespace: source.GetNamespace(), Name: source.GetName()})
+		}
+		return serializedHTTPRouteContext{
+			HTTPRoute:           httpRouteContext.HTTPRoute,
+			ProviderSpecificIR:  httpRouteContext.ProviderSpecificIR,
+			Sources:             sources,
+			UnsupportedFeatures: httpRouteContext.UnsupportedFeatures,
+			RulePriorities:      httpRouteContext.RulePriorities,
+			DirectResponses:     httpRouteContext.DirectResponses,
+			MirrorFractions:     httpRouteContext.MirrorFractions,
+		}
+	})
+	return json.Marshal(serialized)
+}
+
+// Unmarshal deserializes an IR serialized by Marshal, migrating it from the
+// version of the schema it was serialized with to the IRVersion.
+func Unmarshal(data []byte) (IR, error) {
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return IR{}, fmt.Errorf("failed to decode the IR: %w", err)
+	}
+	version, _ := decoded["irVersion"].(string)
+	if version == "" {
+		return IR{}, fmt.Errorf("the IR has no irVersion")
+	}
+	for version != IRVersion {
+		m, ok := migrations[version]
+		if !ok {
+			return IR{}, fmt.Errorf("unsupported IR version %s, supported versions are %v", version, supportedIRVersions())
+		}
+		if err := m.migrate(decoded); err != nil {
+			return IR{}, fmt.Errorf("failed to migrate the IR from %s to %s: %w", version, m.to, err)
+		}
+		version = m.to
+	}
+	decoded["irVersion"] = IRVersion
+
+	migrated, err := json.Marshal(decoded)
+	if err != nil {
+		return IR{}, err
+	}
+	var serialized serializedIR
+	if err = json.Unmarshal(migrated, &serialized); err != nil {
+		return IR{}, fmt.Errorf("failed to decode the IR: %w", err)
+	}
+
+	ir := IR{
+		Services:           fromEntries(serialized.Services, identity[ProviderSpecificServiceIR]),
+		GatewayClasses:     fromEntries(serialized.GatewayClasses, identity[gatewayv1.GatewayClass]),
+		GRPCRoutes:         fromEntries(serialized.GRPCRoutes, identity[gatewayv1.GRPCRoute]),
+		TLSRoutes:          fromEntries(serialized.TLSRoutes, identity[gatewayv1alpha2.TLSRoute]),
+		TCPRoutes:          fromEntries(serialized.TCPRoutes, identity[gatewayv1alpha2.TCPRoute]),
+		UDPRoutes:          fromEntries(serialized.UDPRoutes, identity[gatewayv1alpha2.UDPRoute]),
+		ReferenceGrants:    fromEntries(serialized.ReferenceGrants, identity[gatewayv1beta1.ReferenceGrant]),
+		BackendTLSPolicies: fromEntries(serialized.BackendTLSPolicies, identity[gatewayv1alpha3.BackendTLSPolicy]),
+		BackendLBPolicies:  fromEntries(serialized.BackendLBPolicies, identity[gatewayv1alpha2.BackendLBPolicy]),
+	}
+	ir.Gateways = fromEntries(serialized.Gateways, func(gatewayContext serializedGatewayContext) GatewayContext {
+		return GatewayContext{Gateway: gatewayContext.Gateway, ProviderSpecificIR: gatewayContext.ProviderSpecificIR}
+	})
+	ir.HTTPRoutes = fromEntries(serialized.HTTPRoutes, func(httpRouteContext serializedHTTPRouteContext) HTTPRouteContext {
+		var sources []client.Object
+		for _, reference := range httpRouteContext.Sources {
+			source := &unstructured.Unstructured{}
+			source.SetAPIVersion(reference.APIVersion)
+			source.SetKind(reference.Kind)
+			source.SetNamespace(reference.Namespace)
+			source.SetName(reference.Name)
+			sources = append(sources, source)
+		}
+		return HTTPRouteContext{
+			HTTPRoute:           httpRouteContext.HTTPRoute,
+			ProviderSpecificIR:  httpRouteContext.ProviderSpecificIR,
+			Sources:             sources,
+			UnsupportedFeatures: httpRouteContext.UnsupportedFeatures,
+			RulePriorities:      httpRouteContext.RulePriorities,
+			DirectResponses:     httpRouteContext.DirectResponses,
+			MirrorFractions:     httpRouteContext.MirrorFractions,
+		}
+	})
+	return ir, nil
+}
+
+// supportedIRVersions returns the sorted versions of the serialized IRs
+// Unmarshal supports.
+func supportedIRVersions() []string {
+	versions := []string{IRVersion}
+	for version := range migrations {
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+func identity[V any](v V) V {
+	return v
+}
+
+// toEntries returns the entries of the map, sorted by key, for the serialized
+// IR to be stable.
+func toEntries[V, S any](m map[types.NamespacedName]V, serialize func(V) S) []entry[S] {
+	entries := make([]entry[S], 0, len(m))
+	for key, value := range m {
+		entries = append(entries, entry[S]{Namespace: key.Namespace, Name: key.Name, Value: serialize(value)})
+	}
+	slices.SortFunc(entries, func(a, b entry[S]) int {
+		if c := strings.Compare(a.Namespace, b.Namespace); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return entries
+}
+
+func fromEntries[S, V any](entries []entry[S], deserialize func(S) V) map[types.NamespacedName]V {
+	m := make(map[types.NamespacedName]V, len(entries))
+	for _, e := range entries {
+		m[types.NamespacedName{Namespace: e.Namespace, Name: e.Name}] = deserialize(e.Value)
+	}
+	return m
+}
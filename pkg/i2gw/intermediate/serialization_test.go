@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package intermediate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func testIR() IR {
+	ingress := &networkingv1.Ingress{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "example"},
+	}
+	return IR{
+		Gateways: map[types.NamespacedName]GatewayContext{
+			{Namespace: "default", Name: "nginx"}: {
+				Gateway: gatewayv1.Gateway{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+					Spec: gatewayv1.GatewaySpec{
+						GatewayClassName: "nginx",
+						Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+					},
+				},
+			},
+		},
+		HTTPRoutes: map[types.NamespacedName]HTTPRouteContext{
+			{Namespace: "default", Name: "example"}: {
+				HTTPRoute: gatewayv1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "example"},
+				},
+				ProviderSpecificIR: ProviderSpecificHTTPRouteIR{
+					Istio: &IstioHTTPRouteIR{},
+				},
+				Sources: []client.Object{ingress},
+				UnsupportedFeatures: []UnsupportedFeature{{
+					SourceKind: "Ingress",
+					Source:     types.NamespacedName{Namespace: "default", Name: "example"},
+					Name:       "nginx.ingress.kubernetes.io/server-snippet",
+					RawConfig:  "return 200;",
+				}},
+				RulePriorities:  map[int]int32{0: 10},
+				DirectResponses: map[int]DirectResponse{0: {StatusCode: 503, Body: "maintenance", ContentType: "text/plain"}},
+				MirrorFractions: map[int]map[int]MirrorFraction{1: {0: {Numerator: 125, Denominator: 1000}}},
+			},
+		},
+		Services: map[types.NamespacedName]ProviderSpecificServiceIR{
+			{Namespace: "default", Name: "backend"}: {
+				IngressNginx: &IngressNginxServiceIR{},
+			},
+		},
+	}
+}
+
+func Test_MarshalUnmarshal(t *testing.T) {
+	ir := testIR()
+	data, err := Marshal(ir)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"irVersion":"`+IRVersion+`"`)
+
+	got, err := Unmarshal(data)
+	require.NoError(t, err)
+
+	source := &unstructured.Unstructured{}
+	source.SetAPIVersion("networking.k8s.io/v1")
+	source.SetKind("Ingress")
+	source.SetNamespace("default")
+	source.SetName("example")
+	httpRouteContext := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "example"}]
+	httpRouteContext.Sources = []client.Object{source}
+	ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "example"}] = httpRouteContext
+
+	require.Equal(t, ir.Gateways, got.Gateways)
+	require.Equal(t, ir.HTTPRoutes, got.HTTPRoutes)
+	require.Equal(t, ir.Services, got.Services)
+	require.Empty(t, got.TCPRoutes)
+}
+
+func Test_Unmarshal_versions(t *testing.T) {
+	_, err := Unmarshal([]byte(`{"gateways":[]}`))
+	require.EqualError(t, err, "the IR has no irVersion")
+
+	_, err = Unmarshal([]byte(`{"irVersion":"v9"}`))
+	require.EqualError(t, err, "unsupported IR version v9, supported versions are ["+IRVersion+"]")
+
+	// An IR of a previous version, with the Gateways under another key, is
+	// migrated to the current version.
+	migrations["v0"] = migration{
+		to: IRVersion,
+		migrate: func(ir map[string]interface{}) error {
+			ir["gateways"] = ir["gatewayList"]
+			delete(ir, "gatewayList")
+			return nil
+		},
+	}
+	defer delete(migrations, "v0")
+
+	got, err := Unmarshal([]byte(`{"irVersion":"v0","gatewayList":[{"namespace":"default","name":"nginx","value":{"gateway":{"spec":{"gatewayClassName":"nginx","listeners":null}}}}]}`))
+	require.NoError(t, err)
+	require.Equal(t, gatewayv1.ObjectName("nginx"), got.Gateways[types.NamespacedName{Namespace: "default", Name: "nginx"}].Spec.GatewayClassName)
+
+	_, err = Unmarshal([]byte(`{"irVersion":"v8"}`))
+	require.EqualError(t, err, "unsupported IR version v8, supported versions are [v0 "+IRVersion+"]")
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
@@ -42,10 +43,14 @@ func MergeIRs(irs ...IR) (IR, field.ErrorList) {
 		GatewayClasses:  make(map[types.NamespacedName]gatewayv1.GatewayClass),
 		HTTPRoutes:      make(map[types.NamespacedName]HTTPRouteContext),
 		Services:        make(map[types.NamespacedName]ProviderSpecificServiceIR),
+		GRPCRoutes:      make(map[types.NamespacedName]gatewayv1.GRPCRoute),
 		TLSRoutes:       make(map[types.NamespacedName]gatewayv1alpha2.TLSRoute),
 		TCPRoutes:       make(map[types.NamespacedName]gatewayv1alpha2.TCPRoute),
 		UDPRoutes:       make(map[types.NamespacedName]gatewayv1alpha2.UDPRoute),
 		ReferenceGrants: make(map[types.NamespacedName]gatewayv1beta1.ReferenceGrant),
+
+		BackendTLSPolicies: make(map[types.NamespacedName]gatewayv1alpha3.BackendTLSPolicy),
+		BackendLBPolicies:  make(map[types.NamespacedName]gatewayv1alpha2.BackendLBPolicy),
 	}
 	var errs field.ErrorList
 	mergedIRs.Gateways, errs = mergeGatewayContexts(irs)
@@ -57,10 +62,13 @@ func MergeIRs(irs ...IR) (IR, field.ErrorList) {
 		maps.Copy(mergedIRs.GatewayClasses, gr.GatewayClasses)
 		maps.Copy(mergedIRs.HTTPRoutes, gr.HTTPRoutes)
 		maps.Copy(mergedIRs.Services, gr.Services)
+		maps.Copy(mergedIRs.GRPCRoutes, gr.GRPCRoutes)
 		maps.Copy(mergedIRs.TLSRoutes, gr.TLSRoutes)
 		maps.Copy(mergedIRs.TCPRoutes, gr.TCPRoutes)
 		maps.Copy(mergedIRs.UDPRoutes, gr.UDPRoutes)
 		maps.Copy(mergedIRs.ReferenceGrants, gr.ReferenceGrants)
+		maps.Copy(mergedIRs.BackendTLSPolicies, gr.BackendTLSPolicies)
+		maps.Copy(mergedIRs.BackendLBPolicies, gr.BackendLBPolicies)
 	}
 	return mergedIRs, errs
 }
@@ -77,7 +85,7 @@ func mergeGatewayContexts(irs []IR) (map[types.NamespacedName]GatewayContext, fi
 				g.Gateway.Spec.Addresses = append(g.Gateway.Spec.Addresses, existingGatewayContext.Gateway.Spec.Addresses...)
 				g.ProviderSpecificIR = mergedGatewayIR(g.ProviderSpecificIR, existingGatewayContext.ProviderSpecificIR)
 			}
-			newGatewayContexts[nn] = GatewayContext{Gateway: g.Gateway}
+			newGatewayContexts[nn] = g
 			// 64 is the maximum number of listeners a Gateway can have
 			if len(g.Spec.Listeners) > 64 {
 				fieldPath := field.NewPath(fmt.Sprintf("%s/%s", nn.Namespace, nn.Name)).Child("spec").Child("listeners")
@@ -97,6 +105,7 @@ func mergedGatewayIR(current, existing ProviderSpecificGatewayIR) ProviderSpecif
 	var mergedGatewayIR ProviderSpecificGatewayIR
 	// TODO(issue #190): Find a different way to merge GatewayIR, instead of
 	// delegating them to each provider.
+	mergedGatewayIR.Apisix = mergeApisixGatewayIR(current.Apisix, existing.Apisix)
 	mergedGatewayIR.Gce = mergeGceGatewayIR(current.Gce, existing.Gce)
 	return mergedGatewayIR
 }
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package intermediate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_MergeIRs_gatewayProviderSpecificIR(t *testing.T) {
+	key := types.NamespacedName{Namespace: "default", Name: "gce"}
+	gatewayContext := func(listener gatewayv1.SectionName, gceIR *GceGatewayIR) GatewayContext {
+		return GatewayContext{
+			Gateway: gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+				Spec: gatewayv1.GatewaySpec{
+					Listeners: []gatewayv1.Listener{{Name: listener}},
+				},
+			},
+			ProviderSpecificIR: ProviderSpecificGatewayIR{Gce: gceIR},
+		}
+	}
+
+	merged, errs := MergeIRs(
+		IR{Gateways: map[types.NamespacedName]GatewayContext{
+			key: gatewayContext("http", &GceGatewayIR{SslPolicy: &SslPolicyConfig{Name: "ssl-policy"}}),
+		}},
+		IR{Gateways: map[types.NamespacedName]GatewayContext{
+			key: gatewayContext("https", &GceGatewayIR{EnableHTTPSRedirect: true}),
+		}},
+	)
+	require.Empty(t, errs)
+	require.Len(t, merged.Gateways[key].Spec.Listeners, 2)
+	require.Equal(t, &GceGatewayIR{
+		EnableHTTPSRedirect: true,
+		SslPolicy:           &SslPolicyConfig{Name: "ssl-policy"},
+	}, merged.Gateways[key].ProviderSpecificIR.Gce)
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// LintTarget is the name of a Gateway API implementation whose known
+// incompatibilities the generated HTTPRoutes are checked against.
+type LintTarget string
+
+const (
+	// GKELintTarget checks the HTTPRoutes against the GKE Gateway controller.
+	GKELintTarget LintTarget = "gke"
+	// IstioLintTarget checks the HTTPRoutes against istio.
+	IstioLintTarget LintTarget = "istio"
+	// NGINXGatewayFabricLintTarget checks the HTTPRoutes against NGINX
+	// Gateway Fabric.
+	NGINXGatewayFabricLintTarget LintTarget = "nginx-gateway-fabric"
+)
+
+// lintRule returns the reasons the given HTTPRoute rule is incompatible with
+// an implementation, if any.
+type lintRule func(rule gatewayv1.HTTPRouteRule) []string
+
+// lintRuleSets are the rules of each LintTarget, reflecting the features the
+// implementations document as unsupported.
+var lintRuleSets = map[LintTarget][]lintRule{
+	GKELintTarget: {
+		noPathMatchType(gatewayv1.PathMatchRegularExpression),
+		noMethodMatches,
+		noTimeouts,
+		noExtensionRefFilters(""),
+	},
+	IstioLintTarget: {
+		noExtensionRefFilters(""),
+	},
+	NGINXGatewayFabricLintTarget: {
+		noPathMatchType(gatewayv1.PathMatchRegularExpression),
+		noHeaderMatchType(gatewayv1.HeaderMatchRegularExpression),
+		noQueryParamMatchType(gatewayv1.QueryParamMatchRegularExpression),
+		noFilterType(gatewayv1.HTTPRouteFilterRequestMirror),
+		noTimeouts,
+		noExtensionRefFilters("gateway.nginx.org"),
+	},
+}
+
+// GetSupportedLintTargets returns the names of all the supported lint targets.
+func GetSupportedLintTargets() []string {
+	targets := make([]string, 0, len(lintRuleSets))
+	for target := range lintRuleSets {
+		targets = append(targets, string(target))
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// validateLintTarget returns an error if the given target is not supported.
+// An empty target disables the linter.
+func validateLintTarget(target LintTarget) error {
+	if _, ok := lintRuleSets[target]; target != "" && !ok {
+		return fmt.Errorf("%s is not a supported lint target, supported values are %v", target, GetSupportedLintTargets())
+	}
+	return nil
+}
+
+// lintHTTPRoutes notifies about the rules of the generated HTTPRoutes relying
+// on features the implementation of the given target doesn't support.
+func lintHTTPRoutes(providerName ProviderName, gatewayResources *GatewayResources, target LintTarget) {
+	rules := lintRuleSets[target]
+	if len(rules) == 0 {
+		return
+	}
+	for _, key := range sortedNamespacedNames(gatewayResources.HTTPRoutes) {
+		httpRoute := gatewayResources.HTTPRoutes[key]
+		for _, message := range lintHTTPRoute(httpRoute, target, rules) {
+			notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification, message, &httpRoute), string(providerName))
+		}
+	}
+}
+
+// lintHTTPRoute returns a message for each incompatibility of the rules of
+// the HTTPRoute with the implementation of the target.
+func lintHTTPRoute(httpRoute gatewayv1.HTTPRoute, target LintTarget, rules []lintRule) []string {
+	var messages []string
+	for i, rule := range httpRoute.Spec.Rules {
+		for _, lint := range rules {
+			for _, reason := range lint(rule) {
+				messages = append(messages, fmt.Sprintf("rule %d of HTTPRoute %s/%s %s, which %s doesn't support", i, httpRoute.Namespace, httpRoute.Name, reason, target))
+			}
+		}
+	}
+	return messages
+}
+
+func noPathMatchType(matchType gatewayv1.PathMatchType) lintRule {
+	return func(rule gatewayv1.HTTPRouteRule) []string {
+		for _, match := range rule.Matches {
+			if match.Path != nil && match.Path.Type != nil && *match.Path.Type == matchType {
+				return []string{fmt.Sprintf("has %s path matches", matchType)}
+			}
+		}
+		return nil
+	}
+}
+
+func noHeaderMatchType(matchType gatewayv1.HeaderMatchType) lintRule {
+	return func(rule gatewayv1.HTTPRouteRule) []string {
+		for _, match := range rule.Matches {
+			for _, header := range match.Headers {
+				if header.Type != nil && *header.Type == matchType {
+					return []string{fmt.Sprintf("has %s header matches", matchType)}
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func noQueryParamMatchType(matchType gatewayv1.QueryParamMatchType) lintRule {
+	return func(rule gatewayv1.HTTPRouteRule) []string {
+		for _, match := range rule.Matches {
+			for _, queryParam := range match.QueryParams {
+				if queryParam.Type != nil && *queryParam.Type == matchType {
+					return []string{fmt.Sprintf("has %s query parameter matches", matchType)}
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func noMethodMatches(rule gatewayv1.HTTPRouteRule) []string {
+	for _, match := range rule.Matches {
+		if match.Method != nil {
+			return []string{"has method matches"}
+		}
+	}
+	return nil
+}
+
+func noTimeouts(rule gatewayv1.HTTPRouteRule) []string {
+	if rule.Timeouts != nil {
+		return []string{"has timeouts"}
+	}
+	return nil
+}
+
+func noFilterType(filterType gatewayv1.HTTPRouteFilterType) lintRule {
+	return func(rule gatewayv1.HTTPRouteRule) []string {
+		for _, filter := range ruleFilters(rule) {
+			if filter.Type == filterType {
+				return []string{fmt.Sprintf("has %s filters", filterType)}
+			}
+		}
+		return nil
+	}
+}
+
+// noExtensionRefFilters flags the ExtensionRef filters referencing resources
+// of other groups than the given one, if any.
+func noExtensionRefFilters(group gatewayv1.Group) lintRule {
+	return func(rule gatewayv1.HTTPRouteRule) []string {
+		var reasons []string
+		for _, filter := range ruleFilters(rule) {
+			if filter.Type == gatewayv1.HTTPRouteFilterExtensionRef && filter.ExtensionRef != nil && (group == "" || filter.ExtensionRef.Group != group) {
+				reasons = append(reasons, fmt.Sprintf("has an ExtensionRef filter referencing %s %s", filter.ExtensionRef.Kind, filter.ExtensionRef.Name))
+			}
+		}
+		return reasons
+	}
+}
+
+// ruleFilters returns the filters of the rule and of its backendRefs.
+func ruleFilters(rule gatewayv1.HTTPRouteRule) []gatewayv1.HTTPRouteFilter {
+	filters := append([]gatewayv1.HTTPRouteFilter(nil), rule.Filters...)
+	for _, backendRef := range rule.BackendRefs {
+		filters = append(filters, backendRef.Filters...)
+	}
+	return filters
+}
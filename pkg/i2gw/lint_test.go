@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_validateLintTarget(t *testing.T) {
+	require.NoError(t, validateLintTarget(""))
+	for _, target := range GetSupportedLintTargets() {
+		require.NoError(t, validateLintTarget(LintTarget(target)))
+	}
+	require.Error(t, validateLintTarget("unknown"))
+}
+
+func Test_lintHTTPRoute(t *testing.T) {
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "shop"},
+		Spec: gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{
+			{
+				Matches: []gatewayv1.HTTPRouteMatch{{
+					Path:   &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchRegularExpression), Value: ptr.To("/shop/[0-9]+")},
+					Method: ptr.To(gatewayv1.HTTPMethodGet),
+				}},
+			},
+			{
+				Matches: []gatewayv1.HTTPRouteMatch{{
+					Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/")},
+				}},
+				BackendRefs: []gatewayv1.HTTPBackendRef{{
+					Filters: []gatewayv1.HTTPRouteFilter{
+						{Type: gatewayv1.HTTPRouteFilterExtensionRef, ExtensionRef: &gatewayv1.LocalObjectReference{Group: "gateway.nginx.org", Kind: "SnippetsFilter", Name: "snippets"}},
+						{Type: gatewayv1.HTTPRouteFilterExtensionRef, ExtensionRef: &gatewayv1.LocalObjectReference{Group: "configuration.konghq.com", Kind: "KongPlugin", Name: "rate-limit"}},
+					},
+				}},
+				Timeouts: &gatewayv1.HTTPRouteTimeouts{Request: ptr.To[gatewayv1.Duration]("10s")},
+			},
+		}},
+	}
+
+	tests := []struct {
+		target LintTarget
+		want   []string
+	}{
+		{
+			target: GKELintTarget,
+			want: []string{
+				"rule 0 of HTTPRoute default/shop has RegularExpression path matches, which gke doesn't support",
+				"rule 0 of HTTPRoute default/shop has method matches, which gke doesn't support",
+				"rule 1 of HTTPRoute default/shop has timeouts, which gke doesn't support",
+				"rule 1 of HTTPRoute default/shop has an ExtensionRef filter referencing SnippetsFilter snippets, which gke doesn't support",
+				"rule 1 of HTTPRoute default/shop has an ExtensionRef filter referencing KongPlugin rate-limit, which gke doesn't support",
+			},
+		},
+		{
+			target: IstioLintTarget,
+			want: []string{
+				"rule 1 of HTTPRoute default/shop has an ExtensionRef filter referencing SnippetsFilter snippets, which istio doesn't support",
+				"rule 1 of HTTPRoute default/shop has an ExtensionRef filter referencing KongPlugin rate-limit, which istio doesn't support",
+			},
+		},
+		{
+			target: NGINXGatewayFabricLintTarget,
+			want: []string{
+				"rule 0 of HTTPRoute default/shop has RegularExpression path matches, which nginx-gateway-fabric doesn't support",
+				"rule 1 of HTTPRoute default/shop has timeouts, which nginx-gateway-fabric doesn't support",
+				"rule 1 of HTTPRoute default/shop has an ExtensionRef filter referencing KongPlugin rate-limit, which nginx-gateway-fabric doesn't support",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.target), func(t *testing.T) {
+			require.Equal(t, tt.want, lintHTTPRoute(httpRoute, tt.target, lintRuleSets[tt.target]))
+		})
+	}
+}
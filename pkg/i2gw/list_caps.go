@@ -0,0 +1,379 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// The maximum lengths of the lists of the Gateway API resources, enforced by
+// the validation of their CustomResourceDefinitions.
+const (
+	maxParentRefs          = 32
+	maxHostnames           = 16
+	maxRules               = 16
+	maxMatches             = 8
+	maxFilters             = 16
+	maxBackendRefs         = 16
+	maxMatchConditions     = 16
+	maxHeaderModifications = 16
+	maxListeners           = 64
+	maxAddresses           = 16
+	maxCertificateRefs     = 64
+	maxRouteKinds          = 8
+)
+
+// enforceListCaps makes the lists of the generated resources fit the maximum
+// lengths of Gateway API, for the resources not to be rejected by the
+// validation of the CustomResourceDefinitions:
+//   - The rules of the HTTPRoutes and GRPCRoutes with more matches than
+//     allowed are split into rules with the same filters and backends, each
+//     holding a part of the matches. The mirror fractions of the filters of
+//     a split rule are split along.
+//   - The HTTPRoutes and GRPCRoutes with more parentRefs, hostnames or rules
+//     than allowed are split into routes named `<route>-<n>`, each attached to
+//     a part of the parents, for a part of the hostnames, with a part of the
+//     rules. Gateway API orders the rules of equal match precedence of
+//     distinct routes by their age and name, so the split routes are warned
+//     about. The routes targeted by the policies of the emitter aren't split,
+//     as the policies wouldn't apply to the other parts.
+//
+// The other lists, whose splitting would change the routing, e.g. the
+// backendRefs a rule balances the requests between, fail the conversion.
+func enforceListCaps(providerName ProviderName, gatewayResources *GatewayResources) field.ErrorList {
+	var errs field.ErrorList
+	for _, key := range sortedNamespacedNames(gatewayResources.Gateways) {
+		errs = append(errs, checkGatewayListCaps(key, gatewayResources.Gateways[key])...)
+	}
+
+	for _, key := range sortedNamespacedNames(gatewayResources.HTTPRoutes) {
+		httpRoute := gatewayResources.HTTPRoutes[key]
+		fieldPath := field.NewPath("HTTPRoute").Key(key.String()).Child("spec")
+		var rules []gatewayv1.HTTPRouteRule
+		for i, rule := range httpRoute.Spec.Rules {
+			errs = append(errs, checkHTTPRouteRuleListCaps(rule, fieldPath.Child("rules").Index(i))...)
+			parts := listParts(len(rule.Matches), maxMatches)
+			for _, matches := range parts {
+				part := *rule.DeepCopy()
+				part.Matches = rule.Matches[matches[0]:matches[1]]
+				rules = append(rules, part)
+			}
+			if len(parts) > 1 && len(gatewayResources.MirrorFractions[key]) > 0 {
+				gatewayResources.MirrorFractions[key] = splitMirrorFractions(gatewayResources.MirrorFractions[key], rule.Matches, parts)
+			}
+		}
+		if len(rules) != len(httpRoute.Spec.Rules) {
+			notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.InfoNotification,
+				fmt.Sprintf("HTTPRoute %s: the rules with more than %d matches were split into rules of up to %d matches", key, maxMatches, maxMatches), &httpRoute), string(providerName))
+			httpRoute.Spec.Rules = rules
+			gatewayResources.HTTPRoutes[key] = httpRoute
+		}
+	}
+	errs = append(errs, splitRoutes(providerName, "HTTPRoute", gatewayResources, gatewayResources.HTTPRoutes, func(route gatewayv1.HTTPRoute, name string, part routePart) gatewayv1.HTTPRoute {
+		split := *route.DeepCopy()
+		split.Name = name
+		split.Spec.ParentRefs = route.Spec.ParentRefs[part.parentRefs[0]:part.parentRefs[1]]
+		split.Spec.Hostnames = route.Spec.Hostnames[part.hostnames[0]:part.hostnames[1]]
+		split.Spec.Rules = route.Spec.Rules[part.rules[0]:part.rules[1]]
+		return split
+	}, func(route gatewayv1.HTTPRoute) (int, int, int) {
+		return len(route.Spec.ParentRefs), len(route.Spec.Hostnames), len(route.Spec.Rules)
+	})...)
+
+	for _, key := range sortedNamespacedNames(gatewayResources.GRPCRoutes) {
+		grpcRoute := gatewayResources.GRPCRoutes[key]
+		fieldPath := field.NewPath("GRPCRoute").Key(key.String()).Child("spec")
+		var rules []gatewayv1.GRPCRouteRule
+		for i, rule := range grpcRoute.Spec.Rules {
+			errs = append(errs, checkGRPCRouteRuleListCaps(rule, fieldPath.Child("rules").Index(i))...)
+			for _, matches := range listParts(len(rule.Matches), maxMatches) {
+				part := *rule.DeepCopy()
+				part.Matches = rule.Matches[matches[0]:matches[1]]
+				rules = append(rules, part)
+			}
+		}
+		if len(rules) != len(grpcRoute.Spec.Rules) {
+			notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.InfoNotification,
+				fmt.Sprintf("GRPCRoute %s: the rules with more than %d matches were split into rules of up to %d matches", key, maxMatches, maxMatches), &grpcRoute), string(providerName))
+			grpcRoute.Spec.Rules = rules
+			gatewayResources.GRPCRoutes[key] = grpcRoute
+		}
+	}
+	errs = append(errs, splitRoutes(providerName, "GRPCRoute", gatewayResources, gatewayResources.GRPCRoutes, func(route gatewayv1.GRPCRoute, name string, part routePart) gatewayv1.GRPCRoute {
+		split := *route.DeepCopy()
+		split.Name = name
+		split.Spec.ParentRefs = route.Spec.ParentRefs[part.parentRefs[0]:part.parentRefs[1]]
+		split.Spec.Hostnames = route.Spec.Hostnames[part.hostnames[0]:part.hostnames[1]]
+		split.Spec.Rules = route.Spec.Rules[part.rules[0]:part.rules[1]]
+		return split
+	}, func(route gatewayv1.GRPCRoute) (int, int, int) {
+		return len(route.Spec.ParentRefs), len(route.Spec.Hostnames), len(route.Spec.Rules)
+	})...)
+
+	for _, key := range sortedNamespacedNames(gatewayResources.TLSRoutes) {
+		spec := gatewayResources.TLSRoutes[key].Spec
+		fieldPath := field.NewPath("TLSRoute").Key(key.String()).Child("spec")
+		errs = append(errs, checkListCap(fieldPath.Child("parentRefs"), len(spec.ParentRefs), maxParentRefs)...)
+		errs = append(errs, checkListCap(fieldPath.Child("hostnames"), len(spec.Hostnames), maxHostnames)...)
+		errs = append(errs, checkListCap(fieldPath.Child("rules"), len(spec.Rules), maxRules)...)
+		for i, rule := range spec.Rules {
+			errs = append(errs, checkListCap(fieldPath.Child("rules").Index(i).Child("backendRefs"), len(rule.BackendRefs), maxBackendRefs)...)
+		}
+	}
+	for _, key := range sortedNamespacedNames(gatewayResources.TCPRoutes) {
+		spec := gatewayResources.TCPRoutes[key].Spec
+		errs = append(errs, checkL4RouteListCaps(field.NewPath("TCPRoute").Key(key.String()).Child("spec"), spec.ParentRefs, spec.Rules)...)
+	}
+	for _, key := range sortedNamespacedNames(gatewayResources.UDPRoutes) {
+		spec := gatewayResources.UDPRoutes[key].Spec
+		var rules []gatewayv1alpha2.TCPRouteRule
+		for _, rule := range spec.Rules {
+			rules = append(rules, gatewayv1alpha2.TCPRouteRule{BackendRefs: rule.BackendRefs})
+		}
+		errs = append(errs, checkL4RouteListCaps(field.NewPath("UDPRoute").Key(key.String()).Child("spec"), spec.ParentRefs, rules)...)
+	}
+	return errs
+}
+
+// splitMirrorFractions returns the fractions with the ones of the rule of the
+// given matches replaced by a fraction per part of the matches, the fractions
+// identifying their rule by its matches.
+func splitMirrorFractions(fractions []MirrorFraction, matches []gatewayv1.HTTPRouteMatch, parts [][2]int) []MirrorFraction {
+	var split []MirrorFraction
+	for _, fraction := range fractions {
+		if !apiequality.Semantic.DeepEqual(fraction.Matches, matches) {
+			split = append(split, fraction)
+			continue
+		}
+		for _, part := range parts {
+			partFraction := fraction
+			partFraction.Matches = matches[part[0]:part[1]]
+			split = append(split, partFraction)
+		}
+	}
+	return split
+}
+
+// checkListCap returns an error if the length of the list at the given path
+// exceeds its maximum.
+func checkListCap(fieldPath *field.Path, length, maxLength int) field.ErrorList {
+	if length <= maxLength {
+		return nil
+	}
+	return field.ErrorList{field.TooMany(fieldPath, length, maxLength)}
+}
+
+// checkGatewayListCaps returns the errors of the lists of the Gateway
+// exceeding their maximum.
+func checkGatewayListCaps(key types.NamespacedName, gateway gatewayv1.Gateway) field.ErrorList {
+	fieldPath := field.NewPath("Gateway").Key(key.String()).Child("spec")
+	errs := checkListCap(fieldPath.Child("listeners"), len(gateway.Spec.Listeners), maxListeners)
+	errs = append(errs, checkListCap(fieldPath.Child("addresses"), len(gateway.Spec.Addresses), maxAddresses)...)
+	for i, listener := range gateway.Spec.Listeners {
+		listenerPath := fieldPath.Child("listeners").Index(i)
+		if listener.TLS != nil {
+			errs = append(errs, checkListCap(listenerPath.Child("tls", "certificateRefs"), len(listener.TLS.CertificateRefs), maxCertificateRefs)...)
+		}
+		if listener.AllowedRoutes != nil {
+			errs = append(errs, checkListCap(listenerPath.Child("allowedRoutes", "kinds"), len(listener.AllowedRoutes.Kinds), maxRouteKinds)...)
+		}
+	}
+	return errs
+}
+
+// checkHTTPRouteRuleListCaps returns the errors of the lists of the
+// HTTPRoute rule exceeding their maximum, but its matches.
+func checkHTTPRouteRuleListCaps(rule gatewayv1.HTTPRouteRule, fieldPath *field.Path) field.ErrorList {
+	errs := checkHTTPRouteFiltersListCaps(rule.Filters, fieldPath.Child("filters"))
+	errs = append(errs, checkListCap(fieldPath.Child("backendRefs"), len(rule.BackendRefs), maxBackendRefs)...)
+	for i, backendRef := range rule.BackendRefs {
+		errs = append(errs, checkHTTPRouteFiltersListCaps(backendRef.Filters, fieldPath.Child("backendRefs").Index(i).Child("filters"))...)
+	}
+	for i, match := range rule.Matches {
+		errs = append(errs, checkListCap(fieldPath.Child("matches").Index(i).Child("headers"), len(match.Headers), maxMatchConditions)...)
+		errs = append(errs, checkListCap(fieldPath.Child("matches").Index(i).Child("queryParams"), len(match.QueryParams), maxMatchConditions)...)
+	}
+	return errs
+}
+
+// checkHTTPRouteFiltersListCaps returns the errors of the HTTPRoute filters
+// exceeding their maximum, or of their header modifications.
+func checkHTTPRouteFiltersListCaps(filters []gatewayv1.HTTPRouteFilter, fieldPath *field.Path) field.ErrorList {
+	errs := checkListCap(fieldPath, len(filters), maxFilters)
+	for i, filter := range filters {
+		errs = append(errs, checkHeaderFilterListCaps(filter.RequestHeaderModifier, fieldPath.Index(i).Child("requestHeaderModifier"))...)
+		errs = append(errs, checkHeaderFilterListCaps(filter.ResponseHeaderModifier, fieldPath.Index(i).Child("responseHeaderModifier"))...)
+	}
+	return errs
+}
+
+// checkGRPCRouteRuleListCaps returns the errors of the lists of the
+// GRPCRoute rule exceeding their maximum, but its matches.
+func checkGRPCRouteRuleListCaps(rule gatewayv1.GRPCRouteRule, fieldPath *field.Path) field.ErrorList {
+	errs := checkGRPCRouteFiltersListCaps(rule.Filters, fieldPath.Child("filters"))
+	errs = append(errs, checkListCap(fieldPath.Child("backendRefs"), len(rule.BackendRefs), maxBackendRefs)...)
+	for i, backendRef := range rule.BackendRefs {
+		errs = append(errs, checkGRPCRouteFiltersListCaps(backendRef.Filters, fieldPath.Child("backendRefs").Index(i).Child("filters"))...)
+	}
+	for i, match := range rule.Matches {
+		errs = append(errs, checkListCap(fieldPath.Child("matches").Index(i).Child("headers"), len(match.Headers), maxMatchConditions)...)
+	}
+	return errs
+}
+
+// checkGRPCRouteFiltersListCaps returns the errors of the GRPCRoute filters
+// exceeding their maximum, or of their header modifications.
+func checkGRPCRouteFiltersListCaps(filters []gatewayv1.GRPCRouteFilter, fieldPath *field.Path) field.ErrorList {
+	errs := checkListCap(fieldPath, len(filters), maxFilters)
+	for i, filter := range filters {
+		errs = append(errs, checkHeaderFilterListCaps(filter.RequestHeaderModifier, fieldPath.Index(i).Child("requestHeaderModifier"))...)
+		errs = append(errs, checkHeaderFilterListCaps(filter.ResponseHeaderModifier, fieldPath.Index(i).Child("responseHeaderModifier"))...)
+	}
+	return errs
+}
+
+// checkHeaderFilterListCaps returns the errors of the header modifications
+// of the filter exceeding their maximum.
+func checkHeaderFilterListCaps(filter *gatewayv1.HTTPHeaderFilter, fieldPath *field.Path) field.ErrorList {
+	if filter == nil {
+		return nil
+	}
+	errs := checkListCap(fieldPath.Child("set"), len(filter.Set), maxHeaderModifications)
+	errs = append(errs, checkListCap(fieldPath.Child("add"), len(filter.Add), maxHeaderModifications)...)
+	return append(errs, checkListCap(fieldPath.Child("remove"), len(filter.Remove), maxHeaderModifications)...)
+}
+
+// checkL4RouteListCaps returns the errors of the lists of the TCPRoute or
+// UDPRoute exceeding their maximum.
+func checkL4RouteListCaps(fieldPath *field.Path, parentRefs []gatewayv1.ParentReference, rules []gatewayv1alpha2.TCPRouteRule) field.ErrorList {
+	errs := checkListCap(fieldPath.Child("parentRefs"), len(parentRefs), maxParentRefs)
+	errs = append(errs, checkListCap(fieldPath.Child("rules"), len(rules), maxRules)...)
+	for i, rule := range rules {
+		errs = append(errs, checkListCap(fieldPath.Child("rules").Index(i).Child("backendRefs"), len(rule.BackendRefs), maxBackendRefs)...)
+	}
+	return errs
+}
+
+// routePart is a part of a split route, holding the parentRefs, hostnames and
+// rules of the route in the given index ranges.
+type routePart struct {
+	parentRefs, hostnames, rules [2]int
+}
+
+// listParts returns the index ranges of the parts of a list of the given
+// length, of up to the given maximum length. An empty list has one empty
+// part.
+func listParts(length, maxLength int) [][2]int {
+	parts := [][2]int{{0, 0}}
+	for start := 0; start < length; start += maxLength {
+		parts[len(parts)-1] = [2]int{start, min(start+maxLength, length)}
+		if start+maxLength < length {
+			parts = append(parts, [2]int{})
+		}
+	}
+	return parts
+}
+
+// splitRoutes splits the routes of the given kind with more parentRefs,
+// hostnames or rules than allowed, with the function returning the part of a
+// route of the given name, and returns the errors of the routes which can't
+// be split. The first part keeps the name of the route.
+func splitRoutes[R any, PR interface {
+	*R
+	client.Object
+}](providerName ProviderName, kind string, gatewayResources *GatewayResources, routes map[types.NamespacedName]R, split func(R, string, routePart) R, lengths func(R) (int, int, int)) field.ErrorList {
+	var errs field.ErrorList
+	for _, key := range sortedNamespacedNames(routes) {
+		route := routes[key]
+		parentRefs, hostnames, rules := lengths(route)
+		var parts []routePart
+		for _, parentRefsPart := range listParts(parentRefs, maxParentRefs) {
+			for _, hostnamesPart := range listParts(hostnames, maxHostnames) {
+				for _, rulesPart := range listParts(rules, maxRules) {
+					parts = append(parts, routePart{parentRefs: parentRefsPart, hostnames: hostnamesPart, rules: rulesPart})
+				}
+			}
+		}
+		if len(parts) == 1 {
+			continue
+		}
+
+		fieldPath := field.NewPath(kind).Key(key.String()).Child("spec")
+		if isPolicyTarget(gatewayResources.GatewayExtensions, kind, key) {
+			errs = append(errs, checkListCap(fieldPath.Child("parentRefs"), parentRefs, maxParentRefs)...)
+			errs = append(errs, checkListCap(fieldPath.Child("hostnames"), hostnames, maxHostnames)...)
+			errs = append(errs, checkListCap(fieldPath.Child("rules"), rules, maxRules)...)
+			continue
+		}
+
+		names := []string{key.Name}
+		for i := 2; len(names) < len(parts); i++ {
+			name := fmt.Sprintf("%s-%d", key.Name, i)
+			if _, ok := routes[types.NamespacedName{Namespace: key.Namespace, Name: name}]; !ok {
+				names = append(names, name)
+			}
+		}
+		for i, part := range parts {
+			partKey := types.NamespacedName{Namespace: key.Namespace, Name: names[i]}
+			routes[partKey] = split(route, names[i], part)
+			if partKey == key {
+				continue
+			}
+			if features, ok := gatewayResources.UnsupportedFeatures[key]; ok {
+				gatewayResources.UnsupportedFeatures[partKey] = features
+			}
+			if fractions, ok := gatewayResources.MirrorFractions[key]; ok {
+				gatewayResources.MirrorFractions[partKey] = fractions
+			}
+		}
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification,
+			fmt.Sprintf("%s %s has more parentRefs, hostnames or rules than the %d, %d and %d Gateway API allows, it was split into %ss %s: the order of their rules of equal match precedence depends on their names",
+				kind, key, maxParentRefs, maxHostnames, maxRules, kind, strings.Join(names, ", ")), PR(&route)), string(providerName))
+	}
+	return errs
+}
+
+// isPolicyTarget returns whether a generated extension resource targets the
+// route of the given kind and key, with its targetRef or targetRefs.
+func isPolicyTarget(extensions []unstructured.Unstructured, kind string, key types.NamespacedName) bool {
+	for _, extension := range extensions {
+		if extension.GetNamespace() != key.Namespace {
+			continue
+		}
+		targetRefs, _, _ := unstructured.NestedSlice(extension.Object, "spec", "targetRefs")
+		if targetRef, ok, _ := unstructured.NestedMap(extension.Object, "spec", "targetRef"); ok {
+			targetRefs = append(targetRefs, targetRef)
+		}
+		for _, targetRef := range targetRefs {
+			targetRef, ok := targetRef.(map[string]interface{})
+			if ok && targetRef["kind"] == kind && targetRef["name"] == key.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_enforceListCaps(t *testing.T) {
+	match := func(i int) gatewayv1.HTTPRouteMatch {
+		return gatewayv1.HTTPRouteMatch{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To(fmt.Sprintf("/%d", i))}}
+	}
+	rule := func(first, last int) gatewayv1.HTTPRouteRule {
+		rule := gatewayv1.HTTPRouteRule{BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "backend"}}}}}
+		for i := first; i <= last; i++ {
+			rule.Matches = append(rule.Matches, match(i))
+		}
+		return rule
+	}
+	hostnames := func(first, last int) []gatewayv1.Hostname {
+		var hostnames []gatewayv1.Hostname
+		for i := first; i <= last; i++ {
+			hostnames = append(hostnames, gatewayv1.Hostname(fmt.Sprintf("host%d.example.com", i)))
+		}
+		return hostnames
+	}
+	rules := func(first, last int) []gatewayv1.HTTPRouteRule {
+		var rules []gatewayv1.HTTPRouteRule
+		for i := first; i <= last; i++ {
+			rules = append(rules, rule(i, i))
+		}
+		return rules
+	}
+	httpRoute := func(name string, hostnames []gatewayv1.Hostname, rules ...gatewayv1.HTTPRouteRule) gatewayv1.HTTPRoute {
+		return gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gateway"}}},
+				Hostnames:       hostnames,
+				Rules:           rules,
+			},
+		}
+	}
+	key := types.NamespacedName{Namespace: "default", Name: "route"}
+
+	testCases := []struct {
+		name                  string
+		gatewayResources      GatewayResources
+		expectedHTTPRoutes    map[types.NamespacedName]gatewayv1.HTTPRoute
+		expectedUnsupported   map[types.NamespacedName][]intermediate.UnsupportedFeature
+		expectedErrors        []string
+		expectedNotifications []string
+	}{
+		{
+			name: "matches split into rules",
+			gatewayResources: GatewayResources{HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+				key: httpRoute("route", nil, rule(1, 10)),
+			}},
+			expectedHTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+				key: httpRoute("route", nil, rule(1, 8), rule(9, 10)),
+			},
+			expectedNotifications: []string{"HTTPRoute default/route: the rules with more than 8 matches were split"},
+		},
+		{
+			name: "hostnames and rules split into routes",
+			gatewayResources: GatewayResources{
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+					key:                                     httpRoute("route", hostnames(1, 17), rules(1, 17)...),
+					{Namespace: "default", Name: "route-2"}: httpRoute("route-2", nil),
+				},
+				UnsupportedFeatures: map[types.NamespacedName][]intermediate.UnsupportedFeature{key: {{Name: "feature"}}},
+			},
+			expectedHTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+				key:                                     httpRoute("route", hostnames(1, 16), rules(1, 16)...),
+				{Namespace: "default", Name: "route-2"}: httpRoute("route-2", nil),
+				{Namespace: "default", Name: "route-3"}: httpRoute("route-3", hostnames(1, 16), rules(17, 17)...),
+				{Namespace: "default", Name: "route-4"}: httpRoute("route-4", hostnames(17, 17), rules(1, 16)...),
+				{Namespace: "default", Name: "route-5"}: httpRoute("route-5", hostnames(17, 17), rules(17, 17)...),
+			},
+			expectedUnsupported: map[types.NamespacedName][]intermediate.UnsupportedFeature{
+				key:                                     {{Name: "feature"}},
+				{Namespace: "default", Name: "route-3"}: {{Name: "feature"}},
+				{Namespace: "default", Name: "route-4"}: {{Name: "feature"}},
+				{Namespace: "default", Name: "route-5"}: {{Name: "feature"}},
+			},
+			expectedNotifications: []string{"it was split into HTTPRoutes route, route-3, route-4, route-5"},
+		},
+		{
+			name: "routes targeted by policies aren't split",
+			gatewayResources: GatewayResources{
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{key: httpRoute("route", nil, rules(1, 17)...)},
+				GatewayExtensions: []unstructured.Unstructured{{Object: map[string]interface{}{
+					"metadata": map[string]interface{}{"namespace": "default", "name": "policy"},
+					"spec": map[string]interface{}{"targetRefs": []interface{}{
+						map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "HTTPRoute", "name": "route"},
+					}},
+				}}},
+			},
+			expectedHTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{key: httpRoute("route", nil, rules(1, 17)...)},
+			expectedErrors:     []string{"HTTPRoute[default/route].spec.rules: Too many: 17: must have at most 16 items"},
+		},
+		{
+			name: "backendRefs fail the conversion",
+			gatewayResources: GatewayResources{HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+				key: httpRoute("route", nil, gatewayv1.HTTPRouteRule{BackendRefs: make([]gatewayv1.HTTPBackendRef, 17)}),
+			}},
+			expectedHTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+				key: httpRoute("route", nil, gatewayv1.HTTPRouteRule{BackendRefs: make([]gatewayv1.HTTPBackendRef, 17)}),
+			},
+			expectedErrors: []string{"HTTPRoute[default/route].spec.rules[0].backendRefs: Too many: 17: must have at most 16 items"},
+		},
+		{
+			name: "listeners fail the conversion",
+			gatewayResources: GatewayResources{Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+				{Namespace: "default", Name: "gateway"}: {Spec: gatewayv1.GatewaySpec{Listeners: make([]gatewayv1.Listener, 65)}},
+			}},
+			expectedErrors: []string{"Gateway[default/gateway].spec.listeners: Too many: 65: must have at most 64 items"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr = notifications.NotificationAggregator{Notifications: map[string][]notifications.Notification{}}
+
+			errs := enforceListCaps("test", &tc.gatewayResources)
+
+			var errMessages []string
+			for _, err := range errs {
+				errMessages = append(errMessages, err.Error())
+			}
+			require.Equal(t, tc.expectedErrors, errMessages)
+			if tc.expectedHTTPRoutes != nil {
+				require.Equal(t, tc.expectedHTTPRoutes, tc.gatewayResources.HTTPRoutes)
+			}
+			if tc.expectedUnsupported != nil {
+				require.Equal(t, tc.expectedUnsupported, tc.gatewayResources.UnsupportedFeatures)
+			}
+			require.Len(t, notifications.NotificationAggr.Notifications["test"], len(tc.expectedNotifications))
+			for i, expected := range tc.expectedNotifications {
+				require.Contains(t, notifications.NotificationAggr.Notifications["test"][i].Message, expected)
+			}
+		})
+	}
+}
+
+func Test_enforceListCaps_mirrorFractions(t *testing.T) {
+	var matches []gatewayv1.HTTPRouteMatch
+	for i := 1; i <= 10; i++ {
+		matches = append(matches, gatewayv1.HTTPRouteMatch{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To(fmt.Sprintf("/%d", i))}})
+	}
+	backendRef := gatewayv1.BackendObjectReference{Name: "shadow"}
+	key := types.NamespacedName{Namespace: "default", Name: "route"}
+	gatewayResources := GatewayResources{
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{key: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Spec: gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{
+				Matches: matches,
+				Filters: []gatewayv1.HTTPRouteFilter{{
+					Type:          gatewayv1.HTTPRouteFilterRequestMirror,
+					RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: backendRef},
+				}},
+			}}},
+		}},
+		MirrorFractions: map[types.NamespacedName][]MirrorFraction{key: {{Matches: matches, BackendRef: backendRef, Numerator: 25, Denominator: 100}}},
+	}
+
+	require.Empty(t, enforceListCaps("test", &gatewayResources))
+
+	// Each of the split rules mirrors the fraction of the requests of the
+	// original rule.
+	content := HTTPRouteObject(gatewayResources.HTTPRoutes[key], gatewayResources.MirrorFractions[key]).(*unstructured.Unstructured).Object
+	rules, _, _ := unstructured.NestedSlice(content, "spec", "rules")
+	require.Len(t, rules, 2)
+	for _, rule := range rules {
+		filters := rule.(map[string]interface{})["filters"].([]interface{})
+		require.Equal(t, int64(25), filters[0].(map[string]interface{})["requestMirror"].(map[string]interface{})["percent"])
+	}
+}
+
+func Test_listParts(t *testing.T) {
+	require.Equal(t, [][2]int{{0, 0}}, listParts(0, 16))
+	require.Equal(t, [][2]int{{0, 16}}, listParts(16, 16))
+	require.Equal(t, [][2]int{{0, 16}, {16, 32}, {32, 33}}, listParts(33, 16))
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// ListenerStrategy is a string alias that stores the name of the strategy used
+// to assign the hostnames of a Gateway to its listeners.
+type ListenerStrategy string
+
+const (
+	// PerHostListenerStrategy generates a listener per hostname and protocol,
+	// as generated by the providers.
+	PerHostListenerStrategy ListenerStrategy = "per-host"
+	// PerCertListenerStrategy groups the hostnames served with the same
+	// certificates into a single listener, the routes narrowing the hostnames.
+	// Non-TLS listeners are grouped by port.
+	PerCertListenerStrategy ListenerStrategy = "per-cert"
+	// SingleListenerStrategy generates a single listener per port and
+	// protocol, holding the certificates of all the hostnames.
+	SingleListenerStrategy ListenerStrategy = "single"
+)
+
+// GetSupportedListenerStrategies returns the names of all the supported
+// listener strategies.
+func GetSupportedListenerStrategies() []string {
+	return []string{string(PerHostListenerStrategy), string(PerCertListenerStrategy), string(SingleListenerStrategy)}
+}
+
+// validateListenerStrategy returns an error if the given strategy is not
+// supported. An empty strategy means the PerHostListenerStrategy.
+func validateListenerStrategy(strategy ListenerStrategy) error {
+	if strategy != "" && !slices.Contains(GetSupportedListenerStrategies(), string(strategy)) {
+		return fmt.Errorf("%s is not a supported listener strategy, supported values are %v", strategy, GetSupportedListenerStrategies())
+	}
+	return nil
+}
+
+// listenerGroup is a set of listeners of a Gateway merged into one.
+type listenerGroup struct {
+	listener gatewayv1.Listener
+	members  []gatewayv1.Listener
+}
+
+// groupListeners merges the listeners of the Gateways of the IR according to
+// the given strategy. The routes referencing the merged listeners by
+// sectionName are updated, and the routes without hostnames get the hostnames
+// of the listeners they used to attach to, so that they don't start matching
+// hostnames they didn't match before.
+func groupListeners(providerName ProviderName, ir *intermediate.IR, strategy ListenerStrategy) {
+	if strategy == "" || strategy == PerHostListenerStrategy {
+		return
+	}
+
+	// The listeners replaced by the groups, by Gateway and listener name.
+	groupedListeners := map[types.NamespacedName]map[gatewayv1.SectionName]listenerGroup{}
+	for _, key := range sortedNamespacedNames(ir.Gateways) {
+		gatewayContext := ir.Gateways[key]
+		groups := listenerGroups(gatewayContext.Spec.Listeners, strategy)
+		if len(groups) == len(gatewayContext.Spec.Listeners) {
+			continue
+		}
+
+		groupedListeners[key] = map[gatewayv1.SectionName]listenerGroup{}
+		gatewayContext.Spec.Listeners = nil
+		for _, group := range groups {
+			gatewayContext.Spec.Listeners = append(gatewayContext.Spec.Listeners, group.listener)
+			for _, member := range group.members {
+				groupedListeners[key][member.Name] = group
+			}
+			if group.listener.TLS != nil && len(group.listener.TLS.CertificateRefs) > 1 && len(group.members) > 1 {
+				notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification,
+					fmt.Sprintf("listener %s of Gateway %s holds the certificates of several hostnames, the selection of the certificate of a request is implementation-specific", group.listener.Name, key),
+					&gatewayContext.Gateway), string(providerName))
+			}
+		}
+		ir.Gateways[key] = gatewayContext
+	}
+	if len(groupedListeners) == 0 {
+		return
+	}
+
+	for key, httpRouteContext := range ir.HTTPRoutes {
+		httpRouteContext.Spec.Hostnames = narrowedHostnames(key, httpRouteContext.Spec.ParentRefs, httpRouteContext.Spec.Hostnames, groupedListeners)
+		regroupParentRefs(key, httpRouteContext.Spec.ParentRefs, groupedListeners)
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+	for key, route := range ir.GRPCRoutes {
+		route.Spec.Hostnames = narrowedHostnames(key, route.Spec.ParentRefs, route.Spec.Hostnames, groupedListeners)
+		regroupParentRefs(key, route.Spec.ParentRefs, groupedListeners)
+		ir.GRPCRoutes[key] = route
+	}
+	for key, route := range ir.TLSRoutes {
+		route.Spec.Hostnames = narrowedHostnames(key, route.Spec.ParentRefs, route.Spec.Hostnames, groupedListeners)
+		regroupParentRefs(key, route.Spec.ParentRefs, groupedListeners)
+		ir.TLSRoutes[key] = route
+	}
+	for key, route := range ir.TCPRoutes {
+		regroupParentRefs(key, route.Spec.ParentRefs, groupedListeners)
+	}
+	for key, route := range ir.UDPRoutes {
+		regroupParentRefs(key, route.Spec.ParentRefs, groupedListeners)
+	}
+}
+
+// listenerGroups groups the given listeners according to the given strategy,
+// keeping the order of their first listener. Listeners which can't be grouped
+// without conflicting with another group keep a group of their own.
+func listenerGroups(listeners []gatewayv1.Listener, strategy ListenerStrategy) []listenerGroup {
+	var keys []string
+	membersByKey := map[string][]gatewayv1.Listener{}
+	for _, listener := range listeners {
+		key := listenerGroupKey(listener, strategy)
+		if _, ok := membersByKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		membersByKey[key] = append(membersByKey[key], listener)
+	}
+
+	// A port and protocol family can only be bound by a single listener
+	// without hostname: the groups including such a listener are bound first,
+	// the other groups without hostname are only merged if it's still free.
+	catchAll := map[string]bool{}
+	portKey := func(listener gatewayv1.Listener) string {
+		return fmt.Sprintf("%d/%s", listener.Port, protocolFamily(listener.Protocol))
+	}
+	for _, listener := range listeners {
+		if listener.Hostname == nil {
+			catchAll[portKey(listener)] = true
+		}
+	}
+
+	var groups []listenerGroup
+	for _, key := range keys {
+		members := membersByKey[key]
+		hostname := groupHostname(members)
+		hasCatchAllMember := slices.ContainsFunc(members, func(listener gatewayv1.Listener) bool { return listener.Hostname == nil })
+		if len(members) > 1 && hostname == nil && !hasCatchAllMember {
+			if catchAll[portKey(members[0])] {
+				for _, member := range members {
+					groups = append(groups, listenerGroup{listener: member, members: []gatewayv1.Listener{member}})
+				}
+				continue
+			}
+			catchAll[portKey(members[0])] = true
+		}
+		if len(members) == 1 {
+			groups = append(groups, listenerGroup{listener: members[0], members: members})
+			continue
+		}
+
+		listener := *members[0].DeepCopy()
+		listener.Hostname = hostname
+		if listener.TLS != nil {
+			listener.TLS.CertificateRefs = nil
+			for _, member := range members {
+				for _, ref := range member.TLS.CertificateRefs {
+					if !slices.Contains(listener.TLS.CertificateRefs, ref) {
+						listener.TLS.CertificateRefs = append(listener.TLS.CertificateRefs, ref)
+					}
+				}
+			}
+		}
+		listener.Name = groupListenerName(listener, strategy)
+		groups = append(groups, listenerGroup{listener: listener, members: members})
+	}
+
+	// Disambiguate the names of the groups named after the same certificate
+	// or protocol, e.g. on different ports.
+	names := map[gatewayv1.SectionName]int{}
+	for _, group := range groups {
+		names[group.listener.Name]++
+	}
+	for i, group := range groups {
+		if len(group.members) > 1 && names[group.listener.Name] > 1 {
+			groups[i].listener.Name = gatewayv1.SectionName(fmt.Sprintf("%s-%d", group.listener.Name, group.listener.Port))
+		}
+	}
+	return groups
+}
+
+// listenerGroupKey returns the key of the group of the given listener: the
+// listeners sharing a port, a protocol and the allowed routes are grouped,
+// and by the per-cert strategy only if they share their TLS configuration.
+func listenerGroupKey(listener gatewayv1.Listener, strategy ListenerStrategy) string {
+	allowedRoutes, _ := json.Marshal(listener.AllowedRoutes)
+	key := fmt.Sprintf("%d/%s/%s", listener.Port, listener.Protocol, allowedRoutes)
+	if listener.TLS != nil && listener.TLS.Mode != nil {
+		key += "/" + string(*listener.TLS.Mode)
+	}
+	if strategy == PerCertListenerStrategy && listener.TLS != nil {
+		tls, _ := json.Marshal(listener.TLS)
+		key += "/" + string(tls)
+	}
+	return key
+}
+
+// groupHostname returns the hostname of the listener grouping the given ones:
+// the narrowest wildcard matching all of them, e.g. *.example.com for
+// foo.example.com and bar.example.com, or none if one of them has no hostname
+// or the wildcard would match a top-level domain, e.g. *.com.
+func groupHostname(listeners []gatewayv1.Listener) *gatewayv1.Hostname {
+	var suffix []string
+	for i, listener := range listeners {
+		if listener.Hostname == nil {
+			return nil
+		}
+		labels := strings.Split(strings.TrimPrefix(string(*listener.Hostname), "*."), ".")
+		if i == 0 {
+			suffix = labels
+			continue
+		}
+		n := 0
+		for n < len(suffix) && n < len(labels) && suffix[len(suffix)-1-n] == labels[len(labels)-1-n] {
+			n++
+		}
+		suffix = suffix[len(suffix)-n:]
+	}
+
+	// The wildcard only matches the subdomains of the suffix, not the suffix
+	// itself, which must be a proper suffix of every hostname.
+	for _, listener := range listeners {
+		hostname := string(*listener.Hostname)
+		if !strings.HasPrefix(hostname, "*.") && strings.Count(hostname, ".")+1 == len(suffix) {
+			return nil
+		}
+	}
+	if len(suffix) < 2 {
+		return nil
+	}
+	hostname := gatewayv1.Hostname("*." + strings.Join(suffix, "."))
+	return &hostname
+}
+
+// groupListenerName returns the name of a listener grouping others, after its
+// first certificate with the per-cert strategy, or after its protocol.
+func groupListenerName(listener gatewayv1.Listener, strategy ListenerStrategy) gatewayv1.SectionName {
+	protocol := strings.ToLower(string(listener.Protocol))
+	if strategy == PerCertListenerStrategy && listener.TLS != nil && len(listener.TLS.CertificateRefs) > 0 {
+		certName := strings.ReplaceAll(string(listener.TLS.CertificateRefs[0].Name), ".", "-")
+		return gatewayv1.SectionName(fmt.Sprintf("%s-%s", certName, protocol))
+	}
+	return gatewayv1.SectionName(protocol)
+}
+
+// regroupParentRefs updates the sectionNames of the given parentRefs of the
+// route with the given key referencing grouped listeners.
+func regroupParentRefs(routeKey types.NamespacedName, parentRefs []gatewayv1.ParentReference, groupedListeners map[types.NamespacedName]map[gatewayv1.SectionName]listenerGroup) {
+	for i, parentRef := range parentRefs {
+		if parentRef.SectionName == nil {
+			continue
+		}
+		gatewayKeys := gatewayParentRefKeys(routeKey, []gatewayv1.ParentReference{parentRef})
+		if len(gatewayKeys) == 0 {
+			continue
+		}
+		if group, ok := groupedListeners[gatewayKeys[0]][*parentRef.SectionName]; ok {
+			name := group.listener.Name
+			parentRefs[i].SectionName = &name
+		}
+	}
+}
+
+// narrowedHostnames returns the given hostnames of the route with the given
+// key, or, if it has none, the hostnames of the grouped listeners it used to
+// attach to, unless it used to attach to a listener without hostname.
+func narrowedHostnames(routeKey types.NamespacedName, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, groupedListeners map[types.NamespacedName]map[gatewayv1.SectionName]listenerGroup) []gatewayv1.Hostname {
+	if len(hostnames) > 0 {
+		return hostnames
+	}
+
+	var narrowed []gatewayv1.Hostname
+	for _, parentRef := range parentRefs {
+		gatewayKeys := gatewayParentRefKeys(routeKey, []gatewayv1.ParentReference{parentRef})
+		if len(gatewayKeys) == 0 {
+			continue
+		}
+		listeners, ok := groupedListeners[gatewayKeys[0]]
+		if !ok {
+			continue
+		}
+		names := make([]gatewayv1.SectionName, 0, len(listeners))
+		for name := range listeners {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			if parentRef.SectionName != nil && *parentRef.SectionName != name {
+				continue
+			}
+			if parentRef.Port != nil && *parentRef.Port != listeners[name].listener.Port {
+				continue
+			}
+			member := memberListener(listeners[name], name)
+			if member.Hostname == nil {
+				return nil
+			}
+			if !slices.Contains(narrowed, *member.Hostname) {
+				narrowed = append(narrowed, *member.Hostname)
+			}
+		}
+	}
+	slices.Sort(narrowed)
+	return narrowed
+}
+
+func memberListener(group listenerGroup, name gatewayv1.SectionName) gatewayv1.Listener {
+	for _, member := range group.members {
+		if member.Name == name {
+			return member
+		}
+	}
+	return group.listener
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func Test_validateListenerStrategy(t *testing.T) {
+	require.NoError(t, validateListenerStrategy(""))
+	require.NoError(t, validateListenerStrategy(PerHostListenerStrategy))
+	require.NoError(t, validateListenerStrategy(PerCertListenerStrategy))
+	require.NoError(t, validateListenerStrategy(SingleListenerStrategy))
+	require.Error(t, validateListenerStrategy("per-source"))
+}
+
+func httpListener(name gatewayv1.SectionName, hostname gatewayv1.Hostname) gatewayv1.Listener {
+	return gatewayv1.Listener{Name: name, Hostname: ptr.To(hostname), Port: 80, Protocol: gatewayv1.HTTPProtocolType}
+}
+
+func httpsListener(name gatewayv1.SectionName, hostname gatewayv1.Hostname, secret gatewayv1.ObjectName) gatewayv1.Listener {
+	return gatewayv1.Listener{
+		Name:     name,
+		Hostname: ptr.To(hostname),
+		Port:     443,
+		Protocol: gatewayv1.HTTPSProtocolType,
+		TLS: &gatewayv1.GatewayTLSConfig{
+			CertificateRefs: []gatewayv1.SecretObjectReference{{Name: secret}},
+		},
+	}
+}
+
+func listenerStrategyIR() intermediate.IR {
+	return intermediate.IR{
+		Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+			{Namespace: "default", Name: "nginx"}: {
+				Gateway: gatewayv1.Gateway{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+					Spec: gatewayv1.GatewaySpec{
+						GatewayClassName: "nginx",
+						Listeners: []gatewayv1.Listener{
+							httpListener("foo-example-com-http", "foo.example.com"),
+							httpsListener("foo-example-com-https", "foo.example.com", "example-com"),
+							httpListener("bar-example-com-http", "bar.example.com"),
+							httpsListener("bar-example-com-https", "bar.example.com", "example-com"),
+							httpListener("baz-example-org-http", "baz.example.org"),
+							httpsListener("baz-example-org-https", "baz.example.org", "example-org"),
+						},
+					},
+				},
+			},
+		},
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			{Namespace: "default", Name: "foo"}: {
+				HTTPRoute: gatewayv1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+					Spec: gatewayv1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayv1.CommonRouteSpec{
+							ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}},
+						},
+						Hostnames: []gatewayv1.Hostname{"foo.example.com"},
+					},
+				},
+			},
+			{Namespace: "default", Name: "default-backend"}: {
+				HTTPRoute: gatewayv1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "default-backend"},
+					Spec: gatewayv1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayv1.CommonRouteSpec{
+							ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}},
+						},
+					},
+				},
+			},
+		},
+		TLSRoutes: map[types.NamespacedName]gatewayv1alpha2.TLSRoute{
+			{Namespace: "default", Name: "bar"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar"},
+				Spec: gatewayv1alpha2.TLSRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{
+						ParentRefs: []gatewayv1.ParentReference{{Name: "nginx", SectionName: ptr.To[gatewayv1.SectionName]("bar-example-com-https")}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_groupListeners(t *testing.T) {
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+	defaultBackendKey := types.NamespacedName{Namespace: "default", Name: "default-backend"}
+	tlsRouteKey := types.NamespacedName{Namespace: "default", Name: "bar"}
+
+	t.Run("per-host", func(t *testing.T) {
+		ir := listenerStrategyIR()
+		groupListeners("test", &ir, PerHostListenerStrategy)
+		require.Equal(t, listenerStrategyIR(), ir)
+	})
+
+	t.Run("per-cert", func(t *testing.T) {
+		ir := listenerStrategyIR()
+		groupListeners("test", &ir, PerCertListenerStrategy)
+
+		require.Equal(t, []gatewayv1.Listener{
+			{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+			httpsListener("example-com-https", "*.example.com", "example-com"),
+			httpsListener("baz-example-org-https", "baz.example.org", "example-org"),
+		}, ir.Gateways[gatewayKey].Spec.Listeners)
+
+		require.Equal(t, []gatewayv1.Hostname{"foo.example.com"}, ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo"}].Spec.Hostnames)
+		require.Equal(t, []gatewayv1.Hostname{"bar.example.com", "baz.example.org", "foo.example.com"}, ir.HTTPRoutes[defaultBackendKey].Spec.Hostnames)
+		require.Equal(t, ptr.To[gatewayv1.SectionName]("example-com-https"), ir.TLSRoutes[tlsRouteKey].Spec.ParentRefs[0].SectionName)
+		require.Equal(t, []gatewayv1.Hostname{"bar.example.com"}, ir.TLSRoutes[tlsRouteKey].Spec.Hostnames)
+	})
+
+	t.Run("single", func(t *testing.T) {
+		ir := listenerStrategyIR()
+		groupListeners("test", &ir, SingleListenerStrategy)
+
+		require.Equal(t, []gatewayv1.Listener{
+			{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+			{
+				Name:     "https",
+				Port:     443,
+				Protocol: gatewayv1.HTTPSProtocolType,
+				TLS: &gatewayv1.GatewayTLSConfig{
+					CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "example-com"}, {Name: "example-org"}},
+				},
+			},
+		}, ir.Gateways[gatewayKey].Spec.Listeners)
+		require.Equal(t, ptr.To[gatewayv1.SectionName]("https"), ir.TLSRoutes[tlsRouteKey].Spec.ParentRefs[0].SectionName)
+	})
+}
+
+func Test_listenerGroups_catchAll(t *testing.T) {
+	catchAll := gatewayv1.Listener{
+		Name:     "https",
+		Port:     443,
+		Protocol: gatewayv1.HTTPSProtocolType,
+		TLS: &gatewayv1.GatewayTLSConfig{
+			CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "default"}},
+		},
+	}
+	foo := httpsListener("foo-com-https", "foo.com", "shared")
+	bar := httpsListener("bar-org-https", "bar.org", "shared")
+
+	// foo.com and bar.org share no domain, their group would need a listener
+	// without hostname, conflicting with the existing one.
+	groups := listenerGroups([]gatewayv1.Listener{foo, bar, catchAll}, PerCertListenerStrategy)
+	require.Len(t, groups, 3)
+	require.Equal(t, foo, groups[0].listener)
+	require.Equal(t, bar, groups[1].listener)
+	require.Equal(t, catchAll, groups[2].listener)
+}
+
+func Test_groupHostname(t *testing.T) {
+	testCases := []struct {
+		hostnames []gatewayv1.Hostname
+		expected  *gatewayv1.Hostname
+	}{
+		{hostnames: []gatewayv1.Hostname{"foo.example.com", "bar.example.com"}, expected: ptr.To[gatewayv1.Hostname]("*.example.com")},
+		{hostnames: []gatewayv1.Hostname{"a.foo.example.com", "b.foo.example.com"}, expected: ptr.To[gatewayv1.Hostname]("*.foo.example.com")},
+		{hostnames: []gatewayv1.Hostname{"*.example.com", "foo.example.com"}, expected: ptr.To[gatewayv1.Hostname]("*.example.com")},
+		{hostnames: []gatewayv1.Hostname{"example.com", "foo.example.com"}},
+		{hostnames: []gatewayv1.Hostname{"foo.com", "bar.com"}},
+		{hostnames: []gatewayv1.Hostname{"foo.example.com", "foo.example.org"}},
+	}
+	for _, tc := range testCases {
+		var listeners []gatewayv1.Listener
+		for _, hostname := range tc.hostnames {
+			listeners = append(listeners, gatewayv1.Listener{Hostname: ptr.To(hostname)})
+		}
+		require.Equal(t, tc.expected, groupHostname(listeners), "hostnames %v", tc.hostnames)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// ListenerPortsByGatewayClass holds the only listener ports the
+// implementations of some GatewayClasses can bind, e.g. the L7 load balancers
+// of GKE only serving ports 80 and 443. Providers generating Gateways of such
+// GatewayClasses should register them at startup.
+var ListenerPortsByGatewayClass = map[gatewayv1.ObjectName][]gatewayv1.PortNumber{}
+
+// listenerWarning is a warning about the listeners of a generated Gateway, or
+// the references of a generated route to them.
+type listenerWarning struct {
+	message string
+	object  client.Object
+}
+
+// validateListeners notifies about the listeners of the given GatewayResources
+// which Gateway API implementations would reject or mark as conflicted, and
+// about the routes referencing listeners which don't exist. Providers serving
+// HTTP on other ports than 80 and 443, e.g. the 8443 port of an istio server,
+// are the most likely to generate them.
+func validateListeners(providerName ProviderName, gatewayResources *GatewayResources) {
+	for _, warning := range listenerWarnings(gatewayResources) {
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification, warning.message, warning.object), string(providerName))
+	}
+}
+
+func listenerWarnings(gatewayResources *GatewayResources) []listenerWarning {
+	var warnings []listenerWarning
+	for _, key := range sortedNamespacedNames(gatewayResources.Gateways) {
+		gateway := gatewayResources.Gateways[key]
+		warn := func(format string, args ...interface{}) {
+			warnings = append(warnings, listenerWarning{message: fmt.Sprintf(format, args...), object: &gateway})
+		}
+
+		bindablePorts, restricted := ListenerPortsByGatewayClass[gateway.Spec.GatewayClassName]
+		for i, listener := range gateway.Spec.Listeners {
+			if err := validateListenerTLS(listener); err != "" {
+				warn("listener %s of Gateway %s %s", listener.Name, key, err)
+			}
+			if restricted && !slices.Contains(bindablePorts, listener.Port) {
+				warn("listener %s of Gateway %s binds port %d, the implementation of GatewayClass %s can only bind ports %v", listener.Name, key, listener.Port, gateway.Spec.GatewayClassName, bindablePorts)
+			}
+			for _, other := range gateway.Spec.Listeners[:i] {
+				switch {
+				case other.Name == listener.Name:
+					warn("listeners of Gateway %s share the name %s, the Gateway is invalid", key, listener.Name)
+				case other.Port != listener.Port:
+				case protocolFamily(other.Protocol) != protocolFamily(listener.Protocol):
+					warn("listeners %s and %s of Gateway %s bind port %d with incompatible protocols %s and %s, they are conflicted", other.Name, listener.Name, key, listener.Port, other.Protocol, listener.Protocol)
+				case other.Protocol == listener.Protocol && ptrEqual(other.Hostname, listener.Hostname):
+					warn("listeners %s and %s of Gateway %s bind port %d with the same protocol and hostname, they are conflicted", other.Name, listener.Name, key, listener.Port)
+				}
+			}
+		}
+	}
+
+	for _, route := range generatedRoutes(gatewayResources) {
+		for _, parentRef := range route.parentRefs {
+			if parentRef.SectionName == nil && parentRef.Port == nil {
+				continue
+			}
+			if (parentRef.Group != nil && *parentRef.Group != gatewayv1.GroupName) || (parentRef.Kind != nil && *parentRef.Kind != "Gateway") {
+				continue
+			}
+			gatewayKey := types.NamespacedName{Namespace: route.object.GetNamespace(), Name: string(parentRef.Name)}
+			if parentRef.Namespace != nil {
+				gatewayKey.Namespace = string(*parentRef.Namespace)
+			}
+			gateway, ok := gatewayResources.Gateways[gatewayKey]
+			if !ok {
+				continue
+			}
+			if !slices.ContainsFunc(gateway.Spec.Listeners, func(listener gatewayv1.Listener) bool {
+				return (parentRef.SectionName == nil || listener.Name == *parentRef.SectionName) &&
+					(parentRef.Port == nil || listener.Port == *parentRef.Port)
+			}) {
+				warnings = append(warnings, listenerWarning{
+					message: fmt.Sprintf("%s %s/%s references %s, matching no listener of Gateway %s", route.kind, route.object.GetNamespace(), route.object.GetName(), parentRefListener(parentRef), gatewayKey),
+					object:  route.object,
+				})
+			}
+		}
+	}
+	return warnings
+}
+
+// validateListenerTLS returns why the TLS configuration of the listener is
+// invalid for its protocol, or an empty string.
+func validateListenerTLS(listener gatewayv1.Listener) string {
+	switch listener.Protocol {
+	case gatewayv1.HTTPSProtocolType, gatewayv1.TLSProtocolType:
+		if listener.TLS == nil {
+			return fmt.Sprintf("of protocol %s has no TLS configuration", listener.Protocol)
+		}
+		if listener.Protocol == gatewayv1.HTTPSProtocolType && listener.TLS.Mode != nil && *listener.TLS.Mode == gatewayv1.TLSModePassthrough {
+			return "of protocol HTTPS passes TLS through, only TLS listeners do"
+		}
+	case gatewayv1.HTTPProtocolType, gatewayv1.TCPProtocolType, gatewayv1.UDPProtocolType:
+		if listener.TLS != nil {
+			return fmt.Sprintf("of protocol %s has a TLS configuration, only HTTPS and TLS listeners do", listener.Protocol)
+		}
+	}
+	return ""
+}
+
+// protocolFamily groups the protocols which listeners can share a port with:
+// HTTPS and TLS listeners are told apart by SNI, the others can't share it.
+func protocolFamily(protocol gatewayv1.ProtocolType) gatewayv1.ProtocolType {
+	if protocol == gatewayv1.HTTPSProtocolType {
+		return gatewayv1.TLSProtocolType
+	}
+	return protocol
+}
+
+func parentRefListener(parentRef gatewayv1.ParentReference) string {
+	var parts []string
+	if parentRef.SectionName != nil {
+		parts = append(parts, fmt.Sprintf("sectionName %s", *parentRef.SectionName))
+	}
+	if parentRef.Port != nil {
+		parts = append(parts, fmt.Sprintf("port %d", *parentRef.Port))
+	}
+	return strings.Join(parts, " and ")
+}
+
+type generatedRoute struct {
+	kind       string
+	object     client.Object
+	parentRefs []gatewayv1.ParentReference
+}
+
+// generatedRoutes returns the routes of the GatewayResources, sorted by kind
+// and key.
+func generatedRoutes(gatewayResources *GatewayResources) []generatedRoute {
+	var routes []generatedRoute
+	for _, key := range sortedNamespacedNames(gatewayResources.HTTPRoutes) {
+		route := gatewayResources.HTTPRoutes[key]
+		routes = append(routes, generatedRoute{kind: "HTTPRoute", object: &route, parentRefs: route.Spec.ParentRefs})
+	}
+	for _, key := range sortedNamespacedNames(gatewayResources.GRPCRoutes) {
+		route := gatewayResources.GRPCRoutes[key]
+		routes = append(routes, generatedRoute{kind: "GRPCRoute", object: &route, parentRefs: route.Spec.ParentRefs})
+	}
+	for _, key := range sortedNamespacedNames(gatewayResources.TLSRoutes) {
+		route := gatewayResources.TLSRoutes[key]
+		routes = append(routes, generatedRoute{kind: "TLSRoute", object: &route, parentRefs: route.Spec.ParentRefs})
+	}
+	for _, key := range sortedNamespacedNames(gatewayResources.TCPRoutes) {
+		route := gatewayResources.TCPRoutes[key]
+		routes = append(routes, generatedRoute{kind: "TCPRoute", object: &route, parentRefs: route.Spec.ParentRefs})
+	}
+	for _, key := range sortedNamespacedNames(gatewayResources.UDPRoutes) {
+		route := gatewayResources.UDPRoutes[key]
+		routes = append(routes, generatedRoute{kind: "UDPRoute", object: &route, parentRefs: route.Spec.ParentRefs})
+	}
+	return routes
+}
+
+func ptrEqual[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// sortedNamespacedNames returns the keys of the given map, sorted.
+func sortedNamespacedNames[V any](m map[types.NamespacedName]V) []types.NamespacedName {
+	keys := make([]types.NamespacedName, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return keys
+}
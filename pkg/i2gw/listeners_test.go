@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func Test_listenerWarnings(t *testing.T) {
+	ListenerPortsByGatewayClass["restricted"] = []gatewayv1.PortNumber{80, 443}
+	defer delete(ListenerPortsByGatewayClass, "restricted")
+
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "gateway"}
+	terminate := &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModeTerminate)}
+	passthrough := &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModePassthrough)}
+
+	testCases := []struct {
+		name             string
+		gatewayClassName gatewayv1.ObjectName
+		listeners        []gatewayv1.Listener
+		parentRef        gatewayv1.ParentReference
+		expectedMessages []string
+	}{
+		{
+			name:             "valid listeners on non-standard ports",
+			gatewayClassName: "istio",
+			listeners: []gatewayv1.Listener{
+				{Name: "http", Port: 8080, Protocol: gatewayv1.HTTPProtocolType},
+				{Name: "https", Hostname: ptr.To[gatewayv1.Hostname]("foo.example.com"), Port: 8443, Protocol: gatewayv1.HTTPSProtocolType, TLS: terminate},
+				{Name: "tls", Hostname: ptr.To[gatewayv1.Hostname]("bar.example.com"), Port: 8443, Protocol: gatewayv1.TLSProtocolType, TLS: passthrough},
+			},
+			parentRef: gatewayv1.ParentReference{Name: "gateway", SectionName: ptr.To[gatewayv1.SectionName]("https"), Port: ptr.To[gatewayv1.PortNumber](8443)},
+		},
+		{
+			name:             "invalid protocol and TLS combinations",
+			gatewayClassName: "istio",
+			listeners: []gatewayv1.Listener{
+				{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType, TLS: passthrough},
+				{Name: "https", Port: 443, Protocol: gatewayv1.HTTPSProtocolType, TLS: passthrough},
+				{Name: "tls", Port: 8443, Protocol: gatewayv1.TLSProtocolType},
+			},
+			parentRef: gatewayv1.ParentReference{Name: "gateway"},
+			expectedMessages: []string{
+				"listener http of Gateway default/gateway of protocol HTTP has a TLS configuration, only HTTPS and TLS listeners do",
+				"listener https of Gateway default/gateway of protocol HTTPS passes TLS through, only TLS listeners do",
+				"listener tls of Gateway default/gateway of protocol TLS has no TLS configuration",
+			},
+		},
+		{
+			name:             "conflicted listeners",
+			gatewayClassName: "istio",
+			listeners: []gatewayv1.Listener{
+				{Name: "http", Port: 8443, Protocol: gatewayv1.HTTPProtocolType},
+				{Name: "https", Port: 8443, Protocol: gatewayv1.HTTPSProtocolType, TLS: terminate},
+				{Name: "https-2", Port: 8443, Protocol: gatewayv1.HTTPSProtocolType, TLS: terminate},
+				{Name: "https", Port: 443, Protocol: gatewayv1.HTTPSProtocolType, TLS: terminate},
+			},
+			parentRef: gatewayv1.ParentReference{Name: "gateway"},
+			expectedMessages: []string{
+				"listeners http and https of Gateway default/gateway bind port 8443 with incompatible protocols HTTP and HTTPS, they are conflicted",
+				"listeners http and https-2 of Gateway default/gateway bind port 8443 with incompatible protocols HTTP and HTTPS, they are conflicted",
+				"listeners https and https-2 of Gateway default/gateway bind port 8443 with the same protocol and hostname, they are conflicted",
+				"listeners of Gateway default/gateway share the name https, the Gateway is invalid",
+			},
+		},
+		{
+			name:             "ports the implementation can't bind",
+			gatewayClassName: "restricted",
+			listeners: []gatewayv1.Listener{
+				{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+				{Name: "http-alt", Port: 8080, Protocol: gatewayv1.HTTPProtocolType},
+			},
+			parentRef: gatewayv1.ParentReference{Name: "gateway"},
+			expectedMessages: []string{
+				"listener http-alt of Gateway default/gateway binds port 8080, the implementation of GatewayClass restricted can only bind ports [80 443]",
+			},
+		},
+		{
+			name:             "parentRef matching no listener",
+			gatewayClassName: "istio",
+			listeners: []gatewayv1.Listener{
+				{Name: "tls", Port: 443, Protocol: gatewayv1.TLSProtocolType, TLS: passthrough},
+			},
+			parentRef: gatewayv1.ParentReference{Name: "gateway", SectionName: ptr.To[gatewayv1.SectionName]("tls"), Port: ptr.To[gatewayv1.PortNumber](8443)},
+			expectedMessages: []string{
+				"TLSRoute default/route references sectionName tls and port 8443, matching no listener of Gateway default/gateway",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gatewayResources := &GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					gatewayKey: {
+						ObjectMeta: metav1.ObjectMeta{Namespace: gatewayKey.Namespace, Name: gatewayKey.Name},
+						Spec:       gatewayv1.GatewaySpec{GatewayClassName: tc.gatewayClassName, Listeners: tc.listeners},
+					},
+				},
+				TLSRoutes: map[types.NamespacedName]gatewayv1alpha2.TLSRoute{
+					{Namespace: "default", Name: "route"}: {
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+						Spec: gatewayv1alpha2.TLSRouteSpec{
+							CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{tc.parentRef}},
+						},
+					},
+				},
+			}
+
+			var messages []string
+			for _, warning := range listenerWarnings(gatewayResources) {
+				messages = append(messages, warning.message)
+			}
+			require.Equal(t, tc.expectedMessages, messages)
+		})
+	}
+}
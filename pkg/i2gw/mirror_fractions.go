@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// MirrorFraction is the fraction, Numerator/Denominator, of the requests a
+// RequestMirror filter of a generated HTTPRoute mirrors. The filter is
+// identified by the matches of its rule and by its backend, as the rules are
+// reordered and their filters consolidated once converted.
+type MirrorFraction struct {
+	Matches     []gatewayv1.HTTPRouteMatch
+	BackendRef  gatewayv1.BackendObjectReference
+	Numerator   int32
+	Denominator int32
+}
+
+// addMirrorFractions stores the fractions of the requests the RequestMirror
+// filters of the generated HTTPRoutes mirror, set by the providers in the IR,
+// when the Gateway API version supports them. Otherwise the filters mirror
+// all the requests, which is warned about, none of the emitters having a
+// policy mirroring a fraction of the requests.
+func addMirrorFractions(providerName ProviderName, ir intermediate.IR, gatewayResources *GatewayResources, version gatewayAPIVersion) {
+	for _, key := range sortedNamespacedNames(ir.HTTPRoutes) {
+		httpRouteContext := ir.HTTPRoutes[key]
+		if len(httpRouteContext.MirrorFractions) == 0 {
+			continue
+		}
+		// The HTTPRoute may have been converted to another kind of route.
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for ruleIndex, rule := range httpRouteContext.Spec.Rules {
+			for filterIndex, filter := range rule.Filters {
+				fraction, ok := httpRouteContext.MirrorFractions[ruleIndex][filterIndex]
+				if !ok || filter.RequestMirror == nil || fraction.Denominator <= 0 {
+					continue
+				}
+				if !version.atLeast(mirrorFractionGatewayAPIVersion) {
+					percentage := strconv.FormatFloat(float64(fraction.Numerator)*100/float64(fraction.Denominator), 'f', -1, 64)
+					notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification,
+						fmt.Sprintf("HTTPRoute %s mirrors all the requests to %s instead of %s%% of them: mirroring a fraction of the requests requires Gateway API %s, set by --gateway-api-version",
+							key, filter.RequestMirror.BackendRef.Name, percentage, mirrorFractionGatewayAPIVersion), &httpRoute), string(providerName))
+					continue
+				}
+				if gatewayResources.MirrorFractions == nil {
+					gatewayResources.MirrorFractions = map[types.NamespacedName][]MirrorFraction{}
+				}
+				gatewayResources.MirrorFractions[key] = append(gatewayResources.MirrorFractions[key], MirrorFraction{
+					Matches:     rule.Matches,
+					BackendRef:  filter.RequestMirror.BackendRef,
+					Numerator:   fraction.Numerator,
+					Denominator: fraction.Denominator,
+				})
+			}
+		}
+	}
+}
+
+// HTTPRouteObject returns the HTTPRoute to print, with the given fractions of
+// the requests its RequestMirror filters mirror. The Gateway API types
+// ingress2gateway is built against lacking the fields of the fractions, the
+// HTTPRoute is returned as an unstructured object when it has any, setting
+// the `percent` of the filters mirroring a percentage of the requests and the
+// `fraction` of the other ones.
+func HTTPRouteObject(httpRoute gatewayv1.HTTPRoute, fractions []MirrorFraction) client.Object {
+	if len(fractions) == 0 {
+		return &httpRoute
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&httpRoute)
+	if err != nil {
+		return &httpRoute
+	}
+	object := &unstructured.Unstructured{Object: content}
+	if object.GroupVersionKind().Empty() {
+		object.SetGroupVersionKind(gatewayv1.SchemeGroupVersion.WithKind("HTTPRoute"))
+	}
+	rules, _, _ := unstructured.NestedSlice(content, "spec", "rules")
+
+	for i, rule := range httpRoute.Spec.Rules {
+		for _, fraction := range fractions {
+			if !apiequality.Semantic.DeepEqual(rule.Matches, fraction.Matches) {
+				continue
+			}
+			for j, filter := range rule.Filters {
+				if filter.RequestMirror == nil || !apiequality.Semantic.DeepEqual(filter.RequestMirror.BackendRef, fraction.BackendRef) {
+					continue
+				}
+				// The content was converted from the HTTPRoute, its rules
+				// and filters are the ones of the typed HTTPRoute.
+				filters := rules[i].(map[string]interface{})["filters"].([]interface{})
+				requestMirror := filters[j].(map[string]interface{})["requestMirror"].(map[string]interface{})
+				if fraction.Denominator == 100 {
+					requestMirror["percent"] = int64(fraction.Numerator)
+				} else {
+					requestMirror["fraction"] = map[string]interface{}{
+						"numerator":   int64(fraction.Numerator),
+						"denominator": int64(fraction.Denominator),
+					}
+				}
+			}
+		}
+	}
+	_ = unstructured.SetNestedSlice(content, rules, "spec", "rules")
+	return object
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_addMirrorFractions(t *testing.T) {
+	matches := []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/catalog")}}}
+	mirror := func(name string) gatewayv1.HTTPRouteFilter {
+		return gatewayv1.HTTPRouteFilter{
+			Type:          gatewayv1.HTTPRouteFilterRequestMirror,
+			RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: gatewayv1.BackendObjectReference{Name: gatewayv1.ObjectName(name)}},
+		}
+	}
+	key := types.NamespacedName{Namespace: "default", Name: "route"}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Spec: gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{
+			Matches: matches,
+			Filters: []gatewayv1.HTTPRouteFilter{mirror("shadow"), mirror("audit")},
+		}}},
+	}
+	ir := intermediate.IR{HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+		key: {
+			HTTPRoute:       httpRoute,
+			MirrorFractions: map[int]map[int]intermediate.MirrorFraction{0: {1: {Numerator: 125, Denominator: 1000}}},
+		},
+	}}
+
+	testCases := []struct {
+		name                    string
+		version                 gatewayAPIVersion
+		expectedMirrorFractions map[types.NamespacedName][]MirrorFraction
+		expectedNotification    string
+	}{
+		{
+			name:    "fractions of Gateway API v1.3",
+			version: gatewayAPIVersion{major: 1, minor: 3},
+			expectedMirrorFractions: map[types.NamespacedName][]MirrorFraction{
+				key: {{Matches: matches, BackendRef: gatewayv1.BackendObjectReference{Name: "audit"}, Numerator: 125, Denominator: 1000}},
+			},
+		},
+		{
+			name:                 "all the requests mirrored for earlier versions",
+			version:              gatewayAPIVersion{major: 1, minor: 1},
+			expectedNotification: "HTTPRoute default/route mirrors all the requests to audit instead of 12.5% of them",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr = notifications.NotificationAggregator{Notifications: map[string][]notifications.Notification{}}
+			gatewayResources := GatewayResources{HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{key: httpRoute}}
+
+			addMirrorFractions("test", ir, &gatewayResources, tc.version)
+
+			require.Equal(t, tc.expectedMirrorFractions, gatewayResources.MirrorFractions)
+			if tc.expectedNotification == "" {
+				require.Empty(t, notifications.NotificationAggr.Notifications["test"])
+				return
+			}
+			require.Len(t, notifications.NotificationAggr.Notifications["test"], 1)
+			require.Contains(t, notifications.NotificationAggr.Notifications["test"][0].Message, tc.expectedNotification)
+		})
+	}
+}
+
+func Test_HTTPRouteObject(t *testing.T) {
+	matches := func(path string) []gatewayv1.HTTPRouteMatch {
+		return []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To(path)}}}
+	}
+	backendRef := gatewayv1.BackendObjectReference{Name: "shadow"}
+	filters := []gatewayv1.HTTPRouteFilter{{
+		Type:          gatewayv1.HTTPRouteFilterRequestMirror,
+		RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: backendRef},
+	}}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+		Spec: gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{
+			{Matches: matches("/a"), Filters: filters},
+			{Matches: matches("/b"), Filters: filters},
+			{Matches: matches("/c"), Filters: filters},
+		}},
+	}
+
+	require.Equal(t, &httpRoute, HTTPRouteObject(httpRoute, nil))
+
+	object := HTTPRouteObject(httpRoute, []MirrorFraction{
+		{Matches: matches("/a"), BackendRef: backendRef, Numerator: 50, Denominator: 100},
+		{Matches: matches("/b"), BackendRef: backendRef, Numerator: 125, Denominator: 1000},
+	})
+	content := object.(*unstructured.Unstructured).Object
+	require.Equal(t, "HTTPRoute", object.GetObjectKind().GroupVersionKind().Kind)
+	rules, _, _ := unstructured.NestedSlice(content, "spec", "rules")
+	requestMirror := func(rule int) map[string]interface{} {
+		filters := rules[rule].(map[string]interface{})["filters"].([]interface{})
+		return filters[0].(map[string]interface{})["requestMirror"].(map[string]interface{})
+	}
+	require.Equal(t, int64(50), requestMirror(0)["percent"])
+	require.Equal(t, map[string]interface{}{"numerator": int64(125), "denominator": int64(1000)}, requestMirror(1)["fraction"])
+	require.NotContains(t, requestMirror(2), "percent")
+	require.NotContains(t, requestMirror(2), "fraction")
+}
@@ -47,6 +47,15 @@ type Notification struct {
 type NotificationAggregator struct {
 	mutex         sync.Mutex
 	Notifications map[string][]Notification
+
+	// Redact indicates whether sensitive values, such as credentials and
+	// Secret names, should be masked when creating the notification tables
+	// and in the notifications sent to the sinks.
+	Redact bool
+
+	// sinks are the subscribed sinks, by subscription id.
+	sinks      map[int]Sink
+	nextSinkID int
 }
 
 var NotificationAggr NotificationAggregator
@@ -55,7 +64,35 @@ var NotificationAggr NotificationAggregator
 func (na *NotificationAggregator) DispatchNotification(notification Notification, ProviderName string) {
 	na.mutex.Lock()
 	na.Notifications[ProviderName] = append(na.Notifications[ProviderName], notification)
+	sinks := na.sortedSinks()
+	redact := na.Redact
 	na.mutex.Unlock()
+
+	if len(sinks) == 0 {
+		return
+	}
+	if redact {
+		notification = redactNotification(notification)
+	}
+	for _, sink := range sinks {
+		sink(ProviderName, notification)
+	}
+}
+
+// ProviderNotifications returns a copy of the notifications of the provider,
+// redacted if needed.
+func (na *NotificationAggregator) ProviderNotifications(providerName string) []Notification {
+	na.mutex.Lock()
+	defer na.mutex.Unlock()
+
+	providerNotifications := make([]Notification, 0, len(na.Notifications[providerName]))
+	for _, n := range na.Notifications[providerName] {
+		if na.Redact {
+			n = redactNotification(n)
+		}
+		providerNotifications = append(providerNotifications, n)
+	}
+	return providerNotifications
 }
 
 // CreateNotificationTables takes all generated notifications and returns a map[string]string
@@ -72,7 +109,11 @@ func (na *NotificationAggregator) CreateNotificationTables() map[string]string {
 		t.SetRowLine(true)
 
 		for _, n := range msgs {
-			row := []string{string(n.Type), n.Message, convertObjectsToStr(n.CallingObjects)}
+			message := n.Message
+			if na.Redact {
+				message = RedactString(message)
+			}
+			row := []string{string(n.Type), message, convertObjectsToStr(n.CallingObjects, na.Redact)}
 			t.Append(row)
 		}
 
@@ -84,15 +125,19 @@ func (na *NotificationAggregator) CreateNotificationTables() map[string]string {
 	return notificationTablesMap
 }
 
-func convertObjectsToStr(ob []client.Object) string {
+func convertObjectsToStr(ob []client.Object, redact bool) string {
 	var sb strings.Builder
 
 	for i, o := range ob {
 		if i > 0 {
 			sb.WriteString(", ")
 		}
-		object := o.GetObjectKind().GroupVersionKind().Kind + ": " + client.ObjectKeyFromObject(o).String()
-		sb.WriteString(object)
+		kind := o.GetObjectKind().GroupVersionKind().Kind
+		key := client.ObjectKeyFromObject(o)
+		if redact && isSecret(o) {
+			key.Name = RedactName(key.Name)
+		}
+		sb.WriteString(kind + ": " + key.String())
 	}
 
 	return sb.String()
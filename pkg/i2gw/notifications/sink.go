@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import "sort"
+
+// Sink receives the notifications as they are dispatched, along with the name
+// of the provider dispatching them. It lets the programs embedding the
+// converter surface the notifications in their own way, instead of the
+// notification tables.
+//
+// Sinks are called synchronously by DispatchNotification, possibly from
+// several goroutines, so they must be thread-safe and return quickly.
+type Sink func(providerName string, notification Notification)
+
+// ProviderNotification is a notification along with the name of the provider
+// dispatching it.
+type ProviderNotification struct {
+	ProviderName string
+	Notification
+}
+
+// ChannelSink returns a Sink sending the notifications to the given channel.
+// As the sink blocks until the notification is received, the channel must be
+// buffered or consumed concurrently with the conversion.
+func ChannelSink(ch chan<- ProviderNotification) Sink {
+	return func(providerName string, notification Notification) {
+		ch <- ProviderNotification{ProviderName: providerName, Notification: notification}
+	}
+}
+
+// Subscribe registers the sink to receive the notifications dispatched from
+// now on, in addition to aggregating them. The message of the notifications
+// is redacted when Redact is set. It returns a function unsubscribing the
+// sink.
+func (na *NotificationAggregator) Subscribe(sink Sink) (unsubscribe func()) {
+	na.mutex.Lock()
+	defer na.mutex.Unlock()
+	if na.sinks == nil {
+		na.sinks = map[int]Sink{}
+	}
+	id := na.nextSinkID
+	na.nextSinkID++
+	na.sinks[id] = sink
+
+	return func() {
+		na.mutex.Lock()
+		defer na.mutex.Unlock()
+		delete(na.sinks, id)
+	}
+}
+
+// sortedSinks returns the subscribed sinks in subscription order. The mutex
+// must be held.
+func (na *NotificationAggregator) sortedSinks() []Sink {
+	ids := make([]int, 0, len(na.sinks))
+	for id := range na.sinks {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	sinks := make([]Sink, 0, len(ids))
+	for _, id := range ids {
+		sinks = append(sinks, na.sinks[id])
+	}
+	return sinks
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Overrides is the content of the file of the --override flag, forcing fields
+// of the HTTPRoutes generated from given source resources, for the cases the
+// conversion heuristics get wrong.
+type Overrides struct {
+	Overrides []Override `json:"overrides"`
+}
+
+// Override forces fields of the HTTPRoutes generated from a source resource.
+type Override struct {
+	Source OverrideSource `json:"source"`
+	// PathType, when set, replaces the type of the path matches.
+	PathType *gatewayv1.PathMatchType `json:"pathType,omitempty"`
+	// BackendPort, when set, replaces the port of the backend references, to
+	// BackendService only if set.
+	BackendPort    *gatewayv1.PortNumber `json:"backendPort,omitempty"`
+	BackendService string                `json:"backendService,omitempty"`
+	// SectionName, when set, attaches the HTTPRoutes to this listener of
+	// their parent Gateways.
+	SectionName *gatewayv1.SectionName `json:"sectionName,omitempty"`
+}
+
+// OverrideSource identifies a source resource, e.g. an Ingress, by its kind,
+// namespace and name.
+type OverrideSource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+func (s OverrideSource) String() string {
+	if s.Namespace == "" {
+		return fmt.Sprintf("%s %s", s.Kind, s.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", s.Kind, s.Namespace, s.Name)
+}
+
+var supportedOverridePathTypes = []gatewayv1.PathMatchType{
+	gatewayv1.PathMatchExact,
+	gatewayv1.PathMatchPathPrefix,
+	gatewayv1.PathMatchRegularExpression,
+}
+
+// readOverrides reads and validates the overrides of the given file. An empty
+// path means no overrides.
+func readOverrides(path string) ([]Override, error) {
+	if path == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the overrides file: %w", err)
+	}
+	var overrides Overrides
+	if err = yaml.UnmarshalStrict(content, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse the overrides file %s: %w", path, err)
+	}
+	for i, override := range overrides.Overrides {
+		if override.Source.Kind == "" || override.Source.Name == "" {
+			return nil, fmt.Errorf("override %d of %s: the kind and name of the source are required", i, path)
+		}
+		if override.PathType != nil && !slices.Contains(supportedOverridePathTypes, *override.PathType) {
+			return nil, fmt.Errorf("override %d of %s: %s is not a supported path type, supported values are %v", i, path, *override.PathType, supportedOverridePathTypes)
+		}
+		if override.BackendService != "" && override.BackendPort == nil {
+			return nil, fmt.Errorf("override %d of %s: backendService requires backendPort", i, path)
+		}
+		if override.PathType == nil && override.BackendPort == nil && override.SectionName == nil {
+			return nil, fmt.Errorf("override %d of %s: no field of %s is overridden", i, path, override.Source)
+		}
+	}
+	return overrides.Overrides, nil
+}
+
+// applyOverrides forces the fields of the IR HTTPRoutes generated from the
+// sources of the overrides. The HTTPRoutes merged from several sources are
+// overridden as a whole, as their rules can't be told apart, which is
+// notified: --no-route-merge restricts the overrides to the rules of their
+// sources.
+func applyOverrides(providerName ProviderName, overrides []Override, ir *intermediate.IR) {
+	notify := func(messageType notifications.MessageType, message string, objects ...client.Object) {
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(messageType, message, objects...), string(providerName))
+	}
+	for _, override := range overrides {
+		matched := false
+		for _, key := range sortedNamespacedNames(ir.HTTPRoutes) {
+			httpRouteContext := ir.HTTPRoutes[key]
+			i := slices.IndexFunc(httpRouteContext.Sources, override.Source.matches)
+			if i < 0 {
+				continue
+			}
+			matched = true
+			source := httpRouteContext.Sources[i]
+			overrideHTTPRoute(override, &httpRouteContext.HTTPRoute)
+			ir.HTTPRoutes[key] = httpRouteContext
+			notify(notifications.InfoNotification, fmt.Sprintf("HTTPRoute %s was overridden for %s", key, override.Source), source)
+			if len(httpRouteContext.Sources) > 1 {
+				notify(notifications.WarningNotification, fmt.Sprintf("HTTPRoute %s is merged from several sources, the override of %s applies to all its rules, use --no-route-merge to restrict it", key, override.Source), source)
+			}
+		}
+		if !matched {
+			notify(notifications.WarningNotification, fmt.Sprintf("no HTTPRoute generated by the %s provider from %s, the override was not applied", providerName, override.Source))
+		}
+	}
+}
+
+// matches returns whether the source resource is the given object, of which
+// the kind is the name of its type when unset.
+func (s OverrideSource) matches(object client.Object) bool {
+	kind := object.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		kind = reflect.TypeOf(object).Elem().Name()
+	}
+	return kind == s.Kind && object.GetNamespace() == s.Namespace && object.GetName() == s.Name
+}
+
+func overrideHTTPRoute(override Override, httpRoute *gatewayv1.HTTPRoute) {
+	if override.SectionName != nil {
+		for i := range httpRoute.Spec.ParentRefs {
+			httpRoute.Spec.ParentRefs[i].SectionName = ptr.To(*override.SectionName)
+		}
+	}
+	for i := range httpRoute.Spec.Rules {
+		rule := &httpRoute.Spec.Rules[i]
+		if override.PathType != nil {
+			for j := range rule.Matches {
+				if rule.Matches[j].Path != nil {
+					rule.Matches[j].Path.Type = ptr.To(*override.PathType)
+				}
+			}
+		}
+		if override.BackendPort != nil {
+			for j := range rule.BackendRefs {
+				backendRef := &rule.BackendRefs[j]
+				if override.BackendService != "" && string(backendRef.Name) != override.BackendService {
+					continue
+				}
+				if backendRef.Kind != nil && *backendRef.Kind != "Service" {
+					continue
+				}
+				backendRef.Port = ptr.To(*override.BackendPort)
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_readOverrides(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		expected      []Override
+		expectedError bool
+	}{
+		{
+			name: "valid overrides",
+			content: `overrides:
+- source:
+    kind: Ingress
+    namespace: default
+    name: shop
+  pathType: Exact
+  backendPort: 8080
+  backendService: shop
+  sectionName: https
+`,
+			expected: []Override{{
+				Source:         OverrideSource{Kind: "Ingress", Namespace: "default", Name: "shop"},
+				PathType:       ptr.To(gatewayv1.PathMatchExact),
+				BackendPort:    ptr.To[gatewayv1.PortNumber](8080),
+				BackendService: "shop",
+				SectionName:    ptr.To[gatewayv1.SectionName]("https"),
+			}},
+		},
+		{
+			name:          "unknown field",
+			content:       "overrides:\n- source: {kind: Ingress, name: shop}\n  port: 8080\n",
+			expectedError: true,
+		},
+		{
+			name:          "unsupported path type",
+			content:       "overrides:\n- source: {kind: Ingress, name: shop}\n  pathType: ImplementationSpecific\n",
+			expectedError: true,
+		},
+		{
+			name:          "nothing overridden",
+			content:       "overrides:\n- source: {kind: Ingress, name: shop}\n",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "overrides.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0o600))
+			overrides, err := readOverrides(path)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, overrides)
+		})
+	}
+}
+
+func Test_applyOverrides(t *testing.T) {
+	shop := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "shop"}}
+	other := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"}}
+	httpRoute := func(sources ...client.Object) intermediate.HTTPRouteContext {
+		return intermediate.HTTPRouteContext{
+			HTTPRoute: gatewayv1.HTTPRoute{
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}}},
+					Rules: []gatewayv1.HTTPRouteRule{{
+						Matches: []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/api")}}},
+						BackendRefs: []gatewayv1.HTTPBackendRef{
+							{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "shop", Port: ptr.To[gatewayv1.PortNumber](80)}}},
+							{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "cart", Port: ptr.To[gatewayv1.PortNumber](80)}}},
+						},
+					}},
+				},
+			},
+			Sources: sources,
+		}
+	}
+	shopKey := types.NamespacedName{Namespace: "default", Name: "shop"}
+	otherKey := types.NamespacedName{Namespace: "default", Name: "other"}
+	ir := intermediate.IR{HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+		shopKey:  httpRoute(shop),
+		otherKey: httpRoute(other),
+	}}
+
+	applyOverrides("ingress-nginx", []Override{{
+		Source:         OverrideSource{Kind: "Ingress", Namespace: "default", Name: "shop"},
+		PathType:       ptr.To(gatewayv1.PathMatchExact),
+		BackendPort:    ptr.To[gatewayv1.PortNumber](8080),
+		BackendService: "shop",
+		SectionName:    ptr.To[gatewayv1.SectionName]("https"),
+	}}, &ir)
+
+	overridden := ir.HTTPRoutes[shopKey].Spec
+	require.Equal(t, ptr.To[gatewayv1.SectionName]("https"), overridden.ParentRefs[0].SectionName)
+	require.Equal(t, ptr.To(gatewayv1.PathMatchExact), overridden.Rules[0].Matches[0].Path.Type)
+	require.Equal(t, ptr.To[gatewayv1.PortNumber](8080), overridden.Rules[0].BackendRefs[0].Port)
+	require.Equal(t, ptr.To[gatewayv1.PortNumber](80), overridden.Rules[0].BackendRefs[1].Port)
+	require.Equal(t, httpRoute(other).Spec, ir.HTTPRoutes[otherKey].Spec)
+}
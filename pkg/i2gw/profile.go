@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+)
+
+// ProfileName is a string alias that stores the name of a conversion Profile.
+type ProfileName string
+
+const (
+	// ConservativeProfile only generates resources of the Gateway API standard
+	// channel and does not guess the intent of the source resources.
+	ConservativeProfile ProfileName = "conservative"
+	// BalancedProfile is the default profile.
+	BalancedProfile ProfileName = "balanced"
+	// AggressiveProfile enables every heuristic, favoring fidelity over safety.
+	AggressiveProfile ProfileName = "aggressive"
+)
+
+// Profile bundles the decisions trading conversion fidelity for safety.
+// Providers read it from the ProviderConf and apply the decisions relevant
+// to the features they support.
+type Profile struct {
+	// ExperimentalFeatures indicates whether resources and fields of the
+	// Gateway API experimental channel, e.g. TLSRoutes, TCPRoutes or
+	// BackendTLSPolicies, may be
+	// generated.
+	ExperimentalFeatures bool
+
+	// GRPCDetection indicates whether gRPC backends may be detected
+	// heuristically, e.g. from port names. The backends explicitly declared
+	// as gRPC are always converted.
+	GRPCDetection bool
+
+	// SnippetParsing indicates whether configuration snippets embedded in
+	// annotations may be parsed and converted.
+	SnippetParsing bool
+
+	// RouteMerging indicates whether the rules of different source resources
+	// matching the same hostname may be merged into a single route.
+	RouteMerging bool
+}
+
+// profiles contains the Profile by ProfileName.
+var profiles = map[ProfileName]Profile{
+	ConservativeProfile: {},
+	BalancedProfile: {
+		ExperimentalFeatures: true,
+		RouteMerging:         true,
+	},
+	AggressiveProfile: {
+		ExperimentalFeatures: true,
+		GRPCDetection:        true,
+		SnippetParsing:       true,
+		RouteMerging:         true,
+	},
+}
+
+// GetProfile returns the Profile with the given name. An empty name returns
+// the BalancedProfile.
+func GetProfile(name ProfileName) (Profile, error) {
+	if name == "" {
+		name = BalancedProfile
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%s is not a supported profile, supported values are %v", name, GetSupportedProfiles())
+	}
+	return profile, nil
+}
+
+// GetSupportedProfiles returns the sorted names of all the supported profiles.
+func GetSupportedProfiles() []string {
+	supportedProfiles := make([]string, 0, len(profiles))
+	for name := range profiles {
+		supportedProfiles = append(supportedProfiles, string(name))
+	}
+	slices.Sort(supportedProfiles)
+	return supportedProfiles
+}
+
+// removeExperimentalResources removes the resources of the Gateway API
+// experimental channel from the given GatewayResources, notifying about each
+// removed resource.
+func removeExperimentalResources(providerName ProviderName, gatewayResources *GatewayResources) {
+	for key, tlsRoute := range gatewayResources.TLSRoutes {
+		tlsRoute := tlsRoute
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification,
+			fmt.Sprintf("TLSRoute %s was not generated since experimental features are disabled by the profile", key), &tlsRoute), string(providerName))
+		delete(gatewayResources.TLSRoutes, key)
+	}
+	for key, tcpRoute := range gatewayResources.TCPRoutes {
+		tcpRoute := tcpRoute
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification,
+			fmt.Sprintf("TCPRoute %s was not generated since experimental features are disabled by the profile", key), &tcpRoute), string(providerName))
+		delete(gatewayResources.TCPRoutes, key)
+	}
+	for key, udpRoute := range gatewayResources.UDPRoutes {
+		udpRoute := udpRoute
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification,
+			fmt.Sprintf("UDPRoute %s was not generated since experimental features are disabled by the profile", key), &udpRoute), string(providerName))
+		delete(gatewayResources.UDPRoutes, key)
+	}
+	for key, backendTLSPolicy := range gatewayResources.BackendTLSPolicies {
+		backendTLSPolicy := backendTLSPolicy
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification,
+			fmt.Sprintf("BackendTLSPolicy %s was not generated since experimental features are disabled by the profile", key), &backendTLSPolicy), string(providerName))
+		delete(gatewayResources.BackendTLSPolicies, key)
+	}
+	for key, backendLBPolicy := range gatewayResources.BackendLBPolicies {
+		backendLBPolicy := backendLBPolicy
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification,
+			fmt.Sprintf("BackendLBPolicy %s was not generated since experimental features are disabled by the profile", key), &backendLBPolicy), string(providerName))
+		delete(gatewayResources.BackendLBPolicies, key)
+	}
+}
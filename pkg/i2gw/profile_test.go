@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+)
+
+func Test_GetProfile(t *testing.T) {
+	testCases := []struct {
+		name            string
+		profileName     ProfileName
+		expectedProfile Profile
+		expectingError  bool
+	}{
+		{
+			name:        "default profile",
+			profileName: "",
+			expectedProfile: Profile{
+				ExperimentalFeatures: true,
+				RouteMerging:         true,
+			},
+		},
+		{
+			name:            "conservative profile",
+			profileName:     ConservativeProfile,
+			expectedProfile: Profile{},
+		},
+		{
+			name:        "aggressive profile",
+			profileName: AggressiveProfile,
+			expectedProfile: Profile{
+				ExperimentalFeatures: true,
+				GRPCDetection:        true,
+				SnippetParsing:       true,
+				RouteMerging:         true,
+			},
+		},
+		{
+			name:           "unknown profile",
+			profileName:    "reckless",
+			expectingError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			profile, err := GetProfile(tc.profileName)
+			if tc.expectingError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if profile != tc.expectedProfile {
+				t.Errorf("GetProfile(%q) = %+v, expected %+v", tc.profileName, profile, tc.expectedProfile)
+			}
+		})
+	}
+}
+
+func Test_removeExperimentalResources(t *testing.T) {
+	key := types.NamespacedName{Namespace: "default", Name: "route"}
+	gatewayResources := GatewayResources{
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{key: {}},
+		GRPCRoutes: map[types.NamespacedName]gatewayv1.GRPCRoute{key: {}},
+		TLSRoutes:  map[types.NamespacedName]gatewayv1alpha2.TLSRoute{key: {}},
+		TCPRoutes:  map[types.NamespacedName]gatewayv1alpha2.TCPRoute{key: {}},
+		UDPRoutes:  map[types.NamespacedName]gatewayv1alpha2.UDPRoute{key: {}},
+
+		BackendTLSPolicies: map[types.NamespacedName]gatewayv1alpha3.BackendTLSPolicy{key: {}},
+		BackendLBPolicies:  map[types.NamespacedName]gatewayv1alpha2.BackendLBPolicy{key: {}},
+	}
+
+	removeExperimentalResources("test", &gatewayResources)
+
+	if len(gatewayResources.HTTPRoutes) != 1 || len(gatewayResources.GRPCRoutes) != 1 {
+		t.Errorf("Expected HTTPRoutes and GRPCRoutes to be kept, got %+v", gatewayResources)
+	}
+	if len(gatewayResources.TLSRoutes)+len(gatewayResources.TCPRoutes)+len(gatewayResources.UDPRoutes)+len(gatewayResources.BackendTLSPolicies)+len(gatewayResources.BackendLBPolicies) != 0 {
+		t.Errorf("Expected experimental routes to be removed, got %+v", gatewayResources)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
@@ -49,6 +50,32 @@ type ProviderConf struct {
 	Client                client.Client
 	Namespace             string
 	ProviderSpecificFlags map[string]map[string]string
+
+	// Mesh indicates whether routes for east-west traffic should be attached
+	// to Services, as defined by GAMMA, instead of Gateways. Providers which
+	// do not support mesh traffic ignore it.
+	Mesh bool
+
+	// ClusterDomain is the domain of the cluster-local Service hostnames, the
+	// DefaultClusterDomain of the common package if empty.
+	ClusterDomain string
+
+	// Profile holds the fidelity versus safety decisions of the conversion.
+	Profile Profile
+
+	// IngressClaimer decides which provider converts each Ingress. Providers
+	// reading Ingresses must only keep the ones claimed for them.
+	IngressClaimer *IngressClaimer
+
+	// BackendTLSWellKnownCACertificates, when set, is used to validate the
+	// BackendTLSPolicies generated for backends without CA certificates.
+	// Otherwise, such BackendTLSPolicies are not generated as they would fail
+	// validation.
+	BackendTLSWellKnownCACertificates gatewayv1alpha3.WellKnownCACertificatesType
+
+	// FeatureToggles selects the FeatureParsers the providers run. Providers
+	// with a FeatureChain use its Middleware.
+	FeatureToggles *FeatureToggles
 }
 
 // The Provider interface specifies the required functionality which needs to be
@@ -95,6 +122,11 @@ type ImplementationSpecificHTTPPathTypeMatchConverter func(*gatewayv1.HTTPPathMa
 // implementation-specific fields of the ingress API.
 type ProviderImplementationSpecificOptions struct {
 	ToImplementationSpecificHTTPPathTypeMatch ImplementationSpecificHTTPPathTypeMatchConverter
+
+	// DisableRouteMerging, when set, generates the HTTPRoutes of each Ingress
+	// on its own, instead of merging the rules of all the Ingresses matching
+	// the same host into a single HTTPRoute.
+	DisableRouteMerging bool
 }
 
 // GatewayResources contains all Gateway-API objects and provider Gateway
@@ -104,20 +136,40 @@ type GatewayResources struct {
 	GatewayClasses map[types.NamespacedName]gatewayv1.GatewayClass
 
 	HTTPRoutes map[types.NamespacedName]gatewayv1.HTTPRoute
+	GRPCRoutes map[types.NamespacedName]gatewayv1.GRPCRoute
 	TLSRoutes  map[types.NamespacedName]gatewayv1alpha2.TLSRoute
 	TCPRoutes  map[types.NamespacedName]gatewayv1alpha2.TCPRoute
 	UDPRoutes  map[types.NamespacedName]gatewayv1alpha2.UDPRoute
 
 	ReferenceGrants map[types.NamespacedName]gatewayv1beta1.ReferenceGrant
 
+	BackendTLSPolicies map[types.NamespacedName]gatewayv1alpha3.BackendTLSPolicy
+	BackendLBPolicies  map[types.NamespacedName]gatewayv1alpha2.BackendLBPolicy
+
 	GatewayExtensions []unstructured.Unstructured
+
+	// AnnotatedSources contains the source resources annotated with the
+	// summary of their conversion. It is only populated when
+	// ConversionOptions.AnnotateSources is set.
+	AnnotatedSources []client.Object
+
+	// UnsupportedFeatures contains the features of the source resources
+	// without Gateway API equivalent, by HTTPRoute.
+	UnsupportedFeatures map[types.NamespacedName][]intermediate.UnsupportedFeature
+
+	// MirrorFractions contains the fractions of the requests the
+	// RequestMirror filters of the HTTPRoutes mirror, by HTTPRoute. It is only
+	// populated when ConversionOptions.GatewayAPIVersion is v1.3 or later, the
+	// printed HTTPRoutes being completed by HTTPRouteObject.
+	MirrorFractions map[types.NamespacedName][]MirrorFraction
 }
 
 // FeatureParser is a function that reads the Ingresses, and applies
 // the appropriate modifications to the GatewayResources.
 //
-// Different FeatureParsers will run in undetermined order. The function must
-// modify / create only the required fields of the gateway resources and nothing else.
+// FeatureParsers run in the order of the FeatureChain they are declared in,
+// which honors their declared dependencies. The function must modify / create
+// only the required fields of the gateway resources and nothing else.
 type FeatureParser func([]networkingv1.Ingress, *intermediate.IR) field.ErrorList
 
 var providerSpecificFlagDefinitions = providerSpecificFlags{
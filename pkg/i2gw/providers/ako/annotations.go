@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ako
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+const (
+	// akoAnnotationDomain is the domain of the AKO annotations, which are
+	// also set under its subdomains, e.g. passthrough.ako.vmware.com.
+	akoAnnotationDomain = "ako.vmware.com"
+
+	passthroughAnnotation = "passthrough.ako.vmware.com/enabled"
+)
+
+// annotationsFeature warns about the AKO annotations of the Ingresses, other
+// than the passthrough one, which have no Gateway API equivalent.
+func annotationsFeature(ingresses []networkingv1.Ingress, _ *intermediate.IR) field.ErrorList {
+	for _, ingress := range ingresses {
+		annotations := make([]string, 0, len(ingress.Annotations))
+		for annotation := range ingress.Annotations {
+			annotations = append(annotations, annotation)
+		}
+		slices.Sort(annotations)
+		for _, annotation := range annotations {
+			if annotation == passthroughAnnotation || !isAKOAnnotation(annotation) {
+				continue
+			}
+			notify(notifications.WarningNotification, fmt.Sprintf("%q annotation of ingress %s/%s has no Gateway API equivalent, it was not converted", annotation, ingress.Namespace, ingress.Name), &ingress)
+		}
+	}
+	return nil
+}
+
+func isAKOAnnotation(annotation string) bool {
+	domain, _, found := strings.Cut(annotation, "/")
+	return found && (domain == akoAnnotationDomain || strings.HasSuffix(domain, "."+akoAnnotationDomain))
+}
+
+func isPassthrough(ingress networkingv1.Ingress) bool {
+	return strings.EqualFold(ingress.Annotations[passthroughAnnotation], "true")
+}
+
+// passthroughToIR converts the Ingresses passing TLS through to their
+// backends: each host gets a TLS listener in Passthrough mode, and a TLSRoute
+// to the backend of its root path, the only path AKO supports for them.
+func passthroughToIR(ingresses []networkingv1.Ingress) (intermediate.IR, field.ErrorList) {
+	ir := intermediate.IR{
+		Gateways:  map[types.NamespacedName]intermediate.GatewayContext{},
+		TLSRoutes: map[types.NamespacedName]gatewayv1alpha2.TLSRoute{},
+	}
+
+	var errs field.ErrorList
+	for _, ingress := range ingresses {
+		ingressClass := common.GetIngressClass(ingress)
+		gatewayKey := types.NamespacedName{Namespace: ingress.Namespace, Name: ingressClass}
+		rulesPath := field.NewPath(ingress.Namespace, ingress.Name, "spec", "rules")
+		if len(ingress.Spec.TLS) > 0 {
+			notify(notifications.InfoNotification, fmt.Sprintf("TLS certificates of passthrough ingress %s/%s were ignored, TLS is terminated by its backends", ingress.Namespace, ingress.Name), &ingress)
+		}
+
+		for i, rule := range ingress.Spec.Rules {
+			if common.MatchesAllHosts(rule.Host) {
+				notify(notifications.WarningNotification, fmt.Sprintf("rule %d of passthrough ingress %s/%s has no host to route the TLS connections by, it was not converted", i, ingress.Namespace, ingress.Name), &ingress)
+				continue
+			}
+			if rule.HTTP == nil || len(rule.HTTP.Paths) == 0 {
+				continue
+			}
+			pathIndex := slices.IndexFunc(rule.HTTP.Paths, func(path networkingv1.HTTPIngressPath) bool { return path.Path == "/" || path.Path == "" })
+			if pathIndex < 0 || len(rule.HTTP.Paths) > 1 {
+				pathIndex = max(pathIndex, 0)
+				notify(notifications.WarningNotification, fmt.Sprintf("passthrough ingress %s/%s routes the TLS connections of host %s to the backend of path %q, its other paths were ignored", ingress.Namespace, ingress.Name, rule.Host, rule.HTTP.Paths[pathIndex].Path), &ingress)
+			}
+			backendPath := rulesPath.Index(i).Child("http", "paths").Index(pathIndex).Child("backend")
+			backendRef, err := common.ToBackendRef(rule.HTTP.Paths[pathIndex].Backend, backendPath)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			listener := gatewayv1.Listener{
+				Name:     gatewayv1.SectionName(fmt.Sprintf("%s-tls", common.NameFromHost(rule.Host))),
+				Hostname: ptr.To(gatewayv1.Hostname(rule.Host)),
+				Port:     443,
+				Protocol: gatewayv1.TLSProtocolType,
+				TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModePassthrough)},
+			}
+			gatewayContext, ok := ir.Gateways[gatewayKey]
+			if !ok {
+				gatewayContext.Gateway = gatewayv1.Gateway{
+					ObjectMeta: metav1.ObjectMeta{Namespace: gatewayKey.Namespace, Name: gatewayKey.Name},
+					Spec:       gatewayv1.GatewaySpec{GatewayClassName: gatewayv1.ObjectName(ingressClass)},
+				}
+				gatewayContext.Gateway.SetGroupVersionKind(common.GatewayGVK)
+			}
+			if !slices.ContainsFunc(gatewayContext.Spec.Listeners, func(l gatewayv1.Listener) bool { return l.Name == listener.Name }) {
+				gatewayContext.Spec.Listeners = append(gatewayContext.Spec.Listeners, listener)
+			}
+			ir.Gateways[gatewayKey] = gatewayContext
+
+			tlsRoute := gatewayv1alpha2.TLSRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: ingress.Namespace, Name: common.RouteName(ingress.Name, rule.Host)},
+				Spec: gatewayv1alpha2.TLSRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{
+						ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gatewayKey.Name), SectionName: ptr.To(listener.Name)}},
+					},
+					Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(rule.Host)},
+					Rules:     []gatewayv1alpha2.TLSRouteRule{{BackendRefs: []gatewayv1.BackendRef{*backendRef}}},
+				},
+			}
+			tlsRoute.SetGroupVersionKind(common.TLSRouteGVK)
+			ir.TLSRoutes[types.NamespacedName{Namespace: tlsRoute.Namespace, Name: tlsRoute.Name}] = tlsRoute
+			notify(notifications.InfoNotification, fmt.Sprintf("converted passthrough ingress %s/%s to TLSRoute %s/%s", ingress.Namespace, ingress.Name, tlsRoute.Namespace, tlsRoute.Name), &ingress)
+		}
+	}
+	return ir, errs
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ako
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+)
+
+// resourcesToIRConverter implements the ToIR function of i2gw.ResourcesToIRConverter interface.
+type resourcesToIRConverter struct {
+	featureChain                  *i2gw.FeatureChain
+	implementationSpecificOptions i2gw.ProviderImplementationSpecificOptions
+	wellKnownCACertificates       gatewayv1alpha3.WellKnownCACertificatesType
+}
+
+// newResourcesToIRConverter returns an ako resourcesToIRConverter instance.
+func newResourcesToIRConverter(conf *i2gw.ProviderConf) *resourcesToIRConverter {
+	return &resourcesToIRConverter{
+		featureChain: i2gw.NewFeatureChain(
+			i2gw.NamedFeatureParser{Name: "annotations", Parse: annotationsFeature},
+		).Use(conf.FeatureToggles.Middleware(Name)),
+		implementationSpecificOptions: i2gw.ProviderImplementationSpecificOptions{
+			DisableRouteMerging: !conf.Profile.RouteMerging,
+		},
+		wellKnownCACertificates: conf.BackendTLSWellKnownCACertificates,
+	}
+}
+
+func (c *resourcesToIRConverter) convert(storage *storage) (intermediate.IR, field.ErrorList) {
+	var ingressList, passthroughIngresses []networkingv1.Ingress
+	for _, ingress := range storage.Ingresses {
+		if isPassthrough(*ingress) {
+			passthroughIngresses = append(passthroughIngresses, *ingress)
+		} else {
+			ingressList = append(ingressList, *ingress)
+		}
+	}
+	slices.SortFunc(passthroughIngresses, func(a, b networkingv1.Ingress) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+
+	// Convert plain ingress resources to gateway resources, ignoring all
+	// provider-specific features.
+	ir, errorList := common.ToIR(ingressList, c.implementationSpecificOptions)
+	if len(errorList) > 0 {
+		return intermediate.IR{}, errorList
+	}
+
+	passthroughIR, errorList := passthroughToIR(passthroughIngresses)
+	if len(errorList) > 0 {
+		return intermediate.IR{}, errorList
+	}
+	ir, errorList = intermediate.MergeIRs(ir, passthroughIR)
+	if len(errorList) > 0 {
+		return intermediate.IR{}, errorList
+	}
+
+	// Apply the feature parsing functions to the gateway resources, in order.
+	errorList = append(errorList, c.featureChain.Run(ingressList, &ir)...)
+
+	// The HTTPRules apply first, the HostRules append rules to the
+	// HTTPRoutes, after the indices of the rules of the HTTPRule policies.
+	errorList = append(errorList, c.applyHTTPRules(storage.HTTPRules, &ir)...)
+	errorList = append(errorList, applyHostRules(storage.HostRules, &ir)...)
+
+	return ir, errorList
+}
+
+// sortedKeys returns the keys of the map, sorted.
+func sortedKeys[T any](m map[types.NamespacedName]T) []types.NamespacedName {
+	keys := make([]types.NamespacedName, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b types.NamespacedName) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+	return keys
+}
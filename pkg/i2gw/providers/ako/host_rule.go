@@ -0,0 +1,289 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ako
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// applyHostRules applies the HostRules to the HTTPRoutes of their FQDN, and to
+// the listeners of their Gateways: the certificates of the HostRules replace
+// the ones of the Ingresses, their aliases are added to the hostnames, and
+// their application root path is redirected to. The settings referencing
+// objects of the Avi Controller are recorded as unsupported features.
+func applyHostRules(hostRules map[types.NamespacedName]*HostRule, ir *intermediate.IR) field.ErrorList {
+	var errs field.ErrorList
+	for _, key := range sortedKeys(hostRules) {
+		hostRule := hostRules[key]
+		virtualHost := hostRule.Spec.VirtualHost
+		virtualHostPath := field.NewPath(Name, HostRuleKind).Key(key.String()).Child("spec", "virtualhost")
+		if virtualHost.Fqdn == "" {
+			errs = append(errs, field.Required(virtualHostPath.Child("fqdn"), "the FQDN of the HostRule must be set"))
+			continue
+		}
+
+		var matchesHost func(string) bool
+		switch virtualHost.FqdnType {
+		case "", FqdnTypeExact:
+			matchesHost = func(host string) bool { return host == virtualHost.Fqdn }
+		case FqdnTypeWildcard:
+			suffix := strings.TrimPrefix(virtualHost.Fqdn, "*")
+			matchesHost = func(host string) bool { return host == virtualHost.Fqdn || strings.HasSuffix(host, suffix) }
+		default:
+			notify(notifications.WarningNotification, fmt.Sprintf("FQDN type %s of HostRule %s has no Gateway API equivalent, the HostRule was not converted", virtualHost.FqdnType, key), hostRule)
+			continue
+		}
+
+		var certificateRefs []gatewayv1.SecretObjectReference
+		if virtualHost.TLS != nil {
+			certificateRefs = hostRuleCertificateRefs(hostRule)
+		}
+
+		var matched, redirected bool
+		for _, routeKey := range sortedKeys(ir.HTTPRoutes) {
+			httpRouteContext := ir.HTTPRoutes[routeKey]
+			var hosts []string
+			for _, hostname := range httpRouteContext.Spec.Hostnames {
+				if matchesHost(string(hostname)) {
+					hosts = append(hosts, string(hostname))
+				}
+			}
+			if len(hosts) == 0 {
+				continue
+			}
+			matched = true
+
+			for _, parentRef := range httpRouteContext.Spec.ParentRefs {
+				if (parentRef.Group != nil && *parentRef.Group != gatewayv1.GroupName) || (parentRef.Kind != nil && *parentRef.Kind != "Gateway") {
+					continue
+				}
+				gatewayKey := types.NamespacedName{Namespace: routeKey.Namespace, Name: string(parentRef.Name)}
+				if parentRef.Namespace != nil {
+					gatewayKey.Namespace = string(*parentRef.Namespace)
+				}
+				gatewayContext, ok := ir.Gateways[gatewayKey]
+				if !ok {
+					continue
+				}
+				for _, host := range hosts {
+					if certificateRefs != nil {
+						setListenerCertificates(&gatewayContext.Gateway, host, gatewayCertificateRefs(certificateRefs, hostRule, gatewayKey.Namespace, ir))
+					}
+					for _, alias := range virtualHost.Aliases {
+						addAliasListeners(&gatewayContext.Gateway, host, alias)
+					}
+				}
+				ir.Gateways[gatewayKey] = gatewayContext
+			}
+
+			for _, alias := range virtualHost.Aliases {
+				if !slices.Contains(httpRouteContext.Spec.Hostnames, gatewayv1.Hostname(alias)) {
+					httpRouteContext.Spec.Hostnames = append(httpRouteContext.Spec.Hostnames, gatewayv1.Hostname(alias))
+				}
+			}
+			// The HTTPRoutes of a host are all attached to its listeners,
+			// a single one gets the redirect rule.
+			if virtualHost.ApplicationRootPath != "" && !redirected {
+				httpRouteContext.Spec.Rules = append(httpRouteContext.Spec.Rules, applicationRootRule(virtualHost.ApplicationRootPath))
+				redirected = true
+			}
+			for _, unsupported := range unsupportedVirtualHostFields(virtualHost) {
+				addUnsupportedFeature(&httpRouteContext, HostRuleKind, key, "spec.virtualhost."+unsupported.name, unsupported.raw, hostRule)
+			}
+			ir.HTTPRoutes[routeKey] = httpRouteContext
+		}
+
+		if !matched {
+			notify(notifications.WarningNotification, fmt.Sprintf("HostRule %s matches no host of the converted Ingresses", key), hostRule)
+			continue
+		}
+		if virtualHost.EnableVirtualHost != nil && !*virtualHost.EnableVirtualHost {
+			notify(notifications.WarningNotification, fmt.Sprintf("HostRule %s disables the virtual service of %s, the HTTPRoutes of its hosts were converted nonetheless", key, virtualHost.Fqdn), hostRule)
+		}
+		if virtualHost.UseRegex != nil && *virtualHost.UseRegex {
+			notify(notifications.WarningNotification, fmt.Sprintf("HostRule %s matches the paths of %s as regular expressions, the paths were converted as prefixes", key, virtualHost.Fqdn), hostRule)
+		}
+		if virtualHost.TLS != nil && virtualHost.TLS.SSLProfile != "" {
+			notify(notifications.WarningNotification, fmt.Sprintf("SSL profile %s of HostRule %s is an object of the Avi Controller, it was not converted", virtualHost.TLS.SSLProfile, key), hostRule)
+		}
+	}
+	return errs
+}
+
+// hostRuleCertificateRefs returns the references of the Secrets holding the
+// certificates of a HostRule, in its namespace. Certificates of the Avi
+// Controller must be moved to a Secret named after the FQDN.
+func hostRuleCertificateRefs(hostRule *HostRule) []gatewayv1.SecretObjectReference {
+	var refs []gatewayv1.SecretObjectReference
+	for certificate := &hostRule.Spec.VirtualHost.TLS.SSLKeyCertificate; certificate != nil; certificate = certificate.AlternateCertificate {
+		name := certificate.Name
+		if certificate.Type != CertificateTypeSecret {
+			name = fmt.Sprintf("%s-tls", common.NameFromHost(hostRule.Spec.VirtualHost.Fqdn))
+			if len(refs) > 0 {
+				name = fmt.Sprintf("%s-alternate-tls", common.NameFromHost(hostRule.Spec.VirtualHost.Fqdn))
+			}
+			notify(notifications.WarningNotification, fmt.Sprintf("certificate %s of HostRule %s/%s is an object of the Avi Controller, create the kubernetes.io/tls Secret %s/%s from it", certificate.Name, hostRule.Namespace, hostRule.Name, hostRule.Namespace, name), hostRule)
+		}
+		refs = append(refs, gatewayv1.SecretObjectReference{Name: gatewayv1.ObjectName(name)})
+	}
+	return refs
+}
+
+// gatewayCertificateRefs returns the references of the certificates of a
+// HostRule from a Gateway of the given namespace, with the ReferenceGrant
+// allowing them when the namespaces differ.
+func gatewayCertificateRefs(refs []gatewayv1.SecretObjectReference, hostRule *HostRule, gatewayNamespace string, ir *intermediate.IR) []gatewayv1.SecretObjectReference {
+	if gatewayNamespace == hostRule.Namespace {
+		return slices.Clone(refs)
+	}
+
+	grantKey := types.NamespacedName{Namespace: hostRule.Namespace, Name: fmt.Sprintf("generated-reference-grant-from-%s-to-%s", gatewayNamespace, hostRule.Namespace)}
+	grant, ok := ir.ReferenceGrants[grantKey]
+	if !ok {
+		grant = gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{Namespace: grantKey.Namespace, Name: grantKey.Name},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: gatewayv1.Namespace(gatewayNamespace)}},
+			},
+		}
+		grant.SetGroupVersionKind(common.ReferenceGrantGVK)
+	}
+	namespacedRefs := make([]gatewayv1.SecretObjectReference, 0, len(refs))
+	for _, ref := range refs {
+		ref.Namespace = ptr.To(gatewayv1.Namespace(hostRule.Namespace))
+		namespacedRefs = append(namespacedRefs, ref)
+		to := gatewayv1beta1.ReferenceGrantTo{Group: "", Kind: "Secret", Name: ptr.To(ref.Name)}
+		if !slices.ContainsFunc(grant.Spec.To, func(t gatewayv1beta1.ReferenceGrantTo) bool { return t.Kind == to.Kind && *t.Name == *to.Name }) {
+			grant.Spec.To = append(grant.Spec.To, to)
+		}
+	}
+	if ir.ReferenceGrants == nil {
+		ir.ReferenceGrants = map[types.NamespacedName]gatewayv1beta1.ReferenceGrant{}
+	}
+	ir.ReferenceGrants[grantKey] = grant
+	return namespacedRefs
+}
+
+// setListenerCertificates sets the certificates of the HTTPS listener of the
+// host, which is added when the Ingresses of the host have no TLS.
+func setListenerCertificates(gateway *gatewayv1.Gateway, host string, certificateRefs []gatewayv1.SecretObjectReference) {
+	for i, listener := range gateway.Spec.Listeners {
+		if listener.Hostname != nil && string(*listener.Hostname) == host && listener.Protocol == gatewayv1.HTTPSProtocolType {
+			gateway.Spec.Listeners[i].TLS = &gatewayv1.GatewayTLSConfig{CertificateRefs: certificateRefs}
+			return
+		}
+	}
+	gateway.Spec.Listeners = append(gateway.Spec.Listeners, gatewayv1.Listener{
+		Name:     gatewayv1.SectionName(fmt.Sprintf("%s-https", common.NameFromHost(host))),
+		Hostname: ptr.To(gatewayv1.Hostname(host)),
+		Port:     443,
+		Protocol: gatewayv1.HTTPSProtocolType,
+		TLS:      &gatewayv1.GatewayTLSConfig{CertificateRefs: certificateRefs},
+	})
+}
+
+// addAliasListeners adds copies of the HTTP and HTTPS listeners of the host
+// for its alias.
+func addAliasListeners(gateway *gatewayv1.Gateway, host, alias string) {
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.Hostname == nil || string(*listener.Hostname) != host {
+			continue
+		}
+		if listener.Protocol != gatewayv1.HTTPProtocolType && listener.Protocol != gatewayv1.HTTPSProtocolType {
+			continue
+		}
+		aliasListener := *listener.DeepCopy()
+		aliasListener.Hostname = ptr.To(gatewayv1.Hostname(alias))
+		aliasListener.Name = gatewayv1.SectionName(fmt.Sprintf("%s-%s", common.NameFromHost(alias), strings.ToLower(string(listener.Protocol))))
+		if !slices.ContainsFunc(gateway.Spec.Listeners, func(l gatewayv1.Listener) bool { return l.Name == aliasListener.Name }) {
+			gateway.Spec.Listeners = append(gateway.Spec.Listeners, aliasListener)
+		}
+	}
+}
+
+// applicationRootRule returns the HTTPRoute rule redirecting the requests of
+// the root path to the application root path.
+func applicationRootRule(applicationRootPath string) gatewayv1.HTTPRouteRule {
+	return gatewayv1.HTTPRouteRule{
+		Matches: []gatewayv1.HTTPRouteMatch{{
+			Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchExact), Value: ptr.To("/")},
+		}},
+		Filters: []gatewayv1.HTTPRouteFilter{{
+			Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+			RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+				Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To(applicationRootPath)},
+				StatusCode: ptr.To(302),
+			},
+		}},
+	}
+}
+
+type unsupportedField struct {
+	name string
+	raw  string
+}
+
+// unsupportedVirtualHostFields returns the fields of a HostRule referencing
+// objects of the Avi Controller, which have no Gateway API equivalent.
+func unsupportedVirtualHostFields(virtualHost HostRuleVirtualHost) []unsupportedField {
+	var fields []unsupportedField
+	for _, f := range []struct {
+		name string
+		raw  []byte
+	}{
+		{name: "httpPolicy", raw: virtualHost.HTTPPolicy},
+		{name: "wafPolicy", raw: virtualHost.WAFPolicy},
+		{name: "applicationProfile", raw: virtualHost.ApplicationProfile},
+		{name: "analyticsProfile", raw: virtualHost.AnalyticsProfile},
+		{name: "analyticsPolicy", raw: virtualHost.AnalyticsPolicy},
+		{name: "errorPageProfile", raw: virtualHost.ErrorPageProfile},
+		{name: "icapProfile", raw: virtualHost.ICAPProfile},
+		{name: "datascripts", raw: virtualHost.Datascripts},
+		{name: "gslb", raw: virtualHost.GSLB},
+		{name: "tcpSettings", raw: virtualHost.TCPSettings},
+	} {
+		if len(f.raw) > 0 {
+			fields = append(fields, unsupportedField{name: f.name, raw: string(f.raw)})
+		}
+	}
+	return fields
+}
+
+// addUnsupportedFeature records a field of a HostRule or HTTPRule without
+// Gateway API equivalent as an unsupported feature of the HTTPRoute, so that
+// it can be ported manually.
+func addUnsupportedFeature(httpRouteContext *intermediate.HTTPRouteContext, kind string, source types.NamespacedName, name, rawConfig string, obj client.Object) {
+	feature := intermediate.UnsupportedFeature{SourceKind: kind, Source: source, Name: name, RawConfig: rawConfig}
+	if slices.Contains(httpRouteContext.UnsupportedFeatures, feature) {
+		return
+	}
+	httpRouteContext.UnsupportedFeatures = append(httpRouteContext.UnsupportedFeatures, feature)
+	notify(notifications.WarningNotification, fmt.Sprintf("%s of %s %s has no Gateway API equivalent and must be ported manually", name, kind, source), obj)
+}
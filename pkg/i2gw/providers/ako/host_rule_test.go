@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ako
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// testIR returns the IR of an Ingress of host app.example.com, in the default
+// namespace, with paths / and /api.
+func testIR() intermediate.IR {
+	backendRef := func(name string) []gatewayv1.HTTPBackendRef {
+		return []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+			Name: gatewayv1.ObjectName(name),
+			Port: ptr.To[gatewayv1.PortNumber](80),
+		}}}}
+	}
+	pathPrefix := func(path string) []gatewayv1.HTTPRouteMatch {
+		return []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To(path)}}}
+	}
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: AviIngressClass}
+	routeKey := types.NamespacedName{Namespace: "default", Name: "app-app-example-com"}
+	return intermediate.IR{
+		Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+			gatewayKey: {Gateway: gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Namespace: gatewayKey.Namespace, Name: gatewayKey.Name},
+				Spec: gatewayv1.GatewaySpec{Listeners: []gatewayv1.Listener{{
+					Name:     "app-example-com-http",
+					Hostname: ptr.To[gatewayv1.Hostname]("app.example.com"),
+					Port:     80,
+					Protocol: gatewayv1.HTTPProtocolType,
+				}}},
+			}},
+		},
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			routeKey: {HTTPRoute: gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: AviIngressClass}}},
+					Hostnames:       []gatewayv1.Hostname{"app.example.com"},
+					Rules: []gatewayv1.HTTPRouteRule{
+						{Matches: pathPrefix("/"), BackendRefs: backendRef("web")},
+						{Matches: pathPrefix("/api"), BackendRefs: backendRef("api")},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func Test_applyHostRules(t *testing.T) {
+	testCases := []struct {
+		name                string
+		hostRule            HostRule
+		expectedListeners   []gatewayv1.Listener
+		expectedHostnames   []gatewayv1.Hostname
+		expectedRules       int
+		expectedUnsupported []string
+		expectedGrants      int
+	}{
+		{
+			name: "certificate of another namespace",
+			hostRule: HostRule{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "certs", Name: "app"},
+				Spec: HostRuleSpec{VirtualHost: HostRuleVirtualHost{
+					Fqdn: "app.example.com",
+					TLS:  &HostRuleTLS{SSLKeyCertificate: HostRuleSSLKeyCertificate{Name: "app-cert", Type: CertificateTypeSecret}},
+				}},
+			},
+			expectedListeners: []gatewayv1.Listener{
+				{Name: "app-example-com-http", Hostname: ptr.To[gatewayv1.Hostname]("app.example.com"), Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+				{
+					Name:     "app-example-com-https",
+					Hostname: ptr.To[gatewayv1.Hostname]("app.example.com"),
+					Port:     443,
+					Protocol: gatewayv1.HTTPSProtocolType,
+					TLS: &gatewayv1.GatewayTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{
+						{Name: "app-cert", Namespace: ptr.To[gatewayv1.Namespace]("certs")},
+					}},
+				},
+			},
+			expectedHostnames: []gatewayv1.Hostname{"app.example.com"},
+			expectedRules:     2,
+			expectedGrants:    1,
+		},
+		{
+			name: "wildcard fqdn with alias, root path and WAF policy",
+			hostRule: HostRule{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+				Spec: HostRuleSpec{VirtualHost: HostRuleVirtualHost{
+					Fqdn:                "*.example.com",
+					FqdnType:            FqdnTypeWildcard,
+					Aliases:             []string{"app.example.org"},
+					ApplicationRootPath: "/home",
+					WAFPolicy:           []byte(`"app-waf"`),
+				}},
+			},
+			expectedListeners: []gatewayv1.Listener{
+				{Name: "app-example-com-http", Hostname: ptr.To[gatewayv1.Hostname]("app.example.com"), Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+				{Name: "app-example-org-http", Hostname: ptr.To[gatewayv1.Hostname]("app.example.org"), Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+			},
+			expectedHostnames:   []gatewayv1.Hostname{"app.example.com", "app.example.org"},
+			expectedRules:       3,
+			expectedUnsupported: []string{"spec.virtualhost.wafPolicy"},
+		},
+		{
+			name: "other host",
+			hostRule: HostRule{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"},
+				Spec: HostRuleSpec{VirtualHost: HostRuleVirtualHost{
+					Fqdn: "other.example.com",
+					TLS:  &HostRuleTLS{SSLKeyCertificate: HostRuleSSLKeyCertificate{Name: "other-cert", Type: CertificateTypeSecret}},
+				}},
+			},
+			expectedListeners: []gatewayv1.Listener{
+				{Name: "app-example-com-http", Hostname: ptr.To[gatewayv1.Hostname]("app.example.com"), Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+			},
+			expectedHostnames: []gatewayv1.Hostname{"app.example.com"},
+			expectedRules:     2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir := testIR()
+			key := types.NamespacedName{Namespace: tc.hostRule.Namespace, Name: tc.hostRule.Name}
+			if errs := applyHostRules(map[types.NamespacedName]*HostRule{key: &tc.hostRule}, &ir); len(errs) > 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+
+			gatewayContext := ir.Gateways[types.NamespacedName{Namespace: "default", Name: AviIngressClass}]
+			if diff := cmp.Diff(tc.expectedListeners, gatewayContext.Spec.Listeners); diff != "" {
+				t.Errorf("Unexpected listeners (-want +got): %s", diff)
+			}
+			routeContext := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "app-app-example-com"}]
+			if diff := cmp.Diff(tc.expectedHostnames, routeContext.Spec.Hostnames); diff != "" {
+				t.Errorf("Unexpected hostnames (-want +got): %s", diff)
+			}
+			if len(routeContext.Spec.Rules) != tc.expectedRules {
+				t.Errorf("Expected %d rules, got %d", tc.expectedRules, len(routeContext.Spec.Rules))
+			}
+			var unsupported []string
+			for _, feature := range routeContext.UnsupportedFeatures {
+				unsupported = append(unsupported, feature.Name)
+			}
+			if diff := cmp.Diff(tc.expectedUnsupported, unsupported); diff != "" {
+				t.Errorf("Unexpected unsupported features (-want +got): %s", diff)
+			}
+			if len(ir.ReferenceGrants) != tc.expectedGrants {
+				t.Errorf("Expected %d ReferenceGrants, got %d", tc.expectedGrants, len(ir.ReferenceGrants))
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ako
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+)
+
+// reencryptTLSType is the only TLS type of the HTTPRule paths, re-encrypting
+// the connections to the backends.
+const reencryptTLSType = "reencrypt"
+
+// applyHTTPRules applies the HTTPRules to the HTTPRoute rules of their FQDN
+// matching their paths: their load balancer policies are kept in the
+// provider-specific IR, and BackendTLSPolicies are generated for their
+// reencrypt TLS.
+func (c *resourcesToIRConverter) applyHTTPRules(httpRules map[types.NamespacedName]*HTTPRule, ir *intermediate.IR) field.ErrorList {
+	var errs field.ErrorList
+	for _, key := range sortedKeys(httpRules) {
+		httpRule := httpRules[key]
+		specPath := field.NewPath(Name, HTTPRuleKind).Key(key.String()).Child("spec")
+		if httpRule.Spec.Fqdn == "" {
+			errs = append(errs, field.Required(specPath.Child("fqdn"), "the FQDN of the HTTPRule must be set"))
+			continue
+		}
+
+		for i, path := range httpRule.Spec.Paths {
+			pathPath := specPath.Child("paths").Index(i)
+			var matched bool
+			for _, routeKey := range sortedKeys(ir.HTTPRoutes) {
+				httpRouteContext := ir.HTTPRoutes[routeKey]
+				if !slices.Contains(httpRouteContext.Spec.Hostnames, gatewayv1.Hostname(httpRule.Spec.Fqdn)) {
+					continue
+				}
+				ruleIndices := rulesMatchingPath(httpRouteContext.HTTPRoute, path.Target)
+				if len(ruleIndices) == 0 {
+					continue
+				}
+				matched = true
+
+				if policy := path.LoadBalancerPolicy; policy != nil && policy.Algorithm != "" {
+					if httpRouteContext.ProviderSpecificIR.Ako == nil {
+						httpRouteContext.ProviderSpecificIR.Ako = &intermediate.AkoHTTPRouteIR{}
+					}
+					httpRouteContext.ProviderSpecificIR.Ako.LoadBalancerPolicies = append(httpRouteContext.ProviderSpecificIR.Ako.LoadBalancerPolicies, intermediate.AkoLoadBalancerPolicy{
+						RuleIndices: ruleIndices,
+						Algorithm:   policy.Algorithm,
+						Hash:        policy.Hash,
+						HostHeader:  policy.HostHeader,
+					})
+					notify(notifications.WarningNotification, fmt.Sprintf("load balancing algorithm %s of path %s of HTTPRule %s has no Gateway API equivalent, it is only kept for implementation-specific emitters", policy.Algorithm, path.Target, key), httpRule)
+				}
+				if path.TLS != nil {
+					if path.TLS.Type != reencryptTLSType {
+						errs = append(errs, field.NotSupported(pathPath.Child("tls", "type"), path.TLS.Type, []string{reencryptTLSType}))
+					} else {
+						c.addBackendTLSPolicies(httpRule, path, backendRefs(httpRouteContext.HTTPRoute, ruleIndices), ir)
+					}
+				}
+				for _, unsupported := range []struct {
+					name string
+					raw  []byte
+				}{
+					{name: "healthMonitors", raw: path.HealthMonitors},
+					{name: "applicationPersistence", raw: path.ApplicationPersistence},
+				} {
+					if len(unsupported.raw) > 0 {
+						addUnsupportedFeature(&httpRouteContext, HTTPRuleKind, key, fmt.Sprintf("spec.paths[%d].%s", i, unsupported.name), string(unsupported.raw), httpRule)
+					}
+				}
+				ir.HTTPRoutes[routeKey] = httpRouteContext
+			}
+			if !matched {
+				notify(notifications.WarningNotification, fmt.Sprintf("path %s of HTTPRule %s matches no path of the converted Ingresses of host %s", path.Target, key, httpRule.Spec.Fqdn), httpRule)
+			}
+		}
+	}
+	return errs
+}
+
+// rulesMatchingPath returns the indices of the HTTPRoute rules with a match
+// of the given path.
+func rulesMatchingPath(httpRoute gatewayv1.HTTPRoute, path string) []int {
+	var indices []int
+	for i, rule := range httpRoute.Spec.Rules {
+		if slices.ContainsFunc(rule.Matches, func(match gatewayv1.HTTPRouteMatch) bool {
+			return match.Path != nil && match.Path.Value != nil && *match.Path.Value == path
+		}) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// backendRefs returns the Service backendRefs of the HTTPRoute rules of the
+// given indices.
+func backendRefs(httpRoute gatewayv1.HTTPRoute, ruleIndices []int) []gatewayv1.BackendRef {
+	var refs []gatewayv1.BackendRef
+	for _, i := range ruleIndices {
+		for _, backendRef := range httpRoute.Spec.Rules[i].BackendRefs {
+			if (backendRef.Group != nil && *backendRef.Group != "") || (backendRef.Kind != nil && *backendRef.Kind != "Service") {
+				continue
+			}
+			refs = append(refs, backendRef.BackendRef)
+		}
+	}
+	return refs
+}
+
+// addBackendTLSPolicies generates the BackendTLSPolicies of the Services of a
+// path of an HTTPRule re-encrypting the connections to its backends.
+func (c *resourcesToIRConverter) addBackendTLSPolicies(httpRule *HTTPRule, path HTTPRulePath, backendRefs []gatewayv1.BackendRef, ir *intermediate.IR) {
+	key := types.NamespacedName{Namespace: httpRule.Namespace, Name: httpRule.Name}
+	if path.TLS.SSLProfile != "" || path.TLS.PKIProfile != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("SSL and PKI profiles of path %s of HTTPRule %s are objects of the Avi Controller, they were not converted", path.Target, key), httpRule)
+	}
+
+	var validation gatewayv1alpha3.BackendTLSPolicyValidation
+	switch {
+	case path.TLS.DestinationCA != "":
+		configMapName := fmt.Sprintf("%s-destination-ca", httpRule.Name)
+		validation.CACertificateRefs = []gatewayv1.LocalObjectReference{{Group: "", Kind: "ConfigMap", Name: gatewayv1.ObjectName(configMapName)}}
+		notify(notifications.WarningNotification, fmt.Sprintf("the destination CA certificate of HTTPRule %s is inlined, create the ConfigMap %s/%s holding it in its ca.crt key in the namespaces of its Services", key, httpRule.Namespace, configMapName), httpRule)
+	case c.wellKnownCACertificates != "":
+		validation.WellKnownCACertificates = ptr.To(c.wellKnownCACertificates)
+	default:
+		notify(notifications.WarningNotification, fmt.Sprintf("path %s of HTTPRule %s re-encrypts the connections to its backends without destination CA certificate, BackendTLSPolicies were not generated, set --backend-tls-well-known-ca-certificates to validate them with well-known CA certificates", path.Target, key), httpRule)
+		return
+	}
+
+	if ir.BackendTLSPolicies == nil {
+		ir.BackendTLSPolicies = map[types.NamespacedName]gatewayv1alpha3.BackendTLSPolicy{}
+	}
+	for _, backendRef := range backendRefs {
+		service := types.NamespacedName{Namespace: httpRule.Namespace, Name: string(backendRef.Name)}
+		if backendRef.Namespace != nil {
+			service.Namespace = string(*backendRef.Namespace)
+		}
+		validation := *validation.DeepCopy()
+		validation.Hostname = gatewayv1.PreciseHostname(fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace))
+
+		policyKey := types.NamespacedName{Namespace: service.Namespace, Name: fmt.Sprintf("%s-backend-tls", service.Name)}
+		policy := gatewayv1alpha3.BackendTLSPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: policyKey.Namespace, Name: policyKey.Name},
+			Spec: gatewayv1alpha3.BackendTLSPolicySpec{
+				TargetRefs: []gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName{{
+					LocalPolicyTargetReference: gatewayv1alpha2.LocalPolicyTargetReference{Group: "", Kind: "Service", Name: gatewayv1.ObjectName(service.Name)},
+				}},
+				Validation: validation,
+			},
+		}
+		policy.SetGroupVersionKind(common.BackendTLSPolicyGVK)
+
+		if existing, ok := ir.BackendTLSPolicies[policyKey]; ok {
+			if !reflect.DeepEqual(existing.Spec, policy.Spec) {
+				notify(notifications.WarningNotification, fmt.Sprintf("conflicting destination CA certificate of HTTPRule %s for Service %s was ignored", key, service), httpRule)
+			}
+			continue
+		}
+		ir.BackendTLSPolicies[policyKey] = policy
+		notify(notifications.InfoNotification, fmt.Sprintf("generated BackendTLSPolicy %s for HTTPRule %s", policyKey, key), httpRule)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ako
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+)
+
+func Test_applyHTTPRules(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		httpRule                HTTPRule
+		wellKnownCACertificates gatewayv1alpha3.WellKnownCACertificatesType
+		expectedPolicies        []intermediate.AkoLoadBalancerPolicy
+		expectedBackendTLS      []types.NamespacedName
+		expectedErrors          int
+	}{
+		{
+			name: "load balancer policy",
+			httpRule: HTTPRule{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+				Spec: HTTPRuleSpec{Fqdn: "app.example.com", Paths: []HTTPRulePath{{
+					Target:             "/api",
+					LoadBalancerPolicy: &HTTPRuleLoadBalancerPolicy{Algorithm: "LB_ALGORITHM_CONSISTENT_HASH", Hash: "LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP_ADDRESS"},
+				}}},
+			},
+			expectedPolicies: []intermediate.AkoLoadBalancerPolicy{{
+				RuleIndices: []int{1},
+				Algorithm:   "LB_ALGORITHM_CONSISTENT_HASH",
+				Hash:        "LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP_ADDRESS",
+			}},
+		},
+		{
+			name: "reencrypt with well-known CA certificates",
+			httpRule: HTTPRule{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+				Spec:       HTTPRuleSpec{Fqdn: "app.example.com", Paths: []HTTPRulePath{{Target: "/", TLS: &HTTPRuleTLS{Type: reencryptTLSType}}}},
+			},
+			wellKnownCACertificates: gatewayv1alpha3.WellKnownCACertificatesSystem,
+			expectedBackendTLS:      []types.NamespacedName{{Namespace: "default", Name: "web-backend-tls"}},
+		},
+		{
+			name: "reencrypt without CA certificates",
+			httpRule: HTTPRule{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+				Spec:       HTTPRuleSpec{Fqdn: "app.example.com", Paths: []HTTPRulePath{{Target: "/", TLS: &HTTPRuleTLS{Type: reencryptTLSType}}}},
+			},
+		},
+		{
+			name: "unsupported tls type",
+			httpRule: HTTPRule{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+				Spec:       HTTPRuleSpec{Fqdn: "app.example.com", Paths: []HTTPRulePath{{Target: "/", TLS: &HTTPRuleTLS{Type: "passthrough"}}}},
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "missing fqdn",
+			httpRule: HTTPRule{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+				Spec:       HTTPRuleSpec{Paths: []HTTPRulePath{{Target: "/"}}},
+			},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir := testIR()
+			converter := resourcesToIRConverter{wellKnownCACertificates: tc.wellKnownCACertificates}
+			key := types.NamespacedName{Namespace: tc.httpRule.Namespace, Name: tc.httpRule.Name}
+			errs := converter.applyHTTPRules(map[types.NamespacedName]*HTTPRule{key: &tc.httpRule}, &ir)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+
+			var policies []intermediate.AkoLoadBalancerPolicy
+			if routeIR := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "app-app-example-com"}].ProviderSpecificIR.Ako; routeIR != nil {
+				policies = routeIR.LoadBalancerPolicies
+			}
+			if diff := cmp.Diff(tc.expectedPolicies, policies); diff != "" {
+				t.Errorf("Unexpected load balancer policies (-want +got): %s", diff)
+			}
+			var backendTLS []types.NamespacedName
+			for policyKey := range ir.BackendTLSPolicies {
+				backendTLS = append(backendTLS, policyKey)
+			}
+			if diff := cmp.Diff(tc.expectedBackendTLS, backendTLS); diff != "" {
+				t.Errorf("Unexpected BackendTLSPolicies (-want +got): %s", diff)
+			}
+		})
+	}
+}
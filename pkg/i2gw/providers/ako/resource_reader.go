@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ako
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reader implements the i2gw.CustomResourceReader interface.
+type reader struct {
+	conf *i2gw.ProviderConf
+}
+
+// newResourceReader returns a resourceReader instance.
+func newResourceReader(conf *i2gw.ProviderConf) reader {
+	return reader{
+		conf: conf,
+	}
+}
+
+func (r *reader) readResourcesFromCluster(ctx context.Context) (*storage, error) {
+	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, r.conf.IngressClaimer.ClaimFunc(Name))
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, kind := range []string{HostRuleKind, HTTPRuleKind} {
+		kindObjects, err := r.listFromCluster(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, kindObjects...)
+	}
+
+	res, err := readUnstructuredObjects(objects)
+	if err != nil {
+		return nil, err
+	}
+	res.Ingresses = ingresses
+	return res, nil
+}
+
+// listFromCluster lists the objects of the given kind, of the first API
+// version of the AKO CRDs served.
+func (r *reader) listFromCluster(ctx context.Context, kind string) ([]*unstructured.Unstructured, error) {
+	for _, apiVersion := range apiVersions {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(apiVersion.WithKind(kind + "List"))
+		err := r.conf.Client.List(ctx, list)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ako %s objects: %w", kind, err)
+		}
+		objects := make([]*unstructured.Unstructured, 0, len(list.Items))
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+		return objects, nil
+	}
+	return nil, nil
+}
+
+func (r *reader) readResourcesFromFile(filename string) (*storage, error) {
+	ingresses, err := common.ReadIngressesFromFile(filename, r.conf.Namespace, r.conf.IngressClaimer.ClaimFunc(Name))
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %v: %w", filename, err)
+	}
+	objects, err := common.ExtractObjectsFromReader(bytes.NewReader(stream), r.conf.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract objects: %w", err)
+	}
+
+	res, err := readUnstructuredObjects(objects)
+	if err != nil {
+		return nil, err
+	}
+	res.Ingresses = ingresses
+	return res, nil
+}
+
+func readUnstructuredObjects(objects []*unstructured.Unstructured) (*storage, error) {
+	res := newResourcesStorage()
+
+	for _, obj := range objects {
+		if !isAKOGroupVersion(obj.GroupVersionKind().GroupVersion().String()) {
+			continue
+		}
+		key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+
+		var err error
+		switch obj.GetKind() {
+		case HostRuleKind:
+			var hostRule HostRule
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &hostRule)
+			res.HostRules[key] = &hostRule
+		case HTTPRuleKind:
+			var httpRule HTTPRule
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &httpRule)
+			res.HTTPRules[key] = &httpRule
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ako %s object: %w", obj.GetKind(), err)
+		}
+	}
+
+	return res, nil
+}
+
+func isAKOGroupVersion(groupVersion string) bool {
+	for _, apiVersion := range apiVersions {
+		if apiVersion.String() == groupVersion {
+			return true
+		}
+	}
+	return false
+}
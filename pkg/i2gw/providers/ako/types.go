@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ako
+
+import (
+	"encoding/json"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// AviIngressClass is the IngressClass created by AKO.
+	AviIngressClass = "avi-lb"
+
+	HostRuleKind = "HostRule"
+	HTTPRuleKind = "HTTPRule"
+)
+
+// The API versions of the AKO CRDs read by the provider, v1alpha1 being
+// served by AKO before v1.7.
+var apiVersions = []schema.GroupVersion{
+	{Group: "ako.vmware.com", Version: "v1beta1"},
+	{Group: "ako.vmware.com", Version: "v1alpha1"},
+}
+
+// The types below mirror the subset of the ako.vmware.com API read by the
+// provider, so that it doesn't depend on the AKO module. The fields
+// referencing objects of the Avi Controller, which have no Gateway API
+// equivalent, are kept raw to report them.
+
+// HostRule configures the virtual service of an FQDN.
+type HostRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HostRuleSpec `json:"spec"`
+}
+
+type HostRuleSpec struct {
+	VirtualHost HostRuleVirtualHost `json:"virtualhost"`
+}
+
+type HostRuleVirtualHost struct {
+	Fqdn string `json:"fqdn"`
+	// FqdnType is how the FQDN matches the hosts, Exact by default.
+	FqdnType            string          `json:"fqdnType,omitempty"`
+	EnableVirtualHost   *bool           `json:"enableVirtualHost,omitempty"`
+	TLS                 *HostRuleTLS    `json:"tls,omitempty"`
+	Aliases             []string        `json:"aliases,omitempty"`
+	ApplicationRootPath string          `json:"applicationRootPath,omitempty"`
+	HTTPPolicy          json.RawMessage `json:"httpPolicy,omitempty"`
+	WAFPolicy           json.RawMessage `json:"wafPolicy,omitempty"`
+	ApplicationProfile  json.RawMessage `json:"applicationProfile,omitempty"`
+	AnalyticsProfile    json.RawMessage `json:"analyticsProfile,omitempty"`
+	AnalyticsPolicy     json.RawMessage `json:"analyticsPolicy,omitempty"`
+	ErrorPageProfile    json.RawMessage `json:"errorPageProfile,omitempty"`
+	ICAPProfile         json.RawMessage `json:"icapProfile,omitempty"`
+	Datascripts         json.RawMessage `json:"datascripts,omitempty"`
+	GSLB                json.RawMessage `json:"gslb,omitempty"`
+	TCPSettings         json.RawMessage `json:"tcpSettings,omitempty"`
+	UseRegex            *bool           `json:"useRegex,omitempty"`
+}
+
+const (
+	FqdnTypeExact    = "Exact"
+	FqdnTypeWildcard = "Wildcard"
+	FqdnTypeContains = "Contains"
+)
+
+type HostRuleTLS struct {
+	SSLKeyCertificate HostRuleSSLKeyCertificate `json:"sslKeyCertificate"`
+	SSLProfile        string                    `json:"sslProfile,omitempty"`
+	Termination       string                    `json:"termination,omitempty"`
+}
+
+type HostRuleSSLKeyCertificate struct {
+	Name                 string                     `json:"name"`
+	Type                 string                     `json:"type"`
+	AlternateCertificate *HostRuleSSLKeyCertificate `json:"alternateCertificate,omitempty"`
+}
+
+const (
+	// CertificateTypeSecret references a Kubernetes Secret.
+	CertificateTypeSecret = "secret"
+	// CertificateTypeRef references an SSLKeyAndCertificate of the Avi
+	// Controller.
+	CertificateTypeRef = "ref"
+)
+
+// HTTPRule configures the pools of the paths of an FQDN.
+type HTTPRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HTTPRuleSpec `json:"spec"`
+}
+
+type HTTPRuleSpec struct {
+	Fqdn  string         `json:"fqdn"`
+	Paths []HTTPRulePath `json:"paths,omitempty"`
+}
+
+type HTTPRulePath struct {
+	Target                 string                      `json:"target"`
+	LoadBalancerPolicy     *HTTPRuleLoadBalancerPolicy `json:"loadBalancerPolicy,omitempty"`
+	TLS                    *HTTPRuleTLS                `json:"tls,omitempty"`
+	HealthMonitors         json.RawMessage             `json:"healthMonitors,omitempty"`
+	ApplicationPersistence json.RawMessage             `json:"applicationPersistence,omitempty"`
+}
+
+type HTTPRuleLoadBalancerPolicy struct {
+	Algorithm  string `json:"algorithm,omitempty"`
+	Hash       string `json:"hash,omitempty"`
+	HostHeader string `json:"hostHeader,omitempty"`
+}
+
+type HTTPRuleTLS struct {
+	// Type is the TLS of the connections to the backends, only reencrypt.
+	Type          string `json:"type"`
+	SSLProfile    string `json:"sslProfile,omitempty"`
+	DestinationCA string `json:"destinationCA,omitempty"`
+	PKIProfile    string `json:"pkiProfile,omitempty"`
+}
+
+func (h *HostRule) DeepCopyObject() runtime.Object {
+	out := *h
+	out.ObjectMeta = *h.ObjectMeta.DeepCopy()
+	vh := &out.Spec.VirtualHost
+	vh.EnableVirtualHost = copyPtr(h.Spec.VirtualHost.EnableVirtualHost)
+	vh.UseRegex = copyPtr(h.Spec.VirtualHost.UseRegex)
+	vh.Aliases = slices.Clone(h.Spec.VirtualHost.Aliases)
+	if h.Spec.VirtualHost.TLS != nil {
+		tls := *h.Spec.VirtualHost.TLS
+		tls.SSLKeyCertificate.AlternateCertificate = copyPtr(tls.SSLKeyCertificate.AlternateCertificate)
+		vh.TLS = &tls
+	}
+	for _, raw := range []*json.RawMessage{&vh.HTTPPolicy, &vh.WAFPolicy, &vh.ApplicationProfile, &vh.AnalyticsProfile, &vh.AnalyticsPolicy, &vh.ErrorPageProfile, &vh.ICAPProfile, &vh.Datascripts, &vh.GSLB, &vh.TCPSettings} {
+		*raw = slices.Clone(*raw)
+	}
+	return &out
+}
+
+func (h *HTTPRule) DeepCopyObject() runtime.Object {
+	out := *h
+	out.ObjectMeta = *h.ObjectMeta.DeepCopy()
+	out.Spec.Paths = make([]HTTPRulePath, len(h.Spec.Paths))
+	for i, path := range h.Spec.Paths {
+		path.LoadBalancerPolicy = copyPtr(path.LoadBalancerPolicy)
+		path.TLS = copyPtr(path.TLS)
+		path.HealthMonitors = slices.Clone(path.HealthMonitors)
+		path.ApplicationPersistence = slices.Clone(path.ApplicationPersistence)
+		out.Spec.Paths[i] = path
+	}
+	return &out
+}
+
+// copyPtr returns a shallow copy of the value of p, or nil.
+func copyPtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	return &c
+}
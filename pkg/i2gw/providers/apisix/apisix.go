@@ -32,6 +32,11 @@ const ApisixIngressClass = "apisix"
 
 func init() {
 	i2gw.ProviderConstructorByName[Name] = NewProvider
+	i2gw.RegisterProviderSupportLevel(Name, i2gw.StableSupportLevel)
+	i2gw.RegisterIngressClaimRule(Name, i2gw.IngressClaimRule{
+		IngressClasses:     []string{ApisixIngressClass},
+		AnnotationPrefixes: []string{annotationPrefix + "/"},
+	})
 }
 
 // Provider implements the i2gw.Provider interface.
@@ -46,7 +51,7 @@ func NewProvider(conf *i2gw.ProviderConf) i2gw.Provider {
 	return &Provider{
 		storage:                newResourcesStorage(),
 		resourceReader:         newResourceReader(conf),
-		resourcesToIRConverter: newResourcesToIRConverter(),
+		resourcesToIRConverter: newResourcesToIRConverter(conf),
 	}
 }
 
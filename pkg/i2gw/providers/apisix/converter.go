@@ -22,23 +22,27 @@ import (
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 )
 
 // resourcesToIRConverter implements the ToIR function of i2gw.ResourcesToIRConverter interface.
 type resourcesToIRConverter struct {
-	featureParsers                []i2gw.FeatureParser
+	featureChain                  *i2gw.FeatureChain
 	implementationSpecificOptions i2gw.ProviderImplementationSpecificOptions
+	wellKnownCACertificates       gatewayv1alpha3.WellKnownCACertificatesType
 }
 
 // newResourcesToIRConverter returns an apisix resourcesToIRConverter instance.
-func newResourcesToIRConverter() *resourcesToIRConverter {
+func newResourcesToIRConverter(conf *i2gw.ProviderConf) *resourcesToIRConverter {
 	return &resourcesToIRConverter{
-		featureParsers: []i2gw.FeatureParser{
-			httpToHTTPSFeature,
-		},
+		featureChain: i2gw.NewFeatureChain(
+			i2gw.NamedFeatureParser{Name: "http-to-https", Parse: httpToHTTPSFeature},
+		).Use(conf.FeatureToggles.Middleware(Name)),
 		implementationSpecificOptions: i2gw.ProviderImplementationSpecificOptions{
 			// The list of the implementationSpecific ingress fields options comes here.
+			DisableRouteMerging: !conf.Profile.RouteMerging,
 		},
+		wellKnownCACertificates: conf.BackendTLSWellKnownCACertificates,
 	}
 }
 
@@ -54,12 +58,11 @@ func (c *resourcesToIRConverter) convertToIR(storage *storage) (intermediate.IR,
 		return intermediate.IR{}, errs
 	}
 
-	for _, parseFeatureFunc := range c.featureParsers {
-		// Apply the feature parsing function to the gateway resources, one by one.
-		parseErrs := parseFeatureFunc(ingressList, &ir)
-		// Append the parsing errors to the error list.
-		errs = append(errs, parseErrs...)
-	}
+	// Apply the feature parsing functions to the gateway resources, in order.
+	errs = append(errs, c.featureChain.Run(ingressList, &ir)...)
+	errs = append(errs, pluginConfigsToIR(ingressList, storage.PluginConfigs, &ir)...)
+	globalRulesToIR(storage.GlobalRules, &ir)
+	errs = append(errs, crdsToIR(storage, c.wellKnownCACertificates, &ir)...)
 
 	return ir, errs
 }
@@ -32,7 +32,7 @@ import (
 func httpToHTTPSFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
 	var errs field.ErrorList
 	httpToHTTPSAnnotation := apisixAnnotation("http-to-https")
-	ruleGroups := common.GetRuleGroups(ingresses)
+	ruleGroups := common.GetRuleGroups(ingresses, ir)
 	for _, rg := range ruleGroups {
 		for _, rule := range rg.Rules {
 			if val, annotationFound := rule.Ingress.Annotations[httpToHTTPSAnnotation]; val == "true" {
@@ -0,0 +1,414 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apisix
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// convertedPlugins are the filters converted from the plugins of an
+// ApisixPluginConfig.
+type convertedPlugins struct {
+	filters []gatewayv1.HTTPRouteFilter
+	// plugins are the enabled plugins, kept in the provider-specific IR.
+	plugins []intermediate.ApisixPlugin
+	// unsupported are the enabled plugins which weren't fully converted.
+	unsupported []intermediate.ApisixPlugin
+}
+
+// pluginConfigsToIR converts the plugins of the ApisixPluginConfigs
+// referenced by the k8s.apisix.apache.org/plugin-config-name annotation of
+// the Ingresses into filters of the rules generated from their paths.
+//
+// The header plugins become header modifier filters, and the redirect plugin a
+// request redirect filter. All the enabled plugins are kept in the APISIX
+// HTTPRoute IR, and the ones without Gateway API equivalent are recorded as
+// unsupported features.
+func pluginConfigsToIR(ingresses []networkingv1.Ingress, pluginConfigs map[types.NamespacedName]*ApisixPluginConfig, ir *intermediate.IR) field.ErrorList {
+	var errs field.ErrorList
+	annotation := apisixAnnotation("plugin-config-name")
+	convertedByPluginConfig := map[types.NamespacedName]*convertedPlugins{}
+	for _, rg := range common.GetRuleGroups(ingresses, ir) {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRouteContext, ok := ir.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			name, ok := rule.Ingress.Annotations[annotation]
+			if !ok {
+				continue
+			}
+			pluginConfigKey := types.NamespacedName{Namespace: rule.Ingress.Namespace, Name: name}
+			converted, ok := convertedByPluginConfig[pluginConfigKey]
+			if !ok {
+				pluginConfig, found := pluginConfigs[pluginConfigKey]
+				if !found {
+					notify(notifications.WarningNotification, fmt.Sprintf("%s %s referenced by ingress %s/%s was not found, its plugins were not converted", PluginConfigKind, pluginConfigKey, rule.Ingress.Namespace, rule.Ingress.Name), &rule.Ingress)
+				} else {
+					var convertErrs field.ErrorList
+					converted, convertErrs = convertPlugins(pluginConfigKey, pluginConfig.Spec.Plugins, field.NewPath(PluginConfigKind).Key(pluginConfigKey.String()).Child("spec", "plugins"))
+					errs = append(errs, convertErrs...)
+					notifyUnsupportedPlugins(PluginConfigKind, pluginConfigKey, converted.unsupported, pluginConfig)
+				}
+				convertedByPluginConfig[pluginConfigKey] = converted
+			}
+			if converted == nil {
+				continue
+			}
+
+			for _, i := range common.RuleIndicesForIngressRule(httpRouteContext.HTTPRoute, rule.IngressRule) {
+				for _, filter := range converted.filters {
+					if slices.ContainsFunc(httpRouteContext.Spec.Rules[i].Filters, func(existing gatewayv1.HTTPRouteFilter) bool { return existing.Type == filter.Type }) {
+						notify(notifications.WarningNotification, fmt.Sprintf("rule %d of HTTPRoute %s already has a %s filter, the one of %s %s was not added", i, key, filter.Type, PluginConfigKind, pluginConfigKey), &httpRouteContext.HTTPRoute)
+						continue
+					}
+					httpRouteContext.Spec.Rules[i].Filters = append(httpRouteContext.Spec.Rules[i].Filters, filter)
+				}
+			}
+			if httpRouteContext.ProviderSpecificIR.Apisix == nil {
+				httpRouteContext.ProviderSpecificIR.Apisix = &intermediate.ApisixHTTPRouteIR{}
+			}
+			for _, plugin := range converted.plugins {
+				if !slices.Contains(httpRouteContext.ProviderSpecificIR.Apisix.Plugins, plugin) {
+					httpRouteContext.ProviderSpecificIR.Apisix.Plugins = append(httpRouteContext.ProviderSpecificIR.Apisix.Plugins, plugin)
+				}
+			}
+			for _, plugin := range converted.unsupported {
+				feature := intermediate.UnsupportedFeature{
+					SourceKind: PluginConfigKind,
+					Source:     pluginConfigKey,
+					Name:       plugin.Name,
+					RawConfig:  plugin.Config,
+				}
+				if !slices.Contains(httpRouteContext.UnsupportedFeatures, feature) {
+					httpRouteContext.UnsupportedFeatures = append(httpRouteContext.UnsupportedFeatures, feature)
+				}
+			}
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+	return errs
+}
+
+// globalRulesToIR keeps the plugins of the ApisixGlobalRules, run by APISIX
+// for all the routes, in the APISIX IR of all the Gateways, and reports them,
+// as they have no Gateway API equivalent.
+func globalRulesToIR(globalRules map[types.NamespacedName]*ApisixGlobalRule, ir *intermediate.IR) {
+	var globalPlugins []intermediate.ApisixPlugin
+	keys := make([]types.NamespacedName, 0, len(globalRules))
+	for key := range globalRules {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	for _, key := range keys {
+		globalRule := globalRules[key]
+		var names []string
+		for _, plugin := range globalRule.Spec.Plugins {
+			if !plugin.Enable {
+				continue
+			}
+			globalPlugins = append(globalPlugins, intermediate.ApisixPlugin{Name: plugin.Name, Config: string(plugin.Config.Raw), Source: key})
+			names = append(names, plugin.Name)
+		}
+		if len(names) > 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s %s runs the plugins %v for all the routes, which has no Gateway API equivalent: they are only kept for implementation-specific emitters", GlobalRuleKind, key, names), globalRule)
+		}
+	}
+	if len(globalPlugins) == 0 {
+		return
+	}
+	for key, gatewayContext := range ir.Gateways {
+		gatewayContext.ProviderSpecificIR.Apisix = &intermediate.ApisixGatewayIR{GlobalPlugins: globalPlugins}
+		ir.Gateways[key] = gatewayContext
+	}
+}
+
+// convertPlugins converts the enabled plugins to filters. The plugins
+// configured with variables, or with fields without Gateway API equivalent,
+// are unsupported.
+func convertPlugins(source types.NamespacedName, plugins []ApisixRoutePlugin, path *field.Path) (*convertedPlugins, field.ErrorList) {
+	var errs field.ErrorList
+	converted := &convertedPlugins{}
+	for i, plugin := range plugins {
+		if !plugin.Enable {
+			continue
+		}
+		irPlugin := intermediate.ApisixPlugin{Name: plugin.Name, Config: string(plugin.Config.Raw), Source: source}
+		converted.plugins = append(converted.plugins, irPlugin)
+		if plugin.SecretRef != "" {
+			converted.unsupported = append(converted.unsupported, irPlugin)
+			continue
+		}
+
+		config := map[string]json.RawMessage{}
+		if len(plugin.Config.Raw) > 0 {
+			if err := json.Unmarshal(plugin.Config.Raw, &config); err != nil {
+				errs = append(errs, field.Invalid(path.Index(i).Child("config"), string(plugin.Config.Raw), err.Error()))
+				continue
+			}
+		}
+		var (
+			filter      *gatewayv1.HTTPRouteFilter
+			unsupported bool
+			err         error
+		)
+		switch plugin.Name {
+		case "redirect":
+			filter, unsupported, err = redirectFilter(config)
+		case "proxy-rewrite":
+			var rewrite, headers *gatewayv1.HTTPRouteFilter
+			rewrite, headers, unsupported, err = proxyRewriteFilters(config)
+			if rewrite != nil {
+				converted.filters = append(converted.filters, *rewrite)
+			}
+			filter = headers
+		case "response-rewrite":
+			filter, unsupported, err = responseRewriteFilter(config)
+		default:
+			unsupported = true
+		}
+		if err != nil {
+			errs = append(errs, field.Invalid(path.Index(i).Child("config"), string(plugin.Config.Raw), err.Error()))
+			continue
+		}
+		if filter != nil {
+			converted.filters = append(converted.filters, *filter)
+		}
+		if unsupported {
+			converted.unsupported = append(converted.unsupported, irPlugin)
+		}
+	}
+	return converted, errs
+}
+
+// redirectFilter converts the redirect plugin to a request redirect filter.
+func redirectFilter(config map[string]json.RawMessage) (*gatewayv1.HTTPRouteFilter, bool, error) {
+	var redirect struct {
+		HTTPToHTTPS bool   `json:"http_to_https"`
+		URI         string `json:"uri"`
+		RetCode     int    `json:"ret_code"`
+	}
+	if err := unmarshalConfig(config, &redirect); err != nil {
+		return nil, false, err
+	}
+	unsupported := hasOtherKeys(config, "http_to_https", "uri", "ret_code")
+
+	switch {
+	case redirect.HTTPToHTTPS:
+		return &gatewayv1.HTTPRouteFilter{
+			Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+			RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+				Scheme:     ptr.To("https"),
+				StatusCode: ptr.To(301),
+			},
+		}, unsupported, nil
+	case redirect.URI != "" && !strings.Contains(redirect.URI, "$"):
+		location, err := url.Parse(redirect.URI)
+		if err != nil {
+			return nil, false, err
+		}
+		statusCode := redirect.RetCode
+		if statusCode == 0 {
+			statusCode = 302
+		}
+		if statusCode != 301 && statusCode != 302 {
+			return nil, true, nil
+		}
+		requestRedirect := &gatewayv1.HTTPRequestRedirectFilter{StatusCode: ptr.To(statusCode)}
+		if location.Scheme != "" {
+			requestRedirect.Scheme = ptr.To(location.Scheme)
+		}
+		if location.Host != "" {
+			requestRedirect.Hostname = ptr.To(gatewayv1.PreciseHostname(location.Hostname()))
+		}
+		if location.Path != "" {
+			requestRedirect.Path = &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To(location.Path)}
+		}
+		return &gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterRequestRedirect, RequestRedirect: requestRedirect}, unsupported || location.RawQuery != "", nil
+	default:
+		return nil, true, nil
+	}
+}
+
+// proxyRewriteFilters converts the proxy-rewrite plugin to a URL rewrite
+// filter, and a request header modifier filter.
+func proxyRewriteFilters(config map[string]json.RawMessage) (*gatewayv1.HTTPRouteFilter, *gatewayv1.HTTPRouteFilter, bool, error) {
+	var proxyRewrite struct {
+		URI  string `json:"uri"`
+		Host string `json:"host"`
+	}
+	if err := unmarshalConfig(config, &proxyRewrite); err != nil {
+		return nil, nil, false, err
+	}
+	unsupported := hasOtherKeys(config, "uri", "host", "headers")
+
+	var rewrite *gatewayv1.HTTPRouteFilter
+	if proxyRewrite.URI != "" || proxyRewrite.Host != "" {
+		urlRewrite := &gatewayv1.HTTPURLRewriteFilter{}
+		if strings.Contains(proxyRewrite.URI, "$") {
+			unsupported = true
+		} else if proxyRewrite.URI != "" {
+			urlRewrite.Path = &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To(proxyRewrite.URI)}
+		}
+		if proxyRewrite.Host != "" {
+			urlRewrite.Hostname = ptr.To(gatewayv1.PreciseHostname(proxyRewrite.Host))
+		}
+		if urlRewrite.Path != nil || urlRewrite.Hostname != nil {
+			rewrite = &gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterURLRewrite, URLRewrite: urlRewrite}
+		}
+	}
+
+	headerFilter, headersUnsupported, err := headerModifier(config["headers"], true)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	var headers *gatewayv1.HTTPRouteFilter
+	if headerFilter != nil {
+		headers = &gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier, RequestHeaderModifier: headerFilter}
+	}
+	return rewrite, headers, unsupported || headersUnsupported, nil
+}
+
+// responseRewriteFilter converts the headers of the response-rewrite plugin
+// to a response header modifier filter.
+func responseRewriteFilter(config map[string]json.RawMessage) (*gatewayv1.HTTPRouteFilter, bool, error) {
+	unsupported := hasOtherKeys(config, "headers")
+	headerFilter, headersUnsupported, err := headerModifier(config["headers"], false)
+	if err != nil || headerFilter == nil {
+		return nil, unsupported || headersUnsupported, err
+	}
+	return &gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier, ResponseHeaderModifier: headerFilter}, unsupported || headersUnsupported, nil
+}
+
+// headerModifier converts the headers of the rewrite plugins, of the
+// {"set": {}, "add": {}, "remove": []} form, or of the legacy form setting the
+// headers of a map, removing the ones with an empty value from requests.
+func headerModifier(raw json.RawMessage, legacyRemovesEmpty bool) (*gatewayv1.HTTPHeaderFilter, bool, error) {
+	if len(raw) == 0 {
+		return nil, false, nil
+	}
+	var headers map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &headers); err != nil {
+		return nil, false, err
+	}
+
+	modifier := &gatewayv1.HTTPHeaderFilter{}
+	unsupported := false
+	_, hasSet := headers["set"]
+	_, hasAdd := headers["add"]
+	_, hasRemove := headers["remove"]
+	if hasSet || hasAdd || hasRemove {
+		var operations struct {
+			Set    map[string]string `json:"set"`
+			Add    map[string]string `json:"add"`
+			Remove []string          `json:"remove"`
+		}
+		if err := json.Unmarshal(raw, &operations); err != nil {
+			return nil, false, err
+		}
+		modifier.Set, unsupported = toHTTPHeaders(operations.Set)
+		var addUnsupported bool
+		modifier.Add, addUnsupported = toHTTPHeaders(operations.Add)
+		unsupported = unsupported || addUnsupported
+		modifier.Remove = operations.Remove
+	} else {
+		var set map[string]string
+		if err := json.Unmarshal(raw, &set); err != nil {
+			return nil, false, err
+		}
+		for name, value := range set {
+			if value == "" && legacyRemovesEmpty {
+				modifier.Remove = append(modifier.Remove, name)
+				delete(set, name)
+			}
+		}
+		slices.Sort(modifier.Remove)
+		modifier.Set, unsupported = toHTTPHeaders(set)
+	}
+	if len(modifier.Set) == 0 && len(modifier.Add) == 0 && len(modifier.Remove) == 0 {
+		return nil, unsupported, nil
+	}
+	return modifier, unsupported, nil
+}
+
+// toHTTPHeaders returns the headers sorted by name, skipping the values with
+// variables, e.g. $remote_addr, which are unsupported.
+func toHTTPHeaders(headers map[string]string) ([]gatewayv1.HTTPHeader, bool) {
+	var (
+		httpHeaders []gatewayv1.HTTPHeader
+		unsupported bool
+	)
+	for name, value := range headers {
+		if strings.Contains(value, "$") {
+			unsupported = true
+			continue
+		}
+		httpHeaders = append(httpHeaders, gatewayv1.HTTPHeader{Name: gatewayv1.HTTPHeaderName(name), Value: value})
+	}
+	slices.SortFunc(httpHeaders, func(a, b gatewayv1.HTTPHeader) int {
+		return strings.Compare(string(a.Name), string(b.Name))
+	})
+	return httpHeaders, unsupported
+}
+
+func unmarshalConfig(config map[string]json.RawMessage, v any) error {
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// hasOtherKeys returns whether the configuration has keys other than the
+// given ones.
+func hasOtherKeys(config map[string]json.RawMessage, keys ...string) bool {
+	for key := range config {
+		if !slices.Contains(keys, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyUnsupportedPlugins warns about the plugins of the source which
+// weren't fully converted.
+func notifyUnsupportedPlugins(kind string, source types.NamespacedName, unsupported []intermediate.ApisixPlugin, object *ApisixPluginConfig) {
+	if len(unsupported) == 0 {
+		return
+	}
+	var names []string
+	for _, plugin := range unsupported {
+		names = append(names, plugin.Name)
+	}
+	notify(notifications.WarningNotification, fmt.Sprintf("plugins %v of %s %s have no Gateway API equivalent and must be ported manually", names, kind, source), object)
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apisix
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_convertPlugins(t *testing.T) {
+	source := types.NamespacedName{Namespace: "default", Name: "plugins"}
+	path := field.NewPath("spec", "plugins")
+
+	testCases := []struct {
+		name                string
+		plugins             []ApisixRoutePlugin
+		expectedFilters     []gatewayv1.HTTPRouteFilter
+		expectedUnsupported []string
+		expectedErrors      int
+	}{
+		{
+			name: "redirect to https",
+			plugins: []ApisixRoutePlugin{
+				{Name: "redirect", Enable: true, Config: apiextensionsv1.JSON{Raw: []byte(`{"http_to_https":true}`)}},
+			},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{Scheme: ptr.To("https"), StatusCode: ptr.To(301)},
+			}},
+		},
+		{
+			name: "redirect to an absolute uri",
+			plugins: []ApisixRoutePlugin{
+				{Name: "redirect", Enable: true, Config: apiextensionsv1.JSON{Raw: []byte(`{"uri":"https://example.com/new","ret_code":301}`)}},
+			},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+					Scheme:     ptr.To("https"),
+					Hostname:   ptr.To(gatewayv1.PreciseHostname("example.com")),
+					Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/new")},
+					StatusCode: ptr.To(301),
+				},
+			}},
+		},
+		{
+			name: "redirect with variables",
+			plugins: []ApisixRoutePlugin{
+				{Name: "redirect", Enable: true, Config: apiextensionsv1.JSON{Raw: []byte(`{"uri":"/new$uri"}`)}},
+			},
+			expectedUnsupported: []string{"redirect"},
+		},
+		{
+			name: "proxy-rewrite with legacy headers",
+			plugins: []ApisixRoutePlugin{
+				{Name: "proxy-rewrite", Enable: true, Config: apiextensionsv1.JSON{Raw: []byte(`{"uri":"/api","headers":{"X-Api":"v1","X-Debug":""}}`)}},
+			},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{
+				{
+					Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+					URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/api")}},
+				},
+				{
+					Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+					RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+						Set:    []gatewayv1.HTTPHeader{{Name: "X-Api", Value: "v1"}},
+						Remove: []string{"X-Debug"},
+					},
+				},
+			},
+		},
+		{
+			name: "response-rewrite with a body",
+			plugins: []ApisixRoutePlugin{
+				{Name: "response-rewrite", Enable: true, Config: apiextensionsv1.JSON{Raw: []byte(`{"body":"ok","headers":{"add":{"X-Served-By":"apisix"}}}`)}},
+			},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type:                   gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+				ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{Add: []gatewayv1.HTTPHeader{{Name: "X-Served-By", Value: "apisix"}}},
+			}},
+			expectedUnsupported: []string{"response-rewrite"},
+		},
+		{
+			name: "disabled, secret and unknown plugins",
+			plugins: []ApisixRoutePlugin{
+				{Name: "cors", Enable: false},
+				{Name: "proxy-rewrite", Enable: true, SecretRef: "rewrite"},
+				{Name: "limit-count", Enable: true, Config: apiextensionsv1.JSON{Raw: []byte(`{"count":10}`)}},
+			},
+			expectedUnsupported: []string{"proxy-rewrite", "limit-count"},
+		},
+		{
+			name: "invalid configuration",
+			plugins: []ApisixRoutePlugin{
+				{Name: "redirect", Enable: true, Config: apiextensionsv1.JSON{Raw: []byte(`{"ret_code":"301"}`)}},
+			},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			converted, errs := convertPlugins(source, tc.plugins, path)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if diff := cmp.Diff(tc.expectedFilters, converted.filters); diff != "" {
+				t.Errorf("Unexpected filters, diff (-want +got):\n%s", diff)
+			}
+			var unsupported []string
+			for _, plugin := range converted.unsupported {
+				unsupported = append(unsupported, plugin.Name)
+			}
+			if diff := cmp.Diff(tc.expectedUnsupported, unsupported); diff != "" {
+				t.Errorf("Unexpected unsupported plugins, diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_pluginConfigsToIR(t *testing.T) {
+	ingress := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "shop",
+			Namespace:   "default",
+			Annotations: map[string]string{"k8s.apisix.apache.org/plugin-config-name": "shop-plugins"},
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To("apisix"),
+			Rules: []networkingv1.IngressRule{{
+				Host: "shop.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: ptr.To(networkingv1.PathTypePrefix),
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: "shop",
+							Port: networkingv1.ServiceBackendPort{Number: 80},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+	pluginConfigKey := types.NamespacedName{Namespace: "default", Name: "shop-plugins"}
+	pluginConfigs := map[types.NamespacedName]*ApisixPluginConfig{
+		pluginConfigKey: {
+			ObjectMeta: metav1.ObjectMeta{Name: "shop-plugins", Namespace: "default"},
+			Spec: ApisixPluginConfigSpec{Plugins: []ApisixRoutePlugin{
+				{Name: "proxy-rewrite", Enable: true, Config: apiextensionsv1.JSON{Raw: []byte(`{"host":"internal.example.com"}`)}},
+				{Name: "limit-count", Enable: true, Config: apiextensionsv1.JSON{Raw: []byte(`{"count":10}`)}},
+			}},
+		},
+	}
+	routeKey := types.NamespacedName{Namespace: "default", Name: "shop-shop-example-com"}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			routeKey: {
+				HTTPRoute: gatewayv1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{Name: routeKey.Name, Namespace: routeKey.Namespace},
+					Spec: gatewayv1.HTTPRouteSpec{
+						Hostnames: []gatewayv1.Hostname{"shop.example.com"},
+						Rules: []gatewayv1.HTTPRouteRule{{
+							Matches: []gatewayv1.HTTPRouteMatch{{
+								Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/")},
+							}},
+							BackendRefs: []gatewayv1.HTTPBackendRef{
+								{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "shop", Port: ptr.To(gatewayv1.PortNumber(80))}}},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	if errs := pluginConfigsToIR([]networkingv1.Ingress{ingress}, pluginConfigs, &ir); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	httpRouteContext := ir.HTTPRoutes[routeKey]
+	expectedFilters := []gatewayv1.HTTPRouteFilter{{
+		Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+		URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Hostname: ptr.To(gatewayv1.PreciseHostname("internal.example.com"))},
+	}}
+	if diff := cmp.Diff(expectedFilters, httpRouteContext.Spec.Rules[0].Filters); diff != "" {
+		t.Errorf("Unexpected filters, diff (-want +got):\n%s", diff)
+	}
+	expectedPlugins := &intermediate.ApisixHTTPRouteIR{Plugins: []intermediate.ApisixPlugin{
+		{Name: "proxy-rewrite", Config: `{"host":"internal.example.com"}`, Source: pluginConfigKey},
+		{Name: "limit-count", Config: `{"count":10}`, Source: pluginConfigKey},
+	}}
+	if diff := cmp.Diff(expectedPlugins, httpRouteContext.ProviderSpecificIR.Apisix); diff != "" {
+		t.Errorf("Unexpected APISIX IR, diff (-want +got):\n%s", diff)
+	}
+	expectedUnsupported := []intermediate.UnsupportedFeature{
+		{SourceKind: PluginConfigKind, Source: pluginConfigKey, Name: "limit-count", RawConfig: `{"count":10}`},
+	}
+	if diff := cmp.Diff(expectedUnsupported, httpRouteContext.UnsupportedFeatures); diff != "" {
+		t.Errorf("Unexpected unsupported features, diff (-want +got):\n%s", diff)
+	}
+}
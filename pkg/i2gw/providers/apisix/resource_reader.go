@@ -17,11 +17,17 @@ limitations under the License.
 package apisix
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"os"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
-	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // resourceReader implements the i2gw.CustomResourceReader interface.
@@ -40,11 +46,32 @@ func (r *resourceReader) readResourcesFromCluster(ctx context.Context) (*storage
 	// read apisix related resources from cluster.
 	storage := newResourcesStorage()
 
-	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, sets.New(ApisixIngressClass))
+	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, r.conf.IngressClaimer.ClaimFunc(Name))
 	if err != nil {
 		return nil, err
 	}
 	storage.Ingresses = ingresses
+
+	// The APISIX CRDs are optional, they may not be installed.
+	var objects []*unstructured.Unstructured
+	for _, kind := range []string{RouteKind, UpstreamKind, TLSKind, PluginConfigKind, GlobalRuleKind} {
+		list := &unstructured.UnstructuredList{}
+		list.SetAPIVersion(APIVersion)
+		list.SetKind(kind)
+		err := r.conf.Client.List(ctx, list)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s objects: %w", kind, err)
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+	}
+	if err := readUnstructuredObjects(storage, objects); err != nil {
+		return nil, err
+	}
 	return storage, nil
 }
 
@@ -52,10 +79,65 @@ func (r *resourceReader) readResourcesFromFile(filename string) (*storage, error
 	// read apisix related resources from file.
 	storage := newResourcesStorage()
 
-	ingresses, err := common.ReadIngressesFromFile(filename, r.conf.Namespace, sets.New[string](ApisixIngressClass))
+	ingresses, err := common.ReadIngressesFromFile(filename, r.conf.Namespace, r.conf.IngressClaimer.ClaimFunc(Name))
 	if err != nil {
 		return nil, err
 	}
 	storage.Ingresses = ingresses
+
+	stream, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %v: %w", filename, err)
+	}
+	objects, err := common.ExtractObjectsFromReader(bytes.NewReader(stream), r.conf.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract objects: %w", err)
+	}
+	if err := readUnstructuredObjects(storage, objects); err != nil {
+		return nil, err
+	}
 	return storage, nil
 }
+
+// readUnstructuredObjects stores the APISIX objects of the objects.
+func readUnstructuredObjects(storage *storage, objects []*unstructured.Unstructured) error {
+	for _, obj := range objects {
+		if obj.GetAPIVersion() != APIVersion {
+			continue
+		}
+		key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		switch obj.GetKind() {
+		case RouteKind:
+			var route ApisixRoute
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &route); err != nil {
+				return fmt.Errorf("failed to parse %s object: %w", obj.GetKind(), err)
+			}
+			storage.Routes[key] = &route
+		case UpstreamKind:
+			var upstream ApisixUpstream
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &upstream); err != nil {
+				return fmt.Errorf("failed to parse %s object: %w", obj.GetKind(), err)
+			}
+			storage.Upstreams[key] = &upstream
+		case TLSKind:
+			var tls ApisixTls
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &tls); err != nil {
+				return fmt.Errorf("failed to parse %s object: %w", obj.GetKind(), err)
+			}
+			storage.TLSes[key] = &tls
+		case PluginConfigKind:
+			var pluginConfig ApisixPluginConfig
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &pluginConfig); err != nil {
+				return fmt.Errorf("failed to parse %s object: %w", obj.GetKind(), err)
+			}
+			storage.PluginConfigs[key] = &pluginConfig
+		case GlobalRuleKind:
+			var globalRule ApisixGlobalRule
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &globalRule); err != nil {
+				return fmt.Errorf("failed to parse %s object: %w", obj.GetKind(), err)
+			}
+			storage.GlobalRules[key] = &globalRule
+		}
+	}
+	return nil
+}
@@ -0,0 +1,590 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apisix
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// maxMatches is the maximum number of matches of an HTTPRoute rule.
+const maxMatches = 64
+
+// crdConversion holds the state of the conversion of the APISIX CRDs into the
+// IR of the Ingresses.
+type crdConversion struct {
+	storage                 *storage
+	ir                      *intermediate.IR
+	wellKnownCACertificates gatewayv1alpha3.WellKnownCACertificatesType
+	// convertedUpstreams are the ApisixUpstreams converted already.
+	convertedUpstreams map[types.NamespacedName]bool
+}
+
+// crdsToIR converts the ApisixRoutes and ApisixTlses into the IR:
+//   - Each HTTP rule of an ApisixRoute becomes an HTTPRoute, named after the
+//     ApisixRoute and the rule, attached to the Gateway of its ingress class
+//     in its namespace, and each stream rule a TCPRoute or a UDPRoute.
+//   - The ApisixUpstreams of the backends with the https or grpcs scheme
+//     become BackendTLSPolicies.
+//   - The hosts of the ApisixTlses become HTTPS listeners of the Gateways
+//     serving them.
+func crdsToIR(storage *storage, wellKnownCACertificates gatewayv1alpha3.WellKnownCACertificatesType, ir *intermediate.IR) field.ErrorList {
+	cv := crdConversion{
+		storage:                 storage,
+		ir:                      ir,
+		wellKnownCACertificates: wellKnownCACertificates,
+		convertedUpstreams:      map[types.NamespacedName]bool{},
+	}
+	if ir.Gateways == nil {
+		ir.Gateways = map[types.NamespacedName]intermediate.GatewayContext{}
+	}
+	if ir.HTTPRoutes == nil {
+		ir.HTTPRoutes = map[types.NamespacedName]intermediate.HTTPRouteContext{}
+	}
+
+	var errs field.ErrorList
+	for _, key := range sortedKeys(storage.Routes) {
+		errs = append(errs, cv.convertRoute(storage.Routes[key])...)
+	}
+	for _, key := range sortedKeys(storage.TLSes) {
+		errs = append(errs, cv.convertTLS(storage.TLSes[key])...)
+	}
+	return errs
+}
+
+func (cv *crdConversion) convertRoute(route *ApisixRoute) field.ErrorList {
+	key := types.NamespacedName{Namespace: route.Namespace, Name: route.Name}
+	specPath := field.NewPath(RouteKind).Key(key.String()).Child("spec")
+	class := ingressClass(route.Spec.IngressClassName)
+
+	var errs field.ErrorList
+	for i, rule := range route.Spec.HTTP {
+		errs = append(errs, cv.convertHTTPRule(route, class, rule, specPath.Child("http").Index(i))...)
+	}
+	for i, rule := range route.Spec.Stream {
+		errs = append(errs, cv.convertStreamRule(route, class, rule, specPath.Child("stream").Index(i))...)
+	}
+	return errs
+}
+
+func (cv *crdConversion) convertHTTPRule(route *ApisixRoute, class string, rule ApisixRouteHTTP, rulePath *field.Path) field.ErrorList {
+	source := types.NamespacedName{Namespace: route.Namespace, Name: route.Name}
+	routeKey := types.NamespacedName{Namespace: route.Namespace, Name: fmt.Sprintf("%s-%s", route.Name, rule.Name)}
+	if _, ok := cv.ir.HTTPRoutes[routeKey]; ok {
+		return field.ErrorList{field.Duplicate(rulePath.Child("name"), rule.Name)}
+	}
+
+	var errs field.ErrorList
+	matches, matchErrs := httpMatches(route, rule, rulePath.Child("match"))
+	errs = append(errs, matchErrs...)
+	backendRefs, backendErrs := cv.httpBackendRefs(route, rule, rulePath)
+	errs = append(errs, backendErrs...)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	var hostnames []gatewayv1.Hostname
+	for _, host := range rule.Match.Hosts {
+		if !slices.Contains(hostnames, gatewayv1.Hostname(host)) {
+			hostnames = append(hostnames, gatewayv1.Hostname(host))
+		}
+	}
+	if len(hostnames) == 0 {
+		cv.addListener(route.Namespace, class, httpListener(""))
+	}
+	for _, hostname := range hostnames {
+		cv.addListener(route.Namespace, class, httpListener(hostname))
+	}
+
+	routeIR := &intermediate.ApisixHTTPRouteIR{}
+	var unsupported []intermediate.UnsupportedFeature
+	filters, pluginErrs := cv.ruleFilters(route, rule, rulePath, routeIR, &unsupported)
+	errs = append(errs, pluginErrs...)
+	unsupported = append(unsupported, unsupportedRuleFields(source, rule)...)
+	if len(unsupported) > 0 {
+		var names []string
+		for _, feature := range unsupported {
+			names = append(names, feature.Name)
+		}
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %s: %v have no Gateway API equivalent and must be ported manually", RouteKind, source, names), route)
+	}
+
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(class)}}},
+			Hostnames:       hostnames,
+			Rules: []gatewayv1.HTTPRouteRule{{
+				Matches:     matches,
+				Filters:     filters,
+				BackendRefs: backendRefs,
+			}},
+		},
+	}
+	httpRoute.SetGroupVersionKind(common.HTTPRouteGVK)
+	httpRouteContext := intermediate.HTTPRouteContext{
+		HTTPRoute:           httpRoute,
+		Sources:             []client.Object{route},
+		UnsupportedFeatures: unsupported,
+	}
+	if len(routeIR.Plugins) > 0 {
+		httpRouteContext.ProviderSpecificIR.Apisix = routeIR
+	}
+	cv.ir.HTTPRoutes[routeKey] = httpRouteContext
+	return errs
+}
+
+// httpMatches returns the matches of the paths, methods and expressions of the
+// rule: the paths, the methods and the values of the In expressions are ORed,
+// the expressions ANDed.
+func httpMatches(route *ApisixRoute, rule ApisixRouteHTTP, matchPath *field.Path) ([]gatewayv1.HTTPRouteMatch, field.ErrorList) {
+	source := types.NamespacedName{Namespace: route.Namespace, Name: route.Name}
+	paths := rule.Match.Paths
+	if len(paths) == 0 {
+		paths = []string{"/*"}
+	}
+	var matches []gatewayv1.HTTPRouteMatch
+	for _, path := range paths {
+		pathMatch := toPathMatch(path)
+		if pathMatch.Type != nil && *pathMatch.Type == gatewayv1.PathMatchPathPrefix && !strings.HasSuffix(path, "/*") && path != "/*" {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s %s: path %s matches the paths starting with %s, the PathPrefix match only matches the paths of which it is a prefix of elements", RouteKind, source, path, *pathMatch.Value), route)
+		}
+		matches = append(matches, gatewayv1.HTTPRouteMatch{Path: pathMatch})
+	}
+
+	var errs field.ErrorList
+	if len(rule.Match.Methods) > 0 {
+		var methods []gatewayv1.HTTPMethod
+		for i, method := range rule.Match.Methods {
+			httpMethod := gatewayv1.HTTPMethod(strings.ToUpper(method))
+			if !slices.Contains(supportedMethods, httpMethod) {
+				errs = append(errs, field.NotSupported(matchPath.Child("methods").Index(i), method, methodNames()))
+				continue
+			}
+			methods = append(methods, httpMethod)
+		}
+		var methodMatches []gatewayv1.HTTPRouteMatch
+		for _, match := range matches {
+			for _, method := range methods {
+				methodMatch := *match.DeepCopy()
+				methodMatch.Method = ptr.To(method)
+				methodMatches = append(methodMatches, methodMatch)
+			}
+		}
+		matches = methodMatches
+	}
+
+	for _, expr := range rule.Match.Exprs {
+		alternatives, ok := exprAlternatives(expr)
+		if !ok {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s %s: the expression on the %s %s with the %s operator has no Gateway API equivalent, it was ignored and the rule %s matches more requests", RouteKind, source, expr.Subject.Scope, expr.Subject.Name, expr.Op, rule.Name), route)
+			continue
+		}
+		var exprMatches []gatewayv1.HTTPRouteMatch
+		for _, match := range matches {
+			for _, alternative := range alternatives {
+				exprMatch := *match.DeepCopy()
+				exprMatch.Headers = append(exprMatch.Headers, alternative.Headers...)
+				exprMatch.QueryParams = append(exprMatch.QueryParams, alternative.QueryParams...)
+				exprMatches = append(exprMatches, exprMatch)
+			}
+		}
+		matches = exprMatches
+	}
+
+	if len(matches) > maxMatches {
+		errs = append(errs, field.TooMany(matchPath, len(matches), maxMatches))
+	}
+	return matches, errs
+}
+
+// toPathMatch converts an APISIX path, matching the paths starting with its
+// prefix when it ends with *, and the path itself otherwise.
+func toPathMatch(path string) *gatewayv1.HTTPPathMatch {
+	if prefix, ok := strings.CutSuffix(path, "*"); ok {
+		if prefix != "/" {
+			prefix = strings.TrimSuffix(prefix, "/")
+		}
+		return &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To(prefix)}
+	}
+	return &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchExact), Value: ptr.To(path)}
+}
+
+// exprAlternatives returns the alternative header or query parameter matches
+// of the expression, and whether it has a Gateway API equivalent.
+func exprAlternatives(expr ApisixRouteHTTPMatchExpr) ([]gatewayv1.HTTPRouteMatch, bool) {
+	var (
+		values    []string
+		matchType string
+	)
+	switch expr.Op {
+	case "Equal":
+		if expr.Value == nil {
+			return nil, false
+		}
+		values, matchType = []string{*expr.Value}, "Exact"
+	case "In":
+		values, matchType = expr.Set, "Exact"
+	case "RegexMatch":
+		if expr.Value == nil {
+			return nil, false
+		}
+		values, matchType = []string{*expr.Value}, "RegularExpression"
+	case "RegexMatchCaseInsensitive":
+		if expr.Value == nil {
+			return nil, false
+		}
+		values, matchType = []string{"(?i)" + *expr.Value}, "RegularExpression"
+	default:
+		return nil, false
+	}
+	if len(values) == 0 || expr.Subject.Name == "" {
+		return nil, false
+	}
+
+	var alternatives []gatewayv1.HTTPRouteMatch
+	for _, value := range values {
+		switch expr.Subject.Scope {
+		case "Header":
+			alternatives = append(alternatives, gatewayv1.HTTPRouteMatch{Headers: []gatewayv1.HTTPHeaderMatch{{
+				Type:  ptr.To(gatewayv1.HeaderMatchType(matchType)),
+				Name:  gatewayv1.HTTPHeaderName(expr.Subject.Name),
+				Value: value,
+			}}})
+		case "Query":
+			alternatives = append(alternatives, gatewayv1.HTTPRouteMatch{QueryParams: []gatewayv1.HTTPQueryParamMatch{{
+				Type:  ptr.To(gatewayv1.QueryParamMatchType(matchType)),
+				Name:  gatewayv1.HTTPHeaderName(expr.Subject.Name),
+				Value: value,
+			}}})
+		default:
+			return nil, false
+		}
+	}
+	return alternatives, true
+}
+
+var supportedMethods = []gatewayv1.HTTPMethod{
+	gatewayv1.HTTPMethodGet, gatewayv1.HTTPMethodHead, gatewayv1.HTTPMethodPost, gatewayv1.HTTPMethodPut, gatewayv1.HTTPMethodDelete,
+	gatewayv1.HTTPMethodConnect, gatewayv1.HTTPMethodOptions, gatewayv1.HTTPMethodTrace, gatewayv1.HTTPMethodPatch,
+}
+
+func methodNames() []string {
+	var names []string
+	for _, method := range supportedMethods {
+		names = append(names, string(method))
+	}
+	return names
+}
+
+// httpBackendRefs returns the weighted references to the Services of the
+// backends of the rule, converting their ApisixUpstreams.
+func (cv *crdConversion) httpBackendRefs(route *ApisixRoute, rule ApisixRouteHTTP, rulePath *field.Path) ([]gatewayv1.HTTPBackendRef, field.ErrorList) {
+	if len(rule.Backends) == 0 {
+		if len(rule.Upstreams) > 0 {
+			return nil, field.ErrorList{field.Invalid(rulePath.Child("upstreams"), rule.Upstreams, "the upstreams of external nodes are not supported, the rule requires backends")}
+		}
+		return nil, field.ErrorList{field.Required(rulePath.Child("backends"), "the rule requires backends")}
+	}
+
+	var errs field.ErrorList
+	var backendRefs []gatewayv1.HTTPBackendRef
+	for i, backend := range rule.Backends {
+		backendPath := rulePath.Child("backends").Index(i)
+		port, err := servicePort(backend.ServicePort, backendPath.Child("servicePort"))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		backendRef := gatewayv1.HTTPBackendRef{BackendRef: gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: gatewayv1.ObjectName(backend.ServiceName),
+				Port: ptr.To(port),
+			},
+		}}
+		// APISIX balances the requests between the backends of a rule with a
+		// default weight of 100.
+		if len(rule.Backends) > 1 || backend.Weight != nil {
+			weight := 100
+			if backend.Weight != nil {
+				weight = *backend.Weight
+			}
+			backendRef.Weight = ptr.To(int32(weight))
+		}
+		backendRefs = append(backendRefs, backendRef)
+		cv.convertUpstream(types.NamespacedName{Namespace: route.Namespace, Name: backend.ServiceName})
+	}
+	return backendRefs, errs
+}
+
+// servicePort returns the number of the port, the names of the ports of the
+// Services being unknown.
+func servicePort(port intstr.IntOrString, path *field.Path) (gatewayv1.PortNumber, *field.Error) {
+	if port.Type == intstr.String {
+		return 0, field.Invalid(path, port.StrVal, "named service ports are not supported, use the port number")
+	}
+	return gatewayv1.PortNumber(port.IntVal), nil
+}
+
+// ruleFilters converts the plugins of the rule, and of the ApisixPluginConfig
+// it references, into filters. The plugins of the rule take precedence over
+// the ones of the ApisixPluginConfig, as in APISIX.
+func (cv *crdConversion) ruleFilters(route *ApisixRoute, rule ApisixRouteHTTP, rulePath *field.Path, routeIR *intermediate.ApisixHTTPRouteIR, unsupported *[]intermediate.UnsupportedFeature) ([]gatewayv1.HTTPRouteFilter, field.ErrorList) {
+	source := types.NamespacedName{Namespace: route.Namespace, Name: route.Name}
+	converted, errs := convertPlugins(source, rule.Plugins, rulePath.Child("plugins"))
+	var unsupportedPlugins []string
+	for _, plugin := range converted.unsupported {
+		unsupportedPlugins = append(unsupportedPlugins, plugin.Name)
+		*unsupported = append(*unsupported, intermediate.UnsupportedFeature{SourceKind: RouteKind, Source: source, Name: plugin.Name, RawConfig: plugin.Config})
+	}
+	if len(unsupportedPlugins) > 0 {
+		notify(notifications.WarningNotification, fmt.Sprintf("plugins %v of %s %s have no Gateway API equivalent and must be ported manually", unsupportedPlugins, RouteKind, source), route)
+	}
+	filters := converted.filters
+	routeIR.Plugins = append(routeIR.Plugins, converted.plugins...)
+
+	if rule.PluginConfigName == "" {
+		return filters, errs
+	}
+	pluginConfigKey := types.NamespacedName{Namespace: route.Namespace, Name: rule.PluginConfigName}
+	pluginConfig, ok := cv.storage.PluginConfigs[pluginConfigKey]
+	if !ok {
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %s referenced by %s %s was not found, its plugins were not converted", PluginConfigKind, pluginConfigKey, RouteKind, source), route)
+		return filters, errs
+	}
+	converted, pluginErrs := convertPlugins(pluginConfigKey, pluginConfig.Spec.Plugins, field.NewPath(PluginConfigKind).Key(pluginConfigKey.String()).Child("spec", "plugins"))
+	errs = append(errs, pluginErrs...)
+	notifyUnsupportedPlugins(PluginConfigKind, pluginConfigKey, converted.unsupported, pluginConfig)
+	for _, plugin := range converted.unsupported {
+		*unsupported = append(*unsupported, intermediate.UnsupportedFeature{SourceKind: PluginConfigKind, Source: pluginConfigKey, Name: plugin.Name, RawConfig: plugin.Config})
+	}
+	for _, filter := range converted.filters {
+		if !slices.ContainsFunc(filters, func(existing gatewayv1.HTTPRouteFilter) bool { return existing.Type == filter.Type }) {
+			filters = append(filters, filter)
+		}
+	}
+	for _, plugin := range converted.plugins {
+		if !slices.ContainsFunc(routeIR.Plugins, func(existing intermediate.ApisixPlugin) bool { return existing.Name == plugin.Name }) {
+			routeIR.Plugins = append(routeIR.Plugins, plugin)
+		}
+	}
+	return filters, errs
+}
+
+// unsupportedRuleFields returns the fields of the rule without Gateway API
+// equivalent.
+func unsupportedRuleFields(source types.NamespacedName, rule ApisixRouteHTTP) []intermediate.UnsupportedFeature {
+	fields := map[string]interface{}{}
+	if rule.Priority != 0 {
+		fields["priority"] = rule.Priority
+	}
+	if rule.Timeout != nil {
+		fields["timeout"] = rule.Timeout
+	}
+	if len(rule.Match.RemoteAddrs) > 0 {
+		fields["match.remoteAddrs"] = rule.Match.RemoteAddrs
+	}
+	if len(rule.Upstreams) > 0 {
+		fields["upstreams"] = rule.Upstreams
+	}
+	if rule.Authentication != nil && rule.Authentication.Enable {
+		fields["authentication"] = rule.Authentication
+	}
+	for _, backend := range rule.Backends {
+		if backend.Subset != "" {
+			fields["backends.subset"] = backend.Subset
+		}
+	}
+	var features []intermediate.UnsupportedFeature
+	for _, name := range []string{"priority", "timeout", "match.remoteAddrs", "upstreams", "authentication", "backends.subset"} {
+		value, ok := fields[name]
+		if !ok {
+			continue
+		}
+		rawConfig, _ := json.Marshal(value)
+		features = append(features, intermediate.UnsupportedFeature{
+			SourceKind: RouteKind,
+			Source:     source,
+			Name:       fmt.Sprintf("http[%s].%s", rule.Name, name),
+			RawConfig:  string(rawConfig),
+		})
+	}
+	return features
+}
+
+// convertStreamRule converts the stream rule into a TCPRoute or a UDPRoute
+// attached to a listener of its port.
+func (cv *crdConversion) convertStreamRule(route *ApisixRoute, class string, rule ApisixRouteStream, rulePath *field.Path) field.ErrorList {
+	source := types.NamespacedName{Namespace: route.Namespace, Name: route.Name}
+	routeKey := types.NamespacedName{Namespace: route.Namespace, Name: fmt.Sprintf("%s-%s", route.Name, rule.Name)}
+	port, err := servicePort(rule.Backend.ServicePort, rulePath.Child("backend", "servicePort"))
+	if err != nil {
+		return field.ErrorList{err}
+	}
+	if rule.Match.IngressPort == 0 {
+		return field.ErrorList{field.Required(rulePath.Child("match", "ingressPort"), "the stream rule requires an ingress port")}
+	}
+	if rule.Match.Host != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %s: the stream rule %s matching the SNI %s was converted without matching it", RouteKind, source, rule.Name, rule.Match.Host), route)
+	}
+	if rule.Backend.Subset != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %s: the subset %s of the stream rule %s has no Gateway API equivalent, the connections are routed to all the endpoints of the Service", RouteKind, source, rule.Backend.Subset, rule.Name), route)
+	}
+
+	backendRefs := []gatewayv1.BackendRef{{BackendObjectReference: gatewayv1.BackendObjectReference{
+		Name: gatewayv1.ObjectName(rule.Backend.ServiceName),
+		Port: ptr.To(port),
+	}}}
+	protocol := gatewayv1.ProtocolType(strings.ToUpper(rule.Protocol))
+	listenerName := gatewayv1.SectionName(fmt.Sprintf("%s-%d", strings.ToLower(string(protocol)), rule.Match.IngressPort))
+	parentRefs := []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(class), SectionName: ptr.To(listenerName)}}
+	objectMeta := metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name}
+
+	switch protocol {
+	case gatewayv1.TCPProtocolType:
+		if _, ok := cv.ir.TCPRoutes[routeKey]; ok {
+			return field.ErrorList{field.Duplicate(rulePath.Child("name"), rule.Name)}
+		}
+		tcpRoute := gatewayv1alpha2.TCPRoute{
+			ObjectMeta: objectMeta,
+			Spec: gatewayv1alpha2.TCPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+				Rules:           []gatewayv1alpha2.TCPRouteRule{{BackendRefs: backendRefs}},
+			},
+		}
+		tcpRoute.SetGroupVersionKind(common.TCPRouteGVK)
+		if cv.ir.TCPRoutes == nil {
+			cv.ir.TCPRoutes = map[types.NamespacedName]gatewayv1alpha2.TCPRoute{}
+		}
+		cv.ir.TCPRoutes[routeKey] = tcpRoute
+	case gatewayv1.UDPProtocolType:
+		if _, ok := cv.ir.UDPRoutes[routeKey]; ok {
+			return field.ErrorList{field.Duplicate(rulePath.Child("name"), rule.Name)}
+		}
+		udpRoute := gatewayv1alpha2.UDPRoute{
+			ObjectMeta: objectMeta,
+			Spec: gatewayv1alpha2.UDPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+				Rules:           []gatewayv1alpha2.UDPRouteRule{{BackendRefs: backendRefs}},
+			},
+		}
+		udpRoute.SetGroupVersionKind(common.UDPRouteGVK)
+		if cv.ir.UDPRoutes == nil {
+			cv.ir.UDPRoutes = map[types.NamespacedName]gatewayv1alpha2.UDPRoute{}
+		}
+		cv.ir.UDPRoutes[routeKey] = udpRoute
+	default:
+		return field.ErrorList{field.NotSupported(rulePath.Child("protocol"), rule.Protocol, []string{string(gatewayv1.TCPProtocolType), string(gatewayv1.UDPProtocolType)})}
+	}
+
+	cv.addListener(route.Namespace, class, gatewayv1.Listener{
+		Name:     listenerName,
+		Port:     gatewayv1.PortNumber(rule.Match.IngressPort),
+		Protocol: protocol,
+	})
+	cv.convertUpstream(types.NamespacedName{Namespace: route.Namespace, Name: rule.Backend.ServiceName})
+	return nil
+}
+
+// addListener adds the listener to the Gateway of the ingress class in the
+// namespace, creating it if needed, unless it already has a listener of the
+// same name.
+func (cv *crdConversion) addListener(namespace, class string, listener gatewayv1.Listener) {
+	key := types.NamespacedName{Namespace: namespace, Name: class}
+	gatewayContext, ok := cv.ir.Gateways[key]
+	if !ok {
+		gatewayContext.Gateway = gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: class},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: gatewayv1.ObjectName(class)},
+		}
+		gatewayContext.Gateway.SetGroupVersionKind(common.GatewayGVK)
+	}
+	if !slices.ContainsFunc(gatewayContext.Spec.Listeners, func(existing gatewayv1.Listener) bool { return existing.Name == listener.Name }) {
+		gatewayContext.Spec.Listeners = append(gatewayContext.Spec.Listeners, listener)
+	}
+	cv.ir.Gateways[key] = gatewayContext
+}
+
+// httpListener returns the HTTP listener of the hostname, named as the
+// listeners of the Gateways of the Ingresses.
+func httpListener(hostname gatewayv1.Hostname) gatewayv1.Listener {
+	listener := gatewayv1.Listener{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}
+	if hostname != "" {
+		listener.Name = gatewayv1.SectionName(fmt.Sprintf("%s-http", common.NameFromHost(string(hostname))))
+		listener.Hostname = ptr.To(hostname)
+	}
+	return listener
+}
+
+func ingressClass(ingressClassName string) string {
+	if ingressClassName == "" {
+		return ApisixIngressClass
+	}
+	return ingressClassName
+}
+
+func sortedKeys[T any](m map[types.NamespacedName]T) []types.NamespacedName {
+	keys := make([]types.NamespacedName, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return keys
+}
+
+// addReferenceGrant grants the Gateways of the namespace the references to
+// the Secret of another namespace.
+func (cv *crdConversion) addReferenceGrant(fromNamespace, secretNamespace, secret string) {
+	key := types.NamespacedName{Namespace: secretNamespace, Name: fmt.Sprintf("generated-reference-grant-from-%s-to-%s", fromNamespace, secretNamespace)}
+	if cv.ir.ReferenceGrants == nil {
+		cv.ir.ReferenceGrants = map[types.NamespacedName]gatewayv1beta1.ReferenceGrant{}
+	}
+	referenceGrant, ok := cv.ir.ReferenceGrants[key]
+	if !ok {
+		referenceGrant = gatewayv1beta1.ReferenceGrant{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+		referenceGrant.SetGroupVersionKind(common.ReferenceGrantGVK)
+	}
+	from := gatewayv1beta1.ReferenceGrantFrom{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: gatewayv1.Namespace(fromNamespace)}
+	if !slices.Contains(referenceGrant.Spec.From, from) {
+		referenceGrant.Spec.From = append(referenceGrant.Spec.From, from)
+	}
+	to := gatewayv1beta1.ReferenceGrantTo{Group: "", Kind: "Secret", Name: ptr.To(gatewayv1.ObjectName(secret))}
+	if !slices.ContainsFunc(referenceGrant.Spec.To, func(existing gatewayv1beta1.ReferenceGrantTo) bool {
+		return existing.Kind == to.Kind && existing.Name != nil && *existing.Name == *to.Name
+	}) {
+		referenceGrant.Spec.To = append(referenceGrant.Spec.To, to)
+	}
+	cv.ir.ReferenceGrants[key] = referenceGrant
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apisix
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_httpMatches(t *testing.T) {
+	route := &ApisixRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"}}
+
+	testCases := []struct {
+		name            string
+		match           ApisixRouteHTTPMatch
+		expectedMatches []gatewayv1.HTTPRouteMatch
+		expectedErrors  int
+	}{
+		{
+			name: "no paths",
+			expectedMatches: []gatewayv1.HTTPRouteMatch{
+				{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/")}},
+			},
+		},
+		{
+			name:  "prefix and exact paths with methods",
+			match: ApisixRouteHTTPMatch{Paths: []string{"/api/*", "/health"}, Methods: []string{"get", "HEAD"}},
+			expectedMatches: []gatewayv1.HTTPRouteMatch{
+				{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/api")}, Method: ptr.To(gatewayv1.HTTPMethodGet)},
+				{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/api")}, Method: ptr.To(gatewayv1.HTTPMethodHead)},
+				{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchExact), Value: ptr.To("/health")}, Method: ptr.To(gatewayv1.HTTPMethodGet)},
+				{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchExact), Value: ptr.To("/health")}, Method: ptr.To(gatewayv1.HTTPMethodHead)},
+			},
+		},
+		{
+			name: "header and query expressions",
+			match: ApisixRouteHTTPMatch{
+				Paths: []string{"/*"},
+				Exprs: []ApisixRouteHTTPMatchExpr{
+					{Subject: ApisixRouteHTTPMatchExprSubject{Scope: "Header", Name: "X-Env"}, Op: "In", Set: []string{"dev", "test"}},
+					{Subject: ApisixRouteHTTPMatchExprSubject{Scope: "Query", Name: "id"}, Op: "RegexMatchCaseInsensitive", Value: ptr.To("^a")},
+					{Subject: ApisixRouteHTTPMatchExprSubject{Scope: "Cookie", Name: "session"}, Op: "Equal", Value: ptr.To("1")},
+				},
+			},
+			expectedMatches: []gatewayv1.HTTPRouteMatch{
+				{
+					Path:        &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/")},
+					Headers:     []gatewayv1.HTTPHeaderMatch{{Type: ptr.To(gatewayv1.HeaderMatchExact), Name: "X-Env", Value: "dev"}},
+					QueryParams: []gatewayv1.HTTPQueryParamMatch{{Type: ptr.To(gatewayv1.QueryParamMatchRegularExpression), Name: "id", Value: "(?i)^a"}},
+				},
+				{
+					Path:        &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/")},
+					Headers:     []gatewayv1.HTTPHeaderMatch{{Type: ptr.To(gatewayv1.HeaderMatchExact), Name: "X-Env", Value: "test"}},
+					QueryParams: []gatewayv1.HTTPQueryParamMatch{{Type: ptr.To(gatewayv1.QueryParamMatchRegularExpression), Name: "id", Value: "(?i)^a"}},
+				},
+			},
+		},
+		{
+			name:           "unknown method",
+			match:          ApisixRouteHTTPMatch{Paths: []string{"/api"}, Methods: []string{"PURGE"}},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := ApisixRouteHTTP{Name: "rule", Match: tc.match}
+			matches, errs := httpMatches(route, rule, field.NewPath("match"))
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if tc.expectedErrors > 0 {
+				return
+			}
+			if diff := cmp.Diff(tc.expectedMatches, matches); diff != "" {
+				t.Errorf("unexpected matches (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_crdsToIR(t *testing.T) {
+	route := &ApisixRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "shop"},
+		Spec: ApisixRouteSpec{
+			HTTP: []ApisixRouteHTTP{{
+				Name:  "web",
+				Match: ApisixRouteHTTPMatch{Hosts: []string{"shop.example.com"}, Paths: []string{"/*"}},
+				Backends: []ApisixRouteHTTPBackend{
+					{ServiceName: "shop-v1", ServicePort: intstr.FromInt(80)},
+					{ServiceName: "shop-v2", ServicePort: intstr.FromInt(80), Weight: ptr.To(20)},
+				},
+			}},
+		},
+	}
+	tls := &ApisixTls{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "shop"},
+		Spec: ApisixTlsSpec{
+			Hosts:  []string{"shop.example.com"},
+			Secret: ApisixSecret{Name: "shop-cert", Namespace: "certs"},
+		},
+	}
+	upstream := &ApisixUpstream{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "shop-v1"},
+		Spec:       ApisixUpstreamSpec{ApisixUpstreamConfig: ApisixUpstreamConfig{Scheme: "https"}},
+	}
+
+	storage := newResourcesStorage()
+	storage.Routes[types.NamespacedName{Namespace: "default", Name: "shop"}] = route
+	storage.TLSes[types.NamespacedName{Namespace: "default", Name: "shop"}] = tls
+	storage.Upstreams[types.NamespacedName{Namespace: "default", Name: "shop-v1"}] = upstream
+
+	ir := intermediate.IR{}
+	if errs := crdsToIR(storage, "System", &ir); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	httpRoute, ok := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "shop-web"}]
+	if !ok {
+		t.Fatalf("expected HTTPRoute default/shop-web, got %v", ir.HTTPRoutes)
+	}
+	var weights []int32
+	for _, backendRef := range httpRoute.Spec.Rules[0].BackendRefs {
+		weights = append(weights, *backendRef.Weight)
+	}
+	if diff := cmp.Diff([]int32{100, 20}, weights); diff != "" {
+		t.Errorf("unexpected backend weights (-want +got):\n%s", diff)
+	}
+
+	gateway := ir.Gateways[types.NamespacedName{Namespace: "default", Name: ApisixIngressClass}]
+	var listeners []gatewayv1.SectionName
+	for _, listener := range gateway.Spec.Listeners {
+		listeners = append(listeners, listener.Name)
+	}
+	if diff := cmp.Diff([]gatewayv1.SectionName{"shop-example-com-http", "shop-example-com-https"}, listeners); diff != "" {
+		t.Errorf("unexpected listeners (-want +got):\n%s", diff)
+	}
+	if len(ir.ReferenceGrants) != 1 {
+		t.Errorf("expected a ReferenceGrant to the Secret, got %v", ir.ReferenceGrants)
+	}
+	if _, ok := ir.BackendTLSPolicies[types.NamespacedName{Namespace: "default", Name: "shop-v1-backend-tls"}]; !ok {
+		t.Errorf("expected BackendTLSPolicy default/shop-v1-backend-tls, got %v", ir.BackendTLSPolicies)
+	}
+}
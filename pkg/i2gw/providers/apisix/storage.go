@@ -22,11 +22,21 @@ import (
 )
 
 type storage struct {
-	Ingresses map[types.NamespacedName]*networkingv1.Ingress
+	Ingresses     map[types.NamespacedName]*networkingv1.Ingress
+	Routes        map[types.NamespacedName]*ApisixRoute
+	Upstreams     map[types.NamespacedName]*ApisixUpstream
+	TLSes         map[types.NamespacedName]*ApisixTls
+	PluginConfigs map[types.NamespacedName]*ApisixPluginConfig
+	GlobalRules   map[types.NamespacedName]*ApisixGlobalRule
 }
 
 func newResourcesStorage() *storage {
 	return &storage{
-		Ingresses: map[types.NamespacedName]*networkingv1.Ingress{},
+		Ingresses:     map[types.NamespacedName]*networkingv1.Ingress{},
+		Routes:        map[types.NamespacedName]*ApisixRoute{},
+		Upstreams:     map[types.NamespacedName]*ApisixUpstream{},
+		TLSes:         map[types.NamespacedName]*ApisixTls{},
+		PluginConfigs: map[types.NamespacedName]*ApisixPluginConfig{},
+		GlobalRules:   map[types.NamespacedName]*ApisixGlobalRule{},
 	}
 }
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apisix
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// convertTLS adds an HTTPS listener terminating TLS with the Secret of the
+// ApisixTls for each of its hosts to the Gateways of its ingress class with
+// an HTTP listener for the host.
+func (cv *crdConversion) convertTLS(apisixTLS *ApisixTls) field.ErrorList {
+	source := types.NamespacedName{Namespace: apisixTLS.Namespace, Name: apisixTLS.Name}
+	specPath := field.NewPath(TLSKind).Key(source.String()).Child("spec")
+	if apisixTLS.Spec.Secret.Name == "" {
+		return field.ErrorList{field.Required(specPath.Child("secret", "name"), "the ApisixTls requires a secret")}
+	}
+	secretNamespace := apisixTLS.Spec.Secret.Namespace
+	if secretNamespace == "" {
+		secretNamespace = apisixTLS.Namespace
+	}
+	if apisixTLS.Spec.Client != nil {
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %s: the client certificate validation has no Gateway API equivalent and must be ported manually", TLSKind, source), apisixTLS)
+	}
+
+	class := ingressClass(apisixTLS.Spec.IngressClassName)
+	for _, host := range apisixTLS.Spec.Hosts {
+		hostname := gatewayv1.Hostname(host)
+		httpsListener := gatewayv1.Listener{
+			Name:     gatewayv1.SectionName(fmt.Sprintf("%s-https", common.NameFromHost(host))),
+			Hostname: ptr.To(hostname),
+			Port:     443,
+			Protocol: gatewayv1.HTTPSProtocolType,
+		}
+		matched := false
+		for _, key := range sortedKeys(cv.ir.Gateways) {
+			gatewayContext := cv.ir.Gateways[key]
+			if string(gatewayContext.Spec.GatewayClassName) != class || !servesHostname(gatewayContext.Spec.Listeners, hostname) {
+				continue
+			}
+			matched = true
+			if slices.ContainsFunc(gatewayContext.Spec.Listeners, func(existing gatewayv1.Listener) bool { return existing.Name == httpsListener.Name }) {
+				continue
+			}
+			certificateRef := gatewayv1.SecretObjectReference{Name: gatewayv1.ObjectName(apisixTLS.Spec.Secret.Name)}
+			if secretNamespace != key.Namespace {
+				certificateRef.Namespace = ptr.To(gatewayv1.Namespace(secretNamespace))
+				cv.addReferenceGrant(key.Namespace, secretNamespace, apisixTLS.Spec.Secret.Name)
+			}
+			listener := httpsListener
+			listener.TLS = &gatewayv1.GatewayTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{certificateRef}}
+			gatewayContext.Spec.Listeners = append(gatewayContext.Spec.Listeners, listener)
+			cv.ir.Gateways[key] = gatewayContext
+		}
+		if !matched {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s %s: no Gateway of the ingress class %s serves the host %s, its certificate was not converted", TLSKind, source, class, host), apisixTLS)
+		}
+	}
+	return nil
+}
+
+// servesHostname returns whether one of the HTTP listeners has the hostname,
+// or a wildcard hostname matching it.
+func servesHostname(listeners []gatewayv1.Listener, hostname gatewayv1.Hostname) bool {
+	for _, listener := range listeners {
+		if listener.Protocol != gatewayv1.HTTPProtocolType || listener.Hostname == nil {
+			continue
+		}
+		listenerHostname := string(*listener.Hostname)
+		if listenerHostname == string(hostname) {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(listenerHostname, "*"); ok && strings.HasSuffix(string(hostname), suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,353 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apisix
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	APIVersion = "apisix.apache.org/v2"
+
+	RouteKind        = "ApisixRoute"
+	UpstreamKind     = "ApisixUpstream"
+	TLSKind          = "ApisixTls"
+	PluginConfigKind = "ApisixPluginConfig"
+	GlobalRuleKind   = "ApisixGlobalRule"
+)
+
+// The types below mirror the subset of the apisix.apache.org/v2 API read by
+// the provider, so that it doesn't depend on the APISIX ingress controller
+// module.
+
+// ApisixRoute routes the HTTP requests, and the TCP and UDP connections, to
+// Services.
+type ApisixRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApisixRouteSpec `json:"spec"`
+}
+
+type ApisixRouteSpec struct {
+	IngressClassName string              `json:"ingressClassName,omitempty"`
+	HTTP             []ApisixRouteHTTP   `json:"http,omitempty"`
+	Stream           []ApisixRouteStream `json:"stream,omitempty"`
+}
+
+// ApisixRouteHTTP is a rule routing the HTTP requests matching it.
+type ApisixRouteHTTP struct {
+	Name             string                     `json:"name"`
+	Priority         int                        `json:"priority,omitempty"`
+	Timeout          *UpstreamTimeout           `json:"timeout,omitempty"`
+	Match            ApisixRouteHTTPMatch       `json:"match"`
+	Backends         []ApisixRouteHTTPBackend   `json:"backends,omitempty"`
+	Upstreams        []ApisixRouteUpstreamRef   `json:"upstreams,omitempty"`
+	Websocket        bool                       `json:"websocket,omitempty"`
+	PluginConfigName string                     `json:"plugin_config_name,omitempty"`
+	Plugins          []ApisixRoutePlugin        `json:"plugins,omitempty"`
+	Authentication   *ApisixRouteAuthentication `json:"authentication,omitempty"`
+}
+
+type ApisixRouteHTTPMatch struct {
+	Paths       []string                   `json:"paths,omitempty"`
+	Methods     []string                   `json:"methods,omitempty"`
+	Hosts       []string                   `json:"hosts,omitempty"`
+	RemoteAddrs []string                   `json:"remoteAddrs,omitempty"`
+	Exprs       []ApisixRouteHTTPMatchExpr `json:"exprs,omitempty"`
+}
+
+// ApisixRouteHTTPMatchExpr matches a header, query argument, cookie or path of
+// the requests.
+type ApisixRouteHTTPMatchExpr struct {
+	Subject ApisixRouteHTTPMatchExprSubject `json:"subject"`
+	Op      string                          `json:"op"`
+	Set     []string                        `json:"set,omitempty"`
+	Value   *string                         `json:"value,omitempty"`
+}
+
+type ApisixRouteHTTPMatchExprSubject struct {
+	Scope string `json:"scope"`
+	Name  string `json:"name,omitempty"`
+}
+
+type ApisixRouteHTTPBackend struct {
+	ServiceName        string             `json:"serviceName"`
+	ServicePort        intstr.IntOrString `json:"servicePort"`
+	ResolveGranularity string             `json:"resolveGranularity,omitempty"`
+	Weight             *int               `json:"weight,omitempty"`
+	Subset             string             `json:"subset,omitempty"`
+}
+
+// ApisixRouteUpstreamRef references an ApisixUpstream with external nodes.
+type ApisixRouteUpstreamRef struct {
+	Name   string `json:"name"`
+	Weight *int   `json:"weight,omitempty"`
+}
+
+type ApisixRouteAuthentication struct {
+	Enable bool   `json:"enable"`
+	Type   string `json:"type"`
+}
+
+type UpstreamTimeout struct {
+	Connect metav1.Duration `json:"connect,omitempty"`
+	Send    metav1.Duration `json:"send,omitempty"`
+	Read    metav1.Duration `json:"read,omitempty"`
+}
+
+// ApisixRouteStream is a rule routing the TCP or UDP connections of a port.
+type ApisixRouteStream struct {
+	Name     string                   `json:"name"`
+	Protocol string                   `json:"protocol"`
+	Match    ApisixRouteStreamMatch   `json:"match"`
+	Backend  ApisixRouteStreamBackend `json:"backend"`
+}
+
+type ApisixRouteStreamMatch struct {
+	IngressPort int32  `json:"ingressPort"`
+	Host        string `json:"host,omitempty"`
+}
+
+type ApisixRouteStreamBackend struct {
+	ServiceName        string             `json:"serviceName"`
+	ServicePort        intstr.IntOrString `json:"servicePort"`
+	ResolveGranularity string             `json:"resolveGranularity,omitempty"`
+	Subset             string             `json:"subset,omitempty"`
+}
+
+// ApisixUpstream configures the connections to the Service of the same name.
+type ApisixUpstream struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApisixUpstreamSpec `json:"spec"`
+}
+
+type ApisixUpstreamSpec struct {
+	ApisixUpstreamConfig `json:",inline"`
+
+	ExternalNodes     []apiextensionsv1.JSON `json:"externalNodes,omitempty"`
+	Subsets           []apiextensionsv1.JSON `json:"subsets,omitempty"`
+	PortLevelSettings []PortLevelSettings    `json:"portLevelSettings,omitempty"`
+}
+
+type ApisixUpstreamConfig struct {
+	// Scheme is the protocol of the connections: http, https, grpc or grpcs.
+	Scheme       string                `json:"scheme,omitempty"`
+	LoadBalancer *apiextensionsv1.JSON `json:"loadbalancer,omitempty"`
+	Retries      *int                  `json:"retries,omitempty"`
+	Timeout      *UpstreamTimeout      `json:"timeout,omitempty"`
+	HealthCheck  *apiextensionsv1.JSON `json:"healthCheck,omitempty"`
+	// TLSSecret is the client certificate of the connections.
+	TLSSecret *ApisixSecret `json:"tlsSecret,omitempty"`
+}
+
+type PortLevelSettings struct {
+	ApisixUpstreamConfig `json:",inline"`
+
+	Port int32 `json:"port,omitempty"`
+}
+
+// ApisixTls holds the certificate of hosts.
+type ApisixTls struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApisixTlsSpec `json:"spec"`
+}
+
+type ApisixTlsSpec struct {
+	IngressClassName string       `json:"ingressClassName,omitempty"`
+	Hosts            []string     `json:"hosts"`
+	Secret           ApisixSecret `json:"secret"`
+	// Client configures the mutual TLS authentication of the clients.
+	Client *apiextensionsv1.JSON `json:"client,omitempty"`
+}
+
+type ApisixSecret struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// ApisixPluginConfig holds plugins shared by the routes referencing it.
+type ApisixPluginConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApisixPluginConfigSpec `json:"spec"`
+}
+
+type ApisixPluginConfigSpec struct {
+	Plugins []ApisixRoutePlugin `json:"plugins"`
+}
+
+// ApisixGlobalRule holds plugins run for all the routes.
+type ApisixGlobalRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApisixGlobalRuleSpec `json:"spec"`
+}
+
+type ApisixGlobalRuleSpec struct {
+	Plugins []ApisixRoutePlugin `json:"plugins"`
+}
+
+// ApisixRoutePlugin is a plugin, with its configuration.
+type ApisixRoutePlugin struct {
+	Name   string               `json:"name"`
+	Enable bool                 `json:"enable"`
+	Config apiextensionsv1.JSON `json:"config,omitempty"`
+	// SecretRef is the name of a Secret holding a part of the configuration.
+	SecretRef string `json:"secretRef,omitempty"`
+}
+
+// DeepCopyObject implements runtime.Object, for ApisixPluginConfigs to be
+// referenced by notifications.
+func (in *ApisixPluginConfig) DeepCopyObject() runtime.Object {
+	out := &ApisixPluginConfig{TypeMeta: in.TypeMeta}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.Plugins = deepCopyPlugins(in.Spec.Plugins)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object, for ApisixGlobalRules to be
+// referenced by notifications.
+func (in *ApisixGlobalRule) DeepCopyObject() runtime.Object {
+	out := &ApisixGlobalRule{TypeMeta: in.TypeMeta}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.Plugins = deepCopyPlugins(in.Spec.Plugins)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object, for ApisixRoutes to be referenced
+// by notifications and as the sources of the generated resources.
+func (in *ApisixRoute) DeepCopyObject() runtime.Object {
+	out := &ApisixRoute{TypeMeta: in.TypeMeta}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.IngressClassName = in.Spec.IngressClassName
+	for _, rule := range in.Spec.HTTP {
+		if rule.Timeout != nil {
+			rule.Timeout = ptr.To(*rule.Timeout)
+		}
+		rule.Match.Paths = append([]string(nil), rule.Match.Paths...)
+		rule.Match.Methods = append([]string(nil), rule.Match.Methods...)
+		rule.Match.Hosts = append([]string(nil), rule.Match.Hosts...)
+		rule.Match.RemoteAddrs = append([]string(nil), rule.Match.RemoteAddrs...)
+		var exprs []ApisixRouteHTTPMatchExpr
+		for _, expr := range rule.Match.Exprs {
+			expr.Set = append([]string(nil), expr.Set...)
+			if expr.Value != nil {
+				expr.Value = ptr.To(*expr.Value)
+			}
+			exprs = append(exprs, expr)
+		}
+		rule.Match.Exprs = exprs
+		var backends []ApisixRouteHTTPBackend
+		for _, backend := range rule.Backends {
+			if backend.Weight != nil {
+				backend.Weight = ptr.To(*backend.Weight)
+			}
+			backends = append(backends, backend)
+		}
+		rule.Backends = backends
+		var upstreams []ApisixRouteUpstreamRef
+		for _, upstream := range rule.Upstreams {
+			if upstream.Weight != nil {
+				upstream.Weight = ptr.To(*upstream.Weight)
+			}
+			upstreams = append(upstreams, upstream)
+		}
+		rule.Upstreams = upstreams
+		rule.Plugins = deepCopyPlugins(rule.Plugins)
+		if rule.Authentication != nil {
+			rule.Authentication = ptr.To(*rule.Authentication)
+		}
+		out.Spec.HTTP = append(out.Spec.HTTP, rule)
+	}
+	out.Spec.Stream = append([]ApisixRouteStream(nil), in.Spec.Stream...)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object, for ApisixUpstreams to be
+// referenced by notifications.
+func (in *ApisixUpstream) DeepCopyObject() runtime.Object {
+	out := &ApisixUpstream{TypeMeta: in.TypeMeta}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.ApisixUpstreamConfig = deepCopyUpstreamConfig(in.Spec.ApisixUpstreamConfig)
+	out.Spec.ExternalNodes = deepCopyJSONs(in.Spec.ExternalNodes)
+	out.Spec.Subsets = deepCopyJSONs(in.Spec.Subsets)
+	for _, settings := range in.Spec.PortLevelSettings {
+		settings.ApisixUpstreamConfig = deepCopyUpstreamConfig(settings.ApisixUpstreamConfig)
+		out.Spec.PortLevelSettings = append(out.Spec.PortLevelSettings, settings)
+	}
+	return out
+}
+
+// DeepCopyObject implements runtime.Object, for ApisixTlses to be referenced
+// by notifications.
+func (in *ApisixTls) DeepCopyObject() runtime.Object {
+	out := &ApisixTls{TypeMeta: in.TypeMeta, Spec: in.Spec}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.Hosts = append([]string(nil), in.Spec.Hosts...)
+	out.Spec.Client = in.Spec.Client.DeepCopy()
+	return out
+}
+
+func deepCopyUpstreamConfig(in ApisixUpstreamConfig) ApisixUpstreamConfig {
+	out := in
+	out.LoadBalancer = in.LoadBalancer.DeepCopy()
+	out.HealthCheck = in.HealthCheck.DeepCopy()
+	if in.Retries != nil {
+		out.Retries = ptr.To(*in.Retries)
+	}
+	if in.Timeout != nil {
+		out.Timeout = ptr.To(*in.Timeout)
+	}
+	if in.TLSSecret != nil {
+		out.TLSSecret = ptr.To(*in.TLSSecret)
+	}
+	return out
+}
+
+func deepCopyJSONs(in []apiextensionsv1.JSON) []apiextensionsv1.JSON {
+	if in == nil {
+		return nil
+	}
+	out := make([]apiextensionsv1.JSON, len(in))
+	for i := range in {
+		in[i].DeepCopyInto(&out[i])
+	}
+	return out
+}
+
+func deepCopyPlugins(in []ApisixRoutePlugin) []ApisixRoutePlugin {
+	if in == nil {
+		return nil
+	}
+	out := make([]ApisixRoutePlugin, len(in))
+	for i := range in {
+		out[i] = in[i]
+		in[i].Config.DeepCopyInto(&out[i].Config)
+	}
+	return out
+}
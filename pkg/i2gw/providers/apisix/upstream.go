@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apisix
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+)
+
+// convertUpstream converts the ApisixUpstream of the Service, if any: the
+// https and grpcs schemes become a BackendTLSPolicy, validated with the
+// configured well-known CA certificates, and the other settings are reported.
+func (cv *crdConversion) convertUpstream(service types.NamespacedName) {
+	upstream, ok := cv.storage.Upstreams[service]
+	if !ok || cv.convertedUpstreams[service] {
+		return
+	}
+	cv.convertedUpstreams[service] = true
+
+	switch upstream.Spec.Scheme {
+	case "https", "grpcs":
+		cv.addBackendTLSPolicy(upstream)
+	}
+	if upstream.Spec.Scheme == "grpc" || upstream.Spec.Scheme == "grpcs" {
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %s: the %s scheme has no Gateway API equivalent, set the appProtocol of the port of the Service to kubernetes.io/h2c or route it with a GRPCRoute", UpstreamKind, service, upstream.Spec.Scheme), upstream)
+	}
+
+	var unsupported []string
+	for _, setting := range []struct {
+		name string
+		set  bool
+	}{
+		{"loadbalancer", upstream.Spec.LoadBalancer != nil},
+		{"retries", upstream.Spec.Retries != nil},
+		{"timeout", upstream.Spec.Timeout != nil},
+		{"healthCheck", upstream.Spec.HealthCheck != nil},
+		{"tlsSecret", upstream.Spec.TLSSecret != nil},
+		{"externalNodes", len(upstream.Spec.ExternalNodes) > 0},
+		{"subsets", len(upstream.Spec.Subsets) > 0},
+		{"portLevelSettings", len(upstream.Spec.PortLevelSettings) > 0},
+	} {
+		if setting.set {
+			unsupported = append(unsupported, setting.name)
+		}
+	}
+	if len(unsupported) > 0 {
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %s: %v have no Gateway API equivalent and must be ported manually", UpstreamKind, service, unsupported), upstream)
+	}
+}
+
+func (cv *crdConversion) addBackendTLSPolicy(upstream *ApisixUpstream) {
+	service := types.NamespacedName{Namespace: upstream.Namespace, Name: upstream.Name}
+	if cv.wellKnownCACertificates == "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %s: the connections to the Service use TLS, a BackendTLSPolicy was not generated, set --backend-tls-well-known-ca-certificates to validate them with well-known CA certificates", UpstreamKind, service), upstream)
+		return
+	}
+
+	key := types.NamespacedName{Namespace: service.Namespace, Name: fmt.Sprintf("%s-backend-tls", service.Name)}
+	policy := gatewayv1alpha3.BackendTLSPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Spec: gatewayv1alpha3.BackendTLSPolicySpec{
+			TargetRefs: []gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName{{
+				LocalPolicyTargetReference: gatewayv1alpha2.LocalPolicyTargetReference{Group: "", Kind: "Service", Name: gatewayv1.ObjectName(service.Name)},
+			}},
+			Validation: gatewayv1alpha3.BackendTLSPolicyValidation{
+				Hostname:                gatewayv1.PreciseHostname(fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)),
+				WellKnownCACertificates: ptr.To(cv.wellKnownCACertificates),
+			},
+		},
+	}
+	policy.SetGroupVersionKind(common.BackendTLSPolicyGVK)
+	if cv.ir.BackendTLSPolicies == nil {
+		cv.ir.BackendTLSPolicies = map[types.NamespacedName]gatewayv1alpha3.BackendTLSPolicy{}
+	}
+	cv.ir.BackendTLSPolicies[key] = policy
+	notify(notifications.InfoNotification, fmt.Sprintf("generated BackendTLSPolicy %s for %s %s", key, UpstreamKind, service), upstream)
+}
@@ -30,8 +30,23 @@ import (
 const Name = "cilium"
 const CiliumIngressClass = "cilium"
 
+// LoadBalancerModeFlag is the provider-specific flag setting the load balancer
+// mode of the Ingresses without the ingress.cilium.io/loadbalancer-mode
+// annotation, as configured in the Cilium ingress controller.
+const LoadBalancerModeFlag = "loadbalancer-mode"
+
 func init() {
 	i2gw.ProviderConstructorByName[Name] = NewProvider
+	i2gw.RegisterProviderSupportLevel(Name, i2gw.StableSupportLevel)
+	i2gw.RegisterIngressClaimRule(Name, i2gw.IngressClaimRule{
+		IngressClasses:     []string{CiliumIngressClass},
+		AnnotationPrefixes: []string{annotationPrefix + "/"},
+	})
+	i2gw.RegisterProviderSpecificFlag(Name, i2gw.ProviderSpecificFlag{
+		Name:         LoadBalancerModeFlag,
+		Description:  "The load balancer mode of the Ingresses without the ingress.cilium.io/loadbalancer-mode annotation, dedicated or shared.",
+		DefaultValue: DedicatedLoadBalancerMode,
+	})
 }
 
 // Provider implements the i2gw.Provider interface.
@@ -46,7 +61,7 @@ func NewProvider(conf *i2gw.ProviderConf) i2gw.Provider {
 	return &Provider{
 		storage:                newResourcesStorage(),
 		resourceReader:         newResourceReader(conf),
-		resourcesToIRConverter: newResourcesToIRConverter(),
+		resourcesToIRConverter: newResourcesToIRConverter(conf),
 	}
 }
 
@@ -17,36 +17,109 @@ limitations under the License.
 package cilium
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // resourcesToIRConverter implements the ToIR function of i2gw.ResourcesToIRConverter interface.
 type resourcesToIRConverter struct {
-	featureParsers                []i2gw.FeatureParser
+	featureChain                  *i2gw.FeatureChain
 	implementationSpecificOptions i2gw.ProviderImplementationSpecificOptions
+
+	// defaultLoadBalancerMode is the load balancer mode of the Ingresses
+	// without the ingress.cilium.io/loadbalancer-mode annotation.
+	defaultLoadBalancerMode string
 }
 
 // newResourcesToIRConverter returns a cilium resourcesToIRConverter instance.
-func newResourcesToIRConverter() *resourcesToIRConverter {
-	return &resourcesToIRConverter{
-		featureParsers: []i2gw.FeatureParser{
-			forceHTTPSFeature,
-		},
+func newResourcesToIRConverter(conf *i2gw.ProviderConf) *resourcesToIRConverter {
+	converter := &resourcesToIRConverter{
+		featureChain: i2gw.NewFeatureChain(
+			i2gw.NamedFeatureParser{Name: "force-https", Parse: forceHTTPSFeature},
+		).Use(conf.FeatureToggles.Middleware(Name)),
 		implementationSpecificOptions: i2gw.ProviderImplementationSpecificOptions{
 			// The list of the implementationSpecific ingress fields options comes here.
+			DisableRouteMerging: !conf.Profile.RouteMerging,
 		},
+		defaultLoadBalancerMode: DedicatedLoadBalancerMode,
 	}
+	if mode := conf.ProviderSpecificFlags[Name][LoadBalancerModeFlag]; mode != "" {
+		converter.defaultLoadBalancerMode = mode
+	}
+	return converter
 }
 
+// convertToIR converts the Ingresses sharing a load balancer to Gateways named
+// after their class, and each Ingress with a dedicated load balancer to a
+// Gateway of its own, since Cilium provisions a load balancer per Gateway. The
+// Ingresses passing TLS through to their backends are converted to TLSRoutes.
 func (c *resourcesToIRConverter) convertToIR(storage *storage) (intermediate.IR, field.ErrorList) {
-	ingressList := []networkingv1.Ingress{}
-	for _, ing := range storage.Ingresses {
-		ingressList = append(ingressList, *ing)
+	if c.defaultLoadBalancerMode != DedicatedLoadBalancerMode && c.defaultLoadBalancerMode != SharedLoadBalancerMode {
+		return intermediate.IR{}, field.ErrorList{field.NotSupported(field.NewPath(fmt.Sprintf("--%s-%s", Name, LoadBalancerModeFlag)), c.defaultLoadBalancerMode, []string{DedicatedLoadBalancerMode, SharedLoadBalancerMode})}
+	}
+
+	ingressKeys := make([]types.NamespacedName, 0, len(storage.Ingresses))
+	for key := range storage.Ingresses {
+		ingressKeys = append(ingressKeys, key)
+	}
+	slices.SortFunc(ingressKeys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	var (
+		irs             []intermediate.IR
+		sharedIngresses []networkingv1.Ingress
+		errs            field.ErrorList
+	)
+	for _, key := range ingressKeys {
+		ingress := *storage.Ingresses[key]
+		mode, err := loadBalancerMode(ingress, c.defaultLoadBalancerMode)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		gatewayName := common.GetIngressClass(ingress)
+		if mode == DedicatedLoadBalancerMode {
+			gatewayName = dedicatedGatewayName(ingress)
+		}
+		switch {
+		case isTLSPassthrough(ingress):
+			ir, passthroughErrs := tlsPassthroughToIR(ingress, gatewayName)
+			errs = append(errs, passthroughErrs...)
+			irs = append(irs, ir)
+		case mode == DedicatedLoadBalancerMode:
+			ir, convertErrs := c.convertIngresses([]networkingv1.Ingress{ingress})
+			errs = append(errs, convertErrs...)
+			dedicateGateway(&ir, ingress)
+			irs = append(irs, ir)
+		default:
+			sharedIngresses = append(sharedIngresses, ingress)
+		}
 	}
+	if len(sharedIngresses) > 0 {
+		ir, convertErrs := c.convertIngresses(sharedIngresses)
+		errs = append(errs, convertErrs...)
+		irs = append(irs, ir)
+	}
+	if len(errs) > 0 {
+		return intermediate.IR{}, errs
+	}
+
+	return intermediate.MergeIRs(irs...)
+}
+
+// convertIngresses converts the Ingresses, with their cilium specific
+// features, to an IR.
+func (c *resourcesToIRConverter) convertIngresses(ingressList []networkingv1.Ingress) (intermediate.IR, field.ErrorList) {
 	// Convert plain ingress resources to gateway resources, ignoring all
 	// provider-specific features.
 	ir, errs := common.ToIR(ingressList, c.implementationSpecificOptions)
@@ -54,12 +127,8 @@ func (c *resourcesToIRConverter) convertToIR(storage *storage) (intermediate.IR,
 		return intermediate.IR{}, errs
 	}
 
-	for _, parseFeatureFunc := range c.featureParsers {
-		// Apply the feature parsing function to the gateway resources, one by one.
-		parseErrs := parseFeatureFunc(ingressList, &ir)
-		// Append the parsing errors to the error list.
-		errs = append(errs, parseErrs...)
-	}
+	// Apply the feature parsing functions to the gateway resources, in order.
+	errs = append(errs, c.featureChain.Run(ingressList, &ir)...)
 
 	return ir, errs
 }
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cilium
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func testIngress(name string, annotations map[string]string) *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To(CiliumIngressClass),
+			Rules: []networkingv1.IngressRule{{
+				Host: name + ".example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: ptr.To(networkingv1.PathTypePrefix),
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: name,
+							Port: networkingv1.ServiceBackendPort{Number: 80},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+}
+
+func Test_convertToIR_loadBalancerMode(t *testing.T) {
+	testCases := []struct {
+		name             string
+		defaultMode      string
+		annotations      map[string]string
+		expectedGateways []string
+		expectedErrors   int
+	}{
+		{
+			name:             "dedicated by default",
+			expectedGateways: []string{"cilium-foo"},
+		},
+		{
+			name:             "shared by default",
+			defaultMode:      SharedLoadBalancerMode,
+			expectedGateways: []string{"cilium"},
+		},
+		{
+			name:             "dedicated by annotation",
+			defaultMode:      SharedLoadBalancerMode,
+			annotations:      map[string]string{"ingress.cilium.io/loadbalancer-mode": "dedicated"},
+			expectedGateways: []string{"cilium-foo"},
+		},
+		{
+			name:           "invalid annotation",
+			annotations:    map[string]string{"ingress.cilium.io/loadbalancer-mode": "exclusive"},
+			expectedErrors: 1,
+		},
+		{
+			name:           "invalid default mode",
+			defaultMode:    "exclusive",
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conf := &i2gw.ProviderConf{ProviderSpecificFlags: map[string]map[string]string{Name: {LoadBalancerModeFlag: tc.defaultMode}}}
+			storage := newResourcesStorage()
+			storage.Ingresses[types.NamespacedName{Namespace: "default", Name: "foo"}] = testIngress("foo", tc.annotations)
+
+			ir, errs := newResourcesToIRConverter(conf).convertToIR(storage)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if len(ir.Gateways) != len(tc.expectedGateways) {
+				t.Fatalf("expected Gateways %v, got %v", tc.expectedGateways, ir.Gateways)
+			}
+			for _, name := range tc.expectedGateways {
+				if _, ok := ir.Gateways[types.NamespacedName{Namespace: "default", Name: name}]; !ok {
+					t.Errorf("expected Gateway default/%s, got %v", name, ir.Gateways)
+				}
+			}
+			for key, httpRouteContext := range ir.HTTPRoutes {
+				if parentRef := httpRouteContext.Spec.ParentRefs[0]; string(parentRef.Name) != tc.expectedGateways[0] {
+					t.Errorf("expected HTTPRoute %s to be attached to Gateway %s, got %s", key, tc.expectedGateways[0], parentRef.Name)
+				}
+			}
+		})
+	}
+}
+
+func Test_tlsPassthroughToIR(t *testing.T) {
+	ingress := testIngress("vault", map[string]string{"ingress.cilium.io/tls-passthrough": "enabled"})
+	if !isTLSPassthrough(*ingress) {
+		t.Fatalf("expected ingress to pass TLS through")
+	}
+	ingress.Spec.Rules = append(ingress.Spec.Rules, networkingv1.IngressRule{IngressRuleValue: ingress.Spec.Rules[0].IngressRuleValue})
+
+	ir, errs := tlsPassthroughToIR(*ingress, "cilium")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	gatewayContext := ir.Gateways[types.NamespacedName{Namespace: "default", Name: "cilium"}]
+	if len(gatewayContext.Spec.Listeners) != 1 || gatewayContext.Spec.Listeners[0].Name != "vault-example-com-tls" {
+		t.Errorf("expected a single TLS listener, got %v", gatewayContext.Spec.Listeners)
+	}
+	// The rule without host can't be routed by SNI.
+	if len(ir.TLSRoutes) != 1 {
+		t.Errorf("expected a single TLSRoute, got %v", ir.TLSRoutes)
+	}
+}
@@ -32,7 +32,7 @@ import (
 func forceHTTPSFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
 	var errs field.ErrorList
 	forceHTTPSAnnotation := ciliumAnnotation("force-https")
-	ruleGroups := common.GetRuleGroups(ingresses)
+	ruleGroups := common.GetRuleGroups(ingresses, ir)
 	for _, rg := range ruleGroups {
 
 		for _, rule := range rg.Rules {
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cilium
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// DedicatedLoadBalancerMode exposes an Ingress with its own load balancer.
+	DedicatedLoadBalancerMode = "dedicated"
+	// SharedLoadBalancerMode exposes the Ingresses with a load balancer shared
+	// with the other Ingresses of this mode.
+	SharedLoadBalancerMode = "shared"
+)
+
+// loadBalancerMode returns the load balancer mode of the Ingress, set by the
+// ingress.cilium.io/loadbalancer-mode annotation, or the default mode of the
+// Cilium ingress controller.
+func loadBalancerMode(ingress networkingv1.Ingress, defaultMode string) (string, *field.Error) {
+	annotation := ciliumAnnotation("loadbalancer-mode")
+	mode, ok := ingress.Annotations[annotation]
+	if !ok {
+		return defaultMode, nil
+	}
+	if mode != DedicatedLoadBalancerMode && mode != SharedLoadBalancerMode {
+		return "", field.NotSupported(field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations").Key(annotation), mode, []string{DedicatedLoadBalancerMode, SharedLoadBalancerMode})
+	}
+	return mode, nil
+}
+
+// dedicatedGatewayName returns the name of the Gateway of an Ingress with a
+// dedicated load balancer, as Cilium provisions a load balancer per Gateway.
+func dedicatedGatewayName(ingress networkingv1.Ingress) string {
+	return fmt.Sprintf("%s-%s", common.GetIngressClass(ingress), ingress.Name)
+}
+
+// dedicateGateway renames the Gateway of the IR generated from a single
+// Ingress with a dedicated load balancer, and the parentRefs of its routes.
+func dedicateGateway(ir *intermediate.IR, ingress networkingv1.Ingress) {
+	gatewayKey := types.NamespacedName{Namespace: ingress.Namespace, Name: common.GetIngressClass(ingress)}
+	gatewayContext, ok := ir.Gateways[gatewayKey]
+	if !ok {
+		return
+	}
+	dedicatedKey := types.NamespacedName{Namespace: ingress.Namespace, Name: dedicatedGatewayName(ingress)}
+	gatewayContext.Name = dedicatedKey.Name
+	delete(ir.Gateways, gatewayKey)
+	ir.Gateways[dedicatedKey] = gatewayContext
+
+	for key, httpRouteContext := range ir.HTTPRoutes {
+		for i, parentRef := range httpRouteContext.Spec.ParentRefs {
+			if string(parentRef.Name) == gatewayKey.Name && (parentRef.Namespace == nil || string(*parentRef.Namespace) == gatewayKey.Namespace) {
+				httpRouteContext.Spec.ParentRefs[i].Name = gatewayv1.ObjectName(dedicatedKey.Name)
+			}
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+	notify(notifications.InfoNotification, fmt.Sprintf("ingress %s/%s has a dedicated load balancer, generated Gateway %s for it", ingress.Namespace, ingress.Name, dedicatedKey), &ingress)
+}
@@ -21,7 +21,6 @@ import (
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
-	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 // resourceReader implements the i2gw.CustomResourceReader interface.
@@ -40,7 +39,7 @@ func (r *resourceReader) readResourcesFromCluster(ctx context.Context) (*storage
 	// read cilium related resources from cluster.
 	storage := newResourcesStorage()
 
-	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, sets.New(CiliumIngressClass))
+	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, r.conf.IngressClaimer.ClaimFunc(Name))
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +51,7 @@ func (r *resourceReader) readResourcesFromFile(filename string) (*storage, error
 	// read cilium related resources from file.
 	storage := newResourcesStorage()
 
-	ingresses, err := common.ReadIngressesFromFile(filename, r.conf.Namespace, sets.New[string](CiliumIngressClass))
+	ingresses, err := common.ReadIngressesFromFile(filename, r.conf.Namespace, r.conf.IngressClaimer.ClaimFunc(Name))
 	if err != nil {
 		return nil, err
 	}
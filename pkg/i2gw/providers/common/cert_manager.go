@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// CertManagerAnnotationPrefix is the prefix of the annotations configuring
+	// the certificates cert-manager issues for the Ingresses and Gateways,
+	// e.g. cert-manager.io/cluster-issuer.
+	CertManagerAnnotationPrefix = "cert-manager.io/"
+	// TLSACMEAnnotation requests cert-manager to issue the certificates of the
+	// Ingress with its default issuer.
+	TLSACMEAnnotation = "kubernetes.io/tls-acme"
+
+	// acmeAnnotationPrefix is the prefix of the annotations configuring the
+	// HTTP-01 solver Ingresses of cert-manager, which don't apply to Gateways.
+	acmeAnnotationPrefix = "acme.cert-manager.io/"
+
+	certManagerIssuerAnnotation        = CertManagerAnnotationPrefix + "issuer"
+	certManagerClusterIssuerAnnotation = CertManagerAnnotationPrefix + "cluster-issuer"
+)
+
+// applyCertManagerAnnotations copies the cert-manager annotations of the
+// Ingresses with TLS to the Gateways generated for them, so that cert-manager
+// keeps issuing the certificates of their listeners after the migration. The
+// annotations of the Ingresses sharing a Gateway are merged, the first Ingress
+// winning conflicts.
+func applyCertManagerAnnotations(ingresses []networkingv1.Ingress, gateways map[types.NamespacedName]intermediate.GatewayContext) {
+	sorted := slices.Clone(ingresses)
+	slices.SortFunc(sorted, func(a, b networkingv1.Ingress) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+
+	annotatedGateways := map[types.NamespacedName]bool{}
+	for _, ingress := range sorted {
+		if len(ingress.Spec.TLS) == 0 {
+			continue
+		}
+		annotations := certManagerAnnotations(ingress)
+		tlsACME := ingress.Annotations[TLSACMEAnnotation] == "true"
+		if len(annotations) == 0 && !tlsACME {
+			continue
+		}
+		gatewayKey := types.NamespacedName{Namespace: ingress.Namespace, Name: GetIngressClass(ingress)}
+		gatewayContext, ok := gateways[gatewayKey]
+		if !ok {
+			continue
+		}
+
+		if tlsACME && annotations[certManagerIssuerAnnotation] == "" && annotations[certManagerClusterIssuerAnnotation] == "" {
+			notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s requests its certificates from the default issuer of cert-manager with the %q annotation, which cert-manager doesn't support for Gateways: set the %q or %q annotation of Gateway %s",
+				ingress.Namespace, ingress.Name, TLSACMEAnnotation, certManagerIssuerAnnotation, certManagerClusterIssuerAnnotation, gatewayKey), &ingress)
+		}
+		for _, annotation := range sortedKeys(ingress.Annotations) {
+			if strings.HasPrefix(annotation, acmeAnnotationPrefix) {
+				notify(notifications.WarningNotification, fmt.Sprintf("the %q annotation of ingress %s/%s configures the HTTP-01 solver Ingresses of cert-manager, and was not converted: HTTP-01 challenges of Gateways are solved by the gatewayHTTPRoute solvers of the issuer",
+					annotation, ingress.Namespace, ingress.Name), &ingress)
+			}
+		}
+		if len(annotations) == 0 {
+			continue
+		}
+
+		copyIngressAnnotations(&ingress, annotations, &gatewayContext.Gateway, "Gateway")
+		gateways[gatewayKey] = gatewayContext
+
+		if !annotatedGateways[gatewayKey] {
+			annotatedGateways[gatewayKey] = true
+			notify(notifications.InfoNotification, fmt.Sprintf("copied the cert-manager annotations of ingress %s/%s to Gateway %s: cert-manager issues the certificates of its HTTPS listeners when its Gateway API support is enabled", ingress.Namespace, ingress.Name, gatewayKey), &gatewayContext.Gateway)
+			for _, listener := range gatewayContext.Spec.Listeners {
+				if listener.TLS != nil && listener.Hostname == nil {
+					notify(notifications.WarningNotification, fmt.Sprintf("listener %s of Gateway %s has no hostname, cert-manager doesn't issue its certificates", listener.Name, gatewayKey), &gatewayContext.Gateway)
+				}
+			}
+		}
+	}
+}
+
+// copyIngressAnnotations copies the given annotations of the Ingress to the
+// object of the given kind, keeping the values the object already has.
+func copyIngressAnnotations(ingress client.Object, annotations map[string]string, object client.Object, kind string) {
+	objectAnnotations := object.GetAnnotations()
+	if objectAnnotations == nil {
+		objectAnnotations = map[string]string{}
+	}
+	for _, annotation := range sortedKeys(annotations) {
+		value := annotations[annotation]
+		if existing, ok := objectAnnotations[annotation]; ok {
+			if existing != value {
+				notify(notifications.WarningNotification, fmt.Sprintf("the %q annotation of ingress %s/%s was not copied to %s %s/%s, which already has the value %q of another Ingress",
+					annotation, ingress.GetNamespace(), ingress.GetName(), kind, object.GetNamespace(), object.GetName(), existing), ingress)
+			}
+			continue
+		}
+		objectAnnotations[annotation] = value
+	}
+	object.SetAnnotations(objectAnnotations)
+}
+
+// certManagerAnnotations returns the cert-manager.io annotations of the
+// Ingress.
+func certManagerAnnotations(ingress networkingv1.Ingress) map[string]string {
+	annotations := map[string]string{}
+	for annotation, value := range ingress.Annotations {
+		if strings.HasPrefix(annotation, CertManagerAnnotationPrefix) {
+			annotations[annotation] = value
+		}
+	}
+	return annotations
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func certManagerTestIngress(name, host string, annotations map[string]string, tls bool) networkingv1.Ingress {
+	ingress := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To("nginx"),
+			Rules: []networkingv1.IngressRule{{
+				Host: host,
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: ptr.To(networkingv1.PathTypePrefix),
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: name,
+							Port: networkingv1.ServiceBackendPort{Number: 80},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+	if tls {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{host}, SecretName: name + "-tls"}}
+	}
+	return ingress
+}
+
+func TestApplyCertManagerAnnotations(t *testing.T) {
+	testCases := []struct {
+		name                string
+		ingresses           []networkingv1.Ingress
+		expectedAnnotations map[string]string
+	}{
+		{
+			name: "annotations copied to the Gateway",
+			ingresses: []networkingv1.Ingress{
+				certManagerTestIngress("foo", "foo.example.com", map[string]string{
+					"cert-manager.io/cluster-issuer": "letsencrypt",
+					"cert-manager.io/duration":       "2160h",
+					"kubernetes.io/tls-acme":         "true",
+				}, true),
+			},
+			expectedAnnotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+				"cert-manager.io/duration":       "2160h",
+			},
+		},
+		{
+			name: "first Ingress wins conflicts",
+			ingresses: []networkingv1.Ingress{
+				certManagerTestIngress("foo", "foo.example.com", map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"}, true),
+				certManagerTestIngress("bar", "bar.example.com", map[string]string{
+					"cert-manager.io/cluster-issuer": "letsencrypt-staging",
+					"cert-manager.io/common-name":    "bar.example.com",
+				}, true),
+			},
+			expectedAnnotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt-staging",
+				"cert-manager.io/common-name":    "bar.example.com",
+			},
+		},
+		{
+			name: "Ingress without TLS ignored",
+			ingresses: []networkingv1.Ingress{
+				certManagerTestIngress("foo", "foo.example.com", map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"}, false),
+			},
+		},
+		{
+			name: "tls-acme without issuer not copied",
+			ingresses: []networkingv1.Ingress{
+				certManagerTestIngress("foo", "foo.example.com", map[string]string{
+					"kubernetes.io/tls-acme":                    "true",
+					"acme.cert-manager.io/http01-edit-in-place": "true",
+				}, true),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir, errs := ToIR(tc.ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			require.Empty(t, errs)
+			gateway, ok := ir.Gateways[types.NamespacedName{Namespace: "default", Name: "nginx"}]
+			require.True(t, ok)
+			require.Equal(t, tc.expectedAnnotations, gateway.Annotations)
+		})
+	}
+}
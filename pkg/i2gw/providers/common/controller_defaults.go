@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// ControllerDefault is a behavior an Ingress controller applies by default,
+// i.e. to the Ingresses not configuring it, which the Gateway API
+// implementation the Ingresses are migrated to may not apply.
+type ControllerDefault struct {
+	// Annotation is the annotation configuring the behavior.
+	Annotation string
+	// Behavior describes the default behavior, e.g. "rejects request bodies
+	// larger than 1m".
+	Behavior string
+	// AppliesTo returns whether the default behavior applies to the Ingress,
+	// e.g. only to the Ingresses with TLS. If nil, it applies to all the
+	// Ingresses.
+	AppliesTo func(networkingv1.Ingress) bool
+}
+
+// ReliedUponControllerDefaults returns the defaults applying to the Ingress
+// whose annotation it does not set.
+func ReliedUponControllerDefaults(ingress networkingv1.Ingress, defaults []ControllerDefault) []ControllerDefault {
+	var reliedUpon []ControllerDefault
+	for _, controllerDefault := range defaults {
+		if _, ok := ingress.Annotations[controllerDefault.Annotation]; ok {
+			continue
+		}
+		if controllerDefault.AppliesTo != nil && !controllerDefault.AppliesTo(ingress) {
+			continue
+		}
+		reliedUpon = append(reliedUpon, controllerDefault)
+	}
+	return reliedUpon
+}
+
+// ControllerDefaultsMessage returns the message warning that the Ingress
+// relies on the given defaults of the controller, or an empty message if
+// there are none.
+func ControllerDefaultsMessage(controller string, ingress networkingv1.Ingress, defaults []ControllerDefault) string {
+	if len(defaults) == 0 {
+		return ""
+	}
+	behaviors := make([]string, 0, len(defaults))
+	for _, controllerDefault := range defaults {
+		behaviors = append(behaviors, fmt.Sprintf("%s (%s)", controllerDefault.Behavior, controllerDefault.Annotation))
+	}
+	return fmt.Sprintf("ingress %s/%s relies on the defaults of %s, which the Gateway API implementation may not apply: by default, %s %s; verify the behavior after the migration, or set the annotations explicitly",
+		ingress.Namespace, ingress.Name, controller, controller, strings.Join(behaviors, ", "))
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReliedUponControllerDefaults(t *testing.T) {
+	defaults := []ControllerDefault{
+		{Annotation: "example.com/redirect", Behavior: "redirects to HTTPS", AppliesTo: func(ingress networkingv1.Ingress) bool { return len(ingress.Spec.TLS) > 0 }},
+		{Annotation: "example.com/timeout", Behavior: "times out after 60s"},
+	}
+
+	ingress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "example"}}
+	reliedUpon := ReliedUponControllerDefaults(ingress, defaults)
+	require.Equal(t, []string{"example.com/timeout"}, annotations(reliedUpon))
+	require.Equal(t, "ingress default/example relies on the defaults of example, which the Gateway API implementation may not apply: by default, example times out after 60s (example.com/timeout); verify the behavior after the migration, or set the annotations explicitly",
+		ControllerDefaultsMessage("example", ingress, reliedUpon))
+
+	ingress.Spec.TLS = []networkingv1.IngressTLS{{SecretName: "example-cert"}}
+	require.Equal(t, []string{"example.com/redirect", "example.com/timeout"}, annotations(ReliedUponControllerDefaults(ingress, defaults)))
+
+	ingress.Annotations = map[string]string{"example.com/redirect": "false", "example.com/timeout": "60"}
+	reliedUpon = ReliedUponControllerDefaults(ingress, defaults)
+	require.Empty(t, reliedUpon)
+	require.Empty(t, ControllerDefaultsMessage("example", ingress, reliedUpon))
+}
+
+func annotations(defaults []ControllerDefault) []string {
+	var annotations []string
+	for _, controllerDefault := range defaults {
+		annotations = append(annotations, controllerDefault.Annotation)
+	}
+	return annotations
+}
@@ -24,18 +24,23 @@ import (
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // ToIR converts the received ingresses to intermediate.IR without taking into
 // consideration any provider specific logic.
 func ToIR(ingresses []networkingv1.Ingress, options i2gw.ProviderImplementationSpecificOptions) (intermediate.IR, field.ErrorList) {
-	aggregator := ingressAggregator{ruleGroups: map[ruleGroupKey]*ingressRuleGroup{}}
+	aggregator := ingressAggregator{
+		ruleGroups:          map[ruleGroupKey]*ingressRuleGroup{},
+		disableRouteMerging: options.DisableRouteMerging,
+	}
 
 	var errs field.ErrorList
 	for _, ingress := range ingresses {
@@ -50,10 +55,11 @@ func ToIR(ingresses []networkingv1.Ingress, options i2gw.ProviderImplementationS
 		return intermediate.IR{}, errs
 	}
 
+	sourcesByRouteKey := aggregator.sourcesByRouteKey()
 	routeByKey := make(map[types.NamespacedName]intermediate.HTTPRouteContext)
 	for _, route := range routes {
 		key := types.NamespacedName{Namespace: route.Namespace, Name: route.Name}
-		routeByKey[key] = intermediate.HTTPRouteContext{HTTPRoute: route}
+		routeByKey[key] = intermediate.HTTPRouteContext{HTTPRoute: route, Sources: sourcesByRouteKey[key]}
 	}
 
 	gatewayByKey := make(map[types.NamespacedName]intermediate.GatewayContext)
@@ -61,6 +67,8 @@ func ToIR(ingresses []networkingv1.Ingress, options i2gw.ProviderImplementationS
 		key := types.NamespacedName{Namespace: gateway.Namespace, Name: gateway.Name}
 		gatewayByKey[key] = intermediate.GatewayContext{Gateway: gateway}
 	}
+	applyCertManagerAnnotations(ingresses, gatewayByKey)
+	applyExternalDNSAnnotations(ingresses, gatewayByKey, routeByKey)
 
 	return intermediate.IR{
 		Gateways:   gatewayByKey,
@@ -81,6 +89,12 @@ var (
 		Kind:    "HTTPRoute",
 	}
 
+	GRPCRouteGVK = schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1",
+		Kind:    "GRPCRoute",
+	}
+
 	TLSRouteGVK = schema.GroupVersionKind{
 		Group:   "gateway.networking.k8s.io",
 		Version: "v1alpha2",
@@ -93,11 +107,29 @@ var (
 		Kind:    "TCPRoute",
 	}
 
+	UDPRouteGVK = schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1alpha2",
+		Kind:    "UDPRoute",
+	}
+
 	ReferenceGrantGVK = schema.GroupVersionKind{
 		Group:   "gateway.networking.k8s.io",
 		Version: "v1beta1",
 		Kind:    "ReferenceGrant",
 	}
+
+	BackendTLSPolicyGVK = schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1alpha3",
+		Kind:    "BackendTLSPolicy",
+	}
+
+	BackendLBPolicyGVK = schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1alpha2",
+		Kind:    "BackendLBPolicy",
+	}
 )
 
 type ruleGroupKey string
@@ -105,6 +137,11 @@ type ruleGroupKey string
 type ingressAggregator struct {
 	ruleGroups      map[ruleGroupKey]*ingressRuleGroup
 	defaultBackends []ingressDefaultBackend
+
+	// disableRouteMerging indicates whether the rules of each Ingress are
+	// grouped on their own, instead of with the rules of the other Ingresses
+	// of the same namespace, class and host.
+	disableRouteMerging bool
 }
 
 type pathMatchKey string
@@ -119,10 +156,14 @@ type ingressRuleGroup struct {
 }
 
 type ingressRule struct {
-	rule networkingv1.IngressRule
+	// ingress is the Ingress the rule belongs to, used to detect duplicate
+	// paths within a single Ingress.
+	ingress *networkingv1.Ingress
+	rule    networkingv1.IngressRule
 }
 
 type ingressDefaultBackend struct {
+	ingress      *networkingv1.Ingress
 	name         string
 	namespace    string
 	ingressClass string
@@ -139,10 +180,11 @@ type ingressPath struct {
 func (a *ingressAggregator) addIngress(ingress networkingv1.Ingress) {
 	ingressClass := GetIngressClass(ingress)
 	for _, rule := range ingress.Spec.Rules {
-		a.addIngressRule(ingress.Namespace, ingress.Name, ingressClass, rule, ingress.Spec)
+		a.addIngressRule(&ingress, ingressClass, rule)
 	}
 	if ingress.Spec.DefaultBackend != nil {
 		a.defaultBackends = append(a.defaultBackends, ingressDefaultBackend{
+			ingress:      &ingress,
 			name:         ingress.Name,
 			namespace:    ingress.Namespace,
 			ingressClass: ingressClass,
@@ -151,22 +193,50 @@ func (a *ingressAggregator) addIngress(ingress networkingv1.Ingress) {
 	}
 }
 
-func (a *ingressAggregator) addIngressRule(namespace, name, ingressClass string, rule networkingv1.IngressRule, iSpec networkingv1.IngressSpec) {
-	rgKey := ruleGroupKey(fmt.Sprintf("%s/%s/%s", namespace, ingressClass, rule.Host))
+func (a *ingressAggregator) addIngressRule(ingress *networkingv1.Ingress, ingressClass string, rule networkingv1.IngressRule) {
+	namespace, name, iSpec := ingress.Namespace, ingress.Name, ingress.Spec
+	if rule.Host == WildcardHost {
+		notify(notifications.WarningNotification, fmt.Sprintf("host \"%s\" of ingress %s/%s is not a valid Gateway API hostname, the generated resources match all hosts instead", rule.Host, namespace, name), ingress)
+	}
+	host := NormalizeHost(rule.Host)
+	rgKey := ruleGroupKey(fmt.Sprintf("%s/%s/%s", namespace, ingressClass, host))
+	if a.disableRouteMerging {
+		rgKey = ruleGroupKey(fmt.Sprintf("%s/%s", rgKey, name))
+	}
 	rg, ok := a.ruleGroups[rgKey]
 	if !ok {
 		rg = &ingressRuleGroup{
 			namespace:    namespace,
 			name:         name,
 			ingressClass: ingressClass,
-			host:         rule.Host,
+			host:         host,
 		}
 		a.ruleGroups[rgKey] = rg
 	}
 	if len(iSpec.TLS) > 0 {
 		rg.tls = append(rg.tls, iSpec.TLS...)
 	}
-	rg.rules = append(rg.rules, ingressRule{rule: rule})
+	rg.rules = append(rg.rules, ingressRule{ingress: ingress, rule: rule})
+}
+
+// sourcesByRouteKey returns the Ingresses each HTTPRoute is generated from.
+func (a *ingressAggregator) sourcesByRouteKey() map[types.NamespacedName][]client.Object {
+	sourcesByRouteKey := map[types.NamespacedName][]client.Object{}
+	for _, rg := range a.ruleGroups {
+		key := types.NamespacedName{Namespace: rg.namespace, Name: RouteName(rg.name, rg.host)}
+		for _, rule := range rg.rules {
+			if rule.ingress != nil && !slices.Contains(sourcesByRouteKey[key], client.Object(rule.ingress)) {
+				sourcesByRouteKey[key] = append(sourcesByRouteKey[key], rule.ingress)
+			}
+		}
+	}
+	for _, db := range a.defaultBackends {
+		key := types.NamespacedName{Namespace: db.namespace, Name: fmt.Sprintf("%s-default-backend", db.name)}
+		if db.ingress != nil {
+			sourcesByRouteKey[key] = append(sourcesByRouteKey[key], db.ingress)
+		}
+	}
+	return sourcesByRouteKey
 }
 
 func (a *ingressAggregator) toHTTPRoutesAndGateways(options i2gw.ProviderImplementationSpecificOptions) ([]gatewayv1.HTTPRoute, []gatewayv1.Gateway, field.ErrorList) {
@@ -189,7 +259,7 @@ func (a *ingressAggregator) toHTTPRoutesAndGateways(options i2gw.ProviderImpleme
 		listener := gatewayv1.Listener{}
 		if rg.host != "" {
 			listener.Hostname = (*gatewayv1.Hostname)(&rg.host)
-		} else if len(rg.tls) == 1 && len(rg.tls[0].Hosts) == 1 {
+		} else if len(rg.tls) == 1 && len(rg.tls[0].Hosts) == 1 && !MatchesAllHosts(rg.tls[0].Hosts[0]) {
 			listener.Hostname = (*gatewayv1.Hostname)(&rg.tls[0].Hosts[0])
 		}
 		if len(rg.tls) > 0 {
@@ -207,6 +277,23 @@ func (a *ingressAggregator) toHTTPRoutesAndGateways(options i2gw.ProviderImpleme
 	}
 
 	for i, db := range a.defaultBackends {
+		// The default backend of an Ingress without rules catches the requests
+		// of any host: it gets a listener without hostname, holding the
+		// certificates of the Ingress. Otherwise, the route attaches to the
+		// listeners of the hosts of the rules.
+		if len(db.ingress.Spec.Rules) == 0 {
+			listener := gatewayv1.Listener{}
+			for _, tls := range db.ingress.Spec.TLS {
+				if listener.TLS == nil {
+					listener.TLS = &gatewayv1.GatewayTLSConfig{}
+				}
+				listener.TLS.CertificateRefs = append(listener.TLS.CertificateRefs,
+					gatewayv1.SecretObjectReference{Name: gatewayv1.ObjectName(tls.SecretName)})
+			}
+			gwKey := fmt.Sprintf("%s/%s", db.namespace, db.ingressClass)
+			listenersByNamespacedGateway[gwKey] = append(listenersByNamespacedGateway[gwKey], listener)
+		}
+
 		httpRoute := gatewayv1.HTTPRoute{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      fmt.Sprintf("%s-default-backend", db.name),
@@ -260,7 +347,7 @@ func (a *ingressAggregator) toHTTPRoutesAndGateways(options i2gw.ProviderImpleme
 			gateway.SetGroupVersionKind(GatewayGVK)
 			gatewaysByKey[gwKey] = gateway
 		}
-		for _, listener := range listeners {
+		for _, listener := range mergeListenersByHostname(listeners) {
 			var listenerNamePrefix string
 			if listener.Hostname != nil && *listener.Hostname != "" {
 				listenerNamePrefix = fmt.Sprintf("%s-", NameFromHost(string(*listener.Hostname)))
@@ -292,6 +379,38 @@ func (a *ingressAggregator) toHTTPRoutesAndGateways(options i2gw.ProviderImpleme
 	return httpRoutes, gateways, errors
 }
 
+// mergeListenersByHostname merges the listeners of the rule groups sharing a
+// hostname, which happens when route merging is disabled, so that the Gateway
+// gets a single listener per hostname, holding the certificates of all of them.
+func mergeListenersByHostname(listeners []gatewayv1.Listener) []gatewayv1.Listener {
+	var merged []gatewayv1.Listener
+	indexByHostname := map[gatewayv1.Hostname]int{}
+	for _, listener := range listeners {
+		var hostname gatewayv1.Hostname
+		if listener.Hostname != nil {
+			hostname = *listener.Hostname
+		}
+		i, ok := indexByHostname[hostname]
+		if !ok {
+			indexByHostname[hostname] = len(merged)
+			merged = append(merged, listener)
+			continue
+		}
+		if listener.TLS == nil {
+			continue
+		}
+		if merged[i].TLS == nil {
+			merged[i].TLS = &gatewayv1.GatewayTLSConfig{}
+		}
+		for _, ref := range listener.TLS.CertificateRefs {
+			if !slices.Contains(merged[i].TLS.CertificateRefs, ref) {
+				merged[i].TLS.CertificateRefs = append(merged[i].TLS.CertificateRefs, ref)
+			}
+		}
+	}
+	return merged
+}
+
 func (rg *ingressRuleGroup) toHTTPRoute(options i2gw.ProviderImplementationSpecificOptions) (gatewayv1.HTTPRoute, field.ErrorList) {
 	ingressPathsByMatchKey := groupIngressPathsByMatchKey(rg.rules)
 	httpRoute := gatewayv1.HTTPRoute{
@@ -331,6 +450,12 @@ func (rg *ingressRuleGroup) toHTTPRoute(options i2gw.ProviderImplementationSpeci
 
 		backendRefs, errs := rg.configureBackendRef(paths)
 		errors = append(errors, errs...)
+		if _, ok := ingressPathsByMatchKey.duplicates[key]; ok && len(backendRefs) > 1 {
+			// Duplicate paths of a single Ingress share the traffic equally.
+			for i := range backendRefs {
+				backendRefs[i].Weight = PtrTo(int32(1))
+			}
+		}
 		hrRule.BackendRefs = backendRefs
 
 		httpRoute.Spec.Rules = append(httpRoute.Spec.Rules, hrRule)
@@ -220,6 +220,73 @@ func Test_ToIR(t *testing.T) {
 			},
 			expectedErrors: field.ErrorList{},
 		},
+		{
+			name: "ingress with default backend only",
+			ingresses: []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{Name: "catch-all", Namespace: "default"},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: PtrTo("example-proxy"),
+					TLS:              []networkingv1.IngressTLS{{SecretName: "catch-all-cert"}},
+					DefaultBackend: &networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: "default",
+							Port: networkingv1.ServiceBackendPort{
+								Number: 8080,
+							},
+						},
+					},
+				},
+			}},
+			expectedIR: intermediate.IR{
+				Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+					{Namespace: "default", Name: "example-proxy"}: {
+						Gateway: gatewayv1.Gateway{
+							ObjectMeta: metav1.ObjectMeta{Name: "example-proxy", Namespace: "default"},
+							Spec: gatewayv1.GatewaySpec{
+								GatewayClassName: "example-proxy",
+								Listeners: []gatewayv1.Listener{{
+									Name:     "http",
+									Port:     80,
+									Protocol: gatewayv1.HTTPProtocolType,
+								}, {
+									Name:     "https",
+									Port:     443,
+									Protocol: gatewayv1.HTTPSProtocolType,
+									TLS: &gatewayv1.GatewayTLSConfig{
+										CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "catch-all-cert"}},
+									},
+								}},
+							},
+						},
+					},
+				},
+				HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+					{Namespace: "default", Name: "catch-all-default-backend"}: {
+						HTTPRoute: gatewayv1.HTTPRoute{
+							ObjectMeta: metav1.ObjectMeta{Name: "catch-all-default-backend", Namespace: "default"},
+							Spec: gatewayv1.HTTPRouteSpec{
+								CommonRouteSpec: gatewayv1.CommonRouteSpec{
+									ParentRefs: []gatewayv1.ParentReference{{
+										Name: "example-proxy",
+									}},
+								},
+								Rules: []gatewayv1.HTTPRouteRule{{
+									BackendRefs: []gatewayv1.HTTPBackendRef{{
+										BackendRef: gatewayv1.BackendRef{
+											BackendObjectReference: gatewayv1.BackendObjectReference{
+												Name: "default",
+												Port: PtrTo(gatewayv1.PortNumber(8080)),
+											},
+										}},
+									}},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErrors: field.ErrorList{},
+		},
 		{
 			name: "ingress with custom and default backend",
 			ingresses: []networkingv1.Ingress{{
@@ -430,6 +497,104 @@ func Test_ToIR(t *testing.T) {
 			},
 			expectedErrors: field.ErrorList{},
 		},
+		{
+			name: "duplicated paths within a single ingress",
+			ingresses: []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{Name: "duplicate", Namespace: "test"},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: PtrTo("example-proxy"),
+					Rules: []networkingv1.IngressRule{{
+						Host: "example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     "/foo",
+									PathType: &iPrefix,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "example-a",
+											Port: networkingv1.ServiceBackendPort{
+												Number: 3000,
+											},
+										},
+									},
+								}, {
+									Path:     "/foo",
+									PathType: &iPrefix,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "example-b",
+											Port: networkingv1.ServiceBackendPort{
+												Number: 3000,
+											},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}},
+			expectedIR: intermediate.IR{
+				Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+					{Namespace: "test", Name: "example-proxy"}: {
+						Gateway: gatewayv1.Gateway{
+							ObjectMeta: metav1.ObjectMeta{Name: "example-proxy", Namespace: "test"},
+							Spec: gatewayv1.GatewaySpec{
+								GatewayClassName: "example-proxy",
+								Listeners: []gatewayv1.Listener{{
+									Name:     "example-com-http",
+									Port:     80,
+									Protocol: gatewayv1.HTTPProtocolType,
+									Hostname: PtrTo(gatewayv1.Hostname("example.com")),
+								}},
+							},
+						},
+					},
+				},
+				HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+					{Namespace: "test", Name: "duplicate-example-com"}: {
+						HTTPRoute: gatewayv1.HTTPRoute{
+							ObjectMeta: metav1.ObjectMeta{Name: "duplicate-example-com", Namespace: "test"},
+							Spec: gatewayv1.HTTPRouteSpec{
+								CommonRouteSpec: gatewayv1.CommonRouteSpec{
+									ParentRefs: []gatewayv1.ParentReference{{
+										Name: "example-proxy",
+									}},
+								},
+								Hostnames: []gatewayv1.Hostname{"example.com"},
+								Rules: []gatewayv1.HTTPRouteRule{{
+									Matches: []gatewayv1.HTTPRouteMatch{{
+										Path: &gatewayv1.HTTPPathMatch{
+											Type:  &gPathPrefix,
+											Value: PtrTo("/foo"),
+										},
+									}},
+									BackendRefs: []gatewayv1.HTTPBackendRef{{
+										BackendRef: gatewayv1.BackendRef{
+											BackendObjectReference: gatewayv1.BackendObjectReference{
+												Name: "example-a",
+												Port: PtrTo(gatewayv1.PortNumber(3000)),
+											},
+											Weight: PtrTo(int32(1)),
+										},
+									}, {
+										BackendRef: gatewayv1.BackendRef{
+											BackendObjectReference: gatewayv1.BackendObjectReference{
+												Name: "example-b",
+												Port: PtrTo(gatewayv1.PortNumber(3000)),
+											},
+											Weight: PtrTo(int32(1)),
+										},
+									}},
+								}},
+							},
+						},
+					},
+				},
+			},
+			expectedErrors: field.ErrorList{},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -477,3 +642,165 @@ func Test_ToIR(t *testing.T) {
 		})
 	}
 }
+
+func Test_ToIR_wildcardHosts(t *testing.T) {
+	iPrefix := networkingv1.PathTypePrefix
+	rule := func(host string) networkingv1.IngressRule {
+		return networkingv1.IngressRule{
+			Host: host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: &iPrefix,
+						Backend: networkingv1.IngressBackend{
+							Service: &networkingv1.IngressServiceBackend{Name: "app", Port: networkingv1.ServiceBackendPort{Number: 80}},
+						},
+					}},
+				},
+			},
+		}
+	}
+
+	ingresses := []networkingv1.Ingress{{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "test"},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: PtrTo("nginx"),
+			Rules:            []networkingv1.IngressRule{rule("example.com"), rule("*.example.com"), rule("*")},
+		},
+	}}
+
+	ir, errs := ToIR(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	gateway := ir.Gateways[types.NamespacedName{Namespace: "test", Name: "nginx"}]
+	var listeners []string
+	for _, listener := range gateway.Spec.Listeners {
+		hostname := "<none>"
+		if listener.Hostname != nil {
+			hostname = string(*listener.Hostname)
+		}
+		listeners = append(listeners, string(listener.Name)+"="+hostname)
+	}
+	wantListeners := []string{"http=<none>", "wildcard-example-com-http=*.example.com", "example-com-http=example.com"}
+	if diff := cmp.Diff(wantListeners, listeners); diff != "" {
+		t.Errorf("Unexpected listeners (-want +got): %s", diff)
+	}
+
+	wantHostnames := map[string][]gatewayv1.Hostname{
+		"app-example-com":          {"example.com"},
+		"app-wildcard-example-com": {"*.example.com"},
+		"app-all-hosts":            nil,
+	}
+	for name, hostnames := range wantHostnames {
+		httpRoute, ok := ir.HTTPRoutes[types.NamespacedName{Namespace: "test", Name: name}]
+		if !ok {
+			t.Fatalf("Expected HTTPRoute %s to be generated", name)
+		}
+		if diff := cmp.Diff(hostnames, httpRoute.Spec.Hostnames); diff != "" {
+			t.Errorf("Unexpected hostnames of HTTPRoute %s (-want +got): %s", name, diff)
+		}
+	}
+}
+
+func Test_ToIR_routeMerging(t *testing.T) {
+	iPrefix := networkingv1.PathTypePrefix
+	ingress := func(name, path, secret string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: PtrTo("nginx"),
+				TLS:              []networkingv1.IngressTLS{{Hosts: []string{"example.com"}, SecretName: secret}},
+				Rules: []networkingv1.IngressRule{{
+					Host: "example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path:     path,
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: name, Port: networkingv1.ServiceBackendPort{Number: 80}},
+								},
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+	ingresses := []networkingv1.Ingress{ingress("team-a", "/a", "team-a-tls"), ingress("team-b", "/b", "team-b-tls")}
+
+	testCases := []struct {
+		name                string
+		disableRouteMerging bool
+		expectedRoutes      map[string][]string
+	}{
+		{
+			name:           "merged",
+			expectedRoutes: map[string][]string{"team-a-example-com": {"team-a", "team-b"}},
+		},
+		{
+			name:                "one route per ingress",
+			disableRouteMerging: true,
+			expectedRoutes: map[string][]string{
+				"team-a-example-com": {"team-a"},
+				"team-b-example-com": {"team-b"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir, errs := ToIR(ingresses, i2gw.ProviderImplementationSpecificOptions{DisableRouteMerging: tc.disableRouteMerging})
+			if len(errs) > 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+
+			gotRoutes := map[string][]string{}
+			for key, httpRouteContext := range ir.HTTPRoutes {
+				for _, source := range httpRouteContext.Sources {
+					gotRoutes[key.Name] = append(gotRoutes[key.Name], source.GetName())
+				}
+			}
+			if diff := cmp.Diff(tc.expectedRoutes, gotRoutes); diff != "" {
+				t.Errorf("Unexpected HTTPRoute sources (-want +got): %s", diff)
+			}
+
+			gotRuleGroups := map[string][]string{}
+			for _, rg := range GetRuleGroups(ingresses, &ir) {
+				name := RouteName(rg.Name, rg.Host)
+				for _, rule := range rg.Rules {
+					gotRuleGroups[name] = append(gotRuleGroups[name], rule.Ingress.Name)
+				}
+			}
+			if diff := cmp.Diff(tc.expectedRoutes, gotRuleGroups); diff != "" {
+				t.Errorf("Unexpected rule groups (-want +got): %s", diff)
+			}
+
+			wantListeners := []gatewayv1.Listener{
+				{
+					Name:     "example-com-http",
+					Hostname: PtrTo(gatewayv1.Hostname("example.com")),
+					Port:     80,
+					Protocol: gatewayv1.HTTPProtocolType,
+				},
+				{
+					Name:     "example-com-https",
+					Hostname: PtrTo(gatewayv1.Hostname("example.com")),
+					Port:     443,
+					Protocol: gatewayv1.HTTPSProtocolType,
+					TLS: &gatewayv1.GatewayTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{
+						{Name: "team-a-tls"},
+						{Name: "team-b-tls"},
+					}},
+				},
+			}
+			gateway := ir.Gateways[types.NamespacedName{Namespace: "test", Name: "nginx"}]
+			if diff := cmp.Diff(wantListeners, gateway.Spec.Listeners); diff != "" {
+				t.Errorf("Unexpected listeners (-want +got): %s", diff)
+			}
+		})
+	}
+}
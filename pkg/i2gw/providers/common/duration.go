@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// maxDurationUnitValue is the largest value a single unit of a Gateway API
+// Duration may have, as the values are limited to 5 digits.
+const maxDurationUnitValue = 99999
+
+// gatewayDurationRegex is the format of the Gateway API Duration, as defined by
+// GEP-2257: up to 4 units among h, m, s and ms, each with a value of up to 5 digits.
+var gatewayDurationRegex = regexp.MustCompile(`^([0-9]{1,5}(h|m|s|ms)){1,4}$`)
+
+// IsValidGatewayDuration returns true if the given string is a valid Gateway API
+// Duration as defined by GEP-2257.
+func IsValidGatewayDuration(duration string) bool {
+	return gatewayDurationRegex.MatchString(duration)
+}
+
+// ToGatewayDuration converts the given duration to a Gateway API Duration, e.g.
+// 1m0s is converted to 1m and 1.5s to 1s500ms. Gateway API Durations have a
+// millisecond precision: the duration is rounded to the nearest millisecond,
+// but never down to 0 as 0 usually disables timeouts. An error is returned if
+// the duration is negative or too large to be represented.
+func ToGatewayDuration(duration time.Duration) (gatewayv1.Duration, error) {
+	if duration < 0 {
+		return "", fmt.Errorf("negative duration %v can't be represented as a Gateway API Duration", duration)
+	}
+	if duration == 0 {
+		return "0s", nil
+	}
+
+	duration = duration.Round(time.Millisecond)
+	if duration == 0 {
+		duration = time.Millisecond
+	}
+
+	hours := duration / time.Hour
+	if hours > maxDurationUnitValue {
+		return "", fmt.Errorf("duration %v exceeds the maximum Gateway API Duration of %dh", duration, maxDurationUnitValue)
+	}
+	duration -= hours * time.Hour
+	minutes := duration / time.Minute
+	duration -= minutes * time.Minute
+	seconds := duration / time.Second
+	duration -= seconds * time.Second
+	milliseconds := duration / time.Millisecond
+
+	var sb strings.Builder
+	for _, unit := range []struct {
+		value  time.Duration
+		suffix string
+	}{{hours, "h"}, {minutes, "m"}, {seconds, "s"}, {milliseconds, "ms"}} {
+		if unit.value > 0 {
+			sb.WriteString(fmt.Sprintf("%d%s", unit.value, unit.suffix))
+		}
+	}
+	return gatewayv1.Duration(sb.String()), nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestToGatewayDuration(t *testing.T) {
+	testCases := []struct {
+		name         string
+		duration     time.Duration
+		wantDuration gatewayv1.Duration
+		wantErr      bool
+	}{
+		{
+			name:         "zero",
+			duration:     0,
+			wantDuration: "0s",
+		},
+		{
+			name:         "whole minute",
+			duration:     time.Minute,
+			wantDuration: "1m",
+		},
+		{
+			name:         "all units",
+			duration:     2*time.Hour + 3*time.Minute + 4*time.Second + 5*time.Millisecond,
+			wantDuration: "2h3m4s5ms",
+		},
+		{
+			name:         "fractional seconds",
+			duration:     1500 * time.Millisecond,
+			wantDuration: "1s500ms",
+		},
+		{
+			name:         "sub-millisecond precision is rounded",
+			duration:     10*time.Millisecond + 600*time.Microsecond,
+			wantDuration: "11ms",
+		},
+		{
+			name:         "sub-millisecond duration is not rounded to zero",
+			duration:     100 * time.Microsecond,
+			wantDuration: "1ms",
+		},
+		{
+			name:     "negative",
+			duration: -time.Second,
+			wantErr:  true,
+		},
+		{
+			name:     "too large",
+			duration: 100000 * time.Hour,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			duration, err := ToGatewayDuration(tc.duration)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantDuration, duration)
+			require.True(t, IsValidGatewayDuration(string(duration)), "%s is not a valid Gateway API Duration", duration)
+		})
+	}
+}
+
+func TestIsValidGatewayDuration(t *testing.T) {
+	for duration, want := range map[string]bool{
+		"1h":        true,
+		"1h30m":     true,
+		"1s500ms":   true,
+		"99999s":    true,
+		"100000s":   false,
+		"1m0.5s":    false,
+		"1us":       false,
+		"":          false,
+		"1h1m1s1ms": true,
+	} {
+		require.Equal(t, want, IsValidGatewayDuration(duration), duration)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// ExternalDNSAnnotationPrefix is the prefix of the annotations configuring
+	// the DNS records external-dns manages for the Ingresses and routes, e.g.
+	// external-dns.alpha.kubernetes.io/hostname.
+	ExternalDNSAnnotationPrefix = "external-dns.alpha.kubernetes.io/"
+
+	// externalDNSTargetAnnotation overrides the targets of the DNS records,
+	// which external-dns reads from the Gateways rather than the routes.
+	externalDNSTargetAnnotation = ExternalDNSAnnotationPrefix + "target"
+	// externalDNSHostnameSourceAnnotation selects the hostnames of the DNS
+	// records of an Ingress, and has no equivalent for the routes.
+	externalDNSHostnameSourceAnnotation = ExternalDNSAnnotationPrefix + "ingress-hostname-source"
+)
+
+// applyExternalDNSAnnotations copies the external-dns annotations of the
+// Ingresses to the resources external-dns reads them from with its Gateway API
+// sources, so that it keeps managing their DNS records after the migration:
+// the target annotation to the Gateways, and the others to the HTTPRoutes
+// generated from the Ingresses. The annotations of the Ingresses sharing a
+// resource are merged, the first Ingress winning conflicts.
+func applyExternalDNSAnnotations(ingresses []networkingv1.Ingress, gateways map[types.NamespacedName]intermediate.GatewayContext, routes map[types.NamespacedName]intermediate.HTTPRouteContext) {
+	sorted := slices.Clone(ingresses)
+	slices.SortFunc(sorted, func(a, b networkingv1.Ingress) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	for _, ingress := range sorted {
+		if value, ok := ingress.Annotations[externalDNSHostnameSourceAnnotation]; ok {
+			notify(notifications.WarningNotification, fmt.Sprintf("the %q annotation of ingress %s/%s was not converted: external-dns creates the DNS records of both the hostnames and the %q annotation of the routes, the value %q has no equivalent",
+				externalDNSHostnameSourceAnnotation, ingress.Namespace, ingress.Name, ExternalDNSAnnotationPrefix+"hostname", value), &ingress)
+		}
+		target, ok := ingress.Annotations[externalDNSTargetAnnotation]
+		if !ok {
+			continue
+		}
+		gatewayKey := types.NamespacedName{Namespace: ingress.Namespace, Name: GetIngressClass(ingress)}
+		gatewayContext, ok := gateways[gatewayKey]
+		if !ok {
+			continue
+		}
+		copyIngressAnnotations(&ingress, map[string]string{externalDNSTargetAnnotation: target}, &gatewayContext.Gateway, "Gateway")
+		gateways[gatewayKey] = gatewayContext
+	}
+
+	routeKeys := make([]types.NamespacedName, 0, len(routes))
+	for key := range routes {
+		routeKeys = append(routeKeys, key)
+	}
+	slices.SortFunc(routeKeys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	for _, routeKey := range routeKeys {
+		httpRouteContext := routes[routeKey]
+		var copied bool
+		for _, source := range httpRouteContext.Sources {
+			annotations := externalDNSRouteAnnotations(source.GetAnnotations())
+			if len(annotations) == 0 {
+				continue
+			}
+			copyIngressAnnotations(source, annotations, &httpRouteContext.HTTPRoute, "HTTPRoute")
+			copied = true
+		}
+		if !copied {
+			continue
+		}
+		routes[routeKey] = httpRouteContext
+		notify(notifications.InfoNotification, fmt.Sprintf("copied the external-dns annotations of the Ingresses of HTTPRoute %s to it: external-dns manages its DNS records with the gateway-httproute source", routeKey), &httpRouteContext.HTTPRoute)
+	}
+}
+
+// externalDNSRouteAnnotations returns the external-dns annotations external-dns
+// reads from the routes.
+func externalDNSRouteAnnotations(ingressAnnotations map[string]string) map[string]string {
+	annotations := map[string]string{}
+	for annotation, value := range ingressAnnotations {
+		if !strings.HasPrefix(annotation, ExternalDNSAnnotationPrefix) || annotation == externalDNSTargetAnnotation || annotation == externalDNSHostnameSourceAnnotation {
+			continue
+		}
+		annotations[annotation] = value
+	}
+	return annotations
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestApplyExternalDNSAnnotations(t *testing.T) {
+	ingresses := []networkingv1.Ingress{
+		certManagerTestIngress("foo", "foo.example.com", map[string]string{
+			"external-dns.alpha.kubernetes.io/hostname":                "www.foo.example.com",
+			"external-dns.alpha.kubernetes.io/ttl":                     "60",
+			"external-dns.alpha.kubernetes.io/target":                  "lb.example.com",
+			"external-dns.alpha.kubernetes.io/ingress-hostname-source": "annotation-only",
+		}, false),
+		certManagerTestIngress("bar", "bar.example.com", map[string]string{
+			"external-dns.alpha.kubernetes.io/target": "other-lb.example.com",
+		}, false),
+		certManagerTestIngress("baz", "baz.example.com", nil, false),
+	}
+
+	ir, errs := ToIR(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	require.Empty(t, errs)
+
+	gateway := ir.Gateways[types.NamespacedName{Namespace: "default", Name: "nginx"}]
+	require.Equal(t, map[string]string{"external-dns.alpha.kubernetes.io/target": "other-lb.example.com"}, gateway.Annotations)
+
+	route := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: RouteName("foo", "foo.example.com")}]
+	require.Equal(t, map[string]string{
+		"external-dns.alpha.kubernetes.io/hostname": "www.foo.example.com",
+		"external-dns.alpha.kubernetes.io/ttl":      "60",
+	}, route.Annotations)
+
+	require.Empty(t, ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: RouteName("bar", "bar.example.com")}].Annotations)
+	require.Empty(t, ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: RouteName("baz", "baz.example.com")}].Annotations)
+}
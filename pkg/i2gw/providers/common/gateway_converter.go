@@ -31,10 +31,14 @@ func ToGatewayResources(ir intermediate.IR) (i2gw.GatewayResources, field.ErrorL
 		Gateways:        make(map[types.NamespacedName]gatewayv1.Gateway),
 		HTTPRoutes:      make(map[types.NamespacedName]gatewayv1.HTTPRoute),
 		GatewayClasses:  ir.GatewayClasses,
+		GRPCRoutes:      ir.GRPCRoutes,
 		TLSRoutes:       ir.TLSRoutes,
 		TCPRoutes:       ir.TCPRoutes,
 		UDPRoutes:       ir.UDPRoutes,
 		ReferenceGrants: ir.ReferenceGrants,
+
+		BackendTLSPolicies: ir.BackendTLSPolicies,
+		BackendLBPolicies:  ir.BackendLBPolicies,
 	}
 	for key, gatewayContext := range ir.Gateways {
 		gatewayResources.Gateways[key] = gatewayContext.Gateway
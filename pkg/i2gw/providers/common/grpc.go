@@ -0,0 +1,102 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"regexp"
+	"strings"
+
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+var (
+	// grpcServiceRegexp matches the names of gRPC services qualified by their
+	// package, e.g. helloworld.Greeter.
+	grpcServiceRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)+$`)
+	// grpcMethodRegexp matches the names of gRPC methods, e.g. SayHello.
+	grpcMethodRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// GRPCMethodMatch returns the gRPC method match equivalent to the path match
+// of an HTTPRoute, that is an exact match of /<service>/<method> or a prefix
+// match of /<service>/. It returns false if the path isn't a gRPC one.
+func GRPCMethodMatch(path *gatewayv1.HTTPPathMatch) (*gatewayv1.GRPCMethodMatch, bool) {
+	if path == nil || path.Value == nil {
+		return nil, false
+	}
+	service, method, _ := strings.Cut(strings.TrimPrefix(*path.Value, "/"), "/")
+	if !strings.HasPrefix(*path.Value, "/") || !grpcServiceRegexp.MatchString(service) {
+		return nil, false
+	}
+
+	match := &gatewayv1.GRPCMethodMatch{
+		Type:    ptr.To(gatewayv1.GRPCMethodMatchExact),
+		Service: ptr.To(service),
+	}
+	switch ptr.Deref(path.Type, gatewayv1.PathMatchPathPrefix) {
+	case gatewayv1.PathMatchExact:
+		if !grpcMethodRegexp.MatchString(method) {
+			return nil, false
+		}
+		match.Method = ptr.To(method)
+	case gatewayv1.PathMatchPathPrefix:
+		if method != "" {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+	return match, true
+}
+
+// ConvertHTTPFiltersToGRPCFilters returns the GRPCRoute filters equivalent to
+// the filters of an HTTPRoute rule or backendRef, and the filters without gRPC
+// equivalent, e.g. redirects and rewrites.
+func ConvertHTTPFiltersToGRPCFilters(filters []gatewayv1.HTTPRouteFilter) ([]gatewayv1.GRPCRouteFilter, []gatewayv1.HTTPRouteFilter) {
+	var (
+		grpcFilters []gatewayv1.GRPCRouteFilter
+		unsupported []gatewayv1.HTTPRouteFilter
+	)
+	for _, filter := range filters {
+		switch filter.Type {
+		case gatewayv1.HTTPRouteFilterRequestHeaderModifier:
+			grpcFilters = append(grpcFilters, gatewayv1.GRPCRouteFilter{
+				Type:                  gatewayv1.GRPCRouteFilterRequestHeaderModifier,
+				RequestHeaderModifier: filter.RequestHeaderModifier,
+			})
+		case gatewayv1.HTTPRouteFilterResponseHeaderModifier:
+			grpcFilters = append(grpcFilters, gatewayv1.GRPCRouteFilter{
+				Type:                   gatewayv1.GRPCRouteFilterResponseHeaderModifier,
+				ResponseHeaderModifier: filter.ResponseHeaderModifier,
+			})
+		case gatewayv1.HTTPRouteFilterRequestMirror:
+			grpcFilters = append(grpcFilters, gatewayv1.GRPCRouteFilter{
+				Type:          gatewayv1.GRPCRouteFilterRequestMirror,
+				RequestMirror: filter.RequestMirror,
+			})
+		case gatewayv1.HTTPRouteFilterExtensionRef:
+			grpcFilters = append(grpcFilters, gatewayv1.GRPCRouteFilter{
+				Type:         gatewayv1.GRPCRouteFilterExtensionRef,
+				ExtensionRef: filter.ExtensionRef,
+			})
+		default:
+			unsupported = append(unsupported, filter)
+		}
+	}
+	return grpcFilters, unsupported
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestGRPCMethodMatch(t *testing.T) {
+	testCases := []struct {
+		name      string
+		path      gatewayv1.HTTPPathMatch
+		wantMatch *gatewayv1.GRPCMethodMatch
+	}{
+		{
+			name:      "service prefix",
+			path:      gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/helloworld.Greeter/")},
+			wantMatch: &gatewayv1.GRPCMethodMatch{Type: ptr.To(gatewayv1.GRPCMethodMatchExact), Service: ptr.To("helloworld.Greeter")},
+		},
+		{
+			name:      "exact method",
+			path:      gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchExact), Value: ptr.To("/helloworld.Greeter/SayHello")},
+			wantMatch: &gatewayv1.GRPCMethodMatch{Type: ptr.To(gatewayv1.GRPCMethodMatchExact), Service: ptr.To("helloworld.Greeter"), Method: ptr.To("SayHello")},
+		},
+		{
+			name: "method prefix",
+			path: gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/helloworld.Greeter/SayHello")},
+		},
+		{
+			name: "service without package",
+			path: gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/api")},
+		},
+		{
+			name: "regular expression",
+			path: gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchRegularExpression), Value: ptr.To("/helloworld.Greeter/.*")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			match, ok := GRPCMethodMatch(&tc.path)
+			require.Equal(t, tc.wantMatch != nil, ok)
+			require.Equal(t, tc.wantMatch, match)
+		})
+	}
+}
+
+func TestConvertHTTPFiltersToGRPCFilters(t *testing.T) {
+	headerModifier := &gatewayv1.HTTPHeaderFilter{Set: []gatewayv1.HTTPHeader{{Name: "x-tenant", Value: "a"}}}
+	redirect := gatewayv1.HTTPRouteFilter{
+		Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+		RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{Scheme: ptr.To("https")},
+	}
+
+	grpcFilters, unsupported := ConvertHTTPFiltersToGRPCFilters([]gatewayv1.HTTPRouteFilter{
+		{Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier, RequestHeaderModifier: headerModifier},
+		redirect,
+		{Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier, ResponseHeaderModifier: headerModifier},
+	})
+	require.Equal(t, []gatewayv1.GRPCRouteFilter{
+		{Type: gatewayv1.GRPCRouteFilterRequestHeaderModifier, RequestHeaderModifier: headerModifier},
+		{Type: gatewayv1.GRPCRouteFilterResponseHeaderModifier, ResponseHeaderModifier: headerModifier},
+	}, grpcFilters)
+	require.Equal(t, []gatewayv1.HTTPRouteFilter{redirect}, unsupported)
+}
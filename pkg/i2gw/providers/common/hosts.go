@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"strings"
+)
+
+// WildcardHost is the bare wildcard host, matching all the hosts. It is not a
+// valid Gateway API hostname: all the hosts are matched by omitting the
+// hostname of listeners and routes instead.
+const WildcardHost = "*"
+
+// wildcardHostPrefix is the prefix of the wildcard hosts matching the
+// subdomains of a domain, e.g. "*.example.com".
+const wildcardHostPrefix = "*."
+
+// MatchesAllHosts returns true if the host matches all the hosts, i.e. is
+// empty or the bare wildcard host.
+func MatchesAllHosts(host string) bool {
+	return host == "" || host == WildcardHost
+}
+
+// NormalizeHost returns the host to use for Gateway API listener and route
+// hostnames, the empty host standing for all the hosts. Wildcard hosts like
+// "*.example.com" are preserved.
+func NormalizeHost(host string) string {
+	if MatchesAllHosts(host) {
+		return ""
+	}
+	return host
+}
+
+// IsWildcardHost returns true if the host matches the subdomains of a domain,
+// e.g. "*.example.com".
+func IsWildcardHost(host string) bool {
+	return strings.HasPrefix(host, wildcardHostPrefix)
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameFromHost(t *testing.T) {
+	for host, want := range map[string]string{
+		"":                  "all-hosts",
+		"*":                 "all-hosts",
+		"example.com":       "example-com",
+		"*.example.com":     "wildcard-example-com",
+		"foo.example.com":   "foo-example-com",
+		"*.foo.example.com": "wildcard-foo-example-com",
+	} {
+		require.Equal(t, want, NameFromHost(host), host)
+	}
+}
+
+func TestNormalizeHost(t *testing.T) {
+	require.Equal(t, "", NormalizeHost(""))
+	require.Equal(t, "", NormalizeHost("*"))
+	require.Equal(t, "*.example.com", NormalizeHost("*.example.com"))
+	require.Equal(t, "example.com", NormalizeHost("example.com"))
+}
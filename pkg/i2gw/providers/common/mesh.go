@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// DefaultClusterDomain is the domain of the cluster-local Service hostnames
+// when none is configured.
+const DefaultClusterDomain = "cluster.local"
+
+// ServiceFromHostname returns the Service a cluster-local hostname resolves to,
+// i.e. "svc.ns.svc" or "svc.ns.svc.<clusterDomain>", the clusterDomain
+// defaulting to DefaultClusterDomain. The second return value is false if the
+// hostname does not point to a Service.
+func ServiceFromHostname(hostname, clusterDomain string) (types.NamespacedName, bool) {
+	if clusterDomain == "" {
+		clusterDomain = DefaultClusterDomain
+	}
+	name, rest, _ := strings.Cut(hostname, ".")
+	namespace, suffix, _ := strings.Cut(rest, ".")
+	if suffix != "svc" && suffix != "svc."+clusterDomain {
+		return types.NamespacedName{}, false
+	}
+	if len(validation.IsDNS1035Label(name)) > 0 || len(validation.IsDNS1123Label(namespace)) > 0 {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, true
+}
+
+// ServiceParentRef returns a parentRef attaching a route in routeNamespace to
+// the given Service, as defined by GAMMA. When the Service lives in another
+// namespace, the route is a consumer route and only applies to the traffic
+// originating from routeNamespace.
+func ServiceParentRef(service types.NamespacedName, routeNamespace string) gatewayv1.ParentReference {
+	parentRef := gatewayv1.ParentReference{
+		Group: PtrTo(gatewayv1.Group("")),
+		Kind:  PtrTo(gatewayv1.Kind("Service")),
+		Name:  gatewayv1.ObjectName(service.Name),
+	}
+	if service.Namespace != "" && service.Namespace != routeNamespace {
+		parentRef.Namespace = PtrTo(gatewayv1.Namespace(service.Namespace))
+	}
+	return parentRef
+}
+
+// ToMeshHTTPRoutes attaches the HTTPRoutes which only match cluster-local
+// Service hostnames to those Services instead of Gateways, so that they are
+// handled by GAMMA mesh implementations. The Gateway listeners of these
+// hostnames are removed, as well as the Gateways left without listeners.
+// It returns the keys of the HTTPRoutes that were converted.
+func ToMeshHTTPRoutes(ir *intermediate.IR, clusterDomain string) []types.NamespacedName {
+	var meshRoutes []types.NamespacedName
+	meshHostnamesByGateway := map[types.NamespacedName][]gatewayv1.Hostname{}
+
+	for key, httpRouteContext := range ir.HTTPRoutes {
+		hostnames := httpRouteContext.Spec.Hostnames
+		if len(hostnames) == 0 {
+			continue
+		}
+
+		var parentRefs []gatewayv1.ParentReference
+		for _, hostname := range hostnames {
+			service, ok := ServiceFromHostname(string(hostname), clusterDomain)
+			if !ok {
+				parentRefs = nil
+				break
+			}
+			parentRefs = append(parentRefs, ServiceParentRef(service, key.Namespace))
+		}
+		if len(parentRefs) == 0 {
+			continue
+		}
+
+		for _, parentRef := range httpRouteContext.Spec.ParentRefs {
+			if parentRef.Kind != nil && string(*parentRef.Kind) != GatewayGVK.Kind {
+				continue
+			}
+			gatewayKey := types.NamespacedName{Namespace: key.Namespace, Name: string(parentRef.Name)}
+			if parentRef.Namespace != nil {
+				gatewayKey.Namespace = string(*parentRef.Namespace)
+			}
+			meshHostnamesByGateway[gatewayKey] = append(meshHostnamesByGateway[gatewayKey], hostnames...)
+		}
+
+		// Hostnames are ignored for routes attached to Services.
+		httpRouteContext.Spec.Hostnames = nil
+		httpRouteContext.Spec.ParentRefs = parentRefs
+		ir.HTTPRoutes[key] = httpRouteContext
+		meshRoutes = append(meshRoutes, key)
+	}
+
+	for gatewayKey, meshHostnames := range meshHostnamesByGateway {
+		gatewayContext, ok := ir.Gateways[gatewayKey]
+		if !ok {
+			continue
+		}
+		gatewayContext.Spec.Listeners = slices.DeleteFunc(gatewayContext.Spec.Listeners, func(listener gatewayv1.Listener) bool {
+			return listener.Hostname != nil && slices.Contains(meshHostnames, *listener.Hostname)
+		})
+		if len(gatewayContext.Spec.Listeners) == 0 {
+			delete(ir.Gateways, gatewayKey)
+			continue
+		}
+		ir.Gateways[gatewayKey] = gatewayContext
+	}
+
+	slices.SortFunc(meshRoutes, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return meshRoutes
+}
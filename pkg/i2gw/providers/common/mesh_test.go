@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestServiceFromHostname(t *testing.T) {
+	testCases := []struct {
+		name          string
+		hostname      string
+		clusterDomain string
+		wantService   types.NamespacedName
+		wantOK        bool
+	}{
+		{
+			name:        "fully qualified service hostname",
+			hostname:    "reviews.prod.svc.cluster.local",
+			wantService: types.NamespacedName{Namespace: "prod", Name: "reviews"},
+			wantOK:      true,
+		},
+		{
+			name:        "service hostname without cluster domain",
+			hostname:    "reviews.prod.svc",
+			wantService: types.NamespacedName{Namespace: "prod", Name: "reviews"},
+			wantOK:      true,
+		},
+		{
+			name:          "service hostname of a custom cluster domain",
+			hostname:      "reviews.prod.svc.example.internal",
+			clusterDomain: "example.internal",
+			wantService:   types.NamespacedName{Namespace: "prod", Name: "reviews"},
+			wantOK:        true,
+		},
+		{
+			name:     "service hostname of another cluster domain",
+			hostname: "reviews.prod.svc.example.internal",
+		},
+		{
+			name:          "default cluster domain with a custom cluster domain",
+			hostname:      "reviews.prod.svc.cluster.local",
+			clusterDomain: "example.internal",
+		},
+		{
+			name:     "partial cluster domain",
+			hostname: "reviews.prod.svc.cluster",
+		},
+		{
+			name:     "subdomain of the svc label",
+			hostname: "reviews.prod.svc.evil.com",
+		},
+		{
+			name:     "missing namespace",
+			hostname: "reviews.svc",
+		},
+		{
+			name:     "subdomain of a service hostname",
+			hostname: "v1.reviews.prod.svc.cluster.local",
+		},
+		{
+			name:     "external hostname",
+			hostname: "reviews.example.com",
+		},
+		{
+			name:     "wildcard hostname",
+			hostname: "*.prod.svc.cluster.local",
+		},
+		{
+			name:     "short name",
+			hostname: "reviews",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, ok := ServiceFromHostname(tc.hostname, tc.clusterDomain)
+			require.Equal(t, tc.wantOK, ok)
+			require.Equal(t, tc.wantService, service)
+		})
+	}
+}
+
+func TestToMeshHTTPRoutes(t *testing.T) {
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+	meshRouteKey := types.NamespacedName{Namespace: "default", Name: "ing-reviews-prod-svc-cluster-local"}
+	northSouthRouteKey := types.NamespacedName{Namespace: "default", Name: "ing-example-com"}
+
+	httpRoute := func(key types.NamespacedName, hostname gatewayv1.Hostname) intermediate.HTTPRouteContext {
+		return intermediate.HTTPRouteContext{
+			HTTPRoute: gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{
+						ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}},
+					},
+					Hostnames: []gatewayv1.Hostname{hostname},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name              string
+		ir                intermediate.IR
+		wantMeshRoutes    []types.NamespacedName
+		wantListenerNames []gatewayv1.SectionName
+		wantParentRefs    []gatewayv1.ParentReference
+	}{
+		{
+			name: "mixed north-south and east-west routes",
+			ir: intermediate.IR{
+				Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+					gatewayKey: {
+						Gateway: gatewayv1.Gateway{
+							ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+							Spec: gatewayv1.GatewaySpec{
+								Listeners: []gatewayv1.Listener{
+									{Name: "example-com-http", Hostname: PtrTo[gatewayv1.Hostname]("example.com")},
+									{Name: "reviews-prod-svc-cluster-local-http", Hostname: PtrTo[gatewayv1.Hostname]("reviews.prod.svc.cluster.local")},
+								},
+							},
+						},
+					},
+				},
+				HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+					meshRouteKey:       httpRoute(meshRouteKey, "reviews.prod.svc.cluster.local"),
+					northSouthRouteKey: httpRoute(northSouthRouteKey, "example.com"),
+				},
+			},
+			wantMeshRoutes:    []types.NamespacedName{meshRouteKey},
+			wantListenerNames: []gatewayv1.SectionName{"example-com-http"},
+			wantParentRefs: []gatewayv1.ParentReference{{
+				Group:     PtrTo[gatewayv1.Group](""),
+				Kind:      PtrTo[gatewayv1.Kind]("Service"),
+				Name:      "reviews",
+				Namespace: PtrTo[gatewayv1.Namespace]("prod"),
+			}},
+		},
+		{
+			name: "gateway without remaining listeners is removed",
+			ir: intermediate.IR{
+				Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+					gatewayKey: {
+						Gateway: gatewayv1.Gateway{
+							ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+							Spec: gatewayv1.GatewaySpec{
+								Listeners: []gatewayv1.Listener{
+									{Name: "reviews-prod-svc-cluster-local-http", Hostname: PtrTo[gatewayv1.Hostname]("reviews.prod.svc.cluster.local")},
+								},
+							},
+						},
+					},
+				},
+				HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+					meshRouteKey: httpRoute(meshRouteKey, "reviews.prod.svc.cluster.local"),
+				},
+			},
+			wantMeshRoutes: []types.NamespacedName{meshRouteKey},
+			wantParentRefs: []gatewayv1.ParentReference{{
+				Group:     PtrTo[gatewayv1.Group](""),
+				Kind:      PtrTo[gatewayv1.Kind]("Service"),
+				Name:      "reviews",
+				Namespace: PtrTo[gatewayv1.Namespace]("prod"),
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			meshRoutes := ToMeshHTTPRoutes(&tc.ir, "")
+			require.Equal(t, tc.wantMeshRoutes, meshRoutes)
+
+			meshRoute := tc.ir.HTTPRoutes[meshRouteKey]
+			require.Empty(t, meshRoute.Spec.Hostnames)
+			if diff := cmp.Diff(tc.wantParentRefs, meshRoute.Spec.ParentRefs); diff != "" {
+				t.Errorf("unexpected mesh parentRefs (-want +got): %s", diff)
+			}
+
+			gatewayContext, ok := tc.ir.Gateways[gatewayKey]
+			require.Equal(t, len(tc.wantListenerNames) > 0, ok)
+			var listenerNames []gatewayv1.SectionName
+			for _, listener := range gatewayContext.Spec.Listeners {
+				listenerNames = append(listenerNames, listener.Name)
+			}
+			require.Equal(t, tc.wantListenerNames, listenerNames)
+
+			if northSouthRoute, ok := tc.ir.HTTPRoutes[northSouthRouteKey]; ok {
+				require.Equal(t, []gatewayv1.Hostname{"example.com"}, northSouthRoute.Spec.Hostnames)
+			}
+		})
+	}
+}
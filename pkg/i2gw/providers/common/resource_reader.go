@@ -17,23 +17,27 @@ limitations under the License.
 package common
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"runtime"
+	"sync"
 
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/sets"
 	kubeyaml "k8s.io/apimachinery/pkg/util/yaml"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func ReadIngressesFromCluster(ctx context.Context, client client.Client, ingressClasses sets.Set[string]) (map[types.NamespacedName]*networkingv1.Ingress, error) {
+// ReadIngressesFromCluster returns the Ingresses of the cluster claimed by the
+// given function, see i2gw.IngressClaimer.
+func ReadIngressesFromCluster(ctx context.Context, client client.Client, claim func(*networkingv1.Ingress) (*networkingv1.Ingress, bool)) (map[types.NamespacedName]*networkingv1.Ingress, error) {
 	var ingressList networkingv1.IngressList
 	err := client.List(ctx, &ingressList)
 	if err != nil {
@@ -41,17 +45,20 @@ func ReadIngressesFromCluster(ctx context.Context, client client.Client, ingress
 	}
 
 	ingresses := map[types.NamespacedName]*networkingv1.Ingress{}
-	for i, ingress := range ingressList.Items {
-		if !ingressClasses.Has(GetIngressClass(ingress)) {
+	for i := range ingressList.Items {
+		ingress, ok := claim(&ingressList.Items[i])
+		if !ok {
 			continue
 		}
-		ingresses[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = &ingressList.Items[i]
+		ingresses[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = ingress
 	}
 
 	return ingresses, nil
 }
 
-func ReadIngressesFromFile(filename, namespace string, ingressClasses sets.Set[string]) (map[types.NamespacedName]*networkingv1.Ingress, error) {
+// ReadIngressesFromFile returns the Ingresses of the file claimed by the given
+// function, see i2gw.IngressClaimer.
+func ReadIngressesFromFile(filename, namespace string, claim func(*networkingv1.Ingress) (*networkingv1.Ingress, bool)) (map[types.NamespacedName]*networkingv1.Ingress, error) {
 	stream, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file %v: %w", filename, err)
@@ -64,17 +71,20 @@ func ReadIngressesFromFile(filename, namespace string, ingressClasses sets.Set[s
 
 	ingresses := map[types.NamespacedName]*networkingv1.Ingress{}
 	for _, f := range unstructuredObjects {
-		if !f.GroupVersionKind().Empty() && f.GroupVersionKind().Kind == "Ingress" {
+		// Only networking.k8s.io Ingresses are read, not the Ingress CRDs of
+		// some controllers, e.g. voyager.appscode.com.
+		if f.GroupVersionKind().Group == networkingv1.GroupName && f.GroupVersionKind().Kind == "Ingress" {
 			var ingress networkingv1.Ingress
-			err = runtime.DefaultUnstructuredConverter.
+			err = k8sruntime.DefaultUnstructuredConverter.
 				FromUnstructured(f.UnstructuredContent(), &ingress)
 			if err != nil {
 				return nil, err
 			}
-			if !ingressClasses.Has(GetIngressClass(ingress)) {
+			claimed, ok := claim(&ingress)
+			if !ok {
 				continue
 			}
-			ingresses[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = &ingress
+			ingresses[types.NamespacedName{Namespace: claimed.Namespace, Name: claimed.Name}] = claimed
 		}
 
 	}
@@ -86,30 +96,23 @@ func ReadIngressesFromFile(filename, namespace string, ingressClasses sets.Set[s
 // It retrieves all objects, including nested ones if they are contained within a list.
 // The function takes a namespace parameter to optionally return only namespaced resources.
 func ExtractObjectsFromReader(reader io.Reader, namespace string) ([]*unstructured.Unstructured, error) {
-	d := kubeyaml.NewYAMLOrJSONDecoder(reader, 4096)
+	decoded, err := decodeObjects(reader)
 	var objs []*unstructured.Unstructured
-	for {
-		u := &unstructured.Unstructured{}
-		if err := d.Decode(&u); err != nil {
-			if errors.Is(err, io.EOF) {
-				break
-			}
-			return objs, fmt.Errorf("failed to unmarshal manifest: %w", err)
-		}
-		if u == nil {
-			continue
-		}
+	for _, u := range decoded {
 		if namespace != "" && u.GetNamespace() != namespace {
 			continue
 		}
 		objs = append(objs, u)
 	}
+	if err != nil {
+		return objs, err
+	}
 
 	finalObjs := []*unstructured.Unstructured{}
 	for _, obj := range objs {
 		tmpObjs := []*unstructured.Unstructured{}
 		if obj.IsList() {
-			err := obj.EachListItem(func(object runtime.Object) error {
+			err := obj.EachListItem(func(object k8sruntime.Object) error {
 				unstructuredObj, ok := object.(*unstructured.Unstructured)
 				if ok {
 					tmpObjs = append(tmpObjs, unstructuredObj)
@@ -128,3 +131,120 @@ func ExtractObjectsFromReader(reader io.Reader, namespace string) ([]*unstructur
 
 	return finalObjs, nil
 }
+
+// decodeWorkers is the number of YAML documents decoded concurrently.
+var decodeWorkers = runtime.GOMAXPROCS(0)
+
+// yamlDocument is the YAML document of the given index of a stream.
+type yamlDocument struct {
+	index int
+	raw   []byte
+}
+
+// decodedDocument holds the objects decoded from the YAML document of the
+// given index of a stream.
+type decodedDocument struct {
+	index int
+	objs  []*unstructured.Unstructured
+	err   error
+}
+
+// decodeObjects decodes the objects of the YAML or JSON stream, in order.
+// The documents of YAML streams, which may hold hundreds of MB of the exported
+// state of a cluster, are decoded by a pool of workers while being read.
+// It returns the objects preceding the first document it failed to decode.
+func decodeObjects(reader io.Reader) ([]*unstructured.Unstructured, error) {
+	buffered := bufio.NewReader(reader)
+	if isJSONStream(buffered) {
+		return decodeDocuments(buffered)
+	}
+
+	documents := make(chan yamlDocument)
+	results := make(chan decodedDocument)
+
+	var workers sync.WaitGroup
+	for i := 0; i < decodeWorkers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for document := range documents {
+				objs, err := decodeDocuments(bytes.NewReader(document.raw))
+				results <- decodedDocument{index: document.index, objs: objs, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(documents)
+		yamlReader := kubeyaml.NewYAMLReader(buffered)
+		for index := 0; ; index++ {
+			raw, err := yamlReader.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				results <- decodedDocument{index: index, err: fmt.Errorf("failed to read manifest: %w", err)}
+				return
+			}
+			documents <- yamlDocument{index: index, raw: raw}
+		}
+	}()
+
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	decoded := map[int]decodedDocument{}
+	for result := range results {
+		decoded[result.index] = result
+	}
+
+	var objs []*unstructured.Unstructured
+	for index := 0; index < len(decoded); index++ {
+		result := decoded[index]
+		if result.err != nil {
+			return objs, result.err
+		}
+		objs = append(objs, result.objs...)
+	}
+	return objs, nil
+}
+
+// decodeDocuments sequentially decodes the objects of the YAML or JSON stream.
+func decodeDocuments(reader io.Reader) ([]*unstructured.Unstructured, error) {
+	d := kubeyaml.NewYAMLOrJSONDecoder(reader, 4096)
+	var objs []*unstructured.Unstructured
+	for {
+		u := &unstructured.Unstructured{}
+		if err := d.Decode(&u); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return objs, fmt.Errorf("failed to unmarshal manifest: %w", err)
+		}
+		if u == nil {
+			continue
+		}
+		objs = append(objs, u)
+	}
+	return objs, nil
+}
+
+// isJSONStream returns true if the stream starts with a JSON object or array,
+// which isn't split in YAML documents.
+func isJSONStream(reader *bufio.Reader) bool {
+	for size := 1; ; size++ {
+		peeked, _ := reader.Peek(size)
+		if len(peeked) < size {
+			return false
+		}
+		switch peeked[size-1] {
+		case ' ', '\t', '\r', '\n':
+		case '{', '[':
+			return true
+		default:
+			return false
+		}
+	}
+}
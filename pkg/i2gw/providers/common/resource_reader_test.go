@@ -142,3 +142,45 @@ func compareIngressLists(t *testing.T, gotIngressList *networkingv1.IngressList,
 		}
 	}
 }
+
+func Test_ExtractObjectsFromReader_concurrentDecoding(t *testing.T) {
+	const count = 500
+
+	var manifest bytes.Buffer
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&manifest, "---\n# comment only document\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: service-%d\n  namespace: namespace%d\n", i, i%2)
+	}
+
+	objs, err := ExtractObjectsFromReader(bytes.NewReader(manifest.Bytes()), "")
+	if err != nil {
+		t.Fatalf("failed to extract objects: %v", err)
+	}
+	if len(objs) != count {
+		t.Fatalf("Expected %d objects, got %d", count, len(objs))
+	}
+	for i, obj := range objs {
+		if want := fmt.Sprintf("service-%d", i); obj.GetName() != want {
+			t.Fatalf("Expected object %d to be %s, got %s", i, want, obj.GetName())
+		}
+	}
+
+	objs, err = ExtractObjectsFromReader(bytes.NewReader(manifest.Bytes()), "namespace1")
+	if err != nil {
+		t.Fatalf("failed to extract objects: %v", err)
+	}
+	if len(objs) != count/2 {
+		t.Fatalf("Expected %d objects of namespace1, got %d", count/2, len(objs))
+	}
+}
+
+func Test_ExtractObjectsFromReader_invalidDocument(t *testing.T) {
+	manifest := "apiVersion: v1\nkind: Service\nmetadata:\n  name: valid\n---\nkind: [invalid\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: after-invalid\n"
+
+	objs, err := ExtractObjectsFromReader(bytes.NewReader([]byte(manifest)), "")
+	if err == nil {
+		t.Fatalf("Expected an error for the invalid document")
+	}
+	if len(objs) != 1 || objs[0].GetName() != "valid" {
+		t.Errorf("Expected the objects preceding the invalid document, got %v", objs)
+	}
+}
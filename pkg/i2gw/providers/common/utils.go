@@ -19,9 +19,13 @@ package common
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
@@ -54,22 +58,37 @@ type Rule struct {
 	IngressRule networkingv1.IngressRule
 }
 
-func GetRuleGroups(ingresses []networkingv1.Ingress) map[string]IngressRuleGroup {
+// GetRuleGroups groups the rules of the Ingresses by the HTTPRoute of the IR
+// they were converted to. The rules of the Ingresses of the same namespace,
+// class and host are grouped under the first of these Ingresses, unless the IR
+// holds an HTTPRoute generated from each Ingress on its own, as it does when
+// route merging is disabled.
+func GetRuleGroups(ingresses []networkingv1.Ingress, ir *intermediate.IR) map[string]IngressRuleGroup {
 	ruleGroups := make(map[string]IngressRuleGroup)
+	firstIngressByKey := make(map[string]string)
 
 	for _, ingress := range ingresses {
 		ingressClass := GetIngressClass(ingress)
 
 		for _, rule := range ingress.Spec.Rules {
+			host := NormalizeHost(rule.Host)
+			rgKey := fmt.Sprintf("%s/%s/%s", ingress.Namespace, ingressClass, host)
+			if _, ok := firstIngressByKey[rgKey]; !ok {
+				firstIngressByKey[rgKey] = ingress.Name
+			}
+			name := firstIngressByKey[rgKey]
+			if name != ingress.Name && hasOwnHTTPRoute(ir, ingress, host) {
+				name = ingress.Name
+			}
+			rgKey = fmt.Sprintf("%s/%s", rgKey, name)
 
-			rgKey := fmt.Sprintf("%s/%s/%s", ingress.Namespace, ingressClass, rule.Host)
 			rg, ok := ruleGroups[rgKey]
 			if !ok {
 				rg = IngressRuleGroup{
 					Namespace:    ingress.Namespace,
-					Name:         ingress.Name,
+					Name:         name,
 					IngressClass: ingressClass,
-					Host:         rule.Host,
+					Host:         host,
 				}
 				ruleGroups[rgKey] = rg
 			}
@@ -87,7 +106,30 @@ func GetRuleGroups(ingresses []networkingv1.Ingress) map[string]IngressRuleGroup
 	return ruleGroups
 }
 
+// hasOwnHTTPRoute returns whether the IR holds an HTTPRoute generated from the
+// rules of the Ingress for the host only.
+func hasOwnHTTPRoute(ir *intermediate.IR, ingress networkingv1.Ingress, host string) bool {
+	if ir == nil {
+		return false
+	}
+	httpRouteContext, ok := ir.HTTPRoutes[types.NamespacedName{Namespace: ingress.Namespace, Name: RouteName(ingress.Name, host)}]
+	if !ok {
+		return false
+	}
+	for _, source := range httpRouteContext.Sources {
+		if source.GetNamespace() == ingress.Namespace && source.GetName() == ingress.Name {
+			return true
+		}
+	}
+	return false
+}
+
 func NameFromHost(host string) string {
+	// wildcard hosts are named distinctly from the domain they match the
+	// subdomains of, e.g. *.example.com and example.com
+	if IsWildcardHost(host) {
+		return "wildcard-" + NameFromHost(strings.TrimPrefix(host, wildcardHostPrefix))
+	}
 	// replace all special chars with -
 	reg, _ := regexp.Compile("[^a-zA-Z0-9]+")
 	step1 := reg.ReplaceAllString(host, "-")
@@ -95,7 +137,7 @@ func NameFromHost(host string) string {
 	reg2, _ := regexp.Compile("^[^a-zA-Z0-9]+")
 	step2 := reg2.ReplaceAllString(step1, "")
 	// if nothing left, return "all-hosts"
-	if len(host) == 0 || host == "*" {
+	if MatchesAllHosts(host) {
 		return "all-hosts"
 	}
 	return step2
@@ -130,6 +172,9 @@ func ToBackendRef(ib networkingv1.IngressBackend, path *field.Path) (*gatewayv1.
 type orderedIngressPathsByMatchKey struct {
 	keys []pathMatchKey
 	data map[pathMatchKey][]ingressPath
+	// duplicates contains the keys matched by several paths of a single
+	// Ingress pointing to different backends.
+	duplicates map[pathMatchKey]struct{}
 }
 
 func groupIngressPathsByMatchKey(rules []ingressRule) orderedIngressPathsByMatchKey {
@@ -149,9 +194,55 @@ func groupIngressPathsByMatchKey(rules []ingressRule) orderedIngressPathsByMatch
 			ingressPathsByMatchKey.data[pmKey] = append(ingressPathsByMatchKey.data[pmKey], ip)
 		}
 	}
+
+	// Ingress controllers disagree on how duplicate paths of a single Ingress
+	// are handled: some keep the first or the last backend, others generate
+	// duplicate matches. The backends of such paths are always merged into a
+	// single rule splitting the traffic equally.
+	for _, pmKey := range ingressPathsByMatchKey.keys {
+		var ingresses []*networkingv1.Ingress
+		backendsByIngress := map[*networkingv1.Ingress]map[string]struct{}{}
+		for _, ip := range ingressPathsByMatchKey.data[pmKey] {
+			ingress := rules[ip.ruleIdx].ingress
+			if ingress == nil {
+				continue
+			}
+			if backendsByIngress[ingress] == nil {
+				ingresses = append(ingresses, ingress)
+				backendsByIngress[ingress] = map[string]struct{}{}
+			}
+			backendsByIngress[ingress][ingressBackendKey(ip.path.Backend)] = struct{}{}
+		}
+		for _, ingress := range ingresses {
+			if len(backendsByIngress[ingress]) < 2 {
+				continue
+			}
+			if ingressPathsByMatchKey.duplicates == nil {
+				ingressPathsByMatchKey.duplicates = map[pathMatchKey]struct{}{}
+			}
+			ingressPathsByMatchKey.duplicates[pmKey] = struct{}{}
+			notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s has duplicate %q paths pointing to different backends, the backends were merged with equal weights", ingress.Namespace, ingress.Name, pmKey), ingress)
+		}
+	}
 	return ingressPathsByMatchKey
 }
 
+// ingressBackendKey returns a string identifying the backend an IngressBackend
+// points to.
+func ingressBackendKey(backend networkingv1.IngressBackend) string {
+	if backend.Service != nil {
+		return fmt.Sprintf("service/%s/%s/%d", backend.Service.Name, backend.Service.Port.Name, backend.Service.Port.Number)
+	}
+	if backend.Resource != nil {
+		var apiGroup string
+		if backend.Resource.APIGroup != nil {
+			apiGroup = *backend.Resource.APIGroup
+		}
+		return fmt.Sprintf("resource/%s/%s/%s", apiGroup, backend.Resource.Kind, backend.Resource.Name)
+	}
+	return ""
+}
+
 func PtrTo[T any](a T) *T {
 	return &a
 }
@@ -200,3 +291,45 @@ func removeBackendRefsDuplicates(backendRefs []gatewayv1.HTTPBackendRef) []gatew
 	}
 	return uniqueBackendRefs
 }
+
+// HTTPRouteRuleMatchesPath returns true if one of the matches of the HTTPRoute rule was generated
+// from the given Ingress path.
+func HTTPRouteRuleMatchesPath(rule gatewayv1.HTTPRouteRule, path networkingv1.HTTPIngressPath) bool {
+	for _, match := range rule.Matches {
+		if match.Path == nil || match.Path.Value == nil || *match.Path.Value != path.Path {
+			continue
+		}
+		if path.PathType == nil || match.Path.Type == nil {
+			continue
+		}
+		switch *path.PathType {
+		case networkingv1.PathTypePrefix:
+			if *match.Path.Type == gatewayv1.PathMatchPathPrefix {
+				return true
+			}
+		case networkingv1.PathTypeExact:
+			if *match.Path.Type == gatewayv1.PathMatchExact {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RuleIndicesForIngressRule returns the indices of the HTTPRoute rules generated from the paths
+// of the given Ingress rule.
+func RuleIndicesForIngressRule(httpRoute gatewayv1.HTTPRoute, ingressRule networkingv1.IngressRule) []int {
+	var indices []int
+	if ingressRule.HTTP == nil {
+		return indices
+	}
+	for i, rule := range httpRoute.Spec.Rules {
+		for _, path := range ingressRule.HTTP.Paths {
+			if HTTPRouteRuleMatchesPath(rule, path) {
+				indices = append(indices, i)
+				break
+			}
+		}
+	}
+	return indices
+}
@@ -21,11 +21,40 @@ import (
 
 	"github.com/stretchr/testify/require"
 	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestGroupIngressPathsByMatchKey(t *testing.T) {
 	iPrefix := networkingv1.PathTypePrefix
 
+	duplicateIngress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "duplicate", Namespace: "default"},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{{
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{
+								Path:     "/test",
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: "test-a", Port: networkingv1.ServiceBackendPort{Number: 80}},
+								},
+							},
+							{
+								Path:     "/test",
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: "test-b", Port: networkingv1.ServiceBackendPort{Number: 80}},
+								},
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+
 	testCases := []struct {
 		name     string
 		rules    []ingressRule
@@ -43,7 +72,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 			name: "1 rule with 1 match",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -96,7 +125,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 			name: "1 rule, multiple matches, different path",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -181,7 +210,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 			name: "multiple rules with single matches, same path",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -203,7 +232,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 					},
 				},
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -273,7 +302,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 			name: "multiple rules with single matches, different path",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -295,7 +324,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 					},
 				},
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -368,7 +397,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 			name: "multiple rules with multiple matches, mixed paths",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -402,7 +431,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 					},
 				},
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -520,6 +549,25 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "duplicate paths within a single ingress",
+			rules: []ingressRule{
+				{
+					ingress: duplicateIngress,
+					rule:    duplicateIngress.Spec.Rules[0],
+				},
+			},
+			expected: orderedIngressPathsByMatchKey{
+				keys: []pathMatchKey{"Prefix//test"},
+				data: map[pathMatchKey][]ingressPath{
+					"Prefix//test": {
+						{ruleIdx: 0, pathIdx: 0, ruleType: "http", path: duplicateIngress.Spec.Rules[0].HTTP.Paths[0]},
+						{ruleIdx: 0, pathIdx: 1, ruleType: "http", path: duplicateIngress.Spec.Rules[0].HTTP.Paths[1]},
+					},
+				},
+				duplicates: map[pathMatchKey]struct{}{"Prefix//test": {}},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
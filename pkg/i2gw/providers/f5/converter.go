@@ -0,0 +1,537 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package f5
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+const (
+	// httpTrafficAllow serves the HTTP requests of a VirtualServer with TLS,
+	// the default.
+	httpTrafficAllow = "allow"
+	// httpTrafficRedirect redirects the HTTP requests of a VirtualServer with
+	// TLS to HTTPS.
+	httpTrafficRedirect = "redirect"
+	// httpTrafficNone drops the HTTP requests of a VirtualServer with TLS.
+	httpTrafficNone = "none"
+)
+
+type resourcesToIRConverter struct {
+	gatewayClassName string
+}
+
+func newResourcesToIRConverter(conf *i2gw.ProviderConf) resourcesToIRConverter {
+	converter := resourcesToIRConverter{gatewayClassName: DefaultGatewayClassName}
+	if gatewayClassName := conf.ProviderSpecificFlags[ProviderName][GatewayClassFlag]; gatewayClassName != "" {
+		converter.gatewayClassName = gatewayClassName
+	}
+	return converter
+}
+
+// conversion holds the state of the conversion of the F5 CRDs: the IR being
+// built, and its Gateways, one per namespace and virtual server address.
+type conversion struct {
+	storage  *storage
+	ir       intermediate.IR
+	gateways map[types.NamespacedName]*gatewayv1.Gateway
+}
+
+func (c *resourcesToIRConverter) convertToIR(storage *storage) (intermediate.IR, field.ErrorList) {
+	cv := conversion{
+		storage: storage,
+		ir: intermediate.IR{
+			Gateways:        make(map[types.NamespacedName]intermediate.GatewayContext),
+			HTTPRoutes:      make(map[types.NamespacedName]intermediate.HTTPRouteContext),
+			TCPRoutes:       make(map[types.NamespacedName]gatewayv1alpha2.TCPRoute),
+			UDPRoutes:       make(map[types.NamespacedName]gatewayv1alpha2.UDPRoute),
+			ReferenceGrants: make(map[types.NamespacedName]gatewayv1beta1.ReferenceGrant),
+		},
+		gateways: make(map[types.NamespacedName]*gatewayv1.Gateway),
+	}
+
+	var errs field.ErrorList
+	for _, key := range sortedKeys(storage.VirtualServers) {
+		errs = append(errs, c.convertVirtualServer(storage.VirtualServers[key], &cv)...)
+	}
+	for _, key := range sortedKeys(storage.TransportServers) {
+		errs = append(errs, c.convertTransportServer(storage.TransportServers[key], &cv)...)
+	}
+
+	for key, gateway := range cv.gateways {
+		if len(gateway.Spec.Listeners) > 0 {
+			cv.ir.Gateways[key] = intermediate.GatewayContext{Gateway: *gateway}
+		}
+	}
+	return cv.ir, errs
+}
+
+func (c *resourcesToIRConverter) convertVirtualServer(virtualServer *VirtualServer, cv *conversion) field.ErrorList {
+	key := types.NamespacedName{Namespace: virtualServer.Namespace, Name: virtualServer.Name}
+	specPath := field.NewPath(ProviderName, VirtualServerKind).Key(key.String()).Child("spec")
+
+	certificateRefs, passthrough, err := cv.certificateRefs(virtualServer, specPath.Child("tlsProfileName"))
+	if err != nil {
+		return field.ErrorList{err}
+	}
+	if passthrough {
+		notify(notifications.WarningNotification, fmt.Sprintf("VirtualServer %s passes TLS through, it was not converted, use a TransportServer or a TLSRoute", key), virtualServer)
+		return nil
+	}
+	tls := len(certificateRefs) > 0
+	httpTraffic := virtualServer.Spec.HTTPTraffic
+	if httpTraffic == "" {
+		httpTraffic = httpTrafficAllow
+	}
+	if tls && !slices.Contains([]string{httpTrafficAllow, httpTrafficRedirect, httpTrafficNone}, httpTraffic) {
+		return field.ErrorList{field.NotSupported(specPath.Child("httpTraffic"), httpTraffic, []string{httpTrafficAllow, httpTrafficRedirect, httpTrafficNone})}
+	}
+	notifyUnsupportedFeatures(virtualServer)
+
+	var hostnames []gatewayv1.Hostname
+	for _, host := range append([]string{virtualServer.Spec.Host}, virtualServer.Spec.HostAliases...) {
+		if host != "" && !slices.Contains(hostnames, gatewayv1.Hostname(host)) {
+			hostnames = append(hostnames, gatewayv1.Hostname(host))
+		}
+	}
+	httpPort := portOrDefault(virtualServer.Spec.VirtualServerHTTPPort, 80)
+	httpsPort := portOrDefault(virtualServer.Spec.VirtualServerHTTPSPort, 443)
+
+	gateway := cv.gateway(virtualServer.Namespace, virtualServer.Spec.VirtualServerAddress, c.gatewayClassName)
+	var httpListeners, httpsListeners []gatewayv1.SectionName
+	listenerHostnames := hostnames
+	if len(listenerHostnames) == 0 {
+		listenerHostnames = []gatewayv1.Hostname{""}
+	}
+	for _, hostname := range listenerHostnames {
+		if !tls || httpTraffic != httpTrafficNone {
+			listener := hostListener(hostname, gatewayv1.HTTPProtocolType, httpPort, 80)
+			addListener(gateway, listener)
+			httpListeners = append(httpListeners, listener.Name)
+		}
+		if tls {
+			listener := hostListener(hostname, gatewayv1.HTTPSProtocolType, httpsPort, 443)
+			listener.TLS = &gatewayv1.GatewayTLSConfig{CertificateRefs: certificateRefs}
+			addListener(gateway, listener)
+			httpsListeners = append(httpsListeners, listener.Name)
+		}
+	}
+
+	var sectionNames []gatewayv1.SectionName
+	switch {
+	case !tls:
+		sectionNames = httpListeners
+	case httpTraffic == httpTrafficAllow:
+		sectionNames = append(httpsListeners, httpListeners...)
+	default:
+		sectionNames = httpsListeners
+	}
+
+	var (
+		errs  field.ErrorList
+		rules []gatewayv1.HTTPRouteRule
+	)
+	for i, pool := range virtualServer.Spec.Pools {
+		rule, poolErrs := cv.poolRule(virtualServer, pool, specPath.Child("pools").Index(i))
+		if len(poolErrs) > 0 {
+			errs = append(errs, poolErrs...)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: commonRouteSpec(gateway.Name, sectionNames),
+			Hostnames:       hostnames,
+			Rules:           rules,
+		},
+	}
+	httpRoute.SetGroupVersionKind(common.HTTPRouteGVK)
+	cv.ir.HTTPRoutes[key] = intermediate.HTTPRouteContext{
+		HTTPRoute:           httpRoute,
+		Sources:             []client.Object{virtualServer},
+		UnsupportedFeatures: unsupportedFeatures(virtualServer),
+	}
+
+	if tls && httpTraffic == httpTrafficRedirect {
+		redirect := gatewayv1.HTTPRequestRedirectFilter{
+			Scheme:     ptr.To("https"),
+			StatusCode: ptr.To(302),
+		}
+		if httpsPort != 443 {
+			redirect.Port = ptr.To(httpsPort)
+		}
+		redirectKey := types.NamespacedName{Namespace: key.Namespace, Name: fmt.Sprintf("%s-redirect", key.Name)}
+		redirectRoute := gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: redirectKey.Namespace, Name: redirectKey.Name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: commonRouteSpec(gateway.Name, httpListeners),
+				Hostnames:       hostnames,
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Filters: []gatewayv1.HTTPRouteFilter{{
+						Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+						RequestRedirect: &redirect,
+					}},
+				}},
+			},
+		}
+		redirectRoute.SetGroupVersionKind(common.HTTPRouteGVK)
+		cv.ir.HTTPRoutes[redirectKey] = intermediate.HTTPRouteContext{
+			HTTPRoute: redirectRoute,
+			Sources:   []client.Object{virtualServer},
+		}
+	}
+
+	notify(notifications.InfoNotification, fmt.Sprintf("successfully converted VirtualServer %s to HTTPRoute %s", key, key), virtualServer)
+	return nil
+}
+
+// poolRule converts a pool of a VirtualServer to an HTTPRoute rule matching
+// the path of the pool.
+func (cv *conversion) poolRule(virtualServer *VirtualServer, pool Pool, poolPath *field.Path) (gatewayv1.HTTPRouteRule, field.ErrorList) {
+	backendRefs, errs := cv.poolBackendRefs(virtualServer.Namespace, pool, poolPath, common.HTTPRouteGVK)
+	if len(errs) > 0 {
+		return gatewayv1.HTTPRouteRule{}, errs
+	}
+
+	path := pool.Path
+	if path == "" {
+		path = "/"
+	}
+	rule := gatewayv1.HTTPRouteRule{
+		Matches: []gatewayv1.HTTPRouteMatch{{
+			Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To(path)},
+		}},
+	}
+	for _, backendRef := range backendRefs {
+		rule.BackendRefs = append(rule.BackendRefs, gatewayv1.HTTPBackendRef{BackendRef: backendRef})
+	}
+	if pool.Rewrite != "" || pool.HostRewrite != "" {
+		urlRewrite := &gatewayv1.HTTPURLRewriteFilter{}
+		if pool.Rewrite != "" {
+			urlRewrite.Path = &gatewayv1.HTTPPathModifier{
+				Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+				ReplacePrefixMatch: ptr.To(pool.Rewrite),
+			}
+		}
+		if pool.HostRewrite != "" {
+			urlRewrite.Hostname = ptr.To(gatewayv1.PreciseHostname(pool.HostRewrite))
+		}
+		rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterURLRewrite, URLRewrite: urlRewrite})
+	}
+	if pool.Monitor != nil {
+		notify(notifications.InfoNotification, fmt.Sprintf("the %s health monitor of %s of VirtualServer %s/%s was not converted, configure the health checks of the implementation", pool.Monitor.Type, poolPath, virtualServer.Namespace, virtualServer.Name), virtualServer)
+	}
+	if pool.WAF != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("the WAF policy %s of %s of VirtualServer %s/%s was not converted, the requests of the path are not inspected", pool.WAF, poolPath, virtualServer.Namespace, virtualServer.Name), virtualServer)
+	}
+	return rule, nil
+}
+
+func (c *resourcesToIRConverter) convertTransportServer(transportServer *TransportServer, cv *conversion) field.ErrorList {
+	key := types.NamespacedName{Namespace: transportServer.Namespace, Name: transportServer.Name}
+	specPath := field.NewPath(ProviderName, TransportServerKind).Key(key.String()).Child("spec")
+
+	if transportServer.Spec.VirtualServerPort == 0 {
+		return field.ErrorList{field.Required(specPath.Child("virtualServerPort"), "the port of the TransportServer must be set")}
+	}
+	protocol := gatewayv1.TCPProtocolType
+	switch transportServer.Spec.Type {
+	case "", "tcp":
+	case "udp":
+		protocol = gatewayv1.UDPProtocolType
+	default:
+		return field.ErrorList{field.NotSupported(specPath.Child("type"), transportServer.Spec.Type, []string{"tcp", "udp"})}
+	}
+
+	routeGVK := common.TCPRouteGVK
+	if protocol == gatewayv1.UDPProtocolType {
+		routeGVK = common.UDPRouteGVK
+	}
+	backendRefs, errs := cv.poolBackendRefs(transportServer.Namespace, transportServer.Spec.Pool, specPath.Child("pool"), routeGVK)
+	if len(errs) > 0 {
+		return errs
+	}
+	if transportServer.Spec.Pool.Monitor != nil {
+		notify(notifications.InfoNotification, fmt.Sprintf("the %s health monitor of the pool of TransportServer %s was not converted, configure the health checks of the implementation", transportServer.Spec.Pool.Monitor.Type, key), transportServer)
+	}
+
+	gateway := cv.gateway(transportServer.Namespace, transportServer.Spec.VirtualServerAddress, c.gatewayClassName)
+	port := gatewayv1.PortNumber(transportServer.Spec.VirtualServerPort)
+	listener := gatewayv1.Listener{
+		Name:     gatewayv1.SectionName(fmt.Sprintf("%s-%d", strings.ToLower(string(protocol)), port)),
+		Port:     port,
+		Protocol: protocol,
+	}
+	if slices.ContainsFunc(gateway.Spec.Listeners, func(existing gatewayv1.Listener) bool { return existing.Name == listener.Name }) {
+		return field.ErrorList{field.Duplicate(specPath.Child("virtualServerPort"), transportServer.Spec.VirtualServerPort)}
+	}
+	addListener(gateway, listener)
+
+	objectMeta := metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}
+	commonRouteSpec := commonRouteSpec(gateway.Name, []gatewayv1.SectionName{listener.Name})
+	if protocol == gatewayv1.UDPProtocolType {
+		udpRoute := gatewayv1alpha2.UDPRoute{
+			ObjectMeta: objectMeta,
+			Spec: gatewayv1alpha2.UDPRouteSpec{
+				CommonRouteSpec: commonRouteSpec,
+				Rules:           []gatewayv1alpha2.UDPRouteRule{{BackendRefs: backendRefs}},
+			},
+		}
+		udpRoute.SetGroupVersionKind(common.UDPRouteGVK)
+		cv.ir.UDPRoutes[key] = udpRoute
+	} else {
+		tcpRoute := gatewayv1alpha2.TCPRoute{
+			ObjectMeta: objectMeta,
+			Spec: gatewayv1alpha2.TCPRouteSpec{
+				CommonRouteSpec: commonRouteSpec,
+				Rules:           []gatewayv1alpha2.TCPRouteRule{{BackendRefs: backendRefs}},
+			},
+		}
+		tcpRoute.SetGroupVersionKind(common.TCPRouteGVK)
+		cv.ir.TCPRoutes[key] = tcpRoute
+	}
+	notify(notifications.InfoNotification, fmt.Sprintf("successfully converted TransportServer %s to %s %s", key, routeGVK.Kind, key), transportServer)
+	return nil
+}
+
+// certificateRefs returns the references to the certificates of the TLS
+// profile of the VirtualServer, and whether the profile passes TLS through.
+// SSL profiles of the BIG-IP can't be referenced, a VirtualServer referencing
+// them is converted without TLS.
+func (cv *conversion) certificateRefs(virtualServer *VirtualServer, path *field.Path) ([]gatewayv1.SecretObjectReference, bool, *field.Error) {
+	if virtualServer.Spec.TLSProfileName == "" {
+		return nil, false, nil
+	}
+	tlsProfile, ok := cv.storage.TLSProfiles[types.NamespacedName{Namespace: virtualServer.Namespace, Name: virtualServer.Spec.TLSProfileName}]
+	if !ok {
+		return nil, false, field.NotFound(path, virtualServer.Spec.TLSProfileName)
+	}
+
+	tls := tlsProfile.Spec.TLS
+	switch tls.Termination {
+	case "passthrough":
+		return nil, true, nil
+	case "reencrypt":
+		notify(notifications.WarningNotification, fmt.Sprintf("the re-encryption to the backends of TLSProfile %s/%s was not converted, configure it with BackendTLSPolicies", tlsProfile.Namespace, tlsProfile.Name), virtualServer)
+	}
+	if tls.Reference != "secret" {
+		notify(notifications.WarningNotification, fmt.Sprintf("TLSProfile %s/%s references SSL profiles of the BIG-IP, which listeners can't reference, VirtualServer %s/%s was converted without TLS", tlsProfile.Namespace, tlsProfile.Name, virtualServer.Namespace, virtualServer.Name), virtualServer)
+		return nil, false, nil
+	}
+
+	var refs []gatewayv1.SecretObjectReference
+	for _, secretName := range append([]string{tls.ClientSSL}, tls.ClientSSLs...) {
+		ref := gatewayv1.SecretObjectReference{Name: gatewayv1.ObjectName(secretName)}
+		if secretName != "" && !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, false, nil
+}
+
+// poolBackendRefs returns the references to the Service of the pool, and to
+// its alternate backends, granting the references across namespaces.
+func (cv *conversion) poolBackendRefs(namespace string, pool Pool, poolPath *field.Path, routeGVK schema.GroupVersionKind) ([]gatewayv1.BackendRef, field.ErrorList) {
+	var errs field.ErrorList
+	if pool.Service == "" {
+		errs = append(errs, field.Required(poolPath.Child("service"), "the Service of the pool must be set"))
+	}
+	if pool.ServicePort == 0 {
+		errs = append(errs, field.Required(poolPath.Child("servicePort"), "the port of the Service must be set"))
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	backendRefs := []gatewayv1.BackendRef{cv.backendRef(namespace, pool.Service, pool.ServiceNamespace, pool.ServicePort, pool.Weight, routeGVK)}
+	for i, backend := range pool.AlternateBackends {
+		if backend.Service == "" {
+			errs = append(errs, field.Required(poolPath.Child("alternateBackends").Index(i).Child("service"), "the Service of the backend must be set"))
+			continue
+		}
+		backendRefs = append(backendRefs, cv.backendRef(namespace, backend.Service, backend.ServiceNamespace, pool.ServicePort, backend.Weight, routeGVK))
+	}
+	return backendRefs, errs
+}
+
+func (cv *conversion) backendRef(namespace, service, serviceNamespace string, port int32, weight *int32, routeGVK schema.GroupVersionKind) gatewayv1.BackendRef {
+	backendRef := gatewayv1.BackendRef{
+		BackendObjectReference: gatewayv1.BackendObjectReference{
+			Name: gatewayv1.ObjectName(service),
+			Port: ptr.To(gatewayv1.PortNumber(port)),
+		},
+		Weight: weight,
+	}
+	if serviceNamespace != "" && serviceNamespace != namespace {
+		backendRef.Namespace = ptr.To(gatewayv1.Namespace(serviceNamespace))
+		cv.addReferenceGrant(namespace, routeGVK, serviceNamespace, service)
+	}
+	return backendRef
+}
+
+// addReferenceGrant grants the routes of the given kind and namespace the
+// references to the Service of another namespace.
+func (cv *conversion) addReferenceGrant(fromNamespace string, routeGVK schema.GroupVersionKind, serviceNamespace, service string) {
+	key := types.NamespacedName{Namespace: serviceNamespace, Name: fmt.Sprintf("generated-reference-grant-from-%s-to-%s", fromNamespace, serviceNamespace)}
+	referenceGrant, ok := cv.ir.ReferenceGrants[key]
+	if !ok {
+		referenceGrant = gatewayv1beta1.ReferenceGrant{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+		referenceGrant.SetGroupVersionKind(common.ReferenceGrantGVK)
+	}
+	from := gatewayv1beta1.ReferenceGrantFrom{Group: gatewayv1.Group(routeGVK.Group), Kind: gatewayv1.Kind(routeGVK.Kind), Namespace: gatewayv1.Namespace(fromNamespace)}
+	if !slices.Contains(referenceGrant.Spec.From, from) {
+		referenceGrant.Spec.From = append(referenceGrant.Spec.From, from)
+	}
+	to := gatewayv1beta1.ReferenceGrantTo{Group: "", Kind: "Service", Name: ptr.To(gatewayv1.ObjectName(service))}
+	if !slices.ContainsFunc(referenceGrant.Spec.To, func(existing gatewayv1beta1.ReferenceGrantTo) bool {
+		return existing.Kind == to.Kind && *existing.Name == *to.Name
+	}) {
+		referenceGrant.Spec.To = append(referenceGrant.Spec.To, to)
+	}
+	cv.ir.ReferenceGrants[key] = referenceGrant
+}
+
+// gateway returns the Gateway of the given namespace and virtual server
+// address, named after the GatewayClass and the address, creating it if
+// needed.
+func (cv *conversion) gateway(namespace, address, gatewayClassName string) *gatewayv1.Gateway {
+	name := gatewayClassName
+	if address != "" {
+		name = fmt.Sprintf("%s-%s", name, strings.NewReplacer(".", "-", ":", "-").Replace(address))
+	}
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	if gateway, ok := cv.gateways[key]; ok {
+		return gateway
+	}
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: gatewayv1.ObjectName(gatewayClassName)},
+	}
+	if address != "" {
+		gateway.Spec.Addresses = []gatewayv1.GatewayAddress{{Type: ptr.To(gatewayv1.IPAddressType), Value: address}}
+	}
+	gateway.SetGroupVersionKind(common.GatewayGVK)
+	cv.gateways[key] = gateway
+	return gateway
+}
+
+// notifyUnsupportedFeatures notifies about the features of the VirtualServer
+// referencing BIG-IP objects, which have no Gateway API equivalent.
+func notifyUnsupportedFeatures(virtualServer *VirtualServer) {
+	key := types.NamespacedName{Namespace: virtualServer.Namespace, Name: virtualServer.Name}
+	if virtualServer.Spec.WAF != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("the WAF policy %s of VirtualServer %s was not converted, the requests are not inspected", virtualServer.Spec.WAF, key), virtualServer)
+	}
+	if len(virtualServer.Spec.IRules) > 0 {
+		notify(notifications.WarningNotification, fmt.Sprintf("the iRules %v of VirtualServer %s were not converted", virtualServer.Spec.IRules, key), virtualServer)
+	}
+	if virtualServer.Spec.PolicyName != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("the Policy %s of VirtualServer %s was not converted", virtualServer.Spec.PolicyName, key), virtualServer)
+	}
+}
+
+// unsupportedFeatures returns the iRules of the VirtualServer, to be ported
+// manually.
+func unsupportedFeatures(virtualServer *VirtualServer) []intermediate.UnsupportedFeature {
+	var features []intermediate.UnsupportedFeature
+	for _, iRule := range virtualServer.Spec.IRules {
+		features = append(features, intermediate.UnsupportedFeature{
+			SourceKind: VirtualServerKind,
+			Source:     types.NamespacedName{Namespace: virtualServer.Namespace, Name: virtualServer.Name},
+			Name:       "iRules",
+			RawConfig:  iRule,
+		})
+	}
+	return features
+}
+
+// hostListener returns the listener of the given hostname, protocol and
+// port, named after the hostname and the protocol, and suffixed by the port
+// if it isn't the default port of the protocol.
+func hostListener(hostname gatewayv1.Hostname, protocol gatewayv1.ProtocolType, port, defaultPort gatewayv1.PortNumber) gatewayv1.Listener {
+	name := strings.ToLower(string(protocol))
+	listener := gatewayv1.Listener{Port: port, Protocol: protocol}
+	if hostname != "" {
+		name = fmt.Sprintf("%s-%s", common.NameFromHost(string(hostname)), name)
+		listener.Hostname = ptr.To(hostname)
+	}
+	if port != defaultPort {
+		name = fmt.Sprintf("%s-%d", name, port)
+	}
+	listener.Name = gatewayv1.SectionName(name)
+	return listener
+}
+
+// addListener adds the listener to the Gateway, unless it already has a
+// listener of the same name.
+func addListener(gateway *gatewayv1.Gateway, listener gatewayv1.Listener) {
+	if slices.ContainsFunc(gateway.Spec.Listeners, func(existing gatewayv1.Listener) bool { return existing.Name == listener.Name }) {
+		return
+	}
+	gateway.Spec.Listeners = append(gateway.Spec.Listeners, listener)
+}
+
+func commonRouteSpec(gatewayName string, sectionNames []gatewayv1.SectionName) gatewayv1.CommonRouteSpec {
+	var parentRefs []gatewayv1.ParentReference
+	for _, sectionName := range sectionNames {
+		parentRefs = append(parentRefs, gatewayv1.ParentReference{
+			Name:        gatewayv1.ObjectName(gatewayName),
+			SectionName: ptr.To(sectionName),
+		})
+	}
+	return gatewayv1.CommonRouteSpec{ParentRefs: parentRefs}
+}
+
+func portOrDefault(port int32, defaultPort gatewayv1.PortNumber) gatewayv1.PortNumber {
+	if port == 0 {
+		return defaultPort
+	}
+	return gatewayv1.PortNumber(port)
+}
+
+func sortedKeys[T any](m map[types.NamespacedName]T) []types.NamespacedName {
+	keys := make([]types.NamespacedName, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return keys
+}
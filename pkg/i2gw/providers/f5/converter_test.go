@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package f5
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_convertToIR(t *testing.T) {
+	tlsProfiles := []*TLSProfile{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "edge", Namespace: "default"},
+			Spec:       TLSProfileSpec{TLS: TLS{Termination: "edge", Reference: "secret", ClientSSL: "cafe-tls"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "passthrough", Namespace: "default"},
+			Spec:       TLSProfileSpec{TLS: TLS{Termination: "passthrough"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "bigip", Namespace: "default"},
+			Spec:       TLSProfileSpec{TLS: TLS{Termination: "edge", Reference: "bigip", ClientSSL: "/Common/clientssl"}},
+		},
+	}
+
+	testCases := []struct {
+		name               string
+		virtualServers     []*VirtualServer
+		transportServers   []*TransportServer
+		expectedGateway    string
+		expectedListeners  []gatewayv1.SectionName
+		expectedHTTPRoutes []string
+		expectedTCPRoutes  []string
+		expectedUDPRoutes  []string
+		expectedErrors     int
+	}{
+		{
+			name: "virtual server with tls redirecting http",
+			virtualServers: []*VirtualServer{{
+				ObjectMeta: metav1.ObjectMeta{Name: "cafe", Namespace: "default"},
+				Spec: VirtualServerSpec{
+					Host:                 "cafe.example.com",
+					HostAliases:          []string{"www.cafe.example.com"},
+					VirtualServerAddress: "10.0.0.1",
+					TLSProfileName:       "edge",
+					HTTPTraffic:          "redirect",
+					Pools:                []Pool{{Path: "/coffee", Service: "coffee", ServicePort: 80}},
+				},
+			}},
+			expectedGateway:    "f5-10-0-0-1",
+			expectedListeners:  []gatewayv1.SectionName{"cafe-example-com-http", "cafe-example-com-https", "www-cafe-example-com-http", "www-cafe-example-com-https"},
+			expectedHTTPRoutes: []string{"cafe", "cafe-redirect"},
+		},
+		{
+			name: "virtual servers without tls or with unsupported tls profiles",
+			virtualServers: []*VirtualServer{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "default"},
+					Spec:       VirtualServerSpec{Host: "plain.example.com", VirtualServerHTTPPort: 8080, Pools: []Pool{{Service: "plain", ServicePort: 80}}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "bigip", Namespace: "default"},
+					Spec:       VirtualServerSpec{Host: "bigip.example.com", TLSProfileName: "bigip", Pools: []Pool{{Service: "bigip", ServicePort: 80}}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "passthrough", Namespace: "default"},
+					Spec:       VirtualServerSpec{Host: "passthrough.example.com", TLSProfileName: "passthrough", Pools: []Pool{{Service: "passthrough", ServicePort: 443}}},
+				},
+			},
+			expectedGateway:    "f5",
+			expectedListeners:  []gatewayv1.SectionName{"bigip-example-com-http", "plain-example-com-http-8080"},
+			expectedHTTPRoutes: []string{"bigip", "plain"},
+		},
+		{
+			name: "transport servers",
+			transportServers: []*TransportServer{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "default"},
+					Spec:       TransportServerSpec{VirtualServerPort: 5432, Pool: Pool{Service: "postgres", ServicePort: 5432}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "dns", Namespace: "default"},
+					Spec:       TransportServerSpec{VirtualServerPort: 53, Type: "udp", Pool: Pool{Service: "dns", ServicePort: 53}},
+				},
+			},
+			expectedGateway:   "f5",
+			expectedListeners: []gatewayv1.SectionName{"udp-53", "tcp-5432"},
+			expectedTCPRoutes: []string{"postgres"},
+			expectedUDPRoutes: []string{"dns"},
+		},
+		{
+			name: "invalid resources",
+			virtualServers: []*VirtualServer{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "no-service", Namespace: "default"},
+					Spec:       VirtualServerSpec{Host: "a.example.com", Pools: []Pool{{ServicePort: 80}}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "missing-profile", Namespace: "default"},
+					Spec:       VirtualServerSpec{Host: "b.example.com", TLSProfileName: "missing", Pools: []Pool{{Service: "b", ServicePort: 80}}},
+				},
+			},
+			transportServers: []*TransportServer{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "no-port", Namespace: "default"},
+					Spec:       TransportServerSpec{Pool: Pool{Service: "svc", ServicePort: 80}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "sctp", Namespace: "default"},
+					Spec:       TransportServerSpec{VirtualServerPort: 9000, Type: "sctp", Pool: Pool{Service: "svc", ServicePort: 80}},
+				},
+			},
+			expectedGateway:   "f5",
+			expectedListeners: []gatewayv1.SectionName{"a-example-com-http"},
+			expectedErrors:    4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := newResourcesStorage()
+			for _, tlsProfile := range tlsProfiles {
+				storage.TLSProfiles[types.NamespacedName{Namespace: tlsProfile.Namespace, Name: tlsProfile.Name}] = tlsProfile
+			}
+			for _, virtualServer := range tc.virtualServers {
+				storage.VirtualServers[types.NamespacedName{Namespace: virtualServer.Namespace, Name: virtualServer.Name}] = virtualServer
+			}
+			for _, transportServer := range tc.transportServers {
+				storage.TransportServers[types.NamespacedName{Namespace: transportServer.Namespace, Name: transportServer.Name}] = transportServer
+			}
+			converter := newResourcesToIRConverter(&i2gw.ProviderConf{})
+
+			ir, errs := converter.convertToIR(storage)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+
+			var listeners []gatewayv1.SectionName
+			for _, listener := range ir.Gateways[types.NamespacedName{Namespace: "default", Name: tc.expectedGateway}].Spec.Listeners {
+				listeners = append(listeners, listener.Name)
+			}
+			if diff := cmp.Diff(tc.expectedListeners, listeners); diff != "" {
+				t.Errorf("unexpected listeners (-want +got):\n%s", diff)
+			}
+
+			var httpRoutes, tcpRoutes, udpRoutes []string
+			for _, key := range sortedKeys(ir.HTTPRoutes) {
+				httpRoutes = append(httpRoutes, key.Name)
+			}
+			for _, key := range sortedKeys(ir.TCPRoutes) {
+				tcpRoutes = append(tcpRoutes, key.Name)
+			}
+			for _, key := range sortedKeys(ir.UDPRoutes) {
+				udpRoutes = append(udpRoutes, key.Name)
+			}
+			if diff := cmp.Diff(tc.expectedHTTPRoutes, httpRoutes); diff != "" {
+				t.Errorf("unexpected HTTPRoutes (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedTCPRoutes, tcpRoutes); diff != "" {
+				t.Errorf("unexpected TCPRoutes (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedUDPRoutes, udpRoutes); diff != "" {
+				t.Errorf("unexpected UDPRoutes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package f5
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// The ProviderName returned to the provider's registry.
+const ProviderName = "f5"
+
+// GatewayClassFlag is the provider-specific flag setting the GatewayClass of
+// the generated Gateways.
+const GatewayClassFlag = "gateway-class-name"
+
+func init() {
+	i2gw.ProviderConstructorByName[ProviderName] = NewProvider
+	i2gw.RegisterProviderSpecificFlag(ProviderName, i2gw.ProviderSpecificFlag{
+		Name:         GatewayClassFlag,
+		Description:  "The GatewayClass of the Gateways generated for the VirtualServers and TransportServers.",
+		DefaultValue: DefaultGatewayClassName,
+	})
+}
+
+// Provider implements the i2gw.Provider interface for the CRDs of F5
+// BIG-IP Container Ingress Services.
+type Provider struct {
+	storage                *storage
+	reader                 reader
+	resourcesToIRConverter resourcesToIRConverter
+}
+
+// NewProvider constructs and returns the f5 implementation of i2gw.Provider.
+func NewProvider(conf *i2gw.ProviderConf) i2gw.Provider {
+	return &Provider{
+		storage:                newResourcesStorage(),
+		reader:                 newResourceReader(conf),
+		resourcesToIRConverter: newResourcesToIRConverter(conf),
+	}
+}
+
+// ToIR converts the stored VirtualServers and TransportServers to intermediate.IR.
+func (p *Provider) ToIR() (intermediate.IR, field.ErrorList) {
+	return p.resourcesToIRConverter.convertToIR(p.storage)
+}
+
+func (p *Provider) ToGatewayResources(ir intermediate.IR) (i2gw.GatewayResources, field.ErrorList) {
+	return common.ToGatewayResources(ir)
+}
+
+func (p *Provider) ReadResourcesFromCluster(ctx context.Context) error {
+	storage, err := p.reader.readResourcesFromCluster(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read resources from cluster: %w", err)
+	}
+	p.storage = storage
+	return nil
+}
+
+func (p *Provider) ReadResourcesFromFile(_ context.Context, filename string) error {
+	storage, err := p.reader.readResourcesFromFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read resources from file: %w", err)
+	}
+	p.storage = storage
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package f5
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reader implements the i2gw.CustomResourceReader interface.
+type reader struct {
+	conf *i2gw.ProviderConf
+}
+
+// newResourceReader returns a resourceReader instance.
+func newResourceReader(conf *i2gw.ProviderConf) reader {
+	return reader{
+		conf: conf,
+	}
+}
+
+func (r *reader) readResourcesFromCluster(ctx context.Context) (*storage, error) {
+	var objects []*unstructured.Unstructured
+	for _, kind := range []string{VirtualServerKind, TransportServerKind, TLSProfileKind} {
+		list := &unstructured.UnstructuredList{}
+		list.SetAPIVersion(APIVersion)
+		list.SetKind(kind)
+		err := r.conf.Client.List(ctx, list)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list f5 %s objects: %w", kind, err)
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+	}
+	return r.readUnstructuredObjects(objects)
+}
+
+func (r *reader) readResourcesFromFile(filename string) (*storage, error) {
+	stream, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %v: %w", filename, err)
+	}
+	unstructuredObjects, err := common.ExtractObjectsFromReader(bytes.NewReader(stream), r.conf.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract objects: %w", err)
+	}
+	return r.readUnstructuredObjects(unstructuredObjects)
+}
+
+func (r *reader) readUnstructuredObjects(objects []*unstructured.Unstructured) (*storage, error) {
+	res := newResourcesStorage()
+	for _, obj := range objects {
+		if obj.GetAPIVersion() != APIVersion {
+			continue
+		}
+		key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		var err error
+		switch obj.GetKind() {
+		case VirtualServerKind:
+			var virtualServer VirtualServer
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &virtualServer)
+			res.VirtualServers[key] = &virtualServer
+		case TransportServerKind:
+			var transportServer TransportServer
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &transportServer)
+			res.TransportServers[key] = &transportServer
+		case TLSProfileKind:
+			var tlsProfile TLSProfile
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &tlsProfile)
+			res.TLSProfiles[key] = &tlsProfile
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse f5 %s object: %w", obj.GetKind(), err)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package f5
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	APIVersion = "cis.f5.com/v1"
+
+	VirtualServerKind   = "VirtualServer"
+	TransportServerKind = "TransportServer"
+	TLSProfileKind      = "TLSProfile"
+
+	// DefaultGatewayClassName is the name of the GatewayClass of the
+	// generated Gateways.
+	DefaultGatewayClassName = "f5"
+)
+
+// The types below mirror the subset of the cis.f5.com/v1 API read by the
+// provider, so that it doesn't depend on the F5 CIS module.
+
+// VirtualServer routes the HTTP requests of a host to pools of Services.
+type VirtualServer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VirtualServerSpec `json:"spec"`
+}
+
+type VirtualServerSpec struct {
+	Host                   string   `json:"host,omitempty"`
+	HostAliases            []string `json:"hostAliases,omitempty"`
+	VirtualServerAddress   string   `json:"virtualServerAddress,omitempty"`
+	VirtualServerHTTPPort  int32    `json:"virtualServerHTTPPort,omitempty"`
+	VirtualServerHTTPSPort int32    `json:"virtualServerHTTPSPort,omitempty"`
+	TLSProfileName         string   `json:"tlsProfileName,omitempty"`
+	// HTTPTraffic is the handling of HTTP requests of a VirtualServer with
+	// TLS: allow, redirect or none.
+	HTTPTraffic string   `json:"httpTraffic,omitempty"`
+	WAF         string   `json:"waf,omitempty"`
+	IRules      []string `json:"iRules,omitempty"`
+	PolicyName  string   `json:"policyName,omitempty"`
+	Pools       []Pool   `json:"pools,omitempty"`
+}
+
+// Pool is a pool of the endpoints of a Service.
+type Pool struct {
+	Path              string             `json:"path,omitempty"`
+	Service           string             `json:"service"`
+	ServiceNamespace  string             `json:"serviceNamespace,omitempty"`
+	ServicePort       int32              `json:"servicePort"`
+	Weight            *int32             `json:"weight,omitempty"`
+	AlternateBackends []AlternateBackend `json:"alternateBackends,omitempty"`
+	Rewrite           string             `json:"rewrite,omitempty"`
+	HostRewrite       string             `json:"hostRewrite,omitempty"`
+	Monitor           *Monitor           `json:"monitor,omitempty"`
+	WAF               string             `json:"waf,omitempty"`
+}
+
+type AlternateBackend struct {
+	Service          string `json:"service"`
+	ServiceNamespace string `json:"serviceNamespace,omitempty"`
+	Weight           *int32 `json:"weight,omitempty"`
+}
+
+// Monitor is a health monitor of the endpoints of a pool.
+type Monitor struct {
+	Type     string `json:"type,omitempty"`
+	Send     string `json:"send,omitempty"`
+	Recv     string `json:"recv,omitempty"`
+	Interval int    `json:"interval,omitempty"`
+	Timeout  int    `json:"timeout,omitempty"`
+}
+
+// TransportServer forwards the TCP or UDP traffic of a port to a pool.
+type TransportServer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TransportServerSpec `json:"spec"`
+}
+
+type TransportServerSpec struct {
+	VirtualServerAddress string `json:"virtualServerAddress,omitempty"`
+	VirtualServerPort    int32  `json:"virtualServerPort"`
+	// Type is the protocol of the TransportServer, tcp or udp.
+	Type string `json:"type,omitempty"`
+	Pool Pool   `json:"pool"`
+}
+
+// TLSProfile holds the TLS configuration of the VirtualServers referencing
+// it.
+type TLSProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TLSProfileSpec `json:"spec"`
+}
+
+type TLSProfileSpec struct {
+	TLS TLS `json:"tls"`
+}
+
+type TLS struct {
+	// Termination is edge, reencrypt or passthrough.
+	Termination string `json:"termination,omitempty"`
+	// Reference tells whether the SSL profiles are Secrets, secret, or
+	// profiles of the BIG-IP, bigip.
+	Reference  string   `json:"reference,omitempty"`
+	ClientSSL  string   `json:"clientSSL,omitempty"`
+	ClientSSLs []string `json:"clientSSLs,omitempty"`
+	ServerSSL  string   `json:"serverSSL,omitempty"`
+}
+
+// DeepCopyObject implements runtime.Object, for VirtualServers to be
+// referenced by notifications and as the sources of the generated resources.
+func (in *VirtualServer) DeepCopyObject() runtime.Object {
+	out := &VirtualServer{TypeMeta: in.TypeMeta, Spec: in.Spec}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.HostAliases = append([]string(nil), in.Spec.HostAliases...)
+	out.Spec.IRules = append([]string(nil), in.Spec.IRules...)
+	out.Spec.Pools = nil
+	for _, pool := range in.Spec.Pools {
+		out.Spec.Pools = append(out.Spec.Pools, deepCopyPool(pool))
+	}
+	return out
+}
+
+// DeepCopyObject implements runtime.Object, for TransportServers to be
+// referenced by notifications.
+func (in *TransportServer) DeepCopyObject() runtime.Object {
+	out := &TransportServer{TypeMeta: in.TypeMeta, Spec: in.Spec}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.Pool = deepCopyPool(in.Spec.Pool)
+	return out
+}
+
+func deepCopyPool(in Pool) Pool {
+	if in.Weight != nil {
+		weight := *in.Weight
+		in.Weight = &weight
+	}
+	alternateBackends := in.AlternateBackends
+	in.AlternateBackends = nil
+	for _, backend := range alternateBackends {
+		if backend.Weight != nil {
+			weight := *backend.Weight
+			backend.Weight = &weight
+		}
+		in.AlternateBackends = append(in.AlternateBackends, backend)
+	}
+	if in.Monitor != nil {
+		monitor := *in.Monitor
+		in.Monitor = &monitor
+	}
+	return in
+}
@@ -27,6 +27,17 @@ import (
 
 var supportedHcProtocol = sets.NewString("HTTP", "HTTPS", "HTTP2")
 
+// httpsRedirectStatusCodes are the status codes of the responseCodeNames of
+// the HTTPS redirects of FrontendConfigs. An empty name is the default.
+var httpsRedirectStatusCodes = map[string]int{
+	"":                          301,
+	"MOVED_PERMANENTLY_DEFAULT": 301,
+	"FOUND":                     302,
+	"SEE_OTHER":                 303,
+	"TEMPORARY_REDIRECT":        307,
+	"PERMANENT_REDIRECT":        308,
+}
+
 func ValidateBeConfig(beConfig *backendconfigv1.BackendConfig) error {
 	if beConfig.Spec.SessionAffinity != nil {
 		if err := validateSessionAffinity(beConfig); err != nil {
@@ -38,6 +49,20 @@ func ValidateBeConfig(beConfig *backendconfigv1.BackendConfig) error {
 			return err
 		}
 	}
+	if beConfig.Spec.Logging != nil {
+		if err := validateLogging(beConfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ValidateFeConfig(feConfig *frontendconfigv1beta1.FrontendConfig) error {
+	if feConfig.Spec.RedirectToHttps != nil {
+		if _, ok := httpsRedirectStatusCodes[feConfig.Spec.RedirectToHttps.ResponseCodeName]; !ok {
+			return fmt.Errorf("FrontendConfig has an invalid redirectToHttps responseCodeName %q", feConfig.Spec.RedirectToHttps.ResponseCodeName)
+		}
+	}
 	return nil
 }
 
@@ -50,8 +75,10 @@ func validateSessionAffinity(beConfig *backendconfigv1.BackendConfig) error {
 
 func validateHealthCheck(beConfig *backendconfigv1.BackendConfig) error {
 	hcType := beConfig.Spec.HealthCheck.Type
+	// An unspecified type falls back to the protocol of the backend, which is
+	// HTTP unless the Service declares otherwise.
 	if hcType == nil {
-		return fmt.Errorf("HealthCheck Protocol type is not specified")
+		return nil
 	}
 
 	if !supportedHcProtocol.Has(*hcType) {
@@ -60,6 +87,20 @@ func validateHealthCheck(beConfig *backendconfigv1.BackendConfig) error {
 	return nil
 }
 
+func validateLogging(beConfig *backendconfigv1.BackendConfig) error {
+	sampleRate := beConfig.Spec.Logging.SampleRate
+	if sampleRate == nil {
+		return nil
+	}
+	if !beConfig.Spec.Logging.Enable {
+		return fmt.Errorf("BackendConfig has logging sampleRate set, but logging is not enabled")
+	}
+	if *sampleRate < 0 || *sampleRate > 1 {
+		return fmt.Errorf("Logging sampleRate %v is not valid, must be in [0, 1]", *sampleRate)
+	}
+	return nil
+}
+
 func BuildIRSessionAffinityConfig(beConfig *backendconfigv1.BackendConfig) *intermediate.SessionAffinityConfig {
 	return &intermediate.SessionAffinityConfig{
 		AffinityType: beConfig.Spec.SessionAffinity.AffinityType,
@@ -79,6 +120,28 @@ func BuildIRSslPolicyConfig(feConfig *frontendconfigv1beta1.FrontendConfig) *int
 	}
 }
 
+func BuildIRHTTPSRedirectStatusCode(feConfig *frontendconfigv1beta1.FrontendConfig) int {
+	return httpsRedirectStatusCodes[feConfig.Spec.RedirectToHttps.ResponseCodeName]
+}
+
+func BuildIRIAPConfig(beConfig *backendconfigv1.BackendConfig) *intermediate.IAPConfig {
+	iapConfig := intermediate.IAPConfig{
+		Enabled: beConfig.Spec.Iap.Enabled,
+	}
+	if credentials := beConfig.Spec.Iap.OAuthClientCredentials; credentials != nil {
+		iapConfig.ClientID = credentials.ClientID
+		iapConfig.SecretName = credentials.SecretName
+	}
+	return &iapConfig
+}
+
+func BuildIRCDNConfig(beConfig *backendconfigv1.BackendConfig) *intermediate.CDNConfig {
+	return &intermediate.CDNConfig{
+		Enabled:   beConfig.Spec.Cdn.Enabled,
+		CacheMode: beConfig.Spec.Cdn.CacheMode,
+	}
+}
+
 func BuildIRHealthCheckConfig(beConfig *backendconfigv1.BackendConfig) *intermediate.HealthCheckConfig {
 	return &intermediate.HealthCheckConfig{
 		CheckIntervalSec:   beConfig.Spec.HealthCheck.CheckIntervalSec,
@@ -90,3 +153,16 @@ func BuildIRHealthCheckConfig(beConfig *backendconfigv1.BackendConfig) *intermed
 		RequestPath:        beConfig.Spec.HealthCheck.RequestPath,
 	}
 }
+
+func BuildIRConnectionDrainingConfig(beConfig *backendconfigv1.BackendConfig) *intermediate.ConnectionDrainingConfig {
+	return &intermediate.ConnectionDrainingConfig{
+		DrainingTimeoutSec: beConfig.Spec.ConnectionDraining.DrainingTimeoutSec,
+	}
+}
+
+func BuildIRLoggingConfig(beConfig *backendconfigv1.BackendConfig) *intermediate.LoggingConfig {
+	return &intermediate.LoggingConfig{
+		Enable:     beConfig.Spec.Logging.Enable,
+		SampleRate: beConfig.Spec.Logging.SampleRate,
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package extensions
 
 import (
+	"math"
+
 	gkegatewayv1 "github.com/GoogleCloudPlatform/gke-gateway-api/apis/networking/v1"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
 )
@@ -37,6 +39,45 @@ func BuildGCPBackendPolicySecurityPolicyConfig(serviceIR intermediate.ProviderSp
 	return &securityPolicy
 }
 
+func BuildGCPBackendPolicyConnectionDrainingConfig(serviceIR intermediate.ProviderSpecificServiceIR) *gkegatewayv1.ConnectionDraining {
+	drainingTimeoutSec := serviceIR.Gce.ConnectionDraining.DrainingTimeoutSec
+	return &gkegatewayv1.ConnectionDraining{
+		DrainingTimeoutSec: &drainingTimeoutSec,
+	}
+}
+
+// BuildGCPBackendPolicyLoggingConfig converts the sample rate from the
+// [0, 1] proportion used by BackendConfig to the [0, 1e6] range used by
+// GCPBackendPolicy.
+func BuildGCPBackendPolicyLoggingConfig(serviceIR intermediate.ProviderSpecificServiceIR) *gkegatewayv1.LoggingConfig {
+	enabled := serviceIR.Gce.Logging.Enable
+	loggingConfig := gkegatewayv1.LoggingConfig{
+		Enabled: &enabled,
+	}
+	if enabled && serviceIR.Gce.Logging.SampleRate != nil {
+		sampleRate := int32(math.Round(*serviceIR.Gce.Logging.SampleRate * 1e6))
+		loggingConfig.SampleRate = &sampleRate
+	}
+	return &loggingConfig
+}
+
+// BuildGCPBackendPolicyIAPConfig references the OAuth client Secret of the
+// BackendConfig, whose client secret GCPBackendPolicy reads from the key
+// "key" rather than "client_secret".
+func BuildGCPBackendPolicyIAPConfig(serviceIR intermediate.ProviderSpecificServiceIR) *gkegatewayv1.IdentityAwareProxyConfig {
+	enabled := serviceIR.Gce.IAP.Enabled
+	iapConfig := gkegatewayv1.IdentityAwareProxyConfig{
+		Enabled: &enabled,
+	}
+	if clientID := serviceIR.Gce.IAP.ClientID; clientID != "" {
+		iapConfig.ClientID = &clientID
+	}
+	if secretName := serviceIR.Gce.IAP.SecretName; secretName != "" {
+		iapConfig.Oauth2ClientSecret = &gkegatewayv1.Oauth2ClientSecret{Name: &secretName}
+	}
+	return &iapConfig
+}
+
 func BuildGCPGatewayPolicySecurityPolicyConfig(gatewayIR intermediate.ProviderSpecificGatewayIR) string {
 	return gatewayIR.Gce.SslPolicy.Name
 }
@@ -55,7 +96,14 @@ func BuildHealthCheckPolicyConfig(serviceIR intermediate.ProviderSpecificService
 		RequestPath: serviceIR.Gce.HealthCheck.RequestPath,
 	}
 
-	switch *serviceIR.Gce.HealthCheck.Type {
+	// BackendConfig health checks without a type use the protocol of the
+	// backend, which defaults to HTTP.
+	hcType := "HTTP"
+	if serviceIR.Gce.HealthCheck.Type != nil {
+		hcType = *serviceIR.Gce.HealthCheck.Type
+	}
+
+	switch hcType {
 	case "HTTP":
 		hcConfig.Config = &gkegatewayv1.HealthCheck{
 			Type: gkegatewayv1.HTTP,
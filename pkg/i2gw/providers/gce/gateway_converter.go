@@ -120,7 +120,9 @@ func addGCPBackendPolicyIfConfigured(serviceNamespacedName types.NamespacedName,
 		return nil
 	}
 	// If there is no specification related to GCPBackendPolicy feature, return nil.
-	if serviceIR.Gce.SessionAffinity == nil && serviceIR.Gce.SecurityPolicy == nil {
+	if serviceIR.Gce.SessionAffinity == nil && serviceIR.Gce.SecurityPolicy == nil &&
+		serviceIR.Gce.TimeoutSec == nil && serviceIR.Gce.ConnectionDraining == nil && serviceIR.Gce.Logging == nil &&
+		serviceIR.Gce.IAP == nil {
 		return nil
 	}
 
@@ -146,6 +148,18 @@ func addGCPBackendPolicyIfConfigured(serviceNamespacedName types.NamespacedName,
 	if serviceIR.Gce.SecurityPolicy != nil {
 		gcpBackendPolicy.Spec.Default.SecurityPolicy = extensions.BuildGCPBackendPolicySecurityPolicyConfig(serviceIR)
 	}
+	if serviceIR.Gce.TimeoutSec != nil {
+		gcpBackendPolicy.Spec.Default.TimeoutSec = serviceIR.Gce.TimeoutSec
+	}
+	if serviceIR.Gce.ConnectionDraining != nil {
+		gcpBackendPolicy.Spec.Default.ConnectionDraining = extensions.BuildGCPBackendPolicyConnectionDrainingConfig(serviceIR)
+	}
+	if serviceIR.Gce.Logging != nil {
+		gcpBackendPolicy.Spec.Default.Logging = extensions.BuildGCPBackendPolicyLoggingConfig(serviceIR)
+	}
+	if serviceIR.Gce.IAP != nil {
+		gcpBackendPolicy.Spec.Default.IAP = extensions.BuildGCPBackendPolicyIAPConfig(serviceIR)
+	}
 
 	return &gcpBackendPolicy
 }
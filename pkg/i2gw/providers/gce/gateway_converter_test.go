@@ -39,6 +39,7 @@ const (
 	testGatewayName             = "test-gateway"
 	testHTTPRouteName           = "test-http-route"
 	testSaGCPBackendPolicyName  = testServiceName
+	testIAPClientID             = "test-client-id"
 	testSslGCPGatewayPolicyName = testGatewayName
 )
 
@@ -158,6 +159,59 @@ var (
 		},
 	}
 
+	testBackendServiceGCPBackendPolicy = gkegatewayv1.GCPBackendPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.gke.io/v1",
+			Kind:       "GCPBackendPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      testSaGCPBackendPolicyName,
+		},
+		Spec: gkegatewayv1.GCPBackendPolicySpec{
+			Default: &gkegatewayv1.GCPBackendPolicyConfig{
+				TimeoutSec: common.PtrTo(int64(60)),
+				ConnectionDraining: &gkegatewayv1.ConnectionDraining{
+					DrainingTimeoutSec: common.PtrTo(int64(120)),
+				},
+				Logging: &gkegatewayv1.LoggingConfig{
+					Enabled:    common.PtrTo(true),
+					SampleRate: common.PtrTo(int32(500000)),
+				},
+			},
+			TargetRef: v1alpha2.NamespacedPolicyTargetReference{
+				Group: "",
+				Kind:  "Service",
+				Name:  gatewayv1.ObjectName(testServiceName),
+			},
+		},
+	}
+
+	testIAPGCPBackendPolicy = gkegatewayv1.GCPBackendPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.gke.io/v1",
+			Kind:       "GCPBackendPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      testSaGCPBackendPolicyName,
+		},
+		Spec: gkegatewayv1.GCPBackendPolicySpec{
+			Default: &gkegatewayv1.GCPBackendPolicyConfig{
+				IAP: &gkegatewayv1.IdentityAwareProxyConfig{
+					Enabled:            common.PtrTo(true),
+					ClientID:           common.PtrTo(testIAPClientID),
+					Oauth2ClientSecret: &gkegatewayv1.Oauth2ClientSecret{Name: common.PtrTo(testIAPSecretName)},
+				},
+			},
+			TargetRef: v1alpha2.NamespacedPolicyTargetReference{
+				Group: "",
+				Kind:  "Service",
+				Name:  gatewayv1.ObjectName(testServiceName),
+			},
+		},
+	}
+
 	testSslGCPGatewayPolicy = gkegatewayv1.GCPGatewayPolicy{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "networking.gke.io/v1",
@@ -193,6 +247,14 @@ func Test_irToGateway(t *testing.T) {
 	if err != nil {
 		t.Errorf("Failed to generate unstructured GCP Backend Policy with Security Policy feature: %v", err)
 	}
+	testBackendServiceGCPBackendPolicyUnstructured, err := i2gw.CastToUnstructured(&testBackendServiceGCPBackendPolicy)
+	if err != nil {
+		t.Errorf("Failed to generate unstructured GCP Backend Policy with timeout, connection draining and logging features: %v", err)
+	}
+	testIAPGCPBackendPolicyUnstructured, err := i2gw.CastToUnstructured(&testIAPGCPBackendPolicy)
+	if err != nil {
+		t.Errorf("Failed to generate unstructured GCP Backend Policy with IAP feature: %v", err)
+	}
 	testSslGCPGatewayPolicyUnstructured, err := i2gw.CastToUnstructured(&testSslGCPGatewayPolicy)
 	if err != nil {
 		t.Errorf("Failed to generate unstructured GCP Gateway Policy with Ssl Policy feature: %v", err)
@@ -313,6 +375,86 @@ func Test_irToGateway(t *testing.T) {
 			},
 			expectedErrors: field.ErrorList{},
 		},
+		{
+			name: "ingress with a Backend Config specifying timeout, connection draining and logging",
+			ir: intermediate.IR{
+				Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+					{Namespace: testNamespace, Name: testGatewayName}: {
+						Gateway: testGateway,
+					},
+				},
+				HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+					{Namespace: testNamespace, Name: testHTTPRouteName}: {
+						HTTPRoute: testHTTPRoute,
+					},
+				},
+				Services: map[types.NamespacedName]intermediate.ProviderSpecificServiceIR{
+					{Namespace: testNamespace, Name: testServiceName}: {
+						Gce: &intermediate.GceServiceIR{
+							TimeoutSec: common.PtrTo(int64(60)),
+							ConnectionDraining: &intermediate.ConnectionDrainingConfig{
+								DrainingTimeoutSec: 120,
+							},
+							Logging: &intermediate.LoggingConfig{
+								Enable:     true,
+								SampleRate: common.PtrTo(0.5),
+							},
+						},
+					},
+				},
+			},
+			expectedGatewayResources: i2gw.GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					{Namespace: testNamespace, Name: testGatewayName}: testGateway,
+				},
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+					{Namespace: testNamespace, Name: testHTTPRouteName}: testHTTPRoute,
+				},
+				GatewayExtensions: []unstructured.Unstructured{
+					*testBackendServiceGCPBackendPolicyUnstructured,
+				},
+			},
+			expectedErrors: field.ErrorList{},
+		},
+		{
+			name: "ingress with a Backend Config specifying IAP and CDN",
+			ir: intermediate.IR{
+				Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+					{Namespace: testNamespace, Name: testGatewayName}: {
+						Gateway: testGateway,
+					},
+				},
+				HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+					{Namespace: testNamespace, Name: testHTTPRouteName}: {
+						HTTPRoute: testHTTPRoute,
+					},
+				},
+				Services: map[types.NamespacedName]intermediate.ProviderSpecificServiceIR{
+					{Namespace: testNamespace, Name: testServiceName}: {
+						Gce: &intermediate.GceServiceIR{
+							IAP: &intermediate.IAPConfig{
+								Enabled:    true,
+								ClientID:   testIAPClientID,
+								SecretName: testIAPSecretName,
+							},
+							CDN: &intermediate.CDNConfig{Enabled: true},
+						},
+					},
+				},
+			},
+			expectedGatewayResources: i2gw.GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					{Namespace: testNamespace, Name: testGatewayName}: testGateway,
+				},
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+					{Namespace: testNamespace, Name: testHTTPRouteName}: testHTTPRoute,
+				},
+				GatewayExtensions: []unstructured.Unstructured{
+					*testIAPGCPBackendPolicyUnstructured,
+				},
+			},
+			expectedErrors: field.ErrorList{},
+		},
 		{
 			name: "ingress with a Frontend Config specifying Ssl Policy",
 			ir: intermediate.IR{
@@ -26,12 +26,22 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
 	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 const ProviderName = "gce"
 
 func init() {
 	i2gw.ProviderConstructorByName[ProviderName] = NewProvider
+	i2gw.RegisterProviderSupportLevel(ProviderName, i2gw.StableSupportLevel)
+	i2gw.RegisterIngressClaimRule(ProviderName, i2gw.IngressClaimRule{
+		IngressClasses:     supportedGCEIngressClasses,
+		AnnotationPrefixes: []string{"ingress.gcp.kubernetes.io/", "networking.gke.io/", "kubernetes.io/ingress.global-static-ip-name", "kubernetes.io/ingress.regional-static-ip-name"},
+	})
+	// The global external Application Load Balancers only serve HTTP on port
+	// 80, and HTTPS on port 443.
+	i2gw.ListenerPortsByGatewayClass[gceL7GlobalExternalManagedGatewayClass] = []gatewayv1.PortNumber{80, 443}
+	i2gw.ListenerPortsByGatewayClass[gceL7GlobalExternalManagedMCGatewayClass] = []gatewayv1.PortNumber{80, 443}
 }
 
 // Provider implements the i2gw.Provider interface.
@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// buildHTTPSRedirects converts the redirectToHttps of the FrontendConfigs,
+// which redirects the HTTP requests of the load balancer to HTTPS. The
+// HTTPRoutes of the Gateways redirecting to HTTPS are attached to the HTTPS
+// listeners of their hosts, and an HTTPRoute named <gateway>-https-redirect
+// redirecting all the requests to HTTPS is attached to the HTTP listeners.
+func buildHTTPSRedirects(ir *intermediate.IR) {
+	gatewayKeys := make([]types.NamespacedName, 0, len(ir.Gateways))
+	for key, gatewayContext := range ir.Gateways {
+		if gce := gatewayContext.ProviderSpecificIR.Gce; gce != nil && gce.EnableHTTPSRedirect && gatewayContext.Name != "" {
+			gatewayKeys = append(gatewayKeys, key)
+		}
+	}
+	if len(gatewayKeys) == 0 {
+		return
+	}
+	slices.SortFunc(gatewayKeys, compareNamespacedNames)
+
+	routeKeys := make([]types.NamespacedName, 0, len(ir.HTTPRoutes))
+	for key := range ir.HTTPRoutes {
+		routeKeys = append(routeKeys, key)
+	}
+	slices.SortFunc(routeKeys, compareNamespacedNames)
+
+	for _, gatewayKey := range gatewayKeys {
+		gatewayContext := ir.Gateways[gatewayKey]
+		var redirectedListeners []gatewayv1.SectionName
+		var sources []client.Object
+		for _, routeKey := range routeKeys {
+			httpRouteContext := ir.HTTPRoutes[routeKey]
+			var hostname gatewayv1.Hostname
+			if len(httpRouteContext.Spec.Hostnames) > 0 {
+				hostname = httpRouteContext.Spec.Hostnames[0]
+			}
+			for i, parentRef := range httpRouteContext.Spec.ParentRefs {
+				if !refersToGateway(routeKey.Namespace, parentRef, gatewayKey) || parentRef.SectionName != nil {
+					continue
+				}
+				httpListener, httpsListener := hostListeners(gatewayContext.Gateway, hostname)
+				if httpsListener == nil {
+					notify(notifications.WarningNotification, fmt.Sprintf("Gateway %s redirects HTTP requests to HTTPS, but has no HTTPS listener for the hosts of HTTPRoute %s, its requests are not redirected", gatewayKey, routeKey), &httpRouteContext.HTTPRoute)
+					continue
+				}
+				httpRouteContext.Spec.ParentRefs[i].SectionName = httpsListener
+				if httpListener != nil && !slices.Contains(redirectedListeners, *httpListener) {
+					redirectedListeners = append(redirectedListeners, *httpListener)
+				}
+				for _, source := range httpRouteContext.Sources {
+					if !slices.Contains(sources, source) {
+						sources = append(sources, source)
+					}
+				}
+			}
+			ir.HTTPRoutes[routeKey] = httpRouteContext
+		}
+		if len(redirectedListeners) == 0 {
+			continue
+		}
+
+		redirectContext := httpsRedirectHTTPRoute(gatewayKey, redirectedListeners, httpsRedirectStatusCode(gatewayContext))
+		redirectContext.Sources = sources
+		redirectKey := types.NamespacedName{Namespace: redirectContext.Namespace, Name: redirectContext.Name}
+		ir.HTTPRoutes[redirectKey] = redirectContext
+		notify(notifications.InfoNotification, fmt.Sprintf("converted the HTTPS redirect of the FrontendConfig of Gateway %s to HTTPRoute %s", gatewayKey, redirectKey), &redirectContext.HTTPRoute)
+	}
+}
+
+// httpsRedirectStatusCode returns the status code of the HTTPS redirect of
+// the Gateway. Gateway API only supports 301 and 302 redirects, the other
+// codes are replaced by the one of the same permanence.
+func httpsRedirectStatusCode(gatewayContext intermediate.GatewayContext) int {
+	statusCode := gatewayContext.ProviderSpecificIR.Gce.HTTPSRedirectStatusCode
+	switch statusCode {
+	case 0:
+		return 301
+	case 301, 302:
+		return statusCode
+	case 308:
+		notify(notifications.WarningNotification, fmt.Sprintf("Gateway %s redirects HTTP requests to HTTPS with a 308, which Gateway API doesn't support, a 301 is used instead; clients may change the method of the redirected requests", gatewayContext.Name), &gatewayContext.Gateway)
+		return 301
+	default:
+		notify(notifications.WarningNotification, fmt.Sprintf("Gateway %s redirects HTTP requests to HTTPS with a %d, which Gateway API doesn't support, a 302 is used instead; clients may change the method of the redirected requests", gatewayContext.Name, statusCode), &gatewayContext.Gateway)
+		return 302
+	}
+}
+
+// httpsRedirectHTTPRoute returns the HTTPRoute redirecting all the requests
+// of the given HTTP listeners of the Gateway to HTTPS.
+func httpsRedirectHTTPRoute(gatewayKey types.NamespacedName, httpListeners []gatewayv1.SectionName, statusCode int) intermediate.HTTPRouteContext {
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: gatewayKey.Namespace,
+			Name:      fmt.Sprintf("%s-https-redirect", gatewayKey.Name),
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Rules: []gatewayv1.HTTPRouteRule{{
+				Filters: []gatewayv1.HTTPRouteFilter{{
+					Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+					RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+						Scheme:     ptr.To("https"),
+						StatusCode: ptr.To(statusCode),
+					},
+				}},
+			}},
+		},
+		Status: gatewayv1.HTTPRouteStatus{
+			RouteStatus: gatewayv1.RouteStatus{
+				Parents: []gatewayv1.RouteParentStatus{},
+			},
+		},
+	}
+	httpRoute.SetGroupVersionKind(common.HTTPRouteGVK)
+	for _, listener := range httpListeners {
+		httpRoute.Spec.ParentRefs = append(httpRoute.Spec.ParentRefs, gatewayv1.ParentReference{
+			Name:        gatewayv1.ObjectName(gatewayKey.Name),
+			SectionName: ptr.To(listener),
+		})
+	}
+	return intermediate.HTTPRouteContext{HTTPRoute: httpRoute}
+}
+
+// hostListeners returns the names of the HTTP and HTTPS listeners of the
+// hostname on the Gateway.
+func hostListeners(gateway gatewayv1.Gateway, hostname gatewayv1.Hostname) (httpListener, httpsListener *gatewayv1.SectionName) {
+	for _, listener := range gateway.Spec.Listeners {
+		if ptr.Deref(listener.Hostname, "") != hostname {
+			continue
+		}
+		switch listener.Protocol {
+		case gatewayv1.HTTPProtocolType:
+			httpListener = ptr.To(listener.Name)
+		case gatewayv1.HTTPSProtocolType:
+			httpsListener = ptr.To(listener.Name)
+		}
+	}
+	return httpListener, httpsListener
+}
+
+// refersToGateway returns whether the parentRef of a route of the namespace
+// refers to the Gateway.
+func refersToGateway(routeNamespace string, parentRef gatewayv1.ParentReference, gatewayKey types.NamespacedName) bool {
+	if parentRef.Kind != nil && *parentRef.Kind != "Gateway" {
+		return false
+	}
+	namespace := routeNamespace
+	if parentRef.Namespace != nil {
+		namespace = string(*parentRef.Namespace)
+	}
+	return namespace == gatewayKey.Namespace && string(parentRef.Name) == gatewayKey.Name
+}
+
+func compareNamespacedNames(a, b types.NamespacedName) int {
+	return strings.Compare(a.String(), b.String())
+}
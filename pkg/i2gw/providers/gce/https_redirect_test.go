@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/gce/extensions"
+	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
+)
+
+func Test_httpsRedirectStatusCode(t *testing.T) {
+	testCases := []struct {
+		responseCodeName   string
+		expectedStatusCode int
+	}{
+		{responseCodeName: "", expectedStatusCode: 301},
+		{responseCodeName: "MOVED_PERMANENTLY_DEFAULT", expectedStatusCode: 301},
+		{responseCodeName: "FOUND", expectedStatusCode: 302},
+		{responseCodeName: "SEE_OTHER", expectedStatusCode: 302},
+		{responseCodeName: "TEMPORARY_REDIRECT", expectedStatusCode: 302},
+		{responseCodeName: "PERMANENT_REDIRECT", expectedStatusCode: 301},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.responseCodeName, func(t *testing.T) {
+			feConfig := getTestFrontendConfig(testNamespace, testFrontendConfigName, frontendconfigv1beta1.FrontendConfigSpec{
+				RedirectToHttps: &frontendconfigv1beta1.HttpsRedirectConfig{Enabled: true, ResponseCodeName: tc.responseCodeName},
+			})
+			if err := extensions.ValidateFeConfig(feConfig); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			gceGatewayIR := feConfigToGceGatewayIR(feConfig)
+			gatewayContext := intermediate.GatewayContext{ProviderSpecificIR: intermediate.ProviderSpecificGatewayIR{Gce: &gceGatewayIR}}
+			if statusCode := httpsRedirectStatusCode(gatewayContext); statusCode != tc.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tc.expectedStatusCode, statusCode)
+			}
+		})
+	}
+
+	feConfig := getTestFrontendConfig(testNamespace, testFrontendConfigName, frontendconfigv1beta1.FrontendConfigSpec{
+		RedirectToHttps: &frontendconfigv1beta1.HttpsRedirectConfig{Enabled: true, ResponseCodeName: "MOVED"},
+	})
+	if err := extensions.ValidateFeConfig(feConfig); err == nil {
+		t.Errorf("Expected an error for an invalid responseCodeName")
+	}
+}
@@ -18,6 +18,7 @@ package gce
 
 import (
 	"context"
+	"fmt"
 
 	"encoding/json"
 
@@ -55,6 +56,7 @@ func newResourcesToIRConverter(conf *i2gw.ProviderConf) resourcesToIRConverter {
 		conf: conf,
 		implementationSpecificOptions: i2gw.ProviderImplementationSpecificOptions{
 			ToImplementationSpecificHTTPPathTypeMatch: implementationSpecificHTTPPathTypeMatch,
+			DisableRouteMerging:                       !conf.Profile.RouteMerging,
 		},
 		ctx: context.Background(),
 	}
@@ -83,7 +85,13 @@ func (c *resourcesToIRConverter) convertToIR(storage *storage) (intermediate.IR,
 	if len(errs) > 0 {
 		return intermediate.IR{}, errs
 	}
+	buildSSLCertificates(ingressList, storage.ManagedCertificates, &ir)
+	errs = multiClusterIngressesToIR(storage, c.implementationSpecificOptions, &ir)
+	if len(errs) > 0 {
+		return intermediate.IR{}, errs
+	}
 	buildGceGatewayIR(c.ctx, storage, &ir)
+	buildHTTPSRedirects(&ir)
 	buildGceServiceIR(c.ctx, storage, &ir)
 	return ir, errs
 }
@@ -98,6 +106,10 @@ func buildGceGatewayIR(ctx context.Context, storage *storage, ir *intermediate.I
 		if feConfig == nil {
 			continue
 		}
+		if err := extensions.ValidateFeConfig(feConfig); err != nil {
+			notify(notifications.ErrorNotification, err.Error(), feConfig)
+			continue
+		}
 		gceGatewayIR := feConfigToGceGatewayIR(feConfig)
 		gateways := feConfigToGwys[feConfigKey]
 
@@ -145,6 +157,10 @@ func feConfigToGceGatewayIR(feConfig *frontendconfigv1beta1.FrontendConfig) inte
 	if feConfig.Spec.SslPolicy != nil {
 		gceGatewayIR.SslPolicy = extensions.BuildIRSslPolicyConfig(feConfig)
 	}
+	if feConfig.Spec.RedirectToHttps != nil && feConfig.Spec.RedirectToHttps.Enabled {
+		gceGatewayIR.EnableHTTPSRedirect = true
+		gceGatewayIR.HTTPSRedirectStatusCode = extensions.BuildIRHTTPSRedirectStatusCode(feConfig)
+	}
 	return gceGatewayIR
 }
 
@@ -165,6 +181,7 @@ func buildGceServiceIR(ctx context.Context, storage *storage, ir *intermediate.I
 			continue
 		}
 		gceServiceIR := beConfigToGceServiceIR(beConfig)
+		notifyUnconvertedBackendConfig(beConfig)
 		services := beConfigToSvcs[beConfigKey]
 		for _, svcKey := range services {
 			serviceIR := ir.Services[svcKey]
@@ -258,6 +275,31 @@ func parseBackendConfigName(ctx context.Context, val string) (string, bool) {
 	return configs.Default, true
 }
 
+// notifyUnconvertedBackendConfig warns about the BackendConfig features GKE
+// Gateway doesn't support, or supports differently.
+func notifyUnconvertedBackendConfig(beConfig *backendconfigv1.BackendConfig) {
+	if cdn := beConfig.Spec.Cdn; cdn != nil && cdn.Enabled {
+		notify(notifications.WarningNotification, "Cloud CDN has no GKE Gateway equivalent and was not converted, the responses of the Service are no longer cached by the load balancer", beConfig)
+	}
+	iap := beConfig.Spec.Iap
+	if iap == nil || !iap.Enabled {
+		return
+	}
+	credentials := iap.OAuthClientCredentials
+	if credentials == nil || credentials.SecretName == "" {
+		notify(notifications.WarningNotification, "IAP is enabled without an OAuth client Secret, GCPBackendPolicy requires the clientID and the Secret holding the client secret", beConfig)
+		return
+	}
+	if credentials.ClientSecret != "" {
+		notify(notifications.WarningNotification, "the clientSecret of the IAP OAuth client credentials was not converted, GCPBackendPolicy reads it from the Secret", beConfig)
+	}
+	msg := fmt.Sprintf("GCPBackendPolicy reads the IAP OAuth client secret from the key \"key\" of Secret %s/%s, rather than \"client_secret\"", beConfig.Namespace, credentials.SecretName)
+	if credentials.ClientID == "" {
+		msg += ", and requires the clientID, which is in its \"client_id\" key: set the clientID of the generated GCPBackendPolicy"
+	}
+	notify(notifications.WarningNotification, msg, beConfig)
+}
+
 func beConfigToGceServiceIR(beConfig *backendconfigv1.BackendConfig) intermediate.GceServiceIR {
 	var gceServiceIR intermediate.GceServiceIR
 	if beConfig.Spec.SessionAffinity != nil {
@@ -269,6 +311,21 @@ func beConfigToGceServiceIR(beConfig *backendconfigv1.BackendConfig) intermediat
 	if beConfig.Spec.HealthCheck != nil {
 		gceServiceIR.HealthCheck = extensions.BuildIRHealthCheckConfig(beConfig)
 	}
+	if beConfig.Spec.TimeoutSec != nil {
+		gceServiceIR.TimeoutSec = beConfig.Spec.TimeoutSec
+	}
+	if beConfig.Spec.ConnectionDraining != nil {
+		gceServiceIR.ConnectionDraining = extensions.BuildIRConnectionDrainingConfig(beConfig)
+	}
+	if beConfig.Spec.Logging != nil {
+		gceServiceIR.Logging = extensions.BuildIRLoggingConfig(beConfig)
+	}
+	if beConfig.Spec.Iap != nil {
+		gceServiceIR.IAP = extensions.BuildIRIAPConfig(beConfig)
+	}
+	if beConfig.Spec.Cdn != nil {
+		gceServiceIR.CDN = extensions.BuildIRCDNConfig(beConfig)
+	}
 
 	return gceServiceIR
 }
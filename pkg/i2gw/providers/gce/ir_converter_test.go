@@ -53,6 +53,8 @@ const (
 	testServiceName        = "test-service"
 	testBackendConfigName  = "test-backendconfig"
 	testFrontendConfigName = "test-frontendconfig"
+	testSecretName         = "test-secret"
+	testIAPSecretName      = "test-iap-secret"
 	testSecurityPolicy     = "test-security-policy"
 	testCookieTTLSec       = int64(10)
 	testSslPolicy          = "test-ssl-policy"
@@ -715,6 +717,100 @@ func Test_convertToIR(t *testing.T) {
 			},
 			expectedErrors: field.ErrorList{},
 		},
+		{
+			name: "ingress with a Backend Config specifying IAP and CDN",
+			modify: func(storage *storage) {
+				testService := storage.Services[types.NamespacedName{Namespace: testNamespace, Name: testServiceName}]
+				testService.Annotations = map[string]string{
+					backendConfigKey: `{"default":"test-backendconfig"}`,
+				}
+				storage.Services[types.NamespacedName{Namespace: testNamespace, Name: testServiceName}] = testService
+
+				beConfigSpec := backendconfigv1.BackendConfigSpec{
+					Iap: &backendconfigv1.IAPConfig{
+						Enabled:                true,
+						OAuthClientCredentials: &backendconfigv1.OAuthClientCredentials{SecretName: testIAPSecretName},
+					},
+					Cdn: &backendconfigv1.CDNConfig{
+						Enabled:   true,
+						CacheMode: common.PtrTo("CACHE_ALL_STATIC"),
+					},
+				}
+				storage.BackendConfigs = map[types.NamespacedName]*backendconfigv1.BackendConfig{
+					{Namespace: testNamespace, Name: testBackendConfigName}: getTestBackendConfig(beConfigSpec),
+				}
+			},
+			expectedIR: intermediate.IR{
+				Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+					{Namespace: testNamespace, Name: gceIngressClass}: {
+						Gateway: gatewayv1.Gateway{
+							ObjectMeta: metav1.ObjectMeta{Name: gceIngressClass, Namespace: testNamespace},
+							Spec: gatewayv1.GatewaySpec{
+								GatewayClassName: gceL7GlobalExternalManagedGatewayClass,
+								Listeners: []gatewayv1.Listener{{
+									Name:     "test-mydomain-com-http",
+									Port:     80,
+									Protocol: gatewayv1.HTTPProtocolType,
+									Hostname: common.PtrTo(gatewayv1.Hostname(testHost)),
+								}},
+							},
+						},
+					},
+				},
+				HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+					{Namespace: testNamespace, Name: fmt.Sprintf("%s-test-mydomain-com", testIngressName)}: {
+						HTTPRoute: gatewayv1.HTTPRoute{
+							ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-test-mydomain-com", testIngressName), Namespace: testNamespace},
+							Spec: gatewayv1.HTTPRouteSpec{
+								CommonRouteSpec: gatewayv1.CommonRouteSpec{
+									ParentRefs: []gatewayv1.ParentReference{{
+										Name: gceIngressClass,
+									}},
+								},
+								Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(testHost)},
+								Rules: []gatewayv1.HTTPRouteRule{
+									{
+										Matches: []gatewayv1.HTTPRouteMatch{
+											{
+												Path: &gatewayv1.HTTPPathMatch{
+													Type:  common.PtrTo(gPathPrefix),
+													Value: common.PtrTo("/"),
+												},
+											},
+										},
+										BackendRefs: []gatewayv1.HTTPBackendRef{
+											{
+												BackendRef: gatewayv1.BackendRef{
+													BackendObjectReference: gatewayv1.BackendObjectReference{
+														Name: gatewayv1.ObjectName(testServiceName),
+														Port: common.PtrTo(gatewayv1.PortNumber(80)),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				Services: map[types.NamespacedName]intermediate.ProviderSpecificServiceIR{
+					{Namespace: testNamespace, Name: testServiceName}: {
+						Gce: &intermediate.GceServiceIR{
+							IAP: &intermediate.IAPConfig{
+								Enabled:    true,
+								SecretName: testIAPSecretName,
+							},
+							CDN: &intermediate.CDNConfig{
+								Enabled:   true,
+								CacheMode: common.PtrTo("CACHE_ALL_STATIC"),
+							},
+						},
+					},
+				},
+			},
+			expectedErrors: field.ErrorList{},
+		},
 		{
 			name: "ingress with a Backend Config specifying custom HTTP Health Check",
 			modify: func(storage *storage) {
@@ -890,6 +986,112 @@ func Test_convertToIR(t *testing.T) {
 			},
 			expectedErrors: field.ErrorList{},
 		},
+		{
+			name: "ingress with a Frontend Config redirecting to HTTPS",
+			modify: func(storage *storage) {
+				testIngress := storage.Ingresses[types.NamespacedName{Namespace: testNamespace, Name: testIngressName}]
+				testIngress.Annotations[frontendConfigKey] = testFrontendConfigName
+				testIngress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{testHost}, SecretName: testSecretName}}
+				storage.Ingresses[types.NamespacedName{Namespace: testNamespace, Name: testIngressName}] = testIngress
+
+				feConfigSpec := frontendconfigv1beta1.FrontendConfigSpec{
+					RedirectToHttps: &frontendconfigv1beta1.HttpsRedirectConfig{Enabled: true, ResponseCodeName: "FOUND"},
+				}
+				storage.FrontendConfigs = map[types.NamespacedName]*frontendconfigv1beta1.FrontendConfig{
+					{Namespace: testNamespace, Name: testFrontendConfigName}: getTestFrontendConfig(testNamespace, testFrontendConfigName, feConfigSpec),
+				}
+			},
+			expectedIR: intermediate.IR{
+				Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+					{Namespace: testNamespace, Name: gceIngressClass}: {
+						Gateway: gatewayv1.Gateway{
+							ObjectMeta: metav1.ObjectMeta{Name: gceIngressClass, Namespace: testNamespace},
+							Spec: gatewayv1.GatewaySpec{
+								GatewayClassName: gceL7GlobalExternalManagedGatewayClass,
+								Listeners: []gatewayv1.Listener{
+									{
+										Name:     "test-mydomain-com-http",
+										Port:     80,
+										Protocol: gatewayv1.HTTPProtocolType,
+										Hostname: common.PtrTo(gatewayv1.Hostname(testHost)),
+									},
+									{
+										Name:     "test-mydomain-com-https",
+										Port:     443,
+										Protocol: gatewayv1.HTTPSProtocolType,
+										Hostname: common.PtrTo(gatewayv1.Hostname(testHost)),
+										TLS: &gatewayv1.GatewayTLSConfig{
+											CertificateRefs: []gatewayv1.SecretObjectReference{{Name: testSecretName}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+					{Namespace: testNamespace, Name: fmt.Sprintf("%s-test-mydomain-com", testIngressName)}: {
+						HTTPRoute: gatewayv1.HTTPRoute{
+							ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-test-mydomain-com", testIngressName), Namespace: testNamespace},
+							Spec: gatewayv1.HTTPRouteSpec{
+								CommonRouteSpec: gatewayv1.CommonRouteSpec{
+									ParentRefs: []gatewayv1.ParentReference{{
+										Name:        gceIngressClass,
+										SectionName: common.PtrTo(gatewayv1.SectionName("test-mydomain-com-https")),
+									}},
+								},
+								Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(testHost)},
+								Rules: []gatewayv1.HTTPRouteRule{
+									{
+										Matches: []gatewayv1.HTTPRouteMatch{
+											{
+												Path: &gatewayv1.HTTPPathMatch{
+													Type:  common.PtrTo(gPathPrefix),
+													Value: common.PtrTo("/"),
+												},
+											},
+										},
+										BackendRefs: []gatewayv1.HTTPBackendRef{
+											{
+												BackendRef: gatewayv1.BackendRef{
+													BackendObjectReference: gatewayv1.BackendObjectReference{
+														Name: gatewayv1.ObjectName(testServiceName),
+														Port: common.PtrTo(gatewayv1.PortNumber(80)),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					{Namespace: testNamespace, Name: fmt.Sprintf("%s-https-redirect", gceIngressClass)}: {
+						HTTPRoute: gatewayv1.HTTPRoute{
+							ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-https-redirect", gceIngressClass), Namespace: testNamespace},
+							Spec: gatewayv1.HTTPRouteSpec{
+								CommonRouteSpec: gatewayv1.CommonRouteSpec{
+									ParentRefs: []gatewayv1.ParentReference{{
+										Name:        gceIngressClass,
+										SectionName: common.PtrTo(gatewayv1.SectionName("test-mydomain-com-http")),
+									}},
+								},
+								Rules: []gatewayv1.HTTPRouteRule{{
+									Filters: []gatewayv1.HTTPRouteFilter{{
+										Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+										RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+											Scheme:     common.PtrTo("https"),
+											StatusCode: common.PtrTo(302),
+										},
+									}},
+								}},
+							},
+						},
+					},
+				},
+			},
+			expectedErrors: field.ErrorList{},
+		},
 	}
 
 	for _, tc := range testCases {
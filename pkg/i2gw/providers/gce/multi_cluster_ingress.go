@@ -0,0 +1,350 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The types below mirror the subset of the networking.gke.io/v1
+// MultiClusterIngress and MultiClusterService APIs read by the provider,
+// whose API types are not vendored.
+
+// multiClusterIngress load balances the traffic of its hosts across the
+// clusters of a fleet, from the config cluster.
+type multiClusterIngress struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec multiClusterIngressSpec `json:"spec"`
+}
+
+type multiClusterIngressSpec struct {
+	Template struct {
+		Spec multiClusterIngressTemplateSpec `json:"spec"`
+	} `json:"template"`
+}
+
+type multiClusterIngressTemplateSpec struct {
+	Backend *multiClusterIngressBackend `json:"backend,omitempty"`
+	Rules   []multiClusterIngressRule   `json:"rules,omitempty"`
+	TLS     []networkingv1.IngressTLS   `json:"tls,omitempty"`
+}
+
+type multiClusterIngressRule struct {
+	Host string `json:"host,omitempty"`
+	HTTP *struct {
+		Paths []multiClusterIngressPath `json:"paths"`
+	} `json:"http,omitempty"`
+}
+
+type multiClusterIngressPath struct {
+	Path    string                     `json:"path,omitempty"`
+	Backend multiClusterIngressBackend `json:"backend"`
+}
+
+// multiClusterIngressBackend references a MultiClusterService of the
+// namespace of the MultiClusterIngress.
+type multiClusterIngressBackend struct {
+	ServiceName string             `json:"serviceName"`
+	ServicePort intstr.IntOrString `json:"servicePort"`
+}
+
+// multiClusterService derives the Services of the clusters of a fleet the
+// MultiClusterIngresses route to.
+type multiClusterService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec multiClusterServiceSpec `json:"spec"`
+}
+
+type multiClusterServiceSpec struct {
+	Template struct {
+		Spec struct {
+			Ports []apiv1.ServicePort `json:"ports,omitempty"`
+		} `json:"spec"`
+	} `json:"template"`
+	// Clusters are the clusters the Services are derived in, all the clusters
+	// of the fleet if empty.
+	Clusters []struct {
+		Link string `json:"link"`
+	} `json:"clusters,omitempty"`
+}
+
+// DeepCopyObject implements runtime.Object, for MultiClusterIngresses to be
+// referenced by notifications and as the sources of the generated resources.
+func (in *multiClusterIngress) DeepCopyObject() runtime.Object {
+	out := &multiClusterIngress{TypeMeta: in.TypeMeta}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec.Template.Spec.Backend != nil {
+		out.Spec.Template.Spec.Backend = ptr.To(*in.Spec.Template.Spec.Backend)
+	}
+	for _, rule := range in.Spec.Template.Spec.Rules {
+		if rule.HTTP != nil {
+			http := *rule.HTTP
+			http.Paths = slices.Clone(rule.HTTP.Paths)
+			rule.HTTP = &http
+		}
+		out.Spec.Template.Spec.Rules = append(out.Spec.Template.Spec.Rules, rule)
+	}
+	for _, tls := range in.Spec.Template.Spec.TLS {
+		out.Spec.Template.Spec.TLS = append(out.Spec.Template.Spec.TLS, *tls.DeepCopy())
+	}
+	return out
+}
+
+// DeepCopyObject implements runtime.Object, for MultiClusterServices to be
+// referenced by notifications.
+func (in *multiClusterService) DeepCopyObject() runtime.Object {
+	out := &multiClusterService{TypeMeta: in.TypeMeta}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	for _, port := range in.Spec.Template.Spec.Ports {
+		out.Spec.Template.Spec.Ports = append(out.Spec.Template.Spec.Ports, *port.DeepCopy())
+	}
+	out.Spec.Clusters = slices.Clone(in.Spec.Clusters)
+	return out
+}
+
+// multiClusterIngressesToIR converts the MultiClusterIngresses to the GKE
+// multi-cluster Gateway model. They are converted as Ingresses of the
+// gke-l7-global-external-managed-mc class, served by the Gateway of that name
+// of their namespace, whose HTTPRoutes reference the ServiceImports of their
+// MultiClusterServices: the Services the MultiClusterServices select have to
+// be exported from the clusters of the fleet with ServiceExports of the same
+// name, which GKE imports in the fleet.
+//   - The networking.gke.io/static-ip annotation becomes an address of the
+//     Gateway.
+//   - The networking.gke.io/pre-shared-certs annotation references the SSL
+//     certificates with the networking.gke.io/pre-shared-certs TLS option of
+//     the HTTPS listeners of the hosts.
+func multiClusterIngressesToIR(storage *storage, options i2gw.ProviderImplementationSpecificOptions, ir *intermediate.IR) field.ErrorList {
+	var errs field.ErrorList
+	keys := make([]types.NamespacedName, 0, len(storage.MultiClusterIngresses))
+	for key := range storage.MultiClusterIngresses {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, compareNamespacedNames)
+
+	var ingresses []networkingv1.Ingress
+	ingressesByKey := map[types.NamespacedName]networkingv1.Ingress{}
+	referencedServices := map[types.NamespacedName][]*multiClusterIngress{}
+	for _, key := range keys {
+		mci := storage.MultiClusterIngresses[key]
+		ingress, services, ingressErrs := multiClusterIngressToIngress(mci, storage.MultiClusterServices)
+		errs = append(errs, ingressErrs...)
+		ingresses = append(ingresses, ingress)
+		ingressesByKey[key] = ingress
+		for _, service := range services {
+			referencedServices[service] = append(referencedServices[service], mci)
+		}
+	}
+	if len(errs) > 0 || len(ingresses) == 0 {
+		return errs
+	}
+
+	mciIR, errs := common.ToIR(ingresses, options)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	for key, httpRouteContext := range mciIR.HTTPRoutes {
+		if _, ok := ir.HTTPRoutes[key]; ok {
+			errs = append(errs, field.Duplicate(field.NewPath(MultiClusterIngressGVK.Kind), key.String()))
+			continue
+		}
+		var sources []client.Object
+		for _, source := range httpRouteContext.Sources {
+			if mci, ok := storage.MultiClusterIngresses[types.NamespacedName{Namespace: source.GetNamespace(), Name: source.GetName()}]; ok {
+				sources = append(sources, mci)
+			}
+		}
+		httpRouteContext.Sources = sources
+		for i := range httpRouteContext.Spec.Rules {
+			for j := range httpRouteContext.Spec.Rules[i].BackendRefs {
+				backendRef := &httpRouteContext.Spec.Rules[i].BackendRefs[j]
+				backendRef.Group = ptr.To(gatewayv1.Group(ServiceImportGVK.Group))
+				backendRef.Kind = ptr.To(gatewayv1.Kind(ServiceImportGVK.Kind))
+			}
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+
+	for _, key := range keys {
+		mci := storage.MultiClusterIngresses[key]
+		gatewayKey := types.NamespacedName{Namespace: mci.Namespace, Name: gceL7GlobalExternalManagedMCGatewayClass}
+		gatewayContext, ok := mciIR.Gateways[gatewayKey]
+		if !ok {
+			continue
+		}
+		if address, ok := mci.Annotations[multiClusterStaticIPKey]; ok {
+			addStaticIP(&gatewayContext, address, mci)
+		}
+		if certificates := splitCertificateNames(mci.Annotations[multiClusterPreSharedCertsKey]); len(certificates) > 0 {
+			for _, host := range ingressHosts(ingressesByKey[key]) {
+				addPreSharedCerts(&gatewayContext, host, certificates, mci)
+			}
+		}
+		mciIR.Gateways[gatewayKey] = gatewayContext
+	}
+	for key, gatewayContext := range mciIR.Gateways {
+		if _, ok := ir.Gateways[key]; ok {
+			errs = append(errs, field.Duplicate(field.NewPath(MultiClusterIngressGVK.Kind), key.String()))
+			continue
+		}
+		ir.Gateways[key] = gatewayContext
+	}
+
+	notifyServiceExports(referencedServices, storage.MultiClusterServices)
+	return errs
+}
+
+// multiClusterIngressToIngress returns the Ingress of the
+// gke-l7-global-external-managed-mc class equivalent to the
+// MultiClusterIngress, with the ImplementationSpecific paths of GCE, and the
+// MultiClusterServices it references. The named ports of the backends are
+// resolved from the ports of the MultiClusterServices.
+func multiClusterIngressToIngress(mci *multiClusterIngress, services map[types.NamespacedName]*multiClusterService) (networkingv1.Ingress, []types.NamespacedName, field.ErrorList) {
+	var errs field.ErrorList
+	var referenced []types.NamespacedName
+	specPath := field.NewPath(MultiClusterIngressGVK.Kind).Key(fmt.Sprintf("%s/%s", mci.Namespace, mci.Name)).Child("spec", "template", "spec")
+
+	toBackend := func(backend multiClusterIngressBackend, fieldPath *field.Path) networkingv1.IngressBackend {
+		key := types.NamespacedName{Namespace: mci.Namespace, Name: backend.ServiceName}
+		if !slices.Contains(referenced, key) {
+			referenced = append(referenced, key)
+		}
+		port := networkingv1.ServiceBackendPort{Number: backend.ServicePort.IntVal}
+		if backend.ServicePort.Type == intstr.String {
+			port.Number = 0
+			if service, ok := services[key]; ok {
+				for _, servicePort := range service.Spec.Template.Spec.Ports {
+					if servicePort.Name == backend.ServicePort.StrVal {
+						port.Number = servicePort.Port
+					}
+				}
+			}
+			if port.Number == 0 {
+				errs = append(errs, field.Invalid(fieldPath.Child("servicePort"), backend.ServicePort.StrVal, fmt.Sprintf("no port of this name in MultiClusterService %s", key)))
+			}
+		}
+		return networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: backend.ServiceName, Port: port}}
+	}
+
+	ingress := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   mci.Namespace,
+			Name:        mci.Name,
+			Annotations: mci.Annotations,
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To(gceL7GlobalExternalManagedMCGatewayClass),
+			TLS:              mci.Spec.Template.Spec.TLS,
+		},
+	}
+	if backend := mci.Spec.Template.Spec.Backend; backend != nil {
+		ingress.Spec.DefaultBackend = ptr.To(toBackend(*backend, specPath.Child("backend")))
+	}
+	for i, rule := range mci.Spec.Template.Spec.Rules {
+		ingressRule := networkingv1.IngressRule{Host: rule.Host}
+		if rule.HTTP != nil {
+			ingressRule.HTTP = &networkingv1.HTTPIngressRuleValue{}
+			for j, path := range rule.HTTP.Paths {
+				value := path.Path
+				if value == "" {
+					value = "/*"
+				}
+				ingressRule.HTTP.Paths = append(ingressRule.HTTP.Paths, networkingv1.HTTPIngressPath{
+					Path:     value,
+					PathType: ptr.To(networkingv1.PathTypeImplementationSpecific),
+					Backend:  toBackend(path.Backend, specPath.Child("rules").Index(i).Child("http", "paths").Index(j).Child("backend")),
+				})
+			}
+		}
+		ingress.Spec.Rules = append(ingress.Spec.Rules, ingressRule)
+	}
+	return ingress, referenced, errs
+}
+
+// addStaticIP adds the static IP address of the MultiClusterIngress to the
+// Gateway, as an IP address, or as a named address if it is the name of a
+// reserved address of the project.
+func addStaticIP(gatewayContext *intermediate.GatewayContext, value string, mci *multiClusterIngress) {
+	address := gatewayv1.GatewayAddress{Type: ptr.To(gatewayv1.IPAddressType), Value: value}
+	if net.ParseIP(value) == nil {
+		address = gatewayv1.GatewayAddress{Type: ptr.To(gatewayv1.NamedAddressType), Value: value[strings.LastIndex(value, "/")+1:]}
+	}
+	if slices.Contains(gatewayContext.Spec.Addresses, address) {
+		return
+	}
+	if len(gatewayContext.Spec.Addresses) > 0 {
+		notify(notifications.WarningNotification, fmt.Sprintf("Gateway %s/%s has an address already, the static IP %s of MultiClusterIngress %s/%s was not added to it, as GKE Gateways have a single address",
+			gatewayContext.Namespace, gatewayContext.Name, value, mci.Namespace, mci.Name), mci)
+		return
+	}
+	gatewayContext.Spec.Addresses = append(gatewayContext.Spec.Addresses, address)
+}
+
+// notifyServiceExports reports the ServiceExports to create for the
+// ServiceImports referenced by the HTTPRoutes, and the MultiClusterServices
+// which were not found.
+func notifyServiceExports(referencedServices map[types.NamespacedName][]*multiClusterIngress, services map[types.NamespacedName]*multiClusterService) {
+	keys := make([]types.NamespacedName, 0, len(referencedServices))
+	for key := range referencedServices {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, compareNamespacedNames)
+
+	for _, key := range keys {
+		service, ok := services[key]
+		if !ok {
+			mci := referencedServices[key][0]
+			notify(notifications.WarningNotification, fmt.Sprintf("MultiClusterService %s referenced by MultiClusterIngress %s/%s was not found, the HTTPRoutes reference the ServiceImport %s nonetheless", key, mci.Namespace, mci.Name, key), mci)
+			continue
+		}
+		clusters := "the clusters of the fleet"
+		if len(service.Spec.Clusters) > 0 {
+			var links []string
+			for _, cluster := range service.Spec.Clusters {
+				links = append(links, cluster.Link)
+			}
+			clusters = fmt.Sprintf("the clusters %s", strings.Join(links, ", "))
+		}
+		notify(notifications.InfoNotification, fmt.Sprintf("the HTTPRoutes reference the ServiceImport %s instead of MultiClusterService %s: export the Services it selects from %s with a Service named %s and a ServiceExport of the same name", key, key, clusters, key.Name), service)
+		if _, ok := service.Annotations[backendConfigKey]; ok {
+			notify(notifications.WarningNotification, fmt.Sprintf("the BackendConfig of MultiClusterService %s was not converted, configure the ServiceImport %s with GCPBackendPolicies and HealthCheckPolicies instead", key, key), service)
+		}
+	}
+}
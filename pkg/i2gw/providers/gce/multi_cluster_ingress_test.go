@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_multiClusterIngressesToIR(t *testing.T) {
+	testCases := []struct {
+		name              string
+		annotations       map[string]string
+		servicePort       intstr.IntOrString
+		expectedAddresses []gatewayv1.GatewayAddress
+		expectedListeners []gatewayv1.Listener
+		expectedPort      *gatewayv1.PortNumber
+		expectedErrors    int
+	}{
+		{
+			name:        "named port, static IP and pre-shared certificates",
+			servicePort: intstr.FromString("http"),
+			annotations: map[string]string{
+				multiClusterStaticIPKey:       "203.0.113.10",
+				multiClusterPreSharedCertsKey: "cert-a,cert-b",
+			},
+			expectedAddresses: []gatewayv1.GatewayAddress{{Type: common.PtrTo(gatewayv1.IPAddressType), Value: "203.0.113.10"}},
+			expectedListeners: []gatewayv1.Listener{
+				{
+					Name:     "test-mydomain-com-http",
+					Port:     80,
+					Protocol: gatewayv1.HTTPProtocolType,
+					Hostname: common.PtrTo(gatewayv1.Hostname(testHost)),
+				},
+				{
+					Name:     "test-mydomain-com-https",
+					Port:     443,
+					Protocol: gatewayv1.HTTPSProtocolType,
+					Hostname: common.PtrTo(gatewayv1.Hostname(testHost)),
+					TLS: &gatewayv1.GatewayTLSConfig{
+						Mode: common.PtrTo(gatewayv1.TLSModeTerminate),
+						Options: map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{
+							preSharedCertsTLSOption: "cert-a,cert-b",
+						},
+					},
+				},
+			},
+			expectedPort: common.PtrTo(gatewayv1.PortNumber(8080)),
+		},
+		{
+			name:              "reserved address name",
+			servicePort:       intstr.FromInt32(80),
+			annotations:       map[string]string{multiClusterStaticIPKey: "projects/my-project/global/addresses/my-address"},
+			expectedAddresses: []gatewayv1.GatewayAddress{{Type: common.PtrTo(gatewayv1.NamedAddressType), Value: "my-address"}},
+			expectedListeners: []gatewayv1.Listener{{
+				Name:     "test-mydomain-com-http",
+				Port:     80,
+				Protocol: gatewayv1.HTTPProtocolType,
+				Hostname: common.PtrTo(gatewayv1.Hostname(testHost)),
+			}},
+			expectedPort: common.PtrTo(gatewayv1.PortNumber(80)),
+		},
+		{
+			name:           "unknown named port",
+			servicePort:    intstr.FromString("grpc"),
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mci := &multiClusterIngress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testIngressName, Annotations: tc.annotations},
+			}
+			mci.Spec.Template.Spec.Rules = []multiClusterIngressRule{{Host: testHost}}
+			mci.Spec.Template.Spec.Rules[0].HTTP = &struct {
+				Paths []multiClusterIngressPath `json:"paths"`
+			}{Paths: []multiClusterIngressPath{{
+				Path:    "/*",
+				Backend: multiClusterIngressBackend{ServiceName: testServiceName, ServicePort: tc.servicePort},
+			}}}
+			mcs := &multiClusterService{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testServiceName}}
+			mcs.Spec.Template.Spec.Ports = []apiv1.ServicePort{{Name: "http", Port: 8080}}
+
+			storage := newResourcesStorage()
+			storage.MultiClusterIngresses[types.NamespacedName{Namespace: testNamespace, Name: testIngressName}] = mci
+			storage.MultiClusterServices[types.NamespacedName{Namespace: testNamespace, Name: testServiceName}] = mcs
+			ir := intermediate.IR{
+				Gateways:   map[types.NamespacedName]intermediate.GatewayContext{},
+				HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{},
+			}
+
+			errs := multiClusterIngressesToIR(storage, i2gw.ProviderImplementationSpecificOptions{
+				ToImplementationSpecificHTTPPathTypeMatch: implementationSpecificHTTPPathTypeMatch,
+			}, &ir)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("Expected %d errors, got %v", tc.expectedErrors, errs)
+			}
+			if tc.expectedErrors > 0 {
+				return
+			}
+
+			gatewayContext := ir.Gateways[types.NamespacedName{Namespace: testNamespace, Name: gceL7GlobalExternalManagedMCGatewayClass}]
+			if gatewayContext.Spec.GatewayClassName != gceL7GlobalExternalManagedMCGatewayClass {
+				t.Errorf("Expected GatewayClass %s, got %s", gceL7GlobalExternalManagedMCGatewayClass, gatewayContext.Spec.GatewayClassName)
+			}
+			if diff := cmp.Diff(tc.expectedAddresses, gatewayContext.Spec.Addresses); diff != "" {
+				t.Errorf("Unexpected addresses (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedListeners, gatewayContext.Spec.Listeners); diff != "" {
+				t.Errorf("Unexpected listeners (-want +got): %s", diff)
+			}
+
+			httpRouteContext := ir.HTTPRoutes[types.NamespacedName{Namespace: testNamespace, Name: common.RouteName(testIngressName, testHost)}]
+			if diff := cmp.Diff([]client.Object{mci}, httpRouteContext.Sources); diff != "" {
+				t.Errorf("Unexpected sources (-want +got): %s", diff)
+			}
+			expectedBackendRefs := []gatewayv1.HTTPBackendRef{{
+				BackendRef: gatewayv1.BackendRef{
+					BackendObjectReference: gatewayv1.BackendObjectReference{
+						Group: common.PtrTo(gatewayv1.Group("net.gke.io")),
+						Kind:  common.PtrTo(gatewayv1.Kind("ServiceImport")),
+						Name:  testServiceName,
+						Port:  tc.expectedPort,
+					},
+				},
+			}}
+			if len(httpRouteContext.Spec.Rules) != 1 {
+				t.Fatalf("Expected 1 rule, got %d", len(httpRouteContext.Spec.Rules))
+			}
+			if diff := cmp.Diff(expectedBackendRefs, httpRouteContext.Spec.Rules[0].BackendRefs); diff != "" {
+				t.Errorf("Unexpected backendRefs (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -26,10 +26,11 @@ import (
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	apiv1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/sets"
 	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
 	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
 )
@@ -38,7 +39,7 @@ import (
 // 1. "gce", for external Ingress
 // 2. "gce-internal", for internal Ingress
 // 3. "", which defaults to external Ingress
-var supportedGCEIngressClass = sets.New(gceIngressClass, gceL7ILBIngressClass, "")
+var supportedGCEIngressClasses = []string{gceIngressClass, gceL7ILBIngressClass, ""}
 
 const (
 	IngressKind = "Ingress"
@@ -59,7 +60,7 @@ func newResourceReader(conf *i2gw.ProviderConf) reader {
 func (r *reader) readResourcesFromCluster(ctx context.Context) (*storage, error) {
 	storage := newResourcesStorage()
 
-	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, supportedGCEIngressClass)
+	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, r.conf.IngressClaimer.ClaimFunc(ProviderName))
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +83,24 @@ func (r *reader) readResourcesFromCluster(ctx context.Context) (*storage, error)
 		return nil, err
 	}
 	storage.FrontendConfigs = frontConfigs
+
+	managedCertificates, err := r.readManagedCertificatesFromCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+	storage.ManagedCertificates = managedCertificates
+
+	multiClusterIngresses, err := readMultiClusterObjectsFromCluster[multiClusterIngress](ctx, r, MultiClusterIngressGVK)
+	if err != nil {
+		return nil, err
+	}
+	storage.MultiClusterIngresses = multiClusterIngresses
+
+	multiClusterServices, err := readMultiClusterObjectsFromCluster[multiClusterService](ctx, r, MultiClusterServiceGVK)
+	if err != nil {
+		return nil, err
+	}
+	storage.MultiClusterServices = multiClusterServices
 	return storage, nil
 }
 
@@ -143,6 +162,57 @@ func (r *reader) readFrontendConfigsFromCluster(ctx context.Context) (map[types.
 	return frontendConfigs, nil
 }
 
+// readManagedCertificatesFromCluster reads the ManagedCertificates, or none
+// if their CRD is not installed.
+func (r *reader) readManagedCertificatesFromCluster(ctx context.Context) (map[types.NamespacedName]*managedCertificate, error) {
+	var managedCertificateList unstructured.UnstructuredList
+	managedCertificateList.SetGroupVersionKind(ManagedCertificateGVK.GroupVersion().WithKind(ManagedCertificateGVK.Kind + "List"))
+	err := r.conf.Client.List(ctx, &managedCertificateList)
+	if meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+		return map[types.NamespacedName]*managedCertificate{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get managedCertificates from the cluster: %w", err)
+	}
+	managedCertificates := make(map[types.NamespacedName]*managedCertificate)
+	for i := range managedCertificateList.Items {
+		item := &managedCertificateList.Items[i]
+		managedCertificates[types.NamespacedName{Namespace: item.GetNamespace(), Name: item.GetName()}] = toManagedCertificate(item)
+	}
+	return managedCertificates, nil
+}
+
+// toManagedCertificate reads the managedCertificate of the unstructured
+// ManagedCertificate.
+func toManagedCertificate(object *unstructured.Unstructured) *managedCertificate {
+	certificateName, _, _ := unstructured.NestedString(object.Object, "status", "certificateName")
+	return &managedCertificate{CertificateName: certificateName}
+}
+
+// readMultiClusterObjectsFromCluster reads the MultiClusterIngresses or
+// MultiClusterServices, or none if their CRD is not installed, as only the
+// config cluster of a fleet has them.
+func readMultiClusterObjectsFromCluster[T multiClusterIngress | multiClusterService](ctx context.Context, r *reader, gvk schema.GroupVersionKind) (map[types.NamespacedName]*T, error) {
+	var list unstructured.UnstructuredList
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	err := r.conf.Client.List(ctx, &list)
+	if meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+		return map[types.NamespacedName]*T{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s from the cluster: %w", gvk.Kind, err)
+	}
+	objects := make(map[types.NamespacedName]*T)
+	for i := range list.Items {
+		var object T
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(list.Items[i].UnstructuredContent(), &object); err != nil {
+			return nil, err
+		}
+		objects[types.NamespacedName{Namespace: list.Items[i].GetNamespace(), Name: list.Items[i].GetName()}] = &object
+	}
+	return objects, nil
+}
+
 func (r *reader) readUnstructuredObjects(objects []*unstructured.Unstructured) (*storage, error) {
 	res := newResourcesStorage()
 
@@ -150,6 +220,9 @@ func (r *reader) readUnstructuredObjects(objects []*unstructured.Unstructured) (
 	services := make(map[types.NamespacedName]*apiv1.Service)
 	backendConfigs := make(map[types.NamespacedName]*backendconfigv1.BackendConfig)
 	frontendConfigs := make(map[types.NamespacedName]*frontendconfigv1beta1.FrontendConfig)
+	managedCertificates := make(map[types.NamespacedName]*managedCertificate)
+	multiClusterIngresses := make(map[types.NamespacedName]*multiClusterIngress)
+	multiClusterServices := make(map[types.NamespacedName]*multiClusterService)
 
 	for _, f := range objects {
 		if f.GroupVersionKind().Empty() {
@@ -163,10 +236,11 @@ func (r *reader) readUnstructuredObjects(objects []*unstructured.Unstructured) (
 			if err != nil {
 				return nil, err
 			}
-			if !supportedGCEIngressClass.Has(common.GetIngressClass(ingress)) {
+			claimed, ok := r.conf.IngressClaimer.Claim(ProviderName, &ingress)
+			if !ok {
 				continue
 			}
-			ingresses[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = &ingress
+			ingresses[types.NamespacedName{Namespace: claimed.Namespace, Name: claimed.Name}] = claimed
 		}
 		if f.GetAPIVersion() == "v1" && f.GetKind() == "Service" {
 			var service apiv1.Service
@@ -195,10 +269,34 @@ func (r *reader) readUnstructuredObjects(objects []*unstructured.Unstructured) (
 			}
 			frontendConfigs[types.NamespacedName{Namespace: frontendConfig.Namespace, Name: frontendConfig.Name}] = &frontendConfig
 		}
+		if f.GroupVersionKind() == ManagedCertificateGVK {
+			managedCertificates[types.NamespacedName{Namespace: f.GetNamespace(), Name: f.GetName()}] = toManagedCertificate(f)
+		}
+		if f.GroupVersionKind() == MultiClusterIngressGVK {
+			var mci multiClusterIngress
+			err := runtime.DefaultUnstructuredConverter.
+				FromUnstructured(f.UnstructuredContent(), &mci)
+			if err != nil {
+				return nil, err
+			}
+			multiClusterIngresses[types.NamespacedName{Namespace: mci.Namespace, Name: mci.Name}] = &mci
+		}
+		if f.GroupVersionKind() == MultiClusterServiceGVK {
+			var mcs multiClusterService
+			err := runtime.DefaultUnstructuredConverter.
+				FromUnstructured(f.UnstructuredContent(), &mcs)
+			if err != nil {
+				return nil, err
+			}
+			multiClusterServices[types.NamespacedName{Namespace: mcs.Namespace, Name: mcs.Name}] = &mcs
+		}
 	}
 	res.Ingresses = ingresses
 	res.Services = services
 	res.BackendConfigs = backendConfigs
 	res.FrontendConfigs = frontendConfigs
+	res.ManagedCertificates = managedCertificates
+	res.MultiClusterIngresses = multiClusterIngresses
+	res.MultiClusterServices = multiClusterServices
 	return res, nil
 }
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// buildSSLCertificates converts the SSL certificates GKE Ingress serves
+// besides the Secrets of the Ingress TLS: the pre-shared certificates of the
+// ingress.gcp.kubernetes.io/pre-shared-cert annotation, and the certificates
+// provisioned for the ManagedCertificates of the
+// networking.gke.io/managed-certificates annotation. They are referenced with
+// the networking.gke.io/pre-shared-certs TLS option of the HTTPS listeners of
+// the hosts of the Ingress, which are added to the Gateway if needed.
+func buildSSLCertificates(ingresses []networkingv1.Ingress, managedCertificates map[types.NamespacedName]*managedCertificate, ir *intermediate.IR) {
+	sorted := slices.Clone(ingresses)
+	slices.SortFunc(sorted, func(a, b networkingv1.Ingress) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+
+	for _, ingress := range sorted {
+		certificates := splitCertificateNames(ingress.Annotations[preSharedCertKey])
+		for _, name := range splitCertificateNames(ingress.Annotations[managedCertificatesKey]) {
+			certificate, ok := managedCertificates[types.NamespacedName{Namespace: ingress.Namespace, Name: name}]
+			if !ok || certificate.CertificateName == "" {
+				notify(notifications.WarningNotification, fmt.Sprintf("ManagedCertificate %s/%s of ingress %s/%s was not found or is not provisioned, its certificate was not converted", ingress.Namespace, name, ingress.Namespace, ingress.Name), &ingress)
+				continue
+			}
+			certificates = append(certificates, certificate.CertificateName)
+			notify(notifications.WarningNotification, fmt.Sprintf("ManagedCertificates are not supported by GKE Gateway, the certificate %s provisioned for ManagedCertificate %s/%s is referenced as a pre-shared certificate, and is deleted with the ManagedCertificate: migrate it to Certificate Manager",
+				certificate.CertificateName, ingress.Namespace, name), &ingress)
+		}
+		if len(certificates) == 0 {
+			continue
+		}
+
+		gatewayKey := types.NamespacedName{Namespace: ingress.Namespace, Name: common.GetIngressClass(ingress)}
+		gatewayContext, ok := ir.Gateways[gatewayKey]
+		if !ok {
+			continue
+		}
+		for _, host := range ingressHosts(ingress) {
+			addPreSharedCerts(&gatewayContext, host, certificates, &ingress)
+		}
+		ir.Gateways[gatewayKey] = gatewayContext
+	}
+}
+
+// addPreSharedCerts adds the certificates to the pre-shared certificates of
+// the HTTPS listener of the host, adding the listener if the Gateway has none.
+// The listeners referencing Secrets are left unchanged.
+func addPreSharedCerts(gatewayContext *intermediate.GatewayContext, host string, certificates []string, source client.Object) {
+	hostname := gatewayv1.Hostname(host)
+	index := slices.IndexFunc(gatewayContext.Spec.Listeners, func(listener gatewayv1.Listener) bool {
+		return listener.Protocol == gatewayv1.HTTPSProtocolType && ptr.Deref(listener.Hostname, "") == hostname
+	})
+	if index < 0 {
+		listener := gatewayv1.Listener{
+			Name:     "https",
+			Port:     443,
+			Protocol: gatewayv1.HTTPSProtocolType,
+			TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModeTerminate)},
+		}
+		if host != "" {
+			listener.Name = gatewayv1.SectionName(fmt.Sprintf("%s-https", common.NameFromHost(host)))
+			listener.Hostname = &hostname
+		}
+		gatewayContext.Spec.Listeners = append(gatewayContext.Spec.Listeners, listener)
+		index = len(gatewayContext.Spec.Listeners) - 1
+	}
+
+	listener := &gatewayContext.Spec.Listeners[index]
+	if listener.TLS == nil {
+		listener.TLS = &gatewayv1.GatewayTLSConfig{}
+	}
+	if len(listener.TLS.CertificateRefs) > 0 {
+		notify(notifications.WarningNotification, fmt.Sprintf("listener %s of Gateway %s/%s references the Secrets of the Ingress TLS, the certificates %v of %s/%s were not added to it",
+			listener.Name, gatewayContext.Namespace, gatewayContext.Name, certificates, source.GetNamespace(), source.GetName()), source)
+		return
+	}
+
+	existing := splitCertificateNames(string(listener.TLS.Options[preSharedCertsTLSOption]))
+	for _, certificate := range certificates {
+		if !slices.Contains(existing, certificate) {
+			existing = append(existing, certificate)
+		}
+	}
+	if listener.TLS.Options == nil {
+		listener.TLS.Options = map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{}
+	}
+	listener.TLS.Options[preSharedCertsTLSOption] = gatewayv1.AnnotationValue(strings.Join(existing, ","))
+}
+
+// ingressHosts returns the hosts of the rules of the Ingress, or the empty
+// host matching all hosts for its default backend.
+func ingressHosts(ingress networkingv1.Ingress) []string {
+	var hosts []string
+	for _, rule := range ingress.Spec.Rules {
+		host := common.NormalizeHost(rule.Host)
+		if !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	if ingress.Spec.DefaultBackend != nil && len(ingress.Spec.Rules) == 0 {
+		hosts = append(hosts, "")
+	}
+	return hosts
+}
+
+// splitCertificateNames splits the comma-separated names of the SSL
+// certificates of an annotation.
+func splitCertificateNames(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
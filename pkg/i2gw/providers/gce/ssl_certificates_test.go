@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_buildSSLCertificates(t *testing.T) {
+	testCases := []struct {
+		name              string
+		annotations       map[string]string
+		tls               []networkingv1.IngressTLS
+		expectedListeners []gatewayv1.Listener
+	}{
+		{
+			name: "pre-shared and managed certificates",
+			annotations: map[string]string{
+				preSharedCertKey:       "cert-a, cert-b",
+				managedCertificatesKey: "managed,missing",
+			},
+			expectedListeners: []gatewayv1.Listener{
+				{
+					Name:     "test-mydomain-com-http",
+					Port:     80,
+					Protocol: gatewayv1.HTTPProtocolType,
+					Hostname: common.PtrTo(gatewayv1.Hostname(testHost)),
+				},
+				{
+					Name:     "test-mydomain-com-https",
+					Port:     443,
+					Protocol: gatewayv1.HTTPSProtocolType,
+					Hostname: common.PtrTo(gatewayv1.Hostname(testHost)),
+					TLS: &gatewayv1.GatewayTLSConfig{
+						Mode: common.PtrTo(gatewayv1.TLSModeTerminate),
+						Options: map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{
+							preSharedCertsTLSOption: "cert-a,cert-b,mcrt-1234",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "listener referencing Secrets unchanged",
+			annotations: map[string]string{preSharedCertKey: "cert-a"},
+			tls:         []networkingv1.IngressTLS{{Hosts: []string{testHost}, SecretName: testSecretName}},
+			expectedListeners: []gatewayv1.Listener{
+				{
+					Name:     "test-mydomain-com-http",
+					Port:     80,
+					Protocol: gatewayv1.HTTPProtocolType,
+					Hostname: common.PtrTo(gatewayv1.Hostname(testHost)),
+				},
+				{
+					Name:     "test-mydomain-com-https",
+					Port:     443,
+					Protocol: gatewayv1.HTTPSProtocolType,
+					Hostname: common.PtrTo(gatewayv1.Hostname(testHost)),
+					TLS: &gatewayv1.GatewayTLSConfig{
+						CertificateRefs: []gatewayv1.SecretObjectReference{{Name: testSecretName}},
+					},
+				},
+			},
+		},
+		{
+			name:        "unprovisioned managed certificate ignored",
+			annotations: map[string]string{managedCertificatesKey: "pending"},
+			expectedListeners: []gatewayv1.Listener{{
+				Name:     "test-mydomain-com-http",
+				Port:     80,
+				Protocol: gatewayv1.HTTPProtocolType,
+				Hostname: common.PtrTo(gatewayv1.Hostname(testHost)),
+			}},
+		},
+	}
+
+	managedCertificates := map[types.NamespacedName]*managedCertificate{
+		{Namespace: testNamespace, Name: "managed"}: {CertificateName: "mcrt-1234"},
+		{Namespace: testNamespace, Name: "pending"}: {},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := getTestIngress(testNamespace, testIngressName, testServiceName)
+			for key, value := range tc.annotations {
+				ingress.Annotations[key] = value
+			}
+			ingress.Spec.TLS = tc.tls
+			ingresses := []networkingv1.Ingress{*ingress}
+
+			ir, errs := common.ToIR(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) > 0 {
+				t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+			}
+			buildSSLCertificates(ingresses, managedCertificates, &ir)
+
+			gatewayContext := ir.Gateways[types.NamespacedName{Namespace: testNamespace, Name: gceIngressClass}]
+			if diff := cmp.Diff(tc.expectedListeners, gatewayContext.Spec.Listeners); diff != "" {
+				t.Errorf("Unexpected listeners (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -38,6 +38,26 @@ type storage struct {
 	// on an Ingress.
 	// FrontendConfig map is keyed by the namespaced name of the FrontendConfig.
 	FrontendConfigs map[types.NamespacedName]*frontendconfigv1beta1.FrontendConfig
+	// ManagedCertificates is a GKE Ingress extension, and it is associated to
+	// a GKE Ingress through specifying `networking.gke.io/managed-certificates`
+	// on an Ingress.
+	// ManagedCertificate map is keyed by the namespaced name of the
+	// ManagedCertificate.
+	ManagedCertificates map[types.NamespacedName]*managedCertificate
+
+	// MultiClusterIngresses and MultiClusterServices are the multi-cluster
+	// Ingress resources of the config cluster of a GKE fleet, keyed by their
+	// namespaced name.
+	MultiClusterIngresses map[types.NamespacedName]*multiClusterIngress
+	MultiClusterServices  map[types.NamespacedName]*multiClusterService
+}
+
+// managedCertificate is the part of a ManagedCertificate used by the
+// conversion, whose API types are not vendored.
+type managedCertificate struct {
+	// CertificateName is the name of the Google-managed SSL certificate
+	// provisioned for the ManagedCertificate, empty until it is provisioned.
+	CertificateName string
 }
 
 func newResourcesStorage() *storage {
@@ -46,5 +66,9 @@ func newResourcesStorage() *storage {
 		Services:        make(map[types.NamespacedName]*apiv1.Service),
 		BackendConfigs:  make(map[types.NamespacedName]*backendconfigv1.BackendConfig),
 		FrontendConfigs: make(map[types.NamespacedName]*frontendconfigv1beta1.FrontendConfig),
+
+		ManagedCertificates:   make(map[types.NamespacedName]*managedCertificate),
+		MultiClusterIngresses: make(map[types.NamespacedName]*multiClusterIngress),
+		MultiClusterServices:  make(map[types.NamespacedName]*multiClusterService),
 	}
 }
@@ -24,9 +24,20 @@ const (
 
 	gceL7GlobalExternalManagedGatewayClass = "gke-l7-global-external-managed"
 	gceL7RegionalInternalGatewayClass      = "gke-l7-rilb"
-	backendConfigKey                       = "cloud.google.com/backend-config"
-	betaBackendConfigKey                   = "beta.cloud.google.com/backend-config"
-	frontendConfigKey                      = "networking.gke.io/v1beta1.FrontendConfig"
+	// gceL7GlobalExternalManagedMCGatewayClass is the GatewayClass of the
+	// multi-cluster Gateways the MultiClusterIngresses are converted to.
+	gceL7GlobalExternalManagedMCGatewayClass = "gke-l7-global-external-managed-mc"
+	backendConfigKey                         = "cloud.google.com/backend-config"
+	betaBackendConfigKey                     = "beta.cloud.google.com/backend-config"
+	frontendConfigKey                        = "networking.gke.io/v1beta1.FrontendConfig"
+	preSharedCertKey                         = "ingress.gcp.kubernetes.io/pre-shared-cert"
+	managedCertificatesKey                   = "networking.gke.io/managed-certificates"
+	multiClusterStaticIPKey                  = "networking.gke.io/static-ip"
+	multiClusterPreSharedCertsKey            = "networking.gke.io/pre-shared-certs"
+
+	// preSharedCertsTLSOption is the TLS option of the GKE Gateway listeners
+	// referencing SSL certificates of the project.
+	preSharedCertsTLSOption = "networking.gke.io/pre-shared-certs"
 )
 
 var (
@@ -47,4 +58,31 @@ var (
 		Version: "v1",
 		Kind:    "HealthCheckPolicy",
 	}
+
+	ManagedCertificateGVK = schema.GroupVersionKind{
+		Group:   "networking.gke.io",
+		Version: "v1",
+		Kind:    "ManagedCertificate",
+	}
+
+	MultiClusterIngressGVK = schema.GroupVersionKind{
+		Group:   "networking.gke.io",
+		Version: "v1",
+		Kind:    "MultiClusterIngress",
+	}
+
+	MultiClusterServiceGVK = schema.GroupVersionKind{
+		Group:   "networking.gke.io",
+		Version: "v1",
+		Kind:    "MultiClusterService",
+	}
+
+	// ServiceImportGVK is the kind of the backends of the multi-cluster
+	// Gateways, imported from the Services exported by the clusters of the
+	// fleet.
+	ServiceImportGVK = schema.GroupVersionKind{
+		Group:   "net.gke.io",
+		Version: "v1",
+		Kind:    "ServiceImport",
+	}
 )
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	affinityAnnotation                             = "nginx.ingress.kubernetes.io/affinity"
+	affinityModeAnnotation                         = "nginx.ingress.kubernetes.io/affinity-mode"
+	sessionCookieNameAnnotation                    = "nginx.ingress.kubernetes.io/session-cookie-name"
+	sessionCookiePathAnnotation                    = "nginx.ingress.kubernetes.io/session-cookie-path"
+	sessionCookieDomainAnnotation                  = "nginx.ingress.kubernetes.io/session-cookie-domain"
+	sessionCookieExpiresAnnotation                 = "nginx.ingress.kubernetes.io/session-cookie-expires"
+	sessionCookieMaxAgeAnnotation                  = "nginx.ingress.kubernetes.io/session-cookie-max-age"
+	sessionCookieSameSiteAnnotation                = "nginx.ingress.kubernetes.io/session-cookie-samesite"
+	sessionCookieConditionalSameSiteNoneAnnotation = "nginx.ingress.kubernetes.io/session-cookie-conditional-samesite-none"
+	sessionCookieSecureAnnotation                  = "nginx.ingress.kubernetes.io/session-cookie-secure"
+	sessionCookieChangeOnFailureAnnotation         = "nginx.ingress.kubernetes.io/session-cookie-change-on-failure"
+	upstreamHashByAnnotation                       = "nginx.ingress.kubernetes.io/upstream-hash-by"
+
+	// defaultSessionCookieName is the name of the affinity cookie of
+	// ingress-nginx when not set.
+	defaultSessionCookieName = "INGRESSCOOKIE"
+)
+
+// affinityFeature parses the nginx.ingress.kubernetes.io/affinity,
+// nginx.ingress.kubernetes.io/affinity-mode, nginx.ingress.kubernetes.io/session-cookie-*
+// and nginx.ingress.kubernetes.io/upstream-hash-by annotations into the Affinity policy of
+// the ingress-nginx HTTPRoute IR.
+//
+// The Gateway API core has no equivalent for pinning the clients to the endpoints of the
+// backends, so the policy can only be honored by implementation-specific emitters.
+func affinityFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	return patchIngressPolicies(ingresses, ir, func(ingress networkingv1.Ingress) (func(*intermediate.IngressNginxPolicy), field.ErrorList) {
+		affinity, errs := parseAffinityAnnotations(ingress)
+		if affinity == nil {
+			return nil, errs
+		}
+		notify(notifications.InfoNotification, fmt.Sprintf("ingress %s/%s pins its clients to the endpoints of its backends, which has no Gateway API equivalent: the configuration is only kept for implementation-specific emitters", ingress.Namespace, ingress.Name), &ingress)
+		return func(policy *intermediate.IngressNginxPolicy) {
+			policy.Affinity = affinity
+		}, errs
+	})
+}
+
+// parseAffinityAnnotations returns the AffinityConfig of the Ingress, or nil if its clients
+// aren't pinned to endpoints.
+func parseAffinityAnnotations(ingress networkingv1.Ingress) (*intermediate.AffinityConfig, field.ErrorList) {
+	annotationsPath := field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations")
+	annotations := ingress.Annotations
+
+	affinity, ok := annotations[affinityAnnotation]
+	if ok && strings.TrimSpace(affinity) != "cookie" {
+		return nil, field.ErrorList{field.NotSupported(annotationsPath.Key(affinityAnnotation), affinity, []string{"cookie"})}
+	}
+	hashBy := strings.TrimSpace(annotations[upstreamHashByAnnotation])
+	if !ok {
+		if hashBy == "" {
+			return nil, nil
+		}
+		return &intermediate.AffinityConfig{HashBy: hashBy}, nil
+	}
+	if hashBy != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("%q annotation of ingress %s/%s is ignored, the clients are pinned with a cookie", upstreamHashByAnnotation, ingress.Namespace, ingress.Name), &ingress)
+	}
+
+	var errs field.ErrorList
+	cookie := intermediate.AffinityCookieConfig{
+		Name:   defaultSessionCookieName,
+		Path:   strings.TrimSpace(annotations[sessionCookiePathAnnotation]),
+		Domain: strings.TrimSpace(annotations[sessionCookieDomainAnnotation]),
+		Mode:   intermediate.AffinityModeBalanced,
+	}
+	if name := strings.TrimSpace(annotations[sessionCookieNameAnnotation]); name != "" {
+		cookie.Name = name
+	}
+	if mode, ok := annotations[affinityModeAnnotation]; ok {
+		switch affinityMode := intermediate.AffinityMode(strings.TrimSpace(mode)); affinityMode {
+		case intermediate.AffinityModeBalanced, intermediate.AffinityModePersistent:
+			cookie.Mode = affinityMode
+		default:
+			errs = append(errs, field.NotSupported(annotationsPath.Key(affinityModeAnnotation), mode, []string{string(intermediate.AffinityModeBalanced), string(intermediate.AffinityModePersistent)}))
+		}
+	}
+	if sameSite, ok := annotations[sessionCookieSameSiteAnnotation]; ok {
+		switch strings.ToLower(strings.TrimSpace(sameSite)) {
+		case "none":
+			cookie.SameSite = "None"
+		case "lax":
+			cookie.SameSite = "Lax"
+		case "strict":
+			cookie.SameSite = "Strict"
+		default:
+			errs = append(errs, field.NotSupported(annotationsPath.Key(sessionCookieSameSiteAnnotation), sameSite, []string{"None", "Lax", "Strict"}))
+		}
+	}
+
+	for _, seconds := range []struct {
+		annotation string
+		value      **time.Duration
+	}{
+		{annotation: sessionCookieExpiresAnnotation, value: &cookie.Expires},
+		{annotation: sessionCookieMaxAgeAnnotation, value: &cookie.MaxAge},
+	} {
+		value, ok := annotations[seconds.annotation]
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || parsed < 0 {
+			errs = append(errs, field.Invalid(annotationsPath.Key(seconds.annotation), value, "must be a non-negative number of seconds"))
+			continue
+		}
+		*seconds.value = ptr.To(time.Duration(parsed) * time.Second)
+	}
+
+	for _, flag := range []struct {
+		annotation string
+		value      *bool
+	}{
+		{annotation: sessionCookieConditionalSameSiteNoneAnnotation, value: &cookie.ConditionalSameSiteNone},
+		{annotation: sessionCookieSecureAnnotation, value: &cookie.Secure},
+		{annotation: sessionCookieChangeOnFailureAnnotation, value: &cookie.ChangeOnFailure},
+	} {
+		value, ok := annotations[flag.annotation]
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			errs = append(errs, field.Invalid(annotationsPath.Key(flag.annotation), value, "must be a boolean"))
+			continue
+		}
+		*flag.value = parsed
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return &intermediate.AffinityConfig{Cookie: &cookie}, nil
+}
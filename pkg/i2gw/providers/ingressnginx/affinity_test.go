@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func Test_parseAffinityAnnotations(t *testing.T) {
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		expected       *intermediate.AffinityConfig
+		expectedErrors int
+	}{
+		{
+			name:        "no affinity",
+			annotations: map[string]string{},
+		},
+		{
+			name:        "default cookie",
+			annotations: map[string]string{affinityAnnotation: "cookie"},
+			expected: &intermediate.AffinityConfig{Cookie: &intermediate.AffinityCookieConfig{
+				Name: defaultSessionCookieName,
+				Mode: intermediate.AffinityModeBalanced,
+			}},
+		},
+		{
+			name: "cookie attributes",
+			annotations: map[string]string{
+				affinityAnnotation:                             "cookie",
+				affinityModeAnnotation:                         "persistent",
+				sessionCookieNameAnnotation:                    "route",
+				sessionCookiePathAnnotation:                    "/app",
+				sessionCookieDomainAnnotation:                  "example.com",
+				sessionCookieExpiresAnnotation:                 "172800",
+				sessionCookieMaxAgeAnnotation:                  "172800",
+				sessionCookieSameSiteAnnotation:                "none",
+				sessionCookieConditionalSameSiteNoneAnnotation: "true",
+				sessionCookieSecureAnnotation:                  "true",
+				sessionCookieChangeOnFailureAnnotation:         "true",
+				upstreamHashByAnnotation:                       "$binary_remote_addr",
+			},
+			expected: &intermediate.AffinityConfig{Cookie: &intermediate.AffinityCookieConfig{
+				Name:                    "route",
+				Path:                    "/app",
+				Domain:                  "example.com",
+				Expires:                 ptr.To(48 * time.Hour),
+				MaxAge:                  ptr.To(48 * time.Hour),
+				SameSite:                "None",
+				ConditionalSameSiteNone: true,
+				Secure:                  true,
+				ChangeOnFailure:         true,
+				Mode:                    intermediate.AffinityModePersistent,
+			}},
+		},
+		{
+			name:        "consistent hashing",
+			annotations: map[string]string{upstreamHashByAnnotation: "$http_x_user"},
+			expected:    &intermediate.AffinityConfig{HashBy: "$http_x_user"},
+		},
+		{
+			name:           "unsupported affinity",
+			annotations:    map[string]string{affinityAnnotation: "ip"},
+			expectedErrors: 1,
+		},
+		{
+			name: "invalid cookie attributes",
+			annotations: map[string]string{
+				affinityAnnotation:              "cookie",
+				affinityModeAnnotation:          "sticky",
+				sessionCookieSameSiteAnnotation: "Always",
+				sessionCookieMaxAgeAnnotation:   "2d",
+				sessionCookieSecureAnnotation:   "yes please",
+			},
+			expectedErrors: 4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: tc.annotations}}
+			affinity, errs := parseAffinityAnnotations(ingress)
+			if len(errs) != tc.expectedErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if diff := cmp.Diff(tc.expected, affinity); diff != "" {
+				t.Errorf("Unexpected affinity (-want +got): %s", diff)
+			}
+		})
+	}
+}
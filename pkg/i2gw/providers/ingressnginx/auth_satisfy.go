@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const satisfyAnnotation = "nginx.ingress.kubernetes.io/satisfy"
+
+// authSatisfyFeature parses the nginx.ingress.kubernetes.io/satisfy annotation into the AuthSatisfy
+// of the ingress-nginx policies authenticating the requests with both basic and external
+// authentication, so that emitters can combine the authentications accordingly.
+//
+// It must run after the features parsing the authentications.
+func authSatisfyFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	return patchIngressPolicies(ingresses, ir, func(ingress networkingv1.Ingress) (func(*intermediate.IngressNginxPolicy), field.ErrorList) {
+		satisfy, errs := parseSatisfyAnnotation(ingress)
+		if len(errs) > 0 {
+			return nil, errs
+		}
+		_, basicAuth := ingress.Annotations[authTypeAnnotation]
+		_, externalAuth := ingress.Annotations[authURLAnnotation]
+		if !basicAuth || !externalAuth {
+			if satisfy == intermediate.AuthSatisfyAny {
+				notify(notifications.InfoNotification, fmt.Sprintf("ingress %s/%s sets the %q annotation, which has no effect without both basic and external authentication", ingress.Namespace, ingress.Name, satisfyAnnotation), &ingress)
+			}
+			return nil, nil
+		}
+		notify(notifications.InfoNotification, fmt.Sprintf("ingress %s/%s requires both basic and external authentication, the requests must satisfy %s of them", ingress.Namespace, ingress.Name, satisfy), &ingress)
+		return func(policy *intermediate.IngressNginxPolicy) {
+			if policy.MultipleAuth() {
+				policy.AuthSatisfy = satisfy
+			}
+		}, nil
+	})
+}
+
+// parseSatisfyAnnotation returns how the authentications of the Ingress combine, all of them
+// being required by default.
+func parseSatisfyAnnotation(ingress networkingv1.Ingress) (intermediate.AuthSatisfy, field.ErrorList) {
+	satisfy, ok := ingress.Annotations[satisfyAnnotation]
+	if !ok || satisfy == "" {
+		return intermediate.AuthSatisfyAll, nil
+	}
+	switch intermediate.AuthSatisfy(satisfy) {
+	case intermediate.AuthSatisfyAll, intermediate.AuthSatisfyAny:
+		return intermediate.AuthSatisfy(satisfy), nil
+	}
+	annotationsPath := field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations")
+	return "", field.ErrorList{field.NotSupported(annotationsPath.Key(satisfyAnnotation), satisfy, []string{string(intermediate.AuthSatisfyAll), string(intermediate.AuthSatisfyAny)})}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_authSatisfyFeature(t *testing.T) {
+	iPrefix := networkingv1.PathTypePrefix
+	basicAuth := map[string]string{authTypeAnnotation: "basic", authSecretAnnotation: "basic-auth"}
+	externalAuth := map[string]string{authURLAnnotation: "http://auth.default.svc/verify"}
+	withAnnotations := func(annotations ...map[string]string) map[string]string {
+		merged := map[string]string{}
+		for _, a := range annotations {
+			for k, v := range a {
+				merged[k] = v
+			}
+		}
+		return merged
+	}
+
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		expectedPolicy intermediate.IngressNginxPolicy
+		expectedErrors int
+	}{
+		{
+			name:        "both authentications required by default",
+			annotations: withAnnotations(basicAuth, externalAuth),
+			expectedPolicy: intermediate.IngressNginxPolicy{
+				RuleIndices:  []int{0},
+				BasicAuth:    &intermediate.BasicAuthConfig{SecretName: "basic-auth", SecretType: authSecretTypeFile},
+				ExternalAuth: &intermediate.ExternalAuthConfig{URL: "http://auth.default.svc/verify"},
+				AuthSatisfy:  intermediate.AuthSatisfyAll,
+			},
+		},
+		{
+			name:        "any authentication",
+			annotations: withAnnotations(basicAuth, externalAuth, map[string]string{satisfyAnnotation: "any"}),
+			expectedPolicy: intermediate.IngressNginxPolicy{
+				RuleIndices:  []int{0},
+				BasicAuth:    &intermediate.BasicAuthConfig{SecretName: "basic-auth", SecretType: authSecretTypeFile},
+				ExternalAuth: &intermediate.ExternalAuthConfig{URL: "http://auth.default.svc/verify"},
+				AuthSatisfy:  intermediate.AuthSatisfyAny,
+			},
+		},
+		{
+			name:        "single authentication",
+			annotations: withAnnotations(basicAuth, map[string]string{satisfyAnnotation: "any"}),
+			expectedPolicy: intermediate.IngressNginxPolicy{
+				RuleIndices: []int{0},
+				BasicAuth:   &intermediate.BasicAuthConfig{SecretName: "basic-auth", SecretType: authSecretTypeFile},
+			},
+		},
+		{
+			name:           "unsupported value",
+			annotations:    withAnnotations(basicAuth, externalAuth, map[string]string{satisfyAnnotation: "some"}),
+			expectedErrors: 1,
+			expectedPolicy: intermediate.IngressNginxPolicy{
+				RuleIndices:  []int{0},
+				BasicAuth:    &intermediate.BasicAuthConfig{SecretName: "basic-auth", SecretType: authSecretTypeFile},
+				ExternalAuth: &intermediate.ExternalAuthConfig{URL: "http://auth.default.svc/verify"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: tc.annotations},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptrTo("nginx"),
+					Rules: []networkingv1.IngressRule{{
+						Host: "example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     "/",
+									PathType: &iPrefix,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{Name: "app", Port: networkingv1.ServiceBackendPort{Number: 80}},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}
+			ingresses := []networkingv1.Ingress{ingress}
+
+			ir, errs := common.ToIR(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) > 0 {
+				t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+			}
+			for _, feature := range []i2gw.FeatureParser{basicAuthFeature, externalAuthFeature} {
+				if errs = feature(ingresses, &ir); len(errs) > 0 {
+					t.Fatalf("Unexpected errors: %v", errs)
+				}
+			}
+			if errs = authSatisfyFeature(ingresses, &ir); len(errs) != tc.expectedErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+
+			routeIR := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: common.RouteName("app", "example.com")}].ProviderSpecificIR.IngressNginx
+			if routeIR == nil {
+				t.Fatalf("Expected the ingress-nginx HTTPRoute IR to be set")
+			}
+			if diff := cmp.Diff(tc.expectedPolicy, routeIR.Policies["app"]); diff != "" {
+				t.Errorf("Unexpected policy (-want +got): %s", diff)
+			}
+		})
+	}
+}
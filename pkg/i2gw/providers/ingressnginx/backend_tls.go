@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+)
+
+const (
+	backendProtocolAnnotation = "nginx.ingress.kubernetes.io/backend-protocol"
+	proxySSLSecretAnnotation  = "nginx.ingress.kubernetes.io/proxy-ssl-secret"
+	proxySSLNameAnnotation    = "nginx.ingress.kubernetes.io/proxy-ssl-name"
+)
+
+// backendTLSFeature returns a FeatureParser generating a BackendTLSPolicy for
+// the Services of the Ingresses whose nginx.ingress.kubernetes.io/backend-protocol
+// annotation indicates TLS to the upstream.
+//
+// The CA certificates are read from the Secret of the
+// nginx.ingress.kubernetes.io/proxy-ssl-secret annotation. Without it, the
+// policies are validated with the given well-known CA certificates, and are not
+// generated if none is given, as they would fail validation.
+func backendTLSFeature(wellKnownCACertificates gatewayv1alpha3.WellKnownCACertificatesType) i2gw.FeatureParser {
+	return func(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+		var errs field.ErrorList
+
+		for _, ingress := range ingresses {
+			protocol := strings.ToUpper(ingress.Annotations[backendProtocolAnnotation])
+			if protocol != "HTTPS" && protocol != "GRPCS" {
+				continue
+			}
+			annotationsPath := field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations")
+
+			var validation gatewayv1alpha3.BackendTLSPolicyValidation
+			if secret, ok := ingress.Annotations[proxySSLSecretAnnotation]; ok {
+				namespace, name, found := strings.Cut(secret, "/")
+				if !found || namespace == "" || name == "" {
+					errs = append(errs, field.Invalid(annotationsPath.Key(proxySSLSecretAnnotation), secret, "the secret must be specified as <namespace>/<name>"))
+					continue
+				}
+				if namespace != ingress.Namespace {
+					notify(notifications.WarningNotification, fmt.Sprintf("CA certificates of ingress %s/%s can't be referenced from Secret %s of another namespace, BackendTLSPolicies were not generated", ingress.Namespace, ingress.Name, secret), &ingress)
+					continue
+				}
+				validation.CACertificateRefs = []gatewayv1.LocalObjectReference{{Group: "", Kind: "Secret", Name: gatewayv1.ObjectName(name)}}
+			} else if wellKnownCACertificates != "" {
+				validation.WellKnownCACertificates = ptr.To(wellKnownCACertificates)
+			} else {
+				notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s uses TLS to its backends without CA certificates, BackendTLSPolicies were not generated, set --backend-tls-well-known-ca-certificates to validate them with well-known CA certificates", ingress.Namespace, ingress.Name), &ingress)
+				continue
+			}
+
+			for _, service := range ingressServiceNames(ingress) {
+				validation := *validation.DeepCopy()
+				validation.Hostname = gatewayv1.PreciseHostname(fmt.Sprintf("%s.%s.svc", service, ingress.Namespace))
+				if sslName := ingress.Annotations[proxySSLNameAnnotation]; sslName != "" {
+					validation.Hostname = gatewayv1.PreciseHostname(sslName)
+				}
+
+				key := types.NamespacedName{Namespace: ingress.Namespace, Name: fmt.Sprintf("%s-backend-tls", service)}
+				policy := gatewayv1alpha3.BackendTLSPolicy{
+					ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+					Spec: gatewayv1alpha3.BackendTLSPolicySpec{
+						TargetRefs: []gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName{{
+							LocalPolicyTargetReference: gatewayv1alpha2.LocalPolicyTargetReference{Group: "", Kind: "Service", Name: gatewayv1.ObjectName(service)},
+						}},
+						Validation: validation,
+					},
+				}
+				policy.SetGroupVersionKind(common.BackendTLSPolicyGVK)
+
+				if ir.BackendTLSPolicies == nil {
+					ir.BackendTLSPolicies = map[types.NamespacedName]gatewayv1alpha3.BackendTLSPolicy{}
+				}
+				if existing, ok := ir.BackendTLSPolicies[key]; ok {
+					if !reflect.DeepEqual(existing.Spec, policy.Spec) {
+						notify(notifications.WarningNotification, fmt.Sprintf("conflicting upstream TLS configuration of ingress %s/%s for Service %s was ignored", ingress.Namespace, ingress.Name, service), &existing)
+					}
+					continue
+				}
+				ir.BackendTLSPolicies[key] = policy
+				notify(notifications.InfoNotification, fmt.Sprintf("parsed \"%v\" annotation of ingress %s/%s and generated BackendTLSPolicy %s", backendProtocolAnnotation, ingress.Namespace, ingress.Name, key), &policy)
+			}
+		}
+
+		return errs
+	}
+}
+
+// ingressServiceNames returns the sorted names of the Services the Ingress
+// routes to.
+func ingressServiceNames(ingress networkingv1.Ingress) []string {
+	var names []string
+	addBackend := func(backend *networkingv1.IngressBackend) {
+		if backend != nil && backend.Service != nil && !slices.Contains(names, backend.Service.Name) {
+			names = append(names, backend.Service.Name)
+		}
+	}
+
+	addBackend(ingress.Spec.DefaultBackend)
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			addBackend(&path.Backend)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+)
+
+func Test_backendTLSFeature(t *testing.T) {
+	testIngress := func(annotations map[string]string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptrTo("nginx"),
+				DefaultBackend: &networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{Name: "app", Port: networkingv1.ServiceBackendPort{Number: 443}},
+				},
+			},
+		}
+	}
+
+	policyKey := types.NamespacedName{Namespace: "default", Name: "app-backend-tls"}
+
+	testCases := []struct {
+		name                    string
+		ingress                 networkingv1.Ingress
+		wellKnownCACertificates gatewayv1alpha3.WellKnownCACertificatesType
+		expectedValidation      *gatewayv1alpha3.BackendTLSPolicyValidation
+		expectedErrors          int
+	}{
+		{
+			name:    "no TLS to the backends",
+			ingress: testIngress(nil),
+		},
+		{
+			name: "CA certificates from the proxy SSL secret",
+			ingress: testIngress(map[string]string{
+				backendProtocolAnnotation: "HTTPS",
+				proxySSLSecretAnnotation:  "default/app-ca",
+				proxySSLNameAnnotation:    "app.example.com",
+			}),
+			wellKnownCACertificates: gatewayv1alpha3.WellKnownCACertificatesSystem,
+			expectedValidation: &gatewayv1alpha3.BackendTLSPolicyValidation{
+				CACertificateRefs: []gatewayv1.LocalObjectReference{{Kind: "Secret", Name: "app-ca"}},
+				Hostname:          "app.example.com",
+			},
+		},
+		{
+			name:                    "well-known CA certificates without proxy SSL secret",
+			ingress:                 testIngress(map[string]string{backendProtocolAnnotation: "https"}),
+			wellKnownCACertificates: gatewayv1alpha3.WellKnownCACertificatesSystem,
+			expectedValidation: &gatewayv1alpha3.BackendTLSPolicyValidation{
+				WellKnownCACertificates: ptr.To(gatewayv1alpha3.WellKnownCACertificatesSystem),
+				Hostname:                "app.default.svc",
+			},
+		},
+		{
+			name:    "no policy without CA certificates",
+			ingress: testIngress(map[string]string{backendProtocolAnnotation: "GRPCS"}),
+		},
+		{
+			name: "proxy SSL secret of another namespace",
+			ingress: testIngress(map[string]string{
+				backendProtocolAnnotation: "HTTPS",
+				proxySSLSecretAnnotation:  "certs/app-ca",
+			}),
+		},
+		{
+			name: "invalid proxy SSL secret",
+			ingress: testIngress(map[string]string{
+				backendProtocolAnnotation: "HTTPS",
+				proxySSLSecretAnnotation:  "app-ca",
+			}),
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir := intermediate.IR{}
+			errs := backendTLSFeature(tc.wellKnownCACertificates)([]networkingv1.Ingress{tc.ingress}, &ir)
+			if len(errs) != tc.expectedErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+
+			policy, ok := ir.BackendTLSPolicies[policyKey]
+			if tc.expectedValidation == nil {
+				if ok {
+					t.Fatalf("Expected no BackendTLSPolicy, got %v", policy)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("Expected BackendTLSPolicy %s to be generated", policyKey)
+			}
+			if diff := cmp.Diff(*tc.expectedValidation, policy.Spec.Validation); diff != "" {
+				t.Errorf("Unexpected validation (-want +got): %s", diff)
+			}
+			if targetRef := policy.Spec.TargetRefs[0]; targetRef.Kind != "Service" || targetRef.Name != "app" {
+				t.Errorf("Unexpected target %v", targetRef)
+			}
+		})
+	}
+}
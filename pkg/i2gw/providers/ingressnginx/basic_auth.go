@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	authTypeAnnotation       = "nginx.ingress.kubernetes.io/auth-type"
+	authSecretAnnotation     = "nginx.ingress.kubernetes.io/auth-secret"
+	authSecretTypeAnnotation = "nginx.ingress.kubernetes.io/auth-secret-type"
+	authRealmAnnotation      = "nginx.ingress.kubernetes.io/auth-realm"
+
+	authSecretTypeFile = "auth-file"
+	authSecretTypeMap  = "auth-map"
+)
+
+// basicAuthFeature parses the nginx.ingress.kubernetes.io/auth-type, nginx.ingress.kubernetes.io/auth-secret,
+// nginx.ingress.kubernetes.io/auth-secret-type and nginx.ingress.kubernetes.io/auth-realm annotations into
+// the BasicAuth policy of the ingress-nginx HTTPRoute IR.
+//
+// The Gateway API has no core equivalent for authentication, so the policy can only be honored by
+// implementation-specific emitters.
+func basicAuthFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	return patchIngressPolicies(ingresses, ir, func(ingress networkingv1.Ingress) (func(*intermediate.IngressNginxPolicy), field.ErrorList) {
+		basicAuth, errs := parseBasicAuthAnnotations(ingress)
+		if basicAuth == nil {
+			return nil, errs
+		}
+		notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s requires basic authentication, which has no Gateway API equivalent: the authentication is only kept for implementation-specific emitters, the requests are not authenticated otherwise", ingress.Namespace, ingress.Name), &ingress)
+		return func(policy *intermediate.IngressNginxPolicy) {
+			policy.BasicAuth = basicAuth
+		}, errs
+	})
+}
+
+// parseBasicAuthAnnotations returns the BasicAuthConfig of the Ingress, or nil if the
+// requests are not authenticated with the HTTP Basic authentication scheme.
+func parseBasicAuthAnnotations(ingress networkingv1.Ingress) (*intermediate.BasicAuthConfig, field.ErrorList) {
+	annotationsPath := field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations")
+
+	authType, ok := ingress.Annotations[authTypeAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	switch authType {
+	case "basic":
+	case "digest":
+		notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s requires digest authentication, which is not supported, the authentication was ignored", ingress.Namespace, ingress.Name), &ingress)
+		return nil, nil
+	default:
+		return nil, field.ErrorList{field.NotSupported(annotationsPath.Key(authTypeAnnotation), authType, []string{"basic", "digest"})}
+	}
+
+	secret := ingress.Annotations[authSecretAnnotation]
+	if secret == "" {
+		return nil, field.ErrorList{field.Required(annotationsPath.Key(authSecretAnnotation), "required by basic authentication")}
+	}
+	if namespace, name, found := strings.Cut(secret, "/"); found {
+		if namespace != ingress.Namespace {
+			notify(notifications.WarningNotification, fmt.Sprintf("credentials of ingress %s/%s can't be referenced from Secret %s of another namespace, the authentication was ignored", ingress.Namespace, ingress.Name, secret), &ingress)
+			return nil, nil
+		}
+		secret = name
+	}
+
+	secretType := authSecretTypeFile
+	if value, ok := ingress.Annotations[authSecretTypeAnnotation]; ok {
+		if value != authSecretTypeFile && value != authSecretTypeMap {
+			return nil, field.ErrorList{field.NotSupported(annotationsPath.Key(authSecretTypeAnnotation), value, []string{authSecretTypeFile, authSecretTypeMap})}
+		}
+		secretType = value
+	}
+
+	return &intermediate.BasicAuthConfig{
+		SecretName: secret,
+		SecretType: secretType,
+		Realm:      ingress.Annotations[authRealmAnnotation],
+	}, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_parseBasicAuthAnnotations(t *testing.T) {
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		expected       *intermediate.BasicAuthConfig
+		expectedErrors int
+	}{
+		{
+			name:        "no authentication",
+			annotations: map[string]string{},
+		},
+		{
+			name:        "basic authentication",
+			annotations: map[string]string{authTypeAnnotation: "basic", authSecretAnnotation: "basic-auth", authRealmAnnotation: "Restricted"},
+			expected:    &intermediate.BasicAuthConfig{SecretName: "basic-auth", SecretType: authSecretTypeFile, Realm: "Restricted"},
+		},
+		{
+			name:        "secret in the namespace of the ingress",
+			annotations: map[string]string{authTypeAnnotation: "basic", authSecretAnnotation: "default/basic-auth", authSecretTypeAnnotation: authSecretTypeMap},
+			expected:    &intermediate.BasicAuthConfig{SecretName: "basic-auth", SecretType: authSecretTypeMap},
+		},
+		{
+			name:        "secret of another namespace",
+			annotations: map[string]string{authTypeAnnotation: "basic", authSecretAnnotation: "other/basic-auth"},
+		},
+		{
+			name:        "digest authentication",
+			annotations: map[string]string{authTypeAnnotation: "digest", authSecretAnnotation: "basic-auth"},
+		},
+		{
+			name:           "missing secret",
+			annotations:    map[string]string{authTypeAnnotation: "basic"},
+			expectedErrors: 1,
+		},
+		{
+			name:           "unsupported authentication type",
+			annotations:    map[string]string{authTypeAnnotation: "oauth", authSecretAnnotation: "basic-auth"},
+			expectedErrors: 1,
+		},
+		{
+			name:           "unsupported secret type",
+			annotations:    map[string]string{authTypeAnnotation: "basic", authSecretAnnotation: "basic-auth", authSecretTypeAnnotation: "auth-json"},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: tc.annotations}}
+			basicAuth, errs := parseBasicAuthAnnotations(ingress)
+			if len(errs) != tc.expectedErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if diff := cmp.Diff(tc.expected, basicAuth); diff != "" {
+				t.Errorf("Unexpected basic auth (-want +got): %s", diff)
+			}
+		})
+	}
+}
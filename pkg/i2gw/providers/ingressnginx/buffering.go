@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	proxyBodySizeAnnotation        = "nginx.ingress.kubernetes.io/proxy-body-size"
+	clientBodyBufferSizeAnnotation = "nginx.ingress.kubernetes.io/client-body-buffer-size"
+)
+
+// bufferingFeature parses the nginx.ingress.kubernetes.io/proxy-body-size and
+// nginx.ingress.kubernetes.io/client-body-buffer-size annotations into the Buffering policy
+// of the ingress-nginx HTTPRoute IR.
+//
+// The Gateway API has no core equivalent for limiting and buffering the request bodies, so
+// the policy can only be honored by implementation-specific emitters.
+func bufferingFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	return patchIngressPolicies(ingresses, ir, func(ingress networkingv1.Ingress) (func(*intermediate.IngressNginxPolicy), field.ErrorList) {
+		buffering, errs := parseBufferingAnnotations(ingress)
+		if buffering == nil {
+			return nil, errs
+		}
+		notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s configures the size of the request bodies, which has no Gateway API equivalent: the configuration is only kept for implementation-specific emitters", ingress.Namespace, ingress.Name), &ingress)
+		return func(policy *intermediate.IngressNginxPolicy) {
+			policy.Buffering = buffering
+		}, errs
+	})
+}
+
+// parseBufferingAnnotations returns the BufferingConfig of the Ingress, or nil if the
+// request bodies are handled with the defaults.
+func parseBufferingAnnotations(ingress networkingv1.Ingress) (*intermediate.BufferingConfig, field.ErrorList) {
+	annotationsPath := field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations")
+
+	var (
+		buffering intermediate.BufferingConfig
+		errs      field.ErrorList
+	)
+	for _, size := range []struct {
+		annotation string
+		bytes      **int64
+	}{
+		{annotation: proxyBodySizeAnnotation, bytes: &buffering.MaxRequestBodyBytes},
+		{annotation: clientBodyBufferSizeAnnotation, bytes: &buffering.MemRequestBodyBytes},
+	} {
+		annotation := size.annotation
+		value, ok := ingress.Annotations[annotation]
+		if !ok {
+			continue
+		}
+		bytes, err := parseNginxSize(value)
+		if err != nil {
+			errs = append(errs, field.Invalid(annotationsPath.Key(annotation), value, err.Error()))
+			continue
+		}
+		*size.bytes = ptr.To(bytes)
+	}
+
+	if len(errs) > 0 || (buffering.MaxRequestBodyBytes == nil && buffering.MemRequestBodyBytes == nil) {
+		return nil, errs
+	}
+	return &buffering, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func Test_parseBufferingAnnotations(t *testing.T) {
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		expected       *intermediate.BufferingConfig
+		expectedErrors int
+	}{
+		{
+			name:        "default buffering",
+			annotations: map[string]string{},
+		},
+		{
+			name:        "body size and buffer size",
+			annotations: map[string]string{proxyBodySizeAnnotation: "8m", clientBodyBufferSizeAnnotation: "16k"},
+			expected:    &intermediate.BufferingConfig{MaxRequestBodyBytes: ptr.To[int64](8 << 20), MemRequestBodyBytes: ptr.To[int64](16 << 10)},
+		},
+		{
+			name:        "unlimited body size",
+			annotations: map[string]string{proxyBodySizeAnnotation: "0"},
+			expected:    &intermediate.BufferingConfig{MaxRequestBodyBytes: ptr.To[int64](0)},
+		},
+		{
+			name:           "invalid size",
+			annotations:    map[string]string{proxyBodySizeAnnotation: "8mb"},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: tc.annotations}}
+			buffering, errs := parseBufferingAnnotations(ingress)
+			if len(errs) != tc.expectedErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if diff := cmp.Diff(tc.expected, buffering); diff != "" {
+				t.Errorf("Unexpected buffering (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_parseNginxSize(t *testing.T) {
+	testCases := []struct {
+		size          string
+		expected      int64
+		expectedError bool
+	}{
+		{size: "1024", expected: 1024},
+		{size: "1k", expected: 1 << 10},
+		{size: "8M", expected: 8 << 20},
+		{size: "1g", expected: 1 << 30},
+		{size: "1.5m", expectedError: true},
+		{size: "", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.size, func(t *testing.T) {
+			size, err := parseNginxSize(tc.size)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("Expected error to be %v, got %v", tc.expectedError, err)
+			}
+			if size != tc.expected {
+				t.Errorf("Expected %d bytes, got %d", tc.expected, size)
+			}
+		})
+	}
+}
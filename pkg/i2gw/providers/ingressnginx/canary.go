@@ -18,6 +18,8 @@ package ingressnginx
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
 	"strconv"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
@@ -31,7 +33,7 @@ import (
 )
 
 func canaryFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
-	ruleGroups := common.GetRuleGroups(ingresses)
+	ruleGroups := common.GetRuleGroups(ingresses, ir)
 
 	for _, rg := range ruleGroups {
 		ingressPathsByMatchKey, errs := getPathsByMatchGroups(rg)
@@ -42,32 +44,54 @@ func canaryFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.
 		// We're dividing ingresses based on rule groups.  If any path within a
 		// rule group is associated with an ingress object containing canary annotations,
 		// the entire rule group is affected.
-		canaryEnabled := false
+		canaryEnabled, primaryFound := false, false
 		for _, paths := range ingressPathsByMatchKey {
 			for _, path := range paths {
 				if path.extra.canary.enable {
 					canaryEnabled = true
+				} else {
+					primaryFound = true
 				}
 			}
 		}
 
 		if canaryEnabled {
-			for _, paths := range ingressPathsByMatchKey {
-				path := paths[0]
-
-				backendRefs, calculationErrs := calculateBackendRefWeight(paths)
-				errs = append(errs, calculationErrs...)
-
-				key := types.NamespacedName{Namespace: path.ingress.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
-				httpRouteContext, ok := ir.HTTPRoutes[key]
-				if !ok {
-					// If there wasn't an HTTPRoute for this Ingress, we can skip it as something is wrong.
-					// All the available errors will be returned at the end.
-					continue
+			if !primaryFound {
+				warned := map[string]bool{}
+				for _, rule := range rg.Rules {
+					ingress := rule.Ingress
+					if warned[ingress.Name] {
+						continue
+					}
+					warned[ingress.Name] = true
+					notify(notifications.WarningNotification, fmt.Sprintf("canary ingress %s/%s shares no HTTPRoute with the ingress it is a canary of, e.g. because route merging is disabled: its traffic is not split by weight", ingress.Namespace, ingress.Name), &ingress)
 				}
+			}
+			matchKeys := make([]pathMatchKey, 0, len(ingressPathsByMatchKey))
+			for matchKey := range ingressPathsByMatchKey {
+				matchKeys = append(matchKeys, matchKey)
+			}
+			slices.Sort(matchKeys)
+
+			key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+			httpRouteContext, ok := ir.HTTPRoutes[key]
+			if !ok {
+				// If there wasn't an HTTPRoute for this Ingress, we can skip it as something is wrong.
+				// All the available errors will be returned at the end.
+				continue
+			}
 
+			for _, matchKey := range matchKeys {
+				backendRefs, calculationErrs := calculateBackendRefWeight(ingressPathsByMatchKey[matchKey])
+				errs = append(errs, calculationErrs...)
 				patchHTTPRouteWithBackendRefs(&httpRouteContext.HTTPRoute, backendRefs)
 			}
+			// The rules of the header and cookie matches are added once all the
+			// weights are set, as the weights are patched by backend name.
+			for _, matchKey := range matchKeys {
+				errs = append(errs, addCanaryRules(&httpRouteContext.HTTPRoute, ingressPathsByMatchKey[matchKey])...)
+			}
+			ir.HTTPRoutes[key] = httpRouteContext
 			if len(errs) > 0 {
 				return errs
 			}
@@ -127,6 +151,97 @@ func patchHTTPRouteWithBackendRefs(httpRoute *gatewayv1.HTTPRoute, backendRefs [
 	}
 }
 
+// addCanaryRules adds the rules routing the requests selected by the
+// canary-by-header and canary-by-cookie annotations of the canary paths to
+// their backend, next to the rule of the paths splitting the other requests by
+// weight. The header rules are added before the cookie rules, so that the
+// header takes precedence over the cookie, as in ingress-nginx. When the
+// header or the cookie is set to "never", the requests are routed to the
+// primary backends.
+func addCanaryRules(httpRoute *gatewayv1.HTTPRoute, paths []ingressPath) field.ErrorList {
+	var errs field.ErrorList
+	var primaryRefs []gatewayv1.HTTPBackendRef
+	var canaryPaths []ingressPath
+	for i, path := range paths {
+		if path.extra.canary.enable {
+			if path.extra.canary.headerKey != "" || path.extra.canary.cookie != "" {
+				canaryPaths = append(canaryPaths, path)
+			}
+			continue
+		}
+		backendRef, err := common.ToBackendRef(path.path.Backend, field.NewPath("paths", "backends").Index(i))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		primaryRefs = append(primaryRefs, gatewayv1.HTTPBackendRef{BackendRef: *backendRef})
+	}
+	if len(canaryPaths) == 0 {
+		return errs
+	}
+
+	ruleIndex := slices.IndexFunc(httpRoute.Spec.Rules, func(rule gatewayv1.HTTPRouteRule) bool {
+		return common.HTTPRouteRuleMatchesPath(rule, paths[0].path)
+	})
+	if ruleIndex < 0 {
+		return errs
+	}
+	rule := httpRoute.Spec.Rules[ruleIndex]
+
+	addRule := func(header gatewayv1.HTTPHeaderMatch, backendRefs []gatewayv1.HTTPBackendRef) {
+		if len(backendRefs) == 0 {
+			return
+		}
+		canaryRule := *rule.DeepCopy()
+		for i := range canaryRule.Matches {
+			canaryRule.Matches[i].Headers = append(canaryRule.Matches[i].Headers, header)
+		}
+		canaryRule.BackendRefs = backendRefs
+		httpRoute.Spec.Rules = append(httpRoute.Spec.Rules, canaryRule)
+	}
+
+	for _, path := range canaryPaths {
+		canary := path.extra.canary
+		backendRef, err := common.ToBackendRef(path.path.Backend, field.NewPath("paths", "backends"))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		canaryRefs := []gatewayv1.HTTPBackendRef{{BackendRef: *backendRef}}
+
+		if canary.headerKey != "" {
+			headerName := gatewayv1.HTTPHeaderName(canary.headerKey)
+			switch {
+			case canary.headerRegexMatch:
+				addRule(gatewayv1.HTTPHeaderMatch{Type: ptr.To(gatewayv1.HeaderMatchRegularExpression), Name: headerName, Value: canary.headerValue}, canaryRefs)
+			case canary.headerValue != "always":
+				addRule(gatewayv1.HTTPHeaderMatch{Type: ptr.To(gatewayv1.HeaderMatchExact), Name: headerName, Value: canary.headerValue}, canaryRefs)
+			default:
+				addRule(gatewayv1.HTTPHeaderMatch{Type: ptr.To(gatewayv1.HeaderMatchExact), Name: headerName, Value: "always"}, canaryRefs)
+				addRule(gatewayv1.HTTPHeaderMatch{Type: ptr.To(gatewayv1.HeaderMatchExact), Name: headerName, Value: "never"}, primaryRefs)
+			}
+		}
+
+		if canary.cookie != "" {
+			addRule(cookieHeaderMatch(canary.cookie, "always"), canaryRefs)
+			addRule(cookieHeaderMatch(canary.cookie, "never"), primaryRefs)
+			notify(notifications.WarningNotification, fmt.Sprintf("the canary cookie %s of ingress %s/%s is matched with RegularExpression matches of the Cookie header, whose support is implementation-specific", canary.cookie, path.ingress.Namespace, path.ingress.Name), &path.ingress)
+		}
+	}
+	notify(notifications.InfoNotification, fmt.Sprintf("added the rules of the canary header and cookie matches of the %s path", paths[0].path.Path), httpRoute)
+	return errs
+}
+
+// cookieHeaderMatch returns the match of the requests whose Cookie header sets
+// the cookie to the given value.
+func cookieHeaderMatch(cookie, value string) gatewayv1.HTTPHeaderMatch {
+	return gatewayv1.HTTPHeaderMatch{
+		Type:  ptr.To(gatewayv1.HeaderMatchRegularExpression),
+		Name:  "Cookie",
+		Value: fmt.Sprintf(`(^|;\s*)%s=%s(;|$)`, regexp.QuoteMeta(cookie), value),
+	}
+}
+
 func calculateBackendRefWeight(paths []ingressPath) ([]gatewayv1.HTTPBackendRef, field.ErrorList) {
 	var errors field.ErrorList
 	var backendRefs []gatewayv1.HTTPBackendRef
@@ -176,6 +291,7 @@ type canaryAnnotations struct {
 	headerKey        string
 	headerValue      string
 	headerRegexMatch bool
+	cookie           string
 	weight           int
 	weightTotal      int
 }
@@ -200,6 +316,9 @@ func parseCanaryAnnotations(ingress networkingv1.Ingress) (canaryAnnotations, fi
 			annotations.headerValue = cHeaderRegex
 			annotations.headerRegexMatch = true
 		}
+		if cCookie := ingress.Annotations["nginx.ingress.kubernetes.io/canary-by-cookie"]; cCookie != "" {
+			annotations.cookie = cCookie
+		}
 		if cHeaderWeight := ingress.Annotations["nginx.ingress.kubernetes.io/canary-weight"]; cHeaderWeight != "" {
 			annotations.weight, err = strconv.Atoi(cHeaderWeight)
 			if err != nil {
@@ -217,16 +336,16 @@ func parseCanaryAnnotations(ingress networkingv1.Ingress) (canaryAnnotations, fi
 	return annotations, errs
 }
 
+// getPathMatchKey returns the key of the path match of the Ingress path. The
+// canary paths share the key of the primary paths they are a canary of,
+// whatever their header and cookie, as they split the traffic of the same
+// HTTPRoute rule.
 func getPathMatchKey(ip ingressPath) pathMatchKey {
 	var pathType string
 	if ip.path.PathType != nil {
 		pathType = string(*ip.path.PathType)
 	}
-	var canaryHeaderKey string
-	if ip.extra != nil && ip.extra.canary != nil && ip.extra.canary.headerKey != "" {
-		canaryHeaderKey = ip.extra.canary.headerKey
-	}
-	return pathMatchKey(fmt.Sprintf("%s/%s/%s", pathType, ip.path.Path, canaryHeaderKey))
+	return pathMatchKey(fmt.Sprintf("%s/%s", pathType, ip.path.Path))
 }
 
 type pathMatchKey string
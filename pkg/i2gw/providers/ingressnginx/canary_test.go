@@ -276,6 +276,27 @@ func Test_parseCanaryAnnotations(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "header and cookie",
+			ingress: networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"nginx.ingress.kubernetes.io/canary":                 "true",
+						"nginx.ingress.kubernetes.io/canary-by-header":       "X-Canary",
+						"nginx.ingress.kubernetes.io/canary-by-header-value": "yes",
+						"nginx.ingress.kubernetes.io/canary-by-cookie":       "canary",
+					},
+				},
+			},
+			expectedExtra: &extra{
+				canary: &canaryAnnotations{
+					enable:      true,
+					headerKey:   "X-Canary",
+					headerValue: "yes",
+					cookie:      "canary",
+				},
+			},
+		},
 		{
 			name: "errors on non integer weight",
 			ingress: networkingv1.Ingress{
@@ -324,3 +345,79 @@ func Test_parseCanaryAnnotations(t *testing.T) {
 		})
 	}
 }
+
+func Test_addCanaryRules(t *testing.T) {
+	path := func(service string, canary canaryAnnotations) ingressPath {
+		return ingressPath{
+			path: networkingv1.HTTPIngressPath{
+				Path:     "/",
+				PathType: ptrTo(networkingv1.PathTypePrefix),
+				Backend: networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{Name: service, Port: networkingv1.ServiceBackendPort{Number: 80}},
+				},
+			},
+			extra: &extra{canary: &canary},
+		}
+	}
+	backendRefs := func(service string) []gatewayv1.HTTPBackendRef {
+		return []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+			Name: gatewayv1.ObjectName(service),
+			Port: ptrTo(gatewayv1.PortNumber(80)),
+		}}}}
+	}
+	rule := func(header *gatewayv1.HTTPHeaderMatch, service string) gatewayv1.HTTPRouteRule {
+		match := gatewayv1.HTTPRouteMatch{Path: &gatewayv1.HTTPPathMatch{Type: ptrTo(gatewayv1.PathMatchPathPrefix), Value: ptrTo("/")}}
+		if header != nil {
+			match.Headers = []gatewayv1.HTTPHeaderMatch{*header}
+		}
+		return gatewayv1.HTTPRouteRule{Matches: []gatewayv1.HTTPRouteMatch{match}, BackendRefs: backendRefs(service)}
+	}
+
+	testCases := []struct {
+		name          string
+		canary        canaryAnnotations
+		expectedRules []gatewayv1.HTTPRouteRule
+	}{
+		{
+			name:   "weight only",
+			canary: canaryAnnotations{enable: true, weight: 10},
+		},
+		{
+			name:   "header value",
+			canary: canaryAnnotations{enable: true, headerKey: "X-Canary", headerValue: "yes"},
+			expectedRules: []gatewayv1.HTTPRouteRule{
+				rule(&gatewayv1.HTTPHeaderMatch{Type: ptrTo(gatewayv1.HeaderMatchExact), Name: "X-Canary", Value: "yes"}, "app-canary"),
+			},
+		},
+		{
+			name:   "header pattern",
+			canary: canaryAnnotations{enable: true, headerKey: "X-Canary", headerValue: "^(yes|true)$", headerRegexMatch: true},
+			expectedRules: []gatewayv1.HTTPRouteRule{
+				rule(&gatewayv1.HTTPHeaderMatch{Type: ptrTo(gatewayv1.HeaderMatchRegularExpression), Name: "X-Canary", Value: "^(yes|true)$"}, "app-canary"),
+			},
+		},
+		{
+			name:   "cookie",
+			canary: canaryAnnotations{enable: true, cookie: "canary.v2"},
+			expectedRules: []gatewayv1.HTTPRouteRule{
+				rule(&gatewayv1.HTTPHeaderMatch{Type: ptrTo(gatewayv1.HeaderMatchRegularExpression), Name: "Cookie", Value: `(^|;\s*)canary\.v2=always(;|$)`}, "app-canary"),
+				rule(&gatewayv1.HTTPHeaderMatch{Type: ptrTo(gatewayv1.HeaderMatchRegularExpression), Name: "Cookie", Value: `(^|;\s*)canary\.v2=never(;|$)`}, "app"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpRoute := gatewayv1.HTTPRoute{Spec: gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{rule(nil, "app")}}}
+			errs := addCanaryRules(&httpRoute, []ingressPath{path("app", canaryAnnotations{}), path("app-canary", tc.canary)})
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+
+			expectedRules := append([]gatewayv1.HTTPRouteRule{rule(nil, "app")}, tc.expectedRules...)
+			if diff := cmp.Diff(expectedRules, httpRoute.Spec.Rules); diff != "" {
+				t.Errorf("unexpected rules (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
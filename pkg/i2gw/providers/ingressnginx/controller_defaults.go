@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	sslRedirectAnnotation         = "nginx.ingress.kubernetes.io/ssl-redirect"
+	proxyConnectTimeoutAnnotation = "nginx.ingress.kubernetes.io/proxy-connect-timeout"
+	proxyReadTimeoutAnnotation    = "nginx.ingress.kubernetes.io/proxy-read-timeout"
+	proxySendTimeoutAnnotation    = "nginx.ingress.kubernetes.io/proxy-send-timeout"
+	proxyNextUpstreamAnnotation   = "nginx.ingress.kubernetes.io/proxy-next-upstream"
+)
+
+// controllerDefaults are the behaviors ingress-nginx applies to the Ingresses
+// not configuring them, with the defaults of the controller ConfigMap.
+var controllerDefaults = []common.ControllerDefault{
+	{
+		Annotation: sslRedirectAnnotation,
+		Behavior:   "redirects HTTP requests to HTTPS with a 308 when the Ingress has TLS",
+		AppliesTo: func(ingress networkingv1.Ingress) bool {
+			return len(ingress.Spec.TLS) > 0
+		},
+	},
+	{
+		Annotation: proxyBodySizeAnnotation,
+		Behavior:   "rejects request bodies larger than 1m with a 413",
+	},
+	{
+		Annotation: proxyConnectTimeoutAnnotation,
+		Behavior:   "times out connecting to the backends after 5s",
+	},
+	{
+		Annotation: proxyReadTimeoutAnnotation,
+		Behavior:   "times out reading from the backends after 60s",
+	},
+	{
+		Annotation: proxySendTimeoutAnnotation,
+		Behavior:   "times out sending to the backends after 60s",
+	},
+	{
+		Annotation: proxyNextUpstreamAnnotation,
+		Behavior:   "retries the requests failing with an error or a timeout on another endpoint",
+	},
+}
+
+// controllerDefaultsFeature warns about the ingress-nginx defaults the
+// Ingresses rely on, since the migration silently changes the behavior of the
+// Ingresses if the Gateway API implementation has other defaults.
+func controllerDefaultsFeature(ingresses []networkingv1.Ingress, _ *intermediate.IR) field.ErrorList {
+	for _, ingress := range ingresses {
+		reliedUpon := common.ReliedUponControllerDefaults(ingress, controllerDefaults)
+		if message := common.ControllerDefaultsMessage(Name, ingress, reliedUpon); message != "" {
+			notify(notifications.WarningNotification, message, &ingress)
+		}
+	}
+	return nil
+}
@@ -17,22 +17,68 @@ limitations under the License.
 package ingressnginx
 
 import (
+	"fmt"
+
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // resourcesToIRConverter implements the ToIR function of i2gw.ResourcesToIRConverter interface.
 type resourcesToIRConverter struct {
-	featureParsers []i2gw.FeatureParser
+	featureChain *i2gw.FeatureChain
+
+	// mesh indicates whether the routes of east-west traffic, i.e. routes for
+	// cluster-local Service hostnames, should be attached to Services.
+	mesh bool
+
+	// clusterDomain is the domain of the cluster-local Service hostnames.
+	clusterDomain string
+
+	// namespace is the namespace the conversion is restricted to, if any.
+	namespace string
+
+	implementationSpecificOptions i2gw.ProviderImplementationSpecificOptions
 }
 
 // newResourcesToIRConverter returns an ingress-nginx resourcesToIRConverter instance.
-func newResourcesToIRConverter() *resourcesToIRConverter {
+func newResourcesToIRConverter(conf *i2gw.ProviderConf) *resourcesToIRConverter {
 	return &resourcesToIRConverter{
-		featureParsers: []i2gw.FeatureParser{
-			canaryFeature,
+		featureChain: i2gw.NewFeatureChain(
+			i2gw.NamedFeatureParser{Name: "canary", Parse: canaryFeature},
+			i2gw.NamedFeatureParser{Name: "x-forwarded-prefix", Parse: xForwardedPrefixFeature},
+			i2gw.NamedFeatureParser{Name: "proxy-redirect", Parse: proxyRedirectFeature},
+			i2gw.NamedFeatureParser{Name: "rate-limit", Parse: rateLimitFeature},
+			i2gw.NamedFeatureParser{Name: "basic-auth", Parse: basicAuthFeature},
+			i2gw.NamedFeatureParser{Name: "external-auth", Parse: externalAuthFeature},
+			// The satisfy annotation only applies to the policies with both
+			// basic and external authentication.
+			i2gw.NamedFeatureParser{Name: "auth-satisfy", Parse: authSatisfyFeature, After: []string{"basic-auth", "external-auth"}},
+			i2gw.NamedFeatureParser{Name: "buffering", Parse: bufferingFeature},
+			i2gw.NamedFeatureParser{Name: "source-range", Parse: sourceRangeFeature},
+			i2gw.NamedFeatureParser{Name: "upstream-connection", Parse: upstreamConnectionFeature},
+			i2gw.NamedFeatureParser{Name: "affinity", Parse: affinityFeature},
+			i2gw.NamedFeatureParser{Name: "mirror", Parse: mirrorFeature(conf.ClusterDomain)},
+			i2gw.NamedFeatureParser{Name: "default-backend", Parse: defaultBackendFeature(conf.ProviderSpecificFlags[Name][DefaultBackendServiceFlag])},
+			i2gw.NamedFeatureParser{Name: "snippets", Parse: snippetsFeature(conf.Profile.SnippetParsing)},
+			i2gw.NamedFeatureParser{Name: "backend-tls", Parse: backendTLSFeature(conf.BackendTLSWellKnownCACertificates)},
+			i2gw.NamedFeatureParser{Name: "controller-defaults", Parse: controllerDefaultsFeature},
+			// The rewrite feature changes the path matches the other features
+			// find the rules generated from the Ingress paths with.
+			i2gw.NamedFeatureParser{Name: "rewrite", Parse: rewriteFeature, After: []string{"canary", "x-forwarded-prefix", "proxy-redirect", "rate-limit", "basic-auth", "external-auth", "auth-satisfy", "buffering", "source-range", "upstream-connection", "affinity", "mirror", "default-backend"}},
+			// The HTTPS redirect copies the final matches of the HTTPRoutes.
+			i2gw.NamedFeatureParser{Name: "ssl-redirect", Parse: sslRedirectFeature, After: []string{"rewrite"}},
+			// The gRPC routes are converted from the final HTTPRoutes, policies included.
+			i2gw.NamedFeatureParser{Name: "grpc-routes", Parse: grpcRoutesFeature, After: []string{"ssl-redirect", "snippets", "upstream-connection", "affinity", "controller-defaults"}},
+		).Use(conf.FeatureToggles.Middleware(Name)),
+		mesh:          conf.Mesh,
+		clusterDomain: conf.ClusterDomain,
+		namespace:     conf.Namespace,
+		implementationSpecificOptions: i2gw.ProviderImplementationSpecificOptions{
+			DisableRouteMerging: !conf.Profile.RouteMerging,
 		},
 	}
 }
@@ -44,16 +90,24 @@ func (c *resourcesToIRConverter) convert(storage *storage) (intermediate.IR, fie
 
 	// Convert plain ingress resources to gateway resources, ignoring all
 	// provider-specific features.
-	ir, errs := common.ToIR(ingressList, i2gw.ProviderImplementationSpecificOptions{})
+	ir, errs := common.ToIR(ingressList, c.implementationSpecificOptions)
 	if len(errs) > 0 {
 		return intermediate.IR{}, errs
 	}
 
-	for _, parseFeatureFunc := range c.featureParsers {
-		// Apply the feature parsing function to the gateway resources, one by one.
-		parseErrs := parseFeatureFunc(ingressList, &ir)
-		// Append the parsing errors to the error list.
-		errs = append(errs, parseErrs...)
+	// Apply the feature parsing functions to the gateway resources, in order.
+	errs = append(errs, c.featureChain.Run(ingressList, &ir)...)
+
+	// The TCP and UDP services of the controller are exposed on other ports
+	// than the Ingresses, no feature applies to them.
+	convertL4Services(storage.TCPServices, gatewayv1.TCPProtocolType, c.namespace, &ir)
+	convertL4Services(storage.UDPServices, gatewayv1.UDPProtocolType, c.namespace, &ir)
+
+	if c.mesh {
+		for _, routeKey := range common.ToMeshHTTPRoutes(&ir, c.clusterDomain) {
+			httpRoute := ir.HTTPRoutes[routeKey].HTTPRoute
+			notify(notifications.InfoNotification, fmt.Sprintf("HTTPRoute %s matches cluster-local Service hostnames, attached it to the Services for mesh traffic", routeKey), &httpRoute)
+		}
 	}
 
 	return ir, errs
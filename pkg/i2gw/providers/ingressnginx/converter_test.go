@@ -18,11 +18,14 @@ package ingressnginx
 
 import (
 	"errors"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -650,7 +653,7 @@ func Test_ToIR(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 
-			provider := NewProvider(&i2gw.ProviderConf{})
+			provider := NewProvider(&i2gw.ProviderConf{Profile: i2gw.Profile{RouteMerging: true}})
 
 			nginxProvider := provider.(*Provider)
 			nginxProvider.storage.Ingresses = tc.ingresses
@@ -701,3 +704,110 @@ func Test_ToIR(t *testing.T) {
 func ptrTo[T any](a T) *T {
 	return &a
 }
+
+func Test_featureChainOrder(t *testing.T) {
+	parsers, err := newResourcesToIRConverter(&i2gw.ProviderConf{}).featureChain.Order()
+	if err != nil {
+		t.Fatalf("Unexpected error ordering the feature parsers: %v", err)
+	}
+	// The rewrite feature changes the path matches and must run last, but for
+	// the HTTPS redirect copying the final matches and the gRPC routes
+	// converting the final HTTPRoutes.
+	var names []string
+	for _, parser := range parsers {
+		names = append(names, parser.Name)
+	}
+	if last := names[len(names)-3:]; !slices.Equal(last, []string{"rewrite", "ssl-redirect", "grpc-routes"}) {
+		t.Errorf("Expected the rewrite, ssl-redirect and grpc-routes feature parsers to run last, got %v", last)
+	}
+}
+
+func Test_featureChainProfiles(t *testing.T) {
+	iPrefix := networkingv1.PathTypePrefix
+	testIngress := func(name string, annotations map[string]string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptrTo("nginx"),
+				Rules: []networkingv1.IngressRule{{
+					Host: name + ".example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path:     "/",
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: name,
+										Port: networkingv1.ServiceBackendPort{Number: 80},
+									},
+								},
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+	ingresses := []networkingv1.Ingress{
+		testIngress("grpc", map[string]string{backendProtocolAnnotation: "GRPC"}),
+		testIngress("web", map[string]string{configurationSnippetAnnotation: `more_set_headers "X-Frame-Options: DENY";`}),
+	}
+	webRouteKey := types.NamespacedName{Namespace: "default", Name: common.RouteName("web", "web.example.com")}
+
+	testCases := []struct {
+		profile         i2gw.ProfileName
+		expectedEnabled bool
+	}{
+		{profile: i2gw.ConservativeProfile},
+		{profile: i2gw.BalancedProfile},
+		{profile: i2gw.AggressiveProfile, expectedEnabled: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.profile), func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			t.Cleanup(func() {
+				notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			})
+			profile, err := i2gw.GetProfile(tc.profile)
+			if err != nil {
+				t.Fatalf("Unexpected error getting the profile: %v", err)
+			}
+
+			ir, errs := common.ToIR(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) > 0 {
+				t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+			}
+			if errs = newResourcesToIRConverter(&i2gw.ProviderConf{Profile: profile}).featureChain.Run(ingresses, &ir); len(errs) > 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+
+			// The explicit backend-protocol annotation isn't a heuristic.
+			if len(ir.GRPCRoutes) != 1 {
+				t.Errorf("Expected the gRPC Ingress to be converted to a GRPCRoute, got GRPCRoutes %v", ir.GRPCRoutes)
+			}
+			webRoute := ir.HTTPRoutes[webRouteKey]
+			if parsed := len(webRoute.Spec.Rules[0].Filters) > 0; parsed != tc.expectedEnabled {
+				t.Errorf("Expected the snippet to be converted to filters: %t, got filters %v", tc.expectedEnabled, webRoute.Spec.Rules[0].Filters)
+			}
+			if reported := len(webRoute.UnsupportedFeatures) > 0; reported == tc.expectedEnabled {
+				t.Errorf("Expected the snippet to be reported as an unsupported feature: %t, got %v", !tc.expectedEnabled, webRoute.UnsupportedFeatures)
+			}
+
+			var disabledFeatures []string
+			for _, n := range notifications.NotificationAggr.Notifications[Name] {
+				if n.Type == notifications.WarningNotification && strings.Contains(n.Message, "as the profile disables snippet parsing") {
+					disabledFeatures = append(disabledFeatures, n.Message)
+				}
+			}
+			expectedDisabledFeatures := 1
+			if tc.expectedEnabled {
+				expectedDisabledFeatures = 0
+			}
+			if len(disabledFeatures) != expectedDisabledFeatures {
+				t.Errorf("Expected %d warnings about the snippet parsing disabled by the profile, got %v", expectedDisabledFeatures, disabledFeatures)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+const (
+	defaultBackendAnnotation   = "nginx.ingress.kubernetes.io/default-backend"
+	customHTTPErrorsAnnotation = "nginx.ingress.kubernetes.io/custom-http-errors"
+)
+
+// defaultBackendPort is the port of the default backends. ingress-nginx
+// proxies to the first port of their Service, which is not read, and 80 is
+// the port of the default backend of the ingress-nginx Helm chart.
+const defaultBackendPort gatewayv1.PortNumber = 80
+
+// defaultBackendFeature converts the default backends of ingress-nginx, which
+// serve the requests not matching the paths of the Ingresses:
+//   - the default backend of the nginx.ingress.kubernetes.io/default-backend
+//     annotation becomes a catch-all rule of the HTTPRoutes of the Ingress.
+//   - the default backend of the controller, of the --default-backend-service
+//     flag, becomes a catch-all HTTPRoute of each Gateway.
+//
+// The nginx.ingress.kubernetes.io/custom-http-errors annotation, replacing the
+// error responses of the backends by the responses of the default backend, has
+// no Gateway API equivalent and is kept in the CustomHTTPErrors policy of the
+// ingress-nginx HTTPRoute IR.
+func defaultBackendFeature(defaultBackendService string) i2gw.FeatureParser {
+	return func(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+		controllerBackend, err := parseDefaultBackendService(defaultBackendService)
+		if err != nil {
+			return field.ErrorList{err}
+		}
+
+		ingressBackends, errs := parseIngressDefaultBackends(ingresses)
+		errs = append(errs, patchIngressPolicies(ingresses, ir, func(ingress networkingv1.Ingress) (func(*intermediate.IngressNginxPolicy), field.ErrorList) {
+			backend, ok := ingressBackends[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}]
+			if !ok {
+				backend = controllerBackend
+			}
+			customHTTPErrors, err := parseCustomHTTPErrors(ingress, backend)
+			if err != nil {
+				return nil, field.ErrorList{err}
+			}
+			if customHTTPErrors == nil {
+				return nil, nil
+			}
+			notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s replaces the error responses %v of its backends, which has no Gateway API equivalent: the configuration is only kept for implementation-specific emitters", ingress.Namespace, ingress.Name, customHTTPErrors.Codes), &ingress)
+			return func(policy *intermediate.IngressNginxPolicy) {
+				policy.CustomHTTPErrors = customHTTPErrors
+			}, nil
+		})...)
+		addDefaultBackendRules(ingresses, ir, ingressBackends)
+
+		if controllerBackend != nil {
+			controllerDefaultBackendRoutes(ir, *controllerBackend)
+		}
+		return errs
+	}
+}
+
+// parseIngressDefaultBackends returns the default backends of the annotations
+// of the Ingresses, by Ingress.
+func parseIngressDefaultBackends(ingresses []networkingv1.Ingress) (map[types.NamespacedName]*gatewayv1.BackendObjectReference, field.ErrorList) {
+	var errs field.ErrorList
+	backends := map[types.NamespacedName]*gatewayv1.BackendObjectReference{}
+	for _, ingress := range ingresses {
+		service, ok := ingress.Annotations[defaultBackendAnnotation]
+		if !ok {
+			continue
+		}
+		service = strings.TrimSpace(service)
+		if service == "" || strings.Contains(service, "/") {
+			errs = append(errs, field.Invalid(field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations").Key(defaultBackendAnnotation), service, "must be the name of a Service of the namespace of the Ingress"))
+			continue
+		}
+		backends[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = &gatewayv1.BackendObjectReference{
+			Name: gatewayv1.ObjectName(service),
+			Port: ptr.To(defaultBackendPort),
+		}
+		notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s routes the requests not matching its paths to its default backend Service %s, assumed to listen on port %d: in ingress-nginx, the default backend also serves the requests of the Services without active endpoints, which has no Gateway API equivalent", ingress.Namespace, ingress.Name, service, defaultBackendPort), &ingress)
+	}
+	return backends, errs
+}
+
+// addDefaultBackendRules adds a catch-all rule routing to the default backend
+// of each Ingress to its HTTPRoutes without one. The rule is added to the
+// policy of the Ingress, if any.
+func addDefaultBackendRules(ingresses []networkingv1.Ingress, ir *intermediate.IR, backends map[types.NamespacedName]*gatewayv1.BackendObjectReference) {
+	if len(backends) == 0 {
+		return
+	}
+
+	for _, rg := range common.GetRuleGroups(ingresses, ir) {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRouteContext, ok := ir.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			ingressKey := types.NamespacedName{Namespace: rule.Ingress.Namespace, Name: rule.Ingress.Name}
+			backend, ok := backends[ingressKey]
+			if !ok {
+				continue
+			}
+			if index := catchAllRuleIndex(httpRouteContext.HTTPRoute); index >= 0 {
+				if !slices.ContainsFunc(httpRouteContext.Spec.Rules[index].BackendRefs, func(backendRef gatewayv1.HTTPBackendRef) bool {
+					return backendRef.BackendObjectReference == *backend
+				}) {
+					notify(notifications.WarningNotification, fmt.Sprintf("the %q annotation of ingress %s/%s was ignored for HTTPRoute %s, which already routes all its paths", defaultBackendAnnotation, ingressKey.Namespace, ingressKey.Name, key), &httpRouteContext.HTTPRoute)
+				}
+				continue
+			}
+			httpRouteContext.Spec.Rules = append(httpRouteContext.Spec.Rules, gatewayv1.HTTPRouteRule{
+				Matches: []gatewayv1.HTTPRouteMatch{{
+					Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/")},
+				}},
+				BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: *backend}}},
+			})
+			index := len(httpRouteContext.Spec.Rules) - 1
+			// The policies of the Ingress apply to the requests of its
+			// default backend too.
+			if routeIR := httpRouteContext.ProviderSpecificIR.IngressNginx; routeIR != nil {
+				if _, ok := routeIR.Policies[ingressKey.Name]; ok {
+					patchPolicy(&httpRouteContext, ingressKey.Name, []int{index}, func(*intermediate.IngressNginxPolicy) {})
+				}
+			}
+			notify(notifications.InfoNotification, fmt.Sprintf("parsed \"%v\" annotation of ingress %s/%s and added %v", defaultBackendAnnotation, ingressKey.Namespace, ingressKey.Name, field.NewPath("httproute", "spec", "rules").Index(index)), &httpRouteContext.HTTPRoute)
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+}
+
+// catchAllRuleIndex returns the index of the rule of the HTTPRoute matching
+// all the requests, or -1 if it has none.
+func catchAllRuleIndex(httpRoute gatewayv1.HTTPRoute) int {
+	for i, rule := range httpRoute.Spec.Rules {
+		if len(rule.Matches) == 0 {
+			return i
+		}
+		for _, match := range rule.Matches {
+			if len(match.Headers) > 0 || len(match.QueryParams) > 0 || match.Method != nil {
+				continue
+			}
+			if match.Path == nil || (match.Path.Type != nil && *match.Path.Type == gatewayv1.PathMatchPathPrefix && match.Path.Value != nil && *match.Path.Value == "/") {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseCustomHTTPErrors returns the CustomHTTPErrorsConfig of the Ingress,
+// served by the given default backend, or nil if it doesn't replace the error
+// responses of its backends.
+func parseCustomHTTPErrors(ingress networkingv1.Ingress, backend *gatewayv1.BackendObjectReference) (*intermediate.CustomHTTPErrorsConfig, *field.Error) {
+	value, ok := ingress.Annotations[customHTTPErrorsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	annotationPath := field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations").Key(customHTTPErrorsAnnotation)
+
+	var codes []int32
+	for _, code := range strings.Split(value, ",") {
+		parsed, err := strconv.ParseInt(strings.TrimSpace(code), 10, 32)
+		if err != nil || parsed < 400 || parsed > 599 {
+			return nil, field.Invalid(annotationPath, value, "must be a comma-separated list of HTTP error status codes")
+		}
+		if !slices.Contains(codes, int32(parsed)) {
+			codes = append(codes, int32(parsed))
+		}
+	}
+	slices.Sort(codes)
+	return &intermediate.CustomHTTPErrorsConfig{Codes: codes, Backend: backend}, nil
+}
+
+// parseDefaultBackendService parses the <namespace>/<name> of the Service of
+// the default backend of the controller, returning nil if it is not set.
+func parseDefaultBackendService(value string) (*gatewayv1.BackendObjectReference, *field.Error) {
+	if value == "" {
+		return nil, nil
+	}
+	namespace, name, found := strings.Cut(value, "/")
+	if !found || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return nil, field.Invalid(field.NewPath(fmt.Sprintf("--%s-%s", Name, DefaultBackendServiceFlag)), value, "must be <namespace>/<name>")
+	}
+	return &gatewayv1.BackendObjectReference{
+		Namespace: ptr.To(gatewayv1.Namespace(namespace)),
+		Name:      gatewayv1.ObjectName(name),
+		Port:      ptr.To(defaultBackendPort),
+	}, nil
+}
+
+// controllerDefaultBackendRoutes generates an HTTPRoute routing the requests
+// matching no other route of each Gateway to the default backend of the
+// controller, along with an HTTP listener without hostname for the requests of
+// other hosts, and the ReferenceGrant allowing the references to the Service
+// from the namespaces of the Gateways.
+func controllerDefaultBackendRoutes(ir *intermediate.IR, backend gatewayv1.BackendObjectReference) {
+	gatewayKeys := make([]types.NamespacedName, 0, len(ir.Gateways))
+	for key := range ir.Gateways {
+		gatewayKeys = append(gatewayKeys, key)
+	}
+	slices.SortFunc(gatewayKeys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	serviceNamespace := string(*backend.Namespace)
+	for _, gatewayKey := range gatewayKeys {
+		gatewayContext := ir.Gateways[gatewayKey]
+		if !slices.ContainsFunc(gatewayContext.Spec.Listeners, func(listener gatewayv1.Listener) bool {
+			return listener.Protocol == gatewayv1.HTTPProtocolType && listener.Port == 80 && listener.Hostname == nil
+		}) {
+			gatewayContext.Spec.Listeners = append(gatewayContext.Spec.Listeners, gatewayv1.Listener{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType})
+			ir.Gateways[gatewayKey] = gatewayContext
+		}
+
+		routeBackend := backend
+		if serviceNamespace == gatewayKey.Namespace {
+			routeBackend.Namespace = nil
+		} else {
+			addDefaultBackendReferenceGrant(ir, gatewayKey.Namespace, backend)
+		}
+		routeKey := types.NamespacedName{Namespace: gatewayKey.Namespace, Name: gatewayKey.Name + "-controller-default-backend"}
+		httpRoute := gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gatewayKey.Name)}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: routeBackend}}},
+				}},
+			},
+		}
+		httpRoute.SetGroupVersionKind(common.HTTPRouteGVK)
+		ir.HTTPRoutes[routeKey] = intermediate.HTTPRouteContext{HTTPRoute: httpRoute}
+		notify(notifications.InfoNotification, fmt.Sprintf("generated HTTPRoute %s routing the requests of Gateway %s matching no other route to the default backend Service %s/%s of the controller, assumed to listen on port %d", routeKey, gatewayKey, serviceNamespace, backend.Name, defaultBackendPort), &httpRoute)
+	}
+}
+
+// addDefaultBackendReferenceGrant allows the HTTPRoutes of the namespace to
+// reference the Service of the default backend of the controller.
+func addDefaultBackendReferenceGrant(ir *intermediate.IR, fromNamespace string, backend gatewayv1.BackendObjectReference) {
+	key := types.NamespacedName{Namespace: string(*backend.Namespace), Name: fmt.Sprintf("%s-from-%s", backend.Name, fromNamespace)}
+	if _, ok := ir.ReferenceGrants[key]; ok {
+		return
+	}
+	referenceGrant := gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: gatewayv1.Namespace(fromNamespace)}},
+			To:   []gatewayv1beta1.ReferenceGrantTo{{Group: "", Kind: "Service", Name: ptr.To(backend.Name)}},
+		},
+	}
+	referenceGrant.SetGroupVersionKind(common.ReferenceGrantGVK)
+	if ir.ReferenceGrants == nil {
+		ir.ReferenceGrants = map[types.NamespacedName]gatewayv1beta1.ReferenceGrant{}
+	}
+	ir.ReferenceGrants[key] = referenceGrant
+}
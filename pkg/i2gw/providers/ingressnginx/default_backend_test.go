@@ -0,0 +1,229 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func defaultBackendTestIngress(path string, annotations map[string]string) networkingv1.Ingress {
+	iPrefix := networkingv1.PathTypePrefix
+	return networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: annotations},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptrTo("nginx"),
+			Rules: []networkingv1.IngressRule{{
+				Host: "example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     path,
+							PathType: &iPrefix,
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: "app",
+									Port: networkingv1.ServiceBackendPort{Number: 80},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func Test_defaultBackendFeature(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		path                  string
+		defaultBackendService string
+		expectedRules         int
+		expectedCustomErrors  *intermediate.CustomHTTPErrorsConfig
+		expectedErrors        int
+	}{
+		{
+			name:          "catch-all rule added for the default backend",
+			path:          "/api",
+			expectedRules: 2,
+			expectedCustomErrors: &intermediate.CustomHTTPErrorsConfig{
+				Codes:   []int32{404, 503},
+				Backend: &gatewayv1.BackendObjectReference{Name: "errors", Port: ptrTo[gatewayv1.PortNumber](80)},
+			},
+		},
+		{
+			name:          "no catch-all rule when the Ingress routes all paths",
+			path:          "/",
+			expectedRules: 1,
+			expectedCustomErrors: &intermediate.CustomHTTPErrorsConfig{
+				Codes:   []int32{404, 503},
+				Backend: &gatewayv1.BackendObjectReference{Name: "errors", Port: ptrTo[gatewayv1.PortNumber](80)},
+			},
+		},
+		{
+			name:                  "invalid controller default backend",
+			path:                  "/api",
+			defaultBackendService: "default-http-backend",
+			expectedRules:         1,
+			expectedErrors:        1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingresses := []networkingv1.Ingress{defaultBackendTestIngress(tc.path, map[string]string{
+				defaultBackendAnnotation:   "errors",
+				customHTTPErrorsAnnotation: "503, 404,503",
+			})}
+			ir, errs := common.ToIR(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) > 0 {
+				t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+			}
+			errs = defaultBackendFeature(tc.defaultBackendService)(ingresses, &ir)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+
+			routeKey := types.NamespacedName{Namespace: "default", Name: common.RouteName("app", "example.com")}
+			rules := ir.HTTPRoutes[routeKey].Spec.Rules
+			if len(rules) != tc.expectedRules {
+				t.Fatalf("Expected %d rules, got %d", tc.expectedRules, len(rules))
+			}
+			if tc.expectedRules == 2 {
+				expectedRule := gatewayv1.HTTPRouteRule{
+					Matches: []gatewayv1.HTTPRouteMatch{{
+						Path: &gatewayv1.HTTPPathMatch{Type: ptrTo(gatewayv1.PathMatchPathPrefix), Value: ptrTo("/")},
+					}},
+					BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{Name: "errors", Port: ptrTo[gatewayv1.PortNumber](80)},
+					}}},
+				}
+				if diff := cmp.Diff(expectedRule, rules[1]); diff != "" {
+					t.Errorf("Unexpected default backend rule (-want +got): %s", diff)
+				}
+			}
+
+			var customErrors *intermediate.CustomHTTPErrorsConfig
+			if routeIR := ir.HTTPRoutes[routeKey].ProviderSpecificIR.IngressNginx; routeIR != nil {
+				if policy, ok := routeIR.Policies["app"]; ok {
+					customErrors = policy.CustomHTTPErrors
+				}
+			}
+			if diff := cmp.Diff(tc.expectedCustomErrors, customErrors); diff != "" {
+				t.Errorf("Unexpected CustomHTTPErrors (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_defaultBackendFeature_controllerDefaultBackend(t *testing.T) {
+	ingresses := []networkingv1.Ingress{defaultBackendTestIngress("/", nil)}
+	ir, errs := common.ToIR(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+	}
+	if errs = defaultBackendFeature("ingress-nginx/default-http-backend")(ingresses, &ir); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+	gatewayContext, ok := ir.Gateways[gatewayKey]
+	if !ok {
+		t.Fatalf("Expected Gateway %s, got %v", gatewayKey, ir.Gateways)
+	}
+	var catchAllListeners int
+	for _, listener := range gatewayContext.Spec.Listeners {
+		if listener.Protocol == gatewayv1.HTTPProtocolType && listener.Port == 80 && listener.Hostname == nil {
+			catchAllListeners++
+		}
+	}
+	if catchAllListeners != 1 {
+		t.Errorf("Expected 1 HTTP listener without hostname, got %d", catchAllListeners)
+	}
+
+	routeKey := types.NamespacedName{Namespace: "default", Name: "nginx-controller-default-backend"}
+	httpRouteContext, ok := ir.HTTPRoutes[routeKey]
+	if !ok {
+		t.Fatalf("Expected HTTPRoute %s", routeKey)
+	}
+	expectedSpec := gatewayv1.HTTPRouteSpec{
+		CommonRouteSpec: gatewayv1.CommonRouteSpec{
+			ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}},
+		},
+		Rules: []gatewayv1.HTTPRouteRule{{
+			BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+				Namespace: ptrTo[gatewayv1.Namespace]("ingress-nginx"),
+				Name:      "default-http-backend",
+				Port:      ptrTo[gatewayv1.PortNumber](80),
+			}}}},
+		}},
+	}
+	if diff := cmp.Diff(expectedSpec, httpRouteContext.Spec); diff != "" {
+		t.Errorf("Unexpected HTTPRoute spec (-want +got): %s", diff)
+	}
+
+	grantKey := types.NamespacedName{Namespace: "ingress-nginx", Name: "default-http-backend-from-default"}
+	referenceGrant, ok := ir.ReferenceGrants[grantKey]
+	if !ok {
+		t.Fatalf("Expected ReferenceGrant %s", grantKey)
+	}
+	if len(referenceGrant.Spec.From) != 1 || referenceGrant.Spec.From[0].Namespace != "default" {
+		t.Errorf("Unexpected ReferenceGrant from: %v", referenceGrant.Spec.From)
+	}
+}
+
+func Test_parseCustomHTTPErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		value         string
+		expectedCodes []int32
+		expectedError bool
+	}{
+		{name: "single code", value: "404", expectedCodes: []int32{404}},
+		{name: "sorted and deduplicated", value: "503,404, 503", expectedCodes: []int32{404, 503}},
+		{name: "not an error code", value: "404,302", expectedError: true},
+		{name: "not a number", value: "404,not-found", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := defaultBackendTestIngress("/", map[string]string{customHTTPErrorsAnnotation: tc.value})
+			config, err := parseCustomHTTPErrors(ingress, nil)
+			if tc.expectedError {
+				if err == nil {
+					t.Fatalf("Expected error, got %v", config)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedCodes, config.Codes); diff != "" {
+				t.Errorf("Unexpected codes (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	authURLAnnotation             = "nginx.ingress.kubernetes.io/auth-url"
+	authMethodAnnotation          = "nginx.ingress.kubernetes.io/auth-method"
+	authSigninAnnotation          = "nginx.ingress.kubernetes.io/auth-signin"
+	authResponseHeadersAnnotation = "nginx.ingress.kubernetes.io/auth-response-headers"
+)
+
+// externalAuthFeature parses the nginx.ingress.kubernetes.io/auth-url, nginx.ingress.kubernetes.io/auth-method,
+// nginx.ingress.kubernetes.io/auth-signin and nginx.ingress.kubernetes.io/auth-response-headers annotations
+// into the ExternalAuth policy of the ingress-nginx HTTPRoute IR.
+//
+// The Gateway API has no core equivalent for external authentication, so the policy can only be honored by
+// implementation-specific emitters.
+func externalAuthFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	return patchIngressPolicies(ingresses, ir, func(ingress networkingv1.Ingress) (func(*intermediate.IngressNginxPolicy), field.ErrorList) {
+		externalAuth, errs := parseExternalAuthAnnotations(ingress)
+		if externalAuth == nil {
+			return nil, errs
+		}
+		notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s requires external authentication, which has no Gateway API equivalent: the authentication is only kept for implementation-specific emitters, the requests are not authenticated otherwise", ingress.Namespace, ingress.Name), &ingress)
+		return func(policy *intermediate.IngressNginxPolicy) {
+			policy.ExternalAuth = externalAuth
+		}, errs
+	})
+}
+
+// parseExternalAuthAnnotations returns the ExternalAuthConfig of the Ingress, or nil if the
+// requests are not authenticated with an external service.
+func parseExternalAuthAnnotations(ingress networkingv1.Ingress) (*intermediate.ExternalAuthConfig, field.ErrorList) {
+	annotationsPath := field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations")
+
+	authURL, ok := ingress.Annotations[authURLAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	if u, err := url.Parse(authURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, field.ErrorList{field.Invalid(annotationsPath.Key(authURLAnnotation), authURL, "must be an absolute http or https URL")}
+	}
+
+	var responseHeaders []string
+	for _, header := range strings.Split(ingress.Annotations[authResponseHeadersAnnotation], ",") {
+		if header = strings.TrimSpace(header); header != "" {
+			responseHeaders = append(responseHeaders, header)
+		}
+	}
+
+	return &intermediate.ExternalAuthConfig{
+		URL:             authURL,
+		Method:          strings.ToUpper(ingress.Annotations[authMethodAnnotation]),
+		SigninURL:       ingress.Annotations[authSigninAnnotation],
+		ResponseHeaders: responseHeaders,
+	}, nil
+}
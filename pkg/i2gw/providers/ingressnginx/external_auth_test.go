@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_parseExternalAuthAnnotations(t *testing.T) {
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		expected       *intermediate.ExternalAuthConfig
+		expectedErrors int
+	}{
+		{
+			name:        "no authentication",
+			annotations: map[string]string{},
+		},
+		{
+			name:        "external authentication",
+			annotations: map[string]string{authURLAnnotation: "http://auth.default.svc/verify"},
+			expected:    &intermediate.ExternalAuthConfig{URL: "http://auth.default.svc/verify"},
+		},
+		{
+			name: "external authentication with sign-in and response headers",
+			annotations: map[string]string{
+				authURLAnnotation:             "https://auth.example.com/oauth2/auth",
+				authMethodAnnotation:          "post",
+				authSigninAnnotation:          "https://auth.example.com/oauth2/start",
+				authResponseHeadersAnnotation: "X-User, X-Email,",
+			},
+			expected: &intermediate.ExternalAuthConfig{
+				URL:             "https://auth.example.com/oauth2/auth",
+				Method:          "POST",
+				SigninURL:       "https://auth.example.com/oauth2/start",
+				ResponseHeaders: []string{"X-User", "X-Email"},
+			},
+		},
+		{
+			name:           "relative URL",
+			annotations:    map[string]string{authURLAnnotation: "/verify"},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: tc.annotations}}
+			externalAuth, errs := parseExternalAuthAnnotations(ingress)
+			if len(errs) != tc.expectedErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if diff := cmp.Diff(tc.expected, externalAuth); diff != "" {
+				t.Errorf("Unexpected external auth (-want +got): %s", diff)
+			}
+		})
+	}
+}
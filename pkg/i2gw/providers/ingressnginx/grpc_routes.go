@@ -0,0 +1,199 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// grpcRoutesFeature converts the HTTPRoutes of the Ingresses whose
+// nginx.ingress.kubernetes.io/backend-protocol annotation is GRPC or GRPCS to
+// GRPCRoutes. The BackendTLSPolicies of the GRPCS backends are generated by the
+// backend-tls feature.
+//
+// A route is only converted when all the Ingresses of its host route to gRPC
+// backends, its paths are either / or gRPC service and method paths, and it has
+// no policy or unsupported feature only expressible for HTTPRoutes. The filters
+// without GRPCRoute equivalent, e.g. redirects and rewrites, are dropped with a
+// warning.
+func grpcRoutesFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses, ir)
+	rgKeys := make([]string, 0, len(ruleGroups))
+	for rgKey := range ruleGroups {
+		rgKeys = append(rgKeys, rgKey)
+	}
+	slices.Sort(rgKeys)
+	for _, rgKey := range rgKeys {
+		rg := ruleGroups[rgKey]
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRouteContext, ok := ir.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		var grpcIngresses, httpIngresses []*networkingv1.Ingress
+		for i := range rg.Rules {
+			ingress := &rg.Rules[i].Ingress
+			if slices.ContainsFunc(grpcIngresses, sameIngress(ingress)) || slices.ContainsFunc(httpIngresses, sameIngress(ingress)) {
+				continue
+			}
+			if isGRPCBackendProtocol(ingress.Annotations[backendProtocolAnnotation]) {
+				grpcIngresses = append(grpcIngresses, ingress)
+			} else {
+				httpIngresses = append(httpIngresses, ingress)
+			}
+		}
+		if len(grpcIngresses) == 0 {
+			continue
+		}
+		if len(httpIngresses) > 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s routes to gRPC backends, but ingress %s/%s sharing HTTPRoute %s doesn't, the route was not converted to a GRPCRoute",
+				grpcIngresses[0].Namespace, grpcIngresses[0].Name, httpIngresses[0].Namespace, httpIngresses[0].Name, key), &httpRouteContext.HTTPRoute)
+			continue
+		}
+
+		grpcRoute, ok := toGRPCRoute(httpRouteContext)
+		if !ok {
+			continue
+		}
+		if ir.GRPCRoutes == nil {
+			ir.GRPCRoutes = map[types.NamespacedName]gatewayv1.GRPCRoute{}
+		}
+		ir.GRPCRoutes[key] = *grpcRoute
+		delete(ir.HTTPRoutes, key)
+		notify(notifications.InfoNotification, fmt.Sprintf("parsed \"%v\" annotation of ingress %s/%s and converted HTTPRoute %s to a GRPCRoute", backendProtocolAnnotation, grpcIngresses[0].Namespace, grpcIngresses[0].Name, key), grpcRoute)
+	}
+	return nil
+}
+
+// isGRPCBackendProtocol returns whether the value of the backend-protocol
+// annotation is one of the gRPC protocols.
+func isGRPCBackendProtocol(protocol string) bool {
+	protocol = strings.ToUpper(strings.TrimSpace(protocol))
+	return protocol == "GRPC" || protocol == "GRPCS"
+}
+
+// toGRPCRoute converts the HTTPRoute of gRPC Ingresses to a GRPCRoute. It
+// returns false, with a warning, if the route can't be expressed as a
+// GRPCRoute.
+func toGRPCRoute(httpRouteContext intermediate.HTTPRouteContext) (*gatewayv1.GRPCRoute, bool) {
+	httpRoute := &httpRouteContext.HTTPRoute
+	key := types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}
+	notConverted := func(reason string) (*gatewayv1.GRPCRoute, bool) {
+		notify(notifications.WarningNotification, fmt.Sprintf("HTTPRoute %s routes to gRPC backends but %s, it was not converted to a GRPCRoute", key, reason), httpRoute)
+		return nil, false
+	}
+
+	if len(httpRouteContext.UnsupportedFeatures) > 0 {
+		return notConverted("has features only reported for HTTPRoutes")
+	}
+	if routeIR := httpRouteContext.ProviderSpecificIR.IngressNginx; routeIR != nil {
+		for ingressName, policy := range routeIR.Policies {
+			if !isGRPCOnlyPolicy(policy) {
+				return notConverted(fmt.Sprintf("ingress %s/%s has policies only converted for HTTPRoutes", key.Namespace, ingressName))
+			}
+		}
+	}
+
+	apiVersion, kind := common.GRPCRouteGVK.ToAPIVersionAndKind()
+	grpcRoute := &gatewayv1.GRPCRoute{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiVersion,
+			Kind:       kind,
+		},
+		ObjectMeta: *httpRoute.ObjectMeta.DeepCopy(),
+		Spec: gatewayv1.GRPCRouteSpec{
+			CommonRouteSpec: *httpRoute.Spec.CommonRouteSpec.DeepCopy(),
+			Hostnames:       slices.Clone(httpRoute.Spec.Hostnames),
+		},
+	}
+
+	var dropped []gatewayv1.HTTPRouteFilter
+	for _, rule := range httpRoute.Spec.Rules {
+		grpcRule := gatewayv1.GRPCRouteRule{}
+		for _, match := range rule.Matches {
+			if len(match.QueryParams) > 0 || match.Method != nil {
+				return notConverted("matches query parameters or methods")
+			}
+			grpcMatch := gatewayv1.GRPCRouteMatch{}
+			if match.Path != nil && !isRootPathPrefix(match.Path) {
+				methodMatch, ok := common.GRPCMethodMatch(match.Path)
+				if !ok {
+					return notConverted(fmt.Sprintf("its path %s is neither / nor a gRPC service or method", ptr.Deref(match.Path.Value, "")))
+				}
+				grpcMatch.Method = methodMatch
+			}
+			for _, header := range match.Headers {
+				grpcMatch.Headers = append(grpcMatch.Headers, gatewayv1.GRPCHeaderMatch{
+					Type:  header.Type,
+					Name:  gatewayv1.GRPCHeaderName(header.Name),
+					Value: header.Value,
+				})
+			}
+			grpcRule.Matches = append(grpcRule.Matches, grpcMatch)
+		}
+		if rule.Timeouts != nil {
+			notify(notifications.WarningNotification, fmt.Sprintf("the timeouts of HTTPRoute %s have no GRPCRoute equivalent and were dropped", key), grpcRoute)
+		}
+
+		filters, unsupported := common.ConvertHTTPFiltersToGRPCFilters(rule.Filters)
+		grpcRule.Filters = filters
+		dropped = append(dropped, unsupported...)
+		for _, backendRef := range rule.BackendRefs {
+			backendFilters, unsupported := common.ConvertHTTPFiltersToGRPCFilters(backendRef.Filters)
+			dropped = append(dropped, unsupported...)
+			grpcRule.BackendRefs = append(grpcRule.BackendRefs, gatewayv1.GRPCBackendRef{
+				BackendRef: backendRef.BackendRef,
+				Filters:    backendFilters,
+			})
+		}
+		grpcRoute.Spec.Rules = append(grpcRoute.Spec.Rules, grpcRule)
+	}
+
+	for _, filter := range dropped {
+		notify(notifications.WarningNotification, fmt.Sprintf("the %s filter of HTTPRoute %s is not supported on GRPCRoutes and was dropped", filter.Type, key), grpcRoute)
+	}
+	return grpcRoute, true
+}
+
+// isRootPathPrefix returns whether the path match matches all the paths.
+func isRootPathPrefix(path *gatewayv1.HTTPPathMatch) bool {
+	return ptr.Deref(path.Type, gatewayv1.PathMatchPathPrefix) == gatewayv1.PathMatchPathPrefix && ptr.Deref(path.Value, "/") == "/"
+}
+
+// isGRPCOnlyPolicy returns whether the policy only proxies the requests to
+// the backends with HTTP/2, which GRPCRoutes imply.
+func isGRPCOnlyPolicy(policy intermediate.IngressNginxPolicy) bool {
+	policy.RuleIndices = nil
+	if policy.UpstreamConnection != nil && reflect.DeepEqual(*policy.UpstreamConnection, intermediate.UpstreamConnectionConfig{HTTPVersion: intermediate.HTTPVersion2}) {
+		policy.UpstreamConnection = nil
+	}
+	return reflect.DeepEqual(policy, intermediate.IngressNginxPolicy{})
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_grpcRoutesFeature(t *testing.T) {
+	iPrefix := networkingv1.PathTypePrefix
+
+	testIngress := func(name string, annotations map[string]string, path string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptrTo("nginx"),
+				Rules: []networkingv1.IngressRule{{
+					Host: "grpc.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path:     path,
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: name,
+										Port: networkingv1.ServiceBackendPort{Number: 50051},
+									},
+								},
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+	grpcAnnotations := func(annotations map[string]string) map[string]string {
+		annotations[backendProtocolAnnotation] = "GRPC"
+		return annotations
+	}
+	routeKey := types.NamespacedName{Namespace: "default", Name: common.RouteName("greeter", "grpc.example.com")}
+
+	testCases := []struct {
+		name            string
+		ingresses       []networkingv1.Ingress
+		expectedMatches []gatewayv1.GRPCRouteMatch
+	}{
+		{
+			name:            "all the paths",
+			ingresses:       []networkingv1.Ingress{testIngress("greeter", grpcAnnotations(map[string]string{}), "/")},
+			expectedMatches: []gatewayv1.GRPCRouteMatch{{}},
+		},
+		{
+			name:      "gRPC service path",
+			ingresses: []networkingv1.Ingress{testIngress("greeter", grpcAnnotations(map[string]string{}), "/helloworld.Greeter")},
+			expectedMatches: []gatewayv1.GRPCRouteMatch{{
+				Method: &gatewayv1.GRPCMethodMatch{Type: ptrTo(gatewayv1.GRPCMethodMatchExact), Service: ptrTo("helloworld.Greeter")},
+			}},
+		},
+		{
+			name:      "path other than a gRPC service",
+			ingresses: []networkingv1.Ingress{testIngress("greeter", grpcAnnotations(map[string]string{}), "/api")},
+		},
+		{
+			name: "ingress of the route without gRPC backends",
+			ingresses: []networkingv1.Ingress{
+				testIngress("greeter", grpcAnnotations(map[string]string{}), "/"),
+				testIngress("web", map[string]string{}, "/web"),
+			},
+		},
+		{
+			name:      "policy only converted for HTTPRoutes",
+			ingresses: []networkingv1.Ingress{testIngress("greeter", grpcAnnotations(map[string]string{"nginx.ingress.kubernetes.io/limit-rps": "10"}), "/")},
+		},
+		{
+			name:      "HTTP backends",
+			ingresses: []networkingv1.Ingress{testIngress("greeter", map[string]string{}, "/")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir, errs := common.ToIR(tc.ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) > 0 {
+				t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+			}
+
+			if errs = newResourcesToIRConverter(&i2gw.ProviderConf{}).featureChain.Run(tc.ingresses, &ir); len(errs) > 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+
+			grpcRoute, ok := ir.GRPCRoutes[routeKey]
+			if _, httpRouteOK := ir.HTTPRoutes[routeKey]; ok == httpRouteOK {
+				t.Fatalf("Expected the route to be either an HTTPRoute or a GRPCRoute, got HTTPRoute: %t, GRPCRoute: %t", httpRouteOK, ok)
+			}
+			if ok != (tc.expectedMatches != nil) {
+				t.Fatalf("Expected GRPCRoute: %t, got: %t", tc.expectedMatches != nil, ok)
+			}
+			if !ok {
+				return
+			}
+			if len(grpcRoute.Spec.Rules) != 1 {
+				t.Fatalf("Expected 1 GRPCRoute rule, got %d", len(grpcRoute.Spec.Rules))
+			}
+			if diff := cmp.Diff(tc.expectedMatches, grpcRoute.Spec.Rules[0].Matches); diff != "" {
+				t.Errorf("Unexpected GRPCRoute matches (-want +got): %s", diff)
+			}
+			expectedBackendRefs := []gatewayv1.GRPCBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: "greeter",
+				Port: ptrTo[gatewayv1.PortNumber](50051),
+			}}}}
+			if diff := cmp.Diff(expectedBackendRefs, grpcRoute.Spec.Rules[0].BackendRefs); diff != "" {
+				t.Errorf("Unexpected GRPCRoute backendRefs (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -30,8 +30,47 @@ import (
 const Name = "ingress-nginx"
 const NginxIngressClass = "nginx"
 
+const (
+	// TCPServicesConfigMapFlag is the provider-specific flag setting the
+	// <namespace>/<name> of the ConfigMap of the TCP services of the controller.
+	TCPServicesConfigMapFlag = "tcp-services-configmap"
+	// UDPServicesConfigMapFlag is the provider-specific flag setting the
+	// <namespace>/<name> of the ConfigMap of the UDP services of the controller.
+	UDPServicesConfigMapFlag = "udp-services-configmap"
+	// DefaultBackendServiceFlag is the provider-specific flag setting the
+	// <namespace>/<name> of the Service of the default backend of the
+	// controller.
+	DefaultBackendServiceFlag = "default-backend-service"
+)
+
+// The default ConfigMaps of the TCP and UDP services, named as by the
+// ingress-nginx Helm chart.
+const (
+	defaultTCPServicesConfigMap = "ingress-nginx/ingress-nginx-tcp"
+	defaultUDPServicesConfigMap = "ingress-nginx/ingress-nginx-udp"
+)
+
 func init() {
 	i2gw.ProviderConstructorByName[Name] = NewProvider
+	i2gw.RegisterProviderSupportLevel(Name, i2gw.StableSupportLevel)
+	i2gw.RegisterIngressClaimRule(Name, i2gw.IngressClaimRule{
+		IngressClasses:     []string{NginxIngressClass},
+		AnnotationPrefixes: []string{"nginx.ingress.kubernetes.io/"},
+	})
+	i2gw.RegisterProviderSpecificFlag(Name, i2gw.ProviderSpecificFlag{
+		Name:         TCPServicesConfigMapFlag,
+		Description:  "The <namespace>/<name> of the ConfigMap of the TCP services exposed by ingress-nginx, converted to TCP listeners and TCPRoutes.",
+		DefaultValue: defaultTCPServicesConfigMap,
+	})
+	i2gw.RegisterProviderSpecificFlag(Name, i2gw.ProviderSpecificFlag{
+		Name:         UDPServicesConfigMapFlag,
+		Description:  "The <namespace>/<name> of the ConfigMap of the UDP services exposed by ingress-nginx, converted to UDP listeners and UDPRoutes.",
+		DefaultValue: defaultUDPServicesConfigMap,
+	})
+	i2gw.RegisterProviderSpecificFlag(Name, i2gw.ProviderSpecificFlag{
+		Name:        DefaultBackendServiceFlag,
+		Description: "The <namespace>/<name> of the Service of the default backend of ingress-nginx, set by its --default-backend-service flag, routed the requests matching no route of the Gateways to.",
+	})
 }
 
 // Provider implements the i2gw.Provider interface.
@@ -46,7 +85,7 @@ func NewProvider(conf *i2gw.ProviderConf) i2gw.Provider {
 	return &Provider{
 		storage:                newResourcesStorage(),
 		resourceReader:         newResourceReader(conf),
-		resourcesToIRConverter: newResourcesToIRConverter(),
+		resourcesToIRConverter: newResourcesToIRConverter(conf),
 	}
 }
 
@@ -57,8 +96,12 @@ func (p *Provider) ToIR() (intermediate.IR, field.ErrorList) {
 }
 
 func (p *Provider) ToGatewayResources(ir intermediate.IR) (i2gw.GatewayResources, field.ErrorList) {
-	return common.ToGatewayResources(ir)
-
+	gatewayResources, errs := common.ToGatewayResources(ir)
+	if len(errs) > 0 {
+		return gatewayResources, errs
+	}
+	gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, externalNameServices(ir)...)
+	return gatewayResources, nil
 }
 
 func (p *Provider) ReadResourcesFromCluster(ctx context.Context) error {
@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// l4Service is an entry of the ConfigMap of the TCP or UDP services,
+// <port>: <namespace>/<service>:<service port>[:PROXY][:PROXY], exposing the
+// port of the Service on the given port of the controller.
+type l4Service struct {
+	port        gatewayv1.PortNumber
+	service     types.NamespacedName
+	servicePort gatewayv1.PortNumber
+	// proxyProtocol indicates whether the PROXY protocol is decoded from the
+	// clients or encoded to the backend.
+	proxyProtocol bool
+}
+
+// convertL4Services converts the entries of the ConfigMap of the TCP or UDP
+// services to listeners of the ingress-nginx Gateway of the namespace of their
+// Service, and to TCPRoutes or UDPRoutes attached to them. Invalid entries are
+// skipped with a warning, as ingress-nginx does.
+func convertL4Services(configMap *apiv1.ConfigMap, protocol gatewayv1.ProtocolType, namespace string, ir *intermediate.IR) {
+	if configMap == nil {
+		return
+	}
+	configMapKey := types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}
+
+	ports := make([]string, 0, len(configMap.Data))
+	for port := range configMap.Data {
+		ports = append(ports, port)
+	}
+	sort.Strings(ports)
+
+	for _, port := range ports {
+		service, err := parseL4Service(port, configMap.Data[port])
+		if err != nil {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s service of port %s of ConfigMap %s was not converted: %v", protocol, port, configMapKey, err), configMap)
+			continue
+		}
+		if namespace != "" && service.service.Namespace != namespace {
+			continue
+		}
+		if service.proxyProtocol {
+			notify(notifications.WarningNotification, fmt.Sprintf("the PROXY protocol of the %s service of port %s of ConfigMap %s has no Gateway API equivalent, it was not converted", protocol, port, configMapKey), configMap)
+		}
+
+		listenerName := gatewayv1.SectionName(fmt.Sprintf("%s-%d", strings.ToLower(string(protocol)), service.port))
+		gatewayKey := types.NamespacedName{Namespace: service.service.Namespace, Name: NginxIngressClass}
+		if !addL4Listener(ir, gatewayKey, gatewayv1.Listener{Name: listenerName, Port: service.port, Protocol: protocol}) {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s service of port %s of ConfigMap %s was not converted: Gateway %s already has a listener %s", protocol, port, configMapKey, gatewayKey, listenerName), configMap)
+			continue
+		}
+
+		routeKey := types.NamespacedName{Namespace: service.service.Namespace, Name: fmt.Sprintf("%s-%s", service.service.Name, listenerName)}
+		commonRouteSpec := gatewayv1.CommonRouteSpec{
+			ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gatewayKey.Name), SectionName: ptr.To(listenerName)}},
+		}
+		backendRefs := []gatewayv1.BackendRef{{
+			BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: gatewayv1.ObjectName(service.service.Name),
+				Port: ptr.To(service.servicePort),
+			},
+		}}
+		switch protocol {
+		case gatewayv1.TCPProtocolType:
+			tcpRoute := gatewayv1alpha2.TCPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+				Spec: gatewayv1alpha2.TCPRouteSpec{
+					CommonRouteSpec: commonRouteSpec,
+					Rules:           []gatewayv1alpha2.TCPRouteRule{{BackendRefs: backendRefs}},
+				},
+			}
+			tcpRoute.SetGroupVersionKind(common.TCPRouteGVK)
+			if ir.TCPRoutes == nil {
+				ir.TCPRoutes = map[types.NamespacedName]gatewayv1alpha2.TCPRoute{}
+			}
+			ir.TCPRoutes[routeKey] = tcpRoute
+		case gatewayv1.UDPProtocolType:
+			udpRoute := gatewayv1alpha2.UDPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+				Spec: gatewayv1alpha2.UDPRouteSpec{
+					CommonRouteSpec: commonRouteSpec,
+					Rules:           []gatewayv1alpha2.UDPRouteRule{{BackendRefs: backendRefs}},
+				},
+			}
+			udpRoute.SetGroupVersionKind(common.UDPRouteGVK)
+			if ir.UDPRoutes == nil {
+				ir.UDPRoutes = map[types.NamespacedName]gatewayv1alpha2.UDPRoute{}
+			}
+			ir.UDPRoutes[routeKey] = udpRoute
+		}
+	}
+}
+
+// parseL4Service parses an entry of the ConfigMap of the TCP or UDP services.
+// Named Service ports are not supported, since the Services are not read.
+func parseL4Service(port, value string) (l4Service, error) {
+	var service l4Service
+	portNumber, err := strconv.ParseInt(port, 10, 32)
+	if err != nil || portNumber < 1 || portNumber > 65535 {
+		return service, fmt.Errorf("invalid port %q", port)
+	}
+	// The HTTP and HTTPS ports are reserved by ingress-nginx.
+	if portNumber == 80 || portNumber == 443 {
+		return service, fmt.Errorf("port %d is reserved for HTTP and HTTPS", portNumber)
+	}
+	service.port = gatewayv1.PortNumber(portNumber)
+
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 {
+		return service, fmt.Errorf("invalid service %q, expected <namespace>/<service>:<port>", value)
+	}
+	namespace, name, found := strings.Cut(parts[0], "/")
+	if !found || namespace == "" || name == "" {
+		return service, fmt.Errorf("invalid service %q, expected <namespace>/<service>:<port>", value)
+	}
+	service.service = types.NamespacedName{Namespace: namespace, Name: name}
+
+	servicePort, err := strconv.ParseInt(parts[1], 10, 32)
+	if err != nil || servicePort < 1 || servicePort > 65535 {
+		return service, fmt.Errorf("invalid port %q of service %s, only port numbers are supported", parts[1], service.service)
+	}
+	service.servicePort = gatewayv1.PortNumber(servicePort)
+
+	// The options decode and encode the PROXY protocol, e.g. ::PROXY only
+	// encodes it.
+	if len(parts) > 4 {
+		return service, fmt.Errorf("invalid service %q, expected <namespace>/<service>:<port>[:PROXY][:PROXY]", value)
+	}
+	for _, option := range parts[2:] {
+		switch option {
+		case "":
+		case "PROXY":
+			service.proxyProtocol = true
+		default:
+			return service, fmt.Errorf("invalid option %q of service %s", option, service.service)
+		}
+	}
+	return service, nil
+}
+
+// addL4Listener adds the listener to the Gateway of the given key, creating
+// it if needed. It returns false if the Gateway already has a listener of the
+// same name or port.
+func addL4Listener(ir *intermediate.IR, gatewayKey types.NamespacedName, listener gatewayv1.Listener) bool {
+	gatewayContext, ok := ir.Gateways[gatewayKey]
+	if !ok {
+		gatewayContext.Gateway = gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: gatewayKey.Namespace, Name: gatewayKey.Name},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: gatewayv1.ObjectName(gatewayKey.Name)},
+		}
+		gatewayContext.Gateway.SetGroupVersionKind(common.GatewayGVK)
+	}
+	for _, existing := range gatewayContext.Spec.Listeners {
+		if existing.Name == listener.Name || (existing.Port == listener.Port && existing.Protocol == listener.Protocol) {
+			return false
+		}
+	}
+	gatewayContext.Spec.Listeners = append(gatewayContext.Spec.Listeners, listener)
+	if ir.Gateways == nil {
+		ir.Gateways = map[types.NamespacedName]intermediate.GatewayContext{}
+	}
+	ir.Gateways[gatewayKey] = gatewayContext
+	return true
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func Test_convertL4Services(t *testing.T) {
+	httpListener := gatewayv1.Listener{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}
+	ir := intermediate.IR{
+		Gateways: map[types.NamespacedName]intermediate.GatewayContext{
+			{Namespace: "default", Name: "nginx"}: {Gateway: gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+				Spec:       gatewayv1.GatewaySpec{GatewayClassName: "nginx", Listeners: []gatewayv1.Listener{httpListener}},
+			}},
+		},
+	}
+	tcpServices := &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ingress-nginx", Name: "ingress-nginx-tcp"},
+		Data: map[string]string{
+			"9000": "default/app:8080",
+			"80":   "default/app:8080",
+			"9001": "default/app:http",
+			"5432": "db/postgres:5432:PROXY",
+		},
+	}
+	udpServices := &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ingress-nginx", Name: "ingress-nginx-udp"},
+		Data:       map[string]string{"53": "kube-system/kube-dns:53"},
+	}
+
+	convertL4Services(tcpServices, gatewayv1.TCPProtocolType, "", &ir)
+	convertL4Services(udpServices, gatewayv1.UDPProtocolType, "", &ir)
+
+	expectedGateways := map[types.NamespacedName]gatewayv1.Gateway{
+		{Namespace: "default", Name: "nginx"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+			Spec: gatewayv1.GatewaySpec{GatewayClassName: "nginx", Listeners: []gatewayv1.Listener{
+				httpListener,
+				{Name: "tcp-9000", Port: 9000, Protocol: gatewayv1.TCPProtocolType},
+			}},
+		},
+		{Namespace: "db", Name: "nginx"}: {
+			TypeMeta:   metav1.TypeMeta{APIVersion: common.GatewayGVK.GroupVersion().String(), Kind: common.GatewayGVK.Kind},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "db", Name: "nginx"},
+			Spec: gatewayv1.GatewaySpec{GatewayClassName: "nginx", Listeners: []gatewayv1.Listener{
+				{Name: "tcp-5432", Port: 5432, Protocol: gatewayv1.TCPProtocolType},
+			}},
+		},
+		{Namespace: "kube-system", Name: "nginx"}: {
+			TypeMeta:   metav1.TypeMeta{APIVersion: common.GatewayGVK.GroupVersion().String(), Kind: common.GatewayGVK.Kind},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "nginx"},
+			Spec: gatewayv1.GatewaySpec{GatewayClassName: "nginx", Listeners: []gatewayv1.Listener{
+				{Name: "udp-53", Port: 53, Protocol: gatewayv1.UDPProtocolType},
+			}},
+		},
+	}
+	gateways := map[types.NamespacedName]gatewayv1.Gateway{}
+	for key, gatewayContext := range ir.Gateways {
+		gateways[key] = gatewayContext.Gateway
+	}
+	if diff := cmp.Diff(expectedGateways, gateways); diff != "" {
+		t.Errorf("Unexpected Gateways (-want +got): %s", diff)
+	}
+
+	expectedTCPRoutes := map[types.NamespacedName]gatewayv1alpha2.TCPRoute{
+		{Namespace: "default", Name: "app-tcp-9000"}: expectedTCPRoute("default", "app", "tcp-9000", 8080),
+		{Namespace: "db", Name: "postgres-tcp-5432"}: expectedTCPRoute("db", "postgres", "tcp-5432", 5432),
+	}
+	if diff := cmp.Diff(expectedTCPRoutes, ir.TCPRoutes); diff != "" {
+		t.Errorf("Unexpected TCPRoutes (-want +got): %s", diff)
+	}
+
+	udpRoute := gatewayv1alpha2.UDPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "kube-dns-udp-53"},
+		Spec: gatewayv1alpha2.UDPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: "nginx", SectionName: ptrTo[gatewayv1.SectionName]("udp-53")}},
+			},
+			Rules: []gatewayv1alpha2.UDPRouteRule{{BackendRefs: []gatewayv1.BackendRef{{
+				BackendObjectReference: gatewayv1.BackendObjectReference{Name: "kube-dns", Port: ptrTo[gatewayv1.PortNumber](53)},
+			}}}},
+		},
+	}
+	udpRoute.SetGroupVersionKind(common.UDPRouteGVK)
+	expectedUDPRoutes := map[types.NamespacedName]gatewayv1alpha2.UDPRoute{
+		{Namespace: "kube-system", Name: "kube-dns-udp-53"}: udpRoute,
+	}
+	if diff := cmp.Diff(expectedUDPRoutes, ir.UDPRoutes); diff != "" {
+		t.Errorf("Unexpected UDPRoutes (-want +got): %s", diff)
+	}
+}
+
+func Test_convertL4Services_namespace(t *testing.T) {
+	ir := intermediate.IR{}
+	tcpServices := &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ingress-nginx", Name: "ingress-nginx-tcp"},
+		Data: map[string]string{
+			"9000": "default/app:8080",
+			"5432": "db/postgres:5432",
+		},
+	}
+
+	convertL4Services(tcpServices, gatewayv1.TCPProtocolType, "db", &ir)
+
+	var routeKeys []types.NamespacedName
+	for key := range ir.TCPRoutes {
+		routeKeys = append(routeKeys, key)
+	}
+	if diff := cmp.Diff([]types.NamespacedName{{Namespace: "db", Name: "postgres-tcp-5432"}}, routeKeys); diff != "" {
+		t.Errorf("Unexpected TCPRoutes (-want +got): %s", diff)
+	}
+}
+
+func Test_parseL4Service(t *testing.T) {
+	testCases := []struct {
+		name          string
+		port          string
+		value         string
+		expected      l4Service
+		expectedError bool
+	}{
+		{
+			name:     "service",
+			port:     "9000",
+			value:    "default/app:8080",
+			expected: l4Service{port: 9000, service: types.NamespacedName{Namespace: "default", Name: "app"}, servicePort: 8080},
+		},
+		{
+			name:     "PROXY protocol",
+			port:     "9000",
+			value:    "default/app:8080::PROXY",
+			expected: l4Service{port: 9000, service: types.NamespacedName{Namespace: "default", Name: "app"}, servicePort: 8080, proxyProtocol: true},
+		},
+		{
+			name:          "reserved port",
+			port:          "443",
+			value:         "default/app:8443",
+			expectedError: true,
+		},
+		{
+			name:          "invalid port",
+			port:          "dns",
+			value:         "default/app:53",
+			expectedError: true,
+		},
+		{
+			name:          "no namespace",
+			port:          "9000",
+			value:         "app:8080",
+			expectedError: true,
+		},
+		{
+			name:          "named service port",
+			port:          "9000",
+			value:         "default/app:http",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, err := parseL4Service(tc.port, tc.value)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", service)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, service, cmp.AllowUnexported(l4Service{})); diff != "" {
+				t.Errorf("Unexpected service (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func expectedTCPRoute(namespace, service string, listenerName gatewayv1.SectionName, port gatewayv1.PortNumber) gatewayv1alpha2.TCPRoute {
+	tcpRoute := gatewayv1alpha2.TCPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: service + "-" + string(listenerName)},
+		Spec: gatewayv1alpha2.TCPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: "nginx", SectionName: ptrTo(listenerName)}},
+			},
+			Rules: []gatewayv1alpha2.TCPRouteRule{{BackendRefs: []gatewayv1.BackendRef{{
+				BackendObjectReference: gatewayv1.BackendObjectReference{Name: gatewayv1.ObjectName(service), Port: ptrTo(port)},
+			}}}},
+		},
+	}
+	tcpRoute.SetGroupVersionKind(common.TCPRouteGVK)
+	return tcpRoute
+}
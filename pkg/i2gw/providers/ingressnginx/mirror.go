@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	mirrorTargetAnnotation      = "nginx.ingress.kubernetes.io/mirror-target"
+	mirrorHostAnnotation        = "nginx.ingress.kubernetes.io/mirror-host"
+	mirrorRequestBodyAnnotation = "nginx.ingress.kubernetes.io/mirror-request-body"
+
+	requestURIVariable = "$request_uri"
+)
+
+// mirrorFeature converts the nginx.ingress.kubernetes.io/mirror-target
+// annotation into RequestMirror filters on the HTTPRoute rules generated from
+// the paths of the annotated Ingress.
+//
+// Targets resolving to cluster-local Service hostnames of the given cluster
+// domain are mirrored to these Services. Other targets are mirrored to a generated Service of type
+// ExternalName, named <ingress>-mirror, resolving to the host of the target.
+func mirrorFeature(clusterDomain string) i2gw.FeatureParser {
+	return func(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+		var errs field.ErrorList
+
+		mirrorBackends := map[types.NamespacedName]gatewayv1.BackendObjectReference{}
+		for _, ingress := range ingresses {
+			target, ok := ingress.Annotations[mirrorTargetAnnotation]
+			if !ok {
+				continue
+			}
+			backendRef, externalName, err := parseMirrorTarget(ingress, target, clusterDomain)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			ingressKey := types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}
+			mirrorBackends[ingressKey] = *backendRef
+			if externalName != nil {
+				if ir.Services == nil {
+					ir.Services = map[types.NamespacedName]intermediate.ProviderSpecificServiceIR{}
+				}
+				serviceKey := types.NamespacedName{Namespace: ingress.Namespace, Name: string(backendRef.Name)}
+				serviceIR := ir.Services[serviceKey]
+				serviceIR.IngressNginx = &intermediate.IngressNginxServiceIR{ExternalName: externalName}
+				ir.Services[serviceKey] = serviceIR
+				notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s mirrors its requests to %s outside of the cluster, generated Service %s of type ExternalName as the mirror backend, which not all Gateway API implementations support", ingress.Namespace, ingress.Name, externalName.Host, serviceKey), &ingress)
+			}
+
+			if host, ok := ingress.Annotations[mirrorHostAnnotation]; ok {
+				notify(notifications.WarningNotification, fmt.Sprintf("the %q annotation of ingress %s/%s has no Gateway API equivalent, the mirrored requests keep the Host header %s", mirrorHostAnnotation, ingress.Namespace, ingress.Name, host), &ingress)
+			}
+			if strings.TrimSpace(ingress.Annotations[mirrorRequestBodyAnnotation]) == "off" {
+				notify(notifications.WarningNotification, fmt.Sprintf("the %q annotation of ingress %s/%s has no Gateway API equivalent, the mirrored requests keep their body", mirrorRequestBodyAnnotation, ingress.Namespace, ingress.Name), &ingress)
+			}
+		}
+		if len(mirrorBackends) == 0 {
+			return errs
+		}
+
+		for _, rg := range common.GetRuleGroups(ingresses, ir) {
+			key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+			httpRouteContext, ok := ir.HTTPRoutes[key]
+			if !ok {
+				continue
+			}
+
+			for _, rule := range rg.Rules {
+				backendRef, ok := mirrorBackends[types.NamespacedName{Namespace: rule.Ingress.Namespace, Name: rule.Ingress.Name}]
+				if !ok {
+					continue
+				}
+				for _, i := range common.RuleIndicesForIngressRule(httpRouteContext.HTTPRoute, rule.IngressRule) {
+					if addRequestMirror(&httpRouteContext.Spec.Rules[i], backendRef) {
+						notify(notifications.InfoNotification, fmt.Sprintf("parsed \"%v\" annotation of ingress %s/%s and patched %v fields", mirrorTargetAnnotation, rule.Ingress.Namespace, rule.Ingress.Name, field.NewPath("httproute", "spec", "rules").Index(i).Child("filters")), &httpRouteContext.HTTPRoute)
+					} else {
+						notify(notifications.WarningNotification, fmt.Sprintf("conflicting \"%v\" annotation of ingress %s/%s for rule %d was ignored", mirrorTargetAnnotation, rule.Ingress.Namespace, rule.Ingress.Name, i), &httpRouteContext.HTTPRoute)
+					}
+				}
+			}
+
+			ir.HTTPRoutes[key] = httpRouteContext
+		}
+
+		return errs
+	}
+}
+
+// parseMirrorTarget returns the backend of the mirror target URL of the
+// Ingress, and the ExternalName Service to generate for it if the target is
+// outside of the cluster.
+func parseMirrorTarget(ingress networkingv1.Ingress, target, clusterDomain string) (*gatewayv1.BackendObjectReference, *intermediate.ExternalNameService, *field.Error) {
+	fieldPath := field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations").Key(mirrorTargetAnnotation)
+
+	target = strings.TrimSpace(target)
+	uri, keepsRequestURI := strings.CutSuffix(target, requestURIVariable)
+	if strings.Contains(uri, "$") {
+		return nil, nil, field.Invalid(fieldPath, target, fmt.Sprintf("only the %s variable is supported, at the end of the target", requestURIVariable))
+	}
+	u, err := url.Parse(uri)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, nil, field.Invalid(fieldPath, target, "must be an http or https URL")
+	}
+	if net.ParseIP(u.Hostname()) != nil {
+		return nil, nil, field.Invalid(fieldPath, target, "IP addresses are not supported, the target must be a hostname")
+	}
+
+	port := int32(80)
+	if u.Scheme == "https" {
+		port = 443
+	}
+	if u.Port() != "" {
+		parsed, err := strconv.ParseInt(u.Port(), 10, 32)
+		if err != nil {
+			return nil, nil, field.Invalid(fieldPath, target, "the port must be a number")
+		}
+		port = int32(parsed)
+	}
+
+	if !keepsRequestURI || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s mirrors its requests to the URI of %s, while RequestMirror filters keep the URI of the requests", ingress.Namespace, ingress.Name, target), &ingress)
+	}
+	if u.Scheme == "https" {
+		notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s mirrors its requests to %s with TLS, which RequestMirror filters don't configure: attach a BackendTLSPolicy to the mirror backend", ingress.Namespace, ingress.Name, target), &ingress)
+	}
+
+	backendRef := &gatewayv1.BackendObjectReference{Port: ptr.To(gatewayv1.PortNumber(port))}
+	if service, ok := common.ServiceFromHostname(u.Hostname(), clusterDomain); ok {
+		backendRef.Name = gatewayv1.ObjectName(service.Name)
+		if service.Namespace != ingress.Namespace {
+			backendRef.Namespace = ptr.To(gatewayv1.Namespace(service.Namespace))
+			notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s mirrors its requests to Service %s of another namespace, which requires a ReferenceGrant from the HTTPRoutes of namespace %s", ingress.Namespace, ingress.Name, service, ingress.Namespace), &ingress)
+		}
+		return backendRef, nil, nil
+	}
+	backendRef.Name = gatewayv1.ObjectName(fmt.Sprintf("%s-mirror", ingress.Name))
+	return backendRef, &intermediate.ExternalNameService{Host: u.Hostname(), Port: port}, nil
+}
+
+// addRequestMirror adds a RequestMirror filter to the backend to the rule. It
+// returns false if the rule already mirrors its requests to another backend.
+func addRequestMirror(rule *gatewayv1.HTTPRouteRule, backendRef gatewayv1.BackendObjectReference) bool {
+	for _, filter := range rule.Filters {
+		if filter.Type == gatewayv1.HTTPRouteFilterRequestMirror && filter.RequestMirror != nil {
+			return apiequality.Semantic.DeepEqual(filter.RequestMirror.BackendRef, backendRef)
+		}
+	}
+	rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{
+		Type:          gatewayv1.HTTPRouteFilterRequestMirror,
+		RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: backendRef},
+	})
+	return true
+}
+
+// externalNameServices returns the Services of type ExternalName of the IR,
+// generated for the backends outside of the cluster.
+func externalNameServices(ir intermediate.IR) []unstructured.Unstructured {
+	var keys []types.NamespacedName
+	for key, serviceIR := range ir.Services {
+		if serviceIR.IngressNginx != nil && serviceIR.IngressNginx.ExternalName != nil {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	services := make([]unstructured.Unstructured, 0, len(keys))
+	for _, key := range keys {
+		externalName := ir.Services[key].IngressNginx.ExternalName
+		service := unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"type":         "ExternalName",
+				"externalName": externalName.Host,
+				"ports": []interface{}{
+					map[string]interface{}{"port": int64(externalName.Port), "protocol": "TCP"},
+				},
+			},
+		}}
+		service.SetAPIVersion("v1")
+		service.SetKind("Service")
+		service.SetNamespace(key.Namespace)
+		service.SetName(key.Name)
+		services = append(services, service)
+	}
+	return services
+}
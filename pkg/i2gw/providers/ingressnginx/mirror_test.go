@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_mirrorFeature(t *testing.T) {
+	iPrefix := networkingv1.PathTypePrefix
+	ingress := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: map[string]string{
+			mirrorTargetAnnotation: "https://shadow.example.com$request_uri",
+		}},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptrTo("nginx"),
+			Rules: []networkingv1.IngressRule{{
+				Host: "example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/",
+							PathType: &iPrefix,
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: "app",
+									Port: networkingv1.ServiceBackendPort{Number: 80},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+	ingresses := []networkingv1.Ingress{ingress}
+
+	ir, errs := common.ToIR(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+	}
+	if errs = mirrorFeature("")(ingresses, &ir); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	routeKey := types.NamespacedName{Namespace: "default", Name: common.RouteName("app", "example.com")}
+	expectedFilters := []gatewayv1.HTTPRouteFilter{{
+		Type: gatewayv1.HTTPRouteFilterRequestMirror,
+		RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: gatewayv1.BackendObjectReference{
+			Name: "app-mirror",
+			Port: ptrTo[gatewayv1.PortNumber](443),
+		}},
+	}}
+	if diff := cmp.Diff(expectedFilters, ir.HTTPRoutes[routeKey].Spec.Rules[0].Filters); diff != "" {
+		t.Errorf("Unexpected filters (-want +got): %s", diff)
+	}
+
+	expectedServices := map[types.NamespacedName]intermediate.ProviderSpecificServiceIR{
+		{Namespace: "default", Name: "app-mirror"}: {IngressNginx: &intermediate.IngressNginxServiceIR{
+			ExternalName: &intermediate.ExternalNameService{Host: "shadow.example.com", Port: 443},
+		}},
+	}
+	if diff := cmp.Diff(expectedServices, ir.Services); diff != "" {
+		t.Errorf("Unexpected Services IR (-want +got): %s", diff)
+	}
+
+	services := externalNameServices(ir)
+	if len(services) != 1 {
+		t.Fatalf("Expected 1 ExternalName Service, got %d", len(services))
+	}
+	expectedService := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]interface{}{"namespace": "default", "name": "app-mirror"},
+		"spec": map[string]interface{}{
+			"type":         "ExternalName",
+			"externalName": "shadow.example.com",
+			"ports":        []interface{}{map[string]interface{}{"port": int64(443), "protocol": "TCP"}},
+		},
+	}
+	if diff := cmp.Diff(expectedService, services[0].Object); diff != "" {
+		t.Errorf("Unexpected Service (-want +got): %s", diff)
+	}
+}
+
+func Test_parseMirrorTarget(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		target               string
+		clusterDomain        string
+		expectedBackendRef   *gatewayv1.BackendObjectReference
+		expectedExternalName *intermediate.ExternalNameService
+		expectedError        bool
+	}{
+		{
+			name:   "cluster-local Service",
+			target: "http://shadow.default.svc.cluster.local:8080$request_uri",
+			expectedBackendRef: &gatewayv1.BackendObjectReference{
+				Name: "shadow",
+				Port: ptrTo[gatewayv1.PortNumber](8080),
+			},
+		},
+		{
+			name:   "Service of another namespace",
+			target: "http://shadow.test.svc$request_uri",
+			expectedBackendRef: &gatewayv1.BackendObjectReference{
+				Name:      "shadow",
+				Namespace: ptrTo[gatewayv1.Namespace]("test"),
+				Port:      ptrTo[gatewayv1.PortNumber](80),
+			},
+		},
+		{
+			name:          "Service of a custom cluster domain",
+			target:        "http://shadow.default.svc.example.internal$request_uri",
+			clusterDomain: "example.internal",
+			expectedBackendRef: &gatewayv1.BackendObjectReference{
+				Name: "shadow",
+				Port: ptrTo[gatewayv1.PortNumber](80),
+			},
+		},
+		{
+			name:   "Service of another cluster domain",
+			target: "http://shadow.default.svc.example.internal$request_uri",
+			expectedBackendRef: &gatewayv1.BackendObjectReference{
+				Name: "app-mirror",
+				Port: ptrTo[gatewayv1.PortNumber](80),
+			},
+			expectedExternalName: &intermediate.ExternalNameService{Host: "shadow.default.svc.example.internal", Port: 80},
+		},
+		{
+			name:   "external host",
+			target: "http://shadow.example.com/",
+			expectedBackendRef: &gatewayv1.BackendObjectReference{
+				Name: "app-mirror",
+				Port: ptrTo[gatewayv1.PortNumber](80),
+			},
+			expectedExternalName: &intermediate.ExternalNameService{Host: "shadow.example.com", Port: 80},
+		},
+		{
+			name:          "other variable",
+			target:        "http://shadow.example.com$uri",
+			expectedError: true,
+		},
+		{
+			name:          "IP address",
+			target:        "http://10.0.0.1$request_uri",
+			expectedError: true,
+		},
+		{
+			name:          "not a URL",
+			target:        "shadow",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+			backendRef, externalName, err := parseMirrorTarget(ingress, tc.target, tc.clusterDomain)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("Expected error: %t, got: %v", tc.expectedError, err)
+			}
+			if diff := cmp.Diff(tc.expectedBackendRef, backendRef); diff != "" {
+				t.Errorf("Unexpected backendRef (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedExternalName, externalName); diff != "" {
+				t.Errorf("Unexpected ExternalName Service (-want +got): %s", diff)
+			}
+		})
+	}
+}
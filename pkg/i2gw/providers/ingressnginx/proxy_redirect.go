@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	proxyRedirectFromAnnotation = "nginx.ingress.kubernetes.io/proxy-redirect-from"
+	proxyRedirectToAnnotation   = "nginx.ingress.kubernetes.io/proxy-redirect-to"
+
+	proxyRedirectOff     = "off"
+	proxyRedirectDefault = "default"
+)
+
+// proxyRedirectFeature parses the nginx.ingress.kubernetes.io/proxy-redirect-from and
+// nginx.ingress.kubernetes.io/proxy-redirect-to annotations into the ProxyRedirect policy
+// of the ingress-nginx HTTPRoute IR.
+//
+// The Gateway API has no core equivalent for rewriting the Location and Refresh response
+// headers, so the policy can only be honored by implementation-specific extensions. A warning
+// is always emitted, as broken redirects are easily missed after a migration.
+func proxyRedirectFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses, ir)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRouteContext, ok := ir.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			proxyRedirect := parseProxyRedirectAnnotations(ingress)
+			if proxyRedirect == nil {
+				continue
+			}
+
+			ruleIndices := common.RuleIndicesForIngressRule(httpRouteContext.HTTPRoute, rule.IngressRule)
+			patchPolicy(&httpRouteContext, ingress.Name, ruleIndices, func(policy *intermediate.IngressNginxPolicy) {
+				policy.ProxyRedirect = proxyRedirect
+			})
+
+			notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s rewrites the Location and Refresh response headers from %q to %q, which has no Gateway API equivalent: the rewrite is only kept for implementation-specific extensions, make sure the redirects of %v still work", ingress.Namespace, ingress.Name, proxyRedirect.From, proxyRedirect.To, key), &ingress)
+		}
+
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+
+	return nil
+}
+
+// parseProxyRedirectAnnotations returns the ProxyRedirectConfig of the Ingress, or nil if the
+// Location and Refresh response headers are not rewritten.
+func parseProxyRedirectAnnotations(ingress networkingv1.Ingress) *intermediate.ProxyRedirectConfig {
+	from, fromOK := ingress.Annotations[proxyRedirectFromAnnotation]
+	to, toOK := ingress.Annotations[proxyRedirectToAnnotation]
+	if !fromOK && !toOK {
+		return nil
+	}
+
+	switch {
+	case from == proxyRedirectOff:
+		return nil
+	case from == proxyRedirectDefault:
+		return &intermediate.ProxyRedirectConfig{From: from}
+	case from == "" || to == "":
+		notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s must set both %q and %q annotations, the annotations were ignored", ingress.Namespace, ingress.Name, proxyRedirectFromAnnotation, proxyRedirectToAnnotation), &ingress)
+		return nil
+	}
+
+	return &intermediate.ProxyRedirectConfig{From: from, To: to}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_proxyRedirectFeature(t *testing.T) {
+	iPrefix := networkingv1.PathTypePrefix
+
+	testIngress := func(name string, annotations map[string]string, path string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptrTo("nginx"),
+				Rules: []networkingv1.IngressRule{{
+					Host: "example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path:     path,
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: name,
+										Port: networkingv1.ServiceBackendPort{Number: 80},
+									},
+								},
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		ingresses  []networkingv1.Ingress
+		expectedIR *intermediate.IngressNginxHTTPRouteIR
+	}{
+		{
+			name: "from and to",
+			ingresses: []networkingv1.Ingress{
+				testIngress("other", nil, "/other"),
+				testIngress("app", map[string]string{
+					proxyRedirectFromAnnotation: "http://backend.internal/",
+					proxyRedirectToAnnotation:   "https://example.com/app/",
+				}, "/app"),
+			},
+			expectedIR: &intermediate.IngressNginxHTTPRouteIR{
+				Policies: map[string]intermediate.IngressNginxPolicy{
+					"app": {
+						RuleIndices:   []int{1},
+						ProxyRedirect: &intermediate.ProxyRedirectConfig{From: "http://backend.internal/", To: "https://example.com/app/"},
+					},
+				},
+			},
+		},
+		{
+			name: "default",
+			ingresses: []networkingv1.Ingress{
+				testIngress("other", map[string]string{proxyRedirectFromAnnotation: "default"}, "/other"),
+			},
+			expectedIR: &intermediate.IngressNginxHTTPRouteIR{
+				Policies: map[string]intermediate.IngressNginxPolicy{
+					"other": {
+						RuleIndices:   []int{0},
+						ProxyRedirect: &intermediate.ProxyRedirectConfig{From: "default"},
+					},
+				},
+			},
+		},
+		{
+			name: "off",
+			ingresses: []networkingv1.Ingress{
+				testIngress("other", map[string]string{proxyRedirectFromAnnotation: "off"}, "/other"),
+			},
+		},
+		{
+			name: "missing to",
+			ingresses: []networkingv1.Ingress{
+				testIngress("other", map[string]string{proxyRedirectFromAnnotation: "http://backend.internal/"}, "/other"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir, errs := common.ToIR(tc.ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) > 0 {
+				t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+			}
+
+			if errs = proxyRedirectFeature(tc.ingresses, &ir); len(errs) > 0 {
+				t.Errorf("Unexpected errors: %v", errs)
+			}
+
+			routeName := common.RouteName(tc.ingresses[0].Name, "example.com")
+			httpRouteContext := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: routeName}]
+			if diff := cmp.Diff(tc.expectedIR, httpRouteContext.ProviderSpecificIR.IngressNginx); diff != "" {
+				t.Errorf("Unexpected ingress-nginx HTTPRoute IR (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	limitRPSAnnotation             = "nginx.ingress.kubernetes.io/limit-rps"
+	limitRPMAnnotation             = "nginx.ingress.kubernetes.io/limit-rpm"
+	limitBurstMultiplierAnnotation = "nginx.ingress.kubernetes.io/limit-burst-multiplier"
+
+	defaultLimitBurstMultiplier = 5
+)
+
+// rateLimitFeature parses the nginx.ingress.kubernetes.io/limit-rps and
+// nginx.ingress.kubernetes.io/limit-rpm annotations into the RateLimit policy of the
+// ingress-nginx HTTPRoute IR.
+//
+// The Gateway API has no core equivalent for rate limiting, so the policy can only be
+// honored by implementation-specific emitters.
+func rateLimitFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	return patchIngressPolicies(ingresses, ir, func(ingress networkingv1.Ingress) (func(*intermediate.IngressNginxPolicy), field.ErrorList) {
+		rateLimit, errs := parseRateLimitAnnotations(ingress)
+		if rateLimit == nil {
+			return nil, errs
+		}
+		notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s limits the rate of the requests to %d per %v, which has no Gateway API equivalent: the limit is only kept for implementation-specific emitters", ingress.Namespace, ingress.Name, rateLimit.Requests, rateLimit.Period), &ingress)
+		return func(policy *intermediate.IngressNginxPolicy) {
+			policy.RateLimit = rateLimit
+		}, errs
+	})
+}
+
+// parseRateLimitAnnotations returns the RateLimitConfig of the Ingress, or nil if the rate
+// of the requests is not limited.
+func parseRateLimitAnnotations(ingress networkingv1.Ingress) (*intermediate.RateLimitConfig, field.ErrorList) {
+	annotationsPath := field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations")
+	parsePositive := func(annotation string) (int32, *field.Error) {
+		value, err := strconv.ParseInt(ingress.Annotations[annotation], 10, 32)
+		if err != nil || value <= 0 {
+			return 0, field.Invalid(annotationsPath.Key(annotation), ingress.Annotations[annotation], "must be a positive integer")
+		}
+		return int32(value), nil
+	}
+
+	var (
+		annotation string
+		period     time.Duration
+	)
+	_, rpsOK := ingress.Annotations[limitRPSAnnotation]
+	_, rpmOK := ingress.Annotations[limitRPMAnnotation]
+	switch {
+	case rpsOK:
+		annotation, period = limitRPSAnnotation, time.Second
+		if rpmOK {
+			notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s limits the rate of the requests both per second and per minute, only the %q annotation was converted", ingress.Namespace, ingress.Name, limitRPSAnnotation), &ingress)
+		}
+	case rpmOK:
+		annotation, period = limitRPMAnnotation, time.Minute
+	default:
+		return nil, nil
+	}
+
+	requests, err := parsePositive(annotation)
+	if err != nil {
+		return nil, field.ErrorList{err}
+	}
+	multiplier := int32(defaultLimitBurstMultiplier)
+	if _, ok := ingress.Annotations[limitBurstMultiplierAnnotation]; ok {
+		if multiplier, err = parsePositive(limitBurstMultiplierAnnotation); err != nil {
+			return nil, field.ErrorList{err}
+		}
+	}
+
+	return &intermediate.RateLimitConfig{Requests: requests, Period: period, Burst: requests * multiplier}, nil
+}
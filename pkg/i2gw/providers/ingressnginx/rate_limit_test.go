@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_rateLimitFeature(t *testing.T) {
+	iPrefix := networkingv1.PathTypePrefix
+	ingress := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: map[string]string{limitRPSAnnotation: "10"}},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptrTo("nginx"),
+			Rules: []networkingv1.IngressRule{{
+				Host: "example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/",
+							PathType: &iPrefix,
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{Name: "app", Port: networkingv1.ServiceBackendPort{Number: 80}},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+
+	ir, errs := common.ToIR([]networkingv1.Ingress{ingress}, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+	}
+	if errs = rateLimitFeature([]networkingv1.Ingress{ingress}, &ir); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	routeIR := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: common.RouteName("app", "example.com")}].ProviderSpecificIR.IngressNginx
+	if routeIR == nil {
+		t.Fatalf("Expected the ingress-nginx HTTPRoute IR to be set")
+	}
+	expectedPolicies := map[string]intermediate.IngressNginxPolicy{
+		"app": {
+			RuleIndices: []int{0},
+			RateLimit:   &intermediate.RateLimitConfig{Requests: 10, Period: time.Second, Burst: 50},
+		},
+	}
+	if diff := cmp.Diff(expectedPolicies, routeIR.Policies); diff != "" {
+		t.Errorf("Unexpected policies (-want +got): %s", diff)
+	}
+}
+
+func Test_parseRateLimitAnnotations(t *testing.T) {
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		expected       *intermediate.RateLimitConfig
+		expectedErrors int
+	}{
+		{
+			name:        "no rate limit",
+			annotations: map[string]string{},
+		},
+		{
+			name:        "requests per minute",
+			annotations: map[string]string{limitRPMAnnotation: "100"},
+			expected:    &intermediate.RateLimitConfig{Requests: 100, Period: time.Minute, Burst: 500},
+		},
+		{
+			name:        "requests per second take precedence",
+			annotations: map[string]string{limitRPSAnnotation: "5", limitRPMAnnotation: "100", limitBurstMultiplierAnnotation: "2"},
+			expected:    &intermediate.RateLimitConfig{Requests: 5, Period: time.Second, Burst: 10},
+		},
+		{
+			name:           "invalid rate",
+			annotations:    map[string]string{limitRPSAnnotation: "ten"},
+			expectedErrors: 1,
+		},
+		{
+			name:           "invalid burst multiplier",
+			annotations:    map[string]string{limitRPSAnnotation: "10", limitBurstMultiplierAnnotation: "0"},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: tc.annotations}}
+			rateLimit, errs := parseRateLimitAnnotations(ingress)
+			if len(errs) != tc.expectedErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if diff := cmp.Diff(tc.expected, rateLimit); diff != "" {
+				t.Errorf("Unexpected rate limit (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -17,11 +17,18 @@ limitations under the License.
 package ingressnginx
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"os"
+	"strings"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
-	"k8s.io/apimachinery/pkg/util/sets"
+	apiv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // converter implements the i2gw.CustomResourceReader interface.
@@ -39,21 +46,110 @@ func newResourceReader(conf *i2gw.ProviderConf) *resourceReader {
 func (r *resourceReader) readResourcesFromCluster(ctx context.Context) (*storage, error) {
 	storage := newResourcesStorage()
 
-	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, sets.New(NginxIngressClass))
+	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, r.conf.IngressClaimer.ClaimFunc(Name))
 	if err != nil {
 		return nil, err
 	}
 	storage.Ingresses.FromMap(ingresses)
+
+	tcpServicesKey, udpServicesKey, err := r.servicesConfigMapKeys()
+	if err != nil {
+		return nil, err
+	}
+	if storage.TCPServices, err = r.readConfigMapFromCluster(ctx, tcpServicesKey); err != nil {
+		return nil, err
+	}
+	if storage.UDPServices, err = r.readConfigMapFromCluster(ctx, udpServicesKey); err != nil {
+		return nil, err
+	}
 	return storage, nil
 }
 
 func (r *resourceReader) readResourcesFromFile(filename string) (*storage, error) {
 	storage := newResourcesStorage()
 
-	ingresses, err := common.ReadIngressesFromFile(filename, r.conf.Namespace, sets.New(NginxIngressClass))
+	ingresses, err := common.ReadIngressesFromFile(filename, r.conf.Namespace, r.conf.IngressClaimer.ClaimFunc(Name))
 	if err != nil {
 		return nil, err
 	}
 	storage.Ingresses.FromMap(ingresses)
+
+	tcpServicesKey, udpServicesKey, err := r.servicesConfigMapKeys()
+	if err != nil {
+		return nil, err
+	}
+	if storage.TCPServices, err = readConfigMapFromFile(filename, tcpServicesKey); err != nil {
+		return nil, err
+	}
+	if storage.UDPServices, err = readConfigMapFromFile(filename, udpServicesKey); err != nil {
+		return nil, err
+	}
 	return storage, nil
 }
+
+// servicesConfigMapKeys returns the keys of the ConfigMaps of the TCP and UDP
+// services of the provider-specific flags.
+func (r *resourceReader) servicesConfigMapKeys() (types.NamespacedName, types.NamespacedName, error) {
+	flags := r.conf.ProviderSpecificFlags[Name]
+	tcpServicesKey, err := configMapKey(TCPServicesConfigMapFlag, flags[TCPServicesConfigMapFlag], defaultTCPServicesConfigMap)
+	if err != nil {
+		return types.NamespacedName{}, types.NamespacedName{}, err
+	}
+	udpServicesKey, err := configMapKey(UDPServicesConfigMapFlag, flags[UDPServicesConfigMapFlag], defaultUDPServicesConfigMap)
+	if err != nil {
+		return types.NamespacedName{}, types.NamespacedName{}, err
+	}
+	return tcpServicesKey, udpServicesKey, nil
+}
+
+// configMapKey parses the <namespace>/<name> of the ConfigMap of the flag,
+// the default one if the flag is not set.
+func configMapKey(flag, value, defaultValue string) (types.NamespacedName, error) {
+	if value == "" {
+		value = defaultValue
+	}
+	namespace, name, found := strings.Cut(value, "/")
+	if !found || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return types.NamespacedName{}, fmt.Errorf("invalid --%s-%s %q, expected <namespace>/<name>", Name, flag, value)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// readConfigMapFromCluster returns the ConfigMap of the given key, or nil if
+// it doesn't exist.
+func (r *resourceReader) readConfigMapFromCluster(ctx context.Context, key types.NamespacedName) (*apiv1.ConfigMap, error) {
+	var configMap apiv1.ConfigMap
+	if err := r.conf.Client.Get(ctx, key, &configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get ConfigMap %s from the cluster: %w", key, err)
+	}
+	return &configMap, nil
+}
+
+// readConfigMapFromFile returns the ConfigMap of the given key of the file, or
+// nil if it has none. The ConfigMap is read regardless of the namespace
+// filter, as it is in the namespace of the controller.
+func readConfigMapFromFile(filename string, key types.NamespacedName) (*apiv1.ConfigMap, error) {
+	stream, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %v: %w", filename, err)
+	}
+
+	objs, err := common.ExtractObjectsFromReader(bytes.NewReader(stream), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract objects: %w", err)
+	}
+	for _, f := range objs {
+		if f.GetAPIVersion() != "v1" || f.GetKind() != "ConfigMap" || f.GetNamespace() != key.Namespace || f.GetName() != key.Name {
+			continue
+		}
+		var configMap apiv1.ConfigMap
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(f.UnstructuredContent(), &configMap); err != nil {
+			return nil, err
+		}
+		return &configMap, nil
+	}
+	return nil, nil
+}
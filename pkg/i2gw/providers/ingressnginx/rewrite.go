@@ -0,0 +1,334 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	rewriteTargetAnnotation = "nginx.ingress.kubernetes.io/rewrite-target"
+	useRegexAnnotation      = "nginx.ingress.kubernetes.io/use-regex"
+
+	// regexMetaCharacters are the characters making an ingress-nginx path a
+	// regular expression rather than a literal prefix. The dot is left out as
+	// paths like /v1.0 are meant literally.
+	regexMetaCharacters = `*+?()[]{}|^$\`
+)
+
+// regexPathKind describes the paths matched by an ingress-nginx regular
+// expression path.
+type regexPathKind int
+
+const (
+	// prefixRegexPath matches the paths starting with the literal prefix.
+	prefixRegexPath regexPathKind = iota
+	// exactRegexPath only matches the literal prefix.
+	exactRegexPath
+	// exactOrSlashRegexPath matches the literal prefix, with or without a
+	// trailing slash.
+	exactOrSlashRegexPath
+)
+
+// regexPathSuffix is a regular expression following the literal prefix of an
+// ingress-nginx path, e.g. "(/|$)(.*)" in "/foo(/|$)(.*)".
+type regexPathSuffix struct {
+	suffix string
+	kind   regexPathKind
+	// restGroup is the index of the capture group matching the rest of the
+	// path after the literal prefix, 0 if there is none.
+	restGroup int
+	// restHasSlash is true if the rest captured by restGroup starts with the
+	// slash following the literal prefix.
+	restHasSlash bool
+	// slashRequired is true if the literal prefix must be followed by a
+	// slash, i.e. the literal prefix alone is not matched.
+	slashRequired bool
+}
+
+// regexPathSuffixes are the regular expressions whose semantics can be
+// represented with Gateway API path matches. Longer suffixes come first, so
+// that e.g. "/(.*)" is preferred over "(.*)".
+var regexPathSuffixes = []regexPathSuffix{
+	{suffix: "(/|$)(.*)$", kind: prefixRegexPath, restGroup: 2},
+	{suffix: "(/|$)(.*)", kind: prefixRegexPath, restGroup: 2},
+	{suffix: "/?(.*)$", kind: prefixRegexPath, restGroup: 1},
+	{suffix: "/?(.*)", kind: prefixRegexPath, restGroup: 1},
+	{suffix: "/(.*)$", kind: prefixRegexPath, restGroup: 1, slashRequired: true},
+	{suffix: "/(.*)", kind: prefixRegexPath, restGroup: 1, slashRequired: true},
+	{suffix: "(/.*)$", kind: prefixRegexPath, restGroup: 1, restHasSlash: true, slashRequired: true},
+	{suffix: "(/.*)", kind: prefixRegexPath, restGroup: 1, restHasSlash: true, slashRequired: true},
+	{suffix: "(.*)$", kind: prefixRegexPath, restGroup: 1, restHasSlash: true},
+	{suffix: "(.*)", kind: prefixRegexPath, restGroup: 1, restHasSlash: true},
+	{suffix: "(/|$)", kind: prefixRegexPath},
+	{suffix: "/?$", kind: exactOrSlashRegexPath},
+	{suffix: ".*", kind: prefixRegexPath},
+	{suffix: "/?", kind: prefixRegexPath},
+	{suffix: "$", kind: exactRegexPath},
+}
+
+// rewriteFeature converts the nginx.ingress.kubernetes.io/use-regex and
+// nginx.ingress.kubernetes.io/rewrite-target annotations.
+//
+// Like ingress-nginx, as soon as one Ingress of a host uses regular
+// expressions, the Prefix paths of all the Ingresses of the host are treated
+// as regular expressions anchored at the start of the path. The recognized
+// expressions are converted to the PathPrefix and Exact matches selecting the
+// same paths, generating an additional Exact match when nginx matches the path
+// both with and without a trailing slash. The rewrite target is converted to a
+// URLRewrite filter when its captures can be expressed with a prefix
+// replacement.
+//
+// As it changes the path matches, this feature must run after the features
+// locating HTTPRoute rules by Ingress path.
+func rewriteFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	var errs field.ErrorList
+
+	ruleGroups := common.GetRuleGroups(ingresses, ir)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRouteContext, ok := ir.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		useRegex := false
+		for _, rule := range rg.Rules {
+			if _, ok := rule.Ingress.Annotations[rewriteTargetAnnotation]; ok || rule.Ingress.Annotations[useRegexAnnotation] == "true" {
+				useRegex = true
+			}
+		}
+		if !useRegex {
+			continue
+		}
+
+		// The rules are located before any path match is converted, as
+		// several Ingress paths may share the same HTTPRoute rule.
+		type regexPathRule struct {
+			ruleIndex int
+			path      networkingv1.HTTPIngressPath
+			ingress   *networkingv1.Ingress
+		}
+		var regexPathRules []regexPathRule
+		for _, rule := range rg.Rules {
+			if rule.IngressRule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.IngressRule.HTTP.Paths {
+				for i := range httpRouteContext.Spec.Rules {
+					if common.HTTPRouteRuleMatchesPath(httpRouteContext.Spec.Rules[i], path) {
+						regexPathRules = append(regexPathRules, regexPathRule{ruleIndex: i, path: path, ingress: &rule.Ingress})
+					}
+				}
+			}
+		}
+
+		type convertedMatch struct {
+			ruleIndex int
+			path      string
+		}
+		converted := map[convertedMatch]struct{}{}
+		for _, r := range regexPathRules {
+			rule := &httpRouteContext.Spec.Rules[r.ruleIndex]
+			prefix, suffix, ok := parseRegexPath(r.path.Path)
+
+			matchKey := convertedMatch{ruleIndex: r.ruleIndex, path: r.path.Path}
+			if _, done := converted[matchKey]; !done && *r.path.PathType == networkingv1.PathTypePrefix {
+				if ok {
+					convertRegexPathMatches(rule, r.path.Path, prefix, suffix)
+				} else {
+					convertUnsupportedRegexPathMatches(rule, r.path.Path)
+					notify(notifications.WarningNotification, fmt.Sprintf("regular expression path %s of ingress %s/%s was converted to a RegularExpression path match, whose support and semantics are implementation-specific", r.path.Path, r.ingress.Namespace, r.ingress.Name), &httpRouteContext.HTTPRoute)
+				}
+				converted[matchKey] = struct{}{}
+			}
+
+			target, found := r.ingress.Annotations[rewriteTargetAnnotation]
+			if !found || r.ingress.Annotations["nginx.ingress.kubernetes.io/canary"] == "true" {
+				// ingress-nginx ignores the rewrite target of canary Ingresses.
+				continue
+			}
+			fieldPath := field.NewPath(r.ingress.Namespace, r.ingress.Name, "metadata", "annotations").Key(rewriteTargetAnnotation)
+			if target == "" {
+				errs = append(errs, field.Invalid(fieldPath, target, "the rewrite target must not be empty"))
+				continue
+			}
+
+			var filter *gatewayv1.HTTPURLRewriteFilter
+			if *r.path.PathType == networkingv1.PathTypePrefix && ok {
+				filter = toURLRewriteFilter(prefix, suffix, target)
+			} else if !strings.Contains(target, "$") {
+				filter = &gatewayv1.HTTPURLRewriteFilter{Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To(target)}}
+			}
+			if filter == nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("rewrite target %s of ingress %s/%s for path %s cannot be expressed with a URLRewrite filter and was ignored", target, r.ingress.Namespace, r.ingress.Name, r.path.Path), &httpRouteContext.HTTPRoute)
+				continue
+			}
+			if setURLRewrite(rule, filter) {
+				notify(notifications.InfoNotification, fmt.Sprintf("parsed \"%v\" annotation of ingress %s/%s and patched %v fields", rewriteTargetAnnotation, r.ingress.Namespace, r.ingress.Name, field.NewPath("httproute", "spec", "rules").Index(r.ruleIndex).Child("filters")), &httpRouteContext.HTTPRoute)
+			} else {
+				notify(notifications.WarningNotification, fmt.Sprintf("conflicting \"%v\" annotation of ingress %s/%s for path %s was ignored", rewriteTargetAnnotation, r.ingress.Namespace, r.ingress.Name, r.path.Path), &httpRouteContext.HTTPRoute)
+			}
+		}
+
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+
+	return errs
+}
+
+// parseRegexPath splits the ingress-nginx regular expression path into its
+// literal prefix and one of the supported regexPathSuffixes. It returns false
+// if the path is not supported.
+func parseRegexPath(path string) (string, regexPathSuffix, bool) {
+	if !strings.ContainsAny(path, regexMetaCharacters) {
+		return path, regexPathSuffix{kind: prefixRegexPath}, true
+	}
+	for _, suffix := range regexPathSuffixes {
+		prefix, found := strings.CutSuffix(path, suffix.suffix)
+		if !found || strings.ContainsAny(prefix, regexMetaCharacters) {
+			continue
+		}
+		if !strings.HasPrefix(prefix, "/") {
+			prefix = "/" + prefix
+		}
+		return prefix, suffix, true
+	}
+	return "", regexPathSuffix{}, false
+}
+
+// toHTTPPathMatches returns the Gateway API path matches selecting the paths
+// matched by the parsed regular expression path.
+func toHTTPPathMatches(prefix string, suffix regexPathSuffix) []gatewayv1.HTTPPathMatch {
+	switch suffix.kind {
+	case exactRegexPath:
+		return []gatewayv1.HTTPPathMatch{{Type: ptr.To(gatewayv1.PathMatchExact), Value: ptr.To(prefix)}}
+	case exactOrSlashRegexPath:
+		trimmed := strings.TrimSuffix(prefix, "/")
+		if trimmed == "" {
+			return []gatewayv1.HTTPPathMatch{{Type: ptr.To(gatewayv1.PathMatchExact), Value: ptr.To("/")}}
+		}
+		return []gatewayv1.HTTPPathMatch{
+			{Type: ptr.To(gatewayv1.PathMatchExact), Value: ptr.To(trimmed)},
+			{Type: ptr.To(gatewayv1.PathMatchExact), Value: ptr.To(trimmed + "/")},
+		}
+	default:
+		if suffix.slashRequired && !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+		return []gatewayv1.HTTPPathMatch{{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To(prefix)}}
+	}
+}
+
+// convertRegexPathMatches replaces the PathPrefix matches of the rule
+// generated from the given regular expression path with the equivalent path
+// matches.
+func convertRegexPathMatches(rule *gatewayv1.HTTPRouteRule, path, prefix string, suffix regexPathSuffix) {
+	pathMatches := toHTTPPathMatches(prefix, suffix)
+
+	var matches []gatewayv1.HTTPRouteMatch
+	for _, match := range rule.Matches {
+		if !isPrefixMatchForPath(match, path) {
+			matches = append(matches, match)
+			continue
+		}
+		for _, pathMatch := range pathMatches {
+			converted := *match.DeepCopy()
+			converted.Path = pathMatch.DeepCopy()
+			matches = append(matches, converted)
+		}
+	}
+	rule.Matches = matches
+}
+
+// convertUnsupportedRegexPathMatches replaces the PathPrefix matches of the
+// rule generated from the given regular expression path with
+// RegularExpression matches.
+func convertUnsupportedRegexPathMatches(rule *gatewayv1.HTTPRouteRule, path string) {
+	// nginx only anchors the regular expression at the start of the path.
+	value := path
+	if !strings.HasSuffix(value, "$") {
+		value += ".*"
+	}
+	for i := range rule.Matches {
+		if isPrefixMatchForPath(rule.Matches[i], path) {
+			rule.Matches[i].Path = &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchRegularExpression), Value: ptr.To(value)}
+		}
+	}
+}
+
+func isPrefixMatchForPath(match gatewayv1.HTTPRouteMatch, path string) bool {
+	return match.Path != nil && match.Path.Type != nil && *match.Path.Type == gatewayv1.PathMatchPathPrefix &&
+		match.Path.Value != nil && *match.Path.Value == path
+}
+
+// toURLRewriteFilter returns the URLRewrite filter equivalent to the rewrite
+// target of the parsed regular expression path, or nil if there is none.
+//
+// nginx replaces the whole path with the target, in which captures are
+// expanded. A target without captures is a full path replacement, and a
+// target ending with the capture of the rest of the path is a prefix
+// replacement.
+func toURLRewriteFilter(prefix string, suffix regexPathSuffix, target string) *gatewayv1.HTTPURLRewriteFilter {
+	if !strings.Contains(target, "$") {
+		return &gatewayv1.HTTPURLRewriteFilter{Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To(target)}}
+	}
+	if suffix.kind != prefixRegexPath || suffix.restGroup == 0 {
+		return nil
+	}
+
+	base, found := strings.CutSuffix(target, fmt.Sprintf("$%d", suffix.restGroup))
+	if !found || strings.Contains(base, "$") {
+		return nil
+	}
+	// The prefix replacement keeps the slash following the prefix, so the
+	// target must provide it exactly once.
+	if suffix.restHasSlash == strings.HasSuffix(base, "/") {
+		return nil
+	}
+	replacement := strings.TrimSuffix(base, "/")
+	if replacement == "" {
+		replacement = "/"
+	}
+	return &gatewayv1.HTTPURLRewriteFilter{Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr.To(replacement)}}
+}
+
+// setURLRewrite adds the URLRewrite filter to the rule. It returns false if
+// the rule already has a different URLRewrite filter.
+func setURLRewrite(rule *gatewayv1.HTTPRouteRule, urlRewrite *gatewayv1.HTTPURLRewriteFilter) bool {
+	for _, filter := range rule.Filters {
+		if filter.Type == gatewayv1.HTTPRouteFilterURLRewrite {
+			return reflect.DeepEqual(filter.URLRewrite, urlRewrite)
+		}
+	}
+	rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{
+		Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+		URLRewrite: urlRewrite,
+	})
+	return true
+}
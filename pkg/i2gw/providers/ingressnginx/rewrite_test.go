@@ -0,0 +1,228 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_rewriteFeature(t *testing.T) {
+	testIngress := func(name string, annotations map[string]string, path string, pathType networkingv1.PathType) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptrTo("nginx"),
+				Rules: []networkingv1.IngressRule{{
+					Host: "example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path:     path,
+								PathType: &pathType,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: name,
+										Port: networkingv1.ServiceBackendPort{Number: 80},
+									},
+								},
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	pathMatch := func(pathType gatewayv1.PathMatchType, value string) gatewayv1.HTTPRouteMatch {
+		return gatewayv1.HTTPRouteMatch{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(pathType), Value: ptr.To(value)}}
+	}
+	replacePrefixMatch := func(value string) []gatewayv1.HTTPRouteFilter {
+		return []gatewayv1.HTTPRouteFilter{{
+			Type: gatewayv1.HTTPRouteFilterURLRewrite,
+			URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+				Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr.To(value)},
+			},
+		}}
+	}
+	replaceFullPath := func(value string) []gatewayv1.HTTPRouteFilter {
+		return []gatewayv1.HTTPRouteFilter{{
+			Type: gatewayv1.HTTPRouteFilterURLRewrite,
+			URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+				Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To(value)},
+			},
+		}}
+	}
+
+	testCases := []struct {
+		name           string
+		ingresses      []networkingv1.Ingress
+		expectedRules  []gatewayv1.HTTPRouteRule
+		expectedErrors int
+	}{
+		{
+			name: "paths are left untouched without regular expressions",
+			ingresses: []networkingv1.Ingress{
+				testIngress("app", nil, "/app", networkingv1.PathTypePrefix),
+			},
+			expectedRules: []gatewayv1.HTTPRouteRule{
+				{Matches: []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/app")}},
+			},
+		},
+		{
+			name: "segment capture is converted to a prefix replacement",
+			ingresses: []networkingv1.Ingress{
+				testIngress("app", map[string]string{rewriteTargetAnnotation: "/$2"}, "/app(/|$)(.*)", networkingv1.PathTypePrefix),
+			},
+			expectedRules: []gatewayv1.HTTPRouteRule{{
+				Matches: []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/app")},
+				Filters: replacePrefixMatch("/"),
+			}},
+		},
+		{
+			name: "capture after a required slash",
+			ingresses: []networkingv1.Ingress{
+				testIngress("app", map[string]string{rewriteTargetAnnotation: "/api/$1"}, "/app/(.*)", networkingv1.PathTypePrefix),
+			},
+			expectedRules: []gatewayv1.HTTPRouteRule{{
+				Matches: []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/app/")},
+				Filters: replacePrefixMatch("/api"),
+			}},
+		},
+		{
+			name: "target without captures replaces the full path",
+			ingresses: []networkingv1.Ingress{
+				testIngress("app", map[string]string{rewriteTargetAnnotation: "/"}, "/app", networkingv1.PathTypePrefix),
+			},
+			expectedRules: []gatewayv1.HTTPRouteRule{{
+				Matches: []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/app")},
+				Filters: replaceFullPath("/"),
+			}},
+		},
+		{
+			name: "optional trailing slash generates an additional exact match",
+			ingresses: []networkingv1.Ingress{
+				testIngress("app", map[string]string{useRegexAnnotation: "true"}, "/app/?$", networkingv1.PathTypePrefix),
+			},
+			expectedRules: []gatewayv1.HTTPRouteRule{{
+				Matches: []gatewayv1.HTTPRouteMatch{
+					pathMatch(gatewayv1.PathMatchExact, "/app"),
+					pathMatch(gatewayv1.PathMatchExact, "/app/"),
+				},
+			}},
+		},
+		{
+			name: "regular expressions apply to all the ingresses of the host",
+			ingresses: []networkingv1.Ingress{
+				testIngress("app", map[string]string{useRegexAnnotation: "true"}, "/app", networkingv1.PathTypePrefix),
+				testIngress("other", nil, "/other$", networkingv1.PathTypePrefix),
+			},
+			expectedRules: []gatewayv1.HTTPRouteRule{
+				{Matches: []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/app")}},
+				{Matches: []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchExact, "/other")}},
+			},
+		},
+		{
+			name: "unsupported regular expression",
+			ingresses: []networkingv1.Ingress{
+				testIngress("app", map[string]string{rewriteTargetAnnotation: "/$1"}, "/app/([0-9]+)/items", networkingv1.PathTypePrefix),
+			},
+			expectedRules: []gatewayv1.HTTPRouteRule{
+				{Matches: []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchRegularExpression, "/app/([0-9]+)/items.*")}},
+			},
+		},
+		{
+			name: "capture target on an exact path is ignored",
+			ingresses: []networkingv1.Ingress{
+				testIngress("app", map[string]string{rewriteTargetAnnotation: "/$1"}, "/app", networkingv1.PathTypeExact),
+			},
+			expectedRules: []gatewayv1.HTTPRouteRule{
+				{Matches: []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchExact, "/app")}},
+			},
+		},
+		{
+			name: "empty rewrite target is invalid",
+			ingresses: []networkingv1.Ingress{
+				testIngress("app", map[string]string{rewriteTargetAnnotation: ""}, "/app", networkingv1.PathTypePrefix),
+			},
+			expectedRules: []gatewayv1.HTTPRouteRule{
+				{Matches: []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/app")}},
+			},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir, errs := common.ToIR(tc.ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) > 0 {
+				t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+			}
+
+			errs = rewriteFeature(tc.ingresses, &ir)
+			if len(errs) != tc.expectedErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+
+			httpRoute := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: common.RouteName("app", "example.com")}]
+			var rules []gatewayv1.HTTPRouteRule
+			for _, rule := range httpRoute.Spec.Rules {
+				rules = append(rules, gatewayv1.HTTPRouteRule{Matches: rule.Matches, Filters: rule.Filters})
+			}
+			if diff := cmp.Diff(tc.expectedRules, rules); diff != "" {
+				t.Errorf("Unexpected rules (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_parseRegexPath(t *testing.T) {
+	testCases := []struct {
+		path           string
+		expectedPrefix string
+		expectedSuffix string
+		expectedOK     bool
+	}{
+		{path: "/app", expectedPrefix: "/app", expectedOK: true},
+		{path: "/app(/|$)(.*)", expectedPrefix: "/app", expectedSuffix: "(/|$)(.*)", expectedOK: true},
+		{path: "/app/(.*)", expectedPrefix: "/app", expectedSuffix: "/(.*)", expectedOK: true},
+		{path: "/app(.*)", expectedPrefix: "/app", expectedSuffix: "(.*)", expectedOK: true},
+		{path: "/(.*)", expectedPrefix: "/", expectedSuffix: "/(.*)", expectedOK: true},
+		{path: "/app/?$", expectedPrefix: "/app", expectedSuffix: "/?$", expectedOK: true},
+		{path: "/app/[a-z]+", expectedOK: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			prefix, suffix, ok := parseRegexPath(tc.path)
+			if ok != tc.expectedOK {
+				t.Fatalf("Expected ok to be %v, got %v", tc.expectedOK, ok)
+			}
+			if prefix != tc.expectedPrefix || suffix.suffix != tc.expectedSuffix {
+				t.Errorf("Expected prefix %q and suffix %q, got %q and %q", tc.expectedPrefix, tc.expectedSuffix, prefix, suffix.suffix)
+			}
+		})
+	}
+}
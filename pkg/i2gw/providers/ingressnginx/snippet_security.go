@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// securityControlKind is the kind of access control of a snippet.
+type securityControlKind string
+
+const (
+	deniedAddressesControl  securityControlKind = "denies the requests of the client addresses"
+	allowedAddressesControl securityControlKind = "only allows the requests of the client addresses"
+	deniedCountriesControl  securityControlKind = "denies the requests from the countries"
+	allowedCountriesControl securityControlKind = "only allows the requests from the countries"
+)
+
+// securityControl is an access control configured by a snippet, restricting
+// the clients by address or by country.
+type securityControl struct {
+	kind   securityControlKind
+	values []string
+}
+
+func (c securityControl) String() string {
+	return fmt.Sprintf("%s %s", c.kind, strings.Join(c.values, ", "))
+}
+
+var (
+	// accessDirectiveRegexp matches the allow and deny directives of the nginx
+	// access module.
+	accessDirectiveRegexp = regexp.MustCompile(`(?m)(?:^|[;{\s])(allow|deny)\s+([^\s;]+)\s*;`)
+	// geoIPConditionRegexp matches the conditions on the country of the
+	// clients of the geoip and geoip2 modules rejecting the requests.
+	geoIPConditionRegexp = regexp.MustCompile(`if\s*\(\s*\$geoip2?_\w*country\w*\s*(!?~\*?|!?=)\s*([^{]+?)\s*\)\s*\{\s*return\s+[45]\d\d`)
+	// countryCodeRegexp matches the ISO 3166 country codes of a condition.
+	countryCodeRegexp = regexp.MustCompile(`\b[A-Z]{2,3}\b`)
+)
+
+// parseSecurityControls returns the access controls by client address and by
+// country configured in the snippet, which have no Gateway API equivalent.
+func parseSecurityControls(snippet string) []securityControl {
+	var controls []securityControl
+
+	var allowed, denied []string
+	for _, match := range accessDirectiveRegexp.FindAllStringSubmatch(snippet, -1) {
+		directive, address := match[1], match[2]
+		switch {
+		case directive == "allow" && address != "all":
+			allowed = append(allowed, address)
+		case directive == "deny":
+			denied = append(denied, address)
+		}
+	}
+	switch {
+	case len(allowed) > 0:
+		// Addresses allowed before denying all the others.
+		controls = append(controls, securityControl{kind: allowedAddressesControl, values: allowed})
+	case len(denied) > 0:
+		controls = append(controls, securityControl{kind: deniedAddressesControl, values: denied})
+	}
+
+	for _, match := range geoIPConditionRegexp.FindAllStringSubmatch(snippet, -1) {
+		operator, value := match[1], match[2]
+		countries := countryCodeRegexp.FindAllString(value, -1)
+		if len(countries) == 0 {
+			continue
+		}
+		kind := deniedCountriesControl
+		if strings.HasPrefix(operator, "!") {
+			// Requests are rejected unless they come from the countries.
+			kind = allowedCountriesControl
+		}
+		controls = append(controls, securityControl{kind: kind, values: countries})
+	}
+
+	return controls
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseSecurityControls(t *testing.T) {
+	testCases := []struct {
+		name    string
+		snippet string
+		want    []securityControl
+	}{
+		{
+			name:    "no access control",
+			snippet: `more_set_headers "X-Frame-Options: DENY";`,
+		},
+		{
+			name:    "denied addresses",
+			snippet: "deny 192.168.1.1;\ndeny 10.0.0.0/8;",
+			want:    []securityControl{{kind: deniedAddressesControl, values: []string{"192.168.1.1", "10.0.0.0/8"}}},
+		},
+		{
+			name:    "allowed addresses",
+			snippet: "allow 10.0.0.0/8; allow 2001:db8::/32; deny all;",
+			want:    []securityControl{{kind: allowedAddressesControl, values: []string{"10.0.0.0/8", "2001:db8::/32"}}},
+		},
+		{
+			name:    "denied countries",
+			snippet: "if ($geoip_country_code ~ (CN|RU|KP)) {\n  return 403;\n}",
+			want:    []securityControl{{kind: deniedCountriesControl, values: []string{"CN", "RU", "KP"}}},
+		},
+		{
+			name:    "allowed countries with geoip2",
+			snippet: `if ($geoip2_data_country_code != "FR") { return 451; }`,
+			want:    []securityControl{{kind: allowedCountriesControl, values: []string{"FR"}}},
+		},
+		{
+			name:    "addresses and countries",
+			snippet: "location /admin { deny 203.0.113.7; }\nif ($geoip_country_code = US) { return 403; }",
+			want: []securityControl{
+				{kind: deniedAddressesControl, values: []string{"203.0.113.7"}},
+				{kind: deniedCountriesControl, values: []string{"US"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseSecurityControls(tc.snippet)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(securityControl{})); diff != "" {
+				t.Errorf("Unexpected security controls (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,507 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	serverSnippetAnnotation        = "nginx.ingress.kubernetes.io/server-snippet"
+	configurationSnippetAnnotation = "nginx.ingress.kubernetes.io/configuration-snippet"
+	locationSnippetAnnotation      = "nginx.ingress.kubernetes.io/location-snippet"
+)
+
+// snippetAnnotations are the annotations embedding raw nginx configuration.
+var snippetAnnotations = []string{serverSnippetAnnotation, configurationSnippetAnnotation, locationSnippetAnnotation}
+
+// snippetStatement is a top-level statement of a snippet, either a simple
+// directive or a block.
+type snippetStatement struct {
+	// raw is the statement as written in the snippet.
+	raw string
+	// args are the name and the unquoted arguments of a simple directive,
+	// nil for blocks and unterminated statements.
+	args []string
+}
+
+// snippetsFeature converts the common directives of the location snippets to
+// filters of the HTTPRoute rules generated from the paths of the annotated
+// Ingresses:
+//
+//   - return 301 and 302 to RequestRedirect filters,
+//   - return with other status codes to direct responses of the rules,
+//   - add_header and more_set_headers to ResponseHeaderModifier filters,
+//   - proxy_set_header to RequestHeaderModifier filters.
+//
+// The other directives, the ones using nginx variables and the whole server
+// snippets, applying to all the locations of the host, have no Gateway API
+// equivalent. They are recorded as unsupported features of the HTTPRoutes,
+// so that they can be ported manually.
+//
+// The directives are only converted when the profile enables snippet parsing,
+// otherwise the whole snippets are recorded as unsupported features. The
+// access controls of the snippets are reported either way.
+//
+// As it locates HTTPRoute rules by Ingress path, this feature must run before
+// the rewrite feature.
+func snippetsFeature(snippetParsing bool) i2gw.FeatureParser {
+	return func(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+		ruleGroups := common.GetRuleGroups(ingresses, ir)
+		for _, rg := range ruleGroups {
+			key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+			httpRouteContext, ok := ir.HTTPRoutes[key]
+			if !ok {
+				continue
+			}
+
+			// An Ingress may have several rules for the same host.
+			ruleIndicesByIngress := map[types.NamespacedName][]int{}
+			var ingressesOfGroup []networkingv1.Ingress
+			for _, rule := range rg.Rules {
+				ingressKey := types.NamespacedName{Namespace: rule.Ingress.Namespace, Name: rule.Ingress.Name}
+				if _, ok := ruleIndicesByIngress[ingressKey]; !ok {
+					ingressesOfGroup = append(ingressesOfGroup, rule.Ingress)
+				}
+				ruleIndicesByIngress[ingressKey] = append(ruleIndicesByIngress[ingressKey], common.RuleIndicesForIngressRule(httpRouteContext.HTTPRoute, rule.IngressRule)...)
+			}
+			// The rules merged from the same path of several Ingresses are
+			// shared by them.
+			var sharedRuleIndices []int
+			ruleIngresses := map[int]types.NamespacedName{}
+			for ingressKey, ruleIndices := range ruleIndicesByIngress {
+				for _, i := range ruleIndices {
+					if other, ok := ruleIngresses[i]; ok && other != ingressKey && !slices.Contains(sharedRuleIndices, i) {
+						sharedRuleIndices = append(sharedRuleIndices, i)
+					}
+					ruleIngresses[i] = ingressKey
+				}
+			}
+
+			for _, ingress := range ingressesOfGroup {
+				ingressKey := types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}
+				for _, annotation := range snippetAnnotations {
+					snippet, ok := ingress.Annotations[annotation]
+					if !ok {
+						continue
+					}
+
+					manual := snippet
+					if annotation != serverSnippetAnnotation && snippetParsing {
+						manual = convertSnippet(&httpRouteContext, ingress, annotation, snippet, ruleIndicesByIngress[ingressKey], sharedRuleIndices)
+					}
+					if annotation != serverSnippetAnnotation && !snippetParsing {
+						notify(notifications.WarningNotification, fmt.Sprintf("\"%v\" annotation of ingress %s/%s was not converted to filters, as the profile disables snippet parsing: use the %s profile to convert it", annotation, ingress.Namespace, ingress.Name, i2gw.AggressiveProfile), &httpRouteContext.HTTPRoute)
+					}
+					if manual != "" {
+						httpRouteContext.UnsupportedFeatures = append(httpRouteContext.UnsupportedFeatures, intermediate.UnsupportedFeature{
+							SourceKind: "Ingress",
+							Source:     ingressKey,
+							Name:       annotation,
+							RawConfig:  manual,
+						})
+						notify(notifications.WarningNotification, fmt.Sprintf("manual migration needed: \"%v\" annotation of ingress %s/%s has no Gateway API equivalent and must be ported manually: %s", annotation, ingress.Namespace, ingress.Name, strings.ReplaceAll(manual, "\n", " ")), &httpRouteContext.HTTPRoute)
+					}
+					for _, control := range parseSecurityControls(snippet) {
+						notify(notifications.WarningNotification, fmt.Sprintf("security control requires re-implementation: \"%v\" annotation of ingress %s/%s %s", annotation, ingress.Namespace, ingress.Name, control), &httpRouteContext.HTTPRoute)
+					}
+				}
+			}
+
+			ir.HTTPRoutes[key] = httpRouteContext
+		}
+
+		return nil
+	}
+}
+
+// convertSnippet converts the directives of a location snippet to filters of
+// the given HTTPRoute rules, of which the shared ones are merged from the
+// paths of other Ingresses too. It returns the statements left to port
+// manually, one per line.
+func convertSnippet(httpRouteContext *intermediate.HTTPRouteContext, ingress networkingv1.Ingress, annotation, snippet string, ruleIndices, sharedRuleIndices []int) string {
+	var manual []string
+	for _, statement := range parseSnippetStatements(snippet) {
+		if len(ruleIndices) == 0 || !convertSnippetStatement(httpRouteContext, ingress, statement, ruleIndices, sharedRuleIndices) {
+			manual = append(manual, statement.raw)
+			continue
+		}
+		notify(notifications.InfoNotification, fmt.Sprintf("converted \"%s\" of \"%v\" annotation of ingress %s/%s to filters of %v", statement.raw, annotation, ingress.Namespace, ingress.Name, field.NewPath("httproute", "spec", "rules")), &httpRouteContext.HTTPRoute)
+	}
+	return strings.Join(manual, "\n")
+}
+
+// convertSnippetStatement patches the given HTTPRoute rules with the filter
+// equivalent to the statement. It returns false, leaving the rules untouched,
+// if the statement has no equivalent or conflicts with the existing filters.
+func convertSnippetStatement(httpRouteContext *intermediate.HTTPRouteContext, ingress networkingv1.Ingress, statement snippetStatement, ruleIndices, sharedRuleIndices []int) bool {
+	if len(statement.args) == 0 {
+		return false
+	}
+	// The redirects check the variables of their URL on their own.
+	if statement.args[0] != "return" && slices.ContainsFunc(statement.args, func(arg string) bool { return strings.Contains(arg, "$") }) {
+		return false
+	}
+
+	var (
+		patch          func(rule *gatewayv1.HTTPRouteRule) bool
+		directResponse *intermediate.DirectResponse
+	)
+	switch name, args := statement.args[0], statement.args[1:]; {
+	case name == "return" && slices.ContainsFunc(ruleIndices, func(i int) bool { return slices.Contains(sharedRuleIndices, i) }):
+		// The rules shared with other Ingresses would answer their requests
+		// with the response, or redirect them, too.
+		return false
+	case name == "return" && slices.ContainsFunc(ruleIndices, func(i int) bool {
+		_, ok := httpRouteContext.DirectResponses[i]
+		return ok
+	}):
+		// Only the first return directive applies.
+		return false
+	case name == "return" && isSnippetDirectResponse(args):
+		response, ok := parseSnippetDirectResponse(args)
+		if !ok {
+			return false
+		}
+		directResponse = response
+		patch = func(rule *gatewayv1.HTTPRouteRule) bool {
+			if slices.ContainsFunc(rule.Filters, func(filter gatewayv1.HTTPRouteFilter) bool {
+				return filter.Type == gatewayv1.HTTPRouteFilterRequestRedirect
+			}) {
+				return false
+			}
+			// nginx returns the response without proxying the request.
+			rule.BackendRefs = nil
+			return true
+		}
+	case name == "return":
+		redirect, ok := parseSnippetRedirect(args)
+		// The path of the redirect would be rewritten by the rewrite-target
+		// annotation, converted later on.
+		if _, rewrite := ingress.Annotations[rewriteTargetAnnotation]; !ok || rewrite {
+			return false
+		}
+		patch = func(rule *gatewayv1.HTTPRouteRule) bool {
+			if slices.ContainsFunc(rule.Filters, func(filter gatewayv1.HTTPRouteFilter) bool {
+				return filter.Type == gatewayv1.HTTPRouteFilterRequestRedirect || filter.Type == gatewayv1.HTTPRouteFilterURLRewrite
+			}) {
+				return false
+			}
+			rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{
+				Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: redirect.DeepCopy(),
+			})
+			// nginx returns the redirect without proxying the request.
+			rule.BackendRefs = nil
+			return true
+		}
+	case name == "add_header" && (len(args) == 2 || len(args) == 3 && args[2] == "always"):
+		patch = func(rule *gatewayv1.HTTPRouteRule) bool {
+			return modifyResponseHeader(rule, false, gatewayv1.HTTPHeaderName(args[0]), args[1])
+		}
+	case name == "more_set_headers" && len(args) > 0:
+		headers := make([]gatewayv1.HTTPHeader, 0, len(args))
+		for _, arg := range args {
+			headerName, value, ok := strings.Cut(arg, ":")
+			if !ok || strings.HasPrefix(arg, "-") {
+				return false
+			}
+			headers = append(headers, gatewayv1.HTTPHeader{Name: gatewayv1.HTTPHeaderName(strings.TrimSpace(headerName)), Value: strings.TrimSpace(value)})
+		}
+		patch = func(rule *gatewayv1.HTTPRouteRule) bool {
+			for _, header := range headers {
+				if !modifyResponseHeader(rule, true, header.Name, header.Value) {
+					return false
+				}
+			}
+			return true
+		}
+	case name == "proxy_set_header" && len(args) == 2:
+		patch = func(rule *gatewayv1.HTTPRouteRule) bool {
+			if args[1] == "" {
+				// An empty value removes the header.
+				return removeRequestHeader(rule, args[0])
+			}
+			return setRequestHeader(rule, gatewayv1.HTTPHeaderName(args[0]), args[1])
+		}
+	default:
+		return false
+	}
+
+	// The rules are only patched if the statement applies to all of them.
+	rules := slices.Clone(httpRouteContext.Spec.Rules)
+	for _, i := range ruleIndices {
+		rules[i] = *rules[i].DeepCopy()
+		if !patch(&rules[i]) {
+			return false
+		}
+	}
+	httpRouteContext.Spec.Rules = rules
+	if directResponse != nil {
+		if httpRouteContext.DirectResponses == nil {
+			httpRouteContext.DirectResponses = map[int]intermediate.DirectResponse{}
+		}
+		for _, i := range ruleIndices {
+			httpRouteContext.DirectResponses[i] = *directResponse
+		}
+		notify(notifications.WarningNotification, fmt.Sprintf("Gateway API core lacks direct responses, the requests of ingress %s/%s are answered with a 500 status code unless the emitter generates its %d response", ingress.Namespace, ingress.Name, directResponse.StatusCode), &httpRouteContext.HTTPRoute)
+	}
+	return true
+}
+
+// snippetRedirectStatusCodes are the status codes of the return directives
+// redirecting the requests.
+var snippetRedirectStatusCodes = []string{"301", "302", "303", "307", "308"}
+
+// isSnippetDirectResponse returns whether the arguments of a return directive
+// respond with a status code rather than redirecting the request.
+func isSnippetDirectResponse(args []string) bool {
+	return len(args) > 0 && !slices.Contains(snippetRedirectStatusCodes, args[0]) && !strings.Contains(args[0], "/")
+}
+
+// parseSnippetDirectResponse returns the direct response of the arguments of
+// a return directive responding with a status code, or false if it has no
+// equivalent: the text of the response must be literal, and the status code
+// can't be the nginx specific 444, closing the connection without response.
+// The text is returned with the text/html default type of ingress-nginx.
+func parseSnippetDirectResponse(args []string) (*intermediate.DirectResponse, bool) {
+	if len(args) > 2 {
+		return nil, false
+	}
+	statusCode, err := strconv.Atoi(args[0])
+	if err != nil || statusCode < 100 || statusCode > 599 || statusCode == 444 {
+		return nil, false
+	}
+	response := &intermediate.DirectResponse{StatusCode: int32(statusCode)}
+	if len(args) == 2 {
+		if strings.Contains(args[1], "$") {
+			return nil, false
+		}
+		response.Body = args[1]
+		response.ContentType = "text/html"
+	}
+	return response, true
+}
+
+// parseSnippetRedirect returns the RequestRedirect filter equivalent to the
+// arguments of a return directive, or false if it has no equivalent: the
+// redirect must use a 301 or 302 status code, and its URL must either be a
+// literal path or keep the request URI with $request_uri. The only other
+// variable supported is $host, keeping the hostname of the request.
+func parseSnippetRedirect(args []string) (*gatewayv1.HTTPRequestRedirectFilter, bool) {
+	if len(args) != 2 {
+		return nil, false
+	}
+	statusCode, err := strconv.Atoi(args[0])
+	if err != nil || (statusCode != 301 && statusCode != 302) {
+		return nil, false
+	}
+	redirect := &gatewayv1.HTTPRequestRedirectFilter{StatusCode: ptr.To(statusCode)}
+
+	url, keepPath := strings.CutSuffix(args[1], "$request_uri")
+	if url == "" {
+		// Redirecting to the request URI loops.
+		return nil, false
+	}
+	path := url
+	if scheme, rest, ok := strings.Cut(url, "://"); ok {
+		if scheme != "http" && scheme != "https" {
+			return nil, false
+		}
+		redirect.Scheme = ptr.To(scheme)
+		var hostPort string
+		hostPort, path, _ = strings.Cut(rest, "/")
+		if path != "" || !keepPath {
+			path = "/" + path
+		}
+		host, port, hasPort := strings.Cut(hostPort, ":")
+		switch {
+		case host == "$host":
+			// The hostname of the request is kept.
+		case host == "" || strings.Contains(hostPort, "$"):
+			return nil, false
+		default:
+			redirect.Hostname = ptr.To(gatewayv1.PreciseHostname(host))
+		}
+		if hasPort {
+			portNumber, err := strconv.Atoi(port)
+			if err != nil || portNumber < 1 || portNumber > 65535 {
+				return nil, false
+			}
+			redirect.Port = ptr.To(gatewayv1.PortNumber(portNumber))
+		}
+	}
+
+	switch {
+	case keepPath && path == "":
+	case !keepPath && strings.HasPrefix(path, "/") && !strings.ContainsAny(path, "$?#"):
+		redirect.Path = &gatewayv1.HTTPPathModifier{
+			Type:            gatewayv1.FullPathHTTPPathModifier,
+			ReplaceFullPath: ptr.To(path),
+		}
+	default:
+		return nil, false
+	}
+	return redirect, true
+}
+
+// modifyResponseHeader adds or sets the given header in the
+// ResponseHeaderModifier filter of the rule, creating the filter if needed.
+// It returns false if the filter already modifies the header.
+func modifyResponseHeader(rule *gatewayv1.HTTPRouteRule, set bool, name gatewayv1.HTTPHeaderName, value string) bool {
+	var modifier *gatewayv1.HTTPHeaderFilter
+	for i := range rule.Filters {
+		if rule.Filters[i].Type == gatewayv1.HTTPRouteFilterResponseHeaderModifier && rule.Filters[i].ResponseHeaderModifier != nil {
+			modifier = rule.Filters[i].ResponseHeaderModifier
+		}
+	}
+	if modifier == nil {
+		rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{
+			Type:                   gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+			ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{},
+		})
+		modifier = rule.Filters[len(rule.Filters)-1].ResponseHeaderModifier
+	}
+
+	sameName := func(header gatewayv1.HTTPHeader) bool { return strings.EqualFold(string(header.Name), string(name)) }
+	if slices.ContainsFunc(modifier.Set, sameName) || slices.ContainsFunc(modifier.Add, sameName) {
+		return false
+	}
+	if set {
+		modifier.Set = append(modifier.Set, gatewayv1.HTTPHeader{Name: name, Value: value})
+	} else {
+		modifier.Add = append(modifier.Add, gatewayv1.HTTPHeader{Name: name, Value: value})
+	}
+	return true
+}
+
+// removeRequestHeader removes the given header in the RequestHeaderModifier
+// filter of the rule, creating the filter if needed. It returns false if the
+// filter sets the header.
+func removeRequestHeader(rule *gatewayv1.HTTPRouteRule, name string) bool {
+	for i := range rule.Filters {
+		filter := &rule.Filters[i]
+		if filter.Type != gatewayv1.HTTPRouteFilterRequestHeaderModifier || filter.RequestHeaderModifier == nil {
+			continue
+		}
+		if slices.ContainsFunc(filter.RequestHeaderModifier.Set, func(header gatewayv1.HTTPHeader) bool { return strings.EqualFold(string(header.Name), name) }) {
+			return false
+		}
+		if !slices.Contains(filter.RequestHeaderModifier.Remove, name) {
+			filter.RequestHeaderModifier.Remove = append(filter.RequestHeaderModifier.Remove, name)
+		}
+		return true
+	}
+
+	rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{
+		Type:                  gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+		RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{Remove: []string{name}},
+	})
+	return true
+}
+
+// parseSnippetStatements splits a snippet into its top-level statements,
+// skipping the comments. The arguments of the simple directives are unquoted.
+func parseSnippetStatements(snippet string) []snippetStatement {
+	var (
+		statements []snippetStatement
+		current    strings.Builder
+		args       []string
+		arg        strings.Builder
+		inArg      bool
+		quote      rune
+		escaped    bool
+		depth      int
+		comment    bool
+	)
+	endArg := func() {
+		if inArg {
+			args = append(args, arg.String())
+			arg.Reset()
+			inArg = false
+		}
+	}
+	endStatement := func(isDirective bool) {
+		endArg()
+		statement := snippetStatement{raw: strings.TrimSpace(current.String())}
+		if isDirective {
+			statement.args = args
+		}
+		if statement.raw != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+		args = nil
+	}
+
+	for _, r := range snippet {
+		switch {
+		case comment:
+			comment = r != '\n'
+			continue
+		case escaped:
+			escaped = false
+			arg.WriteRune(r)
+		case r == '\\' && quote != 0:
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '#':
+			comment = true
+			continue
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == '{':
+			depth++
+		case r == '}':
+			depth--
+		case r == ';' && depth == 0:
+			current.WriteRune(r)
+			endStatement(true)
+			continue
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			endArg()
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+		current.WriteRune(r)
+		if r == '}' && depth == 0 {
+			endStatement(false)
+		}
+	}
+	endStatement(false)
+
+	return statements
+}
@@ -0,0 +1,340 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_snippetsFeature(t *testing.T) {
+	iPrefix := networkingv1.PathTypePrefix
+	rule := func(path string) networkingv1.IngressRule {
+		return networkingv1.IngressRule{
+			Host: "example.com",
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     path,
+						PathType: &iPrefix,
+						Backend: networkingv1.IngressBackend{
+							Service: &networkingv1.IngressServiceBackend{
+								Name: "app",
+								Port: networkingv1.ServiceBackendPort{Number: 80},
+							},
+						},
+					}},
+				},
+			},
+		}
+	}
+
+	ingresses := []networkingv1.Ingress{{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: map[string]string{
+			serverSnippetAnnotation:        "location /admin { deny all; }",
+			configurationSnippetAnnotation: "more_set_headers \"X-Frame-Options: DENY\";\n# forwarded by default\nproxy_set_header X-Real-IP $remote_addr;\nproxy_set_header Accept-Encoding \"\";",
+		}},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptrTo("nginx"),
+			Rules:            []networkingv1.IngressRule{rule("/app"), rule("/other")},
+		},
+	}}
+
+	ir, errs := common.ToIR(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+	}
+	if errs = snippetsFeature(true)(ingresses, &ir); len(errs) > 0 {
+		t.Fatalf("Unexpected errors parsing snippets: %v", errs)
+	}
+
+	source := types.NamespacedName{Namespace: "default", Name: "app"}
+	expected := []intermediate.UnsupportedFeature{
+		{SourceKind: "Ingress", Source: source, Name: serverSnippetAnnotation, RawConfig: "location /admin { deny all; }"},
+		{SourceKind: "Ingress", Source: source, Name: configurationSnippetAnnotation, RawConfig: "proxy_set_header X-Real-IP $remote_addr;"},
+	}
+	httpRoute := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: common.RouteName("app", "example.com")}]
+	if diff := cmp.Diff(expected, httpRoute.UnsupportedFeatures); diff != "" {
+		t.Errorf("Unexpected unsupported features (-want +got): %s", diff)
+	}
+
+	expectedFilters := []gatewayv1.HTTPRouteFilter{
+		{
+			Type:                   gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+			ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{Set: []gatewayv1.HTTPHeader{{Name: "X-Frame-Options", Value: "DENY"}}},
+		},
+		{
+			Type:                  gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+			RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{Remove: []string{"Accept-Encoding"}},
+		},
+	}
+	for i, rule := range httpRoute.Spec.Rules {
+		if diff := cmp.Diff(expectedFilters, rule.Filters); diff != "" {
+			t.Errorf("Unexpected filters of rule %d (-want +got): %s", i, diff)
+		}
+	}
+}
+
+func Test_parseSnippetStatements(t *testing.T) {
+	snippet := `# redirect the old paths
+return 301 "https://example.com/new";
+location /admin {
+  deny all;
+}
+add_header X-Frame-Options 'DENY' always;
+if ($http_x_debug`
+
+	expected := []snippetStatement{
+		{raw: `return 301 "https://example.com/new";`, args: []string{"return", "301", "https://example.com/new"}},
+		{raw: "location /admin {\n  deny all;\n}"},
+		{raw: "add_header X-Frame-Options 'DENY' always;", args: []string{"add_header", "X-Frame-Options", "DENY", "always"}},
+		{raw: "if ($http_x_debug"},
+	}
+	if diff := cmp.Diff(expected, parseSnippetStatements(snippet), cmp.AllowUnexported(snippetStatement{})); diff != "" {
+		t.Errorf("Unexpected statements (-want +got): %s", diff)
+	}
+}
+
+func Test_parseSnippetRedirect(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected *gatewayv1.HTTPRequestRedirectFilter
+	}{
+		{
+			name: "https with request URI",
+			args: []string{"301", "https://$host$request_uri"},
+			expected: &gatewayv1.HTTPRequestRedirectFilter{
+				Scheme:     ptr.To("https"),
+				StatusCode: ptr.To(301),
+			},
+		},
+		{
+			name: "other host and port",
+			args: []string{"302", "http://example.com:8080$request_uri"},
+			expected: &gatewayv1.HTTPRequestRedirectFilter{
+				Scheme:     ptr.To("http"),
+				Hostname:   ptr.To[gatewayv1.PreciseHostname]("example.com"),
+				Port:       ptr.To[gatewayv1.PortNumber](8080),
+				StatusCode: ptr.To(302),
+			},
+		},
+		{
+			name: "full URL",
+			args: []string{"301", "https://example.com/new"},
+			expected: &gatewayv1.HTTPRequestRedirectFilter{
+				Scheme:     ptr.To("https"),
+				Hostname:   ptr.To[gatewayv1.PreciseHostname]("example.com"),
+				Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/new")},
+				StatusCode: ptr.To(301),
+			},
+		},
+		{
+			name: "path",
+			args: []string{"302", "/maintenance"},
+			expected: &gatewayv1.HTTPRequestRedirectFilter{
+				Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/maintenance")},
+				StatusCode: ptr.To(302),
+			},
+		},
+		{
+			name: "permanent redirect status code",
+			args: []string{"308", "https://$host$request_uri"},
+		},
+		{
+			name: "path prefix with request URI",
+			args: []string{"301", "https://example.com/v2$request_uri"},
+		},
+		{
+			name: "other variable",
+			args: []string{"301", "https://$server_name$request_uri"},
+		},
+		{
+			name: "response body",
+			args: []string{"403"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			redirect, ok := parseSnippetRedirect(tc.args)
+			if ok != (tc.expected != nil) {
+				t.Fatalf("Expected converted to be %v, got %v", tc.expected != nil, ok)
+			}
+			if diff := cmp.Diff(tc.expected, redirect); diff != "" {
+				t.Errorf("Unexpected redirect (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_parseSnippetDirectResponse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected *intermediate.DirectResponse
+	}{
+		{
+			name:     "status code",
+			args:     []string{"403"},
+			expected: &intermediate.DirectResponse{StatusCode: 403},
+		},
+		{
+			name:     "text",
+			args:     []string{"503", "Down for maintenance"},
+			expected: &intermediate.DirectResponse{StatusCode: 503, Body: "Down for maintenance", ContentType: "text/html"},
+		},
+		{
+			name: "text with variable",
+			args: []string{"200", "$remote_addr"},
+		},
+		{
+			name: "connection closed",
+			args: []string{"444"},
+		},
+		{
+			name: "invalid status code",
+			args: []string{"1000"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, ok := parseSnippetDirectResponse(tc.args)
+			if ok != (tc.expected != nil) {
+				t.Fatalf("Expected converted to be %v, got %v", tc.expected != nil, ok)
+			}
+			if diff := cmp.Diff(tc.expected, response); diff != "" {
+				t.Errorf("Unexpected direct response (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_snippetsFeature_directResponse(t *testing.T) {
+	ingresses := []networkingv1.Ingress{{
+		ObjectMeta: metav1.ObjectMeta{Name: "maintenance", Namespace: "default", Annotations: map[string]string{
+			configurationSnippetAnnotation: "return 503 \"Down for maintenance\";\nreturn 404;",
+		}},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptrTo("nginx"),
+			Rules: []networkingv1.IngressRule{{
+				Host: "example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/",
+							PathType: ptr.To(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{Name: "app", Port: networkingv1.ServiceBackendPort{Number: 80}},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}}
+
+	ir, errs := common.ToIR(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+	}
+	if errs = snippetsFeature(true)(ingresses, &ir); len(errs) > 0 {
+		t.Fatalf("Unexpected errors parsing snippets: %v", errs)
+	}
+
+	httpRoute := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: common.RouteName("maintenance", "example.com")}]
+	expected := map[int]intermediate.DirectResponse{0: {StatusCode: 503, Body: "Down for maintenance", ContentType: "text/html"}}
+	if diff := cmp.Diff(expected, httpRoute.DirectResponses); diff != "" {
+		t.Errorf("Unexpected direct responses (-want +got): %s", diff)
+	}
+	if len(httpRoute.Spec.Rules[0].BackendRefs) > 0 {
+		t.Errorf("Expected the rule of the direct response to have no backendRefs, got %v", httpRoute.Spec.Rules[0].BackendRefs)
+	}
+	// The second return directive is never reached by nginx.
+	expectedFeatures := []intermediate.UnsupportedFeature{{
+		SourceKind: "Ingress",
+		Source:     types.NamespacedName{Namespace: "default", Name: "maintenance"},
+		Name:       configurationSnippetAnnotation,
+		RawConfig:  "return 404;",
+	}}
+	if diff := cmp.Diff(expectedFeatures, httpRoute.UnsupportedFeatures); diff != "" {
+		t.Errorf("Unexpected unsupported features (-want +got): %s", diff)
+	}
+}
+
+func Test_snippetsFeature_directResponseSharedRule(t *testing.T) {
+	ingress := func(name string, annotations map[string]string, paths ...string) networkingv1.Ingress {
+		var httpPaths []networkingv1.HTTPIngressPath
+		for _, path := range paths {
+			httpPaths = append(httpPaths, networkingv1.HTTPIngressPath{
+				Path:     path,
+				PathType: ptr.To(networkingv1.PathTypePrefix),
+				Backend: networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{Name: name, Port: networkingv1.ServiceBackendPort{Number: 80}},
+				},
+			})
+		}
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptrTo("nginx"),
+				Rules: []networkingv1.IngressRule{{
+					Host:             "example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: httpPaths}},
+				}},
+			},
+		}
+	}
+	// The rule of the / path is merged from both Ingresses, so that its
+	// direct response would answer the requests of the app Ingress too.
+	ingresses := []networkingv1.Ingress{
+		ingress("app", nil, "/"),
+		ingress("maintenance", map[string]string{configurationSnippetAnnotation: "return 503;"}, "/", "/down"),
+	}
+
+	ir, errs := common.ToIR(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+	}
+	if errs = snippetsFeature(true)(ingresses, &ir); len(errs) > 0 {
+		t.Fatalf("Unexpected errors parsing snippets: %v", errs)
+	}
+
+	httpRoute := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: common.RouteName("app", "example.com")}]
+	if len(httpRoute.DirectResponses) > 0 {
+		t.Errorf("Expected no direct responses, got %v", httpRoute.DirectResponses)
+	}
+	expectedFeatures := []intermediate.UnsupportedFeature{{
+		SourceKind: "Ingress",
+		Source:     types.NamespacedName{Namespace: "default", Name: "maintenance"},
+		Name:       configurationSnippetAnnotation,
+		RawConfig:  "return 503;",
+	}}
+	if diff := cmp.Diff(expectedFeatures, httpRoute.UnsupportedFeatures); diff != "" {
+		t.Errorf("Unexpected unsupported features (-want +got): %s", diff)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	whitelistSourceRangeAnnotation = "nginx.ingress.kubernetes.io/whitelist-source-range"
+	denylistSourceRangeAnnotation  = "nginx.ingress.kubernetes.io/denylist-source-range"
+)
+
+// sourceRangeFeature parses the nginx.ingress.kubernetes.io/whitelist-source-range and
+// nginx.ingress.kubernetes.io/denylist-source-range annotations into the SourceRange policy
+// of the ingress-nginx HTTPRoute IR.
+//
+// The Gateway API has no core equivalent for restricting the client addresses, so the
+// policy can only be honored by implementation-specific emitters.
+func sourceRangeFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	return patchIngressPolicies(ingresses, ir, func(ingress networkingv1.Ingress) (func(*intermediate.IngressNginxPolicy), field.ErrorList) {
+		sourceRange, errs := parseSourceRangeAnnotations(ingress)
+		if sourceRange == nil {
+			return nil, errs
+		}
+		notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s restricts the client addresses, %s, which has no Gateway API equivalent: the ranges are only kept for implementation-specific emitters, without which the requests of all the clients are allowed", ingress.Namespace, ingress.Name, sourceRangeDescription(*sourceRange)), &ingress)
+		return func(policy *intermediate.IngressNginxPolicy) {
+			policy.SourceRange = sourceRange
+		}, errs
+	})
+}
+
+// parseSourceRangeAnnotations returns the SourceRangeConfig of the Ingress, or nil if the
+// client addresses are not restricted.
+func parseSourceRangeAnnotations(ingress networkingv1.Ingress) (*intermediate.SourceRangeConfig, field.ErrorList) {
+	annotationsPath := field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations")
+
+	var (
+		sourceRange intermediate.SourceRangeConfig
+		errs        field.ErrorList
+	)
+	for _, ranges := range []struct {
+		annotation string
+		cidrs      *[]string
+	}{
+		{annotation: whitelistSourceRangeAnnotation, cidrs: &sourceRange.Allow},
+		{annotation: denylistSourceRangeAnnotation, cidrs: &sourceRange.Deny},
+	} {
+		value, ok := ingress.Annotations[ranges.annotation]
+		if !ok {
+			continue
+		}
+		cidrs, err := parseCIDRs(value)
+		if err != nil {
+			errs = append(errs, field.Invalid(annotationsPath.Key(ranges.annotation), value, err.Error()))
+			continue
+		}
+		*ranges.cidrs = cidrs
+	}
+	if len(errs) > 0 || (len(sourceRange.Allow) == 0 && len(sourceRange.Deny) == 0) {
+		return nil, errs
+	}
+	return &sourceRange, nil
+}
+
+// parseCIDRs parses a comma-separated list of CIDRs. As in ingress-nginx, addresses
+// without prefix length are accepted as single-address ranges.
+func parseCIDRs(value string) ([]string, error) {
+	var cidrs []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			addr, err := netip.ParseAddr(item)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a valid address or CIDR", item)
+			}
+			cidrs = append(cidrs, netip.PrefixFrom(addr, addr.BitLen()).String())
+			continue
+		}
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid address or CIDR", item)
+		}
+		cidrs = append(cidrs, prefix.Masked().String())
+	}
+	return cidrs, nil
+}
+
+func sourceRangeDescription(sourceRange intermediate.SourceRangeConfig) string {
+	var parts []string
+	if len(sourceRange.Allow) > 0 {
+		parts = append(parts, fmt.Sprintf("allowing %v", sourceRange.Allow))
+	}
+	if len(sourceRange.Deny) > 0 {
+		parts = append(parts, fmt.Sprintf("denying %v", sourceRange.Deny))
+	}
+	return strings.Join(parts, " and ")
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_parseSourceRangeAnnotations(t *testing.T) {
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		expected       *intermediate.SourceRangeConfig
+		expectedErrors int
+	}{
+		{
+			name:        "no restriction",
+			annotations: map[string]string{},
+		},
+		{
+			name:        "allowed and denied ranges",
+			annotations: map[string]string{whitelistSourceRangeAnnotation: "10.0.0.0/8, 192.168.1.7", denylistSourceRangeAnnotation: "10.1.2.3/16,2001:db8::1"},
+			expected:    &intermediate.SourceRangeConfig{Allow: []string{"10.0.0.0/8", "192.168.1.7/32"}, Deny: []string{"10.1.0.0/16", "2001:db8::1/128"}},
+		},
+		{
+			name:        "empty range",
+			annotations: map[string]string{whitelistSourceRangeAnnotation: ""},
+		},
+		{
+			name:           "invalid range",
+			annotations:    map[string]string{whitelistSourceRangeAnnotation: "10.0.0.0/8,10.0.0.0/33"},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: tc.annotations}}
+			sourceRange, errs := parseSourceRangeAnnotations(ingress)
+			if len(errs) != tc.expectedErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if diff := cmp.Diff(tc.expected, sourceRange); diff != "" {
+				t.Errorf("Unexpected source range (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const forceSSLRedirectAnnotation = "nginx.ingress.kubernetes.io/force-ssl-redirect"
+
+// sslRedirectFeature converts the nginx.ingress.kubernetes.io/ssl-redirect and
+// force-ssl-redirect annotations, which redirect the HTTP requests of the
+// Ingress hosts to HTTPS. The HTTPRoute of such a host is attached to its
+// HTTPS listener only, and an HTTPRoute redirecting the requests of its paths
+// to HTTPS is attached to its HTTP listener.
+//
+// ssl-redirect only applies to the hosts the Ingress has TLS for, while
+// force-ssl-redirect applies to all of them. As the routes are split by
+// listener, the redirect is only converted when all the Ingresses of a route
+// request it and the Gateway has an HTTPS listener for its host.
+func sslRedirectFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses, ir)
+	rgKeys := make([]string, 0, len(ruleGroups))
+	for rgKey := range ruleGroups {
+		rgKeys = append(rgKeys, rgKey)
+	}
+	slices.Sort(rgKeys)
+	for _, rgKey := range rgKeys {
+		rg := ruleGroups[rgKey]
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRouteContext, ok := ir.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		var redirected, notRedirected []*networkingv1.Ingress
+		for i := range rg.Rules {
+			ingress := &rg.Rules[i].Ingress
+			if slices.ContainsFunc(redirected, sameIngress(ingress)) || slices.ContainsFunc(notRedirected, sameIngress(ingress)) {
+				continue
+			}
+			if redirectsToHTTPS(ingress, rg.Host) {
+				redirected = append(redirected, ingress)
+			} else {
+				notRedirected = append(notRedirected, ingress)
+			}
+		}
+		if len(redirected) == 0 {
+			continue
+		}
+		if len(notRedirected) > 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s redirects HTTP requests to HTTPS, but ingress %s/%s sharing HTTPRoute %s doesn't, the redirect was not converted",
+				redirected[0].Namespace, redirected[0].Name, notRedirected[0].Namespace, notRedirected[0].Name, key), &httpRouteContext.HTTPRoute)
+			continue
+		}
+
+		httpListener, httpsListener := hostListeners(ir, httpRouteContext.HTTPRoute, rg.Host)
+		if httpListener == nil || httpsListener == nil {
+			notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s redirects HTTP requests to HTTPS, but the Gateway of HTTPRoute %s has no HTTPS listener for its host, the redirect was not converted; if TLS is terminated in front of the Gateway, redirect the requests there",
+				redirected[0].Namespace, redirected[0].Name, key), &httpRouteContext.HTTPRoute)
+			continue
+		}
+
+		redirectContext := sslRedirectHTTPRoute(httpRouteContext, *httpListener)
+		for i := range httpRouteContext.Spec.ParentRefs {
+			httpRouteContext.Spec.ParentRefs[i].SectionName = ptr.To(*httpsListener)
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+		redirectKey := types.NamespacedName{Namespace: redirectContext.Namespace, Name: redirectContext.Name}
+		ir.HTTPRoutes[redirectKey] = redirectContext
+
+		notify(notifications.WarningNotification, fmt.Sprintf("converted the HTTPS redirect of ingress %s/%s to HTTPRoute %s attached to listener %s, with a 301 instead of the 308 of ingress-nginx, as Gateway API doesn't support it; clients may change the method of the redirected requests",
+			redirected[0].Namespace, redirected[0].Name, redirectKey, *httpListener), &redirectContext.HTTPRoute)
+	}
+	return nil
+}
+
+// redirectsToHTTPS returns whether the Ingress redirects the HTTP requests of
+// the host to HTTPS.
+func redirectsToHTTPS(ingress *networkingv1.Ingress, host string) bool {
+	if ingress.Annotations[forceSSLRedirectAnnotation] == "true" {
+		return true
+	}
+	if ingress.Annotations[sslRedirectAnnotation] != "true" {
+		return false
+	}
+	return slices.ContainsFunc(ingress.Spec.TLS, func(tls networkingv1.IngressTLS) bool {
+		return len(tls.Hosts) == 0 || slices.Contains(tls.Hosts, host)
+	})
+}
+
+// hostListeners returns the names of the HTTP and HTTPS listeners of the
+// host on the Gateway the HTTPRoute is attached to.
+func hostListeners(ir *intermediate.IR, httpRoute gatewayv1.HTTPRoute, host string) (httpListener, httpsListener *gatewayv1.SectionName) {
+	if len(httpRoute.Spec.ParentRefs) != 1 {
+		return nil, nil
+	}
+	gatewayKey := types.NamespacedName{Namespace: httpRoute.Namespace, Name: string(httpRoute.Spec.ParentRefs[0].Name)}
+	gatewayContext, ok := ir.Gateways[gatewayKey]
+	if !ok {
+		return nil, nil
+	}
+	for _, listener := range gatewayContext.Spec.Listeners {
+		if ptr.Deref(listener.Hostname, "") != gatewayv1.Hostname(host) {
+			continue
+		}
+		switch listener.Protocol {
+		case gatewayv1.HTTPProtocolType:
+			httpListener = ptr.To(listener.Name)
+		case gatewayv1.HTTPSProtocolType:
+			httpsListener = ptr.To(listener.Name)
+		}
+	}
+	return httpListener, httpsListener
+}
+
+// sslRedirectHTTPRoute returns the HTTPRoute redirecting the requests matched
+// by the given HTTPRoute to HTTPS, attached to the given HTTP listener.
+func sslRedirectHTTPRoute(httpRouteContext intermediate.HTTPRouteContext, httpListener gatewayv1.SectionName) intermediate.HTTPRouteContext {
+	var matches []gatewayv1.HTTPRouteMatch
+	for _, rule := range httpRouteContext.Spec.Rules {
+		for _, match := range rule.Matches {
+			if !slices.ContainsFunc(matches, func(m gatewayv1.HTTPRouteMatch) bool { return apiequality.Semantic.DeepEqual(m, match) }) {
+				matches = append(matches, *match.DeepCopy())
+			}
+		}
+	}
+
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: httpRouteContext.Namespace,
+			Name:      fmt.Sprintf("%s-ssl-redirect", httpRouteContext.Name),
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: slices.Clone(httpRouteContext.Spec.Hostnames),
+			Rules: []gatewayv1.HTTPRouteRule{{
+				Matches: matches,
+				Filters: []gatewayv1.HTTPRouteFilter{{
+					Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+					RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+						Scheme:     ptr.To("https"),
+						StatusCode: ptr.To(301),
+					},
+				}},
+			}},
+		},
+		Status: gatewayv1.HTTPRouteStatus{
+			RouteStatus: gatewayv1.RouteStatus{
+				Parents: []gatewayv1.RouteParentStatus{},
+			},
+		},
+	}
+	httpRoute.SetGroupVersionKind(common.HTTPRouteGVK)
+	for _, parentRef := range httpRouteContext.Spec.ParentRefs {
+		parentRef = *parentRef.DeepCopy()
+		parentRef.SectionName = ptr.To(httpListener)
+		httpRoute.Spec.ParentRefs = append(httpRoute.Spec.ParentRefs, parentRef)
+	}
+	return intermediate.HTTPRouteContext{HTTPRoute: httpRoute, Sources: slices.Clone(httpRouteContext.Sources)}
+}
+
+// sameIngress returns a function matching the Ingresses with the namespaced
+// name of the given one.
+func sameIngress(ingress *networkingv1.Ingress) func(*networkingv1.Ingress) bool {
+	return func(other *networkingv1.Ingress) bool {
+		return other.Namespace == ingress.Namespace && other.Name == ingress.Name
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_sslRedirectFeature(t *testing.T) {
+	iPrefix := networkingv1.PathTypePrefix
+
+	testIngress := func(name string, annotations map[string]string, path string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptrTo("nginx"),
+				TLS:              []networkingv1.IngressTLS{{Hosts: []string{"example.com"}, SecretName: "example-cert"}},
+				Rules: []networkingv1.IngressRule{{
+					Host: "example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path:     path,
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: name,
+										Port: networkingv1.ServiceBackendPort{Number: 80},
+									},
+								},
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+	routeKey := types.NamespacedName{Namespace: "default", Name: common.RouteName("app", "example.com")}
+	redirectKey := types.NamespacedName{Namespace: "default", Name: common.RouteName("app", "example.com") + "-ssl-redirect"}
+
+	testCases := []struct {
+		name               string
+		ingresses          []networkingv1.Ingress
+		expectedParentRefs []gatewayv1.ParentReference
+		expectedRedirect   bool
+	}{
+		{
+			name: "all ingresses of the route redirect",
+			ingresses: []networkingv1.Ingress{
+				testIngress("app", map[string]string{sslRedirectAnnotation: "true"}, "/app"),
+				testIngress("other", map[string]string{forceSSLRedirectAnnotation: "true"}, "/other"),
+			},
+			expectedParentRefs: []gatewayv1.ParentReference{{Name: "nginx", SectionName: ptrTo[gatewayv1.SectionName]("example-com-https")}},
+			expectedRedirect:   true,
+		},
+		{
+			name: "an ingress of the route doesn't redirect",
+			ingresses: []networkingv1.Ingress{
+				testIngress("app", map[string]string{sslRedirectAnnotation: "true"}, "/app"),
+				testIngress("other", map[string]string{sslRedirectAnnotation: "false"}, "/other"),
+			},
+			expectedParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir, errs := common.ToIR(tc.ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) > 0 {
+				t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+			}
+
+			if errs = sslRedirectFeature(tc.ingresses, &ir); len(errs) > 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+
+			if diff := cmp.Diff(tc.expectedParentRefs, ir.HTTPRoutes[routeKey].Spec.ParentRefs); diff != "" {
+				t.Errorf("Unexpected parentRefs (-want +got): %s", diff)
+			}
+			redirect, ok := ir.HTTPRoutes[redirectKey]
+			if ok != tc.expectedRedirect {
+				t.Fatalf("Expected redirect HTTPRoute: %t, got: %t", tc.expectedRedirect, ok)
+			}
+			if !ok {
+				return
+			}
+			expectedParentRefs := []gatewayv1.ParentReference{{Name: "nginx", SectionName: ptrTo[gatewayv1.SectionName]("example-com-http")}}
+			if diff := cmp.Diff(expectedParentRefs, redirect.Spec.ParentRefs); diff != "" {
+				t.Errorf("Unexpected redirect parentRefs (-want +got): %s", diff)
+			}
+			var paths []string
+			for _, match := range redirect.Spec.Rules[0].Matches {
+				paths = append(paths, *match.Path.Value)
+			}
+			if diff := cmp.Diff([]string{"/app", "/other"}, paths); diff != "" {
+				t.Errorf("Unexpected redirect paths (-want +got): %s", diff)
+			}
+		})
+	}
+}
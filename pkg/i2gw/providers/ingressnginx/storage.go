@@ -19,6 +19,7 @@ package ingressnginx
 import (
 	"sort"
 
+	apiv1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -29,6 +30,12 @@ type OrderedIngressMap struct {
 }
 type storage struct {
 	Ingresses OrderedIngressMap
+
+	// TCPServices and UDPServices are the ConfigMaps of the TCP and UDP
+	// services exposed by the controller, set by its --tcp-services-configmap
+	// and --udp-services-configmap flags, or nil if they don't exist.
+	TCPServices *apiv1.ConfigMap
+	UDPServices *apiv1.ConfigMap
 }
 
 func newResourcesStorage() *storage {
@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	proxyHTTPVersionAnnotation             = "nginx.ingress.kubernetes.io/proxy-http-version"
+	upstreamKeepaliveConnectionsAnnotation = "nginx.ingress.kubernetes.io/upstream-keepalive-connections"
+	upstreamKeepaliveRequestsAnnotation    = "nginx.ingress.kubernetes.io/upstream-keepalive-requests"
+	upstreamKeepaliveTimeAnnotation        = "nginx.ingress.kubernetes.io/upstream-keepalive-time"
+	upstreamKeepaliveTimeoutAnnotation     = "nginx.ingress.kubernetes.io/upstream-keepalive-timeout"
+)
+
+// upstreamConnectionFeature parses the nginx.ingress.kubernetes.io/proxy-http-version and
+// nginx.ingress.kubernetes.io/upstream-keepalive-* annotations into the UpstreamConnection
+// policy of the ingress-nginx HTTPRoute IR. gRPC backends, set with the
+// nginx.ingress.kubernetes.io/backend-protocol annotation, are proxied with HTTP/2.
+//
+// The Gateway API has no core equivalent for configuring the connections to the backends, so
+// the policy can only be honored by implementation-specific emitters.
+func upstreamConnectionFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	return patchIngressPolicies(ingresses, ir, func(ingress networkingv1.Ingress) (func(*intermediate.IngressNginxPolicy), field.ErrorList) {
+		upstreamConnection, errs := parseUpstreamConnectionAnnotations(ingress)
+		if upstreamConnection == nil {
+			return nil, errs
+		}
+		notify(notifications.InfoNotification, fmt.Sprintf("ingress %s/%s configures the connections to its backends, which has no Gateway API equivalent: the configuration is only kept for implementation-specific emitters", ingress.Namespace, ingress.Name), &ingress)
+		return func(policy *intermediate.IngressNginxPolicy) {
+			policy.UpstreamConnection = upstreamConnection
+		}, errs
+	})
+}
+
+// parseUpstreamConnectionAnnotations returns the UpstreamConnectionConfig of the Ingress, or
+// nil if the connections to the backends are configured with the defaults.
+func parseUpstreamConnectionAnnotations(ingress networkingv1.Ingress) (*intermediate.UpstreamConnectionConfig, field.ErrorList) {
+	annotationsPath := field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations")
+
+	var (
+		upstreamConnection intermediate.UpstreamConnectionConfig
+		errs               field.ErrorList
+	)
+	if version, ok := ingress.Annotations[proxyHTTPVersionAnnotation]; ok {
+		switch httpVersion := intermediate.HTTPVersion(strings.TrimSpace(version)); httpVersion {
+		case intermediate.HTTPVersion10, intermediate.HTTPVersion11:
+			upstreamConnection.HTTPVersion = httpVersion
+		default:
+			errs = append(errs, field.NotSupported(annotationsPath.Key(proxyHTTPVersionAnnotation), version, []string{string(intermediate.HTTPVersion10), string(intermediate.HTTPVersion11)}))
+		}
+	}
+	if protocol := strings.ToUpper(ingress.Annotations[backendProtocolAnnotation]); protocol == "GRPC" || protocol == "GRPCS" {
+		if upstreamConnection.HTTPVersion != "" {
+			notify(notifications.InfoNotification, fmt.Sprintf("%q annotation of ingress %s/%s is ignored, gRPC backends are proxied with HTTP/2", proxyHTTPVersionAnnotation, ingress.Namespace, ingress.Name), &ingress)
+		}
+		upstreamConnection.HTTPVersion = intermediate.HTTPVersion2
+	}
+
+	for _, count := range []struct {
+		annotation string
+		value      **int32
+	}{
+		{annotation: upstreamKeepaliveConnectionsAnnotation, value: &upstreamConnection.KeepaliveConnections},
+		{annotation: upstreamKeepaliveRequestsAnnotation, value: &upstreamConnection.KeepaliveRequests},
+	} {
+		value, ok := ingress.Annotations[count.annotation]
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+		if err != nil || parsed < 0 {
+			errs = append(errs, field.Invalid(annotationsPath.Key(count.annotation), value, "must be a non-negative integer"))
+			continue
+		}
+		*count.value = ptr.To(int32(parsed))
+	}
+
+	for _, duration := range []struct {
+		annotation string
+		value      **time.Duration
+	}{
+		{annotation: upstreamKeepaliveTimeAnnotation, value: &upstreamConnection.KeepaliveTime},
+		{annotation: upstreamKeepaliveTimeoutAnnotation, value: &upstreamConnection.KeepaliveTimeout},
+	} {
+		value, ok := ingress.Annotations[duration.annotation]
+		if !ok {
+			continue
+		}
+		parsed, err := parseNginxTime(value)
+		if err != nil {
+			errs = append(errs, field.Invalid(annotationsPath.Key(duration.annotation), value, err.Error()))
+			continue
+		}
+		*duration.value = ptr.To(parsed)
+	}
+
+	if len(errs) > 0 || upstreamConnection == (intermediate.UpstreamConnectionConfig{}) {
+		return nil, errs
+	}
+	return &upstreamConnection, nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func Test_parseUpstreamConnectionAnnotations(t *testing.T) {
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		expected       *intermediate.UpstreamConnectionConfig
+		expectedErrors int
+	}{
+		{
+			name:        "default connections",
+			annotations: map[string]string{},
+		},
+		{
+			name: "http version and keepalive",
+			annotations: map[string]string{
+				proxyHTTPVersionAnnotation:             "1.0",
+				upstreamKeepaliveConnectionsAnnotation: "32",
+				upstreamKeepaliveRequestsAnnotation:    "1000",
+				upstreamKeepaliveTimeAnnotation:        "1h",
+				upstreamKeepaliveTimeoutAnnotation:     "60",
+			},
+			expected: &intermediate.UpstreamConnectionConfig{
+				HTTPVersion:          intermediate.HTTPVersion10,
+				KeepaliveConnections: ptr.To[int32](32),
+				KeepaliveRequests:    ptr.To[int32](1000),
+				KeepaliveTime:        ptr.To(time.Hour),
+				KeepaliveTimeout:     ptr.To(60 * time.Second),
+			},
+		},
+		{
+			name:        "grpc backend",
+			annotations: map[string]string{backendProtocolAnnotation: "grpc", proxyHTTPVersionAnnotation: "1.1"},
+			expected:    &intermediate.UpstreamConnectionConfig{HTTPVersion: intermediate.HTTPVersion2},
+		},
+		{
+			name:           "unsupported http version",
+			annotations:    map[string]string{proxyHTTPVersionAnnotation: "2"},
+			expectedErrors: 1,
+		},
+		{
+			name:           "invalid keepalive",
+			annotations:    map[string]string{upstreamKeepaliveRequestsAnnotation: "-1", upstreamKeepaliveTimeoutAnnotation: "1 minute"},
+			expectedErrors: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: tc.annotations}}
+			upstreamConnection, errs := parseUpstreamConnectionAnnotations(ingress)
+			if len(errs) != tc.expectedErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if diff := cmp.Diff(tc.expected, upstreamConnection); diff != "" {
+				t.Errorf("Unexpected upstream connection (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_parseNginxTime(t *testing.T) {
+	testCases := []struct {
+		value         string
+		expected      time.Duration
+		expectedError bool
+	}{
+		{value: "60", expected: time.Minute},
+		{value: "500ms", expected: 500 * time.Millisecond},
+		{value: "1h30m", expected: 90 * time.Minute},
+		{value: "1d", expected: 24 * time.Hour},
+		{value: "1.5s", expectedError: true},
+		{value: "1 s", expectedError: true},
+		{value: "", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			duration, err := parseNginxTime(tc.value)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("Expected error to be %v, got %v", tc.expectedError, err)
+			}
+			if duration != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, duration)
+			}
+		})
+	}
+}
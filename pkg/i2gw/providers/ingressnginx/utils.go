@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// patchPolicy applies the given patch function to the policy of the Ingress in the HTTPRoute IR,
+// creating the IR and the policy if needed, and records the indices of the rules the policy
+// applies to.
+func patchPolicy(httpRouteContext *intermediate.HTTPRouteContext, ingressName string, ruleIndices []int, patch func(*intermediate.IngressNginxPolicy)) {
+	if httpRouteContext.ProviderSpecificIR.IngressNginx == nil {
+		httpRouteContext.ProviderSpecificIR.IngressNginx = &intermediate.IngressNginxHTTPRouteIR{}
+	}
+	routeIR := httpRouteContext.ProviderSpecificIR.IngressNginx
+	if routeIR.Policies == nil {
+		routeIR.Policies = map[string]intermediate.IngressNginxPolicy{}
+	}
+
+	policy := routeIR.Policies[ingressName]
+	for _, i := range ruleIndices {
+		if !slices.Contains(policy.RuleIndices, i) {
+			policy.RuleIndices = append(policy.RuleIndices, i)
+		}
+	}
+	slices.Sort(policy.RuleIndices)
+	patch(&policy)
+	routeIR.Policies[ingressName] = policy
+}
+
+// patchIngressPolicies parses every Ingress once with the given function, and applies the
+// returned patch function, if any, to the policy of the Ingress in every HTTPRoute generated
+// from its rules.
+func patchIngressPolicies(ingresses []networkingv1.Ingress, ir *intermediate.IR, parse func(networkingv1.Ingress) (func(*intermediate.IngressNginxPolicy), field.ErrorList)) field.ErrorList {
+	var errs field.ErrorList
+	patches := map[types.NamespacedName]func(*intermediate.IngressNginxPolicy){}
+	for _, ingress := range ingresses {
+		patch, parseErrs := parse(ingress)
+		errs = append(errs, parseErrs...)
+		if patch != nil {
+			patches[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = patch
+		}
+	}
+	if len(patches) == 0 {
+		return errs
+	}
+
+	for _, rg := range common.GetRuleGroups(ingresses, ir) {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRouteContext, ok := ir.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			patch, ok := patches[types.NamespacedName{Namespace: rule.Ingress.Namespace, Name: rule.Ingress.Name}]
+			if !ok {
+				continue
+			}
+			patchPolicy(&httpRouteContext, rule.Ingress.Name, common.RuleIndicesForIngressRule(httpRouteContext.HTTPRoute, rule.IngressRule), patch)
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+	return errs
+}
+
+var nginxSizeRegex = regexp.MustCompile(`^([0-9]+)([kKmMgG]?)$`)
+
+// parseNginxSize parses an nginx size, e.g. "8m", into bytes.
+func parseNginxSize(size string) (int64, error) {
+	groups := nginxSizeRegex.FindStringSubmatch(strings.TrimSpace(size))
+	if groups == nil {
+		return 0, fmt.Errorf("%q is not a valid size", size)
+	}
+	value, err := strconv.ParseInt(groups[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	switch strings.ToLower(groups[2]) {
+	case "k":
+		value <<= 10
+	case "m":
+		value <<= 20
+	case "g":
+		value <<= 30
+	}
+	return value, nil
+}
+
+var nginxTimeRegex = regexp.MustCompile(`([0-9]+)(ms|s|m|h|d|w)?`)
+
+// parseNginxTime parses an nginx time, e.g. "1h30m", seconds by default.
+func parseNginxTime(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || nginxTimeRegex.ReplaceAllString(value, "") != "" {
+		return 0, fmt.Errorf("%q is not a valid time", value)
+	}
+	var duration time.Duration
+	for _, groups := range nginxTimeRegex.FindAllStringSubmatch(value, -1) {
+		amount, err := strconv.ParseInt(groups[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		unit := time.Second
+		switch groups[2] {
+		case "ms":
+			unit = time.Millisecond
+		case "m":
+			unit = time.Minute
+		case "h":
+			unit = time.Hour
+		case "d":
+			unit = 24 * time.Hour
+		case "w":
+			unit = 7 * 24 * time.Hour
+		}
+		duration += time.Duration(amount) * unit
+	}
+	return duration, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	xForwardedPrefixAnnotation = "nginx.ingress.kubernetes.io/x-forwarded-prefix"
+	xForwardedPrefixHeader     = "X-Forwarded-Prefix"
+)
+
+// xForwardedPrefixFeature converts the nginx.ingress.kubernetes.io/x-forwarded-prefix
+// annotation into a RequestHeaderModifier filter setting the X-Forwarded-Prefix header
+// on the HTTPRoute rules generated from the paths of the annotated Ingress.
+func xForwardedPrefixFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	var errs field.ErrorList
+
+	ruleGroups := common.GetRuleGroups(ingresses, ir)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRouteContext, ok := ir.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		// The duplicate paths are merged into a single HTTPRoute rule, only
+		// notified once.
+		patchedRules := map[int]bool{}
+		for _, rule := range rg.Rules {
+			prefix, ok := rule.Ingress.Annotations[xForwardedPrefixAnnotation]
+			if !ok || rule.IngressRule.HTTP == nil {
+				continue
+			}
+			if prefix == "" {
+				fieldPath := field.NewPath(rule.Ingress.Namespace, rule.Ingress.Name, "metadata", "annotations").Key(xForwardedPrefixAnnotation)
+				errs = append(errs, field.Invalid(fieldPath, prefix, "the prefix must not be empty"))
+				continue
+			}
+
+			for _, path := range rule.IngressRule.HTTP.Paths {
+				for i := range httpRouteContext.Spec.Rules {
+					if !common.HTTPRouteRuleMatchesPath(httpRouteContext.Spec.Rules[i], path) {
+						continue
+					}
+					if setRequestHeader(&httpRouteContext.Spec.Rules[i], xForwardedPrefixHeader, prefix) {
+						if patchedRules[i] {
+							continue
+						}
+						patchedRules[i] = true
+						notify(notifications.InfoNotification, fmt.Sprintf("parsed \"%v\" annotation of ingress %s/%s and patched %v fields", xForwardedPrefixAnnotation, rule.Ingress.Namespace, rule.Ingress.Name, field.NewPath("httproute", "spec", "rules").Index(i).Child("filters")), &httpRouteContext.HTTPRoute)
+					} else {
+						notify(notifications.WarningNotification, fmt.Sprintf("conflicting \"%v\" annotation of ingress %s/%s for path %s was ignored", xForwardedPrefixAnnotation, rule.Ingress.Namespace, rule.Ingress.Name, path.Path), &httpRouteContext.HTTPRoute)
+					}
+				}
+			}
+		}
+
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+
+	return errs
+}
+
+// setRequestHeader sets the given header in the RequestHeaderModifier filter of the rule,
+// creating the filter if needed. It returns false if the header is already set to
+// a different value.
+func setRequestHeader(rule *gatewayv1.HTTPRouteRule, name gatewayv1.HTTPHeaderName, value string) bool {
+	for i := range rule.Filters {
+		filter := &rule.Filters[i]
+		if filter.Type != gatewayv1.HTTPRouteFilterRequestHeaderModifier || filter.RequestHeaderModifier == nil {
+			continue
+		}
+		for _, header := range filter.RequestHeaderModifier.Set {
+			if header.Name == name {
+				return header.Value == value
+			}
+		}
+		filter.RequestHeaderModifier.Set = append(filter.RequestHeaderModifier.Set, gatewayv1.HTTPHeader{Name: name, Value: value})
+		return true
+	}
+
+	rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{
+		Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+		RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+			Set: []gatewayv1.HTTPHeader{{Name: name, Value: value}},
+		},
+	})
+	return true
+}
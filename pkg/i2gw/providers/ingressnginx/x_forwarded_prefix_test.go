@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_xForwardedPrefixFeature(t *testing.T) {
+	iPrefix := networkingv1.PathTypePrefix
+
+	testIngress := func(name string, annotations map[string]string, path string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptrTo("nginx"),
+				Rules: []networkingv1.IngressRule{{
+					Host: "example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path:     path,
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: name,
+										Port: networkingv1.ServiceBackendPort{Number: 80},
+									},
+								},
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		ingresses       []networkingv1.Ingress
+		expectedFilters map[string][]gatewayv1.HTTPRouteFilter
+		expectedErrors  int
+	}{
+		{
+			name: "annotation sets the header on the rules of the annotated ingress only",
+			ingresses: []networkingv1.Ingress{
+				testIngress("app", map[string]string{xForwardedPrefixAnnotation: "/app"}, "/app"),
+				testIngress("other", nil, "/other"),
+			},
+			expectedFilters: map[string][]gatewayv1.HTTPRouteFilter{
+				"/app": {{
+					Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+					RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+						Set: []gatewayv1.HTTPHeader{{Name: "X-Forwarded-Prefix", Value: "/app"}},
+					},
+				}},
+				"/other": nil,
+			},
+		},
+		{
+			name: "empty annotation is invalid",
+			ingresses: []networkingv1.Ingress{
+				testIngress("app", map[string]string{xForwardedPrefixAnnotation: ""}, "/app"),
+			},
+			expectedFilters: map[string][]gatewayv1.HTTPRouteFilter{
+				"/app": nil,
+			},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir, errs := common.ToIR(tc.ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) > 0 {
+				t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+			}
+
+			errs = xForwardedPrefixFeature(tc.ingresses, &ir)
+			if len(errs) != tc.expectedErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+
+			httpRoute := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: common.RouteName("app", "example.com")}]
+			for _, rule := range httpRoute.Spec.Rules {
+				path := *rule.Matches[0].Path.Value
+				if diff := cmp.Diff(tc.expectedFilters[path], rule.Filters); diff != "" {
+					t.Errorf("Unexpected filters for path %s (-want +got): %s", path, diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_xForwardedPrefixFeature_duplicatePaths(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	t.Cleanup(func() {
+		notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	})
+
+	iPrefix := networkingv1.PathTypePrefix
+	path := networkingv1.HTTPIngressPath{
+		Path:     "/app",
+		PathType: &iPrefix,
+		Backend: networkingv1.IngressBackend{
+			Service: &networkingv1.IngressServiceBackend{
+				Name: "app",
+				Port: networkingv1.ServiceBackendPort{Number: 80},
+			},
+		},
+	}
+	ingresses := []networkingv1.Ingress{{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: map[string]string{xForwardedPrefixAnnotation: "/app"}},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptrTo("nginx"),
+			Rules: []networkingv1.IngressRule{{
+				Host: "example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{path, path}},
+				},
+			}},
+		},
+	}}
+
+	ir, errs := common.ToIR(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors converting ingresses: %v", errs)
+	}
+	if errs = xForwardedPrefixFeature(ingresses, &ir); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	var patched []string
+	for _, n := range notifications.NotificationAggr.Notifications[Name] {
+		if n.Type == notifications.InfoNotification && strings.Contains(n.Message, xForwardedPrefixAnnotation) {
+			patched = append(patched, n.Message)
+		}
+	}
+	if len(patched) != 1 {
+		t.Errorf("Expected a single notification for the duplicate paths, got %v", patched)
+	}
+}
+
+func Test_setRequestHeader(t *testing.T) {
+	rule := gatewayv1.HTTPRouteRule{
+		Filters: []gatewayv1.HTTPRouteFilter{{
+			Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+			RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+				Set: []gatewayv1.HTTPHeader{{Name: "X-Custom", Value: "value"}},
+			},
+		}},
+	}
+
+	if !setRequestHeader(&rule, xForwardedPrefixHeader, "/app") {
+		t.Errorf("Expected header to be added to the existing filter")
+	}
+	if !setRequestHeader(&rule, xForwardedPrefixHeader, "/app") {
+		t.Errorf("Expected setting the same value twice to succeed")
+	}
+	if setRequestHeader(&rule, xForwardedPrefixHeader, "/other") {
+		t.Errorf("Expected conflicting value to be rejected")
+	}
+
+	expectedFilters := []gatewayv1.HTTPRouteFilter{{
+		Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+		RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+			Set: []gatewayv1.HTTPHeader{{Name: "X-Custom", Value: "value"}, {Name: "X-Forwarded-Prefix", Value: "/app"}},
+		},
+	}}
+	if diff := cmp.Diff(expectedFilters, rule.Filters); diff != "" {
+		t.Errorf("Unexpected filters (-want +got): %s", diff)
+	}
+}
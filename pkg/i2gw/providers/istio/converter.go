@@ -18,24 +18,33 @@ package istio
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"net"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	istiov1beta1 "istio.io/api/networking/v1beta1"
 	istioclientv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/yaml"
 )
 
 type contextKey int
@@ -48,16 +57,63 @@ type resourcesToIRConverter struct {
 	// gw -> namespace -> hosts; stores hosts allowed by each Gateway
 	gwAllowedHosts map[types.NamespacedName]map[string]sets.Set[string]
 	ctx            context.Context
+	// mesh indicates whether HTTPRoutes attached to Services should be generated
+	// for the VirtualServices bound to the mesh.
+	mesh bool
+	// clusterDomain is the domain of the cluster-local Service hostnames.
+	clusterDomain string
+	// wellKnownCACertificates validates the generated BackendTLSPolicies.
+	wellKnownCACertificates gatewayv1alpha3.WellKnownCACertificatesType
+	// credentialNamespace is the namespace of the Secrets referenced by the
+	// credentialName of the Gateway servers, the one of each Gateway if empty.
+	credentialNamespace string
+	// unsupportedFeatures stores the features of the VirtualServices without
+	// Gateway API equivalent by key of the HTTPRoutes they were generated to.
+	unsupportedFeatures map[types.NamespacedName][]intermediate.UnsupportedFeature
+	// faultInjections stores the fault injection of the VirtualService routes
+	// by key of the HTTPRoutes they were generated to.
+	faultInjections map[types.NamespacedName]*intermediate.FaultInjectionConfig
+	// rulePriorities stores the priority of the VirtualService routes, by key
+	// of the HTTPRoutes they were generated to, Istio evaluating the routes
+	// in order.
+	rulePriorities map[types.NamespacedName]int32
+	// mirrorFractions stores the fractions of the requests the RequestMirror
+	// filters of the VirtualService routes mirror, by key of the HTTPRoutes
+	// they were generated to and index of the filter.
+	mirrorFractions map[types.NamespacedName]map[int]intermediate.MirrorFraction
+	// grpcRoutes indicates whether GRPCRoutes should be generated for the
+	// VirtualServices routing gRPC methods of the HTTP2 or GRPC servers.
+	grpcRoutes    bool
+	grpcRoutesErr *field.Error
+	// grpcGateways stores the keys of the Gateways with HTTP2 or GRPC servers.
+	grpcGateways sets.Set[types.NamespacedName]
 }
 
-func newResourcesToIRConverter() resourcesToIRConverter {
-	return resourcesToIRConverter{
+func newResourcesToIRConverter(conf *i2gw.ProviderConf) resourcesToIRConverter {
+	converter := resourcesToIRConverter{
 		gwAllowedHosts: make(map[types.NamespacedName]map[string]sets.Set[string]),
 		ctx:            context.Background(),
+		mesh:           conf.Mesh,
+		clusterDomain:  conf.ClusterDomain,
+
+		wellKnownCACertificates: conf.BackendTLSWellKnownCACertificates,
+		credentialNamespace:     conf.ProviderSpecificFlags[ProviderName][CredentialNamespaceFlag],
+		grpcGateways:            sets.New[types.NamespacedName](),
+	}
+	if grpcRoutes := conf.ProviderSpecificFlags[ProviderName][GRPCRoutesFlag]; grpcRoutes != "" {
+		var err error
+		if converter.grpcRoutes, err = strconv.ParseBool(grpcRoutes); err != nil {
+			converter.grpcRoutesErr = field.Invalid(field.NewPath(fmt.Sprintf("--%s-%s", ProviderName, GRPCRoutesFlag)), grpcRoutes, "must be a boolean")
+		}
 	}
+	return converter
 }
 
 func (c *resourcesToIRConverter) convertToIR(storage *storage) (intermediate.IR, field.ErrorList) {
+	if c.grpcRoutesErr != nil {
+		return intermediate.IR{}, field.ErrorList{c.grpcRoutesErr}
+	}
+
 	var errList field.ErrorList
 
 	gatewayResources := intermediate.IR{
@@ -67,6 +123,9 @@ func (c *resourcesToIRConverter) convertToIR(storage *storage) (intermediate.IR,
 		TCPRoutes:       make(map[types.NamespacedName]gatewayv1alpha2.TCPRoute),
 		ReferenceGrants: make(map[types.NamespacedName]gatewayv1beta1.ReferenceGrant),
 	}
+	if c.grpcRoutes {
+		gatewayResources.GRPCRoutes = make(map[types.NamespacedName]gatewayv1.GRPCRoute)
+	}
 
 	rootPath := field.NewPath(ProviderName)
 
@@ -83,41 +142,92 @@ func (c *resourcesToIRConverter) convertToIR(storage *storage) (intermediate.IR,
 		}] = intermediate.GatewayContext{Gateway: *gw}
 	}
 
-	for _, vs := range storage.VirtualServices {
-		vsFieldPath := rootPath.Child("VirtualService").Key(types.NamespacedName{
-			Namespace: vs.Namespace,
-			Name:      vs.Name,
-		}.String())
+	delegates := delegateVirtualServices(storage.VirtualServices)
+	// Services of other namespaces referenced by the routes delegated to
+	// VirtualServices of these namespaces, by namespace of the routes.
+	delegatedServices := map[string][]types.NamespacedName{}
+
+	for key, vs := range storage.VirtualServices {
+		vsFieldPath := rootPath.Child("VirtualService").Key(key.String())
+
+		if delegates.Has(key) {
+			notify(notifications.InfoNotification, fmt.Sprintf("VirtualService is a delegate, its routes are converted with the routes delegating to it, path: %v", vsFieldPath), vs)
+			continue
+		}
 
 		// We add Virtual Service to the context in order to reference the calling object during notifications
 		// generated from functions that do not have access to this object.
 		c.ctx = context.WithValue(c.ctx, virtualServiceKey, vs)
 
 		parentRefs, referenceGrants := c.generateReferences(vs, vsFieldPath)
+		meshParentRefs := c.generateMeshParentRefs(vs, vsFieldPath)
+
+		istioHTTPRoutes, services := c.expandDelegates(vs, storage.VirtualServices, vsFieldPath)
+		delegatedServices[vs.Namespace] = append(delegatedServices[vs.Namespace], services...)
 
-		httpRoutes, errors := c.convertVsHTTPRoutes(vs.ObjectMeta, vs.Spec.GetHttp(), vs.Spec.GetHosts(), vsFieldPath)
+		httpRoutes, errors := c.convertVsHTTPRoutes(vs.ObjectMeta, istioHTTPRoutes, vs.Spec.GetHosts(), vsFieldPath)
 		if len(errors) > 0 {
 			errList = append(errList, errors...)
 		} else {
 			for _, httpRoute := range httpRoutes {
-				httpRoute.Spec.ParentRefs = parentRefs
-				gatewayResources.HTTPRoutes[types.NamespacedName{
-					Namespace: httpRoute.Namespace,
-					Name:      httpRoute.Name,
-				}] = intermediate.HTTPRouteContext{HTTPRoute: *httpRoute}
+				httpRouteKey := types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}
+				if len(meshParentRefs) > 0 {
+					meshHTTPRoute := httpRoute
+					if len(parentRefs) > 0 {
+						// Gateway and mesh traffic are routed by distinct HTTPRoutes,
+						// since hostnames are ignored for routes attached to Services.
+						meshHTTPRoute = httpRoute.DeepCopy()
+						meshHTTPRoute.Name = fmt.Sprintf("%s-mesh", httpRoute.Name)
+					}
+					meshHTTPRoute.Spec.ParentRefs = meshParentRefs
+					meshHTTPRoute.Spec.Hostnames = nil
+					gatewayResources.HTTPRoutes[types.NamespacedName{
+						Namespace: meshHTTPRoute.Namespace,
+						Name:      meshHTTPRoute.Name,
+					}] = intermediate.HTTPRouteContext{
+						HTTPRoute:           *meshHTTPRoute,
+						ProviderSpecificIR:  c.providerSpecificIR(httpRouteKey),
+						UnsupportedFeatures: c.unsupportedFeatures[httpRouteKey],
+						RulePriorities:      c.routeRulePriorities(httpRouteKey),
+						MirrorFractions:     c.routeMirrorFractions(httpRouteKey),
+					}
+					if len(parentRefs) == 0 {
+						continue
+					}
+				}
+				for _, parentHTTPRoute := range c.parentHTTPRoutes(httpRoute, parentRefs, vs, vsFieldPath) {
+					parentHTTPRouteKey := types.NamespacedName{Namespace: parentHTTPRoute.Namespace, Name: parentHTTPRoute.Name}
+					if c.grpcRoutes && c.routesGRPC(parentHTTPRoute.Spec.ParentRefs, vs.Namespace) {
+						if grpcRoute, ok := c.toGRPCRoute(parentHTTPRoute, vs, vsFieldPath); ok {
+							gatewayResources.GRPCRoutes[parentHTTPRouteKey] = *grpcRoute
+							continue
+						}
+					}
+					gatewayResources.HTTPRoutes[parentHTTPRouteKey] = intermediate.HTTPRouteContext{
+						HTTPRoute:           *parentHTTPRoute,
+						ProviderSpecificIR:  c.providerSpecificIR(parentHTTPRouteKey),
+						UnsupportedFeatures: c.unsupportedFeatures[parentHTTPRouteKey],
+						RulePriorities:      c.routeRulePriorities(parentHTTPRouteKey),
+						MirrorFractions:     c.routeMirrorFractions(parentHTTPRouteKey),
+					}
+				}
 			}
 		}
 
-		for _, tlsRoute := range c.convertVsTLSRoutes(vs.ObjectMeta, vs.Spec.GetTls(), vsFieldPath) {
-			tlsRoute.Spec.ParentRefs = parentRefs
+		if len(meshParentRefs) > 0 && (len(vs.Spec.GetTls()) > 0 || len(vs.Spec.GetTcp()) > 0) {
+			notify(notifications.WarningNotification, fmt.Sprintf("mesh routes are only generated for HTTP traffic, TLS and TCP routes of the VirtualService are not attached to Services, path: %v", vsFieldPath), vs)
+		}
+
+		for i, tlsRoute := range c.convertVsTLSRoutes(vs.ObjectMeta, vs.Spec.GetTls(), vsFieldPath) {
+			tlsRoute.Spec.ParentRefs = l4ParentRefs(parentRefs, matchPorts(vs.Spec.GetTls()[i].GetMatch()), gatewayv1.TLSProtocolType, gatewayResources.Gateways, vs, vsFieldPath.Child("Tls").Index(i))
 			gatewayResources.TLSRoutes[types.NamespacedName{
 				Namespace: tlsRoute.Namespace,
 				Name:      tlsRoute.Name,
 			}] = *tlsRoute
 		}
 
-		for _, tcpRoute := range c.convertVsTCPRoutes(vs.ObjectMeta, vs.Spec.GetTcp(), vsFieldPath) {
-			tcpRoute.Spec.ParentRefs = parentRefs
+		for i, tcpRoute := range c.convertVsTCPRoutes(vs.ObjectMeta, vs.Spec.GetTcp(), vsFieldPath) {
+			tcpRoute.Spec.ParentRefs = l4ParentRefs(parentRefs, matchPorts(vs.Spec.GetTcp()[i].GetMatch()), gatewayv1.TCPProtocolType, gatewayResources.Gateways, vs, vsFieldPath.Child("Tcp").Index(i))
 			gatewayResources.TCPRoutes[types.NamespacedName{
 				Namespace: tcpRoute.Namespace,
 				Name:      tcpRoute.Name,
@@ -132,6 +242,24 @@ func (c *resourcesToIRConverter) convertToIR(storage *storage) (intermediate.IR,
 		}
 	}
 
+	for _, gatewayContext := range gatewayResources.Gateways {
+		addCertificateReferenceGrants(&gatewayResources, gatewayContext.Gateway)
+	}
+
+	for fromNamespace, services := range delegatedServices {
+		for _, service := range services {
+			addServiceReferenceGrant(&gatewayResources, fromNamespace, service)
+		}
+	}
+
+	for _, dr := range storage.DestinationRules {
+		drFieldPath := rootPath.Child("DestinationRule").Key(types.NamespacedName{
+			Namespace: dr.Namespace,
+			Name:      dr.Name,
+		}.String())
+		errList = append(errList, c.convertDestinationRule(dr, &gatewayResources, drFieldPath)...)
+	}
+
 	return gatewayResources, errList
 }
 
@@ -183,7 +311,10 @@ func (c *resourcesToIRConverter) convertGateway(gw *istioclientv1beta1.Gateway,
 			continue
 		}
 
-		var tlsMode gatewayv1.TLSModeType
+		var (
+			tlsMode         gatewayv1.TLSModeType
+			certificateRefs []gatewayv1.SecretObjectReference
+		)
 		if serverTLS := server.GetTls(); serverTLS != nil {
 			tlsFieldPath := serverFieldPath.Child("TLS")
 
@@ -204,13 +335,8 @@ func (c *resourcesToIRConverter) convertGateway(gw *istioclientv1beta1.Gateway,
 				notify(notifications.WarningNotification, fmt.Sprintf("ignoring field: %v", tlsFieldPath.Child("HttpsRedirect")), gw)
 				klog.Infof("ignoring field: %v", tlsFieldPath.Child("HttpsRedirect"))
 			}
-			if serverTLS.GetServerCertificate() != "" {
-				notify(notifications.WarningNotification, fmt.Sprintf("ignoring field: %v", tlsFieldPath.Child("ServerCertificate")), gw)
-				klog.Infof("ignoring field: %v", tlsFieldPath.Child("ServerCertificate"))
-			}
-			if serverTLS.GetPrivateKey() != "" {
-				notify(notifications.WarningNotification, fmt.Sprintf("ignoring field: %v", tlsFieldPath.Child("PrivateKey")), gw)
-				klog.Infof("ignoring field: %v", tlsFieldPath.Child("PrivateKey"))
+			if serverTLS.GetCredentialName() == "" && (serverTLS.GetServerCertificate() != "" || serverTLS.GetPrivateKey() != "") {
+				notify(notifications.WarningNotification, fmt.Sprintf("the certificate files of the server can't be referenced by Gateway listeners, store them in a kubernetes.io/tls Secret referenced by credentialName: %v", tlsFieldPath), gw)
 			}
 			if serverTLS.GetCaCertificates() != "" {
 				notify(notifications.WarningNotification, fmt.Sprintf("ignoring field: %v", tlsFieldPath.Child("CaCertificates")), gw)
@@ -220,9 +346,15 @@ func (c *resourcesToIRConverter) convertGateway(gw *istioclientv1beta1.Gateway,
 				notify(notifications.WarningNotification, fmt.Sprintf("ignoring field: %v", tlsFieldPath.Child("SubjectAltNames")), gw)
 				klog.Infof("ignoring field: %v", tlsFieldPath.Child("SubjectAltNames"))
 			}
-			if serverTLS.GetCredentialName() != "" {
-				notify(notifications.WarningNotification, fmt.Sprintf("ignoring field: %v", tlsFieldPath.Child("CredentialName")), gw)
-				klog.Infof("ignoring field: %v", tlsFieldPath.Child("CredentialName"))
+			if credentialName := serverTLS.GetCredentialName(); credentialName != "" && tlsMode == gatewayv1.TLSModeTerminate {
+				certificateRef := gatewayv1.SecretObjectReference{Name: gatewayv1.ObjectName(credentialName)}
+				if c.credentialNamespace != "" && c.credentialNamespace != gw.Namespace {
+					certificateRef.Namespace = ptr.To(gatewayv1.Namespace(c.credentialNamespace))
+				}
+				certificateRefs = []gatewayv1.SecretObjectReference{certificateRef}
+				if serverTLS.GetMode() == istiov1beta1.ServerTLSSettings_MUTUAL {
+					notify(notifications.WarningNotification, fmt.Sprintf("the validation of the client certificates of the server has no Gateway API equivalent, it was not converted: %v", tlsFieldPath.Child("Mode")), gw)
+				}
 			}
 			if len(serverTLS.GetVerifyCertificateSpki()) > 0 {
 				notify(notifications.WarningNotification, fmt.Sprintf("ignoring field: %v", tlsFieldPath.Child("VerifyCertificateSpki")), gw)
@@ -246,6 +378,24 @@ func (c *resourcesToIRConverter) convertGateway(gw *istioclientv1beta1.Gateway,
 			}
 		}
 
+		// Gateway API only configures TLS on HTTPS and TLS listeners, and only
+		// terminates it on HTTPS ones. The TLS settings of other servers only
+		// carry the httpsRedirect field, ignored above.
+		switch {
+		case protocol == gatewayv1.HTTPSProtocolType && tlsMode == gatewayv1.TLSModePassthrough:
+			notify(notifications.InfoNotification, fmt.Sprintf("HTTPS server passing TLS through is converted to a TLS listener, path %v", serverFieldPath), gw)
+			protocol = gatewayv1.TLSProtocolType
+		case protocol == gatewayv1.TLSProtocolType && tlsMode == "":
+			// The TLS mode of istio servers defaults to PASSTHROUGH.
+			tlsMode = gatewayv1.TLSModePassthrough
+		case protocol != gatewayv1.HTTPSProtocolType && protocol != gatewayv1.TLSProtocolType && tlsMode != "":
+			if protocol != gatewayv1.HTTPProtocolType {
+				notify(notifications.WarningNotification, fmt.Sprintf("ignoring field: %v", serverFieldPath.Child("TLS")), gw)
+				klog.Infof("ignoring field: %v", serverFieldPath.Child("TLS"))
+			}
+			tlsMode = ""
+		}
+
 		if server.GetBind() != "" {
 			notify(notifications.WarningNotification, fmt.Sprintf("ignoring field: %v", serverFieldPath.Child("Bind").Key(server.GetBind())), gw)
 			klog.Infof("ignoring field: %v", serverFieldPath.Child("Bind").Key(server.GetBind()))
@@ -258,7 +408,8 @@ func (c *resourcesToIRConverter) convertGateway(gw *istioclientv1beta1.Gateway,
 			}
 			if tlsMode != "" {
 				gwListener.TLS = &gatewayv1.GatewayTLSConfig{
-					Mode: &tlsMode,
+					Mode:            &tlsMode,
+					CertificateRefs: slices.Clone(certificateRefs),
 				}
 			}
 
@@ -284,6 +435,11 @@ func (c *resourcesToIRConverter) convertGateway(gw *istioclientv1beta1.Gateway,
 				gwListenerName = strings.ToLower(fmt.Sprintf("%v-protocol-dot-ns-%v", protocol, dnsName))
 			}
 			gwListenerName = strings.Replace(gwListenerName, "*", "wildcard", -1)
+			// Servers of the same protocol and hosts on several ports, e.g. HTTPS
+			// on 443 and 8443, yield listeners told apart by their port.
+			if slices.ContainsFunc(listeners, func(l gatewayv1.Listener) bool { return string(l.Name) == gwListenerName }) {
+				gwListenerName = fmt.Sprintf("%v-%v", gwListenerName, gwListener.Port)
+			}
 
 			// listener name should match RFC 1123 subdomain requirement: lowercase alphanumeric characters, '-' or '.', and must start and end with a lowercase alphanumeric character
 			gwListener.Name = gatewayv1.SectionName(gwListenerName)
@@ -296,10 +452,17 @@ func (c *resourcesToIRConverter) convertGateway(gw *istioclientv1beta1.Gateway,
 		return nil, errList
 	}
 
-	c.gwAllowedHosts[types.NamespacedName{
+	gwKey := types.NamespacedName{
 		Namespace: gw.Namespace,
 		Name:      gw.Name,
-	}] = gwAllowedHosts
+	}
+	c.gwAllowedHosts[gwKey] = gwAllowedHosts
+	if slices.ContainsFunc(gw.Spec.GetServers(), func(server *istiov1beta1.Server) bool {
+		protocol := server.GetPort().GetProtocol()
+		return protocol == "HTTP2" || protocol == "GRPC"
+	}) {
+		c.grpcGateways.Insert(gwKey)
+	}
 
 	gateway := gatewayv1.Gateway{
 		TypeMeta: metav1.TypeMeta{
@@ -581,23 +744,25 @@ func (c *resourcesToIRConverter) convertVsHTTPRoutes(virtualService metav1.Objec
 			notify(notifications.InfoNotification, fmt.Sprintf("ignoring field: %v", httpRouteFieldPath.Child("DirectResponse")), vs)
 			klog.Infof("ignoring field: %v", httpRouteFieldPath.Child("DirectResponse"))
 		}
-		if httpRoute.GetDelegate() != nil {
-			notify(notifications.InfoNotification, fmt.Sprintf("ignoring field: %v", httpRouteFieldPath.Child("Delegate")), vs)
-			klog.Infof("ignoring field: %v", httpRouteFieldPath.Child("Delegate"))
-		}
-		if httpRoute.GetRetries() != nil {
-			notify(notifications.InfoNotification, fmt.Sprintf("ignoring field: %v", httpRouteFieldPath.Child("Retries")), vs)
-			klog.Infof("ignoring field: %v", httpRouteFieldPath.Child("Retries"))
+		var unsupportedFeatures []intermediate.UnsupportedFeature
+		if retries := httpRoute.GetRetries(); retries != nil && (retries.GetAttempts() > 0 || retries.GetRetryOn() != "" || retries.GetRetryRemoteLocalities() != nil) {
+			// The HTTPRouteRetry isn't part of the supported Gateway API version,
+			// only the perTryTimeout is converted, as the backendRequest timeout.
+			notify(notifications.WarningNotification, fmt.Sprintf("retry policy has no Gateway API equivalent, it was not converted: %v", httpRouteFieldPath.Child("Retries")), vs)
+			unsupportedFeatures = append(unsupportedFeatures, routeFeature(vs, retries, field.NewPath("spec", "http").Key(httpRouteFieldName).Child("retries")))
 		}
-		if httpRoute.GetFault() != nil {
-			notify(notifications.InfoNotification, fmt.Sprintf("ignoring field: %v", httpRouteFieldPath.Child("Fault")), vs)
-			klog.Infof("ignoring field: %v", httpRouteFieldPath.Child("Fault"))
+		var faultInjection *intermediate.FaultInjectionConfig
+		if fault := httpRoute.GetFault(); fault != nil {
+			faultInjection = convertFault(vs, fault, httpRouteFieldPath.Child("Fault"))
 		}
-		if httpRoute.GetCorsPolicy() != nil {
-			notify(notifications.InfoNotification, fmt.Sprintf("ignoring field: %v", httpRouteFieldPath.Child("CorsPolicy")), vs)
-			klog.Infof("ignoring field: %v", httpRouteFieldPath.Child("CorsPolicy"))
+		if corsPolicy := httpRoute.GetCorsPolicy(); corsPolicy != nil {
+			// The HTTPCORSFilter isn't part of the supported Gateway API version,
+			// the policy is kept to be ported manually.
+			notify(notifications.WarningNotification, fmt.Sprintf("CORS policy has no Gateway API equivalent, it was not converted: %v", httpRouteFieldPath.Child("CorsPolicy")), vs)
+			unsupportedFeatures = append(unsupportedFeatures, routeFeature(vs, corsPolicy, field.NewPath("spec", "http").Key(httpRouteFieldName).Child("corsPolicy")))
 		}
 
+		mirrorFractions := map[int]intermediate.MirrorFraction{}
 		if httpRoute.GetMirror() != nil && len(httpRoute.GetMirrors()) > 0 {
 			errList = append(errList, field.Invalid(httpRouteFieldPath, httpRoute, "HTTP route cannot contain both mirror and mirrors"))
 			continue
@@ -608,6 +773,11 @@ func (c *resourcesToIRConverter) convertVsHTTPRoutes(virtualService metav1.Objec
 
 			backendObjRef := destination2backendObjRef(c.ctx, mirror, virtualService.Namespace, routeDestinationFieldPath)
 			if backendObjRef != nil {
+				if mirrorPercentage := httpRoute.GetMirrorPercentage(); mirrorPercentage != nil {
+					addMirrorFraction(mirrorFractions, len(gwHTTPRouteFilters), mirrorPercentage.GetValue())
+				} else if mirrorPercent := httpRoute.GetMirrorPercent(); mirrorPercent != nil {
+					addMirrorFraction(mirrorFractions, len(gwHTTPRouteFilters), float64(mirrorPercent.GetValue()))
+				}
 				gwHTTPRouteFilters = append(gwHTTPRouteFilters, gatewayv1.HTTPRouteFilter{
 					Type: gatewayv1.HTTPRouteFilterRequestMirror,
 					RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{
@@ -620,13 +790,11 @@ func (c *resourcesToIRConverter) convertVsHTTPRoutes(virtualService metav1.Objec
 		for j, mirror := range httpRoute.GetMirrors() {
 			routeDestinationFieldPath := httpRouteFieldPath.Child("Mirrors").Index(j)
 
-			if mirror.GetPercentage() != nil {
-				notify(notifications.InfoNotification, fmt.Sprintf("ignoring field: %v", routeDestinationFieldPath.Child("Percentage")), vs)
-				klog.Infof("ignoring field: %v", routeDestinationFieldPath.Child("Percentage"))
-			}
-
 			backendObjRef := destination2backendObjRef(c.ctx, mirror.GetDestination(), virtualService.Namespace, routeDestinationFieldPath)
 			if backendObjRef != nil {
+				if percentage := mirror.GetPercentage(); percentage != nil {
+					addMirrorFraction(mirrorFractions, len(gwHTTPRouteFilters), percentage.GetValue())
+				}
 				gwHTTPRouteFilters = append(gwHTTPRouteFilters, gatewayv1.HTTPRouteFilter{
 					Type: gatewayv1.HTTPRouteFilterRequestMirror,
 					RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{
@@ -638,9 +806,29 @@ func (c *resourcesToIRConverter) convertVsHTTPRoutes(virtualService metav1.Objec
 
 		var httpRouteTimeouts *gatewayv1.HTTPRouteTimeouts
 		if routeTimeout := httpRoute.GetTimeout(); routeTimeout != nil {
-			d := gatewayv1.Duration(routeTimeout.AsDuration().String())
-			httpRouteTimeouts = &gatewayv1.HTTPRouteTimeouts{
-				Request: &d,
+			d, err := common.ToGatewayDuration(routeTimeout.AsDuration())
+			if err != nil {
+				errList = append(errList, field.Invalid(httpRouteFieldPath.Child("Timeout"), routeTimeout.AsDuration().String(), err.Error()))
+			} else {
+				httpRouteTimeouts = &gatewayv1.HTTPRouteTimeouts{
+					Request: &d,
+				}
+			}
+		}
+		if perTryTimeout := httpRoute.GetRetries().GetPerTryTimeout(); perTryTimeout != nil {
+			perTryTimeoutFieldPath := httpRouteFieldPath.Child("Retries").Child("PerTryTimeout")
+			d, err := common.ToGatewayDuration(perTryTimeout.AsDuration())
+			switch {
+			case err != nil:
+				errList = append(errList, field.Invalid(perTryTimeoutFieldPath, perTryTimeout.AsDuration().String(), err.Error()))
+			case httpRoute.GetTimeout() != nil && perTryTimeout.AsDuration() > httpRoute.GetTimeout().AsDuration():
+				// The backendRequest timeout can't exceed the request one.
+				notify(notifications.WarningNotification, fmt.Sprintf("perTryTimeout exceeds the timeout of the route, it was not converted: %v", perTryTimeoutFieldPath), vs)
+			default:
+				if httpRouteTimeouts == nil {
+					httpRouteTimeouts = &gatewayv1.HTTPRouteTimeouts{}
+				}
+				httpRouteTimeouts.BackendRequest = &d
 			}
 		}
 
@@ -693,6 +881,10 @@ func (c *resourcesToIRConverter) convertVsHTTPRoutes(virtualService metav1.Objec
 			httpRoutesWithRewrites := c.createHTTPRoutesWithRewrite(createHTTPRouteParams, httpRoute.GetRewrite(), httpRouteFieldPath.Child("HTTPRewrite"))
 			resHTTPRoutes = append(resHTTPRoutes, httpRoutesWithRewrites...)
 			for _, httpRoute := range httpRoutesWithRewrites {
+				c.addUnsupportedFeatures(httpRoute, unsupportedFeatures)
+				c.addFaultInjection(httpRoute, faultInjection)
+				c.addMirrorFractions(httpRoute, mirrorFractions)
+				c.addRulePriority(httpRoute, len(istioHTTPRoutes)-i, len(istioHTTPRoutes))
 				notify(notifications.InfoNotification, fmt.Sprintf("successfully converted to HTTPRoute \"%v/%v\"", httpRoute.Namespace, httpRoute.Name), vs)
 			}
 			continue
@@ -700,6 +892,10 @@ func (c *resourcesToIRConverter) convertVsHTTPRoutes(virtualService metav1.Objec
 
 		httpRoute := c.createHTTPRoute(createHTTPRouteParams)
 		resHTTPRoutes = append(resHTTPRoutes, httpRoute)
+		c.addUnsupportedFeatures(httpRoute, unsupportedFeatures)
+		c.addFaultInjection(httpRoute, faultInjection)
+		c.addMirrorFractions(httpRoute, mirrorFractions)
+		c.addRulePriority(httpRoute, len(istioHTTPRoutes)-i, len(istioHTTPRoutes))
 		notify(notifications.InfoNotification, fmt.Sprintf("successfully converted to HTTPRoute \"%v/%v\"", httpRoute.Namespace, httpRoute.Name), vs)
 	}
 
@@ -710,6 +906,162 @@ func (c *resourcesToIRConverter) convertVsHTTPRoutes(virtualService metav1.Objec
 	return resHTTPRoutes, nil
 }
 
+// addMirrorFraction stores the fraction of the requests the RequestMirror
+// filter of the given index mirrors, for percentages below 100. The fraction
+// keeps the decimals of the percentage, up to a millionth.
+func addMirrorFraction(mirrorFractions map[int]intermediate.MirrorFraction, filterIndex int, percentage float64) {
+	if percentage >= 100 {
+		return
+	}
+	numerator, denominator := math.Max(percentage, 0), int32(100)
+	for math.Abs(numerator-math.Round(numerator)) > 1e-9 && denominator < 1000000 {
+		numerator *= 10
+		denominator *= 10
+	}
+	mirrorFractions[filterIndex] = intermediate.MirrorFraction{Numerator: int32(math.Round(numerator)), Denominator: denominator}
+}
+
+// addCertificateReferenceGrants grants the Gateway the references to the
+// Secrets of other namespaces its listeners terminate TLS with.
+func addCertificateReferenceGrants(ir *intermediate.IR, gateway gatewayv1.Gateway) {
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.TLS == nil {
+			continue
+		}
+		for _, certificateRef := range listener.TLS.CertificateRefs {
+			if certificateRef.Namespace == nil || string(*certificateRef.Namespace) == gateway.Namespace {
+				continue
+			}
+			addReferenceGrant(ir, gatewayv1beta1.ReferenceGrantFrom{
+				Group:     gatewayv1.Group(common.GatewayGVK.Group),
+				Kind:      gatewayv1.Kind(common.GatewayGVK.Kind),
+				Namespace: gatewayv1.Namespace(gateway.Namespace),
+			}, string(*certificateRef.Namespace), gatewayv1beta1.ReferenceGrantTo{Kind: "Secret", Name: ptr.To(certificateRef.Name)})
+		}
+	}
+}
+
+// addReferenceGrant grants the references from the given kind of resources to
+// the resource of the given namespace, merging the grant into the generated
+// ReferenceGrant between the namespaces if any.
+func addReferenceGrant(ir *intermediate.IR, from gatewayv1beta1.ReferenceGrantFrom, toNamespace string, to gatewayv1beta1.ReferenceGrantTo) {
+	key := types.NamespacedName{Namespace: toNamespace, Name: fmt.Sprintf("generated-reference-grant-from-%v-to-%v", from.Namespace, toNamespace)}
+	referenceGrant, ok := ir.ReferenceGrants[key]
+	if !ok {
+		referenceGrant = gatewayv1beta1.ReferenceGrant{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+		referenceGrant.SetGroupVersionKind(common.ReferenceGrantGVK)
+	}
+	if !slices.Contains(referenceGrant.Spec.From, from) {
+		referenceGrant.Spec.From = append(referenceGrant.Spec.From, from)
+	}
+	if !slices.ContainsFunc(referenceGrant.Spec.To, func(existing gatewayv1beta1.ReferenceGrantTo) bool {
+		return apiequality.Semantic.DeepEqual(existing, to)
+	}) {
+		referenceGrant.Spec.To = append(referenceGrant.Spec.To, to)
+	}
+	ir.ReferenceGrants[key] = referenceGrant
+}
+
+// addUnsupportedFeatures stores the unsupported features of the VirtualService
+// route the HTTPRoute was generated from.
+func (c *resourcesToIRConverter) addUnsupportedFeatures(httpRoute *gatewayv1.HTTPRoute, features []intermediate.UnsupportedFeature) {
+	if len(features) == 0 {
+		return
+	}
+	if c.unsupportedFeatures == nil {
+		c.unsupportedFeatures = make(map[types.NamespacedName][]intermediate.UnsupportedFeature)
+	}
+	key := types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}
+	c.unsupportedFeatures[key] = append(c.unsupportedFeatures[key], features...)
+}
+
+// addFaultInjection stores the fault injection of the VirtualService route the
+// HTTPRoute was generated from.
+func (c *resourcesToIRConverter) addFaultInjection(httpRoute *gatewayv1.HTTPRoute, faultInjection *intermediate.FaultInjectionConfig) {
+	if faultInjection == nil {
+		return
+	}
+	if c.faultInjections == nil {
+		c.faultInjections = make(map[types.NamespacedName]*intermediate.FaultInjectionConfig)
+	}
+	c.faultInjections[types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}] = faultInjection
+}
+
+// addMirrorFractions stores the fractions of the requests the RequestMirror
+// filters of the VirtualService route the HTTPRoute was generated from mirror,
+// by index of the filter.
+func (c *resourcesToIRConverter) addMirrorFractions(httpRoute *gatewayv1.HTTPRoute, mirrorFractions map[int]intermediate.MirrorFraction) {
+	if len(mirrorFractions) == 0 {
+		return
+	}
+	if c.mirrorFractions == nil {
+		c.mirrorFractions = make(map[types.NamespacedName]map[int]intermediate.MirrorFraction)
+	}
+	c.mirrorFractions[types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}] = mirrorFractions
+}
+
+// routeMirrorFractions returns the fractions of the requests the RequestMirror
+// filters of the single rule of the HTTPRoute of the given key mirror, if any.
+func (c *resourcesToIRConverter) routeMirrorFractions(httpRouteKey types.NamespacedName) map[int]map[int]intermediate.MirrorFraction {
+	mirrorFractions, ok := c.mirrorFractions[httpRouteKey]
+	if !ok {
+		return nil
+	}
+	return map[int]map[int]intermediate.MirrorFraction{0: mirrorFractions}
+}
+
+// addRulePriority stores the priority of the rule of the HTTPRoute generated
+// from the VirtualService route of the given priority, the first route of the
+// VirtualService having the highest. The routes of VirtualServices with a
+// single route have none.
+func (c *resourcesToIRConverter) addRulePriority(httpRoute *gatewayv1.HTTPRoute, priority, routes int) {
+	if routes < 2 {
+		return
+	}
+	if c.rulePriorities == nil {
+		c.rulePriorities = make(map[types.NamespacedName]int32)
+	}
+	c.rulePriorities[types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}] = int32(priority)
+}
+
+// routeRulePriorities returns the priority of the single rule of the HTTPRoute
+// of the given key, if any.
+func (c *resourcesToIRConverter) routeRulePriorities(httpRouteKey types.NamespacedName) map[int]int32 {
+	priority, ok := c.rulePriorities[httpRouteKey]
+	if !ok {
+		return nil
+	}
+	return map[int]int32{0: priority}
+}
+
+// providerSpecificIR returns the istio IR of the HTTPRoute of the given key,
+// if any.
+func (c *resourcesToIRConverter) providerSpecificIR(httpRouteKey types.NamespacedName) intermediate.ProviderSpecificHTTPRouteIR {
+	faultInjection, ok := c.faultInjections[httpRouteKey]
+	if !ok {
+		return intermediate.ProviderSpecificHTTPRouteIR{}
+	}
+	return intermediate.ProviderSpecificHTTPRouteIR{
+		Istio: &intermediate.IstioHTTPRouteIR{FaultInjection: faultInjection},
+	}
+}
+
+// routeFeature returns the field of the VirtualService route as an unsupported
+// feature, with its configuration in YAML.
+func routeFeature(vs *istioclientv1beta1.VirtualService, config json.Marshaler, fieldPath *field.Path) intermediate.UnsupportedFeature {
+	feature := intermediate.UnsupportedFeature{
+		SourceKind: VirtualServiceKind,
+		Source:     types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name},
+		Name:       fieldPath.String(),
+	}
+	if rawJSON, err := config.MarshalJSON(); err == nil {
+		if rawYAML, err := yaml.JSONToYAML(rawJSON); err == nil {
+			feature.RawConfig = string(rawYAML)
+		}
+	}
+	return feature
+}
+
 type createHTTPRouteParams struct {
 	objectMeta  metav1.ObjectMeta
 	hostnames   []gatewayv1.Hostname
@@ -856,10 +1208,6 @@ func (c *resourcesToIRConverter) convertVsTLSRoutes(virtualService metav1.Object
 				notify(notifications.InfoNotification, fmt.Sprintf("ignoring field: %v", tlsMatchFieldPath.Child("DestinationSubnets")), vs)
 				klog.Infof("ignoring field: %v", tlsMatchFieldPath.Child("DestinationSubnets"))
 			}
-			if match.GetPort() != 0 {
-				notify(notifications.InfoNotification, fmt.Sprintf("ignoring field: %v", tlsMatchFieldPath.Child("Port")), vs)
-				klog.Infof("ignoring field: %v", tlsMatchFieldPath.Child("Port"))
-			}
 			if len(match.GetSourceLabels()) > 0 {
 				notify(notifications.InfoNotification, fmt.Sprintf("ignoring field: %v", tlsMatchFieldPath.Child("SourceLabels")), vs)
 				klog.Infof("ignoring field: %v", tlsMatchFieldPath.Child("SourceLabels"))
@@ -932,10 +1280,6 @@ func (c *resourcesToIRConverter) convertVsTCPRoutes(virtualService metav1.Object
 				notify(notifications.InfoNotification, fmt.Sprintf("ignoring field: %v", tcpMatchFieldPath.Child("DestinationSubnets")), vs)
 				klog.Infof("ignoring field: %v", tcpMatchFieldPath.Child("DestinationSubnets"))
 			}
-			if match.GetPort() != 0 {
-				notify(notifications.InfoNotification, fmt.Sprintf("ignoring field: %v", tcpMatchFieldPath.Child("Port")), vs)
-				klog.Infof("ignoring field: %v", tcpMatchFieldPath.Child("Port"))
-			}
 			if match.GetSourceSubnet() != "" {
 				notify(notifications.InfoNotification, fmt.Sprintf("ignoring field: %v", tcpMatchFieldPath.Child("SourceSubnet")), vs)
 				klog.Infof("ignoring field: %v", tcpMatchFieldPath.Child("SourceSubnet"))
@@ -986,6 +1330,83 @@ func (c *resourcesToIRConverter) convertVsTCPRoutes(virtualService metav1.Object
 	return resTCPRoutes
 }
 
+// matchPorts returns the sorted ports of the matches of an L4 route, or nil if
+// one of them matches any port.
+func matchPorts[M interface{ GetPort() uint32 }](matches []M) []gatewayv1.PortNumber {
+	var ports []gatewayv1.PortNumber
+	for _, match := range matches {
+		if match.GetPort() == 0 {
+			return nil
+		}
+		if port := gatewayv1.PortNumber(match.GetPort()); !slices.Contains(ports, port) {
+			ports = append(ports, port)
+		}
+	}
+	slices.Sort(ports)
+	return ports
+}
+
+// l4ParentRefs returns the parentRefs of a TLS or TCP route attached to the
+// listeners of the given protocol of its Gateways, on the ports of its
+// matches if any: the sectionName of the listener when a single one binds a
+// port, the port otherwise, e.g. for TLS listeners told apart by SNI. Without
+// ports, the route is attached to the only port of such listeners, since it
+// would otherwise attach to all of them.
+func l4ParentRefs(parentRefs []gatewayv1.ParentReference, ports []gatewayv1.PortNumber, protocol gatewayv1.ProtocolType, gateways map[types.NamespacedName]intermediate.GatewayContext, vs *istioclientv1beta1.VirtualService, fieldPath *field.Path) []gatewayv1.ParentReference {
+	var refs []gatewayv1.ParentReference
+	for _, parentRef := range parentRefs {
+		gateway := types.NamespacedName{Namespace: vs.Namespace, Name: string(parentRef.Name)}
+		if parentRef.Namespace != nil {
+			gateway.Namespace = string(*parentRef.Namespace)
+		}
+		gatewayContext, ok := gateways[gateway]
+		if !ok {
+			refs = append(refs, parentRef)
+			continue
+		}
+
+		listenersByPort := map[gatewayv1.PortNumber][]gatewayv1.Listener{}
+		var listenerPorts []gatewayv1.PortNumber
+		for _, listener := range gatewayContext.Spec.Listeners {
+			if listener.Protocol != protocol {
+				continue
+			}
+			if _, ok := listenersByPort[listener.Port]; !ok {
+				listenerPorts = append(listenerPorts, listener.Port)
+			}
+			listenersByPort[listener.Port] = append(listenersByPort[listener.Port], listener)
+		}
+
+		routePorts := ports
+		if len(routePorts) == 0 {
+			if len(listenerPorts) != 1 {
+				if len(listenerPorts) > 1 {
+					notify(notifications.WarningNotification, fmt.Sprintf("%v route matches no port, it is attached to the %v listeners of all the ports %v of gateway %v, path: %v", protocol, protocol, listenerPorts, gateway, fieldPath), vs)
+				}
+				refs = append(refs, parentRef)
+				continue
+			}
+			routePorts = listenerPorts
+		}
+
+		for _, port := range routePorts {
+			listeners, ok := listenersByPort[port]
+			if !ok {
+				notify(notifications.WarningNotification, fmt.Sprintf("gateway %v has no %v listener on port %v matched by the route, parentRef is not generated for it, path: %v", gateway, protocol, port, fieldPath), vs)
+				continue
+			}
+			l4ParentRef := *parentRef.DeepCopy()
+			if len(listeners) == 1 {
+				l4ParentRef.SectionName = common.PtrTo(listeners[0].Name)
+			} else {
+				l4ParentRef.Port = common.PtrTo(port)
+			}
+			refs = append(refs, l4ParentRef)
+		}
+	}
+	return refs
+}
+
 func (c *resourcesToIRConverter) isVirtualServiceAllowedForGateway(gateway types.NamespacedName, vs *istioclientv1beta1.VirtualService, fieldPath *field.Path) bool {
 	// by default, if ExportTo is empty it allowes export of the VirtualService to all namespaces
 	vsAllowedNamespaces := sets.New("*")
@@ -1041,6 +1462,13 @@ func (c *resourcesToIRConverter) generateReferences(vs *istioclientv1beta1.Virtu
 	)
 
 	for _, allowedGateway := range vs.Spec.GetGateways() {
+		if allowedGateway == MeshGateway {
+			if !c.mesh {
+				notify(notifications.InfoNotification, fmt.Sprintf("VirtualService is bound to the mesh, use --mesh to generate routes attached to Services, path: %v", fieldPath), vs)
+			}
+			continue
+		}
+
 		gwNamespace, gwName, ok := strings.Cut(allowedGateway, "/")
 		if !ok {
 			gwNamespace, gwName = vs.Namespace, allowedGateway
@@ -1091,6 +1519,141 @@ func (c *resourcesToIRConverter) generateReferences(vs *istioclientv1beta1.Virtu
 	return parentRefs, referenceGrants
 }
 
+// parentHTTPRoutes attaches the HTTPRoute generated for the VirtualService to
+// the given Gateways. When the Gateways allow different hosts of the VirtualService,
+// one HTTPRoute named after each Gateway is generated instead, with the hostnames
+// narrowed to the hosts allowed by the Gateway, so that no parent is given
+// hostnames it doesn't accept.
+func (c *resourcesToIRConverter) parentHTTPRoutes(httpRoute *gatewayv1.HTTPRoute, parentRefs []gatewayv1.ParentReference, vs *istioclientv1beta1.VirtualService, fieldPath *field.Path) []*gatewayv1.HTTPRoute {
+	httpRoute.Spec.ParentRefs = parentRefs
+	if len(parentRefs) < 2 || len(httpRoute.Spec.Hostnames) == 0 {
+		return []*gatewayv1.HTTPRoute{httpRoute}
+	}
+
+	parentHostnames := make([][]gatewayv1.Hostname, len(parentRefs))
+	split := false
+	for i, parentRef := range parentRefs {
+		gateway := types.NamespacedName{Namespace: vs.Namespace, Name: string(parentRef.Name)}
+		if parentRef.Namespace != nil {
+			gateway.Namespace = string(*parentRef.Namespace)
+		}
+		parentHostnames[i] = narrowHostnames(httpRoute.Spec.Hostnames, c.gatewayHosts(gateway, vs.Namespace))
+		if !slices.Equal(parentHostnames[i], httpRoute.Spec.Hostnames) {
+			split = true
+		}
+	}
+	if !split {
+		return []*gatewayv1.HTTPRoute{httpRoute}
+	}
+
+	httpRouteKey := types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}
+	var httpRoutes []*gatewayv1.HTTPRoute
+	for i, parentRef := range parentRefs {
+		if len(parentHostnames[i]) == 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("gateway %s allows none of the hostnames of HTTPRoute %v, the route is not attached to it, path: %v", parentRef.Name, httpRouteKey, fieldPath), vs)
+			continue
+		}
+		name := fmt.Sprintf("%s-%s", httpRoute.Name, parentRef.Name)
+		if parentRef.Namespace != nil {
+			name = fmt.Sprintf("%s-%s-%s", httpRoute.Name, *parentRef.Namespace, parentRef.Name)
+		}
+		parentHTTPRoute := httpRoute.DeepCopy()
+		parentHTTPRoute.Name = name
+		parentHTTPRoute.Spec.ParentRefs = []gatewayv1.ParentReference{parentRef}
+		parentHTTPRoute.Spec.Hostnames = parentHostnames[i]
+		c.addUnsupportedFeatures(parentHTTPRoute, c.unsupportedFeatures[httpRouteKey])
+		c.addFaultInjection(parentHTTPRoute, c.faultInjections[httpRouteKey])
+		c.addMirrorFractions(parentHTTPRoute, c.mirrorFractions[httpRouteKey])
+		if priority, ok := c.rulePriorities[httpRouteKey]; ok {
+			c.rulePriorities[types.NamespacedName{Namespace: parentHTTPRoute.Namespace, Name: name}] = priority
+		}
+		httpRoutes = append(httpRoutes, parentHTTPRoute)
+		notify(notifications.InfoNotification, fmt.Sprintf("gateways of the VirtualService allow different hosts, generated HTTPRoute %s/%s for gateway %s with hostnames %v, path: %v", parentHTTPRoute.Namespace, name, parentRef.Name, parentHostnames[i], fieldPath), vs)
+	}
+	return httpRoutes
+}
+
+// gatewayHosts returns the hosts the Gateway allows for the VirtualServices of
+// the given namespace.
+func (c *resourcesToIRConverter) gatewayHosts(gateway types.NamespacedName, vsNamespace string) []string {
+	allowedHosts := c.gwAllowedHosts[gateway]
+	hosts := sets.New[string]()
+	hosts = hosts.Union(allowedHosts[vsNamespace]).Union(allowedHosts["*"])
+	if vsNamespace == gateway.Namespace {
+		hosts = hosts.Union(allowedHosts["."])
+	}
+	return sets.List(hosts)
+}
+
+// narrowHostnames returns the intersection of the hostnames with the hosts, in
+// the order of the hostnames: the hostnames matching a host at least as general,
+// otherwise the more specific hosts they match, e.g. foo.example.com for *.example.com.
+func narrowHostnames(hostnames []gatewayv1.Hostname, hosts []string) []gatewayv1.Hostname {
+	var narrowed []gatewayv1.Hostname
+	add := func(hostname gatewayv1.Hostname) {
+		if !slices.Contains(narrowed, hostname) {
+			narrowed = append(narrowed, hostname)
+		}
+	}
+	for _, hostname := range hostnames {
+		var specificHosts []string
+		covered := false
+		for _, host := range hosts {
+			if !matches(string(hostname), host) {
+				continue
+			}
+			if isWildCarded(string(hostname)) && (!isWildCarded(host) || len(host) > len(hostname)) {
+				specificHosts = append(specificHosts, host)
+				continue
+			}
+			covered = true
+			break
+		}
+		if covered {
+			add(hostname)
+			continue
+		}
+		for _, host := range specificHosts {
+			add(gatewayv1.Hostname(host))
+		}
+	}
+	return narrowed
+}
+
+// generateMeshParentRefs generates parentRefs to the Services of the VirtualService hosts, as defined by GAMMA,
+// if mesh routes should be generated and the VirtualService is bound to the mesh, i.e. its gateways are either
+// empty or include the reserved "mesh" gateway.
+func (c *resourcesToIRConverter) generateMeshParentRefs(vs *istioclientv1beta1.VirtualService, fieldPath *field.Path) []gatewayv1.ParentReference {
+	if !c.mesh {
+		return nil
+	}
+	if len(vs.Spec.GetGateways()) > 0 && !slices.Contains(vs.Spec.GetGateways(), MeshGateway) {
+		return nil
+	}
+
+	var parentRefs []gatewayv1.ParentReference
+	for i, host := range vs.Spec.GetHosts() {
+		hostFieldPath := fieldPath.Child("Spec", "Hosts").Index(i)
+
+		var service types.NamespacedName
+		if !strings.Contains(host, ".") {
+			// short names are interpreted relative to the VirtualService namespace
+			service = types.NamespacedName{Namespace: vs.Namespace, Name: host}
+		} else if svc, ok := common.ServiceFromHostname(host, c.clusterDomain); ok {
+			service = svc
+		} else {
+			notify(notifications.WarningNotification, fmt.Sprintf("host %q is not a Kubernetes Service, mesh parentRef is not generated for it, path: %v", host, hostFieldPath), vs)
+			klog.Warningf("host %q is not a Kubernetes Service, mesh parentRef is not generated for it, path: %v", host, hostFieldPath)
+			continue
+		}
+
+		parentRefs = append(parentRefs, common.ServiceParentRef(service, vs.Namespace))
+		notify(notifications.InfoNotification, fmt.Sprintf("generated new mesh Parent Reference to Service %v", service), vs)
+	}
+
+	return parentRefs
+}
+
 type generateReferenceGrantsParams struct {
 	gateway                                types.NamespacedName
 	fromNamespace                          string
@@ -23,6 +23,9 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	"google.golang.org/protobuf/types/known/durationpb"
 	istiov1beta1 "istio.io/api/networking/v1beta1"
@@ -172,37 +175,37 @@ func Test_resourcesToIRConverter_convertGateway(t *testing.T) {
 							Protocol: "HTTP",
 						},
 						{
-							Name:     "https-protocol-https-ns-wildcard.example.com",
+							Name:     "tls-protocol-https-ns-wildcard.example.com",
 							Hostname: common.PtrTo(gatewayv1.Hostname("*.example.com")),
 							Port:     443,
-							Protocol: "HTTPS",
+							Protocol: "TLS",
 							TLS:      &gatewayv1.GatewayTLSConfig{Mode: common.PtrTo(gatewayv1.TLSModeType("Passthrough"))},
 						},
 						{
-							Name:     "https-protocol-https-ns-wildcard",
+							Name:     "tls-protocol-https-ns-wildcard",
 							Port:     443,
-							Protocol: "HTTPS",
+							Protocol: "TLS",
 							TLS:      &gatewayv1.GatewayTLSConfig{Mode: common.PtrTo(gatewayv1.TLSModeType("Passthrough"))},
 						},
 						{
-							Name:     "https-protocol-wildcard-ns-foo.example.com",
+							Name:     "tls-protocol-wildcard-ns-foo.example.com",
 							Hostname: common.PtrTo(gatewayv1.Hostname("foo.example.com")),
 							Port:     443,
-							Protocol: "HTTPS",
+							Protocol: "TLS",
 							TLS:      &gatewayv1.GatewayTLSConfig{Mode: common.PtrTo(gatewayv1.TLSModeType("Passthrough"))},
 						},
 						{
-							Name:     "https-protocol-dot-ns-foo.example.com",
+							Name:     "tls-protocol-dot-ns-foo.example.com",
 							Hostname: common.PtrTo(gatewayv1.Hostname("foo.example.com")),
 							Port:     443,
-							Protocol: "HTTPS",
+							Protocol: "TLS",
 							TLS:      &gatewayv1.GatewayTLSConfig{Mode: common.PtrTo(gatewayv1.TLSModeType("Passthrough"))},
 						},
 						{
-							Name:     "https-protocol-wildcard-ns-wildcard.example.com",
+							Name:     "tls-protocol-wildcard-ns-wildcard.example.com",
 							Hostname: common.PtrTo(gatewayv1.Hostname("*.example.com")),
 							Port:     443,
-							Protocol: "HTTPS",
+							Protocol: "TLS",
 							TLS:      &gatewayv1.GatewayTLSConfig{Mode: common.PtrTo(gatewayv1.TLSModeType("Passthrough"))},
 						},
 						{
@@ -289,6 +292,98 @@ func Test_resourcesToIRConverter_convertGateway(t *testing.T) {
 				}: {},
 			},
 		},
+		{
+			name: "servers of the same hosts on non-standard ports",
+			args: args{
+				gw: &istioclientv1beta1.Gateway{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Gateway",
+						APIVersion: "networking.istio.io/v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "name",
+						Namespace: "test",
+					},
+					Spec: istiov1beta1.Gateway{
+						Servers: []*istiov1beta1.Server{
+							{
+								Port: &istiov1beta1.Port{
+									Number:   8080,
+									Protocol: "HTTP",
+								},
+								Tls: &istiov1beta1.ServerTLSSettings{
+									HttpsRedirect: true,
+								},
+								Hosts: []string{"test/foo.example.com"},
+							},
+							{
+								Port: &istiov1beta1.Port{
+									Number:   443,
+									Protocol: "HTTPS",
+								},
+								Tls: &istiov1beta1.ServerTLSSettings{
+									Mode: istiov1beta1.ServerTLSSettings_SIMPLE,
+								},
+								Hosts: []string{"test/foo.example.com"},
+							},
+							{
+								Port: &istiov1beta1.Port{
+									Number:   8443,
+									Protocol: "HTTPS",
+								},
+								Tls: &istiov1beta1.ServerTLSSettings{
+									Mode: istiov1beta1.ServerTLSSettings_SIMPLE,
+								},
+								Hosts: []string{"test/foo.example.com"},
+							},
+						},
+					},
+				},
+			},
+			wantGateway: &gatewayv1.Gateway{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: common.GatewayGVK.GroupVersion().String(),
+					Kind:       common.GatewayGVK.Kind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "test",
+				},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: K8SGatewayClassName,
+					Listeners: []gatewayv1.Listener{
+						{
+							Name:     "http-protocol-test-ns-foo.example.com",
+							Hostname: common.PtrTo(gatewayv1.Hostname("foo.example.com")),
+							Port:     8080,
+							Protocol: "HTTP",
+						},
+						{
+							Name:     "https-protocol-test-ns-foo.example.com",
+							Hostname: common.PtrTo(gatewayv1.Hostname("foo.example.com")),
+							Port:     443,
+							Protocol: "HTTPS",
+							TLS:      &gatewayv1.GatewayTLSConfig{Mode: common.PtrTo(gatewayv1.TLSModeType("Terminate"))},
+						},
+						{
+							Name:     "https-protocol-test-ns-foo.example.com-8443",
+							Hostname: common.PtrTo(gatewayv1.Hostname("foo.example.com")),
+							Port:     8443,
+							Protocol: "HTTPS",
+							TLS:      &gatewayv1.GatewayTLSConfig{Mode: common.PtrTo(gatewayv1.TLSModeType("Terminate"))},
+						},
+					},
+				},
+			},
+			wantAllowedHosts: map[types.NamespacedName]map[string]sets.Set[string]{
+				{
+					Namespace: "test",
+					Name:      "name",
+				}: {
+					"test": sets.New[string]("foo.example.com"),
+				},
+			},
+		},
 		{
 			name: "unknown istio server protocol returns an error",
 			args: args{
@@ -326,7 +421,7 @@ func Test_resourcesToIRConverter_convertGateway(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := newResourcesToIRConverter()
+			c := newResourcesToIRConverter(&i2gw.ProviderConf{})
 			got, errList := c.convertGateway(tt.args.gw, field.NewPath(""))
 			if tt.wantError && len(errList) == 0 {
 				t.Errorf("resourcesToIRConverter.convertGateway().errList = %+v, wantError %+v", errList, tt.wantError)
@@ -1216,7 +1311,77 @@ func Test_resourcesToIRConverter_convertVsHTTPRoutes(t *testing.T) {
 						Rules: []gatewayv1.HTTPRouteRule{
 							{
 								Timeouts: &gatewayv1.HTTPRouteTimeouts{
-									Request: common.PtrTo[gatewayv1.Duration]("1m0s"),
+									Request: common.PtrTo[gatewayv1.Duration]("1m"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "route.Retries.PerTryTimeout is converted",
+			args: args{
+				virtualService: &istioclientv1beta1.VirtualService{
+					TypeMeta: metav1.TypeMeta{
+						Kind: "VirtualService",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "ns",
+					},
+				},
+				istioHTTPRoutes: []*istiov1beta1.HTTPRoute{
+					{
+						Timeout: durationpb.New(time.Minute),
+						Retries: &istiov1beta1.HTTPRetry{
+							Attempts:      3,
+							PerTryTimeout: durationpb.New(10 * time.Second),
+						},
+					},
+					{
+						Timeout: durationpb.New(time.Second),
+						Retries: &istiov1beta1.HTTPRetry{
+							PerTryTimeout: durationpb.New(10 * time.Second),
+						},
+					},
+				},
+			},
+			want: []*gatewayv1.HTTPRoute{
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "HTTPRoute",
+						APIVersion: "gateway.networking.k8s.io/v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-idx-0",
+						Namespace: "ns",
+					},
+					Spec: gatewayv1.HTTPRouteSpec{
+						Rules: []gatewayv1.HTTPRouteRule{
+							{
+								Timeouts: &gatewayv1.HTTPRouteTimeouts{
+									Request:        common.PtrTo[gatewayv1.Duration]("1m"),
+									BackendRequest: common.PtrTo[gatewayv1.Duration]("10s"),
+								},
+							},
+						},
+					},
+				},
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "HTTPRoute",
+						APIVersion: "gateway.networking.k8s.io/v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-idx-1",
+						Namespace: "ns",
+					},
+					Spec: gatewayv1.HTTPRouteSpec{
+						Rules: []gatewayv1.HTTPRouteRule{
+							{
+								Timeouts: &gatewayv1.HTTPRouteTimeouts{
+									Request: common.PtrTo[gatewayv1.Duration]("1s"),
 								},
 							},
 						},
@@ -1628,6 +1793,57 @@ func Test_resourcesToIRConverter_convertVsTCPRoutes(t *testing.T) {
 	}
 }
 
+func Test_l4ParentRefs(t *testing.T) {
+	vs := &istioclientv1beta1.VirtualService{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "vs"}}
+	gateways := map[types.NamespacedName]intermediate.GatewayContext{
+		{Namespace: "ns", Name: "gw"}: {Gateway: gatewayv1.Gateway{Spec: gatewayv1.GatewaySpec{Listeners: []gatewayv1.Listener{
+			{Name: "http-protocol-wildcard-ns-wildcard", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+			{Name: "tcp-protocol-wildcard-ns-wildcard", Port: 27017, Protocol: gatewayv1.TCPProtocolType},
+			{Name: "tcp-protocol-wildcard-ns-wildcard-3306", Port: 3306, Protocol: gatewayv1.TCPProtocolType},
+			{Name: "tls-protocol-wildcard-ns-a.example.com", Port: 443, Protocol: gatewayv1.TLSProtocolType},
+			{Name: "tls-protocol-wildcard-ns-b.example.com", Port: 443, Protocol: gatewayv1.TLSProtocolType},
+		}}}},
+	}
+	parentRefs := []gatewayv1.ParentReference{{Name: "gw"}}
+
+	tests := []struct {
+		name     string
+		ports    []gatewayv1.PortNumber
+		protocol gatewayv1.ProtocolType
+		want     []gatewayv1.ParentReference
+	}{
+		{
+			name:     "port of a single listener",
+			ports:    []gatewayv1.PortNumber{27017},
+			protocol: gatewayv1.TCPProtocolType,
+			want:     []gatewayv1.ParentReference{{Name: "gw", SectionName: common.PtrTo[gatewayv1.SectionName]("tcp-protocol-wildcard-ns-wildcard")}},
+		},
+		{
+			name:     "port of several listeners",
+			protocol: gatewayv1.TLSProtocolType,
+			want:     []gatewayv1.ParentReference{{Name: "gw", Port: common.PtrTo[gatewayv1.PortNumber](443)}},
+		},
+		{
+			name:     "no port with several listener ports",
+			protocol: gatewayv1.TCPProtocolType,
+			want:     parentRefs,
+		},
+		{
+			name:     "port without listener",
+			ports:    []gatewayv1.PortNumber{5432, 3306},
+			protocol: gatewayv1.TCPProtocolType,
+			want:     []gatewayv1.ParentReference{{Name: "gw", SectionName: common.PtrTo[gatewayv1.SectionName]("tcp-protocol-wildcard-ns-wildcard-3306")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l4ParentRefs(parentRefs, tt.ports, tt.protocol, gateways, vs, field.NewPath("")); !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("l4ParentRefs() diff (-want +got): %s", cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
+
 func TestNameMatches(t *testing.T) {
 	tests := []struct {
 		name string
@@ -2188,3 +2404,813 @@ func Test_convertHostnames(t *testing.T) {
 		})
 	}
 }
+
+func Test_resourcesToIRConverter_convertToIR_mesh(t *testing.T) {
+	vsHTTP := []*istiov1beta1.HTTPRoute{
+		{
+			Route: []*istiov1beta1.HTTPRouteDestination{
+				{
+					Destination: &istiov1beta1.Destination{
+						Host: "reviews",
+						Port: &istiov1beta1.PortSelector{Number: 9080},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mesh           bool
+		gateways       []string
+		hosts          []string
+		wantParentRefs map[types.NamespacedName][]gatewayv1.ParentReference
+	}{
+		{
+			name:     "mesh disabled",
+			mesh:     false,
+			gateways: []string{"mesh"},
+			hosts:    []string{"reviews"},
+			wantParentRefs: map[types.NamespacedName][]gatewayv1.ParentReference{
+				{Namespace: "test", Name: "vs-idx-0"}: nil,
+			},
+		},
+		{
+			name:  "bound to the mesh by default",
+			mesh:  true,
+			hosts: []string{"reviews", "ratings.prod.svc.cluster.local", "example.com"},
+			wantParentRefs: map[types.NamespacedName][]gatewayv1.ParentReference{
+				{Namespace: "test", Name: "vs-idx-0"}: {
+					{
+						Group: common.PtrTo[gatewayv1.Group](""),
+						Kind:  common.PtrTo[gatewayv1.Kind]("Service"),
+						Name:  "reviews",
+					},
+					{
+						Group:     common.PtrTo[gatewayv1.Group](""),
+						Kind:      common.PtrTo[gatewayv1.Kind]("Service"),
+						Name:      "ratings",
+						Namespace: common.PtrTo[gatewayv1.Namespace]("prod"),
+					},
+				},
+			},
+		},
+		{
+			name:     "bound to a gateway and the mesh",
+			mesh:     true,
+			gateways: []string{"gateway", "mesh"},
+			hosts:    []string{"reviews"},
+			wantParentRefs: map[types.NamespacedName][]gatewayv1.ParentReference{
+				{Namespace: "test", Name: "vs-idx-0"}: {
+					{
+						Group: common.PtrTo[gatewayv1.Group]("gateway.networking.k8s.io"),
+						Kind:  common.PtrTo[gatewayv1.Kind]("Gateway"),
+						Name:  "gateway",
+					},
+				},
+				{Namespace: "test", Name: "vs-idx-0-mesh"}: {
+					{
+						Group: common.PtrTo[gatewayv1.Group](""),
+						Kind:  common.PtrTo[gatewayv1.Kind]("Service"),
+						Name:  "reviews",
+					},
+				},
+			},
+		},
+		{
+			name:     "bound to a gateway only",
+			mesh:     true,
+			gateways: []string{"gateway"},
+			hosts:    []string{"reviews"},
+			wantParentRefs: map[types.NamespacedName][]gatewayv1.ParentReference{
+				{Namespace: "test", Name: "vs-idx-0"}: {
+					{
+						Group: common.PtrTo[gatewayv1.Group]("gateway.networking.k8s.io"),
+						Kind:  common.PtrTo[gatewayv1.Kind]("Gateway"),
+						Name:  "gateway",
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newResourcesToIRConverter(&i2gw.ProviderConf{Mesh: tt.mesh})
+			c.gwAllowedHosts = map[types.NamespacedName]map[string]sets.Set[string]{
+				{Namespace: "test", Name: "gateway"}: {"*": sets.New[string]("*")},
+			}
+
+			ir, errList := c.convertToIR(&storage{
+				VirtualServices: map[types.NamespacedName]*istioclientv1beta1.VirtualService{
+					{Namespace: "test", Name: "vs"}: {
+						ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "vs"},
+						Spec: istiov1beta1.VirtualService{
+							Gateways: tt.gateways,
+							Hosts:    tt.hosts,
+							Http:     vsHTTP,
+						},
+					},
+				},
+			})
+			if len(errList) > 0 {
+				t.Fatalf("unexpected errors: %v", errList)
+			}
+
+			gotParentRefs := map[types.NamespacedName][]gatewayv1.ParentReference{}
+			for key, httpRouteContext := range ir.HTTPRoutes {
+				gotParentRefs[key] = httpRouteContext.Spec.ParentRefs
+				if key.Name != "vs-idx-0" && len(httpRouteContext.Spec.Hostnames) > 0 {
+					t.Errorf("mesh HTTPRoute %v should not have hostnames, got %v", key, httpRouteContext.Spec.Hostnames)
+				}
+			}
+			if diff := cmp.Diff(tt.wantParentRefs, gotParentRefs); diff != "" {
+				t.Errorf("unexpected parentRefs (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_resourcesToIRConverter_convertToIR_parentHTTPRoutes(t *testing.T) {
+	gatewayRef := func(name string) gatewayv1.ParentReference {
+		return gatewayv1.ParentReference{
+			Group: common.PtrTo[gatewayv1.Group]("gateway.networking.k8s.io"),
+			Kind:  common.PtrTo[gatewayv1.Kind]("Gateway"),
+			Name:  gatewayv1.ObjectName(name),
+		}
+	}
+
+	tests := []struct {
+		name           string
+		gwAllowedHosts map[types.NamespacedName]map[string]sets.Set[string]
+		wantHTTPRoutes map[types.NamespacedName]gatewayv1.HTTPRouteSpec
+	}{
+		{
+			name: "gateways allowing the same hosts",
+			gwAllowedHosts: map[types.NamespacedName]map[string]sets.Set[string]{
+				{Namespace: "test", Name: "public"}:   {"*": sets.New[string]("*")},
+				{Namespace: "test", Name: "internal"}: {".": sets.New[string]("*.example.com", "bar.org")},
+			},
+			wantHTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRouteSpec{
+				{Namespace: "test", Name: "vs-idx-0"}: {
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{gatewayRef("public"), gatewayRef("internal")}},
+					Hostnames:       []gatewayv1.Hostname{"foo.example.com", "bar.org"},
+				},
+			},
+		},
+		{
+			name: "gateways allowing different hosts",
+			gwAllowedHosts: map[types.NamespacedName]map[string]sets.Set[string]{
+				{Namespace: "test", Name: "public"}:   {"*": sets.New[string]("*")},
+				{Namespace: "test", Name: "internal"}: {"test": sets.New[string]("*.example.com")},
+			},
+			wantHTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRouteSpec{
+				{Namespace: "test", Name: "vs-idx-0-public"}: {
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{gatewayRef("public")}},
+					Hostnames:       []gatewayv1.Hostname{"foo.example.com", "bar.org"},
+				},
+				{Namespace: "test", Name: "vs-idx-0-internal"}: {
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{gatewayRef("internal")}},
+					Hostnames:       []gatewayv1.Hostname{"foo.example.com"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newResourcesToIRConverter(&i2gw.ProviderConf{})
+			c.gwAllowedHosts = tt.gwAllowedHosts
+
+			ir, errList := c.convertToIR(&storage{
+				VirtualServices: map[types.NamespacedName]*istioclientv1beta1.VirtualService{
+					{Namespace: "test", Name: "vs"}: {
+						ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "vs"},
+						Spec: istiov1beta1.VirtualService{
+							Gateways: []string{"public", "internal"},
+							Hosts:    []string{"foo.example.com", "bar.org"},
+							Http: []*istiov1beta1.HTTPRoute{
+								{
+									Route: []*istiov1beta1.HTTPRouteDestination{
+										{Destination: &istiov1beta1.Destination{Host: "reviews", Port: &istiov1beta1.PortSelector{Number: 9080}}},
+									},
+								},
+							},
+						},
+					},
+				},
+			})
+			if len(errList) > 0 {
+				t.Fatalf("unexpected errors: %v", errList)
+			}
+
+			gotHTTPRoutes := map[types.NamespacedName]gatewayv1.HTTPRouteSpec{}
+			for key, httpRouteContext := range ir.HTTPRoutes {
+				gotHTTPRoutes[key] = gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: httpRouteContext.Spec.CommonRouteSpec,
+					Hostnames:       httpRouteContext.Spec.Hostnames,
+				}
+			}
+			if diff := cmp.Diff(tt.wantHTTPRoutes, gotHTTPRoutes); diff != "" {
+				t.Errorf("unexpected HTTPRoutes (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_narrowHostnames(t *testing.T) {
+	tests := []struct {
+		name      string
+		hostnames []gatewayv1.Hostname
+		hosts     []string
+		want      []gatewayv1.Hostname
+	}{
+		{
+			name:      "all hosts allowed",
+			hostnames: []gatewayv1.Hostname{"foo.example.com", "*.example.org"},
+			hosts:     []string{"*"},
+			want:      []gatewayv1.Hostname{"foo.example.com", "*.example.org"},
+		},
+		{
+			name:      "hostname not allowed",
+			hostnames: []gatewayv1.Hostname{"foo.example.com", "bar.org"},
+			hosts:     []string{"*.example.com"},
+			want:      []gatewayv1.Hostname{"foo.example.com"},
+		},
+		{
+			name:      "wildcard narrowed to the allowed hosts",
+			hostnames: []gatewayv1.Hostname{"*.example.com"},
+			hosts:     []string{"foo.example.com", "*.bar.example.com", "example.org"},
+			want:      []gatewayv1.Hostname{"foo.example.com", "*.bar.example.com"},
+		},
+		{
+			name:      "no allowed host",
+			hostnames: []gatewayv1.Hostname{"foo.example.com"},
+			hosts:     []string{"example.org"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, narrowHostnames(tt.hostnames, tt.hosts)); diff != "" {
+				t.Errorf("unexpected hostnames (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_resourcesToIRConverter_convertToIR_corsPolicy(t *testing.T) {
+	c := newResourcesToIRConverter(&i2gw.ProviderConf{})
+	c.gwAllowedHosts = map[types.NamespacedName]map[string]sets.Set[string]{
+		{Namespace: "test", Name: "gateway"}: {"*": sets.New[string]("*")},
+	}
+
+	ir, errList := c.convertToIR(&storage{
+		VirtualServices: map[types.NamespacedName]*istioclientv1beta1.VirtualService{
+			{Namespace: "test", Name: "vs"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "vs"},
+				Spec: istiov1beta1.VirtualService{
+					Gateways: []string{"gateway"},
+					Hosts:    []string{"*"},
+					Http: []*istiov1beta1.HTTPRoute{{
+						Name: "cors",
+						Route: []*istiov1beta1.HTTPRouteDestination{{
+							Destination: &istiov1beta1.Destination{Host: "reviews"},
+						}},
+						CorsPolicy: &istiov1beta1.CorsPolicy{
+							AllowOrigins: []*istiov1beta1.StringMatch{{MatchType: &istiov1beta1.StringMatch_Exact{Exact: "https://example.com"}}},
+							AllowMethods: []string{"GET"},
+						},
+					}},
+				},
+			},
+		},
+	})
+	if len(errList) > 0 {
+		t.Fatalf("unexpected errors: %v", errList)
+	}
+
+	want := []intermediate.UnsupportedFeature{{
+		SourceKind: VirtualServiceKind,
+		Source:     types.NamespacedName{Namespace: "test", Name: "vs"},
+		Name:       "spec.http[cors].corsPolicy",
+		RawConfig:  "allowMethods:\n- GET\nallowOrigins:\n- exact: https://example.com\n",
+	}}
+	got := ir.HTTPRoutes[types.NamespacedName{Namespace: "test", Name: "vs-cors"}].UnsupportedFeatures
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected unsupported features (-want +got): %s", diff)
+	}
+}
+
+func Test_resourcesToIRConverter_convertToIR_fault(t *testing.T) {
+	c := newResourcesToIRConverter(&i2gw.ProviderConf{})
+	c.gwAllowedHosts = map[types.NamespacedName]map[string]sets.Set[string]{
+		{Namespace: "test", Name: "gateway"}: {"*": sets.New[string]("*")},
+	}
+
+	ir, errList := c.convertToIR(&storage{
+		VirtualServices: map[types.NamespacedName]*istioclientv1beta1.VirtualService{
+			{Namespace: "test", Name: "vs"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "vs"},
+				Spec: istiov1beta1.VirtualService{
+					Gateways: []string{"gateway"},
+					Hosts:    []string{"*"},
+					Http: []*istiov1beta1.HTTPRoute{
+						{
+							Name: "fault",
+							Route: []*istiov1beta1.HTTPRouteDestination{{
+								Destination: &istiov1beta1.Destination{Host: "reviews"},
+							}},
+							Fault: &istiov1beta1.HTTPFaultInjection{
+								Delay: &istiov1beta1.HTTPFaultInjection_Delay{
+									HttpDelayType: &istiov1beta1.HTTPFaultInjection_Delay_FixedDelay{FixedDelay: durationpb.New(5 * time.Second)},
+									Percent:       10,
+								},
+								Abort: &istiov1beta1.HTTPFaultInjection_Abort{
+									ErrorType:  &istiov1beta1.HTTPFaultInjection_Abort_HttpStatus{HttpStatus: 503},
+									Percentage: &istiov1beta1.Percent{Value: 0.5},
+								},
+							},
+						},
+						{
+							Name: "exponential-delay",
+							Route: []*istiov1beta1.HTTPRouteDestination{{
+								Destination: &istiov1beta1.Destination{Host: "ratings"},
+							}},
+							Fault: &istiov1beta1.HTTPFaultInjection{
+								Delay: &istiov1beta1.HTTPFaultInjection_Delay{
+									HttpDelayType: &istiov1beta1.HTTPFaultInjection_Delay_ExponentialDelay{ExponentialDelay: durationpb.New(time.Second)},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	if len(errList) > 0 {
+		t.Fatalf("unexpected errors: %v", errList)
+	}
+
+	want := &intermediate.IstioHTTPRouteIR{FaultInjection: &intermediate.FaultInjectionConfig{
+		Delay: &intermediate.FaultDelay{FixedDelay: 5 * time.Second, Percentage: 10},
+		Abort: &intermediate.FaultAbort{HTTPStatus: 503, Percentage: 0.5},
+	}}
+	got := ir.HTTPRoutes[types.NamespacedName{Namespace: "test", Name: "vs-fault"}].ProviderSpecificIR.Istio
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected istio IR (-want +got): %s", diff)
+	}
+	if got := ir.HTTPRoutes[types.NamespacedName{Namespace: "test", Name: "vs-exponential-delay"}].ProviderSpecificIR.Istio; got != nil {
+		t.Errorf("expected no istio IR for the exponential delay, got %v", got)
+	}
+}
+
+func Test_resourcesToIRConverter_convertToIR_mirrorFractions(t *testing.T) {
+	c := newResourcesToIRConverter(&i2gw.ProviderConf{})
+	c.gwAllowedHosts = map[types.NamespacedName]map[string]sets.Set[string]{
+		{Namespace: "test", Name: "gateway"}: {"*": sets.New[string]("*")},
+	}
+
+	ir, errList := c.convertToIR(&storage{
+		VirtualServices: map[types.NamespacedName]*istioclientv1beta1.VirtualService{
+			{Namespace: "test", Name: "vs"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "vs"},
+				Spec: istiov1beta1.VirtualService{
+					Gateways: []string{"gateway"},
+					Hosts:    []string{"*"},
+					Http: []*istiov1beta1.HTTPRoute{
+						{
+							Name: "mirror",
+							Route: []*istiov1beta1.HTTPRouteDestination{{
+								Destination: &istiov1beta1.Destination{Host: "reviews"},
+							}},
+							Headers: &istiov1beta1.Headers{
+								Request: &istiov1beta1.Headers_HeaderOperations{Set: map[string]string{"h1": "v1"}},
+							},
+							Mirror:           &istiov1beta1.Destination{Host: "reviews-shadow"},
+							MirrorPercentage: &istiov1beta1.Percent{Value: 12.5},
+						},
+						{
+							Name: "mirrors",
+							Route: []*istiov1beta1.HTTPRouteDestination{{
+								Destination: &istiov1beta1.Destination{Host: "ratings"},
+							}},
+							Mirrors: []*istiov1beta1.HTTPMirrorPolicy{
+								{Destination: &istiov1beta1.Destination{Host: "ratings-shadow"}, Percentage: &istiov1beta1.Percent{Value: 100}},
+								{Destination: &istiov1beta1.Destination{Host: "ratings-audit"}, Percentage: &istiov1beta1.Percent{Value: 0.5}},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	if len(errList) > 0 {
+		t.Fatalf("unexpected errors: %v", errList)
+	}
+
+	want := map[types.NamespacedName]map[int]map[int]intermediate.MirrorFraction{
+		{Namespace: "test", Name: "vs-mirror"}:  {0: {0: {Numerator: 125, Denominator: 1000}}},
+		{Namespace: "test", Name: "vs-mirrors"}: {0: {1: {Numerator: 5, Denominator: 1000}}},
+	}
+	got := map[types.NamespacedName]map[int]map[int]intermediate.MirrorFraction{}
+	for key, httpRouteContext := range ir.HTTPRoutes {
+		got[key] = httpRouteContext.MirrorFractions
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected mirror fractions (-want +got): %s", diff)
+	}
+}
+
+func Test_resourcesToIRConverter_convertToIR_delegateMirrorFractions(t *testing.T) {
+	c := newResourcesToIRConverter(&i2gw.ProviderConf{})
+	c.gwAllowedHosts = map[types.NamespacedName]map[string]sets.Set[string]{
+		{Namespace: "test", Name: "gateway"}: {"*": sets.New[string]("*")},
+	}
+
+	delegate := func(name, host string) *istioclientv1beta1.VirtualService {
+		return &istioclientv1beta1.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name},
+			Spec: istiov1beta1.VirtualService{
+				Http: []*istiov1beta1.HTTPRoute{{
+					Name: "v1",
+					Route: []*istiov1beta1.HTTPRouteDestination{{
+						Destination: &istiov1beta1.Destination{Host: host},
+					}},
+				}},
+			},
+		}
+	}
+	ir, errList := c.convertToIR(&storage{
+		VirtualServices: map[types.NamespacedName]*istioclientv1beta1.VirtualService{
+			{Namespace: "test", Name: "vs"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "vs"},
+				Spec: istiov1beta1.VirtualService{
+					Gateways: []string{"gateway"},
+					Hosts:    []string{"*"},
+					Http: []*istiov1beta1.HTTPRoute{
+						{
+							Name: "mirror",
+							Match: []*istiov1beta1.HTTPMatchRequest{{
+								Uri: &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: "/reviews"}},
+							}},
+							Delegate:         &istiov1beta1.Delegate{Name: "reviews", Namespace: "test"},
+							Mirror:           &istiov1beta1.Destination{Host: "reviews-shadow"},
+							MirrorPercentage: &istiov1beta1.Percent{Value: 12.5},
+						},
+						{
+							Name: "mirrors",
+							Match: []*istiov1beta1.HTTPMatchRequest{{
+								Uri: &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: "/ratings"}},
+							}},
+							Delegate: &istiov1beta1.Delegate{Name: "ratings", Namespace: "test"},
+							Mirrors: []*istiov1beta1.HTTPMirrorPolicy{
+								{Destination: &istiov1beta1.Destination{Host: "ratings-shadow"}, Percentage: &istiov1beta1.Percent{Value: 100}},
+								{Destination: &istiov1beta1.Destination{Host: "ratings-audit"}, Percentage: &istiov1beta1.Percent{Value: 0.5}},
+							},
+						},
+					},
+				},
+			},
+			{Namespace: "test", Name: "reviews"}: delegate("reviews", "reviews"),
+			{Namespace: "test", Name: "ratings"}: delegate("ratings", "ratings"),
+		},
+	})
+	if len(errList) > 0 {
+		t.Fatalf("unexpected errors: %v", errList)
+	}
+
+	want := map[types.NamespacedName]map[int]map[int]intermediate.MirrorFraction{
+		{Namespace: "test", Name: "vs-mirror-v1"}:  {0: {0: {Numerator: 125, Denominator: 1000}}},
+		{Namespace: "test", Name: "vs-mirrors-v1"}: {0: {1: {Numerator: 5, Denominator: 1000}}},
+	}
+	got := map[types.NamespacedName]map[int]map[int]intermediate.MirrorFraction{}
+	for key, httpRouteContext := range ir.HTTPRoutes {
+		got[key] = httpRouteContext.MirrorFractions
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected mirror fractions (-want +got): %s", diff)
+	}
+}
+
+func Test_resourcesToIRConverter_convertToIR_rulePriorities(t *testing.T) {
+	c := newResourcesToIRConverter(&i2gw.ProviderConf{})
+	c.gwAllowedHosts = map[types.NamespacedName]map[string]sets.Set[string]{
+		{Namespace: "test", Name: "gateway"}: {"*": sets.New[string]("*")},
+	}
+
+	route := func(name, host string) *istiov1beta1.HTTPRoute {
+		return &istiov1beta1.HTTPRoute{
+			Name: name,
+			Match: []*istiov1beta1.HTTPMatchRequest{{
+				Uri: &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: "/"}},
+			}},
+			Route: []*istiov1beta1.HTTPRouteDestination{{
+				Destination: &istiov1beta1.Destination{Host: host},
+			}},
+		}
+	}
+	ir, errList := c.convertToIR(&storage{
+		VirtualServices: map[types.NamespacedName]*istioclientv1beta1.VirtualService{
+			{Namespace: "test", Name: "vs"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "vs"},
+				Spec: istiov1beta1.VirtualService{
+					Gateways: []string{"gateway"},
+					Hosts:    []string{"*"},
+					Http:     []*istiov1beta1.HTTPRoute{route("reviews", "reviews"), route("default", "ratings")},
+				},
+			},
+			{Namespace: "test", Name: "single"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "single"},
+				Spec: istiov1beta1.VirtualService{
+					Gateways: []string{"gateway"},
+					Hosts:    []string{"*"},
+					Http:     []*istiov1beta1.HTTPRoute{route("details", "details")},
+				},
+			},
+		},
+	})
+	if len(errList) > 0 {
+		t.Fatalf("unexpected errors: %v", errList)
+	}
+
+	want := map[string]map[int]int32{
+		"vs-reviews":     {0: 2},
+		"vs-default":     {0: 1},
+		"single-details": nil,
+	}
+	for name, priorities := range want {
+		got := ir.HTTPRoutes[types.NamespacedName{Namespace: "test", Name: name}].RulePriorities
+		if diff := cmp.Diff(priorities, got); diff != "" {
+			t.Errorf("unexpected rule priorities of HTTPRoute %s (-want +got): %s", name, diff)
+		}
+	}
+}
+
+func Test_resourcesToIRConverter_convertToIR_credentialNamespace(t *testing.T) {
+	c := newResourcesToIRConverter(&i2gw.ProviderConf{
+		ProviderSpecificFlags: map[string]map[string]string{
+			ProviderName: {CredentialNamespaceFlag: "istio-system"},
+		},
+	})
+
+	ir, errList := c.convertToIR(&storage{
+		Gateways: map[types.NamespacedName]*istioclientv1beta1.Gateway{
+			{Namespace: "bookinfo", Name: "gateway"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "bookinfo", Name: "gateway"},
+				Spec: istiov1beta1.Gateway{
+					Servers: []*istiov1beta1.Server{{
+						Port:  &istiov1beta1.Port{Number: 443, Protocol: "HTTPS"},
+						Hosts: []string{"bookinfo.com"},
+						Tls:   &istiov1beta1.ServerTLSSettings{Mode: istiov1beta1.ServerTLSSettings_SIMPLE, CredentialName: "bookinfo-secret"},
+					}},
+				},
+			},
+		},
+	})
+	if len(errList) > 0 {
+		t.Fatalf("unexpected errors: %v", errList)
+	}
+
+	wantCertificateRefs := []gatewayv1.SecretObjectReference{{Name: "bookinfo-secret", Namespace: common.PtrTo[gatewayv1.Namespace]("istio-system")}}
+	gotCertificateRefs := ir.Gateways[types.NamespacedName{Namespace: "bookinfo", Name: "gateway"}].Spec.Listeners[0].TLS.CertificateRefs
+	if diff := cmp.Diff(wantCertificateRefs, gotCertificateRefs); diff != "" {
+		t.Errorf("unexpected certificateRefs (-want +got): %s", diff)
+	}
+
+	wantReferenceGrants := map[types.NamespacedName]gatewayv1beta1.ReferenceGrant{
+		{Namespace: "istio-system", Name: "generated-reference-grant-from-bookinfo-to-istio-system"}: {
+			TypeMeta:   metav1.TypeMeta{APIVersion: common.ReferenceGrantGVK.GroupVersion().String(), Kind: common.ReferenceGrantGVK.Kind},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "istio-system", Name: "generated-reference-grant-from-bookinfo-to-istio-system"},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "bookinfo"}},
+				To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Secret", Name: common.PtrTo[gatewayv1.ObjectName]("bookinfo-secret")}},
+			},
+		},
+	}
+	if diff := cmp.Diff(wantReferenceGrants, ir.ReferenceGrants); diff != "" {
+		t.Errorf("unexpected ReferenceGrants (-want +got): %s", diff)
+	}
+}
+
+func Test_resourcesToIRConverter_convertToIR_grpcRoutes(t *testing.T) {
+	gateways := map[types.NamespacedName]*istioclientv1beta1.Gateway{
+		{Namespace: "test", Name: "grpc"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "grpc"},
+			Spec: istiov1beta1.Gateway{
+				Servers: []*istiov1beta1.Server{{
+					Port:  &istiov1beta1.Port{Number: 80, Protocol: "GRPC"},
+					Hosts: []string{"*"},
+				}},
+			},
+		},
+		{Namespace: "test", Name: "http"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "http"},
+			Spec: istiov1beta1.Gateway{
+				Servers: []*istiov1beta1.Server{{
+					Port:  &istiov1beta1.Port{Number: 80, Protocol: "HTTP"},
+					Hosts: []string{"*"},
+				}},
+			},
+		},
+	}
+	virtualService := func(gateway string) *istioclientv1beta1.VirtualService {
+		return &istioclientv1beta1.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "vs"},
+			Spec: istiov1beta1.VirtualService{
+				Gateways: []string{gateway},
+				Hosts:    []string{"greeter.example.com"},
+				Http: []*istiov1beta1.HTTPRoute{
+					{
+						Name: "say-hello",
+						Match: []*istiov1beta1.HTTPMatchRequest{{
+							Uri: &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Exact{Exact: "/helloworld.Greeter/SayHello"}},
+						}},
+						Route: []*istiov1beta1.HTTPRouteDestination{{
+							Destination: &istiov1beta1.Destination{Host: "greeter", Port: &istiov1beta1.PortSelector{Number: 50051}},
+						}},
+					},
+					{
+						Name: "greeter",
+						Match: []*istiov1beta1.HTTPMatchRequest{{
+							Uri: &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: "/helloworld.Greeter/"}},
+						}},
+						Route: []*istiov1beta1.HTTPRouteDestination{{
+							Destination: &istiov1beta1.Destination{Host: "greeter-v2", Port: &istiov1beta1.PortSelector{Number: 50051}},
+						}},
+					},
+					{
+						Name: "health",
+						Match: []*istiov1beta1.HTTPMatchRequest{{
+							Uri: &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: "/healthz"}},
+						}},
+						Route: []*istiov1beta1.HTTPRouteDestination{{
+							Destination: &istiov1beta1.Destination{Host: "greeter", Port: &istiov1beta1.PortSelector{Number: 8080}},
+						}},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		grpcRoutes     string
+		gateway        string
+		wantGRPCRoutes []types.NamespacedName
+		wantHTTPRoutes []types.NamespacedName
+		wantErr        bool
+	}{
+		{
+			name:           "disabled",
+			gateway:        "grpc",
+			wantHTTPRoutes: []types.NamespacedName{{Namespace: "test", Name: "vs-greeter"}, {Namespace: "test", Name: "vs-health"}, {Namespace: "test", Name: "vs-say-hello"}},
+		},
+		{
+			name:           "gRPC server",
+			grpcRoutes:     "true",
+			gateway:        "grpc",
+			wantGRPCRoutes: []types.NamespacedName{{Namespace: "test", Name: "vs-greeter"}, {Namespace: "test", Name: "vs-say-hello"}},
+			wantHTTPRoutes: []types.NamespacedName{{Namespace: "test", Name: "vs-health"}},
+		},
+		{
+			name:           "HTTP server",
+			grpcRoutes:     "true",
+			gateway:        "http",
+			wantHTTPRoutes: []types.NamespacedName{{Namespace: "test", Name: "vs-greeter"}, {Namespace: "test", Name: "vs-health"}, {Namespace: "test", Name: "vs-say-hello"}},
+		},
+		{
+			name:       "invalid flag",
+			grpcRoutes: "yes",
+			gateway:    "grpc",
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newResourcesToIRConverter(&i2gw.ProviderConf{
+				ProviderSpecificFlags: map[string]map[string]string{
+					ProviderName: {GRPCRoutesFlag: tc.grpcRoutes},
+				},
+			})
+
+			ir, errList := c.convertToIR(&storage{
+				Gateways:        gateways,
+				VirtualServices: map[types.NamespacedName]*istioclientv1beta1.VirtualService{{Namespace: "test", Name: "vs"}: virtualService(tc.gateway)},
+			})
+			if tc.wantErr {
+				if len(errList) == 0 {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if len(errList) > 0 {
+				t.Fatalf("unexpected errors: %v", errList)
+			}
+
+			var gotHTTPRoutes, gotGRPCRoutes []types.NamespacedName
+			for key := range ir.HTTPRoutes {
+				gotHTTPRoutes = append(gotHTTPRoutes, key)
+			}
+			for key := range ir.GRPCRoutes {
+				gotGRPCRoutes = append(gotGRPCRoutes, key)
+			}
+			sortKeys := cmpopts.SortSlices(func(a, b types.NamespacedName) bool { return a.String() < b.String() })
+			if diff := cmp.Diff(tc.wantHTTPRoutes, gotHTTPRoutes, sortKeys); diff != "" {
+				t.Errorf("unexpected HTTPRoutes (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.wantGRPCRoutes, gotGRPCRoutes, sortKeys); diff != "" {
+				t.Errorf("unexpected GRPCRoutes (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_httpRouteToGRPCRoute(t *testing.T) {
+	backendRef := gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "greeter", Port: common.PtrTo[gatewayv1.PortNumber](50051)}}
+	httpRoute := func(path gatewayv1.HTTPPathMatch, filters ...gatewayv1.HTTPRouteFilter) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "vs-greeter"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Hostnames: []gatewayv1.Hostname{"greeter.example.com"},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Matches: []gatewayv1.HTTPRouteMatch{{
+						Path:    &path,
+						Headers: []gatewayv1.HTTPHeaderMatch{{Name: "x-version", Value: "v2"}},
+					}},
+					Filters:     filters,
+					BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: backendRef}},
+				}},
+			},
+		}
+	}
+	grpcRoute := func(method *gatewayv1.GRPCMethodMatch) *gatewayv1.GRPCRoute {
+		return &gatewayv1.GRPCRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: common.GRPCRouteGVK.GroupVersion().String(), Kind: common.GRPCRouteGVK.Kind},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "vs-greeter"},
+			Spec: gatewayv1.GRPCRouteSpec{
+				Hostnames: []gatewayv1.Hostname{"greeter.example.com"},
+				Rules: []gatewayv1.GRPCRouteRule{{
+					Matches: []gatewayv1.GRPCRouteMatch{{
+						Method:  method,
+						Headers: []gatewayv1.GRPCHeaderMatch{{Name: "x-version", Value: "v2"}},
+					}},
+					BackendRefs: []gatewayv1.GRPCBackendRef{{BackendRef: backendRef}},
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		httpRoute *gatewayv1.HTTPRoute
+		want      *gatewayv1.GRPCRoute
+	}{
+		{
+			name:      "method",
+			httpRoute: httpRoute(gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchExact), Value: common.PtrTo("/helloworld.Greeter/SayHello")}),
+			want: grpcRoute(&gatewayv1.GRPCMethodMatch{
+				Type:    common.PtrTo(gatewayv1.GRPCMethodMatchExact),
+				Service: common.PtrTo("helloworld.Greeter"),
+				Method:  common.PtrTo("SayHello"),
+			}),
+		},
+		{
+			name:      "service",
+			httpRoute: httpRoute(gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchPathPrefix), Value: common.PtrTo("/helloworld.Greeter")}),
+			want: grpcRoute(&gatewayv1.GRPCMethodMatch{
+				Type:    common.PtrTo(gatewayv1.GRPCMethodMatchExact),
+				Service: common.PtrTo("helloworld.Greeter"),
+			}),
+		},
+		{
+			name:      "service without package",
+			httpRoute: httpRoute(gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchPathPrefix), Value: common.PtrTo("/api")}),
+		},
+		{
+			name:      "prefix of a method",
+			httpRoute: httpRoute(gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchPathPrefix), Value: common.PtrTo("/helloworld.Greeter/Say")}),
+		},
+		{
+			name:      "regular expression",
+			httpRoute: httpRoute(gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchRegularExpression), Value: common.PtrTo("/helloworld.Greeter/.*")}),
+		},
+		{
+			name: "URL rewrite",
+			httpRoute: httpRoute(gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchExact), Value: common.PtrTo("/helloworld.Greeter/SayHello")}, gatewayv1.HTTPRouteFilter{
+				Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Hostname: common.PtrTo[gatewayv1.PreciseHostname]("greeter.internal")},
+			}),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := httpRouteToGRPCRoute(tc.httpRoute)
+			if ok != (tc.want != nil) {
+				t.Fatalf("expected converted to be %v, got %v", tc.want != nil, ok)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected GRPCRoute (-want +got): %s", diff)
+			}
+		})
+	}
+}
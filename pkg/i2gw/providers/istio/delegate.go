@@ -0,0 +1,292 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"google.golang.org/protobuf/proto"
+	istiov1beta1 "istio.io/api/networking/v1beta1"
+	istioclientv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// delegateKey returns the VirtualService the HTTP route delegates to, the
+// namespace defaulting to the one of the delegating VirtualService.
+func delegateKey(delegate *istiov1beta1.Delegate, namespace string) types.NamespacedName {
+	if delegate.GetNamespace() != "" {
+		namespace = delegate.GetNamespace()
+	}
+	return types.NamespacedName{Namespace: namespace, Name: delegate.GetName()}
+}
+
+// delegateVirtualServices returns the VirtualServices the HTTP routes of other
+// VirtualServices delegate to. As istio, only the VirtualServices without
+// hosts are delegates: they are not converted on their own.
+func delegateVirtualServices(virtualServices map[types.NamespacedName]*istioclientv1beta1.VirtualService) sets.Set[types.NamespacedName] {
+	delegates := sets.New[types.NamespacedName]()
+	for _, vs := range virtualServices {
+		for _, httpRoute := range vs.Spec.GetHttp() {
+			if httpRoute.GetDelegate() == nil {
+				continue
+			}
+			key := delegateKey(httpRoute.GetDelegate(), vs.Namespace)
+			if delegate, ok := virtualServices[key]; ok && len(delegate.Spec.GetHosts()) == 0 {
+				delegates.Insert(key)
+			}
+		}
+	}
+	return delegates
+}
+
+// expandDelegates returns the HTTP routes of the VirtualService, the routes
+// delegating to another VirtualService being replaced by the routes of the
+// delegate, merged with them as istio does: the matches are intersected,
+// and the delegate routes inherit the settings they don't set. The unnamed
+// routes are named after their index, so that the names of the generated
+// HTTPRoutes don't depend on the number of delegated routes.
+// The Services of other namespaces the delegated routes reference are
+// returned as well, as the routes need ReferenceGrants to them.
+func (c *resourcesToIRConverter) expandDelegates(vs *istioclientv1beta1.VirtualService, virtualServices map[types.NamespacedName]*istioclientv1beta1.VirtualService, fieldPath *field.Path) ([]*istiov1beta1.HTTPRoute, []types.NamespacedName) {
+	httpRoutes := vs.Spec.GetHttp()
+	if !hasDelegates(httpRoutes) {
+		return httpRoutes, nil
+	}
+
+	var (
+		expanded []*istiov1beta1.HTTPRoute
+		services []types.NamespacedName
+	)
+	for i, httpRoute := range httpRoutes {
+		routeName := httpRoute.GetName()
+		if routeName == "" {
+			routeName = fmt.Sprintf("idx-%d", i)
+		}
+		routeFieldPath := fieldPath.Child("Http").Key(routeName)
+
+		if httpRoute.GetDelegate() == nil {
+			named := proto.Clone(httpRoute).(*istiov1beta1.HTTPRoute)
+			named.Name = routeName
+			expanded = append(expanded, named)
+			continue
+		}
+
+		key := delegateKey(httpRoute.GetDelegate(), vs.Namespace)
+		delegate, ok := virtualServices[key]
+		if !ok {
+			notify(notifications.WarningNotification, fmt.Sprintf("delegate VirtualService %v not found, the route was not converted, path: %v", key, routeFieldPath.Child("Delegate")), vs)
+			continue
+		}
+		if len(delegate.Spec.GetHosts()) > 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("VirtualService %v has hosts and can't be a delegate, the route was not converted, path: %v", key, routeFieldPath.Child("Delegate")), vs)
+			continue
+		}
+
+		for j, delegateRoute := range delegate.Spec.GetHttp() {
+			delegateRouteName := delegateRoute.GetName()
+			if delegateRouteName == "" {
+				delegateRouteName = fmt.Sprintf("idx-%d", j)
+			}
+			delegateFieldPath := field.NewPath(ProviderName).Child("VirtualService").Key(key.String()).Child("Http").Key(delegateRouteName)
+
+			if delegateRoute.GetDelegate() != nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("delegate VirtualServices can't delegate further, the route was not converted, path: %v", delegateFieldPath.Child("Delegate")), vs, delegate)
+				continue
+			}
+			var delegateServices []types.NamespacedName
+			if delegate.Namespace != vs.Namespace {
+				delegateRoute = proto.Clone(delegateRoute).(*istiov1beta1.HTTPRoute)
+				delegateServices = qualifyDestinations(delegateRoute, delegate.Namespace)
+			}
+			merged, ok := mergeDelegateRoute(httpRoute, delegateRoute)
+			if !ok {
+				notify(notifications.WarningNotification, fmt.Sprintf("the matches of the route don't intersect with the ones of the delegating route %v, the route was not converted, path: %v", routeFieldPath, delegateFieldPath), vs, delegate)
+				continue
+			}
+			merged.Name = fmt.Sprintf("%s-%s", routeName, delegateRouteName)
+			services = append(services, delegateServices...)
+			expanded = append(expanded, merged)
+		}
+		notify(notifications.InfoNotification, fmt.Sprintf("merged the routes of delegate VirtualService %v, path: %v", key, routeFieldPath.Child("Delegate")), vs, delegate)
+	}
+	return expanded, services
+}
+
+func hasDelegates(httpRoutes []*istiov1beta1.HTTPRoute) bool {
+	for _, httpRoute := range httpRoutes {
+		if httpRoute.GetDelegate() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeDelegateRoute returns the delegate route merged with the delegating
+// one, or false if none of their matches intersect.
+func mergeDelegateRoute(root, delegate *istiov1beta1.HTTPRoute) (*istiov1beta1.HTTPRoute, bool) {
+	merged := proto.Clone(delegate).(*istiov1beta1.HTTPRoute)
+
+	if len(root.GetMatch()) > 0 {
+		if len(delegate.GetMatch()) == 0 {
+			merged.Match = root.GetMatch()
+		} else {
+			merged.Match = nil
+			for _, rootMatch := range root.GetMatch() {
+				for _, delegateMatch := range delegate.GetMatch() {
+					if match, ok := mergeDelegateMatch(rootMatch, delegateMatch); ok {
+						merged.Match = append(merged.Match, match)
+					}
+				}
+			}
+			if len(merged.Match) == 0 {
+				return nil, false
+			}
+		}
+	}
+
+	if merged.Rewrite == nil {
+		merged.Rewrite = root.GetRewrite()
+	}
+	if merged.Timeout == nil {
+		merged.Timeout = root.GetTimeout()
+	}
+	if merged.Retries == nil {
+		merged.Retries = root.GetRetries()
+	}
+	if merged.Fault == nil {
+		merged.Fault = root.GetFault()
+	}
+	if merged.Mirror == nil && len(merged.Mirrors) == 0 {
+		merged.Mirror = root.GetMirror()
+		merged.MirrorPercentage = root.GetMirrorPercentage()
+		merged.MirrorPercent = root.GetMirrorPercent()
+		merged.Mirrors = root.GetMirrors()
+	}
+	if merged.CorsPolicy == nil {
+		merged.CorsPolicy = root.GetCorsPolicy()
+	}
+	if merged.Headers == nil {
+		merged.Headers = root.GetHeaders()
+	}
+	return merged, true
+}
+
+// mergeDelegateMatch returns the intersection of the matches, or false if the
+// delegate match isn't within the delegating one.
+func mergeDelegateMatch(root, delegate *istiov1beta1.HTTPMatchRequest) (*istiov1beta1.HTTPMatchRequest, bool) {
+	merged := proto.Clone(delegate).(*istiov1beta1.HTTPMatchRequest)
+
+	if root.GetUri() != nil {
+		if merged.Uri == nil {
+			merged.Uri = root.GetUri()
+		} else if !uriWithin(root.GetUri(), merged.Uri) {
+			return nil, false
+		}
+	}
+	if root.GetMethod() != nil {
+		if merged.Method == nil {
+			merged.Method = root.GetMethod()
+		} else if !proto.Equal(root.GetMethod(), merged.Method) {
+			return nil, false
+		}
+	}
+
+	var ok bool
+	if merged.Headers, ok = mergeStringMatches(root.GetHeaders(), merged.Headers); !ok {
+		return nil, false
+	}
+	if merged.QueryParams, ok = mergeStringMatches(root.GetQueryParams(), merged.QueryParams); !ok {
+		return nil, false
+	}
+	return merged, true
+}
+
+// uriWithin returns true if the URIs matched by the delegate are matched by
+// the delegating route.
+func uriWithin(root, delegate *istiov1beta1.StringMatch) bool {
+	switch {
+	case root.GetPrefix() != "":
+		return (delegate.GetPrefix() != "" && strings.HasPrefix(delegate.GetPrefix(), root.GetPrefix())) ||
+			(delegate.GetExact() != "" && strings.HasPrefix(delegate.GetExact(), root.GetPrefix()))
+	default:
+		return proto.Equal(root, delegate)
+	}
+}
+
+// mergeStringMatches adds the matches of the delegating route the delegate
+// doesn't have, or returns false if they match the same name differently.
+func mergeStringMatches(root, delegate map[string]*istiov1beta1.StringMatch) (map[string]*istiov1beta1.StringMatch, bool) {
+	if len(root) == 0 {
+		return delegate, true
+	}
+	merged := make(map[string]*istiov1beta1.StringMatch, len(root)+len(delegate))
+	for name, match := range delegate {
+		merged[name] = match
+	}
+	for name, match := range root {
+		if existing, ok := merged[name]; ok {
+			if !proto.Equal(existing, match) {
+				return nil, false
+			}
+			continue
+		}
+		merged[name] = match
+	}
+	return merged, true
+}
+
+// qualifyDestinations qualifies the short hosts of the destinations of the
+// delegate route with the namespace of the delegate, as istio resolves them
+// relatively to it, and returns the referenced Services.
+func qualifyDestinations(httpRoute *istiov1beta1.HTTPRoute, namespace string) []types.NamespacedName {
+	destinations := []*istiov1beta1.Destination{httpRoute.GetMirror()}
+	for _, route := range httpRoute.GetRoute() {
+		destinations = append(destinations, route.GetDestination())
+	}
+	for _, mirror := range httpRoute.GetMirrors() {
+		destinations = append(destinations, mirror.GetDestination())
+	}
+
+	var services []types.NamespacedName
+	for _, destination := range destinations {
+		if destination == nil || strings.Contains(destination.GetHost(), ".") {
+			continue
+		}
+		services = append(services, types.NamespacedName{Namespace: namespace, Name: destination.GetHost()})
+		destination.Host = fmt.Sprintf("%s.%s.svc.cluster.local", destination.GetHost(), namespace)
+	}
+	return services
+}
+
+// addServiceReferenceGrant grants the HTTPRoutes of the given namespace the
+// references to the Service of another namespace.
+func addServiceReferenceGrant(ir *intermediate.IR, fromNamespace string, service types.NamespacedName) {
+	addReferenceGrant(ir, gatewayv1beta1.ReferenceGrantFrom{
+		Group:     gatewayv1.Group(common.HTTPRouteGVK.Group),
+		Kind:      gatewayv1.Kind(common.HTTPRouteGVK.Kind),
+		Namespace: gatewayv1.Namespace(fromNamespace),
+	}, service.Namespace, gatewayv1beta1.ReferenceGrantTo{Kind: "Service", Name: ptr.To(gatewayv1.ObjectName(service.Name))})
+}
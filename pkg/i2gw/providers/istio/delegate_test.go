@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+	istiov1beta1 "istio.io/api/networking/v1beta1"
+)
+
+func Test_mergeDelegateRoute(t *testing.T) {
+	prefix := func(p string) *istiov1beta1.StringMatch {
+		return &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: p}}
+	}
+	exact := func(e string) *istiov1beta1.StringMatch {
+		return &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Exact{Exact: e}}
+	}
+	reviews := []*istiov1beta1.HTTPRouteDestination{{Destination: &istiov1beta1.Destination{Host: "reviews"}}}
+
+	tests := []struct {
+		name     string
+		root     *istiov1beta1.HTTPRoute
+		delegate *istiov1beta1.HTTPRoute
+		want     *istiov1beta1.HTTPRoute
+		wantOk   bool
+	}{
+		{
+			name: "delegate without matches inherits the delegating matches",
+			root: &istiov1beta1.HTTPRoute{
+				Match: []*istiov1beta1.HTTPMatchRequest{{Uri: prefix("/reviews")}},
+			},
+			delegate: &istiov1beta1.HTTPRoute{Route: reviews},
+			want: &istiov1beta1.HTTPRoute{
+				Match: []*istiov1beta1.HTTPMatchRequest{{Uri: prefix("/reviews")}},
+				Route: reviews,
+			},
+			wantOk: true,
+		},
+		{
+			name: "delegate matches within the delegating matches are merged",
+			root: &istiov1beta1.HTTPRoute{
+				Match: []*istiov1beta1.HTTPMatchRequest{{
+					Uri:     prefix("/reviews"),
+					Headers: map[string]*istiov1beta1.StringMatch{"end-user": exact("jason")},
+				}},
+				Timeout: durationpb.New(5e9),
+			},
+			delegate: &istiov1beta1.HTTPRoute{
+				Match: []*istiov1beta1.HTTPMatchRequest{{
+					Uri:         exact("/reviews/v2"),
+					QueryParams: map[string]*istiov1beta1.StringMatch{"v": exact("2")},
+				}},
+				Route: reviews,
+			},
+			want: &istiov1beta1.HTTPRoute{
+				Match: []*istiov1beta1.HTTPMatchRequest{{
+					Uri:         exact("/reviews/v2"),
+					Headers:     map[string]*istiov1beta1.StringMatch{"end-user": exact("jason")},
+					QueryParams: map[string]*istiov1beta1.StringMatch{"v": exact("2")},
+				}},
+				Route:   reviews,
+				Timeout: durationpb.New(5e9),
+			},
+			wantOk: true,
+		},
+		{
+			name: "delegate timeout takes precedence",
+			root: &istiov1beta1.HTTPRoute{Timeout: durationpb.New(5e9)},
+			delegate: &istiov1beta1.HTTPRoute{
+				Route:   reviews,
+				Timeout: durationpb.New(1e9),
+			},
+			want: &istiov1beta1.HTTPRoute{
+				Route:   reviews,
+				Timeout: durationpb.New(1e9),
+			},
+			wantOk: true,
+		},
+		{
+			name: "delegate without mirror inherits the delegating mirror percentage",
+			root: &istiov1beta1.HTTPRoute{
+				Mirror:           &istiov1beta1.Destination{Host: "reviews-shadow"},
+				MirrorPercentage: &istiov1beta1.Percent{Value: 12.5},
+			},
+			delegate: &istiov1beta1.HTTPRoute{Route: reviews},
+			want: &istiov1beta1.HTTPRoute{
+				Route:            reviews,
+				Mirror:           &istiov1beta1.Destination{Host: "reviews-shadow"},
+				MirrorPercentage: &istiov1beta1.Percent{Value: 12.5},
+			},
+			wantOk: true,
+		},
+		{
+			name: "delegate without mirror inherits the delegating mirrors percentages",
+			root: &istiov1beta1.HTTPRoute{
+				Mirrors: []*istiov1beta1.HTTPMirrorPolicy{
+					{Destination: &istiov1beta1.Destination{Host: "reviews-shadow"}, Percentage: &istiov1beta1.Percent{Value: 0.5}},
+				},
+			},
+			delegate: &istiov1beta1.HTTPRoute{Route: reviews},
+			want: &istiov1beta1.HTTPRoute{
+				Route: reviews,
+				Mirrors: []*istiov1beta1.HTTPMirrorPolicy{
+					{Destination: &istiov1beta1.Destination{Host: "reviews-shadow"}, Percentage: &istiov1beta1.Percent{Value: 0.5}},
+				},
+			},
+			wantOk: true,
+		},
+		{
+			name: "delegate mirror takes precedence with its own percentage",
+			root: &istiov1beta1.HTTPRoute{
+				Mirror:           &istiov1beta1.Destination{Host: "reviews-shadow"},
+				MirrorPercentage: &istiov1beta1.Percent{Value: 12.5},
+			},
+			delegate: &istiov1beta1.HTTPRoute{
+				Route: reviews,
+				Mirrors: []*istiov1beta1.HTTPMirrorPolicy{
+					{Destination: &istiov1beta1.Destination{Host: "reviews-audit"}, Percentage: &istiov1beta1.Percent{Value: 50}},
+				},
+			},
+			want: &istiov1beta1.HTTPRoute{
+				Route: reviews,
+				Mirrors: []*istiov1beta1.HTTPMirrorPolicy{
+					{Destination: &istiov1beta1.Destination{Host: "reviews-audit"}, Percentage: &istiov1beta1.Percent{Value: 50}},
+				},
+			},
+			wantOk: true,
+		},
+		{
+			name: "delegate uri outside the delegating prefix",
+			root: &istiov1beta1.HTTPRoute{
+				Match: []*istiov1beta1.HTTPMatchRequest{{Uri: prefix("/reviews")}},
+			},
+			delegate: &istiov1beta1.HTTPRoute{
+				Match: []*istiov1beta1.HTTPMatchRequest{{Uri: prefix("/ratings")}},
+				Route: reviews,
+			},
+			wantOk: false,
+		},
+		{
+			name: "conflicting header matches",
+			root: &istiov1beta1.HTTPRoute{
+				Match: []*istiov1beta1.HTTPMatchRequest{{Headers: map[string]*istiov1beta1.StringMatch{"end-user": exact("jason")}}},
+			},
+			delegate: &istiov1beta1.HTTPRoute{
+				Match: []*istiov1beta1.HTTPMatchRequest{{Headers: map[string]*istiov1beta1.StringMatch{"end-user": exact("bill")}}},
+				Route: reviews,
+			},
+			wantOk: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := mergeDelegateRoute(tc.root, tc.delegate)
+			if ok != tc.wantOk {
+				t.Fatalf("mergeDelegateRoute() ok = %v, want %v", ok, tc.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("mergeDelegateRoute() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	istiov1beta1 "istio.io/api/networking/v1beta1"
+	istioclientv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+)
+
+// convertDestinationRule converts the traffic policy of the DestinationRule:
+// SIMPLE TLS origination becomes a BackendTLSPolicy and consistent hashing on
+// a cookie or a header becomes the session persistence of a BackendLBPolicy,
+// both targeting the Service of the host. The settings without Gateway API
+// equivalent are reported.
+func (c *resourcesToIRConverter) convertDestinationRule(dr *istioclientv1beta1.DestinationRule, ir *intermediate.IR, fieldPath *field.Path) field.ErrorList {
+	host := dr.Spec.GetHost()
+	if strings.Contains(host, "*") || (!strings.Contains(host, ".svc") && strings.Contains(host, ".")) {
+		notify(notifications.WarningNotification, fmt.Sprintf("host %q is not a Kubernetes Service, the DestinationRule was not converted, path: %v", host, fieldPath.Child("Host")), dr)
+		return nil
+	}
+	serviceName, serviceNamespace := parseK8SServiceFromDomain(host, dr.Namespace)
+	service := types.NamespacedName{Namespace: serviceNamespace, Name: serviceName}
+
+	trafficPolicy := dr.Spec.GetTrafficPolicy()
+	policyPath := fieldPath.Child("TrafficPolicy")
+
+	var unsupported []string
+	for _, setting := range []struct {
+		name string
+		set  bool
+	}{
+		{"trafficPolicy.connectionPool", trafficPolicy.GetConnectionPool() != nil},
+		{"trafficPolicy.outlierDetection", trafficPolicy.GetOutlierDetection() != nil},
+		{"trafficPolicy.portLevelSettings", len(trafficPolicy.GetPortLevelSettings()) > 0},
+		{"trafficPolicy.tunnel", trafficPolicy.GetTunnel() != nil},
+		{"subsets", len(dr.Spec.GetSubsets()) > 0},
+		{"workloadSelector", dr.Spec.GetWorkloadSelector() != nil},
+	} {
+		if setting.set {
+			unsupported = append(unsupported, setting.name)
+		}
+	}
+	if len(unsupported) > 0 {
+		notify(notifications.WarningNotification, fmt.Sprintf("%v of the DestinationRule have no Gateway API equivalent and were ignored, path: %v", unsupported, fieldPath), dr)
+	}
+
+	c.convertClientTLSSettings(dr, service, trafficPolicy.GetTls(), ir, policyPath.Child("Tls"))
+	return c.convertLoadBalancerSettings(dr, service, trafficPolicy.GetLoadBalancer(), ir, policyPath.Child("LoadBalancer"))
+}
+
+// convertClientTLSSettings generates the BackendTLSPolicy of the Service the
+// connections to which are originated with SIMPLE TLS. The CA certificates of
+// the settings can't be referenced by a BackendTLSPolicy: the connections are
+// validated with the configured well-known CA certificates instead.
+func (c *resourcesToIRConverter) convertClientTLSSettings(dr *istioclientv1beta1.DestinationRule, service types.NamespacedName, tls *istiov1beta1.ClientTLSSettings, ir *intermediate.IR, fieldPath *field.Path) {
+	if tls == nil {
+		return
+	}
+
+	switch tls.GetMode() {
+	case istiov1beta1.ClientTLSSettings_DISABLE:
+		return
+	case istiov1beta1.ClientTLSSettings_ISTIO_MUTUAL:
+		notify(notifications.WarningNotification, fmt.Sprintf("ISTIO_MUTUAL TLS is provided by the mesh and was not converted, path: %v", fieldPath.Child("Mode")), dr)
+		return
+	case istiov1beta1.ClientTLSSettings_MUTUAL:
+		notify(notifications.WarningNotification, fmt.Sprintf("MUTUAL TLS has no Gateway API equivalent, a BackendTLSPolicy was not generated, path: %v", fieldPath.Child("Mode")), dr)
+		return
+	}
+
+	if tls.GetInsecureSkipVerify().GetValue() {
+		notify(notifications.WarningNotification, fmt.Sprintf("BackendTLSPolicies always verify the certificates of the backends, a BackendTLSPolicy was not generated, path: %v", fieldPath.Child("InsecureSkipVerify")), dr)
+		return
+	}
+	if tls.GetCaCertificates() != "" || tls.GetCredentialName() != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("the CA certificates of the TLS settings can't be referenced by a BackendTLSPolicy and were ignored, reference them from a ConfigMap in the generated BackendTLSPolicy, path: %v", fieldPath), dr)
+	}
+	if len(tls.GetSubjectAltNames()) > 0 {
+		notify(notifications.WarningNotification, fmt.Sprintf("subjectAltNames have no Gateway API equivalent and were ignored, path: %v", fieldPath.Child("SubjectAltNames")), dr)
+	}
+	if c.wellKnownCACertificates == "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("the connections to Service %s use TLS, a BackendTLSPolicy was not generated, set --backend-tls-well-known-ca-certificates to validate them with well-known CA certificates, path: %v", service, fieldPath), dr)
+		return
+	}
+
+	hostname := fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)
+	if sni := tls.GetSni(); sni != "" {
+		hostname = sni
+	}
+	key := types.NamespacedName{Namespace: service.Namespace, Name: fmt.Sprintf("%s-backend-tls", service.Name)}
+	policy := gatewayv1alpha3.BackendTLSPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Spec: gatewayv1alpha3.BackendTLSPolicySpec{
+			TargetRefs: []gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName{{
+				LocalPolicyTargetReference: serviceTargetRef(service),
+			}},
+			Validation: gatewayv1alpha3.BackendTLSPolicyValidation{
+				Hostname:                gatewayv1.PreciseHostname(hostname),
+				WellKnownCACertificates: ptr.To(c.wellKnownCACertificates),
+			},
+		},
+	}
+	policy.SetGroupVersionKind(common.BackendTLSPolicyGVK)
+
+	if ir.BackendTLSPolicies == nil {
+		ir.BackendTLSPolicies = map[types.NamespacedName]gatewayv1alpha3.BackendTLSPolicy{}
+	}
+	if existing, ok := ir.BackendTLSPolicies[key]; ok {
+		if !reflect.DeepEqual(existing.Spec, policy.Spec) {
+			notify(notifications.WarningNotification, fmt.Sprintf("conflicting TLS settings for Service %s were ignored, path: %v", service, fieldPath), dr)
+		}
+		return
+	}
+	ir.BackendTLSPolicies[key] = policy
+	notify(notifications.InfoNotification, fmt.Sprintf("generated BackendTLSPolicy %s, path: %v", key, fieldPath), dr)
+}
+
+// convertLoadBalancerSettings generates the BackendLBPolicy of the Service
+// the requests to which are consistently hashed on a cookie or a header.
+func (c *resourcesToIRConverter) convertLoadBalancerSettings(dr *istioclientv1beta1.DestinationRule, service types.NamespacedName, loadBalancer *istiov1beta1.LoadBalancerSettings, ir *intermediate.IR, fieldPath *field.Path) field.ErrorList {
+	if loadBalancer == nil {
+		return nil
+	}
+
+	if loadBalancer.GetSimple() != istiov1beta1.LoadBalancerSettings_UNSPECIFIED {
+		notify(notifications.WarningNotification, fmt.Sprintf("the %v load balancing algorithm has no Gateway API equivalent and was ignored, path: %v", loadBalancer.GetSimple(), fieldPath.Child("Simple")), dr)
+	}
+	if loadBalancer.GetLocalityLbSetting() != nil || loadBalancer.GetWarmupDurationSecs() != nil {
+		notify(notifications.WarningNotification, fmt.Sprintf("localityLbSetting and warmupDurationSecs have no Gateway API equivalent and were ignored, path: %v", fieldPath), dr)
+	}
+
+	consistentHash := loadBalancer.GetConsistentHash()
+	if consistentHash == nil {
+		return nil
+	}
+	hashPath := fieldPath.Child("ConsistentHash")
+
+	var sessionPersistence *gatewayv1.SessionPersistence
+	switch {
+	case consistentHash.GetHttpCookie() != nil:
+		cookie := consistentHash.GetHttpCookie()
+		sessionPersistence = &gatewayv1.SessionPersistence{
+			SessionName: ptr.To(cookie.GetName()),
+			Type:        ptr.To(gatewayv1.CookieBasedSessionPersistence),
+		}
+		if ttl := cookie.GetTtl(); ttl != nil && ttl.AsDuration() > 0 {
+			d, err := common.ToGatewayDuration(ttl.AsDuration())
+			if err != nil {
+				return field.ErrorList{field.Invalid(hashPath.Child("HttpCookie", "Ttl"), ttl.AsDuration().String(), err.Error())}
+			}
+			sessionPersistence.AbsoluteTimeout = &d
+			sessionPersistence.CookieConfig = &gatewayv1.CookieConfig{LifetimeType: ptr.To(gatewayv1.PermanentCookieLifetimeType)}
+		}
+		if cookie.GetPath() != "" {
+			notify(notifications.WarningNotification, fmt.Sprintf("the path of the session cookie has no Gateway API equivalent and was ignored, path: %v", hashPath.Child("HttpCookie", "Path")), dr)
+		}
+	case consistentHash.GetHttpHeaderName() != "":
+		sessionPersistence = &gatewayv1.SessionPersistence{
+			SessionName: ptr.To(consistentHash.GetHttpHeaderName()),
+			Type:        ptr.To(gatewayv1.HeaderBasedSessionPersistence),
+		}
+	default:
+		notify(notifications.WarningNotification, fmt.Sprintf("consistent hashing is only converted to session persistence for cookies and headers, path: %v", hashPath), dr)
+		return nil
+	}
+
+	key := types.NamespacedName{Namespace: service.Namespace, Name: fmt.Sprintf("%s-backend-lb", service.Name)}
+	policy := gatewayv1alpha2.BackendLBPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Spec: gatewayv1alpha2.BackendLBPolicySpec{
+			TargetRefs:         []gatewayv1alpha2.LocalPolicyTargetReference{serviceTargetRef(service)},
+			SessionPersistence: sessionPersistence,
+		},
+	}
+	policy.SetGroupVersionKind(common.BackendLBPolicyGVK)
+
+	if ir.BackendLBPolicies == nil {
+		ir.BackendLBPolicies = map[types.NamespacedName]gatewayv1alpha2.BackendLBPolicy{}
+	}
+	if existing, ok := ir.BackendLBPolicies[key]; ok {
+		if !reflect.DeepEqual(existing.Spec, policy.Spec) {
+			notify(notifications.WarningNotification, fmt.Sprintf("conflicting consistent hashing for Service %s was ignored, path: %v", service, hashPath), dr)
+		}
+		return nil
+	}
+	ir.BackendLBPolicies[key] = policy
+	notify(notifications.InfoNotification, fmt.Sprintf("generated BackendLBPolicy %s, path: %v", key, hashPath), dr)
+	return nil
+}
+
+func serviceTargetRef(service types.NamespacedName) gatewayv1alpha2.LocalPolicyTargetReference {
+	return gatewayv1alpha2.LocalPolicyTargetReference{Group: "", Kind: "Service", Name: gatewayv1.ObjectName(service.Name)}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"google.golang.org/protobuf/types/known/durationpb"
+	istiov1beta1 "istio.io/api/networking/v1beta1"
+	istioclientv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+)
+
+func Test_resourcesToIRConverter_convertDestinationRule(t *testing.T) {
+	reviewsTargetRef := gatewayv1alpha2.LocalPolicyTargetReference{Kind: "Service", Name: "reviews"}
+
+	tests := []struct {
+		name                    string
+		host                    string
+		trafficPolicy           *istiov1beta1.TrafficPolicy
+		wellKnownCACertificates gatewayv1alpha3.WellKnownCACertificatesType
+		wantTLSPolicies         map[types.NamespacedName]gatewayv1alpha3.BackendTLSPolicy
+		wantLBPolicies          map[types.NamespacedName]gatewayv1alpha2.BackendLBPolicy
+	}{
+		{
+			name: "simple TLS with sni",
+			host: "reviews.prod.svc.cluster.local",
+			trafficPolicy: &istiov1beta1.TrafficPolicy{
+				Tls: &istiov1beta1.ClientTLSSettings{Mode: istiov1beta1.ClientTLSSettings_SIMPLE, Sni: "reviews.example.com"},
+			},
+			wellKnownCACertificates: gatewayv1alpha3.WellKnownCACertificatesSystem,
+			wantTLSPolicies: map[types.NamespacedName]gatewayv1alpha3.BackendTLSPolicy{
+				{Namespace: "prod", Name: "reviews-backend-tls"}: {
+					TypeMeta:   metav1.TypeMeta{APIVersion: common.BackendTLSPolicyGVK.GroupVersion().String(), Kind: common.BackendTLSPolicyGVK.Kind},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "reviews-backend-tls"},
+					Spec: gatewayv1alpha3.BackendTLSPolicySpec{
+						TargetRefs: []gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName{{LocalPolicyTargetReference: reviewsTargetRef}},
+						Validation: gatewayv1alpha3.BackendTLSPolicyValidation{
+							Hostname:                "reviews.example.com",
+							WellKnownCACertificates: ptr.To(gatewayv1alpha3.WellKnownCACertificatesSystem),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "simple TLS without well-known CA certificates",
+			host: "reviews",
+			trafficPolicy: &istiov1beta1.TrafficPolicy{
+				Tls: &istiov1beta1.ClientTLSSettings{Mode: istiov1beta1.ClientTLSSettings_SIMPLE},
+			},
+		},
+		{
+			name: "istio mutual TLS",
+			host: "reviews",
+			trafficPolicy: &istiov1beta1.TrafficPolicy{
+				Tls: &istiov1beta1.ClientTLSSettings{Mode: istiov1beta1.ClientTLSSettings_ISTIO_MUTUAL},
+			},
+			wellKnownCACertificates: gatewayv1alpha3.WellKnownCACertificatesSystem,
+		},
+		{
+			name: "consistent hash on a cookie",
+			host: "reviews",
+			trafficPolicy: &istiov1beta1.TrafficPolicy{
+				LoadBalancer: &istiov1beta1.LoadBalancerSettings{
+					LbPolicy: &istiov1beta1.LoadBalancerSettings_ConsistentHash{
+						ConsistentHash: &istiov1beta1.LoadBalancerSettings_ConsistentHashLB{
+							HashKey: &istiov1beta1.LoadBalancerSettings_ConsistentHashLB_HttpCookie{
+								HttpCookie: &istiov1beta1.LoadBalancerSettings_ConsistentHashLB_HTTPCookie{Name: "user", Ttl: durationpb.New(90 * time.Second)},
+							},
+						},
+					},
+				},
+				OutlierDetection: &istiov1beta1.OutlierDetection{},
+			},
+			wantLBPolicies: map[types.NamespacedName]gatewayv1alpha2.BackendLBPolicy{
+				{Namespace: "default", Name: "reviews-backend-lb"}: {
+					TypeMeta:   metav1.TypeMeta{APIVersion: common.BackendLBPolicyGVK.GroupVersion().String(), Kind: common.BackendLBPolicyGVK.Kind},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "reviews-backend-lb"},
+					Spec: gatewayv1alpha2.BackendLBPolicySpec{
+						TargetRefs: []gatewayv1alpha2.LocalPolicyTargetReference{reviewsTargetRef},
+						SessionPersistence: &gatewayv1.SessionPersistence{
+							SessionName:     ptr.To("user"),
+							AbsoluteTimeout: ptr.To[gatewayv1.Duration]("1m30s"),
+							Type:            ptr.To(gatewayv1.CookieBasedSessionPersistence),
+							CookieConfig:    &gatewayv1.CookieConfig{LifetimeType: ptr.To(gatewayv1.PermanentCookieLifetimeType)},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "consistent hash on a header",
+			host: "reviews.default.svc",
+			trafficPolicy: &istiov1beta1.TrafficPolicy{
+				LoadBalancer: &istiov1beta1.LoadBalancerSettings{
+					LbPolicy: &istiov1beta1.LoadBalancerSettings_ConsistentHash{
+						ConsistentHash: &istiov1beta1.LoadBalancerSettings_ConsistentHashLB{
+							HashKey: &istiov1beta1.LoadBalancerSettings_ConsistentHashLB_HttpHeaderName{HttpHeaderName: "x-user"},
+						},
+					},
+				},
+			},
+			wantLBPolicies: map[types.NamespacedName]gatewayv1alpha2.BackendLBPolicy{
+				{Namespace: "default", Name: "reviews-backend-lb"}: {
+					TypeMeta:   metav1.TypeMeta{APIVersion: common.BackendLBPolicyGVK.GroupVersion().String(), Kind: common.BackendLBPolicyGVK.Kind},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "reviews-backend-lb"},
+					Spec: gatewayv1alpha2.BackendLBPolicySpec{
+						TargetRefs: []gatewayv1alpha2.LocalPolicyTargetReference{reviewsTargetRef},
+						SessionPersistence: &gatewayv1.SessionPersistence{
+							SessionName: ptr.To("x-user"),
+							Type:        ptr.To(gatewayv1.HeaderBasedSessionPersistence),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "consistent hash on the source IP",
+			host: "reviews",
+			trafficPolicy: &istiov1beta1.TrafficPolicy{
+				LoadBalancer: &istiov1beta1.LoadBalancerSettings{
+					LbPolicy: &istiov1beta1.LoadBalancerSettings_ConsistentHash{
+						ConsistentHash: &istiov1beta1.LoadBalancerSettings_ConsistentHashLB{
+							HashKey: &istiov1beta1.LoadBalancerSettings_ConsistentHashLB_UseSourceIp{UseSourceIp: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "external host",
+			host: "api.example.com",
+			trafficPolicy: &istiov1beta1.TrafficPolicy{
+				Tls: &istiov1beta1.ClientTLSSettings{Mode: istiov1beta1.ClientTLSSettings_SIMPLE},
+			},
+			wellKnownCACertificates: gatewayv1alpha3.WellKnownCACertificatesSystem,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &resourcesToIRConverter{wellKnownCACertificates: tt.wellKnownCACertificates}
+			dr := &istioclientv1beta1.DestinationRule{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "reviews"},
+				Spec:       istiov1beta1.DestinationRule{Host: tt.host, TrafficPolicy: tt.trafficPolicy},
+			}
+			var ir intermediate.IR
+			if errs := c.convertDestinationRule(dr, &ir, field.NewPath("")); len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !apiequality.Semantic.DeepEqual(ir.BackendTLSPolicies, tt.wantTLSPolicies) {
+				t.Errorf("BackendTLSPolicies diff (-want +got): %s", cmp.Diff(tt.wantTLSPolicies, ir.BackendTLSPolicies))
+			}
+			if !apiequality.Semantic.DeepEqual(ir.BackendLBPolicies, tt.wantLBPolicies) {
+				t.Errorf("BackendLBPolicies diff (-want +got): %s", cmp.Diff(tt.wantLBPolicies, ir.BackendLBPolicies))
+			}
+		})
+	}
+}
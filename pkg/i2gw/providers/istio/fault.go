@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	istiov1beta1 "istio.io/api/networking/v1beta1"
+	istioclientv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// convertFault converts the fault injection of a VirtualService route to the
+// IR, for the emitters to generate the fault injection policies of their
+// implementation. It returns nil if none of the faults can be converted.
+func convertFault(vs *istioclientv1beta1.VirtualService, fault *istiov1beta1.HTTPFaultInjection, fieldPath *field.Path) *intermediate.FaultInjectionConfig {
+	var faultInjection intermediate.FaultInjectionConfig
+
+	if delay := fault.GetDelay(); delay != nil {
+		if fixedDelay := delay.GetFixedDelay(); fixedDelay != nil {
+			faultInjection.Delay = &intermediate.FaultDelay{
+				FixedDelay: fixedDelay.AsDuration(),
+				Percentage: faultPercentage(delay.GetPercentage(), delay.GetPercent()),
+			}
+		} else {
+			notify(notifications.WarningNotification, fmt.Sprintf("only fixed delays can be injected, the delay was not converted: %v", fieldPath.Child("Delay")), vs)
+		}
+	}
+
+	if abort := fault.GetAbort(); abort != nil {
+		switch {
+		case abort.GetHttpStatus() != 0:
+			faultInjection.Abort = &intermediate.FaultAbort{HTTPStatus: abort.GetHttpStatus()}
+		case abort.GetGrpcStatus() != "":
+			faultInjection.Abort = &intermediate.FaultAbort{GRPCStatus: abort.GetGrpcStatus()}
+		default:
+			notify(notifications.WarningNotification, fmt.Sprintf("only HTTP and gRPC statuses can be injected, the abort was not converted: %v", fieldPath.Child("Abort")), vs)
+		}
+		if faultInjection.Abort != nil {
+			faultInjection.Abort.Percentage = faultPercentage(abort.GetPercentage(), 0)
+		}
+	}
+
+	if faultInjection.Delay == nil && faultInjection.Abort == nil {
+		return nil
+	}
+	notify(notifications.InfoNotification, fmt.Sprintf("fault injection has no Gateway API equivalent, it is only generated by the istio and kgateway emitters: %v", fieldPath), vs)
+	return &faultInjection
+}
+
+// faultPercentage returns the percentage of the requests a fault is injected
+// in. Istio falls back to the deprecated integer percent when the percentage
+// isn't set, and injects no fault when neither is.
+func faultPercentage(percentage *istiov1beta1.Percent, percent int32) float64 {
+	if percentage != nil {
+		return percentage.GetValue()
+	}
+	return float64(percent)
+}
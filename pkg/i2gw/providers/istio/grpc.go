@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	istioclientv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// grpcRouteFilters returns the GRPCRoute filters equivalent to the filters of
+// an HTTPRoute rule, or false if one of them has no gRPC equivalent.
+func grpcRouteFilters(filters []gatewayv1.HTTPRouteFilter) ([]gatewayv1.GRPCRouteFilter, bool) {
+	grpcFilters, unsupported := common.ConvertHTTPFiltersToGRPCFilters(filters)
+	return grpcFilters, len(unsupported) == 0
+}
+
+// routesGRPC returns whether all the Gateways of the parentRefs of the routes
+// of a VirtualService have HTTP2 or GRPC servers.
+func (c *resourcesToIRConverter) routesGRPC(parentRefs []gatewayv1.ParentReference, namespace string) bool {
+	for _, parentRef := range parentRefs {
+		gwKey := types.NamespacedName{
+			Namespace: string(ptr.Deref(parentRef.Namespace, gatewayv1.Namespace(namespace))),
+			Name:      string(parentRef.Name),
+		}
+		if !c.grpcGateways.Has(gwKey) {
+			return false
+		}
+	}
+	return len(parentRefs) > 0
+}
+
+// toGRPCRoute converts an HTTPRoute of a VirtualService bound to gRPC
+// Gateways to a GRPCRoute, unless the HTTPRoute doesn't clearly route gRPC
+// methods or carries features GRPCRoutes can't express.
+func (c *resourcesToIRConverter) toGRPCRoute(httpRoute *gatewayv1.HTTPRoute, vs *istioclientv1beta1.VirtualService, fieldPath *field.Path) (*gatewayv1.GRPCRoute, bool) {
+	httpRouteKey := types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}
+	grpcRoute, ok := httpRouteToGRPCRoute(httpRoute)
+	if ok && (c.faultInjections[httpRouteKey] != nil || len(c.mirrorFractions[httpRouteKey]) > 0 || len(c.unsupportedFeatures[httpRouteKey]) > 0) {
+		ok = false
+	}
+	if !ok {
+		notify(notifications.InfoNotification, fmt.Sprintf("HTTPRoute %v is bound to gRPC servers but doesn't only match gRPC methods with GRPCRoute features, it was not converted to a GRPCRoute, path: %v", httpRouteKey, fieldPath), vs)
+		return nil, false
+	}
+	notify(notifications.InfoNotification, fmt.Sprintf("routes matching gRPC methods were converted to GRPCRoute %v, path: %v", httpRouteKey, fieldPath), vs)
+	return grpcRoute, true
+}
+
+// httpRouteToGRPCRoute converts an HTTPRoute generated for a VirtualService to
+// a GRPCRoute. It returns false unless the route clearly serves gRPC, that is
+// unless all its path matches are gRPC service or method matches, and all of
+// its matches, filters and timeouts have a GRPCRoute equivalent.
+func httpRouteToGRPCRoute(httpRoute *gatewayv1.HTTPRoute) (*gatewayv1.GRPCRoute, bool) {
+	apiVersion, kind := common.GRPCRouteGVK.ToAPIVersionAndKind()
+	grpcRoute := &gatewayv1.GRPCRoute{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiVersion,
+			Kind:       kind,
+		},
+		ObjectMeta: httpRoute.ObjectMeta,
+		Spec: gatewayv1.GRPCRouteSpec{
+			CommonRouteSpec: httpRoute.Spec.CommonRouteSpec,
+			Hostnames:       httpRoute.Spec.Hostnames,
+		},
+	}
+
+	var grpcPaths int
+	for _, rule := range httpRoute.Spec.Rules {
+		if rule.Timeouts != nil || rule.SessionPersistence != nil {
+			return nil, false
+		}
+		grpcRule := gatewayv1.GRPCRouteRule{}
+		for _, match := range rule.Matches {
+			if len(match.QueryParams) > 0 || match.Method != nil {
+				return nil, false
+			}
+			grpcMatch := gatewayv1.GRPCRouteMatch{}
+			if match.Path != nil {
+				methodMatch, ok := common.GRPCMethodMatch(match.Path)
+				if !ok {
+					return nil, false
+				}
+				grpcMatch.Method = methodMatch
+				grpcPaths++
+			}
+			for _, header := range match.Headers {
+				grpcMatch.Headers = append(grpcMatch.Headers, gatewayv1.GRPCHeaderMatch{
+					Type:  header.Type,
+					Name:  gatewayv1.GRPCHeaderName(header.Name),
+					Value: header.Value,
+				})
+			}
+			grpcRule.Matches = append(grpcRule.Matches, grpcMatch)
+		}
+
+		filters, ok := grpcRouteFilters(rule.Filters)
+		if !ok {
+			return nil, false
+		}
+		grpcRule.Filters = filters
+
+		for _, backendRef := range rule.BackendRefs {
+			backendFilters, ok := grpcRouteFilters(backendRef.Filters)
+			if !ok {
+				return nil, false
+			}
+			grpcRule.BackendRefs = append(grpcRule.BackendRefs, gatewayv1.GRPCBackendRef{
+				BackendRef: backendRef.BackendRef,
+				Filters:    backendFilters,
+			})
+		}
+		grpcRoute.Spec.Rules = append(grpcRoute.Spec.Rules, grpcRule)
+	}
+
+	if grpcPaths == 0 {
+		return nil, false
+	}
+	return grpcRoute, true
+}
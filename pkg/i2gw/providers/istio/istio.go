@@ -29,8 +29,26 @@ import (
 // The ProviderName returned to the provider's registry.
 const ProviderName = "istio"
 
+// CredentialNamespaceFlag is the provider-specific flag setting the namespace
+// of the Secrets referenced by the credentialName of the Gateway servers.
+const CredentialNamespaceFlag = "credential-namespace"
+
+// GRPCRoutesFlag is the provider-specific flag enabling the conversion of the
+// VirtualService routes matching gRPC methods to GRPCRoutes.
+const GRPCRoutesFlag = "grpc-routes"
+
 func init() {
 	i2gw.ProviderConstructorByName[ProviderName] = NewProvider
+	i2gw.RegisterProviderSupportLevel(ProviderName, i2gw.StableSupportLevel)
+	i2gw.RegisterProviderSpecificFlag(ProviderName, i2gw.ProviderSpecificFlag{
+		Name:        CredentialNamespaceFlag,
+		Description: "The namespace of the Secrets referenced by the credentialName of the Gateway servers, that is the namespace of the istio ingress gateway deployment, e.g. istio-system. Defaults to the namespace of each Gateway.",
+	})
+	i2gw.RegisterProviderSpecificFlag(ProviderName, i2gw.ProviderSpecificFlag{
+		Name:         GRPCRoutesFlag,
+		Description:  "If true, the routes of the VirtualServices bound to HTTP2 or GRPC servers matching /<package>.<Service>/<Method> paths are converted to GRPCRoutes with method matches instead of HTTPRoutes.",
+		DefaultValue: "false",
+	})
 }
 
 type Provider struct {
@@ -44,7 +62,7 @@ func NewProvider(conf *i2gw.ProviderConf) i2gw.Provider {
 	return &Provider{
 		storage:                newResourcesStorage(),
 		reader:                 newResourceReader(conf),
-		resourcesToIRConverter: newResourcesToIRConverter(),
+		resourcesToIRConverter: newResourcesToIRConverter(conf),
 	}
 }
 
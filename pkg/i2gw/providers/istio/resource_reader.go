@@ -58,6 +58,13 @@ func (r *reader) readResourcesFromCluster(ctx context.Context) (*storage, error)
 
 	res.VirtualServices = virtualServices
 
+	destinationRules, err := r.readDestinationRulesFromCluster(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read destination rules: %w", err)
+	}
+
+	res.DestinationRules = destinationRules
+
 	return res, nil
 }
 
@@ -110,6 +117,17 @@ func (r *reader) readUnstructuredObjects(objects []*unstructured.Unstructured) (
 				Namespace: vs.Namespace,
 				Name:      vs.Name,
 			}] = &vs
+
+		case DestinationRuleKind:
+			var dr istiov1beta1.DestinationRule
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &dr); err != nil {
+				return nil, fmt.Errorf("failed to parse istio destination rule object: %w", err)
+			}
+
+			res.DestinationRules[types.NamespacedName{
+				Namespace: dr.Namespace,
+				Name:      dr.Name,
+			}] = &dr
 		default:
 			log.Printf("%v provider: skipped resource with unsupported Kind: %v", ProviderName, objKind)
 			continue
@@ -171,3 +189,30 @@ func (r *reader) readVirtualServicesFromCluster(ctx context.Context) (map[types.
 
 	return res, nil
 }
+
+func (r *reader) readDestinationRulesFromCluster(ctx context.Context) (map[types.NamespacedName]*istiov1beta1.DestinationRule, error) {
+	destinationRulesList := &unstructured.UnstructuredList{}
+	destinationRulesList.SetAPIVersion(APIVersion)
+	destinationRulesList.SetKind(DestinationRuleKind)
+
+	err := r.conf.Client.List(ctx, destinationRulesList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list istio destination rules: %w", err)
+	}
+
+	res := map[types.NamespacedName]*istiov1beta1.DestinationRule{}
+
+	for _, obj := range destinationRulesList.Items {
+		var dr istiov1beta1.DestinationRule
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &dr); err != nil {
+			return nil, fmt.Errorf("failed to parse istio destination rule object: %w", err)
+		}
+
+		res[types.NamespacedName{
+			Namespace: dr.Namespace,
+			Name:      dr.Name,
+		}] = &dr
+	}
+
+	return res, nil
+}
@@ -22,13 +22,15 @@ import (
 )
 
 type storage struct {
-	Gateways        map[types.NamespacedName]*istiov1beta1.Gateway
-	VirtualServices map[types.NamespacedName]*istiov1beta1.VirtualService
+	Gateways         map[types.NamespacedName]*istiov1beta1.Gateway
+	VirtualServices  map[types.NamespacedName]*istiov1beta1.VirtualService
+	DestinationRules map[types.NamespacedName]*istiov1beta1.DestinationRule
 }
 
 func newResourcesStorage() *storage {
 	return &storage{
-		Gateways:        map[types.NamespacedName]*istiov1beta1.Gateway{},
-		VirtualServices: map[types.NamespacedName]*istiov1beta1.VirtualService{},
+		Gateways:         map[types.NamespacedName]*istiov1beta1.Gateway{},
+		VirtualServices:  map[types.NamespacedName]*istiov1beta1.VirtualService{},
+		DestinationRules: map[types.NamespacedName]*istiov1beta1.DestinationRule{},
 	}
 }
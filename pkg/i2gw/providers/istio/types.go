@@ -17,9 +17,14 @@ limitations under the License.
 package istio
 
 const (
-	APIVersion         = "networking.istio.io/v1beta1"
-	GatewayKind        = "Gateway"
-	VirtualServiceKind = "VirtualService"
+	APIVersion          = "networking.istio.io/v1beta1"
+	GatewayKind         = "Gateway"
+	VirtualServiceKind  = "VirtualService"
+	DestinationRuleKind = "DestinationRule"
 
 	K8SGatewayClassName = "istio"
+
+	// MeshGateway is the reserved gateway name binding a VirtualService to
+	// all the sidecars in the mesh.
+	MeshGateway = "mesh"
 )
@@ -25,21 +25,41 @@ import (
 const (
 	annotationPrefix = "konghq.com"
 
-	headersKey = "headers"
-	methodsKey = "methods"
-	pluginsKey = "plugins"
+	headersKey       = "headers"
+	methodsKey       = "methods"
+	overrideKey      = "override"
+	pluginsKey       = "plugins"
+	regexPriorityKey = "regex-priority"
 )
 
 const (
+	v1Version      = "v1"
 	v1beta1Version = "v1beta1"
 
 	kongResourcesGroup = "configuration.konghq.com"
 
-	kongPluginKind = "KongPlugin"
-	tcpIngressKind = "TCPIngress"
+	kongClusterPluginKind = "KongClusterPlugin"
+	kongIngressKind       = "KongIngress"
+	kongPluginKind        = "KongPlugin"
+	tcpIngressKind        = "TCPIngress"
 )
 
 var (
+	kongClusterPluginGVK = schema.GroupVersionKind{
+		Group:   kongResourcesGroup,
+		Version: v1Version,
+		Kind:    kongClusterPluginKind,
+	}
+	kongIngressGVK = schema.GroupVersionKind{
+		Group:   kongResourcesGroup,
+		Version: v1Version,
+		Kind:    kongIngressKind,
+	}
+	kongPluginGVK = schema.GroupVersionKind{
+		Group:   kongResourcesGroup,
+		Version: v1Version,
+		Kind:    kongPluginKind,
+	}
 	tcpIngressGVK = schema.GroupVersionKind{
 		Group:   kongResourcesGroup,
 		Version: v1beta1Version,
@@ -28,20 +28,24 @@ import (
 
 // resourcesToIRConverter implements the ToIR function of i2gw.ResourcesToIRConverter interface.
 type resourcesToIRConverter struct {
-	featureParsers                []i2gw.FeatureParser
+	featureChain                  *i2gw.FeatureChain
 	implementationSpecificOptions i2gw.ProviderImplementationSpecificOptions
 }
 
 // newResourcesToIRConverter returns an kong converter instance.
-func newResourcesToIRConverter() *resourcesToIRConverter {
+func newResourcesToIRConverter(conf *i2gw.ProviderConf) *resourcesToIRConverter {
 	return &resourcesToIRConverter{
-		featureParsers: []i2gw.FeatureParser{
-			headerMatchingFeature,
-			methodMatchingFeature,
-			pluginsFeature,
-		},
+		featureChain: i2gw.NewFeatureChain(
+			i2gw.NamedFeatureParser{Name: "header-matching", Parse: headerMatchingFeature},
+			// The header matching feature replaces the headers of the matches,
+			// which the method matching feature duplicates per method.
+			i2gw.NamedFeatureParser{Name: "method-matching", Parse: methodMatchingFeature, After: []string{"header-matching"}},
+			i2gw.NamedFeatureParser{Name: "plugins", Parse: pluginsFeature},
+			i2gw.NamedFeatureParser{Name: "regex-priority", Parse: regexPriorityFeature},
+		).Use(conf.FeatureToggles.Middleware(Name)),
 		implementationSpecificOptions: i2gw.ProviderImplementationSpecificOptions{
 			ToImplementationSpecificHTTPPathTypeMatch: implementationSpecificHTTPPathTypeMatch,
+			DisableRouteMerging:                       !conf.Profile.RouteMerging,
 		},
 	}
 }
@@ -76,12 +80,12 @@ func (c *resourcesToIRConverter) convert(storage *storage) (intermediate.IR, fie
 		return intermediate.IR{}, errs
 	}
 
-	for _, parseFeatureFunc := range c.featureParsers {
-		// Apply the feature parsing function to the gateway resources, one by one.
-		errs = parseFeatureFunc(ingressList, &ir)
-		// Append the parsing errors to the error list.
-		errorList = append(errorList, errs...)
-	}
+	// Apply the feature parsing functions to the gateway resources, in order.
+	errorList = append(errorList, c.featureChain.Run(ingressList, &ir)...)
+	resolver := pluginResolver{kongPlugins: storage.KongPlugins, kongClusterPlugins: storage.KongClusterPlugins}
+	errorList = append(errorList, kongIngressesToIR(ingressList, storage.KongIngresses, &ir)...)
+	errorList = append(errorList, transformerPluginsToFilters(resolver, &ir)...)
+	errorList = append(errorList, policyPluginsToIR(resolver, &ir)...)
 
 	return ir, errorList
 }
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kong
+
+import "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+
+func init() {
+	i2gw.RegisterProviderExample(Name, i2gw.ProviderExample{
+		Feature:     "header-matching",
+		Description: "The konghq.com/headers.* annotations are converted to header matches, the values of a header being ORed.",
+		Manifest: `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: header-matching
+  namespace: default
+  annotations:
+    konghq.com/headers.x-routing: alpha,bravo
+spec:
+  ingressClassName: kong
+  rules:
+  - host: headers.example.com
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: routing
+            port:
+              number: 80
+`,
+	})
+	i2gw.RegisterProviderExample(Name, i2gw.ProviderExample{
+		Feature:     "method-matching",
+		Description: "The konghq.com/methods annotation is converted to method matches.",
+		Manifest: `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: method-matching
+  namespace: default
+  annotations:
+    konghq.com/methods: GET,POST
+spec:
+  ingressClassName: kong
+  rules:
+  - host: methods.example.com
+    http:
+      paths:
+      - path: /api
+        pathType: Prefix
+        backend:
+          service:
+            name: api
+            port:
+              number: 80
+`,
+	})
+	i2gw.RegisterProviderExample(Name, i2gw.ProviderExample{
+		Feature:     "plugins",
+		Description: "The request-transformer and response-transformer KongPlugins of the konghq.com/plugins annotation are converted to header modifier filters, the other plugins are referenced by ExtensionRef filters.",
+		Manifest: `apiVersion: configuration.konghq.com/v1
+kind: KongPlugin
+metadata:
+  name: add-prefix
+  namespace: default
+plugin: request-transformer
+config:
+  add:
+    headers:
+    - x-forwarded-prefix:/shop
+---
+apiVersion: configuration.konghq.com/v1
+kind: KongPlugin
+metadata:
+  name: no-store
+  namespace: default
+plugin: response-transformer
+config:
+  replace:
+    headers:
+    - cache-control:no-store
+---
+apiVersion: configuration.konghq.com/v1
+kind: KongPlugin
+metadata:
+  name: rate-limit
+  namespace: default
+plugin: rate-limiting
+config:
+  minute: 60
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: plugins
+  namespace: default
+  annotations:
+    konghq.com/plugins: add-prefix,no-store,rate-limit
+spec:
+  ingressClassName: kong
+  rules:
+  - host: shop.example.com
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: shop
+            port:
+              number: 80
+`,
+	})
+}
@@ -39,7 +39,7 @@ import (
 // All the values defined for each annotation name, and separated by comma, MUST be ORed.
 // All the annotation names MUST be ANDed, with the respective values.
 func headerMatchingFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
-	ruleGroups := common.GetRuleGroups(ingresses)
+	ruleGroups := common.GetRuleGroups(ingresses, ir)
 	for _, rg := range ruleGroups {
 		for _, rule := range rg.Rules {
 			headerskeys, headersValues := parseHeadersAnnotations(rule.Ingress.Annotations)
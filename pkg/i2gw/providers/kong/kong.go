@@ -32,6 +32,11 @@ const KongIngressClass = "kong"
 
 func init() {
 	i2gw.ProviderConstructorByName[Name] = NewProvider
+	i2gw.RegisterProviderSupportLevel(Name, i2gw.StableSupportLevel)
+	i2gw.RegisterIngressClaimRule(Name, i2gw.IngressClaimRule{
+		IngressClasses:     []string{KongIngressClass},
+		AnnotationPrefixes: []string{annotationPrefix + "/"},
+	})
 }
 
 // Provider implements the i2gw.Provider interface.
@@ -45,7 +50,7 @@ type Provider struct {
 func NewProvider(conf *i2gw.ProviderConf) i2gw.Provider {
 	return &Provider{
 		resourceReader:         newResourceReader(conf),
-		resourcesToIRConverter: newResourcesToIRConverter(),
+		resourcesToIRConverter: newResourcesToIRConverter(conf),
 	}
 }
 
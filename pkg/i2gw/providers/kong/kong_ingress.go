@@ -0,0 +1,293 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kong
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// kongIngressesToIR applies the KongIngresses referenced by the
+// konghq.com/override annotation of the Ingresses to the HTTPRoute rules
+// generated from their paths:
+//   - route.methods and route.headers become method and header matches, unless
+//     the Ingress sets the konghq.com/methods or konghq.com/headers.*
+//     annotations, which take precedence in Kong.
+//   - route.strip_path becomes a URLRewrite filter replacing the matched
+//     prefix with proxy.path, or "/".
+//   - route.regex_priority sets the priority of the rules, unless the Ingress
+//     sets the konghq.com/regex-priority annotation.
+//
+// The other fields have no Gateway API equivalent, they are notified and
+// recorded as unsupported features of the HTTPRoutes.
+//
+// Example: konghq.com/override: "kong-ingress"
+func kongIngressesToIR(ingresses []networkingv1.Ingress, kongIngresses map[types.NamespacedName]*kongv1.KongIngress, ir *intermediate.IR) field.ErrorList {
+	var errs field.ErrorList
+	annotation := kongAnnotation(overrideKey)
+	notified := map[types.NamespacedName]bool{}
+	for _, rg := range common.GetRuleGroups(ingresses, ir) {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRouteContext, ok := ir.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			name, ok := rule.Ingress.Annotations[annotation]
+			if !ok {
+				continue
+			}
+			kongIngressKey := types.NamespacedName{Namespace: rule.Ingress.Namespace, Name: name}
+			kongIngress, ok := kongIngresses[kongIngressKey]
+			if !ok {
+				if !notified[kongIngressKey] {
+					notify(notifications.WarningNotification, fmt.Sprintf("KongIngress %s referenced by ingress %s/%s was not found", kongIngressKey, rule.Ingress.Namespace, rule.Ingress.Name), &rule.Ingress)
+					notified[kongIngressKey] = true
+				}
+				continue
+			}
+			fieldPath := field.NewPath(kongIngressKind).Key(kongIngressKey.String())
+
+			ruleIndices := common.RuleIndicesForIngressRule(httpRouteContext.HTTPRoute, rule.IngressRule)
+			if route := kongIngress.Route; route != nil {
+				if _, ok := rule.Ingress.Annotations[kongAnnotation(methodsKey)]; !ok && len(route.Methods) > 0 {
+					methods, methodErrs := kongIngressMethods(route.Methods, fieldPath.Child("route", "methods"))
+					if len(methodErrs) > 0 {
+						errs = append(errs, methodErrs...)
+						continue
+					}
+					for _, i := range ruleIndices {
+						httpRouteContext.Spec.Rules[i].Matches = matchesWithMethods(httpRouteContext.Spec.Rules[i].Matches, methods)
+					}
+				}
+				if headerNames, _ := parseHeadersAnnotations(rule.Ingress.Annotations); len(headerNames) == 0 && len(route.Headers) > 0 {
+					for _, i := range ruleIndices {
+						httpRouteContext.Spec.Rules[i].Matches = matchesWithHeaders(httpRouteContext.Spec.Rules[i].Matches, route.Headers)
+					}
+				}
+				if _, ok := rule.Ingress.Annotations[kongAnnotation(regexPriorityKey)]; !ok && route.RegexPriority != nil {
+					if *route.RegexPriority < math.MinInt32 || *route.RegexPriority > math.MaxInt32 {
+						errs = append(errs, field.Invalid(fieldPath.Child("route", "regex_priority"), *route.RegexPriority, "must be a 32-bit integer"))
+					} else {
+						setRulePriority(&httpRouteContext, ingressRuleIndices(httpRouteContext.HTTPRoute, rule.IngressRule), int32(*route.RegexPriority), fieldPath.Child("route", "regex_priority").String())
+					}
+				}
+				if route.StripPath != nil && *route.StripPath {
+					prefix := "/"
+					if kongIngress.Proxy != nil && kongIngress.Proxy.Path != nil {
+						prefix = *kongIngress.Proxy.Path
+					}
+					for _, i := range ruleIndices {
+						addStripPathFilter(&httpRouteContext.Spec.Rules[i], prefix)
+					}
+				}
+			}
+
+			for _, feature := range unsupportedKongIngressFields(kongIngress) {
+				feature.Source = kongIngressKey
+				if !slices.Contains(httpRouteContext.UnsupportedFeatures, feature) {
+					httpRouteContext.UnsupportedFeatures = append(httpRouteContext.UnsupportedFeatures, feature)
+				}
+			}
+			if !notified[kongIngressKey] {
+				notifyKongIngress(kongIngressKey, kongIngress, &httpRouteContext.HTTPRoute)
+				notified[kongIngressKey] = true
+			}
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+	return errs
+}
+
+func kongIngressMethods(methods []*string, fieldPath *field.Path) ([]gatewayv1.HTTPMethod, field.ErrorList) {
+	var errs field.ErrorList
+	var httpMethods []gatewayv1.HTTPMethod
+	for i, method := range methods {
+		if method == nil {
+			continue
+		}
+		httpMethod := gatewayv1.HTTPMethod(strings.ToUpper(*method))
+		if err := validateHTTPMethod(httpMethod); err != nil {
+			errs = append(errs, field.Invalid(fieldPath.Index(i), *method, err.Error()))
+			continue
+		}
+		httpMethods = append(httpMethods, httpMethod)
+	}
+	return httpMethods, errs
+}
+
+// matchesWithMethods duplicates the matches per method, as the methods are
+// ORed.
+func matchesWithMethods(matches []gatewayv1.HTTPRouteMatch, methods []gatewayv1.HTTPMethod) []gatewayv1.HTTPRouteMatch {
+	var newMatches []gatewayv1.HTTPRouteMatch
+	for _, match := range matches {
+		if match.Method != nil {
+			newMatches = append(newMatches, match)
+			continue
+		}
+		for _, method := range methods {
+			newMatch := match.DeepCopy()
+			newMatch.Method = ptr.To(method)
+			newMatches = append(newMatches, *newMatch)
+		}
+	}
+	return newMatches
+}
+
+// matchesWithHeaders duplicates the matches per combination of the header
+// values, as the headers are ANDed and the values of a header ORed.
+func matchesWithHeaders(matches []gatewayv1.HTTPRouteMatch, headers map[string][]string) []gatewayv1.HTTPRouteMatch {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	newMatches := matches
+	for _, name := range names {
+		var expanded []gatewayv1.HTTPRouteMatch
+		for _, match := range newMatches {
+			for _, value := range headers[name] {
+				newMatch := match.DeepCopy()
+				newMatch.Headers = append(newMatch.Headers, gatewayv1.HTTPHeaderMatch{Name: gatewayv1.HTTPHeaderName(name), Value: value})
+				expanded = append(expanded, *newMatch)
+			}
+		}
+		if len(expanded) > 0 {
+			newMatches = expanded
+		}
+	}
+	return newMatches
+}
+
+// addStripPathFilter replaces the prefix matched by the rule with the given
+// prefix, unless the rule rewrites the URLs already.
+func addStripPathFilter(rule *gatewayv1.HTTPRouteRule, prefix string) {
+	if slices.ContainsFunc(rule.Filters, func(filter gatewayv1.HTTPRouteFilter) bool {
+		return filter.Type == gatewayv1.HTTPRouteFilterURLRewrite
+	}) {
+		return
+	}
+	if !slices.ContainsFunc(rule.Matches, func(match gatewayv1.HTTPRouteMatch) bool {
+		return match.Path != nil && match.Path.Type != nil && *match.Path.Type == gatewayv1.PathMatchPathPrefix
+	}) {
+		return
+	}
+	rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{
+		Type: gatewayv1.HTTPRouteFilterURLRewrite,
+		URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+			Path: &gatewayv1.HTTPPathModifier{
+				Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+				ReplacePrefixMatch: ptr.To(prefix),
+			},
+		},
+	})
+}
+
+// unsupportedKongIngressFields returns the fields of the KongIngress without
+// Gateway API equivalent.
+func unsupportedKongIngressFields(kongIngress *kongv1.KongIngress) []intermediate.UnsupportedFeature {
+	fields := map[string]interface{}{}
+	if route := kongIngress.Route; route != nil {
+		if len(route.Protocols) > 0 {
+			fields["route.protocols"] = route.Protocols
+		}
+		if route.PreserveHost != nil {
+			fields["route.preserve_host"] = *route.PreserveHost
+		}
+		if route.HTTPSRedirectStatusCode != nil {
+			fields["route.https_redirect_status_code"] = *route.HTTPSRedirectStatusCode
+		}
+		if route.PathHandling != nil {
+			fields["route.path_handling"] = *route.PathHandling
+		}
+		if len(route.SNIs) > 0 {
+			fields["route.snis"] = route.SNIs
+		}
+		if route.RequestBuffering != nil {
+			fields["route.request_buffering"] = *route.RequestBuffering
+		}
+		if route.ResponseBuffering != nil {
+			fields["route.response_buffering"] = *route.ResponseBuffering
+		}
+	}
+	if proxy := kongIngress.Proxy; proxy != nil {
+		if proxy.Protocol != nil {
+			fields["proxy.protocol"] = *proxy.Protocol
+		}
+		// The path of the service is only converted with strip_path.
+		if proxy.Path != nil && (kongIngress.Route == nil || kongIngress.Route.StripPath == nil || !*kongIngress.Route.StripPath) {
+			fields["proxy.path"] = *proxy.Path
+		}
+		if proxy.Retries != nil {
+			fields["proxy.retries"] = *proxy.Retries
+		}
+		if proxy.ConnectTimeout != nil {
+			fields["proxy.connect_timeout"] = *proxy.ConnectTimeout
+		}
+		if proxy.ReadTimeout != nil {
+			fields["proxy.read_timeout"] = *proxy.ReadTimeout
+		}
+		if proxy.WriteTimeout != nil {
+			fields["proxy.write_timeout"] = *proxy.WriteTimeout
+		}
+	}
+	if kongIngress.Upstream != nil {
+		fields["upstream"] = kongIngress.Upstream
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var features []intermediate.UnsupportedFeature
+	for _, name := range names {
+		rawConfig, _ := json.Marshal(fields[name])
+		features = append(features, intermediate.UnsupportedFeature{
+			SourceKind: kongIngressKind,
+			Name:       name,
+			RawConfig:  string(rawConfig),
+		})
+	}
+	return features
+}
+
+func notifyKongIngress(key types.NamespacedName, kongIngress *kongv1.KongIngress, httpRoute *gatewayv1.HTTPRoute) {
+	var names []string
+	for _, feature := range unsupportedKongIngressFields(kongIngress) {
+		names = append(names, feature.Name)
+	}
+	if len(names) > 0 {
+		notify(notifications.WarningNotification, fmt.Sprintf("KongIngress %s: %v have no Gateway API equivalent and must be ported manually", key, names), httpRoute)
+	}
+	notify(notifications.InfoNotification, fmt.Sprintf("applied KongIngress %s to HTTPRoute %s/%s", key, httpRoute.Namespace, httpRoute.Name), httpRoute)
+}
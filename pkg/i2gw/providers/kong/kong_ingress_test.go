@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kong
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestKongIngressesToIR(t *testing.T) {
+	ingress := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "api",
+			Annotations: map[string]string{kongAnnotation(overrideKey): "api-override"},
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To("kong"),
+			Rules: []networkingv1.IngressRule{{
+				Host: "api.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/api",
+						PathType: ptr.To(networkingv1.PathTypePrefix),
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: "api",
+							Port: networkingv1.ServiceBackendPort{Number: 80},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+	kongIngressKey := types.NamespacedName{Namespace: "default", Name: "api-override"}
+	kongIngresses := map[types.NamespacedName]*kongv1.KongIngress{
+		kongIngressKey: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api-override"},
+			Route: &kongv1.KongIngressRoute{
+				Methods:       []*string{ptr.To("get"), ptr.To("POST")},
+				Headers:       map[string][]string{"x-version": {"1", "2"}},
+				StripPath:     ptr.To(true),
+				RegexPriority: ptr.To(5),
+			},
+			Proxy: &kongv1.KongIngressService{
+				Path:    ptr.To("/v1"),
+				Retries: ptr.To(3),
+			},
+		},
+	}
+
+	key := types.NamespacedName{Namespace: "default", Name: "api-api-example-com"}
+	pathMatch := gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/api")}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			key: {HTTPRoute: gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"api.example.com"},
+					Rules: []gatewayv1.HTTPRouteRule{{
+						Matches: []gatewayv1.HTTPRouteMatch{{Path: &pathMatch}},
+					}},
+				},
+			}},
+		},
+	}
+
+	if errs := kongIngressesToIR([]networkingv1.Ingress{ingress}, kongIngresses, &ir); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	match := func(method gatewayv1.HTTPMethod, version string) gatewayv1.HTTPRouteMatch {
+		return gatewayv1.HTTPRouteMatch{
+			Path:    &pathMatch,
+			Method:  ptr.To(method),
+			Headers: []gatewayv1.HTTPHeaderMatch{{Name: "x-version", Value: version}},
+		}
+	}
+	expectedRule := gatewayv1.HTTPRouteRule{
+		Matches: []gatewayv1.HTTPRouteMatch{
+			match(gatewayv1.HTTPMethodGet, "1"),
+			match(gatewayv1.HTTPMethodGet, "2"),
+			match(gatewayv1.HTTPMethodPost, "1"),
+			match(gatewayv1.HTTPMethodPost, "2"),
+		},
+		Filters: []gatewayv1.HTTPRouteFilter{{
+			Type: gatewayv1.HTTPRouteFilterURLRewrite,
+			URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Path: &gatewayv1.HTTPPathModifier{
+				Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+				ReplacePrefixMatch: ptr.To("/v1"),
+			}},
+		}},
+	}
+	httpRouteContext := ir.HTTPRoutes[key]
+	if diff := cmp.Diff(expectedRule, httpRouteContext.Spec.Rules[0]); diff != "" {
+		t.Errorf("unexpected rule (-want +got):\n%s", diff)
+	}
+	expectedUnsupported := []intermediate.UnsupportedFeature{
+		{SourceKind: kongIngressKind, Source: kongIngressKey, Name: "proxy.retries", RawConfig: "3"},
+	}
+	if diff := cmp.Diff(expectedUnsupported, httpRouteContext.UnsupportedFeatures); diff != "" {
+		t.Errorf("unexpected unsupported features (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[int]int32{0: 5}, httpRouteContext.RulePriorities); diff != "" {
+		t.Errorf("unexpected rule priorities (-want +got):\n%s", diff)
+	}
+}
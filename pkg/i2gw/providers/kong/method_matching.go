@@ -38,7 +38,7 @@ import (
 //
 // All the values defined and separated by comma, MUST be ORed.
 func methodMatchingFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
-	ruleGroups := common.GetRuleGroups(ingresses)
+	ruleGroups := common.GetRuleGroups(ingresses, ir)
 	for _, rg := range ruleGroups {
 		for _, rule := range rg.Rules {
 			key := types.NamespacedName{Namespace: rule.Ingress.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
@@ -37,7 +38,7 @@ import (
 //
 // Example: konghq.com/plugins: "plugin1,plugin2"
 func pluginsFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
-	ruleGroups := common.GetRuleGroups(ingresses)
+	ruleGroups := common.GetRuleGroups(ingresses, ir)
 	for _, rg := range ruleGroups {
 		for _, rule := range rg.Rules {
 			key := types.NamespacedName{Namespace: rule.Ingress.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
@@ -87,3 +88,69 @@ func patchHTTPRoutePlugins(httpRoute *gatewayv1.HTTPRoute, extensionRefs []gatew
 		notify(notifications.InfoNotification, fmt.Sprintf("parsed \"%v\" annotation of ingress and patched %v fields", kongAnnotation(pluginsKey), field.NewPath("httproute", "spec", "rules").Key("").Child("filters")), httpRoute)
 	}
 }
+
+// referencedPlugin is a KongPlugin, or KongClusterPlugin, referenced by an
+// ExtensionRef filter.
+type referencedPlugin struct {
+	kind       string
+	key        types.NamespacedName
+	pluginName string
+	config     []byte
+}
+
+func (p *referencedPlugin) String() string {
+	if p.kind == kongClusterPluginKind {
+		return fmt.Sprintf("%s %s", p.kind, p.key.Name)
+	}
+	return fmt.Sprintf("%s %s", p.kind, p.key)
+}
+
+// pluginResolver resolves the plugins referenced by the ExtensionRef filters
+// of the HTTPRoutes.
+type pluginResolver struct {
+	kongPlugins        map[types.NamespacedName]*kongv1.KongPlugin
+	kongClusterPlugins map[string]*kongv1.KongClusterPlugin
+}
+
+// resolve returns the enabled plugin referenced by the filter, if any. As
+// Kong, the KongPlugin of the namespace is looked up first, then the
+// KongClusterPlugin of the same name. The plugins configured from Secrets are
+// not resolved, as their configuration isn't available.
+func (r pluginResolver) resolve(namespace string, filter gatewayv1.HTTPRouteFilter) *referencedPlugin {
+	if filter.Type != gatewayv1.HTTPRouteFilterExtensionRef || filter.ExtensionRef == nil ||
+		filter.ExtensionRef.Group != kongResourcesGroup || filter.ExtensionRef.Kind != kongPluginKind {
+		return nil
+	}
+	name := string(filter.ExtensionRef.Name)
+	if kongPlugin, ok := r.kongPlugins[types.NamespacedName{Namespace: namespace, Name: name}]; ok {
+		if kongPlugin.Disabled || kongPlugin.ConfigFrom != nil {
+			return nil
+		}
+		return &referencedPlugin{
+			kind:       kongPluginKind,
+			key:        types.NamespacedName{Namespace: namespace, Name: name},
+			pluginName: kongPlugin.PluginName,
+			config:     kongPlugin.Config.Raw,
+		}
+	}
+	if kongClusterPlugin, ok := r.kongClusterPlugins[name]; ok {
+		if kongClusterPlugin.Disabled || kongClusterPlugin.ConfigFrom != nil {
+			return nil
+		}
+		return &referencedPlugin{
+			kind:       kongClusterPluginKind,
+			key:        types.NamespacedName{Name: name},
+			pluginName: kongClusterPlugin.PluginName,
+			config:     kongClusterPlugin.Config.Raw,
+		}
+	}
+	return nil
+}
+
+// pluginPath returns the path of the configuration of the plugin, for errors.
+func pluginPath(plugin *referencedPlugin) *field.Path {
+	if plugin.kind == kongClusterPluginKind {
+		return field.NewPath(plugin.kind).Key(plugin.key.Name).Child("config")
+	}
+	return field.NewPath(plugin.kind).Key(plugin.key.String()).Child("config")
+}
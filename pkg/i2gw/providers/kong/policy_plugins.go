@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kong
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	rateLimitingPlugin = "rate-limiting"
+	corsPlugin         = "cors"
+)
+
+// rateLimitingConfig is the configuration of the rate-limiting plugin, with
+// the number of requests allowed per window.
+type rateLimitingConfig struct {
+	Second  *int64 `json:"second"`
+	Minute  *int64 `json:"minute"`
+	Hour    *int64 `json:"hour"`
+	Day     *int64 `json:"day"`
+	Month   *int64 `json:"month"`
+	Year    *int64 `json:"year"`
+	LimitBy string `json:"limit_by"`
+}
+
+// corsConfig is the configuration of the cors plugin.
+type corsConfig struct {
+	Origins        []string `json:"origins"`
+	Methods        []string `json:"methods"`
+	Headers        []string `json:"headers"`
+	ExposedHeaders []string `json:"exposed_headers"`
+	Credentials    bool     `json:"credentials"`
+	MaxAge         *int64   `json:"max_age"`
+}
+
+// policyPluginsToIR keeps the rate-limiting and cors plugins referenced by the
+// ExtensionRef filters of the HTTPRoutes as policies of the Kong HTTPRoute IR,
+// as they have no Gateway API core equivalent. The plugins stay referenced,
+// for Kong, and the implementation emitters can convert the policies to their
+// own resources.
+//
+// Example:
+//
+//	apiVersion: configuration.konghq.com/v1
+//	kind: KongPlugin
+//	plugin: rate-limiting
+//	config:
+//	  minute: 100
+func policyPluginsToIR(resolver pluginResolver, ir *intermediate.IR) field.ErrorList {
+	var errs field.ErrorList
+	policiesByPlugin := map[string]*intermediate.KongPolicy{}
+	for key, httpRouteContext := range ir.HTTPRoutes {
+		var policies []intermediate.KongPolicy
+		for i, rule := range httpRouteContext.Spec.Rules {
+			for _, filter := range rule.Filters {
+				plugin := resolver.resolve(key.Namespace, filter)
+				if plugin == nil || (plugin.pluginName != rateLimitingPlugin && plugin.pluginName != corsPlugin) {
+					continue
+				}
+				policy, ok := policiesByPlugin[plugin.String()]
+				if !ok {
+					var err *field.Error
+					policy, err = pluginPolicy(plugin)
+					if err != nil {
+						errs = append(errs, err)
+					}
+					policiesByPlugin[plugin.String()] = policy
+				}
+				if policy == nil {
+					continue
+				}
+				policies = addPolicyRule(policies, *policy, i)
+			}
+		}
+		if len(policies) == 0 {
+			continue
+		}
+		if httpRouteContext.ProviderSpecificIR.Kong == nil {
+			httpRouteContext.ProviderSpecificIR.Kong = &intermediate.KongHTTPRouteIR{}
+		}
+		httpRouteContext.ProviderSpecificIR.Kong.Policies = append(httpRouteContext.ProviderSpecificIR.Kong.Policies, policies...)
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+	return errs
+}
+
+// pluginPolicy converts the configuration of the rate-limiting or cors plugin
+// to a policy.
+func pluginPolicy(plugin *referencedPlugin) (*intermediate.KongPolicy, *field.Error) {
+	policy := &intermediate.KongPolicy{
+		Plugin:        plugin.key.Name,
+		ClusterScoped: plugin.kind == kongClusterPluginKind,
+	}
+	switch plugin.pluginName {
+	case rateLimitingPlugin:
+		var config rateLimitingConfig
+		if err := json.Unmarshal(plugin.config, &config); err != nil {
+			return nil, field.Invalid(pluginPath(plugin), string(plugin.config), err.Error())
+		}
+		policy.RateLimit = rateLimit(plugin, config)
+		if policy.RateLimit == nil {
+			return nil, field.Required(pluginPath(plugin), "a rate-limiting plugin requires a limit of requests per second, minute, hour, day, month or year")
+		}
+	case corsPlugin:
+		var config corsConfig
+		if err := json.Unmarshal(plugin.config, &config); err != nil {
+			return nil, field.Invalid(pluginPath(plugin), string(plugin.config), err.Error())
+		}
+		policy.CORS = &intermediate.KongCORSConfig{
+			Origins:        config.Origins,
+			Methods:        config.Methods,
+			Headers:        config.Headers,
+			ExposedHeaders: config.ExposedHeaders,
+			Credentials:    config.Credentials,
+		}
+		if config.MaxAge != nil {
+			policy.CORS.MaxAge = ptr.To(time.Duration(*config.MaxAge) * time.Second)
+		}
+	}
+	notify(notifications.InfoNotification, fmt.Sprintf("%s %s has no Gateway API equivalent, it stays referenced and was kept as a policy for the implementation emitters", plugin.pluginName, plugin))
+	return policy, nil
+}
+
+// rateLimit returns the limit of the shortest window of the configuration.
+// Kong enforces the limits of all the windows, the others are notified.
+func rateLimit(plugin *referencedPlugin, config rateLimitingConfig) *intermediate.KongRateLimitConfig {
+	windows := []struct {
+		limit  *int64
+		period time.Duration
+	}{
+		{config.Second, time.Second},
+		{config.Minute, time.Minute},
+		{config.Hour, time.Hour},
+		{config.Day, 24 * time.Hour},
+		{config.Month, 30 * 24 * time.Hour},
+		{config.Year, 365 * 24 * time.Hour},
+	}
+	var limit *intermediate.KongRateLimitConfig
+	ignored := 0
+	for _, window := range windows {
+		if window.limit == nil {
+			continue
+		}
+		if limit != nil {
+			ignored++
+			continue
+		}
+		limit = &intermediate.KongRateLimitConfig{Requests: *window.limit, Period: window.period, LimitBy: config.LimitBy}
+	}
+	if limit == nil {
+		return nil
+	}
+	if limit.LimitBy == "" {
+		limit.LimitBy = "consumer"
+	}
+	if ignored > 0 {
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %s limits the requests of %d windows, only the limit of %d requests per %s was kept in its policy", plugin.pluginName, plugin, ignored+1, limit.Requests, limit.Period))
+	}
+	return limit
+}
+
+// addPolicyRule adds the rule index to the policy of the same plugin, adding
+// the policy if missing.
+func addPolicyRule(policies []intermediate.KongPolicy, policy intermediate.KongPolicy, ruleIndex int) []intermediate.KongPolicy {
+	for i := range policies {
+		if policies[i].Plugin == policy.Plugin && policies[i].ClusterScoped == policy.ClusterScoped {
+			if !slices.Contains(policies[i].RuleIndices, ruleIndex) {
+				policies[i].RuleIndices = append(policies[i].RuleIndices, ruleIndex)
+			}
+			return policies
+		}
+	}
+	policy.RuleIndices = []int{ruleIndex}
+	return append(policies, policy)
+}
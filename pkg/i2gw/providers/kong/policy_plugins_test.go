@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kong
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestPolicyPluginsToIR(t *testing.T) {
+	pluginRef := func(name string) gatewayv1.HTTPRouteFilter {
+		return gatewayv1.HTTPRouteFilter{
+			Type: gatewayv1.HTTPRouteFilterExtensionRef,
+			ExtensionRef: &gatewayv1.LocalObjectReference{
+				Group: kongResourcesGroup,
+				Kind:  kongPluginKind,
+				Name:  gatewayv1.ObjectName(name),
+			},
+		}
+	}
+	resolver := pluginResolver{
+		kongPlugins: map[types.NamespacedName]*kongv1.KongPlugin{
+			{Namespace: "default", Name: "rate-limit"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "rate-limit"},
+				PluginName: rateLimitingPlugin,
+				Config:     apiextensionsv1.JSON{Raw: []byte(`{"minute":100,"hour":1000,"limit_by":"ip"}`)},
+			},
+			{Namespace: "default", Name: "invalid"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "invalid"},
+				PluginName: rateLimitingPlugin,
+				Config:     apiextensionsv1.JSON{Raw: []byte(`{"policy":"local"}`)},
+			},
+		},
+		kongClusterPlugins: map[string]*kongv1.KongClusterPlugin{
+			"cors": {
+				ObjectMeta: metav1.ObjectMeta{Name: "cors"},
+				PluginName: corsPlugin,
+				Config:     apiextensionsv1.JSON{Raw: []byte(`{"origins":["https://example.com"],"methods":["GET","POST"],"credentials":true,"max_age":3600}`)},
+			},
+		},
+	}
+
+	key := types.NamespacedName{Namespace: "default", Name: "route"}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			key: {HTTPRoute: gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+				Spec: gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{
+					{Filters: []gatewayv1.HTTPRouteFilter{pluginRef("rate-limit"), pluginRef("cors")}},
+					{Filters: []gatewayv1.HTTPRouteFilter{pluginRef("cors"), pluginRef("invalid")}},
+					{},
+				}},
+			}},
+		},
+	}
+
+	errs := policyPluginsToIR(resolver, &ir)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+	}
+	expected := &intermediate.KongHTTPRouteIR{Policies: []intermediate.KongPolicy{
+		{
+			Plugin:      "rate-limit",
+			RuleIndices: []int{0},
+			RateLimit:   &intermediate.KongRateLimitConfig{Requests: 100, Period: time.Minute, LimitBy: "ip"},
+		},
+		{
+			Plugin:        "cors",
+			ClusterScoped: true,
+			RuleIndices:   []int{0, 1},
+			CORS: &intermediate.KongCORSConfig{
+				Origins:     []string{"https://example.com"},
+				Methods:     []string{"GET", "POST"},
+				Credentials: true,
+				MaxAge:      ptr.To(time.Hour),
+			},
+		},
+	}}
+	if diff := cmp.Diff(expected, ir.HTTPRoutes[key].ProviderSpecificIR.Kong); diff != "" {
+		t.Errorf("unexpected Kong IR (-want +got):\n%s", diff)
+	}
+	// The plugins stay referenced.
+	if diff := cmp.Diff([]gatewayv1.HTTPRouteFilter{pluginRef("rate-limit"), pluginRef("cors")}, ir.HTTPRoutes[key].Spec.Rules[0].Filters); diff != "" {
+		t.Errorf("unexpected filters (-want +got):\n%s", diff)
+	}
+}
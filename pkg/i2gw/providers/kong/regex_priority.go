@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kong
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// regexPriorityFeature parses the Kong Ingress Controller regex priority
+// annotation, and sets the priority of the HTTPRoute rules generated from the
+// paths of the ingress, which orders them in their HTTPRoute.
+//
+// Kong evaluates the routes matching a request with regular expressions by
+// descending regex priority, 0 by default:
+// konghq.com/regex-priority: "10"
+//
+// Gateway API only orders the rules of equal match precedence by their index
+// though, so the priority is honoured among the regular expression matches
+// only, whose precedence is implementation-specific.
+func regexPriorityFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	var errs field.ErrorList
+	annotation := kongAnnotation(regexPriorityKey)
+	for _, rg := range common.GetRuleGroups(ingresses, ir) {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRouteContext, ok := ir.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			value, ok := rule.Ingress.Annotations[annotation]
+			if !ok {
+				continue
+			}
+			priority, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				fieldPath := field.NewPath(fmt.Sprintf("%s/%s", rule.Ingress.Namespace, rule.Ingress.Name)).Child("metadata").Child("annotations").Child(annotation)
+				errs = append(errs, field.Invalid(fieldPath, value, "must be a 32-bit integer"))
+				continue
+			}
+			setRulePriority(&httpRouteContext, ingressRuleIndices(httpRouteContext.HTTPRoute, rule.IngressRule), int32(priority), annotation)
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+	return errs
+}
+
+// setRulePriority sets the priority of the rules of the HTTPRoute. The rules
+// generated from the paths of several ingresses keep the highest priority of
+// these ingresses.
+func setRulePriority(httpRouteContext *intermediate.HTTPRouteContext, ruleIndices []int, priority int32, source string) {
+	if httpRouteContext.RulePriorities == nil {
+		httpRouteContext.RulePriorities = map[int]int32{}
+	}
+	for _, i := range ruleIndices {
+		if current, ok := httpRouteContext.RulePriorities[i]; ok && current != priority {
+			notify(notifications.WarningNotification, fmt.Sprintf("rule %d of the HTTPRoute is generated from ingresses of distinct regex priorities, the highest one is kept", i), &httpRouteContext.HTTPRoute)
+			if current > priority {
+				continue
+			}
+		}
+		httpRouteContext.RulePriorities[i] = priority
+	}
+	if len(ruleIndices) > 0 {
+		notify(notifications.InfoNotification, fmt.Sprintf("parsed %q and set the priority of the rules %v of the HTTPRoute to %d", source, ruleIndices, priority), &httpRouteContext.HTTPRoute)
+	}
+}
+
+// ingressRuleIndices returns the indices of the HTTPRoute rules generated from
+// the paths of the ingress rule, including the ImplementationSpecific paths,
+// which Kong converts to regular expression or prefix matches.
+func ingressRuleIndices(httpRoute gatewayv1.HTTPRoute, ingressRule networkingv1.IngressRule) []int {
+	var indices []int
+	if ingressRule.HTTP == nil {
+		return indices
+	}
+	for i, rule := range httpRoute.Spec.Rules {
+		for _, path := range ingressRule.HTTP.Paths {
+			if ruleMatchesPath(rule, path) {
+				indices = append(indices, i)
+				break
+			}
+		}
+	}
+	return indices
+}
+
+func ruleMatchesPath(rule gatewayv1.HTTPRouteRule, path networkingv1.HTTPIngressPath) bool {
+	if path.PathType == nil || *path.PathType != networkingv1.PathTypeImplementationSpecific {
+		return common.HTTPRouteRuleMatchesPath(rule, path)
+	}
+	pathMatch := gatewayv1.HTTPPathMatch{Value: common.PtrTo(path.Path)}
+	implementationSpecificHTTPPathTypeMatch(&pathMatch)
+	for _, match := range rule.Matches {
+		if match.Path != nil && match.Path.Type != nil && match.Path.Value != nil &&
+			*match.Path.Type == *pathMatch.Type && *match.Path.Value == *pathMatch.Value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kong
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func TestRegexPriorityFeature(t *testing.T) {
+	ingress := func(name, path string, pathType networkingv1.PathType, annotations map[string]string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptr.To("kong"),
+				Rules: []networkingv1.IngressRule{{
+					Host: "api.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     path,
+							PathType: ptr.To(pathType),
+							Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+								Name: name,
+								Port: networkingv1.ServiceBackendPort{Number: 80},
+							}},
+						}},
+					}},
+				}},
+			},
+		}
+	}
+	options := i2gw.ProviderImplementationSpecificOptions{ToImplementationSpecificHTTPPathTypeMatch: implementationSpecificHTTPPathTypeMatch}
+	key := types.NamespacedName{Namespace: "default", Name: "versions-api-example-com"}
+
+	testCases := []struct {
+		name               string
+		ingresses          []networkingv1.Ingress
+		expectedPriorities map[int]int32
+		expectedErrors     int
+	}{
+		{
+			name: "regex paths of the ingresses of a host",
+			ingresses: []networkingv1.Ingress{
+				ingress("versions", "/~/v[0-9]+", networkingv1.PathTypeImplementationSpecific, map[string]string{kongAnnotation(regexPriorityKey): "10"}),
+				ingress("v1", "/~/v1", networkingv1.PathTypeImplementationSpecific, map[string]string{kongAnnotation(regexPriorityKey): "20"}),
+				ingress("default", "/", networkingv1.PathTypePrefix, nil),
+			},
+			expectedPriorities: map[int]int32{0: 10, 1: 20},
+		},
+		{
+			name: "path of ingresses of distinct priorities",
+			ingresses: []networkingv1.Ingress{
+				ingress("versions", "/~/v[0-9]+", networkingv1.PathTypeImplementationSpecific, map[string]string{kongAnnotation(regexPriorityKey): "10"}),
+				ingress("other", "/~/v[0-9]+", networkingv1.PathTypeImplementationSpecific, map[string]string{kongAnnotation(regexPriorityKey): "-5"}),
+			},
+			expectedPriorities: map[int]int32{0: 10},
+		},
+		{
+			name: "invalid priority",
+			ingresses: []networkingv1.Ingress{
+				ingress("versions", "/~/v[0-9]+", networkingv1.PathTypeImplementationSpecific, map[string]string{kongAnnotation(regexPriorityKey): "high"}),
+			},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir, errs := common.ToIR(tc.ingresses, options)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			errs = regexPriorityFeature(tc.ingresses, &ir)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("expected %d errors, got %v", tc.expectedErrors, errs)
+			}
+			if diff := cmp.Diff(tc.expectedPriorities, ir.HTTPRoutes[key].RulePriorities, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("unexpected rule priorities (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
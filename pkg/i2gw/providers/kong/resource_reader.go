@@ -24,8 +24,9 @@ import (
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/types"
 
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
@@ -50,7 +51,7 @@ func newResourceReader(conf *i2gw.ProviderConf) *resourceReader {
 func (r *resourceReader) readResourcesFromCluster(ctx context.Context) (*storage, error) {
 	storage := newResourceStorage()
 
-	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, sets.New(KongIngressClass))
+	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, r.conf.IngressClaimer.ClaimFunc(Name))
 	if err != nil {
 		return nil, err
 	}
@@ -62,13 +63,31 @@ func (r *resourceReader) readResourcesFromCluster(ctx context.Context) (*storage
 	}
 	storage.TCPIngresses = tcpIngresses
 
+	kongPlugins, err := r.readKongPluginsFromCluster(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read KongPlugins: %w", err)
+	}
+	storage.KongPlugins = kongPlugins
+
+	kongClusterPlugins, err := r.readKongClusterPluginsFromCluster(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read KongClusterPlugins: %w", err)
+	}
+	storage.KongClusterPlugins = kongClusterPlugins
+
+	kongIngresses, err := r.readKongIngressesFromCluster(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read KongIngresses: %w", err)
+	}
+	storage.KongIngresses = kongIngresses
+
 	return storage, nil
 }
 
 func (r *resourceReader) readResourcesFromFile(filename string) (*storage, error) {
 	storage := newResourceStorage()
 
-	ingresses, err := common.ReadIngressesFromFile(filename, r.conf.Namespace, sets.New(KongIngressClass))
+	ingresses, err := common.ReadIngressesFromFile(filename, r.conf.Namespace, r.conf.IngressClaimer.ClaimFunc(Name))
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +99,24 @@ func (r *resourceReader) readResourcesFromFile(filename string) (*storage, error
 	}
 	storage.TCPIngresses = tcpIngresses
 
+	kongPlugins, err := r.readKongPluginsFromFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read KongPlugins: %w", err)
+	}
+	storage.KongPlugins = kongPlugins
+
+	kongClusterPlugins, err := r.readKongClusterPluginsFromFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read KongClusterPlugins: %w", err)
+	}
+	storage.KongClusterPlugins = kongClusterPlugins
+
+	kongIngresses, err := r.readKongIngressesFromFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read KongIngresses: %w", err)
+	}
+	storage.KongIngresses = kongIngresses
+
 	return storage, nil
 }
 
@@ -140,3 +177,176 @@ func (r *resourceReader) readTCPIngressesFromFile(filename string) ([]kongv1beta
 
 	return tcpIngresses, nil
 }
+
+// -----------------------------------------------------------------------------
+// readers - KongPlugin
+// -----------------------------------------------------------------------------
+
+func (r *resourceReader) readKongPluginsFromCluster(ctx context.Context) (map[types.NamespacedName]*kongv1.KongPlugin, error) {
+	kongPluginList := &unstructured.UnstructuredList{}
+	kongPluginList.SetGroupVersionKind(kongPluginGVK)
+
+	err := r.conf.Client.List(ctx, kongPluginList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", kongPluginGVK.GroupKind().String(), err)
+	}
+
+	kongPlugins := map[types.NamespacedName]*kongv1.KongPlugin{}
+	for _, obj := range kongPluginList.Items {
+		var kongPlugin kongv1.KongPlugin
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &kongPlugin); err != nil {
+			return nil, fmt.Errorf("failed to parse Kong KongPlugin object: %w", err)
+		}
+
+		kongPlugins[types.NamespacedName{Namespace: kongPlugin.Namespace, Name: kongPlugin.Name}] = &kongPlugin
+	}
+
+	return kongPlugins, nil
+}
+
+func (r *resourceReader) readKongPluginsFromFile(filename string) (map[types.NamespacedName]*kongv1.KongPlugin, error) {
+	stream, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := bytes.NewReader(stream)
+	objs, err := common.ExtractObjectsFromReader(reader, r.conf.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	kongPlugins := map[types.NamespacedName]*kongv1.KongPlugin{}
+	for _, f := range objs {
+		if r.conf.Namespace != "" && f.GetNamespace() != r.conf.Namespace {
+			continue
+		}
+		if !f.GroupVersionKind().Empty() &&
+			f.GroupVersionKind() == kongPluginGVK {
+			kongPlugin := &kongv1.KongPlugin{}
+			err = runtime.DefaultUnstructuredConverter.
+				FromUnstructured(f.UnstructuredContent(), kongPlugin)
+			if err != nil {
+				return nil, err
+			}
+			kongPlugins[types.NamespacedName{Namespace: kongPlugin.Namespace, Name: kongPlugin.Name}] = kongPlugin
+		}
+	}
+
+	return kongPlugins, nil
+}
+
+// -----------------------------------------------------------------------------
+// readers - KongClusterPlugin
+// -----------------------------------------------------------------------------
+
+func (r *resourceReader) readKongClusterPluginsFromCluster(ctx context.Context) (map[string]*kongv1.KongClusterPlugin, error) {
+	kongClusterPluginList := &unstructured.UnstructuredList{}
+	kongClusterPluginList.SetGroupVersionKind(kongClusterPluginGVK)
+
+	err := r.conf.Client.List(ctx, kongClusterPluginList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", kongClusterPluginGVK.GroupKind().String(), err)
+	}
+
+	kongClusterPlugins := map[string]*kongv1.KongClusterPlugin{}
+	for _, obj := range kongClusterPluginList.Items {
+		var kongClusterPlugin kongv1.KongClusterPlugin
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &kongClusterPlugin); err != nil {
+			return nil, fmt.Errorf("failed to parse Kong KongClusterPlugin object: %w", err)
+		}
+
+		kongClusterPlugins[kongClusterPlugin.Name] = &kongClusterPlugin
+	}
+
+	return kongClusterPlugins, nil
+}
+
+func (r *resourceReader) readKongClusterPluginsFromFile(filename string) (map[string]*kongv1.KongClusterPlugin, error) {
+	stream, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// The KongClusterPlugins are cluster-scoped, they are read regardless of
+	// the namespace filter.
+	reader := bytes.NewReader(stream)
+	objs, err := common.ExtractObjectsFromReader(reader, "")
+	if err != nil {
+		return nil, err
+	}
+
+	kongClusterPlugins := map[string]*kongv1.KongClusterPlugin{}
+	for _, f := range objs {
+		if !f.GroupVersionKind().Empty() &&
+			f.GroupVersionKind() == kongClusterPluginGVK {
+			kongClusterPlugin := &kongv1.KongClusterPlugin{}
+			err = runtime.DefaultUnstructuredConverter.
+				FromUnstructured(f.UnstructuredContent(), kongClusterPlugin)
+			if err != nil {
+				return nil, err
+			}
+			kongClusterPlugins[kongClusterPlugin.Name] = kongClusterPlugin
+		}
+	}
+
+	return kongClusterPlugins, nil
+}
+
+// -----------------------------------------------------------------------------
+// readers - KongIngress
+// -----------------------------------------------------------------------------
+
+func (r *resourceReader) readKongIngressesFromCluster(ctx context.Context) (map[types.NamespacedName]*kongv1.KongIngress, error) {
+	kongIngressList := &unstructured.UnstructuredList{}
+	kongIngressList.SetGroupVersionKind(kongIngressGVK)
+
+	err := r.conf.Client.List(ctx, kongIngressList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", kongIngressGVK.GroupKind().String(), err)
+	}
+
+	kongIngresses := map[types.NamespacedName]*kongv1.KongIngress{}
+	for _, obj := range kongIngressList.Items {
+		var kongIngress kongv1.KongIngress
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &kongIngress); err != nil {
+			return nil, fmt.Errorf("failed to parse Kong KongIngress object: %w", err)
+		}
+
+		kongIngresses[types.NamespacedName{Namespace: kongIngress.Namespace, Name: kongIngress.Name}] = &kongIngress
+	}
+
+	return kongIngresses, nil
+}
+
+func (r *resourceReader) readKongIngressesFromFile(filename string) (map[types.NamespacedName]*kongv1.KongIngress, error) {
+	stream, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := bytes.NewReader(stream)
+	objs, err := common.ExtractObjectsFromReader(reader, r.conf.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	kongIngresses := map[types.NamespacedName]*kongv1.KongIngress{}
+	for _, f := range objs {
+		if r.conf.Namespace != "" && f.GetNamespace() != r.conf.Namespace {
+			continue
+		}
+		if !f.GroupVersionKind().Empty() &&
+			f.GroupVersionKind() == kongIngressGVK {
+			kongIngress := &kongv1.KongIngress{}
+			err = runtime.DefaultUnstructuredConverter.
+				FromUnstructured(f.UnstructuredContent(), kongIngress)
+			if err != nil {
+				return nil, err
+			}
+			kongIngresses[types.NamespacedName{Namespace: kongIngress.Namespace, Name: kongIngress.Name}] = kongIngress
+		}
+	}
+
+	return kongIngresses, nil
+}
@@ -17,19 +17,26 @@ limitations under the License.
 package kong
 
 import (
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
 type storage struct {
-	Ingresses    map[types.NamespacedName]*networkingv1.Ingress
-	TCPIngresses []kongv1beta1.TCPIngress
+	Ingresses          map[types.NamespacedName]*networkingv1.Ingress
+	TCPIngresses       []kongv1beta1.TCPIngress
+	KongPlugins        map[types.NamespacedName]*kongv1.KongPlugin
+	KongClusterPlugins map[string]*kongv1.KongClusterPlugin
+	KongIngresses      map[types.NamespacedName]*kongv1.KongIngress
 }
 
 func newResourceStorage() *storage {
 	return &storage{
-		Ingresses:    map[types.NamespacedName]*networkingv1.Ingress{},
-		TCPIngresses: []kongv1beta1.TCPIngress{},
+		Ingresses:          map[types.NamespacedName]*networkingv1.Ingress{},
+		TCPIngresses:       []kongv1beta1.TCPIngress{},
+		KongPlugins:        map[types.NamespacedName]*kongv1.KongPlugin{},
+		KongClusterPlugins: map[string]*kongv1.KongClusterPlugin{},
+		KongIngresses:      map[types.NamespacedName]*kongv1.KongIngress{},
 	}
 }
@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kong
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	requestTransformerPlugin  = "request-transformer"
+	responseTransformerPlugin = "response-transformer"
+)
+
+// transformerConfig is the configuration of the request-transformer and
+// response-transformer plugins. Kong applies its operations in the order of
+// the fields: remove, rename, replace, add, and append.
+type transformerConfig struct {
+	Remove  transformerOperation `json:"remove"`
+	Rename  transformerOperation `json:"rename"`
+	Replace transformerOperation `json:"replace"`
+	Add     transformerOperation `json:"add"`
+	Append  transformerOperation `json:"append"`
+
+	HTTPMethod string `json:"http_method"`
+}
+
+type transformerOperation struct {
+	Headers     []string `json:"headers"`
+	Querystring []string `json:"querystring"`
+	Body        []string `json:"body"`
+	JSON        []string `json:"json"`
+	URI         string   `json:"uri"`
+}
+
+// transformerPluginsToFilters replaces the ExtensionRef filters of the
+// HTTPRoutes referencing request-transformer and response-transformer
+// KongPlugins by RequestHeaderModifier and ResponseHeaderModifier filters.
+// The transformations of the query strings, bodies, URIs and methods have no
+// equivalent, they are notified and the plugins stay referenced.
+//
+// Example:
+//
+//	apiVersion: configuration.konghq.com/v1
+//	kind: KongPlugin
+//	plugin: request-transformer
+//	config:
+//	  add:
+//	    headers:
+//	    - x-forwarded-prefix:/api
+func transformerPluginsToFilters(resolver pluginResolver, ir *intermediate.IR) field.ErrorList {
+	var errs field.ErrorList
+	for key, httpRouteContext := range ir.HTTPRoutes {
+		for i := range httpRouteContext.Spec.Rules {
+			rule := &httpRouteContext.Spec.Rules[i]
+			var filters []gatewayv1.HTTPRouteFilter
+			for _, filter := range rule.Filters {
+				plugin := resolver.resolve(key.Namespace, filter)
+				if plugin == nil || (plugin.pluginName != requestTransformerPlugin && plugin.pluginName != responseTransformerPlugin) {
+					filters = append(filters, filter)
+					continue
+				}
+				var config transformerConfig
+				if err := json.Unmarshal(plugin.config, &config); err != nil {
+					errs = append(errs, field.Invalid(pluginPath(plugin), string(plugin.config), err.Error()))
+					filters = append(filters, filter)
+					continue
+				}
+				modifier, fullyConverted := transformerHeaderModifier(plugin, config, &httpRouteContext.HTTPRoute)
+				filterType := gatewayv1.HTTPRouteFilterRequestHeaderModifier
+				if plugin.pluginName == responseTransformerPlugin {
+					filterType = gatewayv1.HTTPRouteFilterResponseHeaderModifier
+				}
+				filters = mergeHeaderModifier(filters, filterType, modifier)
+				if !fullyConverted {
+					filters = append(filters, filter)
+				}
+			}
+			rule.Filters = filters
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+	return errs
+}
+
+// transformerHeaderModifier converts the header operations of the transformer
+// configuration to a header modifier, and returns whether all the operations
+// of the configuration were converted.
+func transformerHeaderModifier(plugin *referencedPlugin, config transformerConfig, httpRoute *gatewayv1.HTTPRoute) (*gatewayv1.HTTPHeaderFilter, bool) {
+	modifier := &gatewayv1.HTTPHeaderFilter{}
+	for _, name := range config.Remove.Headers {
+		modifier.Remove = appendUnique(modifier.Remove, name)
+	}
+	// Set headers are replaced in requests lacking them too, and added
+	// headers are appended to the values of the requests having them already.
+	for _, header := range config.Replace.Headers {
+		modifier.Set = setHeader(modifier.Set, header, false)
+	}
+	for _, header := range config.Add.Headers {
+		modifier.Add = setHeader(modifier.Add, header, false)
+	}
+	for _, header := range config.Append.Headers {
+		modifier.Add = setHeader(modifier.Add, header, true)
+	}
+
+	var unsupported []string
+	for name, operation := range map[string]transformerOperation{
+		"remove": config.Remove, "rename": config.Rename, "replace": config.Replace, "add": config.Add, "append": config.Append,
+	} {
+		if name == "rename" && len(operation.Headers) > 0 {
+			unsupported = append(unsupported, "rename.headers")
+		}
+		if len(operation.Querystring) > 0 {
+			unsupported = append(unsupported, name+".querystring")
+		}
+		if len(operation.Body) > 0 {
+			unsupported = append(unsupported, name+".body")
+		}
+		if len(operation.JSON) > 0 {
+			unsupported = append(unsupported, name+".json")
+		}
+		if operation.URI != "" {
+			unsupported = append(unsupported, name+".uri")
+		}
+	}
+	if config.HTTPMethod != "" {
+		unsupported = append(unsupported, "http_method")
+	}
+
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %s: %v have no Gateway API equivalent, the plugin stays referenced for them", plugin.pluginName, plugin, unsupported), httpRoute)
+	}
+	notify(notifications.InfoNotification, fmt.Sprintf("converted the headers of %s %s to a header modifier filter", plugin.pluginName, plugin), httpRoute)
+	return modifier, len(unsupported) == 0
+}
+
+// setHeader sets the "name:value" header in the headers, case-insensitively,
+// appending the value to the existing one if requested.
+func setHeader(headers []gatewayv1.HTTPHeader, header string, appendValue bool) []gatewayv1.HTTPHeader {
+	name, value, _ := strings.Cut(header, ":")
+	for i := range headers {
+		if strings.EqualFold(string(headers[i].Name), name) {
+			if appendValue {
+				headers[i].Value = headers[i].Value + "," + value
+			} else {
+				headers[i].Value = value
+			}
+			return headers
+		}
+	}
+	return append(headers, gatewayv1.HTTPHeader{Name: gatewayv1.HTTPHeaderName(name), Value: value})
+}
+
+// mergeHeaderModifier merges the modifier into the filter of its type, as a
+// rule can have a single filter of each header modifier type.
+func mergeHeaderModifier(filters []gatewayv1.HTTPRouteFilter, filterType gatewayv1.HTTPRouteFilterType, modifier *gatewayv1.HTTPHeaderFilter) []gatewayv1.HTTPRouteFilter {
+	if len(modifier.Set) == 0 && len(modifier.Add) == 0 && len(modifier.Remove) == 0 {
+		return filters
+	}
+	for i := range filters {
+		if filters[i].Type != filterType {
+			continue
+		}
+		existing := filters[i].RequestHeaderModifier
+		if filterType == gatewayv1.HTTPRouteFilterResponseHeaderModifier {
+			existing = filters[i].ResponseHeaderModifier
+		}
+		for _, header := range modifier.Set {
+			existing.Set = setHeader(existing.Set, string(header.Name)+":"+header.Value, false)
+		}
+		for _, header := range modifier.Add {
+			existing.Add = setHeader(existing.Add, string(header.Name)+":"+header.Value, true)
+		}
+		for _, name := range modifier.Remove {
+			existing.Remove = appendUnique(existing.Remove, name)
+		}
+		return filters
+	}
+	filter := gatewayv1.HTTPRouteFilter{Type: filterType}
+	if filterType == gatewayv1.HTTPRouteFilterResponseHeaderModifier {
+		filter.ResponseHeaderModifier = modifier
+	} else {
+		filter.RequestHeaderModifier = modifier
+	}
+	return append(filters, filter)
+}
+
+func appendUnique(names []string, name string) []string {
+	for _, existing := range names {
+		if strings.EqualFold(existing, name) {
+			return names
+		}
+	}
+	return append(names, name)
+}
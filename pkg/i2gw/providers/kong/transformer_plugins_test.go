@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kong
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestTransformerPluginsToFilters(t *testing.T) {
+	pluginRef := func(name string) gatewayv1.HTTPRouteFilter {
+		return gatewayv1.HTTPRouteFilter{
+			Type: gatewayv1.HTTPRouteFilterExtensionRef,
+			ExtensionRef: &gatewayv1.LocalObjectReference{
+				Group: kongResourcesGroup,
+				Kind:  kongPluginKind,
+				Name:  gatewayv1.ObjectName(name),
+			},
+		}
+	}
+	kongPlugin := func(name, pluginName, config string) *kongv1.KongPlugin {
+		return &kongv1.KongPlugin{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			PluginName: pluginName,
+			Config:     apiextensionsv1.JSON{Raw: []byte(config)},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		kongPlugins     []*kongv1.KongPlugin
+		filters         []gatewayv1.HTTPRouteFilter
+		expectedFilters []gatewayv1.HTTPRouteFilter
+		expectedErrors  int
+	}{
+		{
+			name: "request and response headers",
+			kongPlugins: []*kongv1.KongPlugin{
+				kongPlugin("add-headers", requestTransformerPlugin, `{"remove":{"headers":["x-internal"]},"replace":{"headers":["x-env:prod"]},"add":{"headers":["x-prefix:/api"]},"append":{"headers":["x-prefix:/v1"]}}`),
+				kongPlugin("cache-control", responseTransformerPlugin, `{"add":{"headers":["cache-control:no-store"]}}`),
+			},
+			filters: []gatewayv1.HTTPRouteFilter{pluginRef("add-headers"), pluginRef("cache-control")},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{
+				{
+					Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+					RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+						Set:    []gatewayv1.HTTPHeader{{Name: "x-env", Value: "prod"}},
+						Add:    []gatewayv1.HTTPHeader{{Name: "x-prefix", Value: "/api,/v1"}},
+						Remove: []string{"x-internal"},
+					},
+				},
+				{
+					Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+					ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+						Add: []gatewayv1.HTTPHeader{{Name: "cache-control", Value: "no-store"}},
+					},
+				},
+			},
+		},
+		{
+			name: "transformers of the same type are merged",
+			kongPlugins: []*kongv1.KongPlugin{
+				kongPlugin("first", requestTransformerPlugin, `{"add":{"headers":["x-a:1"]}}`),
+				kongPlugin("second", requestTransformerPlugin, `{"add":{"headers":["x-b:2"]},"remove":{"headers":["x-c"]}}`),
+			},
+			filters: []gatewayv1.HTTPRouteFilter{pluginRef("first"), pluginRef("second")},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+				RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+					Add:    []gatewayv1.HTTPHeader{{Name: "x-a", Value: "1"}, {Name: "x-b", Value: "2"}},
+					Remove: []string{"x-c"},
+				},
+			}},
+		},
+		{
+			name: "unsupported operations keep the plugin referenced",
+			kongPlugins: []*kongv1.KongPlugin{
+				kongPlugin("querystring", requestTransformerPlugin, `{"add":{"headers":["x-a:1"],"querystring":["version:2"]}}`),
+				kongPlugin("rate-limiting", "rate-limiting", `{"minute":5}`),
+			},
+			filters: []gatewayv1.HTTPRouteFilter{pluginRef("querystring"), pluginRef("rate-limiting"), pluginRef("missing")},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{
+				{
+					Type:                  gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+					RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{Add: []gatewayv1.HTTPHeader{{Name: "x-a", Value: "1"}}},
+				},
+				pluginRef("querystring"),
+				pluginRef("rate-limiting"),
+				pluginRef("missing"),
+			},
+		},
+		{
+			name:            "invalid configuration",
+			kongPlugins:     []*kongv1.KongPlugin{kongPlugin("invalid", requestTransformerPlugin, `{"add":{"headers":"x-a:1"}}`)},
+			filters:         []gatewayv1.HTTPRouteFilter{pluginRef("invalid")},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{pluginRef("invalid")},
+			expectedErrors:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kongPlugins := map[types.NamespacedName]*kongv1.KongPlugin{}
+			for _, kongPlugin := range tc.kongPlugins {
+				kongPlugins[types.NamespacedName{Namespace: kongPlugin.Namespace, Name: kongPlugin.Name}] = kongPlugin
+			}
+			key := types.NamespacedName{Namespace: "default", Name: "route"}
+			ir := intermediate.IR{
+				HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+					key: {HTTPRoute: gatewayv1.HTTPRoute{
+						ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+						Spec:       gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{Filters: tc.filters}}},
+					}},
+				},
+			}
+
+			errs := transformerPluginsToFilters(pluginResolver{kongPlugins: kongPlugins}, &ir)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if diff := cmp.Diff(tc.expectedFilters, ir.HTTPRoutes[key].Spec.Rules[0].Filters); diff != "" {
+				t.Errorf("unexpected filters (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netscaler
+
+import "fmt"
+
+const (
+	annotationPrefix = "ingress.citrix.com"
+)
+
+// supportedAnnotations are the ingress.citrix.com annotations converted, or
+// reported with the NetScaler parameters they hold.
+var supportedAnnotations = []string{
+	netscalerAnnotation(frontendIPKey),
+	netscalerAnnotation(sslPassthroughKey),
+	netscalerAnnotation(lbvserverKey),
+	netscalerAnnotation(servicegroupKey),
+	netscalerAnnotation(monitorKey),
+}
+
+const (
+	frontendIPKey     = "frontend-ip"
+	sslPassthroughKey = "ssl-passthrough"
+	lbvserverKey      = "lbvserver"
+	servicegroupKey   = "servicegroup"
+	monitorKey        = "monitor"
+)
+
+func netscalerAnnotation(suffix string) string {
+	return fmt.Sprintf("%s/%s", annotationPrefix, suffix)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netscaler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// resourcesToIRConverter implements the ToIR function of i2gw.ResourcesToIRConverter interface.
+type resourcesToIRConverter struct {
+	featureChain                  *i2gw.FeatureChain
+	implementationSpecificOptions i2gw.ProviderImplementationSpecificOptions
+}
+
+// newResourcesToIRConverter returns a netscaler resourcesToIRConverter instance.
+func newResourcesToIRConverter(conf *i2gw.ProviderConf) *resourcesToIRConverter {
+	return &resourcesToIRConverter{
+		featureChain: i2gw.NewFeatureChain(
+			i2gw.NamedFeatureParser{Name: "load-balancing", Parse: loadBalancingFeature},
+		).Use(conf.FeatureToggles.Middleware(Name)),
+		implementationSpecificOptions: i2gw.ProviderImplementationSpecificOptions{
+			// The list of the implementationSpecific ingress fields options comes here.
+			DisableRouteMerging: !conf.Profile.RouteMerging,
+		},
+	}
+}
+
+// convertToIR converts the Ingresses sharing a frontend IP to the Gateways of
+// that IP, as NetScaler serves them with the same content switching virtual
+// server. The Ingresses passing SSL through to their backends are converted to
+// TLSRoutes.
+func (c *resourcesToIRConverter) convertToIR(storage *storage) (intermediate.IR, field.ErrorList) {
+	ingressKeys := make([]types.NamespacedName, 0, len(storage.Ingresses))
+	for key := range storage.Ingresses {
+		ingressKeys = append(ingressKeys, key)
+	}
+	slices.SortFunc(ingressKeys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	var (
+		frontendIPs      []string
+		ingressesByIP    = map[string][]networkingv1.Ingress{}
+		passthroughsByIP = map[string][]networkingv1.Ingress{}
+		errs             field.ErrorList
+	)
+	for _, key := range ingressKeys {
+		ingress := *storage.Ingresses[key]
+		ip, err := frontendIP(ingress)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		notifyUnsupportedAnnotations(ingress)
+		if !slices.Contains(frontendIPs, ip) {
+			frontendIPs = append(frontendIPs, ip)
+		}
+		if isSSLPassthrough(ingress) {
+			passthroughsByIP[ip] = append(passthroughsByIP[ip], ingress)
+		} else {
+			ingressesByIP[ip] = append(ingressesByIP[ip], ingress)
+		}
+	}
+
+	var irs []intermediate.IR
+	for _, ip := range frontendIPs {
+		var ipIRs []intermediate.IR
+		if len(ingressesByIP[ip]) > 0 {
+			ir, convertErrs := c.convertIngresses(ingressesByIP[ip])
+			errs = append(errs, convertErrs...)
+			ipIRs = append(ipIRs, ir)
+		}
+		for _, ingress := range passthroughsByIP[ip] {
+			ir, passthroughErrs := sslPassthroughToIR(ingress)
+			errs = append(errs, passthroughErrs...)
+			ipIRs = append(ipIRs, ir)
+		}
+		ir, mergeErrs := intermediate.MergeIRs(ipIRs...)
+		errs = append(errs, mergeErrs...)
+		if ip != "" {
+			assignFrontendIP(&ir, ip)
+		}
+		irs = append(irs, ir)
+	}
+	if len(errs) > 0 {
+		return intermediate.IR{}, errs
+	}
+
+	return intermediate.MergeIRs(irs...)
+}
+
+// convertIngresses converts the Ingresses, with their netscaler specific
+// features, to an IR.
+func (c *resourcesToIRConverter) convertIngresses(ingressList []networkingv1.Ingress) (intermediate.IR, field.ErrorList) {
+	// Convert plain ingress resources to gateway resources, ignoring all
+	// provider-specific features.
+	ir, errs := common.ToIR(ingressList, c.implementationSpecificOptions)
+	if len(errs) > 0 {
+		return intermediate.IR{}, errs
+	}
+
+	// Apply the feature parsing functions to the gateway resources, in order.
+	errs = append(errs, c.featureChain.Run(ingressList, &ir)...)
+
+	return ir, errs
+}
+
+// notifyUnsupportedAnnotations warns about the ingress.citrix.com annotations
+// of the Ingress which aren't converted.
+func notifyUnsupportedAnnotations(ingress networkingv1.Ingress) {
+	var unsupported []string
+	for annotation := range ingress.Annotations {
+		if strings.HasPrefix(annotation, annotationPrefix+"/") && !slices.Contains(supportedAnnotations, annotation) {
+			unsupported = append(unsupported, annotation)
+		}
+	}
+	if len(unsupported) == 0 {
+		return
+	}
+	slices.Sort(unsupported)
+	notify(notifications.WarningNotification, fmt.Sprintf("annotations %v of ingress %s/%s have no Gateway API equivalent and were not converted", unsupported, ingress.Namespace, ingress.Name), &ingress)
+}
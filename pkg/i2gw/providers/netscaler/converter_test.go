@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netscaler
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func testIngress(name string, annotations map[string]string) *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To(NetscalerIngressClass),
+			Rules: []networkingv1.IngressRule{{
+				Host: name + ".example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: ptr.To(networkingv1.PathTypePrefix),
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: name,
+							Port: networkingv1.ServiceBackendPort{Number: 80},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+}
+
+func Test_convertToIR(t *testing.T) {
+	testCases := []struct {
+		name                        string
+		annotations                 map[string]string
+		expectedGateway             string
+		expectedAddress             string
+		expectedHTTPRoutes          int
+		expectedTLSRoutes           int
+		expectedUnsupportedFeatures int
+		expectedErrors              int
+	}{
+		{
+			name:               "no annotations",
+			expectedGateway:    "citrix",
+			expectedHTTPRoutes: 1,
+		},
+		{
+			name:               "frontend ip",
+			annotations:        map[string]string{"ingress.citrix.com/frontend-ip": "2001:db8::10"},
+			expectedGateway:    "citrix-2001-db8-10",
+			expectedAddress:    "2001:db8::10",
+			expectedHTTPRoutes: 1,
+		},
+		{
+			name:              "ssl passthrough",
+			annotations:       map[string]string{"ingress.citrix.com/ssl-passthrough": "true", "ingress.citrix.com/frontend-ip": "10.0.0.1"},
+			expectedGateway:   "citrix-10-0-0-1",
+			expectedAddress:   "10.0.0.1",
+			expectedTLSRoutes: 1,
+		},
+		{
+			name: "load balancing parameters",
+			annotations: map[string]string{
+				"ingress.citrix.com/lbvserver": `{"foo":{"lbmethod":"ROUNDROBIN"}}`,
+				"ingress.citrix.com/monitor":   `{"foo":{"type":"http"}}`,
+			},
+			expectedGateway:             "citrix",
+			expectedHTTPRoutes:          1,
+			expectedUnsupportedFeatures: 2,
+		},
+		{
+			name:           "invalid frontend ip",
+			annotations:    map[string]string{"ingress.citrix.com/frontend-ip": "vip"},
+			expectedErrors: 1,
+		},
+		{
+			name:           "invalid load balancing parameters",
+			annotations:    map[string]string{"ingress.citrix.com/servicegroup": "ROUNDROBIN"},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := newResourcesStorage()
+			storage.Ingresses[types.NamespacedName{Namespace: "default", Name: "foo"}] = testIngress("foo", tc.annotations)
+
+			ir, errs := newResourcesToIRConverter(&i2gw.ProviderConf{}).convertToIR(storage)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if tc.expectedErrors > 0 {
+				return
+			}
+
+			gatewayContext, ok := ir.Gateways[types.NamespacedName{Namespace: "default", Name: tc.expectedGateway}]
+			if !ok || len(ir.Gateways) != 1 {
+				t.Fatalf("expected Gateway default/%s, got %v", tc.expectedGateway, ir.Gateways)
+			}
+			if tc.expectedAddress == "" && len(gatewayContext.Spec.Addresses) > 0 {
+				t.Errorf("expected no addresses, got %v", gatewayContext.Spec.Addresses)
+			}
+			if tc.expectedAddress != "" && (len(gatewayContext.Spec.Addresses) != 1 || gatewayContext.Spec.Addresses[0].Value != tc.expectedAddress) {
+				t.Errorf("expected address %s, got %v", tc.expectedAddress, gatewayContext.Spec.Addresses)
+			}
+			if len(ir.HTTPRoutes) != tc.expectedHTTPRoutes || len(ir.TLSRoutes) != tc.expectedTLSRoutes {
+				t.Fatalf("expected %d HTTPRoutes and %d TLSRoutes, got %v and %v", tc.expectedHTTPRoutes, tc.expectedTLSRoutes, ir.HTTPRoutes, ir.TLSRoutes)
+			}
+			for key, httpRouteContext := range ir.HTTPRoutes {
+				if parentRef := httpRouteContext.Spec.ParentRefs[0]; string(parentRef.Name) != tc.expectedGateway {
+					t.Errorf("expected HTTPRoute %s to be attached to Gateway %s, got %s", key, tc.expectedGateway, parentRef.Name)
+				}
+				if len(httpRouteContext.UnsupportedFeatures) != tc.expectedUnsupportedFeatures {
+					t.Errorf("expected %d unsupported features, got %v", tc.expectedUnsupportedFeatures, httpRouteContext.UnsupportedFeatures)
+				}
+			}
+			for key, tlsRoute := range ir.TLSRoutes {
+				if parentRef := tlsRoute.Spec.ParentRefs[0]; string(parentRef.Name) != tc.expectedGateway {
+					t.Errorf("expected TLSRoute %s to be attached to Gateway %s, got %s", key, tc.expectedGateway, parentRef.Name)
+				}
+			}
+		})
+	}
+}
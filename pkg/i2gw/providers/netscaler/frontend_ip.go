@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netscaler
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// frontendIP returns the virtual IP the Ingress is exposed on, set by the
+// ingress.citrix.com/frontend-ip annotation, if any.
+func frontendIP(ingress networkingv1.Ingress) (string, *field.Error) {
+	annotation := netscalerAnnotation(frontendIPKey)
+	ip, ok := ingress.Annotations[annotation]
+	if !ok {
+		return "", nil
+	}
+	if net.ParseIP(ip) == nil {
+		return "", field.Invalid(field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations").Key(annotation), ip, "must be an IP address")
+	}
+	return ip, nil
+}
+
+// frontendGatewayName returns the name of the Gateway of the given name
+// serving the Ingresses exposed on the frontend IP.
+func frontendGatewayName(gatewayName, ip string) string {
+	return fmt.Sprintf("%s-%s", gatewayName, common.NameFromHost(ip))
+}
+
+// assignFrontendIP renames the Gateways of the IR generated from the Ingresses
+// exposed on the frontend IP after it, requesting the IP as their address, and
+// renames the parentRefs of the routes accordingly.
+func assignFrontendIP(ir *intermediate.IR, ip string) {
+	renamed := map[types.NamespacedName]types.NamespacedName{}
+	for key, gatewayContext := range ir.Gateways {
+		frontendKey := types.NamespacedName{Namespace: key.Namespace, Name: frontendGatewayName(key.Name, ip)}
+		gatewayContext.Name = frontendKey.Name
+		gatewayContext.Spec.Addresses = []gatewayv1.GatewayAddress{{Type: ptr.To(gatewayv1.IPAddressType), Value: ip}}
+		delete(ir.Gateways, key)
+		ir.Gateways[frontendKey] = gatewayContext
+		renamed[key] = frontendKey
+		notify(notifications.InfoNotification, fmt.Sprintf("generated Gateway %s for the ingresses of frontend IP %s", frontendKey, ip), &gatewayContext.Gateway)
+	}
+
+	renameParentRefs := func(namespace string, parentRefs []gatewayv1.ParentReference) {
+		for i, parentRef := range parentRefs {
+			parentNamespace := namespace
+			if parentRef.Namespace != nil {
+				parentNamespace = string(*parentRef.Namespace)
+			}
+			if frontendKey, ok := renamed[types.NamespacedName{Namespace: parentNamespace, Name: string(parentRef.Name)}]; ok {
+				parentRefs[i].Name = gatewayv1.ObjectName(frontendKey.Name)
+			}
+		}
+	}
+	for key, httpRouteContext := range ir.HTTPRoutes {
+		renameParentRefs(key.Namespace, httpRouteContext.Spec.ParentRefs)
+	}
+	for key, tlsRoute := range ir.TLSRoutes {
+		renameParentRefs(key.Namespace, tlsRoute.Spec.ParentRefs)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netscaler
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// loadBalancingAnnotations hold the parameters of the NetScaler entities
+// load balancing the requests to each Service, as JSON objects keyed by
+// Service name, e.g. {"frontend": {"lbmethod": "LEASTCONNECTION"}}.
+var loadBalancingAnnotations = []string{
+	netscalerAnnotation(lbvserverKey),
+	netscalerAnnotation(servicegroupKey),
+	netscalerAnnotation(monitorKey),
+}
+
+// loadBalancingFeature reports the load balancing parameters of the
+// Ingresses, which require NetScaler-specific policies, with a warning per
+// Service, and records them as unsupported features of the HTTPRoutes
+// generated from the annotated Ingresses, so that they can be ported manually.
+func loadBalancingFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	var errs field.ErrorList
+	for _, ingress := range ingresses {
+		for _, annotation := range loadBalancingAnnotations {
+			value, ok := ingress.Annotations[annotation]
+			if !ok {
+				continue
+			}
+			var parametersByService map[string]map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(value), &parametersByService); err != nil {
+				errs = append(errs, field.Invalid(field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations").Key(annotation), value, fmt.Sprintf("must be a JSON object of the parameters by Service: %v", err)))
+				continue
+			}
+			for _, service := range sortedKeys(parametersByService) {
+				notify(notifications.WarningNotification, fmt.Sprintf("%q annotation of ingress %s/%s sets the parameters %v of Service %s, which require NetScaler-specific policies", annotation, ingress.Namespace, ingress.Name, sortedKeys(parametersByService[service]), service), &ingress)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	for _, rg := range common.GetRuleGroups(ingresses, ir) {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRouteContext, ok := ir.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			for _, annotation := range loadBalancingAnnotations {
+				value, ok := rule.Ingress.Annotations[annotation]
+				if !ok {
+					continue
+				}
+				feature := intermediate.UnsupportedFeature{
+					SourceKind: "Ingress",
+					Source:     types.NamespacedName{Namespace: rule.Ingress.Namespace, Name: rule.Ingress.Name},
+					Name:       annotation,
+					RawConfig:  value,
+				}
+				// An Ingress may have several rules for the same host.
+				if !slices.Contains(httpRouteContext.UnsupportedFeatures, feature) {
+					httpRouteContext.UnsupportedFeatures = append(httpRouteContext.UnsupportedFeatures, feature)
+				}
+			}
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+	return nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nginx
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// annotationDomain is the domain of the annotations of the NGINX Ingress
+	// Controller.
+	annotationDomain = "nginx.org"
+
+	mergeableIngressTypeAnnotation = "nginx.org/mergeable-ingress-type"
+)
+
+// annotationsFeature warns about the nginx.org annotations of the Ingresses,
+// other than the mergeable Ingress type, which are not converted.
+func annotationsFeature(ingresses []networkingv1.Ingress, _ *intermediate.IR) field.ErrorList {
+	for _, ingress := range ingresses {
+		annotations := make([]string, 0, len(ingress.Annotations))
+		for annotation := range ingress.Annotations {
+			annotations = append(annotations, annotation)
+		}
+		slices.Sort(annotations)
+		for _, annotation := range annotations {
+			if annotation == mergeableIngressTypeAnnotation || !strings.HasPrefix(annotation, annotationDomain+"/") {
+				continue
+			}
+			notify(notifications.WarningNotification, fmt.Sprintf("%q annotation of ingress %s/%s is not supported, it was not converted", annotation, ingress.Namespace, ingress.Name), &ingress)
+		}
+	}
+	return nil
+}
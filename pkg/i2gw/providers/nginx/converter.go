@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nginx
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// resourcesToIRConverter implements the ToIR function of i2gw.ResourcesToIRConverter interface.
+type resourcesToIRConverter struct {
+	featureChain                  *i2gw.FeatureChain
+	implementationSpecificOptions i2gw.ProviderImplementationSpecificOptions
+}
+
+// newResourcesToIRConverter returns an nginx resourcesToIRConverter instance.
+func newResourcesToIRConverter(conf *i2gw.ProviderConf) *resourcesToIRConverter {
+	return &resourcesToIRConverter{
+		featureChain: i2gw.NewFeatureChain(
+			i2gw.NamedFeatureParser{Name: "annotations", Parse: annotationsFeature},
+		).Use(conf.FeatureToggles.Middleware(Name)),
+		implementationSpecificOptions: i2gw.ProviderImplementationSpecificOptions{
+			DisableRouteMerging: !conf.Profile.RouteMerging,
+		},
+	}
+}
+
+func (c *resourcesToIRConverter) convert(storage *storage) (intermediate.IR, field.ErrorList) {
+	ingressList := make([]networkingv1.Ingress, 0, len(storage.Ingresses))
+	for _, ingress := range storage.Ingresses {
+		ingressList = append(ingressList, *ingress)
+	}
+	slices.SortFunc(ingressList, func(a, b networkingv1.Ingress) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+
+	// The minions are converted as part of the Ingresses of their masters,
+	// holding their paths.
+	mergeables, errorList := mergeMinions(ingressList)
+	if len(errorList) > 0 {
+		return intermediate.IR{}, errorList
+	}
+
+	// Convert plain ingress resources to gateway resources, ignoring all
+	// provider-specific features.
+	ir, errorList := common.ToIR(mergeables.ingresses(), c.implementationSpecificOptions)
+	if len(errorList) > 0 {
+		return intermediate.IR{}, errorList
+	}
+	mergeables.setSources(&ir)
+
+	// Apply the feature parsing functions to the gateway resources, in order.
+	errorList = append(errorList, c.featureChain.Run(ingressList, &ir)...)
+
+	return ir, errorList
+}
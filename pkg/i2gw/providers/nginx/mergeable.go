@@ -0,0 +1,212 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nginx
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// masterIngressType is the mergeable Ingress type of the Ingresses
+	// holding the host, TLS and server configuration of a host, the paths of
+	// which are held by its minions.
+	masterIngressType = "master"
+	// minionIngressType is the mergeable Ingress type of the Ingresses
+	// holding paths of the host of a master.
+	minionIngressType = "minion"
+)
+
+// mergeableIngresses holds the Ingresses to convert, in which the minions are
+// merged into their masters.
+type mergeableIngresses struct {
+	converted []networkingv1.Ingress
+
+	// masters are the master Ingresses, by key, as read.
+	masters map[types.NamespacedName]*networkingv1.Ingress
+	// minions are the minion Ingresses merged into each master, by key of
+	// the master.
+	minions map[types.NamespacedName][]mergedMinion
+}
+
+// mergedMinion is a minion Ingress, along with its rule holding the paths
+// merged into its master.
+type mergedMinion struct {
+	ingress *networkingv1.Ingress
+	rule    networkingv1.IngressRule
+}
+
+// mergeMinions merges the paths of the minion Ingresses into the master
+// Ingress of their host in their namespace, the way the NGINX Ingress
+// Controller serves them from a single server.
+func mergeMinions(ingresses []networkingv1.Ingress) (*mergeableIngresses, field.ErrorList) {
+	m := &mergeableIngresses{
+		masters: map[types.NamespacedName]*networkingv1.Ingress{},
+		minions: map[types.NamespacedName][]mergedMinion{},
+	}
+	var errs field.ErrorList
+
+	// masterByHost holds the index of the converted master of each host, by
+	// namespace and host.
+	masterByHost := map[types.NamespacedName]int{}
+	var minions []*networkingv1.Ingress
+	for i := range ingresses {
+		ingress := &ingresses[i]
+		key := types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}
+		ingressPath := field.NewPath(Name, "Ingress").Key(key.String())
+
+		switch ingressType := ingress.Annotations[mergeableIngressTypeAnnotation]; ingressType {
+		case "":
+			m.converted = append(m.converted, *ingress)
+		case masterIngressType:
+			host, err := mergeableHost(ingress, ingressPath)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			hostKey := types.NamespacedName{Namespace: ingress.Namespace, Name: host}
+			if _, ok := masterByHost[hostKey]; ok {
+				errs = append(errs, field.Duplicate(ingressPath.Child("spec", "rules").Index(0).Child("host"), host))
+				continue
+			}
+			master := ingress.DeepCopy()
+			if http := master.Spec.Rules[0].HTTP; http != nil && len(http.Paths) > 0 {
+				notify(notifications.WarningNotification, fmt.Sprintf("master ingress %s/%s has paths, which the NGINX Ingress Controller rejects, they were not converted: move them to minions", ingress.Namespace, ingress.Name), ingress)
+			}
+			master.Spec.Rules[0].HTTP = &networkingv1.HTTPIngressRuleValue{}
+			masterByHost[hostKey] = len(m.converted)
+			m.masters[key] = ingress
+			m.converted = append(m.converted, *master)
+		case minionIngressType:
+			if _, err := mergeableHost(ingress, ingressPath); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			minions = append(minions, ingress)
+		default:
+			errs = append(errs, field.NotSupported(ingressPath.Child("metadata", "annotations").Key(mergeableIngressTypeAnnotation), ingressType, []string{masterIngressType, minionIngressType}))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	// mergedPaths holds the minion each merged path comes from, by path and
+	// path type, per master, the first minion of a path taking precedence.
+	mergedPaths := map[int]map[mergedPath]*networkingv1.Ingress{}
+	for _, minion := range minions {
+		rule := minion.Spec.Rules[0]
+		i, ok := masterByHost[types.NamespacedName{Namespace: minion.Namespace, Name: rule.Host}]
+		if !ok {
+			notify(notifications.WarningNotification, fmt.Sprintf("minion ingress %s/%s has no master ingress of host %s in its namespace, the NGINX Ingress Controller ignores it, it was not converted", minion.Namespace, minion.Name, rule.Host), minion)
+			continue
+		}
+		master := &m.converted[i]
+		if len(minion.Spec.TLS) > 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("the TLS of minion ingress %s/%s was ignored, the TLS of master ingress %s/%s applies to its host", minion.Namespace, minion.Name, master.Namespace, master.Name), minion)
+		}
+		if mergedPaths[i] == nil {
+			mergedPaths[i] = map[mergedPath]*networkingv1.Ingress{}
+		}
+		merged := mergedMinion{ingress: minion, rule: networkingv1.IngressRule{Host: rule.Host}}
+		if rule.HTTP != nil {
+			merged.rule.HTTP = &networkingv1.HTTPIngressRuleValue{}
+			for _, path := range rule.HTTP.Paths {
+				key := mergedPath{path: path.Path, pathType: ptr.Deref(path.PathType, networkingv1.PathTypeImplementationSpecific)}
+				if other, ok := mergedPaths[i][key]; ok {
+					notify(notifications.WarningNotification, fmt.Sprintf("%s path %s of minion ingress %s/%s is already defined by minion ingress %s/%s, it was ignored", key.pathType, path.Path, minion.Namespace, minion.Name, other.Namespace, other.Name), minion)
+					continue
+				}
+				mergedPaths[i][key] = minion
+				merged.rule.HTTP.Paths = append(merged.rule.HTTP.Paths, path)
+				master.Spec.Rules[0].HTTP.Paths = append(master.Spec.Rules[0].HTTP.Paths, path)
+			}
+		}
+		masterKey := types.NamespacedName{Namespace: master.Namespace, Name: master.Name}
+		m.minions[masterKey] = append(m.minions[masterKey], merged)
+	}
+
+	return m, nil
+}
+
+// mergedPath identifies a path merged from a minion Ingress.
+type mergedPath struct {
+	path     string
+	pathType networkingv1.PathType
+}
+
+// mergeableHost returns the host of the master or minion Ingress, which must
+// have a single rule with a host.
+func mergeableHost(ingress *networkingv1.Ingress, ingressPath *field.Path) (string, *field.Error) {
+	rulesPath := ingressPath.Child("spec", "rules")
+	if len(ingress.Spec.Rules) != 1 {
+		return "", field.Invalid(rulesPath, len(ingress.Spec.Rules), fmt.Sprintf("a %s ingress must have a single rule", ingress.Annotations[mergeableIngressTypeAnnotation]))
+	}
+	if ingress.Spec.Rules[0].Host == "" {
+		return "", field.Required(rulesPath.Index(0).Child("host"), fmt.Sprintf("a %s ingress must have a host", ingress.Annotations[mergeableIngressTypeAnnotation]))
+	}
+	return ingress.Spec.Rules[0].Host, nil
+}
+
+// ingresses returns the Ingresses to convert.
+func (m *mergeableIngresses) ingresses() []networkingv1.Ingress {
+	return m.converted
+}
+
+// setSources sets the master Ingresses and their minions as the sources of
+// the HTTPRoutes generated from the masters, and reports the rules of the
+// HTTPRoutes generated from the paths of each minion.
+func (m *mergeableIngresses) setSources(ir *intermediate.IR) {
+	routeKeys := make([]types.NamespacedName, 0, len(ir.HTTPRoutes))
+	for key := range ir.HTTPRoutes {
+		routeKeys = append(routeKeys, key)
+	}
+	slices.SortFunc(routeKeys, func(a, b types.NamespacedName) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+
+	for _, routeKey := range routeKeys {
+		httpRouteContext := ir.HTTPRoutes[routeKey]
+		var sources []client.Object
+		for _, source := range httpRouteContext.Sources {
+			sourceKey := types.NamespacedName{Namespace: source.GetNamespace(), Name: source.GetName()}
+			master, ok := m.masters[sourceKey]
+			if !ok {
+				sources = append(sources, source)
+				continue
+			}
+			sources = append(sources, master)
+			for _, minion := range m.minions[sourceKey] {
+				sources = append(sources, minion.ingress)
+				ruleIndices := common.RuleIndicesForIngressRule(httpRouteContext.HTTPRoute, minion.rule)
+				notify(notifications.InfoNotification, fmt.Sprintf("merged the paths of minion ingress %s/%s into the rules %v of HTTPRoute %s of master ingress %s/%s", minion.ingress.Namespace, minion.ingress.Name, ruleIndices, routeKey, master.Namespace, master.Name), minion.ingress)
+			}
+		}
+		httpRouteContext.Sources = sources
+		ir.HTTPRoutes[routeKey] = httpRouteContext
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func mergeableIngress(namespace, name, ingressType, host string, paths ...string) *networkingv1.Ingress {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        name,
+			Annotations: map[string]string{mergeableIngressTypeAnnotation: ingressType},
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To(NginxIngressClass),
+			Rules:            []networkingv1.IngressRule{{Host: host}},
+		},
+	}
+	if len(paths) > 0 {
+		ingress.Spec.Rules[0].HTTP = &networkingv1.HTTPIngressRuleValue{}
+	}
+	for _, path := range paths {
+		ingress.Spec.Rules[0].HTTP.Paths = append(ingress.Spec.Rules[0].HTTP.Paths, networkingv1.HTTPIngressPath{
+			Path:     path,
+			PathType: ptr.To(networkingv1.PathTypePrefix),
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: name, Port: networkingv1.ServiceBackendPort{Number: 80}},
+			},
+		})
+	}
+	return ingress
+}
+
+func Test_convertMergeableIngresses(t *testing.T) {
+	exact := func(ingress *networkingv1.Ingress) *networkingv1.Ingress {
+		for i := range ingress.Spec.Rules[0].HTTP.Paths {
+			ingress.Spec.Rules[0].HTTP.Paths[i].PathType = ptr.To(networkingv1.PathTypeExact)
+		}
+		return ingress
+	}
+	testCases := []struct {
+		name            string
+		ingresses       []*networkingv1.Ingress
+		expectedRules   map[types.NamespacedName][]string
+		expectedSources map[types.NamespacedName][]string
+		expectedErrors  int
+	}{
+		{
+			name: "minions merged into the route of their master",
+			ingresses: []*networkingv1.Ingress{
+				mergeableIngress("cafe", "master", masterIngressType, "cafe.example.com"),
+				mergeableIngress("cafe", "coffee", minionIngressType, "cafe.example.com", "/coffee"),
+				mergeableIngress("cafe", "tea", minionIngressType, "cafe.example.com", "/tea", "/coffee"),
+			},
+			expectedRules: map[types.NamespacedName][]string{
+				{Namespace: "cafe", Name: "master-cafe-example-com"}: {"/coffee", "/tea"},
+			},
+			expectedSources: map[types.NamespacedName][]string{
+				{Namespace: "cafe", Name: "master-cafe-example-com"}: {"cafe/master", "cafe/coffee", "cafe/tea"},
+			},
+		},
+		{
+			name: "same path of another path type",
+			ingresses: []*networkingv1.Ingress{
+				mergeableIngress("cafe", "master", masterIngressType, "cafe.example.com"),
+				mergeableIngress("cafe", "coffee", minionIngressType, "cafe.example.com", "/coffee"),
+				exact(mergeableIngress("cafe", "espresso", minionIngressType, "cafe.example.com", "/coffee")),
+			},
+			expectedRules: map[types.NamespacedName][]string{
+				{Namespace: "cafe", Name: "master-cafe-example-com"}: {"/coffee", "/coffee"},
+			},
+			expectedSources: map[types.NamespacedName][]string{
+				{Namespace: "cafe", Name: "master-cafe-example-com"}: {"cafe/master", "cafe/coffee", "cafe/espresso"},
+			},
+		},
+		{
+			name: "minions of another namespace or host are not converted",
+			ingresses: []*networkingv1.Ingress{
+				mergeableIngress("cafe", "master", masterIngressType, "cafe.example.com"),
+				mergeableIngress("bar", "juice", minionIngressType, "cafe.example.com", "/juice"),
+				mergeableIngress("cafe", "water", minionIngressType, "bar.example.com", "/water"),
+			},
+			expectedRules: map[types.NamespacedName][]string{
+				{Namespace: "cafe", Name: "master-cafe-example-com"}: nil,
+			},
+			expectedSources: map[types.NamespacedName][]string{
+				{Namespace: "cafe", Name: "master-cafe-example-com"}: {"cafe/master"},
+			},
+		},
+		{
+			name: "paths of masters are not converted",
+			ingresses: []*networkingv1.Ingress{
+				mergeableIngress("cafe", "master", masterIngressType, "cafe.example.com", "/"),
+				mergeableIngress("cafe", "coffee", minionIngressType, "cafe.example.com", "/coffee"),
+			},
+			expectedRules: map[types.NamespacedName][]string{
+				{Namespace: "cafe", Name: "master-cafe-example-com"}: {"/coffee"},
+			},
+			expectedSources: map[types.NamespacedName][]string{
+				{Namespace: "cafe", Name: "master-cafe-example-com"}: {"cafe/master", "cafe/coffee"},
+			},
+		},
+		{
+			name: "masters of the same host",
+			ingresses: []*networkingv1.Ingress{
+				mergeableIngress("cafe", "master", masterIngressType, "cafe.example.com"),
+				mergeableIngress("cafe", "other", masterIngressType, "cafe.example.com"),
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "minion without host",
+			ingresses: []*networkingv1.Ingress{
+				mergeableIngress("cafe", "coffee", minionIngressType, "", "/coffee"),
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "unknown mergeable ingress type",
+			ingresses: []*networkingv1.Ingress{
+				mergeableIngress("cafe", "coffee", "servant", "cafe.example.com", "/coffee"),
+			},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := newResourcesStorage()
+			for _, ingress := range tc.ingresses {
+				storage.Ingresses[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = ingress
+			}
+
+			// The minions are merged into their masters whatever the route
+			// merging of the profile, disabled here.
+			ir, errs := newResourcesToIRConverter(&i2gw.ProviderConf{}).convert(storage)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if tc.expectedErrors > 0 {
+				return
+			}
+
+			rules := map[types.NamespacedName][]string{}
+			sources := map[types.NamespacedName][]string{}
+			for key, httpRouteContext := range ir.HTTPRoutes {
+				rules[key] = nil
+				for _, rule := range httpRouteContext.Spec.Rules {
+					for _, match := range rule.Matches {
+						rules[key] = append(rules[key], *match.Path.Value)
+					}
+				}
+				for _, source := range httpRouteContext.Sources {
+					sources[key] = append(sources[key], types.NamespacedName{Namespace: source.GetNamespace(), Name: source.GetName()}.String())
+				}
+			}
+			if diff := cmp.Diff(tc.expectedRules, rules); diff != "" {
+				t.Errorf("unexpected rule paths (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedSources, sources); diff != "" {
+				t.Errorf("unexpected sources (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nginx
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/ingressnginx"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func Test_claimWithIngressNginx(t *testing.T) {
+	providers := []string{ingressnginx.Name, Name}
+	claimer, err := i2gw.NewIngressClaimer(providers, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating the claimer: %v", err)
+	}
+
+	plain := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "plain"},
+		Spec:       networkingv1.IngressSpec{IngressClassName: ptr.To(NginxIngressClass)},
+	}
+	testCases := []struct {
+		name             string
+		ingress          *networkingv1.Ingress
+		expectedClaimant i2gw.ProviderName
+	}{
+		{
+			name:             "plain ingress of the shared class",
+			ingress:          plain,
+			expectedClaimant: ingressnginx.Name,
+		},
+		{
+			name:             "mergeable master",
+			ingress:          mergeableIngress("default", "cafe-master", "master", "cafe.example.com"),
+			expectedClaimant: Name,
+		},
+		{
+			name:             "mergeable minion",
+			ingress:          mergeableIngress("default", "tea-minion", "minion", "cafe.example.com", "/tea"),
+			expectedClaimant: Name,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var claimants []i2gw.ProviderName
+			for _, provider := range providers {
+				if _, ok := claimer.Claim(i2gw.ProviderName(provider), tc.ingress.DeepCopy()); ok {
+					claimants = append(claimants, i2gw.ProviderName(provider))
+				}
+			}
+			if len(claimants) != 1 || claimants[0] != tc.expectedClaimant {
+				t.Errorf("Expected the ingress to be claimed by the %s provider only, got %v", tc.expectedClaimant, claimants)
+			}
+		})
+	}
+}
@@ -283,7 +283,11 @@ func (c *resourcesToIRConverter) toListener(protocolAndHostname string, _ int) g
 	listener := gatewayv1.Listener{
 		Name:     name,
 		Protocol: gatewayv1.ProtocolType(strings.ToUpper(protocol)),
-		Hostname: common.PtrTo(gatewayv1.Hostname(hostname)),
+	}
+	// the wildcard hostname is not a valid listener hostname, all the hosts are
+	// matched by omitting it
+	if !common.MatchesAllHosts(hostname) {
+		listener.Hostname = common.PtrTo(gatewayv1.Hostname(hostname))
 	}
 
 	switch protocol {
@@ -357,7 +361,8 @@ func (c *resourcesToIRConverter) toHTTPRoute(name, gatewayName string, listenerN
 	if c.namespace != "" {
 		route.SetNamespace(c.namespace)
 	}
-	if len(hostnames) > 1 || !slices.Contains(hostnames, HostWildcard) {
+	// routes matching all the hosts have no hostnames
+	if !slices.Contains(hostnames, HostWildcard) {
 		route.Spec.Hostnames = lo.Map(hostnames, toGatewayAPIHostname)
 	}
 	return route
@@ -39,6 +39,7 @@ const (
 
 func init() {
 	i2gw.ProviderConstructorByName[ProviderName] = NewProvider
+	i2gw.RegisterProviderSupportLevel(ProviderName, i2gw.StableSupportLevel)
 
 	i2gw.RegisterProviderSpecificFlag(ProviderName, i2gw.ProviderSpecificFlag{
 		Name:        BackendFlag,
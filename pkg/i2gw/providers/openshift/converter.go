@@ -0,0 +1,434 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+)
+
+// defaultWeight is the weight of the Route backends without explicit weight.
+const defaultWeight = 100
+
+type resourcesToIRConverter struct {
+	gatewayClassName        string
+	wellKnownCACertificates gatewayv1alpha3.WellKnownCACertificatesType
+}
+
+func newResourcesToIRConverter(conf *i2gw.ProviderConf) resourcesToIRConverter {
+	converter := resourcesToIRConverter{
+		gatewayClassName:        DefaultGatewayClassName,
+		wellKnownCACertificates: conf.BackendTLSWellKnownCACertificates,
+	}
+	if gatewayClassName := conf.ProviderSpecificFlags[ProviderName][GatewayClassFlag]; gatewayClassName != "" {
+		converter.gatewayClassName = gatewayClassName
+	}
+	return converter
+}
+
+// routeConversion holds the state of the conversion of the Routes: the IR
+// being built, and the listeners of its Gateways.
+type routeConversion struct {
+	ir        intermediate.IR
+	services  map[types.NamespacedName]*apiv1.Service
+	listeners map[types.NamespacedName][]gatewayv1.Listener
+}
+
+func (c *resourcesToIRConverter) convertToIR(storage *storage) (intermediate.IR, field.ErrorList) {
+	conversion := routeConversion{
+		ir: intermediate.IR{
+			Gateways:           make(map[types.NamespacedName]intermediate.GatewayContext),
+			HTTPRoutes:         make(map[types.NamespacedName]intermediate.HTTPRouteContext),
+			TLSRoutes:          make(map[types.NamespacedName]gatewayv1alpha2.TLSRoute),
+			BackendTLSPolicies: make(map[types.NamespacedName]gatewayv1alpha3.BackendTLSPolicy),
+		},
+		services:  storage.Services,
+		listeners: make(map[types.NamespacedName][]gatewayv1.Listener),
+	}
+
+	routeKeys := make([]types.NamespacedName, 0, len(storage.Routes))
+	for key := range storage.Routes {
+		routeKeys = append(routeKeys, key)
+	}
+	slices.SortFunc(routeKeys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	var errs field.ErrorList
+	for _, key := range routeKeys {
+		errs = append(errs, c.convertRoute(storage.Routes[key], &conversion)...)
+	}
+
+	for gatewayKey, listeners := range conversion.listeners {
+		gateway := gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: gatewayKey.Namespace, Name: gatewayKey.Name},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: gatewayv1.ObjectName(c.gatewayClassName),
+				Listeners:        listeners,
+			},
+		}
+		gateway.SetGroupVersionKind(common.GatewayGVK)
+		conversion.ir.Gateways[gatewayKey] = intermediate.GatewayContext{Gateway: gateway}
+	}
+
+	return conversion.ir, errs
+}
+
+func (c *resourcesToIRConverter) convertRoute(route *Route, conversion *routeConversion) field.ErrorList {
+	routePath := field.NewPath(ProviderName, "Route").Key(types.NamespacedName{Namespace: route.Namespace, Name: route.Name}.String())
+	specPath := routePath.Child("spec")
+
+	backendRefs, errs := routeBackendRefs(route, conversion.services, specPath)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	hostname := routeHostname(route)
+	gatewayKey := types.NamespacedName{Namespace: route.Namespace, Name: c.gatewayClassName}
+	var listenerNamePrefix string
+	if hostname != nil {
+		listenerNamePrefix = fmt.Sprintf("%s-", common.NameFromHost(string(*hostname)))
+	}
+	parentRef := func(listener gatewayv1.Listener) gatewayv1.ParentReference {
+		return gatewayv1.ParentReference{Name: gatewayv1.ObjectName(gatewayKey.Name), SectionName: ptr.To(listener.Name)}
+	}
+	httpListener := gatewayv1.Listener{
+		Name:     gatewayv1.SectionName(fmt.Sprintf("%shttp", listenerNamePrefix)),
+		Hostname: hostname,
+		Port:     80,
+		Protocol: gatewayv1.HTTPProtocolType,
+	}
+
+	var tls TLSConfig
+	if route.Spec.TLS != nil {
+		tls = *route.Spec.TLS
+	}
+	switch tls.Termination {
+	case "":
+		conversion.addListener(gatewayKey, httpListener, route)
+		conversion.addHTTPRoute(route, route.Name, []gatewayv1.ParentReference{parentRef(httpListener)}, hostname, gatewayv1.HTTPRouteRule{BackendRefs: toHTTPBackendRefs(backendRefs)})
+
+	case TLSTerminationEdge, TLSTerminationReencrypt:
+		httpsListener := gatewayv1.Listener{
+			Name:     gatewayv1.SectionName(fmt.Sprintf("%shttps", listenerNamePrefix)),
+			Hostname: hostname,
+			Port:     443,
+			Protocol: gatewayv1.HTTPSProtocolType,
+			TLS: &gatewayv1.GatewayTLSConfig{
+				Mode:            ptr.To(gatewayv1.TLSModeTerminate),
+				CertificateRefs: []gatewayv1.SecretObjectReference{routeCertificateRef(route)},
+			},
+		}
+		conversion.addListener(gatewayKey, httpsListener, route)
+		parentRefs := []gatewayv1.ParentReference{parentRef(httpsListener)}
+		switch tls.InsecureEdgeTerminationPolicy {
+		case "", InsecureEdgeTerminationPolicyNone:
+		case InsecureEdgeTerminationPolicyAllow:
+			conversion.addListener(gatewayKey, httpListener, route)
+			parentRefs = append(parentRefs, parentRef(httpListener))
+		case InsecureEdgeTerminationPolicyRedirect:
+			conversion.addListener(gatewayKey, httpListener, route)
+			conversion.addRedirectHTTPRoute(route, parentRef(httpListener), hostname)
+		default:
+			return field.ErrorList{field.NotSupported(specPath.Child("tls", "insecureEdgeTerminationPolicy"), tls.InsecureEdgeTerminationPolicy,
+				[]string{string(InsecureEdgeTerminationPolicyNone), string(InsecureEdgeTerminationPolicyAllow), string(InsecureEdgeTerminationPolicyRedirect)})}
+		}
+		conversion.addHTTPRoute(route, route.Name, parentRefs, hostname, gatewayv1.HTTPRouteRule{BackendRefs: toHTTPBackendRefs(backendRefs)})
+		if tls.Termination == TLSTerminationReencrypt {
+			c.addBackendTLSPolicies(route, backendRefs, conversion)
+		}
+
+	case TLSTerminationPassthrough:
+		tlsListener := gatewayv1.Listener{
+			Name:     gatewayv1.SectionName(fmt.Sprintf("%stls", listenerNamePrefix)),
+			Hostname: hostname,
+			Port:     443,
+			Protocol: gatewayv1.TLSProtocolType,
+			TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModePassthrough)},
+		}
+		conversion.addListener(gatewayKey, tlsListener, route)
+		switch tls.InsecureEdgeTerminationPolicy {
+		case "", InsecureEdgeTerminationPolicyNone:
+		case InsecureEdgeTerminationPolicyRedirect:
+			conversion.addListener(gatewayKey, httpListener, route)
+			conversion.addRedirectHTTPRoute(route, parentRef(httpListener), hostname)
+		default:
+			return field.ErrorList{field.NotSupported(specPath.Child("tls", "insecureEdgeTerminationPolicy"), tls.InsecureEdgeTerminationPolicy,
+				[]string{string(InsecureEdgeTerminationPolicyNone), string(InsecureEdgeTerminationPolicyRedirect)})}
+		}
+		if route.Spec.Path != "" {
+			notify(notifications.WarningNotification, fmt.Sprintf("path %s of passthrough Route %s/%s was ignored, TLS passthrough routes the traffic by hostname only", route.Spec.Path, route.Namespace, route.Name), route)
+		}
+		tlsRoute := gatewayv1alpha2.TLSRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: route.Namespace, Name: route.Name},
+			Spec: gatewayv1alpha2.TLSRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{parentRef(tlsListener)}},
+				Rules:           []gatewayv1alpha2.TLSRouteRule{{BackendRefs: backendRefs}},
+			},
+		}
+		if hostname != nil {
+			tlsRoute.Spec.Hostnames = []gatewayv1.Hostname{*hostname}
+		}
+		tlsRoute.SetGroupVersionKind(common.TLSRouteGVK)
+		conversion.ir.TLSRoutes[types.NamespacedName{Namespace: tlsRoute.Namespace, Name: tlsRoute.Name}] = tlsRoute
+		notify(notifications.InfoNotification, fmt.Sprintf("successfully converted Route %s/%s to TLSRoute %s/%s", route.Namespace, route.Name, tlsRoute.Namespace, tlsRoute.Name), route)
+
+	default:
+		return field.ErrorList{field.NotSupported(specPath.Child("tls", "termination"), tls.Termination,
+			[]string{string(TLSTerminationEdge), string(TLSTerminationPassthrough), string(TLSTerminationReencrypt)})}
+	}
+
+	return nil
+}
+
+// routeHostname returns the hostname of the Route, the wildcard hostname of
+// its domain if it matches all the subdomains of it, or nil if the router
+// generates it.
+func routeHostname(route *Route) *gatewayv1.Hostname {
+	host := route.Spec.Host
+	if host == "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("Route %s/%s has no host, the host generated by the router from its domain must be set on the generated routes and listeners", route.Namespace, route.Name), route)
+		return nil
+	}
+	if route.Spec.WildcardPolicy == WildcardPolicySubdomain {
+		if _, domain, found := strings.Cut(host, "."); found {
+			host = "*." + domain
+		}
+	}
+	return ptr.To(gatewayv1.Hostname(host))
+}
+
+// routeCertificateRef returns the reference of the Secret holding the
+// certificate of the edge or reencrypt Route. Certificates inlined in Routes,
+// or defaulting to the certificate of the router, must be moved to a Secret
+// named after the Route.
+func routeCertificateRef(route *Route) gatewayv1.SecretObjectReference {
+	if route.Spec.TLS.ExternalCertificate != nil && route.Spec.TLS.ExternalCertificate.Name != "" {
+		return gatewayv1.SecretObjectReference{Name: gatewayv1.ObjectName(route.Spec.TLS.ExternalCertificate.Name)}
+	}
+	secretName := fmt.Sprintf("%s-tls", route.Name)
+	if route.Spec.TLS.Certificate != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("the certificate of Route %s/%s is inlined, create the kubernetes.io/tls Secret %s/%s from its certificate and key", route.Namespace, route.Name, route.Namespace, secretName), route)
+	} else {
+		notify(notifications.WarningNotification, fmt.Sprintf("Route %s/%s uses the default certificate of the router, create the kubernetes.io/tls Secret %s/%s from a certificate of its host", route.Namespace, route.Name, route.Namespace, secretName), route)
+	}
+	return gatewayv1.SecretObjectReference{Name: gatewayv1.ObjectName(secretName)}
+}
+
+// routeBackendRefs returns the references of the Services of the Route, with
+// their weights when it has alternate backends.
+func routeBackendRefs(route *Route, services map[types.NamespacedName]*apiv1.Service, specPath *field.Path) ([]gatewayv1.BackendRef, field.ErrorList) {
+	var (
+		backendRefs []gatewayv1.BackendRef
+		errs        field.ErrorList
+	)
+	targets := append([]RouteTargetReference{route.Spec.To}, route.Spec.AlternateBackends...)
+	for i, target := range targets {
+		targetPath := specPath.Child("to")
+		if i > 0 {
+			targetPath = specPath.Child("alternateBackends").Index(i - 1)
+		}
+		if target.Kind != "" && target.Kind != "Service" {
+			errs = append(errs, field.NotSupported(targetPath.Child("kind"), target.Kind, []string{"Service"}))
+			continue
+		}
+
+		port, err := servicePort(route, services[types.NamespacedName{Namespace: route.Namespace, Name: target.Name}], specPath.Child("port", "targetPort"))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		backendRef := gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{Name: gatewayv1.ObjectName(target.Name), Port: ptr.To(port)},
+		}
+		weight := int32(defaultWeight)
+		if target.Weight != nil {
+			weight = *target.Weight
+		}
+		if len(targets) > 1 || weight == 0 {
+			backendRef.Weight = ptr.To(weight)
+		}
+		backendRefs = append(backendRefs, backendRef)
+	}
+	return backendRefs, errs
+}
+
+// servicePort returns the port of the Service of which endpoints have the
+// target port of the Route. Without the Service, numeric target ports are
+// assumed to be the ports of the Service.
+func servicePort(route *Route, service *apiv1.Service, targetPortPath *field.Path) (gatewayv1.PortNumber, *field.Error) {
+	if service == nil {
+		switch {
+		case route.Spec.Port == nil:
+			return 0, field.Required(targetPortPath, "must be set when the Service of the Route is not among the resources read")
+		case route.Spec.Port.TargetPort.Type == intstr.String:
+			return 0, field.Invalid(targetPortPath, route.Spec.Port.TargetPort.StrVal, "named target ports can only be resolved when the Service of the Route is among the resources read")
+		}
+		notify(notifications.InfoNotification, fmt.Sprintf("the Services of Route %s/%s are not among the resources read, their port is assumed to be the target port %d", route.Namespace, route.Name, route.Spec.Port.TargetPort.IntVal), route)
+		return gatewayv1.PortNumber(route.Spec.Port.TargetPort.IntVal), nil
+	}
+
+	if route.Spec.Port == nil {
+		if len(service.Spec.Ports) != 1 {
+			return 0, field.Required(targetPortPath, fmt.Sprintf("must be set, Service %s/%s has %d ports", service.Namespace, service.Name, len(service.Spec.Ports)))
+		}
+		return gatewayv1.PortNumber(service.Spec.Ports[0].Port), nil
+	}
+	targetPort := route.Spec.Port.TargetPort
+	for _, port := range service.Spec.Ports {
+		if targetPort.Type == intstr.String && (port.Name == targetPort.StrVal || port.TargetPort.Type == intstr.String && port.TargetPort.StrVal == targetPort.StrVal) {
+			return gatewayv1.PortNumber(port.Port), nil
+		}
+		if targetPort.Type == intstr.Int && (port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal == targetPort.IntVal || port.TargetPort.IntVal == 0 && port.TargetPort.StrVal == "" && port.Port == targetPort.IntVal) {
+			return gatewayv1.PortNumber(port.Port), nil
+		}
+	}
+	return 0, field.Invalid(targetPortPath, targetPort.String(), fmt.Sprintf("matches no port of Service %s/%s", service.Namespace, service.Name))
+}
+
+func toHTTPBackendRefs(backendRefs []gatewayv1.BackendRef) []gatewayv1.HTTPBackendRef {
+	httpBackendRefs := make([]gatewayv1.HTTPBackendRef, 0, len(backendRefs))
+	for _, backendRef := range backendRefs {
+		httpBackendRefs = append(httpBackendRefs, gatewayv1.HTTPBackendRef{BackendRef: backendRef})
+	}
+	return httpBackendRefs
+}
+
+// addListener adds the listener to the Gateway, merging the certificates of
+// the listeners of the same name.
+func (c *routeConversion) addListener(gatewayKey types.NamespacedName, listener gatewayv1.Listener, route *Route) {
+	listeners := c.listeners[gatewayKey]
+	for i, existing := range listeners {
+		if existing.Name == listener.Name {
+			if listener.TLS != nil {
+				for _, ref := range listener.TLS.CertificateRefs {
+					if !slices.Contains(existing.TLS.CertificateRefs, ref) {
+						listeners[i].TLS.CertificateRefs = append(listeners[i].TLS.CertificateRefs, ref)
+					}
+				}
+			}
+			return
+		}
+		if existing.Port == listener.Port && existing.Protocol != listener.Protocol && reflect.DeepEqual(existing.Hostname, listener.Hostname) {
+			notify(notifications.WarningNotification, fmt.Sprintf("listeners %s and %s of Gateway %s share port %d and hostname, Route %s/%s mixes TLS passthrough and termination with other Routes of its host", existing.Name, listener.Name, gatewayKey, listener.Port, route.Namespace, route.Name), route)
+		}
+	}
+	c.listeners[gatewayKey] = append(listeners, listener)
+}
+
+// addHTTPRoute adds an HTTPRoute generated from the Route, matching its path.
+func (c *routeConversion) addHTTPRoute(route *Route, name string, parentRefs []gatewayv1.ParentReference, hostname *gatewayv1.Hostname, rule gatewayv1.HTTPRouteRule) {
+	path := route.Spec.Path
+	if path == "" {
+		path = "/"
+	}
+	rule.Matches = []gatewayv1.HTTPRouteMatch{{
+		Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To(path)},
+	}}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: route.Namespace, Name: name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+			Rules:           []gatewayv1.HTTPRouteRule{rule},
+		},
+	}
+	if hostname != nil {
+		httpRoute.Spec.Hostnames = []gatewayv1.Hostname{*hostname}
+	}
+	httpRoute.SetGroupVersionKind(common.HTTPRouteGVK)
+	c.ir.HTTPRoutes[types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}] = intermediate.HTTPRouteContext{
+		HTTPRoute: httpRoute,
+		Sources:   []client.Object{route},
+	}
+	notify(notifications.InfoNotification, fmt.Sprintf("successfully converted Route %s/%s to HTTPRoute %s/%s", route.Namespace, route.Name, httpRoute.Namespace, httpRoute.Name), route)
+}
+
+// addRedirectHTTPRoute adds an HTTPRoute redirecting the insecure traffic of
+// the Route to HTTPS, as the router does with a 302 status code.
+func (c *routeConversion) addRedirectHTTPRoute(route *Route, parentRef gatewayv1.ParentReference, hostname *gatewayv1.Hostname) {
+	c.addHTTPRoute(route, fmt.Sprintf("%s-redirect", route.Name), []gatewayv1.ParentReference{parentRef}, hostname, gatewayv1.HTTPRouteRule{
+		Filters: []gatewayv1.HTTPRouteFilter{{
+			Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+			RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+				Scheme:     ptr.To("https"),
+				StatusCode: ptr.To(302),
+			},
+		}},
+	})
+}
+
+// addBackendTLSPolicies adds the BackendTLSPolicies of the Services of the
+// reencrypt Route, validated with its destination CA certificate, or with the
+// configured well-known CA certificates without it.
+func (c *resourcesToIRConverter) addBackendTLSPolicies(route *Route, backendRefs []gatewayv1.BackendRef, conversion *routeConversion) {
+	var validation gatewayv1alpha3.BackendTLSPolicyValidation
+	switch {
+	case route.Spec.TLS.DestinationCACertificate != "":
+		configMapName := fmt.Sprintf("%s-destination-ca", route.Name)
+		validation.CACertificateRefs = []gatewayv1.LocalObjectReference{{Group: "", Kind: "ConfigMap", Name: gatewayv1.ObjectName(configMapName)}}
+		notify(notifications.WarningNotification, fmt.Sprintf("the destination CA certificate of Route %s/%s is inlined, create the ConfigMap %s/%s holding it in its ca.crt key", route.Namespace, route.Name, route.Namespace, configMapName), route)
+	case c.wellKnownCACertificates != "":
+		validation.WellKnownCACertificates = ptr.To(c.wellKnownCACertificates)
+	default:
+		notify(notifications.WarningNotification, fmt.Sprintf("reencrypt Route %s/%s has no destination CA certificate, BackendTLSPolicies were not generated, set --backend-tls-well-known-ca-certificates to validate them with well-known CA certificates", route.Namespace, route.Name), route)
+		return
+	}
+
+	for _, backendRef := range backendRefs {
+		service := string(backendRef.Name)
+		validation := *validation.DeepCopy()
+		validation.Hostname = gatewayv1.PreciseHostname(fmt.Sprintf("%s.%s.svc", service, route.Namespace))
+
+		key := types.NamespacedName{Namespace: route.Namespace, Name: fmt.Sprintf("%s-backend-tls", service)}
+		policy := gatewayv1alpha3.BackendTLSPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Spec: gatewayv1alpha3.BackendTLSPolicySpec{
+				TargetRefs: []gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName{{
+					LocalPolicyTargetReference: gatewayv1alpha2.LocalPolicyTargetReference{Group: "", Kind: "Service", Name: gatewayv1.ObjectName(service)},
+				}},
+				Validation: validation,
+			},
+		}
+		policy.SetGroupVersionKind(common.BackendTLSPolicyGVK)
+
+		if existing, ok := conversion.ir.BackendTLSPolicies[key]; ok {
+			if !reflect.DeepEqual(existing.Spec, policy.Spec) {
+				notify(notifications.WarningNotification, fmt.Sprintf("conflicting destination CA certificate of Route %s/%s for Service %s was ignored", route.Namespace, route.Name, service), route)
+			}
+			continue
+		}
+		conversion.ir.BackendTLSPolicies[key] = policy
+		notify(notifications.InfoNotification, fmt.Sprintf("generated BackendTLSPolicy %s for reencrypt Route %s/%s", key, route.Namespace, route.Name), route)
+	}
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_convertToIR(t *testing.T) {
+	testCases := []struct {
+		name               string
+		route              Route
+		expectedParentRefs map[string][]gatewayv1.ParentReference
+		expectedHostnames  []gatewayv1.Hostname
+		expectedListeners  []gatewayv1.SectionName
+		expectedErrors     int
+	}{
+		{
+			name: "insecure route",
+			route: Route{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: RouteSpec{
+					Host: "app.example.com",
+					To:   RouteTargetReference{Kind: "Service", Name: "app"},
+					Port: &RoutePort{TargetPort: intstr.FromInt32(8080)},
+				},
+			},
+			expectedParentRefs: map[string][]gatewayv1.ParentReference{
+				"app": {{Name: DefaultGatewayClassName, SectionName: ptr.To[gatewayv1.SectionName]("app-example-com-http")}},
+			},
+			expectedHostnames: []gatewayv1.Hostname{"app.example.com"},
+			expectedListeners: []gatewayv1.SectionName{"app-example-com-http"},
+		},
+		{
+			name: "edge route allowing insecure traffic",
+			route: Route{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: RouteSpec{
+					Host: "app.example.com",
+					To:   RouteTargetReference{Kind: "Service", Name: "app"},
+					Port: &RoutePort{TargetPort: intstr.FromInt32(8080)},
+					TLS:  &TLSConfig{Termination: TLSTerminationEdge, InsecureEdgeTerminationPolicy: InsecureEdgeTerminationPolicyAllow},
+				},
+			},
+			expectedParentRefs: map[string][]gatewayv1.ParentReference{
+				"app": {
+					{Name: DefaultGatewayClassName, SectionName: ptr.To[gatewayv1.SectionName]("app-example-com-https")},
+					{Name: DefaultGatewayClassName, SectionName: ptr.To[gatewayv1.SectionName]("app-example-com-http")},
+				},
+			},
+			expectedHostnames: []gatewayv1.Hostname{"app.example.com"},
+			expectedListeners: []gatewayv1.SectionName{"app-example-com-https", "app-example-com-http"},
+		},
+		{
+			name: "wildcard route",
+			route: Route{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: RouteSpec{
+					Host:           "www.example.com",
+					WildcardPolicy: WildcardPolicySubdomain,
+					To:             RouteTargetReference{Kind: "Service", Name: "app"},
+					Port:           &RoutePort{TargetPort: intstr.FromInt32(8080)},
+				},
+			},
+			expectedParentRefs: map[string][]gatewayv1.ParentReference{
+				"app": {{Name: DefaultGatewayClassName, SectionName: ptr.To[gatewayv1.SectionName]("wildcard-example-com-http")}},
+			},
+			expectedHostnames: []gatewayv1.Hostname{"*.example.com"},
+			expectedListeners: []gatewayv1.SectionName{"wildcard-example-com-http"},
+		},
+		{
+			name: "unsupported backend kind",
+			route: Route{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: RouteSpec{
+					Host: "app.example.com",
+					To:   RouteTargetReference{Kind: "Deployment", Name: "app"},
+				},
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "unsupported insecure policy of passthrough route",
+			route: Route{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: RouteSpec{
+					Host: "app.example.com",
+					To:   RouteTargetReference{Kind: "Service", Name: "app"},
+					Port: &RoutePort{TargetPort: intstr.FromInt32(8443)},
+					TLS:  &TLSConfig{Termination: TLSTerminationPassthrough, InsecureEdgeTerminationPolicy: InsecureEdgeTerminationPolicyAllow},
+				},
+			},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := newResourcesStorage()
+			storage.Routes[types.NamespacedName{Namespace: tc.route.Namespace, Name: tc.route.Name}] = &tc.route
+
+			converter := newResourcesToIRConverter(&i2gw.ProviderConf{})
+			ir, errs := converter.convertToIR(storage)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if tc.expectedErrors > 0 {
+				return
+			}
+
+			parentRefs := map[string][]gatewayv1.ParentReference{}
+			for key, httpRouteContext := range ir.HTTPRoutes {
+				parentRefs[key.Name] = httpRouteContext.Spec.ParentRefs
+				if diff := cmp.Diff(tc.expectedHostnames, httpRouteContext.Spec.Hostnames); diff != "" {
+					t.Errorf("Unexpected hostnames of HTTPRoute %s (-want +got): %s", key, diff)
+				}
+				if len(httpRouteContext.Sources) != 1 {
+					t.Errorf("Expected the Route to be the source of HTTPRoute %s, got %v", key, httpRouteContext.Sources)
+				}
+			}
+			if diff := cmp.Diff(tc.expectedParentRefs, parentRefs); diff != "" {
+				t.Errorf("Unexpected parentRefs (-want +got): %s", diff)
+			}
+
+			gateway := ir.Gateways[types.NamespacedName{Namespace: "default", Name: DefaultGatewayClassName}]
+			var listeners []gatewayv1.SectionName
+			for _, listener := range gateway.Spec.Listeners {
+				listeners = append(listeners, listener.Name)
+			}
+			if diff := cmp.Diff(tc.expectedListeners, listeners); diff != "" {
+				t.Errorf("Unexpected listeners (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_servicePort(t *testing.T) {
+	service := &apiv1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: apiv1.ServiceSpec{
+			Ports: []apiv1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromInt32(8080)},
+				{Name: "metrics", Port: 9090, TargetPort: intstr.FromString("metrics")},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		port          *RoutePort
+		service       *apiv1.Service
+		expected      gatewayv1.PortNumber
+		expectedError bool
+	}{
+		{
+			name:     "numeric target port",
+			port:     &RoutePort{TargetPort: intstr.FromInt32(8080)},
+			service:  service,
+			expected: 80,
+		},
+		{
+			name:     "named port",
+			port:     &RoutePort{TargetPort: intstr.FromString("http")},
+			service:  service,
+			expected: 80,
+		},
+		{
+			name:     "named target port",
+			port:     &RoutePort{TargetPort: intstr.FromString("metrics")},
+			service:  service,
+			expected: 9090,
+		},
+		{
+			name:          "unknown target port",
+			port:          &RoutePort{TargetPort: intstr.FromInt32(8443)},
+			service:       service,
+			expectedError: true,
+		},
+		{
+			name:          "no port with several Service ports",
+			service:       service,
+			expectedError: true,
+		},
+		{
+			name:     "numeric target port without Service",
+			port:     &RoutePort{TargetPort: intstr.FromInt32(8080)},
+			expected: 8080,
+		},
+		{
+			name:          "named target port without Service",
+			port:          &RoutePort{TargetPort: intstr.FromString("http")},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			route := &Route{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}, Spec: RouteSpec{Port: tc.port}}
+			port, err := servicePort(route, tc.service, field.NewPath("spec", "port", "targetPort"))
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if port != tc.expected {
+				t.Errorf("Expected port %d, got %d", tc.expected, port)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reader implements the i2gw.CustomResourceReader interface.
+type reader struct {
+	conf *i2gw.ProviderConf
+}
+
+// newResourceReader returns a resourceReader instance.
+func newResourceReader(conf *i2gw.ProviderConf) reader {
+	return reader{
+		conf: conf,
+	}
+}
+
+func (r *reader) readResourcesFromCluster(ctx context.Context) (*storage, error) {
+	res := newResourcesStorage()
+
+	routeList := &unstructured.UnstructuredList{}
+	routeList.SetAPIVersion(APIVersion)
+	routeList.SetKind(RouteKind)
+	if err := r.conf.Client.List(ctx, routeList); err != nil {
+		return nil, fmt.Errorf("failed to list openshift routes: %w", err)
+	}
+	for _, obj := range routeList.Items {
+		route, err := routeFromUnstructured(&obj)
+		if err != nil {
+			return nil, err
+		}
+		res.Routes[types.NamespacedName{Namespace: route.Namespace, Name: route.Name}] = route
+	}
+
+	var serviceList apiv1.ServiceList
+	if err := r.conf.Client.List(ctx, &serviceList); err != nil {
+		return nil, fmt.Errorf("failed to get services from the cluster: %w", err)
+	}
+	for i, service := range serviceList.Items {
+		res.Services[types.NamespacedName{Namespace: service.Namespace, Name: service.Name}] = &serviceList.Items[i]
+	}
+
+	return res, nil
+}
+
+func (r *reader) readResourcesFromFile(filename string) (*storage, error) {
+	stream, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %v: %w", filename, err)
+	}
+
+	unstructuredObjects, err := common.ExtractObjectsFromReader(bytes.NewReader(stream), r.conf.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract objects: %w", err)
+	}
+
+	return r.readUnstructuredObjects(unstructuredObjects)
+}
+
+func (r *reader) readUnstructuredObjects(objects []*unstructured.Unstructured) (*storage, error) {
+	res := newResourcesStorage()
+
+	for _, obj := range objects {
+		switch {
+		case obj.GetAPIVersion() == APIVersion && obj.GetKind() == RouteKind:
+			route, err := routeFromUnstructured(obj)
+			if err != nil {
+				return nil, err
+			}
+			res.Routes[types.NamespacedName{Namespace: route.Namespace, Name: route.Name}] = route
+		case obj.GetAPIVersion() == "v1" && obj.GetKind() == "Service":
+			var service apiv1.Service
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &service); err != nil {
+				return nil, fmt.Errorf("failed to parse service object: %w", err)
+			}
+			res.Services[types.NamespacedName{Namespace: service.Namespace, Name: service.Name}] = &service
+		}
+	}
+
+	return res, nil
+}
+
+func routeFromUnstructured(obj *unstructured.Unstructured) (*Route, error) {
+	var route Route
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &route); err != nil {
+		return nil, fmt.Errorf("failed to parse openshift route object: %w", err)
+	}
+	return &route, nil
+}
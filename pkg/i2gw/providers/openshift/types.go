@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	APIVersion = "route.openshift.io/v1"
+	RouteKind  = "Route"
+
+	// DefaultGatewayClassName is the name of the GatewayClass of the Gateway
+	// API implementation of OpenShift.
+	DefaultGatewayClassName = "openshift-default"
+)
+
+// The types below mirror the subset of the route.openshift.io/v1 API read by
+// the provider, so that it doesn't depend on the OpenShift API module.
+
+// Route exposes a Service at a host name, so that external clients can reach
+// it by name.
+type Route struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RouteSpec `json:"spec"`
+}
+
+// RouteSpec describes the hostname or path the Route exposes, its TLS
+// configuration, and the Services backing it.
+type RouteSpec struct {
+	Host              string                 `json:"host,omitempty"`
+	Subdomain         string                 `json:"subdomain,omitempty"`
+	Path              string                 `json:"path,omitempty"`
+	To                RouteTargetReference   `json:"to"`
+	AlternateBackends []RouteTargetReference `json:"alternateBackends,omitempty"`
+	Port              *RoutePort             `json:"port,omitempty"`
+	TLS               *TLSConfig             `json:"tls,omitempty"`
+	WildcardPolicy    WildcardPolicyType     `json:"wildcardPolicy,omitempty"`
+}
+
+// RouteTargetReference specifies a Service backing the Route, and its
+// relative weight.
+type RouteTargetReference struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// RoutePort selects the target port of the endpoints of the Services.
+type RoutePort struct {
+	TargetPort intstr.IntOrString `json:"targetPort"`
+}
+
+// TLSConfig configures the TLS termination of the Route.
+type TLSConfig struct {
+	Termination                   TLSTerminationType                `json:"termination"`
+	Certificate                   string                            `json:"certificate,omitempty"`
+	Key                           string                            `json:"key,omitempty"`
+	CACertificate                 string                            `json:"caCertificate,omitempty"`
+	DestinationCACertificate      string                            `json:"destinationCACertificate,omitempty"`
+	InsecureEdgeTerminationPolicy InsecureEdgeTerminationPolicyType `json:"insecureEdgeTerminationPolicy,omitempty"`
+	ExternalCertificate           *LocalObjectReference             `json:"externalCertificate,omitempty"`
+}
+
+// LocalObjectReference references an object in the namespace of the Route.
+type LocalObjectReference struct {
+	Name string `json:"name,omitempty"`
+}
+
+type TLSTerminationType string
+
+const (
+	TLSTerminationEdge        TLSTerminationType = "edge"
+	TLSTerminationPassthrough TLSTerminationType = "passthrough"
+	TLSTerminationReencrypt   TLSTerminationType = "reencrypt"
+)
+
+type InsecureEdgeTerminationPolicyType string
+
+const (
+	InsecureEdgeTerminationPolicyNone     InsecureEdgeTerminationPolicyType = "None"
+	InsecureEdgeTerminationPolicyAllow    InsecureEdgeTerminationPolicyType = "Allow"
+	InsecureEdgeTerminationPolicyRedirect InsecureEdgeTerminationPolicyType = "Redirect"
+)
+
+type WildcardPolicyType string
+
+const (
+	WildcardPolicyNone      WildcardPolicyType = "None"
+	WildcardPolicySubdomain WildcardPolicyType = "Subdomain"
+)
+
+// DeepCopyObject implements runtime.Object, for Routes to be referenced by
+// notifications and as the sources of the generated resources.
+func (r *Route) DeepCopyObject() runtime.Object {
+	out := &Route{TypeMeta: r.TypeMeta, Spec: r.Spec}
+	r.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if r.Spec.To.Weight != nil {
+		out.Spec.To.Weight = ptrTo(*r.Spec.To.Weight)
+	}
+	if r.Spec.AlternateBackends != nil {
+		out.Spec.AlternateBackends = make([]RouteTargetReference, len(r.Spec.AlternateBackends))
+		for i, backend := range r.Spec.AlternateBackends {
+			out.Spec.AlternateBackends[i] = backend
+			if backend.Weight != nil {
+				out.Spec.AlternateBackends[i].Weight = ptrTo(*backend.Weight)
+			}
+		}
+	}
+	if r.Spec.Port != nil {
+		out.Spec.Port = ptrTo(*r.Spec.Port)
+	}
+	if r.Spec.TLS != nil {
+		tls := *r.Spec.TLS
+		if tls.ExternalCertificate != nil {
+			tls.ExternalCertificate = ptrTo(*tls.ExternalCertificate)
+		}
+		out.Spec.TLS = &tls
+	}
+	return out
+}
+
+func ptrTo[T any](a T) *T {
+	return &a
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skipper
+
+import (
+	"slices"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	annotationPrefix = "zalando.org"
+
+	// filterAnnotation holds the eskip filter chain applied to the routes of
+	// the Ingress, e.g. setRequestHeader("X-Foo", "bar") -> ratelimit(20, "1m").
+	filterAnnotation = annotationPrefix + "/skipper-filter"
+	// predicateAnnotation holds the eskip predicates the requests must match
+	// in addition to the rules of the Ingress, e.g. Method("GET").
+	predicateAnnotation = annotationPrefix + "/skipper-predicate"
+)
+
+// ingressRuleIndices returns the indices of the rules of the HTTPRoute
+// generated from the paths of each Ingress of the rule group, by Ingress.
+func ingressRuleIndices(rg common.IngressRuleGroup, httpRoute gatewayv1.HTTPRoute) map[types.NamespacedName][]int {
+	indices := map[types.NamespacedName][]int{}
+	for _, rule := range rg.Rules {
+		key := types.NamespacedName{Namespace: rule.Ingress.Namespace, Name: rule.Ingress.Name}
+		for _, i := range common.RuleIndicesForIngressRule(httpRoute, rule.IngressRule) {
+			if !slices.Contains(indices[key], i) {
+				indices[key] = append(indices[key], i)
+			}
+		}
+	}
+	for _, ruleIndices := range indices {
+		slices.Sort(ruleIndices)
+	}
+	return indices
+}
+
+// addUnsupportedCalls records the calls of the annotation of the Ingress as
+// unsupported features of the HTTPRoute, so that they can be ported manually.
+func addUnsupportedCalls(httpRouteContext *intermediate.HTTPRouteContext, ingress types.NamespacedName, annotation string, calls []eskipCall) {
+	for _, call := range calls {
+		feature := intermediate.UnsupportedFeature{
+			SourceKind: "Ingress",
+			Source:     ingress,
+			Name:       annotation,
+			RawConfig:  call.Raw,
+		}
+		if !slices.Contains(httpRouteContext.UnsupportedFeatures, feature) {
+			httpRouteContext.UnsupportedFeatures = append(httpRouteContext.UnsupportedFeatures, feature)
+		}
+	}
+}
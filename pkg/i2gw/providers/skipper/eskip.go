@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skipper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// filterSeparator separates the filters of a filter chain.
+	filterSeparator = "->"
+	// predicateSeparator separates the predicates of a route.
+	predicateSeparator = "&&"
+)
+
+// eskipCall is a filter or a predicate of an eskip expression, e.g.
+// setRequestHeader("X-Foo", "bar") or Method("GET").
+type eskipCall struct {
+	Name string
+	// Args are the arguments of the call, either strings, for the string and
+	// regular expression literals, or float64 numbers.
+	Args []interface{}
+	// Raw is the source of the call in the expression.
+	Raw string
+}
+
+// stringArg returns the i-th argument of the call, if it is a string.
+func (c eskipCall) stringArg(i int) (string, bool) {
+	if i >= len(c.Args) {
+		return "", false
+	}
+	s, ok := c.Args[i].(string)
+	return s, ok
+}
+
+// numberArg returns the i-th argument of the call, if it is a number.
+func (c eskipCall) numberArg(i int) (float64, bool) {
+	if i >= len(c.Args) {
+		return 0, false
+	}
+	n, ok := c.Args[i].(float64)
+	return n, ok
+}
+
+// parseEskipCalls parses the calls of an eskip filter chain or conjunction of
+// predicates, separated by the given separator.
+func parseEskipCalls(expression, separator string) ([]eskipCall, error) {
+	p := &eskipParser{input: expression}
+	var calls []eskipCall
+	for {
+		p.skipSpaces()
+		if p.done() {
+			if len(calls) > 0 {
+				return nil, fmt.Errorf("expected a call after %q at the end of the expression", separator)
+			}
+			return nil, nil
+		}
+		call, err := p.parseCall()
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+
+		p.skipSpaces()
+		if p.done() {
+			return calls, nil
+		}
+		if !strings.HasPrefix(p.input[p.pos:], separator) {
+			return nil, fmt.Errorf("expected %q at position %d", separator, p.pos)
+		}
+		p.pos += len(separator)
+	}
+}
+
+type eskipParser struct {
+	input string
+	pos   int
+}
+
+func (p *eskipParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *eskipParser) skipSpaces() {
+	for !p.done() && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *eskipParser) parseCall() (eskipCall, error) {
+	start := p.pos
+	for !p.done() && (isIdentifierChar(p.input[p.pos])) {
+		p.pos++
+	}
+	name := p.input[start:p.pos]
+	if name == "" {
+		return eskipCall{}, fmt.Errorf("expected a filter or predicate name at position %d", start)
+	}
+
+	p.skipSpaces()
+	if p.done() || p.input[p.pos] != '(' {
+		return eskipCall{}, fmt.Errorf("expected '(' after %q at position %d", name, p.pos)
+	}
+	p.pos++
+
+	call := eskipCall{Name: name}
+	for {
+		p.skipSpaces()
+		if p.done() {
+			return eskipCall{}, fmt.Errorf("unterminated arguments of %q", name)
+		}
+		if p.input[p.pos] == ')' && len(call.Args) == 0 {
+			p.pos++
+			break
+		}
+		arg, err := p.parseArg()
+		if err != nil {
+			return eskipCall{}, err
+		}
+		call.Args = append(call.Args, arg)
+
+		p.skipSpaces()
+		if p.done() {
+			return eskipCall{}, fmt.Errorf("unterminated arguments of %q", name)
+		}
+		if p.input[p.pos] == ')' {
+			p.pos++
+			break
+		}
+		if p.input[p.pos] != ',' {
+			return eskipCall{}, fmt.Errorf("expected ',' or ')' at position %d", p.pos)
+		}
+		p.pos++
+	}
+	call.Raw = p.input[start:p.pos]
+	return call, nil
+}
+
+func (p *eskipParser) parseArg() (interface{}, error) {
+	switch c := p.input[p.pos]; {
+	case c == '"' || c == '/':
+		return p.parseQuoted(c)
+	case c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		start := p.pos
+		for !p.done() && strings.IndexByte("+-.0123456789eE", p.input[p.pos]) >= 0 {
+			p.pos++
+		}
+		number, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", p.input[start:p.pos], start)
+		}
+		return number, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", c, p.pos)
+	}
+}
+
+// parseQuoted parses a string literal, quoted with '"', or a regular
+// expression literal, quoted with '/'. The quote can be escaped with '\'.
+func (p *eskipParser) parseQuoted(quote byte) (string, error) {
+	start := p.pos
+	p.pos++
+	var value strings.Builder
+	for !p.done() {
+		c := p.input[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return value.String(), nil
+		case c == '\\' && p.pos+1 < len(p.input):
+			next := p.input[p.pos+1]
+			// Regular expressions keep their escape sequences, but for the
+			// escaped quotes.
+			if quote == '/' && next != '/' {
+				value.WriteByte(c)
+			}
+			value.WriteByte(next)
+			p.pos += 2
+		default:
+			value.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", fmt.Errorf("unterminated literal at position %d", start)
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
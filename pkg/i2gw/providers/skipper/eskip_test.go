@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skipper
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseEskipCalls(t *testing.T) {
+	testCases := []struct {
+		name          string
+		expression    string
+		separator     string
+		expected      []eskipCall
+		expectedError bool
+	}{
+		{
+			name:       "empty",
+			expression: "  ",
+			separator:  filterSeparator,
+		},
+		{
+			name:       "filter chain",
+			expression: `setRequestHeader("X-Foo", "bar \"baz\"") -> ratelimit(20, "1m")->tee()`,
+			separator:  filterSeparator,
+			expected: []eskipCall{
+				{Name: "setRequestHeader", Args: []interface{}{"X-Foo", `bar "baz"`}, Raw: `setRequestHeader("X-Foo", "bar \"baz\"")`},
+				{Name: "ratelimit", Args: []interface{}{20.0, "1m"}, Raw: `ratelimit(20, "1m")`},
+				{Name: "tee", Raw: "tee()"},
+			},
+		},
+		{
+			name:       "predicates with regular expressions",
+			expression: `HeaderRegexp("X-Version", /^v\d+\/beta$/) && Method("GET")`,
+			separator:  predicateSeparator,
+			expected: []eskipCall{
+				{Name: "HeaderRegexp", Args: []interface{}{"X-Version", `^v\d+/beta$`}, Raw: `HeaderRegexp("X-Version", /^v\d+\/beta$/)`},
+				{Name: "Method", Args: []interface{}{"GET"}, Raw: `Method("GET")`},
+			},
+		},
+		{
+			name:          "wrong separator",
+			expression:    `Method("GET") -> Method("POST")`,
+			separator:     predicateSeparator,
+			expectedError: true,
+		},
+		{
+			name:          "trailing separator",
+			expression:    `tee() ->`,
+			separator:     filterSeparator,
+			expectedError: true,
+		},
+		{
+			name:          "unterminated string",
+			expression:    `setPath("/foo)`,
+			separator:     filterSeparator,
+			expectedError: true,
+		},
+		{
+			name:          "missing arguments",
+			expression:    `setPath`,
+			separator:     filterSeparator,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls, err := parseEskipCalls(tc.expression, tc.separator)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error: %v, got %v", tc.expectedError, err)
+			}
+			if diff := cmp.Diff(tc.expected, calls); diff != "" {
+				t.Errorf("unexpected calls (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
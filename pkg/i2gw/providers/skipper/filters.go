@@ -0,0 +1,344 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skipper
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// skipperFilters are the filters of the zalando.org/skipper-filter
+// annotation of an Ingress.
+type skipperFilters struct {
+	// filters are the HTTPRoute filters equivalent to the Skipper filters.
+	filters []gatewayv1.HTTPRouteFilter
+	// redirect is whether the requests are redirected rather than proxied to
+	// the backends.
+	redirect bool
+
+	rateLimit       *intermediate.RateLimitConfig
+	clientRateLimit *intermediate.RateLimitConfig
+
+	// unsupported are the filters without Gateway API equivalent.
+	unsupported []eskipCall
+}
+
+// filtersFeature converts the zalando.org/skipper-filter annotation of the
+// Ingresses into filters of the rules generated from their paths.
+//
+// The header filters become header modifier filters, redirectTo a request
+// redirect filter, and setPath a URL rewrite filter. The rate limits have no
+// Gateway API equivalent, and are kept in the Skipper policy of the Ingress
+// for implementation-specific emitters. The other filters are recorded as
+// unsupported features.
+func filtersFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	var errs field.ErrorList
+	filtersByIngress := map[types.NamespacedName]skipperFilters{}
+	for _, ingress := range ingresses {
+		filters, parseErrs := parseFilterAnnotation(ingress)
+		errs = append(errs, parseErrs...)
+		if filters == nil {
+			continue
+		}
+		for _, call := range filters.unsupported {
+			notify(notifications.WarningNotification, fmt.Sprintf("filter %s of ingress %s/%s has no Gateway API equivalent and must be ported manually", call.Raw, ingress.Namespace, ingress.Name), &ingress)
+		}
+		if filters.rateLimit != nil || filters.clientRateLimit != nil {
+			notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s limits the rate of the requests, which has no Gateway API equivalent: the configuration is only kept for implementation-specific emitters", ingress.Namespace, ingress.Name), &ingress)
+		}
+		filtersByIngress[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = *filters
+	}
+	if len(filtersByIngress) == 0 {
+		return errs
+	}
+
+	for _, rg := range common.GetRuleGroups(ingresses, ir) {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRouteContext, ok := ir.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for ingressKey, ruleIndices := range ingressRuleIndices(rg, httpRouteContext.HTTPRoute) {
+			filters, ok := filtersByIngress[ingressKey]
+			if !ok {
+				continue
+			}
+			for _, i := range ruleIndices {
+				rule := &httpRouteContext.Spec.Rules[i]
+				for _, filter := range filters.filters {
+					rule.Filters = append(rule.Filters, *filter.DeepCopy())
+				}
+				if filters.redirect {
+					rule.BackendRefs = nil
+				}
+			}
+			if filters.rateLimit != nil || filters.clientRateLimit != nil {
+				patchPolicy(&httpRouteContext, ingressKey.Name, ruleIndices, func(policy *intermediate.SkipperPolicy) {
+					policy.RateLimit = filters.rateLimit
+					policy.ClientRateLimit = filters.clientRateLimit
+				})
+			}
+			addUnsupportedCalls(&httpRouteContext, ingressKey, filterAnnotation, filters.unsupported)
+			notify(notifications.InfoNotification, fmt.Sprintf("parsed %q annotation of ingress %s/%s and patched the filters of HTTPRoute %s", filterAnnotation, ingressKey.Namespace, ingressKey.Name, key), &httpRouteContext.HTTPRoute)
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+	return errs
+}
+
+// patchPolicy applies the given patch function to the Skipper policy of the
+// Ingress in the HTTPRoute IR, creating the IR and the policy if needed, and
+// records the indices of the rules the policy applies to.
+func patchPolicy(httpRouteContext *intermediate.HTTPRouteContext, ingressName string, ruleIndices []int, patch func(*intermediate.SkipperPolicy)) {
+	if httpRouteContext.ProviderSpecificIR.Skipper == nil {
+		httpRouteContext.ProviderSpecificIR.Skipper = &intermediate.SkipperHTTPRouteIR{}
+	}
+	routeIR := httpRouteContext.ProviderSpecificIR.Skipper
+	if routeIR.Policies == nil {
+		routeIR.Policies = map[string]intermediate.SkipperPolicy{}
+	}
+
+	policy := routeIR.Policies[ingressName]
+	for _, i := range ruleIndices {
+		if !slices.Contains(policy.RuleIndices, i) {
+			policy.RuleIndices = append(policy.RuleIndices, i)
+		}
+	}
+	slices.Sort(policy.RuleIndices)
+	patch(&policy)
+	routeIR.Policies[ingressName] = policy
+}
+
+// parseFilterAnnotation returns the filters of the Ingress, or nil if it has
+// none.
+func parseFilterAnnotation(ingress networkingv1.Ingress) (*skipperFilters, field.ErrorList) {
+	value, ok := ingress.Annotations[filterAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	annotationPath := field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations").Key(filterAnnotation)
+	calls, err := parseEskipCalls(value, filterSeparator)
+	if err != nil {
+		return nil, field.ErrorList{field.Invalid(annotationPath, value, err.Error())}
+	}
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	var (
+		filters         skipperFilters
+		requestHeaders  gatewayv1.HTTPHeaderFilter
+		responseHeaders gatewayv1.HTTPHeaderFilter
+		urlRewrite      *gatewayv1.HTTPURLRewriteFilter
+		redirect        *gatewayv1.HTTPRequestRedirectFilter
+		errs            field.ErrorList
+	)
+	for _, call := range calls {
+		switch call.Name {
+		case "setRequestHeader", "appendRequestHeader", "dropRequestHeader",
+			"setResponseHeader", "appendResponseHeader", "dropResponseHeader":
+			headers := &requestHeaders
+			if strings.HasSuffix(call.Name, "ResponseHeader") {
+				headers = &responseHeaders
+			}
+			if !patchHeaderFilter(headers, call) {
+				// The header values can be templates, e.g. ${request.host},
+				// with no Gateway API equivalent.
+				filters.unsupported = append(filters.unsupported, call)
+			}
+		case "redirectTo":
+			if redirect != nil {
+				errs = append(errs, field.Invalid(annotationPath, value, "the requests can only be redirected once"))
+				continue
+			}
+			var err error
+			if redirect, err = toRequestRedirect(call); err != nil {
+				errs = append(errs, field.Invalid(annotationPath, value, err.Error()))
+				continue
+			}
+			if statusCode, _ := call.numberArg(0); int(statusCode) != *redirect.StatusCode {
+				notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s redirects the requests with a %d, which the Gateway API does not support: converted to a %d", ingress.Namespace, ingress.Name, int(statusCode), *redirect.StatusCode), &ingress)
+			}
+		case "setPath":
+			path, ok := call.stringArg(0)
+			if !ok || len(call.Args) != 1 {
+				errs = append(errs, field.Invalid(annotationPath, value, "setPath expects a path"))
+				continue
+			}
+			urlRewrite = &gatewayv1.HTTPURLRewriteFilter{
+				Path: &gatewayv1.HTTPPathModifier{
+					Type:            gatewayv1.FullPathHTTPPathModifier,
+					ReplaceFullPath: ptr.To(path),
+				},
+			}
+		case "ratelimit", "clientRatelimit":
+			rateLimit, ok := toRateLimit(call)
+			if !ok {
+				// The client rate limits can identify the clients with a
+				// header rather than their address.
+				filters.unsupported = append(filters.unsupported, call)
+				continue
+			}
+			if call.Name == "ratelimit" {
+				filters.rateLimit = rateLimit
+			} else {
+				filters.clientRateLimit = rateLimit
+			}
+		default:
+			filters.unsupported = append(filters.unsupported, call)
+		}
+	}
+	if redirect != nil && urlRewrite != nil {
+		errs = append(errs, field.Invalid(annotationPath, value, "redirectTo can't be combined with setPath"))
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if len(requestHeaders.Set)+len(requestHeaders.Add)+len(requestHeaders.Remove) > 0 {
+		filters.filters = append(filters.filters, gatewayv1.HTTPRouteFilter{
+			Type:                  gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+			RequestHeaderModifier: &requestHeaders,
+		})
+	}
+	if len(responseHeaders.Set)+len(responseHeaders.Add)+len(responseHeaders.Remove) > 0 {
+		filters.filters = append(filters.filters, gatewayv1.HTTPRouteFilter{
+			Type:                   gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+			ResponseHeaderModifier: &responseHeaders,
+		})
+	}
+	if urlRewrite != nil {
+		filters.filters = append(filters.filters, gatewayv1.HTTPRouteFilter{
+			Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+			URLRewrite: urlRewrite,
+		})
+	}
+	if redirect != nil {
+		filters.filters = append(filters.filters, gatewayv1.HTTPRouteFilter{
+			Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+			RequestRedirect: redirect,
+		})
+		filters.redirect = true
+	}
+	return &filters, nil
+}
+
+// patchHeaderFilter adds the header modification of the call to the header
+// filter, and returns whether it has a Gateway API equivalent.
+func patchHeaderFilter(headers *gatewayv1.HTTPHeaderFilter, call eskipCall) bool {
+	name, ok := call.stringArg(0)
+	if !ok {
+		return false
+	}
+	if strings.HasPrefix(call.Name, "drop") {
+		if len(call.Args) != 1 {
+			return false
+		}
+		headers.Remove = append(headers.Remove, name)
+		return true
+	}
+	value, ok := call.stringArg(1)
+	if !ok || len(call.Args) != 2 || strings.Contains(value, "${") {
+		return false
+	}
+	header := gatewayv1.HTTPHeader{Name: gatewayv1.HTTPHeaderName(name), Value: value}
+	if strings.HasPrefix(call.Name, "set") {
+		headers.Set = append(headers.Set, header)
+	} else {
+		headers.Add = append(headers.Add, header)
+	}
+	return true
+}
+
+// toRequestRedirect converts a redirectTo filter, e.g.
+// redirectTo(308, "https://example.com/path"), into a request redirect filter.
+// The path of the requests is kept unless the location has a path. As the
+// Gateway API only supports the 301 and 302 status codes, the permanent
+// redirects are converted to 301s and the others to 302s.
+func toRequestRedirect(call eskipCall) (*gatewayv1.HTTPRequestRedirectFilter, error) {
+	statusCode, statusOK := call.numberArg(0)
+	location, locationOK := call.stringArg(1)
+	if !statusOK || !locationOK || len(call.Args) != 2 {
+		return nil, fmt.Errorf("redirectTo expects a status code and a location")
+	}
+	redirect := &gatewayv1.HTTPRequestRedirectFilter{}
+	switch int(statusCode) {
+	case 301, 308:
+		redirect.StatusCode = ptr.To(301)
+	case 302, 303, 307:
+		redirect.StatusCode = ptr.To(302)
+	default:
+		return nil, fmt.Errorf("%v is not a redirect status code", statusCode)
+	}
+
+	target, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid location %q: %w", location, err)
+	}
+	if target.Scheme != "" {
+		redirect.Scheme = ptr.To(target.Scheme)
+	}
+	if hostname := target.Hostname(); hostname != "" {
+		redirect.Hostname = ptr.To(gatewayv1.PreciseHostname(hostname))
+	}
+	if port := target.Port(); port != "" {
+		portNumber, err := strconv.ParseInt(port, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port of location %q: %w", location, err)
+		}
+		redirect.Port = ptr.To(gatewayv1.PortNumber(portNumber))
+	}
+	if target.Path != "" {
+		redirect.Path = &gatewayv1.HTTPPathModifier{
+			Type:            gatewayv1.FullPathHTTPPathModifier,
+			ReplaceFullPath: ptr.To(target.Path),
+		}
+	}
+	return redirect, nil
+}
+
+// toRateLimit converts a ratelimit or clientRatelimit filter, e.g.
+// ratelimit(20, "1m"), into a rate limit, if it identifies the clients by
+// their address.
+func toRateLimit(call eskipCall) (*intermediate.RateLimitConfig, bool) {
+	requests, requestsOK := call.numberArg(0)
+	period, periodOK := call.stringArg(1)
+	if !requestsOK || !periodOK || len(call.Args) != 2 || requests < 1 {
+		return nil, false
+	}
+	duration, err := time.ParseDuration(period)
+	if err != nil || duration <= 0 {
+		return nil, false
+	}
+	return &intermediate.RateLimitConfig{
+		Requests: int32(requests),
+		Period:   duration,
+	}, true
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skipper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_parseFilterAnnotation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		annotation     string
+		expected       *skipperFilters
+		expectedErrors int
+	}{
+		{
+			name: "no filters",
+		},
+		{
+			name:       "header filters",
+			annotation: `setRequestHeader("X-Foo", "bar") -> appendRequestHeader("X-Bar", "baz") -> dropResponseHeader("Server")`,
+			expected: &skipperFilters{
+				filters: []gatewayv1.HTTPRouteFilter{
+					{
+						Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+						RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+							Set: []gatewayv1.HTTPHeader{{Name: "X-Foo", Value: "bar"}},
+							Add: []gatewayv1.HTTPHeader{{Name: "X-Bar", Value: "baz"}},
+						},
+					},
+					{
+						Type:                   gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+						ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{Remove: []string{"Server"}},
+					},
+				},
+			},
+		},
+		{
+			name:       "redirect",
+			annotation: `redirectTo(308, "https://example.com:8443/new")`,
+			expected: &skipperFilters{
+				filters: []gatewayv1.HTTPRouteFilter{{
+					Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+					RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+						Scheme:     ptr.To("https"),
+						Hostname:   ptr.To(gatewayv1.PreciseHostname("example.com")),
+						Port:       ptr.To(gatewayv1.PortNumber(8443)),
+						Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/new")},
+						StatusCode: ptr.To(301),
+					},
+				}},
+				redirect: true,
+			},
+		},
+		{
+			name:       "rate limits and unsupported filters",
+			annotation: `ratelimit(100, "1m") -> clientRatelimit(10, "1s") -> setRequestHeader("X-Host", "${request.host}") -> compress()`,
+			expected: &skipperFilters{
+				rateLimit:       &intermediate.RateLimitConfig{Requests: 100, Period: time.Minute},
+				clientRateLimit: &intermediate.RateLimitConfig{Requests: 10, Period: time.Second},
+				unsupported: []eskipCall{
+					{Name: "setRequestHeader", Args: []interface{}{"X-Host", "${request.host}"}, Raw: `setRequestHeader("X-Host", "${request.host}")`},
+					{Name: "compress", Raw: "compress()"},
+				},
+			},
+		},
+		{
+			name:           "redirect and rewrite",
+			annotation:     `setPath("/new") -> redirectTo(302, "/other")`,
+			expectedErrors: 1,
+		},
+		{
+			name:           "invalid redirect status code",
+			annotation:     `redirectTo(200, "/other")`,
+			expectedErrors: 1,
+		},
+		{
+			name:           "invalid expression",
+			annotation:     `setPath("/new"`,
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+			if tc.annotation != "" {
+				ingress.Annotations = map[string]string{filterAnnotation: tc.annotation}
+			}
+			filters, errs := parseFilterAnnotation(ingress)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if diff := cmp.Diff(tc.expected, filters, cmp.AllowUnexported(skipperFilters{})); diff != "" {
+				t.Errorf("unexpected filters (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
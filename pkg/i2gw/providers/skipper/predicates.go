@@ -0,0 +1,205 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skipper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// skipperPredicates are the predicates of the zalando.org/skipper-predicate
+// annotation of an Ingress.
+type skipperPredicates struct {
+	headers     []gatewayv1.HTTPHeaderMatch
+	queryParams []gatewayv1.HTTPQueryParamMatch
+	// methods are the methods the requests must have one of, any if empty.
+	methods []gatewayv1.HTTPMethod
+	// unsupported are the predicates without Gateway API equivalent.
+	unsupported []eskipCall
+}
+
+// predicatesFeature converts the zalando.org/skipper-predicate annotation of
+// the Ingresses into header, query parameter and method matches of the rules
+// generated from their paths.
+//
+// The supported predicates are Header, HeaderRegexp, QueryParam, Method and
+// Methods. The other predicates are recorded as unsupported features.
+func predicatesFeature(ingresses []networkingv1.Ingress, ir *intermediate.IR) field.ErrorList {
+	var errs field.ErrorList
+	predicatesByIngress := map[types.NamespacedName]skipperPredicates{}
+	for _, ingress := range ingresses {
+		predicates, parseErrs := parsePredicateAnnotation(ingress)
+		errs = append(errs, parseErrs...)
+		if predicates == nil {
+			continue
+		}
+		for _, call := range predicates.unsupported {
+			notify(notifications.WarningNotification, fmt.Sprintf("predicate %s of ingress %s/%s has no Gateway API equivalent and must be ported manually", call.Raw, ingress.Namespace, ingress.Name), &ingress)
+		}
+		predicatesByIngress[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = *predicates
+	}
+	if len(predicatesByIngress) == 0 {
+		return errs
+	}
+
+	for _, rg := range common.GetRuleGroups(ingresses, ir) {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRouteContext, ok := ir.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for ingressKey, ruleIndices := range ingressRuleIndices(rg, httpRouteContext.HTTPRoute) {
+			predicates, ok := predicatesByIngress[ingressKey]
+			if !ok {
+				continue
+			}
+			for _, i := range ruleIndices {
+				httpRouteContext.Spec.Rules[i].Matches = patchMatches(httpRouteContext.Spec.Rules[i].Matches, predicates)
+			}
+			addUnsupportedCalls(&httpRouteContext, ingressKey, predicateAnnotation, predicates.unsupported)
+			notify(notifications.InfoNotification, fmt.Sprintf("parsed %q annotation of ingress %s/%s and patched the matches of HTTPRoute %s", predicateAnnotation, ingressKey.Namespace, ingressKey.Name, key), &httpRouteContext.HTTPRoute)
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+	return errs
+}
+
+// patchMatches adds the header and query parameter matches of the predicates
+// to the matches, duplicated per method of the predicates.
+func patchMatches(matches []gatewayv1.HTTPRouteMatch, predicates skipperPredicates) []gatewayv1.HTTPRouteMatch {
+	if len(matches) == 0 {
+		matches = []gatewayv1.HTTPRouteMatch{{}}
+	}
+	var patched []gatewayv1.HTTPRouteMatch
+	for _, match := range matches {
+		match.Headers = append(match.Headers, predicates.headers...)
+		match.QueryParams = append(match.QueryParams, predicates.queryParams...)
+		if len(predicates.methods) == 0 {
+			patched = append(patched, match)
+			continue
+		}
+		for _, method := range predicates.methods {
+			methodMatch := *match.DeepCopy()
+			methodMatch.Method = ptr.To(method)
+			patched = append(patched, methodMatch)
+		}
+	}
+	return patched
+}
+
+// parsePredicateAnnotation returns the predicates of the Ingress, or nil if it
+// has none.
+func parsePredicateAnnotation(ingress networkingv1.Ingress) (*skipperPredicates, field.ErrorList) {
+	value, ok := ingress.Annotations[predicateAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	annotationPath := field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations").Key(predicateAnnotation)
+	calls, err := parseEskipCalls(value, predicateSeparator)
+	if err != nil {
+		return nil, field.ErrorList{field.Invalid(annotationPath, value, err.Error())}
+	}
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	var (
+		predicates skipperPredicates
+		errs       field.ErrorList
+	)
+	for _, call := range calls {
+		switch call.Name {
+		case "Header", "HeaderRegexp":
+			name, nameOK := call.stringArg(0)
+			headerValue, valueOK := call.stringArg(1)
+			if !nameOK || !valueOK || len(call.Args) != 2 {
+				errs = append(errs, field.Invalid(annotationPath, value, fmt.Sprintf("%s expects a name and a value", call.Name)))
+				continue
+			}
+			matchType := gatewayv1.HeaderMatchExact
+			if call.Name == "HeaderRegexp" {
+				matchType = gatewayv1.HeaderMatchRegularExpression
+			}
+			predicates.headers = append(predicates.headers, gatewayv1.HTTPHeaderMatch{
+				Type:  ptr.To(matchType),
+				Name:  gatewayv1.HTTPHeaderName(name),
+				Value: headerValue,
+			})
+		case "QueryParam":
+			name, nameOK := call.stringArg(0)
+			// Without a regular expression, the query parameter must only be
+			// present.
+			pattern := ".*"
+			if len(call.Args) == 2 {
+				var patternOK bool
+				pattern, patternOK = call.stringArg(1)
+				nameOK = nameOK && patternOK
+			}
+			if !nameOK || len(call.Args) > 2 {
+				errs = append(errs, field.Invalid(annotationPath, value, "QueryParam expects a name and an optional regular expression"))
+				continue
+			}
+			predicates.queryParams = append(predicates.queryParams, gatewayv1.HTTPQueryParamMatch{
+				Type:  ptr.To(gatewayv1.QueryParamMatchRegularExpression),
+				Name:  gatewayv1.HTTPHeaderName(name),
+				Value: pattern,
+			})
+		case "Method", "Methods":
+			methods, ok := methodArgs(call)
+			if !ok || (call.Name == "Method" && len(methods) != 1) {
+				errs = append(errs, field.Invalid(annotationPath, value, fmt.Sprintf("%s expects method names", call.Name)))
+				continue
+			}
+			if len(predicates.methods) > 0 {
+				errs = append(errs, field.Invalid(annotationPath, value, "the methods can only be matched once"))
+				continue
+			}
+			predicates.methods = methods
+		default:
+			predicates.unsupported = append(predicates.unsupported, call)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return &predicates, nil
+}
+
+// methodArgs returns the methods of a Method or Methods predicate.
+func methodArgs(call eskipCall) ([]gatewayv1.HTTPMethod, bool) {
+	if len(call.Args) == 0 {
+		return nil, false
+	}
+	methods := make([]gatewayv1.HTTPMethod, 0, len(call.Args))
+	for i := range call.Args {
+		method, ok := call.stringArg(i)
+		if !ok {
+			return nil, false
+		}
+		methods = append(methods, gatewayv1.HTTPMethod(strings.ToUpper(method)))
+	}
+	return methods, true
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skipper
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_predicatesFeature(t *testing.T) {
+	testCases := []struct {
+		name                string
+		annotation          string
+		expectedMatches     []gatewayv1.HTTPRouteMatch
+		expectedUnsupported int
+		expectedErrors      int
+	}{
+		{
+			name: "no predicates",
+			expectedMatches: []gatewayv1.HTTPRouteMatch{
+				{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/api")}},
+			},
+		},
+		{
+			name:       "headers, query parameters and methods",
+			annotation: `Header("X-Tenant", "acme") && QueryParam("debug") && Methods("get", "POST")`,
+			expectedMatches: []gatewayv1.HTTPRouteMatch{
+				{
+					Path:        &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/api")},
+					Headers:     []gatewayv1.HTTPHeaderMatch{{Type: ptr.To(gatewayv1.HeaderMatchExact), Name: "X-Tenant", Value: "acme"}},
+					QueryParams: []gatewayv1.HTTPQueryParamMatch{{Type: ptr.To(gatewayv1.QueryParamMatchRegularExpression), Name: "debug", Value: ".*"}},
+					Method:      ptr.To(gatewayv1.HTTPMethodGet),
+				},
+				{
+					Path:        &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/api")},
+					Headers:     []gatewayv1.HTTPHeaderMatch{{Type: ptr.To(gatewayv1.HeaderMatchExact), Name: "X-Tenant", Value: "acme"}},
+					QueryParams: []gatewayv1.HTTPQueryParamMatch{{Type: ptr.To(gatewayv1.QueryParamMatchRegularExpression), Name: "debug", Value: ".*"}},
+					Method:      ptr.To(gatewayv1.HTTPMethodPost),
+				},
+			},
+		},
+		{
+			name:       "unsupported predicates",
+			annotation: `HeaderRegexp("X-Version", /^v2/) && Cookie("canary", "true") && Traffic(0.1)`,
+			expectedMatches: []gatewayv1.HTTPRouteMatch{
+				{
+					Path:    &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/api")},
+					Headers: []gatewayv1.HTTPHeaderMatch{{Type: ptr.To(gatewayv1.HeaderMatchRegularExpression), Name: "X-Version", Value: "^v2"}},
+				},
+			},
+			expectedUnsupported: 2,
+		},
+		{
+			name:           "methods matched twice",
+			annotation:     `Method("GET") && Method("POST")`,
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptr.To(SkipperIngressClass),
+					Rules: []networkingv1.IngressRule{{
+						Host: "example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path:     "/api",
+								PathType: ptr.To(networkingv1.PathTypePrefix),
+								Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+									Name: "api",
+									Port: networkingv1.ServiceBackendPort{Number: 80},
+								}},
+							}},
+						}},
+					}},
+				},
+			}
+			if tc.annotation != "" {
+				ingress.Annotations = map[string]string{predicateAnnotation: tc.annotation}
+			}
+			ingresses := []networkingv1.Ingress{ingress}
+			ir, errs := common.ToIR(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors converting the Ingress: %v", errs)
+			}
+
+			errs = predicatesFeature(ingresses, &ir)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+			if tc.expectedErrors > 0 {
+				return
+			}
+			httpRouteContext := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: common.RouteName("app", "example.com")}]
+			if diff := cmp.Diff(tc.expectedMatches, httpRouteContext.Spec.Rules[0].Matches); diff != "" {
+				t.Errorf("unexpected matches (-want +got):\n%s", diff)
+			}
+			if len(httpRouteContext.UnsupportedFeatures) != tc.expectedUnsupported {
+				t.Errorf("expected %d unsupported features, got %v", tc.expectedUnsupported, httpRouteContext.UnsupportedFeatures)
+			}
+		})
+	}
+}
@@ -0,0 +1,626 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traefik
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// defaultEntryPoints are the entry points of the default static configuration
+// of Traefik.
+const defaultEntryPoints = "web=80,websecure=443"
+
+type resourcesToIRConverter struct {
+	gatewayClassName string
+	entryPointPorts  map[string]gatewayv1.PortNumber
+	entryPointsErr   *field.Error
+}
+
+func newResourcesToIRConverter(conf *i2gw.ProviderConf) resourcesToIRConverter {
+	converter := resourcesToIRConverter{gatewayClassName: DefaultGatewayClassName}
+	flags := conf.ProviderSpecificFlags[ProviderName]
+	if gatewayClassName := flags[GatewayClassFlag]; gatewayClassName != "" {
+		converter.gatewayClassName = gatewayClassName
+	}
+	entryPoints := flags[EntryPointsFlag]
+	if entryPoints == "" {
+		entryPoints = defaultEntryPoints
+	}
+	converter.entryPointPorts, converter.entryPointsErr = parseEntryPoints(entryPoints)
+	return converter
+}
+
+// parseEntryPoints parses the ports of the entry points, <name>=<port>
+// separated by commas.
+func parseEntryPoints(entryPoints string) (map[string]gatewayv1.PortNumber, *field.Error) {
+	ports := map[string]gatewayv1.PortNumber{}
+	flagPath := field.NewPath(fmt.Sprintf("--%s-%s", ProviderName, EntryPointsFlag))
+	for _, entryPoint := range strings.Split(entryPoints, ",") {
+		name, port, found := strings.Cut(strings.TrimSpace(entryPoint), "=")
+		portNumber, err := strconv.ParseInt(port, 10, 32)
+		if !found || name == "" || err != nil || portNumber < 1 || portNumber > 65535 {
+			return nil, field.Invalid(flagPath, entryPoints, "must be a comma-separated list of <name>=<port>")
+		}
+		ports[name] = gatewayv1.PortNumber(portNumber)
+	}
+	return ports, nil
+}
+
+// entryPoint is an entry point of Traefik routes are attached to.
+type entryPoint struct {
+	name string
+	port gatewayv1.PortNumber
+}
+
+// conversion holds the state of the conversion of the Traefik CRDs: the IR
+// being built, and the listeners of its Gateways.
+type conversion struct {
+	storage   *storage
+	ir        intermediate.IR
+	listeners map[types.NamespacedName][]gatewayv1.Listener
+}
+
+func (c *resourcesToIRConverter) convertToIR(storage *storage) (intermediate.IR, field.ErrorList) {
+	if c.entryPointsErr != nil {
+		return intermediate.IR{}, field.ErrorList{c.entryPointsErr}
+	}
+	cv := conversion{
+		storage: storage,
+		ir: intermediate.IR{
+			Gateways:        make(map[types.NamespacedName]intermediate.GatewayContext),
+			HTTPRoutes:      make(map[types.NamespacedName]intermediate.HTTPRouteContext),
+			TLSRoutes:       make(map[types.NamespacedName]gatewayv1alpha2.TLSRoute),
+			TCPRoutes:       make(map[types.NamespacedName]gatewayv1alpha2.TCPRoute),
+			UDPRoutes:       make(map[types.NamespacedName]gatewayv1alpha2.UDPRoute),
+			ReferenceGrants: make(map[types.NamespacedName]gatewayv1beta1.ReferenceGrant),
+		},
+		listeners: make(map[types.NamespacedName][]gatewayv1.Listener),
+	}
+
+	var errs field.ErrorList
+	for _, key := range sortedKeys(storage.IngressRoutes) {
+		errs = append(errs, c.convertIngressRoute(storage.IngressRoutes[key], &cv)...)
+	}
+	for _, key := range sortedKeys(storage.IngressRouteTCPs) {
+		errs = append(errs, c.convertIngressRouteTCP(storage.IngressRouteTCPs[key], &cv)...)
+	}
+	for _, key := range sortedKeys(storage.IngressRouteUDPs) {
+		errs = append(errs, c.convertIngressRouteUDP(storage.IngressRouteUDPs[key], &cv)...)
+	}
+
+	for gatewayKey, listeners := range cv.listeners {
+		gateway := gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: gatewayKey.Namespace, Name: gatewayKey.Name},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: gatewayv1.ObjectName(c.gatewayClassName),
+				Listeners:        listeners,
+			},
+		}
+		gateway.SetGroupVersionKind(common.GatewayGVK)
+		cv.ir.Gateways[gatewayKey] = intermediate.GatewayContext{Gateway: gateway}
+	}
+
+	return cv.ir, errs
+}
+
+// hostRules are the HTTPRoute rules of the routes of an IngressRoute matching
+// the same hosts.
+type hostRules struct {
+	hosts []string
+	rules []gatewayv1.HTTPRouteRule
+}
+
+func (c *resourcesToIRConverter) convertIngressRoute(ingressRoute *IngressRoute, cv *conversion) field.ErrorList {
+	key := types.NamespacedName{Namespace: ingressRoute.Namespace, Name: ingressRoute.Name}
+	specPath := field.NewPath(ProviderName, IngressRouteKind).Key(key.String()).Child("spec")
+
+	defaultEntryPoint := "web"
+	if ingressRoute.Spec.TLS != nil {
+		defaultEntryPoint = "websecure"
+	}
+	entryPoints, err := c.entryPoints(ingressRoute.Spec.EntryPoints, defaultEntryPoint, specPath.Child("entryPoints"), IngressRouteKind, ingressRoute)
+	if err != nil {
+		return field.ErrorList{err}
+	}
+
+	var (
+		errs         field.ErrorList
+		rulesByHosts []*hostRules
+	)
+	for i, route := range ingressRoute.Spec.Routes {
+		routePath := specPath.Child("routes").Index(i)
+		if route.Kind != "" && route.Kind != "Rule" {
+			errs = append(errs, field.NotSupported(routePath.Child("kind"), route.Kind, []string{"Rule"}))
+			continue
+		}
+
+		matchesByHosts, err := ruleMatches(route.Match, ingressRoute)
+		if err != nil {
+			if isUnsupported(err) {
+				notify(notifications.WarningNotification, fmt.Sprintf("route %d of IngressRoute %s was not converted: %v", i, key, err), ingressRoute)
+			} else {
+				errs = append(errs, field.Invalid(routePath.Child("match"), route.Match, err.Error()))
+			}
+			continue
+		}
+		if route.Priority != 0 {
+			notify(notifications.InfoNotification, fmt.Sprintf("priority of route %d of IngressRoute %s was ignored, Gateway API orders the rules by the precedence of their matches", i, key), ingressRoute)
+		}
+
+		backendRefs, backendErrs := cv.backendRefs(ingressRoute.Namespace, route.Services, routePath.Child("services"), common.HTTPRouteGVK, IngressRouteKind, ingressRoute)
+		if len(backendErrs) > 0 {
+			errs = append(errs, backendErrs...)
+			continue
+		}
+		if len(backendRefs) == 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("route %d of IngressRoute %s was not converted, it has no Service backend", i, key), ingressRoute)
+			continue
+		}
+
+		for _, hostMatches := range matchesByHosts {
+			rule := gatewayv1.HTTPRouteRule{
+				Matches:     hostMatches.matches,
+				BackendRefs: toHTTPBackendRefs(backendRefs),
+				Filters:     cv.middlewareFilters(ingressRoute.Namespace, route.Middlewares, hostMatches.matches, ingressRoute),
+			}
+			if slices.ContainsFunc(rule.Filters, func(filter gatewayv1.HTTPRouteFilter) bool {
+				return filter.Type == gatewayv1.HTTPRouteFilterRequestRedirect
+			}) {
+				rule.BackendRefs = nil
+			}
+
+			i := slices.IndexFunc(rulesByHosts, func(r *hostRules) bool { return slices.Equal(r.hosts, hostMatches.hosts) })
+			if i < 0 {
+				rulesByHosts = append(rulesByHosts, &hostRules{hosts: hostMatches.hosts})
+				i = len(rulesByHosts) - 1
+			}
+			rulesByHosts[i].rules = append(rulesByHosts[i].rules, rule)
+		}
+	}
+
+	var certificateRefs []gatewayv1.SecretObjectReference
+	if ingressRoute.Spec.TLS != nil {
+		certificateRefs = tlsCertificateRefs(ingressRoute.Spec.TLS, IngressRouteKind, ingressRoute)
+	}
+	gatewayKey := types.NamespacedName{Namespace: ingressRoute.Namespace, Name: c.gatewayClassName}
+	for _, hostRules := range rulesByHosts {
+		name := ingressRoute.Name
+		if len(rulesByHosts) > 1 {
+			var host string
+			if len(hostRules.hosts) > 0 {
+				host = hostRules.hosts[0]
+			}
+			name = fmt.Sprintf("%s-%s", ingressRoute.Name, common.NameFromHost(host))
+		}
+
+		var parentRefs []gatewayv1.ParentReference
+		hostnames := hostRules.hosts
+		if len(hostnames) == 0 {
+			hostnames = []string{""}
+		}
+		for _, entryPoint := range entryPoints {
+			for _, hostname := range hostnames {
+				listener := gatewayv1.Listener{
+					Name:     listenerName(hostname, entryPoint, ""),
+					Port:     entryPoint.port,
+					Protocol: gatewayv1.HTTPProtocolType,
+				}
+				if hostname != "" {
+					listener.Hostname = ptr.To(gatewayv1.Hostname(hostname))
+				}
+				if certificateRefs != nil {
+					listener.Protocol = gatewayv1.HTTPSProtocolType
+					listener.TLS = &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModeTerminate), CertificateRefs: slices.Clone(certificateRefs)}
+				}
+				cv.addListener(gatewayKey, listener)
+				parentRefs = append(parentRefs, gatewayv1.ParentReference{Name: gatewayv1.ObjectName(gatewayKey.Name), SectionName: ptr.To(listener.Name)})
+			}
+		}
+
+		httpRoute := gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ingressRoute.Namespace, Name: name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+				Rules:           hostRules.rules,
+			},
+		}
+		for _, host := range hostRules.hosts {
+			httpRoute.Spec.Hostnames = append(httpRoute.Spec.Hostnames, gatewayv1.Hostname(host))
+		}
+		httpRoute.SetGroupVersionKind(common.HTTPRouteGVK)
+		cv.ir.HTTPRoutes[types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}] = intermediate.HTTPRouteContext{
+			HTTPRoute: httpRoute,
+			Sources:   []client.Object{ingressRoute},
+		}
+		notify(notifications.InfoNotification, fmt.Sprintf("successfully converted IngressRoute %s to HTTPRoute %s/%s", key, httpRoute.Namespace, httpRoute.Name), ingressRoute)
+	}
+
+	return errs
+}
+
+// hostMatches are the matches of a route matching the same hosts.
+type hostMatches struct {
+	hosts   []string
+	matches []gatewayv1.HTTPRouteMatch
+}
+
+// ruleMatches returns the matches of the rule of a route, grouped by the hosts
+// they match.
+func ruleMatches(rule string, ingressRoute *IngressRoute) ([]hostMatches, error) {
+	conjunctions, err := parseRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	var matchesByHosts []hostMatches
+	for _, conjunction := range conjunctions {
+		hosts, match, broader, err := toHTTPRouteMatch(conjunction)
+		if err != nil {
+			return nil, err
+		}
+		for _, reason := range broader {
+			notify(notifications.WarningNotification, fmt.Sprintf("the rule of IngressRoute %s/%s was broadened: %s", ingressRoute.Namespace, ingressRoute.Name, reason), ingressRoute)
+		}
+		slices.Sort(hosts)
+		hosts = slices.Compact(hosts)
+		i := slices.IndexFunc(matchesByHosts, func(m hostMatches) bool { return slices.Equal(m.hosts, hosts) })
+		if i < 0 {
+			matchesByHosts = append(matchesByHosts, hostMatches{hosts: hosts})
+			i = len(matchesByHosts) - 1
+		}
+		matchesByHosts[i].matches = append(matchesByHosts[i].matches, match)
+	}
+	return matchesByHosts, nil
+}
+
+func (c *resourcesToIRConverter) convertIngressRouteTCP(ingressRouteTCP *IngressRouteTCP, cv *conversion) field.ErrorList {
+	key := types.NamespacedName{Namespace: ingressRouteTCP.Namespace, Name: ingressRouteTCP.Name}
+	specPath := field.NewPath(ProviderName, IngressRouteTCPKind).Key(key.String()).Child("spec")
+
+	entryPoints, err := c.entryPoints(ingressRouteTCP.Spec.EntryPoints, "", specPath.Child("entryPoints"), IngressRouteTCPKind, ingressRouteTCP)
+	if err != nil {
+		return field.ErrorList{err}
+	}
+	tls := ingressRouteTCP.Spec.TLS
+	var certificateRefs []gatewayv1.SecretObjectReference
+	if tls != nil && !tls.Passthrough {
+		certificateRefs = tlsCertificateRefs(tls, IngressRouteTCPKind, ingressRouteTCP)
+	}
+
+	var errs field.ErrorList
+	gatewayKey := types.NamespacedName{Namespace: ingressRouteTCP.Namespace, Name: c.gatewayClassName}
+	for i, route := range ingressRouteTCP.Spec.Routes {
+		routePath := specPath.Child("routes").Index(i)
+		hosts, err := hostSNIs(route.Match)
+		if err != nil {
+			if isUnsupported(err) {
+				notify(notifications.WarningNotification, fmt.Sprintf("route %d of IngressRouteTCP %s was not converted: %v", i, key, err), ingressRouteTCP)
+			} else {
+				errs = append(errs, field.Invalid(routePath.Child("match"), route.Match, err.Error()))
+			}
+			continue
+		}
+		if tls == nil && len(hosts) > 0 {
+			errs = append(errs, field.Invalid(routePath.Child("match"), route.Match, "server names can only be matched with TLS"))
+			continue
+		}
+		if len(route.Middlewares) > 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("TCP middlewares of route %d of IngressRouteTCP %s have no Gateway API equivalent, they were not converted", i, key), ingressRouteTCP)
+		}
+
+		routeGVK := common.TCPRouteGVK
+		if tls != nil && tls.Passthrough {
+			routeGVK = common.TLSRouteGVK
+		}
+		backendRefs, backendErrs := cv.backendRefs(ingressRouteTCP.Namespace, route.Services, routePath.Child("services"), routeGVK, IngressRouteTCPKind, ingressRouteTCP)
+		if len(backendErrs) > 0 {
+			errs = append(errs, backendErrs...)
+			continue
+		}
+
+		var parentRefs []gatewayv1.ParentReference
+		hostnames := hosts
+		if len(hostnames) == 0 {
+			hostnames = []string{""}
+		}
+		for _, entryPoint := range entryPoints {
+			for _, hostname := range hostnames {
+				listener := gatewayv1.Listener{Port: entryPoint.port}
+				switch {
+				case tls == nil:
+					listener.Name = listenerName("", entryPoint, "tcp")
+					listener.Protocol = gatewayv1.TCPProtocolType
+				case tls.Passthrough:
+					listener.Name = listenerName(hostname, entryPoint, "tls")
+					listener.Protocol = gatewayv1.TLSProtocolType
+					listener.TLS = &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModePassthrough)}
+				default:
+					listener.Name = listenerName(hostname, entryPoint, "tls")
+					listener.Protocol = gatewayv1.TLSProtocolType
+					listener.TLS = &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModeTerminate), CertificateRefs: slices.Clone(certificateRefs)}
+				}
+				if hostname != "" {
+					listener.Hostname = ptr.To(gatewayv1.Hostname(hostname))
+				}
+				cv.addListener(gatewayKey, listener)
+				parentRefs = append(parentRefs, gatewayv1.ParentReference{Name: gatewayv1.ObjectName(gatewayKey.Name), SectionName: ptr.To(listener.Name)})
+			}
+		}
+
+		name := ingressRouteTCP.Name
+		if len(ingressRouteTCP.Spec.Routes) > 1 {
+			name = fmt.Sprintf("%s-%d", ingressRouteTCP.Name, i)
+		}
+		objectMeta := metav1.ObjectMeta{Namespace: ingressRouteTCP.Namespace, Name: name}
+		commonRouteSpec := gatewayv1.CommonRouteSpec{ParentRefs: parentRefs}
+		if routeGVK == common.TLSRouteGVK {
+			tlsRoute := gatewayv1alpha2.TLSRoute{
+				ObjectMeta: objectMeta,
+				Spec: gatewayv1alpha2.TLSRouteSpec{
+					CommonRouteSpec: commonRouteSpec,
+					Rules:           []gatewayv1alpha2.TLSRouteRule{{BackendRefs: backendRefs}},
+				},
+			}
+			for _, host := range hosts {
+				tlsRoute.Spec.Hostnames = append(tlsRoute.Spec.Hostnames, gatewayv1.Hostname(host))
+			}
+			tlsRoute.SetGroupVersionKind(common.TLSRouteGVK)
+			cv.ir.TLSRoutes[types.NamespacedName{Namespace: tlsRoute.Namespace, Name: tlsRoute.Name}] = tlsRoute
+		} else {
+			tcpRoute := gatewayv1alpha2.TCPRoute{
+				ObjectMeta: objectMeta,
+				Spec: gatewayv1alpha2.TCPRouteSpec{
+					CommonRouteSpec: commonRouteSpec,
+					Rules:           []gatewayv1alpha2.TCPRouteRule{{BackendRefs: backendRefs}},
+				},
+			}
+			tcpRoute.SetGroupVersionKind(common.TCPRouteGVK)
+			cv.ir.TCPRoutes[types.NamespacedName{Namespace: tcpRoute.Namespace, Name: tcpRoute.Name}] = tcpRoute
+		}
+		notify(notifications.InfoNotification, fmt.Sprintf("successfully converted IngressRouteTCP %s to %s %s/%s", key, routeGVK.Kind, objectMeta.Namespace, objectMeta.Name), ingressRouteTCP)
+	}
+
+	return errs
+}
+
+func (c *resourcesToIRConverter) convertIngressRouteUDP(ingressRouteUDP *IngressRouteUDP, cv *conversion) field.ErrorList {
+	key := types.NamespacedName{Namespace: ingressRouteUDP.Namespace, Name: ingressRouteUDP.Name}
+	specPath := field.NewPath(ProviderName, IngressRouteUDPKind).Key(key.String()).Child("spec")
+
+	entryPoints, err := c.entryPoints(ingressRouteUDP.Spec.EntryPoints, "", specPath.Child("entryPoints"), IngressRouteUDPKind, ingressRouteUDP)
+	if err != nil {
+		return field.ErrorList{err}
+	}
+
+	var errs field.ErrorList
+	gatewayKey := types.NamespacedName{Namespace: ingressRouteUDP.Namespace, Name: c.gatewayClassName}
+	for i, route := range ingressRouteUDP.Spec.Routes {
+		backendRefs, backendErrs := cv.backendRefs(ingressRouteUDP.Namespace, route.Services, specPath.Child("routes").Index(i).Child("services"), common.UDPRouteGVK, IngressRouteUDPKind, ingressRouteUDP)
+		if len(backendErrs) > 0 {
+			errs = append(errs, backendErrs...)
+			continue
+		}
+
+		var parentRefs []gatewayv1.ParentReference
+		for _, entryPoint := range entryPoints {
+			listener := gatewayv1.Listener{
+				Name:     listenerName("", entryPoint, "udp"),
+				Port:     entryPoint.port,
+				Protocol: gatewayv1.UDPProtocolType,
+			}
+			cv.addListener(gatewayKey, listener)
+			parentRefs = append(parentRefs, gatewayv1.ParentReference{Name: gatewayv1.ObjectName(gatewayKey.Name), SectionName: ptr.To(listener.Name)})
+		}
+
+		name := ingressRouteUDP.Name
+		if len(ingressRouteUDP.Spec.Routes) > 1 {
+			name = fmt.Sprintf("%s-%d", ingressRouteUDP.Name, i)
+		}
+		udpRoute := gatewayv1alpha2.UDPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ingressRouteUDP.Namespace, Name: name},
+			Spec: gatewayv1alpha2.UDPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+				Rules:           []gatewayv1alpha2.UDPRouteRule{{BackendRefs: backendRefs}},
+			},
+		}
+		udpRoute.SetGroupVersionKind(common.UDPRouteGVK)
+		cv.ir.UDPRoutes[types.NamespacedName{Namespace: udpRoute.Namespace, Name: udpRoute.Name}] = udpRoute
+		notify(notifications.InfoNotification, fmt.Sprintf("successfully converted IngressRouteUDP %s to UDPRoute %s/%s", key, udpRoute.Namespace, udpRoute.Name), ingressRouteUDP)
+	}
+
+	return errs
+}
+
+// entryPoints returns the entry points of a route, with their ports. Routes
+// without entry points are attached to the given default entry point, if any.
+// HTTP routes default to the websecure entry point with TLS, and to the web
+// entry point otherwise, as with the default static configuration of Traefik.
+func (c *resourcesToIRConverter) entryPoints(names []string, defaultName string, path *field.Path, kind string, obj client.Object) ([]entryPoint, *field.Error) {
+	if len(names) == 0 {
+		if defaultName == "" {
+			return nil, field.Required(path, "the entry points must be set to generate the listeners of the Gateway")
+		}
+		names = []string{defaultName}
+		notify(notifications.InfoNotification, fmt.Sprintf("%s %s/%s has no entry points, it was attached to the %s entry point", kind, obj.GetNamespace(), obj.GetName(), defaultName), obj)
+	}
+
+	var entryPoints []entryPoint
+	for i, name := range names {
+		port, ok := c.entryPointPorts[name]
+		if !ok {
+			return nil, field.Invalid(path.Index(i), name, fmt.Sprintf("unknown entry point, set its port with --%s-%s", ProviderName, EntryPointsFlag))
+		}
+		entryPoints = append(entryPoints, entryPoint{name: name, port: port})
+	}
+	return entryPoints, nil
+}
+
+// listenerName returns the name of the listener of the given entry point and
+// hostname, with the given suffix for the non-HTTP listeners.
+func listenerName(hostname string, entryPoint entryPoint, suffix string) gatewayv1.SectionName {
+	parts := []string{strings.ToLower(entryPoint.name)}
+	if hostname != "" {
+		parts = append([]string{common.NameFromHost(hostname)}, parts...)
+	}
+	if suffix != "" {
+		parts = append(parts, suffix)
+	}
+	return gatewayv1.SectionName(strings.Join(parts, "-"))
+}
+
+// tlsCertificateRefs returns the references of the Secrets holding the
+// certificates of a route. Certificates issued by certificate resolvers, or
+// defaulting to the default certificate of Traefik, must be moved to a Secret
+// named after the route.
+func tlsCertificateRefs(tls *TLS, kind string, obj client.Object) []gatewayv1.SecretObjectReference {
+	if tls.Options != nil {
+		notify(notifications.WarningNotification, fmt.Sprintf("TLS options %s of %s %s/%s were not converted", tls.Options.Name, kind, obj.GetNamespace(), obj.GetName()), obj)
+	}
+	if tls.SecretName != "" {
+		return []gatewayv1.SecretObjectReference{{Name: gatewayv1.ObjectName(tls.SecretName)}}
+	}
+	secretName := fmt.Sprintf("%s-tls", obj.GetName())
+	if tls.CertResolver != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %s/%s gets its certificates from the %s certificate resolver, issue them into the kubernetes.io/tls Secret %s/%s, e.g. with cert-manager", kind, obj.GetNamespace(), obj.GetName(), tls.CertResolver, obj.GetNamespace(), secretName), obj)
+	} else {
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %s/%s uses the default certificate of Traefik, create the kubernetes.io/tls Secret %s/%s from a certificate of its hosts", kind, obj.GetNamespace(), obj.GetName(), obj.GetNamespace(), secretName), obj)
+	}
+	return []gatewayv1.SecretObjectReference{{Name: gatewayv1.ObjectName(secretName)}}
+}
+
+// backendRefs returns the references of the Services of a route, generating
+// the ReferenceGrants of the Services of other namespaces. TraefikServices
+// have no Gateway API equivalent and are skipped.
+func (cv *conversion) backendRefs(namespace string, services []Service, path *field.Path, routeGVK schema.GroupVersionKind, kind string, obj client.Object) ([]gatewayv1.BackendRef, field.ErrorList) {
+	var (
+		backendRefs []gatewayv1.BackendRef
+		errs        field.ErrorList
+	)
+	for i, service := range services {
+		servicePath := path.Index(i)
+		if service.Kind != "" && service.Kind != "Service" {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s %s of %s %s/%s has no Gateway API equivalent, it was not converted", service.Kind, service.Name, kind, obj.GetNamespace(), obj.GetName()), obj)
+			continue
+		}
+
+		port := service.Port.IntVal
+		if service.Port.Type == intstr.String {
+			parsed, err := strconv.ParseInt(service.Port.StrVal, 10, 32)
+			if err != nil {
+				errs = append(errs, field.Invalid(servicePath.Child("port"), service.Port.StrVal, "named ports are not supported, use the port number"))
+				continue
+			}
+			port = int32(parsed)
+		}
+		if port == 0 {
+			errs = append(errs, field.Required(servicePath.Child("port"), "the port of the Service must be set"))
+			continue
+		}
+		if service.Scheme != "" && service.Scheme != "http" {
+			notify(notifications.WarningNotification, fmt.Sprintf("the %s scheme of Service %s was not converted, configure the protocol to the backend on the Service, e.g. with a BackendTLSPolicy or its appProtocol", service.Scheme, service.Name), obj)
+		}
+
+		backendRef := gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{Name: gatewayv1.ObjectName(service.Name), Port: ptr.To(gatewayv1.PortNumber(port))},
+			Weight:                 service.Weight,
+		}
+		if service.Namespace != "" && service.Namespace != namespace {
+			backendRef.Namespace = ptr.To(gatewayv1.Namespace(service.Namespace))
+			cv.addReferenceGrant(namespace, routeGVK, service)
+		}
+		backendRefs = append(backendRefs, backendRef)
+	}
+	return backendRefs, errs
+}
+
+// addReferenceGrant grants the routes of the given kind and namespace the
+// references to the Service of another namespace.
+func (cv *conversion) addReferenceGrant(fromNamespace string, routeGVK schema.GroupVersionKind, service Service) {
+	key := types.NamespacedName{Namespace: service.Namespace, Name: fmt.Sprintf("generated-reference-grant-from-%s-to-%s", fromNamespace, service.Namespace)}
+	referenceGrant, ok := cv.ir.ReferenceGrants[key]
+	if !ok {
+		referenceGrant = gatewayv1beta1.ReferenceGrant{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+		referenceGrant.SetGroupVersionKind(common.ReferenceGrantGVK)
+	}
+	from := gatewayv1beta1.ReferenceGrantFrom{Group: gatewayv1.Group(routeGVK.Group), Kind: gatewayv1.Kind(routeGVK.Kind), Namespace: gatewayv1.Namespace(fromNamespace)}
+	if !slices.Contains(referenceGrant.Spec.From, from) {
+		referenceGrant.Spec.From = append(referenceGrant.Spec.From, from)
+	}
+	to := gatewayv1beta1.ReferenceGrantTo{Group: "", Kind: "Service", Name: ptr.To(gatewayv1.ObjectName(service.Name))}
+	if !slices.ContainsFunc(referenceGrant.Spec.To, func(existing gatewayv1beta1.ReferenceGrantTo) bool {
+		return existing.Kind == to.Kind && *existing.Name == *to.Name
+	}) {
+		referenceGrant.Spec.To = append(referenceGrant.Spec.To, to)
+	}
+	cv.ir.ReferenceGrants[key] = referenceGrant
+}
+
+func toHTTPBackendRefs(backendRefs []gatewayv1.BackendRef) []gatewayv1.HTTPBackendRef {
+	httpBackendRefs := make([]gatewayv1.HTTPBackendRef, 0, len(backendRefs))
+	for _, backendRef := range backendRefs {
+		httpBackendRefs = append(httpBackendRefs, gatewayv1.HTTPBackendRef{BackendRef: backendRef})
+	}
+	return httpBackendRefs
+}
+
+// addListener adds the listener to the Gateway, merging the certificates of
+// the listeners of the same name.
+func (cv *conversion) addListener(gatewayKey types.NamespacedName, listener gatewayv1.Listener) {
+	listeners := cv.listeners[gatewayKey]
+	for i, existing := range listeners {
+		if existing.Name != listener.Name {
+			continue
+		}
+		if listener.TLS != nil && existing.TLS != nil {
+			for _, ref := range listener.TLS.CertificateRefs {
+				if !slices.Contains(existing.TLS.CertificateRefs, ref) {
+					listeners[i].TLS.CertificateRefs = append(listeners[i].TLS.CertificateRefs, ref)
+				}
+			}
+		}
+		return
+	}
+	cv.listeners[gatewayKey] = append(listeners, listener)
+}
+
+func sortedKeys[T any](m map[types.NamespacedName]T) []types.NamespacedName {
+	keys := make([]types.NamespacedName, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return keys
+}
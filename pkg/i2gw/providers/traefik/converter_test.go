@@ -0,0 +1,245 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traefik
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_convertIngressRoute(t *testing.T) {
+	stripPrefix := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": APIVersion,
+		"kind":       MiddlewareKind,
+		"metadata":   map[string]interface{}{"name": "strip-api", "namespace": "default"},
+		"spec":       map[string]interface{}{"stripPrefix": map[string]interface{}{"prefixes": []interface{}{"/api"}}},
+	}}
+
+	testCases := []struct {
+		name              string
+		ingressRoute      IngressRoute
+		expectedRoutes    map[string][]gatewayv1.Hostname
+		expectedFilters   map[string][]gatewayv1.HTTPRouteFilterType
+		expectedListeners []gatewayv1.SectionName
+		expectedGrants    int
+		expectedErrors    int
+	}{
+		{
+			name: "routes of several hosts",
+			ingressRoute: IngressRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: IngressRouteSpec{
+					EntryPoints: []string{"web"},
+					Routes: []Route{
+						{Match: "Host(`a.example.com`) && PathPrefix(`/api`)", Services: []Service{{Name: "api", Port: intstr.FromInt32(80)}}, Middlewares: []MiddlewareRef{{Name: "strip-api"}}},
+						{Match: "Host(`b.example.com`)", Services: []Service{{Name: "web", Port: intstr.FromInt32(80)}}},
+					},
+				},
+			},
+			expectedRoutes: map[string][]gatewayv1.Hostname{
+				"app-a-example-com": {"a.example.com"},
+				"app-b-example-com": {"b.example.com"},
+			},
+			expectedFilters: map[string][]gatewayv1.HTTPRouteFilterType{
+				"app-a-example-com": {gatewayv1.HTTPRouteFilterURLRewrite},
+			},
+			expectedListeners: []gatewayv1.SectionName{"a-example-com-web", "b-example-com-web"},
+		},
+		{
+			name: "tls route without entry points",
+			ingressRoute: IngressRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: IngressRouteSpec{
+					TLS: &TLS{SecretName: "app-cert"},
+					Routes: []Route{
+						{Match: "Host(`a.example.com`)", Services: []Service{{Name: "web", Port: intstr.FromInt32(80)}}},
+					},
+				},
+			},
+			expectedRoutes:    map[string][]gatewayv1.Hostname{"app": {"a.example.com"}},
+			expectedListeners: []gatewayv1.SectionName{"a-example-com-websecure"},
+		},
+		{
+			name: "service of another namespace",
+			ingressRoute: IngressRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: IngressRouteSpec{
+					EntryPoints: []string{"web"},
+					Routes: []Route{
+						{Match: "PathPrefix(`/`)", Services: []Service{{Name: "web", Namespace: "backend", Port: intstr.FromInt32(80)}}},
+					},
+				},
+			},
+			expectedRoutes:    map[string][]gatewayv1.Hostname{"app": nil},
+			expectedListeners: []gatewayv1.SectionName{"web"},
+			expectedGrants:    1,
+		},
+		{
+			name: "unknown entry point",
+			ingressRoute: IngressRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: IngressRouteSpec{
+					EntryPoints: []string{"internal"},
+					Routes: []Route{
+						{Match: "PathPrefix(`/`)", Services: []Service{{Name: "web", Port: intstr.FromInt32(80)}}},
+					},
+				},
+			},
+			expectedRoutes: map[string][]gatewayv1.Hostname{},
+			expectedErrors: 1,
+		},
+		{
+			name: "named service port",
+			ingressRoute: IngressRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: IngressRouteSpec{
+					EntryPoints: []string{"web"},
+					Routes: []Route{
+						{Match: "PathPrefix(`/`)", Services: []Service{{Name: "web", Port: intstr.FromString("http")}}},
+					},
+				},
+			},
+			expectedRoutes: map[string][]gatewayv1.Hostname{},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := newResourcesStorage()
+			storage.IngressRoutes[types.NamespacedName{Namespace: tc.ingressRoute.Namespace, Name: tc.ingressRoute.Name}] = &tc.ingressRoute
+			storage.Middlewares[types.NamespacedName{Namespace: "default", Name: "strip-api"}] = stripPrefix
+
+			converter := newResourcesToIRConverter(&i2gw.ProviderConf{})
+			ir, errs := converter.convertToIR(storage)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("Expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+
+			routes := map[string][]gatewayv1.Hostname{}
+			filters := map[string][]gatewayv1.HTTPRouteFilterType{}
+			for key, routeContext := range ir.HTTPRoutes {
+				routes[key.Name] = routeContext.HTTPRoute.Spec.Hostnames
+				for _, rule := range routeContext.HTTPRoute.Spec.Rules {
+					for _, filter := range rule.Filters {
+						filters[key.Name] = append(filters[key.Name], filter.Type)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.expectedRoutes, routes); diff != "" {
+				t.Errorf("Unexpected HTTPRoutes (-want +got): %s", diff)
+			}
+			if tc.expectedFilters == nil {
+				tc.expectedFilters = map[string][]gatewayv1.HTTPRouteFilterType{}
+			}
+			if diff := cmp.Diff(tc.expectedFilters, filters); diff != "" {
+				t.Errorf("Unexpected filters (-want +got): %s", diff)
+			}
+
+			var listeners []gatewayv1.SectionName
+			gatewayContext := ir.Gateways[types.NamespacedName{Namespace: "default", Name: DefaultGatewayClassName}]
+			for _, listener := range gatewayContext.Spec.Listeners {
+				listeners = append(listeners, listener.Name)
+			}
+			if diff := cmp.Diff(tc.expectedListeners, listeners); diff != "" {
+				t.Errorf("Unexpected listeners (-want +got): %s", diff)
+			}
+			if len(ir.ReferenceGrants) != tc.expectedGrants {
+				t.Errorf("Expected %d ReferenceGrants, got %d", tc.expectedGrants, len(ir.ReferenceGrants))
+			}
+		})
+	}
+}
+
+func Test_convertIngressRouteTCPAndUDP(t *testing.T) {
+	storage := newResourcesStorage()
+	storage.IngressRouteTCPs[types.NamespacedName{Namespace: "default", Name: "db"}] = &IngressRouteTCP{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Spec: IngressRouteTCPSpec{
+			EntryPoints: []string{"websecure"},
+			TLS:         &TLS{Passthrough: true},
+			Routes: []RouteTCP{
+				{Match: "HostSNI(`db.example.com`)", Services: []Service{{Name: "db", Port: intstr.FromInt32(5432)}}},
+			},
+		},
+	}
+	storage.IngressRouteUDPs[types.NamespacedName{Namespace: "default", Name: "dns"}] = &IngressRouteUDP{
+		ObjectMeta: metav1.ObjectMeta{Name: "dns", Namespace: "default"},
+		Spec: IngressRouteUDPSpec{
+			EntryPoints: []string{"dns"},
+			Routes:      []RouteUDP{{Services: []Service{{Name: "coredns", Port: intstr.FromInt32(53)}}}},
+		},
+	}
+
+	conf := &i2gw.ProviderConf{ProviderSpecificFlags: map[string]map[string]string{
+		ProviderName: {EntryPointsFlag: "websecure=443,dns=53"},
+	}}
+	converter := newResourcesToIRConverter(conf)
+	ir, errs := converter.convertToIR(storage)
+	if len(errs) != 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	tlsRoute, ok := ir.TLSRoutes[types.NamespacedName{Namespace: "default", Name: "db"}]
+	if !ok {
+		t.Fatalf("Expected TLSRoute default/db, got %v", ir.TLSRoutes)
+	}
+	if diff := cmp.Diff([]gatewayv1.Hostname{"db.example.com"}, tlsRoute.Spec.Hostnames); diff != "" {
+		t.Errorf("Unexpected TLSRoute hostnames (-want +got): %s", diff)
+	}
+	if _, ok := ir.UDPRoutes[types.NamespacedName{Namespace: "default", Name: "dns"}]; !ok {
+		t.Fatalf("Expected UDPRoute default/dns, got %v", ir.UDPRoutes)
+	}
+
+	expectedListeners := []gatewayv1.Listener{
+		{
+			Name:     "db-example-com-websecure-tls",
+			Hostname: ptr.To[gatewayv1.Hostname]("db.example.com"),
+			Port:     443,
+			Protocol: gatewayv1.TLSProtocolType,
+			TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModePassthrough)},
+		},
+		{Name: "dns-udp", Port: 53, Protocol: gatewayv1.UDPProtocolType},
+	}
+	gatewayContext := ir.Gateways[types.NamespacedName{Namespace: "default", Name: DefaultGatewayClassName}]
+	if diff := cmp.Diff(expectedListeners, gatewayContext.Spec.Listeners); diff != "" {
+		t.Errorf("Unexpected listeners (-want +got): %s", diff)
+	}
+}
+
+func Test_parseEntryPoints(t *testing.T) {
+	ports, err := parseEntryPoints("web=8000, websecure=8443")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(map[string]gatewayv1.PortNumber{"web": 8000, "websecure": 8443}, ports); diff != "" {
+		t.Errorf("Unexpected ports (-want +got): %s", diff)
+	}
+	for _, entryPoints := range []string{"web", "web=http", "web=0", "=80"} {
+		if _, err := parseEntryPoints(entryPoints); err == nil {
+			t.Errorf("Expected an error for %q", entryPoints)
+		}
+	}
+}
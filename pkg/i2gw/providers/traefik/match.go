@@ -0,0 +1,363 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traefik
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// matcher is a call of a matcher of a Traefik rule, e.g. Host(`example.com`).
+type matcher struct {
+	name string
+	args []string
+}
+
+func (m matcher) String() string {
+	return fmt.Sprintf("%s(`%s`)", m.name, strings.Join(m.args, "`, `"))
+}
+
+// unsupportedError reports a rule which is valid, but has no Gateway API
+// equivalent.
+type unsupportedError struct {
+	reason string
+}
+
+func (e unsupportedError) Error() string {
+	return e.reason
+}
+
+func isUnsupported(err error) bool {
+	var unsupported unsupportedError
+	return errors.As(err, &unsupported)
+}
+
+// parseRule parses the rule of a Traefik route, and returns it in disjunctive
+// normal form: the route matches the requests matching all the matchers of any
+// of the returned conjunctions. Negated matchers are not supported.
+func parseRule(rule string) ([][]matcher, error) {
+	tokens, err := tokenizeRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty rule")
+	}
+	p := ruleParser{tokens: tokens}
+	conjunctions, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].value)
+	}
+	return conjunctions, nil
+}
+
+type tokenKind int
+
+const (
+	identToken tokenKind = iota
+	stringToken
+	operatorToken
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func tokenizeRule(rule string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(rule); {
+		c := rule[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(rule[i:], "&&") || strings.HasPrefix(rule[i:], "||"):
+			tokens = append(tokens, token{kind: operatorToken, value: rule[i : i+2]})
+			i += 2
+		case c == '!' || c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, token{kind: operatorToken, value: string(c)})
+			i++
+		case c == '`':
+			end := strings.IndexByte(rule[i+1:], '`')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			tokens = append(tokens, token{kind: stringToken, value: rule[i+1 : i+1+end]})
+			i += end + 2
+		case c == '"':
+			end := i + 1
+			for ; end < len(rule) && rule[end] != '"'; end++ {
+				if rule[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(rule) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			value, err := strconv.Unquote(rule[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at %d: %w", i, err)
+			}
+			tokens = append(tokens, token{kind: stringToken, value: value})
+			i = end + 1
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			end := i
+			for end < len(rule) && (rule[end] >= 'a' && rule[end] <= 'z' || rule[end] >= 'A' && rule[end] <= 'Z') {
+				end++
+			}
+			tokens = append(tokens, token{kind: identToken, value: rule[i:end]})
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d", c, i)
+		}
+	}
+	return tokens, nil
+}
+
+type ruleParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *ruleParser) peek(value string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == operatorToken && p.tokens[p.pos].value == value
+}
+
+func (p *ruleParser) expect(value string) error {
+	if !p.peek(value) {
+		if p.pos < len(p.tokens) {
+			return fmt.Errorf("expected %q, got %q", value, p.tokens[p.pos].value)
+		}
+		return fmt.Errorf("expected %q at the end of the rule", value)
+	}
+	p.pos++
+	return nil
+}
+
+// parseOr parses a disjunction of conjunctions.
+func (p *ruleParser) parseOr() ([][]matcher, error) {
+	conjunctions, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("||") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		conjunctions = append(conjunctions, right...)
+	}
+	return conjunctions, nil
+}
+
+// parseAnd parses a conjunction, distributing it over the disjunctions it
+// holds.
+func (p *ruleParser) parseAnd() ([][]matcher, error) {
+	conjunctions, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("&&") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		var distributed [][]matcher
+		for _, l := range conjunctions {
+			for _, r := range right {
+				distributed = append(distributed, append(append([]matcher{}, l...), r...))
+			}
+		}
+		conjunctions = distributed
+	}
+	return conjunctions, nil
+}
+
+func (p *ruleParser) parseUnary() ([][]matcher, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of the rule")
+	}
+	switch t := p.tokens[p.pos]; {
+	case p.peek("!"):
+		return nil, unsupportedError{reason: "negated matchers have no Gateway API equivalent"}
+	case p.peek("("):
+		p.pos++
+		conjunctions, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return conjunctions, p.expect(")")
+	case t.kind == identToken:
+		p.pos++
+		m := matcher{name: t.value}
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		for !p.peek(")") {
+			if len(m.args) > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+			if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != stringToken {
+				return nil, fmt.Errorf("expected a string argument of %s", m.name)
+			}
+			m.args = append(m.args, p.tokens[p.pos].value)
+			p.pos++
+		}
+		p.pos++
+		if len(m.args) == 0 {
+			return nil, fmt.Errorf("%s has no argument", m.name)
+		}
+		return [][]matcher{{m}}, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q", t.value)
+	}
+}
+
+// hostRegexpSubdomainPattern matches the Traefik v2 host regexps matching the
+// subdomains of a domain, e.g. {subdomain:[a-z]+}.example.com.
+var hostRegexpSubdomainPattern = regexp.MustCompile(`^\{[^{}]*\}\.([^{}]+)$`)
+
+// toHTTPRouteMatch converts a conjunction of matchers to the hostnames and the
+// match of an HTTPRoute rule. It also returns the ways the match is broader
+// than the conjunction.
+func toHTTPRouteMatch(conjunction []matcher) ([]string, gatewayv1.HTTPRouteMatch, []string, error) {
+	var (
+		hosts    []string
+		match    gatewayv1.HTTPRouteMatch
+		broader  []string
+		hostSeen bool
+	)
+	setPath := func(m matcher, pathType gatewayv1.PathMatchType) error {
+		if len(m.args) != 1 {
+			return unsupportedError{reason: fmt.Sprintf("%v matches several paths", m)}
+		}
+		if match.Path != nil {
+			return unsupportedError{reason: fmt.Sprintf("%v matches a path along another path matcher", m)}
+		}
+		if pathType != gatewayv1.PathMatchRegularExpression && strings.Contains(m.args[0], "{") {
+			return unsupportedError{reason: fmt.Sprintf("%v matches a path regexp", m)}
+		}
+		match.Path = &gatewayv1.HTTPPathMatch{Type: ptr.To(pathType), Value: ptr.To(m.args[0])}
+		return nil
+	}
+	addHeader := func(m matcher, matchType gatewayv1.HeaderMatchType) error {
+		if len(m.args) != 2 {
+			return fmt.Errorf("%v must have a name and a value", m)
+		}
+		match.Headers = append(match.Headers, gatewayv1.HTTPHeaderMatch{Type: ptr.To(matchType), Name: gatewayv1.HTTPHeaderName(m.args[0]), Value: m.args[1]})
+		return nil
+	}
+	addQueryParam := func(m matcher, matchType gatewayv1.QueryParamMatchType) error {
+		name, value := m.args[0], ""
+		switch {
+		case len(m.args) == 2:
+			value = m.args[1]
+		case len(m.args) == 1 && strings.Contains(m.args[0], "="):
+			// Traefik v2 syntax, Query(`name=value`).
+			name, value, _ = strings.Cut(m.args[0], "=")
+		default:
+			return unsupportedError{reason: fmt.Sprintf("%v doesn't match a single query parameter value", m)}
+		}
+		match.QueryParams = append(match.QueryParams, gatewayv1.HTTPQueryParamMatch{Type: ptr.To(matchType), Name: gatewayv1.HTTPHeaderName(name), Value: value})
+		return nil
+	}
+
+	for _, m := range conjunction {
+		var err error
+		switch strings.ToLower(m.name) {
+		case "host", "hostheader":
+			if hostSeen {
+				return nil, match, nil, unsupportedError{reason: fmt.Sprintf("%v matches hosts along another host matcher", m)}
+			}
+			hostSeen = true
+			hosts = append(hosts, m.args...)
+		case "hostregexp":
+			if hostSeen {
+				return nil, match, nil, unsupportedError{reason: fmt.Sprintf("%v matches hosts along another host matcher", m)}
+			}
+			hostSeen = true
+			for _, arg := range m.args {
+				domain := hostRegexpSubdomainPattern.FindStringSubmatch(arg)
+				if domain == nil {
+					return nil, match, nil, unsupportedError{reason: fmt.Sprintf("%v matches a host regexp", m)}
+				}
+				hosts = append(hosts, "*."+domain[1])
+				broader = append(broader, fmt.Sprintf("%v matches all the subdomains of %s", m, domain[1]))
+			}
+		case "path":
+			err = setPath(m, gatewayv1.PathMatchExact)
+		case "pathprefix":
+			err = setPath(m, gatewayv1.PathMatchPathPrefix)
+		case "pathregexp":
+			err = setPath(m, gatewayv1.PathMatchRegularExpression)
+		case "method":
+			if len(m.args) != 1 || match.Method != nil {
+				return nil, match, nil, unsupportedError{reason: fmt.Sprintf("%v matches several methods", m)}
+			}
+			match.Method = ptr.To(gatewayv1.HTTPMethod(strings.ToUpper(m.args[0])))
+		case "headers", "header":
+			err = addHeader(m, gatewayv1.HeaderMatchExact)
+		case "headersregexp", "headerregexp":
+			err = addHeader(m, gatewayv1.HeaderMatchRegularExpression)
+		case "query":
+			err = addQueryParam(m, gatewayv1.QueryParamMatchExact)
+		case "queryregexp":
+			err = addQueryParam(m, gatewayv1.QueryParamMatchRegularExpression)
+		default:
+			err = unsupportedError{reason: fmt.Sprintf("%v has no Gateway API equivalent", m)}
+		}
+		if err != nil {
+			return nil, match, nil, err
+		}
+	}
+	return hosts, match, broader, nil
+}
+
+// hostSNIs returns the hosts of a TCP rule, matching the server name of the
+// TLS connections, or nil if it matches all the connections.
+func hostSNIs(rule string) ([]string, error) {
+	conjunctions, err := parseRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	var hosts []string
+	for _, conjunction := range conjunctions {
+		if len(conjunction) != 1 || strings.ToLower(conjunction[0].name) != "hostsni" {
+			return nil, unsupportedError{reason: "only HostSNI matchers have a Gateway API equivalent"}
+		}
+		for _, host := range conjunction[0].args {
+			if host == "*" {
+				return nil, nil
+			}
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts, nil
+}
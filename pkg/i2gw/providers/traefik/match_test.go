@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traefik
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_parseRule(t *testing.T) {
+	testCases := []struct {
+		name                string
+		rule                string
+		expected            [][]matcher
+		expectedErr         bool
+		expectedUnsupported bool
+	}{
+		{
+			name:     "single matcher",
+			rule:     "Host(`example.com`)",
+			expected: [][]matcher{{{name: "Host", args: []string{"example.com"}}}},
+		},
+		{
+			name: "conjunction",
+			rule: "Host(`example.com`) && PathPrefix(\"/api\")",
+			expected: [][]matcher{{
+				{name: "Host", args: []string{"example.com"}},
+				{name: "PathPrefix", args: []string{"/api"}},
+			}},
+		},
+		{
+			name: "conjunction distributed over a disjunction",
+			rule: "Host(`example.com`, `www.example.com`) && (PathPrefix(`/api`) || Headers(`X-Api`, `true`))",
+			expected: [][]matcher{
+				{{name: "Host", args: []string{"example.com", "www.example.com"}}, {name: "PathPrefix", args: []string{"/api"}}},
+				{{name: "Host", args: []string{"example.com", "www.example.com"}}, {name: "Headers", args: []string{"X-Api", "true"}}},
+			},
+		},
+		{
+			name:                "negation",
+			rule:                "Host(`example.com`) && !PathPrefix(`/admin`)",
+			expectedErr:         true,
+			expectedUnsupported: true,
+		},
+		{
+			name:        "unterminated string",
+			rule:        "Host(`example.com)",
+			expectedErr: true,
+		},
+		{
+			name:        "missing parenthesis",
+			rule:        "(Host(`example.com`)",
+			expectedErr: true,
+		},
+		{
+			name:        "empty rule",
+			rule:        "",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conjunctions, err := parseRule(tc.rule)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+			if isUnsupported(err) != tc.expectedUnsupported {
+				t.Errorf("Expected unsupported error %v, got %v", tc.expectedUnsupported, err)
+			}
+			if diff := cmp.Diff(tc.expected, conjunctions, cmp.AllowUnexported(matcher{})); diff != "" {
+				t.Errorf("Unexpected conjunctions (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_toHTTPRouteMatch(t *testing.T) {
+	testCases := []struct {
+		name                string
+		conjunction         []matcher
+		expectedHosts       []string
+		expectedMatch       gatewayv1.HTTPRouteMatch
+		expectedBroader     int
+		expectedUnsupported bool
+	}{
+		{
+			name: "host, path prefix and method",
+			conjunction: []matcher{
+				{name: "Host", args: []string{"example.com"}},
+				{name: "PathPrefix", args: []string{"/api"}},
+				{name: "Method", args: []string{"post"}},
+			},
+			expectedHosts: []string{"example.com"},
+			expectedMatch: gatewayv1.HTTPRouteMatch{
+				Path:   &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/api")},
+				Method: ptr.To(gatewayv1.HTTPMethodPost),
+			},
+		},
+		{
+			name: "headers and query parameters",
+			conjunction: []matcher{
+				{name: "Headers", args: []string{"X-Version", "2"}},
+				{name: "HeaderRegexp", args: []string{"X-Tenant", "^a.*"}},
+				{name: "Query", args: []string{"debug=true"}},
+				{name: "Query", args: []string{"mode", "fast"}},
+			},
+			expectedMatch: gatewayv1.HTTPRouteMatch{
+				Headers: []gatewayv1.HTTPHeaderMatch{
+					{Type: ptr.To(gatewayv1.HeaderMatchExact), Name: "X-Version", Value: "2"},
+					{Type: ptr.To(gatewayv1.HeaderMatchRegularExpression), Name: "X-Tenant", Value: "^a.*"},
+				},
+				QueryParams: []gatewayv1.HTTPQueryParamMatch{
+					{Type: ptr.To(gatewayv1.QueryParamMatchExact), Name: "debug", Value: "true"},
+					{Type: ptr.To(gatewayv1.QueryParamMatchExact), Name: "mode", Value: "fast"},
+				},
+			},
+		},
+		{
+			name:            "subdomain host regexp",
+			conjunction:     []matcher{{name: "HostRegexp", args: []string{"{subdomain:[a-z]+}.example.com"}}},
+			expectedHosts:   []string{"*.example.com"},
+			expectedBroader: 1,
+		},
+		{
+			name:                "host regexp",
+			conjunction:         []matcher{{name: "HostRegexp", args: []string{"^api-[0-9]+\\.example\\.com$"}}},
+			expectedUnsupported: true,
+		},
+		{
+			name:                "v2 path regexp",
+			conjunction:         []matcher{{name: "Path", args: []string{"/users/{id:[0-9]+}"}}},
+			expectedUnsupported: true,
+		},
+		{
+			name: "several paths",
+			conjunction: []matcher{
+				{name: "PathPrefix", args: []string{"/api"}},
+				{name: "Path", args: []string{"/api/v1"}},
+			},
+			expectedUnsupported: true,
+		},
+		{
+			name:                "client IP",
+			conjunction:         []matcher{{name: "ClientIP", args: []string{"10.0.0.0/8"}}},
+			expectedUnsupported: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hosts, match, broader, err := toHTTPRouteMatch(tc.conjunction)
+			if isUnsupported(err) != tc.expectedUnsupported {
+				t.Fatalf("Expected unsupported error %v, got %v", tc.expectedUnsupported, err)
+			}
+			if tc.expectedUnsupported {
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedHosts, hosts); diff != "" {
+				t.Errorf("Unexpected hosts (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedMatch, match); diff != "" {
+				t.Errorf("Unexpected match (-want +got): %s", diff)
+			}
+			if len(broader) != tc.expectedBroader {
+				t.Errorf("Expected %d broadened matchers, got %v", tc.expectedBroader, broader)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traefik
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// middlewareFilters returns the HTTPRoute filters of the middlewares of a
+// route with the given matches. Middlewares without Gateway API equivalent
+// are referenced as extensions, which only Traefik supports.
+func (cv *conversion) middlewareFilters(namespace string, refs []MiddlewareRef, matches []gatewayv1.HTTPRouteMatch, ingressRoute *IngressRoute) []gatewayv1.HTTPRouteFilter {
+	var filters []gatewayv1.HTTPRouteFilter
+	for _, ref := range refs {
+		if strings.Contains(ref.Name, "@") {
+			notify(notifications.WarningNotification, fmt.Sprintf("middleware %s of IngressRoute %s/%s is defined by another Traefik provider, it was not converted", ref.Name, ingressRoute.Namespace, ingressRoute.Name), ingressRoute)
+			continue
+		}
+		key := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+		if ref.Namespace != "" {
+			key.Namespace = ref.Namespace
+		}
+		obj, ok := cv.storage.Middlewares[key]
+		if !ok {
+			notify(notifications.WarningNotification, fmt.Sprintf("Middleware %s of IngressRoute %s/%s was not found, it was not converted", key, ingressRoute.Namespace, ingressRoute.Name), ingressRoute)
+			continue
+		}
+
+		var middleware Middleware
+		filter, err := func() (*gatewayv1.HTTPRouteFilter, error) {
+			if spec, _ := obj.Object["spec"].(map[string]interface{}); len(spec) != 1 {
+				return nil, nil
+			}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &middleware); err != nil {
+				return nil, err
+			}
+			return middlewareFilter(middleware.Spec, matches)
+		}()
+		if err != nil {
+			notify(notifications.WarningNotification, fmt.Sprintf("Middleware %s of IngressRoute %s/%s was not converted: %v", key, ingressRoute.Namespace, ingressRoute.Name, err), ingressRoute)
+			continue
+		}
+		if filter == nil {
+			if key.Namespace != namespace {
+				notify(notifications.WarningNotification, fmt.Sprintf("Middleware %s of IngressRoute %s/%s has no Gateway API equivalent, and can't be referenced as an extension from another namespace, it was not converted", key, ingressRoute.Namespace, ingressRoute.Name), ingressRoute)
+				continue
+			}
+			notify(notifications.WarningNotification, fmt.Sprintf("Middleware %s of IngressRoute %s/%s has no Gateway API equivalent, it was referenced as an extension, which only Traefik supports", key, ingressRoute.Namespace, ingressRoute.Name), ingressRoute)
+			filter = &gatewayv1.HTTPRouteFilter{
+				Type:         gatewayv1.HTTPRouteFilterExtensionRef,
+				ExtensionRef: &gatewayv1.LocalObjectReference{Group: "traefik.io", Kind: MiddlewareKind, Name: gatewayv1.ObjectName(key.Name)},
+			}
+		}
+		filters = addFilter(filters, *filter, ingressRoute)
+	}
+	return filters
+}
+
+// middlewareFilter returns the filter equivalent to the middleware, or nil if
+// it has no Gateway API equivalent for the given matches.
+func middlewareFilter(spec MiddlewareSpec, matches []gatewayv1.HTTPRouteMatch) (*gatewayv1.HTTPRouteFilter, error) {
+	switch {
+	case spec.RedirectScheme != nil:
+		redirect := &gatewayv1.HTTPRequestRedirectFilter{StatusCode: ptr.To(302)}
+		if spec.RedirectScheme.Permanent {
+			redirect.StatusCode = ptr.To(301)
+		}
+		if spec.RedirectScheme.Scheme != "" {
+			redirect.Scheme = ptr.To(spec.RedirectScheme.Scheme)
+		}
+		if spec.RedirectScheme.Port != "" {
+			port, err := strconv.ParseInt(spec.RedirectScheme.Port, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid redirect port %q", spec.RedirectScheme.Port)
+			}
+			redirect.Port = ptr.To(gatewayv1.PortNumber(port))
+		}
+		return &gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterRequestRedirect, RequestRedirect: redirect}, nil
+
+	case spec.StripPrefix != nil:
+		// The prefix matched by PathPrefix matches can be replaced, when it
+		// is one of the stripped prefixes.
+		for _, match := range matches {
+			if match.Path == nil || *match.Path.Type != gatewayv1.PathMatchPathPrefix || !slices.ContainsFunc(spec.StripPrefix.Prefixes, func(prefix string) bool {
+				return strings.TrimSuffix(prefix, "/") == strings.TrimSuffix(*match.Path.Value, "/")
+			}) {
+				return nil, nil
+			}
+		}
+		return replacePrefixMatchFilter("/"), nil
+
+	case spec.AddPrefix != nil:
+		// The prefix can be added by replacing the / prefix, matching all the
+		// paths.
+		for _, match := range matches {
+			if match.Path == nil || *match.Path.Type != gatewayv1.PathMatchPathPrefix || *match.Path.Value != "/" {
+				return nil, nil
+			}
+		}
+		return replacePrefixMatchFilter(spec.AddPrefix.Prefix), nil
+
+	case spec.Headers != nil:
+		if spec.Headers.CustomRequestHeaders != nil && spec.Headers.CustomResponseHeaders != nil {
+			return nil, nil
+		}
+		if spec.Headers.CustomRequestHeaders != nil {
+			return &gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier, RequestHeaderModifier: headerFilter(spec.Headers.CustomRequestHeaders)}, nil
+		}
+		if spec.Headers.CustomResponseHeaders != nil {
+			return &gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier, ResponseHeaderModifier: headerFilter(spec.Headers.CustomResponseHeaders)}, nil
+		}
+	}
+	return nil, nil
+}
+
+func replacePrefixMatchFilter(prefix string) *gatewayv1.HTTPRouteFilter {
+	return &gatewayv1.HTTPRouteFilter{
+		Type: gatewayv1.HTTPRouteFilterURLRewrite,
+		URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+			Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr.To(prefix)},
+		},
+	}
+}
+
+// headerFilter returns the header modifier of the custom headers of a headers
+// middleware, which removes the headers with empty values.
+func headerFilter(headers map[string]string) *gatewayv1.HTTPHeaderFilter {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	filter := &gatewayv1.HTTPHeaderFilter{}
+	for _, name := range names {
+		if headers[name] == "" {
+			filter.Remove = append(filter.Remove, name)
+			continue
+		}
+		filter.Set = append(filter.Set, gatewayv1.HTTPHeader{Name: gatewayv1.HTTPHeaderName(name), Value: headers[name]})
+	}
+	return filter
+}
+
+// addFilter adds the filter to the filters of a rule, merging the header
+// modifiers, which may only be set once per rule.
+func addFilter(filters []gatewayv1.HTTPRouteFilter, filter gatewayv1.HTTPRouteFilter, obj client.Object) []gatewayv1.HTTPRouteFilter {
+	i := slices.IndexFunc(filters, func(existing gatewayv1.HTTPRouteFilter) bool { return existing.Type == filter.Type })
+	switch {
+	case i < 0:
+		return append(filters, filter)
+	case filter.Type == gatewayv1.HTTPRouteFilterRequestHeaderModifier:
+		filters[i].RequestHeaderModifier.Set = append(filters[i].RequestHeaderModifier.Set, filter.RequestHeaderModifier.Set...)
+		filters[i].RequestHeaderModifier.Remove = append(filters[i].RequestHeaderModifier.Remove, filter.RequestHeaderModifier.Remove...)
+		return filters
+	case filter.Type == gatewayv1.HTTPRouteFilterResponseHeaderModifier:
+		filters[i].ResponseHeaderModifier.Set = append(filters[i].ResponseHeaderModifier.Set, filter.ResponseHeaderModifier.Set...)
+		filters[i].ResponseHeaderModifier.Remove = append(filters[i].ResponseHeaderModifier.Remove, filter.ResponseHeaderModifier.Remove...)
+		return filters
+	case filter.Type == gatewayv1.HTTPRouteFilterExtensionRef:
+		return append(filters, filter)
+	default:
+		notify(notifications.WarningNotification, fmt.Sprintf("%s filter of a middleware of %s/%s was ignored, a rule can only have one", filter.Type, obj.GetNamespace(), obj.GetName()), obj)
+		return filters
+	}
+}
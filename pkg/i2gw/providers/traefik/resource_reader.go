@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traefik
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reader implements the i2gw.CustomResourceReader interface.
+type reader struct {
+	conf *i2gw.ProviderConf
+}
+
+// newResourceReader returns a resourceReader instance.
+func newResourceReader(conf *i2gw.ProviderConf) reader {
+	return reader{
+		conf: conf,
+	}
+}
+
+func (r *reader) readResourcesFromCluster(ctx context.Context) (*storage, error) {
+	var objects []*unstructured.Unstructured
+	for _, kind := range []string{IngressRouteKind, IngressRouteTCPKind, IngressRouteUDPKind, MiddlewareKind} {
+		kindObjects, err := r.listFromCluster(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, kindObjects...)
+	}
+	return r.readUnstructuredObjects(objects)
+}
+
+// listFromCluster lists the objects of the given kind, of the current API
+// version of the Traefik CRDs, or of the legacy one when only it is served.
+func (r *reader) listFromCluster(ctx context.Context, kind string) ([]*unstructured.Unstructured, error) {
+	for _, apiVersion := range []string{APIVersion, LegacyAPIVersion} {
+		list := &unstructured.UnstructuredList{}
+		list.SetAPIVersion(apiVersion)
+		list.SetKind(kind)
+		err := r.conf.Client.List(ctx, list)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list traefik %s objects: %w", kind, err)
+		}
+		objects := make([]*unstructured.Unstructured, 0, len(list.Items))
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+		return objects, nil
+	}
+	return nil, nil
+}
+
+func (r *reader) readResourcesFromFile(filename string) (*storage, error) {
+	stream, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %v: %w", filename, err)
+	}
+
+	unstructuredObjects, err := common.ExtractObjectsFromReader(bytes.NewReader(stream), r.conf.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract objects: %w", err)
+	}
+
+	return r.readUnstructuredObjects(unstructuredObjects)
+}
+
+func (r *reader) readUnstructuredObjects(objects []*unstructured.Unstructured) (*storage, error) {
+	res := newResourcesStorage()
+
+	for _, obj := range objects {
+		if obj.GetAPIVersion() != APIVersion && obj.GetAPIVersion() != LegacyAPIVersion {
+			continue
+		}
+		key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+
+		var err error
+		switch obj.GetKind() {
+		case IngressRouteKind:
+			var ingressRoute IngressRoute
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &ingressRoute)
+			res.IngressRoutes[key] = &ingressRoute
+		case IngressRouteTCPKind:
+			var ingressRouteTCP IngressRouteTCP
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &ingressRouteTCP)
+			res.IngressRouteTCPs[key] = &ingressRouteTCP
+		case IngressRouteUDPKind:
+			var ingressRouteUDP IngressRouteUDP
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &ingressRouteUDP)
+			res.IngressRouteUDPs[key] = &ingressRouteUDP
+		case MiddlewareKind:
+			res.Middlewares[key] = obj
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse traefik %s object: %w", obj.GetKind(), err)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traefik
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type storage struct {
+	IngressRoutes    map[types.NamespacedName]*IngressRoute
+	IngressRouteTCPs map[types.NamespacedName]*IngressRouteTCP
+	IngressRouteUDPs map[types.NamespacedName]*IngressRouteUDP
+
+	// Middlewares are kept unstructured, to tell the middlewares with
+	// Gateway API equivalent from the others.
+	Middlewares map[types.NamespacedName]*unstructured.Unstructured
+}
+
+func newResourcesStorage() *storage {
+	return &storage{
+		IngressRoutes:    map[types.NamespacedName]*IngressRoute{},
+		IngressRouteTCPs: map[types.NamespacedName]*IngressRouteTCP{},
+		IngressRouteUDPs: map[types.NamespacedName]*IngressRouteUDP{},
+		Middlewares:      map[types.NamespacedName]*unstructured.Unstructured{},
+	}
+}
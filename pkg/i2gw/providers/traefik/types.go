@@ -0,0 +1,234 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traefik
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	APIVersion = "traefik.io/v1alpha1"
+	// LegacyAPIVersion is the API version of the Traefik CRDs before Traefik
+	// v3, still served by Traefik v2.10.
+	LegacyAPIVersion = "traefik.containo.us/v1alpha1"
+
+	IngressRouteKind    = "IngressRoute"
+	IngressRouteTCPKind = "IngressRouteTCP"
+	IngressRouteUDPKind = "IngressRouteUDP"
+	MiddlewareKind      = "Middleware"
+
+	// DefaultGatewayClassName is the name of the GatewayClass of the Gateway
+	// API implementation of Traefik.
+	DefaultGatewayClassName = "traefik"
+)
+
+// The types below mirror the subset of the traefik.io/v1alpha1 API read by the
+// provider, so that it doesn't depend on the Traefik module.
+
+// IngressRoute routes HTTP requests to Services.
+type IngressRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IngressRouteSpec `json:"spec"`
+}
+
+type IngressRouteSpec struct {
+	Routes      []Route  `json:"routes"`
+	EntryPoints []string `json:"entryPoints,omitempty"`
+	TLS         *TLS     `json:"tls,omitempty"`
+}
+
+type Route struct {
+	Match       string          `json:"match"`
+	Kind        string          `json:"kind,omitempty"`
+	Priority    int             `json:"priority,omitempty"`
+	Services    []Service       `json:"services,omitempty"`
+	Middlewares []MiddlewareRef `json:"middlewares,omitempty"`
+}
+
+type Service struct {
+	Name      string             `json:"name"`
+	Namespace string             `json:"namespace,omitempty"`
+	Kind      string             `json:"kind,omitempty"`
+	Port      intstr.IntOrString `json:"port,omitempty"`
+	Weight    *int32             `json:"weight,omitempty"`
+	Scheme    string             `json:"scheme,omitempty"`
+}
+
+type MiddlewareRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type TLS struct {
+	SecretName   string           `json:"secretName,omitempty"`
+	Options      *ObjectReference `json:"options,omitempty"`
+	CertResolver string           `json:"certResolver,omitempty"`
+	// Passthrough is only supported by IngressRouteTCPs.
+	Passthrough bool `json:"passthrough,omitempty"`
+}
+
+type ObjectReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// IngressRouteTCP routes TCP connections to Services.
+type IngressRouteTCP struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IngressRouteTCPSpec `json:"spec"`
+}
+
+type IngressRouteTCPSpec struct {
+	Routes      []RouteTCP `json:"routes"`
+	EntryPoints []string   `json:"entryPoints,omitempty"`
+	TLS         *TLS       `json:"tls,omitempty"`
+}
+
+type RouteTCP struct {
+	Match       string          `json:"match"`
+	Services    []Service       `json:"services,omitempty"`
+	Middlewares []MiddlewareRef `json:"middlewares,omitempty"`
+}
+
+// IngressRouteUDP routes UDP datagrams to Services.
+type IngressRouteUDP struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IngressRouteUDPSpec `json:"spec"`
+}
+
+type IngressRouteUDPSpec struct {
+	Routes      []RouteUDP `json:"routes"`
+	EntryPoints []string   `json:"entryPoints,omitempty"`
+}
+
+type RouteUDP struct {
+	Services []Service `json:"services,omitempty"`
+}
+
+// Middleware tweaks the requests of the routes referencing it. Only the
+// middlewares with Gateway API equivalent are read, the others are referenced
+// as extensions.
+type Middleware struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MiddlewareSpec `json:"spec"`
+}
+
+type MiddlewareSpec struct {
+	RedirectScheme *RedirectScheme `json:"redirectScheme,omitempty"`
+	StripPrefix    *StripPrefix    `json:"stripPrefix,omitempty"`
+	AddPrefix      *AddPrefix      `json:"addPrefix,omitempty"`
+	Headers        *Headers        `json:"headers,omitempty"`
+}
+
+type RedirectScheme struct {
+	Scheme    string `json:"scheme,omitempty"`
+	Port      string `json:"port,omitempty"`
+	Permanent bool   `json:"permanent,omitempty"`
+}
+
+type StripPrefix struct {
+	Prefixes []string `json:"prefixes,omitempty"`
+}
+
+type AddPrefix struct {
+	Prefix string `json:"prefix,omitempty"`
+}
+
+type Headers struct {
+	CustomRequestHeaders  map[string]string `json:"customRequestHeaders,omitempty"`
+	CustomResponseHeaders map[string]string `json:"customResponseHeaders,omitempty"`
+}
+
+// DeepCopyObject implements runtime.Object, for IngressRoutes to be
+// referenced by notifications and as the sources of the generated resources.
+func (r *IngressRoute) DeepCopyObject() runtime.Object {
+	out := &IngressRoute{TypeMeta: r.TypeMeta}
+	r.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.EntryPoints = append([]string(nil), r.Spec.EntryPoints...)
+	out.Spec.TLS = deepCopyTLS(r.Spec.TLS)
+	for _, route := range r.Spec.Routes {
+		route.Services = deepCopyServices(route.Services)
+		route.Middlewares = append([]MiddlewareRef(nil), route.Middlewares...)
+		out.Spec.Routes = append(out.Spec.Routes, route)
+	}
+	return out
+}
+
+// DeepCopyObject implements runtime.Object, for IngressRouteTCPs to be
+// referenced by notifications.
+func (r *IngressRouteTCP) DeepCopyObject() runtime.Object {
+	out := &IngressRouteTCP{TypeMeta: r.TypeMeta}
+	r.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.EntryPoints = append([]string(nil), r.Spec.EntryPoints...)
+	out.Spec.TLS = deepCopyTLS(r.Spec.TLS)
+	for _, route := range r.Spec.Routes {
+		route.Services = deepCopyServices(route.Services)
+		route.Middlewares = append([]MiddlewareRef(nil), route.Middlewares...)
+		out.Spec.Routes = append(out.Spec.Routes, route)
+	}
+	return out
+}
+
+// DeepCopyObject implements runtime.Object, for IngressRouteUDPs to be
+// referenced by notifications.
+func (r *IngressRouteUDP) DeepCopyObject() runtime.Object {
+	out := &IngressRouteUDP{TypeMeta: r.TypeMeta}
+	r.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.EntryPoints = append([]string(nil), r.Spec.EntryPoints...)
+	for _, route := range r.Spec.Routes {
+		route.Services = deepCopyServices(route.Services)
+		out.Spec.Routes = append(out.Spec.Routes, route)
+	}
+	return out
+}
+
+func deepCopyTLS(tls *TLS) *TLS {
+	if tls == nil {
+		return nil
+	}
+	out := *tls
+	if tls.Options != nil {
+		options := *tls.Options
+		out.Options = &options
+	}
+	return &out
+}
+
+func deepCopyServices(services []Service) []Service {
+	if services == nil {
+		return nil
+	}
+	out := make([]Service, len(services))
+	for i, service := range services {
+		out[i] = service
+		if service.Weight != nil {
+			weight := *service.Weight
+			out[i].Weight = &weight
+		}
+	}
+	return out
+}
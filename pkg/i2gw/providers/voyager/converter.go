@@ -0,0 +1,499 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package voyager
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// annotationPrefix is the prefix of the annotations configuring the HAProxy
+// instances of Voyager, which have no Gateway API equivalent.
+const annotationPrefix = "ingress.appscode.com/"
+
+type resourcesToIRConverter struct {
+	gatewayClassName string
+}
+
+func newResourcesToIRConverter(conf *i2gw.ProviderConf) resourcesToIRConverter {
+	converter := resourcesToIRConverter{gatewayClassName: DefaultGatewayClassName}
+	if gatewayClassName := conf.ProviderSpecificFlags[ProviderName][GatewayClassFlag]; gatewayClassName != "" {
+		converter.gatewayClassName = gatewayClassName
+	}
+	return converter
+}
+
+// convertToIR converts each Voyager Ingress to a Gateway of its own, as
+// Voyager runs an HAProxy instance per Ingress, and to the HTTPRoutes and
+// TCPRoutes of its rules.
+func (c *resourcesToIRConverter) convertToIR(storage *storage) (intermediate.IR, field.ErrorList) {
+	ir := intermediate.IR{
+		Gateways:   make(map[types.NamespacedName]intermediate.GatewayContext),
+		HTTPRoutes: make(map[types.NamespacedName]intermediate.HTTPRouteContext),
+		TCPRoutes:  make(map[types.NamespacedName]gatewayv1alpha2.TCPRoute),
+	}
+
+	var errs field.ErrorList
+	for _, key := range sortedKeys(storage.Ingresses) {
+		errs = append(errs, c.convertIngress(storage.Ingresses[key], &ir)...)
+	}
+	return ir, errs
+}
+
+// ingressConversion holds the state of the conversion of a Voyager Ingress.
+type ingressConversion struct {
+	ingress  *Ingress
+	key      types.NamespacedName
+	specPath *field.Path
+	ir       *intermediate.IR
+	gateway  gatewayv1.Gateway
+	// httpRoutesByPort holds the keys of the generated HTTPRoutes by the port
+	// of the listener they are attached to.
+	httpRoutesByPort map[gatewayv1.PortNumber][]types.NamespacedName
+}
+
+func (c *resourcesToIRConverter) convertIngress(ingress *Ingress, ir *intermediate.IR) field.ErrorList {
+	cv := ingressConversion{
+		ingress:  ingress,
+		key:      types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name},
+		specPath: field.NewPath(ProviderName, IngressKind).Key(fmt.Sprintf("%s/%s", ingress.Namespace, ingress.Name)).Child("spec"),
+		ir:       ir,
+		gateway: gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ingress.Namespace, Name: ingress.Name},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: gatewayv1.ObjectName(c.gatewayClassName)},
+		},
+		httpRoutesByPort: map[gatewayv1.PortNumber][]types.NamespacedName{},
+	}
+	cv.gateway.SetGroupVersionKind(common.GatewayGVK)
+
+	var errs field.ErrorList
+	for i, rule := range ingress.Spec.Rules {
+		rulePath := cv.specPath.Child("rules").Index(i)
+		switch {
+		case rule.HTTP != nil:
+			errs = append(errs, cv.convertHTTPRule(rule, rulePath)...)
+		case rule.TCP != nil:
+			errs = append(errs, cv.convertTCPRule(rule, rulePath)...)
+		}
+	}
+
+	defaultBackend, defaultBackendPath := ingress.Spec.DefaultBackend, cv.specPath.Child("defaultBackend")
+	if defaultBackend == nil {
+		defaultBackend, defaultBackendPath = ingress.Spec.Backend, cv.specPath.Child("backend")
+	}
+	if defaultBackend != nil {
+		errs = append(errs, cv.convertDefaultBackend(defaultBackend, defaultBackendPath)...)
+	}
+
+	errs = append(errs, cv.convertFrontendRules()...)
+	cv.notifyUnsupportedAnnotations()
+
+	if len(cv.gateway.Spec.Listeners) > 0 {
+		ir.Gateways[cv.key] = intermediate.GatewayContext{Gateway: cv.gateway}
+	}
+	if len(errs) == 0 {
+		notify(notifications.InfoNotification, fmt.Sprintf("successfully converted Voyager Ingress %s to Gateway %s", cv.key, cv.key), ingress)
+	}
+	return errs
+}
+
+// convertHTTPRule converts the HTTP rule of a host to an HTTPRoute attached
+// to the listener of the host and port of the rule, an HTTPS listener if the
+// host has a certificate.
+func (cv *ingressConversion) convertHTTPRule(rule IngressRule, rulePath *field.Path) field.ErrorList {
+	httpPath := rulePath.Child("http")
+	certificateRefs := cv.certificateRefs(rule.Host)
+	protocol, defaultPort := gatewayv1.HTTPProtocolType, gatewayv1.PortNumber(80)
+	if len(certificateRefs) > 0 && !rule.HTTP.NoTLS {
+		protocol, defaultPort = gatewayv1.HTTPSProtocolType, 443
+	}
+	port, err := portNumber(rule.HTTP.Port, defaultPort, httpPath.Child("port"))
+	if err != nil {
+		return field.ErrorList{err}
+	}
+
+	listener := gatewayv1.Listener{
+		Name:     listenerName(rule.Host, protocol, port, defaultPort),
+		Port:     port,
+		Protocol: protocol,
+	}
+	if hostname := listenerHostname(rule.Host); hostname != "" {
+		listener.Hostname = ptr.To(gatewayv1.Hostname(hostname))
+	}
+	if protocol == gatewayv1.HTTPSProtocolType {
+		listener.TLS = &gatewayv1.GatewayTLSConfig{CertificateRefs: certificateRefs}
+	}
+	cv.addListener(listener)
+
+	routeName := cv.ingress.Name
+	if hostname := listenerHostname(rule.Host); hostname != "" {
+		routeName = fmt.Sprintf("%s-%s", routeName, common.NameFromHost(hostname))
+	}
+	if port != defaultPort {
+		routeName = fmt.Sprintf("%s-%d", routeName, port)
+	}
+	routeKey := types.NamespacedName{Namespace: cv.ingress.Namespace, Name: routeName}
+	httpRouteContext := cv.httpRouteContext(routeKey, listener)
+	if listener.Hostname != nil && len(httpRouteContext.Spec.Hostnames) == 0 {
+		httpRouteContext.Spec.Hostnames = []gatewayv1.Hostname{*listener.Hostname}
+	}
+
+	var errs field.ErrorList
+	for i, path := range rule.HTTP.Paths {
+		pathPath := httpPath.Child("paths").Index(i)
+		backendRef, err := cv.backendRef(path.Backend.IngressBackend, pathPath.Child("backend"))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		value := path.Path
+		if value == "" {
+			value = "/"
+		}
+		httpRouteContext.Spec.Rules = append(httpRouteContext.Spec.Rules, gatewayv1.HTTPRouteRule{
+			Matches: []gatewayv1.HTTPRouteMatch{{
+				Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To(value)},
+			}},
+			BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: backendRef}},
+		})
+		cv.addUnsupportedBackendRules(&httpRouteContext, path.Backend, pathPath.Child("backend"))
+	}
+	cv.storeHTTPRoute(routeKey, listener.Port, httpRouteContext)
+	return errs
+}
+
+// convertTCPRule converts the TCP rule of a port to a TCPRoute attached to
+// the TCP listener of the port.
+func (cv *ingressConversion) convertTCPRule(rule IngressRule, rulePath *field.Path) field.ErrorList {
+	tcpPath := rulePath.Child("tcp")
+	port, err := portNumber(rule.TCP.Port, 0, tcpPath.Child("port"))
+	if err != nil {
+		return field.ErrorList{err}
+	}
+	backendRef, err := cv.backendRef(rule.TCP.Backend, tcpPath.Child("backend"))
+	if err != nil {
+		return field.ErrorList{err}
+	}
+
+	routeKey := types.NamespacedName{Namespace: cv.ingress.Namespace, Name: fmt.Sprintf("%s-tcp-%d", cv.ingress.Name, port)}
+	if _, ok := cv.ir.TCPRoutes[routeKey]; ok {
+		return field.ErrorList{field.Duplicate(tcpPath.Child("port"), port)}
+	}
+	if len(cv.certificateRefs(rule.Host)) > 0 && !rule.TCP.NoTLS {
+		notify(notifications.WarningNotification, fmt.Sprintf("TLS termination of the TCP rule of port %d of Voyager Ingress %s was not converted, the TLS connections are forwarded to the backend", port, cv.key), cv.ingress)
+	}
+	if len(rule.TCP.Backend.BackendRules) > 0 || len(rule.TCP.Backend.HostNames) > 0 {
+		notify(notifications.WarningNotification, fmt.Sprintf("the backend rules and hostNames of the TCP rule of port %d of Voyager Ingress %s have no Gateway API equivalent, they were not converted", port, cv.key), cv.ingress)
+	}
+
+	listener := gatewayv1.Listener{
+		Name:     gatewayv1.SectionName(fmt.Sprintf("tcp-%d", port)),
+		Port:     port,
+		Protocol: gatewayv1.TCPProtocolType,
+	}
+	cv.addListener(listener)
+
+	tcpRoute := gatewayv1alpha2.TCPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec: gatewayv1alpha2.TCPRouteSpec{
+			CommonRouteSpec: cv.commonRouteSpec(listener.Name),
+			Rules:           []gatewayv1alpha2.TCPRouteRule{{BackendRefs: []gatewayv1.BackendRef{backendRef}}},
+		},
+	}
+	tcpRoute.SetGroupVersionKind(common.TCPRouteGVK)
+	cv.ir.TCPRoutes[routeKey] = tcpRoute
+	return nil
+}
+
+// convertDefaultBackend converts the default backend of the Ingress to an
+// HTTPRoute attached to a listener of port 80 without hostname.
+func (cv *ingressConversion) convertDefaultBackend(backend *HTTPIngressBackend, path *field.Path) field.ErrorList {
+	backendRef, err := cv.backendRef(backend.IngressBackend, path)
+	if err != nil {
+		return field.ErrorList{err}
+	}
+
+	listener := gatewayv1.Listener{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}
+	cv.addListener(listener)
+
+	routeKey := types.NamespacedName{Namespace: cv.ingress.Namespace, Name: fmt.Sprintf("%s-default-backend", cv.ingress.Name)}
+	httpRouteContext := cv.httpRouteContext(routeKey, listener)
+	httpRouteContext.Spec.Rules = append(httpRouteContext.Spec.Rules, gatewayv1.HTTPRouteRule{
+		BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: backendRef}},
+	})
+	cv.addUnsupportedBackendRules(&httpRouteContext, *backend, path)
+	cv.storeHTTPRoute(routeKey, listener.Port, httpRouteContext)
+	return nil
+}
+
+// convertFrontendRules records the raw HAProxy rules of the frontends as
+// unsupported features of the HTTPRoutes of their port.
+func (cv *ingressConversion) convertFrontendRules() field.ErrorList {
+	var errs field.ErrorList
+	for i, frontendRule := range cv.ingress.Spec.FrontendRules {
+		frontendRulePath := cv.specPath.Child("frontendRules").Index(i)
+		port, err := portNumber(frontendRule.Port, 0, frontendRulePath.Child("port"))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		notify(notifications.WarningNotification, fmt.Sprintf("the frontend rules of port %d of Voyager Ingress %s are raw HAProxy rules, they were not converted", port, cv.key), cv.ingress)
+		for _, routeKey := range cv.httpRoutesByPort[port] {
+			httpRouteContext := cv.ir.HTTPRoutes[routeKey]
+			for _, rule := range frontendRule.Rules {
+				cv.addUnsupportedFeature(&httpRouteContext, frontendRulePath.Child("rules").String(), rule)
+			}
+			cv.ir.HTTPRoutes[routeKey] = httpRouteContext
+		}
+	}
+	return errs
+}
+
+// notifyUnsupportedAnnotations notifies about the annotations configuring
+// HAProxy, which are not converted.
+func (cv *ingressConversion) notifyUnsupportedAnnotations() {
+	var annotations []string
+	for annotation := range cv.ingress.Annotations {
+		if strings.HasPrefix(annotation, annotationPrefix) {
+			annotations = append(annotations, annotation)
+		}
+	}
+	if len(annotations) == 0 {
+		return
+	}
+	slices.Sort(annotations)
+	notify(notifications.WarningNotification, fmt.Sprintf("annotations %v of Voyager Ingress %s have no Gateway API equivalent, they were not converted", annotations, cv.key), cv.ingress)
+}
+
+// addUnsupportedBackendRules records the raw HAProxy rules and the hostNames
+// of the backend as unsupported features of the HTTPRoute.
+func (cv *ingressConversion) addUnsupportedBackendRules(httpRouteContext *intermediate.HTTPRouteContext, backend HTTPIngressBackend, path *field.Path) {
+	rulesByField := []struct {
+		name  string
+		rules []string
+	}{
+		{name: "backendRules", rules: backend.BackendRules},
+		{name: "headerRules", rules: backend.HeaderRules},
+		{name: "rewriteRules", rules: backend.RewriteRules},
+		{name: "hostNames", rules: backend.HostNames},
+	}
+	for _, backendField := range rulesByField {
+		if len(backendField.rules) == 0 {
+			continue
+		}
+		fieldPath := path.Child(backendField.name)
+		notify(notifications.WarningNotification, fmt.Sprintf("%s of Voyager Ingress %s have no Gateway API equivalent, they were not converted", fieldPath, cv.key), cv.ingress)
+		for _, rule := range backendField.rules {
+			cv.addUnsupportedFeature(httpRouteContext, fieldPath.String(), rule)
+		}
+	}
+}
+
+func (cv *ingressConversion) addUnsupportedFeature(httpRouteContext *intermediate.HTTPRouteContext, name, rawConfig string) {
+	feature := intermediate.UnsupportedFeature{
+		SourceKind: IngressKind,
+		Source:     cv.key,
+		Name:       name,
+		RawConfig:  rawConfig,
+	}
+	if !slices.Contains(httpRouteContext.UnsupportedFeatures, feature) {
+		httpRouteContext.UnsupportedFeatures = append(httpRouteContext.UnsupportedFeatures, feature)
+	}
+}
+
+// httpRouteContext returns the HTTPRoute of the given key attached to the
+// given listener, creating it if needed.
+func (cv *ingressConversion) httpRouteContext(routeKey types.NamespacedName, listener gatewayv1.Listener) intermediate.HTTPRouteContext {
+	if httpRouteContext, ok := cv.ir.HTTPRoutes[routeKey]; ok {
+		return httpRouteContext
+	}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name},
+		Spec:       gatewayv1.HTTPRouteSpec{CommonRouteSpec: cv.commonRouteSpec(listener.Name)},
+	}
+	httpRoute.SetGroupVersionKind(common.HTTPRouteGVK)
+	return intermediate.HTTPRouteContext{
+		HTTPRoute: httpRoute,
+		Sources:   []client.Object{cv.ingress},
+	}
+}
+
+// storeHTTPRoute stores the HTTPRoute of the given key, attached to a
+// listener of the given port, unless it has no rule.
+func (cv *ingressConversion) storeHTTPRoute(routeKey types.NamespacedName, port gatewayv1.PortNumber, httpRouteContext intermediate.HTTPRouteContext) {
+	if len(httpRouteContext.Spec.Rules) == 0 {
+		return
+	}
+	if _, ok := cv.ir.HTTPRoutes[routeKey]; !ok {
+		cv.httpRoutesByPort[port] = append(cv.httpRoutesByPort[port], routeKey)
+	}
+	cv.ir.HTTPRoutes[routeKey] = httpRouteContext
+}
+
+func (cv *ingressConversion) commonRouteSpec(sectionName gatewayv1.SectionName) gatewayv1.CommonRouteSpec {
+	return gatewayv1.CommonRouteSpec{
+		ParentRefs: []gatewayv1.ParentReference{{
+			Name:        gatewayv1.ObjectName(cv.gateway.Name),
+			SectionName: ptr.To(sectionName),
+		}},
+	}
+}
+
+// addListener adds the listener to the Gateway, unless it already has a
+// listener of the same name.
+func (cv *ingressConversion) addListener(listener gatewayv1.Listener) {
+	if slices.ContainsFunc(cv.gateway.Spec.Listeners, func(existing gatewayv1.Listener) bool { return existing.Name == listener.Name }) {
+		return
+	}
+	cv.gateway.Spec.Listeners = append(cv.gateway.Spec.Listeners, listener)
+}
+
+// certificateRefs returns the references to the certificates of the given
+// host, set by the TLS entries of the Ingress. Voyager Certificates can't be
+// referenced by listeners, they are skipped.
+func (cv *ingressConversion) certificateRefs(host string) []gatewayv1.SecretObjectReference {
+	var refs []gatewayv1.SecretObjectReference
+	for i, tls := range cv.ingress.Spec.TLS {
+		if !slices.ContainsFunc(tls.Hosts, func(tlsHost string) bool { return hostMatches(tlsHost, host) }) {
+			continue
+		}
+		secretName := tls.SecretName
+		if tls.Ref != nil {
+			if tls.Ref.Kind != "" && tls.Ref.Kind != "Secret" {
+				notify(notifications.WarningNotification, fmt.Sprintf("%s %s referenced by %s of Voyager Ingress %s can't be referenced by a listener, reference the Secret holding its certificate instead", tls.Ref.Kind, tls.Ref.Name, cv.specPath.Child("tls").Index(i).Child("ref"), cv.key), cv.ingress)
+				continue
+			}
+			secretName = tls.Ref.Name
+		}
+		ref := gatewayv1.SecretObjectReference{Name: gatewayv1.ObjectName(secretName)}
+		if secretName != "" && !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// backendRef returns the reference to the Service of the given backend.
+func (cv *ingressConversion) backendRef(backend IngressBackend, path *field.Path) (gatewayv1.BackendRef, *field.Error) {
+	if backend.ServiceName == "" {
+		return gatewayv1.BackendRef{}, field.Required(path.Child("serviceName"), "the Service of the backend must be set")
+	}
+	if backend.ServicePort.Type == intstr.String {
+		if _, err := strconv.ParseInt(backend.ServicePort.StrVal, 10, 32); err != nil {
+			return gatewayv1.BackendRef{}, field.Invalid(path.Child("servicePort"), backend.ServicePort.StrVal, "named ports are not supported, use the port number")
+		}
+	}
+	port, err := portNumber(backend.ServicePort, 0, path.Child("servicePort"))
+	if err != nil {
+		return gatewayv1.BackendRef{}, err
+	}
+	return gatewayv1.BackendRef{
+		BackendObjectReference: gatewayv1.BackendObjectReference{
+			Name: gatewayv1.ObjectName(backend.ServiceName),
+			Port: ptr.To(port),
+		},
+	}, nil
+}
+
+// portNumber returns the number of the given port, or the given default port
+// if it isn't set. A port is required if the default port is 0.
+func portNumber(port intstr.IntOrString, defaultPort gatewayv1.PortNumber, path *field.Path) (gatewayv1.PortNumber, *field.Error) {
+	number := int64(port.IntVal)
+	if port.Type == intstr.String {
+		if port.StrVal == "" {
+			number = 0
+		} else {
+			parsed, err := strconv.ParseInt(port.StrVal, 10, 32)
+			if err != nil {
+				return 0, field.Invalid(path, port.StrVal, "must be a port number")
+			}
+			number = parsed
+		}
+	}
+	if number == 0 {
+		if defaultPort == 0 {
+			return 0, field.Required(path, "the port must be set")
+		}
+		return defaultPort, nil
+	}
+	if number < 1 || number > 65535 {
+		return 0, field.Invalid(path, number, "must be a port number")
+	}
+	return gatewayv1.PortNumber(number), nil
+}
+
+// listenerName returns the name of the listener of the given host, protocol
+// and port, e.g. foo-example-com-https, suffixed by the port if it isn't the
+// default port of the protocol.
+func listenerName(host string, protocol gatewayv1.ProtocolType, port, defaultPort gatewayv1.PortNumber) gatewayv1.SectionName {
+	name := strings.ToLower(string(protocol))
+	if hostname := listenerHostname(host); hostname != "" {
+		name = fmt.Sprintf("%s-%s", common.NameFromHost(hostname), name)
+	}
+	if port != defaultPort {
+		name = fmt.Sprintf("%s-%d", name, port)
+	}
+	return gatewayv1.SectionName(name)
+}
+
+// listenerHostname returns the hostname of the listener of the given host,
+// none for the * host matching all the hosts.
+func listenerHostname(host string) string {
+	if common.MatchesAllHosts(host) {
+		return ""
+	}
+	return host
+}
+
+// hostMatches returns whether the given TLS host, possibly a wildcard, covers
+// the given rule host.
+func hostMatches(tlsHost, host string) bool {
+	if tlsHost == host {
+		return true
+	}
+	if suffix, ok := strings.CutPrefix(tlsHost, "*."); ok {
+		prefix, found := strings.CutSuffix(host, "."+suffix)
+		return found && prefix != "" && !strings.Contains(prefix, ".")
+	}
+	return false
+}
+
+func sortedKeys[T any](m map[types.NamespacedName]T) []types.NamespacedName {
+	keys := make([]types.NamespacedName, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return keys
+}
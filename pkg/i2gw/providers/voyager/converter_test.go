@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package voyager
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_convertIngress(t *testing.T) {
+	testCases := []struct {
+		name              string
+		ingress           Ingress
+		expectedListeners []gatewayv1.SectionName
+		expectedHTTPRoute []string
+		expectedTCPRoutes []string
+		expectedErrors    int
+	}{
+		{
+			name: "http rules with and without tls",
+			ingress: Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: IngressSpec{
+					TLS: []IngressTLS{{Hosts: []string{"*.example.com"}, Ref: &LocalTypedReference{Kind: "Secret", Name: "wildcard"}}},
+					Rules: []IngressRule{
+						{Host: "a.example.com", HTTP: &HTTPIngressRuleValue{Paths: []HTTPIngressPath{{Backend: HTTPIngressBackend{IngressBackend: IngressBackend{ServiceName: "a", ServicePort: intstr.FromInt32(80)}}}}}},
+						{Host: "b.example.com", HTTP: &HTTPIngressRuleValue{NoTLS: true, Paths: []HTTPIngressPath{{Backend: HTTPIngressBackend{IngressBackend: IngressBackend{ServiceName: "b", ServicePort: intstr.FromInt32(80)}}}}}},
+						{Host: "c.example.org", HTTP: &HTTPIngressRuleValue{Paths: []HTTPIngressPath{{Backend: HTTPIngressBackend{IngressBackend: IngressBackend{ServiceName: "c", ServicePort: intstr.FromInt32(80)}}}}}},
+					},
+				},
+			},
+			expectedListeners: []gatewayv1.SectionName{"a-example-com-https", "b-example-com-http", "c-example-org-http"},
+			expectedHTTPRoute: []string{"app-a-example-com", "app-b-example-com", "app-c-example-org"},
+		},
+		{
+			name: "tcp rules",
+			ingress: Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+				Spec: IngressSpec{
+					Rules: []IngressRule{
+						{TCP: &TCPIngressRuleValue{Port: intstr.FromString("5432"), Backend: IngressBackend{ServiceName: "postgres", ServicePort: intstr.FromInt32(5432)}}},
+						{TCP: &TCPIngressRuleValue{Port: intstr.FromInt32(6379), Backend: IngressBackend{ServiceName: "redis", ServicePort: intstr.FromInt32(6379)}}},
+					},
+				},
+			},
+			expectedListeners: []gatewayv1.SectionName{"tcp-5432", "tcp-6379"},
+			expectedTCPRoutes: []string{"db-tcp-5432", "db-tcp-6379"},
+		},
+		{
+			name: "invalid rules",
+			ingress: Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "default"},
+				Spec: IngressSpec{
+					Rules: []IngressRule{
+						{TCP: &TCPIngressRuleValue{Backend: IngressBackend{ServiceName: "no-port", ServicePort: intstr.FromInt32(80)}}},
+						{TCP: &TCPIngressRuleValue{Port: intstr.FromInt32(9000), Backend: IngressBackend{ServiceName: "named", ServicePort: intstr.FromString("http")}}},
+						{TCP: &TCPIngressRuleValue{Port: intstr.FromInt32(9001), Backend: IngressBackend{ServiceName: "svc", ServicePort: intstr.FromInt32(80)}}},
+						{TCP: &TCPIngressRuleValue{Port: intstr.FromInt32(9001), Backend: IngressBackend{ServiceName: "svc", ServicePort: intstr.FromInt32(80)}}},
+						{HTTP: &HTTPIngressRuleValue{Paths: []HTTPIngressPath{{Backend: HTTPIngressBackend{IngressBackend: IngressBackend{ServicePort: intstr.FromInt32(80)}}}}}},
+					},
+				},
+			},
+			expectedListeners: []gatewayv1.SectionName{"tcp-9001", "http"},
+			expectedTCPRoutes: []string{"invalid-tcp-9001"},
+			expectedErrors:    4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := newResourcesStorage()
+			storage.Ingresses[types.NamespacedName{Namespace: tc.ingress.Namespace, Name: tc.ingress.Name}] = &tc.ingress
+			converter := newResourcesToIRConverter(&i2gw.ProviderConf{})
+
+			ir, errs := converter.convertToIR(storage)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("expected %d errors, got %d: %v", tc.expectedErrors, len(errs), errs)
+			}
+
+			var listeners []gatewayv1.SectionName
+			for _, listener := range ir.Gateways[types.NamespacedName{Namespace: tc.ingress.Namespace, Name: tc.ingress.Name}].Spec.Listeners {
+				listeners = append(listeners, listener.Name)
+			}
+			if diff := cmp.Diff(tc.expectedListeners, listeners); diff != "" {
+				t.Errorf("unexpected listeners (-want +got):\n%s", diff)
+			}
+
+			var httpRoutes, tcpRoutes []string
+			for _, key := range sortedKeys(ir.HTTPRoutes) {
+				httpRoutes = append(httpRoutes, key.Name)
+			}
+			for _, key := range sortedKeys(ir.TCPRoutes) {
+				tcpRoutes = append(tcpRoutes, key.Name)
+			}
+			if diff := cmp.Diff(tc.expectedHTTPRoute, httpRoutes); diff != "" {
+				t.Errorf("unexpected HTTPRoutes (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedTCPRoutes, tcpRoutes); diff != "" {
+				t.Errorf("unexpected TCPRoutes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_portNumber(t *testing.T) {
+	path := field.NewPath("port")
+	testCases := []struct {
+		port        intstr.IntOrString
+		defaultPort gatewayv1.PortNumber
+		expected    gatewayv1.PortNumber
+		expectErr   bool
+	}{
+		{port: intstr.FromInt32(8080), defaultPort: 80, expected: 8080},
+		{port: intstr.FromString("8443"), expected: 8443},
+		{port: intstr.IntOrString{}, defaultPort: 443, expected: 443},
+		{port: intstr.IntOrString{}, expectErr: true},
+		{port: intstr.FromString("http"), defaultPort: 80, expectErr: true},
+		{port: intstr.FromInt32(70000), expectErr: true},
+	}
+	for _, tc := range testCases {
+		port, err := portNumber(tc.port, tc.defaultPort, path)
+		if tc.expectErr != (err != nil) {
+			t.Errorf("portNumber(%v): expected error %v, got %v", tc.port, tc.expectErr, err)
+			continue
+		}
+		if port != tc.expected {
+			t.Errorf("portNumber(%v): expected %d, got %d", tc.port, tc.expected, port)
+		}
+	}
+}
+
+func Test_hostMatches(t *testing.T) {
+	testCases := []struct {
+		tlsHost, host string
+		expected      bool
+	}{
+		{tlsHost: "foo.example.com", host: "foo.example.com", expected: true},
+		{tlsHost: "*.example.com", host: "foo.example.com", expected: true},
+		{tlsHost: "*.example.com", host: "example.com"},
+		{tlsHost: "*.example.com", host: "a.b.example.com"},
+		{tlsHost: "foo.example.com", host: "bar.example.com"},
+	}
+	for _, tc := range testCases {
+		if got := hostMatches(tc.tlsHost, tc.host); got != tc.expected {
+			t.Errorf("hostMatches(%q, %q): expected %v, got %v", tc.tlsHost, tc.host, tc.expected, got)
+		}
+	}
+}
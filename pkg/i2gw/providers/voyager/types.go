@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package voyager
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	APIVersion = "voyager.appscode.com/v1"
+	// LegacyAPIVersion is the API version of the Voyager CRDs before Voyager
+	// v12.
+	LegacyAPIVersion = "voyager.appscode.com/v1beta1"
+
+	IngressKind = "Ingress"
+
+	// DefaultGatewayClassName is the name of the GatewayClass of the generated
+	// Gateways. Voyager has no Gateway API implementation, the GatewayClass of
+	// the implementation migrated to is expected to be set with the
+	// gateway-class-name flag.
+	DefaultGatewayClassName = "voyager"
+)
+
+// The types below mirror the subset of the voyager.appscode.com API read by
+// the provider, so that it doesn't depend on the Voyager module.
+
+// Ingress is the Ingress CRD of Voyager, extending the Kubernetes Ingress
+// with TCP rules and raw HAProxy rules.
+type Ingress struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IngressSpec `json:"spec"`
+}
+
+type IngressSpec struct {
+	// Backend is the default backend of the v1beta1 API, renamed
+	// DefaultBackend by the v1 API.
+	Backend        *HTTPIngressBackend `json:"backend,omitempty"`
+	DefaultBackend *HTTPIngressBackend `json:"defaultBackend,omitempty"`
+	TLS            []IngressTLS        `json:"tls,omitempty"`
+	FrontendRules  []FrontendRule      `json:"frontendRules,omitempty"`
+	Rules          []IngressRule       `json:"rules,omitempty"`
+}
+
+type IngressTLS struct {
+	Hosts      []string             `json:"hosts,omitempty"`
+	SecretName string               `json:"secretName,omitempty"`
+	Ref        *LocalTypedReference `json:"ref,omitempty"`
+}
+
+// LocalTypedReference references the certificate of an IngressTLS, a Secret
+// or a Voyager Certificate.
+type LocalTypedReference struct {
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name"`
+}
+
+// FrontendRule holds raw HAProxy rules applied to the frontend of a port.
+type FrontendRule struct {
+	Port  intstr.IntOrString `json:"port,omitempty"`
+	Rules []string           `json:"rules,omitempty"`
+}
+
+type IngressRule struct {
+	Host string                `json:"host,omitempty"`
+	HTTP *HTTPIngressRuleValue `json:"http,omitempty"`
+	TCP  *TCPIngressRuleValue  `json:"tcp,omitempty"`
+}
+
+type HTTPIngressRuleValue struct {
+	Port  intstr.IntOrString `json:"port,omitempty"`
+	NoTLS bool               `json:"noTLS,omitempty"`
+	Paths []HTTPIngressPath  `json:"paths"`
+}
+
+type HTTPIngressPath struct {
+	Path    string             `json:"path,omitempty"`
+	Backend HTTPIngressBackend `json:"backend"`
+}
+
+type HTTPIngressBackend struct {
+	IngressBackend `json:",inline"`
+
+	// HeaderRules and RewriteRules are raw HAProxy rules, deprecated in
+	// favor of BackendRules.
+	HeaderRules  []string `json:"headerRules,omitempty"`
+	RewriteRules []string `json:"rewriteRules,omitempty"`
+}
+
+type IngressBackend struct {
+	ServiceName string             `json:"serviceName,omitempty"`
+	ServicePort intstr.IntOrString `json:"servicePort,omitempty"`
+	// HostNames restricts the backends to the pods of the given hostnames.
+	HostNames []string `json:"hostNames,omitempty"`
+	// BackendRules are raw HAProxy rules applied to the backend.
+	BackendRules []string `json:"backendRules,omitempty"`
+}
+
+type TCPIngressRuleValue struct {
+	Port    intstr.IntOrString `json:"port,omitempty"`
+	NoTLS   bool               `json:"noTLS,omitempty"`
+	Backend IngressBackend     `json:"backend"`
+}
+
+// DeepCopyObject implements runtime.Object, for Ingresses to be referenced by
+// notifications and as the sources of the generated resources.
+func (in *Ingress) DeepCopyObject() runtime.Object {
+	out := &Ingress{TypeMeta: in.TypeMeta}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.Backend = deepCopyHTTPIngressBackend(in.Spec.Backend)
+	out.Spec.DefaultBackend = deepCopyHTTPIngressBackend(in.Spec.DefaultBackend)
+	for _, tls := range in.Spec.TLS {
+		tls.Hosts = append([]string(nil), tls.Hosts...)
+		if tls.Ref != nil {
+			ref := *tls.Ref
+			tls.Ref = &ref
+		}
+		out.Spec.TLS = append(out.Spec.TLS, tls)
+	}
+	for _, frontendRule := range in.Spec.FrontendRules {
+		frontendRule.Rules = append([]string(nil), frontendRule.Rules...)
+		out.Spec.FrontendRules = append(out.Spec.FrontendRules, frontendRule)
+	}
+	for _, rule := range in.Spec.Rules {
+		if rule.HTTP != nil {
+			http := *rule.HTTP
+			http.Paths = nil
+			for _, path := range rule.HTTP.Paths {
+				path.Backend = *deepCopyHTTPIngressBackend(&path.Backend)
+				http.Paths = append(http.Paths, path)
+			}
+			rule.HTTP = &http
+		}
+		if rule.TCP != nil {
+			tcp := *rule.TCP
+			tcp.Backend = deepCopyIngressBackend(tcp.Backend)
+			rule.TCP = &tcp
+		}
+		out.Spec.Rules = append(out.Spec.Rules, rule)
+	}
+	return out
+}
+
+func deepCopyHTTPIngressBackend(in *HTTPIngressBackend) *HTTPIngressBackend {
+	if in == nil {
+		return nil
+	}
+	return &HTTPIngressBackend{
+		IngressBackend: deepCopyIngressBackend(in.IngressBackend),
+		HeaderRules:    append([]string(nil), in.HeaderRules...),
+		RewriteRules:   append([]string(nil), in.RewriteRules...),
+	}
+}
+
+func deepCopyIngressBackend(in IngressBackend) IngressBackend {
+	in.HostNames = append([]string(nil), in.HostNames...)
+	in.BackendRules = append([]string(nil), in.BackendRules...)
+	return in
+}
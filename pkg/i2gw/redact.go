@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const secretKind = "Secret"
+
+// extensionSecretNameFields are the fields holding Secret names in the
+// extensions generated by the emitters and by the conversion, by kind of
+// extension. References with an explicit Secret kind are redacted regardless.
+var extensionSecretNameFields = map[schema.GroupKind][][]string{
+	CertManagerCertificateGVK.GroupKind(): {
+		// Certificates are named after the Secret they issue.
+		{"metadata", "name"},
+		{"spec", "secretName"},
+	},
+	{Group: "gateway.envoyproxy.io", Kind: "SecurityPolicy"}: {{"spec", "basicAuth", "users", "name"}},
+	{Group: "traefik.io", Kind: "Middleware"}:                {{"spec", "basicAuth", "secret"}},
+	{Group: "networking.gke.io", Kind: "GCPBackendPolicy"}:   {{"spec", "default", "iap", "oauth2ClientSecret", "name"}},
+}
+
+// RedactGatewayResources masks the names of all the Secrets referenced by the
+// given Gateway API resources, so the output can be shared without disclosing
+// sensitive configuration. Redacted names are stable, hence references between
+// the redacted resources remain consistent.
+func RedactGatewayResources(gatewayResources []GatewayResources) {
+	for _, r := range gatewayResources {
+		for key, gateway := range r.Gateways {
+			for i := range gateway.Spec.Listeners {
+				tls := gateway.Spec.Listeners[i].TLS
+				if tls == nil {
+					continue
+				}
+				for j, ref := range tls.CertificateRefs {
+					if isSecretReference(ref.Group, ref.Kind) {
+						tls.CertificateRefs[j].Name = gatewayv1.ObjectName(notifications.RedactName(string(ref.Name)))
+					}
+				}
+			}
+			r.Gateways[key] = gateway
+		}
+
+		for key, referenceGrant := range r.ReferenceGrants {
+			for i, to := range referenceGrant.Spec.To {
+				if to.Group == "" && to.Kind == secretKind && to.Name != nil {
+					redactedName := gatewayv1.ObjectName(notifications.RedactName(string(*to.Name)))
+					referenceGrant.Spec.To[i].Name = &redactedName
+				}
+			}
+			r.ReferenceGrants[key] = referenceGrant
+		}
+
+		for key, policy := range r.BackendTLSPolicies {
+			for i, ref := range policy.Spec.Validation.CACertificateRefs {
+				if ref.Group == "" && ref.Kind == secretKind {
+					policy.Spec.Validation.CACertificateRefs[i].Name = gatewayv1.ObjectName(notifications.RedactName(string(ref.Name)))
+				}
+			}
+			r.BackendTLSPolicies[key] = policy
+		}
+
+		for i := range r.GatewayExtensions {
+			redactExtension(&r.GatewayExtensions[i])
+		}
+
+		// The raw configuration of unsupported features may embed credentials.
+		for key, features := range r.UnsupportedFeatures {
+			for i := range features {
+				features[i].RawConfig = notifications.RedactString(features[i].RawConfig)
+			}
+			r.UnsupportedFeatures[key] = features
+		}
+	}
+}
+
+// isSecretReference returns true if the group and kind of a reference point to
+// a core Secret. Empty kinds default to Secret for certificate references.
+func isSecretReference(group *gatewayv1.Group, kind *gatewayv1.Kind) bool {
+	if group != nil && *group != "" {
+		return false
+	}
+	return kind == nil || *kind == secretKind
+}
+
+// redactExtension masks the Secret names of the known Secret fields of the
+// extension and of its Secret references, and the credentials of its string
+// values, e.g. the URLs of external authentication services.
+func redactExtension(extension *unstructured.Unstructured) {
+	for _, fields := range extensionSecretNameFields[extension.GroupVersionKind().GroupKind()] {
+		if name, found, _ := unstructured.NestedString(extension.Object, fields...); found {
+			_ = unstructured.SetNestedField(extension.Object, notifications.RedactName(name), fields...)
+		}
+	}
+	if spec, ok := extension.Object["spec"]; ok {
+		extension.Object["spec"] = redactValue(spec)
+	}
+}
+
+// redactValue returns the value with the names of its Secret references masked
+// and the credentials of its strings redacted.
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			v[key] = redactValue(nested)
+		}
+		if group, _ := v["group"].(string); group == "" && v["kind"] == secretKind {
+			if name, ok := v["name"].(string); ok {
+				v["name"] = notifications.RedactName(name)
+			}
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+		return v
+	case string:
+		return notifications.RedactString(v)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// ConversionResult is the result of a conversion run.
+type ConversionResult struct {
+	// GatewayResources are the generated resources of each provider, in the
+	// order of the provider names.
+	GatewayResources []GatewayResources
+
+	// Resources are the generated resources by GroupVersionKind, each along
+	// with the notifications about it, for tools to show them next to the
+	// generated manifests.
+	Resources map[schema.GroupVersionKind][]ResourceResult
+
+	// NotificationTables are the notifications of each provider, formatted
+	// as a table, by provider name.
+	NotificationTables map[string]string
+}
+
+// ResourceResult is a generated resource, along with the notifications of the
+// conversion about it.
+type ResourceResult struct {
+	// Provider is the provider the resource was generated by.
+	Provider ProviderName
+
+	// Object is the generated resource. Typed Gateway API resources are
+	// pointers to their Go types, and the GatewayExtensions are
+	// *unstructured.Unstructured.
+	Object client.Object
+
+	// Notifications are the notifications of the provider about the
+	// resource, i.e. whose calling objects include it.
+	Notifications []notifications.Notification
+}
+
+// newConversionResult returns the ConversionResult of the given resources, by
+// provider, with the notifications about each of them.
+func newConversionResult(gatewayResourcesByProvider map[ProviderName]*GatewayResources, notificationTables map[string]string) ConversionResult {
+	providerNames := sortedProviderNames(gatewayResourcesByProvider)
+
+	result := ConversionResult{
+		GatewayResources:   make([]GatewayResources, 0, len(providerNames)),
+		Resources:          map[schema.GroupVersionKind][]ResourceResult{},
+		NotificationTables: notificationTables,
+	}
+	for _, name := range providerNames {
+		gatewayResources := gatewayResourcesByProvider[name]
+		result.GatewayResources = append(result.GatewayResources, *gatewayResources)
+
+		notificationsByObject := map[objectID][]notifications.Notification{}
+		for _, n := range notifications.NotificationAggr.ProviderNotifications(string(name)) {
+			var ids []objectID
+			for _, obj := range n.CallingObjects {
+				if id := idOf(obj); !slices.Contains(ids, id) {
+					ids = append(ids, id)
+					notificationsByObject[id] = append(notificationsByObject[id], n)
+				}
+			}
+		}
+
+		for _, generated := range generatedObjects(gatewayResources) {
+			result.Resources[generated.gvk] = append(result.Resources[generated.gvk], ResourceResult{
+				Provider:      name,
+				Object:        generated.object,
+				Notifications: notificationsByObject[objectID{kind: generated.gvk.Kind, key: client.ObjectKeyFromObject(generated.object)}],
+			})
+		}
+	}
+	return result
+}
+
+// objectID identifies the objects of the notifications, whose type meta is
+// often unset.
+type objectID struct {
+	kind string
+	key  types.NamespacedName
+}
+
+// idOf returns the objectID of the object, of the kind of its type meta, or
+// else of the name of its Go type.
+func idOf(obj client.Object) objectID {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		t := reflect.TypeOf(obj)
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		kind = t.Name()
+	}
+	return objectID{kind: kind, key: client.ObjectKeyFromObject(obj)}
+}
+
+// generatedObject is a generated resource and its GroupVersionKind.
+type generatedObject struct {
+	gvk    schema.GroupVersionKind
+	object client.Object
+}
+
+// generatedObjects returns the resources of the GatewayResources, by kind and
+// key, with their GroupVersionKind.
+func generatedObjects(gatewayResources *GatewayResources) []generatedObject {
+	var objects []generatedObject
+	objects = appendGeneratedObjects(objects, gatewayResources.GatewayClasses, gatewayv1.SchemeGroupVersion.WithKind("GatewayClass"))
+	objects = appendGeneratedObjects(objects, gatewayResources.Gateways, gatewayv1.SchemeGroupVersion.WithKind("Gateway"))
+	httpRoutes := len(objects)
+	objects = appendGeneratedObjects(objects, gatewayResources.HTTPRoutes, gatewayv1.SchemeGroupVersion.WithKind("HTTPRoute"))
+	for i := httpRoutes; i < len(objects); i++ {
+		httpRoute := objects[i].object.(*gatewayv1.HTTPRoute)
+		objects[i].object = HTTPRouteObject(*httpRoute, gatewayResources.MirrorFractions[client.ObjectKeyFromObject(httpRoute)])
+	}
+	objects = appendGeneratedObjects(objects, gatewayResources.GRPCRoutes, gatewayv1.SchemeGroupVersion.WithKind("GRPCRoute"))
+	objects = appendGeneratedObjects(objects, gatewayResources.TLSRoutes, gatewayv1alpha2.SchemeGroupVersion.WithKind("TLSRoute"))
+	objects = appendGeneratedObjects(objects, gatewayResources.TCPRoutes, gatewayv1alpha2.SchemeGroupVersion.WithKind("TCPRoute"))
+	objects = appendGeneratedObjects(objects, gatewayResources.UDPRoutes, gatewayv1alpha2.SchemeGroupVersion.WithKind("UDPRoute"))
+	objects = appendGeneratedObjects(objects, gatewayResources.ReferenceGrants, gatewayv1beta1.SchemeGroupVersion.WithKind("ReferenceGrant"))
+	objects = appendGeneratedObjects(objects, gatewayResources.BackendTLSPolicies, gatewayv1alpha3.SchemeGroupVersion.WithKind("BackendTLSPolicy"))
+	objects = appendGeneratedObjects(objects, gatewayResources.BackendLBPolicies, gatewayv1alpha2.SchemeGroupVersion.WithKind("BackendLBPolicy"))
+	for i := range gatewayResources.GatewayExtensions {
+		extension := gatewayResources.GatewayExtensions[i].DeepCopy()
+		objects = append(objects, generatedObject{gvk: extension.GroupVersionKind(), object: extension})
+	}
+	return objects
+}
+
+// appendGeneratedObjects appends copies of the typed resources, sorted by key,
+// of the given GroupVersionKind unless their type meta is set.
+func appendGeneratedObjects[T any, PT interface {
+	*T
+	client.Object
+}](objects []generatedObject, resources map[types.NamespacedName]T, defaultGVK schema.GroupVersionKind) []generatedObject {
+	keys := make([]types.NamespacedName, 0, len(resources))
+	for key := range resources {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	for _, key := range keys {
+		resource := resources[key]
+		object := PT(&resource)
+		gvk := object.GetObjectKind().GroupVersionKind()
+		if gvk.Empty() {
+			gvk = defaultGVK
+		}
+		objects = append(objects, generatedObject{gvk: gvk, object: object})
+	}
+	return objects
+}
+
+// sortedProviderNames returns the provider names of the given map, sorted.
+func sortedProviderNames[V any](m map[ProviderName]V) []ProviderName {
+	names := make([]ProviderName, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func Test_newConversionResult(t *testing.T) {
+	gateway := gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"}}
+	httpRouteA := gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a"}}
+	httpRouteB := gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "b"}}
+	referenceGrant := gatewayv1beta1.ReferenceGrant{
+		TypeMeta:   metav1.TypeMeta{APIVersion: gatewayv1beta1.GroupVersion.String(), Kind: "ReferenceGrant"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "backends", Name: "from-default"},
+	}
+	extension := unstructured.Unstructured{}
+	extension.SetAPIVersion("v1")
+	extension.SetKind("Service")
+	extension.SetNamespace("default")
+	extension.SetName("a-mirror")
+
+	gatewayResourcesByProvider := map[ProviderName]*GatewayResources{
+		"ingress-nginx": {
+			Gateways:          map[types.NamespacedName]gatewayv1.Gateway{{Namespace: "default", Name: "nginx"}: gateway},
+			HTTPRoutes:        map[types.NamespacedName]gatewayv1.HTTPRoute{{Namespace: "default", Name: "b"}: httpRouteB, {Namespace: "default", Name: "a"}: httpRouteA},
+			ReferenceGrants:   map[types.NamespacedName]gatewayv1beta1.ReferenceGrant{{Namespace: "backends", Name: "from-default"}: referenceGrant},
+			GatewayExtensions: []unstructured.Unstructured{extension},
+		},
+		"gce": {},
+	}
+
+	ingress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a"}}
+	routeWarning := notifications.NewNotification(notifications.WarningNotification, "unsupported annotation", &httpRouteA, ingress)
+	gatewayInfo := notifications.NewNotification(notifications.InfoNotification, "gateway created", &gateway, &gateway)
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{
+		"ingress-nginx": {routeWarning, gatewayInfo},
+		"gce":           {notifications.NewNotification(notifications.WarningNotification, "other provider", &httpRouteA)},
+	}
+	t.Cleanup(func() {
+		notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	})
+
+	tables := map[string]string{"ingress-nginx": "table"}
+	result := newConversionResult(gatewayResourcesByProvider, tables)
+
+	require.Equal(t, []GatewayResources{*gatewayResourcesByProvider["gce"], *gatewayResourcesByProvider["ingress-nginx"]}, result.GatewayResources)
+	require.Equal(t, tables, result.NotificationTables)
+	require.Equal(t, map[schema.GroupVersionKind][]ResourceResult{
+		gatewayv1.SchemeGroupVersion.WithKind("Gateway"): {
+			{Provider: "ingress-nginx", Object: &gateway, Notifications: []notifications.Notification{gatewayInfo}},
+		},
+		gatewayv1.SchemeGroupVersion.WithKind("HTTPRoute"): {
+			{Provider: "ingress-nginx", Object: &httpRouteA, Notifications: []notifications.Notification{routeWarning}},
+			{Provider: "ingress-nginx", Object: &httpRouteB},
+		},
+		gatewayv1beta1.SchemeGroupVersion.WithKind("ReferenceGrant"): {
+			{Provider: "ingress-nginx", Object: &referenceGrant},
+		},
+		{Version: "v1", Kind: "Service"}: {
+			{Provider: "ingress-nginx", Object: &extension},
+		},
+	}, result.Resources)
+}
+
+func Test_newConversionResultRedacted(t *testing.T) {
+	httpRoute := gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "shop"}}
+	gatewayResourcesByProvider := map[ProviderName]*GatewayResources{
+		"ingress-nginx": {
+			HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{{Namespace: "default", Name: "shop"}: httpRoute},
+		},
+	}
+
+	notifications.NotificationAggr.Redact = true
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{
+		"ingress-nginx": {notifications.NewNotification(notifications.WarningNotification, "Secret default/basic-auth has no auth key", &httpRoute)},
+	}
+	t.Cleanup(func() {
+		notifications.NotificationAggr.Redact = false
+		notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	})
+
+	result := newConversionResult(gatewayResourcesByProvider, nil)
+
+	require.Equal(t, []ResourceResult{{
+		Provider: "ingress-nginx",
+		Object:   &httpRoute,
+		Notifications: []notifications.Notification{
+			notifications.NewNotification(notifications.WarningNotification, "Secret default/"+notifications.RedactName("basic-auth")+" has no auth key", &httpRoute),
+		},
+	}}, result.Resources[gatewayv1.SchemeGroupVersion.WithKind("HTTPRoute")])
+}
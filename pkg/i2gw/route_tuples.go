@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// anyPath is the path of the route tuples of TLSRoutes and GRPCRoutes, which
+// don't match paths and represent the source tuples of any path.
+const anyPath = "*"
+
+// anyBackend is the backend of the route tuples of redirected HTTPRoute rules,
+// which represent the source tuples of any backend.
+var anyBackend = types.NamespacedName{Name: "*"}
+
+// routeTuple is a (host, path, backend) tuple the traffic is routed by.
+// Backends are identified by the namespaced name of their Service.
+type routeTuple struct {
+	host    string
+	path    string
+	backend types.NamespacedName
+}
+
+func (t routeTuple) String() string {
+	return fmt.Sprintf("(%q, %q, %s)", t.host, t.path, t.backend)
+}
+
+// checkRouteTuples reports the (host, path, backend) tuples of the source
+// Ingresses of the IR HTTPRoutes which aren't represented in the generated
+// routes, after all the passes merging or splitting them. It is a safety net
+// against providers silently dropping rules. The backends of the source
+// tuples are renamed by the service mapping, as the generated routes are.
+func checkRouteTuples(providerName ProviderName, ir intermediate.IR, gatewayResources *GatewayResources, serviceMapping map[types.NamespacedName]types.NamespacedName) {
+	ingresses := map[types.NamespacedName]*networkingv1.Ingress{}
+	for _, httpRouteContext := range ir.HTTPRoutes {
+		for _, source := range httpRouteContext.Sources {
+			if ingress, ok := source.(*networkingv1.Ingress); ok {
+				ingresses[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = ingress
+			}
+		}
+	}
+	if len(ingresses) == 0 {
+		return
+	}
+
+	represented := generatedRouteTuples(gatewayResources)
+	for _, key := range sortedNamespacedNames(ingresses) {
+		ingress := ingresses[key]
+		sourceTuples := ingressRouteTuples(ingress)
+		var missing []string
+		for _, tuple := range sourceTuples {
+			mappedTuple := tuple
+			if service, ok := serviceMapping[tuple.backend]; ok {
+				mappedTuple.backend = service
+			}
+			if !isRepresented(represented, mappedTuple) {
+				missing = append(missing, tuple.String())
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		message := fmt.Sprintf("%d of the %d (host, path, backend) tuples of Ingress %s are not represented in the generated routes: %s", len(missing), len(sourceTuples), key, strings.Join(missing, ", "))
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification, message, ingress), string(providerName))
+	}
+}
+
+// isRepresented returns true if the traffic of the source tuple is routed by
+// one of the route tuples.
+func isRepresented(routeTuples sets.Set[routeTuple], tuple routeTuple) bool {
+	for _, path := range []string{tuple.path, anyPath} {
+		for _, backend := range []types.NamespacedName{tuple.backend, anyBackend} {
+			if routeTuples.Has(routeTuple{host: tuple.host, path: path, backend: backend}) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ingressRouteTuples returns the distinct (host, path, backend) tuples of the
+// rules of the Ingress, sorted.
+func ingressRouteTuples(ingress *networkingv1.Ingress) []routeTuple {
+	tuples := sets.New[routeTuple]()
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			backend := ingressBackendName(path.Backend)
+			if backend == "" {
+				continue
+			}
+			tuples.Insert(routeTuple{
+				host:    rule.Host,
+				path:    normalizePath(path.Path),
+				backend: types.NamespacedName{Namespace: ingress.Namespace, Name: backend},
+			})
+		}
+	}
+	sorted := tuples.UnsortedList()
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].String() < sorted[j].String()
+	})
+	return sorted
+}
+
+func ingressBackendName(backend networkingv1.IngressBackend) string {
+	switch {
+	case backend.Service != nil:
+		return backend.Service.Name
+	case backend.Resource != nil:
+		return backend.Resource.Name
+	default:
+		return ""
+	}
+}
+
+// generatedRouteTuples returns the (host, path, backend) tuples the generated
+// HTTPRoutes, GRPCRoutes and TLSRoutes route the traffic by.
+func generatedRouteTuples(gatewayResources *GatewayResources) sets.Set[routeTuple] {
+	tuples := sets.New[routeTuple]()
+	insert := func(hostnames []gatewayv1.Hostname, paths []string, namespace string, backends []types.NamespacedName) {
+		hosts := []string{""}
+		if len(hostnames) > 0 {
+			hosts = hosts[:0]
+			for _, hostname := range hostnames {
+				hosts = append(hosts, string(hostname))
+			}
+		}
+		for _, backend := range backends {
+			for _, host := range hosts {
+				for _, path := range paths {
+					tuples.Insert(routeTuple{host: host, path: path, backend: backend})
+				}
+			}
+		}
+	}
+
+	for _, httpRoute := range gatewayResources.HTTPRoutes {
+		for _, rule := range httpRoute.Spec.Rules {
+			var paths []string
+			for _, match := range rule.Matches {
+				if match.Path != nil && match.Path.Value != nil {
+					paths = append(paths, normalizePath(*match.Path.Value))
+				} else {
+					paths = append(paths, "/")
+				}
+			}
+			if len(paths) == 0 {
+				paths = []string{"/"}
+			}
+			var backends []types.NamespacedName
+			for _, backendRef := range rule.BackendRefs {
+				backends = append(backends, backendName(backendRef.BackendRef, httpRoute.Namespace))
+			}
+			for _, filter := range rule.Filters {
+				if filter.Type == gatewayv1.HTTPRouteFilterRequestRedirect {
+					backends = append(backends, anyBackend)
+				}
+			}
+			insert(httpRoute.Spec.Hostnames, paths, httpRoute.Namespace, backends)
+		}
+	}
+	for _, grpcRoute := range gatewayResources.GRPCRoutes {
+		for _, rule := range grpcRoute.Spec.Rules {
+			var backends []types.NamespacedName
+			for _, backendRef := range rule.BackendRefs {
+				backends = append(backends, backendName(backendRef.BackendRef, grpcRoute.Namespace))
+			}
+			insert(grpcRoute.Spec.Hostnames, []string{anyPath}, grpcRoute.Namespace, backends)
+		}
+	}
+	for _, tlsRoute := range gatewayResources.TLSRoutes {
+		for _, rule := range tlsRoute.Spec.Rules {
+			var backends []types.NamespacedName
+			for _, backendRef := range rule.BackendRefs {
+				backends = append(backends, backendName(backendRef, tlsRoute.Namespace))
+			}
+			insert(tlsRoute.Spec.Hostnames, []string{anyPath}, tlsRoute.Namespace, backends)
+		}
+	}
+	return tuples
+}
+
+// backendName returns the namespaced name of the backend of the route of the
+// given namespace.
+func backendName(backendRef gatewayv1.BackendRef, routeNamespace string) types.NamespacedName {
+	backend := types.NamespacedName{Namespace: routeNamespace, Name: string(backendRef.Name)}
+	if backendRef.Namespace != nil {
+		backend.Namespace = string(*backendRef.Namespace)
+	}
+	return backend
+}
+
+// normalizePath returns the path an empty Ingress or HTTPRoute path matches.
+func normalizePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func Test_routeTuples(t *testing.T) {
+	ingressPath := func(path, service string) networkingv1.HTTPIngressPath {
+		return networkingv1.HTTPIngressPath{
+			Path:     path,
+			PathType: ptr.To(networkingv1.PathTypePrefix),
+			Backend:  networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: service}},
+		}
+	}
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "shop"},
+		Spec: networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{
+			{
+				Host: "shop.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+					ingressPath("", "shop"),
+					ingressPath("/cart", "cart"),
+					ingressPath("/legacy", "legacy"),
+					ingressPath("/dropped", "dropped"),
+				}}},
+			},
+			{
+				Host: "secure.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+					ingressPath("/", "secure"),
+				}}},
+			},
+		}},
+	}
+
+	pathMatch := func(path string) []gatewayv1.HTTPRouteMatch {
+		return []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To(path)}}}
+	}
+	gatewayResources := &GatewayResources{
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+			{Namespace: "default", Name: "shop"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "shop"},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"shop.example.com"},
+					Rules: []gatewayv1.HTTPRouteRule{
+						{
+							Matches:     pathMatch("/"),
+							BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "shop"}}}},
+						},
+						{
+							Matches:     pathMatch("/cart"),
+							BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "cart", Namespace: ptr.To[gatewayv1.Namespace]("default")}}}},
+						},
+						{
+							Matches: pathMatch("/legacy"),
+							Filters: []gatewayv1.HTTPRouteFilter{{Type: gatewayv1.HTTPRouteFilterRequestRedirect, RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{Hostname: ptr.To[gatewayv1.PreciseHostname]("new.example.com")}}},
+						},
+					},
+				},
+			},
+		},
+		TLSRoutes: map[types.NamespacedName]gatewayv1alpha2.TLSRoute{
+			{Namespace: "default", Name: "secure"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "secure"},
+				Spec: gatewayv1alpha2.TLSRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"secure.example.com"},
+					Rules:     []gatewayv1alpha2.TLSRouteRule{{BackendRefs: []gatewayv1.BackendRef{{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "secure"}}}}},
+				},
+			},
+		},
+	}
+
+	sourceTuples := ingressRouteTuples(ingress)
+	require.Len(t, sourceTuples, 5)
+
+	represented := generatedRouteTuples(gatewayResources)
+	var missing []routeTuple
+	for _, tuple := range sourceTuples {
+		if !isRepresented(represented, tuple) {
+			missing = append(missing, tuple)
+		}
+	}
+	require.Equal(t, []routeTuple{{host: "shop.example.com", path: "/dropped", backend: types.NamespacedName{Namespace: "default", Name: "dropped"}}}, missing)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// orderRulesByPriority orders the rules of the generated HTTPRoutes by the
+// descending priorities the providers set in the IR, keeping the order of the
+// rules of equal priority. It runs once the emitters extended the rules, as
+// they refer to them by their index in the IR.
+//
+// Gateway API only orders the rules of equal match precedence by their index
+// though, and the rules of distinct HTTPRoutes by the age and name of the
+// routes: rules with the same matches in distinct HTTPRoutes, whose order
+// doesn't follow their priorities, are warned about.
+func orderRulesByPriority(providerName ProviderName, ir intermediate.IR, gatewayResources *GatewayResources) {
+	var prioritizedRules []prioritizedRule
+	for _, key := range sortedNamespacedNames(ir.HTTPRoutes) {
+		httpRouteContext := ir.HTTPRoutes[key]
+		if len(httpRouteContext.RulePriorities) == 0 {
+			continue
+		}
+		// The HTTPRoute may have been converted to another kind of route.
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		if len(httpRoute.Spec.Rules) != len(httpRouteContext.Spec.Rules) {
+			notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification,
+				fmt.Sprintf("HTTPRoute %s: the rules weren't ordered by priority, as their number changed during the conversion", key), &httpRoute), string(providerName))
+			continue
+		}
+
+		priorities := make([]int32, len(httpRoute.Spec.Rules))
+		for i, priority := range httpRouteContext.RulePriorities {
+			if i >= 0 && i < len(priorities) {
+				priorities[i] = priority
+			}
+		}
+		order := make([]int, len(priorities))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int {
+			switch {
+			case priorities[a] > priorities[b]:
+				return -1
+			case priorities[a] < priorities[b]:
+				return 1
+			}
+			return 0
+		})
+		if !slices.IsSorted(order) {
+			rules := make([]gatewayv1.HTTPRouteRule, len(order))
+			for i, j := range order {
+				rules[i] = httpRoute.Spec.Rules[j]
+			}
+			httpRoute.Spec.Rules = rules
+			gatewayResources.HTTPRoutes[key] = httpRoute
+			notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.InfoNotification,
+				fmt.Sprintf("HTTPRoute %s: the rules were ordered by priority", key), &httpRoute), string(providerName))
+		}
+
+		for i, j := range order {
+			prioritizedRules = append(prioritizedRules, prioritizedRule{
+				route:    key,
+				index:    i,
+				priority: priorities[j],
+			})
+		}
+	}
+
+	for _, message := range priorityConflicts(prioritizedRules, gatewayResources) {
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(notifications.WarningNotification, message.text, message.route), string(providerName))
+	}
+}
+
+// prioritizedRule is a rule of a generated HTTPRoute, with its priority.
+type prioritizedRule struct {
+	route    types.NamespacedName
+	index    int
+	priority int32
+}
+
+type priorityConflict struct {
+	text  string
+	route *gatewayv1.HTTPRoute
+}
+
+// priorityConflicts returns the rules of distinct HTTPRoutes attached to the
+// same parent and hostname, with the same matches, for which Gateway API
+// favours the rule of lower priority. The generated routes being created
+// together, the first one in alphabetical order takes precedence.
+func priorityConflicts(rules []prioritizedRule, gatewayResources *GatewayResources) []priorityConflict {
+	var conflicts []priorityConflict
+	for i := range rules {
+		for j := i + 1; j < len(rules); j++ {
+			higher, lower := rules[i], rules[j]
+			if higher.route == lower.route || higher.priority == lower.priority {
+				continue
+			}
+			if higher.priority < lower.priority {
+				higher, lower = lower, higher
+			}
+			if higher.route.String() < lower.route.String() {
+				continue
+			}
+			higherRoute := gatewayResources.HTTPRoutes[higher.route]
+			lowerRoute := gatewayResources.HTTPRoutes[lower.route]
+			if !sharesParent(higherRoute, lowerRoute) || !sharesHostname(higherRoute, lowerRoute) {
+				continue
+			}
+			if !apiequality.Semantic.DeepEqual(higherRoute.Spec.Rules[higher.index].Matches, lowerRoute.Spec.Rules[lower.index].Matches) {
+				continue
+			}
+			conflicts = append(conflicts, priorityConflict{
+				text: fmt.Sprintf("rule %d of HTTPRoute %s has the same matches as rule %d of HTTPRoute %s, and a higher priority, but Gateway API gives precedence to the latter, as the oldest HTTPRoute or the first in alphabetical order",
+					higher.index, higher.route, lower.index, lower.route),
+				route: &higherRoute,
+			})
+		}
+	}
+	return conflicts
+}
+
+// sharesParent returns whether the HTTPRoutes are attached to a common
+// parent.
+func sharesParent(a, b gatewayv1.HTTPRoute) bool {
+	for _, parentRef := range a.Spec.ParentRefs {
+		parentRef := qualifiedParentRef(parentRef, a.Namespace)
+		if slices.ContainsFunc(b.Spec.ParentRefs, func(other gatewayv1.ParentReference) bool {
+			return apiequality.Semantic.DeepEqual(parentRef, qualifiedParentRef(other, b.Namespace))
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifiedParentRef returns the parent reference of a route of the namespace
+// with its namespace set.
+func qualifiedParentRef(parentRef gatewayv1.ParentReference, namespace string) gatewayv1.ParentReference {
+	if parentRef.Namespace == nil {
+		parentRef.Namespace = ptr.To(gatewayv1.Namespace(namespace))
+	}
+	return parentRef
+}
+
+// sharesHostname returns whether the HTTPRoutes match a common hostname, the
+// routes without hostname matching any.
+func sharesHostname(a, b gatewayv1.HTTPRoute) bool {
+	if len(a.Spec.Hostnames) == 0 || len(b.Spec.Hostnames) == 0 {
+		return true
+	}
+	for _, hostname := range a.Spec.Hostnames {
+		if slices.Contains(b.Spec.Hostnames, hostname) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_orderRulesByPriority(t *testing.T) {
+	rule := func(path string) gatewayv1.HTTPRouteRule {
+		return gatewayv1.HTTPRouteRule{Matches: []gatewayv1.HTTPRouteMatch{{
+			Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchRegularExpression), Value: ptr.To(path)},
+		}}}
+	}
+	httpRoute := func(name string, rules ...gatewayv1.HTTPRouteRule) gatewayv1.HTTPRoute {
+		return gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gateway"}}},
+				Hostnames:       []gatewayv1.Hostname{"example.com"},
+				Rules:           rules,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		ir            map[string]intermediate.HTTPRouteContext
+		httpRoutes    map[string]gatewayv1.HTTPRoute
+		expectedRules map[string][]gatewayv1.HTTPRouteRule
+	}{
+		{
+			name: "rules ordered by descending priority",
+			ir: map[string]intermediate.HTTPRouteContext{
+				"route": {
+					HTTPRoute:      httpRoute("route", rule("/a"), rule("/b"), rule("/c")),
+					RulePriorities: map[int]int32{1: 5, 2: 10},
+				},
+			},
+			httpRoutes:    map[string]gatewayv1.HTTPRoute{"route": httpRoute("route", rule("/a"), rule("/b"), rule("/c"))},
+			expectedRules: map[string][]gatewayv1.HTTPRouteRule{"route": {rule("/c"), rule("/b"), rule("/a")}},
+		},
+		{
+			name: "rules of equal priority keep their order",
+			ir: map[string]intermediate.HTTPRouteContext{
+				"route": {
+					HTTPRoute:      httpRoute("route", rule("/a"), rule("/b"), rule("/c")),
+					RulePriorities: map[int]int32{0: -1, 2: 0},
+				},
+			},
+			httpRoutes:    map[string]gatewayv1.HTTPRoute{"route": httpRoute("route", rule("/a"), rule("/b"), rule("/c"))},
+			expectedRules: map[string][]gatewayv1.HTTPRouteRule{"route": {rule("/b"), rule("/c"), rule("/a")}},
+		},
+		{
+			name: "rules whose number changed aren't ordered",
+			ir: map[string]intermediate.HTTPRouteContext{
+				"route": {
+					HTTPRoute:      httpRoute("route", rule("/a"), rule("/b")),
+					RulePriorities: map[int]int32{1: 5},
+				},
+			},
+			httpRoutes:    map[string]gatewayv1.HTTPRoute{"route": httpRoute("route", rule("/a"), rule("/b"), rule("/c"))},
+			expectedRules: map[string][]gatewayv1.HTTPRouteRule{"route": {rule("/a"), rule("/b"), rule("/c")}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir := intermediate.IR{HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{}}
+			for name, httpRouteContext := range tc.ir {
+				ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: name}] = httpRouteContext
+			}
+			gatewayResources := GatewayResources{HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{}}
+			for name, route := range tc.httpRoutes {
+				gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: name}] = route
+			}
+
+			orderRulesByPriority("test", ir, &gatewayResources)
+
+			for name, rules := range tc.expectedRules {
+				require.Equal(t, rules, gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: name}].Spec.Rules)
+			}
+		})
+	}
+}
+
+func Test_priorityConflicts(t *testing.T) {
+	httpRoute := func(name, path string, hostname gatewayv1.Hostname) gatewayv1.HTTPRoute {
+		return gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gateway"}}},
+				Hostnames:       []gatewayv1.Hostname{hostname},
+				Rules: []gatewayv1.HTTPRouteRule{{Matches: []gatewayv1.HTTPRouteMatch{{
+					Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To(path)},
+				}}}},
+			},
+		}
+	}
+	key := func(name string) types.NamespacedName {
+		return types.NamespacedName{Namespace: "default", Name: name}
+	}
+
+	testCases := []struct {
+		name              string
+		httpRoutes        []gatewayv1.HTTPRoute
+		priorities        []int32
+		expectedConflicts int
+	}{
+		{
+			name:              "higher priority route last in alphabetical order",
+			httpRoutes:        []gatewayv1.HTTPRoute{httpRoute("vs-a", "/", "example.com"), httpRoute("vs-b", "/", "example.com")},
+			priorities:        []int32{1, 2},
+			expectedConflicts: 1,
+		},
+		{
+			name:       "higher priority route first in alphabetical order",
+			httpRoutes: []gatewayv1.HTTPRoute{httpRoute("vs-a", "/", "example.com"), httpRoute("vs-b", "/", "example.com")},
+			priorities: []int32{2, 1},
+		},
+		{
+			name:       "distinct matches",
+			httpRoutes: []gatewayv1.HTTPRoute{httpRoute("vs-a", "/", "example.com"), httpRoute("vs-b", "/api", "example.com")},
+			priorities: []int32{1, 2},
+		},
+		{
+			name:       "distinct hostnames",
+			httpRoutes: []gatewayv1.HTTPRoute{httpRoute("vs-a", "/", "example.com"), httpRoute("vs-b", "/", "example.org")},
+			priorities: []int32{1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gatewayResources := GatewayResources{HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{}}
+			var rules []prioritizedRule
+			for i, route := range tc.httpRoutes {
+				gatewayResources.HTTPRoutes[key(route.Name)] = route
+				rules = append(rules, prioritizedRule{route: key(route.Name), priority: tc.priorities[i]})
+			}
+			require.Len(t, priorityConflicts(rules, &gatewayResources), tc.expectedConflicts)
+		})
+	}
+}
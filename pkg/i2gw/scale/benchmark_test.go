@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scale
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/apisix"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/cilium"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/gce"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/ingressnginx"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/kong"
+)
+
+// benchmarkSizes are the numbers of Ingresses converted by the benchmarks.
+var benchmarkSizes = []int{1000, 5000}
+
+// BenchmarkToGatewayAPIResources measures the end-to-end conversion, from
+// reading the input file to the generated Gateway API resources, for every
+// provider supporting Ingresses.
+func BenchmarkToGatewayAPIResources(b *testing.B) {
+	var providers []string
+	for provider := range Profiles {
+		providers = append(providers, provider)
+	}
+	slices.Sort(providers)
+
+	for _, provider := range providers {
+		for _, size := range benchmarkSizes {
+			b.Run(fmt.Sprintf("%s/%d", provider, size), func(b *testing.B) {
+				inputFile := writeInputFile(b, Options{Count: size, Profile: Profiles[provider]})
+				opts := i2gw.ConversionOptions{InputFile: inputFile, Providers: []string{provider}}
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				for range b.N {
+					if _, err := i2gw.ToGatewayAPIResources(context.Background(), opts); err != nil {
+						b.Fatalf("Failed to convert resources: %v", err)
+					}
+
+					// Notifications accumulate across conversions.
+					b.StopTimer()
+					notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+					b.StartTimer()
+				}
+			})
+		}
+	}
+}
+
+func writeInputFile(b *testing.B, opts Options) string {
+	b.Helper()
+
+	inputFile := filepath.Join(b.TempDir(), "ingresses.yaml")
+	f, err := os.Create(inputFile)
+	if err != nil {
+		b.Fatalf("Failed to create input file: %v", err)
+	}
+	defer f.Close()
+
+	if err = WriteIngresses(f, GenerateIngresses(opts)); err != nil {
+		b.Fatalf("Failed to write input file: %v", err)
+	}
+	return inputFile
+}
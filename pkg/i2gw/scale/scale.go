@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scale generates large sets of Ingresses, carrying a mix of
+// provider-specific annotations, to measure the conversion pipeline at scale.
+package scale
+
+import (
+	"fmt"
+	"io"
+	"maps"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Profile describes the Ingresses generated for a provider.
+type Profile struct {
+	// IngressClass is the class of the generated Ingresses.
+	IngressClass string
+
+	// Annotations are the sets of annotations applied in turn to the
+	// generated Ingresses, so that they exercise the provider features.
+	Annotations []map[string]string
+}
+
+// Profiles are the profiles of the providers supporting Ingresses, by
+// provider name.
+var Profiles = map[string]Profile{
+	"ingress-nginx": {
+		IngressClass: "nginx",
+		Annotations: []map[string]string{
+			{},
+			{"nginx.ingress.kubernetes.io/x-forwarded-prefix": "/app"},
+			{"nginx.ingress.kubernetes.io/rewrite-target": "/"},
+			{"nginx.ingress.kubernetes.io/use-regex": "true"},
+			{"nginx.ingress.kubernetes.io/configuration-snippet": "more_set_headers \"X-Frame-Options: DENY\";"},
+			{"nginx.ingress.kubernetes.io/proxy-redirect-from": "http://internal/", "nginx.ingress.kubernetes.io/proxy-redirect-to": "/"},
+		},
+	},
+	"kong": {
+		IngressClass: "kong",
+		Annotations: []map[string]string{
+			{},
+			{"konghq.com/methods": "GET,POST"},
+			{"konghq.com/headers.x-env": "prod,staging"},
+			{"konghq.com/plugins": "rate-limiting"},
+		},
+	},
+	"apisix": {
+		IngressClass: "apisix",
+		Annotations: []map[string]string{
+			{},
+			{"k8s.apisix.apache.org/http-to-https": "true"},
+		},
+	},
+	"cilium": {
+		IngressClass: "cilium",
+		Annotations: []map[string]string{
+			{},
+			{"ingress.cilium.io/force-https": "enabled"},
+		},
+	},
+	"gce": {
+		IngressClass: "gce",
+		Annotations: []map[string]string{
+			{},
+		},
+	},
+}
+
+// hostsPerNamespace is the default number of Ingresses, each with its own
+// host, generated in a namespace. A Gateway gets up to two listeners per host.
+const hostsPerNamespace = 25
+
+// Options configures the generated Ingresses.
+type Options struct {
+	// Count is the number of generated Ingresses.
+	Count int
+
+	// Namespaces is the number of namespaces the Ingresses are spread over.
+	// Defaults to enough namespaces for the Gateway generated in each of them
+	// to stay within the limit of 64 listeners.
+	Namespaces int
+
+	// Profile describes the generated Ingresses.
+	Profile Profile
+}
+
+// GenerateIngresses returns the given number of Ingresses, each with its own
+// host, two paths and a TLS configuration for every other Ingress.
+func GenerateIngresses(opts Options) []networkingv1.Ingress {
+	namespaces := opts.Namespaces
+	if namespaces <= 0 {
+		namespaces = max((opts.Count+hostsPerNamespace-1)/hostsPerNamespace, 1)
+	}
+	pathType := networkingv1.PathTypePrefix
+
+	ingresses := make([]networkingv1.Ingress, 0, opts.Count)
+	for i := range opts.Count {
+		name := fmt.Sprintf("app-%d", i)
+		host := fmt.Sprintf("%s.example.com", name)
+		ingress := networkingv1.Ingress{
+			TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: fmt.Sprintf("ns-%d", i%namespaces),
+			},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: &opts.Profile.IngressClass,
+				Rules: []networkingv1.IngressRule{{
+					Host: host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{Path: "/", PathType: &pathType, Backend: serviceBackend(name, 80)},
+								{Path: "/api", PathType: &pathType, Backend: serviceBackend(name+"-api", 8080)},
+							},
+						},
+					},
+				}},
+			},
+		}
+		if len(opts.Profile.Annotations) > 0 {
+			if annotations := opts.Profile.Annotations[i%len(opts.Profile.Annotations)]; len(annotations) > 0 {
+				ingress.Annotations = maps.Clone(annotations)
+			}
+		}
+		if i%2 == 0 {
+			ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{host}, SecretName: name + "-tls"}}
+		}
+		ingresses = append(ingresses, ingress)
+	}
+	return ingresses
+}
+
+// WriteIngresses writes the Ingresses to w as a multi-document YAML manifest,
+// suitable for the --input-file flag.
+func WriteIngresses(w io.Writer, ingresses []networkingv1.Ingress) error {
+	for _, ingress := range ingresses {
+		out, err := yaml.Marshal(ingress)
+		if err != nil {
+			return fmt.Errorf("failed to marshal ingress %s/%s: %w", ingress.Namespace, ingress.Name, err)
+		}
+		if _, err = fmt.Fprintf(w, "---\n%s", out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func serviceBackend(name string, port int32) networkingv1.IngressBackend {
+	return networkingv1.IngressBackend{
+		Service: &networkingv1.IngressServiceBackend{
+			Name: name,
+			Port: networkingv1.ServiceBackendPort{Number: port},
+		},
+	}
+}
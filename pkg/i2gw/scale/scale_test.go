@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scale
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func Test_GenerateIngresses(t *testing.T) {
+	profile := Profiles["ingress-nginx"]
+	ingresses := GenerateIngresses(Options{Count: 20, Namespaces: 3, Profile: profile})
+	if len(ingresses) != 20 {
+		t.Fatalf("Expected 20 ingresses, got %d", len(ingresses))
+	}
+
+	namespaces := map[string]bool{}
+	annotated := 0
+	for _, ingress := range ingresses {
+		namespaces[ingress.Namespace] = true
+		if len(ingress.Annotations) > 0 {
+			annotated++
+		}
+	}
+	if len(namespaces) != 3 {
+		t.Errorf("Expected ingresses in 3 namespaces, got %d", len(namespaces))
+	}
+	// Every sixth ingress gets the empty annotation set of the profile.
+	if annotated != 16 {
+		t.Errorf("Expected 16 annotated ingresses, got %d", annotated)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteIngresses(&buf, ingresses); err != nil {
+		t.Fatalf("Failed to write ingresses: %v", err)
+	}
+	if documents := strings.Count(buf.String(), "---\n"); documents != 20 {
+		t.Errorf("Expected 20 documents, got %d", documents)
+	}
+
+	inputFile := filepath.Join(t.TempDir(), "ingresses.yaml")
+	if err := os.WriteFile(inputFile, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("Failed to write input file: %v", err)
+	}
+	read, err := common.ReadIngressesFromFile(inputFile, "", func(ingress *networkingv1.Ingress) (*networkingv1.Ingress, bool) { return ingress, true })
+	if err != nil {
+		t.Fatalf("Failed to read ingresses back: %v", err)
+	}
+	if len(read) != 20 {
+		t.Errorf("Expected 20 ingresses to be read back, got %d", len(read))
+	}
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// readServiceMapping reads the Services the backends are renamed to, by
+// Service they were generated for, from the file of the --service-mapping
+// flag. Each line of the file maps a Service to another one, e.g.
+// old-ns/old-name=new-ns/new-name, blank lines and lines starting with # are
+// ignored. An empty path means no mapping.
+func readServiceMapping(path string) (map[types.NamespacedName]types.NamespacedName, error) {
+	if path == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the service mapping file: %w", err)
+	}
+
+	mapping := map[types.NamespacedName]types.NamespacedName{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		from, to, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d of %s: %q is not a <namespace>/<name>=<namespace>/<name> pair", lineNumber, path, line)
+		}
+		fromService, err := parseServiceName(from)
+		if err != nil {
+			return nil, fmt.Errorf("line %d of %s: %w", lineNumber, path, err)
+		}
+		toService, err := parseServiceName(to)
+		if err != nil {
+			return nil, fmt.Errorf("line %d of %s: %w", lineNumber, path, err)
+		}
+		if previous, ok := mapping[fromService]; ok && previous != toService {
+			return nil, fmt.Errorf("line %d of %s: Service %s is already mapped to %s", lineNumber, path, fromService, previous)
+		}
+		mapping[fromService] = toService
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the service mapping file: %w", err)
+	}
+	return mapping, nil
+}
+
+// parseServiceName parses the <namespace>/<name> of a Service.
+func parseServiceName(value string) (types.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok || len(validation.IsDNS1123Label(namespace)) > 0 || len(validation.IsDNS1035Label(name)) > 0 {
+		return types.NamespacedName{}, fmt.Errorf("%q is not the <namespace>/<name> of a Service", value)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// applyServiceMapping renames the Services referenced by the backends of the
+// IR routes, mirrors included, as well as the Services of the provider
+// specific IR and the targets of the BackendTLSPolicies. The ReferenceGrants
+// allowing the routes to reference the Services moved to other namespaces
+// are generated.
+func applyServiceMapping(providerName ProviderName, mapping map[types.NamespacedName]types.NamespacedName, ir *intermediate.IR) {
+	if len(mapping) == 0 {
+		return
+	}
+	notify := func(messageType notifications.MessageType, message string) {
+		notifications.NotificationAggr.DispatchNotification(notifications.NewNotification(messageType, message), string(providerName))
+	}
+
+	renamed := map[types.NamespacedName]bool{}
+	mapRef := func(routeKind gatewayv1.Kind, routeNamespace string, ref *gatewayv1.BackendObjectReference) {
+		if ptr.Deref(ref.Group, "") != "" || ptr.Deref(ref.Kind, "Service") != "Service" {
+			return
+		}
+		from := types.NamespacedName{Namespace: string(ptr.Deref(ref.Namespace, gatewayv1.Namespace(routeNamespace))), Name: string(ref.Name)}
+		to, ok := mapping[from]
+		if !ok {
+			return
+		}
+		renamed[from] = true
+		ref.Name = gatewayv1.ObjectName(to.Name)
+		ref.Namespace = nil
+		if to.Namespace != routeNamespace {
+			ref.Namespace = ptr.To(gatewayv1.Namespace(to.Namespace))
+			addServiceReferenceGrant(ir, routeKind, routeNamespace, to)
+		}
+	}
+	mapFilters := func(routeKind gatewayv1.Kind, routeNamespace string, filters []gatewayv1.HTTPRouteFilter) {
+		for i := range filters {
+			if filters[i].RequestMirror != nil {
+				mapRef(routeKind, routeNamespace, &filters[i].RequestMirror.BackendRef)
+			}
+		}
+	}
+	mapGRPCFilters := func(routeNamespace string, filters []gatewayv1.GRPCRouteFilter) {
+		for i := range filters {
+			if filters[i].RequestMirror != nil {
+				mapRef("GRPCRoute", routeNamespace, &filters[i].RequestMirror.BackendRef)
+			}
+		}
+	}
+
+	for _, key := range sortedNamespacedNames(ir.HTTPRoutes) {
+		httpRouteContext := ir.HTTPRoutes[key]
+		for i := range httpRouteContext.Spec.Rules {
+			rule := &httpRouteContext.Spec.Rules[i]
+			mapFilters("HTTPRoute", key.Namespace, rule.Filters)
+			for j := range rule.BackendRefs {
+				mapRef("HTTPRoute", key.Namespace, &rule.BackendRefs[j].BackendObjectReference)
+				mapFilters("HTTPRoute", key.Namespace, rule.BackendRefs[j].Filters)
+			}
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+	for _, key := range sortedNamespacedNames(ir.GRPCRoutes) {
+		grpcRoute := ir.GRPCRoutes[key]
+		for i := range grpcRoute.Spec.Rules {
+			rule := &grpcRoute.Spec.Rules[i]
+			mapGRPCFilters(key.Namespace, rule.Filters)
+			for j := range rule.BackendRefs {
+				mapRef("GRPCRoute", key.Namespace, &rule.BackendRefs[j].BackendObjectReference)
+				mapGRPCFilters(key.Namespace, rule.BackendRefs[j].Filters)
+			}
+		}
+		ir.GRPCRoutes[key] = grpcRoute
+	}
+	for _, key := range sortedNamespacedNames(ir.TLSRoutes) {
+		tlsRoute := ir.TLSRoutes[key]
+		for i := range tlsRoute.Spec.Rules {
+			for j := range tlsRoute.Spec.Rules[i].BackendRefs {
+				mapRef("TLSRoute", key.Namespace, &tlsRoute.Spec.Rules[i].BackendRefs[j].BackendObjectReference)
+			}
+		}
+		ir.TLSRoutes[key] = tlsRoute
+	}
+	for _, key := range sortedNamespacedNames(ir.TCPRoutes) {
+		tcpRoute := ir.TCPRoutes[key]
+		for i := range tcpRoute.Spec.Rules {
+			for j := range tcpRoute.Spec.Rules[i].BackendRefs {
+				mapRef("TCPRoute", key.Namespace, &tcpRoute.Spec.Rules[i].BackendRefs[j].BackendObjectReference)
+			}
+		}
+		ir.TCPRoutes[key] = tcpRoute
+	}
+	for _, key := range sortedNamespacedNames(ir.UDPRoutes) {
+		udpRoute := ir.UDPRoutes[key]
+		for i := range udpRoute.Spec.Rules {
+			for j := range udpRoute.Spec.Rules[i].BackendRefs {
+				mapRef("UDPRoute", key.Namespace, &udpRoute.Spec.Rules[i].BackendRefs[j].BackendObjectReference)
+			}
+		}
+		ir.UDPRoutes[key] = udpRoute
+	}
+
+	for _, from := range sortedNamespacedNames(ir.Services) {
+		if to, ok := mapping[from]; ok {
+			ir.Services[to] = ir.Services[from]
+			delete(ir.Services, from)
+		}
+	}
+	for _, key := range sortedNamespacedNames(ir.BackendTLSPolicies) {
+		policy := ir.BackendTLSPolicies[key]
+		for i, targetRef := range policy.Spec.TargetRefs {
+			from := types.NamespacedName{Namespace: key.Namespace, Name: string(targetRef.Name)}
+			to, ok := mapping[from]
+			if targetRef.Group != "" || targetRef.Kind != "Service" || !ok {
+				continue
+			}
+			if to.Namespace != key.Namespace {
+				notify(notifications.WarningNotification, fmt.Sprintf("BackendTLSPolicy %s targets Service %s, mapped to Service %s of another namespace, move the policy to that namespace", key, from, to))
+				continue
+			}
+			policy.Spec.TargetRefs[i].Name = gatewayv1.ObjectName(to.Name)
+		}
+		ir.BackendTLSPolicies[key] = policy
+	}
+
+	for _, from := range sortedNamespacedNames(renamed) {
+		notify(notifications.InfoNotification, fmt.Sprintf("the backends referencing Service %s were mapped to Service %s", from, mapping[from]))
+	}
+}
+
+// addServiceReferenceGrant adds to the IR the ReferenceGrant allowing the
+// routes of the given kind and namespace to reference a Service of another
+// one.
+func addServiceReferenceGrant(ir *intermediate.IR, routeKind gatewayv1.Kind, routeNamespace string, service types.NamespacedName) {
+	if ir.ReferenceGrants == nil {
+		ir.ReferenceGrants = map[types.NamespacedName]gatewayv1beta1.ReferenceGrant{}
+	}
+	key := types.NamespacedName{Namespace: service.Namespace, Name: fmt.Sprintf("generated-reference-grant-from-%s-to-%s", routeNamespace, service.Namespace)}
+	referenceGrant, ok := ir.ReferenceGrants[key]
+	if !ok {
+		referenceGrant = gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		}
+		referenceGrant.SetGroupVersionKind(gatewayv1beta1.SchemeGroupVersion.WithKind("ReferenceGrant"))
+	}
+	from := gatewayv1beta1.ReferenceGrantFrom{Group: gatewayv1.GroupName, Kind: routeKind, Namespace: gatewayv1.Namespace(routeNamespace)}
+	if !slices.Contains(referenceGrant.Spec.From, from) {
+		referenceGrant.Spec.From = append(referenceGrant.Spec.From, from)
+	}
+	to := gatewayv1beta1.ReferenceGrantTo{Group: "", Kind: "Service", Name: ptr.To(gatewayv1.ObjectName(service.Name))}
+	if !slices.ContainsFunc(referenceGrant.Spec.To, func(t gatewayv1beta1.ReferenceGrantTo) bool {
+		return t.Group == to.Group && t.Kind == to.Kind && (t.Name == nil || *t.Name == *to.Name)
+	}) {
+		referenceGrant.Spec.To = append(referenceGrant.Spec.To, to)
+	}
+	ir.ReferenceGrants[key] = referenceGrant
+}
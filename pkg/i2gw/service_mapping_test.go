@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func Test_readServiceMapping(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		expected      map[types.NamespacedName]types.NamespacedName
+		expectedError bool
+	}{
+		{
+			name:    "valid mapping",
+			content: "# renamed\ndefault/shop=default/store\n\nlegacy/api = platform/api-v2\n",
+			expected: map[types.NamespacedName]types.NamespacedName{
+				{Namespace: "default", Name: "shop"}: {Namespace: "default", Name: "store"},
+				{Namespace: "legacy", Name: "api"}:   {Namespace: "platform", Name: "api-v2"},
+			},
+		},
+		{
+			name:          "missing namespace",
+			content:       "shop=default/store\n",
+			expectedError: true,
+		},
+		{
+			name:          "missing target",
+			content:       "default/shop\n",
+			expectedError: true,
+		},
+		{
+			name:          "conflicting mappings",
+			content:       "default/shop=default/store\ndefault/shop=default/market\n",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "service-mapping.txt")
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0o600))
+
+			mapping, err := readServiceMapping(path)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, mapping)
+		})
+	}
+}
+
+func Test_applyServiceMapping(t *testing.T) {
+	backendRef := func(namespace *gatewayv1.Namespace, name gatewayv1.ObjectName) gatewayv1.BackendObjectReference {
+		return gatewayv1.BackendObjectReference{Namespace: namespace, Name: name, Port: ptr.To[gatewayv1.PortNumber](80)}
+	}
+	ir := intermediate.IR{
+		HTTPRoutes: map[types.NamespacedName]intermediate.HTTPRouteContext{
+			{Namespace: "default", Name: "shop"}: {HTTPRoute: gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "shop"},
+				Spec: gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{
+					Filters: []gatewayv1.HTTPRouteFilter{{
+						Type:          gatewayv1.HTTPRouteFilterRequestMirror,
+						RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: backendRef(nil, "api")},
+					}},
+					BackendRefs: []gatewayv1.HTTPBackendRef{
+						{BackendRef: gatewayv1.BackendRef{BackendObjectReference: backendRef(nil, "shop")}},
+						{BackendRef: gatewayv1.BackendRef{BackendObjectReference: backendRef(nil, "cart")}},
+					},
+				}}},
+			}},
+		},
+		TCPRoutes: map[types.NamespacedName]gatewayv1alpha2.TCPRoute{
+			{Namespace: "default", Name: "db"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db"},
+				Spec: gatewayv1alpha2.TCPRouteSpec{Rules: []gatewayv1alpha2.TCPRouteRule{{
+					BackendRefs: []gatewayv1.BackendRef{{BackendObjectReference: backendRef(ptr.To[gatewayv1.Namespace]("data"), "postgres")}},
+				}}},
+			},
+		},
+	}
+	mapping := map[types.NamespacedName]types.NamespacedName{
+		{Namespace: "default", Name: "shop"}:  {Namespace: "default", Name: "store"},
+		{Namespace: "default", Name: "api"}:   {Namespace: "platform", Name: "api"},
+		{Namespace: "data", Name: "postgres"}: {Namespace: "default", Name: "postgres"},
+	}
+
+	applyServiceMapping("test", mapping, &ir)
+
+	rule := ir.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "shop"}].Spec.Rules[0]
+	require.Equal(t, backendRef(ptr.To[gatewayv1.Namespace]("platform"), "api"), rule.Filters[0].RequestMirror.BackendRef)
+	require.Equal(t, backendRef(nil, "store"), rule.BackendRefs[0].BackendObjectReference)
+	require.Equal(t, backendRef(nil, "cart"), rule.BackendRefs[1].BackendObjectReference)
+	require.Equal(t, backendRef(nil, "postgres"), ir.TCPRoutes[types.NamespacedName{Namespace: "default", Name: "db"}].Spec.Rules[0].BackendRefs[0].BackendObjectReference)
+
+	expectedReferenceGrants := map[types.NamespacedName]gatewayv1beta1.ReferenceGrant{
+		{Namespace: "platform", Name: "generated-reference-grant-from-default-to-platform"}: {
+			TypeMeta:   metav1.TypeMeta{APIVersion: gatewayv1beta1.GroupVersion.String(), Kind: "ReferenceGrant"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "platform", Name: "generated-reference-grant-from-default-to-platform"},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: "default"}},
+				To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Service", Name: ptr.To[gatewayv1.ObjectName]("api")}},
+			},
+		},
+	}
+	require.Equal(t, expectedReferenceGrants, ir.ReferenceGrants)
+}
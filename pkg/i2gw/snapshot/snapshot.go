@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshot reads and writes snapshots of the source resources read
+// from a cluster, so that they can be converted offline.
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// metadataFile is the name of the file of the archive holding the
+	// Metadata of the snapshot.
+	metadataFile = "snapshot.yaml"
+	// resourcesFile is the name of the file of the archive holding the
+	// resources of the snapshot, as a multi-document YAML manifest.
+	resourcesFile = "resources.yaml"
+)
+
+// Metadata describes how a snapshot was taken.
+type Metadata struct {
+	// Providers are the providers the resources were read for.
+	Providers []string `json:"providers"`
+	// Namespace is the namespace the resources were read from, all the
+	// namespaces if empty.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Snapshot holds the source resources read from a cluster.
+type Snapshot struct {
+	Metadata  Metadata
+	Resources []unstructured.Unstructured
+}
+
+// Write writes the snapshot to w as a gzipped tar archive. The archive holds
+// no timestamps, so that the same resources always yield the same archive.
+func Write(w io.Writer, snapshot Snapshot) error {
+	metadata, err := yaml.Marshal(snapshot.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the snapshot metadata: %w", err)
+	}
+	var resources bytes.Buffer
+	if err = WriteManifest(&resources, snapshot.Resources); err != nil {
+		return err
+	}
+
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, file := range []struct {
+		name    string
+		content []byte
+	}{
+		{name: metadataFile, content: metadata},
+		{name: resourcesFile, content: resources.Bytes()},
+	} {
+		header := &tar.Header{
+			Name:     file.name,
+			Mode:     0o644,
+			Size:     int64(len(file.content)),
+			ModTime:  time.Unix(0, 0),
+			Typeflag: tar.TypeReg,
+		}
+		if err = tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s to the snapshot: %w", file.name, err)
+		}
+		if _, err = tarWriter.Write(file.content); err != nil {
+			return fmt.Errorf("failed to write %s to the snapshot: %w", file.name, err)
+		}
+	}
+	if err = tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+// WriteManifest writes the resources to w as a multi-document YAML manifest.
+func WriteManifest(w io.Writer, resources []unstructured.Unstructured) error {
+	for _, resource := range resources {
+		out, err := yaml.Marshal(resource.Object)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s %s/%s: %w", resource.GetKind(), resource.GetNamespace(), resource.GetName(), err)
+		}
+		if _, err = fmt.Fprintf(w, "---\n%s", out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read reads the metadata and the raw resources manifest of a snapshot written
+// by Write.
+func Read(r io.Reader) (Metadata, []byte, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return Metadata{}, nil, fmt.Errorf("failed to read the snapshot: %w", err)
+	}
+	defer gzipReader.Close()
+
+	var (
+		metadata  Metadata
+		manifest  []byte
+		found     = map[string]bool{}
+		tarReader = tar.NewReader(gzipReader)
+	)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Metadata{}, nil, fmt.Errorf("failed to read the snapshot: %w", err)
+		}
+		switch header.Name {
+		case metadataFile:
+			content, err := io.ReadAll(tarReader)
+			if err != nil {
+				return Metadata{}, nil, fmt.Errorf("failed to read %s from the snapshot: %w", metadataFile, err)
+			}
+			if err = yaml.Unmarshal(content, &metadata); err != nil {
+				return Metadata{}, nil, fmt.Errorf("failed to unmarshal the snapshot metadata: %w", err)
+			}
+		case resourcesFile:
+			if manifest, err = io.ReadAll(tarReader); err != nil {
+				return Metadata{}, nil, fmt.Errorf("failed to read %s from the snapshot: %w", resourcesFile, err)
+			}
+		default:
+			continue
+		}
+		found[header.Name] = true
+	}
+	for _, name := range []string{metadataFile, resourcesFile} {
+		if !found[name] {
+			return Metadata{}, nil, fmt.Errorf("the snapshot has no %s file", name)
+		}
+	}
+	return metadata, manifest, nil
+}
+
+// ExtractManifest extracts the resources manifest of the snapshot archive at
+// the given path to a temporary file, suitable for reading the resources with
+// the providers. The returned function removes the file.
+func ExtractManifest(path string) (string, Metadata, func(), error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", Metadata{}, nil, fmt.Errorf("failed to open the snapshot: %w", err)
+	}
+	defer file.Close()
+
+	metadata, manifest, err := Read(file)
+	if err != nil {
+		return "", Metadata{}, nil, err
+	}
+
+	manifestFile, err := os.CreateTemp("", "ingress2gateway-snapshot-*.yaml")
+	if err != nil {
+		return "", Metadata{}, nil, fmt.Errorf("failed to extract the snapshot: %w", err)
+	}
+	cleanup := func() { os.Remove(manifestFile.Name()) }
+	if _, err = manifestFile.Write(manifest); err != nil {
+		manifestFile.Close()
+		cleanup()
+		return "", Metadata{}, nil, fmt.Errorf("failed to extract the snapshot: %w", err)
+	}
+	if err = manifestFile.Close(); err != nil {
+		cleanup()
+		return "", Metadata{}, nil, fmt.Errorf("failed to extract the snapshot: %w", err)
+	}
+	return manifestFile.Name(), metadata, cleanup, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_WriteRead(t *testing.T) {
+	snapshot := Snapshot{
+		Metadata: Metadata{Providers: []string{"ingress-nginx"}, Namespace: "default"},
+		Resources: []unstructured.Unstructured{{Object: map[string]interface{}{
+			"apiVersion": "networking.k8s.io/v1",
+			"kind":       "Ingress",
+			"metadata":   map[string]interface{}{"namespace": "default", "name": "app"},
+		}}},
+	}
+
+	var first, second bytes.Buffer
+	if err := Write(&first, snapshot); err != nil {
+		t.Fatalf("Unexpected error writing the snapshot: %v", err)
+	}
+	if err := Write(&second, snapshot); err != nil {
+		t.Fatalf("Unexpected error writing the snapshot: %v", err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Errorf("Expected the same snapshot to yield the same archive")
+	}
+
+	metadata, manifest, err := Read(bytes.NewReader(first.Bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected error reading the snapshot: %v", err)
+	}
+	if diff := cmp.Diff(snapshot.Metadata, metadata); diff != "" {
+		t.Errorf("Unexpected metadata (-want +got): %s", diff)
+	}
+	expectedManifest := `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: app
+  namespace: default
+`
+	if diff := cmp.Diff(expectedManifest, string(manifest)); diff != "" {
+		t.Errorf("Unexpected manifest (-want +got): %s", diff)
+	}
+
+	path := t.TempDir() + "/snapshot.tar.gz"
+	if err = os.WriteFile(path, first.Bytes(), 0o600); err != nil {
+		t.Fatalf("Unexpected error writing the snapshot file: %v", err)
+	}
+	manifestPath, _, cleanup, err := ExtractManifest(path)
+	if err != nil {
+		t.Fatalf("Unexpected error extracting the manifest: %v", err)
+	}
+	content, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatalf("Unexpected error reading the extracted manifest: %v", err)
+	}
+	if string(content) != expectedManifest {
+		t.Errorf("Unexpected extracted manifest: %s", content)
+	}
+	cleanup()
+	if _, err = os.Stat(manifestPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the extracted manifest to be removed, got %v", err)
+	}
+}
+
+func Test_Read_invalid(t *testing.T) {
+	if _, _, err := Read(bytes.NewReader([]byte("not an archive"))); err == nil {
+		t.Errorf("Expected an error reading an invalid snapshot")
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// ReadSourceResources reads the resources the configured providers read from
+// the cluster, e.g. Ingresses, Services and provider CRDs, so that they can be
+// converted offline with the InputFile option. Secrets are only returned with
+// their metadata.
+func ReadSourceResources(ctx context.Context, opts ConversionOptions) ([]unstructured.Unstructured, error) {
+	profile, err := GetProfile(opts.Profile)
+	if err != nil {
+		return nil, err
+	}
+	ingressClaimer, err := NewIngressClaimer(opts.Providers, opts.ProviderPriority)
+	if err != nil {
+		return nil, err
+	}
+	clusterClient, err := newClusterClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	recorder := newRecordingClient(clusterClient)
+
+	providerByName, err := constructProviders(&ProviderConf{
+		Client:                recorder,
+		Namespace:             opts.Namespace,
+		ProviderSpecificFlags: opts.ProviderSpecificFlags,
+		Mesh:                  opts.Mesh,
+		Profile:               profile,
+		IngressClaimer:        ingressClaimer,
+	}, opts.Providers)
+	if err != nil {
+		return nil, err
+	}
+	if err = readProviderResourcesFromCluster(ctx, providerByName); err != nil {
+		return nil, err
+	}
+	return recorder.resources(), nil
+}
+
+// recordingClient is a client.Client recording the objects it reads.
+type recordingClient struct {
+	client.Client
+
+	recorded map[string]unstructured.Unstructured
+}
+
+func newRecordingClient(c client.Client) *recordingClient {
+	return &recordingClient{Client: c, recorded: map[string]unstructured.Unstructured{}}
+}
+
+func (c *recordingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := c.Client.Get(ctx, key, obj, opts...); err != nil {
+		return err
+	}
+	return c.record(obj)
+}
+
+func (c *recordingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return fmt.Errorf("failed to record the listed objects: %w", err)
+	}
+	for _, item := range items {
+		if err = c.record(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record records the object as an Unstructured, without its managed fields,
+// and without the data of Secrets.
+func (c *recordingClient) record(obj runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return fmt.Errorf("failed to record object: %w", err)
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("failed to record object: %w", err)
+	}
+	u := unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	u.SetManagedFields(nil)
+	if gvk.Group == "" && gvk.Kind == "Secret" {
+		delete(u.Object, "data")
+		delete(u.Object, "stringData")
+	}
+	key := strings.Join([]string{gvk.GroupVersion().String(), gvk.Kind, u.GetNamespace(), u.GetName()}, "/")
+	c.recorded[key] = u
+	return nil
+}
+
+// resources returns the recorded objects, sorted by API version, kind,
+// namespace and name.
+func (c *recordingClient) resources() []unstructured.Unstructured {
+	keys := make([]string, 0, len(c.recorded))
+	for key := range c.recorded {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	resources := make([]unstructured.Unstructured, 0, len(keys))
+	for _, key := range keys {
+		resources = append(resources, c.recorded[key])
+	}
+	return resources
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_recordingClient(t *testing.T) {
+	objects := []runtime.Object{
+		&networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "b"}},
+		&networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a"}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "basic-auth"},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{"auth": []byte("user:password")},
+		},
+	}
+	recorder := newRecordingClient(fake.NewClientBuilder().WithRuntimeObjects(objects...).Build())
+
+	ctx := context.Background()
+	// Listing the Ingresses twice, as several providers do, records them once.
+	for range 2 {
+		if err := recorder.List(ctx, &networkingv1.IngressList{}); err != nil {
+			t.Fatalf("Unexpected error listing Ingresses: %v", err)
+		}
+	}
+	if err := recorder.Get(ctx, types.NamespacedName{Namespace: "default", Name: "basic-auth"}, &corev1.Secret{}); err != nil {
+		t.Fatalf("Unexpected error getting the Secret: %v", err)
+	}
+
+	var got []string
+	for _, resource := range recorder.resources() {
+		got = append(got, resource.GetAPIVersion()+" "+resource.GetKind()+" "+resource.GetNamespace()+"/"+resource.GetName())
+		if resource.GetKind() == "Secret" {
+			if _, ok := resource.Object["data"]; ok {
+				t.Errorf("Expected the data of the Secret not to be recorded")
+			}
+			if resource.Object["type"] != string(corev1.SecretTypeOpaque) {
+				t.Errorf("Expected the type of the Secret to be recorded, got %v", resource.Object["type"])
+			}
+		}
+	}
+	expected := []string{
+		"networking.k8s.io/v1 Ingress default/a",
+		"networking.k8s.io/v1 Ingress default/b",
+		"v1 Secret default/basic-auth",
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Unexpected recorded resources (-want +got): %s", diff)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import "sync"
+
+// SupportLevel is the maturity of a provider or an emitter.
+type SupportLevel string
+
+const (
+	// StableSupportLevel providers and emitters cover the features of their
+	// implementation broadly, and their output only changes to fix bugs.
+	StableSupportLevel SupportLevel = "stable"
+	// ExperimentalSupportLevel providers and emitters cover a subset of the
+	// features of their implementation, and their output may change between
+	// releases. It's the support level of the providers and emitters which
+	// don't register one.
+	ExperimentalSupportLevel SupportLevel = "experimental"
+)
+
+var supportLevels = struct {
+	providers map[ProviderName]SupportLevel
+	emitters  map[EmitterName]SupportLevel
+	mu        sync.RWMutex
+}{
+	providers: map[ProviderName]SupportLevel{},
+	emitters:  map[EmitterName]SupportLevel{},
+}
+
+// RegisterProviderSupportLevel registers the support level of a provider,
+// at startup.
+func RegisterProviderSupportLevel(name ProviderName, level SupportLevel) {
+	supportLevels.mu.Lock()
+	defer supportLevels.mu.Unlock()
+	supportLevels.providers[name] = level
+}
+
+// RegisterEmitterSupportLevel registers the support level of an emitter, at
+// startup.
+func RegisterEmitterSupportLevel(name EmitterName, level SupportLevel) {
+	supportLevels.mu.Lock()
+	defer supportLevels.mu.Unlock()
+	supportLevels.emitters[name] = level
+}
+
+// GetProviderSupportLevel returns the support level of the provider.
+func GetProviderSupportLevel(name ProviderName) SupportLevel {
+	supportLevels.mu.RLock()
+	defer supportLevels.mu.RUnlock()
+	if level, ok := supportLevels.providers[name]; ok {
+		return level
+	}
+	return ExperimentalSupportLevel
+}
+
+// GetEmitterSupportLevel returns the support level of the emitter.
+func GetEmitterSupportLevel(name EmitterName) SupportLevel {
+	supportLevels.mu.RLock()
+	defer supportLevels.mu.RUnlock()
+	if level, ok := supportLevels.emitters[name]; ok {
+		return level
+	}
+	return ExperimentalSupportLevel
+}
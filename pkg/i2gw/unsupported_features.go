@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// UnsupportedFeatureKind is the kind of the commented-out stubs listing the
+// unsupported features of a generated route.
+const UnsupportedFeatureKind = "UnsupportedFeature"
+
+// unsupportedFeatureStub is the commented-out document listing the
+// unsupported features of a route, along with their raw configuration.
+type unsupportedFeatureStub struct {
+	Kind      string                     `json:"kind"`
+	TargetRef unsupportedFeatureObject   `json:"targetRef"`
+	Features  []unsupportedFeatureConfig `json:"features"`
+}
+
+type unsupportedFeatureObject struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+type unsupportedFeatureConfig struct {
+	Source    unsupportedFeatureObject `json:"source"`
+	Name      string                   `json:"name"`
+	RawConfig string                   `json:"rawConfig"`
+}
+
+// UnsupportedFeatureStub returns the given unsupported features of the
+// HTTPRoute as a commented-out YAML document, to be printed next to the
+// HTTPRoute so that operators can port them manually.
+func UnsupportedFeatureStub(httpRouteKey types.NamespacedName, features []intermediate.UnsupportedFeature) (string, error) {
+	stub := unsupportedFeatureStub{
+		Kind: UnsupportedFeatureKind,
+		TargetRef: unsupportedFeatureObject{
+			Group:     gatewayv1.GroupName,
+			Kind:      "HTTPRoute",
+			Namespace: httpRouteKey.Namespace,
+			Name:      httpRouteKey.Name,
+		},
+	}
+	for _, feature := range features {
+		stub.Features = append(stub.Features, unsupportedFeatureConfig{
+			Source: unsupportedFeatureObject{
+				Kind:      feature.SourceKind,
+				Namespace: feature.Source.Namespace,
+				Name:      feature.Source.Name,
+			},
+			Name:      feature.Name,
+			RawConfig: feature.RawConfig,
+		})
+	}
+
+	out, err := yaml.Marshal(stub)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(string(out), "\n"), "\n") {
+		sb.WriteString(strings.TrimSuffix("# "+line, " "))
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// unsupportedFeaturesByRoute returns the unsupported features of the HTTPRoutes
+// of the IR, by HTTPRoute.
+func unsupportedFeaturesByRoute(ir intermediate.IR) map[types.NamespacedName][]intermediate.UnsupportedFeature {
+	features := map[types.NamespacedName][]intermediate.UnsupportedFeature{}
+	for key, httpRouteContext := range ir.HTTPRoutes {
+		if len(httpRouteContext.UnsupportedFeatures) > 0 {
+			features[key] = httpRouteContext.UnsupportedFeatures
+		}
+	}
+	return features
+}
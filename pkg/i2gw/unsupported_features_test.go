@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/intermediate"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_UnsupportedFeatureStub(t *testing.T) {
+	stub, err := UnsupportedFeatureStub(types.NamespacedName{Namespace: "default", Name: "foo-example-com"}, []intermediate.UnsupportedFeature{{
+		SourceKind: "Ingress",
+		Source:     types.NamespacedName{Namespace: "default", Name: "foo"},
+		Name:       "nginx.ingress.kubernetes.io/server-snippet",
+		RawConfig:  "location /admin {\n  deny all;\n}\n",
+	}})
+	require.NoError(t, err)
+
+	want := `# features:
+# - name: nginx.ingress.kubernetes.io/server-snippet
+#   rawConfig: |
+#     location /admin {
+#       deny all;
+#     }
+#   source:
+#     kind: Ingress
+#     name: foo
+#     namespace: default
+# kind: UnsupportedFeature
+# targetRef:
+#   group: gateway.networking.k8s.io
+#   kind: HTTPRoute
+#   name: foo-example-com
+#   namespace: default
+`
+	require.Equal(t, want, stub)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WatchIngresses calls onChange whenever the Ingresses of the cluster of the
+// options change, until the context is done. The changes happening less than
+// the debounce period apart are reported once, after the last of them.
+func WatchIngresses(ctx context.Context, opts ConversionOptions, debounce time.Duration, onChange func()) error {
+	if opts.InputFile != "" {
+		return fmt.Errorf("watching the Ingresses requires reading them from the cluster, not from %s", opts.InputFile)
+	}
+	conf, err := clusterConfig(opts)
+	if err != nil {
+		return err
+	}
+	cl, err := client.NewWithWatch(conf, client.Options{})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	return watchIngresses(ctx, cl, opts.Namespace, debounce, onChange)
+}
+
+// watchIngresses watches the Ingresses of the namespace, all of them if it is
+// empty, from their current state, restarting the watch when the API server
+// closes it.
+func watchIngresses(ctx context.Context, cl client.WithWatch, namespace string, debounce time.Duration, onChange func()) error {
+	var ingressList networkingv1.IngressList
+	if err := cl.List(ctx, &ingressList, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list ingresses: %w", err)
+	}
+	resourceVersion := ingressList.ResourceVersion
+
+	var debounced <-chan time.Time
+	for {
+		watcher, err := cl.Watch(ctx, &networkingv1.IngressList{}, client.InNamespace(namespace), &client.ListOptions{
+			Raw: &metav1.ListOptions{ResourceVersion: resourceVersion, AllowWatchBookmarks: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to watch ingresses: %w", err)
+		}
+
+		for open := true; open; {
+			select {
+			case <-ctx.Done():
+				watcher.Stop()
+				return nil
+			case <-debounced:
+				debounced = nil
+				onChange()
+			case event, ok := <-watcher.ResultChan():
+				if !ok {
+					open = false
+					continue
+				}
+				switch event.Type {
+				case watch.Error:
+					// The resource version expired, the Ingresses are watched
+					// again from their current state, which is then reported
+					// as changed.
+					watcher.Stop()
+					resourceVersion = ""
+					open = false
+				case watch.Bookmark:
+					if ingress, ok := event.Object.(*networkingv1.Ingress); ok {
+						resourceVersion = ingress.ResourceVersion
+					}
+				default:
+					if ingress, ok := event.Object.(*networkingv1.Ingress); ok {
+						resourceVersion = ingress.ResourceVersion
+					}
+					debounced = time.After(debounce)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_watchIngresses(t *testing.T) {
+	existing := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "existing"}}
+	cl := fake.NewClientBuilder().WithObjects(existing).Build()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan struct{}, 10)
+	done := make(chan error)
+	go func() {
+		done <- watchIngresses(ctx, cl, "default", 50*time.Millisecond, func() { changes <- struct{}{} })
+	}()
+
+	// The changes are only watched once the watch is established.
+	time.Sleep(50 * time.Millisecond)
+	require.Empty(t, changes, "existing Ingresses are not reported as changed")
+
+	for _, name := range []string{"a", "b"} {
+		require.NoError(t, cl.Create(ctx, &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}))
+	}
+	require.NoError(t, cl.Create(ctx, &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "c"}}))
+
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the creation of the Ingresses to be reported")
+	}
+	time.Sleep(100 * time.Millisecond)
+	require.Empty(t, changes, "the changes are reported once after the debounce period")
+
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the watch to stop with the context")
+	}
+}